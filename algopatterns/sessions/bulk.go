@@ -0,0 +1,163 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// writes the code of many sessions in one statement. a session listed twice
+// gets either code, callers pass the latest once
+func (r *repository) UpdateSessionCodes(ctx context.Context, codes []SessionCode) error {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(codes))
+	texts := make([]string, len(codes))
+	for i, c := range codes {
+		ids[i], texts[i] = c.SessionID, c.Code
+	}
+
+	_, err := r.db.Exec(ctx, queryUpdateSessionCodes, ids, texts)
+	return err
+}
+
+// writes chat messages and reaction changes in order, in one round trip.
+// order matters: reactions refer to messages written before them
+func (r *repository) AddChatEntries(ctx context.Context, entries []ChatEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, e := range entries {
+		switch e.Kind {
+		case ChatEntryReactionAdded:
+			batch.Queue(queryAddChatReaction, e.SessionID, e.ParentMessageID, e.UserID, e.DisplayName, e.Content)
+		case ChatEntryReactionRemoved:
+			batch.Queue(queryRemoveChatReaction, e.SessionID, e.ParentMessageID, e.UserID, e.DisplayName, e.Content)
+		default:
+			batch.Queue(queryAddChatMessage,
+				e.ID,
+				e.SessionID,
+				nilIfEmpty(e.UserID),
+				e.Content,
+				nilIfEmpty(e.DisplayName),
+				nilIfEmpty(e.AvatarURL),
+				nilIfEmpty(e.ParentMessageID),
+			)
+		}
+	}
+
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
+
+// copies code checkpoints into Postgres. runs in a transaction so the
+// pooler keeps COPY's describe and copy on one connection
+func (r *repository) AddCodeRevisions(ctx context.Context, revisions []CodeRevision) error {
+	if len(revisions) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, len(revisions))
+	for i, rev := range revisions {
+		var sessionID pgtype.UUID
+		if err := sessionID.Scan(rev.SessionID); err != nil {
+			return fmt.Errorf("invalid session id %q: %w", rev.SessionID, err)
+		}
+
+		rows[i] = []any{sessionID, rev.Code, rev.CreatedAt}
+	}
+
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"code_revisions"},
+			[]string{"session_id", "code", "created_at"},
+			pgx.CopyFromRows(rows),
+		)
+		return err
+	})
+}
+
+// adds many activity samples to the rollups in one statement
+func (r *repository) AddActivities(ctx context.Context, samples []ActivitySample) error {
+	samples = mergeActivity(samples)
+	if len(samples) == 0 {
+		return nil
+	}
+
+	n := len(samples)
+	var (
+		sessionIDs    = make([]string, n)
+		buckets       = make([]time.Time, n)
+		participants  = make([]int, n)
+		listeners     = make([]int, n)
+		codeEdits     = make([]int, n)
+		agentRequests = make([]int, n)
+		chatMessages  = make([]int, n)
+		playback      = make([]float64, n)
+	)
+
+	for i, s := range samples {
+		sessionIDs[i] = s.SessionID
+		buckets[i] = s.BucketStart
+		participants[i] = s.Participants
+		listeners[i] = s.Listeners
+		codeEdits[i] = s.CodeEdits
+		agentRequests[i] = s.AgentRequests
+		chatMessages[i] = s.ChatMessages
+		playback[i] = s.PlaybackSeconds
+	}
+
+	_, err := r.db.Exec(ctx, queryAddActivities,
+		sessionIDs, buckets, participants, listeners, codeEdits, agentRequests, chatMessages, playback,
+	)
+	return err
+}
+
+// combines samples of the same session and minute the way the rollup does:
+// connection counts keep the peak, the rest add up. first-seen order is kept
+func mergeActivity(samples []ActivitySample) []ActivitySample {
+	type key struct {
+		sessionID string
+		bucket    int64
+	}
+
+	merged := make([]ActivitySample, 0, len(samples))
+	index := make(map[key]int, len(samples))
+
+	for _, s := range samples {
+		k := key{s.SessionID, s.BucketStart.UnixNano()}
+
+		i, exists := index[k]
+		if !exists {
+			index[k] = len(merged)
+			merged = append(merged, s)
+			continue
+		}
+
+		m := &merged[i]
+		m.Participants = max(m.Participants, s.Participants)
+		m.Listeners = max(m.Listeners, s.Listeners)
+		m.CodeEdits += s.CodeEdits
+		m.AgentRequests += s.AgentRequests
+		m.ChatMessages += s.ChatMessages
+		m.PlaybackSeconds += s.PlaybackSeconds
+	}
+
+	return merged
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
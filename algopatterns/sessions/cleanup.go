@@ -2,22 +2,34 @@ package sessions
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	"codeberg.org/algopatterns/server/internal/logger"
 )
 
-// handles automatic expiry of stale sessions
+// handles automatic expiry of stale sessions. hosts can override the
+// inactivity threshold and what happens on expiry with an ExpiryPolicy
 type CleanupService struct {
 	repo                Repository
 	checkInterval       time.Duration
 	inactivityThreshold time.Duration
 	sessionEnder        SessionEnderFunc
+	codePublisher       CodePublisherFunc
+	onSessionEnding     func(ctx context.Context, tx pgx.Tx, session *Session) error
+	onSessionEnded      func(ctx context.Context, session *Session)
+	tasks               []func(ctx context.Context)
 }
 
 // called to notify WebSocket clients when a session is being cleaned up
 type SessionEnderFunc func(sessionID string, reason string)
 
+// called to save the final code of an expired session as a strudel of its
+// host, returns the strudel's ID
+type CodePublisherFunc func(ctx context.Context, session *Session) (string, error)
+
 // creates a new cleanup service
 func NewCleanupService(
 	repo Repository,
@@ -33,6 +45,27 @@ func NewCleanupService(
 	}
 }
 
+// registers work done in the transaction ending a stale session, like
+// queueing its events. an error keeps the session from being ended
+func (s *CleanupService) SetOnSessionEnding(fn func(ctx context.Context, tx pgx.Tx, session *Session) error) {
+	s.onSessionEnding = fn
+}
+
+// registers a callback invoked after a stale session has been ended
+func (s *CleanupService) SetOnSessionEnded(fn func(ctx context.Context, session *Session)) {
+	s.onSessionEnded = fn
+}
+
+// registers the function publishing the code of sessions whose policy asks for it
+func (s *CleanupService) SetCodePublisher(fn CodePublisherFunc) {
+	s.codePublisher = fn
+}
+
+// registers a housekeeping task run on every check, after stale sessions are ended
+func (s *CleanupService) AddTask(task func(ctx context.Context)) {
+	s.tasks = append(s.tasks, task)
+}
+
 // begins the cleanup service background loop
 func (s *CleanupService) Start(ctx context.Context) {
 	logger.Info("starting session cleanup service",
@@ -50,15 +83,17 @@ func (s *CleanupService) Start(ctx context.Context) {
 			return
 		case <-ticker.C:
 			s.cleanupStaleSessions(ctx)
+
+			for _, task := range s.tasks {
+				task(ctx)
+			}
 		}
 	}
 }
 
 // finds and ends sessions that have been inactive
 func (s *CleanupService) cleanupStaleSessions(ctx context.Context) {
-	threshold := time.Now().Add(-s.inactivityThreshold)
-
-	staleSessions, err := s.repo.ListStaleSessions(ctx, threshold)
+	staleSessions, err := s.repo.ListStaleSessions(ctx, s.inactivityThreshold)
 	if err != nil {
 		logger.ErrorErr(err, "failed to list stale sessions")
 		return
@@ -81,7 +116,9 @@ func (s *CleanupService) cleanupStaleSessions(ctx context.Context) {
 }
 
 // performs cleanup for a single stale session
-func (s *CleanupService) endStaleSession(ctx context.Context, session *Session) error {
+func (s *CleanupService) endStaleSession(ctx context.Context, stale *StaleSession) error {
+	session := &stale.Session
+
 	logger.Info("ending stale session",
 		"session_id", session.ID,
 		"title", session.Title,
@@ -94,10 +131,50 @@ func (s *CleanupService) endStaleSession(ctx context.Context, session *Session)
 	}
 
 	// end the session in database
-	if err := s.repo.EndSession(ctx, session.ID); err != nil {
+	var inTx func(tx pgx.Tx) error
+	if s.onSessionEnding != nil {
+		inTx = func(tx pgx.Tx) error {
+			return s.onSessionEnding(ctx, tx, session)
+		}
+	}
+
+	if err := s.repo.EndSession(ctx, session.ID, inTx); err != nil {
 		return err
 	}
 
+	s.applyEndPolicy(ctx, stale)
+
+	if s.onSessionEnded != nil {
+		s.onSessionEnded(ctx, session)
+	}
+
 	logger.Info("stale session ended successfully", "session_id", session.ID)
 	return nil
 }
+
+// honors the parts of the session's expiry policy that apply once it has
+// ended. failures are only logged, the session stays ended either way
+func (s *CleanupService) applyEndPolicy(ctx context.Context, stale *StaleSession) {
+	if stale.Policy.RevokeInvitesOnEnd {
+		if err := s.repo.RevokeAllInviteTokens(ctx, stale.ID); err != nil {
+			logger.ErrorErr(err, "failed to revoke invites of expired session", "session_id", stale.ID)
+		}
+	}
+
+	// anonymous sessions have nobody to publish for
+	if !stale.Policy.PublishOnEnd || s.codePublisher == nil ||
+		stale.HostUserID == SystemUserID || strings.TrimSpace(stale.Code) == "" {
+		return
+	}
+
+	strudelID, err := s.codePublisher(ctx, &stale.Session)
+	if err != nil {
+		logger.ErrorErr(err, "failed to publish code of expired session", "session_id", stale.ID)
+		return
+	}
+
+	logger.Info("published code of expired session",
+		"session_id", stale.ID,
+		"strudel_id", strudelID,
+	)
+}
@@ -0,0 +1,117 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// records the calls the cleanup service makes
+type cleanupRepo struct {
+	Repository
+	stale   []*StaleSession
+	ended   []string
+	revoked []string
+}
+
+func (r *cleanupRepo) ListStaleSessions(_ context.Context, _ time.Duration) ([]*StaleSession, error) {
+	return r.stale, nil
+}
+
+func (r *cleanupRepo) EndSession(_ context.Context, sessionID string, inTx func(tx pgx.Tx) error) error {
+	if inTx != nil {
+		if err := inTx(nil); err != nil {
+			return err
+		}
+	}
+
+	r.ended = append(r.ended, sessionID)
+	return nil
+}
+
+func (r *cleanupRepo) RevokeAllInviteTokens(_ context.Context, sessionID string) error {
+	r.revoked = append(r.revoked, sessionID)
+	return nil
+}
+
+func staleSession(id, hostID, code string, policy ExpiryPolicy) *StaleSession {
+	return &StaleSession{
+		Session: Session{ID: id, HostUserID: hostID, Code: code},
+		Policy:  policy,
+	}
+}
+
+func TestCleanupAppliesExpiryPolicies(t *testing.T) {
+	repo := &cleanupRepo{stale: []*StaleSession{
+		staleSession("default", "host-1", "s(\"bd\")", ExpiryPolicy{}),
+		staleSession("revoke", "host-1", "s(\"bd\")", ExpiryPolicy{RevokeInvitesOnEnd: true}),
+		staleSession("publish", "host-1", "s(\"bd\")", ExpiryPolicy{PublishOnEnd: true}),
+		staleSession("empty", "host-1", "  \n", ExpiryPolicy{PublishOnEnd: true}),
+		staleSession("anonymous", SystemUserID, "s(\"bd\")", ExpiryPolicy{PublishOnEnd: true}),
+	}}
+
+	var notified, published, ended []string
+	service := NewCleanupService(repo, time.Minute, 30*time.Minute, func(sessionID, _ string) {
+		notified = append(notified, sessionID)
+	})
+	service.SetCodePublisher(func(_ context.Context, session *Session) (string, error) {
+		published = append(published, session.ID)
+		return "strudel-1", nil
+	})
+	service.SetOnSessionEnded(func(_ context.Context, session *Session) {
+		ended = append(ended, session.ID)
+	})
+
+	service.cleanupStaleSessions(context.Background())
+
+	all := []string{"default", "revoke", "publish", "empty", "anonymous"}
+	assert.Equal(t, all, notified)
+	assert.Equal(t, all, repo.ended)
+	assert.Equal(t, all, ended)
+	assert.Equal(t, []string{"revoke"}, repo.revoked)
+	assert.Equal(t, []string{"publish"}, published, "only sessions with code and a real host are published")
+}
+
+func TestCleanupKeepsSessionEndedWhenPublishingFails(t *testing.T) {
+	repo := &cleanupRepo{stale: []*StaleSession{
+		staleSession("s1", "host-1", "s(\"bd\")", ExpiryPolicy{PublishOnEnd: true, RevokeInvitesOnEnd: true}),
+	}}
+
+	service := NewCleanupService(repo, time.Minute, 30*time.Minute, nil)
+	service.SetCodePublisher(func(_ context.Context, _ *Session) (string, error) {
+		return "", errors.New("database unavailable")
+	})
+
+	require.NotPanics(t, func() { service.cleanupStaleSessions(context.Background()) })
+	assert.Equal(t, []string{"s1"}, repo.ended)
+	assert.Equal(t, []string{"s1"}, repo.revoked)
+}
+
+func TestCleanupEndsSessionOnlyWithItsEvents(t *testing.T) {
+	repo := &cleanupRepo{stale: []*StaleSession{
+		staleSession("queued", "host-1", "", ExpiryPolicy{}),
+		staleSession("unqueued", "host-2", "", ExpiryPolicy{}),
+	}}
+
+	var ended []string
+	service := NewCleanupService(repo, time.Minute, 30*time.Minute, nil)
+	service.SetOnSessionEnding(func(_ context.Context, _ pgx.Tx, session *Session) error {
+		if session.ID == "unqueued" {
+			return errors.New("job queue unavailable")
+		}
+		return nil
+	})
+	service.SetOnSessionEnded(func(_ context.Context, session *Session) {
+		ended = append(ended, session.ID)
+	})
+
+	service.cleanupStaleSessions(context.Background())
+
+	assert.Equal(t, []string{"queued"}, repo.ended, "a session whose events can't be queued stays active for the next check")
+	assert.Equal(t, []string{"queued"}, ended)
+}
@@ -0,0 +1,54 @@
+package sessions
+
+import (
+	"codeberg.org/algopatterns/server/internal/keyset"
+)
+
+// fields session, participant and chat message lists can be sorted by
+var (
+	SessionSorts     = []string{"last_activity", "created_at", "title"}
+	ParticipantSorts = []string{"joined_at", "display_name"}
+	MessageSorts     = []string{"created_at"}
+)
+
+// the column and type behind a sort field of session lists
+func sessionSortColumn(field string) (string, string) {
+	switch field {
+	case "created_at":
+		return "created_at", "timestamptz"
+	case "title":
+		return "title", "text"
+	default:
+		return "last_activity", "timestamptz"
+	}
+}
+
+// the position of a session in lists sorted by field
+func SessionKey(field string) func(*Session) keyset.Key {
+	return func(s *Session) keyset.Key {
+		switch field {
+		case "created_at":
+			return keyset.Key{Value: keyset.Time(s.CreatedAt), ID: s.ID}
+		case "title":
+			return keyset.Key{Value: s.Title, ID: s.ID}
+		default:
+			return keyset.Key{Value: keyset.Time(s.LastActivity), ID: s.ID}
+		}
+	}
+}
+
+// the position of a participant in lists sorted by field
+func ParticipantKey(field string) func(*CombinedParticipant) keyset.Key {
+	return func(p *CombinedParticipant) keyset.Key {
+		if field == "display_name" {
+			return keyset.Key{Value: p.DisplayName, ID: p.ID}
+		}
+
+		return keyset.Key{Value: keyset.Time(p.JoinedAt), ID: p.ID}
+	}
+}
+
+// the position of a chat message, which lists sort by time only
+func MessageKey(m *Message) keyset.Key {
+	return keyset.Key{Value: keyset.Time(m.CreatedAt), ID: m.ID}
+}
@@ -0,0 +1,101 @@
+package sessions
+
+import "errors"
+
+// a set of things a participant may do in a session, stored as a bitmask
+type Permission int32
+
+// permissions (bit values are stored in the database, don't renumber)
+const (
+	PermEditCode           Permission = 1 << 0
+	PermUseAgent           Permission = 1 << 1
+	PermChat               Permission = 1 << 2
+	PermManageParticipants Permission = 1 << 3
+	PermControlPlayback    Permission = 1 << 4
+
+	PermAll = PermEditCode | PermUseAgent | PermChat | PermManageParticipants | PermControlPlayback
+)
+
+// permission names used in the API, in bit order
+var permissionNames = []permissionName{
+	{PermEditCode, "edit_code"},
+	{PermUseAgent, "use_agent"},
+	{PermChat, "chat"},
+	{PermManageParticipants, "manage_participants"},
+	{PermControlPlayback, "control_playback"},
+}
+
+type permissionName struct {
+	perm Permission
+	name string
+}
+
+var ErrUnknownPermission = errors.New("unknown permission")
+
+// the permissions of a role when the host hasn't customized them. these match
+// what the roles allowed before permissions existed
+func RolePermissions(role string) Permission {
+	switch role {
+	case "host":
+		return PermAll
+	case "co-author":
+		return PermEditCode | PermUseAgent | PermChat | PermControlPlayback
+	case "viewer", "spectator":
+		return PermChat
+	default:
+		return 0
+	}
+}
+
+// the permissions of a participant: the override if the host set one, otherwise
+// the role preset. hosts always have every permission
+func EffectivePermissions(role string, override *Permission) Permission {
+	if override == nil || role == "host" {
+		return RolePermissions(role)
+	}
+
+	return *override & PermAll
+}
+
+// checks if all of the given permissions are in the set
+func (p Permission) Has(perm Permission) bool {
+	return p&perm == perm
+}
+
+// returns the API names of the permissions in the set
+func (p Permission) Names() []string {
+	names := []string{}
+	for _, n := range permissionNames {
+		if p.Has(n.perm) {
+			names = append(names, n.name)
+		}
+	}
+
+	return names
+}
+
+// builds a permission set from API names
+func ParsePermissions(names []string) (Permission, error) {
+	var p Permission
+
+	for _, name := range names {
+		perm, ok := lookupPermission(name)
+		if !ok {
+			return 0, ErrUnknownPermission
+		}
+
+		p |= perm
+	}
+
+	return p, nil
+}
+
+func lookupPermission(name string) (Permission, bool) {
+	for _, n := range permissionNames {
+		if n.name == name {
+			return n.perm, true
+		}
+	}
+
+	return 0, false
+}
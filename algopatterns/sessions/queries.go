@@ -5,23 +5,23 @@ const (
 	queryCreateSession = `
 		INSERT INTO sessions (host_user_id, title, code)
 		VALUES ($1, $2, $3)
-		RETURNING id, host_user_id, title, code, is_active, is_discoverable, created_at, ended_at, last_activity
+		RETURNING id, host_user_id, title, code, is_active, is_discoverable, chat_slow_mode_seconds, created_at, ended_at, last_activity
 	`
 
 	queryCreateAnonymousSession = `
 		INSERT INTO sessions (host_user_id, title, code)
 		VALUES ('00000000-0000-0000-0000-000000000000', 'Anonymous Session', '')
-		RETURNING id, host_user_id, title, code, is_active, is_discoverable, created_at, ended_at, last_activity
+		RETURNING id, host_user_id, title, code, is_active, is_discoverable, chat_slow_mode_seconds, created_at, ended_at, last_activity
 	`
 
 	queryGetSession = `
-		SELECT id, host_user_id, title, code, is_active, is_discoverable, created_at, ended_at, last_activity
+		SELECT id, host_user_id, title, code, is_active, is_discoverable, chat_slow_mode_seconds, created_at, ended_at, last_activity
 		FROM sessions
 		WHERE id = $1
 	`
 
 	queryGetUserSessions = `
-		SELECT DISTINCT s.id, s.host_user_id, s.title, s.code, s.is_active, s.is_discoverable, s.created_at, s.ended_at, s.last_activity
+		SELECT DISTINCT s.id, s.host_user_id, s.title, s.code, s.is_active, s.is_discoverable, s.chat_slow_mode_seconds, s.created_at, s.ended_at, s.last_activity
 		FROM sessions s
 		LEFT JOIN session_participants sp ON s.id = sp.session_id
 		WHERE s.host_user_id = $1 OR sp.user_id = $1
@@ -29,19 +29,20 @@ const (
 	`
 
 	queryGetUserSessionsActiveOnly = `
-		SELECT DISTINCT s.id, s.host_user_id, s.title, s.code, s.is_active, s.is_discoverable, s.created_at, s.ended_at, s.last_activity
+		SELECT DISTINCT s.id, s.host_user_id, s.title, s.code, s.is_active, s.is_discoverable, s.chat_slow_mode_seconds, s.created_at, s.ended_at, s.last_activity
 		FROM sessions s
 		LEFT JOIN session_participants sp ON s.id = sp.session_id
 		WHERE (s.host_user_id = $1 OR sp.user_id = $1) AND s.is_active = true
 		ORDER BY s.last_activity DESC
 	`
 
+	// %s are the keyset condition, the order and the limit's placeholder
 	queryListDiscoverableSessions = `
-		SELECT id, host_user_id, title, code, is_active, is_discoverable, created_at, ended_at, last_activity
+		SELECT id, host_user_id, title, code, is_active, is_discoverable, chat_slow_mode_seconds, created_at, ended_at, last_activity
 		FROM sessions
-		WHERE is_discoverable = true AND is_active = true
-		ORDER BY last_activity DESC
-		LIMIT $1 OFFSET $2
+		WHERE is_discoverable = true AND is_active = true AND %s
+		ORDER BY %s
+		LIMIT $%d
 	`
 
 	queryCountDiscoverableSessions = `
@@ -50,6 +51,20 @@ const (
 		WHERE is_discoverable = true AND is_active = true
 	`
 
+	queryListSessions = `
+		SELECT id, host_user_id, title, code, is_active, is_discoverable, chat_slow_mode_seconds, created_at, ended_at, last_activity
+		FROM sessions
+		WHERE ($1 = false OR is_active = true)
+		ORDER BY last_activity DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	queryCountSessions = `
+		SELECT COUNT(*)
+		FROM sessions
+		WHERE ($1 = false OR is_active = true)
+	`
+
 	queryUpdateSessionCode = `
 		UPDATE sessions
 		SET code = $1, last_activity = NOW()
@@ -62,6 +77,24 @@ const (
 		WHERE id = $2
 	`
 
+	querySetChatSlowMode = `
+		UPDATE sessions
+		SET chat_slow_mode_seconds = $1
+		WHERE id = $2
+	`
+
+	queryGetExpiryPolicy = `
+		SELECT auto_end_idle_hours, revoke_invites_on_end, publish_on_end
+		FROM sessions
+		WHERE id = $1
+	`
+
+	querySetExpiryPolicy = `
+		UPDATE sessions
+		SET auto_end_idle_hours = $1, revoke_invites_on_end = $2, publish_on_end = $3
+		WHERE id = $4
+	`
+
 	queryEndSession = `
 		UPDATE sessions
 		SET is_active = false, ended_at = NOW()
@@ -74,11 +107,11 @@ const (
 		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (session_id, user_id) DO UPDATE
 		SET status = 'active', left_at = NULL
-		RETURNING id, session_id, user_id, display_name, role, status, joined_at, left_at
+		RETURNING id, session_id, user_id, display_name, role, permissions, muted_at, status, joined_at, left_at
 	`
 
 	queryGetAuthenticatedParticipant = `
-		SELECT id, session_id, user_id, display_name, role, status, joined_at, left_at
+		SELECT id, session_id, user_id, display_name, role, permissions, muted_at, status, joined_at, left_at
 		FROM session_participants
 		WHERE session_id = $1 AND user_id = $2
 	`
@@ -90,14 +123,14 @@ const (
 	`
 
 	queryListAuthenticatedParticipants = `
-		SELECT id, session_id, user_id, display_name, role, status, joined_at, left_at
+		SELECT id, session_id, user_id, display_name, role, permissions, muted_at, status, joined_at, left_at
 		FROM session_participants
 		WHERE session_id = $1
 		ORDER BY joined_at ASC
 	`
 
 	queryGetParticipantByID = `
-		SELECT id, session_id, user_id, display_name, role, status, joined_at, left_at
+		SELECT id, session_id, user_id, display_name, role, permissions, muted_at, status, joined_at, left_at
 		FROM session_participants
 		WHERE id = $1
 	`
@@ -108,9 +141,35 @@ const (
 		WHERE id = $1
 	`
 
+	// a new role brings its preset, replacing any customized permissions
 	queryUpdateParticipantRole = `
 		UPDATE session_participants
-		SET role = $1
+		SET role = $1, permissions = NULL
+		WHERE id = $2
+	`
+
+	queryUpdateAuthParticipantPermissions = `
+		UPDATE session_participants
+		SET permissions = $1
+		WHERE id = $2
+	`
+
+	queryUpdateAnonParticipantPermissions = `
+		UPDATE anonymous_participants
+		SET permissions = $1
+		WHERE id = $2
+	`
+
+	// keeps the time of the first mute while muted
+	queryMuteAuthParticipant = `
+		UPDATE session_participants
+		SET muted_at = CASE WHEN $1::boolean THEN COALESCE(muted_at, NOW()) END
+		WHERE id = $2
+	`
+
+	queryMuteAnonParticipant = `
+		UPDATE anonymous_participants
+		SET muted_at = CASE WHEN $1::boolean THEN COALESCE(muted_at, NOW()) END
 		WHERE id = $2
 	`
 
@@ -118,11 +177,11 @@ const (
 	queryAddAnonymousParticipant = `
 		INSERT INTO anonymous_participants (session_id, display_name, role)
 		VALUES ($1, $2, $3)
-		RETURNING id, session_id, display_name, role, status, joined_at, left_at, expires_at
+		RETURNING id, session_id, display_name, role, permissions, muted_at, status, joined_at, left_at, expires_at
 	`
 
 	queryListAnonymousParticipants = `
-		SELECT id, session_id, display_name, role, status, joined_at, left_at, expires_at
+		SELECT id, session_id, display_name, role, permissions, muted_at, status, joined_at, left_at, expires_at
 		FROM anonymous_participants
 		WHERE session_id = $1
 		ORDER BY joined_at ASC
@@ -135,37 +194,42 @@ const (
 	`
 
 	queryGetAnonymousParticipantByID = `
-		SELECT id, session_id, display_name, role, status, joined_at, left_at, expires_at
+		SELECT id, session_id, display_name, role, permissions, muted_at, status, joined_at, left_at, expires_at
 		FROM anonymous_participants
 		WHERE id = $1
 	`
 
 	// invite token queries
 	queryCreateInviteToken = `
-		INSERT INTO invite_tokens (session_id, token, role, max_uses, expires_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, session_id, token, role, max_uses, uses_count, expires_at, created_at
+		INSERT INTO invite_tokens (session_id, token, role, max_uses, expires_at, email)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, session_id, token, role, max_uses, uses_count, expires_at, email, created_at
 	`
 
 	queryListInviteTokens = `
-		SELECT id, session_id, token, role, max_uses, uses_count, expires_at, created_at
+		SELECT id, session_id, token, role, max_uses, uses_count, expires_at, email, created_at
 		FROM invite_tokens
 		WHERE session_id = $1
 		ORDER BY created_at DESC
 	`
 
 	queryValidateInviteToken = `
-		SELECT id, session_id, token, role, max_uses, uses_count, expires_at, created_at
+		SELECT id, session_id, token, role, max_uses, uses_count, expires_at, email, created_at
 		FROM invite_tokens
 		WHERE token = $1
 		AND (expires_at IS NULL OR expires_at > NOW())
 		AND (max_uses IS NULL OR uses_count < max_uses)
 	`
 
-	queryIncrementTokenUses = `
+	// checks and counts the use in one statement, so concurrent joins can't
+	// use a token more often than max_uses
+	queryClaimInviteToken = `
 		UPDATE invite_tokens
 		SET uses_count = uses_count + 1
 		WHERE id = $1
+		AND (expires_at IS NULL OR expires_at > NOW())
+		AND (max_uses IS NULL OR uses_count < max_uses)
+		RETURNING id
 	`
 
 	queryRevokeInviteToken = `
@@ -173,19 +237,149 @@ const (
 		WHERE id = $1
 	`
 
-	// chat message queries (session-scoped)
+	// chat message queries (session-scoped). reactions are stored as messages
+	// but returned aggregated on the message they belong to
 	queryGetChatMessages = `
-		SELECT id, session_id, user_id, role, content, display_name, avatar_url, created_at
-		FROM session_messages
-		WHERE session_id = $1
-		ORDER BY created_at DESC
+		SELECT m.id, m.session_id, m.user_id, m.role, m.content, m.display_name, m.avatar_url, m.parent_message_id, m.created_at,
+			COALESCE((
+				SELECT json_agg(json_build_object('emoji', r.content, 'userID', r.user_id, 'displayName', r.display_name) ORDER BY r.created_at)
+				FROM session_messages r
+				WHERE r.parent_message_id = m.id AND r.message_type = 'reaction'
+			), '[]')
+		FROM session_messages m
+		WHERE m.session_id = $1 AND m.message_type = 'chat'
+		ORDER BY m.created_at DESC
 		LIMIT $2
 	`
 
+	// %s are the keyset condition, the order and the limit's placeholder
+	queryListChatMessages = `
+		SELECT m.id, m.session_id, m.user_id, m.role, m.content, m.display_name, m.avatar_url, m.parent_message_id, m.created_at,
+			COALESCE((
+				SELECT json_agg(json_build_object('emoji', r.content, 'userID', r.user_id, 'displayName', r.display_name) ORDER BY r.created_at)
+				FROM session_messages r
+				WHERE r.parent_message_id = m.id AND r.message_type = 'reaction'
+			), '[]')
+		FROM session_messages m
+		WHERE m.session_id = $1 AND m.message_type = 'chat' AND %s
+		ORDER BY %s
+		LIMIT $%d
+	`
+
+	// IDs are assigned while buffering, so a flush that is retried returns the stored row
 	queryAddChatMessage = `
-		INSERT INTO session_messages (session_id, user_id, role, content, display_name, avatar_url, message_type)
-		VALUES ($1, $2, 'user', $3, $4, $5, 'chat')
-		RETURNING id, session_id, user_id, role, content, display_name, avatar_url, created_at
+		INSERT INTO session_messages (id, session_id, user_id, role, content, display_name, avatar_url, message_type, parent_message_id)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, 'user', $4, $5, $6, 'chat', $7)
+		ON CONFLICT (id) DO UPDATE SET id = EXCLUDED.id
+		RETURNING id, session_id, user_id, role, content, display_name, avatar_url, parent_message_id, created_at
+	`
+
+	// reactors are identified by user ID, or by display name when anonymous
+	queryAddChatReaction = `
+		INSERT INTO session_messages (session_id, parent_message_id, user_id, display_name, role, content, message_type)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, NULLIF($4, ''), 'user', $5, 'reaction')
+		ON CONFLICT DO NOTHING
+	`
+
+	queryRemoveChatReaction = `
+		DELETE FROM session_messages
+		WHERE session_id = $1
+		  AND parent_message_id = $2
+		  AND message_type = 'reaction'
+		  AND COALESCE(user_id::text, display_name) = COALESCE(NULLIF($3, ''), $4)
+		  AND content = $5
+	`
+
+	// the prompt keeps the time it was asked, the response the time it
+	// arrived
+	queryAddAgentExchange = `
+		INSERT INTO session_messages (session_id, user_id, display_name, role, content, message_type, is_code_response, created_at)
+		VALUES
+			($1, $2, $3, 'user', $4, 'user_prompt', false, $5),
+			($1, $2, $3, 'assistant', $6, 'ai_response', $7, NOW())
+	`
+
+	// chat, AI exchanges and code checkpoints of a session, oldest first
+	queryListTranscript = `
+		SELECT kind, id, user_id, display_name, content, parent_message_id, is_code_response, created_at
+		FROM (
+			SELECT m.message_type AS kind, m.id, m.user_id, m.display_name, m.content, m.parent_message_id,
+				m.message_type = 'ai_response' AND COALESCE(m.is_code_response, true) AS is_code_response,
+				m.created_at
+			FROM session_messages m
+			WHERE m.session_id = $1 AND m.message_type <> 'reaction'
+			UNION ALL
+			SELECT 'code', c.id, NULL, NULL, c.code, NULL, false, c.created_at
+			FROM code_revisions c
+			WHERE c.session_id = $1
+		) t
+		ORDER BY created_at, id
+		LIMIT $2
+	`
+
+	queryAddCodeRevision = `
+		INSERT INTO code_revisions (session_id, code, created_at)
+		VALUES ($1, $2, $3)
+	`
+
+	// adds a minute of activity; instances sampling the same minute add up
+	// their counts, connections keep the peak
+	queryAddActivity = `
+		INSERT INTO session_activity_rollups AS r (
+			session_id, bucket_start, participants, listeners,
+			code_edits, agent_requests, chat_messages, playback_seconds
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (session_id, bucket_start) DO UPDATE SET
+			participants = GREATEST(r.participants, EXCLUDED.participants),
+			listeners = GREATEST(r.listeners, EXCLUDED.listeners),
+			code_edits = r.code_edits + EXCLUDED.code_edits,
+			agent_requests = r.agent_requests + EXCLUDED.agent_requests,
+			chat_messages = r.chat_messages + EXCLUDED.chat_messages,
+			playback_seconds = r.playback_seconds + EXCLUDED.playback_seconds
+	`
+
+	// rolls the minutes up into buckets of $2 seconds
+	queryGetSessionAnalytics = `
+		SELECT date_bin(make_interval(secs => $2), bucket_start, TIMESTAMPTZ '2000-01-01') AS bucket,
+			MAX(participants), MAX(listeners),
+			SUM(code_edits), SUM(agent_requests), SUM(chat_messages), SUM(playback_seconds)
+		FROM session_activity_rollups
+		WHERE session_id = $1 AND bucket_start >= $3
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+
+	// inserts a snapshot and deletes the oldest beyond the limit ($4)
+	queryCreateSnapshot = `
+		WITH created AS (
+			INSERT INTO session_snapshots (session_id, code, reason)
+			VALUES ($1, $2, $3)
+			RETURNING id, session_id, code, reason, created_at
+		), pruned AS (
+			DELETE FROM session_snapshots
+			WHERE id IN (
+				SELECT id FROM session_snapshots
+				WHERE session_id = $1
+				ORDER BY created_at DESC
+				OFFSET $4 - 1
+			)
+		)
+		SELECT id, session_id, code, reason, created_at FROM created
+	`
+
+	queryListSnapshots = `
+		SELECT id, session_id, code, reason, created_at, COUNT(*) OVER()
+		FROM session_snapshots
+		WHERE session_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	queryGetSnapshot = `
+		SELECT id, session_id, code, reason, created_at
+		FROM session_snapshots
+		WHERE id = $1 AND session_id = $2
 	`
 
 	queryUpdateLastActivity = `
@@ -215,7 +409,7 @@ const (
 	// get last user session (most recent active session where user is host)
 	// only returns host sessions - co-authors/viewers can rejoin via invite link or live sessions list
 	queryGetLastUserSession = `
-		SELECT s.id, s.host_user_id, s.title, s.code, s.is_active, s.is_discoverable, s.created_at, s.ended_at, s.last_activity
+		SELECT s.id, s.host_user_id, s.title, s.code, s.is_active, s.is_discoverable, s.chat_slow_mode_seconds, s.created_at, s.ended_at, s.last_activity
 		FROM sessions s
 		WHERE s.is_active = true
 			AND s.host_user_id = $1
@@ -224,10 +418,13 @@ const (
 	`
 
 	// cleanup queries for stale sessions
+	// sessions without their own idle timeout use the default, in seconds
 	queryListStaleSessions = `
-		SELECT id, host_user_id, title, code, is_active, is_discoverable, created_at, ended_at, last_activity
+		SELECT id, host_user_id, title, code, is_active, is_discoverable, chat_slow_mode_seconds, created_at, ended_at, last_activity,
+			auto_end_idle_hours, revoke_invites_on_end, publish_on_end
 		FROM sessions
-		WHERE is_active = true AND last_activity < $1
+		WHERE is_active = true
+		  AND last_activity < NOW() - COALESCE(make_interval(hours => auto_end_idle_hours), make_interval(secs => $1))
 	`
 
 	queryCountActiveParticipants = `
@@ -244,4 +441,32 @@ const (
 			AND (max_uses IS NULL OR uses_count < max_uses)
 		)
 	`
+
+	// bulk writes for the buffer flusher
+	queryUpdateSessionCodes = `
+		UPDATE sessions s
+		SET code = v.code, last_activity = NOW()
+		FROM unnest($1::uuid[], $2::text[]) AS v(id, code)
+		WHERE s.id = v.id
+	`
+
+	// activity of each (session, minute) must appear once, ON CONFLICT can't
+	// update a row twice in one statement
+	queryAddActivities = `
+		INSERT INTO session_activity_rollups AS r (
+			session_id, bucket_start, participants, listeners,
+			code_edits, agent_requests, chat_messages, playback_seconds
+		)
+		SELECT * FROM unnest(
+			$1::uuid[], $2::timestamptz[], $3::int[], $4::int[],
+			$5::int[], $6::int[], $7::int[], $8::float8[]
+		)
+		ON CONFLICT (session_id, bucket_start) DO UPDATE SET
+			participants = GREATEST(r.participants, EXCLUDED.participants),
+			listeners = GREATEST(r.listeners, EXCLUDED.listeners),
+			code_edits = r.code_edits + EXCLUDED.code_edits,
+			agent_requests = r.agent_requests + EXCLUDED.agent_requests,
+			chat_messages = r.chat_messages + EXCLUDED.chat_messages,
+			playback_seconds = r.playback_seconds + EXCLUDED.playback_seconds
+	`
 )
@@ -4,41 +4,70 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/database"
+	"codeberg.org/algopatterns/server/internal/keyset"
 )
 
 type repository struct {
-	db *pgxpool.Pool
+	db       *pgxpool.Pool
+	replicas *database.Router // nil reads everything from db
+}
+
+// creates the Postgres repository. lag-tolerant reads, discovery and
+// analytics, go to replicas when given
+func NewRepository(db *pgxpool.Pool, replicas *database.Router) Repository {
+	return &repository{db: db, replicas: replicas}
 }
 
-func NewRepository(db *pgxpool.Pool) Repository {
-	return &repository{db: db}
+// the pool for a lag-tolerant read
+func (r *repository) reader(ctx context.Context) *pgxpool.Pool {
+	if r.replicas == nil {
+		return r.db
+	}
+
+	return r.replicas.Reader(ctx)
 }
 
 // creates a new collaborative session
-func (r *repository) CreateSession(ctx context.Context, req *CreateSessionRequest) (*Session, error) {
+func (r *repository) CreateSession(ctx context.Context, req *CreateSessionRequest, inTx func(tx pgx.Tx, session *Session) error) (*Session, error) {
 	var session Session
 
-	err := r.db.QueryRow(
-		ctx,
-		queryCreateSession,
-		req.HostUserID,
-		req.Title,
-		req.Code,
-	).Scan(
-		&session.ID,
-		&session.HostUserID,
-		&session.Title,
-		&session.Code,
-		&session.IsActive,
-		&session.IsDiscoverable,
-		&session.CreatedAt,
-		&session.EndedAt,
-		&session.LastActivity,
-	)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(
+			ctx,
+			queryCreateSession,
+			req.HostUserID,
+			req.Title,
+			req.Code,
+		).Scan(
+			&session.ID,
+			&session.HostUserID,
+			&session.Title,
+			&session.Code,
+			&session.IsActive,
+			&session.IsDiscoverable,
+			&session.ChatSlowMode,
+			&session.CreatedAt,
+			&session.EndedAt,
+			&session.LastActivity,
+		)
+
+		if err != nil || inTx == nil {
+			return err
+		}
+
+		return inTx(tx, &session)
+	})
 
 	if err != nil {
 		return nil, err
@@ -58,6 +87,7 @@ func (r *repository) CreateAnonymousSession(ctx context.Context) (*Session, erro
 		&session.Code,
 		&session.IsActive,
 		&session.IsDiscoverable,
+		&session.ChatSlowMode,
 		&session.CreatedAt,
 		&session.EndedAt,
 		&session.LastActivity,
@@ -81,6 +111,7 @@ func (r *repository) GetSession(ctx context.Context, sessionID string) (*Session
 		&session.Code,
 		&session.IsActive,
 		&session.IsDiscoverable,
+		&session.ChatSlowMode,
 		&session.CreatedAt,
 		&session.EndedAt,
 		&session.LastActivity,
@@ -118,6 +149,7 @@ func (r *repository) GetUserSessions(ctx context.Context, userID string, activeO
 			&s.Code,
 			&s.IsActive,
 			&s.IsDiscoverable,
+			&s.ChatSlowMode,
 			&s.CreatedAt,
 			&s.EndedAt,
 			&s.LastActivity,
@@ -135,16 +167,71 @@ func (r *repository) GetUserSessions(ctx context.Context, userID string, activeO
 	return sessions, nil
 }
 
-// lists all discoverable active sessions with pagination
-func (r *repository) ListDiscoverableSessions(ctx context.Context, limit, offset int) ([]*Session, int, error) {
+// lists discoverable active sessions a page at a time, with their total
+func (r *repository) ListDiscoverableSessions(ctx context.Context, page keyset.Page) ([]*Session, keyset.Edges, int, error) {
+	db := r.reader(ctx)
+
 	// get total count first
 	var total int
 
-	if err := r.db.QueryRow(ctx, queryCountDiscoverableSessions).Scan(&total); err != nil {
+	if err := db.QueryRow(ctx, queryCountDiscoverableSessions).Scan(&total); err != nil {
+		return nil, keyset.Edges{}, 0, err
+	}
+
+	column, columnType := sessionSortColumn(page.Sort.Field)
+	where, args := page.Where(column, columnType, "id", "uuid", 1)
+	if where == "" {
+		where = "true"
+	}
+
+	query := fmt.Sprintf(queryListDiscoverableSessions, where, page.OrderBy(column, "id"), len(args)+1)
+	args = append(args, page.Fetch())
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, keyset.Edges{}, 0, err
+	}
+
+	defer rows.Close()
+	var sessions []*Session
+
+	for rows.Next() {
+		var s Session
+		err := rows.Scan(
+			&s.ID,
+			&s.HostUserID,
+			&s.Title,
+			&s.Code,
+			&s.IsActive,
+			&s.IsDiscoverable,
+			&s.ChatSlowMode,
+			&s.CreatedAt,
+			&s.EndedAt,
+			&s.LastActivity,
+		)
+		if err != nil {
+			return nil, keyset.Edges{}, 0, err
+		}
+		sessions = append(sessions, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, keyset.Edges{}, 0, err
+	}
+
+	sessions, edges := keyset.Cut(page, sessions, SessionKey(page.Sort.Field))
+	return sessions, edges, total, nil
+}
+
+// lists all sessions regardless of owner or visibility, most recently active first
+func (r *repository) ListSessions(ctx context.Context, activeOnly bool, limit, offset int) ([]*Session, int, error) {
+	var total int
+
+	if err := r.db.QueryRow(ctx, queryCountSessions, activeOnly).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 
-	rows, err := r.db.Query(ctx, queryListDiscoverableSessions, limit, offset)
+	rows, err := r.db.Query(ctx, queryListSessions, activeOnly, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -161,6 +248,7 @@ func (r *repository) ListDiscoverableSessions(ctx context.Context, limit, offset
 			&s.Code,
 			&s.IsActive,
 			&s.IsDiscoverable,
+			&s.ChatSlowMode,
 			&s.CreatedAt,
 			&s.EndedAt,
 			&s.LastActivity,
@@ -184,14 +272,47 @@ func (r *repository) SetDiscoverable(ctx context.Context, sessionID string, isDi
 	return err
 }
 
+// gets the expiry policy of a session
+func (r *repository) GetExpiryPolicy(ctx context.Context, sessionID string) (*ExpiryPolicy, error) {
+	var policy ExpiryPolicy
+
+	err := r.db.QueryRow(ctx, queryGetExpiryPolicy, sessionID).Scan(
+		&policy.AutoEndIdleHours,
+		&policy.RevokeInvitesOnEnd,
+		&policy.PublishOnEnd,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// replaces the expiry policy of a session
+func (r *repository) SetExpiryPolicy(ctx context.Context, sessionID string, policy *ExpiryPolicy) error {
+	_, err := r.db.Exec(ctx, querySetExpiryPolicy, policy.AutoEndIdleHours, policy.RevokeInvitesOnEnd, policy.PublishOnEnd, sessionID)
+	return err
+}
+
+// sets how many seconds participants wait between chat messages, 0 turns slow mode off
+func (r *repository) SetChatSlowMode(ctx context.Context, sessionID string, seconds int) error {
+	_, err := r.db.Exec(ctx, querySetChatSlowMode, seconds, sessionID)
+	return err
+}
+
 func (r *repository) UpdateSessionCode(ctx context.Context, sessionID, code string) error {
 	_, err := r.db.Exec(ctx, queryUpdateSessionCode, code, sessionID)
 	return err
 }
 
-func (r *repository) EndSession(ctx context.Context, sessionID string) error {
-	_, err := r.db.Exec(ctx, queryEndSession, sessionID)
-	return err
+func (r *repository) EndSession(ctx context.Context, sessionID string, inTx func(tx pgx.Tx) error) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, queryEndSession, sessionID); err != nil || inTx == nil {
+			return err
+		}
+
+		return inTx(tx)
+	})
 }
 
 func (r *repository) AddAuthenticatedParticipant(
@@ -213,6 +334,8 @@ func (r *repository) AddAuthenticatedParticipant(
 		&participant.UserID,
 		&participant.DisplayName,
 		&participant.Role,
+		&participant.PermissionOverride,
+		&participant.MutedAt,
 		&participant.Status,
 		&participant.JoinedAt,
 		&participant.LeftAt,
@@ -234,6 +357,8 @@ func (r *repository) GetAuthenticatedParticipant(ctx context.Context, sessionID,
 		&participant.UserID,
 		&participant.DisplayName,
 		&participant.Role,
+		&participant.PermissionOverride,
+		&participant.MutedAt,
 		&participant.Status,
 		&participant.JoinedAt,
 		&participant.LeftAt,
@@ -261,6 +386,8 @@ func (r *repository) GetParticipantByID(ctx context.Context, participantID strin
 		&authParticipant.UserID,
 		&authParticipant.DisplayName,
 		&authParticipant.Role,
+		&authParticipant.PermissionOverride,
+		&authParticipant.MutedAt,
 		&authParticipant.Status,
 		&authParticipant.JoinedAt,
 		&authParticipant.LeftAt,
@@ -268,14 +395,16 @@ func (r *repository) GetParticipantByID(ctx context.Context, participantID strin
 
 	if err == nil {
 		return &CombinedParticipant{
-			ID:          authParticipant.ID,
-			SessionID:   authParticipant.SessionID,
-			UserID:      &authParticipant.UserID,
-			DisplayName: authParticipant.DisplayName,
-			Role:        authParticipant.Role,
-			Status:      authParticipant.Status,
-			JoinedAt:    authParticipant.JoinedAt,
-			LeftAt:      authParticipant.LeftAt,
+			ID:                 authParticipant.ID,
+			SessionID:          authParticipant.SessionID,
+			UserID:             &authParticipant.UserID,
+			DisplayName:        authParticipant.DisplayName,
+			Role:               authParticipant.Role,
+			PermissionOverride: authParticipant.PermissionOverride,
+			MutedAt:            authParticipant.MutedAt,
+			Status:             authParticipant.Status,
+			JoinedAt:           authParticipant.JoinedAt,
+			LeftAt:             authParticipant.LeftAt,
 		}, nil
 	}
 
@@ -286,6 +415,8 @@ func (r *repository) GetParticipantByID(ctx context.Context, participantID strin
 		&anonParticipant.SessionID,
 		&anonParticipant.DisplayName,
 		&anonParticipant.Role,
+		&anonParticipant.PermissionOverride,
+		&anonParticipant.MutedAt,
 		&anonParticipant.Status,
 		&anonParticipant.JoinedAt,
 		&anonParticipant.LeftAt,
@@ -297,14 +428,16 @@ func (r *repository) GetParticipantByID(ctx context.Context, participantID strin
 	}
 
 	return &CombinedParticipant{
-		ID:          anonParticipant.ID,
-		SessionID:   anonParticipant.SessionID,
-		UserID:      nil,
-		DisplayName: anonParticipant.DisplayName,
-		Role:        anonParticipant.Role,
-		Status:      anonParticipant.Status,
-		JoinedAt:    anonParticipant.JoinedAt,
-		LeftAt:      anonParticipant.LeftAt,
+		ID:                 anonParticipant.ID,
+		SessionID:          anonParticipant.SessionID,
+		UserID:             nil,
+		DisplayName:        anonParticipant.DisplayName,
+		Role:               anonParticipant.Role,
+		PermissionOverride: anonParticipant.PermissionOverride,
+		MutedAt:            anonParticipant.MutedAt,
+		Status:             anonParticipant.Status,
+		JoinedAt:           anonParticipant.JoinedAt,
+		LeftAt:             anonParticipant.LeftAt,
 	}, nil
 }
 
@@ -313,6 +446,39 @@ func (r *repository) UpdateParticipantRole(ctx context.Context, participantID, r
 	return err
 }
 
+// sets the permissions of a participant (authenticated or anonymous), nil
+// goes back to the role preset
+func (r *repository) UpdateParticipantPermissions(ctx context.Context, participantID string, permissions *Permission) error {
+	// try authenticated participants first
+	result, err := r.db.Exec(ctx, queryUpdateAuthParticipantPermissions, permissions, participantID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	_, err = r.db.Exec(ctx, queryUpdateAnonParticipantPermissions, permissions, participantID)
+	return err
+}
+
+// mutes or unmutes a participant (authenticated or anonymous) in the session chat
+func (r *repository) SetParticipantMuted(ctx context.Context, participantID string, muted bool) error {
+	// try authenticated participants first
+	result, err := r.db.Exec(ctx, queryMuteAuthParticipant, muted, participantID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	_, err = r.db.Exec(ctx, queryMuteAnonParticipant, muted, participantID)
+	return err
+}
+
 func (r *repository) AddAnonymousParticipant(
 	ctx context.Context,
 	sessionID, displayName, role string,
@@ -330,6 +496,8 @@ func (r *repository) AddAnonymousParticipant(
 		&participant.SessionID,
 		&participant.DisplayName,
 		&participant.Role,
+		&participant.PermissionOverride,
+		&participant.MutedAt,
 		&participant.Status,
 		&participant.JoinedAt,
 		&participant.LeftAt,
@@ -363,6 +531,8 @@ func (r *repository) ListAllParticipants(ctx context.Context, sessionID string)
 			&p.UserID,
 			&p.DisplayName,
 			&p.Role,
+			&p.PermissionOverride,
+			&p.MutedAt,
 			&p.Status,
 			&p.JoinedAt,
 			&p.LeftAt,
@@ -372,14 +542,16 @@ func (r *repository) ListAllParticipants(ctx context.Context, sessionID string)
 		}
 
 		participants = append(participants, &CombinedParticipant{
-			ID:          p.ID,
-			SessionID:   p.SessionID,
-			UserID:      &p.UserID,
-			DisplayName: p.DisplayName,
-			Role:        p.Role,
-			Status:      p.Status,
-			JoinedAt:    p.JoinedAt,
-			LeftAt:      p.LeftAt,
+			ID:                 p.ID,
+			SessionID:          p.SessionID,
+			UserID:             &p.UserID,
+			DisplayName:        p.DisplayName,
+			Role:               p.Role,
+			PermissionOverride: p.PermissionOverride,
+			MutedAt:            p.MutedAt,
+			Status:             p.Status,
+			JoinedAt:           p.JoinedAt,
+			LeftAt:             p.LeftAt,
 		})
 	}
 
@@ -402,6 +574,8 @@ func (r *repository) ListAllParticipants(ctx context.Context, sessionID string)
 			&p.SessionID,
 			&p.DisplayName,
 			&p.Role,
+			&p.PermissionOverride,
+			&p.MutedAt,
 			&p.Status,
 			&p.JoinedAt,
 			&p.LeftAt,
@@ -412,14 +586,16 @@ func (r *repository) ListAllParticipants(ctx context.Context, sessionID string)
 		}
 
 		participants = append(participants, &CombinedParticipant{
-			ID:          p.ID,
-			SessionID:   p.SessionID,
-			UserID:      nil,
-			DisplayName: p.DisplayName,
-			Role:        p.Role,
-			Status:      p.Status,
-			JoinedAt:    p.JoinedAt,
-			LeftAt:      p.LeftAt,
+			ID:                 p.ID,
+			SessionID:          p.SessionID,
+			UserID:             nil,
+			DisplayName:        p.DisplayName,
+			Role:               p.Role,
+			PermissionOverride: p.PermissionOverride,
+			MutedAt:            p.MutedAt,
+			Status:             p.Status,
+			JoinedAt:           p.JoinedAt,
+			LeftAt:             p.LeftAt,
 		})
 	}
 
@@ -447,33 +623,49 @@ func (r *repository) RemoveParticipant(ctx context.Context, participantID string
 	return err
 }
 
-// creates a new invite token for a session
-func (r *repository) CreateInviteToken(ctx context.Context, req *CreateInviteTokenRequest) (*InviteToken, error) {
+// creates a new invite token for a session. tokens bound to an email are single-use
+func (r *repository) CreateInviteToken(ctx context.Context, req *CreateInviteTokenRequest, inTx func(tx pgx.Tx, token *InviteToken) error) (*InviteToken, error) {
 	token, err := generateToken()
 	if err != nil {
 		return nil, err
 	}
 
+	maxUses := req.MaxUses
+	if req.Email != nil {
+		single := 1
+		maxUses = &single
+	}
+
 	var inviteToken InviteToken
 
-	err = r.db.QueryRow(
-		ctx,
-		queryCreateInviteToken,
-		req.SessionID,
-		token,
-		req.Role,
-		req.MaxUses,
-		req.ExpiresAt,
-	).Scan(
-		&inviteToken.ID,
-		&inviteToken.SessionID,
-		&inviteToken.Token,
-		&inviteToken.Role,
-		&inviteToken.MaxUses,
-		&inviteToken.UsesCount,
-		&inviteToken.ExpiresAt,
-		&inviteToken.CreatedAt,
-	)
+	err = pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(
+			ctx,
+			queryCreateInviteToken,
+			req.SessionID,
+			token,
+			req.Role,
+			maxUses,
+			req.ExpiresAt,
+			req.Email,
+		).Scan(
+			&inviteToken.ID,
+			&inviteToken.SessionID,
+			&inviteToken.Token,
+			&inviteToken.Role,
+			&inviteToken.MaxUses,
+			&inviteToken.UsesCount,
+			&inviteToken.ExpiresAt,
+			&inviteToken.Email,
+			&inviteToken.CreatedAt,
+		)
+
+		if err != nil || inTx == nil {
+			return err
+		}
+
+		return inTx(tx, &inviteToken)
+	})
 
 	if err != nil {
 		return nil, err
@@ -503,6 +695,7 @@ func (r *repository) ListInviteTokens(ctx context.Context, sessionID string) ([]
 			&t.MaxUses,
 			&t.UsesCount,
 			&t.ExpiresAt,
+			&t.Email,
 			&t.CreatedAt,
 		)
 		if err != nil {
@@ -531,6 +724,7 @@ func (r *repository) ValidateInviteToken(ctx context.Context, token string) (*In
 		&inviteToken.MaxUses,
 		&inviteToken.UsesCount,
 		&inviteToken.ExpiresAt,
+		&inviteToken.Email,
 		&inviteToken.CreatedAt,
 	)
 
@@ -541,9 +735,16 @@ func (r *repository) ValidateInviteToken(ctx context.Context, token string) (*In
 	return &inviteToken, nil
 }
 
-func (r *repository) IncrementTokenUses(ctx context.Context, tokenID string) error {
-	_, err := r.db.Exec(ctx, queryIncrementTokenUses, tokenID)
-	return err
+// counts a use of an invite token. returns false when the token expired or
+// ran out of uses since it was validated
+func (r *repository) ClaimInviteToken(ctx context.Context, tokenID string) (bool, error) {
+	var id string
+	err := r.db.QueryRow(ctx, queryClaimInviteToken, tokenID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+
+	return err == nil, err
 }
 
 func (r *repository) RevokeInviteToken(ctx context.Context, tokenID string) error {
@@ -563,6 +764,59 @@ func (r *repository) GetChatMessages(ctx context.Context, sessionID string, limi
 
 	for rows.Next() {
 		var m Message
+		var reactions []byte
+		err := rows.Scan(
+			&m.ID,
+			&m.SessionID,
+			&m.UserID,
+			&m.Role,
+			&m.Content,
+			&m.DisplayName,
+			&m.AvatarURL,
+			&m.ParentMessageID,
+			&m.CreatedAt,
+			&reactions,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(reactions, &m.Reactions); err != nil {
+			return nil, fmt.Errorf("failed to decode reactions: %w", err)
+		}
+
+		messages = append(messages, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// fetches up to page.Fetch() chat messages past the page's position, in
+// fetch order. keyset.Cut with MessageKey turns them into the page
+func (r *repository) ListChatMessages(ctx context.Context, sessionID string, page keyset.Page) ([]*Message, error) {
+	where, args := page.Where("m.created_at", "timestamptz", "m.id", "uuid", 2)
+	if where == "" {
+		where = "true"
+	}
+
+	query := fmt.Sprintf(queryListChatMessages, where, page.OrderBy("m.created_at", "m.id"), len(args)+2)
+	args = append([]any{sessionID}, append(args, page.Fetch())...)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var messages []*Message
+
+	for rows.Next() {
+		var m Message
+		var reactions []byte
 		err := rows.Scan(
 			&m.ID,
 			&m.SessionID,
@@ -571,11 +825,18 @@ func (r *repository) GetChatMessages(ctx context.Context, sessionID string, limi
 			&m.Content,
 			&m.DisplayName,
 			&m.AvatarURL,
+			&m.ParentMessageID,
 			&m.CreatedAt,
+			&reactions,
 		)
 		if err != nil {
 			return nil, err
 		}
+
+		if err := json.Unmarshal(reactions, &m.Reactions); err != nil {
+			return nil, fmt.Errorf("failed to decode reactions: %w", err)
+		}
+
 		messages = append(messages, &m)
 	}
 
@@ -587,35 +848,39 @@ func (r *repository) GetChatMessages(ctx context.Context, sessionID string, limi
 }
 
 // adds a chat message to the session
-func (r *repository) AddChatMessage(
-	ctx context.Context,
-	sessionID, userID, content, displayName, avatarURL string,
-) (*Message, error) {
+func (r *repository) AddChatMessage(ctx context.Context, req *AddChatMessageRequest) (*Message, error) {
 	// convert empty strings to nil pointers
 	var userIDPtr *string
-	if userID != "" {
-		userIDPtr = &userID
+	if req.UserID != "" {
+		userIDPtr = &req.UserID
 	}
 
 	var displayNamePtr *string
-	if displayName != "" {
-		displayNamePtr = &displayName
+	if req.DisplayName != "" {
+		displayNamePtr = &req.DisplayName
 	}
 
 	var avatarURLPtr *string
-	if avatarURL != "" {
-		avatarURLPtr = &avatarURL
+	if req.AvatarURL != "" {
+		avatarURLPtr = &req.AvatarURL
+	}
+
+	var parentIDPtr *string
+	if req.ParentMessageID != "" {
+		parentIDPtr = &req.ParentMessageID
 	}
 
 	var message Message
 	err := r.db.QueryRow(
 		ctx,
 		queryAddChatMessage,
-		sessionID,
+		req.ID,
+		req.SessionID,
 		userIDPtr,
-		content,
+		req.Content,
 		displayNamePtr,
 		avatarURLPtr,
+		parentIDPtr,
 	).Scan(
 		&message.ID,
 		&message.SessionID,
@@ -624,6 +889,7 @@ func (r *repository) AddChatMessage(
 		&message.Content,
 		&message.DisplayName,
 		&message.AvatarURL,
+		&message.ParentMessageID,
 		&message.CreatedAt,
 	)
 
@@ -634,12 +900,196 @@ func (r *repository) AddChatMessage(
 	return &message, nil
 }
 
+// adds an emoji reaction to a chat message. reacting twice with the same emoji is a no-op
+func (r *repository) AddChatReaction(ctx context.Context, sessionID, messageID, userID, displayName, emoji string) error {
+	_, err := r.db.Exec(ctx, queryAddChatReaction, sessionID, messageID, userID, displayName, emoji)
+	return err
+}
+
+// removes an emoji reaction from a chat message
+func (r *repository) RemoveChatReaction(ctx context.Context, sessionID, messageID, userID, displayName, emoji string) error {
+	_, err := r.db.Exec(ctx, queryRemoveChatReaction, sessionID, messageID, userID, displayName, emoji)
+	return err
+}
+
 // updates the last activity timestamp for a session
 func (r *repository) UpdateLastActivity(ctx context.Context, sessionID string) error {
 	_, err := r.db.Exec(ctx, queryUpdateLastActivity, sessionID)
 	return err
 }
 
+// stores a code checkpoint for a session
+func (r *repository) AddCodeRevision(ctx context.Context, sessionID, code string, createdAt time.Time) error {
+	_, err := r.db.Exec(ctx, queryAddCodeRevision, sessionID, code, createdAt)
+	return err
+}
+
+// stores a prompt to the AI assistant and its response
+func (r *repository) AddAgentExchange(ctx context.Context, exchange *AgentExchange) error {
+	_, err := r.db.Exec(ctx, queryAddAgentExchange,
+		exchange.SessionID,
+		nilIfEmpty(exchange.UserID),
+		nilIfEmpty(exchange.DisplayName),
+		exchange.Prompt,
+		exchange.PromptedAt,
+		exchange.Response,
+		exchange.IsCodeResponse,
+	)
+	return err
+}
+
+// retrieves up to limit transcript entries of a session, oldest first
+func (r *repository) ListTranscript(ctx context.Context, sessionID string, limit int) ([]*TranscriptEntry, error) {
+	rows, err := r.db.Query(ctx, queryListTranscript, sessionID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var entries []*TranscriptEntry
+
+	for rows.Next() {
+		var e TranscriptEntry
+		err := rows.Scan(
+			&e.Kind,
+			&e.ID,
+			&e.UserID,
+			&e.DisplayName,
+			&e.Content,
+			&e.ParentMessageID,
+			&e.IsCodeResponse,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// adds a sample to the session's activity rollup for its minute
+func (r *repository) AddActivity(ctx context.Context, sample *ActivitySample) error {
+	_, err := r.db.Exec(ctx, queryAddActivity,
+		sample.SessionID,
+		sample.BucketStart,
+		sample.Participants,
+		sample.Listeners,
+		sample.CodeEdits,
+		sample.AgentRequests,
+		sample.ChatMessages,
+		sample.PlaybackSeconds,
+	)
+	return err
+}
+
+// returns the session's activity since the given time in buckets of the
+// given size, oldest first. buckets without activity are left out
+func (r *repository) GetSessionAnalytics(ctx context.Context, sessionID string, bucket time.Duration, since time.Time) ([]*ActivitySample, error) {
+	rows, err := r.reader(ctx).Query(ctx, queryGetSessionAnalytics, sessionID, bucket.Seconds(), since)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	samples := []*ActivitySample{}
+
+	for rows.Next() {
+		var s ActivitySample
+		err := rows.Scan(
+			&s.BucketStart,
+			&s.Participants,
+			&s.Listeners,
+			&s.CodeEdits,
+			&s.AgentRequests,
+			&s.ChatMessages,
+			&s.PlaybackSeconds,
+		)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, &s)
+	}
+
+	return samples, rows.Err()
+}
+
+// stores a snapshot of session code, dropping the oldest beyond MaxSnapshotsPerSession
+func (r *repository) CreateSnapshot(ctx context.Context, sessionID, code, reason string) (*Snapshot, error) {
+	var s Snapshot
+
+	err := r.db.QueryRow(ctx, queryCreateSnapshot, sessionID, code, reason, MaxSnapshotsPerSession).Scan(
+		&s.ID,
+		&s.SessionID,
+		&s.Code,
+		&s.Reason,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// lists the snapshots of a session, newest first
+func (r *repository) ListSnapshots(ctx context.Context, sessionID string, limit, offset int) ([]*Snapshot, int, error) {
+	rows, err := r.db.Query(ctx, queryListSnapshots, sessionID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	snapshots := []*Snapshot{}
+	total := 0
+
+	for rows.Next() {
+		var s Snapshot
+		err := rows.Scan(
+			&s.ID,
+			&s.SessionID,
+			&s.Code,
+			&s.Reason,
+			&s.CreatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		snapshots = append(snapshots, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return snapshots, total, nil
+}
+
+// retrieves a snapshot of a session
+func (r *repository) GetSnapshot(ctx context.Context, sessionID, snapshotID string) (*Snapshot, error) {
+	var s Snapshot
+
+	err := r.db.QueryRow(ctx, queryGetSnapshot, snapshotID, sessionID).Scan(
+		&s.ID,
+		&s.SessionID,
+		&s.Code,
+		&s.Reason,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
 // generates a cryptographically secure random token
 func generateToken() (string, error) {
 	bytes := make([]byte, 32)
@@ -683,6 +1133,7 @@ func (r *repository) GetLastUserSession(ctx context.Context, userID string) (*Se
 		&session.Code,
 		&session.IsActive,
 		&session.IsDiscoverable,
+		&session.ChatSlowMode,
 		&session.CreatedAt,
 		&session.EndedAt,
 		&session.LastActivity,
@@ -695,18 +1146,19 @@ func (r *repository) GetLastUserSession(ctx context.Context, userID string) (*Se
 	return &session, nil
 }
 
-// lists all sessions that have been inactive for longer than the threshold
-func (r *repository) ListStaleSessions(ctx context.Context, threshold time.Time) ([]*Session, error) {
-	rows, err := r.db.Query(ctx, queryListStaleSessions, threshold)
+// lists active sessions idle for longer than their own timeout, or
+// inactivityThreshold for sessions without one
+func (r *repository) ListStaleSessions(ctx context.Context, inactivityThreshold time.Duration) ([]*StaleSession, error) {
+	rows, err := r.db.Query(ctx, queryListStaleSessions, inactivityThreshold.Seconds())
 	if err != nil {
 		return nil, err
 	}
 
 	defer rows.Close()
-	var sessions []*Session
+	var sessions []*StaleSession
 
 	for rows.Next() {
-		var s Session
+		var s StaleSession
 		err := rows.Scan(
 			&s.ID,
 			&s.HostUserID,
@@ -714,9 +1166,13 @@ func (r *repository) ListStaleSessions(ctx context.Context, threshold time.Time)
 			&s.Code,
 			&s.IsActive,
 			&s.IsDiscoverable,
+			&s.ChatSlowMode,
 			&s.CreatedAt,
 			&s.EndedAt,
 			&s.LastActivity,
+			&s.Policy.AutoEndIdleHours,
+			&s.Policy.RevokeInvitesOnEnd,
+			&s.Policy.PublishOnEnd,
 		)
 		if err != nil {
 			return nil, err
@@ -724,11 +1180,7 @@ func (r *repository) ListStaleSessions(ctx context.Context, threshold time.Time)
 		sessions = append(sessions, &s)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return sessions, nil
+	return sessions, rows.Err()
 }
 
 // counts active participants (both authenticated and anonymous)
@@ -744,3 +1196,26 @@ func (r *repository) HasActiveInviteTokens(ctx context.Context, sessionID string
 	err := r.db.QueryRow(ctx, queryHasActiveInviteTokens, sessionID).Scan(&exists)
 	return exists, err
 }
+
+var messageIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// generates a random (version 4) UUID for a chat message, so messages can be
+// referenced by replies and reactions before they are persisted
+func NewMessageID() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate message id: %w", err)
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+// reports whether id has the format of a message ID
+func IsValidMessageID(id string) bool {
+	return messageIDPattern.MatchString(strings.ToLower(id))
+}
@@ -0,0 +1,163 @@
+//go:build integration
+
+package sessions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/internal/testdb"
+)
+
+// a repository on the test database and a session hosted by a fresh user
+func testSession(t *testing.T) (Repository, *Session) {
+	t.Helper()
+
+	db := testdb.Open(t)
+	repo := NewRepository(db, nil)
+
+	host := testdb.SeedUser(t, db, "host")
+	session, err := repo.CreateSession(context.Background(), &CreateSessionRequest{
+		HostUserID: host,
+		Title:      "jam " + testdb.Unique(t),
+		Code:       `s("bd sd")`,
+	}, nil)
+	require.NoError(t, err)
+
+	return repo, session
+}
+
+func TestSessionCodeRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo, session := testSession(t)
+
+	assert.True(t, session.IsActive)
+	assert.Equal(t, `s("bd sd")`, session.Code)
+
+	require.NoError(t, repo.UpdateSessionCode(ctx, session.ID, `s("hh*8")`))
+
+	got, err := repo.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, `s("hh*8")`, got.Code)
+	assert.Equal(t, session.Title, got.Title)
+}
+
+func TestUpdateSessionCodes(t *testing.T) {
+	ctx := context.Background()
+	repo, first := testSession(t)
+	_, second := testSession(t)
+
+	require.NoError(t, repo.UpdateSessionCodes(ctx, []SessionCode{
+		{SessionID: first.ID, Code: `note("c e g")`},
+		{SessionID: second.ID, Code: `note("a c e")`},
+	}))
+
+	for id, want := range map[string]string{first.ID: `note("c e g")`, second.ID: `note("a c e")`} {
+		got, err := repo.GetSession(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, want, got.Code)
+	}
+}
+
+func TestChatMessagesAndReactions(t *testing.T) {
+	ctx := context.Background()
+	repo, session := testSession(t)
+
+	first, err := NewMessageID()
+	require.NoError(t, err)
+	second, err := NewMessageID()
+	require.NoError(t, err)
+
+	_, err = repo.AddChatMessage(ctx, &AddChatMessageRequest{
+		ID:          first,
+		SessionID:   session.ID,
+		UserID:      session.HostUserID,
+		Content:     "hello",
+		DisplayName: "host",
+	})
+	require.NoError(t, err)
+
+	// a reply and a reaction to the first message, written together the way
+	// the session buffer flushes them
+	require.NoError(t, repo.AddChatEntries(ctx, []ChatEntry{
+		{AddChatMessageRequest: AddChatMessageRequest{
+			ID: second, SessionID: session.ID, Content: "hi", DisplayName: "guest", ParentMessageID: first,
+		}},
+		{Kind: ChatEntryReactionAdded, AddChatMessageRequest: AddChatMessageRequest{
+			SessionID: session.ID, Content: "🔥", DisplayName: "guest", ParentMessageID: first,
+		}},
+	}))
+
+	messages, err := repo.GetChatMessages(ctx, session.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 2, "reactions aren't messages")
+
+	byID := map[string]*Message{}
+	for _, m := range messages {
+		byID[m.ID] = m
+	}
+
+	require.Contains(t, byID, first)
+	assert.Equal(t, "hello", byID[first].Content)
+	require.Len(t, byID[first].Reactions, 1)
+	assert.Equal(t, "🔥", byID[first].Reactions[0].Emoji)
+
+	require.Contains(t, byID, second)
+	require.NotNil(t, byID[second].ParentMessageID)
+	assert.Equal(t, first, *byID[second].ParentMessageID)
+	assert.Nil(t, byID[second].UserID, "anonymous messages have no user")
+}
+
+func TestAddCodeRevisions(t *testing.T) {
+	ctx := context.Background()
+	repo, session := testSession(t)
+	db := testdb.Open(t)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(t, repo.AddCodeRevisions(ctx, []CodeRevision{
+		{SessionID: session.ID, Code: `s("bd")`, CreatedAt: now.Add(-time.Minute)},
+		{SessionID: session.ID, Code: `s("bd sd")`, CreatedAt: now},
+	}))
+
+	var latest string
+	var count int
+	err := db.QueryRow(ctx, `
+		SELECT count(*), (SELECT code FROM code_revisions WHERE session_id = $1 ORDER BY created_at DESC LIMIT 1)
+		FROM code_revisions WHERE session_id = $1
+	`, session.ID).Scan(&count, &latest)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, `s("bd sd")`, latest)
+
+	assert.Error(t, repo.AddCodeRevisions(ctx, []CodeRevision{{SessionID: "not-a-uuid"}}))
+}
+
+func TestEndSessionCommitsWithItsEvents(t *testing.T) {
+	ctx := context.Background()
+	repo, session := testSession(t)
+
+	err := repo.EndSession(ctx, session.ID, func(tx pgx.Tx) error {
+		var active bool
+		require.NoError(t, tx.QueryRow(ctx, `SELECT is_active FROM sessions WHERE id = $1`, session.ID).Scan(&active))
+		assert.False(t, active, "inTx sees the session ended")
+
+		return errors.New("job queue unavailable")
+	})
+	require.Error(t, err)
+
+	got, err := repo.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsActive, "a session whose events can't be queued isn't ended")
+
+	require.NoError(t, repo.EndSession(ctx, session.ID, func(pgx.Tx) error { return nil }))
+
+	got, err = repo.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.False(t, got.IsActive)
+}
@@ -0,0 +1,44 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessageID(t *testing.T) {
+	id, err := NewMessageID()
+	require.NoError(t, err)
+	assert.True(t, IsValidMessageID(id), id)
+
+	other, err := NewMessageID()
+	require.NoError(t, err)
+	assert.NotEqual(t, id, other)
+}
+
+func TestIsValidMessageID(t *testing.T) {
+	assert.True(t, IsValidMessageID("7F0C9A52-3D1E-4B8A-9C61-0E2F4D5A6B7C"))
+	assert.False(t, IsValidMessageID(""))
+	assert.False(t, IsValidMessageID("42"))
+	assert.False(t, IsValidMessageID("7f0c9a52-3d1e-4b8a-9c61-0e2f4d5a6b7c'; --"))
+}
+
+func TestMergeActivity(t *testing.T) {
+	minute := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+
+	merged := mergeActivity([]ActivitySample{
+		{SessionID: "s1", BucketStart: minute, Participants: 3, Listeners: 1, CodeEdits: 4, PlaybackSeconds: 30},
+		{SessionID: "s2", BucketStart: minute, Participants: 1},
+		{SessionID: "s1", BucketStart: minute, Participants: 2, Listeners: 5, CodeEdits: 6, ChatMessages: 1, PlaybackSeconds: 15},
+		{SessionID: "s1", BucketStart: minute.Add(time.Minute), CodeEdits: 1},
+	})
+
+	require.Len(t, merged, 3)
+	assert.Equal(t, ActivitySample{
+		SessionID: "s1", BucketStart: minute, Participants: 3, Listeners: 5, CodeEdits: 10, ChatMessages: 1, PlaybackSeconds: 45,
+	}, merged[0])
+	assert.Equal(t, "s2", merged[1].SessionID)
+	assert.Equal(t, 1, merged[2].CodeEdits)
+}
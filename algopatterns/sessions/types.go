@@ -3,6 +3,10 @@ package sessions
 import (
 	"context"
 	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/internal/keyset"
 )
 
 // message type constants for session messages (must match DB check constraint)
@@ -15,17 +19,32 @@ const (
 // SystemUserID is the UUID for anonymous sessions (nil UUID pattern)
 const SystemUserID = "00000000-0000-0000-0000-000000000000"
 
-// repository interface for session database operations
+// snapshot reasons (must match DB check constraint)
+const (
+	SnapshotReasonAuto       = "auto"        // taken periodically while the session is edited
+	SnapshotReasonPreRestore = "pre_restore" // the code a restore replaced
+)
+
+// number of snapshots kept per session, older ones are deleted
+const MaxSnapshotsPerSession = 200
+
+// repository interface for session database operations. the writes taking
+// inTx run it, when set, in their transaction, so work like queueing the
+// write's events commits or rolls back with it
 type Repository interface {
 	// session operations
-	CreateSession(ctx context.Context, req *CreateSessionRequest) (*Session, error)
+	CreateSession(ctx context.Context, req *CreateSessionRequest, inTx func(tx pgx.Tx, session *Session) error) (*Session, error)
 	CreateAnonymousSession(ctx context.Context) (*Session, error)
 	GetSession(ctx context.Context, sessionID string) (*Session, error)
 	GetUserSessions(ctx context.Context, userID string, activeOnly bool) ([]*Session, error)
-	ListDiscoverableSessions(ctx context.Context, limit, offset int) ([]*Session, int, error)
+	ListDiscoverableSessions(ctx context.Context, page keyset.Page) ([]*Session, keyset.Edges, int, error)
+	ListSessions(ctx context.Context, activeOnly bool, limit, offset int) ([]*Session, int, error)
 	UpdateSessionCode(ctx context.Context, sessionID, code string) error
 	SetDiscoverable(ctx context.Context, sessionID string, isDiscoverable bool) error
-	EndSession(ctx context.Context, sessionID string) error
+	SetChatSlowMode(ctx context.Context, sessionID string, seconds int) error
+	GetExpiryPolicy(ctx context.Context, sessionID string) (*ExpiryPolicy, error)
+	SetExpiryPolicy(ctx context.Context, sessionID string, policy *ExpiryPolicy) error
+	EndSession(ctx context.Context, sessionID string, inTx func(tx pgx.Tx) error) error
 
 	// authenticated participant operations
 	AddAuthenticatedParticipant(ctx context.Context, sessionID, userID, displayName, role string) (*Participant, error)
@@ -33,6 +52,8 @@ type Repository interface {
 	MarkAuthenticatedParticipantLeft(ctx context.Context, participantID string) error
 	GetParticipantByID(ctx context.Context, participantID string) (*CombinedParticipant, error)
 	UpdateParticipantRole(ctx context.Context, participantID, role string) error
+	UpdateParticipantPermissions(ctx context.Context, participantID string, permissions *Permission) error
+	SetParticipantMuted(ctx context.Context, participantID string, muted bool) error
 
 	// anonymous participant operations
 	AddAnonymousParticipant(ctx context.Context, sessionID, displayName, role string) (*AnonymousParticipant, error)
@@ -42,24 +63,51 @@ type Repository interface {
 	RemoveParticipant(ctx context.Context, participantID string) error
 
 	// invite token operations
-	CreateInviteToken(ctx context.Context, req *CreateInviteTokenRequest) (*InviteToken, error)
+	CreateInviteToken(ctx context.Context, req *CreateInviteTokenRequest, inTx func(tx pgx.Tx, token *InviteToken) error) (*InviteToken, error)
 	ListInviteTokens(ctx context.Context, sessionID string) ([]*InviteToken, error)
 	ValidateInviteToken(ctx context.Context, token string) (*InviteToken, error)
-	IncrementTokenUses(ctx context.Context, tokenID string) error
+	ClaimInviteToken(ctx context.Context, tokenID string) (bool, error)
 	RevokeInviteToken(ctx context.Context, tokenID string) error
 	RevokeAllInviteTokens(ctx context.Context, sessionID string) error
 	HasActiveInviteTokens(ctx context.Context, sessionID string) (bool, error)
 
 	// chat message operations (session-scoped, for real-time communication)
 	GetChatMessages(ctx context.Context, sessionID string, limit int) ([]*Message, error)
-	AddChatMessage(ctx context.Context, sessionID, userID, content, displayName, avatarURL string) (*Message, error)
+	ListChatMessages(ctx context.Context, sessionID string, page keyset.Page) ([]*Message, error)
+	AddChatMessage(ctx context.Context, req *AddChatMessageRequest) (*Message, error)
+	AddChatReaction(ctx context.Context, sessionID, messageID, userID, displayName, emoji string) error
+	RemoveChatReaction(ctx context.Context, sessionID, messageID, userID, displayName, emoji string) error
 	UpdateLastActivity(ctx context.Context, sessionID string) error
 
+	// code revision operations (checkpoints backing undo/redo)
+	AddCodeRevision(ctx context.Context, sessionID, code string, createdAt time.Time) error
+
+	// AI assistant exchanges and the transcript interleaving them with chat
+	// and code checkpoints
+	AddAgentExchange(ctx context.Context, exchange *AgentExchange) error
+	ListTranscript(ctx context.Context, sessionID string, limit int) ([]*TranscriptEntry, error)
+
+	// activity rollups behind session analytics (written by the buffer flusher)
+	AddActivity(ctx context.Context, sample *ActivitySample) error
+	GetSessionAnalytics(ctx context.Context, sessionID string, bucket time.Duration, since time.Time) ([]*ActivitySample, error)
+
+	// snapshot operations (restorable copies of session code)
+	CreateSnapshot(ctx context.Context, sessionID, code, reason string) (*Snapshot, error)
+	ListSnapshots(ctx context.Context, sessionID string, limit, offset int) ([]*Snapshot, int, error)
+	GetSnapshot(ctx context.Context, sessionID, snapshotID string) (*Snapshot, error)
+
 	// soft-end and cleanup operations
 	MarkAllNonHostParticipantsLeft(ctx context.Context, sessionID, hostUserID string) error
 	GetLastUserSession(ctx context.Context, userID string) (*Session, error)
-	ListStaleSessions(ctx context.Context, threshold time.Time) ([]*Session, error)
+	ListStaleSessions(ctx context.Context, inactivityThreshold time.Duration) ([]*StaleSession, error)
 	CountActiveParticipants(ctx context.Context, sessionID string) (int, error)
+
+	// bulk writes of many sessions' buffered data (used by the buffer flusher).
+	// each call writes everything or nothing
+	UpdateSessionCodes(ctx context.Context, codes []SessionCode) error
+	AddChatEntries(ctx context.Context, entries []ChatEntry) error
+	AddCodeRevisions(ctx context.Context, revisions []CodeRevision) error
+	AddActivities(ctx context.Context, samples []ActivitySample) error
 }
 
 // represents a collaborative coding session
@@ -70,45 +118,80 @@ type Session struct {
 	Code           string     `json:"code"`
 	IsActive       bool       `json:"is_active"`
 	IsDiscoverable bool       `json:"is_discoverable"`
+	ChatSlowMode   int        `json:"chat_slow_mode_seconds"` // seconds between a participant's chat messages, 0 = off
 	CreatedAt      time.Time  `json:"created_at"`
 	EndedAt        *time.Time `json:"ended_at,omitempty"`
 	LastActivity   time.Time  `json:"last_activity"`
 }
 
+// how the cleanup service treats a session, set by its host
+type ExpiryPolicy struct {
+	AutoEndIdleHours   *int `json:"auto_end_idle_hours"`   // nil uses the server default
+	RevokeInvitesOnEnd bool `json:"revoke_invites_on_end"` // revoke invite tokens when the session is ended
+	PublishOnEnd       bool `json:"publish_on_end"`        // save the final code as a public strudel of the host
+}
+
+// a session the cleanup service will end, with its expiry policy
+type StaleSession struct {
+	Session
+	Policy ExpiryPolicy
+}
+
 // represents a user in a session
 type Participant struct {
-	ID          string     `json:"id"`
-	SessionID   string     `json:"session_id"`
-	UserID      string     `json:"user_id"`
-	DisplayName string     `json:"display_name"`
-	Role        string     `json:"role"`
-	Status      string     `json:"status"`
-	JoinedAt    time.Time  `json:"joined_at"`
-	LeftAt      *time.Time `json:"left_at,omitempty"`
+	ID                 string      `json:"id"`
+	SessionID          string      `json:"session_id"`
+	UserID             string      `json:"user_id"`
+	DisplayName        string      `json:"display_name"`
+	Role               string      `json:"role"`
+	PermissionOverride *Permission `json:"-"`                  // nil uses the role preset
+	MutedAt            *time.Time  `json:"muted_at,omitempty"` // muted participants can't chat
+	Status             string      `json:"status"`
+	JoinedAt           time.Time   `json:"joined_at"`
+	LeftAt             *time.Time  `json:"left_at,omitempty"`
 }
 
 // represents an anonymous user in a session
 type AnonymousParticipant struct {
-	ID          string     `json:"id"`
-	SessionID   string     `json:"session_id"`
-	DisplayName string     `json:"display_name"`
-	Role        string     `json:"role"`
-	Status      string     `json:"status"`
-	JoinedAt    time.Time  `json:"joined_at"`
-	LeftAt      *time.Time `json:"left_at,omitempty"`
-	ExpiresAt   time.Time  `json:"expires_at"`
+	ID                 string      `json:"id"`
+	SessionID          string      `json:"session_id"`
+	DisplayName        string      `json:"display_name"`
+	Role               string      `json:"role"`
+	PermissionOverride *Permission `json:"-"`                  // nil uses the role preset
+	MutedAt            *time.Time  `json:"muted_at,omitempty"` // muted participants can't chat
+	Status             string      `json:"status"`
+	JoinedAt           time.Time   `json:"joined_at"`
+	LeftAt             *time.Time  `json:"left_at,omitempty"`
+	ExpiresAt          time.Time   `json:"expires_at"`
 }
 
 // represents either an authenticated or anonymous participant
 type CombinedParticipant struct {
-	ID          string     `json:"id"`
-	SessionID   string     `json:"session_id"`
-	UserID      *string    `json:"user_id,omitempty"`
-	DisplayName string     `json:"display_name"`
-	Role        string     `json:"role"`
-	Status      string     `json:"status"`
-	JoinedAt    time.Time  `json:"joined_at"`
-	LeftAt      *time.Time `json:"left_at,omitempty"`
+	ID                 string      `json:"id"`
+	SessionID          string      `json:"session_id"`
+	UserID             *string     `json:"user_id,omitempty"`
+	DisplayName        string      `json:"display_name"`
+	Role               string      `json:"role"`
+	PermissionOverride *Permission `json:"-"`                  // nil uses the role preset
+	MutedAt            *time.Time  `json:"muted_at,omitempty"` // muted participants can't chat
+	Status             string      `json:"status"`
+	JoinedAt           time.Time   `json:"joined_at"`
+	LeftAt             *time.Time  `json:"left_at,omitempty"`
+}
+
+// returns what the participant may do in the session
+func (p *Participant) Permissions() Permission {
+	return EffectivePermissions(p.Role, p.PermissionOverride)
+}
+
+// returns what the participant may do in the session
+func (p *AnonymousParticipant) Permissions() Permission {
+	return EffectivePermissions(p.Role, p.PermissionOverride)
+}
+
+// returns what the participant may do in the session
+func (p *CombinedParticipant) Permissions() Permission {
+	return EffectivePermissions(p.Role, p.PermissionOverride)
 }
 
 // represents a session invite token
@@ -120,19 +203,117 @@ type InviteToken struct {
 	MaxUses   *int       `json:"max_uses,omitempty"`
 	UsesCount int        `json:"uses_count"`
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Email     *string    `json:"email,omitempty"` // only this user can join with the token
 	CreatedAt time.Time  `json:"created_at"`
 }
 
 // represents a chat message in a session
 type Message struct {
-	ID          string    `json:"id"`
-	SessionID   string    `json:"sessionID"`
-	UserID      *string   `json:"userID,omitempty"`
-	Role        string    `json:"role"` // user
-	Content     string    `json:"content"`
-	DisplayName *string   `json:"displayName,omitempty"`
-	AvatarURL   *string   `json:"avatarUrl,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID              string     `json:"id"`
+	SessionID       string     `json:"sessionID"`
+	UserID          *string    `json:"userID,omitempty"`
+	Role            string     `json:"role"` // user
+	Content         string     `json:"content"`
+	DisplayName     *string    `json:"displayName,omitempty"`
+	AvatarURL       *string    `json:"avatarUrl,omitempty"`
+	ParentMessageID *string    `json:"parentMessageID,omitempty"` // set for replies
+	Reactions       []Reaction `json:"reactions,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// represents an emoji reaction to a chat message
+type Reaction struct {
+	Emoji       string  `json:"emoji"`
+	UserID      *string `json:"userID,omitempty"`
+	DisplayName *string `json:"displayName,omitempty"`
+}
+
+// request to add a chat message. the database assigns an ID if none is given
+type AddChatMessageRequest struct {
+	ID              string
+	SessionID       string
+	UserID          string
+	Content         string
+	DisplayName     string
+	AvatarURL       string
+	ParentMessageID string // set for replies
+}
+
+// kinds of chat entries besides messages
+const (
+	ChatEntryReactionAdded   = "reaction_added"
+	ChatEntryReactionRemoved = "reaction_removed"
+)
+
+// a chat message or reaction change for AddChatEntries. reactions use
+// Content for the emoji and ParentMessageID for the message reacted to
+type ChatEntry struct {
+	Kind string // empty for messages, or one of ChatEntry*
+	AddChatMessageRequest
+}
+
+// the latest code of a session, for UpdateSessionCodes
+type SessionCode struct {
+	SessionID string
+	Code      string
+}
+
+// represents a checkpoint of session code
+type CodeRevision struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"sessionID"`
+	Code      string    `json:"code"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// a prompt to the AI assistant and the response it got, stored as a
+// user_prompt and an ai_response message
+type AgentExchange struct {
+	SessionID      string
+	UserID         string // empty for anonymous users
+	DisplayName    string
+	Prompt         string
+	Response       string
+	IsCodeResponse bool // the response is code that replaced the editor's
+	PromptedAt     time.Time
+}
+
+// transcript entry kinds, besides the message types
+const TranscriptEntryCode = "code"
+
+// an entry of a session's transcript: a chat message, an AI prompt or
+// response, or a code checkpoint
+type TranscriptEntry struct {
+	Kind            string // chat, user_prompt, ai_response or code
+	ID              string
+	UserID          *string
+	DisplayName     *string
+	Content         string
+	ParentMessageID *string // set for chat replies
+	IsCodeResponse  bool
+	CreatedAt       time.Time
+}
+
+// represents an immutable snapshot of session code
+type Snapshot struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	Code      string    `json:"code"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// activity of a session over a time bucket. participant and listener counts
+// are the peak of the samples taken once a minute
+type ActivitySample struct {
+	SessionID       string    `json:"session_id,omitempty"`
+	BucketStart     time.Time `json:"time"`
+	Participants    int       `json:"participants"`
+	Listeners       int       `json:"listeners"`
+	CodeEdits       int       `json:"code_edits"`
+	AgentRequests   int       `json:"agent_requests"`
+	ChatMessages    int       `json:"chat_messages"`
+	PlaybackSeconds float64   `json:"playback_seconds"`
 }
 
 // contains data for creating a session
@@ -148,4 +329,5 @@ type CreateInviteTokenRequest struct {
 	Role      string     `json:"role"`
 	MaxUses   *int       `json:"max_uses,omitempty"`
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Email     *string    `json:"email,omitempty"` // binds the token to this address, making it single-use
 }
@@ -0,0 +1,108 @@
+package strudels
+
+import (
+	"context"
+	"slices"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/strudel"
+)
+
+// analyzes the code in the background and stores the derived tags,
+// complexity, tempo and instruments, so saving never waits on analysis
+func (r *Repository) AnalyzeAsync(ctx context.Context, strudelID, code string) {
+	// keep request values but outlive its cancellation
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, analysisTimeout)
+		defer cancel()
+
+		if err := r.Analyze(ctx, strudelID, code); err != nil {
+			logger.Warn("failed to store strudel analysis", "error", err, "strudel_id", strudelID)
+		}
+	}()
+}
+
+// analyzes the code and stores the result. the update is skipped when the
+// strudel's code has changed since, as the newer save queues its own analysis.
+func (r *Repository) Analyze(ctx context.Context, strudelID, code string) error {
+	analysis := strudel.AnalyzeCode(code)
+
+	tags := strudel.GenerateTags(analysis, "", nil)
+	slices.Sort(tags)
+
+	var bpm *float64
+	if analysis.EstimatedBPM > 0 {
+		bpm = &analysis.EstimatedBPM
+	}
+
+	_, err := r.db.Exec(
+		ctx,
+		queryUpdateAnalysis,
+		tags,
+		analysis.Instruments,
+		analysis.Complexity,
+		bpm,
+		strudelID,
+		code,
+	)
+
+	return err
+}
+
+// analyzes strudels saved before analysis existed, in batches.
+// runs until none are left or ctx is cancelled.
+func (r *Repository) BackfillAnalysis(ctx context.Context) {
+	analyzed := 0
+
+	for {
+		pending, err := r.listUnanalyzed(ctx, analysisBackfillBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.ErrorErr(err, "failed to list unanalyzed strudels")
+			}
+			return
+		}
+
+		for _, s := range pending {
+			if err := r.Analyze(ctx, s.ID, s.Code); err != nil {
+				if ctx.Err() == nil {
+					logger.ErrorErr(err, "failed to backfill strudel analysis", "strudel_id", s.ID)
+				}
+				return
+			}
+		}
+
+		analyzed += len(pending)
+
+		if len(pending) < analysisBackfillBatchSize {
+			break
+		}
+	}
+
+	if analyzed > 0 {
+		logger.Info("backfilled strudel analysis", "count", analyzed)
+	}
+}
+
+func (r *Repository) listUnanalyzed(ctx context.Context, limit int) ([]Strudel, error) {
+	rows, err := r.db.Query(ctx, queryListUnanalyzed, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var strudels []Strudel
+
+	for rows.Next() {
+		var s Strudel
+		if err := rows.Scan(&s.ID, &s.Code); err != nil {
+			return nil, err
+		}
+
+		strudels = append(strudels, s)
+	}
+
+	return strudels, rows.Err()
+}
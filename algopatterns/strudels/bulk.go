@@ -0,0 +1,209 @@
+package strudels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// max tags per strudel, as for CreateStrudelRequest
+const maxTags = 20
+
+// applies an action to the user's strudels in one transaction. rejected is
+// keyed by ID and rejects items up front with the given reason, e.g. after
+// moderation. when any item fails nothing is changed and applied is false.
+// inTx, when set, runs for each applied item in the transaction, e.g. to
+// queue its events
+func (r *Repository) Bulk(
+	ctx context.Context,
+	userID string,
+	req BulkRequest,
+	rejected map[string]string,
+	inTx func(tx pgx.Tx, result BulkResult) error,
+) (results []BulkResult, applied bool, err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck,gosec // no-op after commit
+
+	failed := false
+	seen := make(map[string]bool, len(req.IDs))
+
+	for _, id := range req.IDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		result := BulkResult{ID: id, Status: BulkApplied}
+
+		if reason, ok := rejected[id]; ok {
+			result.Status, result.Error = BulkRejected, reason
+		} else if err := r.bulkApply(ctx, tx, userID, req, &result); err != nil {
+			return nil, false, err
+		}
+
+		failed = failed || result.Status != BulkApplied
+		results = append(results, result)
+	}
+
+	if failed {
+		for i := range results {
+			if results[i].Status == BulkApplied {
+				results[i] = BulkResult{ID: results[i].ID, Status: BulkSkipped}
+			}
+		}
+
+		return results, false, nil
+	}
+
+	if inTx != nil {
+		for _, result := range results {
+			if err := inTx(tx, result); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, err
+	}
+
+	return results, true, nil
+}
+
+// applies the action to one strudel, setting the item's status
+func (r *Repository) bulkApply(ctx context.Context, tx pgx.Tx, userID string, req BulkRequest, result *BulkResult) error {
+	current, err := scanStrudel(tx.QueryRow(ctx, queryGetForUpdate, result.ID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		result.Status = BulkNotFound
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	result.Previous = current
+
+	if req.Action == BulkDelete {
+		_, err := tx.Exec(ctx, querySoftDelete, result.ID, userID)
+		return err
+	}
+
+	var update UpdateStrudelRequest
+
+	switch req.Action {
+	case BulkAddTags:
+		tags := slices.Clone(current.Tags)
+		for _, tag := range req.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		if len(tags) > maxTags {
+			result.Status, result.Error = BulkRejected, fmt.Sprintf("a strudel can have at most %d tags", maxTags)
+			return nil
+		}
+
+		update.Tags = tags
+
+	case BulkRemoveTags:
+		update.Tags = slices.DeleteFunc(slices.Clone(current.Tags), func(tag string) bool {
+			return slices.Contains(req.Tags, tag)
+		})
+
+	case BulkSetVisibility:
+		if *req.IsPublic {
+			if err := r.checkBulkForkSignal(ctx, current, current.CCSignal, result); err != nil || result.Status != BulkApplied {
+				return err
+			}
+		}
+
+		update.IsPublic = req.IsPublic
+
+	case BulkSetCCSignal:
+		if *req.CCSignal == CCSignalNoAI && current.AIAssistCount > 0 {
+			result.Status, result.Error = BulkRejected, "cannot use 'no-ai' signal with AI-assisted content"
+			return nil
+		}
+
+		if err := r.checkBulkForkSignal(ctx, current, req.CCSignal, result); err != nil || result.Status != BulkApplied {
+			return err
+		}
+
+		update.CCSignal = req.CCSignal
+	}
+
+	result.Strudel, err = scanStrudel(tx.QueryRow(ctx, queryUpdate,
+		update.Title,
+		update.Code,
+		update.IsPublic,
+		update.License,
+		update.CCSignal,
+		nil,
+		update.Description,
+		update.Tags,
+		update.Categories,
+		nil,
+		result.ID,
+		userID,
+		nil,
+	))
+
+	return err
+}
+
+// rejects the item when a fork would end up with a signal more permissive
+// than its ancestry allows
+func (r *Repository) checkBulkForkSignal(ctx context.Context, current *Strudel, signal *CCSignal, result *BulkResult) error {
+	if current.ForkedFrom == nil {
+		return nil
+	}
+
+	inherited, err := r.EffectiveCCSignal(ctx, *current.ForkedFrom)
+	if err != nil {
+		return err
+	}
+
+	if err := forkSignalAllowed(inherited, signal); err != nil {
+		result.Status, result.Error = BulkRejected, err.Error()
+	}
+
+	return nil
+}
+
+// scans a row of the columns queryGet selects
+func scanStrudel(row pgx.Row) (*Strudel, error) {
+	var strudel Strudel
+
+	err := row.Scan(
+		&strudel.ID,
+		&strudel.UserID,
+		&strudel.Title,
+		&strudel.Code,
+		&strudel.IsPublic,
+		&strudel.License,
+		&strudel.CCSignal,
+		&strudel.UseInTraining,
+		&strudel.AIAssistCount,
+		&strudel.ForkedFrom,
+		&strudel.Description,
+		&strudel.Tags,
+		&strudel.Categories,
+		&strudel.ConversationHistory,
+		&strudel.CreatedAt,
+		&strudel.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &strudel, nil
+}
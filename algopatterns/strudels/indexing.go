@@ -0,0 +1,182 @@
+package strudels
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// enables the RAG examples index: eligible strudels are embedded as they are
+// published and edited, and dropped from the index once they aren't
+func (r *Repository) SetEmbedder(embedder Embedder) {
+	r.embedder = embedder
+}
+
+// syncs the strudel's example embedding in the background
+func (r *Repository) IndexAsync(ctx context.Context, strudelID string) {
+	if r.embedder == nil {
+		return
+	}
+
+	// keep request values but outlive its cancellation
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, indexTimeout)
+		defer cancel()
+
+		if err := r.Index(ctx, strudelID); err != nil {
+			logger.Warn("failed to index strudel example", "error", err, "strudel_id", strudelID)
+		}
+	}()
+}
+
+// re-syncs every strudel of a user, e.g. after their training consent changed
+func (r *Repository) IndexUserAsync(ctx context.Context, userID string) {
+	if r.embedder == nil {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		ids, err := r.listIDs(ctx, queryListUserStrudelIDs, userID)
+		if err != nil {
+			logger.Warn("failed to list strudels for indexing", "error", err, "user_id", userID)
+			return
+		}
+
+		for _, id := range ids {
+			indexCtx, cancel := context.WithTimeout(ctx, indexTimeout)
+			if err := r.Index(indexCtx, id); err != nil {
+				logger.Warn("failed to index strudel example", "error", err, "strudel_id", id)
+			}
+			cancel()
+		}
+	}()
+}
+
+// embeds the strudel if it is example-eligible, otherwise removes its
+// embedding. no-ai strudels are never sent to the embedding provider
+func (r *Repository) Index(ctx context.Context, strudelID string) error {
+	var (
+		title, description, code string
+		updatedAt                time.Time
+		eligible                 bool
+	)
+
+	err := r.db.QueryRow(ctx, queryGetIndexSource, strudelID).Scan(&title, &description, &code, &updatedAt, &eligible)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil // deleted since
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !eligible {
+		_, err := r.db.Exec(ctx, queryClearExampleEmbedding, strudelID)
+		return err
+	}
+
+	embedding, err := r.embedder.GenerateEmbedding(ctx, exampleText(title, description, code))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, querySetExampleEmbedding, pgvector.NewVector(embedding), strudelID, updatedAt)
+	return err
+}
+
+// clears embeddings that are no longer eligible and embeds eligible strudels
+// that were published before indexing existed, in batches. runs until none
+// are left or ctx is cancelled.
+func (r *Repository) BackfillIndex(ctx context.Context) {
+	if r.embedder == nil {
+		return
+	}
+
+	tag, err := r.db.Exec(ctx, queryClearIneligibleEmbeddings)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to clear ineligible strudel embeddings")
+		}
+		return
+	}
+
+	if tag.RowsAffected() > 0 {
+		logger.Info("removed ineligible strudels from the examples index", "count", tag.RowsAffected())
+	}
+
+	indexed := 0
+
+	for {
+		pending, err := r.listIDs(ctx, queryListUnindexed, indexBackfillBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.ErrorErr(err, "failed to list unindexed strudels")
+			}
+			return
+		}
+
+		for _, id := range pending {
+			indexCtx, cancel := context.WithTimeout(ctx, indexTimeout)
+			err := r.Index(indexCtx, id)
+			cancel()
+
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.ErrorErr(err, "failed to backfill strudel example", "strudel_id", id)
+				}
+				return
+			}
+		}
+
+		indexed += len(pending)
+
+		if len(pending) < indexBackfillBatchSize {
+			break
+		}
+	}
+
+	if indexed > 0 {
+		logger.Info("backfilled strudel examples index", "count", indexed)
+	}
+}
+
+// reports whether an update may change the strudel's example embedding:
+// the text it is embedded from or whether it is eligible
+func (req *UpdateStrudelRequest) AffectsExample() bool {
+	return req.Code != nil || req.Title != nil || req.Description != nil || req.IsPublic != nil || req.CCSignal != nil
+}
+
+// the text a strudel example is embedded from
+func exampleText(title, description, code string) string {
+	return title + "\n\n" + description + "\n\n" + code
+}
+
+func (r *Repository) listIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var ids []string
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,35 @@
+package strudels
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAffectsExample(t *testing.T) {
+	title := "new title"
+	public := false
+	signal := CCSignalNoAI
+
+	assert.True(t, (&UpdateStrudelRequest{Title: &title}).AffectsExample())
+	assert.True(t, (&UpdateStrudelRequest{IsPublic: &public}).AffectsExample())
+	assert.True(t, (&UpdateStrudelRequest{CCSignal: &signal}).AffectsExample())
+
+	// tags and conversation history aren't part of the embedded text
+	assert.False(t, (&UpdateStrudelRequest{Tags: []string{"techno"}}).AffectsExample())
+	assert.False(t, (&UpdateStrudelRequest{}).AffectsExample())
+}
+
+func TestExampleText(t *testing.T) {
+	assert.Equal(t, "Acid\n\nsquelchy bass\n\nnote(\"c2\").s(\"sawtooth\")", exampleText("Acid", "squelchy bass", `note("c2").s("sawtooth")`))
+}
+
+func TestIndexingDisabledWithoutEmbedder(t *testing.T) {
+	// without an embedder the repository never touches the database
+	r := &Repository{}
+
+	r.IndexAsync(context.Background(), "s1")
+	r.IndexUserAsync(context.Background(), "u1")
+	r.BackfillIndex(context.Background())
+}
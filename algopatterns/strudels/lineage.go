@@ -0,0 +1,157 @@
+package strudels
+
+import (
+	"context"
+)
+
+// gets the fork ancestry of a strudel, from its direct parent up to the
+// original. nodes are redacted unless public or owned by viewerID.
+func (r *Repository) ListAncestors(ctx context.Context, strudelID, viewerID string) ([]*LineageNode, error) {
+	rows, err := r.db.Query(ctx, queryListAncestors, strudelID, maxLineageDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	ancestors := []*LineageNode{}
+
+	for rows.Next() {
+		var node LineageNode
+		var authorName *string
+		err := rows.Scan(
+			&node.ID,
+			&node.UserID,
+			&authorName,
+			&node.Title,
+			&node.IsPublic,
+			&node.CCSignal,
+			&node.License,
+			&node.CreatedAt,
+			&node.Depth,
+			&node.ForkCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if authorName != nil {
+			node.AuthorName = *authorName
+		}
+
+		ancestors = append(ancestors, &node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, node := range ancestors {
+		node.redactFor(viewerID)
+	}
+
+	return ancestors, nil
+}
+
+// gets the tree of forks below a strudel, returning the direct forks with
+// their descendants nested as children. nodes are redacted unless public or
+// owned by viewerID.
+func (r *Repository) ListDescendants(ctx context.Context, strudelID, viewerID string) ([]*LineageNode, ForkCounts, error) {
+	var counts ForkCounts
+
+	rows, err := r.db.Query(ctx, queryListDescendants, strudelID, maxLineageDepth, maxLineageDescendants)
+	if err != nil {
+		return nil, counts, err
+	}
+
+	defer rows.Close()
+	nodes := []*LineageNode{}
+
+	for rows.Next() {
+		var node LineageNode
+		var authorName *string
+		err := rows.Scan(
+			&node.ID,
+			&node.parentID,
+			&node.UserID,
+			&authorName,
+			&node.Title,
+			&node.IsPublic,
+			&node.CCSignal,
+			&node.License,
+			&node.CreatedAt,
+			&node.Depth,
+			&node.ForkCount,
+			&counts.Total,
+		)
+		if err != nil {
+			return nil, counts, err
+		}
+
+		if authorName != nil {
+			node.AuthorName = *authorName
+		}
+
+		nodes = append(nodes, &node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, counts, err
+	}
+
+	forks, counts := assembleForkTree(nodes, counts.Total, viewerID)
+	return forks, counts, nil
+}
+
+// nests descendant rows, ordered by depth, under their parents and returns
+// the direct forks. total is the number of descendants before the size limit
+func assembleForkTree(nodes []*LineageNode, total int, viewerID string) ([]*LineageNode, ForkCounts) {
+	counts := ForkCounts{Total: total}
+	byID := make(map[string]*LineageNode, len(nodes))
+	forks := []*LineageNode{}
+
+	// a parent is always seen before its forks
+	for _, node := range nodes {
+		byID[node.ID] = node
+		counts.MaxDepth = max(counts.MaxDepth, node.Depth)
+
+		if node.Depth == 1 {
+			forks = append(forks, node)
+			continue
+		}
+
+		if parent, ok := byID[node.parentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	// forks below the deepest level fetched were not followed
+	for _, node := range nodes {
+		if node.Depth == maxLineageDepth && node.ForkCount > 0 {
+			counts.Truncated = true
+		}
+
+		node.redactFor(viewerID)
+	}
+
+	counts.Direct = len(forks)
+	if counts.Total > len(nodes) {
+		counts.Truncated = true
+	}
+
+	return forks, counts
+}
+
+// hides identifying details of a private strudel the viewer doesn't own,
+// keeping its position in the graph
+func (n *LineageNode) redactFor(viewerID string) {
+	if n.IsPublic || (viewerID != "" && n.UserID == viewerID) {
+		return
+	}
+
+	*n = LineageNode{
+		Redacted:  true,
+		Depth:     n.Depth,
+		ForkCount: n.ForkCount,
+		Children:  n.Children,
+	}
+}
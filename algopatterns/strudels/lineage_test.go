@@ -0,0 +1,73 @@
+package strudels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssembleForkTree(t *testing.T) {
+	nodes := []*LineageNode{
+		{ID: "a", parentID: "root", UserID: "u1", Title: "a", IsPublic: true, Depth: 1, ForkCount: 2},
+		{ID: "b", parentID: "root", UserID: "u2", Title: "b", Depth: 1, ForkCount: 0},
+		{ID: "a1", parentID: "a", UserID: "u3", Title: "a1", IsPublic: true, Depth: 2, ForkCount: 1},
+		{ID: "a2", parentID: "a", UserID: "viewer", Title: "a2", Depth: 2},
+		{ID: "a1x", parentID: "a1", UserID: "u1", Title: "a1x", IsPublic: true, Depth: 3},
+	}
+
+	forks, counts := assembleForkTree(nodes, 5, "viewer")
+
+	assert.Equal(t, ForkCounts{Direct: 2, Total: 5, MaxDepth: 3}, counts)
+	require.Len(t, forks, 2)
+
+	a := forks[0]
+	assert.Equal(t, "a", a.ID)
+	require.Len(t, a.Children, 2)
+	assert.Equal(t, "a1", a.Children[0].ID)
+	assert.Equal(t, "a2", a.Children[1].ID, "the viewer's own private fork stays visible")
+	require.Len(t, a.Children[0].Children, 1)
+	assert.Equal(t, "a1x", a.Children[0].Children[0].ID)
+
+	// private forks of others keep their place in the tree without details
+	b := forks[1]
+	assert.True(t, b.Redacted)
+	assert.Empty(t, b.ID)
+	assert.Empty(t, b.UserID)
+	assert.Equal(t, 1, b.Depth)
+}
+
+func TestAssembleForkTreeTruncated(t *testing.T) {
+	t.Run("at the size limit", func(t *testing.T) {
+		nodes := []*LineageNode{{ID: "a", parentID: "root", IsPublic: true, Depth: 1}}
+
+		_, counts := assembleForkTree(nodes, 3, "")
+		assert.True(t, counts.Truncated)
+	})
+
+	t.Run("at the depth limit", func(t *testing.T) {
+		nodes := []*LineageNode{{ID: "a", parentID: "root", IsPublic: true, Depth: maxLineageDepth, ForkCount: 1}}
+
+		_, counts := assembleForkTree(nodes, 1, "")
+		assert.True(t, counts.Truncated)
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		nodes := []*LineageNode{{ID: "a", parentID: "root", IsPublic: true, Depth: 1}}
+
+		_, counts := assembleForkTree(nodes, 1, "")
+		assert.False(t, counts.Truncated)
+	})
+}
+
+func TestRedactFor(t *testing.T) {
+	private := &LineageNode{ID: "p", UserID: "owner", Title: "secret", Depth: 2, ForkCount: 3}
+
+	owned := *private
+	owned.redactFor("owner")
+	assert.Equal(t, "secret", owned.Title)
+
+	anonymous := *private
+	anonymous.redactFor("")
+	assert.Equal(t, LineageNode{Redacted: true, Depth: 2, ForkCount: 3}, anonymous)
+}
@@ -10,18 +10,38 @@ const (
 	`
 
 	queryGetPublic = `
-		SELECT s.id, s.user_id, u.name, s.title, s.code, s.is_public, s.license, s.cc_signal, s.use_in_training, s.ai_assist_count, s.forked_from, s.description, s.tags, s.categories, s.conversation_history, s.created_at, s.updated_at
+		SELECT s.id, s.user_id, u.name, s.title, s.code, s.is_public, s.license, s.cc_signal, s.use_in_training, s.ai_assist_count, s.forked_from, s.description, s.tags, s.categories, s.conversation_history, s.created_at, s.updated_at,
+			` + queryPreviewURL + `
 		FROM user_strudels s
 		LEFT JOIN users u ON s.user_id = u.id
-		WHERE s.id = $1 AND s.is_public = true
+		WHERE s.id = $1 AND s.is_public = true AND s.deleted_at IS NULL
 	`
 
 	queryGet = `
+		SELECT s.id, s.user_id, s.title, s.code, s.is_public, s.license, s.cc_signal, s.use_in_training, s.ai_assist_count, s.forked_from, s.description, s.tags, s.categories, s.conversation_history, s.created_at, s.updated_at,
+			` + queryPreviewURL + `
+		FROM user_strudels s
+		WHERE s.id = $1 AND s.user_id = $2 AND s.deleted_at IS NULL
+	`
+
+	// URL of the latest audio render of strudel s
+	queryPreviewURL = `(
+			SELECT r.url FROM strudel_renders r
+			WHERE r.strudel_id = s.id AND r.status = 'ready'
+			ORDER BY r.completed_at DESC
+			LIMIT 1
+		)`
+
+	// bulk operations: locks an owned strudel until the batch commits
+	queryGetForUpdate = `
 		SELECT id, user_id, title, code, is_public, license, cc_signal, use_in_training, ai_assist_count, forked_from, description, tags, categories, conversation_history, created_at, updated_at
 		FROM user_strudels
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
 	`
 
+	// $13 is the updated_at the client last saw (NULL skips the check). compared
+	// at millisecond precision since browsers round-trip timestamps through Date
 	queryUpdate = `
 		UPDATE user_strudels
 		SET title = COALESCE($1, title),
@@ -35,13 +55,44 @@ const (
 		    categories = COALESCE($9, categories),
 		    conversation_history = COALESCE($10, conversation_history),
 		    updated_at = NOW()
-		WHERE id = $11 AND user_id = $12
+		WHERE id = $11 AND user_id = $12 AND deleted_at IS NULL
+		  AND ($13::timestamptz IS NULL OR date_trunc('milliseconds', updated_at) = date_trunc('milliseconds', $13::timestamptz))
+		RETURNING id, user_id, title, code, is_public, license, cc_signal, use_in_training, ai_assist_count, forked_from, description, tags, categories, conversation_history, created_at, updated_at
+	`
+
+	// moves a strudel to the trash; its example embedding is dropped right away
+	// so it stops being retrieved, and restoring indexes it again
+	querySoftDelete = `
+		UPDATE user_strudels
+		SET deleted_at = NOW(), embedding = NULL
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	queryListTrash = `
+		SELECT id, user_id, title, code, is_public, license, cc_signal, use_in_training, ai_assist_count, forked_from, description, tags, categories, conversation_history, created_at, updated_at, deleted_at
+		FROM user_strudels
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	queryCountTrash = `
+		SELECT COUNT(*) FROM user_strudels
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+	`
+
+	queryRestore = `
+		UPDATE user_strudels
+		SET deleted_at = NULL
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
 		RETURNING id, user_id, title, code, is_public, license, cc_signal, use_in_training, ai_assist_count, forked_from, description, tags, categories, conversation_history, created_at, updated_at
 	`
 
-	queryDelete = `
+	// hard-deletes strudels trashed before $1
+	queryPurgeTrash = `
 		DELETE FROM user_strudels
-		WHERE id = $1 AND user_id = $2
+		WHERE deleted_at < $1
+		RETURNING id
 	`
 
 	queryListTrainableWithoutEmbedding = `
@@ -53,6 +104,7 @@ const (
 		  AND us.use_in_training = true
 		  AND us.is_public = true
 		  AND us.embedding IS NULL
+		  AND us.deleted_at IS NULL
 		  AND u.training_consent = true
 		ORDER BY us.created_at DESC
 		LIMIT $1
@@ -64,6 +116,71 @@ const (
 		WHERE id = $2
 	`
 
+	// skips the update if the code changed while it was being analyzed
+	queryUpdateAnalysis = `
+		UPDATE user_strudels
+		SET auto_tags = $1, instruments = $2, complexity = $3, estimated_bpm = $4, analyzed_at = NOW()
+		WHERE id = $5 AND code = $6
+	`
+
+	queryListUnanalyzed = `
+		SELECT id, code
+		FROM user_strudels
+		WHERE analyzed_at IS NULL AND deleted_at IS NULL
+		ORDER BY created_at
+		LIMIT $1
+	`
+
+	// strudels are example-eligible while public, not trashed, with a
+	// permissive cc signal and an author who consents to training
+	queryGetIndexSource = `
+		SELECT us.title, us.description, us.code, us.updated_at,
+			COALESCE(us.is_public AND us.deleted_at IS NULL AND us.cc_signal IS NOT NULL AND us.cc_signal != 'no-ai' AND u.training_consent, false)
+		FROM user_strudels us
+		INNER JOIN users u ON us.user_id = u.id
+		WHERE us.id = $1
+	`
+
+	// skips the update if the strudel was saved again while it was being embedded
+	querySetExampleEmbedding = `
+		UPDATE user_strudels
+		SET embedding = $1
+		WHERE id = $2 AND updated_at = $3
+	`
+
+	queryClearExampleEmbedding = `
+		UPDATE user_strudels
+		SET embedding = NULL
+		WHERE id = $1 AND embedding IS NOT NULL
+	`
+
+	queryListUnindexed = `
+		SELECT us.id
+		FROM user_strudels us
+		INNER JOIN users u ON us.user_id = u.id
+		WHERE us.embedding IS NULL
+		  AND us.is_public = true
+		  AND us.deleted_at IS NULL
+		  AND us.cc_signal IS NOT NULL
+		  AND us.cc_signal != 'no-ai'
+		  AND u.training_consent = true
+		ORDER BY us.created_at
+		LIMIT $1
+	`
+
+	queryClearIneligibleEmbeddings = `
+		UPDATE user_strudels us
+		SET embedding = NULL
+		FROM users u
+		WHERE us.user_id = u.id
+		  AND us.embedding IS NOT NULL
+		  AND NOT COALESCE(us.is_public AND us.deleted_at IS NULL AND us.cc_signal IS NOT NULL AND us.cc_signal != 'no-ai' AND u.training_consent, false)
+	`
+
+	queryListUserStrudelIDs = `
+		SELECT id FROM user_strudels WHERE user_id = $1
+	`
+
 	queryAdminSetUseInTraining = `
 		UPDATE user_strudels
 		SET use_in_training = $1, updated_at = NOW()
@@ -71,6 +188,18 @@ const (
 		RETURNING id, user_id, title, code, is_public, license, cc_signal, use_in_training, ai_assist_count, forked_from, description, tags, categories, conversation_history, created_at, updated_at
 	`
 
+	queryAdminSetPublic = `
+		UPDATE user_strudels
+		SET is_public = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING id, user_id, title, code, is_public, license, cc_signal, use_in_training, ai_assist_count, forked_from, description, tags, categories, conversation_history, created_at, updated_at
+	`
+
+	queryAdminDelete = `
+		DELETE FROM user_strudels
+		WHERE id = $1
+	`
+
 	queryAdminGetStrudel = `
 		SELECT id, user_id, title, code, is_public, license, cc_signal, use_in_training, ai_assist_count, forked_from, description, tags, categories, conversation_history, created_at, updated_at
 		FROM user_strudels
@@ -80,14 +209,14 @@ const (
 	queryListPublicTags = `
 		SELECT DISTINCT unnest(tags) as tag
 		FROM user_strudels
-		WHERE is_public = true AND array_length(tags, 1) > 0
+		WHERE is_public = true AND deleted_at IS NULL AND array_length(tags, 1) > 0
 		ORDER BY tag
 	`
 
 	queryListUserTags = `
 		SELECT DISTINCT unnest(tags) as tag
 		FROM user_strudels
-		WHERE user_id = $1 AND array_length(tags, 1) > 0
+		WHERE user_id = $1 AND deleted_at IS NULL AND array_length(tags, 1) > 0
 		ORDER BY tag
 	`
 
@@ -95,6 +224,22 @@ const (
 		SELECT cc_signal FROM user_strudels WHERE id = $1
 	`
 
+	// fork licensing: cc signals of $1 and its ancestors, trashed ones included
+	// since their signal still applies to forks made before they were trashed
+	queryLineageCCSignals = `
+		WITH RECURSIVE chain AS (
+			SELECT id, forked_from, cc_signal, 0 AS depth
+			FROM user_strudels
+			WHERE id = $1
+			UNION ALL
+			SELECT p.id, p.forked_from, p.cc_signal, c.depth + 1
+			FROM chain c
+			INNER JOIN user_strudels p ON p.id = c.forked_from
+			WHERE c.depth < $2
+		)
+		SELECT cc_signal FROM chain
+	`
+
 	queryGetStrudelForkedFrom = `
 		SELECT forked_from FROM user_strudels WHERE id = $1
 	`
@@ -107,12 +252,24 @@ const (
 		)
 	`
 
+	// paste lock appeals: check if the user published a strudel with the code
+	queryUserPublishedStrudelWithCode = `
+		SELECT EXISTS(
+			SELECT 1 FROM user_strudels
+			WHERE user_id = $1
+			  AND is_public = true
+			  AND deleted_at IS NULL
+			  AND code = $2
+		)
+	`
+
 	// paste detection: check if code matches any public strudel that allows AI (for fork validation)
 	// requires explicit permissive signal - NULL/missing defaults to no-ai (restrictive)
 	queryPublicStrudelExistsWithCodeAllowsAI = `
 		SELECT EXISTS(
 			SELECT 1 FROM user_strudels
 			WHERE is_public = true
+			  AND deleted_at IS NULL
 			  AND code = $1
 			  AND cc_signal IN ('cc-cr', 'cc-dc', 'cc-ec', 'cc-op')
 		)
@@ -128,6 +285,111 @@ const (
 		)
 	`
 
+	// fork lineage: ancestors from the direct parent ($1's forked_from) up to the root.
+	// trashed strudels count as private so they are redacted for other viewers
+	queryListAncestors = `
+		WITH RECURSIVE chain AS (
+			SELECT forked_from AS id, 1 AS depth
+			FROM user_strudels
+			WHERE id = $1 AND forked_from IS NOT NULL
+			UNION ALL
+			SELECT p.forked_from, c.depth + 1
+			FROM chain c
+			INNER JOIN user_strudels p ON p.id = c.id
+			WHERE p.forked_from IS NOT NULL AND c.depth < $2
+		)
+		SELECT s.id, s.user_id, u.name, s.title, s.is_public AND s.deleted_at IS NULL, s.cc_signal, s.license, s.created_at, c.depth,
+			(SELECT COUNT(*) FROM user_strudels f WHERE f.forked_from = s.id) AS fork_count
+		FROM chain c
+		INNER JOIN user_strudels s ON s.id = c.id
+		LEFT JOIN users u ON s.user_id = u.id
+		ORDER BY c.depth
+	`
+
+	// fork lineage: descendants of $1, breadth first, up to $2 generations
+	queryListDescendants = `
+		WITH RECURSIVE tree AS (
+			SELECT id, 1 AS depth
+			FROM user_strudels
+			WHERE forked_from = $1
+			UNION ALL
+			SELECT c.id, t.depth + 1
+			FROM tree t
+			INNER JOIN user_strudels c ON c.forked_from = t.id
+			WHERE t.depth < $2
+		)
+		SELECT s.id, s.forked_from, s.user_id, u.name, s.title, s.is_public AND s.deleted_at IS NULL, s.cc_signal, s.license, s.created_at, t.depth,
+			(SELECT COUNT(*) FROM user_strudels f WHERE f.forked_from = s.id) AS fork_count,
+			COUNT(*) OVER () AS total
+		FROM tree t
+		INNER JOIN user_strudels s ON s.id = t.id
+		LEFT JOIN users u ON s.user_id = u.id
+		ORDER BY t.depth, s.created_at
+		LIMIT $3
+	`
+
+	// version history: the latest version of a strudel, locked while a new one is recorded
+	queryLatestVersion = `
+		SELECT version, code, created_at
+		FROM strudel_versions
+		WHERE strudel_id = $1
+		ORDER BY version DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+
+	queryInsertVersion = `
+		INSERT INTO strudel_versions (strudel_id, version, code)
+		VALUES ($1, $2, $3)
+	`
+
+	queryReplaceVersionCode = `
+		UPDATE strudel_versions
+		SET code = $1
+		WHERE strudel_id = $2 AND version = $3
+	`
+
+	// keeps the newest $2 versions
+	queryTrimVersionCount = `
+		DELETE FROM strudel_versions
+		WHERE strudel_id = $1
+		  AND version <= (
+			SELECT version FROM strudel_versions
+			WHERE strudel_id = $1
+			ORDER BY version DESC
+			OFFSET $2 LIMIT 1
+		  )
+	`
+
+	// keeps only the last version of each day for versions older than $2
+	queryThinOldVersions = `
+		DELETE FROM strudel_versions v
+		WHERE v.strudel_id = $1
+		  AND v.created_at < $2
+		  AND EXISTS (
+			SELECT 1 FROM strudel_versions n
+			WHERE n.strudel_id = v.strudel_id
+			  AND n.version > v.version
+			  AND n.created_at::date = v.created_at::date
+		  )
+	`
+
+	// versions of a strudel the user owns
+	queryListVersions = `
+		SELECT v.version, OCTET_LENGTH(v.code), v.created_at
+		FROM strudel_versions v
+		INNER JOIN user_strudels us ON us.id = v.strudel_id
+		WHERE v.strudel_id = $1 AND us.user_id = $2 AND us.deleted_at IS NULL
+		ORDER BY v.version DESC
+	`
+
+	queryGetVersion = `
+		SELECT v.version, v.code, v.created_at
+		FROM strudel_versions v
+		INNER JOIN user_strudels us ON us.id = v.strudel_id
+		WHERE v.strudel_id = $1 AND us.user_id = $2 AND us.deleted_at IS NULL AND v.version = $3
+	`
+
 	// strudel_messages queries (AI conversation history for saved strudels)
 	queryAddStrudelMessage = `
 		INSERT INTO strudel_messages (strudel_id, user_id, role, content, is_actionable, is_code_response, clarifying_questions, strudel_references, doc_references, display_name)
@@ -143,6 +405,18 @@ const (
 		LIMIT $2
 	`
 
+	queryGetConversationSummary = `
+		SELECT conversation_summary, conversation_summary_through
+		FROM user_strudels
+		WHERE id = $1 AND conversation_summary IS NOT NULL
+	`
+
+	queryUpdateConversationSummary = `
+		UPDATE user_strudels
+		SET conversation_summary = $2, conversation_summary_through = $3
+		WHERE id = $1
+	`
+
 	// fingerprint protection: get all no-ai strudels with sufficient content
 	// used at startup to populate the LSH index for similarity detection
 	queryListNoAIStrudels = `
@@ -151,4 +425,21 @@ const (
 		WHERE cc_signal = 'no-ai'
 		  AND LENGTH(code) >= $1
 	`
+
+	// the code is hashed in the database so checking persisted fingerprints
+	// doesn't transfer every strudel's code
+	queryListNoAIStrudelDigests = `
+		SELECT id, user_id, encode(sha256(convert_to(code, 'UTF8')), 'hex')
+		FROM user_strudels
+		WHERE cc_signal = 'no-ai'
+		  AND LENGTH(code) >= $1
+	`
+
+	queryListNoAIStrudelsByID = `
+		SELECT id, user_id, code, cc_signal
+		FROM user_strudels
+		WHERE id = ANY($1)
+		  AND cc_signal = 'no-ai'
+		  AND LENGTH(code) >= $2
+	`
 )
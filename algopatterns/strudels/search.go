@@ -0,0 +1,166 @@
+package strudels
+
+import (
+	"context"
+	"fmt"
+)
+
+// searches public strudels with keyset pagination. returns the next page's
+// cursor, or nil when there are no more results.
+func (r *Repository) Search(ctx context.Context, params SearchParams) ([]SearchResult, *SearchCursor, error) {
+	baseWhere := "WHERE s.is_public = true AND s.deleted_at IS NULL"
+	args := []interface{}{}
+	argIndex := 1
+
+	rankExpr := "0::float8"
+	if params.Query != "" {
+		rankExpr = fmt.Sprintf("ts_rank(s.searchable_tsvector, websearch_to_tsquery('english', $%d))::float8", argIndex)
+		baseWhere += fmt.Sprintf(" AND s.searchable_tsvector @@ websearch_to_tsquery('english', $%d)", argIndex)
+		args = append(args, params.Query)
+		argIndex++
+	}
+
+	if len(params.Tags) > 0 {
+		baseWhere += fmt.Sprintf(" AND (s.tags && $%d OR s.auto_tags && $%d)", argIndex, argIndex)
+		args = append(args, params.Tags)
+		argIndex++
+	}
+
+	if len(params.CCSignals) > 0 {
+		signals := make([]string, len(params.CCSignals))
+		for i, signal := range params.CCSignals {
+			signals[i] = string(signal)
+		}
+
+		baseWhere += fmt.Sprintf(" AND s.cc_signal = ANY($%d)", argIndex)
+		args = append(args, signals)
+		argIndex++
+	}
+
+	baseWhere, args, argIndex = appendAnalysisFilters(baseWhere, args, argIndex, "s.", ListFilter{
+		MinComplexity: params.MinComplexity,
+		MaxComplexity: params.MaxComplexity,
+	})
+
+	sort := params.Sort
+	if sort == SortRelevance && params.Query == "" {
+		sort = SortRecent
+	}
+
+	// sort key with created_at and id as tie-breakers, so keyset pages never overlap
+	var orderBy, cursorWhere string
+	switch sort {
+	case SortRelevance:
+		orderBy = "rank DESC, created_at DESC, id DESC"
+		if params.After != nil {
+			cursorWhere = fmt.Sprintf("WHERE (rank, created_at, id) < ($%d, $%d, $%d)", argIndex, argIndex+1, argIndex+2)
+			args = append(args, params.After.Rank, params.After.CreatedAt, params.After.ID)
+			argIndex += 3
+		}
+
+	case SortPopular:
+		orderBy = "popularity DESC, created_at DESC, id DESC"
+		if params.After != nil {
+			cursorWhere = fmt.Sprintf("WHERE (popularity, created_at, id) < ($%d, $%d, $%d)", argIndex, argIndex+1, argIndex+2)
+			args = append(args, params.After.Popularity, params.After.CreatedAt, params.After.ID)
+			argIndex += 3
+		}
+
+	default:
+		orderBy = "created_at DESC, id DESC"
+		if params.After != nil {
+			cursorWhere = fmt.Sprintf("WHERE (created_at, id) < ($%d, $%d)", argIndex, argIndex+1)
+			args = append(args, params.After.CreatedAt, params.After.ID)
+			argIndex += 2
+		}
+	}
+
+	// fetch one extra row to know whether there is a next page
+	searchQuery := fmt.Sprintf(`
+		WITH results AS (
+			SELECT s.id, s.user_id, u.name AS author_name, s.title, s.code, s.is_public, s.license, s.cc_signal, s.ai_assist_count, s.forked_from, s.description, s.tags, s.categories, s.created_at, s.updated_at, s.auto_tags, s.instruments, s.complexity, s.estimated_bpm,
+				%s AS rank,
+				(SELECT COUNT(*) FROM user_strudels f WHERE f.forked_from = s.id)
+					+ (SELECT COUNT(*) FROM rag_attributions a WHERE a.source_strudel_id = s.id) AS popularity
+			FROM user_strudels s
+			LEFT JOIN users u ON s.user_id = u.id
+			%s
+		)
+		SELECT * FROM results
+		%s
+		ORDER BY %s
+		LIMIT $%d
+	`, rankExpr, baseWhere, cursorWhere, orderBy, argIndex)
+
+	args = append(args, params.Limit+1)
+
+	rows, err := r.db.Query(ctx, searchQuery, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+	var results []SearchResult
+
+	for rows.Next() {
+		var res SearchResult
+		var authorName *string
+		err := rows.Scan(
+			&res.ID,
+			&res.UserID,
+			&authorName,
+			&res.Title,
+			&res.Code,
+			&res.IsPublic,
+			&res.License,
+			&res.CCSignal,
+			&res.AIAssistCount,
+			&res.ForkedFrom,
+			&res.Description,
+			&res.Tags,
+			&res.Categories,
+			&res.CreatedAt,
+			&res.UpdatedAt,
+			&res.AutoTags,
+			&res.Instruments,
+			&res.Complexity,
+			&res.EstimatedBPM,
+			&res.Rank,
+			&res.Popularity,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if authorName != nil {
+			res.AuthorName = *authorName
+		}
+
+		results = append(results, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(results) <= params.Limit {
+		return results, nil, nil
+	}
+
+	results = results[:params.Limit]
+
+	return results, cursorAfter(sort, results[len(results)-1]), nil
+}
+
+// returns the position of a result in the given sort order
+func cursorAfter(sort string, last SearchResult) *SearchCursor {
+	next := &SearchCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	switch sort {
+	case SortRelevance:
+		next.Rank = last.Rank
+	case SortPopular:
+		next.Popularity = last.Popularity
+	}
+
+	return next
+}
@@ -0,0 +1,22 @@
+package strudels
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorAfter(t *testing.T) {
+	createdAt := time.Date(2026, 2, 1, 9, 15, 0, 0, time.UTC)
+	last := SearchResult{
+		Strudel:    Strudel{ID: "s1", CreatedAt: createdAt},
+		Rank:       0.5,
+		Popularity: 12,
+	}
+
+	// only the sort key and the tie-breakers go into the cursor
+	assert.Equal(t, &SearchCursor{Rank: 0.5, CreatedAt: createdAt, ID: "s1"}, cursorAfter(SortRelevance, last))
+	assert.Equal(t, &SearchCursor{Popularity: 12, CreatedAt: createdAt, ID: "s1"}, cursorAfter(SortPopular, last))
+	assert.Equal(t, &SearchCursor{CreatedAt: createdAt, ID: "s1"}, cursorAfter(SortRecent, last))
+}
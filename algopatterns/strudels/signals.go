@@ -1,6 +1,12 @@
 package strudels
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
 
 // returns the CC Signal for a strudel
 func (r *Repository) GetStrudelCCSignal(ctx context.Context, strudelID string) (*CCSignal, error) {
@@ -14,6 +20,99 @@ func (r *Repository) GetStrudelCCSignal(ctx context.Context, strudelID string) (
 	return ccSignal, nil
 }
 
+// returns the most restrictive cc signal of a strudel and the strudels it was
+// forked from, "" when none of them has one
+func (r *Repository) EffectiveCCSignal(ctx context.Context, strudelID string) (CCSignal, error) {
+	rows, err := r.db.Query(ctx, queryLineageCCSignals, strudelID, maxLineageDepth)
+	if err != nil {
+		return "", err
+	}
+
+	defer rows.Close()
+	var signals []CCSignal
+
+	for rows.Next() {
+		var signal *CCSignal
+		if err := rows.Scan(&signal); err != nil {
+			return "", err
+		}
+
+		if signal != nil {
+			signals = append(signals, *signal)
+		}
+	}
+
+	return MostRestrictive(signals...), rows.Err()
+}
+
+// sets the strudel's effective cc signal. a strudel that isn't a fork has its own
+func (r *Repository) ResolveEffectiveCCSignal(ctx context.Context, strudel *Strudel) error {
+	if strudel.ForkedFrom == nil {
+		strudel.EffectiveCCSignal = strudel.CCSignal
+		return nil
+	}
+
+	effective, err := r.EffectiveCCSignal(ctx, strudel.ID)
+	if err != nil {
+		return err
+	}
+
+	if effective != "" {
+		strudel.EffectiveCCSignal = &effective
+	}
+
+	return nil
+}
+
+// rejects updates that set a fork's cc signal, or publish it, under a signal
+// more permissive than the strudels it was forked from allow
+func (r *Repository) checkForkSignal(ctx context.Context, strudelID, userID string, req UpdateStrudelRequest) error {
+	publishing := req.IsPublic != nil && *req.IsPublic
+	if req.CCSignal == nil && !publishing {
+		return nil
+	}
+
+	current, err := r.Get(ctx, strudelID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// the update reports the missing strudel
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if current.ForkedFrom == nil {
+		return nil
+	}
+
+	signal := current.CCSignal
+	if req.CCSignal != nil {
+		signal = req.CCSignal
+	}
+
+	inherited, err := r.EffectiveCCSignal(ctx, *current.ForkedFrom)
+	if err != nil {
+		return err
+	}
+
+	return forkSignalAllowed(inherited, signal)
+}
+
+// checks a fork's signal against the signal its ancestry passes down
+func forkSignalAllowed(inherited CCSignal, signal *CCSignal) error {
+	var own CCSignal
+	if signal != nil {
+		own = *signal
+	}
+
+	if inherited.MoreRestrictiveThan(own) {
+		return fmt.Errorf("%w: use %s or a more restrictive signal", ErrCCSignalTooPermissive, inherited)
+	}
+
+	return nil
+}
+
 // returns the parent strudel ID if this is a fork
 func (r *Repository) GetStrudelForkedFrom(ctx context.Context, strudelID string) (*string, error) {
 	var forkedFrom *string
@@ -39,6 +138,19 @@ func (r *Repository) UserOwnsStrudelWithCode(ctx context.Context, userID, code s
 	return exists, nil
 }
 
+// checks if the user published a strudel with the exact code
+// used for paste lock appeals - publishing the code is how ownership is verified
+func (r *Repository) UserPublishedStrudelWithCode(ctx context.Context, userID, code string) (bool, error) {
+	var exists bool
+
+	err := r.db.QueryRow(ctx, queryUserPublishedStrudelWithCode, userID, code).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
 // checks if any public strudel has the exact code AND allows AI
 // used for paste detection validation - forking a public strudel that allows AI shouldn't trigger paste lock
 // BUT forking a public strudel with no-ai CC signal SHOULD trigger paste lock to protect creator's wishes
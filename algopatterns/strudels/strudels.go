@@ -6,21 +6,46 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/database"
+	"codeberg.org/algopatterns/server/internal/keyset"
 )
 
 var (
 	ErrStrudelNotFound = errors.New("strudel not found")
+	ErrVersionNotFound = errors.New("strudel version not found")
+	ErrStrudelConflict = errors.New("strudel was modified since it was loaded")
+
+	ErrCCSignalTooPermissive = errors.New("cc signal is more permissive than the strudel it was forked from allows")
 )
 
 func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
+// sends lag-tolerant reads, the public listings, to read replicas
+func (r *Repository) SetReadReplicas(replicas *database.Router) {
+	r.replicas = replicas
+}
+
+// the pool for a lag-tolerant read
+func (r *Repository) reader(ctx context.Context) *pgxpool.Pool {
+	if r.replicas == nil {
+		return r.db
+	}
+
+	return r.replicas.Reader(ctx)
+}
+
+// creates a strudel. inTx, when set, runs in the transaction of the write,
+// e.g. to queue its events
 func (r *Repository) Create(
 	ctx context.Context,
 	userID string,
 	req CreateStrudelRequest,
+	inTx func(tx pgx.Tx, strudel *Strudel) error,
 ) (*Strudel, error) {
 	var strudel Strudel
 
@@ -45,18 +70,19 @@ func (r *Repository) Create(
 		}
 	}
 
-	// if forked, inherit most restrictive cc_signal from parent
+	// if forked, inherit the most restrictive cc_signal of the fork's ancestry
 	ccSignal := req.CCSignal
 
 	if req.ForkedFrom != nil {
-		var parentSignal *CCSignal
+		// without the ancestry's signal the fork could be more permissive
+		inherited, err := r.EffectiveCCSignal(ctx, *req.ForkedFrom)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up the cc signal of the forked strudel: %w", err)
+		}
 
-		err := r.db.QueryRow(ctx, queryGetParentCCSignal, *req.ForkedFrom).Scan(&parentSignal)
-		if err == nil && parentSignal != nil {
-			// if parent has a signal, child must be at least as restrictive
-			if ccSignal == nil || parentSignal.MoreRestrictiveThan(*ccSignal) {
-				ccSignal = parentSignal
-			}
+		// the fork must be at least as restrictive as its ancestry
+		if inherited != "" && (ccSignal == nil || inherited.MoreRestrictiveThan(*ccSignal)) {
+			ccSignal = &inherited
 		}
 	}
 
@@ -66,50 +92,61 @@ func (r *Repository) Create(
 		return nil, fmt.Errorf("failed to marshal conversation history: %w", err)
 	}
 
-	err = r.db.QueryRow(
-		ctx,
-		queryCreate,
-		userID,
-		req.Title,
-		req.Code,
-		req.IsPublic,
-		req.License,
-		ccSignal,
-		aiAssistCount,
-		req.ForkedFrom,
-		req.Description,
-		tags,
-		categories,
-		string(conversationHistoryJSON),
-	).Scan(
-		&strudel.ID,
-		&strudel.UserID,
-		&strudel.Title,
-		&strudel.Code,
-		&strudel.IsPublic,
-		&strudel.License,
-		&strudel.CCSignal,
-		&strudel.UseInTraining,
-		&strudel.AIAssistCount,
-		&strudel.ForkedFrom,
-		&strudel.Description,
-		&strudel.Tags,
-		&strudel.Categories,
-		&strudel.ConversationHistory,
-		&strudel.CreatedAt,
-		&strudel.UpdatedAt,
-	)
+	err = pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(
+			ctx,
+			queryCreate,
+			userID,
+			req.Title,
+			req.Code,
+			req.IsPublic,
+			req.License,
+			ccSignal,
+			aiAssistCount,
+			req.ForkedFrom,
+			req.Description,
+			tags,
+			categories,
+			string(conversationHistoryJSON),
+		).Scan(
+			&strudel.ID,
+			&strudel.UserID,
+			&strudel.Title,
+			&strudel.Code,
+			&strudel.IsPublic,
+			&strudel.License,
+			&strudel.CCSignal,
+			&strudel.UseInTraining,
+			&strudel.AIAssistCount,
+			&strudel.ForkedFrom,
+			&strudel.Description,
+			&strudel.Tags,
+			&strudel.Categories,
+			&strudel.ConversationHistory,
+			&strudel.CreatedAt,
+			&strudel.UpdatedAt,
+		)
+
+		if err != nil || inTx == nil {
+			return err
+		}
+
+		return inTx(tx, &strudel)
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	r.recordVersionLogged(ctx, strudel.ID, strudel.Code, false)
+
 	return &strudel, nil
 }
 
-func (r *Repository) List(ctx context.Context, userID string, limit, offset int, filter ListFilter) ([]Strudel, int, error) {
+// lists a user's strudels one page at a time, with the total matching the filter
+func (r *Repository) List(ctx context.Context, userID string, page keyset.Page, filter ListFilter) ([]Strudel, keyset.Edges, int, error) {
 	// build dynamic query with filters
-	baseWhere := "WHERE user_id = $1"
+	baseWhere := "WHERE user_id = $1 AND deleted_at IS NULL"
 	args := []interface{}{userID}
 	argIndex := 2
 
@@ -120,33 +157,43 @@ func (r *Repository) List(ctx context.Context, userID string, limit, offset int,
 	}
 
 	if len(filter.Tags) > 0 {
-		baseWhere += fmt.Sprintf(" AND tags && $%d", argIndex)
+		baseWhere += fmt.Sprintf(" AND (tags && $%d OR auto_tags && $%d)", argIndex, argIndex)
 		args = append(args, filter.Tags)
 		argIndex++
 	}
 
+	baseWhere, args, argIndex = appendAnalysisFilters(baseWhere, args, argIndex, "", filter)
+
 	// get total count first
 	var total int
 	countQuery := "SELECT COUNT(*) FROM user_strudels " + baseWhere
 
 	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, err
+		return nil, keyset.Edges{}, 0, err
+	}
+
+	column, columnType := sortColumn(page.Sort.Field)
+	pageWhere, pageArgs := page.Where(column, columnType, "id", "uuid", argIndex)
+	if pageWhere != "" {
+		baseWhere += " AND " + pageWhere
+		args = append(args, pageArgs...)
+		argIndex += len(pageArgs)
 	}
 
 	// build list query
 	listQuery := fmt.Sprintf(`
-		SELECT id, user_id, title, code, is_public, license, cc_signal, use_in_training, ai_assist_count, forked_from, description, tags, categories, conversation_history, created_at, updated_at
+		SELECT id, user_id, title, code, is_public, license, cc_signal, use_in_training, ai_assist_count, forked_from, description, tags, categories, conversation_history, created_at, updated_at, auto_tags, instruments, complexity, estimated_bpm
 		FROM user_strudels
 		%s
-		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, baseWhere, argIndex, argIndex+1)
+		ORDER BY %s
+		LIMIT $%d
+	`, baseWhere, page.OrderBy(column, "id"), argIndex)
 
-	args = append(args, limit, offset)
+	args = append(args, page.Fetch())
 
 	rows, err := r.db.Query(ctx, listQuery, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, keyset.Edges{}, 0, err
 	}
 
 	defer rows.Close()
@@ -171,24 +218,32 @@ func (r *Repository) List(ctx context.Context, userID string, limit, offset int,
 			&s.ConversationHistory,
 			&s.CreatedAt,
 			&s.UpdatedAt,
+			&s.AutoTags,
+			&s.Instruments,
+			&s.Complexity,
+			&s.EstimatedBPM,
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, keyset.Edges{}, 0, err
 		}
 
 		strudels = append(strudels, s)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, keyset.Edges{}, 0, err
 	}
 
-	return strudels, total, nil
+	strudels, edges := keyset.Cut(page, strudels, strudelKey(page.Sort.Field))
+	return strudels, edges, total, nil
 }
 
-func (r *Repository) ListPublic(ctx context.Context, limit, offset int, filter ListFilter) ([]Strudel, int, error) {
+// lists public strudels one page at a time, with the total matching the filter
+func (r *Repository) ListPublic(ctx context.Context, page keyset.Page, filter ListFilter) ([]Strudel, keyset.Edges, int, error) {
+	db := r.reader(ctx)
+
 	// build dynamic query with filters
-	baseWhere := "WHERE s.is_public = true"
+	baseWhere := "WHERE s.is_public = true AND s.deleted_at IS NULL"
 	args := []interface{}{}
 	argIndex := 1
 
@@ -199,34 +254,44 @@ func (r *Repository) ListPublic(ctx context.Context, limit, offset int, filter L
 	}
 
 	if len(filter.Tags) > 0 {
-		baseWhere += fmt.Sprintf(" AND s.tags && $%d", argIndex)
+		baseWhere += fmt.Sprintf(" AND (s.tags && $%d OR s.auto_tags && $%d)", argIndex, argIndex)
 		args = append(args, filter.Tags)
 		argIndex++
 	}
 
+	baseWhere, args, argIndex = appendAnalysisFilters(baseWhere, args, argIndex, "s.", filter)
+
 	// get total count first
 	var total int
 	countQuery := "SELECT COUNT(*) FROM user_strudels s " + baseWhere
 
-	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, err
+	if err := db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, keyset.Edges{}, 0, err
+	}
+
+	column, columnType := sortColumn(page.Sort.Field)
+	pageWhere, pageArgs := page.Where("s."+column, columnType, "s.id", "uuid", argIndex)
+	if pageWhere != "" {
+		baseWhere += " AND " + pageWhere
+		args = append(args, pageArgs...)
+		argIndex += len(pageArgs)
 	}
 
 	// build list query with JOIN to get author name
 	listQuery := fmt.Sprintf(`
-		SELECT s.id, s.user_id, u.name, s.title, s.code, s.is_public, s.license, s.cc_signal, s.use_in_training, s.ai_assist_count, s.forked_from, s.description, s.tags, s.categories, s.conversation_history, s.created_at, s.updated_at
+		SELECT s.id, s.user_id, u.name, s.title, s.code, s.is_public, s.license, s.cc_signal, s.use_in_training, s.ai_assist_count, s.forked_from, s.description, s.tags, s.categories, s.conversation_history, s.created_at, s.updated_at, s.auto_tags, s.instruments, s.complexity, s.estimated_bpm
 		FROM user_strudels s
 		LEFT JOIN users u ON s.user_id = u.id
 		%s
-		ORDER BY s.created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, baseWhere, argIndex, argIndex+1)
+		ORDER BY %s
+		LIMIT $%d
+	`, baseWhere, page.OrderBy("s."+column, "s.id"), argIndex)
 
-	args = append(args, limit, offset)
+	args = append(args, page.Fetch())
 
-	rows, err := r.db.Query(ctx, listQuery, args...)
+	rows, err := db.Query(ctx, listQuery, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, keyset.Edges{}, 0, err
 	}
 
 	defer rows.Close()
@@ -253,9 +318,13 @@ func (r *Repository) ListPublic(ctx context.Context, limit, offset int, filter L
 			&s.ConversationHistory,
 			&s.CreatedAt,
 			&s.UpdatedAt,
+			&s.AutoTags,
+			&s.Instruments,
+			&s.Complexity,
+			&s.EstimatedBPM,
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, keyset.Edges{}, 0, err
 		}
 
 		if authorName != nil {
@@ -266,10 +335,78 @@ func (r *Repository) ListPublic(ctx context.Context, limit, offset int, filter L
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, keyset.Edges{}, 0, err
+	}
+
+	strudels, edges := keyset.Cut(page, strudels, strudelKey(page.Sort.Field))
+	return strudels, edges, total, nil
+}
+
+// the column and type behind a sort field of strudel lists
+func sortColumn(field string) (string, string) {
+	switch field {
+	case "updated_at":
+		return "updated_at", "timestamptz"
+	case "title":
+		return "title", "text"
+	default:
+		return "created_at", "timestamptz"
+	}
+}
+
+// the position of a strudel in lists sorted by field
+func strudelKey(field string) func(Strudel) keyset.Key {
+	return func(s Strudel) keyset.Key {
+		switch field {
+		case "updated_at":
+			return keyset.Key{Value: keyset.Time(s.UpdatedAt), ID: s.ID}
+		case "title":
+			return keyset.Key{Value: s.Title, ID: s.ID}
+		default:
+			return keyset.Key{Value: keyset.Time(s.CreatedAt), ID: s.ID}
+		}
+	}
+}
+
+// appends filters on the analysis columns; col is the table alias prefix (e.g. "s.")
+func appendAnalysisFilters(
+	where string,
+	args []interface{},
+	argIndex int,
+	col string,
+	filter ListFilter,
+) (string, []interface{}, int) {
+	if len(filter.Instruments) > 0 {
+		where += fmt.Sprintf(" AND %sinstruments && $%d", col, argIndex)
+		args = append(args, filter.Instruments)
+		argIndex++
+	}
+
+	if filter.MinComplexity != nil {
+		where += fmt.Sprintf(" AND %scomplexity >= $%d", col, argIndex)
+		args = append(args, *filter.MinComplexity)
+		argIndex++
+	}
+
+	if filter.MaxComplexity != nil {
+		where += fmt.Sprintf(" AND %scomplexity <= $%d", col, argIndex)
+		args = append(args, *filter.MaxComplexity)
+		argIndex++
+	}
+
+	if filter.MinBPM != nil {
+		where += fmt.Sprintf(" AND %sestimated_bpm >= $%d", col, argIndex)
+		args = append(args, *filter.MinBPM)
+		argIndex++
+	}
+
+	if filter.MaxBPM != nil {
+		where += fmt.Sprintf(" AND %sestimated_bpm <= $%d", col, argIndex)
+		args = append(args, *filter.MaxBPM)
+		argIndex++
 	}
 
-	return strudels, total, nil
+	return where, args, argIndex
 }
 
 func (r *Repository) GetPublic(ctx context.Context, strudelID string) (*Strudel, error) {
@@ -294,6 +431,7 @@ func (r *Repository) GetPublic(ctx context.Context, strudelID string) (*Strudel,
 		&strudel.ConversationHistory,
 		&strudel.CreatedAt,
 		&strudel.UpdatedAt,
+		&strudel.PreviewURL,
 	)
 
 	if err != nil {
@@ -327,6 +465,7 @@ func (r *Repository) Get(ctx context.Context, strudelID, userID string) (*Strude
 		&strudel.ConversationHistory,
 		&strudel.CreatedAt,
 		&strudel.UpdatedAt,
+		&strudel.PreviewURL,
 	)
 
 	if err != nil {
@@ -336,13 +475,38 @@ func (r *Repository) Get(ctx context.Context, strudelID, userID string) (*Strude
 	return &strudel, nil
 }
 
+// updates a strudel. inTx, when set, runs in the transaction of the write,
+// e.g. to queue its events
 func (r *Repository) Update(
 	ctx context.Context,
 	strudelID, userID string,
 	req UpdateStrudelRequest,
+	inTx func(tx pgx.Tx, strudel *Strudel) error,
+) (*Strudel, error) {
+	strudel, err := r.update(ctx, strudelID, userID, req, inTx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code != nil {
+		r.recordVersionLogged(ctx, strudel.ID, strudel.Code, true)
+	}
+
+	return strudel, nil
+}
+
+func (r *Repository) update(
+	ctx context.Context,
+	strudelID, userID string,
+	req UpdateStrudelRequest,
+	inTx func(tx pgx.Tx, strudel *Strudel) error,
 ) (*Strudel, error) {
 	var strudel Strudel
 
+	if err := r.checkForkSignal(ctx, strudelID, userID, req); err != nil {
+		return nil, err
+	}
+
 	// count AI code responses if conversation history provided
 	var aiAssistCount *int
 
@@ -370,39 +534,55 @@ func (r *Repository) Update(
 		conversationHistoryJSON = string(jsonBytes)
 	}
 
-	err := r.db.QueryRow(
-		ctx,
-		queryUpdate,
-		req.Title,
-		req.Code,
-		req.IsPublic,
-		req.License,
-		req.CCSignal,
-		aiAssistCount,
-		req.Description,
-		req.Tags,
-		req.Categories,
-		conversationHistoryJSON,
-		strudelID,
-		userID,
-	).Scan(
-		&strudel.ID,
-		&strudel.UserID,
-		&strudel.Title,
-		&strudel.Code,
-		&strudel.IsPublic,
-		&strudel.License,
-		&strudel.CCSignal,
-		&strudel.UseInTraining,
-		&strudel.AIAssistCount,
-		&strudel.ForkedFrom,
-		&strudel.Description,
-		&strudel.Tags,
-		&strudel.Categories,
-		&strudel.ConversationHistory,
-		&strudel.CreatedAt,
-		&strudel.UpdatedAt,
-	)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(
+			ctx,
+			queryUpdate,
+			req.Title,
+			req.Code,
+			req.IsPublic,
+			req.License,
+			req.CCSignal,
+			aiAssistCount,
+			req.Description,
+			req.Tags,
+			req.Categories,
+			conversationHistoryJSON,
+			strudelID,
+			userID,
+			req.UpdatedAt,
+		).Scan(
+			&strudel.ID,
+			&strudel.UserID,
+			&strudel.Title,
+			&strudel.Code,
+			&strudel.IsPublic,
+			&strudel.License,
+			&strudel.CCSignal,
+			&strudel.UseInTraining,
+			&strudel.AIAssistCount,
+			&strudel.ForkedFrom,
+			&strudel.Description,
+			&strudel.Tags,
+			&strudel.Categories,
+			&strudel.ConversationHistory,
+			&strudel.CreatedAt,
+			&strudel.UpdatedAt,
+		)
+
+		if err != nil || inTx == nil {
+			return err
+		}
+
+		return inTx(tx, &strudel)
+	})
+
+	// a stale updated_at matches no rows too; tell it apart from a missing strudel
+	if errors.Is(err, pgx.ErrNoRows) && req.UpdatedAt != nil {
+		if _, getErr := r.Get(ctx, strudelID, userID); getErr == nil {
+			return nil, ErrStrudelConflict
+		}
+	}
 
 	if err != nil {
 		return nil, err
@@ -411,8 +591,9 @@ func (r *Repository) Update(
 	return &strudel, nil
 }
 
+// moves a strudel to the trash, where it stays restorable until purged
 func (r *Repository) Delete(ctx context.Context, strudelID, userID string) error {
-	result, err := r.db.Exec(ctx, queryDelete, strudelID, userID)
+	result, err := r.db.Exec(ctx, querySoftDelete, strudelID, userID)
 	if err != nil {
 		return err
 	}
@@ -534,9 +715,57 @@ func (r *Repository) AdminSetUseInTraining(ctx context.Context, strudelID string
 	return &strudel, nil
 }
 
+// publishes or unpublishes any strudel (admin only)
+func (r *Repository) AdminSetPublic(ctx context.Context, strudelID string, isPublic bool) (*Strudel, error) {
+	var strudel Strudel
+
+	err := r.db.QueryRow(ctx, queryAdminSetPublic, isPublic, strudelID).Scan(
+		&strudel.ID,
+		&strudel.UserID,
+		&strudel.Title,
+		&strudel.Code,
+		&strudel.IsPublic,
+		&strudel.License,
+		&strudel.CCSignal,
+		&strudel.UseInTraining,
+		&strudel.AIAssistCount,
+		&strudel.ForkedFrom,
+		&strudel.Description,
+		&strudel.Tags,
+		&strudel.Categories,
+		&strudel.ConversationHistory,
+		&strudel.CreatedAt,
+		&strudel.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrStrudelNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &strudel, nil
+}
+
+// deletes any strudel regardless of owner (admin only)
+func (r *Repository) AdminDelete(ctx context.Context, strudelID string) error {
+	result, err := r.db.Exec(ctx, queryAdminDelete, strudelID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrStrudelNotFound
+	}
+
+	return nil
+}
+
 // returns all unique tags from public strudels
 func (r *Repository) ListPublicTags(ctx context.Context) ([]string, error) {
-	rows, err := r.db.Query(ctx, queryListPublicTags)
+	rows, err := r.reader(ctx).Query(ctx, queryListPublicTags)
 	if err != nil {
 		return nil, err
 	}
@@ -700,7 +929,16 @@ type NoAIStrudel struct {
 }
 
 func (r *Repository) ListNoAIStrudels(ctx context.Context, minContentLength int) ([]NoAIStrudel, error) {
-	rows, err := r.db.Query(ctx, queryListNoAIStrudels, minContentLength)
+	return r.listNoAIStrudels(ctx, queryListNoAIStrudels, minContentLength)
+}
+
+// returns the no-ai strudels among ids with code length >= minContentLength
+func (r *Repository) ListNoAIStrudelsByID(ctx context.Context, ids []string, minContentLength int) ([]NoAIStrudel, error) {
+	return r.listNoAIStrudels(ctx, queryListNoAIStrudelsByID, ids, minContentLength)
+}
+
+func (r *Repository) listNoAIStrudels(ctx context.Context, query string, args ...any) ([]NoAIStrudel, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -730,6 +968,35 @@ func (r *Repository) ListNoAIStrudels(ctx context.Context, minContentLength int)
 	return strudels, nil
 }
 
+// identifies a no-ai strudel's code without its content
+type NoAIStrudelDigest struct {
+	ID          string
+	UserID      string
+	ContentHash string // hex sha256 of the code
+}
+
+// returns digests of the strudels ListNoAIStrudels would return
+func (r *Repository) ListNoAIStrudelDigests(ctx context.Context, minContentLength int) ([]NoAIStrudelDigest, error) {
+	rows, err := r.db.Query(ctx, queryListNoAIStrudelDigests, minContentLength)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var digests []NoAIStrudelDigest
+
+	for rows.Next() {
+		var d NoAIStrudelDigest
+		if err := rows.Scan(&d.ID, &d.UserID, &d.ContentHash); err != nil {
+			return nil, err
+		}
+
+		digests = append(digests, d)
+	}
+
+	return digests, rows.Err()
+}
+
 // retrieves AI conversation messages for a strudel
 func (r *Repository) GetStrudelMessages(ctx context.Context, strudelID string, limit int) ([]*StrudelMessage, error) {
 	rows, err := r.db.Query(ctx, queryGetStrudelMessages, strudelID, limit)
@@ -0,0 +1,31 @@
+package strudels
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// returns the strudel's conversation summary, or nil if it has none
+func (r *Repository) GetConversationSummary(ctx context.Context, strudelID string) (*ConversationSummary, error) {
+	var summary ConversationSummary
+
+	err := r.db.QueryRow(ctx, queryGetConversationSummary, strudelID).Scan(&summary.Text, &summary.Through)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// replaces the strudel's conversation summary
+func (r *Repository) SetConversationSummary(ctx context.Context, strudelID, summary string, through time.Time) error {
+	_, err := r.db.Exec(ctx, queryUpdateConversationSummary, strudelID, summary, through)
+	return err
+}
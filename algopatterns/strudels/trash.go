@@ -0,0 +1,103 @@
+package strudels
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// lists a user's trashed strudels, most recently deleted first
+func (r *Repository) ListTrash(ctx context.Context, userID string, limit, offset int) ([]Strudel, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, queryCountTrash, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, queryListTrash, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	strudels := []Strudel{}
+
+	for rows.Next() {
+		var s Strudel
+		err := rows.Scan(
+			&s.ID,
+			&s.UserID,
+			&s.Title,
+			&s.Code,
+			&s.IsPublic,
+			&s.License,
+			&s.CCSignal,
+			&s.UseInTraining,
+			&s.AIAssistCount,
+			&s.ForkedFrom,
+			&s.Description,
+			&s.Tags,
+			&s.Categories,
+			&s.ConversationHistory,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+			&s.DeletedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		strudels = append(strudels, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return strudels, total, nil
+}
+
+// moves a trashed strudel back to the user's library
+func (r *Repository) Restore(ctx context.Context, strudelID, userID string) (*Strudel, error) {
+	var strudel Strudel
+
+	err := r.db.QueryRow(ctx, queryRestore, strudelID, userID).Scan(
+		&strudel.ID,
+		&strudel.UserID,
+		&strudel.Title,
+		&strudel.Code,
+		&strudel.IsPublic,
+		&strudel.License,
+		&strudel.CCSignal,
+		&strudel.UseInTraining,
+		&strudel.AIAssistCount,
+		&strudel.ForkedFrom,
+		&strudel.Description,
+		&strudel.Tags,
+		&strudel.Categories,
+		&strudel.ConversationHistory,
+		&strudel.CreatedAt,
+		&strudel.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrStrudelNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &strudel, nil
+}
+
+// hard-deletes strudels trashed longer than TrashRetention and returns their IDs.
+// their embeddings go with the rows; callers drop any in-memory fingerprints.
+func (r *Repository) PurgeTrash(ctx context.Context) ([]string, error) {
+	return r.listIDs(ctx, queryPurgeTrash, trashCutoff(time.Now()))
+}
+
+// strudels deleted before the returned time have been in the trash for the
+// full retention period
+func trashCutoff(now time.Time) time.Time {
+	return now.Add(-TrashRetention)
+}
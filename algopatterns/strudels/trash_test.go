@@ -0,0 +1,20 @@
+package strudels
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	cutoff := trashCutoff(now)
+
+	// purged rows are the ones deleted before the cutoff
+	purged := func(deletedAt time.Time) bool { return deletedAt.Before(cutoff) }
+
+	assert.True(t, purged(time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC)), "31 days in the trash")
+	assert.False(t, purged(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)), "exactly 30 days in the trash")
+	assert.False(t, purged(time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC)), "deleted yesterday")
+}
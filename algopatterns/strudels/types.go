@@ -1,11 +1,13 @@
 package strudels
 
 import (
+	"context"
 	"database/sql/driver"
 	"encoding/json"
 	"time"
 
 	"codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/database"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -31,6 +33,52 @@ const (
 	LicenseBYNCND = "CC BY-NC-ND 4.0"
 )
 
+// canonical deeds of the license values
+var licenseURLs = map[string]string{
+	LicenseCC0:    "https://creativecommons.org/publicdomain/zero/1.0/",
+	LicenseBY:     "https://creativecommons.org/licenses/by/4.0/",
+	LicenseBYSA:   "https://creativecommons.org/licenses/by-sa/4.0/",
+	LicenseBYNC:   "https://creativecommons.org/licenses/by-nc/4.0/",
+	LicenseBYNCSA: "https://creativecommons.org/licenses/by-nc-sa/4.0/",
+	LicenseBYND:   "https://creativecommons.org/licenses/by-nd/4.0/",
+	LicenseBYNCND: "https://creativecommons.org/licenses/by-nc-nd/4.0/",
+}
+
+// LicenseURL returns the deed of a license, "" for unknown licenses
+func LicenseURL(license string) string {
+	return licenseURLs[license]
+}
+
+// code analysis constants
+const (
+	analysisTimeout           = 10 * time.Second // per strudel, including the database write
+	analysisBackfillBatchSize = 100
+)
+
+// example indexing constants
+const (
+	indexTimeout           = 30 * time.Second // per strudel, including the embedding request
+	indexBackfillBatchSize = 50
+)
+
+// trashed strudels are purged after this long
+const TrashRetention = 30 * 24 * time.Hour
+
+// version history trimming: saves within versionCoalesceWindow of the latest
+// version replace its code, at most maxVersionsPerStrudel are kept, and versions
+// older than versionThinningAge are thinned to the last one of each day
+const (
+	versionCoalesceWindow = time.Minute
+	maxVersionsPerStrudel = 100
+	versionThinningAge    = 30 * 24 * time.Hour
+)
+
+// fork lineage limits (guard against runaway recursion on very popular strudels)
+const (
+	maxLineageDepth       = 50
+	maxLineageDescendants = 500
+)
+
 // signalRestrictiveness defines the restrictiveness order (higher = more restrictive)
 var signalRestrictiveness = map[CCSignal]int{
 	"":                0, // NULL - no preference
@@ -46,6 +94,43 @@ func (s CCSignal) MoreRestrictiveThan(other CCSignal) bool {
 	return signalRestrictiveness[s] > signalRestrictiveness[other]
 }
 
+// conditions each signal puts on AI use of a work
+var signalRequirements = map[CCSignal][]string{
+	CCSignalCredit:    {"credit"},
+	CCSignalDirect:    {"credit", "direct_contribution"},
+	CCSignalEcosystem: {"credit", "ecosystem_contribution"},
+	CCSignalOpen:      {"credit", "open"},
+}
+
+// AllowsAI returns true if the signal permits AI use. a missing signal is
+// treated as no-ai
+func (s CCSignal) AllowsAI() bool {
+	_, ok := signalRequirements[s]
+	return ok
+}
+
+// Requirements returns the conditions the signal puts on AI use, empty when
+// AI use isn't allowed
+func (s CCSignal) Requirements() []string {
+	if requirements, ok := signalRequirements[s]; ok {
+		return requirements
+	}
+
+	return []string{}
+}
+
+// MostRestrictive returns the most restrictive of the signals, "" for none
+func MostRestrictive(signals ...CCSignal) CCSignal {
+	var most CCSignal
+	for _, signal := range signals {
+		if signal.MoreRestrictiveThan(most) {
+			most = signal
+		}
+	}
+
+	return most
+}
+
 // IsValid returns true if the signal is a valid CC Signal value
 func (s CCSignal) IsValid() bool {
 	switch s {
@@ -57,7 +142,14 @@ func (s CCSignal) IsValid() bool {
 }
 
 type Repository struct {
-	db *pgxpool.Pool
+	db       *pgxpool.Pool
+	replicas *database.Router // nil reads everything from db
+	embedder Embedder         // nil disables example indexing
+}
+
+// embeds strudels for the RAG examples index
+type Embedder interface {
+	GenerateEmbedding(ctx context.Context, text string) ([]float32, error)
 }
 
 type Strudel struct {
@@ -78,6 +170,23 @@ type Strudel struct {
 	ConversationHistory ConversationHistory `json:"conversation_history,omitempty"`
 	CreatedAt           time.Time           `json:"created_at"`
 	UpdatedAt           time.Time           `json:"updated_at"`
+
+	// derived from the code in the background (only populated in list responses)
+	AutoTags     []string `json:"auto_tags,omitempty"`
+	Instruments  []string `json:"instruments,omitempty"`
+	Complexity   *int     `json:"complexity,omitempty"`    // 0-10, nil until analyzed
+	EstimatedBPM *float64 `json:"estimated_bpm,omitempty"` // nil when unknown
+
+	// the most restrictive signal of the strudel and the strudels it was forked
+	// from, which is what applies to it (only populated in single strudel responses)
+	EffectiveCCSignal *CCSignal `json:"effective_cc_signal,omitempty"`
+
+	// audio of the strudel's latest render, which may be of older code (only
+	// populated in single strudel responses)
+	PreviewURL *string `json:"preview_url,omitempty"`
+
+	// when the strudel was moved to the trash (only populated in trash listings)
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type ConversationHistory []agent.Message
@@ -138,11 +247,128 @@ type UpdateStrudelRequest struct {
 	Tags                []string            `json:"tags,omitempty" binding:"max=20,dive,max=50"`
 	Categories          []string            `json:"categories,omitempty" binding:"max=10,dive,max=50"`
 	ConversationHistory ConversationHistory `json:"conversation_history,omitempty" binding:"max=100"`
+
+	// the updated_at the client last loaded; when set, the update fails with
+	// ErrStrudelConflict if the strudel has been saved since. the API requires
+	// it or If-Match, nil skips the check for internal writes like reverts
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// bulk actions
+const (
+	BulkDelete        = "delete" // moves to the trash
+	BulkAddTags       = "add_tags"
+	BulkRemoveTags    = "remove_tags"
+	BulkSetVisibility = "set_visibility"
+	BulkSetCCSignal   = "set_cc_signal"
+)
+
+// per-item outcomes of a bulk operation. the operation is applied to all
+// items or none, so when any item fails the others are skipped
+const (
+	BulkApplied  = "applied"
+	BulkNotFound = "not_found"
+	BulkRejected = "rejected" // the change isn't allowed for this strudel
+	BulkSkipped  = "skipped"  // another item failed, nothing was changed
+)
+
+type BulkRequest struct {
+	Action   string    `json:"action" binding:"required,oneof=delete add_tags remove_tags set_visibility set_cc_signal"`
+	IDs      []string  `json:"ids" binding:"required,min=1,max=100"`        // at most 100 strudels per call
+	Tags     []string  `json:"tags,omitempty" binding:"max=20,dive,max=50"` // add_tags and remove_tags
+	IsPublic *bool     `json:"is_public,omitempty"`                         // set_visibility
+	CCSignal *CCSignal `json:"cc_signal,omitempty"`                         // set_cc_signal
+}
+
+type BulkResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status" enums:"applied,not_found,rejected,skipped"`
+	Error  string `json:"error,omitempty"` // why the item was rejected
+
+	// the strudel before and after the change, for follow-up work (applied items only)
+	Previous *Strudel `json:"-"`
+	Strudel  *Strudel `json:"-"`
 }
 
 type ListFilter struct {
-	Search string   // search in title and description
-	Tags   []string // filter by tags (any match)
+	Search        string   // search in title and description
+	Tags          []string // filter by user or auto tags (any match)
+	Instruments   []string // filter by instruments (any match)
+	MinComplexity *int     // inclusive
+	MaxComplexity *int     // inclusive
+	MinBPM        *float64 // inclusive
+	MaxBPM        *float64 // inclusive
+}
+
+// fields strudel lists can be sorted by
+var ListSorts = []string{"created_at", "updated_at", "title"}
+
+// search sort orders
+const (
+	SortRelevance = "relevance" // full-text rank; falls back to recent without a query
+	SortRecent    = "recent"
+	SortPopular   = "popular" // forks plus uses as AI context
+)
+
+type SearchParams struct {
+	Query         string     // full-text query (websearch syntax: quotes, OR, -exclude)
+	Tags          []string   // filter by user or auto tags (any match)
+	MinComplexity *int       // inclusive
+	MaxComplexity *int       // inclusive
+	CCSignals     []CCSignal // filter by CC signal (any match)
+	Sort          string
+	Limit         int
+	After         *SearchCursor // nil for the first page
+}
+
+// position of the last result of a page; only the fields of the sort order are set
+type SearchCursor struct {
+	Rank       float64   `json:"r,omitempty"`
+	Popularity int       `json:"p,omitempty"`
+	CreatedAt  time.Time `json:"t"`
+	ID         string    `json:"id"`
+}
+
+// a public strudel matching a search
+type SearchResult struct {
+	Strudel
+	Rank       float64 `json:"rank,omitempty"` // full-text relevance, 0 without a query
+	Popularity int     `json:"popularity"`
+}
+
+// a saved revision of a strudel's code
+type StrudelVersion struct {
+	Version   int       `json:"version"`
+	Code      string    `json:"code,omitempty"` // omitted in listings
+	Size      int       `json:"size"`           // code length in bytes
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// a strudel in a fork lineage. private strudels the viewer doesn't own are
+// redacted to their position in the graph.
+type LineageNode struct {
+	ID         string         `json:"id,omitempty"`
+	UserID     string         `json:"user_id,omitempty"`
+	AuthorName string         `json:"author_name,omitempty"`
+	Title      string         `json:"title,omitempty"`
+	CCSignal   *CCSignal      `json:"cc_signal,omitempty"`
+	License    *string        `json:"license,omitempty"`
+	IsPublic   bool           `json:"is_public"`
+	Redacted   bool           `json:"redacted,omitempty"`
+	Depth      int            `json:"depth"`      // generations away from the requested strudel
+	ForkCount  int            `json:"fork_count"` // direct forks, including private ones
+	CreatedAt  *time.Time     `json:"created_at,omitempty"`
+	Children   []*LineageNode `json:"children,omitempty"` // descendant trees only
+
+	parentID string // the strudel this one was forked from, set on descendants
+}
+
+// summary of a strudel's descendant tree
+type ForkCounts struct {
+	Direct    int  `json:"direct"`    // forks of the strudel itself
+	Total     int  `json:"total"`     // all descendants up to the maximum depth
+	MaxDepth  int  `json:"max_depth"` // generations below the strudel
+	Truncated bool `json:"truncated"` // the tree was cut off at the depth or size limit
 }
 
 // represents an AI conversation message for a saved strudel
@@ -161,6 +387,12 @@ type StrudelMessage struct {
 	CreatedAt           time.Time          `json:"created_at"`
 }
 
+// rolling summary of the older messages of a strudel's AI conversation
+type ConversationSummary struct {
+	Text    string
+	Through time.Time // created_at of the newest message it covers
+}
+
 // reference to a strudel used as AI context
 type StrudelReference struct {
 	ID         string `json:"id"`
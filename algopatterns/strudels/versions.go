@@ -0,0 +1,125 @@
+package strudels
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// lists the versions of a strudel the user owns, newest first, without their code
+func (r *Repository) ListVersions(ctx context.Context, strudelID, userID string) ([]StrudelVersion, error) {
+	rows, err := r.db.Query(ctx, queryListVersions, strudelID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	versions := []StrudelVersion{}
+
+	for rows.Next() {
+		var v StrudelVersion
+		if err := rows.Scan(&v.Version, &v.Size, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// gets a version of a strudel the user owns, including its code
+func (r *Repository) GetVersion(ctx context.Context, strudelID, userID string, version int) (*StrudelVersion, error) {
+	var v StrudelVersion
+
+	err := r.db.QueryRow(ctx, queryGetVersion, strudelID, userID, version).Scan(&v.Version, &v.Code, &v.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrVersionNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	v.Size = len(v.Code)
+	return &v, nil
+}
+
+// restores the code of a version. the restored code is saved as a new
+// version, so a revert can itself be reverted.
+func (r *Repository) Revert(ctx context.Context, strudelID, userID string, version int) (*Strudel, error) {
+	v, err := r.GetVersion(ctx, strudelID, userID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	strudel, err := r.update(ctx, strudelID, userID, UpdateStrudelRequest{Code: &v.Code}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordVersionLogged(ctx, strudel.ID, strudel.Code, false)
+
+	return strudel, nil
+}
+
+// records a version without failing the save it belongs to
+func (r *Repository) recordVersionLogged(ctx context.Context, strudelID, code string, coalesce bool) {
+	if err := r.recordVersion(ctx, strudelID, code, coalesce); err != nil {
+		logger.Warn("failed to record strudel version", "error", err, "strudel_id", strudelID)
+	}
+}
+
+// adds code as the next version of a strudel unless it equals the latest
+// version. with coalesce, code saved shortly after the latest version
+// replaces it instead, so autosaves don't flood the history.
+func (r *Repository) recordVersion(ctx context.Context, strudelID, code string, coalesce bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck,gosec // no-op after commit
+
+	var latest int
+	var latestCode string
+	var latestAt time.Time
+
+	err = tx.QueryRow(ctx, queryLatestVersion, strudelID).Scan(&latest, &latestCode, &latestAt)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// first version
+	case err != nil:
+		return err
+	case latestCode == code:
+		return nil
+	case coalesce && time.Since(latestAt) < versionCoalesceWindow:
+		if _, err := tx.Exec(ctx, queryReplaceVersionCode, code, strudelID, latest); err != nil {
+			return err
+		}
+
+		return tx.Commit(ctx)
+	}
+
+	if _, err := tx.Exec(ctx, queryInsertVersion, strudelID, latest+1, code); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, queryTrimVersionCount, strudelID, maxVersionsPerStrudel); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, queryThinOldVersions, strudelID, time.Now().Add(-versionThinningAge)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -1,62 +1,128 @@
 package users
 
 const (
-	queryFindOrCreateByProvider = `
+	queryFindIdentity = `
+		SELECT user_id
+		FROM user_identities
+		WHERE provider = $1 AND provider_id = $2
+	`
+
+	// only the provider the account was created with keeps its profile in sync,
+	// except for an uploaded avatar
+	queryUpdateProviderProfile = `
+		UPDATE users
+		SET email = $4, name = $5,
+			avatar_url = CASE WHEN avatar_asset_id IS NULL THEN $6 ELSE avatar_url END,
+			updated_at = NOW()
+		WHERE id = $1 AND provider = $2 AND provider_id = $3
+	`
+
+	// only an account one of whose providers verified the email, so nobody can
+	// claim an address first and be handed its owner's later logins
+	queryFindUserByVerifiedEmail = `
+		SELECT u.id
+		FROM users u
+		JOIN user_identities i ON i.user_id = u.id
+		WHERE lower(i.email) = lower($1) AND i.email_verified AND u.provider <> 'system'
+		ORDER BY u.created_at
+		LIMIT 1
+	`
+
+	queryCreateUser = `
 		INSERT INTO users (provider, provider_id, email, name, avatar_url)
 		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT (provider, provider_id)
 		DO UPDATE SET
 			email = EXCLUDED.email,
 			name = EXCLUDED.name,
-			avatar_url = EXCLUDED.avatar_url,
+			avatar_url = CASE WHEN users.avatar_asset_id IS NULL THEN EXCLUDED.avatar_url ELSE users.avatar_url END,
 			updated_at = NOW()
-		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, training_consent, ai_features_enabled, created_at, updated_at
+		RETURNING id
+	`
+
+	queryLinkIdentity = `
+		INSERT INTO user_identities (provider, provider_id, user_id, email, email_verified)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		ON CONFLICT (provider, provider_id) DO NOTHING
+	`
+
+	// every login refreshes what the provider says about the identity's email
+	queryUpdateIdentityEmail = `
+		UPDATE user_identities
+		SET email = NULLIF($3, ''), email_verified = $4
+		WHERE provider = $1 AND provider_id = $2
+	`
+
+	queryHasVerifiedEmail = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_identities
+			WHERE user_id = $1 AND lower(email) = lower($2) AND email_verified
+		)
 	`
 
 	queryFindByID = `
-		SELECT id, email, provider, provider_id, name, avatar_url, tier, is_admin, training_consent, ai_features_enabled, created_at, updated_at
+		SELECT id, email, provider, provider_id, name, avatar_url, tier, is_admin, banned_at, ban_reason, training_consent, ai_features_enabled, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
 
+	queryFindByEmail = `
+		SELECT id, email, provider, provider_id, name, avatar_url, tier, is_admin, banned_at, ban_reason, training_consent, ai_features_enabled, created_at, updated_at
+		FROM users
+		WHERE LOWER(email) = LOWER($1)
+		ORDER BY created_at
+		LIMIT 1
+	`
+
+	// setting another avatar_url lets go of the uploaded avatar
 	queryUpdateProfile = `
 		UPDATE users
-		SET name = $1, avatar_url = $2, updated_at = NOW()
+		SET name = $1, avatar_url = $2,
+			avatar_asset_id = CASE WHEN avatar_url IS DISTINCT FROM $2 THEN NULL ELSE avatar_asset_id END,
+			updated_at = NOW()
 		WHERE id = $3
-		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, training_consent, ai_features_enabled, created_at, updated_at
+		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, banned_at, ban_reason, training_consent, ai_features_enabled, created_at, updated_at
 	`
 
 	queryUpdateTrainingConsent = `
 		UPDATE users
 		SET training_consent = $1, updated_at = NOW()
 		WHERE id = $2
-		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, training_consent, ai_features_enabled, created_at, updated_at
+		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, banned_at, ban_reason, training_consent, ai_features_enabled, created_at, updated_at
 	`
 
 	queryUpdateAIFeaturesEnabled = `
 		UPDATE users
 		SET ai_features_enabled = $1, updated_at = NOW()
 		WHERE id = $2
-		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, training_consent, ai_features_enabled, created_at, updated_at
+		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, banned_at, ban_reason, training_consent, ai_features_enabled, created_at, updated_at
 	`
 
 	queryUpdateDisplayName = `
 		UPDATE users
 		SET name = $1, updated_at = NOW()
 		WHERE id = $2
-		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, training_consent, ai_features_enabled, created_at, updated_at
+		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, banned_at, ban_reason, training_consent, ai_features_enabled, created_at, updated_at
 	`
 
-	queryGetUserDailyUsage = `
-		SELECT get_user_usage_today($1)
+	queryBan = `
+		UPDATE users
+		SET banned_at = COALESCE(banned_at, NOW()), ban_reason = $2, banned_by = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, banned_at, ban_reason, training_consent, ai_features_enabled, created_at, updated_at
 	`
 
-	queryGetSessionDailyUsage = `
-		SELECT get_session_usage_today($1)
+	queryUnban = `
+		UPDATE users
+		SET banned_at = NULL, ban_reason = NULL, banned_by = NULL, updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, email, provider, provider_id, name, avatar_url, tier, is_admin, banned_at, ban_reason, training_consent, ai_features_enabled, created_at, updated_at
 	`
 
-	queryLogUsage = `
-		INSERT INTO usage_logs (user_id, session_id, provider, model, input_tokens, output_tokens, is_byok)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	queryIsActiveAdmin = `
+		SELECT is_admin AND banned_at IS NULL
+		FROM users
+		WHERE id = $1
 	`
 )
@@ -2,7 +2,9 @@ package users
 
 import (
 	"context"
+	"errors"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -10,46 +12,97 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
+// returns the account an OAuth identity signs in to, creating it on first
+// login. a new identity joins an existing account with the same email if
+// both its provider and one of the account's have verified that email.
 func (r *Repository) FindOrCreateByProvider(
 	ctx context.Context,
 	provider, providerID, email, name, avatarURL string,
+	emailVerified bool,
 ) (*User, error) {
-	var user User
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	err := r.db.QueryRow(
-		ctx,
-		queryFindOrCreateByProvider,
-		provider,
-		providerID,
-		email,
-		name,
-		avatarURL,
-	).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Provider,
-		&user.ProviderID,
-		&user.Name,
-		&user.AvatarURL,
-		&user.Tier,
-		&user.IsAdmin,
-		&user.TrainingConsent,
-		&user.AIFeaturesEnabled,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	defer tx.Rollback(ctx) //nolint:errcheck,gosec // no-op after commit
+
+	var userID string
+
+	err = tx.QueryRow(ctx, queryFindIdentity, provider, providerID).Scan(&userID)
+	switch {
+	case err == nil:
+		_, err = tx.Exec(ctx, queryUpdateProviderProfile, userID, provider, providerID, email, name, avatarURL)
+		if err == nil {
+			_, err = tx.Exec(ctx, queryUpdateIdentityEmail, provider, providerID, email, emailVerified)
+		}
+	case errors.Is(err, pgx.ErrNoRows):
+		userID, err = linkOrCreateUser(ctx, tx, provider, providerID, email, name, avatarURL, emailVerified)
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &user, nil
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, userID)
+}
+
+// attaches a new identity to the account whose verified email it shares,
+// or creates an account for it
+func linkOrCreateUser(
+	ctx context.Context,
+	tx pgx.Tx,
+	provider, providerID, email, name, avatarURL string,
+	emailVerified bool,
+) (string, error) {
+	var userID string
+
+	if emailVerified && email != "" {
+		err := tx.QueryRow(ctx, queryFindUserByVerifiedEmail, email).Scan(&userID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return "", err
+		}
+	}
+
+	if userID == "" {
+		err := tx.QueryRow(ctx, queryCreateUser, provider, providerID, email, name, avatarURL).Scan(&userID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, queryLinkIdentity, provider, providerID, userID, email, emailVerified); err != nil {
+		return "", err
+	}
+
+	return userID, nil
 }
 
 func (r *Repository) FindByID(ctx context.Context, userID string) (*User, error) {
+	return r.scanUser(r.db.QueryRow(ctx, queryFindByID, userID))
+}
+
+// reports whether one of the user's identities has the given email and
+// its provider verified it, ignoring case
+func (r *Repository) HasVerifiedEmail(ctx context.Context, userID, email string) (bool, error) {
+	var verified bool
+	err := r.db.QueryRow(ctx, queryHasVerifiedEmail, userID, email).Scan(&verified)
+	return verified, err
+}
+
+// finds the user with an email address, ignoring case
+func (r *Repository) FindByEmail(ctx context.Context, email string) (*User, error) {
+	return r.scanUser(r.db.QueryRow(ctx, queryFindByEmail, email))
+}
+
+func (r *Repository) scanUser(row pgx.Row) (*User, error) {
 	var user User
 
-	err := r.db.QueryRow(ctx, queryFindByID, userID).Scan(
+	err := row.Scan(
 		&user.ID,
 		&user.Email,
 		&user.Provider,
@@ -58,6 +111,8 @@ func (r *Repository) FindByID(ctx context.Context, userID string) (*User, error)
 		&user.AvatarURL,
 		&user.Tier,
 		&user.IsAdmin,
+		&user.BannedAt,
+		&user.BanReason,
 		&user.TrainingConsent,
 		&user.AIFeaturesEnabled,
 		&user.CreatedAt,
@@ -92,6 +147,8 @@ func (r *Repository) UpdateProfile(
 		&user.AvatarURL,
 		&user.Tier,
 		&user.IsAdmin,
+		&user.BannedAt,
+		&user.BanReason,
 		&user.TrainingConsent,
 		&user.AIFeaturesEnabled,
 		&user.CreatedAt,
@@ -126,6 +183,8 @@ func (r *Repository) UpdateTrainingConsent(
 		&user.AvatarURL,
 		&user.Tier,
 		&user.IsAdmin,
+		&user.BannedAt,
+		&user.BanReason,
 		&user.TrainingConsent,
 		&user.AIFeaturesEnabled,
 		&user.CreatedAt,
@@ -160,6 +219,8 @@ func (r *Repository) UpdateAIFeaturesEnabled(
 		&user.AvatarURL,
 		&user.Tier,
 		&user.IsAdmin,
+		&user.BannedAt,
+		&user.BanReason,
 		&user.TrainingConsent,
 		&user.AIFeaturesEnabled,
 		&user.CreatedAt,
@@ -194,6 +255,8 @@ func (r *Repository) UpdateDisplayName(
 		&user.AvatarURL,
 		&user.Tier,
 		&user.IsAdmin,
+		&user.BannedAt,
+		&user.BanReason,
 		&user.TrainingConsent,
 		&user.AIFeaturesEnabled,
 		&user.CreatedAt,
@@ -207,90 +270,74 @@ func (r *Repository) UpdateDisplayName(
 	return &user, nil
 }
 
-func (r *Repository) CheckUserRateLimit(ctx context.Context, userID string, isBYOK bool) (*RateLimitResult, error) {
-	if isBYOK {
-		return &RateLimitResult{
-			Allowed:   true,
-			Current:   0,
-			Limit:     DailyLimitBYOK,
-			Remaining: -1,
-		}, nil
-	}
+// bans a user, keeping the original ban time if already banned
+func (r *Repository) Ban(ctx context.Context, userID, adminID, reason string) (*User, error) {
+	var user User
+
+	err := r.db.QueryRow(ctx, queryBan, userID, reason, adminID).Scan(
+		&user.ID,
+		&user.Email,
+		&user.Provider,
+		&user.ProviderID,
+		&user.Name,
+		&user.AvatarURL,
+		&user.Tier,
+		&user.IsAdmin,
+		&user.BannedAt,
+		&user.BanReason,
+		&user.TrainingConsent,
+		&user.AIFeaturesEnabled,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
 
-	user, err := r.FindByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	var limit int
-	switch user.Tier {
-	case "payg":
-		limit = DailyLimitPAYG
-	case "byok":
-		limit = DailyLimitBYOK
-	default:
-		limit = DailyLimitFree
-	}
+	return &user, nil
+}
 
-	if limit == -1 {
-		return &RateLimitResult{
-			Allowed:   true,
-			Current:   0,
-			Limit:     limit,
-			Remaining: -1,
-		}, nil
-	}
+func (r *Repository) Unban(ctx context.Context, userID string) (*User, error) {
+	var user User
+
+	err := r.db.QueryRow(ctx, queryUnban, userID).Scan(
+		&user.ID,
+		&user.Email,
+		&user.Provider,
+		&user.ProviderID,
+		&user.Name,
+		&user.AvatarURL,
+		&user.Tier,
+		&user.IsAdmin,
+		&user.BannedAt,
+		&user.BanReason,
+		&user.TrainingConsent,
+		&user.AIFeaturesEnabled,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
 
-	var current int
-	err = r.db.QueryRow(ctx, queryGetUserDailyUsage, userID).Scan(&current)
 	if err != nil {
 		return nil, err
 	}
 
-	remaining := limit - current
-	if remaining < 0 {
-		remaining = 0
-	}
-
-	return &RateLimitResult{
-		Allowed:   current < limit,
-		Current:   current,
-		Limit:     limit,
-		Remaining: remaining,
-	}, nil
+	return &user, nil
 }
 
-func (r *Repository) CheckSessionRateLimit(ctx context.Context, sessionID string) (*RateLimitResult, error) {
-	var current int
-	err := r.db.QueryRow(ctx, queryGetSessionDailyUsage, sessionID).Scan(&current)
-	if err != nil {
-		return nil, err
-	}
+// reports whether the user currently has the admin role and isn't banned.
+// tokens carry is_admin until they are refreshed, so admin routes re-check it here.
+func (r *Repository) IsActiveAdmin(ctx context.Context, userID string) (bool, error) {
+	var isAdmin bool
 
-	remaining := DailyLimitAnonymous - current
-	if remaining < 0 {
-		remaining = 0
+	err := r.db.QueryRow(ctx, queryIsActiveAdmin, userID).Scan(&isAdmin)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
 	}
 
-	return &RateLimitResult{
-		Allowed:   current < DailyLimitAnonymous,
-		Current:   current,
-		Limit:     DailyLimitAnonymous,
-		Remaining: remaining,
-	}, nil
-}
+	if err != nil {
+		return false, err
+	}
 
-func (r *Repository) LogUsage(ctx context.Context, req *UsageLogRequest) error {
-	_, err := r.db.Exec(
-		ctx,
-		queryLogUsage,
-		req.UserID,
-		req.SessionID,
-		req.Provider,
-		req.Model,
-		req.InputTokens,
-		req.OutputTokens,
-		req.IsBYOK,
-	)
-	return err
+	return isAdmin, nil
 }
@@ -0,0 +1,43 @@
+//go:build integration
+
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/internal/testdb"
+)
+
+func TestVerifiedLoginLinksVerifiedAccount(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(testdb.Open(t))
+	email := testdb.Unique(t) + "@example.com"
+
+	first, err := repo.FindOrCreateByProvider(ctx, "github", testdb.Unique(t), email, "Ada", "", true)
+	require.NoError(t, err)
+
+	second, err := repo.FindOrCreateByProvider(ctx, "google", testdb.Unique(t), email, "Ada", "", true)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.ID, second.ID)
+}
+
+func TestVerifiedLoginSkipsUnverifiedAccount(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(testdb.Open(t))
+	email := testdb.Unique(t) + "@example.com"
+
+	// someone registers the address first through a provider that didn't verify it
+	squatter, err := repo.FindOrCreateByProvider(ctx, "discord", testdb.Unique(t), email, "Mallory", "", false)
+	require.NoError(t, err)
+
+	owner, err := repo.FindOrCreateByProvider(ctx, "google", testdb.Unique(t), email, "Ada", "", true)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, squatter.ID, owner.ID, "the owner's login must not sign in to the squatter's account")
+	assert.Equal(t, "google", owner.Provider)
+}
@@ -1,11 +1,29 @@
 package admin
 
 import (
+	stderrors "errors"
+	"io"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/algopatterns/users"
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/auth"
 	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/faults"
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/moderation"
+	"codeberg.org/algopatterns/server/internal/pasteappeals"
+	"codeberg.org/algopatterns/server/internal/stats"
+	"codeberg.org/algopatterns/server/internal/webhooks"
+	ws "codeberg.org/algopatterns/server/internal/websocket"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 // SetUseInTraining godoc
@@ -23,7 +41,7 @@ import (
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/admin/strudels/{id}/use-in-training [put]
 // @Security AdminKeyAuth
-func SetUseInTraining(strudelRepo *strudels.Repository) gin.HandlerFunc {
+func SetUseInTraining(strudelRepo *strudels.Repository, auditLog *audit.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		strudelID := c.Param("id")
 		if strudelID == "" {
@@ -43,6 +61,10 @@ func SetUseInTraining(strudelRepo *strudels.Repository) gin.HandlerFunc {
 			return
 		}
 
+		recordAction(c, auditLog, audit.ActionAdminStrudelTraining, audit.TargetStrudel, strudelID, gin.H{
+			"use_in_training": req.UseInTraining,
+		})
+
 		c.JSON(http.StatusOK, StrudelAdminResponse{
 			ID:            strudel.ID,
 			UserID:        strudel.UserID,
@@ -94,3 +116,1051 @@ func GetStrudel(strudelRepo *strudels.Repository) gin.HandlerFunc {
 		})
 	}
 }
+
+// DeleteStrudel godoc
+// @Summary Delete any strudel (admin)
+// @Description Admin-only endpoint to delete a strudel regardless of ownership
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Strudel ID"
+// @Param request body ModerationRequest false "Reason for the audit log"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/strudels/{id} [delete]
+// @Security AdminKeyAuth
+func DeleteStrudel(strudelRepo *strudels.Repository, auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req ModerationRequest
+		if !bindOptionalJSON(c, &req) {
+			return
+		}
+
+		strudel, err := strudelRepo.AdminGetStrudel(c.Request.Context(), strudelID)
+		if err != nil {
+			errors.NotFound(c, "strudel")
+			return
+		}
+
+		if err := strudelRepo.AdminDelete(c.Request.Context(), strudelID); err != nil {
+			if stderrors.Is(err, strudels.ErrStrudelNotFound) {
+				errors.NotFound(c, "strudel")
+				return
+			}
+
+			errors.InternalError(c, "failed to delete strudel", err)
+			return
+		}
+
+		recordAction(c, auditLog, audit.ActionAdminStrudelDelete, audit.TargetStrudel, strudelID, gin.H{
+			"reason":  req.Reason,
+			"title":   strudel.Title,
+			"user_id": strudel.UserID,
+		})
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "strudel deleted"})
+	}
+}
+
+// UnpublishStrudel godoc
+// @Summary Unpublish any strudel (admin)
+// @Description Admin-only endpoint to make a strudel private regardless of ownership
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Strudel ID"
+// @Param request body ModerationRequest false "Reason for the audit log"
+// @Success 200 {object} StrudelAdminResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/strudels/{id}/unpublish [post]
+// @Security AdminKeyAuth
+func UnpublishStrudel(strudelRepo *strudels.Repository, auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req ModerationRequest
+		if !bindOptionalJSON(c, &req) {
+			return
+		}
+
+		strudel, err := strudelRepo.AdminSetPublic(c.Request.Context(), strudelID, false)
+		if err != nil {
+			if stderrors.Is(err, strudels.ErrStrudelNotFound) {
+				errors.NotFound(c, "strudel")
+				return
+			}
+
+			errors.InternalError(c, "failed to unpublish strudel", err)
+			return
+		}
+
+		strudelRepo.IndexAsync(c.Request.Context(), strudelID)
+
+		recordAction(c, auditLog, audit.ActionAdminStrudelUnpublish, audit.TargetStrudel, strudelID, gin.H{
+			"reason":  req.Reason,
+			"user_id": strudel.UserID,
+		})
+
+		c.JSON(http.StatusOK, StrudelAdminResponse{
+			ID:            strudel.ID,
+			UserID:        strudel.UserID,
+			Title:         strudel.Title,
+			Code:          strudel.Code,
+			IsPublic:      strudel.IsPublic,
+			UseInTraining: strudel.UseInTraining,
+			Description:   strudel.Description,
+			Tags:          strudel.Tags,
+		})
+	}
+}
+
+// ListSessions godoc
+// @Summary List all sessions (admin)
+// @Description Admin-only endpoint to list sessions regardless of host or visibility, most recently active first
+// @Tags admin
+// @Produce json
+// @Param active query bool false "Only active sessions" default(true)
+// @Param limit query int false "Max results (default 20, max 100)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} SessionsListResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/sessions [get]
+// @Security AdminKeyAuth
+func ListSessions(sessionRepo sessions.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 20, 100)
+		activeOnly := c.DefaultQuery("active", "true") != "false"
+
+		list, total, err := sessionRepo.ListSessions(c.Request.Context(), activeOnly, params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list sessions", err)
+			return
+		}
+
+		responses := make([]SessionAdminResponse, 0, len(list))
+		for _, s := range list {
+			responses = append(responses, SessionAdminResponse{
+				ID:             s.ID,
+				HostUserID:     s.HostUserID,
+				Title:          s.Title,
+				IsActive:       s.IsActive,
+				IsDiscoverable: s.IsDiscoverable,
+				CreatedAt:      s.CreatedAt,
+				EndedAt:        s.EndedAt,
+				LastActivity:   s.LastActivity,
+			})
+		}
+
+		c.JSON(http.StatusOK, SessionsListResponse{
+			Sessions:   responses,
+			Pagination: pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// EndSession godoc
+// @Summary End any session (admin)
+// @Description Admin-only endpoint to end a session and disconnect its participants
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body ModerationRequest false "Reason for the audit log"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/sessions/{id}/end [post]
+// @Security AdminKeyAuth
+func EndSession(sessionRepo sessions.Repository, hub ConnectionHub, auditLog *audit.Service, events EventPublisher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req ModerationRequest
+		if !bindOptionalJSON(c, &req) {
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		if !session.IsActive {
+			errors.Conflict(c, "session has already ended")
+			return
+		}
+
+		err = sessionRepo.EndSession(c.Request.Context(), sessionID, func(tx pgx.Tx) error {
+			return events.PublishTx(c.Request.Context(), tx, session.HostUserID, webhooks.EventSessionEnded, webhooks.SessionEventData{
+				SessionID: sessionID,
+				Title:     session.Title,
+				Reason:    reasonEndedByAdmin,
+			})
+		})
+		if err != nil {
+			errors.InternalError(c, "failed to end session", err)
+			return
+		}
+
+		hub.EndSession(sessionID, "session ended by an admin")
+
+		recordAction(c, auditLog, audit.ActionAdminSessionEnd, audit.TargetSession, sessionID, gin.H{
+			"reason":       req.Reason,
+			"title":        session.Title,
+			"host_user_id": session.HostUserID,
+		})
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "session ended"})
+	}
+}
+
+// GetPasteLock godoc
+// @Summary Inspect a session's paste lock (admin)
+// @Description Admin-only endpoint to see whether a session is paste-locked and the recent paste detection decisions behind it
+// @Tags admin
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Success 200 {object} PasteLockResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse
+// @Router /api/v1/admin/sessions/{id}/paste-lock [get]
+// @Security AdminKeyAuth
+func GetPasteLock(pasteLocks PasteLockInspector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		if pasteLocks == nil {
+			errors.ServiceUnavailable(c, "paste detection is not available")
+			return
+		}
+
+		state, err := pasteLocks.GetLock(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.InternalError(c, "failed to get paste lock", err)
+			return
+		}
+
+		decisions, err := pasteLocks.RecentDecisions(c.Request.Context(), sessionID, pasteDecisionsLimit)
+		if err != nil {
+			errors.InternalError(c, "failed to get paste detection decisions", err)
+			return
+		}
+
+		response := PasteLockResponse{
+			SessionID: sessionID,
+			Locked:    state != nil && state.Locked,
+			Decisions: decisions,
+		}
+
+		if response.Locked {
+			response.BaselineLength = len(state.BaselineCode)
+			response.ExpiresIn = int(state.ExpiresIn.Seconds())
+
+			if !state.LockedAt.IsZero() {
+				response.LockedAt = &state.LockedAt
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// RemovePasteLock godoc
+// @Summary Remove a session's paste lock (admin)
+// @Description Admin-only endpoint to lift a paste lock, e.g. after a false positive. Connected clients are notified.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body ModerationRequest false "Reason for the audit log"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse
+// @Router /api/v1/admin/sessions/{id}/paste-lock [delete]
+// @Security AdminKeyAuth
+func RemovePasteLock(pasteLocks PasteLockInspector, hub ConnectionHub, auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req ModerationRequest
+		if !bindOptionalJSON(c, &req) {
+			return
+		}
+
+		if pasteLocks == nil {
+			errors.ServiceUnavailable(c, "paste detection is not available")
+			return
+		}
+
+		state, err := pasteLocks.GetLock(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.InternalError(c, "failed to get paste lock", err)
+			return
+		}
+
+		if state == nil || !state.Locked {
+			errors.NotFound(c, "paste lock")
+			return
+		}
+
+		if err := pasteLocks.RemoveLock(c.Request.Context(), sessionID); err != nil {
+			errors.InternalError(c, "failed to remove paste lock", err)
+			return
+		}
+
+		msg, err := ws.NewMessage(ws.TypePasteLockChanged, sessionID, "", ws.PasteLockChangedPayload{
+			Locked: false,
+			Reason: reasonUnlockedByAdmin,
+		})
+		if err == nil {
+			hub.BroadcastToSession(sessionID, msg, "")
+		}
+
+		recordAction(c, auditLog, audit.ActionAdminPasteLockRemove, audit.TargetSession, sessionID, gin.H{
+			"reason": req.Reason,
+		})
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "paste lock removed"})
+	}
+}
+
+// ListPasteLockAppeals godoc
+// @Summary List paste lock appeals (admin)
+// @Description Admin-only endpoint to read appeals against paste locks, newest first. Appeals whose ownership was verified were approved automatically
+// @Tags admin
+// @Produce json
+// @Param status query string false "Filter by status (pending, approved, rejected, all)" default(pending)
+// @Param limit query int false "Max results (default 50, max 200)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} PasteLockAppealsResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/paste-lock-appeals [get]
+// @Security AdminKeyAuth
+func ListPasteLockAppeals(appeals *pasteappeals.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 50, 200)
+
+		status := c.DefaultQuery("status", pasteappeals.StatusPending)
+		if status == "all" {
+			status = ""
+		}
+
+		items, total, err := appeals.List(c.Request.Context(), status, params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list paste lock appeals", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, PasteLockAppealsResponse{
+			Appeals:    items,
+			Pagination: pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// ResolvePasteLockAppeal godoc
+// @Summary Resolve paste lock appeal (admin)
+// @Description Admin-only endpoints to close a pending paste lock appeal. Approving lifts the session's paste lock and notifies connected clients
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Appeal ID"
+// @Param request body ModerationRequest false "Reason for the audit log"
+// @Success 200 {object} pasteappeals.Appeal
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/paste-lock-appeals/{id}/approve [post]
+// @Router /api/v1/admin/paste-lock-appeals/{id}/reject [post]
+// @Security AdminKeyAuth
+func ResolvePasteLockAppeal(appeals *pasteappeals.Service, pasteLocks PasteLockInspector, hub ConnectionHub, auditLog *audit.Service, status string) gin.HandlerFunc {
+	action := audit.ActionAdminAppealApprove
+	if status == pasteappeals.StatusRejected {
+		action = audit.ActionAdminAppealReject
+	}
+
+	return func(c *gin.Context) {
+		appealID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req ModerationRequest
+		if !bindOptionalJSON(c, &req) {
+			return
+		}
+
+		reviewerID, _ := auth.GetUserID(c) //nolint:errcheck // set by AdminAuthMiddleware
+
+		appeal, err := appeals.Resolve(c.Request.Context(), appealID, reviewerID, status)
+		if err != nil {
+			if stderrors.Is(err, pasteappeals.ErrAppealNotFound) {
+				errors.NotFound(c, "paste lock appeal")
+				return
+			}
+
+			errors.InternalError(c, "failed to resolve paste lock appeal", err)
+			return
+		}
+
+		// the lock may have lapsed or been edited away since the appeal
+		if status == pasteappeals.StatusApproved && pasteLocks != nil {
+			if err := pasteLocks.RemoveLock(c.Request.Context(), appeal.SessionID); err != nil {
+				errors.InternalError(c, "failed to remove paste lock", err)
+				return
+			}
+
+			msg, err := ws.NewMessage(ws.TypePasteLockChanged, appeal.SessionID, "", ws.PasteLockChangedPayload{
+				Locked: false,
+				Reason: reasonAppealApproved,
+			})
+			if err == nil {
+				hub.BroadcastToSession(appeal.SessionID, msg, "")
+			}
+		}
+
+		recordAction(c, auditLog, action, audit.TargetAppeal, appealID, gin.H{
+			"reason":     req.Reason,
+			"session_id": appeal.SessionID,
+			"user_id":    appeal.UserID,
+		})
+
+		c.JSON(http.StatusOK, appeal)
+	}
+}
+
+// BanUser godoc
+// @Summary Ban a user (admin)
+// @Description Admin-only endpoint to ban a user. Banned users can't sign in or join sessions, and their open connections are closed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param request body BanUserRequest true "Reason for the ban"
+// @Success 200 {object} BanUserResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/users/{id}/ban [post]
+// @Security AdminKeyAuth
+func BanUser(userRepo *users.Repository, hub ConnectionHub, auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req BanUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		adminID, _ := auth.GetUserID(c) //nolint:errcheck // set by AdminAuthMiddleware
+		if userID == adminID {
+			errors.BadRequest(c, "admins can't ban themselves", nil)
+			return
+		}
+
+		user, err := userRepo.FindByID(c.Request.Context(), userID)
+		if err != nil {
+			errors.NotFound(c, "user")
+			return
+		}
+
+		if user.IsAdmin {
+			errors.Forbidden(c, "admins can't be banned, revoke the admin role first")
+			return
+		}
+
+		user, err = userRepo.Ban(c.Request.Context(), userID, adminID, req.Reason)
+		if err != nil {
+			errors.InternalError(c, "failed to ban user", err)
+			return
+		}
+
+		disconnected := hub.DisconnectUser(userID, errors.CodeForbidden, "your account has been suspended")
+
+		recordAction(c, auditLog, audit.ActionAdminUserBan, audit.TargetUser, userID, gin.H{
+			"reason":                   req.Reason,
+			"connections_disconnected": disconnected,
+		})
+
+		c.JSON(http.StatusOK, BanUserResponse{
+			User:                    newUserAdminResponse(user),
+			ConnectionsDisconnected: disconnected,
+		})
+	}
+}
+
+// UnbanUser godoc
+// @Summary Unban a user (admin)
+// @Description Admin-only endpoint to lift a user's ban
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param request body ModerationRequest false "Reason for the audit log"
+// @Success 200 {object} UserAdminResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/users/{id}/ban [delete]
+// @Security AdminKeyAuth
+func UnbanUser(userRepo *users.Repository, auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req ModerationRequest
+		if !bindOptionalJSON(c, &req) {
+			return
+		}
+
+		user, err := userRepo.FindByID(c.Request.Context(), userID)
+		if err != nil {
+			errors.NotFound(c, "user")
+			return
+		}
+
+		if !user.IsBanned() {
+			errors.Conflict(c, "user is not banned")
+			return
+		}
+
+		user, err = userRepo.Unban(c.Request.Context(), userID)
+		if err != nil {
+			errors.InternalError(c, "failed to unban user", err)
+			return
+		}
+
+		recordAction(c, auditLog, audit.ActionAdminUserUnban, audit.TargetUser, userID, gin.H{
+			"reason": req.Reason,
+		})
+
+		c.JSON(http.StatusOK, newUserAdminResponse(user))
+	}
+}
+
+// GetStats godoc
+// @Summary Connection stats (admin)
+// @Description Admin-only endpoint to view websocket connection counts of the serving instance
+// @Tags admin
+// @Produce json
+// @Success 200 {object} StatsResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Router /api/v1/admin/stats [get]
+// @Security AdminKeyAuth
+func GetStats(hub ConnectionHub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, StatsResponse{Connections: hub.Stats()})
+	}
+}
+
+// GetUsageStats godoc
+// @Summary AI usage and cost (admin)
+// @Description Admin-only endpoint breaking down AI generations and tokens per provider and model, with the estimated cost of generations made with platform keys at list prices. Models without a known price count as free and have pricing_known set to false.
+// @Tags admin
+// @Produce json
+// @Param days query int false "Days to look back (max 365)" default(30)
+// @Success 200 {object} stats.UsageStats
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/stats/usage [get]
+// @Security AdminKeyAuth
+func GetUsageStats(statsService *stats.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		days := 30
+		if raw, ok := c.GetQuery("days"); ok {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 || parsed > 365 {
+				errors.BadRequest(c, "days must be between 1 and 365", nil)
+				return
+			}
+			days = parsed
+		}
+
+		since := time.Now().AddDate(0, 0, -days)
+
+		usage, err := statsService.Usage(c.Request.Context(), since)
+		if err != nil {
+			errors.InternalError(c, "failed to retrieve usage stats", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, usage)
+	}
+}
+
+// DrainServer godoc
+// @Summary Drain the instance (admin)
+// @Description Admin-only endpoint to prepare the serving instance for a deploy. It stops accepting WebSocket connections, sends every client a reconnect_hint, waits up to DRAIN_WINDOW for them to reconnect elsewhere and then shuts down. Same as sending the process SIGUSR1.
+// @Tags admin
+// @Produce json
+// @Success 202 {object} MessageResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse "Instance is already draining"
+// @Router /api/v1/admin/drain [post]
+// @Security AdminKeyAuth
+func DrainServer(drainer Drainer, auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !drainer.RequestDrain() {
+			errors.Conflict(c, "server is already draining")
+			return
+		}
+
+		hostname, _ := os.Hostname() //nolint:errcheck // only identifies the instance in the audit log
+
+		recordAction(c, auditLog, audit.ActionAdminServerDrain, audit.TargetInstance, hostname, nil)
+
+		c.JSON(http.StatusAccepted, MessageResponse{Message: "server is draining"})
+	}
+}
+
+// ListDeadLetters godoc
+// @Summary List failed background jobs (admin)
+// @Description Admin-only endpoint to read background jobs that failed permanently or ran out of attempts, most recently failed first. Dead letters are kept for 30 days
+// @Tags admin
+// @Produce json
+// @Param kind query string false "Filter by job kind (e.g. webhooks.event)"
+// @Param limit query int false "Max results (default 50, max 200)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} DeadLettersResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/jobs/dead-letters [get]
+// @Security AdminKeyAuth
+func ListDeadLetters(queue *jobs.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 50, 200)
+
+		letters, total, err := queue.ListDeadLetters(c.Request.Context(), c.Query("kind"), params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list dead letters", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, DeadLettersResponse{
+			DeadLetters: letters,
+			Pagination:  pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// RetryDeadLetter godoc
+// @Summary Retry a failed background job (admin)
+// @Description Admin-only endpoint to put a dead letter back on the job queue with fresh attempts, e.g. after the outage that failed it is over
+// @Tags admin
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 202 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/jobs/dead-letters/{id}/retry [post]
+// @Security AdminKeyAuth
+func RetryDeadLetter(queue *jobs.Queue, auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := queue.RetryDeadLetter(c.Request.Context(), jobID); err != nil {
+			if stderrors.Is(err, jobs.ErrDeadLetterMissing) {
+				errors.NotFound(c, "dead letter")
+				return
+			}
+
+			errors.InternalError(c, "failed to retry job", err)
+			return
+		}
+
+		recordAction(c, auditLog, audit.ActionAdminJobRetry, audit.TargetJob, jobID, nil)
+
+		c.JSON(http.StatusAccepted, MessageResponse{Message: "job queued"})
+	}
+}
+
+// ListFaults godoc
+// @Summary List injected faults (admin)
+// @Description Admin-only endpoint to read the faults currently injected into Redis and Postgres. Only registered when FAULT_INJECTION is enabled, which production refuses
+// @Tags admin
+// @Produce json
+// @Success 200 {object} FaultsResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Router /api/v1/admin/faults [get]
+// @Security AdminKeyAuth
+func ListFaults(injector FaultInjector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		injected := injector.Faults()
+
+		response := FaultsResponse{Faults: make([]FaultResponse, 0, len(injected))}
+		for _, target := range faults.Targets {
+			if fault, ok := injected[target]; ok {
+				response.Faults = append(response.Faults, toFaultResponse(target, fault))
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// SetFault godoc
+// @Summary Inject a fault (admin)
+// @Description Admin-only endpoint to add latency to, fail or drop the connection of a share of this instance's calls to Redis or Postgres, replacing the target's previous fault. Used to check the fallbacks work: Redis errors make the session buffer write to Postgres directly, dropped Redis connections make it hold writes in memory, and paste lock checks fail open. Only registered when FAULT_INJECTION is enabled, which production refuses
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param target path string true "redis or postgres"
+// @Param request body FaultRequest true "Fault to inject"
+// @Success 200 {object} FaultResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Router /api/v1/admin/faults/{target} [put]
+// @Security AdminKeyAuth
+func SetFault(injector FaultInjector, auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		target := c.Param("target")
+
+		var req FaultRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		fault := faults.Fault{
+			Latency:   time.Duration(req.LatencyMs) * time.Millisecond,
+			ErrorRate: req.ErrorRate,
+			DropRate:  req.DropRate,
+			KeyPrefix: req.KeyPrefix,
+		}
+
+		if err := injector.Set(target, fault); err != nil {
+			errors.BadRequest(c, err.Error(), nil)
+			return
+		}
+
+		hostname, _ := os.Hostname() //nolint:errcheck // only identifies the instance in the audit log
+
+		recordAction(c, auditLog, audit.ActionAdminFaultSet, audit.TargetInstance, hostname, gin.H{
+			"target":     target,
+			"latency_ms": req.LatencyMs,
+			"error_rate": req.ErrorRate,
+			"drop_rate":  req.DropRate,
+			"key_prefix": req.KeyPrefix,
+		})
+
+		c.JSON(http.StatusOK, toFaultResponse(target, fault))
+	}
+}
+
+// ResetFaults godoc
+// @Summary Stop injecting faults (admin)
+// @Description Admin-only endpoint to stop injecting faults into Redis and Postgres. Only registered when FAULT_INJECTION is enabled, which production refuses
+// @Tags admin
+// @Produce json
+// @Success 200 {object} MessageResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Router /api/v1/admin/faults [delete]
+// @Security AdminKeyAuth
+func ResetFaults(injector FaultInjector, auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		injector.Reset()
+
+		hostname, _ := os.Hostname() //nolint:errcheck // only identifies the instance in the audit log
+
+		recordAction(c, auditLog, audit.ActionAdminFaultsReset, audit.TargetInstance, hostname, nil)
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "faults cleared"})
+	}
+}
+
+func toFaultResponse(target string, fault faults.Fault) FaultResponse {
+	return FaultResponse{
+		Target:    target,
+		LatencyMs: int(fault.Latency / time.Millisecond),
+		ErrorRate: fault.ErrorRate,
+		DropRate:  fault.DropRate,
+		KeyPrefix: fault.KeyPrefix,
+	}
+}
+
+// ListAuditLog godoc
+// @Summary List audit log (admin)
+// @Description Admin-only endpoint to read the global audit log, newest first
+// @Tags admin
+// @Produce json
+// @Param actor_id query string false "Filter by acting user ID"
+// @Param session_id query string false "Filter by session ID"
+// @Param action query string false "Filter by action (e.g. admin.user.ban)"
+// @Param target_type query string false "Filter by target type (session, participant, invite_token, strudel, user)"
+// @Param target_id query string false "Filter by target ID"
+// @Param limit query int false "Max results (default 50, max 200)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} AuditLogResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/audit [get]
+// @Security AdminKeyAuth
+func ListAuditLog(auditLog *audit.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 50, 200)
+
+		filter := audit.ListFilter{
+			ActorID:    c.Query("actor_id"),
+			Action:     c.Query("action"),
+			TargetType: c.Query("target_type"),
+			TargetID:   c.Query("target_id"),
+			SessionID:  c.Query("session_id"),
+		}
+
+		entries, total, err := auditLog.List(c.Request.Context(), filter, params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list audit log", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, AuditLogResponse{
+			Entries:    entries,
+			Pagination: pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// ListModerationQueue godoc
+// @Summary List moderation queue
+// @Description Admin-only endpoint to read flagged and shadow-hidden content, newest first
+// @Tags admin
+// @Produce json
+// @Param status query string false "Filter by status (pending, approved, removed)" default(pending)
+// @Param content_type query string false "Filter by content type (chat_message, strudel)"
+// @Param limit query int false "Max results (default 50, max 200)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} ModerationQueueResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/moderation [get]
+// @Security AdminKeyAuth
+func ListModerationQueue(moderator *moderation.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 50, 200)
+
+		status := c.DefaultQuery("status", moderation.StatusPending)
+		if status == "all" {
+			status = ""
+		}
+
+		items, total, err := moderator.List(c.Request.Context(), status, c.Query("content_type"), params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list moderation queue", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, ModerationQueueResponse{
+			Items:      items,
+			Pagination: pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// ResolveModerationItem godoc
+// @Summary Resolve moderation item
+// @Description Admin-only endpoints to close a pending moderation item. Approving a hidden strudel publishes it, removing a strudel unpublishes it. Chat messages are only marked as reviewed
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Moderation item ID"
+// @Param request body ModerationRequest false "Reason for the audit log"
+// @Success 200 {object} moderation.QueueItem
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/moderation/{id}/approve [post]
+// @Router /api/v1/admin/moderation/{id}/remove [post]
+// @Security AdminKeyAuth
+func ResolveModerationItem(moderator *moderation.Service, strudelRepo *strudels.Repository, auditLog *audit.Service, status string) gin.HandlerFunc {
+	action := audit.ActionAdminModerationApprove
+	if status == moderation.StatusRemoved {
+		action = audit.ActionAdminModerationRemove
+	}
+
+	return func(c *gin.Context) {
+		itemID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req ModerationRequest
+		if !bindOptionalJSON(c, &req) {
+			return
+		}
+
+		reviewerID, _ := auth.GetUserID(c) //nolint:errcheck // set by AdminAuthMiddleware
+
+		item, err := moderator.Resolve(c.Request.Context(), itemID, reviewerID, status)
+		if err != nil {
+			if stderrors.Is(err, moderation.ErrItemNotFound) {
+				errors.NotFound(c, "moderation item")
+				return
+			}
+
+			errors.InternalError(c, "failed to resolve moderation item", err)
+			return
+		}
+
+		// hidden strudels were kept private, approving publishes them
+		if item.ContentType == moderation.ContentStrudel && item.ContentID != nil &&
+			(status == moderation.StatusRemoved || item.Action == moderation.ActionHide) {
+			publish := status == moderation.StatusApproved
+
+			_, err := strudelRepo.AdminSetPublic(c.Request.Context(), *item.ContentID, publish)
+			if err != nil && !stderrors.Is(err, strudels.ErrStrudelNotFound) {
+				errors.InternalError(c, "failed to update strudel visibility", err)
+				return
+			}
+
+			strudelRepo.IndexAsync(c.Request.Context(), *item.ContentID)
+		}
+
+		recordAction(c, auditLog, action, audit.TargetModeration, itemID, gin.H{
+			"reason":       req.Reason,
+			"content_type": item.ContentType,
+			"content_id":   item.ContentID,
+			"author_id":    item.AuthorID,
+		})
+
+		c.JSON(http.StatusOK, item)
+	}
+}
+
+// writes an admin action to the audit log
+func recordAction(c *gin.Context, auditLog *audit.Service, action, targetType, targetID string, details any) {
+	adminID, _ := auth.GetUserID(c) //nolint:errcheck // set by AdminAuthMiddleware
+
+	auditLog.Record(c.Request.Context(), adminEvent(adminID, action, targetType, targetID, details))
+}
+
+// actions on a session are tied to it so the host sees them in the
+// session's log too
+func adminEvent(adminID, action, targetType, targetID string, details any) audit.Event {
+	event := audit.Event{
+		ActorID:    adminID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+	}
+
+	if targetType == audit.TargetSession {
+		event.SessionID = targetID
+	}
+
+	return event
+}
+
+// binds a JSON body that may be omitted. writes the error response and
+// returns false if a body was sent but is invalid.
+func bindOptionalJSON(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil && !stderrors.Is(err, io.EOF) {
+		errors.ValidationError(c, err)
+		return false
+	}
+
+	return true
+}
+
+func newUserAdminResponse(user *users.User) UserAdminResponse {
+	return UserAdminResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Tier:      user.Tier,
+		IsAdmin:   user.IsAdmin,
+		BannedAt:  user.BannedAt,
+		BanReason: user.BanReason,
+	}
+}
+
+func parsePaginationParams(c *gin.Context) (limit, offset int) {
+	if l, ok := c.GetQuery("limit"); ok {
+		if parsedLimit, err := strconv.Atoi(l); err == nil {
+			limit = parsedLimit
+		}
+	}
+	if o, ok := c.GetQuery("offset"); ok {
+		if parsedOffset, err := strconv.Atoi(o); err == nil {
+			offset = parsedOffset
+		}
+	}
+	return limit, offset
+}
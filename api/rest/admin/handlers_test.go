@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/faults"
+)
+
+func TestAdminEvent(t *testing.T) {
+	// session actions show up in the host's session log too
+	event := adminEvent("admin-1", audit.ActionAdminSessionEnd, audit.TargetSession, "session-1", nil)
+	assert.Equal(t, "admin-1", event.ActorID)
+	assert.Equal(t, "session-1", event.TargetID)
+	assert.Equal(t, "session-1", event.SessionID)
+
+	event = adminEvent("admin-1", audit.ActionAdminUserBan, audit.TargetUser, "user-1", map[string]string{"reason": "spam"})
+	assert.Equal(t, "user-1", event.TargetID)
+	assert.Empty(t, event.SessionID)
+	assert.Equal(t, map[string]string{"reason": "spam"}, event.Details)
+}
+
+func TestListFaults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	injector := faults.New()
+	require.NoError(t, injector.Set(faults.TargetPostgres, faults.Fault{Latency: 250 * time.Millisecond}))
+	require.NoError(t, injector.Set(faults.TargetRedis, faults.Fault{ErrorRate: 0.5, KeyPrefix: "session:"}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/faults", nil)
+
+	ListFaults(injector)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response FaultsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []FaultResponse{
+		{Target: faults.TargetRedis, ErrorRate: 0.5, KeyPrefix: "session:"},
+		{Target: faults.TargetPostgres, LatencyMs: 250},
+	}, response.Faults)
+}
+
+func TestSetFaultRejectsInvalidFaults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for name, tc := range map[string]struct {
+		target string
+		body   string
+	}{
+		"unknown target": {"memcached", `{"error_rate": 0.5}`},
+		"rate above 1":   {faults.TargetRedis, `{"error_rate": 2}`},
+		"rates above 1":  {faults.TargetRedis, `{"error_rate": 0.5, "drop_rate": 0.6}`},
+		"malformed body": {faults.TargetRedis, `{"error_rate": "half"}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			injector := faults.New()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/admin/faults/"+tc.target, strings.NewReader(tc.body))
+			c.Params = gin.Params{{Key: "target", Value: tc.target}}
+
+			SetFault(injector, nil)(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Empty(t, injector.Faults())
+		})
+	}
+}
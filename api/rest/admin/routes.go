@@ -1,15 +1,70 @@
 package admin
 
 import (
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/algopatterns/users"
+	"codeberg.org/algopatterns/server/internal/audit"
 	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/moderation"
+	"codeberg.org/algopatterns/server/internal/pasteappeals"
+	"codeberg.org/algopatterns/server/internal/stats"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(router *gin.RouterGroup, strudelRepo *strudels.Repository) {
+// pasteLocks may be nil when paste detection is unavailable, faultInjector
+// when fault injection is disabled
+func RegisterRoutes(
+	router *gin.RouterGroup,
+	strudelRepo *strudels.Repository,
+	userRepo *users.Repository,
+	sessionRepo sessions.Repository,
+	hub ConnectionHub,
+	pasteLocks PasteLockInspector,
+	auditLog *audit.Service,
+	events EventPublisher,
+	moderator *moderation.Service,
+	drainer Drainer,
+	statsService *stats.Service,
+	appeals *pasteappeals.Service,
+	queue *jobs.Queue,
+	faultInjector FaultInjector,
+) {
 	admin := router.Group("/admin")
-	admin.Use(auth.AdminAuthMiddleware())
+	admin.Use(auth.AdminAuthMiddleware(userRepo))
+
+	admin.GET("/stats", GetStats(hub))
+	admin.GET("/stats/usage", GetUsageStats(statsService))
+	admin.GET("/audit", ListAuditLog(auditLog))
+	admin.POST("/drain", DrainServer(drainer, auditLog))
+
+	admin.GET("/jobs/dead-letters", ListDeadLetters(queue))
+	admin.POST("/jobs/dead-letters/:id/retry", RetryDeadLetter(queue, auditLog))
+
+	if faultInjector != nil {
+		admin.GET("/faults", ListFaults(faultInjector))
+		admin.PUT("/faults/:target", SetFault(faultInjector, auditLog))
+		admin.DELETE("/faults", ResetFaults(faultInjector, auditLog))
+	}
+
+	admin.GET("/sessions", ListSessions(sessionRepo))
+	admin.POST("/sessions/:id/end", EndSession(sessionRepo, hub, auditLog, events))
+	admin.GET("/sessions/:id/paste-lock", GetPasteLock(pasteLocks))
+	admin.DELETE("/sessions/:id/paste-lock", RemovePasteLock(pasteLocks, hub, auditLog))
+	admin.GET("/paste-lock-appeals", ListPasteLockAppeals(appeals))
+	admin.POST("/paste-lock-appeals/:id/approve", ResolvePasteLockAppeal(appeals, pasteLocks, hub, auditLog, pasteappeals.StatusApproved))
+	admin.POST("/paste-lock-appeals/:id/reject", ResolvePasteLockAppeal(appeals, pasteLocks, hub, auditLog, pasteappeals.StatusRejected))
 
 	admin.GET("/strudels/:id", GetStrudel(strudelRepo))
-	admin.PUT("/strudels/:id/use-in-training", SetUseInTraining(strudelRepo))
+	admin.DELETE("/strudels/:id", DeleteStrudel(strudelRepo, auditLog))
+	admin.PUT("/strudels/:id/use-in-training", SetUseInTraining(strudelRepo, auditLog))
+	admin.POST("/strudels/:id/unpublish", UnpublishStrudel(strudelRepo, auditLog))
+
+	admin.GET("/moderation", ListModerationQueue(moderator))
+	admin.POST("/moderation/:id/approve", ResolveModerationItem(moderator, strudelRepo, auditLog, moderation.StatusApproved))
+	admin.POST("/moderation/:id/remove", ResolveModerationItem(moderator, strudelRepo, auditLog, moderation.StatusRemoved))
+
+	admin.POST("/users/:id/ban", BanUser(userRepo, hub, auditLog))
+	admin.DELETE("/users/:id/ban", UnbanUser(userRepo, auditLog))
 }
@@ -1,5 +1,66 @@
 package admin
 
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/ccsignals"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/faults"
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/moderation"
+	"codeberg.org/algopatterns/server/internal/pasteappeals"
+	ws "codeberg.org/algopatterns/server/internal/websocket"
+)
+
+// reasons sent to clients when an admin acts on a session or user
+const (
+	reasonEndedByAdmin    = "ended_by_admin"
+	reasonUnlockedByAdmin = "removed_by_moderator"
+	reasonAppealApproved  = "appeal_approved"
+)
+
+// number of paste detection decisions returned per session
+const pasteDecisionsLimit = 50
+
+// live connections managed by the websocket hub
+type ConnectionHub interface {
+	EndSession(sessionID string, reason string)
+	DisconnectUser(userID string, code errors.Code, message string) int
+	BroadcastToSession(sessionID string, msg *ws.Message, excludeClientID string)
+	Stats() ws.HubStats
+}
+
+// drains this instance's connections before it shuts down (implemented by the
+// server)
+type Drainer interface {
+	RequestDrain() bool
+}
+
+// paste lock state and decisions (implemented by ccsignals.Detector)
+type PasteLockInspector interface {
+	GetLock(ctx context.Context, sessionID string) (*ccsignals.LockState, error)
+	RecentDecisions(ctx context.Context, sessionID string, limit int) ([]ccsignals.Decision, error)
+	RemoveLock(ctx context.Context, sessionID string) error
+}
+
+// faults injected into redis and postgres outside production (implemented by
+// faults.Injector)
+type FaultInjector interface {
+	Faults() map[string]faults.Fault
+	Set(target string, fault faults.Fault) error
+	Reset()
+}
+
+// queues webhook events in the transaction of the write they report (implemented by webhooks.Service)
+type EventPublisher interface {
+	PublishTx(ctx context.Context, tx pgx.Tx, userID, event string, data any) error
+}
+
 type SetUseInTrainingRequest struct {
 	UseInTraining bool `json:"use_in_training"`
 }
@@ -14,3 +75,101 @@ type StrudelAdminResponse struct {
 	Description   string   `json:"description,omitempty"`
 	Tags          []string `json:"tags,omitempty"`
 }
+
+type SessionAdminResponse struct {
+	ID             string     `json:"id"`
+	HostUserID     string     `json:"host_user_id"`
+	Title          string     `json:"title"`
+	IsActive       bool       `json:"is_active"`
+	IsDiscoverable bool       `json:"is_discoverable"`
+	CreatedAt      time.Time  `json:"created_at"`
+	EndedAt        *time.Time `json:"ended_at,omitempty"`
+	LastActivity   time.Time  `json:"last_activity"`
+}
+
+type SessionsListResponse struct {
+	Sessions   []SessionAdminResponse `json:"sessions"`
+	Pagination pagination.Meta        `json:"pagination"`
+}
+
+// reason is shown in the audit log only
+type ModerationRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type BanUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type UserAdminResponse struct {
+	ID        string     `json:"id"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	Tier      string     `json:"tier"`
+	IsAdmin   bool       `json:"is_admin"`
+	BannedAt  *time.Time `json:"banned_at,omitempty"`
+	BanReason *string    `json:"ban_reason,omitempty"`
+}
+
+type BanUserResponse struct {
+	User                    UserAdminResponse `json:"user"`
+	ConnectionsDisconnected int               `json:"connections_disconnected"`
+}
+
+type StatsResponse struct {
+	// connections held by the instance that served the request
+	Connections ws.HubStats `json:"connections"`
+}
+
+type PasteLockResponse struct {
+	SessionID      string               `json:"session_id"`
+	Locked         bool                 `json:"locked"`
+	LockedAt       *time.Time           `json:"locked_at,omitempty"`
+	ExpiresIn      int                  `json:"expires_in_seconds,omitempty"`
+	BaselineLength int                  `json:"baseline_length,omitempty"`
+	Decisions      []ccsignals.Decision `json:"decisions"`
+}
+
+type AuditLogResponse struct {
+	Entries    []audit.Entry   `json:"entries"`
+	Pagination pagination.Meta `json:"pagination"`
+}
+
+type PasteLockAppealsResponse struct {
+	Appeals    []*pasteappeals.Appeal `json:"appeals"`
+	Pagination pagination.Meta        `json:"pagination"`
+}
+
+type ModerationQueueResponse struct {
+	Items      []moderation.QueueItem `json:"items"`
+	Pagination pagination.Meta        `json:"pagination"`
+}
+
+type DeadLettersResponse struct {
+	DeadLetters []*jobs.DeadLetter `json:"dead_letters"`
+	Pagination  pagination.Meta    `json:"pagination"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// what happens to a share of the calls made to a target
+type FaultRequest struct {
+	LatencyMs int     `json:"latency_ms"`           // added to every call
+	ErrorRate float64 `json:"error_rate"`           // 0 to 1, calls failing with an error
+	DropRate  float64 `json:"drop_rate"`            // 0 to 1, calls failing as if the connection dropped
+	KeyPrefix string  `json:"key_prefix,omitempty"` // redis only: limits the fault to keys starting with this
+}
+
+type FaultResponse struct {
+	Target    string  `json:"target"`
+	LatencyMs int     `json:"latency_ms"`
+	ErrorRate float64 `json:"error_rate"`
+	DropRate  float64 `json:"drop_rate"`
+	KeyPrefix string  `json:"key_prefix,omitempty"`
+}
+
+type FaultsResponse struct {
+	Faults []FaultResponse `json:"faults"`
+}
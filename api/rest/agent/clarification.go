@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	agentcore "codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/buffer"
+)
+
+// where a conversation's clarifying questions are kept: the saved strudel,
+// or the session for drafts. empty when there is neither
+func clarificationScope(strudelID, sessionID string) string {
+	switch {
+	case strudelID != "":
+		return fmt.Sprintf("strudel:%s", strudelID)
+	case sessionID != "":
+		return fmt.Sprintf("session:%s", sessionID)
+	default:
+		return ""
+	}
+}
+
+// the clarifying questions the conversation is waiting on, if any
+func pendingClarification(ctx context.Context, sessionBuffer *buffer.SessionBuffer, scope string) *agentcore.Clarification {
+	if scope == "" || sessionBuffer == nil {
+		return nil
+	}
+
+	cached, err := sessionBuffer.GetClarification(ctx, scope)
+	if err != nil {
+		log.Printf("failed to load pending clarification for %s: %v", scope, err)
+		return nil
+	}
+
+	if cached == nil {
+		return nil
+	}
+
+	return &agentcore.Clarification{
+		Query:            cached.Query,
+		ConcreteRequests: cached.ConcreteRequests,
+		Questions:        cached.Questions,
+	}
+}
+
+// keeps newly asked clarifying questions, or forgets the answered ones
+func updateClarification(ctx context.Context, sessionBuffer *buffer.SessionBuffer, scope string, answered bool, asked *agentcore.Clarification) {
+	if scope == "" || sessionBuffer == nil {
+		return
+	}
+
+	var err error
+	switch {
+	case asked != nil:
+		err = sessionBuffer.SetClarification(ctx, scope, &buffer.CachedClarification{
+			Query:            asked.Query,
+			ConcreteRequests: asked.ConcreteRequests,
+			Questions:        asked.Questions,
+		})
+	case answered:
+		err = sessionBuffer.ClearClarification(ctx, scope)
+	}
+
+	if err != nil {
+		log.Printf("failed to update pending clarification for %s: %v", scope, err)
+	}
+}
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -8,28 +9,43 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
-	"codeberg.org/algopatterns/server/algopatterns/users"
 	agentcore "codeberg.org/algopatterns/server/internal/agent"
 	"codeberg.org/algopatterns/server/internal/attribution"
 	"codeberg.org/algopatterns/server/internal/auth"
 	"codeberg.org/algopatterns/server/internal/buffer"
+	"codeberg.org/algopatterns/server/internal/config"
 	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/lessons"
 	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/quota"
 )
 
 const (
-	defaultAnthropicModel = "claude-sonnet-4-20250514"
-	defaultOpenAIModel    = "gpt-4o"
-	defaultMaxTokens      = 4096
-	defaultTemperature    = 0.7
-	maxHistoryMessages    = 50
+	maxHistoryMessages = 50
 
 	// NOTE: free AI tier is currently disabled. users must provide their own API key (BYOK).
 	// to re-enable free tier, set this to true.
 	freeTierEnabled = false
 )
 
+// ListProvidersHandler godoc
+// @Summary List AI providers
+// @Description List the providers and models that can be used with your own API key
+// @Tags agent
+// @Produce json
+// @Success 200 {object} ProvidersResponse
+// @Router /api/v1/agent/providers [get]
+func ListProvidersHandler(providers *llm.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, ProvidersResponse{
+			DefaultProvider: string(providers.DefaultProvider()),
+			Providers:       providers.Providers(),
+		})
+	}
+}
+
 // GenerateHandler godoc
 // @Summary Generate code with AI
 // @Description Generate Strudel code using AI with optional BYOK support
@@ -38,10 +54,12 @@ const (
 // @Produce json
 // @Param request body GenerateRequest true "Generation request"
 // @Success 200 {object} GenerateResponse
-// @Failure 400 {object} errors.ErrorResponse
+// @Failure 400 {object} errors.ErrorResponse "invalid request, or the provider rejected your API key"
+// @Failure 429 {object} errors.ErrorResponse "quota reached, or your API key is rate limited"
 // @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse "AI generation is switched off or the provider is unavailable"
 // @Router /api/v1/agent/generate [post]
-func GenerateHandler(agentClient *agentcore.Agent, _ llm.LLM, strudelRepo *strudels.Repository, userRepo *users.Repository, attrService *attribution.Service, sessionBuffer *buffer.SessionBuffer) gin.HandlerFunc {
+func GenerateHandler(agentClient *agentcore.Agent, providers *llm.Registry, strudelRepo *strudels.Repository, sessionRepo sessions.Repository, quotas *quota.Service, attrService *attribution.Service, sessionBuffer *buffer.SessionBuffer, lessonService *lessons.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req GenerateRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -49,42 +67,33 @@ func GenerateHandler(agentClient *agentcore.Agent, _ llm.LLM, strudelRepo *strud
 			return
 		}
 
+		if !canUseAgent(c, sessionRepo, req.SessionID) {
+			errors.Forbidden(c, "you don't have permission to use the AI assistant in this session")
+			return
+		}
+
 		// check if BYOK is required (free tier disabled)
 		isBYOK := req.ProviderAPIKey != ""
 		if !freeTierEnabled && !isBYOK {
 			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "byok_required",
+				"error":   errors.CodeBYOKRequired,
 				"message": "AI features require your own API key. Add your API key in Settings to use AI assistance.",
 			})
 			return
 		}
 
-		// check rate limits (skip for BYOK users)
-		if !isBYOK {
-			userID, isAuthenticated := auth.GetUserID(c)
-			var rateLimitResult *users.RateLimitResult
-			var err error
-
-			if isAuthenticated {
-				rateLimitResult, err = userRepo.CheckUserRateLimit(c.Request.Context(), userID, false)
-			} else if req.SessionID != "" {
-				rateLimitResult, err = userRepo.CheckSessionRateLimit(c.Request.Context(), req.SessionID)
-			}
-
-			if err != nil {
-				log.Printf("rate limit check failed: %v", err)
-				// fail open - allow request if rate limit check fails
-			} else if rateLimitResult != nil && !rateLimitResult.Allowed {
-				c.JSON(http.StatusTooManyRequests, gin.H{
-					"error":     "rate_limit_exceeded",
-					"message":   fmt.Sprintf("Daily AI limit reached (%d/%d). Try again tomorrow or use your own API key.", rateLimitResult.Current, rateLimitResult.Limit),
-					"limit":     rateLimitResult.Limit,
-					"current":   rateLimitResult.Current,
-					"remaining": rateLimitResult.Remaining,
-				})
-				return
-			}
+		// count the generation against the caller's quota (given back if it fails)
+		subject := quotaSubject(c, req.SessionID, isBYOK)
+		quotaResult, allowed := reserveQuota(c, quotas, subject)
+		if !allowed {
+			return
 		}
+		generated := false
+		defer func() {
+			if !generated && quotaResult != nil {
+				quotas.Release(context.WithoutCancel(c.Request.Context()), subject)
+			}
+		}()
 
 		// paste lock validation (if session_id provided)
 		// decoupled from WebSocket - just check Redis directly
@@ -137,30 +146,14 @@ func GenerateHandler(agentClient *agentcore.Agent, _ llm.LLM, strudelRepo *strud
 			}
 		}
 
+		var conversationSummary string
 		var conversationHistory []agentcore.Message
 
 		// for saved strudels: load history from DB if not provided
 		// for drafts: use history from request
 		if req.StrudelID != "" && len(req.ConversationHistory) == 0 {
-			// load from database
-			messages, err := strudelRepo.GetStrudelMessages(c.Request.Context(), req.StrudelID, maxHistoryMessages)
-			if err != nil {
-				// log error but continue with empty history (non-fatal)
-				conversationHistory = []agentcore.Message{}
-			} else {
-				conversationHistory = make([]agentcore.Message, 0, len(messages))
-				for _, msg := range messages {
-					// only include messages with content
-					if msg.Content != "" {
-						conversationHistory = append(conversationHistory, agentcore.Message{
-							Role:    msg.Role,
-							Content: msg.Content,
-						})
-					}
-				}
-			}
+			conversationSummary, conversationHistory = strudelHistory(c.Request.Context(), agentClient, strudelRepo, req.StrudelID)
 		} else {
-			// use history from request (drafts)
 			conversationHistory = make([]agentcore.Message, 0, len(req.ConversationHistory))
 			for _, msg := range req.ConversationHistory {
 				if msg.Content != "" {
@@ -170,20 +163,31 @@ func GenerateHandler(agentClient *agentcore.Agent, _ llm.LLM, strudelRepo *strud
 					})
 				}
 			}
+			conversationSummary, conversationHistory = draftHistory(c.Request.Context(), agentClient, sessionBuffer, req.SessionID, conversationHistory)
 		}
 
+		// a follow-up may answer the clarifying questions asked last turn
+		scope := clarificationScope(req.StrudelID, req.SessionID)
+		lesson, lessonCheck := lessonContext(c.Request.Context(), lessonService, req.SessionID, req.EditorState)
+
 		// build generate request
 		generateReq := agentcore.GenerateRequest{
 			UserQuery:           req.UserQuery,
 			EditorState:         req.EditorState,
 			ConversationHistory: conversationHistory,
+			ConversationSummary: conversationSummary,
+			Clarification:       pendingClarification(c.Request.Context(), sessionBuffer, scope),
+			Lesson:              lesson,
+		}
+		if quotaResult != nil {
+			generateReq.Tier = quotaResult.Tier
 		}
 
 		// create custom generator if BYOK key provided
 		if req.ProviderAPIKey != "" {
-			customGenerator, err := createBYOKGenerator(req.Provider, req.ProviderAPIKey)
+			customGenerator, err := providers.Generator(llm.Provider(req.Provider), req.Model, req.ProviderAPIKey)
 			if err != nil {
-				errors.BadRequest(c, "invalid provider configuration", err)
+				errors.BadRequest(c, "invalid provider or model", err)
 				return
 			}
 			generateReq.CustomGenerator = customGenerator
@@ -198,10 +202,22 @@ func GenerateHandler(agentClient *agentcore.Agent, _ llm.LLM, strudelRepo *strud
 		// generate response
 		resp, err := agentClient.Generate(c.Request.Context(), generateReq)
 		if err != nil {
-			errors.InternalError(c, "failed to generate code", err)
+			generationFailed(c, err, isBYOK)
 			return
 		}
 
+		generated = true
+		updateClarification(c.Request.Context(), sessionBuffer, scope, generateReq.Clarification != nil, resp.Clarification)
+
+		if quotaResult != nil {
+			quotas.Record(c.Request.Context(), subject, quota.Usage{
+				Provider:     req.Provider,
+				Model:        resp.Model,
+				InputTokens:  resp.InputTokens,
+				OutputTokens: resp.OutputTokens,
+			})
+		}
+
 		// record attributions if examples were used (runs async)
 		if attrService != nil && len(resp.Examples) > 0 {
 			userID, _ := c.Get("user_id")
@@ -299,42 +315,140 @@ func GenerateHandler(agentClient *agentcore.Agent, _ llm.LLM, strudelRepo *strud
 			StrudelReferences:   strudelRefs,
 			DocReferences:       docRefs,
 			Model:               resp.Model,
+			Cached:              resp.Cached,
+			Degraded:            resp.Degraded,
+			RepairIterations:    resp.RepairIterations,
+			Lesson:              lessonCheck,
+			RateLimit:           newRateLimitInfo(quotaResult),
 		})
 	}
 }
 
-// creates a byok generator based on provider
-func createBYOKGenerator(provider, apiKey string) (llm.TextGenerator, error) {
-	switch provider {
-	case "anthropic", "":
-		return llm.NewAnthropicTransformer(llm.AnthropicConfig{
-			APIKey:      apiKey,
-			Model:       defaultAnthropicModel,
-			MaxTokens:   defaultMaxTokens,
-			Temperature: defaultTemperature,
-		}), nil
-	case "openai":
-		return llm.NewOpenAIGenerator(llm.OpenAIConfig{
-			APIKey: apiKey,
-			Model:  defaultOpenAIModel,
-		}), nil
+// turns generation requests away while the ai_generation feature is off
+func requireAIGeneration() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.FeatureEnabled(config.FeatureAIGeneration) {
+			errors.ServiceUnavailable(c, "AI generation is temporarily disabled")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// checks the use_agent permission of a signed-in participant of the session.
+// anonymous callers can't be matched to a participant, so they aren't limited here
+func canUseAgent(c *gin.Context, sessionRepo sessions.Repository, sessionID string) bool {
+	userID, signedIn := auth.GetUserID(c)
+	if sessionID == "" || !signedIn {
+		return true
+	}
+
+	participant, err := sessionRepo.GetAuthenticatedParticipant(c.Request.Context(), sessionID, userID)
+	if err != nil {
+		return true
+	}
+
+	return participant.Permissions().Has(sessions.PermUseAgent)
+}
+
+// who a generation is counted against: the signed-in user, otherwise the session
+func quotaSubject(c *gin.Context, sessionID string, isBYOK bool) quota.Subject {
+	userID, _ := auth.GetUserID(c) //nolint:errcheck // anonymous requests are counted per session
+	return quota.Subject{
+		UserID:    userID,
+		SessionID: sessionID,
+		BYOK:      isBYOK,
+	}
+}
+
+// writes the response for a failed generation. provider failures get a
+// message the caller can act on; problems with a BYOK key are the caller's
+// to fix, problems with the server's keys are logged as internal errors
+func generationFailed(c *gin.Context, err error, isBYOK bool) {
+	message := llm.UserMessage(err, isBYOK)
+	kind := llm.KindOf(err)
+
+	switch {
+	case message == "":
+		errors.InternalError(c, "failed to generate code", err)
+	case isBYOK && kind == llm.ErrorRateLimited:
+		errors.TooManyRequests(c, message)
+	case isBYOK && (kind == llm.ErrorAuth || kind == llm.ErrorInvalidRequest):
+		errors.BadRequest(c, message, nil)
+	case kind == llm.ErrorAuth || kind == llm.ErrorInvalidRequest:
+		errors.InternalError(c, message, err)
 	default:
-		return nil, fmt.Errorf("unsupported provider: %s", provider)
+		errors.ServiceUnavailable(c, message)
+	}
+}
+
+// reserves a generation from the caller's quota, writing a 429 response and
+// returning false when the daily or monthly limit is reached
+func reserveQuota(c *gin.Context, quotas *quota.Service, subject quota.Subject) (*quota.Result, bool) {
+	if quotas == nil {
+		return nil, true
+	}
+
+	result, err := quotas.Reserve(c.Request.Context(), subject)
+	if err != nil {
+		log.Printf("quota check failed: %v", err)
+		// fail open - allow request if quota check fails
+		return nil, true
+	}
+
+	if result != nil && !result.Allowed {
+		message := fmt.Sprintf("Monthly AI limit reached (%d/%d). Try again next month or use your own API key.", result.Monthly.Used, result.Monthly.Limit)
+		switch {
+		case result.Daily.Remaining == 0:
+			message = fmt.Sprintf("Daily AI limit reached (%d/%d). Try again tomorrow or use your own API key.", result.Daily.Used, result.Daily.Limit)
+		case result.Monthly.Remaining != 0:
+			message = fmt.Sprintf("Monthly AI spending limit reached ($%.2f/$%.2f). Try again next month or use your own API key.", result.Spend.UsedUSD, result.Spend.LimitUSD)
+		}
+
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":      errors.CodeRateLimitExceeded,
+			"message":    message,
+			"rate_limit": newRateLimitInfo(result),
+		})
+		return result, false
+	}
+
+	return result, true
+}
+
+// maps a quota result to the response type (nil when the request isn't metered)
+func newRateLimitInfo(result *quota.Result) *RateLimitInfo {
+	if result == nil {
+		return nil
+	}
+
+	return &RateLimitInfo{
+		Tier:      result.Tier,
+		Limit:     result.Daily.Limit,
+		Current:   result.Daily.Used,
+		Remaining: result.Daily.Remaining,
+		ResetAt:   result.Daily.ResetAt,
+		Monthly:   result.Monthly,
+		Spend:     result.Spend,
 	}
 }
 
 // GenerateStreamHandler godoc
 // @Summary Stream generate code with AI (SSE)
-// @Description Stream Strudel code generation using Server-Sent Events. BYOK required.
+// @Description Stream Strudel code generation using Server-Sent Events. Emits "refs" (retrieval counts and references), "chunk" (generated tokens) and a terminal "done" event with references and rate-limit info.
 // @Tags agent
 // @Accept json
 // @Produce text/event-stream
 // @Param request body GenerateRequest true "Generation request"
-// @Success 200 {object} agentcore.StreamEvent
+// @Success 200 {object} StreamEvent
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 403 {object} errors.ErrorResponse
+// @Failure 429 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse "AI generation is switched off"
 // @Router /api/v1/agent/generate/stream [post]
-func GenerateStreamHandler(agentClient *agentcore.Agent, strudelRepo *strudels.Repository, sessionBuffer *buffer.SessionBuffer) gin.HandlerFunc {
+func GenerateStreamHandler(agentClient *agentcore.Agent, providers *llm.Registry, strudelRepo *strudels.Repository, sessionRepo sessions.Repository, quotas *quota.Service, sessionBuffer *buffer.SessionBuffer, lessonService *lessons.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req GenerateRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -342,15 +456,35 @@ func GenerateStreamHandler(agentClient *agentcore.Agent, strudelRepo *strudels.R
 			return
 		}
 
-		// streaming requires BYOK
-		if req.ProviderAPIKey == "" {
+		if !canUseAgent(c, sessionRepo, req.SessionID) {
+			errors.Forbidden(c, "you don't have permission to use the AI assistant in this session")
+			return
+		}
+
+		// check if BYOK is required (free tier disabled)
+		isBYOK := req.ProviderAPIKey != ""
+		if !freeTierEnabled && !isBYOK {
 			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "byok_required",
-				"message": "Streaming requires your own API key. Add your API key in Settings.",
+				"error":   errors.CodeBYOKRequired,
+				"message": "AI features require your own API key. Add your API key in Settings to use AI assistance.",
 			})
 			return
 		}
 
+		// count the generation against the caller's quota (given back if it fails)
+		subject := quotaSubject(c, req.SessionID, isBYOK)
+		quotaResult, allowed := reserveQuota(c, quotas, subject)
+		if !allowed {
+			return
+		}
+		generated := false
+		defer func() {
+			if !generated && quotaResult != nil {
+				quotas.Release(context.WithoutCancel(c.Request.Context()), subject)
+			}
+		}()
+		rateLimit := newRateLimitInfo(quotaResult)
+
 		// paste lock validation
 		if req.SessionID != "" && sessionBuffer != nil {
 			ctx := c.Request.Context()
@@ -387,25 +521,37 @@ func GenerateStreamHandler(agentClient *agentcore.Agent, strudelRepo *strudels.R
 				})
 			}
 		}
+		conversationSummary, conversationHistory := draftHistory(c.Request.Context(), agentClient, sessionBuffer, req.SessionID, conversationHistory)
 
-		// create BYOK generator
-		customGenerator, err := createBYOKGenerator(req.Provider, req.ProviderAPIKey)
-		if err != nil {
-			errors.BadRequest(c, "invalid provider configuration", err)
-			return
-		}
+		scope := clarificationScope(req.StrudelID, req.SessionID)
+		lesson, lessonCheck := lessonContext(c.Request.Context(), lessonService, req.SessionID, req.EditorState)
 
 		generateReq := agentcore.GenerateRequest{
 			UserQuery:           req.UserQuery,
 			EditorState:         req.EditorState,
 			ConversationHistory: conversationHistory,
-			CustomGenerator:     customGenerator,
+			ConversationSummary: conversationSummary,
+			Clarification:       pendingClarification(c.Request.Context(), sessionBuffer, scope),
+			Lesson:              lesson,
+		}
+		if quotaResult != nil {
+			generateReq.Tier = quotaResult.Tier
 		}
 
-		// enable RAG caching
-		if req.SessionID != "" && sessionBuffer != nil {
-			generateReq.SessionID = req.SessionID
-			generateReq.RAGCache = sessionBuffer
+		// create custom generator if BYOK key provided
+		if isBYOK {
+			customGenerator, err := providers.Generator(llm.Provider(req.Provider), req.Model, req.ProviderAPIKey)
+			if err != nil {
+				errors.BadRequest(c, "invalid provider or model", err)
+				return
+			}
+			generateReq.CustomGenerator = customGenerator
+
+			// enable RAG caching
+			if req.SessionID != "" && sessionBuffer != nil {
+				generateReq.SessionID = req.SessionID
+				generateReq.RAGCache = sessionBuffer
+			}
 		}
 
 		// set SSE headers
@@ -415,8 +561,27 @@ func GenerateStreamHandler(agentClient *agentcore.Agent, strudelRepo *strudels.R
 		c.Header("X-Accel-Buffering", "no") // disable nginx buffering
 
 		// stream response
-		err = agentClient.GenerateStream(c.Request.Context(), generateReq, func(event agentcore.StreamEvent) error {
-			eventJSON, err := json.Marshal(event)
+		err := agentClient.GenerateStream(c.Request.Context(), generateReq, func(event agentcore.StreamEvent) error {
+			streamEvent := StreamEvent{StreamEvent: event}
+
+			// terminal event carries the caller's remaining quota
+			if event.Type == "done" {
+				streamEvent.RateLimit = rateLimit
+				streamEvent.Lesson = lessonCheck
+
+				generated = true
+				updateClarification(c.Request.Context(), sessionBuffer, scope, generateReq.Clarification != nil, nil)
+				if quotaResult != nil {
+					quotas.Record(c.Request.Context(), subject, quota.Usage{
+						Provider:     req.Provider,
+						Model:        event.Model,
+						InputTokens:  event.InputTokens,
+						OutputTokens: event.OutputTokens,
+					})
+				}
+			}
+
+			eventJSON, err := json.Marshal(streamEvent)
 			if err != nil {
 				return err
 			}
@@ -431,10 +596,16 @@ func GenerateStreamHandler(agentClient *agentcore.Agent, strudelRepo *strudels.R
 		})
 
 		if err != nil {
+			// provider failures get a message the caller can act on
+			message := llm.UserMessage(err, isBYOK)
+			if message == "" {
+				message = err.Error()
+			}
+
 			// send error event (best-effort, client may have disconnected)
 			errorEvent := agentcore.StreamEvent{
 				Type:  "error",
-				Error: err.Error(),
+				Error: message,
 			}
 			eventJSON, _ := json.Marshal(errorEvent)         //nolint:errcheck
 			fmt.Fprintf(c.Writer, "data: %s\n\n", eventJSON) //nolint:errcheck
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	agentcore "codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/buffer"
+)
+
+// loads a saved strudel's conversation summary and the messages after it,
+// folding messages that no longer fit the token budget into the summary
+func strudelHistory(ctx context.Context, agentClient *agentcore.Agent, strudelRepo *strudels.Repository, strudelID string) (string, []agentcore.Message) {
+	var summary string
+	var through time.Time
+
+	stored, err := strudelRepo.GetConversationSummary(ctx, strudelID)
+	if err != nil {
+		log.Printf("failed to load conversation summary for strudel %s: %v", strudelID, err)
+	} else if stored != nil {
+		summary, through = stored.Text, stored.Through
+	}
+
+	messages, err := strudelRepo.GetStrudelMessages(ctx, strudelID, maxHistoryMessages)
+	if err != nil {
+		// non-fatal, continue with the summary alone
+		return summary, []agentcore.Message{}
+	}
+
+	// messages come newest first
+	history := make([]agentcore.Message, 0, len(messages))
+	sentAt := make([]time.Time, 0, len(messages))
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if msg.Content == "" || !msg.CreatedAt.After(through) {
+			continue
+		}
+
+		history = append(history, agentcore.Message{
+			Role:    msg.Role,
+			Content: msg.Content,
+		})
+		sentAt = append(sentAt, msg.CreatedAt)
+	}
+
+	compacted := agentClient.CompactHistory(ctx, summary, history)
+	if compacted.Folded > 0 {
+		if err := strudelRepo.SetConversationSummary(ctx, strudelID, compacted.Summary, sentAt[compacted.Folded-1]); err != nil {
+			log.Printf("failed to save conversation summary for strudel %s: %v", strudelID, err)
+		}
+	}
+
+	return compacted.Summary, compacted.Recent
+}
+
+// compacts a draft's conversation. drafts send their whole history, so with
+// a session the summary is kept in redis and reused while the history still
+// starts with the messages it covers
+func draftHistory(ctx context.Context, agentClient *agentcore.Agent, sessionBuffer *buffer.SessionBuffer, sessionID string, history []agentcore.Message) (string, []agentcore.Message) {
+	canStore := sessionID != "" && sessionBuffer != nil
+
+	var summary string
+	folded := 0
+
+	if canStore {
+		stored, err := sessionBuffer.GetConversationSummary(ctx, sessionID)
+		if err != nil {
+			log.Printf("failed to load conversation summary for session %s: %v", sessionID, err)
+		} else if stored != nil && stored.Folded <= len(history) && historyDigest(history[:stored.Folded]) == stored.Digest {
+			summary, folded = stored.Summary, stored.Folded
+		}
+	}
+
+	compacted := agentClient.CompactHistory(ctx, summary, history[folded:])
+	if canStore && compacted.Folded > 0 {
+		total := folded + compacted.Folded
+		err := sessionBuffer.SetConversationSummary(ctx, sessionID, &buffer.CachedConversationSummary{
+			Summary: compacted.Summary,
+			Folded:  total,
+			Digest:  historyDigest(history[:total]),
+		})
+		if err != nil {
+			log.Printf("failed to save conversation summary for session %s: %v", sessionID, err)
+		}
+	}
+
+	return compacted.Summary, compacted.Recent
+}
+
+// identifies a run of messages
+func historyDigest(history []agentcore.Message) string {
+	hash := sha256.New()
+	for _, msg := range history {
+		hash.Write([]byte(msg.Role))
+		hash.Write([]byte{0})
+		hash.Write([]byte(msg.Content))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	stderrors "errors"
+	"log"
+
+	agentcore "codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/lessons"
+)
+
+// checks the editor's code against the session's lesson, advancing it when
+// the step passes, and describes the step to teach toward. nil when the
+// session has no lesson in progress
+func lessonContext(ctx context.Context, lessonService *lessons.Service, sessionID, code string) (*agentcore.LessonContext, *lessons.CheckResult) {
+	if lessonService == nil || sessionID == "" {
+		return nil, nil
+	}
+
+	result, err := lessonService.Check(ctx, sessionID, code)
+	if err != nil {
+		if !stderrors.Is(err, lessons.ErrNoLesson) && !stderrors.Is(err, lessons.ErrCompleted) {
+			log.Printf("failed to check lesson progress for session %s: %v", sessionID, err)
+		}
+		return nil, nil
+	}
+
+	progress := result.Progress
+	if progress.Current == nil {
+		return nil, result
+	}
+
+	lesson, err := lessonService.Get(progress.LessonID)
+	if err != nil {
+		return nil, result
+	}
+
+	missing := result.Missing
+	if result.Advanced {
+		missing = progress.Current.Check(code).Missing
+	}
+
+	return &agentcore.LessonContext{
+		Lesson:      lesson.Title,
+		Step:        progress.Step + 1,
+		StepCount:   progress.StepCount,
+		StepTitle:   progress.Current.Title,
+		Explanation: progress.Current.Explanation,
+		Example:     progress.Current.Example,
+		Missing:     missing,
+	}, result
+}
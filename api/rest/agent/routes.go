@@ -3,18 +3,24 @@ package agent
 import (
 	"github.com/gin-gonic/gin"
 
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
-	"codeberg.org/algopatterns/server/algopatterns/users"
 	agentcore "codeberg.org/algopatterns/server/internal/agent"
 	"codeberg.org/algopatterns/server/internal/attribution"
+	"codeberg.org/algopatterns/server/internal/auth"
 	"codeberg.org/algopatterns/server/internal/buffer"
+	"codeberg.org/algopatterns/server/internal/lessons"
 	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/quota"
 )
 
-func RegisterRoutes(router *gin.RouterGroup, agentClient *agentcore.Agent, platformLLM llm.LLM, strudelRepo *strudels.Repository, userRepo *users.Repository, attrService *attribution.Service, sessionBuffer *buffer.SessionBuffer) {
+func RegisterRoutes(router *gin.RouterGroup, agentClient *agentcore.Agent, providers *llm.Registry, strudelRepo *strudels.Repository, sessionRepo sessions.Repository, quotas *quota.Service, attrService *attribution.Service, sessionBuffer *buffer.SessionBuffer, lessonService *lessons.Service) {
+	// optional auth so signed-in users are counted against their own quota
 	agentGroup := router.Group("/agent")
+	agentGroup.Use(auth.OptionalAuthMiddleware())
 	{
-		agentGroup.POST("/generate", GenerateHandler(agentClient, platformLLM, strudelRepo, userRepo, attrService, sessionBuffer))
-		agentGroup.POST("/generate/stream", GenerateStreamHandler(agentClient, strudelRepo, sessionBuffer))
+		agentGroup.GET("/providers", ListProvidersHandler(providers))
+		agentGroup.POST("/generate", requireAIGeneration(), GenerateHandler(agentClient, providers, strudelRepo, sessionRepo, quotas, attrService, sessionBuffer, lessonService))
+		agentGroup.POST("/generate/stream", requireAIGeneration(), GenerateStreamHandler(agentClient, providers, strudelRepo, sessionRepo, quotas, sessionBuffer, lessonService))
 	}
 }
@@ -1,11 +1,21 @@
 package agent
 
+import (
+	"time"
+
+	agentcore "codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/lessons"
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/quota"
+)
+
 // request payload for AI code generation
 type GenerateRequest struct {
 	UserQuery           string    `json:"user_query" binding:"required"`
 	EditorState         string    `json:"editor_state"`
 	ConversationHistory []Message `json:"conversation_history"`
-	Provider            string    `json:"provider,omitempty"`         // "anthropic" or "openai"
+	Provider            string    `json:"provider,omitempty"`         // see GET /agent/providers, defaults to the configured provider
+	Model               string    `json:"model,omitempty"`            // must be allowed for the provider, defaults to its default model
 	ProviderAPIKey      string    `json:"provider_api_key,omitempty"` // BYOK key
 	StrudelID           string    `json:"strudel_id,omitempty"`       // optional: for persisting conversation
 	ForkedFromID        string    `json:"forked_from_id,omitempty"`   // optional: for blocking AI on restricted forks
@@ -35,13 +45,43 @@ type DocReference struct {
 
 // response payload for AI code generation
 type GenerateResponse struct {
-	Code                string             `json:"code,omitempty"`
-	IsActionable        bool               `json:"is_actionable"`
-	IsCodeResponse      bool               `json:"is_code_response"`
-	ClarifyingQuestions []string           `json:"clarifying_questions,omitempty"`
-	DocsRetrieved       int                `json:"docs_retrieved"`
-	ExamplesRetrieved   int                `json:"examples_retrieved"`
-	StrudelReferences   []StrudelReference `json:"strudel_references,omitempty"`
-	DocReferences       []DocReference     `json:"doc_references,omitempty"`
-	Model               string             `json:"model"`
+	Code                string               `json:"code,omitempty"`
+	IsActionable        bool                 `json:"is_actionable"`
+	IsCodeResponse      bool                 `json:"is_code_response"`
+	ClarifyingQuestions []string             `json:"clarifying_questions,omitempty"`
+	DocsRetrieved       int                  `json:"docs_retrieved"`
+	ExamplesRetrieved   int                  `json:"examples_retrieved"`
+	StrudelReferences   []StrudelReference   `json:"strudel_references,omitempty"`
+	DocReferences       []DocReference       `json:"doc_references,omitempty"`
+	Model               string               `json:"model"`
+	Cached              bool                 `json:"cached,omitempty"`            // served from the response cache
+	Degraded            bool                 `json:"degraded,omitempty"`          // generated by the fallback provider during an outage
+	RepairIterations    int                  `json:"repair_iterations,omitempty"` // times the code was sent back to the model to fix lint findings
+	Lesson              *lessons.CheckResult `json:"lesson,omitempty"`            // the editor's code checked against the session's lesson step
+	RateLimit           *RateLimitInfo       `json:"rate_limit,omitempty"`
+}
+
+// AI usage for the caller. the top-level counts are for today (UTC);
+// limit and remaining are -1 when not enforced
+type RateLimitInfo struct {
+	Tier      string            `json:"tier"` // "anonymous", "free", "payg" or "byok"
+	Limit     int               `json:"limit"`
+	Current   int               `json:"current"`
+	Remaining int               `json:"remaining"`
+	ResetAt   time.Time         `json:"reset_at"`
+	Monthly   quota.PeriodUsage `json:"monthly"`
+	Spend     quota.SpendUsage  `json:"spend"` // estimated model cost this month
+}
+
+// providers and models available with a user-provided API key
+type ProvidersResponse struct {
+	DefaultProvider string             `json:"default_provider"`
+	Providers       []llm.ProviderSpec `json:"providers"`
+}
+
+// server-sent event for streaming generation
+type StreamEvent struct {
+	agentcore.StreamEvent
+	RateLimit *RateLimitInfo       `json:"rate_limit,omitempty"` // sent with type="done"
+	Lesson    *lessons.CheckResult `json:"lesson,omitempty"`     // sent with type="done" during a lesson
 }
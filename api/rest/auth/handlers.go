@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"crypto/rand"
 	"encoding/base64"
+	stderrors "errors"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -11,16 +14,18 @@ import (
 	"codeberg.org/algopatterns/server/algopatterns/users"
 	"codeberg.org/algopatterns/server/internal/auth"
 	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/httpcache"
 	"codeberg.org/algopatterns/server/internal/logger"
 	"github.com/gin-gonic/gin"
+	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
 )
 
 // BeginAuthHandler godoc
 // @Summary Start OAuth authentication
-// @Description Begin OAuth authentication flow with specified provider (google, github, apple)
+// @Description Begin OAuth authentication flow with specified provider (google, github, apple, discord, gitlab)
 // @Tags auth
-// @Param provider path string true "OAuth provider" Enums(google, github, apple)
+// @Param provider path string true "OAuth provider" Enums(google, github, apple, discord, gitlab)
 // @Param redirect_url query string false "URL to redirect to after authentication"
 // @Success 302 {string} string "Redirect to OAuth provider"
 // @Failure 400 {object} errors.ErrorResponse
@@ -38,12 +43,16 @@ func BeginAuthHandler(_ *users.Repository) gin.HandlerFunc {
 		q.Add("provider", provider)
 
 		// encode redirect URL in OAuth state parameter (survives the OAuth redirect)
-		// validate to prevent open redirect attacks
-		if redirectURL := validateRedirectURL(c.Query("redirect_url")); redirectURL != "" {
-			state := base64.URLEncoding.EncodeToString([]byte(redirectURL))
-			q.Set("state", state)
+		// validate to prevent open redirect attacks. the random nonce keeps the
+		// state unguessable: gothic matches it against the flow's cookie, so a
+		// callback forged with another account's code is refused
+		state, err := newOAuthState(validateRedirectURL(c.Query("redirect_url")))
+		if err != nil {
+			errors.InternalError(c, "failed to start authentication", err)
+			return
 		}
 
+		q.Set("state", state)
 		c.Request.URL.RawQuery = q.Encode()
 
 		gothic.BeginAuthHandler(c.Writer, c.Request)
@@ -52,16 +61,18 @@ func BeginAuthHandler(_ *users.Repository) gin.HandlerFunc {
 
 // CallbackHandler godoc
 // @Summary OAuth callback
-// @Description OAuth provider callback. Redirects to original URL with token, or returns JSON if no redirect URL
+// @Description OAuth provider callback. Redirects to original URL with token and refresh_token, or returns JSON if no redirect URL.
+// @Description Signing in with a new provider links it to the existing account with the same verified email
 // @Tags auth
 // @Produce json
-// @Param provider path string true "OAuth provider" Enums(google, github, apple)
+// @Param provider path string true "OAuth provider" Enums(google, github, apple, discord, gitlab)
 // @Success 302 {string} string "Redirect to original URL with token"
 // @Success 200 {object} AuthResponse
 // @Failure 400 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/auth/{provider}/callback [get]
-func CallbackHandler(userRepo *users.Repository) gin.HandlerFunc {
+func CallbackHandler(userRepo *users.Repository, refreshTokens *auth.RefreshStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		provider := c.Param("provider")
 
@@ -85,6 +96,7 @@ func CallbackHandler(userRepo *users.Repository) gin.HandlerFunc {
 			gothUser.Email,
 			gothUser.Name,
 			gothUser.AvatarURL,
+			emailVerified(gothUser),
 		)
 
 		if err != nil {
@@ -92,18 +104,35 @@ func CallbackHandler(userRepo *users.Repository) gin.HandlerFunc {
 			return
 		}
 
+		if user.IsBanned() {
+			if redirectURL != "" {
+				handleAuthError(c, redirectURL, "account suspended", nil)
+				return
+			}
+
+			errors.Forbidden(c, "account suspended")
+			return
+		}
+
 		token, err := auth.GenerateJWT(user.ID, user.Email, user.IsAdmin)
 		if err != nil {
 			handleAuthError(c, redirectURL, "failed to generate token", err)
 			return
 		}
 
-		// redirect to original URL with token if available
+		refreshToken, err := refreshTokens.Issue(c.Request.Context(), user.ID)
+		if err != nil {
+			handleAuthError(c, redirectURL, "failed to generate token", err)
+			return
+		}
+
+		// redirect to original URL with tokens if available
 		if redirectURL != "" {
 			parsedURL, err := url.Parse(redirectURL)
 			if err == nil {
 				query := parsedURL.Query()
 				query.Set("token", token)
+				query.Set("refresh_token", refreshToken)
 				parsedURL.RawQuery = query.Encode()
 				c.Redirect(http.StatusTemporaryRedirect, parsedURL.String())
 				return
@@ -112,18 +141,35 @@ func CallbackHandler(userRepo *users.Repository) gin.HandlerFunc {
 
 		// fallback to JSON response if no redirect URL
 		c.JSON(http.StatusOK, AuthResponse{
-			User:  user,
-			Token: token,
+			User:         user,
+			Token:        token,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int(auth.AccessTokenTTL.Seconds()),
 		})
 	}
 }
 
+// random bytes in an OAuth state
+const oauthStateNonceBytes = 16
+
+// newOAuthState creates an OAuth state parameter: a random nonce, then the
+// redirect URL if any
+func newOAuthState(redirectURL string) (string, error) {
+	nonce := make([]byte, oauthStateNonceBytes)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(nonce) + "." + base64.URLEncoding.EncodeToString([]byte(redirectURL)), nil
+}
+
 // extractRedirectURL decodes the redirect URL from the OAuth state parameter
 func extractRedirectURL(state string) string {
-	if state == "" {
+	_, encoded, found := strings.Cut(state, ".")
+	if !found {
 		return ""
 	}
-	decoded, err := base64.URLEncoding.DecodeString(state)
+	decoded, err := base64.URLEncoding.DecodeString(encoded)
 	if err != nil {
 		return ""
 	}
@@ -151,7 +197,9 @@ func handleAuthError(c *gin.Context, redirectURL, message string, err error) {
 // @Description Get authenticated user's profile
 // @Tags auth
 // @Produce json
+// @Param If-None-Match header string false "ETag from an earlier response, answered with 304 while it is current"
 // @Success 200 {object} UserResponse
+// @Success 304 {string} string "Not modified"
 // @Failure 401 {object} errors.ErrorResponse
 // @Failure 404 {object} errors.ErrorResponse
 // @Router /api/v1/auth/me [get]
@@ -171,6 +219,11 @@ func GetCurrentUserHandler(userRepo *users.Repository) gin.HandlerFunc {
 			return
 		}
 
+		httpcache.Private(c)
+		if httpcache.NotModified(c, httpcache.ETag(user.UpdatedAt)) {
+			return
+		}
+
 		c.JSON(http.StatusOK, UserResponse{User: user})
 	}
 }
@@ -213,15 +266,99 @@ func UpdateProfileHandler(userRepo *users.Repository) gin.HandlerFunc {
 	}
 }
 
+// RefreshHandler godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token works once;
+// @Description reusing a rotated token revokes all of the user's refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh token"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/refresh [post]
+func RefreshHandler(userRepo *users.Repository, refreshTokens *auth.RefreshStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RefreshRequest
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		userID, refreshToken, err := refreshTokens.Rotate(c.Request.Context(), req.RefreshToken)
+		if err != nil {
+			if stderrors.Is(err, auth.ErrRefreshTokenReused) {
+				logger.Warn("refresh token reused, revoked all refresh tokens")
+			}
+
+			if stderrors.Is(err, auth.ErrInvalidRefreshToken) || stderrors.Is(err, auth.ErrRefreshTokenReused) {
+				errors.Unauthorized(c, err.Error())
+				return
+			}
+
+			errors.InternalError(c, "failed to refresh token", err)
+			return
+		}
+
+		user, err := userRepo.FindByID(c.Request.Context(), userID)
+		if err != nil {
+			errors.Unauthorized(c, "user not found")
+			return
+		}
+
+		if user.IsBanned() {
+			if err := refreshTokens.RevokeAll(c.Request.Context(), userID); err != nil {
+				logger.ErrorErr(err, "failed to revoke refresh tokens of banned user", "user_id", userID)
+			}
+
+			errors.Forbidden(c, "account suspended")
+			return
+		}
+
+		token, err := auth.GenerateJWT(user.ID, user.Email, user.IsAdmin)
+		if err != nil {
+			errors.InternalError(c, "failed to generate token", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, TokenResponse{
+			Token:        token,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int(auth.AccessTokenTTL.Seconds()),
+		})
+	}
+}
+
 // LogoutHandler godoc
 // @Summary Logout
-// @Description Clear authentication session
+// @Description Clear authentication session and revoke the given refresh token
 // @Tags auth
+// @Accept json
 // @Produce json
+// @Param request body LogoutRequest false "Refresh token to revoke"
 // @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
 // @Router /api/v1/auth/logout [post]
-func LogoutHandler() gin.HandlerFunc {
+func LogoutHandler(refreshTokens *auth.RefreshStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		var req LogoutRequest
+
+		// the body is optional for clients that only hold an access token
+		if err := c.ShouldBindJSON(&req); err != nil && !stderrors.Is(err, io.EOF) {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		if req.RefreshToken != "" {
+			if err := refreshTokens.Revoke(c.Request.Context(), req.RefreshToken); err != nil {
+				logger.ErrorErr(err, "failed to revoke refresh token")
+			}
+		}
+
 		if err := gothic.Logout(c.Writer, c.Request); err != nil {
 			logger.ErrorErr(err, "failed to logout user from gothic session")
 		}
@@ -230,11 +367,67 @@ func LogoutHandler() gin.HandlerFunc {
 	}
 }
 
+// LogoutAllHandler godoc
+// @Summary Logout everywhere
+// @Description Revoke all refresh tokens of the authenticated user. Access tokens stay valid until they expire
+// @Tags auth
+// @Produce json
+// @Success 200 {object} MessageResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/logout-all [post]
+// @Security BearerAuth
+func LogoutAllHandler(refreshTokens *auth.RefreshStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		if err := refreshTokens.RevokeAll(c.Request.Context(), userID); err != nil {
+			errors.InternalError(c, "failed to revoke sessions", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "logged out of all sessions"})
+	}
+}
+
 func isValidProvider(provider string) bool {
-	validProviders := []string{"google", "github", "apple"}
+	validProviders := []string{"google", "github", "apple", "discord", "gitlab"}
 	return slices.Contains(validProviders, provider)
 }
 
+// reports whether the provider vouches for the user's email, which is
+// required before a login is linked to an existing account by email
+func emailVerified(user goth.User) bool {
+	switch user.Provider {
+	case "google":
+		return rawBool(user.RawData, "verified_email") || rawBool(user.RawData, "email_verified")
+	case "discord":
+		return rawBool(user.RawData, "verified")
+	case "github":
+		// only exposes verified addresses
+		return true
+	case "gitlab":
+		// the primary email of an account that was never confirmed isn't
+		return rawString(user.RawData, "confirmed_at") != ""
+	default:
+		return false
+	}
+}
+
+func rawBool(data map[string]any, key string) bool {
+	v, ok := data[key].(bool)
+	return ok && v
+}
+
+func rawString(data map[string]any, key string) string {
+	v, _ := data[key].(string)
+	return v
+}
+
 // validateRedirectURL ensures the redirect URL is safe (relative path or same host as BASE_URL)
 func validateRedirectURL(redirectURL string) string {
 	if redirectURL == "" {
@@ -270,3 +463,189 @@ func validateRedirectURL(redirectURL string) string {
 
 	return redirectURL
 }
+
+// StartDeviceAuthHandler godoc
+// @Summary Start device sign-in
+// @Description Start signing in a client without a browser, like the terminal app. The user approves the returned user code on the website while the client polls /auth/device/token
+// @Tags auth
+// @Produce json
+// @Success 200 {object} DeviceAuthorizationResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/device [post]
+func StartDeviceAuthHandler(deviceCodes *auth.DeviceStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorization, err := deviceCodes.Start(c.Request.Context())
+		if err != nil {
+			errors.InternalError(c, "failed to start device sign-in", err)
+			return
+		}
+
+		verificationURI := deviceVerificationURL()
+
+		c.JSON(http.StatusOK, DeviceAuthorizationResponse{
+			DeviceCode:              authorization.DeviceCode,
+			UserCode:                authorization.UserCode,
+			VerificationURI:         verificationURI,
+			VerificationURIComplete: verificationURI + "?code=" + url.QueryEscape(authorization.UserCode),
+			ExpiresIn:               int(auth.DeviceCodeTTL.Seconds()),
+			Interval:                int(auth.DevicePollInterval.Seconds()),
+		})
+	}
+}
+
+// ApproveDeviceAuthHandler godoc
+// @Summary Approve device sign-in
+// @Description Sign the client showing the user code in as the authenticated user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body DeviceDecisionRequest true "User code shown by the client"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/device/approve [post]
+// @Security BearerAuth
+func ApproveDeviceAuthHandler(deviceCodes *auth.DeviceStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		var req DeviceDecisionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		if err := deviceCodes.Approve(c.Request.Context(), req.UserCode, userID); err != nil {
+			handleDeviceDecisionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "device signed in"})
+	}
+}
+
+// DenyDeviceAuthHandler godoc
+// @Summary Deny device sign-in
+// @Description Refuse the sign-in of the client showing the user code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body DeviceDecisionRequest true "User code shown by the client"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/device/deny [post]
+// @Security BearerAuth
+func DenyDeviceAuthHandler(deviceCodes *auth.DeviceStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DeviceDecisionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		if err := deviceCodes.Deny(c.Request.Context(), req.UserCode); err != nil {
+			handleDeviceDecisionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "device sign-in denied"})
+	}
+}
+
+// DeviceTokenHandler godoc
+// @Summary Poll device sign-in
+// @Description Exchange the device code for tokens once the user approved it. Until then answers 400 with authorization_pending, or slow_down when polled more often than the interval; access_denied and expired_token end the sign-in
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body DeviceTokenRequest true "Device code"
+// @Success 200 {object} AuthResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/device/token [post]
+func DeviceTokenHandler(userRepo *users.Repository, refreshTokens *auth.RefreshStore, deviceCodes *auth.DeviceStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DeviceTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		userID, err := deviceCodes.Poll(c.Request.Context(), req.DeviceCode)
+		if err != nil {
+			switch {
+			case stderrors.Is(err, auth.ErrAuthorizationPending):
+				errors.DeviceAuthorization(c, errors.CodeAuthorizationPending, err.Error())
+			case stderrors.Is(err, auth.ErrSlowDown):
+				errors.DeviceAuthorization(c, errors.CodeSlowDown, err.Error())
+			case stderrors.Is(err, auth.ErrAccessDenied):
+				errors.DeviceAuthorization(c, errors.CodeAccessDenied, err.Error())
+			case stderrors.Is(err, auth.ErrDeviceCodeExpired):
+				errors.DeviceAuthorization(c, errors.CodeExpiredToken, err.Error())
+			default:
+				errors.InternalError(c, "failed to check device sign-in", err)
+			}
+			return
+		}
+
+		user, err := userRepo.FindByID(c.Request.Context(), userID)
+		if err != nil {
+			errors.Unauthorized(c, "user not found")
+			return
+		}
+
+		if user.IsBanned() {
+			errors.Forbidden(c, "account suspended")
+			return
+		}
+
+		token, err := auth.GenerateJWT(user.ID, user.Email, user.IsAdmin)
+		if err != nil {
+			errors.InternalError(c, "failed to generate token", err)
+			return
+		}
+
+		refreshToken, err := refreshTokens.Issue(c.Request.Context(), user.ID)
+		if err != nil {
+			errors.InternalError(c, "failed to generate token", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, AuthResponse{
+			User:         user,
+			Token:        token,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int(auth.AccessTokenTTL.Seconds()),
+		})
+	}
+}
+
+func handleDeviceDecisionError(c *gin.Context, err error) {
+	if stderrors.Is(err, auth.ErrInvalidUserCode) {
+		errors.NotFound(c, "user code")
+		return
+	}
+
+	errors.InternalError(c, "failed to update device sign-in", err)
+}
+
+// the page of the frontend where users enter the code shown by the client
+func deviceVerificationURL() string {
+	appURL := os.Getenv("APP_URL")
+	if appURL == "" {
+		appURL = os.Getenv("BASE_URL")
+	}
+
+	return strings.TrimRight(appURL, "/") + "/device"
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/markbates/goth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailVerified(t *testing.T) {
+	tests := []struct {
+		name string
+		user goth.User
+		want bool
+	}{
+		{"google verified_email", goth.User{Provider: "google", RawData: map[string]any{"verified_email": true}}, true},
+		{"google email_verified", goth.User{Provider: "google", RawData: map[string]any{"email_verified": true}}, true},
+		{"google unverified", goth.User{Provider: "google", RawData: map[string]any{"verified_email": false}}, false},
+		{"google without flag", goth.User{Provider: "google", RawData: map[string]any{}}, false},
+		{"discord verified", goth.User{Provider: "discord", RawData: map[string]any{"verified": true}}, true},
+		{"discord unverified", goth.User{Provider: "discord", RawData: map[string]any{"verified": false}}, false},
+		{"discord flag as string", goth.User{Provider: "discord", RawData: map[string]any{"verified": "true"}}, false},
+		{"github", goth.User{Provider: "github", RawData: map[string]any{}}, true},
+		{"gitlab confirmed", goth.User{Provider: "gitlab", RawData: map[string]any{"confirmed_at": "2024-05-01T10:00:00.000Z"}}, true},
+		{"gitlab unconfirmed", goth.User{Provider: "gitlab", RawData: map[string]any{"confirmed_at": nil}}, false},
+		{"gitlab without confirmed_at", goth.User{Provider: "gitlab", RawData: map[string]any{}}, false},
+		{"unknown provider", goth.User{Provider: "bitbucket", RawData: map[string]any{"verified": true}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, emailVerified(tt.user))
+		})
+	}
+}
+
+func TestOAuthState(t *testing.T) {
+	first, err := newOAuthState("https://algopatterns.cc/editor?id=1")
+	require.NoError(t, err)
+	second, err := newOAuthState("https://algopatterns.cc/editor?id=1")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second, "states must not be guessable from the redirect URL")
+	assert.Equal(t, "https://algopatterns.cc/editor?id=1", extractRedirectURL(first))
+
+	withoutRedirect, err := newOAuthState("")
+	require.NoError(t, err)
+	assert.Empty(t, extractRedirectURL(withoutRedirect))
+
+	assert.Empty(t, extractRedirectURL(""))
+	assert.Empty(t, extractRedirectURL("not a state"))
+}
@@ -6,12 +6,18 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(router *gin.RouterGroup, userRepo *users.Repository) {
+func RegisterRoutes(router *gin.RouterGroup, userRepo *users.Repository, refreshTokens *auth.RefreshStore, deviceCodes *auth.DeviceStore) {
 	authGroup := router.Group("/auth")
 	{
 		authGroup.GET("/:provider", BeginAuthHandler(userRepo))
-		authGroup.GET("/:provider/callback", CallbackHandler(userRepo))
-		authGroup.POST("/logout", LogoutHandler())
+		authGroup.GET("/:provider/callback", CallbackHandler(userRepo, refreshTokens))
+		authGroup.POST("/refresh", RefreshHandler(userRepo, refreshTokens))
+		authGroup.POST("/logout", LogoutHandler(refreshTokens))
+		authGroup.POST("/logout-all", auth.AuthMiddleware(), LogoutAllHandler(refreshTokens))
+		authGroup.POST("/device", StartDeviceAuthHandler(deviceCodes))
+		authGroup.POST("/device/token", DeviceTokenHandler(userRepo, refreshTokens, deviceCodes))
+		authGroup.POST("/device/approve", auth.AuthMiddleware(), ApproveDeviceAuthHandler(deviceCodes))
+		authGroup.POST("/device/deny", auth.AuthMiddleware(), DenyDeviceAuthHandler(deviceCodes))
 		authGroup.GET("/me", auth.AuthMiddleware(), GetCurrentUserHandler(userRepo))
 		authGroup.PUT("/me", auth.AuthMiddleware(), UpdateProfileHandler(userRepo))
 	}
@@ -4,8 +4,28 @@ import "codeberg.org/algopatterns/server/algopatterns/users"
 
 // AuthResponse returned after successful OAuth callback
 type AuthResponse struct {
-	User  *users.User `json:"user"`
-	Token string      `json:"token"`
+	User         *users.User `json:"user"`
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int         `json:"expires_in"` // access token lifetime in seconds
+}
+
+// RefreshRequest exchanges a refresh token for new tokens
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse returned after refreshing. the refresh token in the request
+// is no longer valid, clients must store the new one
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // access token lifetime in seconds
+}
+
+// LogoutRequest optionally names the refresh token to revoke
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // UserResponse wraps user data
@@ -23,3 +43,25 @@ type UpdateProfileRequest struct {
 	Name      string `json:"name" binding:"required,max=100"`
 	AvatarURL string `json:"avatar_url" binding:"max=500"`
 }
+
+// DeviceAuthorizationResponse starts a device sign-in. the client shows the
+// user code and verification URI, then polls the token endpoint with the
+// device code every interval seconds
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"` // device code lifetime in seconds
+	Interval                int    `json:"interval"`   // minimum seconds between polls
+}
+
+// DeviceTokenRequest polls for the tokens of a device sign-in
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"device_code" binding:"required"`
+}
+
+// DeviceDecisionRequest approves or denies the device sign-in of a user code
+type DeviceDecisionRequest struct {
+	UserCode string `json:"user_code" binding:"required,max=16"`
+}
@@ -0,0 +1,121 @@
+package billing
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/billing"
+	"codeberg.org/algopatterns/server/internal/errors"
+)
+
+// CheckoutHandler godoc
+// @Summary Start subscription checkout
+// @Description Create a Stripe Checkout session for the pay-as-you-go tier. Redirect the user to the returned URL; the tier changes once Stripe confirms the subscription.
+// @Tags billing
+// @Produce json
+// @Success 200 {object} SessionURLResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse
+// @Router /api/v1/billing/checkout [post]
+// @Security BearerAuth
+func CheckoutHandler(billingService *billing.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		url, err := billingService.CreateCheckout(c.Request.Context(), userID)
+		if err != nil {
+			handleBillingError(c, err, "failed to create checkout session")
+			return
+		}
+
+		c.JSON(http.StatusOK, SessionURLResponse{URL: url})
+	}
+}
+
+// PortalHandler godoc
+// @Summary Open billing portal
+// @Description Create a Stripe Billing Portal session to manage or cancel the subscription and view invoices
+// @Tags billing
+// @Produce json
+// @Success 200 {object} SessionURLResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse
+// @Router /api/v1/billing/portal [post]
+// @Security BearerAuth
+func PortalHandler(billingService *billing.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		url, err := billingService.CreatePortal(c.Request.Context(), userID)
+		if err != nil {
+			handleBillingError(c, err, "failed to create billing portal session")
+			return
+		}
+
+		c.JSON(http.StatusOK, SessionURLResponse{URL: url})
+	}
+}
+
+// EventsHandler godoc
+// @Summary Stripe webhook
+// @Description Receives Stripe subscription events (signed with the Stripe-Signature header) and updates user tiers
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse
+// @Router /api/v1/billing/events [post]
+func EventsHandler(billingService *billing.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxEventSize))
+		if err != nil {
+			errors.BadRequest(c, "failed to read request body", err)
+			return
+		}
+
+		err = billingService.HandleWebhook(c.Request.Context(), payload, c.GetHeader(billing.SignatureHeader))
+		if err != nil {
+			handleBillingError(c, err, "failed to process billing event")
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "event received"})
+	}
+}
+
+// maps billing service errors to responses
+func handleBillingError(c *gin.Context, err error, message string) {
+	switch {
+	case stderrors.Is(err, billing.ErrNotConfigured):
+		errors.ServiceUnavailable(c, "billing is not available")
+	case stderrors.Is(err, billing.ErrAlreadySubscribed):
+		errors.Conflict(c, err.Error())
+	case stderrors.Is(err, billing.ErrNoCustomer):
+		errors.NotFound(c, "billing account")
+	case stderrors.Is(err, billing.ErrUserNotFound):
+		errors.NotFound(c, "user")
+	case stderrors.Is(err, billing.ErrInvalidSignature),
+		stderrors.Is(err, billing.ErrMalformedEvent):
+		errors.BadRequest(c, err.Error(), nil)
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
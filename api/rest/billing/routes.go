@@ -0,0 +1,19 @@
+package billing
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/billing"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, billingService *billing.Service) {
+	billingGroup := router.Group("/billing")
+	{
+		billingGroup.POST("/checkout", auth.AuthMiddleware(), CheckoutHandler(billingService))
+		billingGroup.POST("/portal", auth.AuthMiddleware(), PortalHandler(billingService))
+
+		// called by Stripe, authenticated by the webhook signature
+		billingGroup.POST("/events", EventsHandler(billingService))
+	}
+}
@@ -0,0 +1,13 @@
+package billing
+
+// largest webhook body accepted (Stripe events are a few KB)
+const maxEventSize = 256 * 1024
+
+// a hosted Stripe page to redirect the user to
+type SessionURLResponse struct {
+	URL string `json:"url"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,56 @@
+package cards
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/cards"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/httpcache"
+)
+
+// GetCardHandler godoc
+// @Summary Get strudel preview card
+// @Description Get the OpenGraph and Twitter card metadata of a public strudel, for the frontend to render into the strudel's page. The image shows the title, author and start of the code; it is generated in the background whenever these change, so image_url is null until the image of the current version is ready, and twitter_card is summary instead of summary_large_image.
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Param If-None-Match header string false "ETag from an earlier response, answered with 304 while it is current"
+// @Success 200 {object} cards.Card
+// @Success 304 {string} string "Not modified"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/public/strudels/{id}/card [get]
+func GetCardHandler(cardService *cards.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		card, err := cardService.Get(c.Request.Context(), strudelID)
+		if err != nil {
+			if stderrors.Is(err, cards.ErrStrudelNotFound) {
+				errors.NotFound(c, "strudel")
+				return
+			}
+			errors.InternalError(c, "failed to get card", err)
+			return
+		}
+
+		imageURL := ""
+		if card.ImageURL != nil {
+			imageURL = *card.ImageURL
+		}
+
+		httpcache.Public(c, httpcache.PublicMaxAge)
+		if httpcache.NotModified(c, httpcache.ETag(card.UpdatedAt, imageURL)) {
+			return
+		}
+
+		c.JSON(http.StatusOK, card)
+	}
+}
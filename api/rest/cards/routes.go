@@ -0,0 +1,12 @@
+package cards
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/cards"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, cardService *cards.Service) {
+	// preview cards of shared strudels, fetched by the frontend's server rendering
+	router.GET("/public/strudels/:id/card", GetCardHandler(cardService))
+}
@@ -1,16 +1,28 @@
 package collaboration
 
 import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 
 	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/audit"
 	"codeberg.org/algopatterns/server/internal/auth"
 	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/httpcache"
+	"codeberg.org/algopatterns/server/internal/keyset"
 	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/mailer"
+	"codeberg.org/algopatterns/server/internal/notifications"
+	"codeberg.org/algopatterns/server/internal/transcript"
+	"codeberg.org/algopatterns/server/internal/webhooks"
 )
 
 // CreateSessionHandler godoc
@@ -26,7 +38,7 @@ import (
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/sessions [post]
 // @Security BearerAuth
-func CreateSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+func CreateSessionHandler(sessionRepo sessions.Repository, events EventPublisher) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := auth.GetUserID(c)
 		if !exists {
@@ -41,12 +53,22 @@ func CreateSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
+		var published func(tx pgx.Tx, session *sessions.Session) error
+		if events != nil {
+			published = func(tx pgx.Tx, session *sessions.Session) error {
+				return events.PublishTx(c.Request.Context(), tx, userID, webhooks.EventSessionCreated, webhooks.SessionEventData{
+					SessionID: session.ID,
+					Title:     session.Title,
+				})
+			}
+		}
+
 		// create session
 		session, err := sessionRepo.CreateSession(c.Request.Context(), &sessions.CreateSessionRequest{
 			HostUserID: userID,
 			Title:      req.Title,
 			Code:       req.Code,
-		})
+		}, published)
 		if err != nil {
 			errors.InternalError(c, "failed to create session", err)
 			return
@@ -80,7 +102,9 @@ func CreateSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 // @Tags sessions
 // @Produce json
 // @Param id path string true "Session ID (UUID)"
+// @Param If-None-Match header string false "ETag from an earlier response, answered with 304 while it is current"
 // @Success 200 {object} SessionResponse
+// @Success 304 {string} string "Not modified"
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
 // @Failure 404 {object} errors.ErrorResponse
@@ -116,13 +140,15 @@ func GetSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 				UserID:      p.UserID,
 				DisplayName: &p.DisplayName,
 				Role:        p.Role,
+				Permissions: p.Permissions().Names(),
+				Muted:       p.MutedAt != nil,
 				Status:      p.Status,
 				JoinedAt:    p.JoinedAt,
 				LeftAt:      p.LeftAt,
 			})
 		}
 
-		c.JSON(http.StatusOK, SessionResponse{
+		body, err := json.Marshal(SessionResponse{
 			ID:             session.ID,
 			HostUserID:     session.HostUserID,
 			Title:          session.Title,
@@ -134,6 +160,19 @@ func GetSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			LastActivity:   session.LastActivity,
 			Participants:   participantResponses,
 		})
+		if err != nil {
+			errors.InternalError(c, "failed to encode session", err)
+			return
+		}
+
+		// participants, settings and buffered code change without touching
+		// last_activity, so the tag covers the whole response
+		httpcache.Private(c)
+		if httpcache.NotModified(c, httpcache.ETag(session.LastActivity, string(body))) {
+			return
+		}
+
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
 	}
 }
 
@@ -143,7 +182,12 @@ func GetSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 // @Tags sessions
 // @Produce json
 // @Param active_only query boolean false "Only return active sessions" default(false)
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param cursor query string false "Cursor from the previous response's next_cursor or prev_cursor"
+// @Param sort query string false "last_activity, created_at or title, with a leading - for descending" default(-last_activity)
+// @Param fields query []string false "Session fields to return (comma-separated), id is always included"
 // @Success 200 {object} SessionsListResponse
+// @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/sessions [get]
@@ -157,6 +201,11 @@ func ListUserSessionsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
+		page, fields, ok := parsePage(c, sessionPages, SessionResponse{})
+		if !ok {
+			return
+		}
+
 		activeOnly := c.DefaultQuery("active_only", "false") == "true"
 
 		userSessions, err := sessionRepo.GetUserSessions(c.Request.Context(), userID, activeOnly)
@@ -165,6 +214,15 @@ func ListUserSessionsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
+		// a user's sessions are few enough to page through in memory
+		key := sessions.SessionKey(page.Sort.Field)
+		userSessions, edges := keyset.Cut(page, keyset.Select(page, userSessions, key), key)
+
+		meta, ok := pageMeta(c, page, edges)
+		if !ok {
+			return
+		}
+
 		// convert to response format
 		responses := make([]SessionResponse, 0, len(userSessions))
 
@@ -182,7 +240,7 @@ func ListUserSessionsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			})
 		}
 
-		c.JSON(http.StatusOK, SessionsListResponse{Sessions: responses})
+		respondPage(c, SessionsListResponse{Sessions: responses, Pagination: meta}, "sessions", fields)
 	}
 }
 
@@ -221,9 +279,8 @@ func UpdateSessionCodeHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
-		// check if authenticated user is host or co-author
 		participant, err := sessionRepo.GetAuthenticatedParticipant(c.Request.Context(), sessionID, userID)
-		if err != nil || (participant.Role != "host" && participant.Role != "co-author") {
+		if err != nil || !participant.Permissions().Has(sessions.PermEditCode) {
 			errors.Forbidden(c, "you don't have permission to edit this session")
 			return
 		}
@@ -262,7 +319,7 @@ func UpdateSessionCodeHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/sessions/{id} [delete]
 // @Security BearerAuth
-func EndSessionHandler(sessionRepo sessions.Repository, sessionEnder SessionEnder) gin.HandlerFunc {
+func EndSessionHandler(sessionRepo sessions.Repository, sessionEnder SessionEnder, events EventPublisher, auditLog AuditLog) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		sessionID, ok := errors.ValidatePathUUID(c, "id")
 		if !ok {
@@ -287,7 +344,18 @@ func EndSessionHandler(sessionRepo sessions.Repository, sessionEnder SessionEnde
 			return
 		}
 
-		if err := sessionRepo.EndSession(c.Request.Context(), sessionID); err != nil {
+		var published func(tx pgx.Tx) error
+		if events != nil {
+			published = func(tx pgx.Tx) error {
+				return events.PublishTx(c.Request.Context(), tx, userID, webhooks.EventSessionEnded, webhooks.SessionEventData{
+					SessionID: sessionID,
+					Title:     session.Title,
+					Reason:    "ended_by_host",
+				})
+			}
+		}
+
+		if err := sessionRepo.EndSession(c.Request.Context(), sessionID, published); err != nil {
 			errors.InternalError(c, "failed to end session", err)
 			return
 		}
@@ -297,13 +365,22 @@ func EndSessionHandler(sessionRepo sessions.Repository, sessionEnder SessionEnde
 			sessionEnder.EndSession(sessionID, "session ended by host")
 		}
 
+		recordEvent(c, auditLog, audit.Event{
+			ActorID:    userID,
+			Action:     audit.ActionSessionEnd,
+			TargetType: audit.TargetSession,
+			TargetID:   sessionID,
+			SessionID:  sessionID,
+			Details:    gin.H{"reason": "ended_by_host", "title": session.Title},
+		})
+
 		c.JSON(http.StatusOK, MessageResponse{Message: "session ended successfully"})
 	}
 }
 
 // CreateInviteTokenHandler godoc
 // @Summary Create invite token
-// @Description Generate an invite link for joining the session (host only)
+// @Description Generate an invite link for joining the session (host only). With an email, the link is emailed and can only be used once, by a signed-in user with that address
 // @Tags sessions
 // @Accept json
 // @Produce json
@@ -317,7 +394,7 @@ func EndSessionHandler(sessionRepo sessions.Repository, sessionEnder SessionEnde
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/sessions/{id}/invite [post]
 // @Security BearerAuth
-func CreateInviteTokenHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+func CreateInviteTokenHandler(sessionRepo sessions.Repository, userFinder UserFinder, inviteMailer InviteMailer, notifier Notifier, auditLog AuditLog) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		sessionID, ok := errors.ValidatePathUUID(c, "id")
 		if !ok {
@@ -347,27 +424,118 @@ func CreateInviteTokenHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
+		var email *string
+		var inviterName string
+		if req.Email != "" {
+			email = &req.Email
+			if host, err := userFinder.FindByID(c.Request.Context(), session.HostUserID); err == nil {
+				inviterName = host.Name
+			}
+		}
+
 		token, err := sessionRepo.CreateInviteToken(c.Request.Context(), &sessions.CreateInviteTokenRequest{
 			SessionID: sessionID,
 			Role:      req.Role,
 			MaxUses:   req.MaxUses,
 			ExpiresAt: req.ExpiresAt,
+			Email:     email,
+		}, func(tx pgx.Tx, token *sessions.InviteToken) error {
+			return notifyInvitee(c.Request.Context(), tx, userFinder, notifier, session, token, inviterName)
 		})
 		if err != nil {
 			errors.InternalError(c, "failed to create invite token", err)
 			return
 		}
 
-		c.JSON(http.StatusCreated, InviteTokenResponse{
-			ID:        token.ID,
-			SessionID: token.SessionID,
-			Token:     token.Token,
-			Role:      token.Role,
-			MaxUses:   token.MaxUses,
-			UsesCount: token.UsesCount,
-			ExpiresAt: token.ExpiresAt,
-			CreatedAt: token.CreatedAt,
+		recordEvent(c, auditLog, audit.Event{
+			ActorID:    userID,
+			Action:     audit.ActionInviteCreate,
+			TargetType: audit.TargetInvite,
+			TargetID:   token.ID,
+			SessionID:  sessionID,
+			Details:    gin.H{"role": token.Role, "max_uses": token.MaxUses, "expires_at": token.ExpiresAt, "email": token.Email},
 		})
+
+		response := newInviteTokenResponse(token)
+
+		if token.Email != nil {
+			sent := sendInvite(c, inviteMailer, session, token, inviterName)
+			response.EmailSent = &sent
+		}
+
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// notifies the invitee of an email-bound invite in-app if they have an account,
+// in the transaction creating the invite
+func notifyInvitee(
+	ctx context.Context,
+	tx pgx.Tx,
+	userFinder UserFinder,
+	notifier Notifier,
+	session *sessions.Session,
+	token *sessions.InviteToken,
+	inviterName string,
+) error {
+	if token.Email == nil {
+		return nil
+	}
+
+	// without an account the email is all the invitee gets
+	invitee, err := userFinder.FindByEmail(ctx, *token.Email)
+	if err != nil {
+		return nil
+	}
+
+	return notifier.NotifyTx(ctx, tx, invitee.ID, notifications.KindSessionInvite, notifications.InviteMessage(inviterName, session.Title), notifications.InviteData{
+		SessionID: session.ID,
+		Title:     session.Title,
+		InvitedBy: session.HostUserID,
+		Role:      token.Role,
+		Token:     token.Token,
+	})
+}
+
+// emails an email-bound invite. returns whether the email was sent; the host
+// can still share the link otherwise.
+func sendInvite(c *gin.Context, inviteMailer InviteMailer, session *sessions.Session, token *sessions.InviteToken, inviterName string) bool {
+	ctx := c.Request.Context()
+
+	err := inviteMailer.SendInvite(ctx, mailer.Invite{
+		To:           *token.Email,
+		InviterName:  inviterName,
+		SessionTitle: session.Title,
+		Role:         token.Role,
+		URL:          inviteMailer.InviteURL(token.Token),
+	})
+
+	if stderrors.Is(err, mailer.ErrUnsubscribed) {
+		return false
+	}
+
+	if err != nil {
+		logger.ErrorErr(err, "failed to email invite",
+			"session_id", session.ID,
+			"token_id", token.ID,
+		)
+		return false
+	}
+
+	return true
+}
+
+func newInviteTokenResponse(token *sessions.InviteToken) InviteTokenResponse {
+	return InviteTokenResponse{
+		ID:        token.ID,
+		SessionID: token.SessionID,
+		Token:     token.Token,
+		Role:      token.Role,
+		MaxUses:   token.MaxUses,
+		UsesCount: token.UsesCount,
+		ExpiresAt: token.ExpiresAt,
+		Email:     token.Email,
+		CreatedAt: token.CreatedAt,
 	}
 }
 
@@ -377,6 +545,10 @@ func CreateInviteTokenHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 // @Tags sessions
 // @Produce json
 // @Param id path string true "Session ID (UUID)"
+// @Param limit query int false "Items per page (max 200)" default(50)
+// @Param cursor query string false "Cursor from the previous response's next_cursor or prev_cursor"
+// @Param sort query string false "joined_at or display_name, with a leading - for descending" default(joined_at)
+// @Param fields query []string false "Participant fields to return (comma-separated), id is always included"
 // @Success 200 {object} ParticipantsListResponse
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
@@ -390,6 +562,11 @@ func ListParticipantsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
+		page, fields, ok := parsePage(c, participantPages, ParticipantResponse{})
+		if !ok {
+			return
+		}
+
 		// get participants (both authenticated and anonymous)
 		participants, err := sessionRepo.ListAllParticipants(c.Request.Context(), sessionID)
 		if err != nil {
@@ -397,6 +574,15 @@ func ListParticipantsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
+		// both kinds come from separate tables, so they're paged in memory
+		key := sessions.ParticipantKey(page.Sort.Field)
+		participants, edges := keyset.Cut(page, keyset.Select(page, participants, key), key)
+
+		meta, ok := pageMeta(c, page, edges)
+		if !ok {
+			return
+		}
+
 		responses := make([]ParticipantResponse, 0, len(participants))
 
 		for _, p := range participants {
@@ -405,13 +591,15 @@ func ListParticipantsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 				UserID:      p.UserID,
 				DisplayName: &p.DisplayName,
 				Role:        p.Role,
+				Permissions: p.Permissions().Names(),
+				Muted:       p.MutedAt != nil,
 				Status:      p.Status,
 				JoinedAt:    p.JoinedAt,
 				LeftAt:      p.LeftAt,
 			})
 		}
 
-		c.JSON(http.StatusOK, ParticipantsListResponse{Participants: responses})
+		respondPage(c, ParticipantsListResponse{Participants: responses, Pagination: meta}, "participants", fields)
 	}
 }
 
@@ -424,11 +612,12 @@ func ListParticipantsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 // @Param request body JoinSessionRequest true "Join request with invite token"
 // @Success 200 {object} JoinSessionResponse
 // @Failure 400 {object} errors.ErrorResponse
-// @Failure 403 {object} errors.ErrorResponse "Invalid or expired invite"
+// @Failure 401 {object} errors.ErrorResponse "Invalid or expired invite"
+// @Failure 403 {object} errors.ErrorResponse "Invite was sent to an email address the user hasn't verified"
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/sessions/join [post]
 // @Security BearerAuth
-func JoinSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+func JoinSessionHandler(sessionRepo sessions.Repository, userFinder UserFinder) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req JoinSessionRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -443,6 +632,32 @@ func JoinSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 		}
 
 		userID, isAuthenticated := auth.GetUserID(c)
+
+		// email-bound invites only admit the invited user
+		if token.Email != nil {
+			if !isAuthenticated {
+				errors.InvalidInvite(c, "sign in with the email address this invite was sent to")
+				return
+			}
+
+			verified, err := userFinder.HasVerifiedEmail(c.Request.Context(), userID, *token.Email)
+			if err != nil || !verified {
+				errors.Forbidden(c, "this invite was sent to an email address your account hasn't verified")
+				return
+			}
+		}
+
+		claimed, err := sessionRepo.ClaimInviteToken(c.Request.Context(), token.ID)
+		if err != nil {
+			errors.InternalError(c, "failed to join session", err)
+			return
+		}
+
+		if !claimed {
+			errors.InvalidInvite(c, "")
+			return
+		}
+
 		displayName := req.DisplayName
 
 		if displayName == "" {
@@ -467,13 +682,6 @@ func JoinSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			}
 		}
 
-		if err := sessionRepo.IncrementTokenUses(c.Request.Context(), token.ID); err != nil {
-			logger.ErrorErr(err, "failed to increment token uses",
-				"session_id", token.SessionID,
-				"token_id", token.ID,
-			)
-		}
-
 		c.JSON(http.StatusOK, JoinSessionResponse{
 			SessionID:   token.SessionID,
 			Role:        token.Role,
@@ -525,11 +733,14 @@ func LeaveSessionHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 
 // GetSessionMessagesHandler godoc
 // @Summary Get session chat messages
-// @Description Retrieve chat messages from a session (AI conversations are strudel-scoped and fetched separately)
+// @Description Retrieve chat messages from a session (AI exchanges are only part of the session transcript)
 // @Tags sessions
 // @Produce json
 // @Param id path string true "Session ID (UUID)"
 // @Param limit query int false "Max messages to return (max 1000)" default(100)
+// @Param cursor query string false "Cursor from the previous response's next_cursor or prev_cursor"
+// @Param sort query string false "created_at, with a leading - for descending" default(-created_at)
+// @Param fields query []string false "Message fields to return (comma-separated), id is always included"
 // @Success 200 {object} MessagesResponse
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
@@ -543,25 +754,103 @@ func GetSessionMessagesHandler(sessionRepo sessions.Repository) gin.HandlerFunc
 			return
 		}
 
-		limit := 100
+		page, fields, ok := parsePage(c, messagePages, sessions.Message{})
+		if !ok {
+			return
+		}
+
+		// get chat messages (AI exchanges are only part of the transcript)
+		messages, err := sessionRepo.ListChatMessages(c.Request.Context(), sessionID, page)
+		if err != nil {
+			errors.InternalError(c, "failed to retrieve messages", err)
+			return
+		}
+
+		messages, edges := keyset.Cut(page, messages, sessions.MessageKey)
 
-		if limitStr := c.Query("limit"); limitStr != "" {
-			var parsedLimit int
-			if _, err := fmt.Sscanf(limitStr, "%d", &parsedLimit); err == nil {
-				if parsedLimit > 0 && parsedLimit <= 1000 {
-					limit = parsedLimit
-				}
+		meta, ok := pageMeta(c, page, edges)
+		if !ok {
+			return
+		}
+
+		if messages == nil {
+			messages = []*sessions.Message{}
+		}
+
+		respondPage(c, MessagesResponse{Messages: messages, Pagination: meta}, "messages", fields)
+	}
+}
+
+// GetSessionTranscriptHandler godoc
+// @Summary Export session transcript
+// @Description Render the timeline of a session, its chat messages, AI prompts and responses, and code checkpoints, as a Markdown or HTML document with highlighted code. Available to the host and anyone who took part in the session. Checkpoints are taken at most every snapshot interval and unchanged code is left out. Transcripts stop after 5000 entries.
+// @Tags sessions
+// @Produce text/markdown
+// @Produce html
+// @Param id path string true "Session ID (UUID)"
+// @Param format query string false "md or html" default(md)
+// @Param download query bool false "Ask browsers to save the document instead of showing it"
+// @Success 200 {string} string "Transcript document"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/transcript [get]
+// @Security BearerAuth
+func GetSessionTranscriptHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		format := c.DefaultQuery("format", transcript.FormatMarkdown)
+		contentType, known := transcriptContentTypes[format]
+		if !known {
+			errors.BadRequest(c, "format must be md or html", nil)
+			return
+		}
+
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		// participants who left keep access, transcripts are mostly read after the session
+		if session.HostUserID != userID {
+			if _, err := sessionRepo.GetAuthenticatedParticipant(c.Request.Context(), sessionID, userID); err != nil {
+				errors.Forbidden(c, "only the host and participants can export the transcript")
+				return
 			}
 		}
 
-		// get chat messages (AI conversations are strudel-scoped and not returned here)
-		messages, err := sessionRepo.GetChatMessages(c.Request.Context(), sessionID, limit)
+		entries, err := sessionRepo.ListTranscript(c.Request.Context(), sessionID, maxTranscriptEntries+1)
 		if err != nil {
-			errors.InternalError(c, "failed to retrieve messages", err)
+			errors.InternalError(c, "failed to retrieve transcript", err)
+			return
+		}
+
+		document, err := transcript.Render(transcript.New(session, entries, maxTranscriptEntries), format)
+		if err != nil {
+			errors.InternalError(c, "failed to render transcript", err)
 			return
 		}
 
-		c.JSON(http.StatusOK, MessagesResponse{Messages: messages})
+		disposition := "inline"
+		if c.Query("download") == "true" {
+			disposition = "attachment"
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`%s; filename="session-%s-transcript.%s"`, disposition, sessionID, format))
+
+		c.Data(http.StatusOK, contentType, document)
 	}
 }
 
@@ -580,7 +869,7 @@ func GetSessionMessagesHandler(sessionRepo sessions.Repository) gin.HandlerFunc
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/sessions/{id}/participants/{participant_id} [delete]
 // @Security BearerAuth
-func RemoveParticipantHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+func RemoveParticipantHandler(sessionRepo sessions.Repository, auditLog AuditLog) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		sessionID, ok := errors.ValidatePathUUID(c, "id")
 		if !ok {
@@ -604,8 +893,8 @@ func RemoveParticipantHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
-		if session.HostUserID != userID {
-			errors.Forbidden(c, "only the host can remove participants")
+		if !hasPermission(c, sessionRepo, session, userID, sessions.PermManageParticipants) {
+			errors.Forbidden(c, "you don't have permission to remove participants")
 			return
 		}
 
@@ -615,6 +904,11 @@ func RemoveParticipantHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
+		if participant.Role == "host" && session.HostUserID != userID {
+			errors.InvalidOperation(c, "cannot remove the host")
+			return
+		}
+
 		if participant.UserID != nil && *participant.UserID == userID {
 			errors.InvalidOperation(c, "cannot remove yourself. use leave endpoint instead")
 			return
@@ -625,11 +919,37 @@ func RemoveParticipantHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			return
 		}
 
+		recordEvent(c, auditLog, audit.Event{
+			ActorID:    userID,
+			Action:     audit.ActionParticipantKick,
+			TargetType: audit.TargetParticipant,
+			TargetID:   participantID,
+			SessionID:  sessionID,
+			Details:    participantDetails(participant),
+		})
+
 		c.JSON(http.StatusOK, MessageResponse{Message: "participant removed successfully"})
 	}
 }
 
-func UpdateParticipantRoleHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+// UpdateParticipantRoleHandler godoc
+// @Summary Update participant role
+// @Description Change a participant's role to co-author or viewer, resetting their permissions to the role's preset. Requires manage_participants. Connected clients are updated immediately.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param participant_id path string true "Participant ID (UUID)"
+// @Param request body UpdateRoleRequest true "New role"
+// @Success 200 {object} UpdateRoleResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/participants/{participant_id} [patch]
+// @Security BearerAuth
+func UpdateParticipantRoleHandler(sessionRepo sessions.Repository, permissionUpdater PermissionUpdater, auditLog AuditLog) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		sessionID, ok := errors.ValidatePathUUID(c, "id")
 		if !ok {
@@ -653,15 +973,12 @@ func UpdateParticipantRoleHandler(sessionRepo sessions.Repository) gin.HandlerFu
 			return
 		}
 
-		if session.HostUserID != userID {
-			errors.Forbidden(c, "only the host can change participant roles")
+		if !hasPermission(c, sessionRepo, session, userID, sessions.PermManageParticipants) {
+			errors.Forbidden(c, "you don't have permission to change participant roles")
 			return
 		}
 
-		var req struct {
-			Role string `json:"role" binding:"required,oneof=co-author viewer"`
-		}
-
+		var req UpdateRoleRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
 			errors.ValidationError(c, err)
 			return
@@ -678,11 +995,34 @@ func UpdateParticipantRoleHandler(sessionRepo sessions.Repository) gin.HandlerFu
 			return
 		}
 
+		if participant.Role == "spectator" {
+			errors.InvalidOperation(c, "spectators can only chat")
+			return
+		}
+
 		if err := sessionRepo.UpdateParticipantRole(c.Request.Context(), participantID, req.Role); err != nil {
 			errors.InternalError(c, "failed to update role", err)
 			return
 		}
 
+		details := participantDetails(participant)
+		details["old_role"] = participant.Role
+		details["new_role"] = req.Role
+
+		recordEvent(c, auditLog, audit.Event{
+			ActorID:    userID,
+			Action:     audit.ActionParticipantRole,
+			TargetType: audit.TargetParticipant,
+			TargetID:   participantID,
+			SessionID:  sessionID,
+			Details:    details,
+		})
+
+		// connected clients keep their role until they reconnect, so hand them
+		// the new role's permissions directly
+		preset := sessions.RolePermissions(req.Role)
+		permissionUpdater.SetParticipantPermissions(sessionID, participantID, &preset)
+
 		c.JSON(http.StatusOK, UpdateRoleResponse{
 			Message: "role updated successfully",
 			Role:    req.Role,
@@ -690,135 +1030,465 @@ func UpdateParticipantRoleHandler(sessionRepo sessions.Repository) gin.HandlerFu
 	}
 }
 
-// ListInviteTokensHandler godoc
-// @Summary List invite tokens
-// @Description Get all invite tokens for a session (host only)
+// UpdateParticipantPermissionsHandler godoc
+// @Summary Update participant permissions
+// @Description Replace a participant's permissions, overriding their role preset. Requires manage_participants; only the host can grant or revoke manage_participants. Connected clients are updated immediately.
 // @Tags sessions
+// @Accept json
 // @Produce json
 // @Param id path string true "Session ID (UUID)"
-// @Success 200 {object} InviteTokensListResponse
+// @Param participant_id path string true "Participant ID (UUID)"
+// @Param request body UpdatePermissionsRequest true "Permissions"
+// @Success 200 {object} UpdatePermissionsResponse
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
 // @Failure 403 {object} errors.ErrorResponse
 // @Failure 404 {object} errors.ErrorResponse
 // @Failure 500 {object} errors.ErrorResponse
-// @Router /api/v1/sessions/{id}/invite [get]
+// @Router /api/v1/sessions/{id}/participants/{participant_id}/permissions [put]
 // @Security BearerAuth
-func ListInviteTokensHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+func UpdateParticipantPermissionsHandler(sessionRepo sessions.Repository, permissionUpdater PermissionUpdater, auditLog AuditLog) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		sessionID, ok := errors.ValidatePathUUID(c, "id")
-		if !ok {
-			return
-		}
-
-		userID, exists := auth.GetUserID(c)
-		if !exists {
-			errors.Unauthorized(c, "")
-			return
-		}
-
-		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
-		if err != nil {
-			errors.SessionNotFound(c)
-			return
-		}
-
-		if session.HostUserID != userID {
-			errors.Forbidden(c, "only the host can view invite tokens")
+		var req UpdatePermissionsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
 			return
 		}
 
-		tokens, err := sessionRepo.ListInviteTokens(c.Request.Context(), sessionID)
+		permissions, err := sessions.ParsePermissions(req.Permissions)
 		if err != nil {
-			errors.InternalError(c, "failed to retrieve invite tokens", err)
+			errors.BadRequest(c, err.Error(), nil)
 			return
 		}
 
-		responses := make([]InviteTokenResponse, 0, len(tokens))
-		for _, t := range tokens {
-			responses = append(responses, InviteTokenResponse{
-				ID:        t.ID,
-				SessionID: t.SessionID,
-				Token:     t.Token,
-				Role:      t.Role,
-				MaxUses:   t.MaxUses,
-				UsesCount: t.UsesCount,
-				ExpiresAt: t.ExpiresAt,
-				CreatedAt: t.CreatedAt,
-			})
-		}
-
-		c.JSON(http.StatusOK, InviteTokensListResponse{Tokens: responses})
+		setParticipantPermissions(c, sessionRepo, permissionUpdater, auditLog, &permissions)
 	}
 }
 
-// RevokeInviteTokenHandler godoc
-// @Summary Revoke invite token
-// @Description Revoke an invite token to prevent further use (host only)
+// ResetParticipantPermissionsHandler godoc
+// @Summary Reset participant permissions
+// @Description Remove a participant's permission override so they get their role preset again. Requires manage_participants.
 // @Tags sessions
 // @Produce json
 // @Param id path string true "Session ID (UUID)"
-// @Param token_id path string true "Token ID (UUID)"
-// @Success 200 {object} MessageResponse
+// @Param participant_id path string true "Participant ID (UUID)"
+// @Success 200 {object} UpdatePermissionsResponse
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
 // @Failure 403 {object} errors.ErrorResponse
 // @Failure 404 {object} errors.ErrorResponse
 // @Failure 500 {object} errors.ErrorResponse
-// @Router /api/v1/sessions/{id}/invite/{token_id} [delete]
+// @Router /api/v1/sessions/{id}/participants/{participant_id}/permissions [delete]
 // @Security BearerAuth
-func RevokeInviteTokenHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+func ResetParticipantPermissionsHandler(sessionRepo sessions.Repository, permissionUpdater PermissionUpdater, auditLog AuditLog) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		sessionID, ok := errors.ValidatePathUUID(c, "id")
-		if !ok {
-			return
-		}
+		setParticipantPermissions(c, sessionRepo, permissionUpdater, auditLog, nil)
+	}
+}
 
-		tokenID, ok := errors.ValidatePathUUID(c, "token_id")
-		if !ok {
-			return
-		}
+// stores a participant's permissions (nil for the role preset) and applies
+// them to their open connections
+func setParticipantPermissions(c *gin.Context, sessionRepo sessions.Repository, permissionUpdater PermissionUpdater, auditLog AuditLog, permissions *sessions.Permission) {
+	sessionID, ok := errors.ValidatePathUUID(c, "id")
+	if !ok {
+		return
+	}
 
-		userID, exists := auth.GetUserID(c)
-		if !exists {
-			errors.Unauthorized(c, "")
-			return
-		}
+	participantID, ok := errors.ValidatePathUUID(c, "participant_id")
+	if !ok {
+		return
+	}
 
-		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
-		if err != nil {
-			errors.SessionNotFound(c)
-			return
-		}
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return
+	}
 
-		if session.HostUserID != userID {
-			errors.Forbidden(c, "only the host can revoke invite tokens")
-			return
-		}
+	session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+	if err != nil {
+		errors.SessionNotFound(c)
+		return
+	}
 
-		if err := sessionRepo.RevokeInviteToken(c.Request.Context(), tokenID); err != nil {
-			errors.InternalError(c, "failed to revoke invite token", err)
-			return
-		}
+	if !hasPermission(c, sessionRepo, session, userID, sessions.PermManageParticipants) {
+		errors.Forbidden(c, "you don't have permission to change participant permissions")
+		return
+	}
 
-		c.JSON(http.StatusOK, MessageResponse{Message: "invite token revoked successfully"})
+	participant, err := sessionRepo.GetParticipantByID(c.Request.Context(), participantID)
+	if err != nil || participant.SessionID != sessionID {
+		errors.ParticipantNotFound(c)
+		return
 	}
+
+	if participant.Role == "host" {
+		errors.InvalidOperation(c, "cannot change host permissions")
+		return
+	}
+
+	if participant.Role == "spectator" {
+		errors.InvalidOperation(c, "spectators can only chat")
+		return
+	}
+
+	oldPermissions := participant.Permissions()
+	newPermissions := sessions.EffectivePermissions(participant.Role, permissions)
+
+	// managers can't hand out or take away the ability to manage
+	if session.HostUserID != userID &&
+		oldPermissions.Has(sessions.PermManageParticipants) != newPermissions.Has(sessions.PermManageParticipants) {
+		errors.Forbidden(c, "only the host can grant or revoke manage_participants")
+		return
+	}
+
+	if err := sessionRepo.UpdateParticipantPermissions(c.Request.Context(), participantID, permissions); err != nil {
+		errors.InternalError(c, "failed to update permissions", err)
+		return
+	}
+
+	permissionUpdater.SetParticipantPermissions(sessionID, participantID, permissions)
+
+	details := participantDetails(participant)
+	details["old_permissions"] = oldPermissions.Names()
+	details["new_permissions"] = newPermissions.Names()
+
+	recordEvent(c, auditLog, audit.Event{
+		ActorID:    userID,
+		Action:     audit.ActionParticipantPermissions,
+		TargetType: audit.TargetParticipant,
+		TargetID:   participantID,
+		SessionID:  sessionID,
+		Details:    details,
+	})
+
+	c.JSON(http.StatusOK, UpdatePermissionsResponse{
+		Message:     "permissions updated successfully",
+		Permissions: newPermissions.Names(),
+		Custom:      permissions != nil,
+	})
 }
 
-// ListLiveSessionsHandler godoc
-// @Summary List live sessions
-// @Description Get discoverable active sessions plus user's own active sessions (if authenticated)
+// MuteParticipantHandler godoc
+// @Summary Mute participant
+// @Description Stop a participant or spectator from chatting without removing them from the session. Requires manage_participants. Connected clients are told with chat_muted.
 // @Tags sessions
 // @Produce json
-// @Param limit query int false "Items per page (max 100)" default(20)
-// @Param offset query int false "Number of items to skip" default(0)
-// @Success 200 {object} LiveSessionsListResponse
+// @Param id path string true "Session ID (UUID)"
+// @Param participant_id path string true "Participant ID (UUID)"
+// @Success 200 {object} MuteResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/participants/{participant_id}/mute [put]
+// @Security BearerAuth
+func MuteParticipantHandler(sessionRepo sessions.Repository, chatModerator ChatModerator, auditLog AuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setParticipantMuted(c, sessionRepo, chatModerator, auditLog, true)
+	}
+}
+
+// UnmuteParticipantHandler godoc
+// @Summary Unmute participant
+// @Description Let a muted participant or spectator chat again. Requires manage_participants.
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param participant_id path string true "Participant ID (UUID)"
+// @Success 200 {object} MuteResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/participants/{participant_id}/mute [delete]
+// @Security BearerAuth
+func UnmuteParticipantHandler(sessionRepo sessions.Repository, chatModerator ChatModerator, auditLog AuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setParticipantMuted(c, sessionRepo, chatModerator, auditLog, false)
+	}
+}
+
+// stores whether a participant is muted and applies it to their open connections
+func setParticipantMuted(c *gin.Context, sessionRepo sessions.Repository, chatModerator ChatModerator, auditLog AuditLog, muted bool) {
+	sessionID, ok := errors.ValidatePathUUID(c, "id")
+	if !ok {
+		return
+	}
+
+	participantID, ok := errors.ValidatePathUUID(c, "participant_id")
+	if !ok {
+		return
+	}
+
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return
+	}
+
+	session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+	if err != nil {
+		errors.SessionNotFound(c)
+		return
+	}
+
+	if !hasPermission(c, sessionRepo, session, userID, sessions.PermManageParticipants) {
+		errors.Forbidden(c, "you don't have permission to mute participants")
+		return
+	}
+
+	participant, err := sessionRepo.GetParticipantByID(c.Request.Context(), participantID)
+	if err != nil || participant.SessionID != sessionID {
+		errors.ParticipantNotFound(c)
+		return
+	}
+
+	if participant.Role == "host" {
+		errors.InvalidOperation(c, "cannot mute the host")
+		return
+	}
+
+	if participant.UserID != nil && *participant.UserID == userID {
+		errors.InvalidOperation(c, "cannot mute yourself")
+		return
+	}
+
+	// managers can't silence each other, only the host can
+	if session.HostUserID != userID && participant.Permissions().Has(sessions.PermManageParticipants) {
+		errors.Forbidden(c, "only the host can mute participants who manage others")
+		return
+	}
+
+	if err := sessionRepo.SetParticipantMuted(c.Request.Context(), participantID, muted); err != nil {
+		errors.InternalError(c, "failed to update mute", err)
+		return
+	}
+
+	chatModerator.SetParticipantMuted(sessionID, participantID, muted)
+
+	action := audit.ActionParticipantUnmute
+	message := "participant unmuted successfully"
+	if muted {
+		action = audit.ActionParticipantMute
+		message = "participant muted successfully"
+	}
+
+	recordEvent(c, auditLog, audit.Event{
+		ActorID:    userID,
+		Action:     action,
+		TargetType: audit.TargetParticipant,
+		TargetID:   participantID,
+		SessionID:  sessionID,
+		Details:    participantDetails(participant),
+	})
+
+	c.JSON(http.StatusOK, MuteResponse{Message: message, Muted: muted})
+}
+
+// UpdateChatSettingsHandler godoc
+// @Summary Update chat settings
+// @Description Set the chat slow mode of a session: each participant and spectator may send one message per slow_mode_seconds. 0 turns it off. Requires manage_participants, who aren't slowed down themselves. Connected clients are told with chat_settings.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body ChatSettingsRequest true "Chat settings"
+// @Success 200 {object} ChatSettingsResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/chat-settings [put]
+// @Security BearerAuth
+func UpdateChatSettingsHandler(sessionRepo sessions.Repository, chatModerator ChatModerator, auditLog AuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		if !hasPermission(c, sessionRepo, session, userID, sessions.PermManageParticipants) {
+			errors.Forbidden(c, "you don't have permission to change chat settings")
+			return
+		}
+
+		var req ChatSettingsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		slowMode := *req.SlowModeSeconds
+
+		if err := sessionRepo.SetChatSlowMode(c.Request.Context(), sessionID, slowMode); err != nil {
+			errors.InternalError(c, "failed to update chat settings", err)
+			return
+		}
+
+		chatModerator.SetChatSlowMode(sessionID, slowMode)
+
+		recordEvent(c, auditLog, audit.Event{
+			ActorID:    userID,
+			Action:     audit.ActionSessionChatSettings,
+			TargetType: audit.TargetSession,
+			TargetID:   sessionID,
+			SessionID:  sessionID,
+			Details:    gin.H{"old_slow_mode_seconds": session.ChatSlowMode, "new_slow_mode_seconds": slowMode},
+		})
+
+		c.JSON(http.StatusOK, ChatSettingsResponse{SlowModeSeconds: slowMode})
+	}
+}
+
+// ListInviteTokensHandler godoc
+// @Summary List invite tokens
+// @Description Get all invite tokens for a session (host only)
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Success 200 {object} InviteTokensListResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/invite [get]
+// @Security BearerAuth
+func ListInviteTokensHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		if session.HostUserID != userID {
+			errors.Forbidden(c, "only the host can view invite tokens")
+			return
+		}
+
+		tokens, err := sessionRepo.ListInviteTokens(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.InternalError(c, "failed to retrieve invite tokens", err)
+			return
+		}
+
+		responses := make([]InviteTokenResponse, 0, len(tokens))
+		for _, t := range tokens {
+			responses = append(responses, newInviteTokenResponse(t))
+		}
+
+		c.JSON(http.StatusOK, InviteTokensListResponse{Tokens: responses})
+	}
+}
+
+// RevokeInviteTokenHandler godoc
+// @Summary Revoke invite token
+// @Description Revoke an invite token to prevent further use (host only)
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param token_id path string true "Token ID (UUID)"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/invite/{token_id} [delete]
+// @Security BearerAuth
+func RevokeInviteTokenHandler(sessionRepo sessions.Repository, auditLog AuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		tokenID, ok := errors.ValidatePathUUID(c, "token_id")
+		if !ok {
+			return
+		}
+
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		if session.HostUserID != userID {
+			errors.Forbidden(c, "only the host can revoke invite tokens")
+			return
+		}
+
+		if err := sessionRepo.RevokeInviteToken(c.Request.Context(), tokenID); err != nil {
+			errors.InternalError(c, "failed to revoke invite token", err)
+			return
+		}
+
+		recordEvent(c, auditLog, audit.Event{
+			ActorID:    userID,
+			Action:     audit.ActionInviteRevoke,
+			TargetType: audit.TargetInvite,
+			TargetID:   tokenID,
+			SessionID:  sessionID,
+		})
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "invite token revoked successfully"})
+	}
+}
+
+// ListLiveSessionsHandler godoc
+// @Summary List live sessions
+// @Description Get discoverable active sessions plus user's own active sessions (if authenticated)
+// @Tags sessions
+// @Produce json
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param cursor query string false "Cursor from the previous response's next_cursor or prev_cursor"
+// @Param sort query string false "last_activity, created_at or title, with a leading - for descending" default(-last_activity)
+// @Param fields query []string false "Session fields to return (comma-separated), id is always included"
+// @Success 200 {object} LiveSessionsListResponse
+// @Failure 400 {object} errors.ErrorResponse
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/sessions/live [get]
 func ListLiveSessionsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		limit, offset := parsePaginationParams(c)
-		params := pagination.DefaultParams(limit, offset, 20, 100)
+		page, fields, ok := parsePage(c, sessionPages, LiveSessionResponse{})
+		if !ok {
+			return
+		}
 
 		// check if user is authenticated (optional)
 		userID, isAuthenticated := auth.GetUserID(c)
@@ -877,12 +1547,26 @@ func ListLiveSessionsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 		}
 
 		// get discoverable sessions
-		liveSessions, total, err := sessionRepo.ListDiscoverableSessions(c.Request.Context(), params.Limit, params.Offset)
+		liveSessions, edges, total, err := sessionRepo.ListDiscoverableSessions(c.Request.Context(), page)
 		if err != nil {
 			errors.InternalError(c, "failed to retrieve live sessions", err)
 			return
 		}
 
+		// adjust total to account for member sessions that aren't discoverable,
+		// discoverable ones are counted already
+		for _, s := range memberSessions {
+			if !s.IsDiscoverable {
+				total++
+			}
+		}
+
+		// member sessions lead the first page only, which is the one without a
+		// cursor or the one walking back ends on
+		if first := page.After == nil || (page.After.Backward && edges.Prev == nil); !first {
+			memberSessions = nil
+		}
+
 		// build response: member sessions first, then other discoverable sessions
 		responses := make([]LiveSessionResponse, 0, len(memberSessions)+len(liveSessions))
 		responses = append(responses, memberSessions...)
@@ -917,18 +1601,14 @@ func ListLiveSessionsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 			})
 		}
 
-		// adjust total to account for member sessions that might not be discoverable
-		adjustedTotal := total + len(memberSessions)
-		for _, s := range liveSessions {
-			if userSessionIDs[s.ID] {
-				adjustedTotal-- // don't double count
-			}
+		meta, ok := pageMeta(c, page, edges)
+		if !ok {
+			return
 		}
 
-		c.JSON(http.StatusOK, LiveSessionsListResponse{
-			Sessions:   responses,
-			Pagination: pagination.NewMeta(params, adjustedTotal),
-		})
+		meta.Total = &total
+
+		respondPage(c, LiveSessionsListResponse{Sessions: responses, Pagination: meta}, "sessions", fields)
 	}
 }
 
@@ -999,50 +1679,556 @@ func SetDiscoverableHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 	}
 }
 
-func parsePaginationParams(c *gin.Context) (limit, offset int) {
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if _, err := fmt.Sscanf(limitStr, "%d", &limit); err != nil {
-			limit = 0
-		}
-	}
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if _, err := fmt.Sscanf(offsetStr, "%d", &offset); err != nil {
-			offset = 0
-		}
-	}
-	return limit, offset
-}
-
-// SoftEndSessionHandler godoc
-// @Summary Soft-end a live session
-// @Description Ends the live portion of a session: kicks all non-host participants, revokes all invite tokens,
-// @Description sets discoverable to false. Host keeps access to the session and their code.
+// GetExpiryPolicyHandler godoc
+// @Summary Get session expiry policy
+// @Description Get how the session is treated when it goes idle (host only). Sessions without their own idle timeout are ended after 30 minutes without activity.
 // @Tags sessions
 // @Produce json
 // @Param id path string true "Session ID (UUID)"
-// @Success 200 {object} SoftEndSessionResponse
+// @Success 200 {object} sessions.ExpiryPolicy
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
 // @Failure 403 {object} errors.ErrorResponse
 // @Failure 404 {object} errors.ErrorResponse
 // @Failure 500 {object} errors.ErrorResponse
-// @Router /api/v1/sessions/{id}/end-live [post]
+// @Router /api/v1/sessions/{id}/expiry-policy [get]
 // @Security BearerAuth
-func SoftEndSessionHandler(sessionRepo sessions.Repository, sessionEnder SessionEnder) gin.HandlerFunc {
+func GetExpiryPolicyHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		sessionID, ok := hostedSessionID(c, sessionRepo, "only the host can view the expiry policy")
 		if !ok {
 			return
 		}
 
-		userID, exists := auth.GetUserID(c)
-		if !exists {
-			errors.Unauthorized(c, "")
+		policy, err := sessionRepo.GetExpiryPolicy(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.InternalError(c, "failed to retrieve expiry policy", err)
 			return
 		}
 
-		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
-		if err != nil {
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// SetExpiryPolicyHandler godoc
+// @Summary Set session expiry policy
+// @Description Replace how the session is treated when it goes idle (host only): end it after auto_end_idle_hours without activity instead of the server default, revoke its invites when it ends, and publish its final code as a public strudel of the host.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body ExpiryPolicyRequest true "Expiry policy"
+// @Success 200 {object} sessions.ExpiryPolicy
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/expiry-policy [put]
+// @Security BearerAuth
+func SetExpiryPolicyHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := hostedSessionID(c, sessionRepo, "only the host can change the expiry policy")
+		if !ok {
+			return
+		}
+
+		var req ExpiryPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		policy := &sessions.ExpiryPolicy{
+			AutoEndIdleHours:   req.AutoEndIdleHours,
+			RevokeInvitesOnEnd: req.RevokeInvitesOnEnd,
+			PublishOnEnd:       req.PublishOnEnd,
+		}
+
+		if err := sessionRepo.SetExpiryPolicy(c.Request.Context(), sessionID, policy); err != nil {
+			errors.InternalError(c, "failed to update expiry policy", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// validates the session ID path param, responding with an error unless the
+// user hosts the session
+func hostedSessionID(c *gin.Context, sessionRepo sessions.Repository, forbidden string) (string, bool) {
+	sessionID, ok := errors.ValidatePathUUID(c, "id")
+	if !ok {
+		return "", false
+	}
+
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return "", false
+	}
+
+	session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+	if err != nil {
+		errors.SessionNotFound(c)
+		return "", false
+	}
+
+	if session.HostUserID != userID {
+		errors.Forbidden(c, forbidden)
+		return "", false
+	}
+
+	return sessionID, true
+}
+
+// GetSessionAnalyticsHandler godoc
+// @Summary Get session analytics
+// @Description Activity of a session over time (host only): connected participants and listeners, code edits, agent requests, chat messages and seconds of playback per bucket. Activity is sampled once a minute and rolled up by the buffer flusher, so the latest minutes may be missing. At most 1000 buckets are returned, ending now.
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param bucket query string false "Bucket size: 1m, 5m, 15m, 1h or 1d" default(5m)
+// @Param since query string false "Start of the series (RFC 3339), defaults to when the session was created"
+// @Success 200 {object} SessionAnalyticsResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/analytics [get]
+// @Security BearerAuth
+func GetSessionAnalyticsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		if session.HostUserID != userID {
+			errors.Forbidden(c, "only the host can view session analytics")
+			return
+		}
+
+		bucket, known := analyticsBuckets[c.DefaultQuery("bucket", "5m")]
+		if !known {
+			errors.BadRequest(c, "bucket must be one of 1m, 5m, 15m, 1h or 1d", nil)
+			return
+		}
+
+		since := session.CreatedAt
+		if raw := c.Query("since"); raw != "" {
+			since, err = time.Parse(time.RFC3339, raw)
+			if err != nil {
+				errors.BadRequest(c, "since must be an RFC 3339 timestamp", err)
+				return
+			}
+		}
+
+		if earliest := time.Now().Add(-maxAnalyticsBuckets * bucket); since.Before(earliest) {
+			since = earliest
+		}
+		since = since.Truncate(bucket)
+
+		series, err := sessionRepo.GetSessionAnalytics(c.Request.Context(), sessionID, bucket, since)
+		if err != nil {
+			errors.InternalError(c, "failed to retrieve session analytics", err)
+			return
+		}
+
+		var totals AnalyticsTotals
+		for _, point := range series {
+			totals.PeakParticipants = max(totals.PeakParticipants, point.Participants)
+			totals.PeakListeners = max(totals.PeakListeners, point.Listeners)
+			totals.CodeEdits += point.CodeEdits
+			totals.AgentRequests += point.AgentRequests
+			totals.ChatMessages += point.ChatMessages
+			totals.PlaybackSeconds += point.PlaybackSeconds
+		}
+
+		c.JSON(http.StatusOK, SessionAnalyticsResponse{
+			SessionID:     sessionID,
+			BucketSeconds: int(bucket.Seconds()),
+			Since:         since,
+			Series:        series,
+			Totals:        totals,
+		})
+	}
+}
+
+// GetSessionAuditLogHandler godoc
+// @Summary Get session audit log
+// @Description Security-relevant actions taken in a session (ends, kicks, role changes, invites), newest first (host only)
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param action query string false "Filter by action (e.g. participant.kick)"
+// @Param limit query int false "Items per page (max 100)" default(50)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} AuditLogResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/audit [get]
+// @Security BearerAuth
+func GetSessionAuditLogHandler(sessionRepo sessions.Repository, auditLog AuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		if session.HostUserID != userID {
+			errors.Forbidden(c, "only the host can view the audit log")
+			return
+		}
+
+		if auditLog == nil {
+			errors.ServiceUnavailable(c, "audit log is not available")
+			return
+		}
+
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 50, 100)
+
+		filter := audit.ListFilter{
+			SessionID: sessionID,
+			Action:    c.Query("action"),
+		}
+
+		entries, total, err := auditLog.List(c.Request.Context(), filter, params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to retrieve audit log", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, AuditLogResponse{
+			Entries:    entries,
+			Pagination: pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// ListSnapshotsHandler godoc
+// @Summary List session snapshots
+// @Description List the restorable snapshots of a session's code, newest first (host or co-authors only). Snapshots are taken every 10 minutes of editing or every 500 edits, and before a restore.
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} SnapshotsListResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/snapshots [get]
+// @Security BearerAuth
+func ListSnapshotsHandler(sessionRepo sessions.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		if !hasPermission(c, sessionRepo, session, userID, sessions.PermEditCode) {
+			errors.Forbidden(c, "only participants who can edit code can view snapshots")
+			return
+		}
+
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 20, 100)
+
+		snapshots, total, err := sessionRepo.ListSnapshots(c.Request.Context(), sessionID, params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list snapshots", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, SnapshotsListResponse{
+			Snapshots:  snapshots,
+			Pagination: pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// RestoreSnapshotHandler godoc
+// @Summary Restore session snapshot
+// @Description Replace the session code with a snapshot (host only). The replaced code is kept as a pre_restore snapshot, and connected clients receive the restored code as a code_update with source "restore".
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param snapshot_id path string true "Snapshot ID (UUID)"
+// @Success 200 {object} UpdateSessionCodeResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/restore/{snapshot_id} [post]
+// @Security BearerAuth
+func RestoreSnapshotHandler(sessionRepo sessions.Repository, codeRestorer CodeRestorer, auditLog AuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		snapshotID, ok := errors.ValidatePathUUID(c, "snapshot_id")
+		if !ok {
+			return
+		}
+
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		session, err := sessionRepo.GetSession(ctx, sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		if session.HostUserID != userID {
+			errors.Forbidden(c, "only the host can restore snapshots")
+			return
+		}
+
+		snapshot, err := sessionRepo.GetSnapshot(ctx, sessionID, snapshotID)
+		if err != nil {
+			errors.NotFound(c, "snapshot")
+			return
+		}
+
+		// the live document is newer than the stored code while clients are connected
+		currentCode := session.Code
+		if codeRestorer != nil {
+			if code, _, live := codeRestorer.GetDocument(sessionID); live {
+				currentCode = code
+			}
+		}
+
+		if currentCode != snapshot.Code {
+			if _, err := sessionRepo.CreateSnapshot(ctx, sessionID, currentCode, sessions.SnapshotReasonPreRestore); err != nil {
+				errors.InternalError(c, "failed to snapshot current code", err)
+				return
+			}
+		}
+
+		if codeRestorer != nil {
+			if _, err := codeRestorer.RestoreCode(sessionID, userID, snapshot.Code); err != nil {
+				logger.ErrorErr(err, "failed to broadcast restored code",
+					"session_id", sessionID,
+					"snapshot_id", snapshotID,
+				)
+			}
+		}
+
+		if err := sessionRepo.UpdateSessionCode(ctx, sessionID, snapshot.Code); err != nil {
+			errors.InternalError(c, "failed to restore code", err)
+			return
+		}
+
+		recordEvent(c, auditLog, audit.Event{
+			ActorID:    userID,
+			Action:     audit.ActionSessionRestore,
+			TargetType: audit.TargetSnapshot,
+			TargetID:   snapshotID,
+			SessionID:  sessionID,
+			Details:    gin.H{"snapshot_created_at": snapshot.CreatedAt},
+		})
+
+		c.JSON(http.StatusOK, UpdateSessionCodeResponse{
+			Message: "snapshot restored successfully",
+			Code:    snapshot.Code,
+		})
+	}
+}
+
+// records an action in the audit log if one is configured
+func recordEvent(c *gin.Context, auditLog AuditLog, event audit.Event) {
+	if auditLog != nil {
+		auditLog.Record(c.Request.Context(), event)
+	}
+}
+
+// checks if the user may do something in the session. the host can do
+// anything, other users need the permission on their participant record
+func hasPermission(c *gin.Context, sessionRepo sessions.Repository, session *sessions.Session, userID string, permission sessions.Permission) bool {
+	if session.HostUserID == userID {
+		return true
+	}
+
+	participant, err := sessionRepo.GetAuthenticatedParticipant(c.Request.Context(), session.ID, userID)
+	if err != nil || participant.Status != "active" {
+		return false
+	}
+
+	return participant.Permissions().Has(permission)
+}
+
+// identifies a participant in audit details, which outlive the participant row
+func participantDetails(p *sessions.CombinedParticipant) gin.H {
+	details := gin.H{"display_name": p.DisplayName}
+	if p.UserID != nil {
+		details["user_id"] = *p.UserID
+	}
+
+	return details
+}
+
+// cursor pagination of session, participant and chat message lists
+var (
+	sessionPages = pagination.KeysetOptions{
+		DefaultLimit: 20,
+		MaxLimit:     100,
+		Sorts:        sessions.SessionSorts,
+		DefaultSort:  "-last_activity",
+	}
+	participantPages = pagination.KeysetOptions{
+		DefaultLimit: 50,
+		MaxLimit:     200,
+		Sorts:        sessions.ParticipantSorts,
+		DefaultSort:  "joined_at",
+	}
+	messagePages = pagination.KeysetOptions{
+		DefaultLimit: 100,
+		MaxLimit:     1000,
+		Sorts:        sessions.MessageSorts,
+		DefaultSort:  "-created_at",
+	}
+)
+
+// reads the page of a list request and the fields of item it asks for,
+// responding with 400 when either is invalid
+func parsePage(c *gin.Context, opts pagination.KeysetOptions, item any) (keyset.Page, pagination.Fields, bool) {
+	query := c.Request.URL.Query()
+
+	page, err := pagination.ParseKeyset(query, opts)
+	if err != nil {
+		errors.BadRequest(c, err.Error(), nil)
+		return page, nil, false
+	}
+
+	fields, err := pagination.ParseFields(query, item)
+	if err != nil {
+		errors.BadRequest(c, err.Error(), nil)
+		return page, nil, false
+	}
+
+	return page, fields, true
+}
+
+// the pagination metadata of a page, responding with 500 when a cursor
+// can't be encoded
+func pageMeta(c *gin.Context, page keyset.Page, edges keyset.Edges) (pagination.CursorMeta, bool) {
+	meta, err := pagination.NewKeysetMeta(c.Request.URL, page, edges)
+	if err != nil {
+		errors.InternalError(c, "failed to encode cursor", err)
+		return meta, false
+	}
+
+	return meta, true
+}
+
+// responds with body, the items of its list at key trimmed to fields
+func respondPage(c *gin.Context, body any, key string, fields pagination.Fields) {
+	projected, err := pagination.Project(body, key, fields)
+	if err != nil {
+		errors.InternalError(c, "failed to select fields", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, projected)
+}
+
+func parsePaginationParams(c *gin.Context) (limit, offset int) {
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if _, err := fmt.Sscanf(limitStr, "%d", &limit); err != nil {
+			limit = 0
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if _, err := fmt.Sscanf(offsetStr, "%d", &offset); err != nil {
+			offset = 0
+		}
+	}
+	return limit, offset
+}
+
+// SoftEndSessionHandler godoc
+// @Summary Soft-end a live session
+// @Description Ends the live portion of a session: kicks all non-host participants, revokes all invite tokens,
+// @Description sets discoverable to false. Host keeps access to the session and their code.
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Success 200 {object} SoftEndSessionResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/end-live [post]
+// @Security BearerAuth
+func SoftEndSessionHandler(sessionRepo sessions.Repository, sessionEnder SessionEnder, auditLog AuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
 			errors.SessionNotFound(c)
 			return
 		}
@@ -1087,6 +2273,15 @@ func SoftEndSessionHandler(sessionRepo sessions.Repository, sessionEnder Session
 			sessionEnder.EndSession(sessionID, "live session ended by host")
 		}
 
+		recordEvent(c, auditLog, audit.Event{
+			ActorID:    userID,
+			Action:     audit.ActionSessionEndLive,
+			TargetType: audit.TargetSession,
+			TargetID:   sessionID,
+			SessionID:  sessionID,
+			Details:    gin.H{"participants_kicked": participantsKicked, "invites_revoked": invitesRevoked},
+		})
+
 		c.JSON(http.StatusOK, SoftEndSessionResponse{
 			Message:            "live session ended successfully",
 			ParticipantsKicked: participantsKicked,
@@ -0,0 +1,307 @@
+package collaboration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/algopatterns/users"
+)
+
+// serves one invite token, allowing maxUses claims
+type inviteRepo struct {
+	sessions.Repository
+	token   *sessions.InviteToken
+	maxUses int
+	claims  int
+	joined  []string
+}
+
+func (r *inviteRepo) ValidateInviteToken(_ context.Context, token string) (*sessions.InviteToken, error) {
+	if token != r.token.Token {
+		return nil, pgx.ErrNoRows
+	}
+
+	return r.token, nil
+}
+
+func (r *inviteRepo) ClaimInviteToken(_ context.Context, _ string) (bool, error) {
+	if r.claims >= r.maxUses {
+		return false, nil
+	}
+
+	r.claims++
+	return true, nil
+}
+
+func (r *inviteRepo) AddAuthenticatedParticipant(_ context.Context, _, userID, _, _ string) (*sessions.Participant, error) {
+	r.joined = append(r.joined, userID)
+	return &sessions.Participant{}, nil
+}
+
+func (r *inviteRepo) AddAnonymousParticipant(_ context.Context, _, displayName, _ string) (*sessions.AnonymousParticipant, error) {
+	r.joined = append(r.joined, displayName)
+	return &sessions.AnonymousParticipant{}, nil
+}
+
+// knows which emails each user's sign-in providers verified
+type verifiedEmails map[string]string
+
+func (v verifiedEmails) FindByID(_ context.Context, userID string) (*users.User, error) {
+	return &users.User{ID: userID}, nil
+}
+
+func (v verifiedEmails) FindByEmail(_ context.Context, _ string) (*users.User, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (v verifiedEmails) HasVerifiedEmail(_ context.Context, userID, email string) (bool, error) {
+	return strings.EqualFold(v[userID], email), nil
+}
+
+func join(repo *inviteRepo, userFinder UserFinder, userID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/sessions/join", strings.NewReader(`{"invite_token":"tok"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	if userID != "" {
+		c.Set("user_id", userID)
+	}
+
+	JoinSessionHandler(repo, userFinder)(c)
+	return w
+}
+
+func TestJoinEmailBoundInvite(t *testing.T) {
+	email := "ada@example.com"
+	userFinder := verifiedEmails{"ada": "Ada@Example.com", "mallory": "mallory@example.com"}
+
+	newRepo := func() *inviteRepo {
+		return &inviteRepo{token: &sessions.InviteToken{ID: "t1", SessionID: "s1", Token: "tok", Role: "co-author", Email: &email}, maxUses: 1}
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		repo := newRepo()
+		assert.Equal(t, http.StatusUnauthorized, join(repo, userFinder, "").Code)
+		assert.Zero(t, repo.claims)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		repo := newRepo()
+		assert.Equal(t, http.StatusForbidden, join(repo, userFinder, "mallory").Code)
+		assert.Zero(t, repo.claims)
+		assert.Empty(t, repo.joined)
+	})
+
+	t.Run("invited user", func(t *testing.T) {
+		repo := newRepo()
+		assert.Equal(t, http.StatusOK, join(repo, userFinder, "ada").Code)
+		assert.Equal(t, []string{"ada"}, repo.joined)
+	})
+}
+
+func TestJoinRejectsUsedUpInvite(t *testing.T) {
+	repo := &inviteRepo{token: &sessions.InviteToken{ID: "t1", SessionID: "s1", Token: "tok", Role: "viewer"}, maxUses: 1}
+	userFinder := verifiedEmails{}
+
+	assert.Equal(t, http.StatusOK, join(repo, userFinder, "first").Code)
+
+	// a join that validated the token before the first one claimed it
+	assert.Equal(t, http.StatusUnauthorized, join(repo, userFinder, "second").Code)
+	assert.Equal(t, []string{"first"}, repo.joined)
+}
+
+// serves a fixed list of participants
+type participantsRepo struct {
+	sessions.Repository
+	participants []*sessions.CombinedParticipant
+}
+
+func (r *participantsRepo) ListAllParticipants(_ context.Context, _ string) ([]*sessions.CombinedParticipant, error) {
+	return r.participants, nil
+}
+
+func listParticipants(repo *participantsRepo, target string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/api/v1/sessions/:id/participants", ListParticipantsHandler(repo))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestListParticipantsPages(t *testing.T) {
+	joined := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &participantsRepo{}
+	for i := range 5 {
+		repo.participants = append(repo.participants, &sessions.CombinedParticipant{
+			ID:          fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i),
+			DisplayName: fmt.Sprintf("user %d", i),
+			Status:      "active",
+			JoinedAt:    joined.Add(time.Duration(4-i) * time.Minute), // latest first
+		})
+	}
+
+	type response struct {
+		Participants []map[string]any `json:"participants"`
+		Pagination   struct {
+			Next string `json:"next"`
+			Prev string `json:"prev"`
+		} `json:"pagination"`
+	}
+
+	var names []any
+	var prev string
+	target := "/api/v1/sessions/11111111-1111-1111-1111-111111111111/participants?limit=2&fields=display_name"
+	for target != "" {
+		w := listParticipants(repo, target)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var page response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		for _, p := range page.Participants {
+			assert.ElementsMatch(t, []string{"id", "display_name"}, slices.Collect(maps.Keys(p)))
+			names = append(names, p["display_name"])
+		}
+
+		target, prev = page.Pagination.Next, page.Pagination.Prev
+	}
+
+	// oldest joins first, by default
+	assert.Equal(t, []any{"user 4", "user 3", "user 2", "user 1", "user 0"}, names)
+	assert.NotEmpty(t, prev, "the last page links back")
+}
+
+func TestListParticipantsRejectsBadParams(t *testing.T) {
+	repo := &participantsRepo{}
+	base := "/api/v1/sessions/11111111-1111-1111-1111-111111111111/participants"
+
+	for _, query := range []string{"?sort=role", "?fields=password", "?cursor=nope"} {
+		assert.Equal(t, http.StatusBadRequest, listParticipants(repo, base+query).Code, query)
+	}
+}
+
+// serves one session with the participants of participantsRepo
+type sessionRepo struct {
+	participantsRepo
+	session *sessions.Session
+}
+
+func (r *sessionRepo) GetSession(_ context.Context, _ string) (*sessions.Session, error) {
+	return r.session, nil
+}
+
+func TestGetSessionRevalidates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &sessionRepo{session: &sessions.Session{ID: "s1", Title: "jam", LastActivity: time.Now()}}
+	repo.participants = []*sessions.CombinedParticipant{{ID: "p1", DisplayName: "ada", Status: "active"}}
+
+	router := gin.New()
+	router.GET("/api/v1/sessions/:id", GetSessionHandler(repo))
+
+	get := func(etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/sessions/11111111-1111-1111-1111-111111111111", nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+
+	unchanged := get(etag)
+	assert.Equal(t, http.StatusNotModified, unchanged.Code)
+	assert.Empty(t, unchanged.Body.String())
+
+	// leaving doesn't touch last_activity, the tag still changes
+	repo.participants[0].Status = "left"
+	changed := get(etag)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+// serves the transcript of one session hosted by "host", with "guest" as
+// its only participant
+type transcriptRepo struct {
+	sessions.Repository
+	entries []*sessions.TranscriptEntry
+}
+
+func (r *transcriptRepo) GetSession(_ context.Context, sessionID string) (*sessions.Session, error) {
+	return &sessions.Session{ID: sessionID, HostUserID: "host", Title: "jam", CreatedAt: time.Now()}, nil
+}
+
+func (r *transcriptRepo) GetAuthenticatedParticipant(_ context.Context, _, userID string) (*sessions.Participant, error) {
+	if userID != "guest" {
+		return nil, pgx.ErrNoRows
+	}
+
+	return &sessions.Participant{UserID: userID, Status: "left"}, nil
+}
+
+func (r *transcriptRepo) ListTranscript(_ context.Context, _ string, _ int) ([]*sessions.TranscriptEntry, error) {
+	return r.entries, nil
+}
+
+func TestGetSessionTranscript(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	name := "ada"
+	repo := &transcriptRepo{entries: []*sessions.TranscriptEntry{
+		{Kind: sessions.MessageTypeChat, DisplayName: &name, Content: "hello", CreatedAt: time.Now()},
+		{Kind: sessions.TranscriptEntryCode, Content: `s("bd")`, CreatedAt: time.Now()},
+	}}
+
+	get := func(userID, query string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/api/v1/sessions/:id/transcript", func(c *gin.Context) {
+			c.Set("user_id", userID)
+		}, GetSessionTranscriptHandler(repo))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/sessions/11111111-1111-1111-1111-111111111111/transcript"+query, nil))
+		return w
+	}
+
+	md := get("host", "")
+	require.Equal(t, http.StatusOK, md.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", md.Header().Get("Content-Type"))
+	assert.Contains(t, md.Header().Get("Content-Disposition"), `inline; filename="session-11111111-1111-1111-1111-111111111111-transcript.md"`)
+	assert.Contains(t, md.Body.String(), "**ada**")
+	assert.Contains(t, md.Body.String(), "```javascript\ns(\"bd\")\n```")
+
+	// participants who left can still export it
+	html := get("guest", "?format=html&download=true")
+	require.Equal(t, http.StatusOK, html.Code)
+	assert.Equal(t, "text/html; charset=utf-8", html.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(html.Header().Get("Content-Disposition"), "attachment;"))
+
+	assert.Equal(t, http.StatusForbidden, get("stranger", "").Code)
+	assert.Equal(t, http.StatusBadRequest, get("host", "?format=pdf").Code)
+}
@@ -7,7 +7,7 @@ import (
 	"codeberg.org/algopatterns/server/internal/auth"
 )
 
-func RegisterRoutes(router *gin.RouterGroup, sessionRepo sessions.Repository, sessionEnder SessionEnder) {
+func RegisterRoutes(router *gin.RouterGroup, sessionRepo sessions.Repository, sessionEnder SessionEnder, codeRestorer CodeRestorer, permissionUpdater PermissionUpdater, chatModerator ChatModerator, events EventPublisher, auditLog AuditLog, userFinder UserFinder, inviteMailer InviteMailer, notifier Notifier) {
 	// live sessions (optional auth - includes user's sessions if authenticated)
 	router.GET("/sessions/live", auth.OptionalAuthMiddleware(), ListLiveSessionsHandler(sessionRepo))
 
@@ -15,16 +15,19 @@ func RegisterRoutes(router *gin.RouterGroup, sessionRepo sessions.Repository, se
 	router.GET("/sessions/last", auth.AuthMiddleware(), GetLastUserSessionHandler(sessionRepo))
 
 	// session management (authenticated)
-	router.POST("/sessions", auth.AuthMiddleware(), CreateSessionHandler(sessionRepo))
+	router.POST("/sessions", auth.AuthMiddleware(), CreateSessionHandler(sessionRepo, events))
 	router.GET("/sessions", auth.AuthMiddleware(), ListUserSessionsHandler(sessionRepo))
 	router.GET("/sessions/:id", auth.AuthMiddleware(), GetSessionHandler(sessionRepo))
 	router.PUT("/sessions/:id", auth.AuthMiddleware(), UpdateSessionCodeHandler(sessionRepo))
-	router.DELETE("/sessions/:id", auth.AuthMiddleware(), EndSessionHandler(sessionRepo, sessionEnder))
+	router.DELETE("/sessions/:id", auth.AuthMiddleware(), EndSessionHandler(sessionRepo, sessionEnder, events, auditLog))
 	router.POST("/sessions/:id/leave", auth.AuthMiddleware(), LeaveSessionHandler(sessionRepo))
 	router.PUT("/sessions/:id/discoverable", auth.AuthMiddleware(), SetDiscoverableHandler(sessionRepo))
+	router.GET("/sessions/:id/expiry-policy", auth.AuthMiddleware(), GetExpiryPolicyHandler(sessionRepo))
+	router.PUT("/sessions/:id/expiry-policy", auth.AuthMiddleware(), SetExpiryPolicyHandler(sessionRepo))
+	router.PUT("/sessions/:id/chat-settings", auth.AuthMiddleware(), UpdateChatSettingsHandler(sessionRepo, chatModerator, auditLog))
 
 	// soft-end live session (kicks participants, revokes invites, keeps code)
-	router.POST("/sessions/:id/end-live", auth.AuthMiddleware(), SoftEndSessionHandler(sessionRepo, sessionEnder))
+	router.POST("/sessions/:id/end-live", auth.AuthMiddleware(), SoftEndSessionHandler(sessionRepo, sessionEnder, auditLog))
 
 	// check live status
 	router.GET("/sessions/:id/live-status", auth.AuthMiddleware(), GetSessionLiveStatusHandler(sessionRepo))
@@ -32,16 +35,33 @@ func RegisterRoutes(router *gin.RouterGroup, sessionRepo sessions.Repository, se
 	// session messages
 	router.GET("/sessions/:id/messages", auth.AuthMiddleware(), GetSessionMessagesHandler(sessionRepo))
 
+	// timeline of chat, AI exchanges and code as a document (host and participants)
+	router.GET("/sessions/:id/transcript", auth.AuthMiddleware(), GetSessionTranscriptHandler(sessionRepo))
+
+	// code snapshots (listing for hosts and co-authors, restoring for the host)
+	router.GET("/sessions/:id/snapshots", auth.AuthMiddleware(), ListSnapshotsHandler(sessionRepo))
+	router.POST("/sessions/:id/restore/:snapshot_id", auth.AuthMiddleware(), RestoreSnapshotHandler(sessionRepo, codeRestorer, auditLog))
+
+	// activity over time (host only)
+	router.GET("/sessions/:id/analytics", auth.AuthMiddleware(), GetSessionAnalyticsHandler(sessionRepo))
+
+	// security-relevant actions taken in the session (host only)
+	router.GET("/sessions/:id/audit", auth.AuthMiddleware(), GetSessionAuditLogHandler(sessionRepo, auditLog))
+
 	// invite tokens (host only)
-	router.POST("/sessions/:id/invite", auth.AuthMiddleware(), CreateInviteTokenHandler(sessionRepo))
+	router.POST("/sessions/:id/invite", auth.AuthMiddleware(), CreateInviteTokenHandler(sessionRepo, userFinder, inviteMailer, notifier, auditLog))
 	router.GET("/sessions/:id/invite", auth.AuthMiddleware(), ListInviteTokensHandler(sessionRepo))
-	router.DELETE("/sessions/:id/invite/:token_id", auth.AuthMiddleware(), RevokeInviteTokenHandler(sessionRepo))
+	router.DELETE("/sessions/:id/invite/:token_id", auth.AuthMiddleware(), RevokeInviteTokenHandler(sessionRepo, auditLog))
 
 	// participants
 	router.GET("/sessions/:id/participants", auth.AuthMiddleware(), ListParticipantsHandler(sessionRepo))
-	router.DELETE("/sessions/:id/participants/:participant_id", auth.AuthMiddleware(), RemoveParticipantHandler(sessionRepo))
-	router.PATCH("/sessions/:id/participants/:participant_id", auth.AuthMiddleware(), UpdateParticipantRoleHandler(sessionRepo))
+	router.DELETE("/sessions/:id/participants/:participant_id", auth.AuthMiddleware(), RemoveParticipantHandler(sessionRepo, auditLog))
+	router.PATCH("/sessions/:id/participants/:participant_id", auth.AuthMiddleware(), UpdateParticipantRoleHandler(sessionRepo, permissionUpdater, auditLog))
+	router.PUT("/sessions/:id/participants/:participant_id/permissions", auth.AuthMiddleware(), UpdateParticipantPermissionsHandler(sessionRepo, permissionUpdater, auditLog))
+	router.DELETE("/sessions/:id/participants/:participant_id/permissions", auth.AuthMiddleware(), ResetParticipantPermissionsHandler(sessionRepo, permissionUpdater, auditLog))
+	router.PUT("/sessions/:id/participants/:participant_id/mute", auth.AuthMiddleware(), MuteParticipantHandler(sessionRepo, chatModerator, auditLog))
+	router.DELETE("/sessions/:id/participants/:participant_id/mute", auth.AuthMiddleware(), UnmuteParticipantHandler(sessionRepo, chatModerator, auditLog))
 
 	// join session (optional auth)
-	router.POST("/sessions/join", auth.OptionalAuthMiddleware(), JoinSessionHandler(sessionRepo))
+	router.POST("/sessions/join", auth.OptionalAuthMiddleware(), JoinSessionHandler(sessionRepo, userFinder))
 }
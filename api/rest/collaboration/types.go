@@ -1,10 +1,17 @@
 package collaboration
 
 import (
+	"context"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/algopatterns/users"
 	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/mailer"
+	"codeberg.org/algopatterns/server/internal/transcript"
 )
 
 // allows ending WebSocket sessions
@@ -12,6 +19,52 @@ type SessionEnder interface {
 	EndSession(sessionID string, reason string)
 }
 
+// replaces the live code of a session (implemented by websocket.Hub)
+type CodeRestorer interface {
+	GetDocument(sessionID string) (string, int, bool)
+	RestoreCode(sessionID, userID, code string) (bool, error)
+}
+
+// applies permission changes to connected clients (implemented by websocket.Hub)
+type PermissionUpdater interface {
+	SetParticipantPermissions(sessionID, participantID string, permissions *sessions.Permission) int
+}
+
+// applies chat moderation to connected clients (implemented by websocket.Hub)
+type ChatModerator interface {
+	SetChatSlowMode(sessionID string, seconds int)
+	SetParticipantMuted(sessionID, participantID string, muted bool) int
+}
+
+// queues webhook events in the transaction of the write they report (implemented by webhooks.Service)
+type EventPublisher interface {
+	PublishTx(ctx context.Context, tx pgx.Tx, userID, event string, data any) error
+}
+
+// emails session invitations (implemented by mailer.Service)
+type InviteMailer interface {
+	SendInvite(ctx context.Context, invite mailer.Invite) error
+	InviteURL(token string) string
+}
+
+// notifies users in-app in the transaction of the write it's about (implemented by notifications.Service)
+type Notifier interface {
+	NotifyTx(ctx context.Context, tx pgx.Tx, userID, kind, message string, data any) error
+}
+
+// looks up users for email-bound invites (implemented by users.Repository)
+type UserFinder interface {
+	FindByID(ctx context.Context, userID string) (*users.User, error)
+	FindByEmail(ctx context.Context, email string) (*users.User, error)
+	HasVerifiedEmail(ctx context.Context, userID, email string) (bool, error)
+}
+
+// records and reads security-relevant actions (implemented by audit.Service)
+type AuditLog interface {
+	Record(ctx context.Context, event audit.Event)
+	List(ctx context.Context, filter audit.ListFilter, limit, offset int) ([]audit.Entry, int, error)
+}
+
 type CreateSessionRequest struct {
 	Title          string `json:"title" binding:"required,max=200"`
 	Code           string `json:"code" binding:"max=1048576"` // 1MB limit
@@ -47,6 +100,8 @@ type ParticipantResponse struct {
 	UserID      *string    `json:"user_id,omitempty"`
 	DisplayName *string    `json:"display_name,omitempty"`
 	Role        string     `json:"role"`
+	Permissions []string   `json:"permissions"`
+	Muted       bool       `json:"muted"`
 	Status      string     `json:"status"`
 	JoinedAt    time.Time  `json:"joined_at"`
 	LeftAt      *time.Time `json:"left_at,omitempty"`
@@ -60,6 +115,7 @@ type CreateInviteTokenRequest struct {
 	Role      string     `json:"role" binding:"required,oneof=co-author viewer"`
 	MaxUses   *int       `json:"max_uses,omitempty"`
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Email     string     `json:"email,omitempty" binding:"omitempty,email,max=254"` // emails a single-use invite only this address can accept
 }
 
 type InviteTokenResponse struct {
@@ -70,6 +126,8 @@ type InviteTokenResponse struct {
 	MaxUses   *int       `json:"max_uses,omitempty"`
 	UsesCount int        `json:"uses_count"`
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Email     *string    `json:"email,omitempty"`
+	EmailSent *bool      `json:"email_sent,omitempty"` // set when the invite was created; false if the invitee opted out or delivery failed
 	CreatedAt time.Time  `json:"created_at"`
 }
 
@@ -86,12 +144,14 @@ type JoinSessionResponse struct {
 
 // SessionsListResponse wraps a list of sessions
 type SessionsListResponse struct {
-	Sessions []SessionResponse `json:"sessions"`
+	Sessions   []SessionResponse     `json:"sessions"`
+	Pagination pagination.CursorMeta `json:"pagination"`
 }
 
 // ParticipantsListResponse wraps a list of participants
 type ParticipantsListResponse struct {
 	Participants []ParticipantResponse `json:"participants"`
+	Pagination   pagination.CursorMeta `json:"pagination"`
 }
 
 // InviteTokensListResponse wraps a list of invite tokens
@@ -110,15 +170,32 @@ type UpdateSessionCodeResponse struct {
 	Code    string `json:"code"`
 }
 
+// UpdateRoleRequest changes a participant's role
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=co-author viewer" enums:"co-author,viewer"`
+}
+
 // UpdateRoleResponse returned after updating participant role
 type UpdateRoleResponse struct {
 	Message string `json:"message"`
 	Role    string `json:"role"`
 }
 
+type UpdatePermissionsRequest struct {
+	Permissions []string `json:"permissions" binding:"required,max=10,dive,max=50"` // empty to allow nothing
+}
+
+// UpdatePermissionsResponse returned after changing participant permissions
+type UpdatePermissionsResponse struct {
+	Message     string   `json:"message"`
+	Permissions []string `json:"permissions"`
+	Custom      bool     `json:"custom"` // false when the role preset applies
+}
+
 // MessagesResponse wraps chat messages
 type MessagesResponse struct {
-	Messages []*sessions.Message `json:"messages"`
+	Messages   []*sessions.Message   `json:"messages"`
+	Pagination pagination.CursorMeta `json:"pagination"`
 }
 
 // SetDiscoverableRequest for updating session discoverability
@@ -126,6 +203,70 @@ type SetDiscoverableRequest struct {
 	IsDiscoverable bool `json:"is_discoverable"`
 }
 
+// ExpiryPolicyRequest replaces the expiry policy of a session
+type ExpiryPolicyRequest struct {
+	AutoEndIdleHours   *int `json:"auto_end_idle_hours" binding:"omitempty,min=1,max=168"` // null uses the server default
+	RevokeInvitesOnEnd bool `json:"revoke_invites_on_end"`
+	PublishOnEnd       bool `json:"publish_on_end"`
+}
+
+// bucket sizes the analytics series can be rolled up into
+var analyticsBuckets = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// most buckets returned in one analytics series; earlier activity needs a
+// larger bucket
+const maxAnalyticsBuckets = 1000
+
+// most entries rendered in a transcript, later ones are left out
+const maxTranscriptEntries = 5000
+
+// content types of the transcript formats
+var transcriptContentTypes = map[string]string{
+	transcript.FormatMarkdown: "text/markdown; charset=utf-8",
+	transcript.FormatHTML:     "text/html; charset=utf-8",
+}
+
+// SessionAnalyticsResponse is the activity of a session over time
+type SessionAnalyticsResponse struct {
+	SessionID     string                     `json:"session_id"`
+	BucketSeconds int                        `json:"bucket_seconds"`
+	Since         time.Time                  `json:"since"`
+	Series        []*sessions.ActivitySample `json:"series"` // buckets without activity are left out
+	Totals        AnalyticsTotals            `json:"totals"`
+}
+
+// AnalyticsTotals sums up the series of a session's analytics
+type AnalyticsTotals struct {
+	PeakParticipants int     `json:"peak_participants"`
+	PeakListeners    int     `json:"peak_listeners"`
+	CodeEdits        int     `json:"code_edits"`
+	AgentRequests    int     `json:"agent_requests"`
+	ChatMessages     int     `json:"chat_messages"`
+	PlaybackSeconds  float64 `json:"playback_seconds"`
+}
+
+// ChatSettingsRequest for updating the chat settings of a session
+type ChatSettingsRequest struct {
+	SlowModeSeconds *int `json:"slow_mode_seconds" binding:"required,min=0,max=3600"` // 0 turns slow mode off
+}
+
+// ChatSettingsResponse contains the chat settings of a session
+type ChatSettingsResponse struct {
+	SlowModeSeconds int `json:"slow_mode_seconds"`
+}
+
+// MuteResponse contains whether a participant is muted
+type MuteResponse struct {
+	Message string `json:"message"`
+	Muted   bool   `json:"muted"`
+}
+
 // LiveSessionResponse for public listing of discoverable sessions
 type LiveSessionResponse struct {
 	ID               string    `json:"id"`
@@ -140,7 +281,7 @@ type LiveSessionResponse struct {
 // LiveSessionsListResponse wraps a list of live sessions with pagination
 type LiveSessionsListResponse struct {
 	Sessions   []LiveSessionResponse `json:"sessions"`
-	Pagination pagination.Meta       `json:"pagination"`
+	Pagination pagination.CursorMeta `json:"pagination"`
 }
 
 // SoftEndSessionResponse returned after soft-ending a session
@@ -157,3 +298,15 @@ type IsLiveResponse struct {
 	HasActiveInviteTokens bool `json:"has_active_invite_tokens"`
 	IsDiscoverable        bool `json:"is_discoverable"`
 }
+
+// SnapshotsListResponse wraps a session's snapshots with pagination
+type SnapshotsListResponse struct {
+	Snapshots  []*sessions.Snapshot `json:"snapshots"`
+	Pagination pagination.Meta      `json:"pagination"`
+}
+
+// AuditLogResponse wraps a session's audit entries with pagination
+type AuditLogResponse struct {
+	Entries    []audit.Entry   `json:"entries"`
+	Pagination pagination.Meta `json:"pagination"`
+}
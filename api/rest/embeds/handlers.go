@@ -0,0 +1,186 @@
+package embeds
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/embeds"
+	"codeberg.org/algopatterns/server/internal/errors"
+)
+
+// CreateSessionEmbedTokenHandler godoc
+// @Summary Create session embed token
+// @Description Create a revocable token granting a read-only view of an active session (code and play state only). Host only.
+// @Tags embeds
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Success 201 {object} embeds.Token
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/embed-token [post]
+// @Security BearerAuth
+func CreateSessionEmbedTokenHandler(embedService *embeds.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := ownerAndPathID(c)
+		if !ok {
+			return
+		}
+
+		token, err := embedService.CreateForSession(c.Request.Context(), userID, sessionID)
+		if err != nil {
+			handleEmbedError(c, err, "session", "failed to create embed token")
+			return
+		}
+
+		c.JSON(http.StatusCreated, token)
+	}
+}
+
+// CreateStrudelEmbedTokenHandler godoc
+// @Summary Create strudel embed token
+// @Description Create a revocable token granting a read-only view of a strudel, public or not. Owner only.
+// @Tags embeds
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Success 201 {object} embeds.Token
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/embed-token [post]
+// @Security BearerAuth
+func CreateStrudelEmbedTokenHandler(embedService *embeds.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, strudelID, ok := ownerAndPathID(c)
+		if !ok {
+			return
+		}
+
+		token, err := embedService.CreateForStrudel(c.Request.Context(), userID, strudelID)
+		if err != nil {
+			handleEmbedError(c, err, "strudel", "failed to create embed token")
+			return
+		}
+
+		c.JSON(http.StatusCreated, token)
+	}
+}
+
+// ListEmbedTokensHandler godoc
+// @Summary List embed tokens
+// @Description Get the authenticated user's active embed tokens
+// @Tags embeds
+// @Produce json
+// @Success 200 {object} EmbedTokensListResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/embed-tokens [get]
+// @Security BearerAuth
+func ListEmbedTokensHandler(embedService *embeds.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		tokens, err := embedService.List(c.Request.Context(), userID)
+		if err != nil {
+			errors.InternalError(c, "failed to list embed tokens", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, EmbedTokensListResponse{Tokens: tokens})
+	}
+}
+
+// RevokeEmbedTokenHandler godoc
+// @Summary Revoke embed token
+// @Description Revoke an embed token. Embeds using it stop loading and can no longer open a connection.
+// @Tags embeds
+// @Produce json
+// @Param id path string true "Embed token ID (UUID)"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/embed-tokens/{id} [delete]
+// @Security BearerAuth
+func RevokeEmbedTokenHandler(embedService *embeds.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, tokenID, ok := ownerAndPathID(c)
+		if !ok {
+			return
+		}
+
+		if err := embedService.Revoke(c.Request.Context(), tokenID, userID); err != nil {
+			handleEmbedError(c, err, "embed token", "failed to revoke embed token")
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "embed token revoked successfully"})
+	}
+}
+
+// GetEmbedHandler godoc
+// @Summary Get embed
+// @Description Get the read-only view an embed token grants: title, current code and whether the session is live. Never includes chat or participants.
+// @Tags embeds
+// @Produce json
+// @Param token path string true "Embed token"
+// @Success 200 {object} embeds.View
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/embed/{token} [get]
+func GetEmbedHandler(embedService *embeds.Service, documents LiveDocuments) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		view, err := embedService.Resolve(c.Request.Context(), c.Param("token"))
+		if err != nil {
+			handleEmbedError(c, err, "embed", "failed to load embed")
+			return
+		}
+
+		// the hub holds newer code than the database while a session is live
+		if view.Kind == embeds.KindSession && view.IsLive {
+			if code, _, live := documents.GetDocument(view.TargetID); live {
+				view.Code = code
+			}
+		}
+
+		// revocation has to take effect on the next load
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, view)
+	}
+}
+
+func ownerAndPathID(c *gin.Context) (userID, id string, ok bool) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return "", "", false
+	}
+
+	id, ok = errors.ValidatePathUUID(c, "id")
+	return userID, id, ok
+}
+
+// maps embed service errors to responses
+func handleEmbedError(c *gin.Context, err error, resource, message string) {
+	switch {
+	case stderrors.Is(err, embeds.ErrTargetNotFound):
+		errors.NotFound(c, resource)
+	case stderrors.Is(err, embeds.ErrTokenNotFound):
+		errors.NotFound(c, resource)
+	case stderrors.Is(err, embeds.ErrTooManyTokens):
+		errors.Conflict(c, "embed token limit reached")
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
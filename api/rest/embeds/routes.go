@@ -0,0 +1,19 @@
+package embeds
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/embeds"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, embedService *embeds.Service, documents LiveDocuments) {
+	// token management, owner only
+	router.POST("/sessions/:id/embed-token", auth.AuthMiddleware(), CreateSessionEmbedTokenHandler(embedService))
+	router.POST("/strudels/:id/embed-token", auth.AuthMiddleware(), CreateStrudelEmbedTokenHandler(embedService))
+	router.GET("/embed-tokens", auth.AuthMiddleware(), ListEmbedTokensHandler(embedService))
+	router.DELETE("/embed-tokens/:id", auth.AuthMiddleware(), RevokeEmbedTokenHandler(embedService))
+
+	// public read-only view, the token is the credential
+	router.GET("/embed/:token", GetEmbedHandler(embedService, documents))
+}
@@ -0,0 +1,18 @@
+package embeds
+
+import "codeberg.org/algopatterns/server/internal/embeds"
+
+// LiveDocuments reads the in-memory code of live sessions
+type LiveDocuments interface {
+	GetDocument(sessionID string) (string, int, bool)
+}
+
+// EmbedTokensListResponse wraps a user's active embed tokens
+type EmbedTokensListResponse struct {
+	Tokens []*embeds.Token `json:"tokens"`
+}
+
+// MessageResponse for simple success messages
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,162 @@
+package engagement
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/engagement"
+	"codeberg.org/algopatterns/server/internal/errors"
+)
+
+// LikeStrudelHandler godoc
+// @Summary Like strudel
+// @Description Like a public strudel. Liking it again changes nothing
+// @Tags engagement
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Success 200 {object} engagement.LikeStatus
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/like [post]
+// @Security BearerAuth
+func LikeStrudelHandler(engagementService *engagement.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, strudelID, ok := userAndStrudelID(c)
+		if !ok {
+			return
+		}
+
+		status, err := engagementService.Like(c.Request.Context(), strudelID, userID)
+		if err != nil {
+			handleEngagementError(c, err, "failed to like strudel")
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// UnlikeStrudelHandler godoc
+// @Summary Unlike strudel
+// @Description Take back your like of a public strudel
+// @Tags engagement
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Success 200 {object} engagement.LikeStatus
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/like [delete]
+// @Security BearerAuth
+func UnlikeStrudelHandler(engagementService *engagement.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, strudelID, ok := userAndStrudelID(c)
+		if !ok {
+			return
+		}
+
+		status, err := engagementService.Unlike(c.Request.Context(), strudelID, userID)
+		if err != nil {
+			handleEngagementError(c, err, "failed to unlike strudel")
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// RecordPlayHandler godoc
+// @Summary Record play
+// @Description Count a play of a public strudel. Plays by the same user (or address, when not signed in) within 30 minutes count once
+// @Tags engagement
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Success 200 {object} PlayResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/play [post]
+func RecordPlayHandler(engagementService *engagement.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		userID, _ := auth.GetUserID(c)
+
+		counted, err := engagementService.RecordPlay(c.Request.Context(), strudelID, userID, c.ClientIP())
+		if err != nil {
+			handleEngagementError(c, err, "failed to record play")
+			return
+		}
+
+		c.JSON(http.StatusOK, PlayResponse{Counted: counted})
+	}
+}
+
+// ListTrendingHandler godoc
+// @Summary List trending strudels
+// @Description Public strudels ranked by engagement over the last 7 days (a fork counts 5, a like 3, a play 1), decaying with age. Rankings and counts are refreshed every 5 minutes
+// @Tags engagement
+// @Produce json
+// @Param limit query int false "Max results (default 20, max 100)"
+// @Param offset query int false "Results to skip"
+// @Success 200 {object} TrendingResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/trending [get]
+func ListTrendingHandler(engagementService *engagement.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, offset := pagination(c)
+
+		trending, err := engagementService.Trending(c.Request.Context(), limit, offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list trending strudels", err)
+			return
+		}
+
+		c.Header("Cache-Control", "public, max-age=60")
+		c.JSON(http.StatusOK, TrendingResponse{Strudels: trending, Limit: limit, Offset: offset})
+	}
+}
+
+func userAndStrudelID(c *gin.Context) (userID, strudelID string, ok bool) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return "", "", false
+	}
+
+	strudelID, ok = errors.ValidatePathUUID(c, "id")
+	return userID, strudelID, ok
+}
+
+// limit and offset query parameters, clamped to the allowed page sizes
+func pagination(c *gin.Context) (limit, offset int) {
+	limit = engagement.DefaultTrendingLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, engagement.MaxTrendingLimit)
+	}
+
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o > 0 {
+		offset = o
+	}
+
+	return limit, offset
+}
+
+func handleEngagementError(c *gin.Context, err error, message string) {
+	if stderrors.Is(err, engagement.ErrStrudelNotFound) {
+		errors.NotFound(c, "strudel")
+		return
+	}
+
+	errors.InternalError(c, message, err)
+}
@@ -0,0 +1,34 @@
+package engagement
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query  string
+		limit  int
+		offset int
+	}{
+		{"", 20, 0},
+		{"?limit=5&offset=10", 5, 10},
+		{"?limit=1000", 100, 0},
+		{"?limit=-3&offset=-1", 20, 0},
+		{"?limit=abc", 20, 0},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/v1/strudels/trending"+tt.query, nil)
+
+		limit, offset := pagination(c)
+		assert.Equal(t, tt.limit, limit, tt.query)
+		assert.Equal(t, tt.offset, offset, tt.query)
+	}
+}
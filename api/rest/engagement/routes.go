@@ -0,0 +1,19 @@
+package engagement
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/engagement"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, engagementService *engagement.Service) {
+	// trending public strudels (no auth required)
+	router.GET("/strudels/trending", ListTrendingHandler(engagementService))
+
+	router.POST("/strudels/:id/like", auth.AuthMiddleware(), LikeStrudelHandler(engagementService))
+	router.DELETE("/strudels/:id/like", auth.AuthMiddleware(), UnlikeStrudelHandler(engagementService))
+
+	// plays are counted for anyone, debounced per user or address
+	router.POST("/strudels/:id/play", auth.OptionalAuthMiddleware(), RecordPlayHandler(engagementService))
+}
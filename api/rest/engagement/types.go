@@ -0,0 +1,13 @@
+package engagement
+
+import "codeberg.org/algopatterns/server/internal/engagement"
+
+type PlayResponse struct {
+	Counted bool `json:"counted"` // false when the same listener played it in the last 30 minutes
+}
+
+type TrendingResponse struct {
+	Strudels []engagement.TrendingStrudel `json:"strudels"`
+	Limit    int                          `json:"limit"`
+	Offset   int                          `json:"offset"`
+}
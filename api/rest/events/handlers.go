@@ -0,0 +1,289 @@
+package events
+
+import (
+	"bytes"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/events"
+)
+
+// CreateEventHandler godoc
+// @Summary Schedule event
+// @Description Schedule a public performance in an active session you host. When it starts, the session becomes discoverable and everyone who RSVPed is notified through their webhooks (event.live).
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param request body CreateEventRequest true "Session, title and start time"
+// @Success 201 {object} events.Event
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/events [post]
+// @Security BearerAuth
+func CreateEventHandler(eventService *events.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		var req CreateEventRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		event, err := eventService.Create(c.Request.Context(), userID, events.CreateEventRequest{
+			SessionID:   req.SessionID,
+			Title:       strings.TrimSpace(req.Title),
+			Description: req.Description,
+			StartsAt:    req.StartsAt,
+		})
+		if err != nil {
+			handleEventError(c, err, "failed to schedule event")
+			return
+		}
+
+		c.JSON(http.StatusCreated, event)
+	}
+}
+
+// ListUpcomingEventsHandler godoc
+// @Summary List upcoming events
+// @Description Browse scheduled and live performances, soonest first
+// @Tags events
+// @Produce json
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} EventsListResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/events [get]
+func ListUpcomingEventsHandler(eventService *events.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		viewerID, _ := auth.GetUserID(c)
+
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 20, 100)
+
+		list, total, err := eventService.ListUpcoming(c.Request.Context(), viewerID, params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list events", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, EventsListResponse{
+			Events:     list,
+			Pagination: pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// CalendarFeedHandler godoc
+// @Summary Calendar feed
+// @Description iCalendar feed of upcoming performances for calendar apps to subscribe to. Pass user_id for the performances of one host. Cancelled events stay in the feed, marked cancelled, until they would have started.
+// @Tags events
+// @Produce text/calendar
+// @Param user_id query string false "Only events hosted by this user (UUID)"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/events/feed.ics [get]
+func CalendarFeedHandler(eventService *events.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hostUserID := c.Query("user_id")
+		if hostUserID != "" && !errors.IsValidUUID(hostUserID) {
+			errors.BadRequest(c, "invalid user_id", nil)
+			return
+		}
+
+		list, err := eventService.ListFeed(c.Request.Context(), hostUserID)
+		if err != nil {
+			errors.InternalError(c, "failed to list events", err)
+			return
+		}
+
+		name := "algopatterns events"
+		if hostUserID != "" && len(list) > 0 && list[0].HostName != "" {
+			name = list[0].HostName + " on algopatterns"
+		}
+
+		var buf bytes.Buffer
+		if err := events.WriteCalendar(&buf, name, list); err != nil {
+			errors.InternalError(c, "failed to write calendar", err)
+			return
+		}
+
+		c.Header("Cache-Control", "public, max-age=300")
+		c.Header("Content-Disposition", `inline; filename="events.ics"`)
+		c.Data(http.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+	}
+}
+
+// GetEventHandler godoc
+// @Summary Get event
+// @Description Get a scheduled performance
+// @Tags events
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Success 200 {object} events.Event
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/events/{id} [get]
+func GetEventHandler(eventService *events.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		viewerID, _ := auth.GetUserID(c)
+
+		event, err := eventService.Get(c.Request.Context(), eventID, viewerID)
+		if err != nil {
+			handleEventError(c, err, "failed to get event")
+			return
+		}
+
+		c.JSON(http.StatusOK, event)
+	}
+}
+
+// CancelEventHandler godoc
+// @Summary Cancel event
+// @Description Cancel a scheduled performance you host
+// @Tags events
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Router /api/v1/events/{id} [delete]
+// @Security BearerAuth
+func CancelEventHandler(eventService *events.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, eventID, ok := userAndEventID(c)
+		if !ok {
+			return
+		}
+
+		if err := eventService.Cancel(c.Request.Context(), eventID, userID); err != nil {
+			handleEventError(c, err, "failed to cancel event")
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "event cancelled successfully"})
+	}
+}
+
+// RSVPHandler godoc
+// @Summary RSVP to event
+// @Description Get notified through your webhooks (event.live) when the performance starts
+// @Tags events
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Router /api/v1/events/{id}/rsvp [post]
+// @Security BearerAuth
+func RSVPHandler(eventService *events.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, eventID, ok := userAndEventID(c)
+		if !ok {
+			return
+		}
+
+		if err := eventService.RSVP(c.Request.Context(), eventID, userID); err != nil {
+			handleEventError(c, err, "failed to RSVP")
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "RSVP saved"})
+	}
+}
+
+// CancelRSVPHandler godoc
+// @Summary Withdraw RSVP
+// @Description Stop being notified when the performance starts
+// @Tags events
+// @Produce json
+// @Param id path string true "Event ID (UUID)"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Router /api/v1/events/{id}/rsvp [delete]
+// @Security BearerAuth
+func CancelRSVPHandler(eventService *events.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, eventID, ok := userAndEventID(c)
+		if !ok {
+			return
+		}
+
+		if err := eventService.CancelRSVP(c.Request.Context(), eventID, userID); err != nil {
+			errors.InternalError(c, "failed to withdraw RSVP", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "RSVP withdrawn"})
+	}
+}
+
+func parsePaginationParams(c *gin.Context) (limit, offset int) {
+	if l, ok := c.GetQuery("limit"); ok {
+		if parsedLimit, err := strconv.Atoi(l); err == nil {
+			limit = parsedLimit
+		}
+	}
+	if o, ok := c.GetQuery("offset"); ok {
+		if parsedOffset, err := strconv.Atoi(o); err == nil {
+			offset = parsedOffset
+		}
+	}
+	return limit, offset
+}
+
+// gets the authenticated user and the event id path param.
+// writes the error response and returns false if either is missing.
+func userAndEventID(c *gin.Context) (userID, eventID string, ok bool) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return "", "", false
+	}
+
+	eventID, ok = errors.ValidatePathUUID(c, "id")
+	return userID, eventID, ok
+}
+
+// maps event service errors to responses
+func handleEventError(c *gin.Context, err error, message string) {
+	switch {
+	case stderrors.Is(err, events.ErrEventNotFound):
+		errors.NotFound(c, "event")
+	case stderrors.Is(err, events.ErrSessionNotFound):
+		errors.NotFound(c, "session")
+	case stderrors.Is(err, events.ErrTooManyEvents),
+		stderrors.Is(err, events.ErrNotUpcoming):
+		errors.Conflict(c, err.Error())
+	case stderrors.Is(err, events.ErrInvalidStart):
+		errors.BadRequest(c, err.Error(), nil)
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
@@ -0,0 +1,23 @@
+package events
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/events"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, eventService *events.Service) {
+	// browsing is public (optional auth - marks the user's RSVPs if authenticated)
+	router.GET("/events", auth.OptionalAuthMiddleware(), ListUpcomingEventsHandler(eventService))
+	router.GET("/events/feed.ics", CalendarFeedHandler(eventService))
+	router.GET("/events/:id", auth.OptionalAuthMiddleware(), GetEventHandler(eventService))
+
+	// scheduling (host only)
+	router.POST("/events", auth.AuthMiddleware(), CreateEventHandler(eventService))
+	router.DELETE("/events/:id", auth.AuthMiddleware(), CancelEventHandler(eventService))
+
+	// RSVPs
+	router.POST("/events/:id/rsvp", auth.AuthMiddleware(), RSVPHandler(eventService))
+	router.DELETE("/events/:id/rsvp", auth.AuthMiddleware(), CancelRSVPHandler(eventService))
+}
@@ -0,0 +1,26 @@
+package events
+
+import (
+	"time"
+
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/events"
+)
+
+type CreateEventRequest struct {
+	SessionID   string    `json:"session_id" binding:"required,uuid"`
+	Title       string    `json:"title" binding:"required,min=1,max=200"`
+	Description string    `json:"description,omitempty" binding:"max=2000"`
+	StartsAt    time.Time `json:"starts_at" binding:"required"`
+}
+
+// EventsListResponse wraps upcoming events with pagination
+type EventsListResponse struct {
+	Events     []*events.Event `json:"events"`
+	Pagination pagination.Meta `json:"pagination"`
+}
+
+// MessageResponse for simple success messages
+type MessageResponse struct {
+	Message string `json:"message"`
+}
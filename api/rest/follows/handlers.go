@@ -0,0 +1,238 @@
+package follows
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/follows"
+)
+
+// FollowUserHandler godoc
+// @Summary Follow user
+// @Description Follow a user to see their publishes, forks and scheduled performances in your feed. Following them again changes nothing
+// @Tags follows
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} follows.FollowStatus
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/users/{id}/follow [post]
+// @Security BearerAuth
+func FollowUserHandler(followService *follows.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, followeeID, ok := userAndTargetID(c)
+		if !ok {
+			return
+		}
+
+		status, err := followService.Follow(c.Request.Context(), userID, followeeID)
+		if err != nil {
+			handleFollowError(c, err, "failed to follow user")
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// UnfollowUserHandler godoc
+// @Summary Unfollow user
+// @Description Stop following a user
+// @Tags follows
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} follows.FollowStatus
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/users/{id}/follow [delete]
+// @Security BearerAuth
+func UnfollowUserHandler(followService *follows.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, followeeID, ok := userAndTargetID(c)
+		if !ok {
+			return
+		}
+
+		status, err := followService.Unfollow(c.Request.Context(), userID, followeeID)
+		if err != nil {
+			handleFollowError(c, err, "failed to unfollow user")
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// ListFollowersHandler godoc
+// @Summary List followers
+// @Description List the users following a user, most recent first
+// @Tags follows
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param limit query int false "Max results (default 20, max 100)"
+// @Param cursor query string false "Cursor from the previous page's pagination.next_cursor"
+// @Success 200 {object} FollowsResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/users/{id}/followers [get]
+func ListFollowersHandler(followService *follows.Service) gin.HandlerFunc {
+	return listFollowsHandler(followService.Followers, "failed to list followers")
+}
+
+// ListFollowingHandler godoc
+// @Summary List followed users
+// @Description List the users a user follows, most recently followed first
+// @Tags follows
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param limit query int false "Max results (default 20, max 100)"
+// @Param cursor query string false "Cursor from the previous page's pagination.next_cursor"
+// @Success 200 {object} FollowsResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/users/{id}/following [get]
+func ListFollowingHandler(followService *follows.Service) gin.HandlerFunc {
+	return listFollowsHandler(followService.Following, "failed to list followed users")
+}
+
+// GetFeedHandler godoc
+// @Summary Get activity feed
+// @Description Public strudels published and forked, and performances scheduled, by the users you follow, newest first
+// @Tags follows
+// @Produce json
+// @Param limit query int false "Max results (default 20, max 100)"
+// @Param cursor query string false "Cursor from the previous page's pagination.next_cursor"
+// @Success 200 {object} FeedResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/feed [get]
+// @Security BearerAuth
+func GetFeedHandler(followService *follows.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		limit, after, ok := pageParams(c)
+		if !ok {
+			return
+		}
+
+		items, next, err := followService.Feed(c.Request.Context(), userID, limit, after)
+		if err != nil {
+			errors.InternalError(c, "failed to load feed", err)
+			return
+		}
+
+		meta, ok := cursorMeta(c, limit, next)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, FeedResponse{Items: items, Pagination: meta})
+	}
+}
+
+type listFollowsFunc func(ctx context.Context, userID string, limit int, after *follows.Cursor) ([]follows.Follow, *follows.Cursor, error)
+
+func listFollowsHandler(list listFollowsFunc, message string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		limit, after, ok := pageParams(c)
+		if !ok {
+			return
+		}
+
+		users, next, err := list(c.Request.Context(), userID, limit, after)
+		if err != nil {
+			handleFollowError(c, err, message)
+			return
+		}
+
+		meta, ok := cursorMeta(c, limit, next)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, FollowsResponse{Users: users, Pagination: meta})
+	}
+}
+
+func userAndTargetID(c *gin.Context) (userID, targetID string, ok bool) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return "", "", false
+	}
+
+	targetID, ok = errors.ValidatePathUUID(c, "id")
+	return userID, targetID, ok
+}
+
+// limit and cursor query parameters. the limit is clamped to the allowed page
+// sizes; an invalid cursor is rejected
+func pageParams(c *gin.Context) (limit int, after *follows.Cursor, ok bool) {
+	limit = follows.DefaultPageLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, follows.MaxPageLimit)
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		after = &follows.Cursor{}
+		if err := pagination.DecodeCursor(cursor, after); err != nil || !errors.IsValidUUID(after.ID) {
+			errors.BadRequest(c, "invalid cursor", err)
+			return 0, nil, false
+		}
+	}
+
+	return limit, after, true
+}
+
+func cursorMeta(c *gin.Context, limit int, next *follows.Cursor) (pagination.CursorMeta, bool) {
+	meta := pagination.CursorMeta{Limit: limit}
+	if next == nil {
+		return meta, true
+	}
+
+	cursor, err := pagination.EncodeCursor(next)
+	if err != nil {
+		errors.InternalError(c, "failed to encode cursor", err)
+		return meta, false
+	}
+
+	meta.NextCursor = cursor
+	meta.HasMore = true
+
+	return meta, true
+}
+
+func handleFollowError(c *gin.Context, err error, message string) {
+	switch {
+	case stderrors.Is(err, follows.ErrUserNotFound):
+		errors.NotFound(c, "user")
+	case stderrors.Is(err, follows.ErrFollowSelf):
+		errors.BadRequest(c, "you cannot follow yourself", nil)
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
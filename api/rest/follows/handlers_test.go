@@ -0,0 +1,65 @@
+package follows
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/follows"
+)
+
+func TestPageParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	position := follows.Cursor{
+		Time: time.Date(2026, 2, 22, 12, 0, 0, 0, time.UTC),
+		ID:   "3f2b8c1e-0d4a-4b6e-9a57-8c2d1e0f4a6b",
+	}
+	cursor, err := pagination.EncodeCursor(position)
+	require.NoError(t, err)
+
+	notUUID, err := pagination.EncodeCursor(follows.Cursor{Time: position.Time, ID: "1; DROP TABLE users"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		query string
+		limit int
+		after *follows.Cursor
+		ok    bool
+	}{
+		{"", 20, nil, true},
+		{"?limit=5", 5, nil, true},
+		{"?limit=1000", 100, nil, true},
+		{"?limit=-3", 20, nil, true},
+		{"?cursor=" + cursor, 20, &position, true},
+		{"?cursor=not-a-cursor", 0, nil, false},
+		{"?cursor=" + notUUID, 0, nil, false},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/feed"+tt.query, nil)
+
+		limit, after, ok := pageParams(c)
+		assert.Equal(t, tt.ok, ok, tt.query)
+		if !tt.ok {
+			assert.Equal(t, 400, w.Code, tt.query)
+			continue
+		}
+
+		assert.Equal(t, tt.limit, limit, tt.query)
+		if tt.after == nil {
+			assert.Nil(t, after, tt.query)
+		} else {
+			require.NotNil(t, after, tt.query)
+			assert.True(t, tt.after.Time.Equal(after.Time), tt.query)
+			assert.Equal(t, tt.after.ID, after.ID, tt.query)
+		}
+	}
+}
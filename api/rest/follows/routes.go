@@ -0,0 +1,20 @@
+package follows
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/follows"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, followService *follows.Service) {
+	// follower lists are public
+	router.GET("/users/:id/followers", ListFollowersHandler(followService))
+	router.GET("/users/:id/following", ListFollowingHandler(followService))
+
+	router.POST("/users/:id/follow", auth.AuthMiddleware(), FollowUserHandler(followService))
+	router.DELETE("/users/:id/follow", auth.AuthMiddleware(), UnfollowUserHandler(followService))
+
+	// activity of the users you follow
+	router.GET("/feed", auth.AuthMiddleware(), GetFeedHandler(followService))
+}
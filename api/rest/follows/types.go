@@ -0,0 +1,16 @@
+package follows
+
+import (
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/follows"
+)
+
+type FollowsResponse struct {
+	Users      []follows.Follow      `json:"users"`
+	Pagination pagination.CursorMeta `json:"pagination"`
+}
+
+type FeedResponse struct {
+	Items      []follows.FeedItem    `json:"items"`
+	Pagination pagination.CursorMeta `json:"pagination"`
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/health"
 )
 
 // Handler godoc
@@ -33,3 +35,38 @@ func PingHandler(c *gin.Context) {
 		Message: "pong",
 	})
 }
+
+// LivenessHandler godoc
+// @Summary Liveness probe
+// @Description Reports that the process is up and serving requests. Doesn't check dependencies, so a database outage doesn't get instances restarted.
+// @Tags health
+// @Produce json
+// @Success 200 {object} Response
+// @Router /healthz [get]
+func LivenessHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{
+		Status:  health.StatusOK,
+		Service: "algopatterns",
+	})
+}
+
+// ReadinessHandler godoc
+// @Summary Readiness probe
+// @Description Reports whether the instance should receive traffic, with the status of Postgres, Redis and the LLM providers. Results are cached for a few seconds. Returns 503 when Postgres or Redis is down and while the instance drains or shuts down; an unreachable LLM provider only marks the instance degraded.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func ReadinessHandler(checker ReadinessChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := checker.Check(c.Request.Context())
+
+		status := http.StatusOK
+		if !report.Ready {
+			status = http.StatusServiceUnavailable
+		}
+
+		c.JSON(status, report)
+	}
+}
@@ -1,5 +1,16 @@
 package health
 
+import (
+	"context"
+
+	"codeberg.org/algopatterns/server/internal/health"
+)
+
+// checks the instance's dependencies (implemented by health.Checker)
+type ReadinessChecker interface {
+	Check(ctx context.Context) health.Report
+}
+
 type Response struct {
 	Status  string `json:"status"`
 	Service string `json:"service"`
@@ -0,0 +1,344 @@
+package joinrequests
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/joinrequests"
+	"codeberg.org/algopatterns/server/internal/notifications"
+	ws "codeberg.org/algopatterns/server/internal/websocket"
+)
+
+// CreateJoinRequestHandler godoc
+// @Summary Knock on a session
+// @Description Ask the host to let you join an active session. The host is told live (join_request WebSocket message) and in-app, and has 10 minutes to answer. Knocking again while a request is open refreshes it. Once approved, connect to the session's WebSocket with your JWT.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body CreateJoinRequestRequest false "Name and note shown to the host"
+// @Success 201 {object} joinrequests.Request
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 429 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/join-requests [post]
+// @Security BearerAuth
+func CreateJoinRequestHandler(sessionRepo sessions.Repository, joinRequests *joinrequests.Service, userFinder UserFinder, hosts HostMessenger, notifier Notifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := userAndSessionID(c)
+		if !ok {
+			return
+		}
+
+		// the body is optional
+		var req CreateJoinRequestRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !stderrors.Is(err, io.EOF) {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.SessionNotFound(c)
+			return
+		}
+
+		if !session.IsActive {
+			errors.Forbidden(c, "session has ended")
+			return
+		}
+
+		if session.HostUserID == userID {
+			errors.BadRequest(c, "you are the host of this session", nil)
+			return
+		}
+
+		displayName := req.DisplayName
+		if displayName == "" {
+			if user, err := userFinder.FindByID(c.Request.Context(), userID); err == nil && user.Name != "" {
+				displayName = user.Name
+			} else {
+				displayName = "User"
+			}
+		}
+
+		ctx := c.Request.Context()
+		request, err := joinRequests.Create(ctx, joinrequests.CreateRequest{
+			SessionID:   sessionID,
+			UserID:      userID,
+			DisplayName: displayName,
+			Message:     req.Message,
+		}, func(tx pgx.Tx, request *joinrequests.Request) error {
+			return notifier.NotifyTx(ctx, tx, session.HostUserID, notifications.KindJoinRequest,
+				notifications.JoinRequestMessage(request.DisplayName, session.Title),
+				newJoinRequestData(session, request),
+			)
+		})
+		if stderrors.Is(err, joinrequests.ErrTooManyPending) {
+			errors.TooManyRequests(c, "the host has too many open join requests, try again later")
+			return
+		}
+
+		if err != nil {
+			errors.InternalError(c, "failed to create join request", err)
+			return
+		}
+
+		// hosts not connected to this instance still get the notification
+		hosts.SendToHost(sessionID, ws.TypeJoinRequest, newJoinRequestPayload(request))
+
+		c.JSON(http.StatusCreated, request)
+	}
+}
+
+// GetJoinRequestHandler godoc
+// @Summary Get join request
+// @Description Get a join request, to poll for the host's answer. Visible to the requester and the host.
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request_id path string true "Join request ID (UUID)"
+// @Success 200 {object} joinrequests.Request
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/join-requests/{request_id} [get]
+// @Security BearerAuth
+func GetJoinRequestHandler(sessionRepo sessions.Repository, joinRequests *joinrequests.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := userAndSessionID(c)
+		if !ok {
+			return
+		}
+
+		requestID, ok := errors.ValidatePathUUID(c, "request_id")
+		if !ok {
+			return
+		}
+
+		request, err := joinRequests.Get(c.Request.Context(), sessionID, requestID)
+		if err != nil {
+			handleJoinRequestError(c, err, "failed to get join request")
+			return
+		}
+
+		if request.UserID != userID {
+			session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+			if err != nil || session.HostUserID != userID {
+				errors.NotFound(c, "join request")
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, request)
+	}
+}
+
+// ListJoinRequestsHandler godoc
+// @Summary List join requests
+// @Description Get the open join requests of a session, oldest first (host only)
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Success 200 {object} JoinRequestsListResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/join-requests [get]
+// @Security BearerAuth
+func ListJoinRequestsHandler(sessionRepo sessions.Repository, joinRequests *joinrequests.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := userAndSessionID(c)
+		if !ok {
+			return
+		}
+
+		if _, ok := hostedSession(c, sessionRepo, sessionID, userID); !ok {
+			return
+		}
+
+		requests, err := joinRequests.ListPending(c.Request.Context(), sessionID)
+		if err != nil {
+			errors.InternalError(c, "failed to list join requests", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, JoinRequestsListResponse{Requests: requests})
+	}
+}
+
+// AnswerJoinRequestHandler godoc
+// @Summary Answer join request
+// @Description Approve or deny an open join request (host only). An approval lets the user connect to the session with the granted role for 24 hours. The requester is notified in-app.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request_id path string true "Join request ID (UUID)"
+// @Param request body AnswerJoinRequestRequest true "Answer"
+// @Success 200 {object} joinrequests.Request
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse "Request was already answered or has expired"
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/join-requests/{request_id} [post]
+// @Security BearerAuth
+func AnswerJoinRequestHandler(sessionRepo sessions.Repository, joinRequests *joinrequests.Service, hosts HostMessenger, notifier Notifier, auditLog AuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := userAndSessionID(c)
+		if !ok {
+			return
+		}
+
+		requestID, ok := errors.ValidatePathUUID(c, "request_id")
+		if !ok {
+			return
+		}
+
+		session, ok := hostedSession(c, sessionRepo, sessionID, userID)
+		if !ok {
+			return
+		}
+
+		var req AnswerJoinRequestRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		approved := req.Action == "approve"
+
+		kind := notifications.KindJoinRequestDenied
+		if approved {
+			kind = notifications.KindJoinRequestApproved
+		}
+
+		ctx := c.Request.Context()
+		notify := func(tx pgx.Tx, request *joinrequests.Request) error {
+			return notifier.NotifyTx(ctx, tx, request.UserID, kind,
+				notifications.JoinRequestAnsweredMessage(session.Title, approved),
+				newJoinRequestData(session, request),
+			)
+		}
+
+		var request *joinrequests.Request
+		var err error
+
+		if approved {
+			role := req.Role
+			if role == "" {
+				role = "viewer"
+			}
+
+			request, err = joinRequests.Approve(ctx, sessionID, requestID, role, notify)
+		} else {
+			request, err = joinRequests.Deny(ctx, sessionID, requestID, notify)
+		}
+
+		if err != nil {
+			handleJoinRequestError(c, err, "failed to answer join request")
+			return
+		}
+
+		action := audit.ActionJoinRequestDeny
+		if approved {
+			action = audit.ActionJoinRequestApprove
+		}
+
+		if auditLog != nil {
+			auditLog.Record(c.Request.Context(), audit.Event{
+				ActorID:    userID,
+				Action:     action,
+				TargetType: audit.TargetJoinRequest,
+				TargetID:   request.ID,
+				SessionID:  sessionID,
+				Details:    gin.H{"user_id": request.UserID, "role": request.Role},
+			})
+		}
+
+		// lets the host's other tabs drop the request
+		hosts.SendToHost(sessionID, ws.TypeJoinRequest, newJoinRequestPayload(request))
+
+		c.JSON(http.StatusOK, request)
+	}
+}
+
+func userAndSessionID(c *gin.Context) (userID, sessionID string, ok bool) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return "", "", false
+	}
+
+	sessionID, ok = errors.ValidatePathUUID(c, "id")
+	return userID, sessionID, ok
+}
+
+// gets the session, responding with an error unless the user hosts it
+func hostedSession(c *gin.Context, sessionRepo sessions.Repository, sessionID, userID string) (*sessions.Session, bool) {
+	session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+	if err != nil {
+		errors.SessionNotFound(c)
+		return nil, false
+	}
+
+	if session.HostUserID != userID {
+		errors.Forbidden(c, "only the host can manage join requests")
+		return nil, false
+	}
+
+	return session, true
+}
+
+func newJoinRequestPayload(request *joinrequests.Request) ws.JoinRequestPayload {
+	payload := ws.JoinRequestPayload{
+		RequestID:   request.ID,
+		UserID:      request.UserID,
+		DisplayName: request.DisplayName,
+		Status:      request.Status,
+		ExpiresAt:   request.ExpiresAt.UnixMilli(),
+	}
+
+	if request.Message != nil {
+		payload.Message = *request.Message
+	}
+
+	return payload
+}
+
+func newJoinRequestData(session *sessions.Session, request *joinrequests.Request) notifications.JoinRequestData {
+	return notifications.JoinRequestData{
+		SessionID:   session.ID,
+		RequestID:   request.ID,
+		Title:       session.Title,
+		DisplayName: request.DisplayName,
+		Status:      request.Status,
+	}
+}
+
+func handleJoinRequestError(c *gin.Context, err error, message string) {
+	switch {
+	case stderrors.Is(err, joinrequests.ErrRequestNotFound):
+		errors.NotFound(c, "join request")
+	case stderrors.Is(err, joinrequests.ErrNotPending):
+		errors.Conflict(c, "join request was already answered or has expired")
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
@@ -0,0 +1,19 @@
+package joinrequests
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/joinrequests"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, sessionRepo sessions.Repository, joinRequests *joinrequests.Service, userFinder UserFinder, hosts HostMessenger, notifier Notifier, auditLog AuditLog) {
+	// knocking, and polling for the answer (signed-in users)
+	router.POST("/sessions/:id/join-requests", auth.AuthMiddleware(), CreateJoinRequestHandler(sessionRepo, joinRequests, userFinder, hosts, notifier))
+	router.GET("/sessions/:id/join-requests/:request_id", auth.AuthMiddleware(), GetJoinRequestHandler(sessionRepo, joinRequests))
+
+	// open requests and answering them (host only)
+	router.GET("/sessions/:id/join-requests", auth.AuthMiddleware(), ListJoinRequestsHandler(sessionRepo, joinRequests))
+	router.POST("/sessions/:id/join-requests/:request_id", auth.AuthMiddleware(), AnswerJoinRequestHandler(sessionRepo, joinRequests, hosts, notifier, auditLog))
+}
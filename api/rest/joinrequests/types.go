@@ -0,0 +1,46 @@
+package joinrequests
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/algopatterns/users"
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/joinrequests"
+)
+
+// sends live messages to the host of a session (implemented by websocket.Hub)
+type HostMessenger interface {
+	SendToHost(sessionID, msgType string, payload any) int
+}
+
+// notifies users in-app in the transaction of the write it's about (implemented by notifications.Service)
+type Notifier interface {
+	NotifyTx(ctx context.Context, tx pgx.Tx, userID, kind, message string, data any) error
+}
+
+// looks up the requester's name (implemented by users.Repository)
+type UserFinder interface {
+	FindByID(ctx context.Context, userID string) (*users.User, error)
+}
+
+// records security-relevant actions (implemented by audit.Service)
+type AuditLog interface {
+	Record(ctx context.Context, event audit.Event)
+}
+
+type CreateJoinRequestRequest struct {
+	DisplayName string `json:"display_name,omitempty" binding:"max=100"` // defaults to the user's name
+	Message     string `json:"message,omitempty" binding:"max=280"`      // shown to the host
+}
+
+type AnswerJoinRequestRequest struct {
+	Action string `json:"action" binding:"required,oneof=approve deny"`
+	Role   string `json:"role,omitempty" binding:"omitempty,oneof=co-author viewer"` // granted on approval, defaults to viewer
+}
+
+// JoinRequestsListResponse wraps the open join requests of a session
+type JoinRequestsListResponse struct {
+	Requests []*joinrequests.Request `json:"requests"`
+}
@@ -0,0 +1,253 @@
+package lessons
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/lessons"
+)
+
+// ListLessonsHandler godoc
+// @Summary List lessons
+// @Description List the guided lessons built from the teaching concepts, without their steps
+// @Tags lessons
+// @Produce json
+// @Success 200 {object} LessonsListResponse
+// @Router /api/v1/lessons [get]
+func ListLessonsHandler(lessonService *lessons.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, LessonsListResponse{Lessons: lessonService.List()})
+	}
+}
+
+// GetLessonHandler godoc
+// @Summary Get lesson
+// @Description Get a lesson with its steps. Each step has an explanation, an example and the criteria code has to meet to finish it.
+// @Tags lessons
+// @Produce json
+// @Param id path string true "Lesson ID"
+// @Success 200 {object} lessons.Lesson
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/lessons/{id} [get]
+func GetLessonHandler(lessonService *lessons.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lesson, err := lessonService.Get(c.Param("id"))
+		if err != nil {
+			errors.NotFound(c, "lesson")
+			return
+		}
+
+		c.JSON(http.StatusOK, lesson)
+	}
+}
+
+// StartSessionLessonHandler godoc
+// @Summary Start lesson in session
+// @Description Start a guided lesson in a session you host, from its first step. Replaces the lesson in progress. While a lesson runs, the AI assistant teaches toward the current step.
+// @Tags lessons
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body StartLessonRequest true "Lesson to start"
+// @Success 201 {object} lessons.Progress
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/lesson [post]
+// @Security BearerAuth
+func StartSessionLessonHandler(sessionRepo sessions.Repository, lessonService *lessons.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := userAndSessionID(c)
+		if !ok {
+			return
+		}
+
+		var req StartLessonRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		if !isHost(c, sessionRepo, sessionID, userID) {
+			return
+		}
+
+		progress, err := lessonService.Start(c.Request.Context(), sessionID, req.LessonID, userID)
+		if err != nil {
+			handleLessonError(c, err, "failed to start lesson")
+			return
+		}
+
+		c.JSON(http.StatusCreated, progress)
+	}
+}
+
+// GetSessionLessonHandler godoc
+// @Summary Get session lesson
+// @Description Get the progress of a session's lesson and its current step (participants only)
+// @Tags lessons
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Success 200 {object} lessons.Progress
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse "no lesson in progress"
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/lesson [get]
+// @Security BearerAuth
+func GetSessionLessonHandler(sessionRepo sessions.Repository, lessonService *lessons.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := userAndSessionID(c)
+		if !ok {
+			return
+		}
+
+		if !isParticipant(c, sessionRepo, sessionID, userID) {
+			return
+		}
+
+		progress, err := lessonService.Progress(c.Request.Context(), sessionID)
+		if err != nil {
+			handleLessonError(c, err, "failed to get lesson progress")
+			return
+		}
+
+		c.JSON(http.StatusOK, progress)
+	}
+}
+
+// CheckSessionLessonHandler godoc
+// @Summary Check code against lesson step
+// @Description Check code against the current step of a session's lesson. The lesson moves on to the next step when the code lints without errors and meets the step's criteria; otherwise missing lists what is left to do.
+// @Tags lessons
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body CheckLessonRequest true "Code to check"
+// @Success 200 {object} lessons.CheckResult
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse "no lesson in progress"
+// @Failure 409 {object} errors.ErrorResponse "lesson already completed"
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/lesson/check [post]
+// @Security BearerAuth
+func CheckSessionLessonHandler(sessionRepo sessions.Repository, lessonService *lessons.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := userAndSessionID(c)
+		if !ok {
+			return
+		}
+
+		var req CheckLessonRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		if !isParticipant(c, sessionRepo, sessionID, userID) {
+			return
+		}
+
+		result, err := lessonService.Check(c.Request.Context(), sessionID, req.Code)
+		if err != nil {
+			handleLessonError(c, err, "failed to check lesson step")
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// StopSessionLessonHandler godoc
+// @Summary Stop session lesson
+// @Description End the lesson of a session you host
+// @Tags lessons
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/lesson [delete]
+// @Security BearerAuth
+func StopSessionLessonHandler(sessionRepo sessions.Repository, lessonService *lessons.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := userAndSessionID(c)
+		if !ok {
+			return
+		}
+
+		if !isHost(c, sessionRepo, sessionID, userID) {
+			return
+		}
+
+		if err := lessonService.Stop(c.Request.Context(), sessionID); err != nil {
+			handleLessonError(c, err, "failed to stop lesson")
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "lesson stopped"})
+	}
+}
+
+func userAndSessionID(c *gin.Context) (userID, sessionID string, ok bool) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return "", "", false
+	}
+
+	sessionID, ok = errors.ValidatePathUUID(c, "id")
+	return userID, sessionID, ok
+}
+
+// responds with an error unless the user hosts the session
+func isHost(c *gin.Context, sessionRepo sessions.Repository, sessionID, userID string) bool {
+	session, err := sessionRepo.GetSession(c.Request.Context(), sessionID)
+	if err != nil {
+		errors.SessionNotFound(c)
+		return false
+	}
+
+	if session.HostUserID != userID {
+		errors.Forbidden(c, "only the host can start and stop lessons")
+		return false
+	}
+
+	return true
+}
+
+// responds with an error unless the user is in the session
+func isParticipant(c *gin.Context, sessionRepo sessions.Repository, sessionID, userID string) bool {
+	if _, err := sessionRepo.GetAuthenticatedParticipant(c.Request.Context(), sessionID, userID); err != nil {
+		errors.Forbidden(c, "you are not a participant in this session")
+		return false
+	}
+
+	return true
+}
+
+func handleLessonError(c *gin.Context, err error, message string) {
+	switch {
+	case stderrors.Is(err, lessons.ErrLessonNotFound):
+		errors.NotFound(c, "lesson")
+	case stderrors.Is(err, lessons.ErrNoLesson):
+		errors.NotFound(c, "lesson in progress")
+	case stderrors.Is(err, lessons.ErrCompleted):
+		errors.Conflict(c, "lesson already completed")
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
@@ -0,0 +1,21 @@
+package lessons
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/lessons"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, sessionRepo sessions.Repository, lessonService *lessons.Service) {
+	// the catalog is public
+	router.GET("/lessons", ListLessonsHandler(lessonService))
+	router.GET("/lessons/:id", GetLessonHandler(lessonService))
+
+	// a session's lesson: participants follow and check it, the host starts and stops it
+	router.GET("/sessions/:id/lesson", auth.AuthMiddleware(), GetSessionLessonHandler(sessionRepo, lessonService))
+	router.POST("/sessions/:id/lesson/check", auth.AuthMiddleware(), CheckSessionLessonHandler(sessionRepo, lessonService))
+	router.POST("/sessions/:id/lesson", auth.AuthMiddleware(), StartSessionLessonHandler(sessionRepo, lessonService))
+	router.DELETE("/sessions/:id/lesson", auth.AuthMiddleware(), StopSessionLessonHandler(sessionRepo, lessonService))
+}
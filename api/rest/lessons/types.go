@@ -0,0 +1,20 @@
+package lessons
+
+import "codeberg.org/algopatterns/server/internal/lessons"
+
+type LessonsListResponse struct {
+	Lessons []lessons.LessonSummary `json:"lessons"`
+}
+
+type StartLessonRequest struct {
+	LessonID string `json:"lesson_id" binding:"required"`
+}
+
+type CheckLessonRequest struct {
+	Code string `json:"code" binding:"max=100000"`
+}
+
+// MessageResponse for simple success messages
+type MessageResponse struct {
+	Message string `json:"message"`
+}
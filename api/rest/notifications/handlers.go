@@ -0,0 +1,206 @@
+package notifications
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/notifications"
+)
+
+// ListNotificationsHandler godoc
+// @Summary List notifications
+// @Description Get the authenticated user's notifications, newest first (kept for 90 days)
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} NotificationsListResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/notifications [get]
+// @Security BearerAuth
+func ListNotificationsHandler(notificationService *notifications.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		unreadOnly := c.Query("unread") == "true"
+
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 20, 100)
+
+		list, total, unread, err := notificationService.List(c.Request.Context(), userID, unreadOnly, params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list notifications", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, NotificationsListResponse{
+			Notifications: list,
+			UnreadCount:   unread,
+			Pagination:    pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// MarkReadHandler godoc
+// @Summary Mark notification as read
+// @Description Mark one of the authenticated user's notifications as read
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Notification ID (UUID)"
+// @Success 200 {object} notifications.Notification
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/notifications/{id}/read [post]
+// @Security BearerAuth
+func MarkReadHandler(notificationService *notifications.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		notificationID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		notification, err := notificationService.MarkRead(c.Request.Context(), notificationID, userID)
+		if stderrors.Is(err, notifications.ErrNotificationNotFound) {
+			errors.NotFound(c, "notification")
+			return
+		}
+
+		if err != nil {
+			errors.InternalError(c, "failed to mark notification as read", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, notification)
+	}
+}
+
+// MarkAllReadHandler godoc
+// @Summary Mark all notifications as read
+// @Description Mark all of the authenticated user's notifications as read
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} MarkAllReadResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/notifications/read-all [post]
+// @Security BearerAuth
+func MarkAllReadHandler(notificationService *notifications.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		updated, err := notificationService.MarkAllRead(c.Request.Context(), userID)
+		if err != nil {
+			errors.InternalError(c, "failed to mark notifications as read", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, MarkAllReadResponse{Updated: updated})
+	}
+}
+
+// GetPreferencesHandler godoc
+// @Summary Get notification preferences
+// @Description Get the authenticated user's notification settings
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} notifications.Preferences
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/notifications/preferences [get]
+// @Security BearerAuth
+func GetPreferencesHandler(notificationService *notifications.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		preferences, err := notificationService.GetPreferences(c.Request.Context(), userID)
+		if err != nil {
+			errors.InternalError(c, "failed to get notification preferences", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, preferences)
+	}
+}
+
+// UpdatePreferencesHandler godoc
+// @Summary Update notification preferences
+// @Description Set how often unread notifications are emailed as a digest (off, daily or weekly)
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body UpdatePreferencesRequest true "Notification settings"
+// @Success 200 {object} notifications.Preferences
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/notifications/preferences [put]
+// @Security BearerAuth
+func UpdatePreferencesHandler(notificationService *notifications.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		var req UpdatePreferencesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		preferences, err := notificationService.SetEmailDigest(c.Request.Context(), userID, req.EmailDigest)
+		if stderrors.Is(err, notifications.ErrUnknownDigest) {
+			errors.BadRequest(c, err.Error(), nil)
+			return
+		}
+
+		if err != nil {
+			errors.InternalError(c, "failed to update notification preferences", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, preferences)
+	}
+}
+
+func parsePaginationParams(c *gin.Context) (limit, offset int) {
+	if l, ok := c.GetQuery("limit"); ok {
+		if parsedLimit, err := strconv.Atoi(l); err == nil {
+			limit = parsedLimit
+		}
+	}
+	if o, ok := c.GetQuery("offset"); ok {
+		if parsedOffset, err := strconv.Atoi(o); err == nil {
+			offset = parsedOffset
+		}
+	}
+	return limit, offset
+}
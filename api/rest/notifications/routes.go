@@ -0,0 +1,19 @@
+package notifications
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/notifications"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, notificationService *notifications.Service) {
+	group := router.Group("/notifications")
+	group.Use(auth.AuthMiddleware())
+
+	group.GET("", ListNotificationsHandler(notificationService))
+	group.POST("/read-all", MarkAllReadHandler(notificationService))
+	group.POST("/:id/read", MarkReadHandler(notificationService))
+	group.GET("/preferences", GetPreferencesHandler(notificationService))
+	group.PUT("/preferences", UpdatePreferencesHandler(notificationService))
+}
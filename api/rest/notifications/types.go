@@ -0,0 +1,23 @@
+package notifications
+
+import (
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/notifications"
+)
+
+// NotificationsListResponse wraps a user's notifications with pagination
+type NotificationsListResponse struct {
+	Notifications []*notifications.Notification `json:"notifications"`
+	UnreadCount   int                           `json:"unread_count"`
+	Pagination    pagination.Meta               `json:"pagination"`
+}
+
+// MarkAllReadResponse reports how many notifications were marked as read
+type MarkAllReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// UpdatePreferencesRequest sets the email digest frequency (off, daily or weekly)
+type UpdatePreferencesRequest struct {
+	EmailDigest string `json:"email_digest" binding:"required"`
+}
@@ -0,0 +1,119 @@
+package pagination
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownField is returned when fields names something items don't have
+var ErrUnknownField = errors.New("unknown field")
+
+// Fields are the item fields a client asked for with fields=a,b. nil means
+// every field
+type Fields []string
+
+// ParseFields reads the fields query parameter, checking the names against
+// the JSON fields of item. the id is always kept so items stay addressable
+func ParseFields(query url.Values, item any) (Fields, error) {
+	raw := query.Get("fields")
+	if raw == "" {
+		return nil, nil
+	}
+
+	known := jsonFields(reflect.TypeOf(item))
+
+	fields := Fields{"id"}
+	for name := range strings.SplitSeq(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(fields, name) {
+			continue
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("%w %q", ErrUnknownField, name)
+		}
+		fields = append(fields, name)
+	}
+
+	return fields, nil
+}
+
+// the JSON names of a struct's fields, following embedded structs
+func jsonFields(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	names := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+
+		// like encoding/json, untagged embedded structs lend their fields
+		if tag == "" && field.Anonymous {
+			for name := range jsonFields(field.Type) {
+				names[name] = true
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if tag == "" {
+			tag = field.Name
+		}
+		names[tag] = true
+	}
+
+	return names
+}
+
+// Project trims each item of the list at key in body down to fields. body
+// comes back as is when fields is nil
+func Project(body any, key string, fields Fields) (any, error) {
+	if fields == nil {
+		return body, nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(object[key], &items); err != nil {
+		return nil, fmt.Errorf("%s is not a list of objects: %w", key, err)
+	}
+
+	for _, item := range items {
+		for name := range item {
+			if !slices.Contains(fields, name) {
+				delete(item, name)
+			}
+		}
+	}
+
+	if object[key], err = json.Marshal(items); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
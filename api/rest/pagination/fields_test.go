@@ -0,0 +1,58 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBase struct {
+	CreatedAt string `json:"created_at"`
+}
+
+type testItem struct {
+	testBase
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Code   string `json:"code,omitempty"`
+	secret string
+}
+
+type testList struct {
+	Items      []testItem `json:"items"`
+	Pagination CursorMeta `json:"pagination"`
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields(url.Values{}, testItem{})
+	require.NoError(t, err)
+	assert.Nil(t, fields)
+
+	fields, err = ParseFields(url.Values{"fields": {"title, created_at,title"}}, testItem{})
+	require.NoError(t, err)
+	assert.Equal(t, Fields{"id", "title", "created_at"}, fields)
+
+	_, err = ParseFields(url.Values{"fields": {"title,secret"}}, &testItem{})
+	assert.ErrorIs(t, err, ErrUnknownField)
+}
+
+func TestProject(t *testing.T) {
+	body := testList{
+		Items:      []testItem{{ID: "a1", Title: "drums", Code: "s(\"bd\")", secret: "x"}},
+		Pagination: CursorMeta{Limit: 20},
+	}
+
+	same, err := Project(body, "items", nil)
+	require.NoError(t, err)
+	assert.Equal(t, body, same)
+
+	projected, err := Project(body, "items", Fields{"id", "title"})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(projected)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items":[{"id":"a1","title":"drums"}],"pagination":{"limit":20,"has_more":false}}`, string(data))
+}
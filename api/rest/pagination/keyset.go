@@ -0,0 +1,90 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"codeberg.org/algopatterns/server/internal/keyset"
+)
+
+// ErrInvalidSort is returned when a list can't be sorted as requested
+var ErrInvalidSort = errors.New("invalid sort")
+
+// KeysetOptions describes how an endpoint pages with cursors
+type KeysetOptions struct {
+	DefaultLimit int
+	MaxLimit     int
+	Sorts        []string // fields the list can be sorted by
+	DefaultSort  string   // e.g. "-created_at" for newest first
+}
+
+// ParseKeyset reads the limit, sort and cursor query parameters. a cursor
+// keeps the sort it was made in, so sort may be left out next to it
+func ParseKeyset(query url.Values, opts KeysetOptions) (keyset.Page, error) {
+	page := keyset.Page{Limit: opts.DefaultLimit, Sort: keyset.ParseSort(opts.DefaultSort)}
+
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		page.Limit = min(limit, opts.MaxLimit)
+	}
+
+	sort := query.Get("sort")
+	if sort != "" {
+		page.Sort = keyset.ParseSort(sort)
+		if !slices.Contains(opts.Sorts, page.Sort.Field) {
+			return page, fmt.Errorf("%w: sort by one of %s, with a leading - for descending", ErrInvalidSort, strings.Join(opts.Sorts, ", "))
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		var after keyset.Position
+		if err := DecodeCursor(cursor, &after); err != nil {
+			return page, err
+		}
+
+		cursorSort := keyset.ParseSort(after.Sort)
+		if !slices.Contains(opts.Sorts, cursorSort.Field) || (sort != "" && cursorSort != page.Sort) {
+			return page, ErrInvalidCursor
+		}
+
+		page.Sort, page.After = cursorSort, &after
+	}
+
+	return page, nil
+}
+
+// NewKeysetMeta builds the metadata of a page, linking the pages around it
+// with the request's other query parameters kept
+func NewKeysetMeta(u *url.URL, page keyset.Page, edges keyset.Edges) (CursorMeta, error) {
+	meta := CursorMeta{Limit: page.Limit, HasMore: edges.Next != nil}
+
+	var err error
+	if meta.NextCursor, meta.Next, err = link(u, edges.Next); err != nil {
+		return meta, err
+	}
+	if meta.PrevCursor, meta.Prev, err = link(u, edges.Prev); err != nil {
+		return meta, err
+	}
+
+	return meta, nil
+}
+
+// the cursor of a position, and the request's url pointed at it
+func link(u *url.URL, position *keyset.Position) (string, string, error) {
+	if position == nil {
+		return "", "", nil
+	}
+
+	cursor, err := EncodeCursor(position)
+	if err != nil {
+		return "", "", err
+	}
+
+	query := u.Query()
+	query.Set("cursor", cursor)
+
+	return cursor, u.Path + "?" + query.Encode(), nil
+}
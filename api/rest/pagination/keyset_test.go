@@ -0,0 +1,69 @@
+package pagination
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/internal/keyset"
+)
+
+var testKeysetOptions = KeysetOptions{
+	DefaultLimit: 20,
+	MaxLimit:     100,
+	Sorts:        []string{"created_at", "title"},
+	DefaultSort:  "-created_at",
+}
+
+func TestParseKeysetDefaults(t *testing.T) {
+	page, err := ParseKeyset(url.Values{"limit": {"500"}}, testKeysetOptions)
+	require.NoError(t, err)
+
+	assert.Equal(t, 100, page.Limit)
+	assert.Equal(t, keyset.Sort{Field: "created_at", Desc: true}, page.Sort)
+	assert.Nil(t, page.After)
+}
+
+func TestParseKeysetRejectsUnknownSort(t *testing.T) {
+	_, err := ParseKeyset(url.Values{"sort": {"-code"}}, testKeysetOptions)
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestParseKeysetCursorKeepsItsSort(t *testing.T) {
+	cursor, err := EncodeCursor(keyset.Position{Sort: "title", Key: keyset.Key{Value: "drums", ID: "a1"}})
+	require.NoError(t, err)
+
+	page, err := ParseKeyset(url.Values{"cursor": {cursor}}, testKeysetOptions)
+	require.NoError(t, err)
+	assert.Equal(t, keyset.Sort{Field: "title"}, page.Sort)
+	require.NotNil(t, page.After)
+	assert.Equal(t, "drums", page.After.Value)
+
+	// a cursor only continues the sort it was made in
+	_, err = ParseKeyset(url.Values{"cursor": {cursor}, "sort": {"-created_at"}}, testKeysetOptions)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestNewKeysetMetaLinks(t *testing.T) {
+	u, err := url.Parse("/api/v1/strudels?limit=2&tags=drums")
+	require.NoError(t, err)
+
+	page := keyset.Page{Limit: 2, Sort: keyset.Sort{Field: "created_at", Desc: true}}
+	edges := keyset.Edges{Next: &keyset.Position{Sort: "-created_at", Key: keyset.Key{Value: "v", ID: "b2"}}}
+
+	meta, err := NewKeysetMeta(u, page, edges)
+	require.NoError(t, err)
+
+	assert.True(t, meta.HasMore)
+	assert.NotEmpty(t, meta.NextCursor)
+	assert.Empty(t, meta.PrevCursor)
+	assert.Empty(t, meta.Prev)
+
+	next, err := url.Parse(meta.Next)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/strudels", next.Path)
+	assert.Equal(t, "drums", next.Query().Get("tags"))
+	assert.Equal(t, meta.NextCursor, next.Query().Get("cursor"))
+}
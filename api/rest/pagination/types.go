@@ -1,5 +1,14 @@
 package pagination
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor is returned when a cursor can't be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 // Params holds pagination parameters from request
 type Params struct {
 	Limit  int
@@ -41,3 +50,38 @@ func DefaultParams(limit, offset, defaultLimit, maxLimit int) Params {
 		Offset: offset,
 	}
 }
+
+// CursorMeta holds pagination metadata for cursor-paginated responses
+type CursorMeta struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Next       string `json:"next,omitempty"` // link to the next page
+	Prev       string `json:"prev,omitempty"` // link to the previous page
+	Total      *int   `json:"total,omitempty"`
+}
+
+// EncodeCursor encodes a page position as an opaque cursor string
+func EncodeCursor(position any) (string, error) {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor string created by EncodeCursor into position
+func DecodeCursor(cursor string, position any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, position); err != nil {
+		return ErrInvalidCursor
+	}
+
+	return nil
+}
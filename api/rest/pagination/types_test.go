@@ -0,0 +1,47 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	// keyset comparisons against postgres need the full timestamp precision
+	position := strudels.SearchCursor{
+		Rank:      0.0607927,
+		CreatedAt: time.Date(2026, 2, 1, 9, 15, 0, 123456000, time.UTC),
+		ID:        "7f0c9a52-3d1e-4b8a-9c61-0e2f4d5a6b7c",
+	}
+
+	cursor, err := EncodeCursor(position)
+	require.NoError(t, err)
+	assert.NotContains(t, cursor, "=", "cursors go in query strings unpadded")
+
+	var decoded strudels.SearchCursor
+	require.NoError(t, DecodeCursor(cursor, &decoded))
+	assert.Equal(t, position.Rank, decoded.Rank)
+	assert.Equal(t, position.ID, decoded.ID)
+	assert.True(t, position.CreatedAt.Equal(decoded.CreatedAt))
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", "eyJ0IjoieWVzdGVyZGF5In0"} {
+		var position strudels.SearchCursor
+		assert.ErrorIs(t, DecodeCursor(cursor, &position), ErrInvalidCursor, cursor)
+	}
+}
+
+func TestNewMeta(t *testing.T) {
+	assert.True(t, NewMeta(Params{Limit: 20, Offset: 0}, 21).HasMore)
+	assert.False(t, NewMeta(Params{Limit: 20, Offset: 20}, 40).HasMore)
+}
+
+func TestDefaultParams(t *testing.T) {
+	assert.Equal(t, Params{Limit: 20, Offset: 0}, DefaultParams(0, -5, 20, 100))
+	assert.Equal(t, Params{Limit: 100, Offset: 40}, DefaultParams(500, 40, 20, 100))
+}
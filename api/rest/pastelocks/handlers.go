@@ -0,0 +1,250 @@
+package pastelocks
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/ccsignals"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/pasteappeals"
+	ws "codeberg.org/algopatterns/server/internal/websocket"
+)
+
+// GetPasteLockStatusHandler godoc
+// @Summary Get paste lock status
+// @Description Whether the AI assistant is blocked in a session by a paste lock, why, and for how long. Includes the protected work the paste matched, if it was fingerprinted, and your latest appeal (participants only)
+// @Tags sessions
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Success 200 {object} PasteLockStatusResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/paste-lock [get]
+// @Security BearerAuth
+func GetPasteLockStatusHandler(sessionRepo sessions.Repository, pasteLocks PasteLocks, appeals *pasteappeals.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, sessionID, ok := participant(c, sessionRepo)
+		if !ok {
+			return
+		}
+
+		if pasteLocks == nil {
+			errors.ServiceUnavailable(c, "paste detection is not available")
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		state, err := pasteLocks.GetLock(ctx, sessionID)
+		if err != nil {
+			errors.InternalError(c, "failed to get paste lock", err)
+			return
+		}
+
+		response := PasteLockStatusResponse{
+			SessionID: sessionID,
+			Locked:    state != nil && state.Locked,
+		}
+
+		if response.Locked {
+			decision, err := pasteLocks.LockDecision(ctx, sessionID)
+			if err != nil {
+				errors.InternalError(c, "failed to get paste detection decision", err)
+				return
+			}
+
+			describeLock(&response, state, decision, userID)
+		}
+
+		response.Appeal, err = appeals.Latest(ctx, sessionID, userID)
+		if err != nil {
+			errors.InternalError(c, "failed to get paste lock appeal", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// AppealPasteLockHandler godoc
+// @Summary Appeal paste lock
+// @Description Appeal the paste lock of a session for admin review. With claim_ownership, the lock is lifted right away when the pasted code is yours: it matched your own protected work, or you published a strudel with it. Connected clients are notified when the lock is lifted (participants only)
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body AppealPasteLockRequest false "Appeal"
+// @Success 201 {object} AppealPasteLockResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse "session isn't paste-locked"
+// @Failure 409 {object} errors.ErrorResponse "an appeal is already waiting for review"
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse
+// @Router /api/v1/sessions/{id}/paste-lock/appeal [post]
+// @Security BearerAuth
+func AppealPasteLockHandler(sessionRepo sessions.Repository, pasteLocks PasteLocks, appeals *pasteappeals.Service, hub SessionBroadcaster, auditLog AuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AppealPasteLockRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !stderrors.Is(err, io.EOF) {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		userID, sessionID, ok := participant(c, sessionRepo)
+		if !ok {
+			return
+		}
+
+		if pasteLocks == nil {
+			errors.ServiceUnavailable(c, "paste detection is not available")
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		state, err := pasteLocks.GetLock(ctx, sessionID)
+		if err != nil {
+			errors.InternalError(c, "failed to get paste lock", err)
+			return
+		}
+
+		if state == nil || !state.Locked {
+			errors.NotFound(c, "paste lock")
+			return
+		}
+
+		decision, err := pasteLocks.LockDecision(ctx, sessionID)
+		if err != nil {
+			errors.InternalError(c, "failed to get paste detection decision", err)
+			return
+		}
+
+		unlocked := false
+		if req.ClaimOwnership {
+			unlocked, err = pasteLocks.UnlockForOwner(ctx, sessionID, userID)
+			if err != nil {
+				errors.InternalError(c, "failed to verify ownership", err)
+				return
+			}
+		}
+
+		create := pasteappeals.CreateAppeal{
+			SessionID:       sessionID,
+			UserID:          userID,
+			Message:         req.Message,
+			ClaimsOwnership: req.ClaimOwnership,
+			Verified:        unlocked,
+		}
+		if decision != nil {
+			create.LockReason = decision.Reason
+			create.MatchedWorkID = decision.MatchedWorkID
+			create.CCSignal = string(decision.CCSignal)
+		}
+
+		appeal, err := appeals.Create(ctx, create)
+		switch {
+		case err == nil:
+		case unlocked:
+			// the lock is gone either way, a failed record only loses the paper trail
+			logger.ErrorErr(err, "failed to record verified paste lock appeal", "session_id", sessionID)
+		case stderrors.Is(err, pasteappeals.ErrAppealPending):
+			errors.Conflict(c, "an appeal for this session is already waiting for review")
+			return
+		default:
+			errors.InternalError(c, "failed to record paste lock appeal", err)
+			return
+		}
+
+		if unlocked {
+			msg, err := ws.NewMessage(ws.TypePasteLockChanged, sessionID, "", ws.PasteLockChangedPayload{
+				Locked: false,
+				Reason: reasonOwnershipVerified,
+			})
+			if err == nil {
+				hub.BroadcastToSession(sessionID, msg, "")
+			}
+		}
+
+		if appeal != nil {
+			auditLog.Record(ctx, audit.Event{
+				ActorID:    userID,
+				Action:     audit.ActionPasteLockAppeal,
+				TargetType: audit.TargetAppeal,
+				TargetID:   appeal.ID,
+				SessionID:  sessionID,
+				Details: gin.H{
+					"claims_ownership": req.ClaimOwnership,
+					"unlocked":         unlocked,
+				},
+			})
+		}
+
+		c.JSON(http.StatusCreated, AppealPasteLockResponse{Appeal: appeal, Unlocked: unlocked})
+	}
+}
+
+// fills in why the session is locked and until when
+func describeLock(response *PasteLockStatusResponse, state *ccsignals.LockState, decision *ccsignals.Decision, userID string) {
+	if state.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(state.ExpiresIn)
+		response.ExpiresAt = &expiresAt
+		response.ExpiresInSeconds = int(state.ExpiresIn.Seconds())
+	}
+
+	if !state.LockedAt.IsZero() {
+		response.LockedAt = &state.LockedAt
+	}
+
+	if decision == nil {
+		return
+	}
+
+	response.Reason = decision.Reason
+	if response.LockedAt == nil && !decision.DecidedAt.IsZero() {
+		response.LockedAt = &decision.DecidedAt
+	}
+
+	if decision.MatchedWorkID != "" || decision.CCSignal != "" {
+		response.MatchedWork = &MatchedWork{
+			ID:       decision.MatchedWorkID,
+			CCSignal: decision.CCSignal,
+			Yours:    decision.MatchedOwnerID != "" && decision.MatchedOwnerID == userID,
+			Lines:    decision.MatchedLines,
+			Coverage: decision.Coverage,
+		}
+	}
+}
+
+// responds with an error unless the user is in the session
+func participant(c *gin.Context, sessionRepo sessions.Repository) (userID, sessionID string, ok bool) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return "", "", false
+	}
+
+	sessionID, ok = errors.ValidatePathUUID(c, "id")
+	if !ok {
+		return "", "", false
+	}
+
+	if _, err := sessionRepo.GetAuthenticatedParticipant(c.Request.Context(), sessionID, userID); err != nil {
+		errors.Forbidden(c, "you are not a participant in this session")
+		return "", "", false
+	}
+
+	return userID, sessionID, true
+}
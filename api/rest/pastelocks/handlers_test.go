@@ -0,0 +1,48 @@
+package pastelocks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/internal/ccsignals"
+)
+
+func TestDescribeLock(t *testing.T) {
+	decidedAt := time.Now().Add(-10 * time.Minute)
+	state := &ccsignals.LockState{Locked: true, ExpiresIn: 50 * time.Minute}
+	decision := &ccsignals.Decision{
+		Action:         ccsignals.DecisionLock,
+		Reason:         "content is similar to protected work with no-ai restriction",
+		MatchedOwnerID: "owner-1",
+		MatchedWorkID:  "work-1",
+		CCSignal:       ccsignals.SignalNoAI,
+		DecidedAt:      decidedAt,
+	}
+
+	var response PasteLockStatusResponse
+	describeLock(&response, state, decision, "user-1")
+
+	assert.Equal(t, decision.Reason, response.Reason)
+	assert.Equal(t, 3000, response.ExpiresInSeconds)
+	require.NotNil(t, response.ExpiresAt)
+	require.NotNil(t, response.LockedAt)
+	assert.Equal(t, decidedAt, *response.LockedAt)
+
+	require.NotNil(t, response.MatchedWork)
+	assert.Equal(t, "work-1", response.MatchedWork.ID)
+	assert.Equal(t, ccsignals.SignalNoAI, response.MatchedWork.CCSignal)
+	assert.False(t, response.MatchedWork.Yours)
+
+	// the owner of the matched work is told they can claim it
+	response = PasteLockStatusResponse{}
+	describeLock(&response, state, decision, "owner-1")
+	assert.True(t, response.MatchedWork.Yours)
+
+	// external pastes match nothing
+	response = PasteLockStatusResponse{}
+	describeLock(&response, state, &ccsignals.Decision{Reason: "large delta with no matching content in database"}, "user-1")
+	assert.Nil(t, response.MatchedWork)
+}
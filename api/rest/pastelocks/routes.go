@@ -0,0 +1,16 @@
+package pastelocks
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/pasteappeals"
+)
+
+// pasteLocks may be nil when paste detection is unavailable
+func RegisterRoutes(router *gin.RouterGroup, sessionRepo sessions.Repository, pasteLocks PasteLocks, appeals *pasteappeals.Service, hub SessionBroadcaster, auditLog AuditLog) {
+	// why the AI assistant is blocked, and appealing it (participants)
+	router.GET("/sessions/:id/paste-lock", auth.AuthMiddleware(), GetPasteLockStatusHandler(sessionRepo, pasteLocks, appeals))
+	router.POST("/sessions/:id/paste-lock/appeal", auth.AuthMiddleware(), AppealPasteLockHandler(sessionRepo, pasteLocks, appeals, hub, auditLog))
+}
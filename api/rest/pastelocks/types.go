@@ -0,0 +1,65 @@
+package pastelocks
+
+import (
+	"context"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/ccsignals"
+	"codeberg.org/algopatterns/server/internal/pasteappeals"
+	ws "codeberg.org/algopatterns/server/internal/websocket"
+)
+
+// sent to clients when an appeal lifts the lock
+const reasonOwnershipVerified = "ownership_verified"
+
+// paste lock state and appeals (implemented by ccsignals.Detector)
+type PasteLocks interface {
+	GetLock(ctx context.Context, sessionID string) (*ccsignals.LockState, error)
+	LockDecision(ctx context.Context, sessionID string) (*ccsignals.Decision, error)
+	UnlockForOwner(ctx context.Context, sessionID, userID string) (bool, error)
+}
+
+// sends live messages to a session (implemented by websocket.Hub)
+type SessionBroadcaster interface {
+	BroadcastToSession(sessionID string, msg *ws.Message, excludeClientID string)
+}
+
+// records security-relevant actions (implemented by audit.Service)
+type AuditLog interface {
+	Record(ctx context.Context, event audit.Event)
+}
+
+type AppealPasteLockRequest struct {
+	Message        string `json:"message,omitempty" binding:"max=1000"` // shown to the reviewer
+	ClaimOwnership bool   `json:"claim_ownership"`                      // the pasted code is yours, verified against your published strudels
+}
+
+// PasteLockStatusResponse explains whether and why the AI assistant is blocked in a session
+type PasteLockStatusResponse struct {
+	SessionID string     `json:"session_id"`
+	Locked    bool       `json:"locked"`
+	Reason    string     `json:"reason,omitempty"` // why paste detection locked the session
+	LockedAt  *time.Time `json:"locked_at,omitempty"`
+	// the lock lapses after this long, every edit that isn't significant enough to unlock restarts it
+	ExpiresInSeconds int                  `json:"expires_in_seconds,omitempty"`
+	ExpiresAt        *time.Time           `json:"expires_at,omitempty"`
+	MatchedWork      *MatchedWork         `json:"matched_work,omitempty"`
+	Appeal           *pasteappeals.Appeal `json:"appeal,omitempty"` // your latest appeal for this session
+}
+
+// the protected work the pasted code matched
+type MatchedWork struct {
+	ID       string             `json:"id,omitempty"` // fingerprint matches only
+	CCSignal ccsignals.CCSignal `json:"cc_signal,omitempty"`
+	Yours    bool               `json:"yours"` // appeal with claim_ownership to lift the lock
+
+	// when a section of the work was pasted: its lines and their share of the work
+	Lines    string  `json:"lines,omitempty"`
+	Coverage float64 `json:"coverage,omitempty"`
+}
+
+type AppealPasteLockResponse struct {
+	Appeal   *pasteappeals.Appeal `json:"appeal"`
+	Unlocked bool                 `json:"unlocked"` // ownership was verified and the lock lifted
+}
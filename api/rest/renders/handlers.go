@@ -0,0 +1,115 @@
+package renders
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/renders"
+)
+
+// RequestRenderHandler godoc
+// @Summary Render strudel to audio
+// @Description Queue a render of the strudel's current code to audio (must be owner). A headless renderer plays the code for the requested duration and the file is stored; poll GET /strudels/{id}/render for its status. Once ready, its URL is the strudel's preview_url, replacing the previous render. Renders the renderer rejects fail without retrying.
+// @Tags strudels
+// @Accept json
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Param request body RenderRequest false "Render options"
+// @Success 202 {object} renders.Render
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse "a render is already in progress"
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse "rendering not configured"
+// @Router /api/v1/strudels/{id}/render [post]
+// @Security BearerAuth
+func RequestRenderHandler(renderService *renders.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req RenderRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				errors.ValidationError(c, err)
+				return
+			}
+		}
+
+		if req.DurationSeconds == 0 {
+			req.DurationSeconds = renders.DefaultDurationSeconds
+		}
+
+		render, err := renderService.Request(c.Request.Context(), strudelID, userID, req.DurationSeconds)
+		if err != nil {
+			handleRenderError(c, err, "failed to queue render")
+			return
+		}
+
+		c.JSON(http.StatusAccepted, render)
+	}
+}
+
+// GetRenderHandler godoc
+// @Summary Get strudel render
+// @Description Get the status of the strudel's latest audio render (must be owner). Failed renders carry the reason in error.
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Success 200 {object} renders.Render
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse "never rendered"
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/render [get]
+// @Security BearerAuth
+func GetRenderHandler(renderService *renders.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		render, err := renderService.Latest(c.Request.Context(), strudelID, userID)
+		if err != nil {
+			handleRenderError(c, err, "failed to get render")
+			return
+		}
+
+		c.JSON(http.StatusOK, render)
+	}
+}
+
+func handleRenderError(c *gin.Context, err error, message string) {
+	switch {
+	case stderrors.Is(err, renders.ErrNotConfigured):
+		errors.ServiceUnavailable(c, "audio rendering is not available")
+	case stderrors.Is(err, renders.ErrStrudelNotFound):
+		errors.NotFound(c, "strudel")
+	case stderrors.Is(err, renders.ErrRenderNotFound):
+		errors.NotFound(c, "render")
+	case stderrors.Is(err, renders.ErrRenderInProgress):
+		errors.Conflict(c, "a render of this strudel is already in progress")
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
@@ -0,0 +1,14 @@
+package renders
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/renders"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, renderService *renders.Service) {
+	// audio renders of owned strudels
+	router.POST("/strudels/:id/render", auth.AuthMiddleware(), RequestRenderHandler(renderService))
+	router.GET("/strudels/:id/render", auth.AuthMiddleware(), GetRenderHandler(renderService))
+}
@@ -0,0 +1,5 @@
+package renders
+
+type RenderRequest struct {
+	DurationSeconds int `json:"duration_seconds" binding:"omitempty,min=5,max=60"` // defaults to 30
+}
@@ -0,0 +1,196 @@
+package samplepacks
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/safehttp"
+	"codeberg.org/algopatterns/server/internal/samplepacks"
+)
+
+// ListLicensesHandler godoc
+// @Summary List sample pack licenses
+// @Description Get the licenses a sample pack can be registered under
+// @Tags sample-packs
+// @Produce json
+// @Success 200 {object} LicensesListResponse
+// @Router /api/v1/sample-packs/licenses [get]
+func ListLicensesHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, LicensesListResponse{Licenses: samplepacks.Licenses})
+	}
+}
+
+// CreateSamplePackHandler godoc
+// @Summary Register sample pack
+// @Description Register a sample pack by its strudel.json manifest. The manifest is fetched and validated, and its sounds are recognized when analyzing code.
+// @Tags sample-packs
+// @Accept json
+// @Produce json
+// @Param request body CreateSamplePackRequest true "Sample pack name, manifest URL and license"
+// @Success 201 {object} samplepacks.SamplePack
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sample-packs [post]
+// @Security BearerAuth
+func CreateSamplePackHandler(packService *samplepacks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		var req CreateSamplePackRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		pack, err := packService.Create(c.Request.Context(), userID, samplepacks.CreateSamplePackRequest{
+			Name:        strings.TrimSpace(req.Name),
+			Description: req.Description,
+			ManifestURL: req.ManifestURL,
+			BaseURL:     req.BaseURL,
+			License:     req.License,
+		})
+		if err != nil {
+			handleSamplePackError(c, err, "failed to register sample pack")
+			return
+		}
+
+		c.JSON(http.StatusCreated, pack)
+	}
+}
+
+// ListSamplePacksHandler godoc
+// @Summary List sample packs
+// @Description Browse registered sample packs, newest first
+// @Tags sample-packs
+// @Produce json
+// @Param q query string false "Match name or description, or an exact sound name"
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} SamplePacksListResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/sample-packs [get]
+func ListSamplePacksHandler(packService *samplepacks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 20, 100)
+
+		packs, total, err := packService.List(c.Request.Context(), strings.TrimSpace(c.Query("q")), params.Limit, params.Offset)
+		if err != nil {
+			errors.InternalError(c, "failed to list sample packs", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, SamplePacksListResponse{
+			SamplePacks: packs,
+			Pagination:  pagination.NewMeta(params, total),
+		})
+	}
+}
+
+// GetSamplePackHandler godoc
+// @Summary Get sample pack
+// @Description Get a sample pack and the sounds it provides
+// @Tags sample-packs
+// @Produce json
+// @Param id path string true "Sample pack ID (UUID)"
+// @Success 200 {object} samplepacks.SamplePack
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/sample-packs/{id} [get]
+func GetSamplePackHandler(packService *samplepacks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		packID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		pack, err := packService.Get(c.Request.Context(), packID)
+		if err != nil {
+			handleSamplePackError(c, err, "failed to get sample pack")
+			return
+		}
+
+		c.JSON(http.StatusOK, pack)
+	}
+}
+
+// DeleteSamplePackHandler godoc
+// @Summary Delete sample pack
+// @Description Delete a sample pack registered by the authenticated user
+// @Tags sample-packs
+// @Produce json
+// @Param id path string true "Sample pack ID (UUID)"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/sample-packs/{id} [delete]
+// @Security BearerAuth
+func DeleteSamplePackHandler(packService *samplepacks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		packID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := packService.Delete(c.Request.Context(), packID, userID); err != nil {
+			handleSamplePackError(c, err, "failed to delete sample pack")
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "sample pack deleted successfully"})
+	}
+}
+
+func parsePaginationParams(c *gin.Context) (limit, offset int) {
+	if l, ok := c.GetQuery("limit"); ok {
+		if parsedLimit, err := strconv.Atoi(l); err == nil {
+			limit = parsedLimit
+		}
+	}
+	if o, ok := c.GetQuery("offset"); ok {
+		if parsedOffset, err := strconv.Atoi(o); err == nil {
+			offset = parsedOffset
+		}
+	}
+	return limit, offset
+}
+
+// maps sample pack service errors to responses
+func handleSamplePackError(c *gin.Context, err error, message string) {
+	switch {
+	case stderrors.Is(err, samplepacks.ErrPackNotFound):
+		errors.NotFound(c, "sample pack")
+	case stderrors.Is(err, samplepacks.ErrNameTaken),
+		stderrors.Is(err, samplepacks.ErrTooManyPacks):
+		errors.Conflict(c, err.Error())
+	case stderrors.Is(err, samplepacks.ErrInvalidLicense),
+		stderrors.Is(err, samplepacks.ErrInvalidManifest),
+		stderrors.Is(err, samplepacks.ErrManifestUnreachable),
+		stderrors.Is(err, safehttp.ErrInvalidURL),
+		stderrors.Is(err, safehttp.ErrBlockedAddress):
+		errors.BadRequest(c, err.Error(), nil)
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
@@ -0,0 +1,19 @@
+package samplepacks
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/samplepacks"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, packService *samplepacks.Service) {
+	group := router.Group("/sample-packs")
+
+	// browsing is public, registering and deleting need an account
+	group.GET("", ListSamplePacksHandler(packService))
+	group.GET("/licenses", ListLicensesHandler())
+	group.GET("/:id", GetSamplePackHandler(packService))
+	group.POST("", auth.AuthMiddleware(), CreateSamplePackHandler(packService))
+	group.DELETE("/:id", auth.AuthMiddleware(), DeleteSamplePackHandler(packService))
+}
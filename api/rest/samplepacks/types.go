@@ -0,0 +1,30 @@
+package samplepacks
+
+import (
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/samplepacks"
+)
+
+type CreateSamplePackRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description,omitempty" binding:"max=2000"`
+	ManifestURL string `json:"manifest_url" binding:"required,url,max=2048"`
+	BaseURL     string `json:"base_url,omitempty" binding:"omitempty,url,max=2048"`
+	License     string `json:"license" binding:"required,max=50"`
+}
+
+// SamplePacksListResponse wraps sample packs with pagination
+type SamplePacksListResponse struct {
+	SamplePacks []*samplepacks.SamplePack `json:"sample_packs"`
+	Pagination  pagination.Meta           `json:"pagination"`
+}
+
+// LicensesListResponse lists the licenses sample packs can be registered under
+type LicensesListResponse struct {
+	Licenses []string `json:"licenses"`
+}
+
+// MessageResponse for simple success messages
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,31 @@
+package stats
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/stats"
+)
+
+// GetPublicStatsHandler godoc
+// @Summary Get public platform stats
+// @Description Anonymized platform-wide numbers for the public stats page: active sessions, public strudels and those published in the last 7 days, the most used tags and the total number of AI generations. Refreshed every 5 minutes.
+// @Tags stats
+// @Produce json
+// @Success 200 {object} stats.PublicStats
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/stats/public [get]
+func GetPublicStatsHandler(statsService *stats.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		public, err := statsService.Public(c.Request.Context())
+		if err != nil {
+			errors.InternalError(c, "failed to retrieve stats", err)
+			return
+		}
+
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, public)
+	}
+}
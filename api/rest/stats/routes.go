@@ -0,0 +1,12 @@
+package stats
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/stats"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, statsService *stats.Service) {
+	// anonymized aggregates for the public stats page
+	router.GET("/stats/public", GetPublicStatsHandler(statsService))
+}
@@ -1,23 +1,37 @@
 package strudels
 
 import (
+	stderrors "errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
 	"codeberg.org/algopatterns/server/api/rest/pagination"
 	"codeberg.org/algopatterns/server/internal/agent"
 	"codeberg.org/algopatterns/server/internal/attribution"
+	"codeberg.org/algopatterns/server/internal/audit"
 	"codeberg.org/algopatterns/server/internal/auth"
 	"codeberg.org/algopatterns/server/internal/ccsignals"
 	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/httpcache"
+	"codeberg.org/algopatterns/server/internal/keyset"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/moderation"
+	"codeberg.org/algopatterns/server/internal/retriever"
+	"codeberg.org/algopatterns/server/internal/strudel"
+	"codeberg.org/algopatterns/server/internal/textdiff"
+	"codeberg.org/algopatterns/server/internal/webhooks"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 // CreateStrudelHandler godoc
 // @Summary Create strudel
-// @Description Save a new Strudel pattern with code, title, and metadata
+// @Description Save a new Strudel pattern with code, title, and metadata. A fork gets at least the most restrictive CC signal of the strudels it was forked from
 // @Tags strudels
 // @Accept json
 // @Produce json
@@ -28,7 +42,7 @@ import (
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/strudels [post]
 // @Security BearerAuth
-func CreateStrudelHandler(strudelRepo *strudels.Repository, fpIndexer FingerprintIndexer) gin.HandlerFunc {
+func CreateStrudelHandler(strudelRepo *strudels.Repository, fpIndexer FingerprintIndexer, events EventPublisher, moderator ContentModerator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := auth.GetUserID(c)
 		if !exists {
@@ -48,12 +62,39 @@ func CreateStrudelHandler(strudelRepo *strudels.Repository, fpIndexer Fingerprin
 			return
 		}
 
-		strudel, err := strudelRepo.Create(c.Request.Context(), userID, req)
+		verdict := screenStrudel(c, moderator, userID, req.Title, req.Description, req.IsPublic)
+		switch verdict.Action {
+		case moderation.ActionBlock:
+			errors.BadRequest(c, errContentPolicy, nil)
+			return
+		case moderation.ActionHide:
+			// kept private until an admin approves it
+			req.IsPublic = false
+		}
+
+		var published func(tx pgx.Tx, strudel *strudels.Strudel) error
+		if events != nil {
+			published = func(tx pgx.Tx, strudel *strudels.Strudel) error {
+				if strudel.IsPublic {
+					if err := publishStrudelPublished(c, events, tx, strudel); err != nil {
+						return err
+					}
+				}
+				if strudel.ForkedFrom != nil {
+					return publishStrudelForked(c, strudelRepo, events, tx, strudel)
+				}
+				return nil
+			}
+		}
+
+		strudel, err := strudelRepo.Create(c.Request.Context(), userID, req, published)
 		if err != nil {
 			errors.InternalError(c, "failed to create strudel", err)
 			return
 		}
 
+		reportStrudel(c, moderator, strudel, verdict)
+
 		// migrate conversation history to strudel_messages table
 		// so GET endpoint can retrieve it (GET reads from strudel_messages, not JSONB column)
 		for _, msg := range req.ConversationHistory {
@@ -79,20 +120,151 @@ func CreateStrudelHandler(strudelRepo *strudels.Repository, fpIndexer Fingerprin
 			fpIndexer.IndexStrudel(strudel.ID, strudel.UserID, strudel.Code, ccsignals.CCSignal(*strudel.CCSignal))
 		}
 
+		// derive tags, complexity, tempo and instruments for explore filters
+		strudelRepo.AnalyzeAsync(c.Request.Context(), strudel.ID, strudel.Code)
+
+		// published strudels with a permissive signal become RAG examples
+		if strudel.IsPublic {
+			strudelRepo.IndexAsync(c.Request.Context(), strudel.ID)
+		}
+
+		resolveEffectiveSignal(c, strudelRepo, strudel)
 		c.JSON(http.StatusCreated, strudel)
 	}
 }
 
+// ImportStrudelHandler godoc
+// @Summary Import strudel
+// @Description Create a private draft from an export bundle (a strudel as returned by GET /api/v1/strudels/{id}) or a strudel.cc share link (https://strudel.cc/#<hash> or the hash alone). Short links (https://strudel.cc/?<id>) can't be imported. The code is analyzed before responding, so auto tags are included.
+// @Tags strudels
+// @Accept json
+// @Produce json
+// @Param request body ImportStrudelRequest true "Bundle or share link"
+// @Success 201 {object} strudels.Strudel
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/import [post]
+// @Security BearerAuth
+func ImportStrudelHandler(strudelRepo *strudels.Repository, fpIndexer FingerprintIndexer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		var req ImportStrudelRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		if (req.Bundle == nil) == (req.URL == "") {
+			errors.BadRequest(c, "provide either bundle or url", nil)
+			return
+		}
+
+		var create strudels.CreateStrudelRequest
+
+		if req.Bundle != nil {
+			if req.Bundle.CCSignal != nil && !req.Bundle.CCSignal.IsValid() {
+				errors.BadRequest(c, "invalid cc_signal", nil)
+				return
+			}
+
+			create = strudels.CreateStrudelRequest{
+				Title:       req.Bundle.Title,
+				Code:        req.Bundle.Code,
+				Description: req.Bundle.Description,
+				Tags:        req.Bundle.Tags,
+				Categories:  req.Bundle.Categories,
+				License:     req.Bundle.License,
+				CCSignal:    req.Bundle.CCSignal,
+			}
+		} else {
+			code, err := strudel.DecodeShareLink(req.URL)
+			if err != nil {
+				errors.BadRequest(c, err.Error(), nil)
+				return
+			}
+
+			if len(code) > maxImportCodeSize {
+				errors.BadRequest(c, "code exceeds the 1MB limit", nil)
+				return
+			}
+
+			create = strudels.CreateStrudelRequest{Code: code}
+		}
+
+		// an explicit title wins, then the bundle's, then the code's @title comment
+		create.Title = importTitle(req.Title, create.Title, create.Code)
+
+		ctx := c.Request.Context()
+
+		created, err := strudelRepo.Create(ctx, userID, create, nil)
+		if err != nil {
+			errors.InternalError(c, "failed to import strudel", err)
+			return
+		}
+
+		if fpIndexer != nil && created.CCSignal != nil {
+			fpIndexer.IndexStrudel(created.ID, created.UserID, created.Code, ccsignals.CCSignal(*created.CCSignal))
+		}
+
+		// analyze before responding so the draft comes back with its auto tags
+		if err := strudelRepo.Analyze(ctx, created.ID, created.Code); err != nil {
+			logger.Warn("failed to analyze imported strudel", "error", err, "strudel_id", created.ID)
+			c.JSON(http.StatusCreated, created)
+			return
+		}
+
+		if analyzed, err := strudelRepo.Get(ctx, created.ID, userID); err == nil {
+			created = analyzed
+		}
+
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+// picks the first non-empty title, limited to the strudel title length
+func importTitle(requested, bundled, code string) string {
+	title := strings.TrimSpace(requested)
+	if title == "" {
+		title = strings.TrimSpace(bundled)
+	}
+	if title == "" {
+		title = strudel.ParseTitle(code)
+	}
+	if title == "" {
+		return defaultImportTitle
+	}
+
+	if runes := []rune(title); len(runes) > maxImportTitleLength {
+		title = strings.TrimSpace(string(runes[:maxImportTitleLength]))
+	}
+
+	return title
+}
+
 // ListStrudelsHandler godoc
 // @Summary List user's strudels
 // @Description Get strudels owned by the authenticated user with pagination, search, and filtering
 // @Tags strudels
 // @Produce json
 // @Param limit query int false "Items per page (max 100)" default(20)
-// @Param offset query int false "Number of items to skip" default(0)
+// @Param cursor query string false "Cursor from the previous response's next_cursor or prev_cursor"
+// @Param sort query string false "created_at, updated_at or title, with a leading - for descending" default(-created_at)
+// @Param fields query []string false "Strudel fields to return (comma-separated), id is always included"
 // @Param search query string false "Search in title and description"
-// @Param tags query []string false "Filter by tags (comma-separated)"
-// @Success 200 {object} StrudelsListResponse
+// @Param tags query []string false "Filter by user or auto-generated tags (comma-separated)"
+// @Param instruments query []string false "Filter by instruments (comma-separated)"
+// @Param complexity_min query int false "Minimum complexity (0-10)"
+// @Param complexity_max query int false "Maximum complexity (0-10)"
+// @Param bpm_min query number false "Minimum estimated BPM"
+// @Param bpm_max query number false "Maximum estimated BPM"
+// @Success 200 {object} StrudelsPageResponse
+// @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/strudels [get]
@@ -105,20 +277,19 @@ func ListStrudelsHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
 			return
 		}
 
-		limit, offset := parsePaginationParams(c)
-		params := pagination.DefaultParams(limit, offset, 20, 100)
+		page, fields, ok := parsePageParams(c)
+		if !ok {
+			return
+		}
 		filter := parseFilterParams(c)
 
-		strudelsList, total, err := strudelRepo.List(c.Request.Context(), userID, params.Limit, params.Offset, filter)
+		strudelsList, edges, total, err := strudelRepo.List(c.Request.Context(), userID, page, filter)
 		if err != nil {
 			errors.InternalError(c, "failed to list strudels", err)
 			return
 		}
 
-		c.JSON(http.StatusOK, StrudelsListResponse{
-			Strudels:   strudelsList,
-			Pagination: pagination.NewMeta(params, total),
-		})
+		respondStrudelPage(c, page, strudelsList, edges, total, fields)
 	}
 }
 
@@ -128,7 +299,9 @@ func ListStrudelsHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
 // @Tags strudels
 // @Produce json
 // @Param id path string true "Strudel ID (UUID)"
+// @Param If-None-Match header string false "ETag from an earlier response, answered with 304 while it is current"
 // @Success 200 {object} StrudelDetailResponse
+// @Success 304 {string} string "Not modified"
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 404 {object} errors.ErrorResponse
 // @Router /api/v1/strudels/{id} [get]
@@ -205,6 +378,21 @@ func GetStrudelHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
 			parentCCSignal, _ = strudelRepo.GetParentCCSignal(c.Request.Context(), *strudel.ForkedFrom) //nolint:errcheck // parent may have been deleted
 		}
 
+		resolveEffectiveSignal(c, strudelRepo, strudel)
+		setCCSignalHeaders(c, strudel)
+		setCachePolicy(c, strudel)
+
+		// the conversation, the signals of the strudels it was forked from and
+		// the preview change without touching updated_at
+		var latestMessageID string
+		if len(messages) > 0 {
+			latestMessageID = messages[0].ID
+		}
+		etag := httpcache.ETag(strudel.UpdatedAt, signalTag(strudel.EffectiveCCSignal), signalTag(parentCCSignal), latestMessageID, previewTag(strudel.PreviewURL))
+		if httpcache.NotModified(c, etag) {
+			return
+		}
+
 		c.JSON(http.StatusOK, StrudelDetailResponse{
 			ID:                  strudel.ID,
 			UserID:              strudel.UserID,
@@ -214,10 +402,12 @@ func GetStrudelHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
 			CCSignal:            strudel.CCSignal,
 			ForkedFrom:          strudel.ForkedFrom,
 			ParentCCSignal:      parentCCSignal,
+			EffectiveCCSignal:   strudel.EffectiveCCSignal,
 			Description:         strudel.Description,
 			Tags:                strudel.Tags,
 			Categories:          strudel.Categories,
 			ConversationHistory: conversationHistory,
+			PreviewURL:          strudel.PreviewURL,
 			CreatedAt:           strudel.CreatedAt,
 			UpdatedAt:           strudel.UpdatedAt,
 		})
@@ -226,19 +416,23 @@ func GetStrudelHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
 
 // UpdateStrudelHandler godoc
 // @Summary Update strudel
-// @Description Update a strudel's properties (must be owner)
+// @Description Update a strudel's properties (must be owner). A fork can't be given, or published under, a CC signal more permissive than the most restrictive signal of the strudels it was forked from. Send the updated_at you last loaded, in the body or as If-Match; the update is rejected with 409 if the strudel was saved since, and with 428 if neither is sent. If-Match: * saves without the check
 // @Tags strudels
 // @Accept json
 // @Produce json
 // @Param id path string true "Strudel ID (UUID)"
+// @Param If-Match header string false "ETag of the strudel as last loaded, or * to save without the check. Required unless updated_at is in the body"
 // @Param request body strudels.UpdateStrudelRequest true "Update data"
 // @Success 200 {object} strudels.Strudel
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
 // @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} StrudelConflictResponse
+// @Failure 428 {object} errors.ErrorResponse "Neither updated_at nor If-Match was sent"
+// @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/strudels/{id} [put]
 // @Security BearerAuth
-func UpdateStrudelHandler(strudelRepo *strudels.Repository, fpIndexer FingerprintIndexer) gin.HandlerFunc {
+func UpdateStrudelHandler(strudelRepo *strudels.Repository, fpIndexer FingerprintIndexer, events EventPublisher, auditLog AuditRecorder, moderator ContentModerator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := auth.GetUserID(c)
 		if !exists {
@@ -258,18 +452,110 @@ func UpdateStrudelHandler(strudelRepo *strudels.Repository, fpIndexer Fingerprin
 			return
 		}
 
+		// the body takes precedence over If-Match. a save has to say which
+		// version it's based on, "If-Match: *" opts out of the check
+		if req.UpdatedAt == nil {
+			ifMatch := c.GetHeader("If-Match")
+			if strings.TrimSpace(ifMatch) == "" {
+				errors.PreconditionRequired(c, "send the updated_at the strudel was loaded with, or If-Match: * to save without the check")
+				return
+			}
+
+			loadedAt, err := parseIfMatch(ifMatch)
+			if err != nil {
+				errors.BadRequest(c, "invalid If-Match header", nil)
+				return
+			}
+			req.UpdatedAt = loadedAt
+		}
+
 		// validate no-ai signal is not used with AI-assisted content
 		if err := validateNoAISignal(req.CCSignal, req.ConversationHistory); err != nil {
 			errors.BadRequest(c, err.Error(), nil)
 			return
 		}
 
-		strudel, err := strudelRepo.Update(c.Request.Context(), strudelID, userID, req)
-		if err != nil {
+		// screen the resulting title and description whenever either or the visibility changes
+		verdict := moderation.Verdict{Action: moderation.ActionAllow}
+		if moderator != nil && (req.Title != nil || req.Description != nil || req.IsPublic != nil) {
+			if current, err := strudelRepo.Get(c.Request.Context(), strudelID, userID); err == nil {
+				title, description, isPublic := current.Title, current.Description, current.IsPublic
+				if req.Title != nil {
+					title = *req.Title
+				}
+				if req.Description != nil {
+					description = *req.Description
+				}
+				if req.IsPublic != nil {
+					isPublic = *req.IsPublic
+				}
+
+				verdict = screenStrudel(c, moderator, userID, title, description, isPublic)
+			}
+		}
+
+		switch verdict.Action {
+		case moderation.ActionBlock:
+			errors.BadRequest(c, errContentPolicy, nil)
+			return
+		case moderation.ActionHide:
+			private := false
+			req.IsPublic = &private
+		}
+
+		// publishing is only an event when the strudel was private before,
+		// and cc signal changes are audited with their previous value
+		publishing := events != nil && req.IsPublic != nil && *req.IsPublic
+		changingSignal := auditLog != nil && req.CCSignal != nil
+
+		var existing *strudels.Strudel
+		if publishing || changingSignal {
+			existing, _ = strudelRepo.Get(c.Request.Context(), strudelID, userID) //nolint:errcheck // Update reports missing strudels
+		}
+
+		var published func(tx pgx.Tx, strudel *strudels.Strudel) error
+		if publishing {
+			published = func(tx pgx.Tx, strudel *strudels.Strudel) error {
+				if existing == nil || existing.IsPublic || !strudel.IsPublic {
+					return nil
+				}
+				return publishStrudelPublished(c, events, tx, strudel)
+			}
+		}
+
+		strudel, err := strudelRepo.Update(c.Request.Context(), strudelID, userID, req, published)
+		if stderrors.Is(err, strudels.ErrStrudelConflict) {
+			respondConflict(c, strudelRepo, strudelID, userID, req.Code)
+			return
+		}
+
+		if stderrors.Is(err, strudels.ErrCCSignalTooPermissive) {
+			errors.BadRequest(c, err.Error(), nil)
+			return
+		}
+
+		if stderrors.Is(err, pgx.ErrNoRows) {
 			errors.NotFound(c, "strudel")
 			return
 		}
 
+		if err != nil {
+			errors.InternalError(c, "failed to update strudel", err)
+			return
+		}
+
+		reportStrudel(c, moderator, strudel, verdict)
+
+		if changingSignal && existing != nil && !sameCCSignal(existing.CCSignal, strudel.CCSignal) {
+			auditLog.Record(c.Request.Context(), audit.Event{
+				ActorID:    userID,
+				Action:     audit.ActionStrudelCCSignal,
+				TargetType: audit.TargetStrudel,
+				TargetID:   strudel.ID,
+				Details:    gin.H{"old": existing.CCSignal, "new": strudel.CCSignal},
+			})
+		}
+
 		// update fingerprint index only if code changed (avoid rehashing on metadata-only updates)
 		// UpdateStrudel also skips rehashing if the content is unchanged (optimization for frequent autosaves)
 		if fpIndexer != nil && req.Code != nil {
@@ -280,13 +566,182 @@ func UpdateStrudelHandler(strudelRepo *strudels.Repository, fpIndexer Fingerprin
 			}
 		}
 
+		// re-analyze only if code changed
+		if req.Code != nil {
+			strudelRepo.AnalyzeAsync(c.Request.Context(), strudel.ID, strudel.Code)
+		}
+
+		if req.AffectsExample() {
+			strudelRepo.IndexAsync(c.Request.Context(), strudel.ID)
+		}
+
+		resolveEffectiveSignal(c, strudelRepo, strudel)
+		c.Header("ETag", strudelETag(strudel))
 		c.JSON(http.StatusOK, strudel)
 	}
 }
 
+// BulkStrudelsHandler godoc
+// @Summary Bulk strudel operation
+// @Description Apply one action to up to 100 of your strudels: delete (move to the trash), add_tags or remove_tags (tags), set_visibility (is_public) or set_cc_signal (cc_signal). The action is applied to every strudel or none: when any item is missing or its change isn't allowed, nothing is changed, the response is 422 and the other items are reported as skipped. Publishing is screened like single updates, but strudels held for review have to be published on their own
+// @Tags strudels
+// @Accept json
+// @Produce json
+// @Param request body strudels.BulkRequest true "Action and strudel IDs"
+// @Success 200 {object} BulkResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 422 {object} BulkResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/bulk [post]
+// @Security BearerAuth
+func BulkStrudelsHandler(strudelRepo *strudels.Repository, fpIndexer FingerprintIndexer, events EventPublisher, auditLog AuditRecorder, moderator ContentModerator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		var req strudels.BulkRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		if err := validateBulkRequest(req); err != nil {
+			errors.BadRequest(c, err.Error(), nil)
+			return
+		}
+
+		// publishing is screened up front, so no external check runs while the rows are locked
+		verdicts := map[string]moderation.Verdict{}
+		rejected := map[string]string{}
+		if req.Action == strudels.BulkSetVisibility && *req.IsPublic && moderator != nil {
+			for _, id := range req.IDs {
+				current, err := strudelRepo.Get(c.Request.Context(), id, userID)
+				if err != nil {
+					continue // reported as not found
+				}
+
+				verdict := screenStrudel(c, moderator, userID, current.Title, current.Description, true)
+				switch verdict.Action {
+				case moderation.ActionBlock:
+					rejected[id] = errContentPolicy
+				case moderation.ActionHide:
+					rejected[id] = "needs review before it can be published, publish it on its own"
+				default:
+					verdicts[id] = verdict
+				}
+			}
+		}
+
+		var published func(tx pgx.Tx, result strudels.BulkResult) error
+		if events != nil && req.Action == strudels.BulkSetVisibility {
+			published = func(tx pgx.Tx, result strudels.BulkResult) error {
+				if !result.Strudel.IsPublic || result.Previous.IsPublic {
+					return nil
+				}
+				return publishStrudelPublished(c, events, tx, result.Strudel)
+			}
+		}
+
+		results, applied, err := strudelRepo.Bulk(c.Request.Context(), userID, req, rejected, published)
+		if err != nil {
+			errors.InternalError(c, "failed to apply bulk operation", err)
+			return
+		}
+
+		if !applied {
+			c.JSON(http.StatusUnprocessableEntity, BulkResponse{
+				Error:   errors.CodeInvalidOperation,
+				Message: "no strudels were changed because some of them failed",
+				Results: results,
+			})
+			return
+		}
+
+		for _, result := range results {
+			afterBulkChange(c, strudelRepo, fpIndexer, auditLog, moderator, req.Action, result, verdicts[result.ID])
+		}
+
+		c.JSON(http.StatusOK, BulkResponse{Applied: true, Results: results})
+	}
+}
+
+// the follow-up work of a single update, for one applied bulk item
+func afterBulkChange(
+	c *gin.Context,
+	strudelRepo *strudels.Repository,
+	fpIndexer FingerprintIndexer,
+	auditLog AuditRecorder,
+	moderator ContentModerator,
+	action string,
+	result strudels.BulkResult,
+	verdict moderation.Verdict,
+) {
+	previous, strudel := result.Previous, result.Strudel
+
+	switch action {
+	case strudels.BulkSetVisibility:
+		if strudel.IsPublic && !previous.IsPublic {
+			reportStrudel(c, moderator, strudel, verdict)
+		}
+
+		strudelRepo.IndexAsync(c.Request.Context(), strudel.ID)
+
+	case strudels.BulkSetCCSignal:
+		if sameCCSignal(previous.CCSignal, strudel.CCSignal) {
+			return
+		}
+
+		if auditLog != nil {
+			auditLog.Record(c.Request.Context(), audit.Event{
+				ActorID:    strudel.UserID,
+				Action:     audit.ActionStrudelCCSignal,
+				TargetType: audit.TargetStrudel,
+				TargetID:   strudel.ID,
+				Details:    gin.H{"old": previous.CCSignal, "new": strudel.CCSignal, "bulk": true},
+			})
+		}
+
+		if fpIndexer != nil {
+			fpIndexer.UpdateStrudel(strudel.ID, strudel.UserID, strudel.Code, ccsignals.CCSignal(*strudel.CCSignal))
+		}
+
+		strudelRepo.IndexAsync(c.Request.Context(), strudel.ID)
+	}
+}
+
+// checks that the action has what it needs
+func validateBulkRequest(req strudels.BulkRequest) error {
+	for _, id := range req.IDs {
+		if !errors.IsValidUUID(id) {
+			return fmt.Errorf("invalid strudel ID format: %s", id)
+		}
+	}
+
+	switch req.Action {
+	case strudels.BulkAddTags, strudels.BulkRemoveTags:
+		if len(req.Tags) == 0 {
+			return fmt.Errorf("tags are required for %s", req.Action)
+		}
+	case strudels.BulkSetVisibility:
+		if req.IsPublic == nil {
+			return fmt.Errorf("is_public is required for %s", req.Action)
+		}
+	case strudels.BulkSetCCSignal:
+		if req.CCSignal == nil || !req.CCSignal.IsValid() {
+			return fmt.Errorf("a valid cc_signal is required for %s", req.Action)
+		}
+	}
+
+	return nil
+}
+
 // DeleteStrudelHandler godoc
 // @Summary Delete strudel
-// @Description Delete a strudel (must be owner)
+// @Description Move a strudel to the trash (must be owner). Trashed strudels can be restored for 30 days before they are permanently deleted
 // @Tags strudels
 // @Produce json
 // @Param id path string true "Strudel ID (UUID)"
@@ -296,7 +751,7 @@ func UpdateStrudelHandler(strudelRepo *strudels.Repository, fpIndexer Fingerprin
 // @Failure 404 {object} errors.ErrorResponse
 // @Router /api/v1/strudels/{id} [delete]
 // @Security BearerAuth
-func DeleteStrudelHandler(strudelRepo *strudels.Repository, fpIndexer FingerprintIndexer) gin.HandlerFunc {
+func DeleteStrudelHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := auth.GetUserID(c)
 		if !exists {
@@ -315,36 +770,39 @@ func DeleteStrudelHandler(strudelRepo *strudels.Repository, fpIndexer Fingerprin
 			return
 		}
 
-		// remove from fingerprint index
-		if fpIndexer != nil {
-			fpIndexer.RemoveStrudel(strudelID)
-		}
+		// the fingerprint stays indexed until the strudel is purged, since
+		// trashed code can still be restored
 
-		c.JSON(http.StatusOK, MessageResponse{Message: "strudel deleted"})
+		c.JSON(http.StatusOK, MessageResponse{Message: "strudel moved to trash"})
 	}
 }
 
-// ListPublicStrudelsHandler godoc
-// @Summary List public strudels
-// @Description Get publicly shared strudels from all users with pagination, search, and filtering
+// ListTrashHandler godoc
+// @Summary List trashed strudels
+// @Description Get the authenticated user's deleted strudels, most recently deleted first. Each is purged 30 days after deleted_at
 // @Tags strudels
 // @Produce json
 // @Param limit query int false "Items per page (max 100)" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
-// @Param search query string false "Search in title and description"
-// @Param tags query []string false "Filter by tags (comma-separated)"
 // @Success 200 {object} StrudelsListResponse
+// @Failure 401 {object} errors.ErrorResponse
 // @Failure 500 {object} errors.ErrorResponse
-// @Router /api/v1/public/strudels [get]
-func ListPublicStrudelsHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+// @Router /api/v1/strudels/trash [get]
+// @Security BearerAuth
+func ListTrashHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
 		limit, offset := parsePaginationParams(c)
 		params := pagination.DefaultParams(limit, offset, 20, 100)
-		filter := parseFilterParams(c)
 
-		strudelsList, total, err := strudelRepo.ListPublic(c.Request.Context(), params.Limit, params.Offset, filter)
+		strudelsList, total, err := strudelRepo.ListTrash(c.Request.Context(), userID, params.Limit, params.Offset)
 		if err != nil {
-			errors.InternalError(c, "failed to list public strudels", err)
+			errors.InternalError(c, "failed to list trash", err)
 			return
 		}
 
@@ -355,41 +813,571 @@ func ListPublicStrudelsHandler(strudelRepo *strudels.Repository) gin.HandlerFunc
 	}
 }
 
-// GetPublicStrudelHandler godoc
-// @Summary Get public strudel by ID
-// @Description Get a publicly shared strudel by its ID (for forking)
+// RestoreStrudelHandler godoc
+// @Summary Restore strudel
+// @Description Move a trashed strudel back to the user's library (must be owner)
 // @Tags strudels
 // @Produce json
 // @Param id path string true "Strudel ID (UUID)"
 // @Success 200 {object} strudels.Strudel
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
 // @Failure 404 {object} errors.ErrorResponse
 // @Failure 500 {object} errors.ErrorResponse
-// @Router /api/v1/public/strudels/{id} [get]
-func GetPublicStrudelHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+// @Router /api/v1/strudels/{id}/restore [post]
+// @Security BearerAuth
+func RestoreStrudelHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		strudelID := c.Param("id")
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
 
-		if !errors.IsValidUUID(strudelID) {
-			errors.BadRequest(c, "invalid strudel ID format", nil)
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
 			return
 		}
 
-		strudel, err := strudelRepo.GetPublic(c.Request.Context(), strudelID)
+		strudel, err := strudelRepo.Restore(c.Request.Context(), strudelID, userID)
 		if err != nil {
-			errors.NotFound(c, "strudel")
+			if stderrors.Is(err, strudels.ErrStrudelNotFound) {
+				errors.NotFound(c, "strudel")
+				return
+			}
+
+			errors.InternalError(c, "failed to restore strudel", err)
 			return
 		}
 
+		// the example embedding was dropped on delete
+		if strudel.IsPublic {
+			strudelRepo.IndexAsync(c.Request.Context(), strudel.ID)
+		}
+
 		c.JSON(http.StatusOK, strudel)
 	}
 }
 
-// ListPublicTagsHandler godoc
-// @Summary List public tags
-// @Description Get all unique tags from public strudels
+// ListVersionsHandler godoc
+// @Summary List strudel versions
+// @Description Get the saved versions of a strudel's code, newest first (must be owner). Saves within a minute of the latest version replace it, at most 100 versions are kept, and versions older than 30 days are thinned to one per day
 // @Tags strudels
 // @Produce json
-// @Success 200 {object} TagsListResponse
+// @Param id path string true "Strudel ID (UUID)"
+// @Success 200 {object} VersionsListResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/versions [get]
+// @Security BearerAuth
+func ListVersionsHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		if _, err := strudelRepo.Get(c.Request.Context(), strudelID, userID); err != nil {
+			errors.NotFound(c, "strudel")
+			return
+		}
+
+		versions, err := strudelRepo.ListVersions(c.Request.Context(), strudelID, userID)
+		if err != nil {
+			errors.InternalError(c, "failed to list versions", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, VersionsListResponse{Versions: versions})
+	}
+}
+
+// VersionDiffHandler godoc
+// @Summary Diff strudel versions
+// @Description Get a unified diff of a strudel's code from another version to the given one (must be owner)
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Param version path int true "Version number"
+// @Param against query int false "Version to diff from (defaults to the previous version)"
+// @Success 200 {object} VersionDiffResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/versions/{version}/diff [get]
+// @Security BearerAuth
+func VersionDiffHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		version, ok := parseVersionParam(c, c.Param("version"))
+		if !ok {
+			return
+		}
+
+		against := version - 1
+		if value := c.Query("against"); value != "" {
+			if against, ok = parseVersionParam(c, value); !ok {
+				return
+			}
+		}
+
+		to, ok := getVersion(c, strudelRepo, strudelID, userID, version)
+		if !ok {
+			return
+		}
+
+		// the first version is diffed against empty code; trimmed versions can't be diffed
+		from := &strudels.StrudelVersion{}
+		if against > 0 {
+			if from, ok = getVersion(c, strudelRepo, strudelID, userID, against); !ok {
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, VersionDiffResponse{
+			From: against,
+			To:   version,
+			Diff: textdiff.Unified(
+				fmt.Sprintf("v%d", against),
+				fmt.Sprintf("v%d", version),
+				from.Code,
+				to.Code,
+				textdiff.DefaultContext,
+			),
+		})
+	}
+}
+
+// RevertStrudelHandler godoc
+// @Summary Revert strudel to a version
+// @Description Restore the code of an earlier version (must be owner). The restored code is saved as a new version
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Param version path int true "Version number"
+// @Success 200 {object} strudels.Strudel
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/revert/{version} [post]
+// @Security BearerAuth
+func RevertStrudelHandler(strudelRepo *strudels.Repository, fpIndexer FingerprintIndexer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		version, ok := parseVersionParam(c, c.Param("version"))
+		if !ok {
+			return
+		}
+
+		strudel, err := strudelRepo.Revert(c.Request.Context(), strudelID, userID, version)
+		if err != nil {
+			if stderrors.Is(err, strudels.ErrVersionNotFound) {
+				errors.NotFound(c, "version")
+				return
+			}
+
+			errors.InternalError(c, "failed to revert strudel", err)
+			return
+		}
+
+		// the code changed, so refresh everything derived from it
+		if fpIndexer != nil {
+			if strudel.CCSignal != nil {
+				fpIndexer.UpdateStrudel(strudel.ID, strudel.UserID, strudel.Code, ccsignals.CCSignal(*strudel.CCSignal))
+			} else {
+				fpIndexer.RemoveStrudel(strudel.ID)
+			}
+		}
+
+		strudelRepo.AnalyzeAsync(c.Request.Context(), strudel.ID, strudel.Code)
+		strudelRepo.IndexAsync(c.Request.Context(), strudel.ID)
+
+		c.JSON(http.StatusOK, strudel)
+	}
+}
+
+// parses a version number, sending a bad request if it isn't a positive integer
+func parseVersionParam(c *gin.Context, value string) (int, bool) {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		errors.BadRequest(c, "version must be a positive integer", nil)
+		return 0, false
+	}
+
+	return version, true
+}
+
+// fetches a version, sending not found or an internal error if that fails
+func getVersion(c *gin.Context, strudelRepo *strudels.Repository, strudelID, userID string, version int) (*strudels.StrudelVersion, bool) {
+	v, err := strudelRepo.GetVersion(c.Request.Context(), strudelID, userID, version)
+	if err != nil {
+		if stderrors.Is(err, strudels.ErrVersionNotFound) {
+			errors.NotFound(c, "version")
+			return nil, false
+		}
+
+		errors.InternalError(c, "failed to get version", err)
+		return nil, false
+	}
+
+	return v, true
+}
+
+// ListPublicStrudelsHandler godoc
+// @Summary List public strudels
+// @Description Get publicly shared strudels from all users with pagination, search, and filtering
+// @Tags strudels
+// @Produce json
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param cursor query string false "Cursor from the previous response's next_cursor or prev_cursor"
+// @Param sort query string false "created_at, updated_at or title, with a leading - for descending" default(-created_at)
+// @Param fields query []string false "Strudel fields to return (comma-separated), id is always included"
+// @Param search query string false "Search in title and description"
+// @Param tags query []string false "Filter by user or auto-generated tags (comma-separated)"
+// @Param instruments query []string false "Filter by instruments (comma-separated)"
+// @Param complexity_min query int false "Minimum complexity (0-10)"
+// @Param complexity_max query int false "Maximum complexity (0-10)"
+// @Param bpm_min query number false "Minimum estimated BPM"
+// @Param bpm_max query number false "Maximum estimated BPM"
+// @Success 200 {object} StrudelsPageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/public/strudels [get]
+func ListPublicStrudelsHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, fields, ok := parsePageParams(c)
+		if !ok {
+			return
+		}
+		filter := parseFilterParams(c)
+
+		strudelsList, edges, total, err := strudelRepo.ListPublic(c.Request.Context(), page, filter)
+		if err != nil {
+			errors.InternalError(c, "failed to list public strudels", err)
+			return
+		}
+
+		respondStrudelPage(c, page, strudelsList, edges, total, fields)
+	}
+}
+
+// SearchStrudelsHandler godoc
+// @Summary Search public strudels
+// @Description Full-text search over title, description and code of public strudels, with filters and cursor pagination
+// @Tags strudels
+// @Produce json
+// @Param q query string false "Search text (supports quotes, OR and -exclude)"
+// @Param tags query []string false "Filter by user or auto-generated tags (comma-separated)"
+// @Param complexity_min query int false "Minimum complexity (0-10)"
+// @Param complexity_max query int false "Maximum complexity (0-10)"
+// @Param cc_signal query []string false "Filter by CC signals (comma-separated, e.g. cc-cr,cc-op)"
+// @Param sort query string false "Sort order: relevance, recent or popular (default relevance with q, otherwise recent)"
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param cursor query string false "Cursor from the previous page's next_cursor"
+// @Success 200 {object} StrudelSearchResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/search [get]
+func SearchStrudelsHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := parsePaginationParams(c)
+		pageParams := pagination.DefaultParams(limit, 0, 20, 100)
+
+		params := strudels.SearchParams{
+			Query:         strings.TrimSpace(c.Query("q")),
+			Tags:          parseListQuery(c, "tags"),
+			MinComplexity: parseIntQuery(c, "complexity_min"),
+			MaxComplexity: parseIntQuery(c, "complexity_max"),
+			Sort:          c.DefaultQuery("sort", strudels.SortRelevance),
+			Limit:         pageParams.Limit,
+		}
+
+		if len(params.Query) > maxSearchQueryLength {
+			errors.BadRequest(c, fmt.Sprintf("search query must be at most %d characters", maxSearchQueryLength), nil)
+			return
+		}
+
+		switch params.Sort {
+		case strudels.SortRelevance, strudels.SortRecent, strudels.SortPopular:
+		default:
+			errors.BadRequest(c, "sort must be one of: relevance, recent, popular", nil)
+			return
+		}
+
+		for _, s := range parseListQuery(c, "cc_signal") {
+			signal := strudels.CCSignal(s)
+			if !signal.IsValid() {
+				errors.BadRequest(c, fmt.Sprintf("invalid cc_signal %q", s), nil)
+				return
+			}
+
+			params.CCSignals = append(params.CCSignals, signal)
+		}
+
+		if cursor := c.Query("cursor"); cursor != "" {
+			var after strudels.SearchCursor
+			if err := pagination.DecodeCursor(cursor, &after); err != nil {
+				errors.BadRequest(c, "invalid cursor", err)
+				return
+			}
+
+			params.After = &after
+		}
+
+		results, next, err := strudelRepo.Search(c.Request.Context(), params)
+		if err != nil {
+			errors.InternalError(c, "failed to search strudels", err)
+			return
+		}
+
+		meta := pagination.CursorMeta{Limit: params.Limit}
+		if next != nil {
+			cursor, err := pagination.EncodeCursor(next)
+			if err != nil {
+				errors.InternalError(c, "failed to encode cursor", err)
+				return
+			}
+
+			meta.NextCursor = cursor
+			meta.HasMore = true
+		}
+
+		if results == nil {
+			results = []strudels.SearchResult{}
+		}
+
+		c.JSON(http.StatusOK, StrudelSearchResponse{
+			Results:    results,
+			Pagination: meta,
+		})
+	}
+}
+
+// SimilarStrudelsHandler godoc
+// @Summary Find similar strudels
+// @Description Get public strudels with the most similar code and description, excluding the strudel's own fork lineage
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Param limit query int false "Number of results (max 50)" default(10)
+// @Success 200 {object} SimilarStrudelsResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/similar [get]
+func SimilarStrudelsHandler(strudelRepo *strudels.Repository, finder SimilarStrudelFinder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudel, ok := getViewableStrudel(c, strudelRepo)
+		if !ok {
+			return
+		}
+
+		limit, _ := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, 0, defaultSimilarLimit, maxSimilarLimit)
+
+		// code is only sent to the embedding provider with an explicit permissive signal
+		allowEmbedding := strudel.CCSignal != nil && *strudel.CCSignal != strudels.CCSignalNoAI
+		text := strudel.Description + "\n\n" + strudel.Code
+
+		results, err := finder.SearchSimilarStrudels(c.Request.Context(), strudel.ID, text, allowEmbedding, params.Limit)
+		if err != nil {
+			if stderrors.Is(err, retriever.ErrNoEmbedding) {
+				errors.Forbidden(c, "similar strudels are not available for strudels that opt out of AI processing")
+				return
+			}
+
+			errors.InternalError(c, "failed to find similar strudels", err)
+			return
+		}
+
+		similar := make([]SimilarStrudelDTO, 0, len(results))
+		for _, r := range results {
+			similar = append(similar, SimilarStrudelDTO{
+				ID:          r.ID,
+				UserID:      r.UserID,
+				AuthorName:  r.AuthorName,
+				Title:       r.Title,
+				Description: r.Description,
+				Code:        r.Code,
+				Tags:        r.Tags,
+				Similarity:  r.Similarity,
+			})
+		}
+
+		c.JSON(http.StatusOK, SimilarStrudelsResponse{Strudels: similar})
+	}
+}
+
+// ListAncestorsHandler godoc
+// @Summary Get strudel fork ancestry
+// @Description Get the chain of strudels this one was forked from, from the direct parent up to the original, with CC signals and authors. Private strudels of other users are redacted.
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Success 200 {object} AncestorsResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/ancestors [get]
+func ListAncestorsHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudel, ok := getViewableStrudel(c, strudelRepo)
+		if !ok {
+			return
+		}
+
+		viewerID, _ := auth.GetUserID(c)
+
+		ancestors, err := strudelRepo.ListAncestors(c.Request.Context(), strudel.ID, viewerID)
+		if err != nil {
+			errors.InternalError(c, "failed to get strudel ancestry", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, AncestorsResponse{
+			Ancestors: ancestors,
+			Count:     len(ancestors),
+		})
+	}
+}
+
+// ListForksHandler godoc
+// @Summary Get strudel fork tree
+// @Description Get the tree of strudels forked from this one, with CC signals and authors at each node. Private strudels of other users are redacted.
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Success 200 {object} ForksResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/forks [get]
+func ListForksHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudel, ok := getViewableStrudel(c, strudelRepo)
+		if !ok {
+			return
+		}
+
+		viewerID, _ := auth.GetUserID(c)
+
+		forks, counts, err := strudelRepo.ListDescendants(c.Request.Context(), strudel.ID, viewerID)
+		if err != nil {
+			errors.InternalError(c, "failed to get strudel forks", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, ForksResponse{
+			Forks:  forks,
+			Counts: counts,
+		})
+	}
+}
+
+// GetPublicStrudelHandler godoc
+// @Summary Get public strudel by ID
+// @Description Get a publicly shared strudel by its ID (for forking)
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Param If-None-Match header string false "ETag from an earlier response, answered with 304 while it is current"
+// @Success 200 {object} strudels.Strudel
+// @Success 304 {string} string "Not modified"
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/public/strudels/{id} [get]
+func GetPublicStrudelHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudelID := c.Param("id")
+
+		if !errors.IsValidUUID(strudelID) {
+			errors.BadRequest(c, "invalid strudel ID format", nil)
+			return
+		}
+
+		strudel, err := strudelRepo.GetPublic(c.Request.Context(), strudelID)
+		if err != nil {
+			errors.NotFound(c, "strudel")
+			return
+		}
+
+		resolveEffectiveSignal(c, strudelRepo, strudel)
+		setCCSignalHeaders(c, strudel)
+		httpcache.Public(c, httpcache.PublicMaxAge)
+
+		if httpcache.NotModified(c, httpcache.ETag(strudel.UpdatedAt, signalTag(strudel.EffectiveCCSignal), previewTag(strudel.PreviewURL))) {
+			return
+		}
+
+		c.JSON(http.StatusOK, strudel)
+	}
+}
+
+// GetCCSignalHandler godoc
+// @Summary Get strudel CC signal
+// @Description Get a strudel's CC signal in machine-readable form, so AI crawlers and tools can honor its AI usage preferences. The effective signal is the most restrictive one of the strudel and the strudels it was forked from; without a signal, AI use is disallowed. Strudel responses link here with a Link header (rel="cc-signal"), and carry X-Robots-Tag: noai when AI use is disallowed.
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Success 200 {object} CCSignalResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/strudels/{id}/cc [get]
+func GetCCSignalHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudel, ok := getViewableStrudel(c, strudelRepo)
+		if !ok {
+			return
+		}
+
+		resolveEffectiveSignal(c, strudelRepo, strudel)
+		setCCSignalHeaders(c, strudel)
+
+		if strudel.IsPublic {
+			c.Header("Cache-Control", "public, max-age=300")
+		}
+
+		c.JSON(http.StatusOK, ccSignalResponse(strudel))
+	}
+}
+
+// ListPublicTagsHandler godoc
+// @Summary List public tags
+// @Description Get all unique tags from public strudels
+// @Tags strudels
+// @Produce json
+// @Success 200 {object} TagsListResponse
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/public/strudels/tags [get]
 func ListPublicTagsHandler(strudelRepo *strudels.Repository) gin.HandlerFunc {
@@ -447,6 +1435,141 @@ func parseInt(s string) (int, error) {
 	return i, err
 }
 
+// notifies the owner that their strudel is now public, in the transaction publishing it
+func publishStrudelPublished(c *gin.Context, events EventPublisher, tx pgx.Tx, strudel *strudels.Strudel) error {
+	return events.PublishTx(c.Request.Context(), tx, strudel.UserID, webhooks.EventStrudelPublished, webhooks.StrudelEventData{
+		StrudelID: strudel.ID,
+		Title:     strudel.Title,
+	})
+}
+
+func sameCCSignal(a, b *strudels.CCSignal) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// the strudel's updated_at, which clients send back in If-Match to detect conflicting saves
+func strudelETag(strudel *strudels.Strudel) string {
+	return httpcache.ETag(strudel.UpdatedAt)
+}
+
+// parses an If-Match header holding a strudel's ETag; empty and "*" skip the
+// check. tags from GETs hash in more than updated_at, only updated_at is read
+func parseIfMatch(header string) (*time.Time, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	loadedAt, err := httpcache.Version(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loadedAt, nil
+}
+
+// a signal as an ETag part
+func signalTag(signal *strudels.CCSignal) string {
+	if signal == nil {
+		return ""
+	}
+
+	return string(*signal)
+}
+
+// a preview URL as an ETag part
+func previewTag(url *string) string {
+	if url == nil {
+		return ""
+	}
+
+	return *url
+}
+
+// public strudels may be cached anywhere for a while, private ones only by
+// their owner's browser
+func setCachePolicy(c *gin.Context, strudel *strudels.Strudel) {
+	if strudel.IsPublic {
+		httpcache.Public(c, httpcache.PublicMaxAge)
+		return
+	}
+
+	httpcache.Private(c)
+}
+
+// responds to a stale update with the strudel as saved, so the client can
+// merge its code with the server's and retry with the new updated_at
+func respondConflict(c *gin.Context, strudelRepo *strudels.Repository, strudelID, userID string, clientCode *string) {
+	current, err := strudelRepo.Get(c.Request.Context(), strudelID, userID)
+	if err != nil {
+		errors.NotFound(c, "strudel")
+		return
+	}
+
+	response := StrudelConflictResponse{
+		Error:      errors.CodeConflict,
+		Message:    "strudel was modified since it was loaded",
+		Current:    current,
+		ServerCode: current.Code,
+	}
+
+	if clientCode != nil {
+		response.ClientCode = clientCode
+		response.Diff = textdiff.Unified("server", "client", current.Code, *clientCode, textdiff.DefaultContext)
+	}
+
+	c.Header("ETag", strudelETag(current))
+	c.JSON(http.StatusConflict, response)
+}
+
+// screens the title and description of a strudel that is or becomes public.
+// private strudels are only seen by their owner and aren't screened
+func screenStrudel(c *gin.Context, moderator ContentModerator, userID, title, description string, isPublic bool) moderation.Verdict {
+	if moderator == nil || !isPublic {
+		return moderation.Verdict{Action: moderation.ActionAllow}
+	}
+
+	return moderator.Screen(c.Request.Context(), moderation.Content{
+		Type:     moderation.ContentStrudel,
+		AuthorID: userID,
+		Text:     strings.TrimSpace(title + "\n" + description),
+	})
+}
+
+// queues a saved strudel for review when screening flagged or hid it
+func reportStrudel(c *gin.Context, moderator ContentModerator, strudel *strudels.Strudel, verdict moderation.Verdict) {
+	if moderator == nil {
+		return
+	}
+
+	moderator.Report(c.Request.Context(), moderation.Content{
+		Type:     moderation.ContentStrudel,
+		ID:       strudel.ID,
+		AuthorID: strudel.UserID,
+		Text:     strings.TrimSpace(strudel.Title + "\n" + strudel.Description),
+	}, verdict)
+}
+
+// notifies the owner of the original that it was forked by someone else, in
+// the transaction creating the fork
+func publishStrudelForked(c *gin.Context, strudelRepo *strudels.Repository, events EventPublisher, tx pgx.Tx, fork *strudels.Strudel) error {
+	parent, err := strudelRepo.GetPublic(c.Request.Context(), *fork.ForkedFrom)
+	if err != nil || parent.UserID == fork.UserID {
+		return nil
+	}
+
+	return events.PublishTx(c.Request.Context(), tx, parent.UserID, webhooks.EventStrudelForked, webhooks.ForkEventData{
+		StrudelID: parent.ID,
+		ForkID:    fork.ID,
+		ForkTitle: fork.Title,
+		ForkedBy:  fork.UserID,
+	})
+}
+
 func parsePaginationParams(c *gin.Context) (limit, offset int) {
 	if l, ok := c.GetQuery("limit"); ok {
 		if parsedLimit, err := parseInt(l); err == nil {
@@ -461,6 +1584,64 @@ func parsePaginationParams(c *gin.Context) (limit, offset int) {
 	return limit, offset
 }
 
+// cursor pagination of strudel lists
+var strudelPages = pagination.KeysetOptions{
+	DefaultLimit: 20,
+	MaxLimit:     100,
+	Sorts:        strudels.ListSorts,
+	DefaultSort:  "-created_at",
+}
+
+// cursor pagination of a strudel's uses
+var usePages = pagination.KeysetOptions{
+	DefaultLimit: 20,
+	MaxLimit:     100,
+	Sorts:        []string{"created_at"},
+	DefaultSort:  "-created_at",
+}
+
+// reads the page and fields of a strudel list request, responding with 400
+// when either is invalid
+func parsePageParams(c *gin.Context) (keyset.Page, pagination.Fields, bool) {
+	query := c.Request.URL.Query()
+
+	page, err := pagination.ParseKeyset(query, strudelPages)
+	if err != nil {
+		errors.BadRequest(c, err.Error(), nil)
+		return page, nil, false
+	}
+
+	fields, err := pagination.ParseFields(query, strudels.Strudel{})
+	if err != nil {
+		errors.BadRequest(c, err.Error(), nil)
+		return page, nil, false
+	}
+
+	return page, fields, true
+}
+
+// responds with a page of strudels, trimmed to the requested fields
+func respondStrudelPage(c *gin.Context, page keyset.Page, list []strudels.Strudel, edges keyset.Edges, total int, fields pagination.Fields) {
+	meta, err := pagination.NewKeysetMeta(c.Request.URL, page, edges)
+	if err != nil {
+		errors.InternalError(c, "failed to encode cursor", err)
+		return
+	}
+	meta.Total = &total
+
+	if list == nil {
+		list = []strudels.Strudel{}
+	}
+
+	body, err := pagination.Project(StrudelsPageResponse{Strudels: list, Pagination: meta}, "strudels", fields)
+	if err != nil {
+		errors.InternalError(c, "failed to select fields", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, body)
+}
+
 func parseFilterParams(c *gin.Context) strudels.ListFilter {
 	filter := strudels.ListFilter{}
 
@@ -477,9 +1658,133 @@ func parseFilterParams(c *gin.Context) strudels.ListFilter {
 		}
 	}
 
+	if instrumentsStr, ok := c.GetQuery("instruments"); ok && instrumentsStr != "" {
+		filter.Instruments = strings.Split(instrumentsStr, ",")
+
+		for i, instrument := range filter.Instruments {
+			filter.Instruments[i] = strings.ToLower(strings.TrimSpace(instrument))
+		}
+	}
+
+	// invalid numbers are ignored, like invalid pagination params
+	filter.MinComplexity = parseIntQuery(c, "complexity_min")
+	filter.MaxComplexity = parseIntQuery(c, "complexity_max")
+	filter.MinBPM = parseFloatQuery(c, "bpm_min")
+	filter.MaxBPM = parseFloatQuery(c, "bpm_max")
+
 	return filter
 }
 
+// gets the strudel in the id path param if the user owns it or it is public.
+// writes the error response and returns false otherwise.
+func getViewableStrudel(c *gin.Context, strudelRepo *strudels.Repository) (*strudels.Strudel, bool) {
+	strudelID, ok := errors.ValidatePathUUID(c, "id")
+	if !ok {
+		return nil, false
+	}
+
+	// try to get as owner first if authenticated
+	if userID, exists := auth.GetUserID(c); exists {
+		if strudel, err := strudelRepo.Get(c.Request.Context(), strudelID, userID); err == nil {
+			return strudel, true
+		}
+	}
+
+	strudel, err := strudelRepo.GetPublic(c.Request.Context(), strudelID)
+	if err != nil {
+		errors.NotFound(c, "strudel")
+		return nil, false
+	}
+
+	return strudel, true
+}
+
+// splits a comma-separated query param, trimming whitespace and dropping empty values
+func parseListQuery(c *gin.Context, key string) []string {
+	var values []string
+
+	for _, value := range strings.Split(c.Query(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func parseIntQuery(c *gin.Context, key string) *int {
+	value, ok := c.GetQuery(key)
+	if !ok {
+		return nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+
+	return &i
+}
+
+func parseFloatQuery(c *gin.Context, key string) *float64 {
+	value, ok := c.GetQuery(key)
+	if !ok {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+
+	return &f
+}
+
+// fills in the signal that applies to a strudel through its fork ancestry,
+// left out when the ancestry can't be read
+func resolveEffectiveSignal(c *gin.Context, strudelRepo *strudels.Repository, strudel *strudels.Strudel) {
+	if err := strudelRepo.ResolveEffectiveCCSignal(c.Request.Context(), strudel); err != nil {
+		logger.Warn("failed to resolve effective cc signal", "strudel_id", strudel.ID, "error", err)
+	}
+}
+
+// describes the AI usage preferences of a strudel with a resolved effective signal
+func ccSignalResponse(strudel *strudels.Strudel) CCSignalResponse {
+	var effective strudels.CCSignal
+	if strudel.EffectiveCCSignal != nil {
+		effective = *strudel.EffectiveCCSignal
+	}
+
+	response := CCSignalResponse{
+		StrudelID:         strudel.ID,
+		CCSignal:          strudel.CCSignal,
+		EffectiveCCSignal: strudel.EffectiveCCSignal,
+		AIUse:             "disallowed",
+		Requirements:      effective.Requirements(),
+		License:           strudel.License,
+	}
+
+	if effective.AllowsAI() {
+		response.AIUse = "allowed"
+	}
+
+	if strudel.License != nil {
+		response.LicenseURL = strudels.LicenseURL(*strudel.License)
+	}
+
+	return response
+}
+
+// links a strudel response to its machine-readable cc signal, and marks
+// strudels that opt out of AI use for crawlers
+func setCCSignalHeaders(c *gin.Context, strudel *strudels.Strudel) {
+	c.Header("Link", fmt.Sprintf(`</api/v1/strudels/%s/cc>; rel="cc-signal"; type="application/json"`, strudel.ID))
+
+	if strudel.EffectiveCCSignal == nil || !strudel.EffectiveCCSignal.AllowsAI() {
+		c.Header("X-Robots-Tag", "noai, noimageai")
+	}
+}
+
 // checks if the no-ai signal is being used with AI-assisted content
 func validateNoAISignal(signal *strudels.CCSignal, history strudels.ConversationHistory) error {
 	if signal == nil || *signal != strudels.CCSignalNoAI {
@@ -496,6 +1801,36 @@ func validateNoAISignal(signal *strudels.CCSignal, history strudels.Conversation
 	return nil
 }
 
+// ValidateCodeHandler godoc
+// @Summary Validate strudel code
+// @Description Lint Strudel code for unbalanced delimiters, empty patterns and unknown functions or sounds
+// @Tags strudels
+// @Accept json
+// @Produce json
+// @Param request body ValidateCodeRequest true "Code to validate"
+// @Success 200 {object} ValidateCodeResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Router /api/v1/strudel/validate [post]
+func ValidateCodeHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ValidateCodeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		diagnostics := strudel.Lint(req.Code)
+		if diagnostics == nil {
+			diagnostics = []strudel.Diagnostic{}
+		}
+
+		c.JSON(http.StatusOK, ValidateCodeResponse{
+			Valid:       !strudel.HasLintErrors(diagnostics),
+			Diagnostics: diagnostics,
+		})
+	}
+}
+
 // GetStrudelStatsHandler godoc
 // @Summary Get strudel usage stats
 // @Description Get attribution stats for a public strudel (how many times it was used as RAG context)
@@ -532,6 +1867,74 @@ func GetStrudelStatsHandler(strudelRepo *strudels.Repository, attrService *attri
 	}
 }
 
+// ListStrudelUsesHandler godoc
+// @Summary List strudel uses
+// @Description List the public strudels a public strudel inspired, as AI context or as a fork, with cursor pagination
+// @Tags strudels
+// @Produce json
+// @Param id path string true "Strudel ID (UUID)"
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param cursor query string false "Cursor from the previous response's next_cursor or prev_cursor"
+// @Param sort query string false "created_at, with a leading - for descending" default(-created_at)
+// @Param fields query []string false "Fields to return (comma-separated), id is always included"
+// @Success 200 {object} StrudelUsesResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/public/strudels/{id}/uses [get]
+func ListStrudelUsesHandler(strudelRepo *strudels.Repository, attrService *attribution.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strudelID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		query := c.Request.URL.Query()
+
+		page, err := pagination.ParseKeyset(query, usePages)
+		if err != nil {
+			errors.BadRequest(c, err.Error(), nil)
+			return
+		}
+
+		fields, err := pagination.ParseFields(query, attribution.StrudelUse{})
+		if err != nil {
+			errors.BadRequest(c, err.Error(), nil)
+			return
+		}
+
+		// verify strudel exists and is public
+		if _, err := strudelRepo.GetPublic(c.Request.Context(), strudelID); err != nil {
+			errors.NotFound(c, "strudel")
+			return
+		}
+
+		uses, edges, err := attrService.ListStrudelUses(c.Request.Context(), strudelID, page)
+		if err != nil {
+			errors.InternalError(c, "failed to list strudel uses", err)
+			return
+		}
+
+		meta, err := pagination.NewKeysetMeta(c.Request.URL, page, edges)
+		if err != nil {
+			errors.InternalError(c, "failed to encode cursor", err)
+			return
+		}
+
+		if uses == nil {
+			uses = []attribution.StrudelUse{}
+		}
+
+		body, err := pagination.Project(StrudelUsesResponse{Uses: uses, Pagination: meta}, "uses", fields)
+		if err != nil {
+			errors.InternalError(c, "failed to select fields", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, body)
+	}
+}
+
 // converts agent.StrudelReference to strudels.StrudelReference
 func convertStrudelRefs(refs []agent.StrudelReference) []strudels.StrudelReference {
 	result := make([]strudels.StrudelReference, len(refs))
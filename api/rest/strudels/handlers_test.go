@@ -0,0 +1,129 @@
+package strudels
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/internal/errors"
+)
+
+func TestIfMatchRoundTrip(t *testing.T) {
+	// postgres keeps microseconds, the etag must not lose them
+	updatedAt := time.Date(2026, 3, 1, 12, 30, 15, 123456000, time.FixedZone("CET", 3600))
+	etag := strudelETag(&strudels.Strudel{UpdatedAt: updatedAt})
+
+	assert.Equal(t, `"2026-03-01T11:30:15.123456Z"`, etag)
+
+	for _, header := range []string{etag, "W/" + etag, " " + etag + " "} {
+		loadedAt, err := parseIfMatch(header)
+		require.NoError(t, err, header)
+		require.NotNil(t, loadedAt, header)
+		assert.True(t, loadedAt.Equal(updatedAt), header)
+	}
+}
+
+func TestParseIfMatchSkipsCheck(t *testing.T) {
+	for _, header := range []string{"", "*", "  *  "} {
+		loadedAt, err := parseIfMatch(header)
+		require.NoError(t, err, header)
+		assert.Nil(t, loadedAt, header)
+	}
+}
+
+func TestParseIfMatchRejectsForeignTags(t *testing.T) {
+	_, err := parseIfMatch(`"33a64df551425fcc55e4d42a148795d9f25f89d4"`)
+	assert.Error(t, err)
+}
+
+func TestUpdateRequiresVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/strudels/:id", func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+	}, UpdateStrudelHandler(nil, nil, nil, nil, nil))
+
+	put := func(ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/strudels/5f0c1e3a-8f4b-4a7e-9d2c-1b6e8a9f0c3d", strings.NewReader(`{"code":"s(\"bd\")"}`))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// a save that doesn't say which version it's based on could overwrite another tab's work
+	for _, ifMatch := range []string{"", "  "} {
+		w := put(ifMatch)
+		require.Equal(t, http.StatusPreconditionRequired, w.Code, "If-Match %q", ifMatch)
+
+		var body errors.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, errors.CodePreconditionRequired, body.Error)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, put(`"not-a-version"`).Code)
+}
+
+func TestCCSignalResponse(t *testing.T) {
+	signal := func(s strudels.CCSignal) *strudels.CCSignal { return &s }
+	license := strudels.LicenseBYSA
+
+	// a fork of no-ai work inherits the restriction
+	response := ccSignalResponse(&strudels.Strudel{
+		ID:                "s1",
+		CCSignal:          signal(strudels.CCSignalCredit),
+		EffectiveCCSignal: signal(strudels.CCSignalNoAI),
+		License:           &license,
+	})
+
+	assert.Equal(t, "disallowed", response.AIUse)
+	assert.Empty(t, response.Requirements)
+	assert.Equal(t, "https://creativecommons.org/licenses/by-sa/4.0/", response.LicenseURL)
+
+	response = ccSignalResponse(&strudels.Strudel{ID: "s2", EffectiveCCSignal: signal(strudels.CCSignalEcosystem)})
+	assert.Equal(t, "allowed", response.AIUse)
+	assert.Equal(t, []string{"credit", "ecosystem_contribution"}, response.Requirements)
+
+	// without a signal, AI use isn't assumed to be fine
+	response = ccSignalResponse(&strudels.Strudel{ID: "s3"})
+	assert.Equal(t, "disallowed", response.AIUse)
+	assert.Empty(t, response.LicenseURL)
+}
+
+func TestValidateBulkRequest(t *testing.T) {
+	id := "0b6f3a52-5c1e-4d7a-9a57-3f0c8a1e2b4d"
+	public := true
+	noAI := strudels.CCSignalNoAI
+	unknown := strudels.CCSignal("cc-xx")
+
+	valid := []strudels.BulkRequest{
+		{Action: strudels.BulkDelete, IDs: []string{id}},
+		{Action: strudels.BulkAddTags, IDs: []string{id}, Tags: []string{"techno"}},
+		{Action: strudels.BulkSetVisibility, IDs: []string{id}, IsPublic: &public},
+		{Action: strudels.BulkSetCCSignal, IDs: []string{id}, CCSignal: &noAI},
+	}
+	for _, req := range valid {
+		assert.NoError(t, validateBulkRequest(req), req.Action)
+	}
+
+	invalid := []strudels.BulkRequest{
+		{Action: strudels.BulkDelete, IDs: []string{"not-a-uuid"}},
+		{Action: strudels.BulkRemoveTags, IDs: []string{id}},
+		{Action: strudels.BulkSetVisibility, IDs: []string{id}},
+		{Action: strudels.BulkSetCCSignal, IDs: []string{id}, CCSignal: &unknown},
+	}
+	for _, req := range invalid {
+		assert.Error(t, validateBulkRequest(req), req.Action)
+	}
+}
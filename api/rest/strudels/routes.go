@@ -1,11 +1,17 @@
 package strudels
 
 import (
+	"context"
+
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
 	"codeberg.org/algopatterns/server/internal/attribution"
+	"codeberg.org/algopatterns/server/internal/audit"
 	"codeberg.org/algopatterns/server/internal/auth"
 	"codeberg.org/algopatterns/server/internal/ccsignals"
+	"codeberg.org/algopatterns/server/internal/moderation"
+	"codeberg.org/algopatterns/server/internal/retriever"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 // provides methods to index/remove strudels from the fingerprint index
@@ -15,19 +21,69 @@ type FingerprintIndexer interface {
 	RemoveStrudel(strudelID string)
 }
 
-func RegisterRoutes(router *gin.RouterGroup, strudelRepo *strudels.Repository, attrService *attribution.Service, fpIndexer FingerprintIndexer) {
+// queues webhook events in the transaction of the write they report (implemented by webhooks.Service)
+type EventPublisher interface {
+	PublishTx(ctx context.Context, tx pgx.Tx, userID, event string, data any) error
+}
+
+// finds public strudels similar to a given one (implemented by retriever.Client)
+type SimilarStrudelFinder interface {
+	SearchSimilarStrudels(ctx context.Context, strudelID, text string, allowEmbedding bool, topK int) ([]retriever.ExampleResult, error)
+}
+
+// records security-relevant actions (implemented by audit.Service)
+type AuditRecorder interface {
+	Record(ctx context.Context, event audit.Event)
+}
+
+// screens titles and descriptions and queues them for review (implemented by moderation.Service)
+type ContentModerator interface {
+	Screen(ctx context.Context, content moderation.Content) moderation.Verdict
+	Report(ctx context.Context, content moderation.Content, verdict moderation.Verdict)
+}
+
+func RegisterRoutes(
+	router *gin.RouterGroup,
+	strudelRepo *strudels.Repository,
+	attrService *attribution.Service,
+	fpIndexer FingerprintIndexer,
+	similarFinder SimilarStrudelFinder,
+	events EventPublisher,
+	auditLog AuditRecorder,
+	moderator ContentModerator,
+) {
+	// search public strudels (no auth required)
+	router.GET("/strudels/search", SearchStrudelsHandler(strudelRepo))
+
 	// GET strudel by ID - allows owner OR public access (optional auth)
 	router.GET("/strudels/:id", auth.OptionalAuthMiddleware(), GetStrudelHandler(strudelRepo))
 
+	// similar public strudels - same access rules as GET strudel by ID
+	router.GET("/strudels/:id/similar", auth.OptionalAuthMiddleware(), SimilarStrudelsHandler(strudelRepo, similarFinder))
+
+	// fork lineage - same access rules as GET strudel by ID
+	router.GET("/strudels/:id/ancestors", auth.OptionalAuthMiddleware(), ListAncestorsHandler(strudelRepo))
+	router.GET("/strudels/:id/forks", auth.OptionalAuthMiddleware(), ListForksHandler(strudelRepo))
+
+	// machine-readable cc signal for crawlers and tools - same access rules as GET strudel by ID
+	router.GET("/strudels/:id/cc", auth.OptionalAuthMiddleware(), GetCCSignalHandler(strudelRepo))
+
 	// authenticated strudel operations
 	strudelsGroup := router.Group("/strudels")
 	strudelsGroup.Use(auth.AuthMiddleware())
 	{
 		strudelsGroup.GET("", ListStrudelsHandler(strudelRepo))
-		strudelsGroup.POST("", CreateStrudelHandler(strudelRepo, fpIndexer))
+		strudelsGroup.POST("", CreateStrudelHandler(strudelRepo, fpIndexer, events, moderator))
+		strudelsGroup.POST("/import", ImportStrudelHandler(strudelRepo, fpIndexer))
+		strudelsGroup.POST("/bulk", BulkStrudelsHandler(strudelRepo, fpIndexer, events, auditLog, moderator))
 		strudelsGroup.GET("/tags", ListUserTagsHandler(strudelRepo))
-		strudelsGroup.PUT("/:id", UpdateStrudelHandler(strudelRepo, fpIndexer))
-		strudelsGroup.DELETE("/:id", DeleteStrudelHandler(strudelRepo, fpIndexer))
+		strudelsGroup.PUT("/:id", UpdateStrudelHandler(strudelRepo, fpIndexer, events, auditLog, moderator))
+		strudelsGroup.DELETE("/:id", DeleteStrudelHandler(strudelRepo))
+		strudelsGroup.GET("/trash", ListTrashHandler(strudelRepo))
+		strudelsGroup.POST("/:id/restore", RestoreStrudelHandler(strudelRepo))
+		strudelsGroup.GET("/:id/versions", ListVersionsHandler(strudelRepo))
+		strudelsGroup.GET("/:id/versions/:version/diff", VersionDiffHandler(strudelRepo))
+		strudelsGroup.POST("/:id/revert/:version", RevertStrudelHandler(strudelRepo, fpIndexer))
 	}
 
 	// public strudels (no auth required)
@@ -35,4 +91,8 @@ func RegisterRoutes(router *gin.RouterGroup, strudelRepo *strudels.Repository, a
 	router.GET("/public/strudels/tags", ListPublicTagsHandler(strudelRepo))
 	router.GET("/public/strudels/:id", GetPublicStrudelHandler(strudelRepo))
 	router.GET("/public/strudels/:id/stats", GetStrudelStatsHandler(strudelRepo, attrService))
+	router.GET("/public/strudels/:id/uses", ListStrudelUsesHandler(strudelRepo, attrService))
+
+	// code linting (no auth required)
+	router.POST("/strudel/validate", ValidateCodeHandler())
 }
@@ -5,14 +5,155 @@ import (
 
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
 	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/attribution"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/strudel"
 )
 
+const (
+	// maximum length of a search query
+	maxSearchQueryLength = 200
+
+	// default and maximum number of similar strudels returned
+	defaultSimilarLimit = 10
+	maxSimilarLimit     = 50
+
+	// returned when moderation blocks a title or description
+	errContentPolicy = "title or description violates the content policy"
+
+	// title of imports without one, and the longest title kept from the code
+	defaultImportTitle   = "Imported strudel"
+	maxImportTitleLength = 200
+
+	// same limit as strudel code
+	maxImportCodeSize = 1048576
+)
+
+// ImportStrudelRequest contains either an export bundle or a strudel.cc share link
+type ImportStrudelRequest struct {
+	Bundle *StrudelBundle `json:"bundle,omitempty"`
+	URL    string         `json:"url,omitempty" binding:"max=2097152"` // share link or its code hash
+	Title  string         `json:"title,omitempty" binding:"max=200"`   // overrides the imported title
+}
+
+// StrudelBundle is a strudel as returned by GET /api/v1/strudels/{id}.
+// other fields of the response (id, author, timestamps) are ignored
+type StrudelBundle struct {
+	Title       string             `json:"title" binding:"max=200"`
+	Code        string             `json:"code" binding:"required,max=1048576"`
+	Description string             `json:"description,omitempty" binding:"max=2000"`
+	Tags        []string           `json:"tags,omitempty" binding:"max=20,dive,max=50"`
+	Categories  []string           `json:"categories,omitempty" binding:"max=10,dive,max=50"`
+	License     *string            `json:"license,omitempty"`
+	CCSignal    *strudels.CCSignal `json:"cc_signal,omitempty"`
+}
+
 // StrudelsListResponse wraps a list of strudels with pagination
 type StrudelsListResponse struct {
 	Strudels   []strudels.Strudel `json:"strudels"`
 	Pagination pagination.Meta    `json:"pagination"`
 }
 
+// StrudelsPageResponse wraps a page of strudels with cursor pagination
+type StrudelsPageResponse struct {
+	Strudels   []strudels.Strudel    `json:"strudels"`
+	Pagination pagination.CursorMeta `json:"pagination"`
+}
+
+// StrudelUsesResponse wraps a page of the strudels a strudel inspired
+type StrudelUsesResponse struct {
+	Uses       []attribution.StrudelUse `json:"uses"`
+	Pagination pagination.CursorMeta    `json:"pagination"`
+}
+
+// VersionsListResponse wraps the versions of a strudel
+type VersionsListResponse struct {
+	Versions []strudels.StrudelVersion `json:"versions"`
+}
+
+// VersionDiffResponse contains a unified diff between two versions of a strudel
+type VersionDiffResponse struct {
+	From int    `json:"from"` // 0 when diffing the first version against empty code
+	To   int    `json:"to"`
+	Diff string `json:"diff"` // empty when the code is identical
+}
+
+// StrudelConflictResponse is returned when an update was based on a stale
+// updated_at. it carries both code bodies so the client can offer a merge
+type StrudelConflictResponse struct {
+	Error      errors.Code       `json:"error"` // always "conflict"
+	Message    string            `json:"message"`
+	Current    *strudels.Strudel `json:"current"`               // as saved; retry with its updated_at
+	ServerCode string            `json:"server_code"`           // same as current.code
+	ClientCode *string           `json:"client_code,omitempty"` // omitted when the update didn't change the code
+	Diff       string            `json:"diff,omitempty"`        // unified diff from server_code to client_code
+}
+
+// ValidateCodeRequest contains the code to lint
+type ValidateCodeRequest struct {
+	Code string `json:"code" binding:"required,max=102400"`
+}
+
+// ValidateCodeResponse contains lint results for the submitted code
+type ValidateCodeResponse struct {
+	Valid       bool                 `json:"valid"`
+	Diagnostics []strudel.Diagnostic `json:"diagnostics"`
+}
+
+// StrudelSearchResponse wraps search results with cursor pagination
+type StrudelSearchResponse struct {
+	Results    []strudels.SearchResult `json:"results"`
+	Pagination pagination.CursorMeta   `json:"pagination"`
+}
+
+// SimilarStrudelDTO is a public strudel similar to the requested one
+type SimilarStrudelDTO struct {
+	ID          string   `json:"id"`
+	UserID      string   `json:"user_id"`
+	AuthorName  string   `json:"author_name"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Code        string   `json:"code"`
+	Tags        []string `json:"tags,omitempty"`
+	Similarity  float32  `json:"similarity"` // cosine similarity, 1 is identical
+}
+
+// SimilarStrudelsResponse wraps strudels similar to the requested one
+type SimilarStrudelsResponse struct {
+	Strudels []SimilarStrudelDTO `json:"strudels"`
+}
+
+// AncestorsResponse wraps a strudel's fork ancestry, direct parent first
+type AncestorsResponse struct {
+	Ancestors []*strudels.LineageNode `json:"ancestors"`
+	Count     int                     `json:"count"`
+}
+
+// ForksResponse wraps a strudel's fork tree
+type ForksResponse struct {
+	Forks  []*strudels.LineageNode `json:"forks"`
+	Counts strudels.ForkCounts     `json:"counts"`
+}
+
+// CCSignalResponse is a strudel's AI usage preferences in machine-readable form
+type CCSignalResponse struct {
+	StrudelID         string             `json:"strudel_id"`
+	CCSignal          *strudels.CCSignal `json:"cc_signal"`           // the strudel's own signal
+	EffectiveCCSignal *strudels.CCSignal `json:"effective_cc_signal"` // what applies, including the fork ancestry
+	AIUse             string             `json:"ai_use" enums:"allowed,disallowed"`
+	Requirements      []string           `json:"requirements"` // conditions on AI use, e.g. credit
+	License           *string            `json:"license"`
+	LicenseURL        string             `json:"license_url,omitempty"`
+}
+
+// BulkResponse reports the outcome of a bulk operation per strudel
+type BulkResponse struct {
+	Applied bool                  `json:"applied"` // false when any item failed and nothing was changed
+	Error   errors.Code           `json:"error,omitempty"`
+	Message string                `json:"message,omitempty"`
+	Results []strudels.BulkResult `json:"results"`
+}
+
 // MessageResponse for simple success messages
 type MessageResponse struct {
 	Message string `json:"message"`
@@ -33,10 +174,12 @@ type StrudelDetailResponse struct {
 	CCSignal            *strudels.CCSignal       `json:"cc_signal,omitempty"`
 	ForkedFrom          *string                  `json:"forked_from,omitempty"`
 	ParentCCSignal      *strudels.CCSignal       `json:"parent_cc_signal,omitempty"`
+	EffectiveCCSignal   *strudels.CCSignal       `json:"effective_cc_signal,omitempty"` // most restrictive signal of the fork ancestry
 	Description         string                   `json:"description,omitempty"`
 	Tags                []string                 `json:"tags,omitempty"`
 	Categories          []string                 `json:"categories,omitempty"`
 	ConversationHistory []ConversationMessageDTO `json:"conversation_history,omitempty"`
+	PreviewURL          *string                  `json:"preview_url,omitempty"` // audio of the latest render
 	CreatedAt           time.Time                `json:"created_at"`
 	UpdatedAt           time.Time                `json:"updated_at"`
 }
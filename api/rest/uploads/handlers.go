@@ -0,0 +1,146 @@
+package uploads
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/assets"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+)
+
+// CreateUploadHandler godoc
+// @Summary Start upload
+// @Description Create a presigned URL to upload a file to. PUT the file to upload_url with the given Content-Type before expires_at, then complete the upload. Avatars may be PNG, JPEG or GIF up to 5 MB and 4096x4096 pixels.
+// @Tags uploads
+// @Accept json
+// @Produce json
+// @Param request body CreateUploadRequest true "What to upload"
+// @Success 201 {object} assets.Upload
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 429 {object} errors.ErrorResponse "too many uploads in progress"
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse "object storage not configured"
+// @Router /api/v1/uploads [post]
+// @Security BearerAuth
+func CreateUploadHandler(assetService *assets.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		var req CreateUploadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		upload, err := assetService.CreateUpload(c.Request.Context(), userID, req.Kind, req.ContentType)
+		if err != nil {
+			handleUploadError(c, err, "failed to create upload")
+			return
+		}
+
+		c.JSON(http.StatusCreated, upload)
+	}
+}
+
+// CompleteUploadHandler godoc
+// @Summary Complete upload
+// @Description Validate and process an uploaded file. Avatars are cropped to a centered square, scaled to 256x256 and become your avatar.
+// @Tags uploads
+// @Produce json
+// @Param id path string true "Asset ID (UUID)"
+// @Success 200 {object} assets.Asset
+// @Failure 400 {object} errors.ErrorResponse "nothing uploaded, too large or not a valid image"
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse "already completed"
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse "object storage not configured"
+// @Router /api/v1/uploads/{id}/complete [post]
+// @Security BearerAuth
+func CompleteUploadHandler(assetService *assets.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		assetID, ok := errors.ValidatePathUUID(c, "id")
+		if !ok {
+			return
+		}
+
+		asset, err := assetService.CompleteUpload(c.Request.Context(), userID, assetID)
+		if err != nil {
+			handleUploadError(c, err, "failed to complete upload")
+			return
+		}
+
+		c.JSON(http.StatusOK, asset)
+	}
+}
+
+// RemoveAvatarHandler godoc
+// @Summary Remove uploaded avatar
+// @Description Remove your uploaded avatar. The file is deleted from storage shortly after.
+// @Tags uploads
+// @Produce json
+// @Success 200 {object} MessageResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse "no uploaded avatar"
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/users/me/avatar [delete]
+// @Security BearerAuth
+func RemoveAvatarHandler(assetService *assets.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		removed, err := assetService.RemoveAvatar(c.Request.Context(), userID)
+		if err != nil {
+			errors.InternalError(c, "failed to remove avatar", err)
+			return
+		}
+
+		if !removed {
+			errors.NotFound(c, "uploaded avatar")
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "avatar removed"})
+	}
+}
+
+func handleUploadError(c *gin.Context, err error, message string) {
+	switch {
+	case stderrors.Is(err, assets.ErrNotConfigured):
+		errors.ServiceUnavailable(c, "uploads are not available")
+	case stderrors.Is(err, assets.ErrTooManyUploads):
+		errors.TooManyRequests(c, "too many uploads in progress, complete them or try again later")
+	case stderrors.Is(err, assets.ErrAssetNotFound):
+		errors.NotFound(c, "upload")
+	case stderrors.Is(err, assets.ErrAlreadyComplete):
+		errors.Conflict(c, "upload already completed")
+	case stderrors.Is(err, assets.ErrTooLarge):
+		errors.BadRequest(c, fmt.Sprintf("uploads must be at most %d MB", assets.MaxUploadBytes>>20), nil)
+	case stderrors.Is(err, assets.ErrUploadMissing),
+		stderrors.Is(err, assets.ErrInvalidImage),
+		stderrors.Is(err, assets.ErrUnsupportedType),
+		stderrors.Is(err, assets.ErrUnsupportedKind):
+		errors.BadRequest(c, err.Error(), nil)
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
@@ -0,0 +1,18 @@
+package uploads
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/assets"
+	"codeberg.org/algopatterns/server/internal/auth"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, assetService *assets.Service) {
+	uploads := router.Group("/uploads")
+	uploads.Use(auth.AuthMiddleware())
+
+	uploads.POST("", CreateUploadHandler(assetService))
+	uploads.POST("/:id/complete", CompleteUploadHandler(assetService))
+
+	router.DELETE("/users/me/avatar", auth.AuthMiddleware(), RemoveAvatarHandler(assetService))
+}
@@ -0,0 +1,10 @@
+package uploads
+
+type CreateUploadRequest struct {
+	Kind        string `json:"kind" binding:"required,oneof=avatar"`
+	ContentType string `json:"content_type" binding:"required,oneof=image/png image/jpeg image/gif"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -2,9 +2,12 @@ package users
 
 import (
 	"net/http"
+	"time"
 
 	"codeberg.org/algopatterns/server/algopatterns/users"
 	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/mailer"
+	"codeberg.org/algopatterns/server/internal/quota"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -97,6 +100,60 @@ func GetUsage(db *pgxpool.Pool) gin.HandlerFunc {
 	}
 }
 
+// GetMonthlyUsage godoc
+// @Summary Get user's monthly AI usage
+// @Description Returns the authenticated user's AI generations, tokens and estimated cost for a calendar month (UTC) per provider and model. The current month also includes the remaining quota, including the monthly spend limit
+// @Tags users
+// @Produce json
+// @Param month query string false "Month as YYYY-MM (default: current month)"
+// @Success 200 {object} MonthlyUsageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/users/me/usage [get]
+// @Security BearerAuth
+func GetMonthlyUsage(ledger UsageLedger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		if userID == "" {
+			errors.Unauthorized(c, "user not authenticated")
+			return
+		}
+
+		now := time.Now().UTC()
+		month := now
+
+		if value := c.Query("month"); value != "" {
+			parsed, err := time.Parse("2006-01", value)
+			if err != nil {
+				errors.BadRequest(c, "month must be formatted as YYYY-MM", err)
+				return
+			}
+
+			month = parsed
+		}
+
+		usage, err := ledger.MonthlyUsage(c.Request.Context(), userID, month)
+		if err != nil {
+			errors.InternalError(c, "failed to fetch usage", err)
+			return
+		}
+
+		response := MonthlyUsageResponse{MonthlyUsage: usage}
+
+		if usage.Month == now.Format("2006-01") {
+			response.Quota, err = ledger.Status(c.Request.Context(), quota.Subject{UserID: userID})
+			if err != nil {
+				errors.InternalError(c, "failed to fetch quota", err)
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 // UpdateTrainingConsent godoc
 // @Summary Update user's training consent
 // @Description Toggle whether the user's public strudels can be used for AI training
@@ -110,7 +167,7 @@ func GetUsage(db *pgxpool.Pool) gin.HandlerFunc {
 // @Failure 500 {object} errors.ErrorResponse
 // @Router /api/v1/users/training-consent [put]
 // @Security BearerAuth
-func UpdateTrainingConsent(db *pgxpool.Pool) gin.HandlerFunc {
+func UpdateTrainingConsent(db *pgxpool.Pool, indexer StrudelIndexer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID := c.GetString("user_id")
 
@@ -132,6 +189,9 @@ func UpdateTrainingConsent(db *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
+		// consent decides whether the user's public strudels are RAG examples
+		indexer.IndexUserAsync(c.Request.Context(), userID)
+
 		c.JSON(http.StatusOK, user)
 	}
 }
@@ -213,3 +273,73 @@ func UpdateDisplayName(db *pgxpool.Pool) gin.HandlerFunc {
 		c.JSON(http.StatusOK, user)
 	}
 }
+
+// GetEmailPreferences godoc
+// @Summary Get user's email preferences
+// @Description Get which emails the authenticated user receives. How often the notification digest is sent is set in the notification preferences
+// @Tags users
+// @Produce json
+// @Success 200 {object} mailer.Preferences
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/users/email-preferences [get]
+// @Security BearerAuth
+func GetEmailPreferences(emailPreferences EmailPreferences) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		if userID == "" {
+			errors.Unauthorized(c, "user not authenticated")
+			return
+		}
+
+		preferences, err := emailPreferences.GetPreferences(c.Request.Context(), userID)
+		if err != nil {
+			errors.InternalError(c, "failed to get email preferences", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, preferences)
+	}
+}
+
+// UpdateEmailPreferences godoc
+// @Summary Update user's email preferences
+// @Description Choose whether the authenticated user receives session invitations by email, or no email at all
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body UpdateEmailPreferencesRequest true "Email preferences"
+// @Success 200 {object} mailer.Preferences
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/users/email-preferences [put]
+// @Security BearerAuth
+func UpdateEmailPreferences(emailPreferences EmailPreferences) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+
+		if userID == "" {
+			errors.Unauthorized(c, "user not authenticated")
+			return
+		}
+
+		var req UpdateEmailPreferencesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		preferences, err := emailPreferences.UpdatePreferences(c.Request.Context(), userID, mailer.Preferences{
+			Invites:      req.Invites,
+			Unsubscribed: req.Unsubscribed,
+		})
+		if err != nil {
+			errors.InternalError(c, "failed to update email preferences", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, preferences)
+	}
+}
@@ -1,17 +1,42 @@
 package users
 
 import (
+	"context"
+	"time"
+
 	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/mailer"
+	"codeberg.org/algopatterns/server/internal/quota"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func RegisterRoutes(rg *gin.RouterGroup, db *pgxpool.Pool) {
+// re-syncs a user's strudels in the RAG examples index
+type StrudelIndexer interface {
+	IndexUserAsync(ctx context.Context, userID string)
+}
+
+// stores which emails a user receives (implemented by mailer.Service)
+type EmailPreferences interface {
+	GetPreferences(ctx context.Context, userID string) (*mailer.Preferences, error)
+	UpdatePreferences(ctx context.Context, userID string, preferences mailer.Preferences) (*mailer.Preferences, error)
+}
+
+// the AI usage ledger and quotas (implemented by quota.Service)
+type UsageLedger interface {
+	MonthlyUsage(ctx context.Context, userID string, month time.Time) (*quota.MonthlyUsage, error)
+	Status(ctx context.Context, subject quota.Subject) (*quota.Result, error)
+}
+
+func RegisterRoutes(rg *gin.RouterGroup, db *pgxpool.Pool, indexer StrudelIndexer, emailPreferences EmailPreferences, usage UsageLedger) {
 	users := rg.Group("/users")
 	users.Use(auth.AuthMiddleware()) // all user routes require authentication
 
 	users.GET("/usage", GetUsage(db))
-	users.PUT("/training-consent", UpdateTrainingConsent(db))
+	users.GET("/me/usage", GetMonthlyUsage(usage))
+	users.PUT("/training-consent", UpdateTrainingConsent(db, indexer))
 	users.PUT("/ai-features-enabled", UpdateAIFeaturesEnabled(db))
 	users.PUT("/display-name", UpdateDisplayName(db))
+	users.GET("/email-preferences", GetEmailPreferences(emailPreferences))
+	users.PUT("/email-preferences", UpdateEmailPreferences(emailPreferences))
 }
@@ -1,5 +1,7 @@
 package users
 
+import "codeberg.org/algopatterns/server/internal/quota"
+
 type UsageResponse struct {
 	Tier      string       `json:"tier"`      // "free", "payg", "byok"
 	Today     int          `json:"today"`     // Generations used today
@@ -13,6 +15,13 @@ type DailyUsage struct {
 	Count int    `json:"count"` // Number of generations
 }
 
+// AI usage of one month with its estimated cost, and the caller's quota when
+// the month is the current one
+type MonthlyUsageResponse struct {
+	*quota.MonthlyUsage
+	Quota *quota.Result `json:"quota,omitempty"`
+}
+
 type TrainingConsentRequest struct {
 	TrainingConsent bool `json:"training_consent"`
 }
@@ -24,3 +33,8 @@ type AIFeaturesEnabledRequest struct {
 type UpdateDisplayNameRequest struct {
 	DisplayName string `json:"display_name" binding:"required,min=1,max=50"`
 }
+
+type UpdateEmailPreferencesRequest struct {
+	Invites      bool `json:"invites"`      // session invitations
+	Unsubscribed bool `json:"unsubscribed"` // no email at all, including digests
+}
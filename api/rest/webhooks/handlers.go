@@ -0,0 +1,334 @@
+package webhooks
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/webhooks"
+)
+
+// ListEventsHandler godoc
+// @Summary List webhook events
+// @Description Get the events a webhook can subscribe to
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} EventsListResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Router /api/v1/webhooks/events [get]
+// @Security BearerAuth
+func ListEventsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, EventsListResponse{Events: webhooks.Events})
+	}
+}
+
+// CreateWebhookHandler godoc
+// @Summary Create webhook
+// @Description Register an https endpoint for events. The response includes the signing secret, which is not shown again.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body CreateWebhookRequest true "Webhook URL and events"
+// @Success 201 {object} WebhookWithSecretResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/webhooks [post]
+// @Security BearerAuth
+func CreateWebhookHandler(webhookService *webhooks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		var req CreateWebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		webhook, err := webhookService.Create(c.Request.Context(), userID, req.URL, req.Events)
+		if err != nil {
+			handleWebhookError(c, err, "failed to create webhook")
+			return
+		}
+
+		c.JSON(http.StatusCreated, WebhookWithSecretResponse{Webhook: webhook, Secret: webhook.Secret})
+	}
+}
+
+// ListWebhooksHandler godoc
+// @Summary List webhooks
+// @Description Get the authenticated user's webhooks
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} WebhooksListResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/webhooks [get]
+// @Security BearerAuth
+func ListWebhooksHandler(webhookService *webhooks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		list, err := webhookService.List(c.Request.Context(), userID)
+		if err != nil {
+			errors.InternalError(c, "failed to list webhooks", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, WebhooksListResponse{Webhooks: list})
+	}
+}
+
+// GetWebhookHandler godoc
+// @Summary Get webhook
+// @Description Get a webhook owned by the authenticated user
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 200 {object} webhooks.Webhook
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/webhooks/{id} [get]
+// @Security BearerAuth
+func GetWebhookHandler(webhookService *webhooks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, webhookID, ok := ownerAndWebhookID(c)
+		if !ok {
+			return
+		}
+
+		webhook, err := webhookService.Get(c.Request.Context(), webhookID, userID)
+		if err != nil {
+			handleWebhookError(c, err, "failed to get webhook")
+			return
+		}
+
+		c.JSON(http.StatusOK, webhook)
+	}
+}
+
+// UpdateWebhookHandler godoc
+// @Summary Update webhook
+// @Description Change the URL, events or active state of a webhook
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Param request body UpdateWebhookRequest true "Fields to update"
+// @Success 200 {object} webhooks.Webhook
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/webhooks/{id} [patch]
+// @Security BearerAuth
+func UpdateWebhookHandler(webhookService *webhooks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, webhookID, ok := ownerAndWebhookID(c)
+		if !ok {
+			return
+		}
+
+		var req UpdateWebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.ValidationError(c, err)
+			return
+		}
+
+		webhook, err := webhookService.Update(c.Request.Context(), webhookID, userID, webhooks.UpdateWebhookRequest{
+			URL:    req.URL,
+			Events: req.Events,
+			Active: req.Active,
+		})
+		if err != nil {
+			handleWebhookError(c, err, "failed to update webhook")
+			return
+		}
+
+		c.JSON(http.StatusOK, webhook)
+	}
+}
+
+// DeleteWebhookHandler godoc
+// @Summary Delete webhook
+// @Description Delete a webhook and its delivery log
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/webhooks/{id} [delete]
+// @Security BearerAuth
+func DeleteWebhookHandler(webhookService *webhooks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, webhookID, ok := ownerAndWebhookID(c)
+		if !ok {
+			return
+		}
+
+		if err := webhookService.Delete(c.Request.Context(), webhookID, userID); err != nil {
+			handleWebhookError(c, err, "failed to delete webhook")
+			return
+		}
+
+		c.JSON(http.StatusOK, MessageResponse{Message: "webhook deleted successfully"})
+	}
+}
+
+// RotateSecretHandler godoc
+// @Summary Rotate webhook secret
+// @Description Replace the signing secret of a webhook. The old secret stops working immediately.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 200 {object} WebhookWithSecretResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/webhooks/{id}/rotate-secret [post]
+// @Security BearerAuth
+func RotateSecretHandler(webhookService *webhooks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, webhookID, ok := ownerAndWebhookID(c)
+		if !ok {
+			return
+		}
+
+		webhook, err := webhookService.RotateSecret(c.Request.Context(), webhookID, userID)
+		if err != nil {
+			handleWebhookError(c, err, "failed to rotate webhook secret")
+			return
+		}
+
+		c.JSON(http.StatusOK, WebhookWithSecretResponse{Webhook: webhook, Secret: webhook.Secret})
+	}
+}
+
+// PingWebhookHandler godoc
+// @Summary Test webhook
+// @Description Queue a ping event to the webhook. The result appears in the delivery log.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 202 {object} webhooks.Delivery
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Router /api/v1/webhooks/{id}/ping [post]
+// @Security BearerAuth
+func PingWebhookHandler(webhookService *webhooks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, webhookID, ok := ownerAndWebhookID(c)
+		if !ok {
+			return
+		}
+
+		delivery, err := webhookService.Ping(c.Request.Context(), webhookID, userID)
+		if err != nil {
+			handleWebhookError(c, err, "failed to ping webhook")
+			return
+		}
+
+		c.JSON(http.StatusAccepted, delivery)
+	}
+}
+
+// ListDeliveriesHandler godoc
+// @Summary List webhook deliveries
+// @Description Get the delivery log of a webhook, newest first (kept for 30 days)
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Param limit query int false "Items per page (max 100)" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} DeliveriesListResponse
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+// @Security BearerAuth
+func ListDeliveriesHandler(webhookService *webhooks.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, webhookID, ok := ownerAndWebhookID(c)
+		if !ok {
+			return
+		}
+
+		limit, offset := parsePaginationParams(c)
+		params := pagination.DefaultParams(limit, offset, 20, 100)
+
+		deliveries, total, err := webhookService.ListDeliveries(c.Request.Context(), webhookID, userID, params.Limit, params.Offset)
+		if err != nil {
+			handleWebhookError(c, err, "failed to list webhook deliveries")
+			return
+		}
+
+		c.JSON(http.StatusOK, DeliveriesListResponse{
+			Deliveries: deliveries,
+			Pagination: pagination.NewMeta(params, total),
+		})
+	}
+}
+
+func parsePaginationParams(c *gin.Context) (limit, offset int) {
+	if l, ok := c.GetQuery("limit"); ok {
+		if parsedLimit, err := strconv.Atoi(l); err == nil {
+			limit = parsedLimit
+		}
+	}
+	if o, ok := c.GetQuery("offset"); ok {
+		if parsedOffset, err := strconv.Atoi(o); err == nil {
+			offset = parsedOffset
+		}
+	}
+	return limit, offset
+}
+
+// gets the authenticated user and the webhook id path param.
+// writes the error response and returns false if either is missing.
+func ownerAndWebhookID(c *gin.Context) (userID, webhookID string, ok bool) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		errors.Unauthorized(c, "")
+		return "", "", false
+	}
+
+	webhookID, ok = errors.ValidatePathUUID(c, "id")
+	return userID, webhookID, ok
+}
+
+// maps webhook service errors to responses
+func handleWebhookError(c *gin.Context, err error, message string) {
+	switch {
+	case stderrors.Is(err, webhooks.ErrWebhookNotFound):
+		errors.NotFound(c, "webhook")
+	case stderrors.Is(err, webhooks.ErrTooManyWebhooks):
+		errors.Conflict(c, "webhook limit reached")
+	case stderrors.Is(err, webhooks.ErrInvalidURL),
+		stderrors.Is(err, webhooks.ErrBlockedAddress),
+		stderrors.Is(err, webhooks.ErrUnknownEvent),
+		stderrors.Is(err, webhooks.ErrNoEvents):
+		errors.BadRequest(c, err.Error(), nil)
+	default:
+		errors.InternalError(c, message, err)
+	}
+}
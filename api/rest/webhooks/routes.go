@@ -0,0 +1,23 @@
+package webhooks
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/webhooks"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, webhookService *webhooks.Service) {
+	group := router.Group("/webhooks")
+	group.Use(auth.AuthMiddleware()) // webhooks are managed by their owner only
+
+	group.GET("/events", ListEventsHandler())
+	group.POST("", CreateWebhookHandler(webhookService))
+	group.GET("", ListWebhooksHandler(webhookService))
+	group.GET("/:id", GetWebhookHandler(webhookService))
+	group.PATCH("/:id", UpdateWebhookHandler(webhookService))
+	group.DELETE("/:id", DeleteWebhookHandler(webhookService))
+	group.POST("/:id/rotate-secret", RotateSecretHandler(webhookService))
+	group.POST("/:id/ping", PingWebhookHandler(webhookService))
+	group.GET("/:id/deliveries", ListDeliveriesHandler(webhookService))
+}
@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"codeberg.org/algopatterns/server/api/rest/pagination"
+	"codeberg.org/algopatterns/server/internal/webhooks"
+)
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,max=10,dive,max=50"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url,omitempty" binding:"omitempty,url,max=2048"`
+	Events []string `json:"events,omitempty" binding:"omitempty,min=1,max=10,dive,max=50"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+// WebhookWithSecretResponse includes the signing secret, only returned on create and rotate
+type WebhookWithSecretResponse struct {
+	*webhooks.Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhooksListResponse wraps a user's webhooks
+type WebhooksListResponse struct {
+	Webhooks []*webhooks.Webhook `json:"webhooks"`
+}
+
+// DeliveriesListResponse wraps a webhook's delivery log with pagination
+type DeliveriesListResponse struct {
+	Deliveries []*webhooks.Delivery `json:"deliveries"`
+	Pagination pagination.Meta      `json:"pagination"`
+}
+
+// EventsListResponse lists the events webhooks can subscribe to
+type EventsListResponse struct {
+	Events []string `json:"events"`
+}
+
+// MessageResponse for simple success messages
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"time"
 
@@ -11,21 +12,53 @@ import (
 	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/algopatterns/users"
 	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/embeds"
 	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/joinrequests"
 	"codeberg.org/algopatterns/server/internal/logger"
 	ws "codeberg.org/algopatterns/server/internal/websocket"
 )
 
 var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin:     ws.CheckOrigin,
+	ReadBufferSize:    1024,
+	WriteBufferSize:   1024,
+	CheckOrigin:       ws.CheckOrigin,
+	HandshakeTimeout:  10 * time.Second,                // writing the 101 response to a client that doesn't read
+	EnableCompression: true,                            // permessage-deflate, used when the client offers it
+	Subprotocols:      []string{ws.SubprotocolMsgpack}, // clients offering none get JSON text frames
 }
 
-// handles WebSocket connections for real-time collaboration.
-// see docs/websocket/API.md for usage documentation.
-func WebSocketHandler(hub *ws.Hub, sessionRepo sessions.Repository, userRepo *users.Repository) gin.HandlerFunc {
+// WebSocketHandler godoc
+// @Summary Connect to a live session
+// @Description Upgrades to a WebSocket for real-time collaboration. Without session_id a new anonymous session is created. Frames are JSON text frames, or msgpack binary frames when the client offers the msgpack subprotocol. Every frame is a Message whose payload is the MessageCatalog field named by its type. See docs/websocket/API.md
+// @Tags websocket
+// @Param session_id query string false "Session to join; a new anonymous session is created without it"
+// @Param previous_session_id query string false "Session whose code a new session starts from"
+// @Param ticket query string false "One-time ticket of a signed-in user, from POST /api/v1/ws/ticket"
+// @Param token query string false "Access token of a signed-in user. Ends up in logs and browser history, use ticket instead"
+// @Param invite query string false "Invite token to join with"
+// @Param display_name query string false "Display name of an anonymous user (max 100 characters)"
+// @Param resume query bool false "Hold back session_state until the client sends resume"
+// @Param audience query bool false "Join a discoverable session as a read-only listener"
+// @Param spectator query bool false "Join a discoverable session as a listener who can chat (requires token)"
+// @Param embed query string false "Embed token, joins its session as a read-only embed"
+// @Success 101 {object} ws.Message{payload=MessageCatalog} "Switching Protocols"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 429 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Failure 503 {object} errors.ErrorResponse
+// @Router /api/v1/ws [get]
+func WebSocketHandler(hub *ws.Hub, sessionRepo sessions.Repository, userRepo *users.Repository, embedService *embeds.Service, joinRequests *joinrequests.Service, tickets *auth.TicketStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// a deploy is replacing this instance, the load balancer retries elsewhere
+		if hub.IsDraining() {
+			errors.ServiceUnavailable(c, "server is restarting, reconnect shortly")
+			return
+		}
+
 		var params ConnectParams
 		if err := c.ShouldBindQuery(&params); err != nil {
 			errors.BadRequest(c, "invalid parameters", err)
@@ -36,6 +69,34 @@ func WebSocketHandler(hub *ws.Hub, sessionRepo sessions.Repository, userRepo *us
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 		defer cancel()
 
+		authUserID, ok := authenticate(ctx, c, tickets, params)
+		if !ok {
+			return
+		}
+
+		// banned users keep valid tokens until they expire, so check the account
+		if authUserID != "" {
+			if user, err := userRepo.FindByID(ctx, authUserID); err == nil && user.IsBanned() {
+				errors.Forbidden(c, "account suspended")
+				return
+			}
+		}
+
+		if params.Embed != "" {
+			connectEmbed(ctx, c, hub, embedService, params)
+			return
+		}
+
+		if params.Spectator {
+			connectSpectator(ctx, c, hub, sessionRepo, userRepo, params, authUserID)
+			return
+		}
+
+		if params.Audience {
+			connectAudience(ctx, c, hub, sessionRepo, params)
+			return
+		}
+
 		var session *sessions.Session
 		var userID string
 		var displayName string
@@ -43,50 +104,47 @@ func WebSocketHandler(hub *ws.Hub, sessionRepo sessions.Repository, userRepo *us
 
 		// case 1: No session_id provided - create new anonymous session
 		if params.SessionID == "" {
-			// check for JWT token first (authenticated user creating session)
-			if params.Token != "" {
-				claims, err := auth.ValidateJWT(params.Token)
-				if err == nil {
-					userID = claims.UserID
-
-					// create session with authenticated user as host
-					newSession, err := sessionRepo.CreateSession(ctx, &sessions.CreateSessionRequest{
-						HostUserID: userID,
-						Title:      "New Session",
-						Code:       "",
-					})
-					if err != nil {
-						errors.InternalError(c, "failed to create session", err)
-						return
-					}
+			// a signed-in user creating a session hosts it
+			if authUserID != "" {
+				userID = authUserID
+
+				// create session with authenticated user as host
+				newSession, err := sessionRepo.CreateSession(ctx, &sessions.CreateSessionRequest{
+					HostUserID: userID,
+					Title:      "New Session",
+					Code:       "",
+				}, nil)
+				if err != nil {
+					errors.InternalError(c, "failed to create session", err)
+					return
+				}
 
-					session = newSession
-					role = "host"
-					// get user's actual name, default to "Host" if not found
-					if user, err := userRepo.FindByID(ctx, userID); err == nil && user.Name != "" {
-						displayName = user.Name
-					} else {
-						displayName = "Host"
-					}
+				session = newSession
+				role = "host"
+				// get user's actual name, default to "Host" if not found
+				if user, err := userRepo.FindByID(ctx, userID); err == nil && user.Name != "" {
+					displayName = user.Name
+				} else {
+					displayName = "Host"
+				}
 
-					// copy code from previous session if provided
-					if params.PreviousSessionID != "" {
-						oldSession, err := sessionRepo.GetSession(ctx, params.PreviousSessionID)
-						if err == nil && oldSession.Code != "" {
-							session.Code = oldSession.Code
-							if updateErr := sessionRepo.UpdateSessionCode(ctx, session.ID, oldSession.Code); updateErr != nil {
-								logger.Warn("failed to copy code from previous session",
-									"new_session_id", session.ID,
-									"previous_session_id", params.PreviousSessionID,
-									"error", updateErr,
-								)
-							}
+				// copy code from previous session if provided
+				if params.PreviousSessionID != "" {
+					oldSession, err := sessionRepo.GetSession(ctx, params.PreviousSessionID)
+					if err == nil && oldSession.Code != "" {
+						session.Code = oldSession.Code
+						if updateErr := sessionRepo.UpdateSessionCode(ctx, session.ID, oldSession.Code); updateErr != nil {
+							logger.Warn("failed to copy code from previous session",
+								"new_session_id", session.ID,
+								"previous_session_id", params.PreviousSessionID,
+								"error", updateErr,
+							)
 						}
 					}
 				}
 			}
 
-			// no valid JWT - create anonymous session
+			// not signed in - create anonymous session
 			if session == nil {
 				newSession, err := sessionRepo.CreateAnonymousSession(ctx)
 				if err != nil {
@@ -124,21 +182,18 @@ func WebSocketHandler(hub *ws.Hub, sessionRepo sessions.Repository, userRepo *us
 				return
 			}
 
-			// try JWT authentication (for user identity, not role assignment)
-			if params.Token != "" {
-				claims, err := auth.ValidateJWT(params.Token)
-				if err == nil {
-					userID = claims.UserID
+			// the signed-in user's identity (not role assignment)
+			if authUserID != "" {
+				userID = authUserID
 
-					// only assign host role here, other roles determined below
-					if userID == session.HostUserID {
-						role = "host"
-						// get user's actual name
-						if user, err := userRepo.FindByID(ctx, userID); err == nil && user.Name != "" {
-							displayName = user.Name
-						} else {
-							displayName = "Host"
-						}
+				// only assign host role here, other roles determined below
+				if userID == session.HostUserID {
+					role = "host"
+					// get user's actual name
+					if user, err := userRepo.FindByID(ctx, userID); err == nil && user.Name != "" {
+						displayName = user.Name
+					} else {
+						displayName = "Host"
 					}
 				}
 			}
@@ -156,7 +211,27 @@ func WebSocketHandler(hub *ws.Hub, sessionRepo sessions.Repository, userRepo *us
 					return
 				}
 
-				if inviteToken.MaxUses != nil && inviteToken.UsesCount >= *inviteToken.MaxUses {
+				// email-bound invites only admit the invited user
+				if inviteToken.Email != nil {
+					if userID == "" {
+						errors.InvalidInvite(c, "sign in with the email address this invite was sent to")
+						return
+					}
+
+					if verified, err := userRepo.HasVerifiedEmail(ctx, userID, *inviteToken.Email); err != nil || !verified {
+						errors.Forbidden(c, "this invite was sent to an email address your account hasn't verified")
+						return
+					}
+				}
+
+				// counts the use, failing when concurrent joins used up the token
+				claimed, err := sessionRepo.ClaimInviteToken(ctx, inviteToken.ID)
+				if err != nil {
+					errors.InternalError(c, "failed to join session", err)
+					return
+				}
+
+				if !claimed {
 					errors.Forbidden(c, "invite token has reached maximum uses")
 					return
 				}
@@ -177,12 +252,24 @@ func WebSocketHandler(hub *ws.Hub, sessionRepo sessions.Repository, userRepo *us
 				} else {
 					displayName = fmt.Sprintf("Anonymous %s", inviteToken.Role)
 				}
+			}
+
+			// an approved join request admits the user with the role the host granted
+			if role == "" && userID != "" {
+				if admittedRole, err := joinRequests.AdmittedRole(ctx, params.SessionID, userID); err == nil {
+					role = admittedRole
 
-				// increment invite token usage count
-				if err := sessionRepo.IncrementTokenUses(ctx, inviteToken.ID); err != nil {
-					logger.Warn("failed to increment invite token uses",
+					if user, err := userRepo.FindByID(ctx, userID); err == nil && user.Name != "" {
+						displayName = user.Name
+					} else if params.DisplayName != "" {
+						displayName = params.DisplayName
+					} else {
+						displayName = "User"
+					}
+				} else if !stderrors.Is(err, joinrequests.ErrNotAdmitted) {
+					logger.Warn("failed to check join requests",
 						"session_id", params.SessionID,
-						"token_id", inviteToken.ID,
+						"user_id", userID,
 						"error", err,
 					)
 				}
@@ -246,53 +333,37 @@ func WebSocketHandler(hub *ws.Hub, sessionRepo sessions.Repository, userRepo *us
 		isAuthenticated := userID != ""
 		initialCode := session.Code
 
-		// fetch chat history for the session (chat is session-scoped)
-		var chatHistory []ws.SessionStateChatMessage
-		messages, err := sessionRepo.GetChatMessages(ctx, params.SessionID, 50)
-		if err != nil {
-			logger.Warn("failed to fetch chat history",
-				"session_id", params.SessionID,
-				"error", err,
-			)
-		} else {
-			for _, msg := range messages {
-				msgDisplayName := ""
-				if msg.DisplayName != nil {
-					msgDisplayName = *msg.DisplayName
-				}
-				avatarURL := ""
-				if msg.AvatarURL != nil {
-					avatarURL = *msg.AvatarURL
-				}
-				chatHistory = append(chatHistory, ws.SessionStateChatMessage{
-					DisplayName: msgDisplayName,
-					AvatarURL:   avatarURL,
-					Content:     msg.Content,
-					Timestamp:   msg.CreatedAt.UnixMilli(),
-				})
-			}
-		}
+		chatHistory := fetchChatHistory(ctx, sessionRepo, params.SessionID)
 
 		client := ws.NewClient(clientID, params.SessionID, userID, displayName, role, ipAddress, initialCode, chatHistory, isAuthenticated, conn, hub)
+		client.DeferSessionState = params.Resume
+		client.ChatSlowMode = session.ChatSlowMode
 
 		// add participant to session (authenticated or anonymous)
 		// note: anonymous hosts are not added to participants table as they're already tracked via the session itself
+		// returning participants keep the permissions the host gave them
 		if isAuthenticated {
-			_, err = sessionRepo.AddAuthenticatedParticipant(ctx, params.SessionID, userID, displayName, role)
+			participant, err := sessionRepo.AddAuthenticatedParticipant(ctx, params.SessionID, userID, displayName, role)
 			if err != nil {
 				logger.Warn("failed to add authenticated participant",
 					"session_id", params.SessionID,
 					"user_id", userID,
 					"error", err,
 				)
+			} else {
+				client.ParticipantID = participant.ID
+				client.SetPermissions(participant.PermissionOverride)
+				client.SetMuted(participant.MutedAt != nil)
 			}
 		} else if role != "host" {
-			_, err = sessionRepo.AddAnonymousParticipant(ctx, params.SessionID, displayName, role)
+			participant, err := sessionRepo.AddAnonymousParticipant(ctx, params.SessionID, displayName, role)
 			if err != nil {
 				logger.Warn("failed to add anonymous participant",
 					"session_id", params.SessionID,
 					"error", err,
 				)
+			} else {
+				client.ParticipantID = participant.ID
 			}
 		}
 
@@ -310,3 +381,243 @@ func WebSocketHandler(hub *ws.Hub, sessionRepo sessions.Repository, userRepo *us
 		)
 	}
 }
+
+// resolves the signed-in user of a connection from its one-time ticket or,
+// from older clients, its access token. a bad ticket is refused, while a bad
+// token connects anonymously as before. false once an error was sent
+func authenticate(ctx context.Context, c *gin.Context, tickets *auth.TicketStore, params ConnectParams) (string, bool) {
+	if params.Ticket != "" {
+		userID, err := tickets.Redeem(ctx, params.Ticket)
+		if stderrors.Is(err, auth.ErrInvalidWSTicket) {
+			errors.Unauthorized(c, "invalid, expired or used ticket, request a new one")
+			return "", false
+		}
+
+		if err != nil {
+			errors.InternalError(c, "failed to check ticket", err)
+			return "", false
+		}
+
+		return userID, true
+	}
+
+	if params.Token != "" {
+		if claims, err := auth.ValidateJWT(params.Token); err == nil {
+			return claims.UserID, true
+		}
+	}
+
+	return "", true
+}
+
+// connects a read-only audience listener to a live, discoverable session.
+// listeners don't join the participants table, get no chat history and
+// can't resume, so reconnecting simply starts from a fresh session_state.
+func connectAudience(ctx context.Context, c *gin.Context, hub *ws.Hub, sessionRepo sessions.Repository, params ConnectParams) {
+	session, ok := audienceSession(ctx, c, sessionRepo, params)
+	if !ok {
+		return
+	}
+
+	acceptListener(c, hub, session.ID, session.Code, "Listener", "audience", nil)
+}
+
+// connects a spectator to a live, discoverable session. spectators are
+// audience listeners who can chat; they sign in and join the participants
+// table, so the host can mute them.
+func connectSpectator(ctx context.Context, c *gin.Context, hub *ws.Hub, sessionRepo sessions.Repository, userRepo *users.Repository, params ConnectParams, userID string) {
+	if userID == "" {
+		errors.Unauthorized(c, "spectators must be signed in")
+		return
+	}
+
+	session, ok := audienceSession(ctx, c, sessionRepo, params)
+	if !ok {
+		return
+	}
+
+	displayName := "Spectator"
+	if user, err := userRepo.FindByID(ctx, userID); err == nil && user.Name != "" {
+		displayName = user.Name
+	}
+
+	// returning users keep their participant record, and with it a mute
+	participant, err := sessionRepo.AddAuthenticatedParticipant(ctx, session.ID, userID, displayName, "spectator")
+	if err != nil {
+		errors.InternalError(c, "failed to join session", err)
+		return
+	}
+
+	chatHistory := fetchChatHistory(ctx, sessionRepo, session.ID)
+
+	acceptListener(c, hub, session.ID, session.Code, displayName, "spectator", func(client *ws.Client) {
+		client.UserID = userID
+		client.IsAuthenticated = true
+		client.InitialChatHistory = chatHistory
+		client.ParticipantID = participant.ID
+		client.ChatSlowMode = session.ChatSlowMode
+		client.SetMuted(participant.MutedAt != nil)
+	})
+}
+
+// gets the session of an audience connection, responding with an error
+// unless it is live and discoverable
+func audienceSession(ctx context.Context, c *gin.Context, sessionRepo sessions.Repository, params ConnectParams) (*sessions.Session, bool) {
+	if !errors.IsValidUUID(params.SessionID) {
+		errors.BadRequest(c, "audience connections require a valid session_id", nil)
+		return nil, false
+	}
+
+	session, err := sessionRepo.GetSession(ctx, params.SessionID)
+	if err != nil {
+		errors.SessionNotFound(c)
+		return nil, false
+	}
+
+	if !session.IsActive {
+		errors.Forbidden(c, "session has ended")
+		return nil, false
+	}
+
+	if !session.IsDiscoverable {
+		errors.Forbidden(c, "session is not open to an audience")
+		return nil, false
+	}
+
+	return session, true
+}
+
+// connects a read-only embed to the live session of an embed token. embeds
+// work like audience listeners, but the token stands in for discoverability
+// and they aren't shown the performers.
+func connectEmbed(ctx context.Context, c *gin.Context, hub *ws.Hub, embedService *embeds.Service, params ConnectParams) {
+	view, err := embedService.Resolve(ctx, params.Embed)
+	if stderrors.Is(err, embeds.ErrTokenNotFound) {
+		errors.NotFound(c, "embed")
+		return
+	}
+
+	if err != nil {
+		errors.InternalError(c, "failed to load embed", err)
+		return
+	}
+
+	if view.Kind != embeds.KindSession {
+		errors.BadRequest(c, "only session embeds can connect", nil)
+		return
+	}
+
+	if !view.IsLive {
+		errors.Forbidden(c, "session has ended")
+		return
+	}
+
+	acceptListener(c, hub, view.TargetID, view.Code, "Embed", "embed", nil)
+}
+
+// upgrades a listener connection and registers it with the hub. setup, if
+// given, fills in the client before it is registered
+func acceptListener(c *gin.Context, hub *ws.Hub, sessionID, code, displayName, role string, setup func(*ws.Client)) {
+	ipAddress := c.ClientIP()
+	canAccept, reason := hub.CanAcceptAudience(sessionID, ipAddress)
+
+	if !canAccept {
+		errors.TooManyRequests(c, reason)
+		return
+	}
+
+	clientID, err := ws.GenerateClientID()
+	if err != nil {
+		errors.InternalError(c, "failed to generate client ID", err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.ErrorErr(err, "failed to upgrade connection",
+			"session_id", sessionID,
+			"ip", ipAddress,
+		)
+
+		return
+	}
+
+	client := ws.NewClient(clientID, sessionID, "", displayName, role, ipAddress, code, nil, false, conn, hub)
+	if setup != nil {
+		setup(client)
+	}
+
+	hub.Register <- client
+
+	go client.WritePump()
+	go client.ReadPump()
+
+	logger.Debug("listener connection established",
+		"client_id", clientID,
+		"session_id", sessionID,
+		"role", role,
+		"ip", ipAddress,
+	)
+}
+
+// fetches the latest chat messages of a session for session_state (chat is
+// session-scoped). failures are logged and leave the history empty
+func fetchChatHistory(ctx context.Context, sessionRepo sessions.Repository, sessionID string) []ws.SessionStateChatMessage {
+	messages, err := sessionRepo.GetChatMessages(ctx, sessionID, 50)
+	if err != nil {
+		logger.Warn("failed to fetch chat history",
+			"session_id", sessionID,
+			"error", err,
+		)
+
+		return nil
+	}
+
+	var chatHistory []ws.SessionStateChatMessage
+	for _, msg := range messages {
+		msgDisplayName := ""
+		if msg.DisplayName != nil {
+			msgDisplayName = *msg.DisplayName
+		}
+		avatarURL := ""
+		if msg.AvatarURL != nil {
+			avatarURL = *msg.AvatarURL
+		}
+		parentMessageID := ""
+		if msg.ParentMessageID != nil {
+			parentMessageID = *msg.ParentMessageID
+		}
+		chatHistory = append(chatHistory, ws.SessionStateChatMessage{
+			ID:              msg.ID,
+			DisplayName:     msgDisplayName,
+			AvatarURL:       avatarURL,
+			Content:         msg.Content,
+			ParentMessageID: parentMessageID,
+			Reactions:       chatReactions(msg.Reactions),
+			Timestamp:       msg.CreatedAt.UnixMilli(),
+		})
+	}
+
+	return chatHistory
+}
+
+// converts stored reactions to the session_state format
+func chatReactions(reactions []sessions.Reaction) []ws.SessionStateChatReaction {
+	if len(reactions) == 0 {
+		return nil
+	}
+
+	result := make([]ws.SessionStateChatReaction, 0, len(reactions))
+	for _, r := range reactions {
+		reaction := ws.SessionStateChatReaction{Emoji: r.Emoji}
+		if r.UserID != nil {
+			reaction.UserID = *r.UserID
+		}
+		if r.DisplayName != nil {
+			reaction.DisplayName = *r.DisplayName
+		}
+		result = append(result, reaction)
+	}
+
+	return result
+}
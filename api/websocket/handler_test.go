@@ -0,0 +1,89 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/tui/api"
+	ws "codeberg.org/algopatterns/server/internal/websocket"
+)
+
+const testSessionID = "5f0c1e3a-8f4b-4a7e-9d2c-1b6e8a9f0c3d"
+
+// a live, discoverable session anyone can join as a viewer
+type discoverableRepo struct {
+	sessions.Repository
+}
+
+func (discoverableRepo) GetSession(_ context.Context, sessionID string) (*sessions.Session, error) {
+	return &sessions.Session{ID: sessionID, HostUserID: "host-1", Code: `s("bd sd")`, IsActive: true, IsDiscoverable: true}, nil
+}
+
+func (discoverableRepo) GetChatMessages(_ context.Context, _ string, _ int) ([]*sessions.Message, error) {
+	return nil, nil
+}
+
+func (discoverableRepo) AddAnonymousParticipant(_ context.Context, _, displayName, role string) (*sessions.AnonymousParticipant, error) {
+	return &sessions.AnonymousParticipant{ID: "participant-1"}, nil
+}
+
+// the WebSocket handler as production serves it
+func newProductionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	t.Setenv("ENVIRONMENT", "production")
+	t.Setenv("ALLOWED_ORIGINS", "https://algopatterns.cc")
+
+	hub := ws.NewHub()
+	go hub.Run()
+	t.Cleanup(hub.Shutdown)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/ws", WebSocketHandler(hub, discoverableRepo{}, nil, nil, nil, nil))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestTUIConnectsInProduction(t *testing.T) {
+	server := newProductionServer(t)
+
+	// the TUI dials without an Origin header, like every client outside a browser
+	conn, err := api.New(server.URL, "").Connect(context.Background(), api.ConnectOptions{SessionID: testSessionID})
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck // test cleanup
+
+	messages, err := conn.Read()
+	require.NoError(t, err)
+	require.NotEmpty(t, messages)
+	assert.Equal(t, "session_state", messages[0].Type)
+}
+
+func TestBrowserFromOtherSiteRefusedInProduction(t *testing.T) {
+	server := newProductionServer(t)
+	target := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws?session_id=" + testSessionID
+
+	header := http.Header{"Origin": {"https://evil.example"}}
+	_, resp, err := websocket.DefaultDialer.Dial(target, header)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	defer resp.Body.Close() //nolint:errcheck // test cleanup
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	header.Set("Origin", "https://algopatterns.cc")
+	conn, resp, err := websocket.DefaultDialer.Dial(target, header)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // test cleanup
+	conn.Close()            //nolint:errcheck,gosec // test cleanup
+}
@@ -5,9 +5,13 @@ import (
 
 	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/algopatterns/users"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/embeds"
+	"codeberg.org/algopatterns/server/internal/joinrequests"
 	ws "codeberg.org/algopatterns/server/internal/websocket"
 )
 
-func RegisterRoutes(router *gin.RouterGroup, hub *ws.Hub, sessionRepo sessions.Repository, userRepo *users.Repository) {
-	router.GET("/ws", WebSocketHandler(hub, sessionRepo, userRepo))
+func RegisterRoutes(router *gin.RouterGroup, hub *ws.Hub, sessionRepo sessions.Repository, userRepo *users.Repository, embedService *embeds.Service, joinRequests *joinrequests.Service, tickets *auth.TicketStore) {
+	router.GET("/ws", WebSocketHandler(hub, sessionRepo, userRepo, embedService, joinRequests, tickets))
+	router.POST("/ws/ticket", auth.AuthMiddleware(), CreateTicketHandler(tickets))
 }
@@ -0,0 +1,43 @@
+package websocket
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+)
+
+// CreateTicketHandler godoc
+// @Summary Create a WebSocket ticket
+// @Description Issue a signed ticket that signs the next WebSocket connection in as the current user, passed as the ticket query parameter of /api/v1/ws in place of the access token. A ticket expires after 30 seconds and works for one connection
+// @Tags websocket
+// @Produce json
+// @Success 201 {object} TicketResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/ws/ticket [post]
+// @Security BearerAuth
+func CreateTicketHandler(tickets *auth.TicketStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserID(c)
+		if !exists {
+			errors.Unauthorized(c, "")
+			return
+		}
+
+		ticket, err := tickets.Issue(userID)
+		if err != nil {
+			errors.InternalError(c, "failed to create ticket", err)
+			return
+		}
+
+		// a ticket signs a connection in, it must not be cached anywhere
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusCreated, TicketResponse{
+			Ticket:    ticket,
+			ExpiresIn: int(auth.WSTicketTTL.Seconds()),
+		})
+	}
+}
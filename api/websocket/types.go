@@ -1,9 +1,69 @@
 package websocket
 
+import (
+	ws "codeberg.org/algopatterns/server/internal/websocket"
+)
+
 type ConnectParams struct {
 	SessionID         string `form:"session_id"`                     // optional - if not provided, creates new anonymous session
 	PreviousSessionID string `form:"previous_session_id"`            // optional - copy code from this session when creating new one
-	Token             string `form:"token"`                          // jwt token for authenticated users
+	Ticket            string `form:"ticket"`                         // one-time ticket for authenticated users
+	Token             string `form:"token"`                          // jwt token for authenticated users, superseded by ticket
 	InviteToken       string `form:"invite"`                         // invite token for joining sessions
 	DisplayName       string `form:"display_name" binding:"max=100"` // optional display name for anonymous users
+	Resume            bool   `form:"resume"`                         // optional - defer session_state until the client sends resume
+	Audience          bool   `form:"audience"`                       // optional - join a discoverable session as a read-only listener
+	Spectator         bool   `form:"spectator"`                      // optional - join a discoverable session as a listener who can chat (requires token)
+	Embed             string `form:"embed"`                          // optional - embed token, joins its session as a read-only embed
+}
+
+// response payload of a WebSocket ticket
+type TicketResponse struct {
+	Ticket    string `json:"ticket"`
+	ExpiresIn int    `json:"expires_in"` // seconds
+}
+
+// MessageCatalog documents the payload of each message type, keyed by the
+// type. it is never sent as is: every frame is a websocket.Message whose
+// payload is the field named by its type. ping and pong carry no payload
+type MessageCatalog struct {
+	CodeUpdate         ws.CodeUpdatePayload         `json:"code_update"`
+	CodeOperation      ws.CodeOperationPayload      `json:"code_op"`
+	CodeOperationAck   ws.CodeOperationAckPayload   `json:"code_op_ack"`
+	CodeValidation     ws.CodeValidationPayload     `json:"code_validation"`
+	Undo               ws.UndoRedoPayload           `json:"undo"`
+	Redo               ws.UndoRedoPayload           `json:"redo"`
+	LockRegion         ws.LockRegionPayload         `json:"lock_region"`
+	UnlockRegion       ws.UnlockRegionPayload       `json:"unlock_region"`
+	RegionLocks        ws.RegionLocksPayload        `json:"region_locks"`
+	CursorPosition     ws.CursorPositionPayload     `json:"cursor_position"`
+	CursorUpdate       ws.CursorUpdatePayload       `json:"cursor_update"`
+	SelectionUpdate    ws.SelectionUpdatePayload    `json:"selection_update"`
+	UserJoined         ws.UserJoinedPayload         `json:"user_joined"`
+	UserLeft           ws.UserLeftPayload           `json:"user_left"`
+	ChatMessage        ws.ChatMessagePayload        `json:"chat_message"`
+	ChatReply          ws.ChatMessagePayload        `json:"chat_reply"`
+	ChatReaction       ws.ChatReactionPayload       `json:"chat_reaction"`
+	ChatSettings       ws.ChatSettingsPayload       `json:"chat_settings"`
+	ChatMuted          ws.ChatMutedPayload          `json:"chat_muted"`
+	SessionState       ws.SessionStatePayload       `json:"session_state"`
+	SessionEnded       ws.SessionEndedPayload       `json:"session_ended"`
+	Resume             ws.ResumePayload             `json:"resume"`
+	ResumeComplete     ws.ResumeCompletePayload     `json:"resume_complete"`
+	Play               ws.PlayPayload               `json:"play"`
+	Stop               ws.StopPayload               `json:"stop"`
+	TransportSync      ws.TransportSyncPayload      `json:"transport_sync"`
+	ClockSync          ws.ClockSyncPayload          `json:"clock_sync"`
+	PasteLockChanged   ws.PasteLockChangedPayload   `json:"paste_lock_changed"`
+	AgentRequest       ws.AgentRequestPayload       `json:"agent_request"`
+	AgentResponseChunk ws.AgentResponseChunkPayload `json:"agent_response_chunk"`
+	AgentResponseDone  ws.AgentResponseDonePayload  `json:"agent_response_done"`
+	ListenerCount      ws.ListenerCountPayload      `json:"listener_count"`
+	RateLimitUpdate    ws.RateLimitUpdatePayload    `json:"rate_limit_update"`
+	ConnectionQuality  ws.ConnectionQualityPayload  `json:"connection_quality"`
+	PermissionsUpdated ws.PermissionsUpdatedPayload `json:"permissions_updated"`
+	JoinRequest        ws.JoinRequestPayload        `json:"join_request"`
+	ServerShutdown     ws.ServerShutdownPayload     `json:"server_shutdown"`
+	ReconnectHint      ws.ReconnectHintPayload      `json:"reconnect_hint"`
+	Error              ws.ErrorPayload              `json:"error"`
 }
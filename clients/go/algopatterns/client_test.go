@@ -0,0 +1,91 @@
+package algopatterns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientSendsRequests(t *testing.T) {
+	var got *http.Request
+	var gotBody map[string]any
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		if r.Body != nil {
+			data, _ := io.ReadAll(r.Body)
+			json.Unmarshal(data, &gotBody) //nolint:errcheck,gosec // empty for GET
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"s1","title":"groove","tags":["dnb"]}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL+"/", "token")
+	ctx := context.Background()
+
+	strudel, err := client.GetStrudelByID(ctx, "a/b", &GetStrudelByIDParams{IfNoneMatch: Ptr(`W/"1"`)})
+	if err != nil {
+		t.Fatalf("GetStrudelByID() error = %v", err)
+	}
+	if strudel.ID == nil || *strudel.ID != "s1" || len(strudel.Tags) != 1 {
+		t.Errorf("GetStrudelByID() = %+v", strudel)
+	}
+	if got.URL.EscapedPath() != "/api/v1/strudels/a%2Fb" {
+		t.Errorf("path = %s, want the id escaped", got.URL.EscapedPath())
+	}
+	if got.Header.Get("Authorization") != "Bearer token" || got.Header.Get("If-None-Match") != `W/"1"` {
+		t.Errorf("headers = %v", got.Header)
+	}
+
+	if _, err := client.ListUsersStrudels(ctx, &ListUsersStrudelsParams{Limit: Ptr(5), Tags: []string{"dnb", "jungle"}}); err != nil {
+		t.Fatalf("ListUsersStrudels() error = %v", err)
+	}
+	if got.URL.RawQuery != "limit=5&tags=dnb%2Cjungle" {
+		t.Errorf("query = %s", got.URL.RawQuery)
+	}
+
+	if _, err := client.ListUsersStrudels(ctx, nil); err != nil {
+		t.Fatalf("ListUsersStrudels(nil) error = %v", err)
+	}
+	if got.URL.RawQuery != "" {
+		t.Errorf("query without params = %s", got.URL.RawQuery)
+	}
+
+	// unset optional fields are left out, set ones are sent even when zero
+	if _, err := client.CreateStrudel(ctx, &CreateStrudelRequest{Title: "groove", Code: `s("bd")`, IsPublic: Ptr(false)}); err != nil {
+		t.Fatalf("CreateStrudel() error = %v", err)
+	}
+	if got.Method != http.MethodPost || got.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("request = %s %v", got.Method, got.Header)
+	}
+	if _, ok := gotBody["description"]; ok {
+		t.Errorf("body = %v, sent an unset field", gotBody)
+	}
+	if gotBody["is_public"] != false {
+		t.Errorf("body = %v, want is_public false", gotBody)
+	}
+}
+
+func TestClientReturnsAPIErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"not_found","message":"strudel not found"}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL, "").GetStrudelByID(context.Background(), "s1", nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want an *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "not_found" || apiErr.Message != "strudel not found" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
@@ -0,0 +1,51 @@
+// generates the typed API clients in clients/ from the OpenAPI spec. run
+// from the repository root, usually through make client
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"codeberg.org/algopatterns/server/internal/clientgen"
+)
+
+func main() {
+	specPath := flag.String("spec", "docs/openapi/swagger.json", "Swagger 2.0 spec written by swag")
+	out := flag.String("out", ".", "repository root the clients are written under")
+	flag.Parse()
+
+	spec, err := clientgen.Load(*specPath)
+	if err != nil {
+		fail(err)
+	}
+
+	files, err := clientgen.Generate(spec)
+	if err != nil {
+		fail(err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		target := filepath.Join(*out, path)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil { //nolint:gosec // source directories
+			fail(err)
+		}
+		if err := os.WriteFile(target, files[path], 0o644); err != nil { //nolint:gosec // generated source
+			fail(err)
+		}
+		fmt.Println("wrote", target)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "clientgen:", err)
+	os.Exit(1)
+}
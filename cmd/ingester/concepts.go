@@ -6,7 +6,6 @@ import (
 
 	"codeberg.org/algopatterns/server/internal/chunker"
 	"codeberg.org/algopatterns/server/internal/config"
-	"codeberg.org/algopatterns/server/internal/llm"
 	"codeberg.org/algopatterns/server/internal/logger"
 	"codeberg.org/algopatterns/server/internal/storage"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -47,11 +46,12 @@ func IngestConcepts(cfg *config.Config, db *pgxpool.Pool, flags config.Flags) er
 
 	logger.Info("generated concept chunks", "count", len(chunks))
 
-	// create OpenAI embedder
-	embedder := llm.NewOpenAIEmbedder(llm.OpenAIConfig{
-		APIKey: cfg.OpenAIKey,
-		Model:  "text-embedding-3-small",
-	})
+	// the server's embedder over rpc, or the configured one, so local setups embed with the same model as the server
+	embedder, _, closeEmbedder, err := newEmbedder(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeEmbedder()
 
 	// generate embeddings for all concept chunks
 	logger.Info("generating embeddings for concept chunks")
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/rpc"
+)
+
+// the embedder and the model it embeds with. with RPC_SERVER_ADDR set the
+// server embeds over its internal API, so the ingester needs no provider keys
+// and always matches the server's model; otherwise the configured provider
+// (EMBEDDER_PROVIDER) is used. close releases the rpc connection
+func newEmbedder(ctx context.Context) (embedder llm.Embedder, model string, close func(), err error) {
+	client, err := rpc.ClientFromEnv()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if client != nil {
+		closeClient := func() {
+			client.Close() //nolint:errcheck,gosec // best-effort cleanup
+		}
+
+		model, err = client.EmbeddingModel(ctx)
+		if err != nil {
+			closeClient()
+			return nil, "", nil, fmt.Errorf("failed to reach rpc server: %w", err)
+		}
+
+		logger.Info("embedding through the rpc server", "model", model)
+		return client, model, closeClient, nil
+	}
+
+	model, err = llm.EmbeddingModel()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	embedder, err = llm.NewEmbedder()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to create embedder: %w", err)
+	}
+
+	return embedder, model, func() {}, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"codeberg.org/algopatterns/server/internal/config"
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/retriever"
+	"codeberg.org/algopatterns/server/internal/retriever/eval"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runs the golden query set against the live indices and prints recall@k and
+// MRR. returns an error if a metric regressed against the baseline report
+func Evaluate(db *pgxpool.Pool, flags config.EvalFlags) error {
+	ctx := context.Background()
+
+	if flags.K <= 0 {
+		return fmt.Errorf("k must be positive")
+	}
+
+	queries, err := eval.LoadGoldenSet(flags.Queries)
+	if err != nil {
+		return err
+	}
+
+	llmClient, err := llm.NewLLM(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create LLM client: %w", err)
+	}
+
+	// evaluate the same pipeline the server runs, including reranking when enabled
+	retrieverClient := retriever.New(db, llmClient)
+	if rerankConfig := retriever.RerankConfigFromEnv(); rerankConfig.Enabled {
+		reranker, err := retriever.NewReranker(rerankConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create reranker: %w", err)
+		}
+
+		retrieverClient.SetReranker(reranker, rerankConfig.Candidates, rerankConfig.Timeout)
+	}
+
+	logger.Info("running retrieval eval", "queries", len(queries), "k", flags.K)
+
+	report := eval.Run(ctx, retrieverClient, queries, flags.K)
+
+	var regressions []eval.Regression
+	if flags.Baseline != "" {
+		baseline, err := eval.LoadReport(flags.Baseline)
+		if err != nil {
+			return err
+		}
+
+		regressions = eval.Compare(baseline, report, flags.Tolerance)
+	}
+
+	if err := eval.WriteReport(os.Stdout, report, regressions); err != nil {
+		return err
+	}
+
+	if flags.Out != "" {
+		if err := eval.SaveReport(flags.Out, report); err != nil {
+			return fmt.Errorf("failed to save report: %w", err)
+		}
+
+		logger.Info("saved eval report", "path", flags.Out)
+	}
+
+	if len(regressions) > 0 {
+		return fmt.Errorf("%d metric(s) regressed against %s", len(regressions), flags.Baseline)
+	}
+
+	return nil
+}
@@ -17,9 +17,21 @@ func main() {
 		fmt.Println("  docs      - ingest documentation from markdown files")
 		fmt.Println("  concepts  - ingest teaching concepts from MDX files")
 		fmt.Println("  all       - ingest everything (docs, concepts)")
+		fmt.Println("  reembed   - re-embed stored docs and strudels with the configured embedding model")
+		fmt.Println("  eval      - score retrieval against the golden query set (recall@k, MRR)")
 		fmt.Println("\nOptions:")
 		fmt.Println("  --path <path>  - Custom path to ingest from")
 		fmt.Println("  --clear        - Clear existing data before ingesting")
+		fmt.Println("\nReembed options:")
+		fmt.Println("  --target <docs|strudels|all>  - What to re-embed (default: all)")
+		fmt.Println("  --batch-size <n>              - Rows embedded per request (default: 100)")
+		fmt.Println("  --restart                     - Start over instead of resuming")
+		fmt.Println("\nEval options:")
+		fmt.Println("  --queries <path>    - Golden query set (default: built-in)")
+		fmt.Println("  --k <n>             - Results scored per query (default: 5)")
+		fmt.Println("  --out <path>        - Save the report as JSON")
+		fmt.Println("  --baseline <path>   - Fail if recall@k or MRR dropped below an earlier report")
+		fmt.Println("  --tolerance <x>     - Allowed drop before failing (default: 0.02)")
 		os.Exit(1)
 	}
 
@@ -85,6 +97,18 @@ func main() {
 
 		logger.Info("successfully ingested all data")
 
+	case "reembed":
+		flags := config.ParseReembedFlags()
+		if err := Reembed(db, flags); err != nil {
+			logger.Fatal("failed to re-embed", "error", err)
+		}
+
+	case "eval":
+		flags := config.ParseEvalFlags()
+		if err := Evaluate(db, flags); err != nil {
+			logger.Fatal("retrieval eval failed", "error", err)
+		}
+
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		os.Exit(1)
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"codeberg.org/algopatterns/server/internal/config"
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/storage"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// regenerates stored embeddings with the configured embedding model. progress
+// is saved per batch, so re-running after an interruption resumes the run
+func Reembed(db *pgxpool.Pool, flags config.ReembedFlags) error {
+	ctx := context.Background()
+
+	var targets []string
+	switch flags.Target {
+	case "all":
+		targets = []string{storage.ReembedTargetDocs, storage.ReembedTargetStrudels}
+	case storage.ReembedTargetDocs, storage.ReembedTargetStrudels:
+		targets = []string{flags.Target}
+	default:
+		return fmt.Errorf("unknown target %q (expected docs, strudels or all)", flags.Target)
+	}
+
+	if flags.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be positive")
+	}
+
+	embedder, model, closeEmbedder, err := newEmbedder(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeEmbedder()
+
+	storageClient := storage.NewClientFromPool(db)
+	defer storageClient.Close() // no-op since we don't own the pool
+
+	logger.Info("starting re-embedding", "model", model, "targets", targets, "batch_size", flags.BatchSize)
+
+	for _, target := range targets {
+		if err := reembedTarget(ctx, storageClient, embedder, target, model, flags); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func reembedTarget(
+	ctx context.Context,
+	storageClient *storage.Client,
+	embedder llm.Embedder,
+	target, model string,
+	flags config.ReembedFlags,
+) error {
+	job, err := storageClient.GetReembedJob(ctx, target, model)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case job != nil && job.CompletedAt != nil && !flags.Restart:
+		logger.Info("already re-embedded with this model, skipping (use --restart to run again)",
+			"target", target,
+			"model", model,
+			"completed_at", job.CompletedAt,
+		)
+		return nil
+
+	case job != nil && !flags.Restart:
+		logger.Info("resuming re-embedding", "target", target, "processed", job.Processed, "total", job.Total)
+
+	default:
+		total, err := storageClient.CountEmbeddingSources(ctx, target)
+		if err != nil {
+			return err
+		}
+
+		if job, err = storageClient.StartReembedJob(ctx, target, model, total); err != nil {
+			return err
+		}
+
+		logger.Info("re-embedding", "target", target, "total", total)
+	}
+
+	for {
+		sources, err := storageClient.ListEmbeddingSources(ctx, target, job.LastID, flags.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(sources) == 0 {
+			break
+		}
+
+		texts := make([]string, len(sources))
+		for i, source := range sources {
+			texts[i] = source.Text
+		}
+
+		embeddings, err := embedder.GenerateEmbeddings(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to generate embeddings for %s after %d rows: %w", target, job.Processed, err)
+		}
+
+		if err := storageClient.SaveReembedBatch(ctx, job, sources, embeddings); err != nil {
+			return err
+		}
+
+		logger.Info("re-embedding progress", "target", target, "processed", job.Processed, "total", job.Total)
+	}
+
+	if err := storageClient.CompleteReembedJob(ctx, job); err != nil {
+		return err
+	}
+
+	logger.Info("re-embedding complete", "target", target, "processed", job.Processed)
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// simulates sessions full of collaborating clients against an in-process hub
+// and reports broadcast latency, dropped sends and memory growth. run before
+// releasing hub or flusher changes, usually through make loadtest
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/loadtest"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+func main() {
+	var cfg loadtest.Config
+	flag.IntVar(&cfg.Sessions, "sessions", 10, "concurrent sessions")
+	flag.IntVar(&cfg.ClientsPerSession, "clients", 4, "clients per session, the first one hosts")
+	flag.DurationVar(&cfg.Duration, "duration", 30*time.Second, "how long clients keep sending code updates")
+	flag.Float64Var(&cfg.UpdateRate, "rate", 2, "code updates per second per client")
+	flag.DurationVar(&cfg.AgentInterval, "agent-interval", 0, "time between agent requests of each host, 0 sends none")
+	flag.DurationVar(&cfg.LLMLatency, "llm-latency", 50*time.Millisecond, "delay of the stub model before each streamed chunk")
+	flag.StringVar(&cfg.RedisURL, "redis", "", "redis URL to buffer writes in and flush as the server does")
+	flag.DurationVar(&cfg.FlushInterval, "flush-interval", 5*time.Second, "how often the flusher empties the buffer")
+	flag.DurationVar(&cfg.StoreLatency, "store-latency", 0, "added to every store write, standing in for postgres")
+	out := flag.String("out", "", "write the report as JSON to this path, for use as a baseline")
+	baselinePath := flag.String("baseline", "", "compare against a report saved with -out and fail on regressions")
+	tolerance := flag.Float64("tolerance", 0.2, "fraction a metric may get worse than the baseline")
+	logLevel := flag.String("log-level", "warn", "server log level: debug, info, warn or error")
+	flag.Parse()
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		fail(err)
+	}
+	logger.SetLevel(level)
+
+	// an interrupted run still reports on what it did
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := loadtest.Run(ctx, cfg)
+	if err != nil {
+		fail(err)
+	}
+
+	var regressions []loadtest.Regression
+	if *baselinePath != "" {
+		baseline, err := loadtest.LoadReport(*baselinePath)
+		if err != nil {
+			fail(err)
+		}
+
+		regressions = loadtest.Compare(baseline, report, *tolerance)
+	}
+
+	if err := loadtest.WriteReport(os.Stdout, report, regressions); err != nil {
+		fail(err)
+	}
+
+	if *out != "" {
+		if err := loadtest.SaveReport(*out, report); err != nil {
+			fail(fmt.Errorf("failed to save report: %w", err))
+		}
+	}
+
+	if len(regressions) > 0 {
+		fail(fmt.Errorf("%d metric(s) regressed against %s", len(regressions), *baselinePath))
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "loadtest:", err)
+	os.Exit(1)
+}
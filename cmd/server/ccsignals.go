@@ -2,11 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
 	"codeberg.org/algopatterns/server/internal/ccsignals"
+	"codeberg.org/algopatterns/server/internal/jobs"
 	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -19,6 +25,16 @@ const (
 	lshNumBands            = 4  // 4 bands of 16 bits each
 	lshSimilarityThreshold = 10 // ~84% similarity required for match
 	lshShingleSize         = 3  // 3-character shingles for fingerprinting
+
+	// fingerprint persistence
+	fingerprintLoadBatch = 500 // strudels fetched per query when recomputing
+
+	// background job that persists a strudel's fingerprint
+	jobPersistFingerprint = "fingerprints.persist"
+
+	// changes are re-read from a little before the last sync, so rows that
+	// committed late with an older updated_at aren't missed
+	fingerprintSyncOverlap = time.Minute
 )
 
 // holds all CC signals detection components
@@ -26,13 +42,29 @@ type CCSignalsSystem struct {
 	Detector     *ccsignals.Detector
 	Fingerprints *ccsignals.IndexedFingerprintStore
 	LockStore    *ccsignals.RedisLockStore
+
+	// nil when fingerprints couldn't be loaded from postgres
+	Persisted *ccsignals.PostgresFingerprintStore
+
+	strudelRepo *strudels.Repository
+	jobs        *jobs.Queue
+
+	syncMu   sync.Mutex
+	syncedAt time.Time // latest persisted change applied to the index
+}
+
+// payload of a jobPersistFingerprint job
+type persistFingerprintJob struct {
+	StrudelID string `json:"strudel_id"`
 }
 
 // sets up the CC signals detection system
 func InitializeCCSignals(
 	ctx context.Context,
-	redisClient *redis.Client,
+	redisClient redis.UniversalClient,
 	strudelRepo *strudels.Repository,
+	db *pgxpool.Pool,
+	queue *jobs.Queue,
 ) (*CCSignalsSystem, error) {
 	// create lock store using existing redis client
 	lockStore := ccsignals.NewRedisLockStore(redisClient)
@@ -40,34 +72,52 @@ func InitializeCCSignals(
 	// create content validator using strudels repository
 	validator := ccsignals.NewStrudelValidator(strudelRepo)
 
-	// create indexed fingerprint store with LSH (in-memory only, computed from strudels)
+	// create indexed fingerprint store with LSH, persisted in postgres
 	indexedFpStore := ccsignals.NewInMemoryIndexedStore(
 		lshNumBands,
 		lshSimilarityThreshold,
 		lshShingleSize,
 	)
 
-	// load existing no-ai strudels and compute fingerprints
-	if err := loadNoAIFingerprints(ctx, indexedFpStore, strudelRepo); err != nil {
-		logger.ErrorErr(err, "failed to load no-ai fingerprints, continuing without them")
-		// don't fail startup - fingerprint protection is optional
+	system := &CCSignalsSystem{
+		Fingerprints: indexedFpStore,
+		LockStore:    lockStore,
+		Persisted:    ccsignals.NewPostgresFingerprintStore(db),
+		strudelRepo:  strudelRepo,
+		jobs:         queue,
+	}
+
+	// load persisted fingerprints and reconcile them with the no-ai strudels,
+	// falling back to computing every fingerprint
+	if err := system.loadFingerprints(ctx, strudelRepo); err != nil {
+		logger.ErrorErr(err, "failed to load persisted fingerprints, computing them from strudels")
+		system.Persisted = nil
+
+		if err := loadNoAIFingerprints(ctx, indexedFpStore, strudelRepo); err != nil {
+			logger.ErrorErr(err, "failed to load no-ai fingerprints, continuing without them")
+			// don't fail startup - fingerprint protection is optional
+		}
+	}
+
+	if system.Persisted != nil {
+		queue.Register(jobPersistFingerprint, system.handlePersistJob)
 	}
 
+	metrics.FingerprintIndexSize.Set(float64(indexedFpStore.Size()))
+
 	// create detector with all components
 	config := ccsignals.DefaultConfig()
-	detector := ccsignals.NewDetector(config, lockStore, validator).
-		WithFingerprints(indexedFpStore)
+	system.Detector = ccsignals.NewDetector(config, lockStore, validator).
+		WithFingerprints(indexedFpStore).
+		WithDecisionLog(lockStore)
 
 	logger.Info("CC signals system initialized",
 		"fingerprints_loaded", indexedFpStore.Size(),
 		"min_content_length", minContentLengthForProtection,
+		"persisted", system.Persisted != nil,
 	)
 
-	return &CCSignalsSystem{
-		Detector:     detector,
-		Fingerprints: indexedFpStore,
-		LockStore:    lockStore,
-	}, nil
+	return system, nil
 }
 
 // loads no-ai strudels and computes fingerprints into the LSH index
@@ -88,6 +138,123 @@ func loadNoAIFingerprints(
 	return nil
 }
 
+// fills the index from postgres, then checks it against the no-ai strudels:
+// fingerprints of changed or new strudels are recomputed and fingerprints of
+// strudels that are no longer protected are removed
+func (s *CCSignalsSystem) loadFingerprints(ctx context.Context, strudelRepo *strudels.Repository) error {
+	if err := s.SyncFingerprints(ctx); err != nil {
+		return err
+	}
+
+	loaded := s.Fingerprints.Size()
+
+	digests, err := strudelRepo.ListNoAIStrudelDigests(ctx, minContentLengthForProtection)
+	if err != nil {
+		return fmt.Errorf("failed to load no-ai strudel digests: %w", err)
+	}
+
+	protected := make(map[string]bool, len(digests))
+	var stale []string
+
+	for _, digest := range digests {
+		protected[digest.ID] = true
+
+		record := s.Fingerprints.Record(digest.ID)
+		if record == nil || record.ContentHash != digest.ContentHash || record.CreatorID != digest.UserID {
+			stale = append(stale, digest.ID)
+		}
+	}
+
+	removed := 0
+	for _, workID := range s.Fingerprints.WorkIDs() {
+		if !protected[workID] {
+			s.Fingerprints.Remove(workID)
+			s.persist(ctx, workID)
+			removed++
+		}
+	}
+
+	for start := 0; start < len(stale); start += fingerprintLoadBatch {
+		batch := stale[start:min(start+fingerprintLoadBatch, len(stale))]
+
+		noaiStrudels, err := strudelRepo.ListNoAIStrudelsByID(ctx, batch, minContentLengthForProtection)
+		if err != nil {
+			return fmt.Errorf("failed to load no-ai strudels: %w", err)
+		}
+
+		for _, strudel := range noaiStrudels {
+			s.Fingerprints.AddFromStrudel(strudel.ID, strudel.UserID, ccsignals.CCSignal(strudel.CCSignal), strudel.Code)
+			s.persist(ctx, strudel.ID)
+		}
+	}
+
+	metrics.FingerprintRepairs.Add(float64(len(stale)), "recomputed")
+	metrics.FingerprintRepairs.Add(float64(removed), "removed")
+
+	logger.Info("fingerprint index checked",
+		"loaded", loaded,
+		"recomputed", len(stale),
+		"removed", removed,
+	)
+
+	return nil
+}
+
+// applies fingerprints persisted since the last sync, including other
+// instances' changes, to the index
+func (s *CCSignalsSystem) SyncFingerprints(ctx context.Context) error {
+	if s.Persisted == nil {
+		return nil
+	}
+
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	since := s.syncedAt
+	if !since.IsZero() {
+		since = since.Add(-fingerprintSyncOverlap)
+	}
+
+	changes, err := s.Persisted.Changes(ctx, since)
+	if err != nil {
+		return fmt.Errorf("failed to load fingerprint changes: %w", err)
+	}
+
+	for _, change := range changes {
+		switch {
+		case change.Deleted:
+			s.Fingerprints.Remove(change.Record.WorkID)
+		case change.Params == s.Fingerprints.Params():
+			s.Fingerprints.InsertRecord(change.Record)
+		}
+
+		if change.UpdatedAt.After(s.syncedAt) {
+			s.syncedAt = change.UpdatedAt
+		}
+	}
+
+	metrics.FingerprintIndexSize.Set(float64(s.Fingerprints.Size()))
+
+	return nil
+}
+
+// syncs the index and purges old tombstones, run periodically
+func (s *CCSignalsSystem) MaintainFingerprints(ctx context.Context) {
+	if s.Persisted == nil {
+		return
+	}
+
+	if err := s.SyncFingerprints(ctx); err != nil {
+		logger.ErrorErr(err, "failed to sync fingerprint index")
+	}
+
+	if purged, err := s.Persisted.PurgeTombstones(ctx); err != nil {
+		logger.ErrorErr(err, "failed to purge fingerprint tombstones")
+	} else if purged > 0 {
+		logger.Debug("purged fingerprint tombstones", "count", purged)
+	}
+}
+
 // adds a strudel to the fingerprint index if it has no-ai signal
 // and meets minimum content length requirements
 func (s *CCSignalsSystem) IndexStrudel(strudelID, creatorID, code string, ccSignal ccsignals.CCSignal) {
@@ -102,6 +269,7 @@ func (s *CCSignalsSystem) IndexStrudel(strudelID, creatorID, code string, ccSign
 	}
 
 	s.Fingerprints.AddFromStrudel(strudelID, creatorID, ccSignal, code)
+	s.persistAsync(strudelID)
 	logger.Debug("indexed no-ai strudel", "strudel_id", strudelID, "content_length", len(code))
 }
 
@@ -111,23 +279,92 @@ func (s *CCSignalsSystem) UpdateStrudel(strudelID, creatorID, code string, ccSig
 	// only index no-ai strudels
 	if ccSignal != ccsignals.SignalNoAI {
 		// not a no-ai strudel, just remove any existing entry
-		s.Fingerprints.Remove(strudelID)
+		s.RemoveStrudel(strudelID)
 		return
 	}
 
 	// only index substantial content
 	if len(code) < minContentLengthForProtection {
-		s.Fingerprints.Remove(strudelID)
+		s.RemoveStrudel(strudelID)
 		return
 	}
 
 	// use update method that skips rehashing if content unchanged
 	if s.Fingerprints.UpdateFromStrudel(strudelID, creatorID, ccSignal, code) {
+		s.persistAsync(strudelID)
 		logger.Debug("updated no-ai strudel fingerprint", "strudel_id", strudelID, "content_length", len(code))
 	}
 }
 
 // removes a strudel from the fingerprint index
 func (s *CCSignalsSystem) RemoveStrudel(strudelID string) {
+	// most strudels were never indexed, skip the write for them
+	if s.Fingerprints.Record(strudelID) == nil {
+		return
+	}
+
 	s.Fingerprints.Remove(strudelID)
+	s.persistAsync(strudelID)
+}
+
+// queues a job persisting a work's index entry to postgres
+func (s *CCSignalsSystem) persistAsync(workID string) {
+	metrics.FingerprintIndexSize.Set(float64(s.Fingerprints.Size()))
+
+	if s.Persisted == nil {
+		return
+	}
+
+	if err := s.jobs.Enqueue(context.Background(), jobPersistFingerprint, persistFingerprintJob{StrudelID: workID}); err != nil {
+		logger.Warn("failed to queue fingerprint persistence", "error", err, "strudel_id", workID)
+	}
+}
+
+// persists the fingerprint of a strudel as it is now, or tombstones it when
+// the strudel is no longer protected. the job may run on any instance, so the
+// strudel is read from postgres rather than trusting this instance's index,
+// and the index is brought in line with it
+func (s *CCSignalsSystem) handlePersistJob(ctx context.Context, payload json.RawMessage) error {
+	var job persistFingerprintJob
+	if err := jobs.Decode(payload, &job); err != nil {
+		return err
+	}
+
+	protected, err := s.strudelRepo.ListNoAIStrudelsByID(ctx, []string{job.StrudelID}, minContentLengthForProtection)
+	if err != nil {
+		return fmt.Errorf("failed to load strudel: %w", err)
+	}
+
+	if len(protected) == 0 {
+		s.Fingerprints.Remove(job.StrudelID)
+		return s.Persisted.Delete(ctx, job.StrudelID)
+	}
+
+	strudel := protected[0]
+
+	record := s.Fingerprints.Record(strudel.ID)
+	if record == nil || record.ContentHash != ccsignals.ContentHash(strudel.Code) || record.CreatorID != strudel.UserID {
+		s.Fingerprints.AddFromStrudel(strudel.ID, strudel.UserID, ccsignals.CCSignal(strudel.CCSignal), strudel.Code)
+		record = s.Fingerprints.Record(strudel.ID)
+	}
+
+	return s.Persisted.Save(ctx, record, s.Fingerprints.Params())
+}
+
+// saves a work's index entry, or tombstones it when it's no longer indexed
+func (s *CCSignalsSystem) persist(ctx context.Context, workID string) {
+	if s.Persisted == nil {
+		return
+	}
+
+	var err error
+	if record := s.Fingerprints.Record(workID); record != nil {
+		err = s.Persisted.Save(ctx, record, s.Fingerprints.Params())
+	} else {
+		err = s.Persisted.Delete(ctx, workID)
+	}
+
+	if err != nil {
+		logger.Warn("failed to persist fingerprint", "error", err, "strudel_id", workID)
+	}
 }
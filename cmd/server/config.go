@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"codeberg.org/algopatterns/server/internal/config"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/ratelimit"
+)
+
+// runs the config subcommand and returns the exit code
+func runConfigCommand() int {
+	if len(os.Args) < 3 || os.Args[2] != "validate" {
+		fmt.Println("Usage: server config validate [options]")
+		fmt.Println("\nOptions:")
+		fmt.Println("  --config <path>  - Config file to check (default: CONFIG_FILE)")
+		return 2
+	}
+
+	flags := config.ParseValidateFlags()
+	if flags.Path != "" {
+		os.Setenv("CONFIG_FILE", flags.Path) //nolint:errcheck,gosec // valid key
+	}
+
+	cfg, err := config.LoadEnvironmentVariables()
+	if err == nil {
+		_, err = ratelimit.ConfigWithOverrides(cfg.RateLimits)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if cfg.FilePath != "" {
+		fmt.Printf("configuration is valid (%s and environment)\n", cfg.FilePath)
+	} else {
+		fmt.Println("configuration is valid (environment only)")
+	}
+
+	return 0
+}
+
+// applies the settings of a changed config file that take effect while
+// running: rate limits and feature flags
+func (s *Server) reloadConfig(file *config.File) {
+	rateLimitConfig, err := ratelimit.ConfigWithOverrides(file.RateLimits)
+	if err != nil {
+		logger.ErrorErr(err, "invalid rate limits in config file, keeping the current ones")
+	} else {
+		s.rateLimiter.SetConfig(rateLimitConfig)
+	}
+
+	config.SetFeatures(file.Features)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// longest random backoff given to reconnecting clients while draining
+const maxReconnectSpread = 10 * time.Second
+
+// asks the server to drain and shut down. returns false if a drain was
+// already requested
+func (s *Server) RequestDrain() bool {
+	requested := false
+	s.drainOnce.Do(func() {
+		close(s.drainRequested)
+		requested = true
+	})
+
+	return requested
+}
+
+// tells websocket clients to reconnect to another instance and waits up to
+// window for them to leave, so a deploy doesn't drop running sessions. a signal
+// on quit cuts the wait short.
+func (s *Server) drainConnections(window time.Duration, quit <-chan os.Signal) {
+	s.health.MarkUnready()
+
+	hinted := s.hub.Drain(min(window/2, maxReconnectSpread))
+	logger.Info("draining server", "clients", hinted, "window", window.String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), window)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if s.hub.WaitForDrain(ctx) {
+		logger.Info("all websocket clients moved, shutting down")
+		return
+	}
+
+	stats := s.hub.Stats()
+	logger.Warn("drain window ended with clients still connected",
+		"connections", stats.Connections,
+		"listeners", stats.Listeners,
+	)
+}
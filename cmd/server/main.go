@@ -12,6 +12,7 @@ import (
 	"codeberg.org/algopatterns/server/internal/auth"
 	"codeberg.org/algopatterns/server/internal/config"
 	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/tracing"
 )
 
 // @title Algopatterns API
@@ -39,6 +40,16 @@ import (
 // @description JWT token for authenticated requests. Format: Bearer {token}
 
 func main() {
+	// checks the configuration without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand())
+	}
+
+	// manages the database schema without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand())
+	}
+
 	logger.Info("starting algopatterns server")
 
 	// load configuration from environment
@@ -47,6 +58,12 @@ func main() {
 		logger.Fatal("failed to load configuration", "error", err)
 	}
 
+	// initialize tracing (no-op unless an OTLP endpoint is configured)
+	shutdownTracing, err := tracing.Init()
+	if err != nil {
+		logger.ErrorErr(err, "failed to initialize tracing, continuing without it")
+	}
+
 	// initialize OAuth providers
 	if err := auth.InitializeProviders(); err != nil {
 		logger.Fatal("failed to initialize OAuth providers", "error", err)
@@ -64,12 +81,15 @@ func main() {
 		port = "8080"
 	}
 
+	// slow clients can't hold connections open by trickling in headers
 	httpServer := &http.Server{
-		Addr:         fmt.Sprintf(":%s", port),
-		Handler:      srv.router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		Addr:              fmt.Sprintf(":%s", port),
+		Handler:           srv.router,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		MaxHeaderBytes:    64 << 10,
 	}
 
 	// start server in goroutine
@@ -80,20 +100,77 @@ func main() {
 		}
 	}()
 
+	// internal API for the ingester and workers (only when RPC_LISTEN_ADDR is set)
+	rpcServer := startRPC(srv.services)
+
 	// start websocket hub
 	go srv.hub.Run()
 
 	// start buffer flusher (Redis → Postgres)
 	srv.flusher.Start()
 
+	// start presence heartbeat (shares connections with other instances)
+	if srv.presence != nil {
+		srv.presence.Start()
+	}
+
 	// start session cleanup service with cancellable context
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
 	go srv.cleanupService.Start(cleanupCtx)
 
+	// watch redis, buffering writes in memory while it's unreachable
+	go srv.buffer.Start(cleanupCtx)
+
+	// measure read replica lag, replicas serve reads once they're caught up
+	go srv.replicas.Start(cleanupCtx)
+
+	// analyze strudels saved before code analysis was added
+	go srv.strudelRepo.BackfillAnalysis(cleanupCtx)
+
+	// sync the examples index with strudels published before indexing was added
+	go srv.strudelRepo.BackfillIndex(cleanupCtx)
+
+	// run background jobs (attributions, notifications, webhook events, fingerprints)
+	go srv.jobs.Start(cleanupCtx)
+
+	// deliver queued webhook events
+	go srv.webhooks.Start(cleanupCtx)
+
+	// send notification email digests
+	go srv.notifications.Start(cleanupCtx)
+
+	// delete old join requests
+	go srv.joinRequests.Start(cleanupCtx)
+
+	// take scheduled performances live
+	go srv.events.Start(cleanupCtx)
+
+	// keep the sample packs code analysis recognizes in sync
+	go srv.samplePacks.Start(cleanupCtx)
+
+	// pick up rate limit and feature flag changes in the config file
+	if cfg.FilePath != "" {
+		go config.WatchFile(cleanupCtx, cfg.FilePath, srv.reloadConfig)
+	}
+
 	// wait for interrupt signal for graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+
+	// SIGUSR1 (or the admin API) drains connections first, for zero-downtime deploys
+	drain := make(chan os.Signal, 1)
+	signal.Notify(drain, syscall.SIGUSR1)
+
+	select {
+	case <-quit:
+	case <-drain:
+		srv.drainConnections(cfg.DrainWindow, quit)
+	case <-srv.drainRequested:
+		srv.drainConnections(cfg.DrainWindow, quit)
+	}
+
+	// stop receiving traffic from the load balancer
+	srv.health.MarkUnready()
 
 	// stop cleanup service
 	cleanupCancel()
@@ -106,6 +183,11 @@ func main() {
 	// stop flusher (flushes remaining data before stopping)
 	srv.flusher.Stop()
 
+	// stop presence heartbeat and remove this instance's connections
+	if srv.presence != nil {
+		srv.presence.Stop()
+	}
+
 	// graceful shutdown with 10 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -114,6 +196,15 @@ func main() {
 		logger.Error("server forced to shutdown", "error", err)
 	}
 
+	if rpcServer != nil {
+		rpcServer.Stop(ctx)
+	}
+
+	// export remaining spans
+	if err := shutdownTracing(ctx); err != nil {
+		logger.ErrorErr(err, "failed to flush traces on shutdown")
+	}
+
 	// close validator if running
 	if srv.services.Validator != nil {
 		srv.services.Validator.Close() //nolint:errcheck,gosec // best-effort cleanup on shutdown
@@ -122,7 +213,8 @@ func main() {
 	// close Redis connection
 	srv.buffer.Close() //nolint:errcheck,gosec // best-effort cleanup on shutdown
 
-	// close database connection
+	// close database connections
+	srv.replicas.Close()
 	srv.db.Close()
 
 	logger.Info("server stopped")
@@ -1,26 +1,72 @@
 package main
 
 import (
+	"crypto/subtle"
+	"net/http"
 	"os"
 
 	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/tracing"
 )
 
-// configures cors-origin resource sharing
-func CORSMiddleware() gin.HandlerFunc {
+// starts a server span for each request, continuing the caller's trace when
+// a traceparent header is present. health checks and scrapes are not traced.
+func TracingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		allowedOrigin := os.Getenv("CORS_ORIGIN")
-		if allowedOrigin == "" {
-			allowedOrigin = "http://localhost:3000"
+		path := c.Request.URL.Path
+		if !tracing.Enabled() || path == "/health" || path == "/healthz" || path == "/readyz" || path == "/metrics" {
+			c.Next()
+			return
+		}
+
+		// unmatched routes share one span name to keep cardinality low
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
 		}
 
-		c.Writer.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
+		ctx := tracing.Extract(c.Request.Context(), c.Request.Header)
+		ctx, span := tracing.StartWithKind(ctx, c.Request.Method+" "+route, tracing.SpanKindServer,
+			tracing.String("http.request.method", c.Request.Method),
+			tracing.String("http.route", route),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(tracing.Int("http.response.status_code", status))
+
+		if status >= http.StatusInternalServerError {
+			span.SetError(http.StatusText(status))
+		}
+	}
+}
+
+// restricts /metrics to scrapers presenting METRICS_TOKEN as a bearer token.
+// the endpoint is open when METRICS_TOKEN is unset (e.g., local development).
+//
+// @Summary Prometheus metrics
+// @Description Metrics in the Prometheus text format. Requires METRICS_TOKEN as a bearer token when the server sets one
+// @Tags health
+// @Produce text/plain
+// @Success 200 {string} string "Prometheus text format"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /metrics [get]
+// @Security BearerAuth
+func MetricsAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := os.Getenv("METRICS_TOKEN")
+		if token == "" {
+			c.Next()
+			return
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		provided := c.GetHeader("Authorization")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte("Bearer "+token)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
 
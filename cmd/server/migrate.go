@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"codeberg.org/algopatterns/server/internal/config"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/migrate"
+	"codeberg.org/algopatterns/server/supabase/migrations"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func printMigrateUsage() {
+	fmt.Println("Usage: server migrate <command>")
+	fmt.Println("\nCommands:")
+	fmt.Println("  up                 - Apply pending migrations")
+	fmt.Println("  down [steps]       - Roll back the latest migrations (default: 1)")
+	fmt.Println("  status             - List migrations and whether they are applied")
+	fmt.Println("  baseline <version> - Record migrations up to version as applied without")
+	fmt.Println("                       running them, for databases set up by hand")
+}
+
+// runs the migrate subcommand and returns the exit code
+func runMigrateCommand() int {
+	if len(os.Args) < 3 {
+		printMigrateUsage()
+		return 2
+	}
+
+	command, args := os.Args[2], os.Args[3:]
+
+	steps := 1
+	switch {
+	case command == "down" && len(args) == 1:
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			fmt.Fprintf(os.Stderr, "steps must be a positive number, got %q\n", args[0])
+			return 2
+		}
+		steps = n
+	case command == "baseline" && len(args) == 1:
+	case command != "baseline" && len(args) == 0:
+	default:
+		printMigrateUsage()
+		return 2
+	}
+
+	dsn, err := config.LoadDatabaseURL()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	ctx := context.Background()
+
+	migrator, closeDB, err := newMigrator(ctx, dsn)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("applied %d migrations\n", applied)
+
+	case "down":
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("rolled back %d migrations\n", rolledBack)
+
+	case "baseline":
+		recorded, err := migrator.Baseline(ctx, args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("recorded %d migrations as applied\n", recorded)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		printMigrationStatus(statuses)
+
+	default:
+		printMigrateUsage()
+		return 2
+	}
+
+	return 0
+}
+
+func printMigrationStatus(statuses []migrate.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tDOWN") //nolint:errcheck // stdout
+
+	pending := 0
+	for _, s := range statuses {
+		status := "pending"
+		switch {
+		case s.Unknown:
+			status = "applied (not in this binary)"
+		case s.Applied:
+			status = "applied"
+		default:
+			pending++
+		}
+
+		down := "no"
+		if s.Down {
+			down = "yes"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Version, s.Name, status, down) //nolint:errcheck // stdout
+	}
+
+	w.Flush() //nolint:errcheck,gosec // stdout
+	fmt.Printf("\n%d pending\n", pending)
+}
+
+// connects a migrator with the embedded migrations to the database
+func newMigrator(ctx context.Context, dsn string) (*migrate.Migrator, func(), error) {
+	loaded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, nil, errors.New("invalid database connection string")
+	}
+
+	// one connection is enough, and PgBouncer needs the simple protocol
+	poolConfig.MaxConns = 1
+	poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create database pool: %w", err)
+	}
+
+	return migrate.New(db, loaded), db.Close, nil
+}
+
+// applies pending migrations before the server starts (AUTO_MIGRATE=true)
+func migrateOnBoot(ctx context.Context, db *pgxpool.Pool) error {
+	loaded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	applied, err := migrate.New(db, loaded).Up(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("database migrated", "applied", applied, "migrations", len(loaded))
+	return nil
+}
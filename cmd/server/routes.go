@@ -4,36 +4,102 @@ import (
 	"codeberg.org/algopatterns/server/api/rest/admin"
 	"codeberg.org/algopatterns/server/api/rest/agent"
 	"codeberg.org/algopatterns/server/api/rest/auth"
+	"codeberg.org/algopatterns/server/api/rest/billing"
+	"codeberg.org/algopatterns/server/api/rest/cards"
 	"codeberg.org/algopatterns/server/api/rest/collaboration"
+	"codeberg.org/algopatterns/server/api/rest/embeds"
+	"codeberg.org/algopatterns/server/api/rest/engagement"
+	"codeberg.org/algopatterns/server/api/rest/events"
+	"codeberg.org/algopatterns/server/api/rest/follows"
 	"codeberg.org/algopatterns/server/api/rest/health"
+	"codeberg.org/algopatterns/server/api/rest/joinrequests"
+	"codeberg.org/algopatterns/server/api/rest/lessons"
+	"codeberg.org/algopatterns/server/api/rest/notifications"
+	"codeberg.org/algopatterns/server/api/rest/pastelocks"
+	"codeberg.org/algopatterns/server/api/rest/renders"
+	"codeberg.org/algopatterns/server/api/rest/samplepacks"
+	"codeberg.org/algopatterns/server/api/rest/stats"
 	"codeberg.org/algopatterns/server/api/rest/strudels"
+	"codeberg.org/algopatterns/server/api/rest/uploads"
 	"codeberg.org/algopatterns/server/api/rest/users"
+	"codeberg.org/algopatterns/server/api/rest/webhooks"
 	"codeberg.org/algopatterns/server/api/websocket"
+	"codeberg.org/algopatterns/server/internal/metrics"
+	"codeberg.org/algopatterns/server/internal/security"
 	"github.com/gin-gonic/gin"
 )
 
 // sets up all API routes and middleware
 func RegisterRoutes(router *gin.Engine, server *Server) {
-	router.Use(CORSMiddleware())
+	securityConfig := security.FromConfig(server.config)
+
+	router.Use(TracingMiddleware())
+	router.Use(security.Headers(securityConfig))
+	router.Use(security.CORS(securityConfig))
+
+	// refused before any other work: oversized bodies, and handshakes past
+	// the number one client may hold open
+	router.Use(security.BodyLimit(securityConfig))
+	router.Use(security.UpgradeGuard(securityConfig))
+
+	// reads made while handling writes go to the primary, not a read replica
+	router.Use(server.replicas.Middleware())
 
 	// bot defense middleware - runs after CORS, before other routes
 	if server.botDefense != nil {
 		router.Use(server.botDefense.Middleware())
 	}
 
+	// per-user / per-IP token buckets for REST endpoints
+	if server.rateLimiter != nil {
+		router.Use(server.rateLimiter.Middleware())
+	}
+
 	router.GET("/health", health.Handler)
+	router.GET("/healthz", health.LivenessHandler)
+	router.GET("/readyz", health.ReadinessHandler(server.health))
+	router.GET("/metrics", MetricsAuthMiddleware(), gin.WrapH(metrics.Handler()))
+
+	// paste lock inspection is unavailable if ccsignals failed to initialize
+	var pasteLocks admin.PasteLockInspector
+	var sessionPasteLocks pastelocks.PasteLocks
+	if server.ccSignals != nil {
+		pasteLocks = server.ccSignals.Detector
+		sessionPasteLocks = server.ccSignals.Detector
+	}
+
+	// fault injection is only available when enabled, never in production
+	var faultInjector admin.FaultInjector
+	if server.faults != nil {
+		faultInjector = server.faults
+	}
 
 	v1 := router.Group("/api/v1")
 
 	{
 		v1.GET("/ping", health.PingHandler)
 
-		auth.RegisterRoutes(v1, server.userRepo)
-		strudels.RegisterRoutes(v1, server.strudelRepo, server.services.Attribution, server.ccSignals)
-		collaboration.RegisterRoutes(v1, server.sessionRepo, server.hub)
-		users.RegisterRoutes(v1, server.db)
-		admin.RegisterRoutes(v1, server.strudelRepo)
-		agent.RegisterRoutes(v1, server.services.Agent, server.services.LLM, server.strudelRepo, server.userRepo, server.services.Attribution, server.buffer)
-		websocket.RegisterRoutes(v1, server.hub, server.sessionRepo, server.userRepo)
+		auth.RegisterRoutes(v1, server.userRepo, server.refreshTokens, server.deviceCodes)
+		strudels.RegisterRoutes(v1, server.strudelRepo, server.services.Attribution, server.ccSignals, server.services.Retriever, server.publisher, server.audit, server.moderation)
+		collaboration.RegisterRoutes(v1, server.sessionRepo, server.hub, server.hub, server.hub, server.hub, server.publisher, server.audit, server.userRepo, server.mailer, server.notifications)
+		users.RegisterRoutes(v1, server.db, server.strudelRepo, server.mailer, server.quotas)
+		admin.RegisterRoutes(v1, server.strudelRepo, server.userRepo, server.sessionRepo, server.hub, pasteLocks, server.audit, server.publisher, server.moderation, server, server.stats, server.appeals, server.jobs, faultInjector)
+		agent.RegisterRoutes(v1, server.services.Agent, server.services.Providers, server.strudelRepo, server.sessionRepo, server.quotas, server.services.Attribution, server.buffer, server.lessons)
+		webhooks.RegisterRoutes(v1, server.webhooks)
+		notifications.RegisterRoutes(v1, server.notifications)
+		samplepacks.RegisterRoutes(v1, server.samplePacks)
+		events.RegisterRoutes(v1, server.events)
+		embeds.RegisterRoutes(v1, server.embeds, server.hub)
+		joinrequests.RegisterRoutes(v1, server.sessionRepo, server.joinRequests, server.userRepo, server.hub, server.notifications, server.audit)
+		billing.RegisterRoutes(v1, server.billing)
+		stats.RegisterRoutes(v1, server.stats)
+		engagement.RegisterRoutes(v1, server.engagement)
+		follows.RegisterRoutes(v1, server.follows)
+		uploads.RegisterRoutes(v1, server.assets)
+		renders.RegisterRoutes(v1, server.renders)
+		cards.RegisterRoutes(v1, server.cards)
+		lessons.RegisterRoutes(v1, server.sessionRepo, server.lessons)
+		pastelocks.RegisterRoutes(v1, server.sessionRepo, sessionPasteLocks, server.appeals, server.hub, server.audit)
+		websocket.RegisterRoutes(v1, server.hub, server.sessionRepo, server.userRepo, server.embeds, server.joinRequests, server.wsTickets)
 	}
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net"
+	"os"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/rpc"
+)
+
+// starts the internal gRPC API for the ingester and workers when
+// RPC_LISTEN_ADDR is set. returns nil when it isn't started
+func startRPC(services *Services) *rpc.Server {
+	addr := os.Getenv("RPC_LISTEN_ADDR")
+	if addr == "" {
+		return nil
+	}
+
+	token := os.Getenv("RPC_TOKEN")
+	if token == "" {
+		// never serve the API unauthenticated
+		logger.Warn("RPC_LISTEN_ADDR is set without RPC_TOKEN, not starting the internal rpc server")
+		return nil
+	}
+
+	model, err := llm.EmbeddingModel()
+	if err != nil {
+		logger.Warn("failed to resolve embedding model for rpc clients", "error", err)
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Fatal("failed to listen for rpc", "addr", addr, "error", err)
+	}
+
+	server := rpc.NewServer(services.LLM, model, services.Retriever, token)
+
+	go func() {
+		logger.Info("rpc server listening", "addr", addr)
+		if err := server.Serve(lis); err != nil {
+			logger.ErrorErr(err, "rpc server stopped")
+		}
+	}()
+
+	return server
+}
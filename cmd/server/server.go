@@ -2,17 +2,48 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
 	"codeberg.org/algopatterns/server/algopatterns/users"
+	"codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/assets"
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/billing"
 	"codeberg.org/algopatterns/server/internal/botdefense"
 	"codeberg.org/algopatterns/server/internal/buffer"
+	"codeberg.org/algopatterns/server/internal/cards"
 	"codeberg.org/algopatterns/server/internal/ccsignals"
 	"codeberg.org/algopatterns/server/internal/config"
+	"codeberg.org/algopatterns/server/internal/database"
+	"codeberg.org/algopatterns/server/internal/embeds"
+	"codeberg.org/algopatterns/server/internal/engagement"
+	"codeberg.org/algopatterns/server/internal/events"
+	"codeberg.org/algopatterns/server/internal/faults"
+	"codeberg.org/algopatterns/server/internal/follows"
+	"codeberg.org/algopatterns/server/internal/health"
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/joinrequests"
+	"codeberg.org/algopatterns/server/internal/lessons"
+	"codeberg.org/algopatterns/server/internal/llm"
 	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/mailer"
+	"codeberg.org/algopatterns/server/internal/moderation"
+	"codeberg.org/algopatterns/server/internal/notifications"
+	"codeberg.org/algopatterns/server/internal/pasteappeals"
+	"codeberg.org/algopatterns/server/internal/presence"
+	"codeberg.org/algopatterns/server/internal/quota"
+	"codeberg.org/algopatterns/server/internal/ratelimit"
+	"codeberg.org/algopatterns/server/internal/renders"
+	"codeberg.org/algopatterns/server/internal/retriever"
+	"codeberg.org/algopatterns/server/internal/samplepacks"
+	"codeberg.org/algopatterns/server/internal/security"
+	"codeberg.org/algopatterns/server/internal/stats"
+	"codeberg.org/algopatterns/server/internal/webhooks"
 	ws "codeberg.org/algopatterns/server/internal/websocket"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
@@ -34,23 +65,20 @@ const (
 func NewServer(cfg *config.Config) (*Server, error) {
 	ctx := context.Background()
 
-	poolConfig, err := pgxpool.ParseConfig(cfg.SupabaseConnString)
+	// sized and configured for the supabase pooler
+	poolConfig, err := database.PoolConfig(cfg.SupabaseConnString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse database config: %w", err)
 	}
 
-	// configure connection pool for supabase free tier pooler compatibility
-	// free tier has ~10-15 pooler connections, so keep our pool small
-	poolConfig.MaxConns = 5
-	poolConfig.MinConns = 1
-	poolConfig.MaxConnLifetime = 30 * time.Minute
-	poolConfig.MaxConnIdleTime = 5 * time.Minute
-	poolConfig.HealthCheckPeriod = 1 * time.Minute
-
-	// CRITICAL: use simple protocol for supabase pooler (PgBouncer) compatibility
-	// pgBouncer in transaction mode doesn't support prepared statements,
-	// which causes connections to hang on subsequent queries
-	poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	// admins may inject faults into redis and postgres to exercise the
+	// fallbacks. config validation refuses this in production
+	var faultInjector *faults.Injector
+	if cfg.FaultInjection {
+		faultInjector = faults.New()
+		faultInjector.PreparePool(poolConfig)
+		logger.Warn("fault injection enabled, admins can slow down or fail redis and postgres calls")
+	}
 
 	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
@@ -62,32 +90,123 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	// apply pending schema migrations first, so no code runs against an old schema
+	if cfg.AutoMigrate {
+		if err := migrateOnBoot(ctx, db); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	// lag-tolerant reads go to read replicas when configured, the rest to db
+	replicas, err := database.NewRouter(db, cfg.ReplicaConnStrings, cfg.ReplicaMaxLag)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to configure read replicas: %w", err)
+	}
+	if len(cfg.ReplicaConnStrings) > 0 {
+		logger.Info("read replicas configured", "count", len(cfg.ReplicaConnStrings), "max_lag", cfg.ReplicaMaxLag)
+	}
+
 	userRepo := users.NewRepository(db)
 	strudelRepo := strudels.NewRepository(db)
-	postgresSessionRepo := sessions.NewRepository(db)
+	strudelRepo.SetReadReplicas(replicas)
+	postgresSessionRepo := sessions.NewRepository(db, replicas)
 
 	// initialize Redis buffer for WebSocket write operations
-	sessionBuffer, err := buffer.NewSessionBuffer(cfg.RedisURL, bufferFlushInterval)
+	sessionBuffer, err := buffer.NewSessionBuffer(cfg.RedisMode, cfg.RedisURL, bufferFlushInterval)
 	if err != nil {
+		replicas.Close()
 		db.Close()
 		return nil, fmt.Errorf("failed to initialize redis buffer: %w", err)
 	}
 
+	if faultInjector != nil {
+		sessionBuffer.Client().AddHook(faultInjector.RedisHook())
+	}
+
 	// wrap session repo with buffering layer (writes go to Redis, reads go to Postgres)
 	sessionRepo := buffer.NewBufferedRepository(postgresSessionRepo, sessionBuffer)
 
 	// create flusher to periodically persist buffered data to Postgres
 	flusher := buffer.NewFlusher(sessionBuffer, postgresSessionRepo, bufferFlushInterval)
 
-	services, err := InitializeServices(cfg, db)
+	// durable background jobs, run by workers on every instance
+	jobQueue := jobs.New(db)
+
+	services, err := InitializeServices(cfg, db, jobQueue)
 	if err != nil {
 		sessionBuffer.Close() //nolint:errcheck,gosec // best-effort cleanup on init failure
+		replicas.Close()
 		db.Close()
 		return nil, fmt.Errorf("failed to initialize services: %w", err)
 	}
 
+	// embed published strudels into the RAG examples index
+	strudelRepo.SetEmbedder(services.LLM)
+	services.Retriever.SetReadReplicas(replicas)
+
+	// deliver events to user-registered webhooks
+	webhookService := webhooks.New(db, jobQueue)
+
+	// transactional email (logged instead of sent unless MAIL_PROVIDER is set)
+	mailConfig := mailer.ConfigFromEnv()
+	mailService, err := mailer.New(db, mailConfig)
+	if err != nil {
+		logger.ErrorErr(err, "failed to initialize mail provider, logging emails instead", "provider", mailConfig.Provider)
+		mailConfig.Provider = mailer.ProviderLog
+		mailService, _ = mailer.New(db, mailConfig) //nolint:errcheck // the log provider needs no configuration
+	}
+	logger.Info("mailer initialized", "provider", mailService.ProviderName())
+
+	// in-app notifications for events users want to hear about, with email digests
+	notificationService := notifications.New(db, jobQueue)
+	notificationService.SetDigestSender(mailService)
+
+	// events go to both webhooks and notifications
+	publisher := eventFanout{webhookService, notificationService}
+	services.Attribution.SetEventPublisher(publisher)
+
+	// scheduled public performances, announced to RSVPs through their webhooks
+	eventService := events.New(db)
+	eventService.SetEventPublisher(publisher)
+
+	// revocable read-only embeds of sessions and strudels
+	embedService := embeds.New(db)
+
+	// knock-to-join requests answered by session hosts
+	joinRequestService := joinrequests.New(db)
+
+	// community sample packs, recognized when analyzing code
+	samplePackService := samplepacks.New(db)
+
+	// appeals against paste locks, reviewed by admins
+	appealService := pasteappeals.New(db)
+
+	// append-only log of security-relevant actions
+	auditLog := audit.New(db)
+
+	// platform-wide usage numbers for the public stats page and admins
+	statsService := stats.New(db)
+	statsService.SetReadReplicas(replicas)
+
+	// likes, plays and the trending feed
+	engagementService := engagement.New(db, sessionBuffer.Client())
+	engagementService.SetReadReplicas(replicas)
+
+	// follows between users and the activity feed
+	followService := follows.New(db)
+
+	// guided lessons built from the teaching concepts, run inside sessions
+	lessonCatalog, err := lessons.LoadLessons(lessons.ConceptsPathFromEnv())
+	if err != nil {
+		logger.Warn("failed to load lessons, continuing without them", "error", err)
+	}
+	lessonService := lessons.New(db, lessonCatalog)
+	logger.Info("lessons loaded", "count", len(lessonService.List()))
+
 	// initialize CC signals detection system
-	ccSignals, err := InitializeCCSignals(ctx, sessionBuffer.Client(), strudelRepo)
+	ccSignals, err := InitializeCCSignals(ctx, sessionBuffer.Client(), strudelRepo, db, jobQueue)
 	if err != nil {
 		logger.ErrorErr(err, "failed to initialize ccsignals, continuing without paste protection")
 		// don't fail startup - paste protection is optional
@@ -113,15 +232,149 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		"honeypot_paths", len(botDefenseConfig.HoneypotPaths),
 	)
 
+	// avatar uploads to S3-compatible object storage (disabled unless configured)
+	storageConfig := assets.ConfigFromEnv()
+	var storageBackend assets.Backend
+	if storageConfig.Enabled() {
+		storageBackend = assets.NewS3(storageConfig)
+	}
+	assetService := assets.New(db, storageBackend)
+	logger.Info("object storage initialized", "enabled", assetService.Enabled())
+
+	// audio renders of strudels by an external headless renderer (disabled unless RENDER_URL and storage are set)
+	renderService := renders.New(db, jobQueue, storageBackend, renders.ConfigFromEnv())
+	logger.Info("audio rendering initialized", "enabled", renderService.Enabled())
+
+	// preview cards of shared strudels, with images by an external worker (disabled unless CARD_IMAGE_URL and storage are set)
+	cardService := cards.New(db, jobQueue, strudelRepo, storageBackend, cards.ConfigFromEnv())
+	logger.Info("card images initialized", "enabled", cardService.ImagesEnabled())
+
+	// Stripe subscriptions (disabled unless configured)
+	billingService := billing.New(db, billing.ConfigFromEnv())
+	logger.Info("billing initialized", "enabled", billingService.Enabled())
+
+	// screens chat and strudel text (disabled unless keyword lists or the classifier are configured)
+	moderationService := moderation.New(db, moderation.ConfigFromEnv(), services.LLM)
+	logger.Info("moderation initialized", "enabled", moderationService.Enabled())
+
+	// reuse generations for repeated prompts (AGENT_CACHE_TTL=0 disables)
+	responseCacheConfig := agent.ResponseCacheConfigFromEnv()
+	if responseCacheConfig.TTL > 0 {
+		services.Agent.SetResponseCache(agent.NewRedisResponseCache(sessionBuffer.Client(), responseCacheConfig.TTL), responseCacheConfig.BypassTiers...)
+		logger.Info("agent response cache enabled",
+			"ttl", responseCacheConfig.TTL,
+			"bypass_tiers", responseCacheConfig.BypassTiers,
+		)
+	}
+
+	// send generated code back to the model to fix lint findings (AGENT_MAX_REPAIRS=0 disables)
+	if maxRepairs := agent.MaxRepairsFromEnv(); maxRepairs > 0 {
+		services.Agent.SetSelfCritique(maxRepairs)
+		logger.Info("agent self-critique enabled", "max_repairs", maxRepairs)
+	}
+
+	// fold older conversation messages into a summary once histories outgrow AGENT_HISTORY_TOKENS
+	summarizer, err := llm.NewTransformerGenerator()
+	if err != nil {
+		logger.Warn("conversation summarizer unavailable, dropping older messages of long histories instead", "error", err)
+	}
+	services.Agent.SetHistoryCompactor(agent.NewHistoryCompactor(summarizer, agent.HistoryTokensFromEnv()))
+
+	// reuse query embeddings across searches (EMBEDDING_CACHE_TTL=0 disables)
+	if ttl := retriever.EmbeddingCacheTTLFromEnv(); ttl > 0 {
+		if model, err := llm.EmbeddingModel(); err != nil {
+			logger.ErrorErr(err, "failed to resolve embedding model, continuing without embedding cache")
+		} else {
+			services.Retriever.SetEmbeddingCache(retriever.NewRedisEmbeddingCache(sessionBuffer.Client(), model, ttl))
+			logger.Info("embedding cache enabled", "model", model, "ttl", ttl)
+		}
+	}
+
+	// AI generation quotas (redis counters backed by usage_logs)
+	quotaService := quota.New(db, sessionBuffer.Client())
+
+	// initialize REST rate limiting (token buckets shared across instances)
+	rateLimitConfig, err := ratelimit.ConfigWithOverrides(cfg.RateLimits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate limits in config file: %w", err)
+	}
+
+	rateLimiter := ratelimit.New(rateLimitConfig, ratelimit.NewStore(sessionBuffer.Client()))
+
+	// feature flags from the config file, reloaded with it
+	config.SetFeatures(cfg.Features)
+
 	hub := ws.NewHub()
+	hub.SetCodeUpdateCoalescing(cfg.CoalesceWindow)
+
+	// share connections with other instances so limits and activity checks see the whole cluster
+	presenceRegistry, err := presence.NewRegistry(sessionBuffer.Client())
+	if err != nil {
+		logger.ErrorErr(err, "failed to initialize presence registry, continuing with local presence only")
+	} else {
+		hub.SetPresenceRegistry(presenceRegistry)
+	}
 
 	// register websocket message handlers (handlers use sessionRepo interface, unaware of Redis)
 	hub.RegisterHandler(ws.TypeCodeUpdate, ws.CodeUpdateHandler(sessionRepo, detector))
-	hub.RegisterHandler(ws.TypeChatMessage, ws.ChatHandler(sessionRepo))
+	hub.RegisterHandler(ws.TypeCodeOperation, ws.CodeOperationHandler(detector))
+	hub.RegisterHandler(ws.TypeChatMessage, ws.ChatHandler(sessionRepo, moderationService))
+	hub.RegisterHandler(ws.TypeChatReply, ws.ChatReplyHandler(sessionRepo, moderationService))
+	hub.RegisterHandler(ws.TypeChatReaction, ws.ChatReactionHandler(sessionRepo))
 	hub.RegisterHandler(ws.TypePlay, ws.PlayHandler())
 	hub.RegisterHandler(ws.TypeStop, ws.StopHandler())
 	hub.RegisterHandler(ws.TypePing, ws.PingHandler())
+	hub.RegisterHandler(ws.TypeClockSync, ws.ClockSyncHandler())
 	hub.RegisterHandler(ws.TypeCursorPosition, ws.CursorPositionHandler())
+	hub.RegisterHandler(ws.TypeCursorUpdate, ws.CursorUpdateHandler())
+	hub.RegisterHandler(ws.TypeSelectionUpdate, ws.SelectionUpdateHandler())
+	hub.RegisterHandler(ws.TypeResume, ws.ResumeHandler())
+	hub.RegisterHandler(ws.TypeAgentRequest, ws.AgentRequestHandler(services.Agent, services.Providers, detector, strudelRepo, sessionBuffer, quotaService))
+	hub.RegisterHandler(ws.TypeUndo, ws.UndoHandler())
+	hub.RegisterHandler(ws.TypeRedo, ws.RedoHandler())
+	hub.RegisterHandler(ws.TypeLockRegion, ws.LockRegionHandler())
+	hub.RegisterHandler(ws.TypeUnlockRegion, ws.UnlockRegionHandler())
+
+	// checkpoint session code for undo/redo (redis ring, flusher writes to Postgres)
+	hub.SetCodeHistory(sessionBuffer)
+	hub.SetActivityRecorder(sessionBuffer)
+	hub.SetChatBackpressure(sessionBuffer)
+
+	// persist merged document snapshots (goes to redis buffer, flusher writes to Postgres)
+	hub.OnDocumentSnapshot(func(sessionID, code string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := sessionRepo.UpdateSessionCode(ctx, sessionID, code); err != nil {
+			logger.ErrorErr(err, "failed to save document snapshot",
+				"session_id", sessionID,
+			)
+		}
+	})
+
+	// keep restorable snapshots of edited sessions
+	hub.OnSessionSnapshot(func(sessionID, code string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := sessionRepo.CreateSnapshot(ctx, sessionID, code, sessions.SnapshotReasonAuto); err != nil {
+			logger.ErrorErr(err, "failed to create session snapshot",
+				"session_id", sessionID,
+			)
+		}
+	})
+
+	// keep AI assistant exchanges for session transcripts
+	hub.OnAgentResponse(func(exchange *sessions.AgentExchange) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := sessionRepo.AddAgentExchange(ctx, exchange); err != nil {
+			logger.ErrorErr(err, "failed to save agent exchange",
+				"session_id", exchange.SessionID,
+			)
+		}
+	})
 
 	// flush buffer on client disconnect
 	hub.OnClientDisconnect(func(client *ws.Client) {
@@ -179,6 +432,9 @@ func NewServer(cfg *config.Config) (*Server, error) {
 	})
 
 	router := gin.Default()
+	if err := security.TrustProxies(router, security.FromConfig(cfg)); err != nil {
+		return nil, fmt.Errorf("failed to configure trusted proxies: %w", err)
+	}
 
 	// create session cleanup service (handles auto-expiry of stale sessions)
 	cleanupService := sessions.NewCleanupService(
@@ -191,8 +447,119 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		},
 	)
 
+	cleanupService.SetOnSessionEnding(func(ctx context.Context, tx pgx.Tx, session *sessions.Session) error {
+		return publisher.PublishTx(ctx, tx, session.HostUserID, webhooks.EventSessionEnded, webhooks.SessionEventData{
+			SessionID: session.ID,
+			Title:     session.Title,
+			Reason:    "inactivity",
+		})
+	})
+
+	cleanupService.SetOnSessionEnded(func(ctx context.Context, session *sessions.Session) {
+		auditLog.Record(ctx, audit.Event{
+			Action:     audit.ActionSessionEnd,
+			TargetType: audit.TargetSession,
+			TargetID:   session.ID,
+			SessionID:  session.ID,
+			Details:    map[string]string{"reason": "inactivity", "title": session.Title},
+		})
+	})
+
+	// hosts can have the final code of an expired session published as a strudel
+	cleanupService.SetCodePublisher(func(ctx context.Context, session *sessions.Session) (string, error) {
+		req := strudels.CreateStrudelRequest{
+			Title:    session.Title,
+			Code:     session.Code,
+			IsPublic: true,
+		}
+
+		content := moderation.Content{
+			Type:     moderation.ContentStrudel,
+			AuthorID: session.HostUserID,
+			Text:     session.Title,
+		}
+
+		verdict := moderationService.Screen(ctx, content)
+		switch verdict.Action {
+		case moderation.ActionBlock:
+			return "", errors.New("session title blocked by content moderation")
+		case moderation.ActionHide:
+			// kept private until an admin approves it
+			req.IsPublic = false
+		}
+
+		strudel, err := strudelRepo.Create(ctx, session.HostUserID, req, nil)
+		if err != nil {
+			return "", err
+		}
+
+		content.ID = strudel.ID
+		moderationService.Report(ctx, content, verdict)
+
+		return strudel.ID, nil
+	})
+
+	// permanently delete strudels that have been in the trash for 30 days
+	cleanupService.AddTask(func(ctx context.Context) {
+		purged, err := strudelRepo.PurgeTrash(ctx)
+		if err != nil {
+			logger.ErrorErr(err, "failed to purge trashed strudels")
+			return
+		}
+
+		if ccSignals != nil {
+			for _, strudelID := range purged {
+				ccSignals.RemoveStrudel(strudelID)
+			}
+		}
+
+		if len(purged) > 0 {
+			logger.Info("purged trashed strudels", "count", len(purged))
+		}
+	})
+
+	// roll likes, plays and forks up into the trending feed
+	cleanupService.AddTask(engagementService.RefreshTrending)
+
+	// delete uploads never completed and avatars no longer in use
+	cleanupService.AddTask(assetService.CleanupOrphans)
+
+	// delete the card images of strudels no longer public
+	cleanupService.AddTask(cardService.DeleteStaleCards)
+
+	// delete jobs that failed for good once admins had time to look at them
+	cleanupService.AddTask(jobQueue.DeleteOldDeadLetters)
+
+	// pick up fingerprints other instances indexed and drop old tombstones
+	if ccSignals != nil {
+		cleanupService.AddTask(ccSignals.MaintainFingerprints)
+	}
+
+	// readiness checks; the LLM providers are optional since every instance
+	// shares them and readiness can't route around an outage. so is redis,
+	// the buffer holds writes in memory while it's away
+	healthChecks := []health.Check{
+		{Name: "postgres", Run: db.Ping},
+		{Name: "redis", Optional: true, Run: sessionBuffer.Check},
+	}
+	if probeLLM, err := llm.ProbeProviders(); err != nil {
+		logger.ErrorErr(err, "failed to set up LLM probe, readiness won't check providers")
+	} else {
+		healthChecks = append(healthChecks, health.Check{Name: "llm", Optional: true, TTL: time.Minute, Run: probeLLM})
+	}
+
+	// reads fall back to the primary, so lagging replicas only degrade
+	if len(cfg.ReplicaConnStrings) > 0 {
+		healthChecks = append(healthChecks, health.Check{Name: "postgres_replicas", Optional: true, Run: replicas.Check})
+	}
+
+	// reports components served by their fallback provider, or failing fast,
+	// while a provider's circuit is open
+	healthChecks = append(healthChecks, health.Check{Name: "llm_circuits", Optional: true, Run: llm.CircuitCheck(services.LLM)})
+
 	server := &Server{
 		db:             db,
+		replicas:       replicas,
 		config:         cfg,
 		userRepo:       userRepo,
 		strudelRepo:    strudelRepo,
@@ -202,12 +569,56 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		router:         router,
 		buffer:         sessionBuffer,
 		flusher:        flusher,
+		faults:         faultInjector,
+		presence:       presenceRegistry,
 		cleanupService: cleanupService,
 		ccSignals:      ccSignals,
 		botDefense:     botDefense,
+		rateLimiter:    rateLimiter,
+		quotas:         quotaService,
+		billing:        billingService,
+		jobs:           jobQueue,
+		webhooks:       webhookService,
+		notifications:  notificationService,
+		mailer:         mailService,
+		publisher:      publisher,
+		samplePacks:    samplePackService,
+		events:         eventService,
+		embeds:         embedService,
+		joinRequests:   joinRequestService,
+		audit:          auditLog,
+		moderation:     moderationService,
+		stats:          statsService,
+		lessons:        lessonService,
+		appeals:        appealService,
+		engagement:     engagementService,
+		follows:        followService,
+		assets:         assetService,
+		renders:        renderService,
+		cards:          cardService,
+		refreshTokens:  auth.NewRefreshStore(sessionBuffer.Client()),
+		deviceCodes:    auth.NewDeviceStore(sessionBuffer.Client()),
+		wsTickets:      auth.NewTicketStore(sessionBuffer.Client()),
+		health:         health.NewChecker(healthChecks...),
+		drainRequested: make(chan struct{}),
 	}
 
 	RegisterRoutes(router, server)
 
 	return server, nil
 }
+
+// sends each event to every publisher, in the transaction of the write it reports
+type eventFanout []interface {
+	PublishTx(ctx context.Context, tx pgx.Tx, userID, event string, data any) error
+}
+
+func (f eventFanout) PublishTx(ctx context.Context, tx pgx.Tx, userID, event string, data any) error {
+	for _, publisher := range f {
+		if err := publisher.PublishTx(ctx, tx, userID, event, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
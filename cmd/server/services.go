@@ -9,6 +9,7 @@ import (
 	"codeberg.org/algopatterns/server/internal/agent"
 	"codeberg.org/algopatterns/server/internal/attribution"
 	"codeberg.org/algopatterns/server/internal/config"
+	"codeberg.org/algopatterns/server/internal/jobs"
 	"codeberg.org/algopatterns/server/internal/llm"
 	"codeberg.org/algopatterns/server/internal/logger"
 	"codeberg.org/algopatterns/server/internal/retriever"
@@ -18,13 +19,32 @@ import (
 )
 
 // creates and configures all service clients
-func InitializeServices(_ *config.Config, db *pgxpool.Pool) (*Services, error) {
+func InitializeServices(_ *config.Config, db *pgxpool.Pool, queue *jobs.Queue) (*Services, error) {
 	llmClient, err := llm.NewLLM(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM client: %w", err)
 	}
 
+	providers := llm.RegistryFromEnv()
+	logger.Info("BYOK providers configured", "count", len(providers.Providers()), "default", providers.DefaultProvider())
+
 	retrieverClient := retriever.New(db, llmClient)
+
+	// optional reranking of hybrid search results
+	if rerankConfig := retriever.RerankConfigFromEnv(); rerankConfig.Enabled {
+		reranker, err := retriever.NewReranker(rerankConfig)
+		if err != nil {
+			logger.Warn("reranker unavailable, continuing without reranking", "error", err)
+		} else {
+			retrieverClient.SetReranker(reranker, rerankConfig.Candidates, rerankConfig.Timeout)
+			logger.Info("retriever reranking enabled",
+				"cross_encoder", rerankConfig.URL != "",
+				"candidates", rerankConfig.Candidates,
+				"timeout", rerankConfig.Timeout,
+			)
+		}
+	}
+
 	storageClient := &storage.Client{}
 
 	// initialize validator (optional/continues without if unavailable)
@@ -42,12 +62,13 @@ func InitializeServices(_ *config.Config, db *pgxpool.Pool) (*Services, error) {
 	}
 
 	agentClient := agent.NewWithValidator(retrieverClient, llmClient, validator)
-	attrService := attribution.New(db)
+	attrService := attribution.New(db, queue)
 
 	return &Services{
 		Agent:       agentClient,
 		Attribution: attrService,
 		LLM:         llmClient,
+		Providers:   providers,
 		Retriever:   retrieverClient,
 		Storage:     storageClient,
 		Validator:   validator,
@@ -1,18 +1,46 @@
 package main
 
 import (
+	"sync"
+
 	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/algopatterns/strudels"
 	"codeberg.org/algopatterns/server/algopatterns/users"
 	"codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/assets"
 	"codeberg.org/algopatterns/server/internal/attribution"
+	"codeberg.org/algopatterns/server/internal/audit"
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/billing"
 	"codeberg.org/algopatterns/server/internal/botdefense"
 	"codeberg.org/algopatterns/server/internal/buffer"
+	"codeberg.org/algopatterns/server/internal/cards"
 	"codeberg.org/algopatterns/server/internal/config"
+	"codeberg.org/algopatterns/server/internal/database"
+	"codeberg.org/algopatterns/server/internal/embeds"
+	"codeberg.org/algopatterns/server/internal/engagement"
+	"codeberg.org/algopatterns/server/internal/events"
+	"codeberg.org/algopatterns/server/internal/faults"
+	"codeberg.org/algopatterns/server/internal/follows"
+	"codeberg.org/algopatterns/server/internal/health"
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/joinrequests"
+	"codeberg.org/algopatterns/server/internal/lessons"
 	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/mailer"
+	"codeberg.org/algopatterns/server/internal/moderation"
+	"codeberg.org/algopatterns/server/internal/notifications"
+	"codeberg.org/algopatterns/server/internal/pasteappeals"
+	"codeberg.org/algopatterns/server/internal/presence"
+	"codeberg.org/algopatterns/server/internal/quota"
+	"codeberg.org/algopatterns/server/internal/ratelimit"
+	"codeberg.org/algopatterns/server/internal/renders"
 	"codeberg.org/algopatterns/server/internal/retriever"
+	"codeberg.org/algopatterns/server/internal/samplepacks"
+	"codeberg.org/algopatterns/server/internal/stats"
 	"codeberg.org/algopatterns/server/internal/storage"
 	"codeberg.org/algopatterns/server/internal/strudel"
+	"codeberg.org/algopatterns/server/internal/webhooks"
 	ws "codeberg.org/algopatterns/server/internal/websocket"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,6 +49,7 @@ import (
 // holds all dependencies and state for the API server
 type Server struct {
 	db             *pgxpool.Pool
+	replicas       *database.Router
 	config         *config.Config
 	userRepo       *users.Repository
 	strudelRepo    *strudels.Repository
@@ -30,9 +59,41 @@ type Server struct {
 	router         *gin.Engine
 	buffer         *buffer.SessionBuffer
 	flusher        *buffer.Flusher
+	presence       *presence.Registry
 	cleanupService *sessions.CleanupService
 	ccSignals      *CCSignalsSystem
 	botDefense     *botdefense.Defense
+	rateLimiter    *ratelimit.Limiter
+	quotas         *quota.Service
+	billing        *billing.Service
+	jobs           *jobs.Queue
+	webhooks       *webhooks.Service
+	notifications  *notifications.Service
+	mailer         *mailer.Service
+	publisher      eventFanout // webhooks and notifications
+	samplePacks    *samplepacks.Service
+	events         *events.Service
+	embeds         *embeds.Service
+	joinRequests   *joinrequests.Service
+	audit          *audit.Service
+	moderation     *moderation.Service
+	stats          *stats.Service
+	engagement     *engagement.Service
+	follows        *follows.Service
+	assets         *assets.Service
+	renders        *renders.Service
+	cards          *cards.Service
+	lessons        *lessons.Service
+	appeals        *pasteappeals.Service
+	refreshTokens  *auth.RefreshStore
+	deviceCodes    *auth.DeviceStore
+	wsTickets      *auth.TicketStore
+	health         *health.Checker
+	faults         *faults.Injector // nil unless FAULT_INJECTION is set
+
+	// closed once a drain is requested through the admin API
+	drainRequested chan struct{}
+	drainOnce      sync.Once
 }
 
 // holds all external service clients (LLM, storage, retriever, agent)
@@ -40,6 +101,7 @@ type Services struct {
 	Agent       *agent.Agent
 	Attribution *attribution.Service
 	LLM         llm.LLM
+	Providers   *llm.Registry // providers available with user-provided API keys
 	Retriever   *retriever.Client
 	Storage     *storage.Client
 	Validator   *strudel.Validator
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
+)
+
+// exit codes of the CLI
+const (
+	exitOK      = 0
+	exitError   = 1
+	exitUsage   = 2
+	exitUnclear = 3 // the agent asked clarifying questions instead of answering with code
+)
+
+var errNotSignedIn = errors.New("not signed in: login in the TUI or set ALGOPATTERNS_TOKEN")
+
+func printUsage() {
+	fmt.Println("Usage: algorave [command]")
+	fmt.Println("\nWithout a command the interactive TUI starts.")
+	fmt.Println("\nCommands:")
+	fmt.Println("  generate <query> [--editor-state <file>] [--json]")
+	fmt.Println("                          - Ask the agent for code and print it. --editor-state")
+	fmt.Println("                            sends the code to change, - reads it from stdin")
+	fmt.Println("  strudels list [--search <text>] [--tags <a,b>] [--json]")
+	fmt.Println("                          - List your strudels, most recently saved first")
+	fmt.Println("  sessions end <id> [--json]")
+	fmt.Println("                          - End a session you host")
+	fmt.Println("\nThe API is ALGOPATTERNS_API_ENDPOINT; requests are signed in with the TUI's")
+	fmt.Println("login, or ALGOPATTERNS_TOKEN when set.")
+	fmt.Println("\nExit codes: 0 success, 1 error, 2 usage, 3 the agent asked clarifying questions")
+}
+
+// runs a subcommand and returns the exit code
+func runCLI(args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := api.NewFromEnv()
+
+	var (
+		code int
+		err  error
+	)
+
+	switch {
+	case args[0] == "help" || args[0] == "-h" || args[0] == "--help":
+		printUsage()
+		return exitOK
+	case args[0] == "generate":
+		code, err = runGenerate(ctx, client, args[1:])
+	case args[0] == "strudels" && len(args) > 1 && args[1] == "list":
+		code, err = runListStrudels(ctx, client, args[2:])
+	case args[0] == "sessions" && len(args) > 1 && args[1] == "end":
+		code, err = runEndSession(ctx, client, args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", strings.Join(args, " "))
+		printUsage()
+		return exitUsage
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+	}
+
+	return code
+}
+
+func runGenerate(ctx context.Context, client *api.Client, args []string) (int, error) {
+	fs := newFlagSet("generate")
+	editorState := fs.String("editor-state", "", "file with the code to change, - for stdin")
+	asJSON := fs.Bool("json", false, "print the whole answer as JSON")
+
+	positional, code, err := parseFlags(fs, args)
+	if code >= 0 {
+		return code, err
+	}
+
+	query := strings.TrimSpace(strings.Join(positional, " "))
+	if query == "" {
+		return exitUsage, errors.New("generate needs a query")
+	}
+
+	req := api.GenerateRequest{UserQuery: query}
+	if *editorState != "" {
+		state, err := readInput(*editorState)
+		if err != nil {
+			return exitError, fmt.Errorf("failed to read editor state: %w", err)
+		}
+		req.EditorState = state
+	}
+
+	generation, err := client.Generate(ctx, req)
+	if err != nil {
+		return exitError, err
+	}
+
+	unclear := generation.Code == "" && len(generation.ClarifyingQuestions) > 0
+
+	if *asJSON {
+		if err := printJSON(generation); err != nil {
+			return exitError, err
+		}
+	} else if unclear {
+		for _, question := range generation.ClarifyingQuestions {
+			fmt.Fprintln(os.Stderr, question)
+		}
+	} else {
+		fmt.Println(generation.Code)
+	}
+
+	if unclear {
+		return exitUnclear, nil
+	}
+
+	return exitOK, nil
+}
+
+func runListStrudels(ctx context.Context, client *api.Client, args []string) (int, error) {
+	fs := newFlagSet("strudels list")
+	search := fs.String("search", "", "match titles and descriptions")
+	tags := fs.String("tags", "", "comma-separated tags a strudel must all have")
+	asJSON := fs.Bool("json", false, "print the strudels as JSON")
+
+	positional, code, err := parseFlags(fs, args)
+	if code >= 0 {
+		return code, err
+	}
+	if len(positional) > 0 {
+		return exitUsage, fmt.Errorf("unexpected arguments: %s", strings.Join(positional, " "))
+	}
+
+	if !client.Authenticated() {
+		return exitError, errNotSignedIn
+	}
+
+	var tagList []string
+	for tag := range strings.SplitSeq(*tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tagList = append(tagList, tag)
+		}
+	}
+
+	// every page, so scripts see the whole library
+	strudels := []api.Strudel{}
+	cursor := ""
+	for {
+		page, err := client.ListStrudels(ctx, *search, tagList, cursor)
+		if err != nil {
+			return exitError, err
+		}
+
+		strudels = append(strudels, page.Strudels...)
+
+		if !page.Pagination.HasMore || page.Pagination.NextCursor == "" {
+			break
+		}
+		cursor = page.Pagination.NextCursor
+	}
+
+	if *asJSON {
+		if err := printJSON(strudels); err != nil {
+			return exitError, err
+		}
+		return exitOK, nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tTAGS\tUPDATED")
+	for _, strudel := range strudels {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", strudel.ID, strudel.Title, strings.Join(strudel.Tags, ","), strudel.UpdatedAt.Format(time.RFC3339))
+	}
+
+	return exitOK, w.Flush()
+}
+
+func runEndSession(ctx context.Context, client *api.Client, args []string) (int, error) {
+	fs := newFlagSet("sessions end")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+
+	positional, code, err := parseFlags(fs, args)
+	if code >= 0 {
+		return code, err
+	}
+	if len(positional) != 1 {
+		return exitUsage, errors.New("sessions end needs one session ID")
+	}
+
+	if !client.Authenticated() {
+		return exitError, errNotSignedIn
+	}
+
+	sessionID := positional[0]
+	if err := client.EndSession(ctx, sessionID); err != nil {
+		return exitError, err
+	}
+
+	if *asJSON {
+		if err := printJSON(endSessionResult{SessionID: sessionID, Ended: true}); err != nil {
+			return exitError, err
+		}
+		return exitOK, nil
+	}
+
+	fmt.Printf("ended session %s\n", sessionID)
+	return exitOK, nil
+}
+
+// printed by sessions end --json
+type endSessionResult struct {
+	SessionID string `json:"session_id"`
+	Ended     bool   `json:"ended"`
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	return fs
+}
+
+// parses flags wherever they are among the arguments, so they may follow
+// the query or ID, and returns the other arguments. code is the exit code
+// to stop with, or -1 to carry on
+func parseFlags(fs *flag.FlagSet, args []string) (positional []string, code int, err error) {
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, exitOK, nil
+			}
+			// already printed by the flag set
+			return nil, exitUsage, nil
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, -1, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// reads a file, or stdin for "-"
+func readInput(path string) (string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // a file the user named
+	return string(data), err
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
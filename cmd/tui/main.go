@@ -9,6 +9,11 @@ import (
 )
 
 func main() {
+	// commands run without the TUI, for scripts and CI
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:]))
+	}
+
 	env := os.Getenv("ALGOPATTERNS_ENV")
 
 	if env == "" {
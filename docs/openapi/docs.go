@@ -22,35 +22,65 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/admin/strudels/{id}": {
+        "/api/v1/admin/audit": {
             "get": {
-                "security": [
-                    {
-                        "AdminKeyAuth": []
-                    }
-                ],
-                "description": "Admin-only endpoint to get any strudel regardless of ownership",
+                "description": "Admin-only endpoint to read the global audit log, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Get any strudel by ID (admin)",
+                "summary": "List audit log (admin)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Strudel ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by acting user ID",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by session ID",
+                        "name": "session_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by action (e.g. admin.user.ban)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by target type (session, participant, invite_token, strudel, user)",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by target ID",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_admin.StrudelAdminResponse"
+                            "$ref": "#/definitions/api_rest_admin.AuditLogResponse"
                         }
                     },
                     "401": {
@@ -59,8 +89,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
@@ -71,56 +101,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/admin/strudels/{id}/use-in-training": {
-            "put": {
+                },
                 "security": [
                     {
                         "AdminKeyAuth": []
                     }
-                ],
-                "description": "Admin-only endpoint to mark a strudel for use in training data",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/v1/admin/drain": {
+            "post": {
+                "description": "Admin-only endpoint to prepare the serving instance for a deploy. It stops accepting WebSocket connections, sends every client a reconnect_hint, waits up to DRAIN_WINDOW for them to reconnect elsewhere and then shuts down. Same as sending the process SIGUSR1.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Set use_in_training flag on a strudel",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Strudel ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Use in training data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api_rest_admin.SetUseInTrainingRequest"
-                        }
-                    }
-                ],
+                "summary": "Drain the instance (admin)",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api_rest_admin.StrudelAdminResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                            "$ref": "#/definitions/api_rest_admin.MessageResponse"
                         }
                     },
                     "401": {
@@ -129,107 +132,76 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Instance is already draining",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/agent/generate": {
-            "post": {
-                "description": "Generate Strudel code using AI with optional BYOK support",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/admin/faults": {
+            "get": {
+                "description": "Admin-only endpoint to read the faults currently injected into Redis and Postgres. Only registered when FAULT_INJECTION is enabled, which production refuses",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "agent"
-                ],
-                "summary": "Generate code with AI",
-                "parameters": [
-                    {
-                        "description": "Generation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api_rest_agent.GenerateRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "List injected faults (admin)",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_agent.GenerateResponse"
+                            "$ref": "#/definitions/api_rest_admin.FaultsResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/auth/logout": {
-            "post": {
-                "description": "Clear authentication session",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Logout",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api_rest_auth.MessageResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/auth/me": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Get authenticated user's profile",
+                ]
+            },
+            "delete": {
+                "description": "Admin-only endpoint to stop injecting faults into Redis and Postgres. Only registered when FAULT_INJECTION is enabled, which production refuses",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Get current user",
+                "summary": "Stop injecting faults (admin)",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_auth.UserResponse"
+                            "$ref": "#/definitions/api_rest_admin.MessageResponse"
                         }
                     },
                     "401": {
@@ -238,21 +210,23 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Update authenticated user's name and avatar",
+                ]
+            }
+        },
+        "/api/v1/admin/faults/{target}": {
+            "put": {
+                "description": "Admin-only endpoint to add latency to, fail or drop the connection of a share of this instance's calls to Redis or Postgres, replacing the target's previous fault. Used to check the fallbacks work: Redis errors make the session buffer write to Postgres directly, dropped Redis connections make it hold writes in memory, and paste lock checks fail open. Only registered when FAULT_INJECTION is enabled, which production refuses",
                 "consumes": [
                     "application/json"
                 ],
@@ -260,17 +234,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Update user profile",
+                "summary": "Inject a fault (admin)",
                 "parameters": [
                     {
-                        "description": "Profile update",
+                        "type": "string",
+                        "description": "redis or postgres",
+                        "name": "target",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fault to inject",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_rest_auth.UpdateProfileRequest"
+                            "$ref": "#/definitions/api_rest_admin.FaultRequest"
                         }
                     }
                 ],
@@ -278,7 +259,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_auth.UserResponse"
+                            "$ref": "#/definitions/api_rest_admin.FaultResponse"
                         }
                     },
                     "400": {
@@ -293,97 +274,129 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/auth/{provider}": {
+        "/api/v1/admin/jobs/dead-letters": {
             "get": {
-                "description": "Begin OAuth authentication flow with specified provider (google, github, apple)",
+                "description": "Admin-only endpoint to read background jobs that failed permanently or ran out of attempts, most recently failed first. Dead letters are kept for 30 days",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Start OAuth authentication",
+                "summary": "List failed background jobs (admin)",
                 "parameters": [
                     {
-                        "enum": [
-                            "google",
-                            "github",
-                            "apple"
-                        ],
                         "type": "string",
-                        "description": "OAuth provider",
-                        "name": "provider",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by job kind (e.g. webhooks.event)",
+                        "name": "kind",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "URL to redirect to after authentication",
-                        "name": "redirect_url",
+                        "type": "integer",
+                        "description": "Max results (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
                 "responses": {
-                    "302": {
-                        "description": "Redirect to OAuth provider",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api_rest_admin.DeadLettersResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/auth/{provider}/callback": {
-            "get": {
-                "description": "OAuth provider callback. Redirects to original URL with token, or returns JSON if no redirect URL",
+        "/api/v1/admin/jobs/dead-letters/{id}/retry": {
+            "post": {
+                "description": "Admin-only endpoint to put a dead letter back on the job queue with fresh attempts, e.g. after the outage that failed it is over",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "OAuth callback",
+                "summary": "Retry a failed background job (admin)",
                 "parameters": [
                     {
-                        "enum": [
-                            "google",
-                            "github",
-                            "apple"
-                        ],
                         "type": "string",
-                        "description": "OAuth provider",
-                        "name": "provider",
+                        "description": "Job ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_auth.AuthResponse"
+                            "$ref": "#/definitions/api_rest_admin.MessageResponse"
                         }
                     },
-                    "302": {
-                        "description": "Redirect to original URL with token",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
@@ -394,68 +407,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/ping": {
-            "get": {
-                "description": "Simple ping endpoint",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Ping",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api_rest_health.PingResponse"
-                        }
+                },
+                "security": [
+                    {
+                        "AdminKeyAuth": []
                     }
-                }
+                ]
             }
         },
-        "/api/v1/public/strudels": {
+        "/api/v1/admin/moderation": {
             "get": {
-                "description": "Get publicly shared strudels from all users with pagination, search, and filtering",
+                "description": "Admin-only endpoint to read flagged and shadow-hidden content, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "strudels"
+                    "admin"
                 ],
-                "summary": "List public strudels",
+                "summary": "List moderation queue",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Items per page (max 100)",
-                        "name": "limit",
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Filter by status (pending, approved, removed)",
+                        "name": "status",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Number of items to skip",
-                        "name": "offset",
+                        "type": "string",
+                        "description": "Filter by content type (chat_message, strudel)",
+                        "name": "content_type",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Search in title and description",
-                        "name": "search",
+                        "type": "integer",
+                        "description": "Max results (default 50, max 200)",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Filter by tags (comma-separated)",
-                        "name": "tags",
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
@@ -463,33 +456,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_strudels.StrudelsListResponse"
+                            "$ref": "#/definitions/api_rest_admin.ModerationQueueResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/public/strudels/tags": {
-            "get": {
-                "description": "Get all unique tags from public strudels",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "strudels"
-                ],
-                "summary": "List public tags",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_strudels.TagsListResponse"
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
                     "500": {
@@ -498,33 +477,67 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/public/strudels/{id}": {
-            "get": {
-                "description": "Get a publicly shared strudel by its ID (for forking)",
+        "/api/v1/admin/moderation/{id}/approve": {
+            "post": {
+                "description": "Admin-only endpoints to close a pending moderation item. Approving a hidden strudel publishes it, removing a strudel unpublishes it. Chat messages are only marked as reviewed",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "strudels"
+                    "admin"
                 ],
-                "summary": "Get public strudel by ID",
+                "summary": "Resolve moderation item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Strudel ID (UUID)",
+                        "description": "Moderation item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Reason for the audit log",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_admin.ModerationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_moderation.QueueItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
                     "404": {
@@ -539,33 +552,67 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/public/strudels/{id}/stats": {
-            "get": {
-                "description": "Get attribution stats for a public strudel (how many times it was used as RAG context)",
+        "/api/v1/admin/moderation/{id}/remove": {
+            "post": {
+                "description": "Admin-only endpoints to close a pending moderation item. Approving a hidden strudel publishes it, removing a strudel unpublishes it. Chat messages are only marked as reviewed",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "strudels"
+                    "admin"
                 ],
-                "summary": "Get strudel usage stats",
+                "summary": "Resolve moderation item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Strudel ID (UUID)",
+                        "description": "Moderation item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Reason for the audit log",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_admin.ModerationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_attribution.StrudelStatsResponse"
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_moderation.QueueItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
                     "404": {
@@ -580,30 +627,42 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Get all sessions where user is host or participant",
+                ]
+            }
+        },
+        "/api/v1/admin/paste-lock-appeals": {
+            "get": {
+                "description": "Admin-only endpoint to read appeals against paste locks, newest first. Appeals whose ownership was verified were approved automatically",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "List user's sessions",
+                "summary": "List paste lock appeals (admin)",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "default": false,
-                        "description": "Only return active sessions",
-                        "name": "active_only",
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Filter by status (pending, approved, rejected, all)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
@@ -611,7 +670,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.SessionsListResponse"
+                            "$ref": "#/definitions/api_rest_admin.PasteLockAppealsResponse"
                         }
                     },
                     "401": {
@@ -620,21 +679,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Create a new collaborative coding session (authenticated users only)",
+                ]
+            }
+        },
+        "/api/v1/admin/paste-lock-appeals/{id}/approve": {
+            "post": {
+                "description": "Admin-only endpoints to close a pending paste lock appeal. Approving lifts the session's paste lock and notifies connected clients",
                 "consumes": [
                     "application/json"
                 ],
@@ -642,25 +709,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "Create collaboration session",
+                "summary": "Resolve paste lock appeal (admin)",
                 "parameters": [
                     {
-                        "description": "Session data",
+                        "type": "string",
+                        "description": "Appeal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the audit log",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.CreateSessionRequest"
+                            "$ref": "#/definitions/api_rest_admin.ModerationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.CreateSessionResponse"
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_pasteappeals.Appeal"
                         }
                     },
                     "400": {
@@ -675,23 +748,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions/join": {
-            "post": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Join a collaborative session using an invite token (authenticated or anonymous)",
+                ]
+            }
+        },
+        "/api/v1/admin/paste-lock-appeals/{id}/reject": {
+            "post": {
+                "description": "Admin-only endpoints to close a pending paste lock appeal. Approving lifts the session's paste lock and notifies connected clients",
                 "consumes": [
                     "application/json"
                 ],
@@ -699,17 +784,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "Join session with invite",
+                "summary": "Resolve paste lock appeal (admin)",
                 "parameters": [
                     {
-                        "description": "Join request with invite token",
+                        "type": "string",
+                        "description": "Appeal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the audit log",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.JoinSessionRequest"
+                            "$ref": "#/definitions/api_rest_admin.ModerationRequest"
                         }
                     }
                 ],
@@ -717,7 +808,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.JoinSessionResponse"
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_pasteappeals.Appeal"
                         }
                     },
                     "400": {
@@ -726,45 +817,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Invalid or expired invite",
-                        "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/sessions/last": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns the user's most recent active session where they are host, excluding sessions they're currently in",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "sessions"
-                ],
-                "summary": "Get user's last active session for recovery",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.LiveSessionResponse"
-                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
@@ -781,31 +841,41 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/sessions/live": {
+        "/api/v1/admin/sessions": {
             "get": {
-                "description": "Get discoverable active sessions plus user's own active sessions (if authenticated)",
+                "description": "Admin-only endpoint to list sessions regardless of host or visibility, most recently active first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "List live sessions",
+                "summary": "List all sessions (admin)",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Only active sessions",
+                        "name": "active",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "default": 20,
-                        "description": "Items per page (max 100)",
+                        "description": "Max results (default 20, max 100)",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 0,
-                        "description": "Number of items to skip",
+                        "description": "Offset for pagination",
                         "name": "offset",
                         "in": "query"
                     }
@@ -814,76 +884,38 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.LiveSessionsListResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/sessions/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get session information including participants",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "sessions"
-                ],
-                "summary": "Get session details",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Session ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.SessionResponse"
+                            "$ref": "#/definitions/api_rest_admin.SessionsListResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Update the code in a session (host or co-authors only)",
+                ]
+            }
+        },
+        "/api/v1/admin/sessions/{id}/end": {
+            "post": {
+                "description": "Admin-only endpoint to end a session and disconnect its participants",
                 "consumes": [
                     "application/json"
                 ],
@@ -891,9 +923,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "Update session code",
+                "summary": "End any session (admin)",
                 "parameters": [
                     {
                         "type": "string",
@@ -903,12 +935,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Code update",
+                        "description": "Reason for the audit log",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.UpdateSessionCodeRequest"
+                            "$ref": "#/definitions/api_rest_admin.ModerationRequest"
                         }
                     }
                 ],
@@ -916,7 +947,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.UpdateSessionCodeResponse"
+                            "$ref": "#/definitions/api_rest_admin.MessageResponse"
                         }
                     },
                     "400": {
@@ -943,28 +974,36 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "End a collaborative session (host only)",
+                ]
+            }
+        },
+        "/api/v1/admin/sessions/{id}/paste-lock": {
+            "get": {
+                "description": "Admin-only endpoint to see whether a session is paste-locked and the recent paste detection decisions behind it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "End session",
+                "summary": "Inspect a session's paste lock (admin)",
                 "parameters": [
                     {
                         "type": "string",
@@ -978,7 +1017,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.MessageResponse"
+                            "$ref": "#/definitions/api_rest_admin.PasteLockResponse"
                         }
                     },
                     "400": {
@@ -999,29 +1038,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions/{id}/discoverable": {
-            "put": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Toggle whether a session appears in the live sessions list (host only)",
+                ]
+            },
+            "delete": {
+                "description": "Admin-only endpoint to lift a paste lock, e.g. after a false positive. Connected clients are notified.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1029,9 +1066,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "Set session discoverability",
+                "summary": "Remove a session's paste lock (admin)",
                 "parameters": [
                     {
                         "type": "string",
@@ -1041,12 +1078,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Discoverability settings",
+                        "description": "Reason for the audit log",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.SetDiscoverableRequest"
+                            "$ref": "#/definitions/api_rest_admin.ModerationRequest"
                         }
                     }
                 ],
@@ -1054,7 +1090,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.SessionResponse"
+                            "$ref": "#/definitions/api_rest_admin.MessageResponse"
                         }
                     },
                     "400": {
@@ -1086,45 +1122,36 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions/{id}/end-live": {
-            "post": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Ends the live portion of a session: kicks all non-host participants, revokes all invite tokens,\nsets discoverable to false. Host keeps access to the session and their code.",
+                ]
+            }
+        },
+        "/api/v1/admin/stats": {
+            "get": {
+                "description": "Admin-only endpoint to view websocket connection counts of the serving instance",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
-                ],
-                "summary": "Soft-end a live session",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Session ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "Connection stats (admin)",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.SoftEndSessionResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                            "$ref": "#/definitions/api_rest_admin.StatsResponse"
                         }
                     },
                     "401": {
@@ -1138,9 +1165,55 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    }
+                },
+                "security": [
+                    {
+                        "AdminKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/admin/stats/usage": {
+            "get": {
+                "description": "Admin-only endpoint breaking down AI generations and tokens per provider and model, with the estimated cost of generations made with platform keys at list prices. Models without a known price count as free and have pricing_known set to false.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "AI usage and cost (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Days to look back (max 365)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_stats.UsageStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
@@ -1151,28 +1224,28 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions/{id}/invite": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Get all invite tokens for a session (host only)",
+                ]
+            }
+        },
+        "/api/v1/admin/strudels/{id}": {
+            "get": {
+                "description": "Admin-only endpoint to get any strudel regardless of ownership",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "List invite tokens",
+                "summary": "Get any strudel by ID (admin)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID (UUID)",
+                        "description": "Strudel ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1182,13 +1255,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.InviteTokensListResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                            "$ref": "#/definitions/api_rest_admin.StrudelAdminResponse"
                         }
                     },
                     "401": {
@@ -1197,12 +1264,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1215,15 +1276,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
-                ],
-                "description": "Generate an invite link for joining the session (host only)",
+                ]
+            },
+            "delete": {
+                "description": "Admin-only endpoint to delete a strudel regardless of ownership",
                 "consumes": [
                     "application/json"
                 ],
@@ -1231,32 +1292,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "Create invite token",
+                "summary": "Delete any strudel (admin)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID (UUID)",
+                        "description": "Strudel ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Token settings",
+                        "description": "Reason for the audit log",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.CreateInviteTokenRequest"
+                            "$ref": "#/definitions/api_rest_admin.ModerationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.InviteTokenResponse"
+                            "$ref": "#/definitions/api_rest_admin.MessageResponse"
                         }
                     },
                     "400": {
@@ -1289,45 +1349,49 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions/{id}/invite/{token_id}": {
-            "delete": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
+                ]
+            }
+        },
+        "/api/v1/admin/strudels/{id}/unpublish": {
+            "post": {
+                "description": "Admin-only endpoint to make a strudel private regardless of ownership",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Revoke an invite token to prevent further use (host only)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "Revoke invite token",
+                "summary": "Unpublish any strudel (admin)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID (UUID)",
+                        "description": "Strudel ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Token ID (UUID)",
-                        "name": "token_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Reason for the audit log",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_admin.ModerationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.MessageResponse"
+                            "$ref": "#/definitions/api_rest_admin.StrudelAdminResponse"
                         }
                     },
                     "400": {
@@ -1360,38 +1424,50 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions/{id}/leave": {
-            "post": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
+                ]
+            }
+        },
+        "/api/v1/admin/strudels/{id}/use-in-training": {
+            "put": {
+                "description": "Admin-only endpoint to mark a strudel for use in training data",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Leave a collaborative session",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "Leave session",
+                "summary": "Set use_in_training flag on a strudel",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID (UUID)",
+                        "description": "Strudel ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Use in training data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_admin.SetUseInTrainingRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.MessageResponse"
+                            "$ref": "#/definitions/api_rest_admin.StrudelAdminResponse"
                         }
                     },
                     "400": {
@@ -1418,38 +1494,50 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions/{id}/live-status": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
+                ]
+            }
+        },
+        "/api/v1/admin/users/{id}/ban": {
+            "post": {
+                "description": "Admin-only endpoint to ban a user. Banned users can't sign in or join sessions, and their open connections are closed.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Returns whether a session is currently live (has other participants or active invite tokens)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "Check if session is live",
+                "summary": "Ban a user (admin)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID (UUID)",
+                        "description": "User ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Reason for the ban",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_admin.BanUserRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.IsLiveResponse"
+                            "$ref": "#/definitions/api_rest_admin.BanUserResponse"
                         }
                     },
                     "400": {
@@ -1464,6 +1552,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1476,45 +1570,47 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions/{id}/messages": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
+                ]
+            },
+            "delete": {
+                "description": "Admin-only endpoint to lift a user's ban",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieve chat messages from a session (AI conversations are strudel-scoped and fetched separately)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "admin"
                 ],
-                "summary": "Get session chat messages",
+                "summary": "Unban a user (admin)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID (UUID)",
+                        "description": "User ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 100,
-                        "description": "Max messages to return (max 1000)",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Reason for the audit log",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_admin.ModerationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.MessagesResponse"
+                            "$ref": "#/definitions/api_rest_admin.UserAdminResponse"
                         }
                     },
                     "400": {
@@ -1529,54 +1625,77 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/sessions/{id}/participants": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "AdminKeyAuth": []
                     }
+                ]
+            }
+        },
+        "/api/v1/agent/generate": {
+            "post": {
+                "description": "Generate Strudel code using AI with optional BYOK support",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get all participants in a session (authenticated and anonymous)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "agent"
                 ],
-                "summary": "List session participants",
+                "summary": "Generate code with AI",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Session ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Generation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_agent.GenerateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.ParticipantsListResponse"
+                            "$ref": "#/definitions/api_rest_agent.GenerateResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "invalid request, or the provider rejected your API key",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "429": {
+                        "description": "quota reached, or your API key is rate limited",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
@@ -1586,46 +1705,45 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "AI generation is switched off or the provider is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/sessions/{id}/participants/{participant_id}": {
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/api/v1/agent/generate/stream": {
+            "post": {
+                "description": "Stream Strudel code generation using Server-Sent Events. Emits \"refs\" (retrieval counts and references), \"chunk\" (generated tokens) and a terminal \"done\" event with references and rate-limit info.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Remove a participant from the session (host only)",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "sessions"
+                    "agent"
                 ],
-                "summary": "Remove participant",
+                "summary": "Stream generate code with AI (SSE)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Session ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Participant ID (UUID)",
-                        "name": "participant_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Generation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_agent.GenerateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_collaboration.MessageResponse"
+                            "$ref": "#/definitions/api_rest_agent.StreamEvent"
                         }
                     },
                     "400": {
@@ -1634,26 +1752,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
-                        }
-                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "AI generation is switched off",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
@@ -1661,64 +1773,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/strudels": {
+        "/api/v1/agent/providers": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get strudels owned by the authenticated user with pagination, search, and filtering",
+                "description": "List the providers and models that can be used with your own API key",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "strudels"
-                ],
-                "summary": "List user's strudels",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Items per page (max 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Number of items to skip",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search in title and description",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Filter by tags (comma-separated)",
-                        "name": "tags",
-                        "in": "query"
-                    }
+                    "agent"
                 ],
+                "summary": "List AI providers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_strudels.StrudelsListResponse"
+                            "$ref": "#/definitions/api_rest_agent.ProvidersResponse"
                         }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/device": {
+            "post": {
+                "description": "Start signing in a client without a browser, like the terminal app. The user approves the returned user code on the website while the client polls /auth/device/token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start device sign-in",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                            "$ref": "#/definitions/api_rest_auth.DeviceAuthorizationResponse"
                         }
                     },
                     "500": {
@@ -1728,14 +1817,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/auth/device/approve": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Save a new Strudel pattern with code, title, and metadata",
+                "description": "Sign the client showing the user code in as the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1743,25 +1829,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "strudels"
+                    "auth"
                 ],
-                "summary": "Create strudel",
+                "summary": "Approve device sign-in",
                 "parameters": [
                     {
-                        "description": "Strudel data",
+                        "description": "User code shown by the client",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CreateStrudelRequest"
+                            "$ref": "#/definitions/api_rest_auth.DeviceDecisionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                            "$ref": "#/definitions/api_rest_auth.MessageResponse"
                         }
                     },
                     "400": {
@@ -1776,39 +1862,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/strudels/tags": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get all unique tags from the authenticated user's strudels",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "strudels"
-                ],
-                "summary": "List user's tags",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api_rest_strudels.TagsListResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
@@ -1819,33 +1874,43 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/strudels/{id}": {
-            "get": {
-                "description": "Get a specific strudel by ID (owner or public)",
+        "/api/v1/auth/device/deny": {
+            "post": {
+                "description": "Refuse the sign-in of the client showing the user code",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "strudels"
+                    "auth"
                 ],
-                "summary": "Get strudel by ID",
+                "summary": "Deny device sign-in",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Strudel ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User code shown by the client",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_auth.DeviceDecisionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_strudels.StrudelDetailResponse"
+                            "$ref": "#/definitions/api_rest_auth.MessageResponse"
                         }
                     },
                     "400": {
@@ -1854,21 +1919,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update a strudel's properties (must be owner)",
+                ]
+            }
+        },
+        "/api/v1/auth/device/token": {
+            "post": {
+                "description": "Exchange the device code for tokens once the user approved it. Until then answers 400 with authorization_pending, or slow_down when polled more often than the interval; access_denied and expired_token end the sign-in",
                 "consumes": [
                     "application/json"
                 ],
@@ -1876,24 +1955,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "strudels"
+                    "auth"
                 ],
-                "summary": "Update strudel",
+                "summary": "Poll device sign-in",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Strudel ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update data",
+                        "description": "Device code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.UpdateStrudelRequest"
+                            "$ref": "#/definitions/api_rest_auth.DeviceTokenRequest"
                         }
                     }
                 ],
@@ -1901,7 +1973,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                            "$ref": "#/definitions/api_rest_auth.AuthResponse"
                         }
                     },
                     "400": {
@@ -1916,42 +1988,49 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/api/v1/auth/logout": {
+            "post": {
+                "description": "Clear authentication session and revoke the given refresh token",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Delete a strudel (must be owner)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "strudels"
+                    "auth"
                 ],
-                "summary": "Delete strudel",
+                "summary": "Logout",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Strudel ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh token to revoke",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_auth.LogoutRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_strudels.MessageResponse"
+                            "$ref": "#/definitions/api_rest_auth.MessageResponse"
                         }
                     },
                     "400": {
@@ -1959,6 +2038,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/logout-all": {
+            "post": {
+                "description": "Revoke all refresh tokens of the authenticated user. Access tokens stay valid until they expire",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout everywhere",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_auth.MessageResponse"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1966,23 +2065,72 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/users/ai-features-enabled": {
-            "put": {
+        "/api/v1/auth/me": {
+            "get": {
+                "description": "Get authenticated user's profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response, answered with 304 while it is current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_auth.UserResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Toggle whether AI features (prompt bar, code generation) are enabled for the user",
+                ]
+            },
+            "put": {
+                "description": "Update authenticated user's name and avatar",
                 "consumes": [
                     "application/json"
                 ],
@@ -1990,17 +2138,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Update user's AI features setting",
+                "summary": "Update user profile",
                 "parameters": [
                     {
-                        "description": "AI features enabled data",
+                        "description": "Profile update",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_rest_users.AIFeaturesEnabledRequest"
+                            "$ref": "#/definitions/api_rest_auth.UpdateProfileRequest"
                         }
                     }
                 ],
@@ -2008,7 +2156,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_users.User"
+                            "$ref": "#/definitions/api_rest_auth.UserResponse"
                         }
                     },
                     "400": {
@@ -2029,17 +2177,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/users/training-consent": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Toggle whether the user's public strudels can be used for AI training",
+                ]
+            }
+        },
+        "/api/v1/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and refresh token. Each refresh token works once;\nreusing a rotated token revokes all of the user's refresh tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -2047,17 +2195,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Update user's training consent",
+                "summary": "Refresh tokens",
                 "parameters": [
                     {
-                        "description": "Consent data",
+                        "description": "Refresh token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_rest_users.TrainingConsentRequest"
+                            "$ref": "#/definitions/api_rest_auth.RefreshRequest"
                         }
                     }
                 ],
@@ -2065,7 +2213,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_users.User"
+                            "$ref": "#/definitions/api_rest_auth.TokenResponse"
                         }
                     },
                     "400": {
@@ -2080,6 +2228,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2089,30 +2243,98 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/usage": {
+        "/api/v1/auth/{provider}": {
             "get": {
-                "security": [
+                "description": "Begin OAuth authentication flow with specified provider (google, github, apple, discord, gitlab)",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start OAuth authentication",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "enum": [
+                            "google",
+                            "github",
+                            "apple",
+                            "discord",
+                            "gitlab"
+                        ],
+                        "type": "string",
+                        "description": "OAuth provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "URL to redirect to after authentication",
+                        "name": "redirect_url",
+                        "in": "query"
                     }
                 ],
-                "description": "Returns usage statistics for the authenticated user including today's count, daily limit, and usage history",
+                "responses": {
+                    "302": {
+                        "description": "Redirect to OAuth provider",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/{provider}/callback": {
+            "get": {
+                "description": "OAuth provider callback. Redirects to original URL with token and refresh_token, or returns JSON if no redirect URL.\nSigning in with a new provider links it to the existing account with the same verified email",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
+                ],
+                "summary": "OAuth callback",
+                "parameters": [
+                    {
+                        "enum": [
+                            "google",
+                            "github",
+                            "apple",
+                            "discord",
+                            "gitlab"
+                        ],
+                        "type": "string",
+                        "description": "OAuth provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user's usage statistics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_users.UsageResponse"
+                            "$ref": "#/definitions/api_rest_auth.AuthResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "302": {
+                        "description": "Redirect to original URL with token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
@@ -2126,713 +2348,12817 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Get service health status",
+        "/api/v1/billing/checkout": {
+            "post": {
+                "description": "Create a Stripe Checkout session for the pay-as-you-go tier. Redirect the user to the returned URL; the tier changes once Stripe confirms the subscription.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "billing"
                 ],
-                "summary": "Health check",
+                "summary": "Start subscription checkout",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_rest_health.Response"
+                            "$ref": "#/definitions/api_rest_billing.SessionURLResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
                         }
                     }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "api_rest_admin.SetUseInTrainingRequest": {
-            "type": "object",
-            "properties": {
-                "use_in_training": {
-                    "type": "boolean"
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "api_rest_admin.StrudelAdminResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "description": {
+        "/api/v1/billing/events": {
+            "post": {
+                "description": "Receives Stripe subscription events (signed with the Stripe-Signature header) and updates user tiers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "billing"
+                ],
+                "summary": "Stripe webhook",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_billing.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/billing/portal": {
+            "post": {
+                "description": "Create a Stripe Billing Portal session to manage or cancel the subscription and view invoices",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "billing"
+                ],
+                "summary": "Open billing portal",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_billing.SessionURLResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/embed-tokens": {
+            "get": {
+                "description": "Get the authenticated user's active embed tokens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "embeds"
+                ],
+                "summary": "List embed tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_embeds.EmbedTokensListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/embed-tokens/{id}": {
+            "delete": {
+                "description": "Revoke an embed token. Embeds using it stop loading and can no longer open a connection.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "embeds"
+                ],
+                "summary": "Revoke embed token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Embed token ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_embeds.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/embed/{token}": {
+            "get": {
+                "description": "Get the read-only view an embed token grants: title, current code and whether the session is live. Never includes chat or participants.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "embeds"
+                ],
+                "summary": "Get embed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Embed token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_embeds.View"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events": {
+            "get": {
+                "description": "Browse scheduled and live performances, soonest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "List upcoming events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_events.EventsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Schedule a public performance in an active session you host. When it starts, the session becomes discoverable and everyone who RSVPed is notified through their webhooks (event.live).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Schedule event",
+                "parameters": [
+                    {
+                        "description": "Session, title and start time",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_events.CreateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_events.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/events/feed.ics": {
+            "get": {
+                "description": "iCalendar feed of upcoming performances for calendar apps to subscribe to. Pass user_id for the performances of one host. Cancelled events stay in the feed, marked cancelled, until they would have started.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Calendar feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events hosted by this user (UUID)",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}": {
+            "get": {
+                "description": "Get a scheduled performance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_events.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancel a scheduled performance you host",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Cancel event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_events.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/events/{id}/rsvp": {
+            "post": {
+                "description": "Get notified through your webhooks (event.live) when the performance starts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "RSVP to event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_events.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Stop being notified when the performance starts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Withdraw RSVP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_events.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/feed": {
+            "get": {
+                "description": "Public strudels published and forked, and performances scheduled, by the users you follow, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Get activity feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page's pagination.next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_follows.FeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/lessons": {
+            "get": {
+                "description": "List the guided lessons built from the teaching concepts, without their steps",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lessons"
+                ],
+                "summary": "List lessons",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_lessons.LessonsListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/lessons/{id}": {
+            "get": {
+                "description": "Get a lesson with its steps. Each step has an explanation, an example and the criteria code has to meet to finish it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lessons"
+                ],
+                "summary": "Get lesson",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lesson ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.Lesson"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notifications": {
+            "get": {
+                "description": "Get the authenticated user's notifications, newest first (kept for 90 days)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_notifications.NotificationsListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/notifications/preferences": {
+            "get": {
+                "description": "Get the authenticated user's notification settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_notifications.Preferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Set how often unread notifications are emailed as a digest (off, daily or weekly)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Update notification preferences",
+                "parameters": [
+                    {
+                        "description": "Notification settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_notifications.UpdatePreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_notifications.Preferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/notifications/read-all": {
+            "post": {
+                "description": "Mark all of the authenticated user's notifications as read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark all notifications as read",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_notifications.MarkAllReadResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/notifications/{id}/read": {
+            "post": {
+                "description": "Mark one of the authenticated user's notifications as read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark notification as read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_notifications.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/ping": {
+            "get": {
+                "description": "Simple ping endpoint",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Ping",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_health.PingResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/public/strudels": {
+            "get": {
+                "description": "Get publicly shared strudels from all users with pagination, search, and filtering",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "List public strudels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous response's next_cursor or prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "created_at, updated_at or title, with a leading - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Strudel fields to return (comma-separated), id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search in title and description",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Filter by user or auto-generated tags (comma-separated)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Filter by instruments (comma-separated)",
+                        "name": "instruments",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum complexity (0-10)",
+                        "name": "complexity_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum complexity (0-10)",
+                        "name": "complexity_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum estimated BPM",
+                        "name": "bpm_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum estimated BPM",
+                        "name": "bpm_max",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.StrudelsPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/public/strudels/tags": {
+            "get": {
+                "description": "Get all unique tags from public strudels",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "List public tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.TagsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/public/strudels/{id}": {
+            "get": {
+                "description": "Get a publicly shared strudel by its ID (for forking)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Get public strudel by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response, answered with 304 while it is current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/public/strudels/{id}/card": {
+            "get": {
+                "description": "Get the OpenGraph and Twitter card metadata of a public strudel, for the frontend to render into the strudel's page. The image shows the title, author and start of the code; it is generated in the background whenever these change, so image_url is null until the image of the current version is ready, and twitter_card is summary instead of summary_large_image.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Get strudel preview card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response, answered with 304 while it is current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_cards.Card"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/public/strudels/{id}/stats": {
+            "get": {
+                "description": "Get attribution stats for a public strudel (how many times it was used as RAG context)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Get strudel usage stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_attribution.StrudelStatsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/public/strudels/{id}/uses": {
+            "get": {
+                "description": "List the public strudels a public strudel inspired, as AI context or as a fork, with cursor pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "List strudel uses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous response's next_cursor or prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "created_at, with a leading - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Fields to return (comma-separated), id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.StrudelUsesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sample-packs": {
+            "get": {
+                "description": "Browse registered sample packs, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sample-packs"
+                ],
+                "summary": "List sample packs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Match name or description, or an exact sound name",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_samplepacks.SamplePacksListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register a sample pack by its strudel.json manifest. The manifest is fetched and validated, and its sounds are recognized when analyzing code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sample-packs"
+                ],
+                "summary": "Register sample pack",
+                "parameters": [
+                    {
+                        "description": "Sample pack name, manifest URL and license",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_samplepacks.CreateSamplePackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_samplepacks.SamplePack"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sample-packs/licenses": {
+            "get": {
+                "description": "Get the licenses a sample pack can be registered under",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sample-packs"
+                ],
+                "summary": "List sample pack licenses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_samplepacks.LicensesListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sample-packs/{id}": {
+            "get": {
+                "description": "Get a sample pack and the sounds it provides",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sample-packs"
+                ],
+                "summary": "Get sample pack",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sample pack ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_samplepacks.SamplePack"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a sample pack registered by the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sample-packs"
+                ],
+                "summary": "Delete sample pack",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sample pack ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_samplepacks.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions": {
+            "get": {
+                "description": "Get all sessions where user is host or participant",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List user's sessions",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Only return active sessions",
+                        "name": "active_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous response's next_cursor or prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-last_activity",
+                        "description": "last_activity, created_at or title, with a leading - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Session fields to return (comma-separated), id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.SessionsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new collaborative coding session (authenticated users only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Create collaboration session",
+                "parameters": [
+                    {
+                        "description": "Session data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.CreateSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.CreateSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/join": {
+            "post": {
+                "description": "Join a collaborative session using an invite token (authenticated or anonymous)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Join session with invite",
+                "parameters": [
+                    {
+                        "description": "Join request with invite token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.JoinSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.JoinSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired invite",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Invite was sent to an email address the user hasn't verified",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/last": {
+            "get": {
+                "description": "Returns the user's most recent active session where they are host, excluding sessions they're currently in",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get user's last active session for recovery",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.LiveSessionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/live": {
+            "get": {
+                "description": "Get discoverable active sessions plus user's own active sessions (if authenticated)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List live sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous response's next_cursor or prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-last_activity",
+                        "description": "last_activity, created_at or title, with a leading - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Session fields to return (comma-separated), id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.LiveSessionsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sessions/{id}": {
+            "get": {
+                "description": "Get session information including participants",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get session details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response, answered with 304 while it is current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.SessionResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update the code in a session (host or co-authors only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Update session code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Code update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.UpdateSessionCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.UpdateSessionCodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "End a collaborative session (host only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "End session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/analytics": {
+            "get": {
+                "description": "Activity of a session over time (host only): connected participants and listeners, code edits, agent requests, chat messages and seconds of playback per bucket. Activity is sampled once a minute and rolled up by the buffer flusher, so the latest minutes may be missing. At most 1000 buckets are returned, ending now.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get session analytics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "5m",
+                        "description": "Bucket size: 1m, 5m, 15m, 1h or 1d",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the series (RFC 3339), defaults to when the session was created",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.SessionAnalyticsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/audit": {
+            "get": {
+                "description": "Security-relevant actions taken in a session (ends, kicks, role changes, invites), newest first (host only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get session audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by action (e.g. participant.kick)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.AuditLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/chat-settings": {
+            "put": {
+                "description": "Set the chat slow mode of a session: each participant and spectator may send one message per slow_mode_seconds. 0 turns it off. Requires manage_participants, who aren't slowed down themselves. Connected clients are told with chat_settings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Update chat settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Chat settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.ChatSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.ChatSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/discoverable": {
+            "put": {
+                "description": "Toggle whether a session appears in the live sessions list (host only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Set session discoverability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Discoverability settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.SetDiscoverableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.SessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/embed-token": {
+            "post": {
+                "description": "Create a revocable token granting a read-only view of an active session (code and play state only). Host only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "embeds"
+                ],
+                "summary": "Create session embed token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_embeds.Token"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/end-live": {
+            "post": {
+                "description": "Ends the live portion of a session: kicks all non-host participants, revokes all invite tokens,\nsets discoverable to false. Host keeps access to the session and their code.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Soft-end a live session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.SoftEndSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/expiry-policy": {
+            "get": {
+                "description": "Get how the session is treated when it goes idle (host only). Sessions without their own idle timeout are ended after 30 minutes without activity.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get session expiry policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_sessions.ExpiryPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace how the session is treated when it goes idle (host only): end it after auto_end_idle_hours without activity instead of the server default, revoke its invites when it ends, and publish its final code as a public strudel of the host.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Set session expiry policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Expiry policy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.ExpiryPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_sessions.ExpiryPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/invite": {
+            "get": {
+                "description": "Get all invite tokens for a session (host only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List invite tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.InviteTokensListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Generate an invite link for joining the session (host only). With an email, the link is emailed and can only be used once, by a signed-in user with that address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Create invite token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Token settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.CreateInviteTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.InviteTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/invite/{token_id}": {
+            "delete": {
+                "description": "Revoke an invite token to prevent further use (host only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revoke invite token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token ID (UUID)",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/join-requests": {
+            "get": {
+                "description": "Get the open join requests of a session, oldest first (host only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List join requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_joinrequests.JoinRequestsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Ask the host to let you join an active session. The host is told live (join_request WebSocket message) and in-app, and has 10 minutes to answer. Knocking again while a request is open refreshes it. Once approved, connect to the session's WebSocket with your JWT.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Knock on a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name and note shown to the host",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_joinrequests.CreateJoinRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_joinrequests.Request"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/join-requests/{request_id}": {
+            "get": {
+                "description": "Get a join request, to poll for the host's answer. Visible to the requester and the host.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get join request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Join request ID (UUID)",
+                        "name": "request_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_joinrequests.Request"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Approve or deny an open join request (host only). An approval lets the user connect to the session with the granted role for 24 hours. The requester is notified in-app.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Answer join request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Join request ID (UUID)",
+                        "name": "request_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_joinrequests.AnswerJoinRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_joinrequests.Request"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Request was already answered or has expired",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/leave": {
+            "post": {
+                "description": "Leave a collaborative session",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Leave session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/lesson": {
+            "get": {
+                "description": "Get the progress of a session's lesson and its current step (participants only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lessons"
+                ],
+                "summary": "Get session lesson",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.Progress"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "no lesson in progress",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Start a guided lesson in a session you host, from its first step. Replaces the lesson in progress. While a lesson runs, the AI assistant teaches toward the current step.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lessons"
+                ],
+                "summary": "Start lesson in session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Lesson to start",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_lessons.StartLessonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.Progress"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "End the lesson of a session you host",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lessons"
+                ],
+                "summary": "Stop session lesson",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_lessons.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/lesson/check": {
+            "post": {
+                "description": "Check code against the current step of a session's lesson. The lesson moves on to the next step when the code lints without errors and meets the step's criteria; otherwise missing lists what is left to do.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lessons"
+                ],
+                "summary": "Check code against lesson step",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Code to check",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_lessons.CheckLessonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.CheckResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "no lesson in progress",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "lesson already completed",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/live-status": {
+            "get": {
+                "description": "Returns whether a session is currently live (has other participants or active invite tokens)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Check if session is live",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.IsLiveResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/messages": {
+            "get": {
+                "description": "Retrieve chat messages from a session (AI exchanges are only part of the session transcript)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get session chat messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Max messages to return (max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous response's next_cursor or prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "created_at, with a leading - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Message fields to return (comma-separated), id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.MessagesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/participants": {
+            "get": {
+                "description": "Get all participants in a session (authenticated and anonymous)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List session participants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Items per page (max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous response's next_cursor or prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "joined_at",
+                        "description": "joined_at or display_name, with a leading - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Participant fields to return (comma-separated), id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.ParticipantsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/participants/{participant_id}": {
+            "delete": {
+                "description": "Remove a participant from the session (host only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Remove participant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Participant ID (UUID)",
+                        "name": "participant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Change a participant's role to co-author or viewer, resetting their permissions to the role's preset. Requires manage_participants. Connected clients are updated immediately.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Update participant role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Participant ID (UUID)",
+                        "name": "participant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.UpdateRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/participants/{participant_id}/mute": {
+            "put": {
+                "description": "Stop a participant or spectator from chatting without removing them from the session. Requires manage_participants. Connected clients are told with chat_muted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Mute participant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Participant ID (UUID)",
+                        "name": "participant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.MuteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Let a muted participant or spectator chat again. Requires manage_participants.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Unmute participant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Participant ID (UUID)",
+                        "name": "participant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.MuteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/participants/{participant_id}/permissions": {
+            "put": {
+                "description": "Replace a participant's permissions, overriding their role preset. Requires manage_participants; only the host can grant or revoke manage_participants. Connected clients are updated immediately.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Update participant permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Participant ID (UUID)",
+                        "name": "participant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Permissions",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.UpdatePermissionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.UpdatePermissionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Remove a participant's permission override so they get their role preset again. Requires manage_participants.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Reset participant permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Participant ID (UUID)",
+                        "name": "participant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.UpdatePermissionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/paste-lock": {
+            "get": {
+                "description": "Whether the AI assistant is blocked in a session by a paste lock, why, and for how long. Includes the protected work the paste matched, if it was fingerprinted, and your latest appeal (participants only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get paste lock status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_pastelocks.PasteLockStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/paste-lock/appeal": {
+            "post": {
+                "description": "Appeal the paste lock of a session for admin review. With claim_ownership, the lock is lifted right away when the pasted code is yours: it matched your own protected work, or you published a strudel with it. Connected clients are notified when the lock is lifted (participants only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Appeal paste lock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Appeal",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_pastelocks.AppealPasteLockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_pastelocks.AppealPasteLockResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "session isn't paste-locked",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "an appeal is already waiting for review",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/restore/{snapshot_id}": {
+            "post": {
+                "description": "Replace the session code with a snapshot (host only). The replaced code is kept as a pre_restore snapshot, and connected clients receive the restored code as a code_update with source \"restore\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Restore session snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot ID (UUID)",
+                        "name": "snapshot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.UpdateSessionCodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/snapshots": {
+            "get": {
+                "description": "List the restorable snapshots of a session's code, newest first (host or co-authors only). Snapshots are taken every 10 minutes of editing or every 500 edits, and before a restore.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List session snapshots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_collaboration.SnapshotsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/sessions/{id}/transcript": {
+            "get": {
+                "description": "Render the timeline of a session, its chat messages, AI prompts and responses, and code checkpoints, as a Markdown or HTML document with highlighted code. Available to the host and anyone who took part in the session. Checkpoints are taken at most every snapshot interval and unchanged code is left out. Transcripts stop after 5000 entries.",
+                "produces": [
+                    "text/markdown",
+                    "text/html"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Export session transcript",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "md",
+                        "description": "md or html",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Ask browsers to save the document instead of showing it",
+                        "name": "download",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transcript document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/stats/public": {
+            "get": {
+                "description": "Anonymized platform-wide numbers for the public stats page: active sessions, public strudels and those published in the last 7 days, the most used tags and the total number of AI generations. Refreshed every 5 minutes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get public platform stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_stats.PublicStats"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/strudel/validate": {
+            "post": {
+                "description": "Lint Strudel code for unbalanced delimiters, empty patterns and unknown functions or sounds",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Validate strudel code",
+                "parameters": [
+                    {
+                        "description": "Code to validate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.ValidateCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.ValidateCodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/strudels": {
+            "get": {
+                "description": "Get strudels owned by the authenticated user with pagination, search, and filtering",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "List user's strudels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous response's next_cursor or prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "created_at, updated_at or title, with a leading - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Strudel fields to return (comma-separated), id is always included",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search in title and description",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Filter by user or auto-generated tags (comma-separated)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Filter by instruments (comma-separated)",
+                        "name": "instruments",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum complexity (0-10)",
+                        "name": "complexity_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum complexity (0-10)",
+                        "name": "complexity_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum estimated BPM",
+                        "name": "bpm_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum estimated BPM",
+                        "name": "bpm_max",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.StrudelsPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Save a new Strudel pattern with code, title, and metadata. A fork gets at least the most restrictive CC signal of the strudels it was forked from",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Create strudel",
+                "parameters": [
+                    {
+                        "description": "Strudel data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CreateStrudelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/bulk": {
+            "post": {
+                "description": "Apply one action to up to 100 of your strudels: delete (move to the trash), add_tags or remove_tags (tags), set_visibility (is_public) or set_cc_signal (cc_signal). The action is applied to every strudel or none: when any item is missing or its change isn't allowed, nothing is changed, the response is 422 and the other items are reported as skipped. Publishing is screened like single updates, but strudels held for review have to be published on their own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Bulk strudel operation",
+                "parameters": [
+                    {
+                        "description": "Action and strudel IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.BulkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.BulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.BulkResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/import": {
+            "post": {
+                "description": "Create a private draft from an export bundle (a strudel as returned by GET /api/v1/strudels/{id}) or a strudel.cc share link (https://strudel.cc/#\u003chash\u003e or the hash alone). Short links (https://strudel.cc/?\u003cid\u003e) can't be imported. The code is analyzed before responding, so auto tags are included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Import strudel",
+                "parameters": [
+                    {
+                        "description": "Bundle or share link",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.ImportStrudelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/search": {
+            "get": {
+                "description": "Full-text search over title, description and code of public strudels, with filters and cursor pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Search public strudels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text (supports quotes, OR and -exclude)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Filter by user or auto-generated tags (comma-separated)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum complexity (0-10)",
+                        "name": "complexity_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum complexity (0-10)",
+                        "name": "complexity_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Filter by CC signals (comma-separated, e.g. cc-cr,cc-op)",
+                        "name": "cc_signal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: relevance, recent or popular (default relevance with q, otherwise recent)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page's next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.StrudelSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/strudels/tags": {
+            "get": {
+                "description": "Get all unique tags from the authenticated user's strudels",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "List user's tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.TagsListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/trash": {
+            "get": {
+                "description": "Get the authenticated user's deleted strudels, most recently deleted first. Each is purged 30 days after deleted_at",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "List trashed strudels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.StrudelsListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/trending": {
+            "get": {
+                "description": "Public strudels ranked by engagement over the last 7 days (a fork counts 5, a like 3, a play 1), decaying with age. Rankings and counts are refreshed every 5 minutes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "engagement"
+                ],
+                "summary": "List trending strudels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_engagement.TrendingResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/strudels/{id}": {
+            "get": {
+                "description": "Get a specific strudel by ID (owner or public)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Get strudel by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response, answered with 304 while it is current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.StrudelDetailResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a strudel's properties (must be owner). A fork can't be given, or published under, a CC signal more permissive than the most restrictive signal of the strudels it was forked from. Send the updated_at you last loaded, in the body or as If-Match; the update is rejected with 409 if the strudel was saved since, and with 428 if neither is sent. If-Match: * saves without the check",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Update strudel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the strudel as last loaded, or * to save without the check. Required unless updated_at is in the body",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.UpdateStrudelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.StrudelConflictResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Neither updated_at nor If-Match was sent",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Move a strudel to the trash (must be owner). Trashed strudels can be restored for 30 days before they are permanently deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Delete strudel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/{id}/ancestors": {
+            "get": {
+                "description": "Get the chain of strudels this one was forked from, from the direct parent up to the original, with CC signals and authors. Private strudels of other users are redacted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Get strudel fork ancestry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.AncestorsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/strudels/{id}/cc": {
+            "get": {
+                "description": "Get a strudel's CC signal in machine-readable form, so AI crawlers and tools can honor its AI usage preferences. The effective signal is the most restrictive one of the strudel and the strudels it was forked from; without a signal, AI use is disallowed. Strudel responses link here with a Link header (rel=\"cc-signal\"), and carry X-Robots-Tag: noai when AI use is disallowed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Get strudel CC signal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.CCSignalResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/strudels/{id}/embed-token": {
+            "post": {
+                "description": "Create a revocable token granting a read-only view of a strudel, public or not. Owner only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "embeds"
+                ],
+                "summary": "Create strudel embed token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_embeds.Token"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/{id}/forks": {
+            "get": {
+                "description": "Get the tree of strudels forked from this one, with CC signals and authors at each node. Private strudels of other users are redacted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Get strudel fork tree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.ForksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/strudels/{id}/like": {
+            "post": {
+                "description": "Like a public strudel. Liking it again changes nothing",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "engagement"
+                ],
+                "summary": "Like strudel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_engagement.LikeStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Take back your like of a public strudel",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "engagement"
+                ],
+                "summary": "Unlike strudel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_engagement.LikeStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/{id}/play": {
+            "post": {
+                "description": "Count a play of a public strudel. Plays by the same user (or address, when not signed in) within 30 minutes count once",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "engagement"
+                ],
+                "summary": "Record play",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_engagement.PlayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/strudels/{id}/render": {
+            "get": {
+                "description": "Get the status of the strudel's latest audio render (must be owner). Failed renders carry the reason in error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Get strudel render",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_renders.Render"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "never rendered",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Queue a render of the strudel's current code to audio (must be owner). A headless renderer plays the code for the requested duration and the file is stored; poll GET /strudels/{id}/render for its status. Once ready, its URL is the strudel's preview_url, replacing the previous render. Renders the renderer rejects fail without retrying.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Render strudel to audio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Render options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_renders.RenderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_renders.Render"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "a render is already in progress",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "rendering not configured",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/{id}/restore": {
+            "post": {
+                "description": "Move a trashed strudel back to the user's library (must be owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Restore strudel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/{id}/revert/{version}": {
+            "post": {
+                "description": "Restore the code of an earlier version (must be owner). The restored code is saved as a new version",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Revert strudel to a version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/{id}/similar": {
+            "get": {
+                "description": "Get public strudels with the most similar code and description, excluding the strudel's own fork lineage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Find similar strudels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of results (max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.SimilarStrudelsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/strudels/{id}/versions": {
+            "get": {
+                "description": "Get the saved versions of a strudel's code, newest first (must be owner). Saves within a minute of the latest version replace it, at most 100 versions are kept, and versions older than 30 days are thinned to one per day",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "List strudel versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.VersionsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/strudels/{id}/versions/{version}/diff": {
+            "get": {
+                "description": "Get a unified diff of a strudel's code from another version to the given one (must be owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "strudels"
+                ],
+                "summary": "Diff strudel versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Strudel ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to diff from (defaults to the previous version)",
+                        "name": "against",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_strudels.VersionDiffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/uploads": {
+            "post": {
+                "description": "Create a presigned URL to upload a file to. PUT the file to upload_url with the given Content-Type before expires_at, then complete the upload. Avatars may be PNG, JPEG or GIF up to 5 MB and 4096x4096 pixels.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "Start upload",
+                "parameters": [
+                    {
+                        "description": "What to upload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_uploads.CreateUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_assets.Upload"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "too many uploads in progress",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "object storage not configured",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/uploads/{id}/complete": {
+            "post": {
+                "description": "Validate and process an uploaded file. Avatars are cropped to a centered square, scaled to 256x256 and become your avatar.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "Complete upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_assets.Asset"
+                        }
+                    },
+                    "400": {
+                        "description": "nothing uploaded, too large or not a valid image",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "already completed",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "object storage not configured",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/ai-features-enabled": {
+            "put": {
+                "description": "Toggle whether AI features (prompt bar, code generation) are enabled for the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user's AI features setting",
+                "parameters": [
+                    {
+                        "description": "AI features enabled data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_users.AIFeaturesEnabledRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_users.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/display-name": {
+            "put": {
+                "description": "Update the authenticated user's display name (shown in sessions and jams)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user's display name",
+                "parameters": [
+                    {
+                        "description": "Display name data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_users.UpdateDisplayNameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_users.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/email-preferences": {
+            "get": {
+                "description": "Get which emails the authenticated user receives. How often the notification digest is sent is set in the notification preferences",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user's email preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_mailer.Preferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Choose whether the authenticated user receives session invitations by email, or no email at all",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user's email preferences",
+                "parameters": [
+                    {
+                        "description": "Email preferences",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_users.UpdateEmailPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_mailer.Preferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/me/avatar": {
+            "delete": {
+                "description": "Remove your uploaded avatar. The file is deleted from storage shortly after.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "uploads"
+                ],
+                "summary": "Remove uploaded avatar",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_uploads.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "no uploaded avatar",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/me/usage": {
+            "get": {
+                "description": "Returns the authenticated user's AI generations, tokens and estimated cost for a calendar month (UTC) per provider and model. The current month also includes the remaining quota, including the monthly spend limit",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user's monthly AI usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month as YYYY-MM (default: current month)",
+                        "name": "month",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_users.MonthlyUsageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/training-consent": {
+            "put": {
+                "description": "Toggle whether the user's public strudels can be used for AI training",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user's training consent",
+                "parameters": [
+                    {
+                        "description": "Consent data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_users.TrainingConsentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_users.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/usage": {
+            "get": {
+                "description": "Returns usage statistics for the authenticated user including today's count, daily limit, and usage history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user's usage statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_users.UsageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/{id}/follow": {
+            "post": {
+                "description": "Follow a user to see their publishes, forks and scheduled performances in your feed. Following them again changes nothing",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Follow user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_follows.FollowStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Stop following a user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Unfollow user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_follows.FollowStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/{id}/followers": {
+            "get": {
+                "description": "List the users following a user, most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "List followers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page's pagination.next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_follows.FollowsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{id}/following": {
+            "get": {
+                "description": "List the users a user follows, most recently followed first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "List followed users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page's pagination.next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_follows.FollowsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "Get the authenticated user's webhooks",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_webhooks.WebhooksListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Register an https endpoint for events. The response includes the signing secret, which is not shown again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook URL and events",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_webhooks.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_webhooks.WebhookWithSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/webhooks/events": {
+            "get": {
+                "description": "Get the events a webhook can subscribe to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook events",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_webhooks.EventsListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "get": {
+                "description": "Get a webhook owned by the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_webhooks.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a webhook and its delivery log",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_webhooks.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Change the URL, events or active state of a webhook",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_webhooks.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_webhooks.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Get the delivery log of a webhook, newest first (kept for 30 days)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Items per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_webhooks.DeliveriesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/webhooks/{id}/ping": {
+            "post": {
+                "description": "Queue a ping event to the webhook. The result appears in the delivery log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Test webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_webhooks.Delivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/webhooks/{id}/rotate-secret": {
+            "post": {
+                "description": "Replace the signing secret of a webhook. The old secret stops working immediately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Rotate webhook secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_webhooks.WebhookWithSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket for real-time collaboration. Without session_id a new anonymous session is created. Frames are JSON text frames, or msgpack binary frames when the client offers the msgpack subprotocol. Every frame is a Message whose payload is the MessageCatalog field named by its type. See docs/websocket/API.md",
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Connect to a live session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session to join; a new anonymous session is created without it",
+                        "name": "session_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session whose code a new session starts from",
+                        "name": "previous_session_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "One-time ticket of a signed-in user, from POST /api/v1/ws/ticket",
+                        "name": "ticket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Access token of a signed-in user. Ends up in logs and browser history, use ticket instead",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invite token to join with",
+                        "name": "invite",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Display name of an anonymous user (max 100 characters)",
+                        "name": "display_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Hold back session_state until the client sends resume",
+                        "name": "resume",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Join a discoverable session as a read-only listener",
+                        "name": "audience",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Join a discoverable session as a listener who can chat (requires token)",
+                        "name": "spectator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Embed token, joins its session as a read-only embed",
+                        "name": "embed",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.Message"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "payload": {
+                                            "$ref": "#/definitions/api_websocket.MessageCatalog"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/ws/ticket": {
+            "post": {
+                "description": "Issue a signed ticket that signs the next WebSocket connection in as the current user, passed as the ticket query parameter of /api/v1/ws in place of the access token. A ticket expires after 30 seconds and works for one connection",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Create a WebSocket ticket",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api_websocket.TicketResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Get service health status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_health.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports that the process is up and serving requests. Doesn't check dependencies, so a database outage doesn't get instances restarted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api_rest_health.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Metrics in the Prometheus text format. Requires METRICS_TOKEN as a bearer token when the server sets one",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Prometheus metrics",
+                "responses": {
+                    "200": {
+                        "description": "Prometheus text format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Reports whether the instance should receive traffic, with the status of Postgres, Redis and the LLM providers. Results are cached for a few seconds. Returns 503 when Postgres or Redis is down and while the instance drains or shuts down; an unreachable LLM provider only marks the instance degraded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_health.Report"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api_rest_admin.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_audit.Entry"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                }
+            }
+        },
+        "api_rest_admin.BanUserRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "api_rest_admin.BanUserResponse": {
+            "type": "object",
+            "properties": {
+                "connections_disconnected": {
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/api_rest_admin.UserAdminResponse"
+                }
+            }
+        },
+        "api_rest_admin.DeadLettersResponse": {
+            "type": "object",
+            "properties": {
+                "dead_letters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_jobs.DeadLetter"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                }
+            }
+        },
+        "api_rest_admin.FaultRequest": {
+            "type": "object",
+            "properties": {
+                "drop_rate": {
+                    "description": "0 to 1, calls failing as if the connection dropped",
+                    "type": "number"
+                },
+                "error_rate": {
+                    "description": "0 to 1, calls failing with an error",
+                    "type": "number"
+                },
+                "key_prefix": {
+                    "description": "redis only: limits the fault to keys starting with this",
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "description": "added to every call",
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_admin.FaultResponse": {
+            "type": "object",
+            "properties": {
+                "drop_rate": {
+                    "type": "number"
+                },
+                "error_rate": {
+                    "type": "number"
+                },
+                "key_prefix": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_admin.FaultsResponse": {
+            "type": "object",
+            "properties": {
+                "faults": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_admin.FaultResponse"
+                    }
+                }
+            }
+        },
+        "api_rest_admin.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_admin.ModerationQueueResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_moderation.QueueItem"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                }
+            }
+        },
+        "api_rest_admin.ModerationRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "api_rest_admin.PasteLockAppealsResponse": {
+            "type": "object",
+            "properties": {
+                "appeals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_pasteappeals.Appeal"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                }
+            }
+        },
+        "api_rest_admin.PasteLockResponse": {
+            "type": "object",
+            "properties": {
+                "baseline_length": {
+                    "type": "integer"
+                },
+                "decisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_ccsignals.Decision"
+                    }
+                },
+                "expires_in_seconds": {
+                    "type": "integer"
+                },
+                "locked": {
+                    "type": "boolean"
+                },
+                "locked_at": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_admin.SessionAdminResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "host_user_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_discoverable": {
+                    "type": "boolean"
+                },
+                "last_activity": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_admin.SessionsListResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_admin.SessionAdminResponse"
+                    }
+                }
+            }
+        },
+        "api_rest_admin.SetUseInTrainingRequest": {
+            "type": "object",
+            "properties": {
+                "use_in_training": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_admin.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "connections": {
+                    "description": "connections held by the instance that served the request",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.HubStats"
+                        }
+                    ]
+                }
+            }
+        },
+        "api_rest_admin.StrudelAdminResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "use_in_training": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_admin.UserAdminResponse": {
+            "type": "object",
+            "properties": {
+                "ban_reason": {
+                    "type": "string"
+                },
+                "banned_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_admin": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tier": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_agent.DocReference": {
+            "type": "object",
+            "properties": {
+                "page_name": {
+                    "type": "string"
+                },
+                "section_title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_agent.GenerateRequest": {
+            "type": "object",
+            "required": [
+                "user_query"
+            ],
+            "properties": {
+                "conversation_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_agent.Message"
+                    }
+                },
+                "editor_state": {
+                    "type": "string"
+                },
+                "forked_from_id": {
+                    "description": "optional: for blocking AI on restricted forks",
+                    "type": "string"
+                },
+                "model": {
+                    "description": "must be allowed for the provider, defaults to its default model",
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "see GET /agent/providers, defaults to the configured provider",
+                    "type": "string"
+                },
+                "provider_api_key": {
+                    "description": "BYOK key",
+                    "type": "string"
+                },
+                "session_id": {
+                    "description": "optional: for paste lock validation",
+                    "type": "string"
+                },
+                "strudel_id": {
+                    "description": "optional: for persisting conversation",
+                    "type": "string"
+                },
+                "user_query": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_agent.GenerateResponse": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "description": "served from the response cache",
+                    "type": "boolean"
+                },
+                "clarifying_questions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "degraded": {
+                    "description": "generated by the fallback provider during an outage",
+                    "type": "boolean"
+                },
+                "doc_references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_agent.DocReference"
+                    }
+                },
+                "docs_retrieved": {
+                    "type": "integer"
+                },
+                "examples_retrieved": {
+                    "type": "integer"
+                },
+                "is_actionable": {
+                    "type": "boolean"
+                },
+                "is_code_response": {
+                    "type": "boolean"
+                },
+                "lesson": {
+                    "description": "the editor's code checked against the session's lesson step",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.CheckResult"
+                        }
+                    ]
+                },
+                "model": {
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "$ref": "#/definitions/api_rest_agent.RateLimitInfo"
+                },
+                "repair_iterations": {
+                    "description": "times the code was sent back to the model to fix lint findings",
+                    "type": "integer"
+                },
+                "strudel_references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_agent.StrudelReference"
+                    }
+                }
+            }
+        },
+        "api_rest_agent.Message": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_agent.ProvidersResponse": {
+            "type": "object",
+            "properties": {
+                "default_provider": {
+                    "type": "string"
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_llm.ProviderSpec"
+                    }
+                }
+            }
+        },
+        "api_rest_agent.RateLimitInfo": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "monthly": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.PeriodUsage"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "reset_at": {
+                    "type": "string"
+                },
+                "spend": {
+                    "description": "estimated model cost this month",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.SpendUsage"
+                        }
+                    ]
+                },
+                "tier": {
+                    "description": "\"anonymous\", \"free\", \"payg\" or \"byok\"",
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_agent.StreamEvent": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "type": "boolean"
+                },
+                "content": {
+                    "description": "text chunk for type=\"chunk\"",
+                    "type": "string"
+                },
+                "degraded": {
+                    "description": "generated by the fallback provider",
+                    "type": "boolean"
+                },
+                "doc_references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.DocReference"
+                    }
+                },
+                "docs_retrieved": {
+                    "description": "retrieval metadata sent with type=\"refs\" and type=\"done\"",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "error message for type=\"error\"",
+                    "type": "string"
+                },
+                "examples_retrieved": {
+                    "type": "integer"
+                },
+                "input_tokens": {
+                    "type": "integer"
+                },
+                "is_code_response": {
+                    "type": "boolean"
+                },
+                "lesson": {
+                    "description": "sent with type=\"done\" during a lesson",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.CheckResult"
+                        }
+                    ]
+                },
+                "model": {
+                    "description": "final metadata sent with type=\"done\"",
+                    "type": "string"
+                },
+                "output_tokens": {
+                    "type": "integer"
+                },
+                "rate_limit": {
+                    "description": "sent with type=\"done\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/api_rest_agent.RateLimitInfo"
+                        }
+                    ]
+                },
+                "strudel_references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.StrudelReference"
+                    }
+                },
+                "type": {
+                    "description": "\"chunk\", \"refs\", \"done\", \"error\"",
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_agent.StrudelReference": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_auth.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "access token lifetime in seconds",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_users.User"
+                }
+            }
+        },
+        "api_rest_auth.DeviceAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "device_code": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "device code lifetime in seconds",
+                    "type": "integer"
+                },
+                "interval": {
+                    "description": "minimum seconds between polls",
+                    "type": "integer"
+                },
+                "user_code": {
+                    "type": "string"
+                },
+                "verification_uri": {
+                    "type": "string"
+                },
+                "verification_uri_complete": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_auth.DeviceDecisionRequest": {
+            "type": "object",
+            "required": [
+                "user_code"
+            ],
+            "properties": {
+                "user_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "api_rest_auth.DeviceTokenRequest": {
+            "type": "object",
+            "required": [
+                "device_code"
+            ],
+            "properties": {
+                "device_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_auth.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_auth.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_auth.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_auth.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "access token lifetime in seconds",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_auth.UpdateProfileRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "api_rest_auth.UserResponse": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_users.User"
+                }
+            }
+        },
+        "api_rest_billing.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_billing.SessionURLResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_collaboration.AnalyticsTotals": {
+            "type": "object",
+            "properties": {
+                "agent_requests": {
+                    "type": "integer"
+                },
+                "chat_messages": {
+                    "type": "integer"
+                },
+                "code_edits": {
+                    "type": "integer"
+                },
+                "peak_listeners": {
+                    "type": "integer"
+                },
+                "peak_participants": {
+                    "type": "integer"
+                },
+                "playback_seconds": {
+                    "type": "number"
+                }
+            }
+        },
+        "api_rest_collaboration.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_audit.Entry"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                }
+            }
+        },
+        "api_rest_collaboration.ChatSettingsRequest": {
+            "type": "object",
+            "required": [
+                "slow_mode_seconds"
+            ],
+            "properties": {
+                "slow_mode_seconds": {
+                    "description": "0 turns slow mode off",
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 0
+                }
+            }
+        },
+        "api_rest_collaboration.ChatSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "slow_mode_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_collaboration.CreateInviteTokenRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "description": "emails a single-use invite only this address can accept",
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "co-author",
+                        "viewer"
+                    ]
+                }
+            }
+        },
+        "api_rest_collaboration.CreateSessionRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "code": {
+                    "description": "1MB limit",
+                    "type": "string",
+                    "maxLength": 1048576
+                },
+                "is_discoverable": {
+                    "description": "optional, defaults to false",
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "api_rest_collaboration.CreateSessionResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "host_user_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_discoverable": {
+                    "type": "boolean"
+                },
+                "last_activity": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_collaboration.ExpiryPolicyRequest": {
+            "type": "object",
+            "properties": {
+                "auto_end_idle_hours": {
+                    "description": "null uses the server default",
+                    "type": "integer",
+                    "maximum": 168,
+                    "minimum": 1
+                },
+                "publish_on_end": {
+                    "type": "boolean"
+                },
+                "revoke_invites_on_end": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_collaboration.InviteTokenResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_sent": {
+                    "description": "set when the invite was created; false if the invitee opted out or delivery failed",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "uses_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_collaboration.InviteTokensListResponse": {
+            "type": "object",
+            "properties": {
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_collaboration.InviteTokenResponse"
+                    }
+                }
+            }
+        },
+        "api_rest_collaboration.IsLiveResponse": {
+            "type": "object",
+            "properties": {
+                "has_active_invite_tokens": {
+                    "type": "boolean"
+                },
+                "is_discoverable": {
+                    "type": "boolean"
+                },
+                "is_live": {
+                    "type": "boolean"
+                },
+                "participant_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_collaboration.JoinSessionRequest": {
+            "type": "object",
+            "required": [
+                "invite_token"
+            ],
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "invite_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_collaboration.JoinSessionResponse": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_collaboration.LiveSessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_discoverable": {
+                    "type": "boolean"
+                },
+                "is_member": {
+                    "type": "boolean"
+                },
+                "last_activity": {
+                    "type": "string"
+                },
+                "participant_count": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_collaboration.LiveSessionsListResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_collaboration.LiveSessionResponse"
+                    }
+                }
+            }
+        },
+        "api_rest_collaboration.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_collaboration.MessagesResponse": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_sessions.Message"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta"
+                }
+            }
+        },
+        "api_rest_collaboration.MuteResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "muted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_collaboration.ParticipantResponse": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "joined_at": {
+                    "type": "string"
+                },
+                "left_at": {
+                    "type": "string"
+                },
+                "muted": {
+                    "type": "boolean"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_collaboration.ParticipantsListResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta"
+                },
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_collaboration.ParticipantResponse"
+                    }
+                }
+            }
+        },
+        "api_rest_collaboration.SessionAnalyticsResponse": {
+            "type": "object",
+            "properties": {
+                "bucket_seconds": {
+                    "type": "integer"
+                },
+                "series": {
+                    "description": "buckets without activity are left out",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_sessions.ActivitySample"
+                    }
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "since": {
+                    "type": "string"
+                },
+                "totals": {
+                    "$ref": "#/definitions/api_rest_collaboration.AnalyticsTotals"
+                }
+            }
+        },
+        "api_rest_collaboration.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "host_user_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_discoverable": {
+                    "type": "boolean"
+                },
+                "last_activity": {
+                    "type": "string"
+                },
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_collaboration.ParticipantResponse"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_collaboration.SessionsListResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_collaboration.SessionResponse"
+                    }
+                }
+            }
+        },
+        "api_rest_collaboration.SetDiscoverableRequest": {
+            "type": "object",
+            "properties": {
+                "is_discoverable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_collaboration.SnapshotsListResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                },
+                "snapshots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_sessions.Snapshot"
+                    }
+                }
+            }
+        },
+        "api_rest_collaboration.SoftEndSessionResponse": {
+            "type": "object",
+            "properties": {
+                "invites_revoked": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "participants_kicked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_collaboration.UpdatePermissionsRequest": {
+            "type": "object",
+            "required": [
+                "permissions"
+            ],
+            "properties": {
+                "permissions": {
+                    "description": "empty to allow nothing",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api_rest_collaboration.UpdatePermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "custom": {
+                    "description": "false when the role preset applies",
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api_rest_collaboration.UpdateRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "co-author",
+                        "viewer"
+                    ]
+                }
+            }
+        },
+        "api_rest_collaboration.UpdateRoleResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_collaboration.UpdateSessionCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "1MB limit",
+                    "type": "string",
+                    "maxLength": 1048576
+                }
+            }
+        },
+        "api_rest_collaboration.UpdateSessionCodeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_embeds.EmbedTokensListResponse": {
+            "type": "object",
+            "properties": {
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_embeds.Token"
+                    }
+                }
+            }
+        },
+        "api_rest_embeds.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_engagement.PlayResponse": {
+            "type": "object",
+            "properties": {
+                "counted": {
+                    "description": "false when the same listener played it in the last 30 minutes",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_engagement.TrendingResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "strudels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_engagement.TrendingStrudel"
+                    }
+                }
+            }
+        },
+        "api_rest_events.CreateEventRequest": {
+            "type": "object",
+            "required": [
+                "session_id",
+                "starts_at",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 1
+                }
+            }
+        },
+        "api_rest_events.EventsListResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_events.Event"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                }
+            }
+        },
+        "api_rest_events.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_follows.FeedResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_follows.FeedItem"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta"
+                }
+            }
+        },
+        "api_rest_follows.FollowsResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_follows.Follow"
+                    }
+                }
+            }
+        },
+        "api_rest_health.PingResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_health.Response": {
+            "type": "object",
+            "properties": {
+                "service": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_joinrequests.AnswerJoinRequestRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "approve",
+                        "deny"
+                    ]
+                },
+                "role": {
+                    "description": "granted on approval, defaults to viewer",
+                    "type": "string",
+                    "enum": [
+                        "co-author",
+                        "viewer"
+                    ]
+                }
+            }
+        },
+        "api_rest_joinrequests.CreateJoinRequestRequest": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "description": "defaults to the user's name",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "message": {
+                    "description": "shown to the host",
+                    "type": "string",
+                    "maxLength": 280
+                }
+            }
+        },
+        "api_rest_joinrequests.JoinRequestsListResponse": {
+            "type": "object",
+            "properties": {
+                "requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_joinrequests.Request"
+                    }
+                }
+            }
+        },
+        "api_rest_lessons.CheckLessonRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 100000
+                }
+            }
+        },
+        "api_rest_lessons.LessonsListResponse": {
+            "type": "object",
+            "properties": {
+                "lessons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.LessonSummary"
+                    }
+                }
+            }
+        },
+        "api_rest_lessons.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_lessons.StartLessonRequest": {
+            "type": "object",
+            "required": [
+                "lesson_id"
+            ],
+            "properties": {
+                "lesson_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_notifications.MarkAllReadResponse": {
+            "type": "object",
+            "properties": {
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_notifications.NotificationsListResponse": {
+            "type": "object",
+            "properties": {
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_notifications.Notification"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                },
+                "unread_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_notifications.UpdatePreferencesRequest": {
+            "type": "object",
+            "required": [
+                "email_digest"
+            ],
+            "properties": {
+                "email_digest": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_pastelocks.AppealPasteLockRequest": {
+            "type": "object",
+            "properties": {
+                "claim_ownership": {
+                    "description": "the pasted code is yours, verified against your published strudels",
+                    "type": "boolean"
+                },
+                "message": {
+                    "description": "shown to the reviewer",
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "api_rest_pastelocks.AppealPasteLockResponse": {
+            "type": "object",
+            "properties": {
+                "appeal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_pasteappeals.Appeal"
+                },
+                "unlocked": {
+                    "description": "ownership was verified and the lock lifted",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_pastelocks.MatchedWork": {
+            "type": "object",
+            "properties": {
+                "cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_ccsignals.CCSignal"
+                },
+                "coverage": {
+                    "type": "number"
+                },
+                "id": {
+                    "description": "fingerprint matches only",
+                    "type": "string"
+                },
+                "lines": {
+                    "description": "when a section of the work was pasted: its lines and their share of the work",
+                    "type": "string"
+                },
+                "yours": {
+                    "description": "appeal with claim_ownership to lift the lock",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_pastelocks.PasteLockStatusResponse": {
+            "type": "object",
+            "properties": {
+                "appeal": {
+                    "description": "your latest appeal for this session",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_pasteappeals.Appeal"
+                        }
+                    ]
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "expires_in_seconds": {
+                    "description": "the lock lapses after this long, every edit that isn't significant enough to unlock restarts it",
+                    "type": "integer"
+                },
+                "locked": {
+                    "type": "boolean"
+                },
+                "locked_at": {
+                    "type": "string"
+                },
+                "matched_work": {
+                    "$ref": "#/definitions/api_rest_pastelocks.MatchedWork"
+                },
+                "reason": {
+                    "description": "why paste detection locked the session",
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_renders.RenderRequest": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "description": "defaults to 30",
+                    "type": "integer",
+                    "maximum": 60,
+                    "minimum": 5
+                }
+            }
+        },
+        "api_rest_samplepacks.CreateSamplePackRequest": {
+            "type": "object",
+            "required": [
+                "license",
+                "manifest_url",
+                "name"
+            ],
+            "properties": {
+                "base_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "license": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "manifest_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "api_rest_samplepacks.LicensesListResponse": {
+            "type": "object",
+            "properties": {
+                "licenses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api_rest_samplepacks.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_samplepacks.SamplePacksListResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                },
+                "sample_packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_samplepacks.SamplePack"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.AncestorsResponse": {
+            "type": "object",
+            "properties": {
+                "ancestors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.LineageNode"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_strudels.BulkResponse": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "false when any item failed and nothing was changed",
+                    "type": "boolean"
+                },
+                "error": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.Code"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.BulkResult"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.CCSignalResponse": {
+            "type": "object",
+            "properties": {
+                "ai_use": {
+                    "type": "string",
+                    "enum": [
+                        "allowed",
+                        "disallowed"
+                    ]
+                },
+                "cc_signal": {
+                    "description": "the strudel's own signal",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                        }
+                    ]
+                },
+                "effective_cc_signal": {
+                    "description": "what applies, including the fork ancestry",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                        }
+                    ]
+                },
+                "license": {
+                    "type": "string"
+                },
+                "license_url": {
+                    "type": "string"
+                },
+                "requirements": {
+                    "description": "conditions on AI use, e.g. credit",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "strudel_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_strudels.ConversationMessageDTO": {
+            "type": "object",
+            "properties": {
+                "clarifying_questions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "doc_references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_strudels.DocReferenceDTO"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_actionable": {
+                    "type": "boolean"
+                },
+                "is_code_response": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "strudel_references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_strudels.StrudelReferenceDTO"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.DocReferenceDTO": {
+            "type": "object",
+            "properties": {
+                "page_name": {
+                    "type": "string"
+                },
+                "section_title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_strudels.ForksResponse": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.ForkCounts"
+                },
+                "forks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.LineageNode"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.ImportStrudelRequest": {
+            "type": "object",
+            "properties": {
+                "bundle": {
+                    "$ref": "#/definitions/api_rest_strudels.StrudelBundle"
+                },
+                "title": {
+                    "description": "overrides the imported title",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "url": {
+                    "description": "share link or its code hash",
+                    "type": "string",
+                    "maxLength": 2097152
+                }
+            }
+        },
+        "api_rest_strudels.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_strudels.SimilarStrudelDTO": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "similarity": {
+                    "description": "cosine similarity, 1 is identical",
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_strudels.SimilarStrudelsResponse": {
+            "type": "object",
+            "properties": {
+                "strudels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_strudels.SimilarStrudelDTO"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.StrudelBundle": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 1048576
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "license": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "api_rest_strudels.StrudelConflictResponse": {
+            "type": "object",
+            "properties": {
+                "client_code": {
+                    "description": "omitted when the update didn't change the code",
+                    "type": "string"
+                },
+                "current": {
+                    "description": "as saved; retry with its updated_at",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                        }
+                    ]
+                },
+                "diff": {
+                    "description": "unified diff from server_code to client_code",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "always \"conflict\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.Code"
+                        }
+                    ]
+                },
+                "message": {
+                    "type": "string"
+                },
+                "server_code": {
+                    "description": "same as current.code",
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_strudels.StrudelDetailResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "conversation_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_strudels.ConversationMessageDTO"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_cc_signal": {
+                    "description": "most restrictive signal of the fork ancestry",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                        }
+                    ]
+                },
+                "forked_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "parent_cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                },
+                "preview_url": {
+                    "description": "audio of the latest render",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_strudels.StrudelReferenceDTO": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_strudels.StrudelSearchResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.SearchResult"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.StrudelUsesResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta"
+                },
+                "uses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_attribution.StrudelUse"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.StrudelsListResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                },
+                "strudels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.StrudelsPageResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta"
+                },
+                "strudels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.TagsListResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api_rest_strudels.ValidateCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 102400
+                }
+            }
+        },
+        "api_rest_strudels.ValidateCodeResponse": {
+            "type": "object",
+            "properties": {
+                "diagnostics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_strudel.Diagnostic"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_strudels.VersionDiffResponse": {
+            "type": "object",
+            "properties": {
+                "diff": {
+                    "description": "empty when the code is identical",
+                    "type": "string"
+                },
+                "from": {
+                    "description": "0 when diffing the first version against empty code",
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_strudels.VersionsListResponse": {
+            "type": "object",
+            "properties": {
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.StrudelVersion"
+                    }
+                }
+            }
+        },
+        "api_rest_uploads.CreateUploadRequest": {
+            "type": "object",
+            "required": [
+                "content_type",
+                "kind"
+            ],
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "enum": [
+                        "image/png",
+                        "image/jpeg",
+                        "image/gif"
+                    ]
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "avatar"
+                    ]
+                }
+            }
+        },
+        "api_rest_uploads.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_users.AIFeaturesEnabledRequest": {
+            "type": "object",
+            "properties": {
+                "ai_features_enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_users.DailyUsage": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Number of generations",
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "Format: \"2006-01-02\"",
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_users.MonthlyUsageResponse": {
+            "type": "object",
+            "properties": {
+                "byok_generations": {
+                    "description": "made with the user's own key",
+                    "type": "integer"
+                },
+                "estimated_cost_usd": {
+                    "description": "includes BYOK generations, paid by the user",
+                    "type": "number"
+                },
+                "generations": {
+                    "type": "integer"
+                },
+                "input_tokens": {
+                    "type": "integer"
+                },
+                "models": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.ModelUsage"
+                    }
+                },
+                "month": {
+                    "description": "\"2006-01\"",
+                    "type": "string"
+                },
+                "output_tokens": {
+                    "type": "integer"
+                },
+                "quota": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.Result"
+                }
+            }
+        },
+        "api_rest_users.TrainingConsentRequest": {
+            "type": "object",
+            "properties": {
+                "training_consent": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_users.UpdateDisplayNameRequest": {
+            "type": "object",
+            "required": [
+                "display_name"
+            ],
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                }
+            }
+        },
+        "api_rest_users.UpdateEmailPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "invites": {
+                    "description": "session invitations",
+                    "type": "boolean"
+                },
+                "unsubscribed": {
+                    "description": "no email at all, including digests",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api_rest_users.UsageResponse": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "description": "Last 30 days",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api_rest_users.DailyUsage"
+                    }
+                },
+                "limit": {
+                    "description": "Daily limit (-1 for unlimited)",
+                    "type": "integer"
+                },
+                "remaining": {
+                    "description": "Remaining generations today (-1 for unlimited)",
+                    "type": "integer"
+                },
+                "tier": {
+                    "description": "\"free\", \"payg\", \"byok\"",
+                    "type": "string"
+                },
+                "today": {
+                    "description": "Generations used today",
+                    "type": "integer"
+                }
+            }
+        },
+        "api_rest_webhooks.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "api_rest_webhooks.DeliveriesListResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_webhooks.Delivery"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                }
+            }
+        },
+        "api_rest_webhooks.EventsListResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api_rest_webhooks.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_webhooks.UpdateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "api_rest_webhooks.WebhookWithSecretResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api_rest_webhooks.WebhooksListResponse": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_webhooks.Webhook"
+                    }
+                }
+            }
+        },
+        "api_websocket.MessageCatalog": {
+            "type": "object",
+            "properties": {
+                "agent_request": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.AgentRequestPayload"
+                },
+                "agent_response_chunk": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.AgentResponseChunkPayload"
+                },
+                "agent_response_done": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.AgentResponseDonePayload"
+                },
+                "chat_message": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ChatMessagePayload"
+                },
+                "chat_muted": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ChatMutedPayload"
+                },
+                "chat_reaction": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ChatReactionPayload"
+                },
+                "chat_reply": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ChatMessagePayload"
+                },
+                "chat_settings": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ChatSettingsPayload"
+                },
+                "clock_sync": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ClockSyncPayload"
+                },
+                "code_op": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.CodeOperationPayload"
+                },
+                "code_op_ack": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.CodeOperationAckPayload"
+                },
+                "code_update": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.CodeUpdatePayload"
+                },
+                "code_validation": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.CodeValidationPayload"
+                },
+                "connection_quality": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ConnectionQualityPayload"
+                },
+                "cursor_position": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.CursorPositionPayload"
+                },
+                "cursor_update": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.CursorUpdatePayload"
+                },
+                "error": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ErrorPayload"
+                },
+                "join_request": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.JoinRequestPayload"
+                },
+                "listener_count": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ListenerCountPayload"
+                },
+                "lock_region": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.LockRegionPayload"
+                },
+                "paste_lock_changed": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.PasteLockChangedPayload"
+                },
+                "permissions_updated": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.PermissionsUpdatedPayload"
+                },
+                "play": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.PlayPayload"
+                },
+                "rate_limit_update": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.RateLimitUpdatePayload"
+                },
+                "reconnect_hint": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ReconnectHintPayload"
+                },
+                "redo": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.UndoRedoPayload"
+                },
+                "region_locks": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.RegionLocksPayload"
+                },
+                "resume": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ResumePayload"
+                },
+                "resume_complete": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ResumeCompletePayload"
+                },
+                "selection_update": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.SelectionUpdatePayload"
+                },
+                "server_shutdown": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.ServerShutdownPayload"
+                },
+                "session_ended": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.SessionEndedPayload"
+                },
+                "session_state": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.SessionStatePayload"
+                },
+                "stop": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.StopPayload"
+                },
+                "transport_sync": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.TransportSyncPayload"
+                },
+                "undo": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.UndoRedoPayload"
+                },
+                "unlock_region": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.UnlockRegionPayload"
+                },
+                "user_joined": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.UserJoinedPayload"
+                },
+                "user_left": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.UserLeftPayload"
+                }
+            }
+        },
+        "api_websocket.TicketResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "seconds",
+                    "type": "integer"
+                },
+                "ticket": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_sessions.ActivitySample": {
+            "type": "object",
+            "properties": {
+                "agent_requests": {
+                    "type": "integer"
+                },
+                "chat_messages": {
+                    "type": "integer"
+                },
+                "code_edits": {
+                    "type": "integer"
+                },
+                "listeners": {
+                    "type": "integer"
+                },
+                "participants": {
+                    "type": "integer"
+                },
+                "playback_seconds": {
+                    "type": "number"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_sessions.ExpiryPolicy": {
+            "type": "object",
+            "properties": {
+                "auto_end_idle_hours": {
+                    "description": "nil uses the server default",
+                    "type": "integer"
+                },
+                "publish_on_end": {
+                    "description": "save the final code as a public strudel of the host",
+                    "type": "boolean"
+                },
+                "revoke_invites_on_end": {
+                    "description": "revoke invite tokens when the session is ended",
+                    "type": "boolean"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_sessions.Message": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "parentMessageID": {
+                    "description": "set for replies",
+                    "type": "string"
+                },
+                "reactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_sessions.Reaction"
+                    }
+                },
+                "role": {
+                    "description": "user",
+                    "type": "string"
+                },
+                "sessionID": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_sessions.Reaction": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "emoji": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_sessions.Snapshot": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.BulkRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "ids"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "delete",
+                        "add_tags",
+                        "remove_tags",
+                        "set_visibility",
+                        "set_cc_signal"
+                    ]
+                },
+                "cc_signal": {
+                    "description": "set_cc_signal",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                        }
+                    ]
+                },
+                "ids": {
+                    "description": "at most 100 strudels per call",
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_public": {
+                    "description": "set_visibility",
+                    "type": "boolean"
+                },
+                "tags": {
+                    "description": "add_tags and remove_tags",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.BulkResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "why the item was rejected",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "applied",
+                        "not_found",
+                        "rejected",
+                        "skipped"
+                    ]
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal": {
+            "type": "string",
+            "enum": [
+                "cc-cr",
+                "cc-dc",
+                "cc-ec",
+                "cc-op",
+                "no-ai"
+            ],
+            "x-enum-comments": {
+                "CCSignalCredit": "Credit: Allow AI use with attribution",
+                "CCSignalDirect": "Credit + Direct: Attribution + financial/in-kind support",
+                "CCSignalEcosystem": "Credit + Ecosystem: Attribution + contribute to commons",
+                "CCSignalNoAI": "No AI: Explicitly opt-out of AI training",
+                "CCSignalOpen": "Credit + Open: Attribution + keep derivatives open"
+            },
+            "x-enum-descriptions": [
+                "Credit: Allow AI use with attribution",
+                "Credit + Direct: Attribution + financial/in-kind support",
+                "Credit + Ecosystem: Attribution + contribute to commons",
+                "Credit + Open: Attribution + keep derivatives open",
+                "No AI: Explicitly opt-out of AI training"
+            ],
+            "x-enum-varnames": [
+                "CCSignalCredit",
+                "CCSignalDirect",
+                "CCSignalEcosystem",
+                "CCSignalOpen",
+                "CCSignalNoAI"
+            ]
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.CreateStrudelRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "title"
+            ],
+            "properties": {
+                "categories": {
+                    "description": "max 10 categories, each max 50 chars",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                },
+                "code": {
+                    "description": "1MB limit",
+                    "type": "string",
+                    "maxLength": 1048576
+                },
+                "conversation_history": {
+                    "description": "max 100 messages",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.Message"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "forked_from": {
+                    "type": "string"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "max 20 tags, each max 50 chars",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.ForkCounts": {
+            "type": "object",
+            "properties": {
+                "direct": {
+                    "description": "forks of the strudel itself",
+                    "type": "integer"
+                },
+                "max_depth": {
+                    "description": "generations below the strudel",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "all descendants up to the maximum depth",
+                    "type": "integer"
+                },
+                "truncated": {
+                    "description": "the tree was cut off at the depth or size limit",
+                    "type": "boolean"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.LineageNode": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                },
+                "children": {
+                    "description": "descendant trees only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.LineageNode"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "depth": {
+                    "description": "generations away from the requested strudel",
+                    "type": "integer"
+                },
+                "fork_count": {
+                    "description": "direct forks, including private ones",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "redacted": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.SearchResult": {
+            "type": "object",
+            "properties": {
+                "ai_assist_count": {
+                    "type": "integer"
+                },
+                "author_name": {
+                    "type": "string"
+                },
+                "auto_tags": {
+                    "description": "derived from the code in the background (only populated in list responses)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "complexity": {
+                    "description": "0-10, nil until analyzed",
+                    "type": "integer"
+                },
+                "conversation_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.Message"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "when the strudel was moved to the trash (only populated in trash listings)",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_cc_signal": {
+                    "description": "the most restrictive signal of the strudel and the strudels it was forked\nfrom, which is what applies to it (only populated in single strudel responses)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                        }
+                    ]
+                },
+                "estimated_bpm": {
+                    "description": "nil when unknown",
+                    "type": "number"
+                },
+                "forked_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "instruments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "popularity": {
+                    "type": "integer"
+                },
+                "preview_url": {
+                    "description": "audio of the strudel's latest render, which may be of older code (only\npopulated in single strudel responses)",
+                    "type": "string"
+                },
+                "rank": {
+                    "description": "full-text relevance, 0 without a query",
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.Strudel": {
+            "type": "object",
+            "properties": {
+                "ai_assist_count": {
+                    "type": "integer"
+                },
+                "author_name": {
+                    "type": "string"
+                },
+                "auto_tags": {
+                    "description": "derived from the code in the background (only populated in list responses)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "complexity": {
+                    "description": "0-10, nil until analyzed",
+                    "type": "integer"
+                },
+                "conversation_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.Message"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "when the strudel was moved to the trash (only populated in trash listings)",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_cc_signal": {
+                    "description": "the most restrictive signal of the strudel and the strudels it was forked\nfrom, which is what applies to it (only populated in single strudel responses)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                        }
+                    ]
+                },
+                "estimated_bpm": {
+                    "description": "nil when unknown",
+                    "type": "number"
+                },
+                "forked_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "instruments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "preview_url": {
+                    "description": "audio of the strudel's latest render, which may be of older code (only\npopulated in single strudel responses)",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.StrudelVersion": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "omitted in listings",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "size": {
+                    "description": "code length in bytes",
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_strudels.UpdateStrudelRequest": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                },
+                "code": {
+                    "description": "1MB limit",
+                    "type": "string",
+                    "maxLength": 1048576
+                },
+                "conversation_history": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.Message"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "updated_at": {
+                    "description": "the updated_at the client last loaded; when set, the update fails with\nErrStrudelConflict if the strudel has been saved since. the API requires\nit or If-Match, nil skips the check for internal writes like reverts",
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_algopatterns_users.User": {
+            "type": "object",
+            "properties": {
+                "ai_features_enabled": {
+                    "type": "boolean"
+                },
+                "avatar_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "training_consent": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_api_rest_pagination.CursorMeta": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "description": "link to the next page",
+                    "type": "string"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev": {
+                    "description": "link to the previous page",
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_api_rest_pagination.Meta": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_agent.DocReference": {
+            "type": "object",
+            "properties": {
+                "page_name": {
+                    "type": "string"
+                },
+                "section_title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_agent.Message": {
+            "type": "object",
+            "properties": {
+                "clarifying_questions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "content": {
+                    "description": "message content",
+                    "type": "string"
+                },
+                "doc_references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.DocReference"
+                    }
+                },
+                "is_actionable": {
+                    "description": "true if response can be applied",
+                    "type": "boolean"
+                },
+                "is_code_response": {
+                    "description": "true if AI generated code",
+                    "type": "boolean"
+                },
+                "role": {
+                    "description": "\"user\" or \"assistant\"",
+                    "type": "string"
+                },
+                "strudel_references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.StrudelReference"
+                    }
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_agent.StrudelReference": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_assets.Asset": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "set once ready",
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_assets.Upload": {
+            "type": "object",
+            "properties": {
+                "asset_id": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "description": "send as the Content-Type header",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "max_bytes": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "upload_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_attribution.StrudelStats": {
+            "type": "object",
+            "properties": {
+                "fork_count": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "total_uses": {
+                    "type": "integer"
+                },
+                "unique_users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_attribution.StrudelStatsResponse": {
+            "type": "object",
+            "properties": {
+                "recent_uses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_attribution.StrudelUse"
+                    }
+                },
+                "stats": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_attribution.StrudelStats"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_attribution.StrudelUse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "requesting_display_name": {
+                    "type": "string"
+                },
+                "requesting_user_id": {
+                    "type": "string"
+                },
+                "similarity_score": {
+                    "type": "number"
+                },
+                "target_strudel_id": {
+                    "type": "string"
+                },
+                "target_strudel_title": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_audit.Entry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_cards.Card": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image_alt": {
+                    "type": "string"
+                },
+                "image_height": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "description": "nil until the image of the current version is generated",
+                    "type": "string"
+                },
+                "image_width": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "twitter_card": {
+                    "description": "summary_large_image with an image, summary without",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "the strudel's page on the frontend",
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_ccsignals.CCSignal": {
+            "type": "string",
+            "enum": [
+                "cc-cr",
+                "cc-dc",
+                "cc-ec",
+                "cc-op",
+                "no-ai"
+            ],
+            "x-enum-comments": {
+                "SignalCredit": "credit: allow AI use with attribution",
+                "SignalDirect": "credit + direct: attribution + financial support",
+                "SignalEcosystem": "credit + ecosystem: attribution + contribute to commons",
+                "SignalNoAI": "no AI: explicitly opt-out of AI usage",
+                "SignalOpen": "credit + open: attribution + keep derivatives open"
+            },
+            "x-enum-descriptions": [
+                "credit: allow AI use with attribution",
+                "credit + direct: attribution + financial support",
+                "credit + ecosystem: attribution + contribute to commons",
+                "credit + open: attribution + keep derivatives open",
+                "no AI: explicitly opt-out of AI usage"
+            ],
+            "x-enum-varnames": [
+                "SignalCredit",
+                "SignalDirect",
+                "SignalEcosystem",
+                "SignalOpen",
+                "SignalNoAI"
+            ]
+        },
+        "codeberg_org_algopatterns_server_internal_ccsignals.Decision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "cc_signal": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_ccsignals.CCSignal"
+                },
+                "code_length": {
+                    "type": "integer"
+                },
+                "coverage": {
+                    "description": "partial matches only, share of the matched work pasted",
+                    "type": "number"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "matched_lines": {
+                    "description": "partial matches only, lines of the matched work",
+                    "type": "string"
+                },
+                "matched_owner_id": {
+                    "type": "string"
+                },
+                "matched_work_id": {
+                    "description": "fingerprint matches only",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_embeds.Token": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "strudel_id": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_embeds.View": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "is_live": {
+                    "description": "session is active; always false for strudels",
+                    "type": "boolean"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_engagement.LikeStatus": {
+            "type": "object",
+            "properties": {
+                "liked": {
+                    "type": "boolean"
+                },
+                "likes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_engagement.TrendingStrudel": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "cc_signal": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "forks": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "plays": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_errors.Code": {
+            "type": "string",
+            "enum": [
+                "unauthorized",
+                "forbidden",
+                "not_found",
+                "validation_error",
+                "server_error",
+                "bad_request",
+                "conflict",
+                "precondition_required",
+                "too_many_requests",
+                "payload_too_large",
+                "service_unavailable",
+                "invalid_operation",
+                "session_not_found",
+                "invalid_invite",
+                "participant_not_found",
+                "authorization_pending",
+                "slow_down",
+                "access_denied",
+                "expired_token",
+                "byok_required",
+                "rate_limit_exceeded",
+                "paste_locked",
+                "ai_unavailable",
+                "provider_rate_limited",
+                "provider_rejected",
+                "region_locked",
+                "feature_disabled",
+                "muted",
+                "slow_mode",
+                "server_busy",
+                "content_blocked"
+            ],
+            "x-enum-comments": {
+                "CodeRateLimitExceeded": "quota used up"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "quota used up",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                "",
+                ""
+            ],
+            "x-enum-varnames": [
+                "CodeUnauthorized",
+                "CodeForbidden",
+                "CodeNotFound",
+                "CodeValidationError",
+                "CodeServerError",
+                "CodeBadRequest",
+                "CodeConflict",
+                "CodePreconditionRequired",
+                "CodeTooManyRequests",
+                "CodePayloadTooLarge",
+                "CodeServiceUnavailable",
+                "CodeInvalidOperation",
+                "CodeSessionNotFound",
+                "CodeInvalidInvite",
+                "CodeParticipantNotFound",
+                "CodeAuthorizationPending",
+                "CodeSlowDown",
+                "CodeAccessDenied",
+                "CodeExpiredToken",
+                "CodeBYOKRequired",
+                "CodeRateLimitExceeded",
+                "CodePasteLocked",
+                "CodeAIUnavailable",
+                "CodeProviderRateLimited",
+                "CodeProviderRejected",
+                "CodeRegionLocked",
+                "CodeFeatureDisabled",
+                "CodeMuted",
+                "CodeSlowMode",
+                "CodeServerBusy",
+                "CodeContentBlocked"
+            ]
+        },
+        "codeberg_org_algopatterns_server_internal_errors.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "description": "optional details (sanitized in production)",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "error code (e.g., \"unauthorized\", \"not_found\")",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.Code"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "user-friendly message",
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "echoed from request for correlation",
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_events.Event": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "host_name": {
+                    "type": "string"
+                },
+                "host_user_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rsvp_count": {
+                    "type": "integer"
+                },
+                "rsvped": {
+                    "description": "whether the requesting user RSVPed",
+                    "type": "boolean"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "went_live_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_follows.FeedItem": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "forked_from": {
+                    "description": "forks only",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "strudel or event ID",
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "description": "events only",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "published, forked or event",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_follows.Follow": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "followed_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_follows.FollowStatus": {
+            "type": "object",
+            "properties": {
+                "followers": {
+                    "type": "integer"
+                },
+                "following": {
+                    "type": "boolean"
+                },
+                "follows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_health.DependencyStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "optional": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_health.Report": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_health.DependencyStatus"
+                    }
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_jobs.DeadLetter": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "when the job was first enqueued",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "is_public": {
+                "kind": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_joinrequests.Request": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "granted on approval",
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_lessons.CheckResult": {
+            "type": "object",
+            "properties": {
+                "advanced": {
+                    "description": "the step passed and the lesson moved on",
                     "type": "boolean"
                 },
-                "tags": {
+                "missing": {
+                    "description": "unmet criteria, readable",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "progress": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.Progress"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_lessons.Criteria": {
+            "type": "object",
+            "properties": {
+                "functions": {
+                    "description": "methods the code must call, like scale",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "scales": {
+                    "description": "scale modes the code must use, like minor:pentatonic",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_lessons.Lesson": {
+            "type": "object",
+            "properties": {
+                "concept": {
+                    "description": "title of the concept file, e.g. \"Music Theory\"",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.Step"
+                    }
+                },
+                "summary": {
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_lessons.LessonSummary": {
+            "type": "object",
+            "properties": {
+                "concept": {
+                    "type": "string"
                 },
-                "use_in_training": {
-                    "type": "boolean"
+                "id": {
+                    "type": "string"
                 },
-                "user_id": {
+                "step_count": {
+                    "type": "integer"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_agent.DocReference": {
+        "codeberg_org_algopatterns_server_internal_lessons.Progress": {
             "type": "object",
             "properties": {
-                "page_name": {
+                "completed_at": {
                     "type": "string"
                 },
-                "section_title": {
+                "current": {
+                    "description": "nil once completed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.Step"
+                        }
+                    ]
+                },
+                "lesson_id": {
                     "type": "string"
                 },
-                "url": {
+                "session_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "started_by": {
+                    "type": "string"
+                },
+                "step": {
+                    "description": "index of the current step, the step count once completed",
+                    "type": "integer"
+                },
+                "step_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_agent.GenerateRequest": {
+        "codeberg_org_algopatterns_server_internal_lessons.Step": {
             "type": "object",
-            "required": [
-                "user_query"
+            "properties": {
+                "criteria": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_lessons.Criteria"
+                },
+                "example": {
+                    "type": "string"
+                },
+                "explanation": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_llm.Provider": {
+            "type": "string",
+            "enum": [
+                "anthropic",
+                "openai",
+                "gemini",
+                "mistral",
+                "ollama"
+            ],
+            "x-enum-comments": {
+                "ProviderOllama": "self-hosted, registered when OLLAMA_BASE_URL is set"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "self-hosted, registered when OLLAMA_BASE_URL is set"
             ],
+            "x-enum-varnames": [
+                "ProviderAnthropic",
+                "ProviderOpenAI",
+                "ProviderGemini",
+                "ProviderMistral",
+                "ProviderOllama"
+            ]
+        },
+        "codeberg_org_algopatterns_server_internal_llm.ProviderSpec": {
+            "type": "object",
             "properties": {
-                "conversation_history": {
+                "default_model": {
+                    "type": "string"
+                },
+                "models": {
+                    "description": "allowlist, always contains DefaultModel",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api_rest_agent.Message"
+                        "type": "string"
                     }
                 },
-                "editor_state": {
+                "name": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_llm.Provider"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_mailer.Preferences": {
+            "type": "object",
+            "properties": {
+                "invites": {
+                    "description": "session invitations",
+                    "type": "boolean"
+                },
+                "unsubscribed": {
+                    "description": "no email at all, including digests",
+                    "type": "boolean"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_moderation.QueueItem": {
+            "type": "object",
+            "properties": {
+                "action": {
                     "type": "string"
                 },
-                "forked_from_id": {
-                    "description": "optional: for blocking AI on restricted forks",
+                "author_id": {
                     "type": "string"
                 },
-                "provider": {
-                    "description": "\"anthropic\" or \"openai\"",
+                "author_name": {
                     "type": "string"
                 },
-                "provider_api_key": {
-                    "description": "BYOK key",
+                "content": {
                     "type": "string"
                 },
-                "session_id": {
-                    "description": "optional: for paste lock validation",
+                "content_id": {
                     "type": "string"
                 },
-                "strudel_id": {
-                    "description": "optional: for persisting conversation",
+                "content_type": {
                     "type": "string"
                 },
-                "user_query": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_agent.GenerateResponse": {
+        "codeberg_org_algopatterns_server_internal_notifications.Notification": {
             "type": "object",
             "properties": {
-                "clarifying_questions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "code": {
+                "created_at": {
                     "type": "string"
                 },
-                "doc_references": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api_rest_agent.DocReference"
+                        "type": "integer"
                     }
                 },
-                "docs_retrieved": {
-                    "type": "integer"
-                },
-                "examples_retrieved": {
-                    "type": "integer"
-                },
-                "is_actionable": {
-                    "type": "boolean"
+                "id": {
+                    "type": "string"
                 },
-                "is_code_response": {
-                    "type": "boolean"
+                "kind": {
+                    "type": "string"
                 },
-                "model": {
+                "message": {
                     "type": "string"
                 },
-                "strudel_references": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api_rest_agent.StrudelReference"
-                    }
+                "read_at": {
+                    "type": "string"
                 }
             }
         },
-        "api_rest_agent.Message": {
+        "codeberg_org_algopatterns_server_internal_notifications.Preferences": {
             "type": "object",
             "properties": {
-                "content": {
+                "email_digest": {
                     "type": "string"
                 },
-                "role": {
+                "last_digest_at": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_agent.StrudelReference": {
+        "codeberg_org_algopatterns_server_internal_pasteappeals.Appeal": {
             "type": "object",
             "properties": {
-                "author_name": {
+                "cc_signal": {
+                    "type": "string"
+                },
+                "claims_ownership": {
+                    "type": "boolean"
+                },
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "title": {
+                "lock_reason": {
                     "type": "string"
                 },
-                "url": {
+                "matched_work_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
+                },
+                "verified": {
+                    "description": "ownership verified, the lock was lifted without review",
+                    "type": "boolean"
                 }
             }
         },
-        "api_rest_auth.AuthResponse": {
+        "codeberg_org_algopatterns_server_internal_quota.ModelUsage": {
             "type": "object",
             "properties": {
-                "token": {
+                "byok_generations": {
+                    "type": "integer"
+                },
+                "estimated_cost_usd": {
+                    "type": "number"
+                },
+                "generations": {
+                    "type": "integer"
+                },
+                "input_tokens": {
+                    "type": "integer"
+                },
+                "model": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_users.User"
+                "output_tokens": {
+                    "type": "integer"
+                },
+                "pricing_known": {
+                    "description": "false when some generations have no price, they count as 0",
+                    "type": "boolean"
+                },
+                "provider": {
+                    "type": "string"
                 }
             }
         },
-        "api_rest_auth.MessageResponse": {
+        "codeberg_org_algopatterns_server_internal_quota.PeriodUsage": {
             "type": "object",
             "properties": {
-                "message": {
+                "limit": {
+                    "description": "Unlimited (-1) when not enforced",
+                    "type": "integer"
+                },
+                "remaining": {
+                    "description": "Unlimited (-1) when not enforced",
+                    "type": "integer"
+                },
+                "reset_at": {
                     "type": "string"
+                },
+                "used": {
+                    "type": "integer"
                 }
             }
         },
-        "api_rest_auth.UpdateProfileRequest": {
+        "codeberg_org_algopatterns_server_internal_quota.Result": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "avatar_url": {
-                    "type": "string",
-                    "maxLength": 500
+                "allowed": {
+                    "type": "boolean"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100
+                "daily": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.PeriodUsage"
+                },
+                "monthly": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.PeriodUsage"
+                },
+                "spend": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.SpendUsage"
+                },
+                "tier": {
+                    "type": "string"
                 }
             }
         },
-        "api_rest_auth.UserResponse": {
+        "codeberg_org_algopatterns_server_internal_quota.SpendUsage": {
             "type": "object",
             "properties": {
-                "user": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_users.User"
+                "limit_usd": {
+                    "description": "Unlimited (-1) when not enforced",
+                    "type": "number"
+                },
+                "remaining_usd": {
+                    "description": "Unlimited (-1) when not enforced",
+                    "type": "number"
+                },
+                "reset_at": {
+                    "type": "string"
+                },
+                "used_usd": {
+                    "type": "number"
                 }
             }
         },
-        "api_rest_collaboration.CreateInviteTokenRequest": {
+        "codeberg_org_algopatterns_server_internal_renders.Render": {
             "type": "object",
-            "required": [
-                "role"
-            ],
             "properties": {
-                "expires_at": {
+                "completed_at": {
                     "type": "string"
                 },
-                "max_uses": {
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_seconds": {
                     "type": "integer"
                 },
-                "role": {
-                    "type": "string",
-                    "enum": [
-                        "co-author",
-                        "viewer"
-                    ]
-                }
-            }
-        },
-        "api_rest_collaboration.CreateSessionRequest": {
-            "type": "object",
-            "required": [
-                "title"
-            ],
-            "properties": {
-                "code": {
-                    "description": "1MB limit",
-                    "type": "string",
-                    "maxLength": 1048576
+                "error": {
+                    "description": "why the last attempt failed",
+                    "type": "string"
                 },
-                "is_discoverable": {
-                    "description": "optional, defaults to false",
-                    "type": "boolean"
+                "id": {
+                    "type": "string"
                 },
-                "title": {
-                    "type": "string",
-                    "maxLength": 200
+                "status": {
+                    "type": "string"
+                },
+                "strudel_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "set once ready",
+                    "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.CreateSessionResponse": {
+        "codeberg_org_algopatterns_server_internal_samplepacks.SamplePack": {
             "type": "object",
             "properties": {
-                "code": {
+                "base_url": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "host_user_id": {
+                "description": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "license": {
+                    "type": "string"
                 },
-                "is_discoverable": {
-                    "type": "boolean"
+                "manifest_url": {
+                    "type": "string"
                 },
-                "last_activity": {
+                "name": {
                     "type": "string"
                 },
-                "title": {
+                "sounds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.InviteTokenResponse": {
+        "codeberg_org_algopatterns_server_internal_stats.ProviderUsage": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "byok_generations": {
+                    "description": "made with the user's own key",
+                    "type": "integer"
                 },
-                "expires_at": {
-                    "type": "string"
+                "estimated_cost_usd": {
+                    "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "generations": {
+                    "type": "integer"
                 },
-                "max_uses": {
+                "input_tokens": {
                     "type": "integer"
                 },
-                "role": {
+                "model": {
                     "type": "string"
                 },
-                "session_id": {
-                    "type": "string"
+                "output_tokens": {
+                    "type": "integer"
                 },
-                "token": {
-                    "type": "string"
+                "pricing_known": {
+                    "description": "false when the model has no price, its cost counts as 0",
+                    "type": "boolean"
                 },
-                "uses_count": {
-                    "type": "integer"
+                "provider": {
+                    "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.InviteTokensListResponse": {
+        "codeberg_org_algopatterns_server_internal_stats.PublicStats": {
             "type": "object",
             "properties": {
-                "tokens": {
+                "active_sessions": {
+                    "type": "integer"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "public_strudels": {
+                    "type": "integer"
+                },
+                "strudels_published_this_week": {
+                    "type": "integer"
+                },
+                "top_tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api_rest_collaboration.InviteTokenResponse"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_stats.TagCount"
                     }
+                },
+                "total_ai_generations": {
+                    "type": "integer"
                 }
             }
         },
-        "api_rest_collaboration.IsLiveResponse": {
+        "codeberg_org_algopatterns_server_internal_stats.TagCount": {
             "type": "object",
             "properties": {
-                "has_active_invite_tokens": {
-                    "type": "boolean"
-                },
-                "is_live": {
-                    "type": "boolean"
-                },
-                "participant_count": {
+                "count": {
                     "type": "integer"
+                },
+                "tag": {
+                    "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.JoinSessionRequest": {
+        "codeberg_org_algopatterns_server_internal_stats.UsageStats": {
             "type": "object",
-            "required": [
-                "invite_token"
-            ],
             "properties": {
-                "display_name": {
-                    "type": "string",
-                    "maxLength": 100
+                "estimated_cost_usd": {
+                    "description": "platform keys only, BYOK is paid by the user",
+                    "type": "number"
                 },
-                "invite_token": {
+                "generations": {
+                    "type": "integer"
+                },
+                "input_tokens": {
+                    "type": "integer"
+                },
+                "output_tokens": {
+                    "type": "integer"
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_stats.ProviderUsage"
+                    }
+                },
+                "since": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.JoinSessionResponse": {
+        "codeberg_org_algopatterns_server_internal_strudel.Diagnostic": {
             "type": "object",
             "properties": {
-                "display_name": {
+                "column": {
+                    "description": "1-based, in characters",
+                    "type": "integer"
+                },
+                "line": {
+                    "description": "1-based",
+                    "type": "integer"
+                },
+                "message": {
                     "type": "string"
                 },
-                "role": {
+                "rule": {
+                    "description": "e.g. \"unbalanced_delimiter\", \"unknown_function\"",
                     "type": "string"
                 },
-                "session_id": {
+                "severity": {
+                    "description": "\"error\" | \"warning\"",
                     "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.LiveSessionResponse": {
+        "codeberg_org_algopatterns_server_internal_webhooks.Delivery": {
             "type": "object",
             "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "is_discoverable": {
-                    "type": "boolean"
+                "last_attempt_at": {
+                    "type": "string"
                 },
-                "is_member": {
-                    "type": "boolean"
+                "next_attempt_at": {
+                    "description": "only while pending",
+                    "type": "string"
                 },
-                "last_activity": {
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "response_body": {
                     "type": "string"
                 },
-                "participant_count": {
+                "response_status": {
                     "type": "integer"
                 },
-                "title": {
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.LiveSessionsListResponse": {
+        "codeberg_org_algopatterns_server_internal_webhooks.Webhook": {
             "type": "object",
             "properties": {
-                "pagination": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                "active": {
+                    "type": "boolean"
                 },
-                "sessions": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api_rest_collaboration.LiveSessionResponse"
+                        "type": "string"
                     }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.MessageResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.AgentRateLimit": {
             "type": "object",
             "properties": {
-                "message": {
+                "daily": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.PeriodUsage"
+                },
+                "monthly": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.PeriodUsage"
+                },
+                "spend": {
+                    "description": "estimated model cost this month",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_quota.SpendUsage"
+                        }
+                    ]
+                },
+                "tier": {
+                    "description": "\"anonymous\", \"free\", \"payg\" or \"byok\"",
                     "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.MessagesResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.AgentRequestPayload": {
             "type": "object",
             "properties": {
-                "messages": {
+                "conversation_history": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_sessions.Message"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.Message"
                     }
-                }
-            }
-        },
-        "api_rest_collaboration.ParticipantResponse": {
-            "type": "object",
-            "properties": {
-                "display_name": {
+                },
+                "editor_state": {
                     "type": "string"
                 },
-                "id": {
+                "forked_from_id": {
+                    "description": "blocks AI on restricted forks",
                     "type": "string"
                 },
-                "joined_at": {
+                "model": {
+                    "description": "defaults to the provider's default model",
                     "type": "string"
                 },
-                "left_at": {
+                "provider": {
+                    "description": "see GET /agent/providers",
                     "type": "string"
                 },
-                "role": {
+                "provider_api_key": {
+                    "description": "BYOK key",
                     "type": "string"
                 },
-                "status": {
+                "request_id": {
+                    "description": "echoed back in chunks for correlation",
                     "type": "string"
                 },
-                "user_id": {
+                "user_query": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.ParticipantsListResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.AgentResponseChunkPayload": {
             "type": "object",
             "properties": {
-                "participants": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api_rest_collaboration.ParticipantResponse"
-                    }
+                "content": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.SessionResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.AgentResponseDonePayload": {
             "type": "object",
             "properties": {
+                "cached": {
+                    "description": "served from the response cache",
+                    "type": "boolean"
+                },
                 "code": {
+                    "description": "content extracted from markdown if needed",
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
+                "degraded": {
+                    "description": "generated by the fallback provider during an outage",
+                    "type": "boolean"
                 },
-                "ended_at": {
-                    "type": "string"
+                "doc_references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.DocReference"
+                    }
                 },
-                "host_user_id": {
-                    "type": "string"
+                "input_tokens": {
+                    "type": "integer"
                 },
-                "id": {
+                "is_code_response": {
+                    "type": "boolean"
+                },
+                "model": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "output_tokens": {
+                    "type": "integer"
                 },
-                "is_discoverable": {
-                    "type": "boolean"
+                "rate_limit": {
+                    "description": "omitted when the request isn't metered",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.AgentRateLimit"
+                        }
+                    ]
                 },
-                "last_activity": {
+                "request_id": {
                     "type": "string"
                 },
-                "participants": {
+                "strudel_references": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api_rest_collaboration.ParticipantResponse"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.StrudelReference"
                     }
-                },
-                "title": {
-                    "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.SessionsListResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.ChatMessagePayload": {
             "type": "object",
             "properties": {
-                "sessions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api_rest_collaboration.SessionResponse"
-                    }
+                "display_name": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "message_id": {
+                    "description": "assigned by the server",
+                    "type": "string"
+                },
+                "parent_message_id": {
+                    "description": "message replied to (chat_reply)",
+                    "type": "string"
                 }
             }
         },
-        "api_rest_collaboration.SetDiscoverableRequest": {
+        "codeberg_org_algopatterns_server_internal_websocket.ChatMutedPayload": {
             "type": "object",
             "properties": {
-                "is_discoverable": {
+                "muted": {
                     "type": "boolean"
                 }
             }
         },
-        "api_rest_collaboration.SoftEndSessionResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.ChatReactionPayload": {
             "type": "object",
             "properties": {
-                "invites_revoked": {
-                    "type": "boolean"
+                "display_name": {
+                    "type": "string"
                 },
-                "message": {
+                "emoji": {
                     "type": "string"
                 },
-                "participants_kicked": {
-                    "type": "integer"
+                "message_id": {
+                    "type": "string"
+                },
+                "removed": {
+                    "type": "boolean"
                 }
             }
         },
-        "api_rest_collaboration.UpdateSessionCodeRequest": {
+        "codeberg_org_algopatterns_server_internal_websocket.ChatSettingsPayload": {
             "type": "object",
-            "required": [
-                "code"
-            ],
             "properties": {
-                "code": {
-                    "description": "1MB limit",
-                    "type": "string",
-                    "maxLength": 1048576
+                "slow_mode_seconds": {
+                    "description": "0 when slow mode is off",
+                    "type": "integer"
                 }
             }
         },
-        "api_rest_collaboration.UpdateSessionCodeResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.ClockSyncPayload": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "string"
+                "client_time": {
+                    "type": "number"
                 },
-                "message": {
-                    "type": "string"
+                "server_receive": {
+                    "type": "number"
+                },
+                "server_send": {
+                    "type": "number"
                 }
             }
         },
-        "api_rest_health.PingResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.CodeOperationAckPayload": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "revision": {
+                    "description": "revision produced by the acknowledged operation",
+                    "type": "integer"
                 }
             }
         },
-        "api_rest_health.Response": {
+        "codeberg_org_algopatterns_server_internal_websocket.CodeOperationPayload": {
             "type": "object",
             "properties": {
-                "service": {
+                "display_name": {
                     "type": "string"
                 },
-                "status": {
+                "operation": {
+                    "description": "ot.js format: n retains, -n deletes, \"s\" inserts",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "revision": {
+                    "description": "revision the operation is based on (client) or produces (broadcast)",
+                    "type": "integer"
+                },
+                "role": {
                     "type": "string"
                 },
-                "version": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_strudels.ConversationMessageDTO": {
+        "codeberg_org_algopatterns_server_internal_websocket.CodeUpdatePayload": {
             "type": "object",
             "properties": {
-                "clarifying_questions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "code": {
+                    "type": "string"
                 },
-                "content": {
+                "cursor_col": {
+                    "type": "integer"
+                },
+                "cursor_line": {
+                    "type": "integer"
+                },
+                "display_name": {
                     "type": "string"
                 },
-                "created_at": {
+                "revision": {
+                    "description": "document revision after the update (added by backend)",
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "\"host\", \"co-author\" - for cursor tracking",
                     "type": "string"
                 },
-                "doc_references": {
+                "source": {
+                    "description": "'typed' | 'loaded_strudel' | 'forked' | 'paste' | 'undo' | 'redo' | 'restore'",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.CodeValidationPayload": {
+            "type": "object",
+            "properties": {
+                "diagnostics": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api_rest_strudels.DocReferenceDTO"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_strudel.Diagnostic"
                     }
                 },
-                "id": {
-                    "type": "string"
+                "revision": {
+                    "description": "document revision the code was linted at",
+                    "type": "integer"
                 },
-                "is_actionable": {
+                "valid": {
+                    "description": "false when any diagnostic is an error",
                     "type": "boolean"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.ConnectionQualityPayload": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
                 },
-                "is_code_response": {
-                    "type": "boolean"
+                "missed_pings": {
+                    "type": "integer"
+                },
+                "quality": {
+                    "description": "good, fair, poor or lost",
+                    "type": "string"
+                },
+                "rtt_ms": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.CursorPositionPayload": {
+            "type": "object",
+            "properties": {
+                "col": {
+                    "description": "0-indexed column number",
+                    "type": "integer"
+                },
+                "display_name": {
+                    "description": "display name (added by backend)",
+                    "type": "string"
+                },
+                "line": {
+                    "description": "1-indexed line number",
+                    "type": "integer"
                 },
                 "role": {
+                    "description": "role (added by backend)",
                     "type": "string"
                 },
-                "strudel_references": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api_rest_strudels.StrudelReferenceDTO"
-                    }
+                "user_id": {
+                    "description": "user ID (added by backend)",
+                    "type": "string"
                 }
             }
         },
-        "api_rest_strudels.DocReferenceDTO": {
+        "codeberg_org_algopatterns_server_internal_websocket.CursorUpdatePayload": {
             "type": "object",
             "properties": {
-                "page_name": {
+                "col": {
+                    "description": "0-indexed column number",
+                    "type": "integer"
+                },
+                "display_name": {
+                    "description": "display name (added by backend)",
                     "type": "string"
                 },
-                "section_title": {
+                "line": {
+                    "description": "1-indexed line number",
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "role (added by backend)",
                     "type": "string"
                 },
-                "url": {
+                "user_id": {
+                    "description": "user ID (added by backend)",
                     "type": "string"
                 }
             }
         },
-        "api_rest_strudels.MessageResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.ErrorPayload": {
             "type": "object",
             "properties": {
+                "details": {
+                    "type": "string"
+                },
+                "error": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_errors.Code"
+                },
                 "message": {
                     "type": "string"
+                },
+                "request_id": {
+                    "description": "echoed from the request that failed",
+                    "type": "string"
+                },
+                "retry_after_ms": {
+                    "type": "integer"
+                },
+                "retryable": {
+                    "type": "boolean"
                 }
             }
         },
-        "api_rest_strudels.StrudelDetailResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.HubStats": {
             "type": "object",
             "properties": {
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "cc_signal": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
-                },
-                "code": {
-                    "type": "string"
+                "authenticated_users": {
+                    "type": "integer"
                 },
-                "conversation_history": {
+                "busiest_sessions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api_rest_strudels.ConversationMessageDTO"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.SessionStats"
                     }
                 },
-                "created_at": {
-                    "type": "string"
+                "connections": {
+                    "type": "integer"
                 },
-                "description": {
-                    "type": "string"
+                "listeners": {
+                    "description": "audience clients, not included in connections",
+                    "type": "integer"
                 },
-                "forked_from": {
-                    "type": "string"
+                "sessions": {
+                    "type": "integer"
                 },
-                "id": {
+                "unique_ips": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.JoinRequestPayload": {
+            "type": "object",
+            "properties": {
+                "display_name": {
                     "type": "string"
                 },
-                "is_public": {
-                    "type": "boolean"
-                },
-                "parent_cc_signal": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                "expires_at": {
+                    "description": "unix millis; pending requests can't be answered after",
+                    "type": "integer"
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "message": {
+                    "type": "string"
                 },
-                "title": {
+                "request_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "status": {
+                    "description": "pending, approved or denied",
                     "type": "string"
                 },
                 "user_id": {
@@ -2840,41 +15166,67 @@ const docTemplate = `{
                 }
             }
         },
-        "api_rest_strudels.StrudelReferenceDTO": {
+        "codeberg_org_algopatterns_server_internal_websocket.ListenerCountPayload": {
             "type": "object",
             "properties": {
-                "author_name": {
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.LockRegionPayload": {
+            "type": "object",
+            "properties": {
+                "block": {
                     "type": "string"
                 },
-                "id": {
+                "end_line": {
+                    "type": "integer"
+                },
+                "start_line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.Message": {
+            "type": "object",
+            "properties": {
+                "payload": {
+                    "type": "object"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "session_id": {
                     "type": "string"
                 },
-                "title": {
+                "timestamp": {
                     "type": "string"
                 },
-                "url": {
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "api_rest_strudels.StrudelsListResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.PasteLockChangedPayload": {
             "type": "object",
             "properties": {
-                "pagination": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_api_rest_pagination.Meta"
+                "locked": {
+                    "type": "boolean"
                 },
-                "strudels": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.Strudel"
-                    }
+                "reason": {
+                    "description": "\"paste_detected\", \"edits_sufficient\", \"ttl_expired\", \"ownership_verified\", \"appeal_approved\"",
+                    "type": "string"
                 }
             }
         },
-        "api_rest_strudels.TagsListResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.PermissionsUpdatedPayload": {
             "type": "object",
             "properties": {
-                "tags": {
+                "permissions": {
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -2882,234 +15234,206 @@ const docTemplate = `{
                 }
             }
         },
-        "api_rest_users.AIFeaturesEnabledRequest": {
+        "codeberg_org_algopatterns_server_internal_websocket.PlayPayload": {
             "type": "object",
             "properties": {
-                "ai_features_enabled": {
-                    "type": "boolean"
+                "cpm": {
+                    "type": "number"
+                },
+                "display_name": {
+                    "type": "string"
                 }
             }
         },
-        "api_rest_users.DailyUsage": {
+        "codeberg_org_algopatterns_server_internal_websocket.RateLimitUpdatePayload": {
             "type": "object",
             "properties": {
-                "count": {
-                    "description": "Number of generations",
+                "code_updates_per_second": {
                     "type": "integer"
                 },
-                "date": {
-                    "description": "Format: \"2006-01-02\"",
+                "reason": {
+                    "description": "backlog or headroom",
                     "type": "string"
                 }
             }
         },
-        "api_rest_users.TrainingConsentRequest": {
-            "type": "object",
-            "properties": {
-                "training_consent": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "api_rest_users.UsageResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.ReconnectHintPayload": {
             "type": "object",
             "properties": {
-                "history": {
-                    "description": "Last 30 days",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/api_rest_users.DailyUsage"
-                    }
-                },
-                "limit": {
-                    "description": "Daily limit (-1 for unlimited)",
+                "backoff_ms": {
+                    "description": "wait this long before reconnecting, spreads the load on the new instance",
                     "type": "integer"
                 },
-                "remaining": {
-                    "description": "Remaining generations today (-1 for unlimited)",
-                    "type": "integer"
+                "client_id": {
+                    "description": "for the resume message",
+                    "type": "string"
                 },
-                "tier": {
-                    "description": "\"free\", \"payg\", \"byok\"",
+                "reason": {
                     "type": "string"
                 },
-                "today": {
-                    "description": "Generations used today",
+                "seq": {
+                    "description": "last sequence number of the session, for the resume message",
                     "type": "integer"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_algopatterns_sessions.Message": {
+        "codeberg_org_algopatterns_server_internal_websocket.RegionLock": {
             "type": "object",
             "properties": {
-                "avatarUrl": {
-                    "type": "string"
-                },
-                "content": {
+                "block": {
+                    "description": "pattern block the lock was claimed by",
                     "type": "string"
                 },
-                "createdAt": {
+                "display_name": {
                     "type": "string"
                 },
-                "displayName": {
-                    "type": "string"
+                "end_line": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
-                "role": {
-                    "description": "user",
-                    "type": "string"
-                },
-                "sessionID": {
-                    "type": "string"
+                "start_line": {
+                    "type": "integer"
                 },
-                "userID": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal": {
-            "type": "string",
-            "enum": [
-                "cc-cr",
-                "cc-dc",
-                "cc-ec",
-                "cc-op",
-                "no-ai"
-            ],
-            "x-enum-comments": {
-                "CCSignalCredit": "Credit: Allow AI use with attribution",
-                "CCSignalDirect": "Credit + Direct: Attribution + financial/in-kind support",
-                "CCSignalEcosystem": "Credit + Ecosystem: Attribution + contribute to commons",
-                "CCSignalNoAI": "No AI: Explicitly opt-out of AI training",
-                "CCSignalOpen": "Credit + Open: Attribution + keep derivatives open"
-            },
-            "x-enum-descriptions": [
-                "Credit: Allow AI use with attribution",
-                "Credit + Direct: Attribution + financial/in-kind support",
-                "Credit + Ecosystem: Attribution + contribute to commons",
-                "Credit + Open: Attribution + keep derivatives open",
-                "No AI: Explicitly opt-out of AI training"
-            ],
-            "x-enum-varnames": [
-                "CCSignalCredit",
-                "CCSignalDirect",
-                "CCSignalEcosystem",
-                "CCSignalOpen",
-                "CCSignalNoAI"
-            ]
-        },
-        "codeberg_org_algopatterns_server_algopatterns_strudels.CreateStrudelRequest": {
+        "codeberg_org_algopatterns_server_internal_websocket.RegionLocksPayload": {
             "type": "object",
-            "required": [
-                "code",
-                "title"
-            ],
-            "properties": {
-                "categories": {
-                    "description": "max 10 categories, each max 50 chars",
-                    "type": "array",
-                    "maxItems": 10,
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "cc_signal": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
-                },
-                "code": {
-                    "description": "1MB limit",
-                    "type": "string",
-                    "maxLength": 1048576
-                },
-                "conversation_history": {
-                    "description": "max 100 messages",
-                    "type": "array",
-                    "maxItems": 100,
-                    "items": {
-                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.Message"
-                    }
-                },
-                "description": {
-                    "type": "string",
-                    "maxLength": 2000
-                },
-                "forked_from": {
-                    "type": "string"
-                },
-                "is_public": {
-                    "type": "boolean"
-                },
-                "license": {
-                    "type": "string"
-                },
-                "tags": {
-                    "description": "max 20 tags, each max 50 chars",
+            "properties": {
+                "locks": {
                     "type": "array",
-                    "maxItems": 20,
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.RegionLock"
                     }
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.ResumeCompletePayload": {
+            "type": "object",
+            "properties": {
+                "replayed": {
+                    "description": "number of messages replayed",
+                    "type": "integer"
                 },
-                "title": {
-                    "type": "string",
-                    "maxLength": 200
+                "seq": {
+                    "description": "sequence number the client is now caught up to",
+                    "type": "integer"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_algopatterns_strudels.Strudel": {
+        "codeberg_org_algopatterns_server_internal_websocket.ResumePayload": {
             "type": "object",
             "properties": {
-                "ai_assist_count": {
+                "client_id": {
+                    "description": "client_id from the previous connection's session_state",
+                    "type": "string"
+                },
+                "last_seq": {
+                    "description": "last sequence number the client received",
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.SelectionRange": {
+            "type": "object",
+            "properties": {
+                "anchor_col": {
+                    "description": "0-indexed column number",
                     "type": "integer"
                 },
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "anchor_line": {
+                    "description": "1-indexed line number",
+                    "type": "integer"
                 },
-                "cc_signal": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                "head_col": {
+                    "type": "integer"
                 },
-                "code": {
+                "head_line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.SelectionUpdatePayload": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "description": "display name (added by backend)",
                     "type": "string"
                 },
-                "conversation_history": {
+                "ranges": {
+                    "description": "empty when nothing is selected",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.Message"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.SelectionRange"
                     }
                 },
-                "created_at": {
+                "role": {
+                    "description": "role (added by backend)",
                     "type": "string"
                 },
-                "description": {
+                "user_id": {
+                    "description": "user ID (added by backend)",
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.ServerShutdownPayload": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.SessionEndedPayload": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.SessionStateChatMessage": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
                     "type": "string"
                 },
-                "forked_from": {
+                "content": {
                     "type": "string"
                 },
-                "id": {
+                "display_name": {
                     "type": "string"
                 },
-                "is_public": {
-                    "type": "boolean"
+                "id": {
+                    "type": "string"
                 },
-                "license": {
+                "parent_message_id": {
                     "type": "string"
                 },
-                "tags": {
+                "reactions": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.SessionStateChatReaction"
                     }
                 },
-                "title": {
+                "timestamp": {
+                    "description": "Unix milliseconds",
+                    "type": "integer"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.SessionStateChatReaction": {
+            "type": "object",
+            "properties": {
+                "display_name": {
                     "type": "string"
                 },
-                "updated_at": {
+                "emoji": {
                     "type": "string"
                 },
                 "user_id": {
@@ -3117,194 +15441,178 @@ const docTemplate = `{
                 }
             }
         },
-        "codeberg_org_algopatterns_server_algopatterns_strudels.UpdateStrudelRequest": {
+        "codeberg_org_algopatterns_server_internal_websocket.SessionStateParticipant": {
             "type": "object",
             "properties": {
-                "categories": {
+                "connection_quality": {
+                    "description": "good, fair or poor",
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "rtt_ms": {
+                    "description": "last ping round trip, omitted until measured or for other instances",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "codeberg_org_algopatterns_server_internal_websocket.SessionStatePayload": {
+            "type": "object",
+            "properties": {
+                "chat_history": {
                     "type": "array",
-                    "maxItems": 10,
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.SessionStateChatMessage"
                     }
                 },
-                "cc_signal": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_algopatterns_strudels.CCSignal"
+                "chat_slow_mode_seconds": {
+                    "description": "0 when slow mode is off",
+                    "type": "integer"
+                },
+                "client_id": {
+                    "description": "pass back in resume after reconnecting",
+                    "type": "string"
                 },
                 "code": {
-                    "description": "1MB limit",
-                    "type": "string",
-                    "maxLength": 1048576
+                    "type": "string"
                 },
-                "conversation_history": {
+                "code_updates_per_second": {
+                    "description": "rate limit of code updates, omitted for audiences",
+                    "type": "integer"
+                },
+                "listener_count": {
+                    "description": "audience clients connected to this session",
+                    "type": "integer"
+                },
+                "muted": {
+                    "description": "the host muted this client in chat",
+                    "type": "boolean"
+                },
+                "participants": {
                     "type": "array",
-                    "maxItems": 100,
                     "items": {
-                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_agent.Message"
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.SessionStateParticipant"
                     }
                 },
-                "description": {
-                    "type": "string",
-                    "maxLength": 2000
+                "region_locks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.RegionLock"
+                    }
                 },
-                "is_public": {
-                    "type": "boolean"
+                "revision": {
+                    "type": "integer"
                 },
-                "license": {
+                "seq": {
+                    "description": "last sequence number broadcast to the session",
+                    "type": "integer"
+                },
+                "transport": {
+                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_websocket.TransportSyncPayload"
+                },
+                "your_display_name": {
                     "type": "string"
                 },
-                "tags": {
+                "your_permissions": {
                     "type": "array",
-                    "maxItems": 20,
                     "items": {
                         "type": "string"
                     }
                 },
-                "title": {
-                    "type": "string",
-                    "maxLength": 200
+                "your_role": {
+                    "type": "string"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_algopatterns_users.User": {
+        "codeberg_org_algopatterns_server_internal_websocket.SessionStats": {
             "type": "object",
             "properties": {
-                "ai_features_enabled": {
-                    "type": "boolean"
-                },
-                "avatar_url": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "provider": {
-                    "type": "string"
-                },
-                "training_consent": {
-                    "type": "boolean"
+                "clients": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "session_id": {
                     "type": "string"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_api_rest_pagination.Meta": {
+        "codeberg_org_algopatterns_server_internal_websocket.StopPayload": {
             "type": "object",
             "properties": {
-                "has_more": {
-                    "type": "boolean"
-                },
-                "limit": {
-                    "type": "integer"
-                },
-                "offset": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
+                "display_name": {
+                    "type": "string"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_internal_agent.Message": {
+        "codeberg_org_algopatterns_server_internal_websocket.TransportSyncPayload": {
             "type": "object",
             "properties": {
-                "content": {
-                    "description": "message content",
+                "cpm": {
+                    "type": "number"
+                },
+                "display_name": {
+                    "description": "who changed the transport",
                     "type": "string"
                 },
-                "is_code_response": {
-                    "description": "true if AI generated code",
+                "origin_cycle": {
+                    "type": "number"
+                },
+                "origin_time": {
+                    "type": "number"
+                },
+                "playing": {
                     "type": "boolean"
                 },
-                "role": {
-                    "description": "\"user\" or \"assistant\"",
-                    "type": "string"
+                "server_time": {
+                    "type": "number"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_internal_attribution.StrudelStats": {
+        "codeberg_org_algopatterns_server_internal_websocket.UndoRedoPayload": {
             "type": "object",
             "properties": {
-                "fork_count": {
-                    "type": "integer"
-                },
-                "last_used_at": {
-                    "type": "string"
-                },
-                "total_uses": {
-                    "type": "integer"
-                },
-                "unique_users": {
+                "revision": {
+                    "description": "latest revision the client has seen",
                     "type": "integer"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_internal_attribution.StrudelStatsResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.UnlockRegionPayload": {
             "type": "object",
             "properties": {
-                "recent_uses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_attribution.StrudelUse"
-                    }
-                },
-                "stats": {
-                    "$ref": "#/definitions/codeberg_org_algopatterns_server_internal_attribution.StrudelStats"
+                "lock_id": {
+                    "type": "string"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_internal_attribution.StrudelUse": {
+        "codeberg_org_algopatterns_server_internal_websocket.UserJoinedPayload": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "requesting_display_name": {
-                    "type": "string"
-                },
-                "requesting_user_id": {
+                "display_name": {
                     "type": "string"
                 },
-                "similarity_score": {
-                    "type": "number"
-                },
-                "target_strudel_id": {
+                "role": {
+                    "description": "\"host\", \"co-author\", \"viewer\"",
                     "type": "string"
                 },
-                "target_strudel_title": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "codeberg_org_algopatterns_server_internal_errors.ErrorResponse": {
+        "codeberg_org_algopatterns_server_internal_websocket.UserLeftPayload": {
             "type": "object",
             "properties": {
-                "details": {
-                    "description": "optional details (sanitized in production)",
-                    "type": "string"
-                },
-                "error": {
-                    "description": "error code (e.g., \"unauthorized\", \"not_found\")",
-                    "type": "string"
-                },
-                "message": {
-                    "description": "user-friendly message",
+                "display_name": {
                     "type": "string"
                 },
-                "request_id": {
-                    "description": "echoed from request for correlation",
+                "user_id": {
                     "type": "string"
                 }
             }
@@ -3323,7 +15631,7 @@ const docTemplate = `{
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
-	Host:             "algopatterns.dev",
+	Host:             "algopatterns.cc",
 	BasePath:         "",
 	Schemes:          []string{},
 	Title:            "Algopatterns API",
@@ -9,6 +9,7 @@ import (
 	"codeberg.org/algopatterns/server/internal/llm"
 	"codeberg.org/algopatterns/server/internal/retriever"
 	"codeberg.org/algopatterns/server/internal/strudel"
+	"codeberg.org/algopatterns/server/internal/tracing"
 )
 
 func New(ret Retriever, llmClient llm.LLM) *Agent {
@@ -33,6 +34,26 @@ func (a *Agent) SetValidator(v *strudel.Validator) {
 }
 
 func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	ctx, span := tracing.Start(ctx, "agent.Generate", tracing.Bool("agent.byok", req.CustomGenerator != nil))
+	defer span.End()
+
+	resp, err := a.generate(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(
+		tracing.Int("agent.docs_retrieved", resp.DocsRetrieved),
+		tracing.Int("agent.examples_retrieved", resp.ExamplesRetrieved),
+		tracing.Bool("agent.did_retry", resp.DidRetry),
+		tracing.Int("agent.repair_iterations", resp.RepairIterations),
+	)
+
+	return resp, nil
+}
+
+func (a *Agent) generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
 	textGenerator := llm.TextGenerator(a.generator)
 	isBYOK := req.CustomGenerator != nil
 
@@ -40,6 +61,18 @@ func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateRes
 		textGenerator = req.CustomGenerator
 	}
 
+	// answers to clarifying questions stand in for the original request
+	if req.Clarification != nil {
+		req.UserQuery = req.Clarification.Combine(req.UserQuery)
+	}
+
+	cacheKey, cacheable := a.responseCacheKey(req, textGenerator.Model())
+	if cacheable {
+		if cached := a.cachedResponse(ctx, cacheKey); cached != nil {
+			return cached, nil
+		}
+	}
+
 	// for byok users: skip AnalyzeQuery to save ~1-3s latency
 	// the main llm will naturally determine if it's a code request or question
 	var analysis *llm.QueryAnalysis
@@ -105,6 +138,8 @@ func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateRes
 		Docs:          docs,
 		Examples:      examples,
 		Conversations: req.ConversationHistory,
+		Summary:       req.ConversationSummary,
+		Lesson:        req.Lesson,
 		QueryAnalysis: analysis,
 		UsedRAGCache:  usedCache, // tell prompt builder to add "need docs" instruction
 	})
@@ -117,6 +152,8 @@ func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateRes
 
 	totalInputTokens := response.Usage.InputTokens
 	totalOutputTokens := response.Usage.OutputTokens
+	model := modelOf(textGenerator, response)
+	degraded := response.Fallback
 
 	// check if llm requested more documentation (only when using cached docs)
 	if usedCache {
@@ -159,6 +196,8 @@ func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateRes
 				Docs:          docs,
 				Examples:      examples,
 				Conversations: req.ConversationHistory,
+				Summary:       req.ConversationSummary,
+				Lesson:        req.Lesson,
 				QueryAnalysis: analysis,
 				UsedRAGCache:  false, // fresh docs, no need for "need docs" instruction
 			})
@@ -169,6 +208,8 @@ func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateRes
 			}
 			totalInputTokens += response.Usage.InputTokens
 			totalOutputTokens += response.Usage.OutputTokens
+			model = modelOf(textGenerator, response)
+			degraded = degraded || response.Fallback
 		}
 	}
 
@@ -191,6 +232,8 @@ func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateRes
 				content, isCode = analyzeResponse(retryResponse.Text)
 				totalInputTokens += retryResponse.Usage.InputTokens
 				totalOutputTokens += retryResponse.Usage.OutputTokens
+				model = modelOf(textGenerator, retryResponse)
+				degraded = degraded || retryResponse.Fallback
 				didRetry = true
 			}
 
@@ -198,6 +241,37 @@ func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateRes
 		}
 	}
 
+	// self-critique: lint the code and ask the model to fix what it finds
+	repairIterations := 0
+	for isCode && repairIterations < a.maxRepairs {
+		violations := critiqueViolations(content)
+		if len(violations) == 0 {
+			break
+		}
+
+		repairResponse, err := a.repairCode(
+			ctx, textGenerator, systemPrompt, req.UserQuery,
+			req.ConversationHistory, content, violations,
+		)
+		if err != nil {
+			log.Printf("self-critique repair failed: %v", err)
+			break
+		}
+
+		totalInputTokens += repairResponse.Usage.InputTokens
+		totalOutputTokens += repairResponse.Usage.OutputTokens
+		model = modelOf(textGenerator, repairResponse)
+		degraded = degraded || repairResponse.Fallback
+		repairIterations++
+
+		// keep the previous code if the model answered with prose
+		repaired, repairedIsCode := analyzeResponse(repairResponse.Text)
+		if !repairedIsCode || repaired == "" {
+			break
+		}
+		content = repaired
+	}
+
 	// build references for frontend display
 	strudelRefs := make([]StrudelReference, 0, len(examples))
 	for _, ex := range examples {
@@ -223,7 +297,11 @@ func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateRes
 		})
 	}
 
-	return &GenerateResponse{
+	// vague requests get clarifying questions, kept by the caller so the
+	// answer can be combined with the request
+	clarification := clarificationFor(req.UserQuery, analysis)
+
+	resp := &GenerateResponse{
 		Code:              content,
 		DocsRetrieved:     len(docs),
 		ExamplesRetrieved: len(examples),
@@ -231,20 +309,44 @@ func (a *Agent) Generate(ctx context.Context, req GenerateRequest) (*GenerateRes
 		Docs:              docs,
 		StrudelReferences: strudelRefs,
 		DocReferences:     docRefs,
-		Model:             textGenerator.Model(),
-		IsActionable:      true,
+		Model:             model,
+		IsActionable:      clarification == nil,
 		IsCodeResponse:    isCode,
+		Clarification:     clarification,
 		InputTokens:       totalInputTokens,
 		OutputTokens:      totalOutputTokens,
 		DidRetry:          didRetry,
 		ValidationError:   validationError,
-	}, nil
+		RepairIterations:  repairIterations,
+		Degraded:          degraded,
+	}
+	if clarification != nil {
+		resp.ClarifyingQuestions = clarification.Questions
+	}
+
+	// answers built on a session's cached retrieval belong to that
+	// conversation, and fallback answers shouldn't outlive the outage
+	if cacheable && !usedCache && !degraded {
+		a.cacheResponse(ctx, cacheKey, resp)
+	}
+
+	return resp, nil
 }
 
 // generates code with streaming response chunks.
 // the onEvent callback is called for each chunk and final metadata.
 // note: streaming skips validation retry to maintain real-time delivery.
 func (a *Agent) GenerateStream(ctx context.Context, req GenerateRequest, onEvent func(event StreamEvent) error) error {
+	ctx, span := tracing.Start(ctx, "agent.GenerateStream", tracing.Bool("agent.byok", req.CustomGenerator != nil))
+	defer span.End()
+
+	err := a.generateStream(ctx, req, onEvent)
+	span.RecordError(err)
+
+	return err
+}
+
+func (a *Agent) generateStream(ctx context.Context, req GenerateRequest, onEvent func(event StreamEvent) error) error {
 	textGenerator := llm.TextGenerator(a.generator)
 	isBYOK := req.CustomGenerator != nil
 
@@ -252,6 +354,18 @@ func (a *Agent) GenerateStream(ctx context.Context, req GenerateRequest, onEvent
 		textGenerator = req.CustomGenerator
 	}
 
+	// answers to clarifying questions stand in for the original request
+	if req.Clarification != nil {
+		req.UserQuery = req.Clarification.Combine(req.UserQuery)
+	}
+
+	cacheKey, cacheable := a.responseCacheKey(req, textGenerator.Model())
+	if cacheable {
+		if cached := a.cachedResponse(ctx, cacheKey); cached != nil {
+			return replayCachedResponse(cached, onEvent)
+		}
+	}
+
 	// rag retrieval with caching (same as non-streaming)
 	var docs []retriever.SearchResult
 	var examples []retriever.ExampleResult
@@ -322,6 +436,8 @@ func (a *Agent) GenerateStream(ctx context.Context, req GenerateRequest, onEvent
 	// send refs event first
 	if err := onEvent(StreamEvent{
 		Type:              "refs",
+		DocsRetrieved:     len(docs),
+		ExamplesRetrieved: len(examples),
 		StrudelReferences: strudelRefs,
 		DocReferences:     docRefs,
 	}); err != nil {
@@ -334,6 +450,8 @@ func (a *Agent) GenerateStream(ctx context.Context, req GenerateRequest, onEvent
 		Docs:          docs,
 		Examples:      examples,
 		Conversations: req.ConversationHistory,
+		Summary:       req.ConversationSummary,
+		Lesson:        req.Lesson,
 		UsedRAGCache:  usedCache,
 	})
 
@@ -370,15 +488,62 @@ func (a *Agent) GenerateStream(ctx context.Context, req GenerateRequest, onEvent
 	// analyze final response
 	content, isCode := analyzeResponse(response.Text)
 
+	model := modelOf(textGenerator, response)
+
+	if cacheable && !usedCache && !response.Fallback {
+		a.cacheResponse(ctx, cacheKey, &GenerateResponse{
+			Code:              content,
+			Docs:              docs,
+			Examples:          examples,
+			StrudelReferences: strudelRefs,
+			DocReferences:     docRefs,
+			Model:             model,
+			IsCodeResponse:    isCode,
+		})
+	}
+
 	// send done event with final metadata
 	return onEvent(StreamEvent{
 		Type:              "done",
 		Content:           content, // processed content (extracted from markdown if needed)
-		Model:             textGenerator.Model(),
+		Model:             model,
 		IsCodeResponse:    isCode,
+		Degraded:          response.Fallback,
 		InputTokens:       response.Usage.InputTokens,
 		OutputTokens:      response.Usage.OutputTokens,
+		DocsRetrieved:     len(docs),
+		ExamplesRetrieved: len(examples),
 		StrudelReferences: strudelRefs,
 		DocReferences:     docRefs,
 	})
 }
+
+// sends a cached response as a stream: references, the whole text as one
+// chunk, then the done event
+func replayCachedResponse(resp *GenerateResponse, onEvent func(event StreamEvent) error) error {
+	if err := onEvent(StreamEvent{
+		Type:              "refs",
+		DocsRetrieved:     resp.DocsRetrieved,
+		ExamplesRetrieved: resp.ExamplesRetrieved,
+		StrudelReferences: resp.StrudelReferences,
+		DocReferences:     resp.DocReferences,
+	}); err != nil {
+		return err
+	}
+
+	if err := onEvent(StreamEvent{Type: "chunk", Content: resp.Code}); err != nil {
+		return err
+	}
+
+	return onEvent(StreamEvent{
+		Type:              "done",
+		Content:           resp.Code,
+		Model:             resp.Model,
+		IsCodeResponse:    resp.IsCodeResponse,
+		DocsRetrieved:     resp.DocsRetrieved,
+		ExamplesRetrieved: resp.ExamplesRetrieved,
+		StrudelReferences: resp.StrudelReferences,
+		DocReferences:     resp.DocReferences,
+		Cached:            true,
+	})
+}
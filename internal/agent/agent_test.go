@@ -11,6 +11,7 @@ import (
 // implements llm.LLM for testing
 type mockLLM struct {
 	generateTextFunc func(ctx context.Context, req llm.TextGenerationRequest) (*llm.TextGenerationResponse, error)
+	analyzeQueryFunc func(ctx context.Context, query string) (*llm.QueryAnalysis, error)
 	model            string
 }
 
@@ -52,7 +53,11 @@ func (m *mockLLM) TransformQuery(_ context.Context, query string) (string, error
 	return query + " expanded", nil
 }
 
-func (m *mockLLM) AnalyzeQuery(_ context.Context, query string) (*llm.QueryAnalysis, error) {
+func (m *mockLLM) AnalyzeQuery(ctx context.Context, query string) (*llm.QueryAnalysis, error) {
+	if m.analyzeQueryFunc != nil {
+		return m.analyzeQueryFunc(ctx, query)
+	}
+
 	return &llm.QueryAnalysis{
 		TransformedQuery:    query + " expanded",
 		IsActionable:        true,
@@ -187,6 +192,40 @@ func TestGenerateCode(t *testing.T) {
 	}
 }
 
+func TestGenerateStreamEvents(t *testing.T) {
+	agent := New(&mockRetriever{}, &mockLLM{})
+
+	var events []StreamEvent
+
+	err := agent.GenerateStream(context.Background(), GenerateRequest{
+		UserQuery: "make a drum beat",
+	}, func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("expected refs, chunk and done events, got %d", len(events))
+	}
+
+	refs, chunk, done := events[0], events[1], events[2]
+
+	if refs.Type != "refs" || refs.DocsRetrieved != 1 || refs.ExamplesRetrieved != 1 {
+		t.Errorf("unexpected refs event: %+v", refs)
+	}
+
+	if chunk.Type != "chunk" || chunk.Content != "sound(\"bd\").fast(4)" {
+		t.Errorf("unexpected chunk event: %+v", chunk)
+	}
+
+	if done.Type != "done" || done.DocsRetrieved != 1 || len(done.StrudelReferences) != 1 {
+		t.Errorf("unexpected done event: %+v", done)
+	}
+}
+
 func TestGenerateCodeWithConversationHistory(t *testing.T) {
 	ctx := context.Background()
 
@@ -297,6 +336,33 @@ func TestBuildSystemPrompt(t *testing.T) {
 	}
 }
 
+func TestBuildSystemPromptWithLesson(t *testing.T) {
+	ctx := SystemPromptContext{
+		Lesson: &LessonContext{
+			Lesson:    "Rhythm",
+			Step:      2,
+			StepCount: 4,
+			StepTitle: "Euclidean rhythms",
+			Example:   "s(\"bd(3,8)\")",
+			Missing:   []string{"use euclid"},
+		},
+	}
+
+	prompt := buildSystemPrompt(ctx)
+
+	if !containsSubstr(prompt, "LESSON MODE") {
+		t.Error("expected prompt to include lesson section")
+	}
+
+	if !containsSubstr(prompt, "step 2 of 4: Euclidean rhythms") || !containsSubstr(prompt, "- use euclid") {
+		t.Errorf("expected lesson section to describe the step, got: %s", prompt)
+	}
+
+	if containsSubstr(buildSystemPrompt(SystemPromptContext{}), "LESSON MODE") {
+		t.Error("expected no lesson section without a lesson")
+	}
+}
+
 func containsSubstr(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -0,0 +1,162 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+func ResponseCacheConfigFromEnv() ResponseCacheConfig {
+	config := ResponseCacheConfig{
+		TTL:         defaultResponseCacheTTL,
+		BypassTiers: []string{"payg"},
+	}
+
+	if ttl, err := time.ParseDuration(os.Getenv("AGENT_CACHE_TTL")); err == nil {
+		config.TTL = ttl
+	}
+
+	if tiers, ok := os.LookupEnv("AGENT_CACHE_BYPASS_TIERS"); ok {
+		config.BypassTiers = nil
+		for _, tier := range strings.Split(tiers, ",") {
+			if tier = strings.TrimSpace(tier); tier != "" {
+				config.BypassTiers = append(config.BypassTiers, tier)
+			}
+		}
+	}
+
+	return config
+}
+
+func NewRedisResponseCache(client redis.UniversalClient, ttl time.Duration) *RedisResponseCache {
+	return &RedisResponseCache{client: client, ttl: ttl}
+}
+
+func (c *RedisResponseCache) GetResponse(ctx context.Context, key string) (*CachedResponse, error) {
+	data, err := c.client.Get(ctx, fmt.Sprintf(keyResponseCache, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var cached CachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+
+	return &cached, nil
+}
+
+func (c *RedisResponseCache) SetResponse(ctx context.Context, key string, resp *CachedResponse) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, fmt.Sprintf(keyResponseCache, key), data, c.ttl).Err()
+}
+
+// caches responses to first prompts. tiers listed in bypassTiers always get
+// a fresh generation. a nil cache disables caching.
+func (a *Agent) SetResponseCache(cache ResponseCache, bypassTiers ...string) {
+	a.responseCache = cache
+	a.cacheBypassTiers = make(map[string]bool, len(bypassTiers))
+
+	for _, tier := range bypassTiers {
+		a.cacheBypassTiers[tier] = true
+	}
+}
+
+// returns the cache key for a request, or false if its response shouldn't be
+// cached. follow-ups depend on the conversation, so only first prompts are
+func (a *Agent) responseCacheKey(req GenerateRequest, model string) (string, bool) {
+	if a.responseCache == nil {
+		return "", false
+	}
+
+	if len(req.ConversationHistory) > 0 || req.ConversationSummary != "" || req.Clarification != nil || req.Lesson != nil || a.cacheBypassTiers[req.Tier] {
+		metrics.AgentResponseCache.Inc("bypass")
+		return "", false
+	}
+
+	hash := sha256.New()
+	for _, part := range []string{model, normalizePrompt(req.UserQuery), collapseWhitespace(req.EditorState)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
+
+// returns the cached response for key, or nil on a miss. lookups fail open
+func (a *Agent) cachedResponse(ctx context.Context, key string) *GenerateResponse {
+	cached, err := a.responseCache.GetResponse(ctx, key)
+	if err != nil {
+		logger.Warn("agent response cache lookup failed", "error", err)
+	}
+
+	if cached == nil {
+		metrics.AgentResponseCache.Inc("miss")
+		return nil
+	}
+
+	metrics.AgentResponseCache.Inc("hit")
+
+	return &GenerateResponse{
+		Code:              cached.Code,
+		DocsRetrieved:     len(cached.Docs),
+		ExamplesRetrieved: len(cached.Examples),
+		Examples:          cached.Examples,
+		Docs:              cached.Docs,
+		StrudelReferences: cached.StrudelReferences,
+		DocReferences:     cached.DocReferences,
+		Model:             cached.Model,
+		IsActionable:      true,
+		IsCodeResponse:    cached.IsCodeResponse,
+		Cached:            true,
+	}
+}
+
+// caches a finished generation. responses that failed validation aren't kept
+func (a *Agent) cacheResponse(ctx context.Context, key string, resp *GenerateResponse) {
+	if resp.Code == "" || resp.ValidationError != "" || resp.Clarification != nil {
+		return
+	}
+
+	err := a.responseCache.SetResponse(context.WithoutCancel(ctx), key, &CachedResponse{
+		Code:              resp.Code,
+		IsCodeResponse:    resp.IsCodeResponse,
+		Model:             resp.Model,
+		Docs:              resp.Docs,
+		Examples:          resp.Examples,
+		StrudelReferences: resp.StrudelReferences,
+		DocReferences:     resp.DocReferences,
+	})
+	if err != nil {
+		logger.Warn("agent response cache store failed", "error", err)
+	}
+}
+
+// lowercases a prompt and drops whitespace and trailing punctuation
+// differences, so "Add a kick drum!" and "add a  kick drum" share a key
+func normalizePrompt(prompt string) string {
+	return strings.TrimRight(collapseWhitespace(strings.ToLower(prompt)), ".!? ")
+}
+
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+)
+
+// implements ResponseCache in memory for testing
+type memoryResponseCache struct {
+	entries map[string]*CachedResponse
+}
+
+func (m *memoryResponseCache) GetResponse(_ context.Context, key string) (*CachedResponse, error) {
+	return m.entries[key], nil
+}
+
+func (m *memoryResponseCache) SetResponse(_ context.Context, key string, resp *CachedResponse) error {
+	m.entries[key] = resp
+	return nil
+}
+
+func TestGenerateResponseCache(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	mockGen := &mockLLM{
+		generateTextFunc: func(_ context.Context, _ llm.TextGenerationRequest) (*llm.TextGenerationResponse, error) {
+			calls++
+			return &llm.TextGenerationResponse{
+				Text:  "sound(\"bd*4\")",
+				Usage: llm.Usage{InputTokens: 100, OutputTokens: 10},
+			}, nil
+		},
+	}
+
+	agent := New(&mockRetriever{}, mockGen)
+	agent.SetResponseCache(&memoryResponseCache{entries: map[string]*CachedResponse{}}, "payg")
+
+	first, err := agent.Generate(ctx, GenerateRequest{UserQuery: "Make a drum beat"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if first.Cached {
+		t.Error("expected first response not to be cached")
+	}
+
+	second, err := agent.Generate(ctx, GenerateRequest{UserQuery: "  make a   drum beat!"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !second.Cached {
+		t.Error("expected second response to be served from the cache")
+	}
+	if second.Code != first.Code {
+		t.Errorf("expected cached code %q, got %q", first.Code, second.Code)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 llm call, got %d", calls)
+	}
+
+	// bypass tiers always reach the model
+	if _, err := agent.Generate(ctx, GenerateRequest{UserQuery: "make a drum beat", Tier: "payg"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected bypass tier to call the llm, got %d calls", calls)
+	}
+}
+
+func TestNormalizePrompt(t *testing.T) {
+	tests := map[string]string{
+		"Make a drum beat":         "make a drum beat",
+		"  make   a drum\tbeat!! ": "make a drum beat",
+		"add reverb?":              "add reverb",
+	}
+
+	for input, expected := range tests {
+		if got := normalizePrompt(input); got != expected {
+			t.Errorf("normalizePrompt(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+)
+
+// the request to generate for once clarifying questions are answered: the
+// original query, what was already clear, the questions and the answer
+func (c *Clarification) Combine(answer string) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Original request: %s\n", c.Query)
+
+	if len(c.ConcreteRequests) > 0 {
+		builder.WriteString("\nAlready clear:\n")
+		for _, request := range c.ConcreteRequests {
+			fmt.Fprintf(&builder, "- %s\n", request)
+		}
+	}
+
+	builder.WriteString("\nYou asked:\n")
+	for _, question := range c.Questions {
+		fmt.Fprintf(&builder, "- %s\n", question)
+	}
+
+	fmt.Fprintf(&builder, "\nAnswer: %s", answer)
+
+	return builder.String()
+}
+
+// the clarification to ask for, or nil when the query can be answered as is
+func clarificationFor(query string, analysis *llm.QueryAnalysis) *Clarification {
+	if analysis == nil || analysis.IsActionable || len(analysis.ClarifyingQuestions) == 0 {
+		return nil
+	}
+
+	return &Clarification{
+		Query:            query,
+		ConcreteRequests: analysis.ConcreteRequests,
+		Questions:        analysis.ClarifyingQuestions,
+	}
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+)
+
+func TestClarificationCombine(t *testing.T) {
+	clarification := &Clarification{
+		Query:            "make something chill",
+		ConcreteRequests: []string{"relaxed tempo"},
+		Questions:        []string{"which genre?", "drums or only synths?"},
+	}
+
+	expected := "Original request: make something chill\n" +
+		"\nAlready clear:\n- relaxed tempo\n" +
+		"\nYou asked:\n- which genre?\n- drums or only synths?\n" +
+		"\nAnswer: lofi hip hop with soft drums"
+
+	if combined := clarification.Combine("lofi hip hop with soft drums"); combined != expected {
+		t.Errorf("Combine() = %q, expected %q", combined, expected)
+	}
+}
+
+func TestGenerateAsksClarifyingQuestions(t *testing.T) {
+	gen := &mockLLM{
+		analyzeQueryFunc: func(_ context.Context, query string) (*llm.QueryAnalysis, error) {
+			return &llm.QueryAnalysis{
+				IsActionable:        false,
+				ConcreteRequests:    []string{"some music"},
+				ClarifyingQuestions: []string{"which genre?"},
+			}, nil
+		},
+	}
+
+	resp, err := New(&mockRetriever{}, gen).Generate(context.Background(), GenerateRequest{UserQuery: "make music"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.IsActionable || len(resp.ClarifyingQuestions) != 1 {
+		t.Errorf("expected a non-actionable response with questions, got %+v", resp)
+	}
+
+	if resp.Clarification == nil || resp.Clarification.Query != "make music" || resp.Clarification.ConcreteRequests[0] != "some music" {
+		t.Errorf("unexpected clarification: %+v", resp.Clarification)
+	}
+}
+
+func TestGenerateCombinesAnswerWithClarification(t *testing.T) {
+	var analyzed string
+	gen, requests := scriptedLLM("s(\"bd*4\")")
+	gen.analyzeQueryFunc = func(_ context.Context, query string) (*llm.QueryAnalysis, error) {
+		analyzed = query
+		return &llm.QueryAnalysis{IsActionable: true, IsCodeRequest: true}, nil
+	}
+
+	resp, err := New(&mockRetriever{}, gen).Generate(context.Background(), GenerateRequest{
+		UserQuery: "techno",
+		Clarification: &Clarification{
+			Query:     "make music",
+			Questions: []string{"which genre?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if !resp.IsActionable || resp.Clarification != nil {
+		t.Errorf("expected an actionable response, got %+v", resp)
+	}
+
+	messages := (*requests)[0].Messages
+	query := messages[len(messages)-1].Content
+	if query != analyzed || !strings.Contains(query, "Original request: make music") || !strings.HasSuffix(query, "Answer: techno") {
+		t.Errorf("expected the combined query to be analyzed and sent, got %q", query)
+	}
+}
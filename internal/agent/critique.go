@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/strudel"
+)
+
+// repair passes after generation, read from AGENT_MAX_REPAIRS (0 disables)
+func MaxRepairsFromEnv() int {
+	n, err := strconv.Atoi(os.Getenv("AGENT_MAX_REPAIRS"))
+	if err != nil || n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+// enables the self-critique pass: generated code is linted and sent back to
+// the model to fix, at most maxRepairs times
+func (a *Agent) SetSelfCritique(maxRepairs int) {
+	a.maxRepairs = maxRepairs
+}
+
+// lint findings the model is asked to fix. other warnings, like unknown
+// sounds, are often intended (custom samples) and are left alone
+func critiqueViolations(code string) []strudel.Diagnostic {
+	var violations []strudel.Diagnostic
+
+	for _, d := range strudel.Lint(code) {
+		if d.Severity == strudel.SeverityError || d.Rule == strudel.RuleUnknownFunction || d.Rule == strudel.RuleMixedStack {
+			violations = append(violations, d)
+		}
+	}
+
+	return violations
+}
+
+// asks the model to fix the lint violations in its code
+func (a *Agent) repairCode(
+	ctx context.Context,
+	generator llm.TextGenerator,
+	systemPrompt, userQuery string,
+	history []Message,
+	code string,
+	violations []strudel.Diagnostic,
+) (*llm.TextGenerationResponse, error) {
+	repairHistory := make([]Message, 0, len(history)+2)
+	repairHistory = append(repairHistory, history...)
+	repairHistory = append(repairHistory, Message{
+		Role:    "user",
+		Content: userQuery,
+	})
+
+	repairHistory = append(repairHistory, Message{
+		Role:    "assistant",
+		Content: code,
+	})
+
+	var problems strings.Builder
+	for _, v := range violations {
+		problems.WriteString(fmt.Sprintf("- line %d, column %d: %s\n", v.Line, v.Column, v.Message))
+	}
+
+	repairPrompt := fmt.Sprintf(`
+	review of the code you generated found these problems:
+	%s
+	code: %s
+	please fix them and return only the corrected strudel code.
+	do not include any explanation or line numbers.`, problems.String(), addLineNumbers(code))
+
+	return a.callGeneratorWithClient(ctx, generator, systemPrompt, repairPrompt, repairHistory)
+}
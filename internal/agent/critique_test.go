@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/strudel"
+)
+
+// answers with each of texts in turn, repeating the last
+func scriptedLLM(texts ...string) (*mockLLM, *[]llm.TextGenerationRequest) {
+	var requests []llm.TextGenerationRequest
+
+	return &mockLLM{
+		generateTextFunc: func(_ context.Context, req llm.TextGenerationRequest) (*llm.TextGenerationResponse, error) {
+			text := texts[min(len(requests), len(texts)-1)]
+			requests = append(requests, req)
+
+			return &llm.TextGenerationResponse{
+				Text:  text,
+				Usage: llm.Usage{InputTokens: 100, OutputTokens: 10},
+			}, nil
+		},
+	}, &requests
+}
+
+func TestCritiqueViolations(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  string
+		rules []string
+	}{
+		{
+			name: "clean code",
+			code: "s(\"bd*4\").bank(\"RolandTR909\")",
+		},
+		{
+			name:  "unknown function",
+			code:  "s(\"bd*4\").fsat(2)",
+			rules: []string{strudel.RuleUnknownFunction},
+		},
+		{
+			name:  "drums and notes in one stack",
+			code:  "stack(s(\"bd*4\"), note(\"c1\").sound(\"sawtooth\"))",
+			rules: []string{strudel.RuleMixedStack},
+		},
+		{
+			name: "unknown sounds are left alone",
+			code: "s(\"kick\")",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := critiqueViolations(tt.code)
+
+			if len(violations) != len(tt.rules) {
+				t.Fatalf("critiqueViolations() returned %d violations, expected %d: %+v", len(violations), len(tt.rules), violations)
+			}
+
+			for i, rule := range tt.rules {
+				if violations[i].Rule != rule {
+					t.Errorf("violation %d rule = %q, expected %q", i, violations[i].Rule, rule)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateRepairsLintViolations(t *testing.T) {
+	gen, requests := scriptedLLM("s(\"bd*4\").fsat(2)", "s(\"bd*4\").fast(2)")
+
+	agent := New(&mockRetriever{}, gen)
+	agent.SetSelfCritique(2)
+
+	resp, err := agent.Generate(context.Background(), GenerateRequest{UserQuery: "make a drum beat"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.Code != "s(\"bd*4\").fast(2)" {
+		t.Errorf("unexpected code: %s", resp.Code)
+	}
+
+	if resp.RepairIterations != 1 {
+		t.Errorf("expected 1 repair iteration, got %d", resp.RepairIterations)
+	}
+
+	if resp.InputTokens != 200 || resp.OutputTokens != 20 {
+		t.Errorf("expected tokens of both calls, got %d in and %d out", resp.InputTokens, resp.OutputTokens)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("expected 2 generator calls, got %d", len(*requests))
+	}
+
+	repair := (*requests)[1].Messages
+	if prompt := repair[len(repair)-1].Content; !strings.Contains(prompt, "unknown function \"fsat\"") {
+		t.Errorf("repair prompt doesn't name the violation: %s", prompt)
+	}
+}
+
+func TestGenerateStopsAfterMaxRepairs(t *testing.T) {
+	gen, requests := scriptedLLM("s(\"bd*4\").fsat(2)")
+
+	agent := New(&mockRetriever{}, gen)
+	agent.SetSelfCritique(2)
+
+	resp, err := agent.Generate(context.Background(), GenerateRequest{UserQuery: "make a drum beat"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.RepairIterations != 2 {
+		t.Errorf("expected 2 repair iterations, got %d", resp.RepairIterations)
+	}
+
+	if len(*requests) != 3 {
+		t.Errorf("expected 3 generator calls, got %d", len(*requests))
+	}
+}
+
+func TestGenerateWithoutSelfCritique(t *testing.T) {
+	gen, requests := scriptedLLM("s(\"bd*4\").fsat(2)")
+
+	resp, err := New(&mockRetriever{}, gen).Generate(context.Background(), GenerateRequest{UserQuery: "make a drum beat"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if resp.RepairIterations != 0 || len(*requests) != 1 {
+		t.Errorf("expected no repairs, got %d iterations and %d calls", resp.RepairIterations, len(*requests))
+	}
+}
@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+const (
+	defaultHistoryTokens = 6000
+
+	// share of the budget kept for recent messages, the rest holds the summary
+	recentHistoryShare = 0.75
+)
+
+const summarySystemPrompt = `You keep a running summary of a conversation between a musician and an assistant that writes Strudel live-coding patterns.
+Merge the new messages into the existing summary. Keep what the user asked for, their preferences (tempo, key, sounds, style),
+decisions made along the way and what the latest code does. Leave out greetings and anything superseded later.
+Reply with the summary only, in plain prose, in at most %d words.`
+
+// token budget for conversation history, read from AGENT_HISTORY_TOKENS
+func HistoryTokensFromEnv() int {
+	n, err := strconv.Atoi(os.Getenv("AGENT_HISTORY_TOKENS"))
+	if err != nil || n <= 0 {
+		return defaultHistoryTokens
+	}
+
+	return n
+}
+
+func NewHistoryCompactor(summarizer llm.TextGenerator, budget int) *HistoryCompactor {
+	return &HistoryCompactor{summarizer: summarizer, budget: budget}
+}
+
+// sets how histories that outgrow the token budget are condensed
+func (a *Agent) SetHistoryCompactor(c *HistoryCompactor) {
+	a.historyCompactor = c
+}
+
+// fits summary and history into the token budget. without a compactor the
+// history is returned unchanged
+func (a *Agent) CompactHistory(ctx context.Context, summary string, history []Message) *CompactedHistory {
+	if a.historyCompactor == nil {
+		return &CompactedHistory{Summary: summary, Recent: history}
+	}
+
+	return a.historyCompactor.Compact(ctx, summary, history)
+}
+
+// keeps the newest messages that fit the budget and folds the older ones
+// into the summary. if summarizing fails, or there is no summarizer, the
+// older messages are dropped and the summary is left as it was
+func (c *HistoryCompactor) Compact(ctx context.Context, summary string, history []Message) *CompactedHistory {
+	total := estimateTokens(summary)
+	for _, msg := range history {
+		total += estimateTokens(msg.Content)
+	}
+
+	if total <= c.budget {
+		return &CompactedHistory{Summary: summary, Recent: history}
+	}
+
+	split := recentHistoryStart(history, int(float64(c.budget)*recentHistoryShare))
+	compacted := &CompactedHistory{Summary: summary, Recent: history[split:]}
+
+	if c.summarizer == nil || split == 0 {
+		return compacted
+	}
+
+	folded, err := c.summarize(ctx, summary, history[:split])
+	if err != nil {
+		logger.Warn("conversation summary failed, dropping older messages", "error", err, "messages", split)
+		return compacted
+	}
+
+	compacted.Summary = folded
+	compacted.Folded = split
+
+	return compacted
+}
+
+// asks the summarizer to merge older messages into the summary
+func (c *HistoryCompactor) summarize(ctx context.Context, summary string, older []Message) (string, error) {
+	summaryTokens := c.budget - int(float64(c.budget)*recentHistoryShare)
+
+	var prompt strings.Builder
+	if summary != "" {
+		fmt.Fprintf(&prompt, "EXISTING SUMMARY:\n%s\n\n", summary)
+	}
+
+	prompt.WriteString("NEW MESSAGES:\n")
+	for _, msg := range older {
+		fmt.Fprintf(&prompt, "%s: %s\n\n", msg.Role, msg.Content)
+	}
+
+	resp, err := c.summarizer.GenerateText(ctx, llm.TextGenerationRequest{
+		SystemPrompt: fmt.Sprintf(summarySystemPrompt, summaryTokens*3/4), // about 0.75 words per token
+		Messages:     []llm.Message{{Role: "user", Content: prompt.String()}},
+		MaxTokens:    summaryTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	folded := strings.TrimSpace(resp.Text)
+	if folded == "" {
+		return "", fmt.Errorf("summarizer returned an empty summary")
+	}
+
+	return folded, nil
+}
+
+// index of the oldest message in the newest run that fits within tokens.
+// the last message is always kept
+func recentHistoryStart(history []Message, tokens int) int {
+	used := 0
+
+	for i := len(history) - 1; i >= 0; i-- {
+		used += estimateTokens(history[i].Content)
+		if used > tokens && i < len(history)-1 {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// rough token count, about four characters per token
+func estimateTokens(text string) int {
+	return len(text) / 4
+}
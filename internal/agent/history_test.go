@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+)
+
+// message of about tokens estimated tokens
+func sizedMessage(role string, tokens int) Message {
+	return Message{Role: role, Content: strings.Repeat("abcd", tokens)}
+}
+
+func TestCompactKeepsHistoryWithinBudget(t *testing.T) {
+	gen, requests := scriptedLLM("unused")
+	history := []Message{sizedMessage("user", 10), sizedMessage("assistant", 10)}
+
+	compacted := NewHistoryCompactor(gen, 100).Compact(context.Background(), "earlier", history)
+
+	if compacted.Summary != "earlier" || len(compacted.Recent) != 2 || compacted.Folded != 0 {
+		t.Errorf("expected history unchanged, got %+v", compacted)
+	}
+
+	if len(*requests) != 0 {
+		t.Errorf("expected no summarizer calls, got %d", len(*requests))
+	}
+}
+
+func TestCompactFoldsOlderMessages(t *testing.T) {
+	gen, requests := scriptedLLM("  user wants a techno beat at 130 bpm  ")
+	history := []Message{
+		sizedMessage("user", 40),
+		sizedMessage("assistant", 40),
+		sizedMessage("user", 30),
+		sizedMessage("assistant", 30),
+	}
+
+	// 75 of the 100 tokens go to recent messages, which fits the last two
+	compacted := NewHistoryCompactor(gen, 100).Compact(context.Background(), "user is new to strudel", history)
+
+	if compacted.Summary != "user wants a techno beat at 130 bpm" {
+		t.Errorf("unexpected summary: %q", compacted.Summary)
+	}
+
+	if compacted.Folded != 2 || len(compacted.Recent) != 2 {
+		t.Fatalf("expected 2 folded and 2 recent messages, got %d and %d", compacted.Folded, len(compacted.Recent))
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("expected 1 summarizer call, got %d", len(*requests))
+	}
+
+	prompt := (*requests)[0].Messages[0].Content
+	if !strings.Contains(prompt, "user is new to strudel") || strings.Count(prompt, "abcd") != 80 {
+		t.Errorf("summary prompt should hold the old summary and the folded messages: %s", prompt)
+	}
+}
+
+func TestCompactKeepsLatestMessageOverBudget(t *testing.T) {
+	history := []Message{sizedMessage("user", 10), sizedMessage("assistant", 500)}
+
+	compacted := NewHistoryCompactor(nil, 100).Compact(context.Background(), "", history)
+
+	if len(compacted.Recent) != 1 || compacted.Recent[0].Role != "assistant" {
+		t.Errorf("expected only the latest message, got %+v", compacted.Recent)
+	}
+}
+
+func TestCompactDropsOlderMessagesWhenSummaryFails(t *testing.T) {
+	gen := &mockLLM{
+		generateTextFunc: func(context.Context, llm.TextGenerationRequest) (*llm.TextGenerationResponse, error) {
+			return nil, errors.New("provider unavailable")
+		},
+	}
+	history := []Message{sizedMessage("user", 80), sizedMessage("assistant", 40)}
+
+	compacted := NewHistoryCompactor(gen, 100).Compact(context.Background(), "earlier", history)
+
+	if compacted.Summary != "earlier" || compacted.Folded != 0 {
+		t.Errorf("expected the summary to be left as it was, got %+v", compacted)
+	}
+
+	if len(compacted.Recent) != 1 {
+		t.Errorf("expected the older message to be dropped, got %d recent", len(compacted.Recent))
+	}
+}
+
+func TestAgentWithoutCompactorKeepsHistory(t *testing.T) {
+	history := []Message{sizedMessage("user", 5000)}
+
+	compacted := New(&mockRetriever{}, &mockLLM{}).CompactHistory(context.Background(), "", history)
+
+	if len(compacted.Recent) != 1 || compacted.Folded != 0 {
+		t.Errorf("expected history unchanged, got %+v", compacted)
+	}
+}
@@ -15,6 +15,8 @@ type SystemPromptContext struct {
 	Docs          []retriever.SearchResult
 	Examples      []retriever.ExampleResult
 	Conversations []Message
+	Summary       string             // rolling summary of older messages
+	Lesson        *LessonContext     // optional: lesson step to teach toward
 	QueryAnalysis *llm.QueryAnalysis // optional: helps generator tailor response
 	UsedRAGCache  bool               // if true, add instruction for requesting more docs
 }
@@ -104,7 +106,25 @@ func buildSystemPrompt(ctx SystemPromptContext) string {
 		}
 	}
 
-	// section 5: query context (if available)
+	// section 5: summary of the conversation's older messages (if any)
+	if ctx.Summary != "" {
+		builder.WriteString("═══════════════════════════════════════════════════════════\n")
+		builder.WriteString("EARLIER IN THIS CONVERSATION (SUMMARY)\n")
+		builder.WriteString("═══════════════════════════════════════════════════════════\n\n")
+		builder.WriteString(ctx.Summary)
+		builder.WriteString("\n\n")
+	}
+
+	// section 6: lesson mode (if the session is working through a lesson)
+	if ctx.Lesson != nil {
+		builder.WriteString("═══════════════════════════════════════════════════════════\n")
+		builder.WriteString("LESSON MODE\n")
+		builder.WriteString("═══════════════════════════════════════════════════════════\n\n")
+		builder.WriteString(buildLessonSection(ctx.Lesson))
+		builder.WriteString("\n")
+	}
+
+	// section 7: query context (if available)
 	if ctx.QueryAnalysis != nil {
 		builder.WriteString("═══════════════════════════════════════════════════════════\n")
 		builder.WriteString("QUERY CONTEXT\n")
@@ -126,13 +146,13 @@ func buildSystemPrompt(ctx SystemPromptContext) string {
 		builder.WriteString("\n")
 	}
 
-	// section 6: instructions
+	// section 8: instructions
 	builder.WriteString("═══════════════════════════════════════════════════════════\n")
 	builder.WriteString("INSTRUCTIONS\n")
 	builder.WriteString("═══════════════════════════════════════════════════════════\n\n")
 	builder.WriteString(getInstructions())
 
-	// section 7: rag cache instruction (only when using cached docs)
+	// section 9: rag cache instruction (only when using cached docs)
 	if ctx.UsedRAGCache {
 		builder.WriteString("\n\n")
 		builder.WriteString("═══════════════════════════════════════════════════════════\n")
@@ -263,3 +283,35 @@ RESOURCES
 - EXAMPLE STRUDELS: Pattern inspiration and working code
 `
 }
+
+// describes the lesson step and how to teach toward it
+func buildLessonSection(lesson *LessonContext) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("The user is working through the lesson \"%s\", step %d of %d: %s\n\n", lesson.Lesson, lesson.Step, lesson.StepCount, lesson.StepTitle))
+
+	if lesson.Explanation != "" {
+		builder.WriteString(lesson.Explanation)
+		builder.WriteString("\n\n")
+	}
+
+	if lesson.Example != "" {
+		builder.WriteString("Example for this step:\n")
+		builder.WriteString(lesson.Example)
+		builder.WriteString("\n\n")
+	}
+
+	if len(lesson.Missing) > 0 {
+		builder.WriteString("To finish the step, the code in the editor still needs to:\n")
+		for _, missing := range lesson.Missing {
+			builder.WriteString(fmt.Sprintf("- %s\n", missing))
+		}
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("Teach toward this step: explain the idea briefly, build on the user's current code with small changes,\n")
+	builder.WriteString("and give hints before complete solutions. Tell the user what to try next.\n")
+	builder.WriteString("If they ask about something else, answer it, then steer back to the lesson.\n")
+
+	return builder.String()
+}
@@ -2,6 +2,9 @@ package agent
 
 import (
 	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
 
 	"codeberg.org/algopatterns/server/internal/buffer"
 	"codeberg.org/algopatterns/server/internal/llm"
@@ -22,11 +25,81 @@ type RAGCache interface {
 	ClearRAGCache(ctx context.Context, sessionID string) error
 }
 
+// stores generated responses for repeated prompts
+type ResponseCache interface {
+	GetResponse(ctx context.Context, key string) (*CachedResponse, error) // nil on a miss
+	SetResponse(ctx context.Context, key string, resp *CachedResponse) error
+}
+
+const (
+	keyResponseCache = "agent:response:%s"
+
+	defaultResponseCacheTTL = 24 * time.Hour
+)
+
+// response cache settings, read from the environment
+type ResponseCacheConfig struct {
+	TTL         time.Duration // AGENT_CACHE_TTL, e.g. "6h"
+	BypassTiers []string      // AGENT_CACHE_BYPASS_TIERS, comma-separated quota tiers (default: payg)
+}
+
+// implements ResponseCache in redis
+type RedisResponseCache struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+// a generation stored for reuse
+type CachedResponse struct {
+	Code              string                    `json:"code"`
+	IsCodeResponse    bool                      `json:"is_code_response"`
+	Model             string                    `json:"model"`
+	Docs              []retriever.SearchResult  `json:"docs"`
+	Examples          []retriever.ExampleResult `json:"examples"`
+	StrudelReferences []StrudelReference        `json:"strudel_references"`
+	DocReferences     []DocReference            `json:"doc_references"`
+}
+
 // orchestrates rag-powered code generation
 type Agent struct {
-	retriever Retriever
-	generator llm.LLM
-	validator *strudel.Validator
+	retriever        Retriever
+	generator        llm.LLM
+	validator        *strudel.Validator
+	responseCache    ResponseCache
+	cacheBypassTiers map[string]bool
+	maxRepairs       int // self-critique passes, 0 disables
+	historyCompactor *HistoryCompactor
+}
+
+// condenses conversation histories that outgrow a token budget
+type HistoryCompactor struct {
+	summarizer llm.TextGenerator // cheap model for summaries, nil drops older messages instead
+	budget     int               // estimated tokens for summary and recent messages together
+}
+
+// the step of a guided lesson a session is on, answers teach toward it
+type LessonContext struct {
+	Lesson      string // lesson title
+	Step        int    // 1-based
+	StepCount   int
+	StepTitle   string
+	Explanation string
+	Example     string
+	Missing     []string // what the editor's code still lacks for the step
+}
+
+// clarifying questions asked about a query that was too vague to act on
+type Clarification struct {
+	Query            string   // the request being clarified
+	ConcreteRequests []string // parts of it that were already clear
+	Questions        []string
+}
+
+// conversation history that fits the token budget
+type CompactedHistory struct {
+	Summary string    // rolling summary of the messages before Recent
+	Recent  []Message // newest messages, sent as they are
+	Folded  int       // leading messages of the input newly folded into Summary, for persisting it
 }
 
 // all inputs for code generation
@@ -34,9 +107,13 @@ type GenerateRequest struct {
 	UserQuery           string
 	EditorState         string
 	ConversationHistory []Message
+	ConversationSummary string            // optional: summary of the conversation before ConversationHistory
+	Clarification       *Clarification    // optional: questions asked last turn, UserQuery is their answer
+	Lesson              *LessonContext    // optional: the lesson step the session is on
 	CustomGenerator     llm.TextGenerator // optional byok generator
 	SessionID           string            // optional: enables rag caching for follow-up messages
 	RAGCache            RAGCache          // optional: cache for rag results
+	Tier                string            // optional: quota tier of the requester, some tiers bypass the response cache
 }
 
 // reference to a strudel used as context
@@ -67,10 +144,14 @@ type GenerateResponse struct {
 	IsActionable        bool                      `json:"is_actionable"`
 	IsCodeResponse      bool                      `json:"is_code_response"` // true if response should update editor
 	ClarifyingQuestions []string                  `json:"clarifying_questions,omitempty"`
+	Clarification       *Clarification            `json:"-"` // set with ClarifyingQuestions, for the caller to keep until answered
 	InputTokens         int                       `json:"input_tokens"`
 	OutputTokens        int                       `json:"output_tokens"`
 	DidRetry            bool                      `json:"did_retry,omitempty"`
 	ValidationError     string                    `json:"validation_error,omitempty"`
+	RepairIterations    int                       `json:"repair_iterations,omitempty"` // self-critique passes that asked the model to fix its code
+	Cached              bool                      `json:"cached,omitempty"`            // served from the response cache
+	Degraded            bool                      `json:"degraded,omitempty"`          // generated by the fallback provider during an outage
 }
 
 // chunk of a streaming response
@@ -79,13 +160,19 @@ type StreamEvent struct {
 	Content string `json:"content,omitempty"` // text chunk for type="chunk"
 	Error   string `json:"error,omitempty"`   // error message for type="error"
 
-	// final metadata sent with type="done"
+	// retrieval metadata sent with type="refs" and type="done"
+	DocsRetrieved     int                `json:"docs_retrieved,omitempty"`
+	ExamplesRetrieved int                `json:"examples_retrieved,omitempty"`
 	StrudelReferences []StrudelReference `json:"strudel_references,omitempty"`
 	DocReferences     []DocReference     `json:"doc_references,omitempty"`
-	Model             string             `json:"model,omitempty"`
-	IsCodeResponse    bool               `json:"is_code_response,omitempty"`
-	InputTokens       int                `json:"input_tokens,omitempty"`
-	OutputTokens      int                `json:"output_tokens,omitempty"`
+
+	// final metadata sent with type="done"
+	Model          string `json:"model,omitempty"`
+	IsCodeResponse bool   `json:"is_code_response,omitempty"`
+	InputTokens    int    `json:"input_tokens,omitempty"`
+	OutputTokens   int    `json:"output_tokens,omitempty"`
+	Cached         bool   `json:"cached,omitempty"`
+	Degraded       bool   `json:"degraded,omitempty"` // generated by the fallback provider
 }
 
 // single conversation turn
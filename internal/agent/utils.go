@@ -131,6 +131,16 @@ func addLineNumbers(code string) string {
 	return builder.String()
 }
 
+// the model that generated a response, which isn't the generator's own
+// after it failed over
+func modelOf(generator llm.TextGenerator, resp *llm.TextGenerationResponse) string {
+	if resp.Model != "" {
+		return resp.Model
+	}
+
+	return generator.Model()
+}
+
 func (a *Agent) callGeneratorWithClient(ctx context.Context, generator llm.TextGenerator, systemPrompt, userQuery string, history []Message) (*llm.TextGenerationResponse, error) {
 	llmMessages := make([]llm.Message, 0, len(history)+1)
 
@@ -0,0 +1,285 @@
+package assets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// reads object storage settings from the environment
+func ConfigFromEnv() Config {
+	pathStyle, _ := strconv.ParseBool(os.Getenv("STORAGE_PATH_STYLE")) //nolint:errcheck // unset means virtual-hosted
+
+	return Config{
+		Endpoint:  os.Getenv("STORAGE_ENDPOINT"),
+		Region:    os.Getenv("STORAGE_REGION"),
+		Bucket:    os.Getenv("STORAGE_BUCKET"),
+		AccessKey: os.Getenv("STORAGE_ACCESS_KEY_ID"),
+		SecretKey: os.Getenv("STORAGE_SECRET_ACCESS_KEY"),
+		PathStyle: pathStyle,
+		PublicURL: os.Getenv("STORAGE_PUBLIC_URL"),
+	}
+}
+
+// reports whether the endpoint, bucket and keys are all set
+func (c Config) Enabled() bool {
+	return c.Endpoint != "" && c.Bucket != "" && c.AccessKey != "" && c.SecretKey != ""
+}
+
+// creates the asset service. backend may be nil, which disables uploads
+func New(db *pgxpool.Pool, backend Backend) *Service {
+	return &Service{db: db, backend: backend}
+}
+
+// reports whether object storage is configured
+func (s *Service) Enabled() bool {
+	return s.backend != nil
+}
+
+// creates a pending asset and returns a presigned URL to upload its original
+// to. the upload is processed once the client completes it
+func (s *Service) CreateUpload(ctx context.Context, userID, kind, contentType string) (*Upload, error) {
+	if !s.Enabled() {
+		return nil, ErrNotConfigured
+	}
+
+	if _, ok := kinds[kind]; !ok {
+		return nil, ErrUnsupportedKind
+	}
+
+	if _, ok := imageTypes[contentType]; !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	var pending int
+	if err := s.db.QueryRow(ctx, queryCountPending, userID, uploadExpiry.Seconds()).Scan(&pending); err != nil {
+		return nil, fmt.Errorf("failed to count pending uploads: %w", err)
+	}
+
+	if pending >= maxPendingUploads {
+		return nil, ErrTooManyUploads
+	}
+
+	var assetID, uploadKey string
+	var createdAt time.Time
+
+	err := s.db.QueryRow(ctx, queryCreateAsset, userID, kind, contentType).Scan(&assetID, &uploadKey, &createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	// an asset whose URL couldn't be signed is cleaned up as never completed
+	url, err := s.backend.PresignPut(uploadKey, uploadExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload: %w", err)
+	}
+
+	upload := &Upload{
+		AssetID:     assetID,
+		URL:         url,
+		Method:      "PUT",
+		ContentType: contentType,
+		MaxBytes:    MaxUploadBytes,
+		ExpiresAt:   createdAt.Add(uploadExpiry),
+	}
+
+	return upload, nil
+}
+
+// validates and processes an uploaded original, stores the result and, for
+// avatars, makes it the user's avatar. the original is deleted afterwards
+func (s *Service) CompleteUpload(ctx context.Context, userID, assetID string) (*Asset, error) {
+	if !s.Enabled() {
+		return nil, ErrNotConfigured
+	}
+
+	var kind, status, uploadKey, contentType string
+	err := s.db.QueryRow(ctx, queryGetPending, assetID, userID).Scan(&kind, &status, &uploadKey, &contentType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAssetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load asset: %w", err)
+	}
+
+	if status != statusPending {
+		return nil, ErrAlreadyComplete
+	}
+
+	spec, ok := kinds[kind]
+	if !ok {
+		return nil, ErrUnsupportedKind
+	}
+
+	original, err := s.backend.Get(ctx, uploadKey, MaxUploadBytes)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil, ErrUploadMissing
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	processed, err := processImage(original, contentType, spec.size)
+	if err != nil {
+		return nil, err
+	}
+
+	objectKey := fmt.Sprintf("%s/%s/%s.%s", spec.prefix, userID, assetID, processed.ext)
+	if err := s.backend.Put(ctx, objectKey, processed.contentType, processed.data); err != nil {
+		return nil, fmt.Errorf("failed to store processed %s: %w", kind, err)
+	}
+
+	asset, err := s.complete(ctx, userID, assetID, kind, objectKey, processed)
+	if err != nil {
+		// the processed file is picked up again on a retry, or never referenced
+		return nil, err
+	}
+
+	if err := s.backend.Delete(ctx, uploadKey); err != nil {
+		logger.Warn("failed to delete uploaded original", "error", err, "asset_id", assetID)
+	}
+
+	return asset, nil
+}
+
+// marks the asset ready and applies it in one transaction
+func (s *Service) complete(ctx context.Context, userID, assetID, kind, objectKey string, processed *processedImage) (*Asset, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck,gosec // no-op after commit
+
+	asset, err := s.scanAsset(tx.QueryRow(ctx, queryCompleteAsset,
+		assetID, objectKey, processed.contentType, len(processed.data), processed.width, processed.height))
+	if errors.Is(err, pgx.ErrNoRows) {
+		// completed concurrently
+		return nil, ErrAlreadyComplete
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete asset: %w", err)
+	}
+
+	if kind == KindAvatar {
+		if _, err := tx.Exec(ctx, querySetAvatar, userID, asset.URL, assetID); err != nil {
+			return nil, fmt.Errorf("failed to set avatar: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+// removes the user's uploaded avatar. the asset is deleted by the cleanup
+// once nothing references it. reports whether there was one
+func (s *Service) RemoveAvatar(ctx context.Context, userID string) (bool, error) {
+	tag, err := s.db.Exec(ctx, queryClearAvatar, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// deletes uploads never completed and assets nothing references anymore,
+// from storage first. run periodically
+func (s *Service) CleanupOrphans(ctx context.Context) {
+	if !s.Enabled() {
+		return
+	}
+
+	rows, err := s.db.Query(ctx, queryListOrphans,
+		(uploadExpiry + pendingGrace).Seconds(), unreferencedGrace.Seconds(), cleanupBatch)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to list orphaned assets")
+		}
+		return
+	}
+
+	orphans, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (orphan, error) {
+		var o orphan
+		err := row.Scan(&o.id, &o.uploadKey, &o.objectKey)
+		return o, err
+	})
+	if err != nil {
+		logger.ErrorErr(err, "failed to list orphaned assets")
+		return
+	}
+
+	deleted := 0
+	for _, o := range orphans {
+		keys := []string{o.uploadKey}
+		if o.objectKey != nil {
+			keys = append(keys, *o.objectKey)
+		}
+
+		if err := s.deleteObjects(ctx, keys); err != nil {
+			// kept for the next run
+			logger.Warn("failed to delete orphaned asset", "error", err, "asset_id", o.id)
+			continue
+		}
+
+		if _, err := s.db.Exec(ctx, queryDeleteAsset, o.id); err != nil {
+			logger.Warn("failed to delete orphaned asset record", "error", err, "asset_id", o.id)
+			continue
+		}
+
+		deleted++
+	}
+
+	if deleted > 0 {
+		logger.Info("deleted orphaned assets", "count", deleted)
+	}
+}
+
+func (s *Service) deleteObjects(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := s.backend.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) scanAsset(row pgx.Row) (*Asset, error) {
+	var asset Asset
+	var objectKey, contentType *string
+	var size, width, height *int
+
+	err := row.Scan(&asset.ID, &asset.Kind, &asset.Status, &objectKey, &contentType,
+		&size, &width, &height, &asset.CreatedAt, &asset.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if objectKey != nil {
+		asset.URL = s.backend.URL(*objectKey)
+	}
+	if contentType != nil {
+		asset.ContentType = *contentType
+	}
+	if size != nil {
+		asset.SizeBytes = *size
+	}
+	if width != nil {
+		asset.Width = *width
+	}
+	if height != nil {
+		asset.Height = *height
+	}
+
+	return &asset, nil
+}
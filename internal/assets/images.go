@@ -0,0 +1,108 @@
+package assets
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the gif decoder
+	"image/jpeg"
+	"image/png"
+)
+
+const jpegQuality = 85
+
+// an image ready to store
+type processedImage struct {
+	data          []byte
+	contentType   string
+	ext           string
+	width, height int
+}
+
+// validates an uploaded image of contentType, crops it to a centered square
+// and scales it down to size. opaque images are stored as JPEG, others as PNG
+func processImage(data []byte, contentType string, size int) (*processedImage, error) {
+	format, ok := imageTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	// check the header before decoding, so huge images aren't decoded
+	config, decoded, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || decoded != format {
+		return nil, ErrInvalidImage
+	}
+
+	if config.Width == 0 || config.Height == 0 ||
+		config.Width > maxImageDimension || config.Height > maxImageDimension {
+		return nil, fmt.Errorf("%w: must be at most %dx%d pixels", ErrInvalidImage, maxImageDimension, maxImageDimension)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	scaled := scaleSquare(src, size)
+
+	var out bytes.Buffer
+	result := &processedImage{width: scaled.Bounds().Dx(), height: scaled.Bounds().Dy()}
+
+	if scaled.Opaque() {
+		result.contentType, result.ext = "image/jpeg", "jpg"
+		err = jpeg.Encode(&out, scaled, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		result.contentType, result.ext = "image/png", "png"
+		err = png.Encode(&out, scaled)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	result.data = out.Bytes()
+	return result, nil
+}
+
+// crops the centered square of src and scales it down to at most size pixels
+// a side, averaging the source pixels each output pixel covers
+func scaleSquare(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Point{
+		X: bounds.Min.X + (bounds.Dx()-side)/2,
+		Y: bounds.Min.Y + (bounds.Dy()-side)/2,
+	})
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), src, crop.Min, draw.Src)
+
+	if side <= size {
+		return square
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := range size {
+		y0, y1 := y*side/size, (y+1)*side/size
+		for x := range size {
+			x0, x1 := x*side/size, (x+1)*side/size
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := square.Pix[sy*square.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r, g, b, a = r+int(p[0]), g+int(p[1]), b+int(p[2]), a+int(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0], d[1], d[2], d[3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n) //nolint:gosec // averages of bytes
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,104 @@
+package assets
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// a w×h image, red on the left half and blue on the right
+func halves(w, h int, alpha uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			c := color.NRGBA{R: 255, A: alpha}
+			if x >= w/2 {
+				c = color.NRGBA{B: 255, A: alpha}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func TestProcessImageCropsAndScales(t *testing.T) {
+	processed, err := processImage(encodePNG(t, halves(800, 400, 255)), "image/png", 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processed.width != 256 || processed.height != 256 {
+		t.Errorf("expected 256x256, got %dx%d", processed.width, processed.height)
+	}
+
+	// opaque images are stored as JPEG
+	if processed.contentType != "image/jpeg" || processed.ext != "jpg" {
+		t.Errorf("expected a jpeg, got %s", processed.contentType)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(processed.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the centered square spans the middle of the image, half red, half blue
+	left, right := color.RGBAModel.Convert(img.At(10, 128)).(color.RGBA), color.RGBAModel.Convert(img.At(245, 128)).(color.RGBA)
+	if left.R < 200 || left.B > 60 || right.B < 200 || right.R > 60 {
+		t.Errorf("expected red left and blue right, got %v and %v", left, right)
+	}
+}
+
+func TestProcessImageKeepsSmallImagesAndTransparency(t *testing.T) {
+	processed, err := processImage(encodePNG(t, halves(100, 120, 128)), "image/png", 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processed.width != 100 || processed.height != 100 {
+		t.Errorf("expected 100x100, got %dx%d", processed.width, processed.height)
+	}
+
+	if processed.contentType != "image/png" {
+		t.Errorf("expected a png for a translucent image, got %s", processed.contentType)
+	}
+}
+
+func TestProcessImageRejectsInvalidUploads(t *testing.T) {
+	valid := encodePNG(t, halves(10, 10, 255))
+
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		want        error
+	}{
+		{"not an image", []byte("<svg onload=alert(1)>"), "image/png", ErrInvalidImage},
+		{"type mismatch", valid, "image/jpeg", ErrInvalidImage},
+		{"unsupported type", valid, "image/svg+xml", ErrUnsupportedType},
+		{"too large", encodePNG(t, image.NewGray(image.Rect(0, 0, maxImageDimension+1, 1))), "image/png", ErrInvalidImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := processImage(tt.data, tt.contentType, 256); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
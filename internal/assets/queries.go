@@ -0,0 +1,65 @@
+package assets
+
+const (
+	assetColumns = `
+		id, kind, status, object_key, content_type, size_bytes, width, height, created_at, completed_at`
+
+	queryCountPending = `
+		SELECT COUNT(*) FROM assets
+		WHERE user_id = $1 AND status = 'pending' AND created_at > NOW() - make_interval(secs => $2)
+	`
+
+	// originals are uploaded to uploads/<user id>/<asset id>
+	queryCreateAsset = `
+		WITH new AS (SELECT gen_random_uuid() AS id)
+		INSERT INTO assets (id, user_id, kind, upload_key, content_type)
+		SELECT new.id, $1, $2, 'uploads/' || $1 || '/' || new.id, $3
+		FROM new
+		RETURNING id, upload_key, created_at
+	`
+
+	queryGetPending = `
+		SELECT kind, status, upload_key, content_type
+		FROM assets
+		WHERE id = $1 AND user_id = $2
+	`
+
+	queryCompleteAsset = `
+		UPDATE assets
+		SET status = 'ready', object_key = $2, content_type = $3, size_bytes = $4,
+			width = $5, height = $6, completed_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING ` + assetColumns + `
+	`
+
+	querySetAvatar = `
+		UPDATE users
+		SET avatar_url = $2, avatar_asset_id = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	// only clears an uploaded avatar; the asset is then orphaned
+	queryClearAvatar = `
+		UPDATE users
+		SET avatar_url = NULL, avatar_asset_id = NULL, updated_at = NOW()
+		WHERE id = $1 AND avatar_asset_id IS NOT NULL
+	`
+
+	// uploads never completed, and ready assets whose owner is gone or that no
+	// user has as their avatar anymore
+	queryListOrphans = `
+		SELECT a.id, a.upload_key, a.object_key
+		FROM assets a
+		WHERE (a.status = 'pending' AND a.created_at < NOW() - make_interval(secs => $1))
+		   OR (a.status = 'ready' AND a.completed_at < NOW() - make_interval(secs => $2)
+		       AND (a.user_id IS NULL
+		            OR (a.kind = 'avatar' AND NOT EXISTS (
+		                  SELECT 1 FROM users u WHERE u.avatar_asset_id = a.id))))
+		ORDER BY a.created_at
+		LIMIT $3
+	`
+
+	queryDeleteAsset = `
+		DELETE FROM assets WHERE id = $1
+	`
+)
@@ -0,0 +1,291 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	amzDateFormat    = "20060102T150405Z"
+)
+
+// creates an S3 client for the configured bucket
+func NewS3(config Config) *S3 {
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	return &S3{
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+		now:    time.Now,
+	}
+}
+
+// a URL the client can PUT an object to until it expires
+func (s *S3) PresignPut(key string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, expires)
+}
+
+// reads an object, failing with ErrTooLarge past maxBytes
+func (s *S3) Get(ctx context.Context, key string, maxBytes int64) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrObjectNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("storage get %s: status %d", key, resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+
+	if int64(len(data)) > maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	return data, nil
+}
+
+func (s *S3) Put(ctx context.Context, key, contentType string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("storage put %s: status %d", key, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// deletes an object; deleting a missing object is not an error
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("storage delete %s: status %d", key, resp.StatusCode)
+	}
+}
+
+// the public URL of an object
+func (s *S3) URL(key string) string {
+	if s.config.PublicURL != "" {
+		return strings.TrimRight(s.config.PublicURL, "/") + "/" + escapePath(key)
+	}
+
+	u, err := s.objectURL(key)
+	if err != nil {
+		return ""
+	}
+
+	return u.String()
+}
+
+// the URL of an object, with the bucket in the path or the host
+func (s *S3) objectURL(key string) (*url.URL, error) {
+	u, err := url.Parse(s.config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage endpoint: %w", err)
+	}
+
+	base := strings.TrimRight(u.Path, "/")
+	if s.config.PathStyle {
+		u.Path = base + "/" + s.config.Bucket + "/" + key
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+		u.Path = base + "/" + key
+	}
+
+	u.RawPath = escapePath(u.Path)
+	u.RawQuery = ""
+
+	return u, nil
+}
+
+// signs a URL for method with query parameters, so it works without headers
+func (s *S3) presign(method, key string, expires time.Duration) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	scope := s.scope(now)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", signingAlgorithm)
+	query.Set("X-Amz-Credential", s.config.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := canonicalQueryString(query)
+	canonicalRequest := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		canonicalQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	signature := s.sign(now, amzDate, scope, canonicalRequest)
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + signature
+
+	return u.String(), nil
+}
+
+// sends a request signed in the Authorization header
+func (s *S3) do(ctx context.Context, method, key, contentType string, body []byte) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build storage request: %w", err)
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format(amzDateFormat)
+	scope := s.scope(now)
+	payloadHash := hashHex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		"",
+		"host:" + u.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	signature := s.sign(now, amzDate, scope, canonicalRequest)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, s.config.AccessKey, scope, signedHeaders, signature))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("storage request failed: %w", err)
+	}
+
+	return resp, nil
+}
+
+// credential scope of a signature made at t
+func (s *S3) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.config.Region + "/s3/aws4_request"
+}
+
+func (s *S3) sign(t time.Time, amzDate, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// query parameters sorted by name, with values encoded the way SigV4 expects
+func canonicalQueryString(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(query.Get(name), true))
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// encodes an object path, keeping its slashes
+func escapePath(path string) string {
+	return uriEncode(path, false)
+}
+
+// percent-encodes everything but unreserved characters, and slashes unless
+// encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,148 @@
+package assets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// asset kinds
+const (
+	KindAvatar = "avatar"
+)
+
+// asset statuses
+const (
+	statusPending = "pending" // presigned, waiting for the client's upload
+	statusReady   = "ready"   // processed and stored
+)
+
+const (
+	// how long a presigned upload URL is valid
+	uploadExpiry = 10 * time.Minute
+
+	// largest original a client may upload
+	MaxUploadBytes = 5 << 20
+
+	// originals wider or taller than this are rejected before decoding
+	maxImageDimension = 4096
+
+	// pending uploads a user may have open at once
+	maxPendingUploads = 5
+
+	// uploads not completed this long after their URL expired are orphaned
+	pendingGrace = time.Hour
+
+	// ready assets nothing references are kept this long before deletion, so
+	// a completion racing with a profile change doesn't lose its avatar
+	unreferencedGrace = time.Hour
+
+	// orphaned assets deleted per cleanup run
+	cleanupBatch = 100
+
+	// timeout of object storage requests
+	requestTimeout = 30 * time.Second
+)
+
+// errors
+var (
+	ErrNotConfigured   = errors.New("object storage is not configured")
+	ErrUnsupportedKind = errors.New("unsupported asset kind")
+	ErrUnsupportedType = errors.New("unsupported content type")
+	ErrTooManyUploads  = errors.New("too many uploads in progress")
+	ErrAssetNotFound   = errors.New("asset not found")
+	ErrAlreadyComplete = errors.New("upload already completed")
+	ErrUploadMissing   = errors.New("nothing was uploaded")
+	ErrTooLarge        = errors.New("upload is too large")
+	ErrInvalidImage    = errors.New("upload is not a valid image")
+	ErrObjectNotFound  = errors.New("object not found")
+)
+
+// content types accepted for image uploads, with the formats they decode as
+var imageTypes = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpeg",
+	"image/gif":  "gif",
+}
+
+// how uploads of a kind are processed
+type kindSpec struct {
+	prefix string // object key prefix of processed files
+	size   int    // processed images are cropped square and scaled to fit this
+}
+
+var kinds = map[string]kindSpec{
+	KindAvatar: {prefix: "avatars", size: 256},
+}
+
+// S3-compatible object storage settings, read from the environment. storage
+// stays disabled unless the endpoint, bucket and keys are all set
+type Config struct {
+	Endpoint  string // STORAGE_ENDPOINT, e.g. https://s3.eu-central-1.amazonaws.com or a MinIO/Supabase storage URL
+	Region    string // STORAGE_REGION, defaults to us-east-1
+	Bucket    string // STORAGE_BUCKET
+	AccessKey string // STORAGE_ACCESS_KEY_ID
+	SecretKey string // STORAGE_SECRET_ACCESS_KEY
+	PathStyle bool   // STORAGE_PATH_STYLE, address the bucket in the path (MinIO, Supabase) instead of the host
+	PublicURL string // STORAGE_PUBLIC_URL, base URL objects are served from; defaults to the bucket URL
+}
+
+// an object store the service keeps assets in
+type Backend interface {
+	// a URL the client can PUT an object to until it expires
+	PresignPut(key string, expires time.Duration) (string, error)
+	// reads an object, failing with ErrTooLarge past maxBytes
+	Get(ctx context.Context, key string, maxBytes int64) ([]byte, error)
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// deletes an object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// the public URL of an object
+	URL(key string) string
+}
+
+// presigned uploads, processing and cleanup of user assets
+type Service struct {
+	db      *pgxpool.Pool
+	backend Backend // nil when storage is not configured
+}
+
+// S3 API client signing requests with AWS Signature Version 4
+type S3 struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// an uploaded file
+type Asset struct {
+	ID          string     `json:"id"`
+	Kind        string     `json:"kind"`
+	Status      string     `json:"status"`
+	URL         string     `json:"url,omitempty"` // set once ready
+	ContentType string     `json:"content_type,omitempty"`
+	SizeBytes   int        `json:"size_bytes,omitempty"`
+	Width       int        `json:"width,omitempty"`
+	Height      int        `json:"height,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// where and how to upload a file
+type Upload struct {
+	AssetID     string    `json:"asset_id"`
+	URL         string    `json:"upload_url"`
+	Method      string    `json:"method"`
+	ContentType string    `json:"content_type"` // send as the Content-Type header
+	MaxBytes    int       `json:"max_bytes"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// an orphaned asset and the objects to delete with it
+type orphan struct {
+	id        string
+	uploadKey string
+	objectKey *string
+}
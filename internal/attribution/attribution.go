@@ -2,53 +2,124 @@ package attribution
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/keyset"
 	"codeberg.org/algopatterns/server/internal/logger"
 	"codeberg.org/algopatterns/server/internal/retriever"
+	"codeberg.org/algopatterns/server/internal/tracing"
+	"codeberg.org/algopatterns/server/internal/webhooks"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type Service struct {
-	db *pgxpool.Pool
+func New(db *pgxpool.Pool, queue *jobs.Queue) *Service {
+	s := &Service{db: db, jobs: queue}
+
+	queue.Register(jobRecord, s.handleRecordJob)
+
+	return s
 }
 
-func New(db *pgxpool.Pool) *Service {
-	return &Service{db: db}
+// notifies strudel owners when their work is used as AI context
+func (s *Service) SetEventPublisher(events EventPublisher) {
+	s.events = events
 }
 
-// records that examples were used as RAG context
-// runs asynchronously to not block the agent response
+// records that examples were used as RAG context. the records are written by
+// a background job so the agent response isn't blocked, and aren't lost if
+// the instance stops
 func (s *Service) RecordAttributions(
-	_ context.Context,
+	ctx context.Context,
 	examples []retriever.ExampleResult,
 	requestingUserID string,
 	targetStrudelID *string,
 ) {
-	go func() {
-		for _, ex := range examples {
-			if ex.UserID == "" || ex.ID == "" {
-				continue
-			}
+	ctx, span := tracing.Start(ctx, "attribution.RecordAttributions",
+		tracing.Int("attribution.examples", len(examples)),
+	)
+	defer span.End()
 
-			// don't record self-attribution
-			if ex.UserID == requestingUserID {
-				continue
-			}
+	job := recordJob{
+		RequestingUserID: requestingUserID,
+		TargetStrudelID:  targetStrudelID,
+	}
+
+	for _, ex := range examples {
+		if ex.UserID == "" || ex.ID == "" {
+			continue
+		}
 
-			_, err := s.db.Exec(
-				context.Background(),
-				queryRecordAttribution,
-				ex.ID,
-				targetStrudelID,
-				requestingUserID,
-				ex.Similarity,
-			)
+		// don't record self-attribution
+		if ex.UserID == requestingUserID {
+			continue
+		}
+
+		job.Examples = append(job.Examples, usedExample{
+			StrudelID:  ex.ID,
+			UserID:     ex.UserID,
+			Title:      ex.Title,
+			Similarity: ex.Similarity,
+		})
+	}
+
+	if len(job.Examples) == 0 {
+		return
+	}
+
+	if err := s.jobs.Enqueue(ctx, jobRecord, job); err != nil {
+		span.RecordError(err)
+		logger.Warn("failed to queue attributions", "error", err, "examples", len(job.Examples))
+	}
+}
 
+// writes the attributions of a request and the events notifying the owners
+// in one transaction, so a retried job doesn't record or announce any twice
+func (s *Service) handleRecordJob(ctx context.Context, payload json.RawMessage) error {
+	var job recordJob
+	if err := jobs.Decode(payload, &job); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	for _, ex := range job.Examples {
+		_, err := tx.Exec(
+			ctx,
+			queryRecordAttribution,
+			ex.StrudelID,
+			job.TargetStrudelID,
+			job.RequestingUserID,
+			ex.Similarity,
+		)
+
+		if err != nil {
+			return fmt.Errorf("failed to record attribution of %s: %w", ex.StrudelID, err)
+		}
+	}
+
+	if s.events != nil {
+		for _, ex := range job.Examples {
+			err := s.events.PublishTx(ctx, tx, ex.UserID, webhooks.EventAttributionRecorded, webhooks.AttributionEventData{
+				StrudelID:       ex.StrudelID,
+				StrudelTitle:    ex.Title,
+				TargetStrudelID: job.TargetStrudelID,
+				RequestedBy:     job.RequestingUserID,
+				Similarity:      ex.Similarity,
+			})
 			if err != nil {
-				logger.Warn("failed to record attribution", "error", err, "source_strudel_id", ex.ID)
+				return err
 			}
 		}
-	}()
+	}
+
+	return tx.Commit(ctx)
 }
 
 // gets attribution stats for a user's strudels
@@ -149,6 +220,53 @@ func (s *Service) GetStrudelRecentUses(ctx context.Context, strudelID string, li
 	return uses, rows.Err()
 }
 
+// lists the uses of a strudel, newest first by default, a page at a time
+func (s *Service) ListStrudelUses(ctx context.Context, strudelID string, page keyset.Page) ([]StrudelUse, keyset.Edges, error) {
+	where, args := page.Where("created_at", "timestamptz", "id", "text", 2)
+	if where == "" {
+		where = "true"
+	}
+
+	query := fmt.Sprintf(queryListStrudelUses, where, page.OrderBy("created_at", "id"), len(args)+2)
+	args = append([]any{strudelID}, append(args, page.Fetch())...)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, keyset.Edges{}, err
+	}
+
+	defer rows.Close()
+	var uses []StrudelUse
+
+	for rows.Next() {
+		var u StrudelUse
+		err := rows.Scan(
+			&u.ID,
+			&u.TargetStrudelID,
+			&u.TargetStrudelTitle,
+			&u.RequestingUserID,
+			&u.RequestingDisplayName,
+			&u.SimilarityScore,
+			&u.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, keyset.Edges{}, err
+		}
+
+		uses = append(uses, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, keyset.Edges{}, err
+	}
+
+	uses, edges := keyset.Cut(page, uses, func(u StrudelUse) keyset.Key {
+		return keyset.Key{Value: keyset.Time(u.CreatedAt), ID: u.ID}
+	})
+	return uses, edges, nil
+}
+
 // gets fork count for a strudel
 func (s *Service) GetStrudelForkCount(ctx context.Context, strudelID string) (int, error) {
 	var count int
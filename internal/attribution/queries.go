@@ -107,6 +107,59 @@ const (
 		LIMIT $2
 	`
 
+	// the same "inspired" list, a page at a time. %s are the keyset condition,
+	// the order and the limit's placeholder
+	queryListStrudelUses = `
+		WITH combined AS (
+			SELECT
+				ra.id::text,
+				ra.target_strudel_id,
+				target.title as target_strudel_title,
+				ra.requesting_user_id,
+				u.name as requesting_display_name,
+				ra.similarity_score,
+				ra.created_at,
+				target.is_public
+			FROM rag_attributions ra
+			LEFT JOIN user_strudels target ON ra.target_strudel_id = target.id
+			LEFT JOIN users u ON ra.requesting_user_id = u.id
+			WHERE ra.source_strudel_id = $1
+				AND ra.target_strudel_id IS NOT NULL
+
+			UNION ALL
+
+			SELECT
+				us.id::text,
+				us.id as target_strudel_id,
+				us.title as target_strudel_title,
+				us.user_id as requesting_user_id,
+				u.name as requesting_display_name,
+				NULL::float as similarity_score,
+				us.created_at,
+				us.is_public
+			FROM user_strudels us
+			LEFT JOIN users u ON us.user_id = u.id
+			WHERE us.forked_from = $1
+		),
+		uses AS (
+			SELECT DISTINCT ON (target_strudel_id)
+				id,
+				target_strudel_id,
+				target_strudel_title,
+				requesting_user_id,
+				requesting_display_name,
+				similarity_score,
+				created_at
+			FROM combined
+			WHERE is_public = true
+			ORDER BY target_strudel_id, created_at DESC
+		)
+		SELECT * FROM uses
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`
+
 	queryGetStrudelForkCount = `
 		SELECT COUNT(*) FROM user_strudels WHERE forked_from = $1
 	`
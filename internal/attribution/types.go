@@ -1,6 +1,43 @@
 package attribution
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/jobs"
+)
+
+// background job that writes the attributions of an agent request
+const jobRecord = "attribution.record"
+
+type Service struct {
+	db     *pgxpool.Pool
+	jobs   *jobs.Queue
+	events EventPublisher
+}
+
+// payload of a jobRecord job
+type recordJob struct {
+	Examples         []usedExample `json:"examples"`
+	RequestingUserID string        `json:"requesting_user_id"`
+	TargetStrudelID  *string       `json:"target_strudel_id,omitempty"`
+}
+
+// a strudel of another user that was used as context
+type usedExample struct {
+	StrudelID  string  `json:"strudel_id"`
+	UserID     string  `json:"user_id"`
+	Title      string  `json:"title"`
+	Similarity float32 `json:"similarity"`
+}
+
+// queues webhook events in the transaction of the write they report (implemented by webhooks.Service)
+type EventPublisher interface {
+	PublishTx(ctx context.Context, tx pgx.Tx, userID, event string, data any) error
+}
 
 type Attribution struct {
 	ID                    string
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+func New(db *pgxpool.Pool) *Service {
+	return &Service{db: db}
+}
+
+// appends an event to the audit log. callers record actions that already
+// happened, so a failed write is logged instead of returned.
+func (s *Service) Record(ctx context.Context, event Event) {
+	data, err := encodeDetails(event.Details)
+	if err != nil {
+		logger.ErrorErr(err, "failed to encode audit details", "action", event.Action)
+		return
+	}
+
+	// record even if the request was cancelled after the action
+	ctx = context.WithoutCancel(ctx)
+
+	_, err = s.db.Exec(ctx, queryRecord,
+		event.ActorID,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.SessionID,
+		data,
+	)
+	if err != nil {
+		logger.ErrorErr(err, "failed to record audit event",
+			"action", event.Action,
+			"actor_id", event.ActorID,
+			"target_id", event.TargetID,
+		)
+	}
+}
+
+// encodes an event's details for the jsonb column, which is never null
+func encodeDetails(details any) ([]byte, error) {
+	if details == nil {
+		details = struct{}{}
+	}
+
+	return json.Marshal(details)
+}
+
+// lists entries matching filter, newest first, with the total count
+func (s *Service) List(ctx context.Context, filter ListFilter, limit, offset int) ([]Entry, int, error) {
+	args := []any{filter.ActorID, filter.Action, filter.TargetType, filter.TargetID, filter.SessionID}
+
+	var total int
+	if err := s.db.QueryRow(ctx, queryCount, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(ctx, queryList, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	entries := []Entry{}
+
+	for rows.Next() {
+		var e Entry
+		err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.TargetType, &e.TargetID, &e.SessionID, &e.Details, &e.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDetails(t *testing.T) {
+	data, err := encodeDetails(nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(data))
+
+	data, err = encodeDetails(map[string]any{"old_role": "viewer", "new_role": "co-author"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"old_role":"viewer","new_role":"co-author"}`, string(data))
+}
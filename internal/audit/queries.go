@@ -0,0 +1,30 @@
+package audit
+
+const (
+	queryRecord = `
+		INSERT INTO audit_log (actor_id, action, target_type, target_id, session_id, details)
+		VALUES (NULLIF($1, '')::uuid, $2, $3, $4, NULLIF($5, '')::uuid, $6)
+	`
+
+	queryList = `
+		SELECT id, actor_id, action, target_type, target_id, session_id, details, created_at
+		FROM audit_log
+		WHERE ($1 = '' OR actor_id::text = $1)
+		  AND ($2 = '' OR action = $2)
+		  AND ($3 = '' OR target_type = $3)
+		  AND ($4 = '' OR target_id = $4)
+		  AND ($5 = '' OR session_id::text = $5)
+		ORDER BY created_at DESC
+		LIMIT $6 OFFSET $7
+	`
+
+	queryCount = `
+		SELECT COUNT(*)
+		FROM audit_log
+		WHERE ($1 = '' OR actor_id::text = $1)
+		  AND ($2 = '' OR action = $2)
+		  AND ($3 = '' OR target_type = $3)
+		  AND ($4 = '' OR target_id = $4)
+		  AND ($5 = '' OR session_id::text = $5)
+	`
+)
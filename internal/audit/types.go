@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// session actions, readable by the session's host
+const (
+	ActionSessionEnd             = "session.end"
+	ActionSessionEndLive         = "session.end_live"
+	ActionSessionRestore         = "session.restore"
+	ActionSessionChatSettings    = "session.chat_settings"
+	ActionParticipantKick        = "participant.kick"
+	ActionParticipantRole        = "participant.role_change"
+	ActionParticipantPermissions = "participant.permissions_change"
+	ActionParticipantMute        = "participant.mute"
+	ActionParticipantUnmute      = "participant.unmute"
+	ActionInviteCreate           = "invite.create"
+	ActionInviteRevoke           = "invite.revoke"
+	ActionJoinRequestApprove     = "join_request.approve"
+	ActionJoinRequestDeny        = "join_request.deny"
+	ActionPasteLockAppeal        = "paste_lock.appeal"
+)
+
+// strudel actions
+const (
+	ActionStrudelCCSignal = "strudel.cc_signal_change"
+)
+
+// admin actions
+const (
+	ActionAdminSessionEnd        = "admin.session.end"
+	ActionAdminStrudelDelete     = "admin.strudel.delete"
+	ActionAdminStrudelUnpublish  = "admin.strudel.unpublish"
+	ActionAdminStrudelTraining   = "admin.strudel.use_in_training"
+	ActionAdminUserBan           = "admin.user.ban"
+	ActionAdminUserUnban         = "admin.user.unban"
+	ActionAdminPasteLockRemove   = "admin.paste_lock.remove"
+	ActionAdminAppealApprove     = "admin.paste_lock_appeal.approve"
+	ActionAdminAppealReject      = "admin.paste_lock_appeal.reject"
+	ActionAdminModerationApprove = "admin.moderation.approve"
+	ActionAdminModerationRemove  = "admin.moderation.remove"
+	ActionAdminServerDrain       = "admin.server.drain"
+	ActionAdminJobRetry          = "admin.job.retry"
+	ActionAdminFaultSet          = "admin.fault.set"
+	ActionAdminFaultsReset       = "admin.faults.reset"
+)
+
+// kinds of resources an action applies to
+const (
+	TargetSession     = "session"
+	TargetParticipant = "participant"
+	TargetInvite      = "invite_token"
+	TargetJoinRequest = "join_request"
+	TargetSnapshot    = "session_snapshot"
+	TargetStrudel     = "strudel"
+	TargetUser        = "user"
+	TargetModeration  = "moderation_item"
+	TargetAppeal      = "paste_lock_appeal"
+	TargetInstance    = "server_instance"
+	TargetJob         = "job"
+)
+
+// writes and reads the audit log
+type Service struct {
+	db *pgxpool.Pool
+}
+
+// an action to record
+type Event struct {
+	ActorID    string // empty for actions taken by the system
+	Action     string
+	TargetType string
+	TargetID   string
+	SessionID  string // set for actions within a session, so its host can read them
+	Details    any    // stored as JSON, may be nil
+}
+
+// a recorded action
+type Entry struct {
+	ID         string          `json:"id"`
+	ActorID    *string         `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	SessionID  *string         `json:"session_id,omitempty"`
+	Details    json.RawMessage `json:"details"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// narrows a listing; empty fields match everything
+type ListFilter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	SessionID  string
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -12,7 +13,9 @@ import (
 	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
 	"github.com/markbates/goth/providers/apple"
+	"github.com/markbates/goth/providers/discord"
 	"github.com/markbates/goth/providers/github"
+	"github.com/markbates/goth/providers/gitlab"
 	"github.com/markbates/goth/providers/google"
 )
 
@@ -28,16 +31,7 @@ func InitializeProviders() error {
 	store := sessions.NewCookieStore([]byte(sessionSecret))
 
 	baseURL := os.Getenv("BASE_URL")
-	isHTTPS := strings.HasPrefix(baseURL, "https://")
-
-	// configure cookie for OAuth redirects
-	store.Options = &sessions.Options{
-		Path:     "/",
-		MaxAge:   300, // 5 minutes, enough for OAuth flow
-		HttpOnly: true,
-		Secure:   isHTTPS,
-		SameSite: http.SameSiteLaxMode,
-	}
+	store.Options = oauthCookieOptions(baseURL)
 
 	gothic.Store = store
 
@@ -77,11 +71,51 @@ func InitializeProviders() error {
 		))
 	}
 
+	if os.Getenv("DISCORD_CLIENT_ID") != "" && os.Getenv("DISCORD_CLIENT_SECRET") != "" {
+		providers = append(providers, discord.New(
+			os.Getenv("DISCORD_CLIENT_ID"),
+			os.Getenv("DISCORD_CLIENT_SECRET"),
+			baseURL+"/api/v1/auth/discord/callback",
+			discord.ScopeIdentify, discord.ScopeEmail,
+		))
+	}
+
+	if os.Getenv("GITLAB_CLIENT_ID") != "" && os.Getenv("GITLAB_CLIENT_SECRET") != "" {
+		providers = append(providers, gitlab.New(
+			os.Getenv("GITLAB_CLIENT_ID"),
+			os.Getenv("GITLAB_CLIENT_SECRET"),
+			baseURL+"/api/v1/auth/gitlab/callback",
+			"read_user",
+		))
+	}
+
 	goth.UseProviders(providers...)
 	return nil
 }
 
-// creates a JWT token for the user
+// the cookie holding an OAuth flow's state between the redirect to the
+// provider and its callback. it is the only cookie the API sets: signed-in
+// requests carry a bearer token, which browsers never attach on their own, so
+// other sites can't make requests in a user's name. Lax rather than Strict,
+// since the provider's redirect back is a cross-site navigation and the
+// callback needs the cookie to check the state
+func oauthCookieOptions(baseURL string) *sessions.Options {
+	// only sent along to the auth routes, under BASE_URL's path if it has one
+	path := "/api/v1/auth/"
+	if u, err := url.Parse(baseURL); err == nil {
+		path = strings.TrimSuffix(u.Path, "/") + path
+	}
+
+	return &sessions.Options{
+		Path:     path,
+		MaxAge:   300, // 5 minutes, enough for OAuth flow
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(baseURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// creates a short-lived JWT access token for the user
 func GenerateJWT(userID, email string, isAdmin bool) (string, error) {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -93,7 +127,7 @@ func GenerateJWT(userID, email string, isAdmin bool) (string, error) {
 		Email:   email,
 		IsAdmin: isAdmin,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)), // 7 days
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"net/http"
 	"os"
 	"strings"
 	"testing"
@@ -165,12 +166,12 @@ func TestJWT_TokenExpiration(t *testing.T) {
 	claims, err := ValidateJWT(token)
 	require.NoError(t, err)
 
-	// verify expiration is set to 7 days
-	expectedExpiry := time.Now().Add(7 * 24 * time.Hour)
+	// verify expiration is set to the access token lifetime
+	expectedExpiry := time.Now().Add(AccessTokenTTL)
 	actualExpiry := claims.ExpiresAt.Time
 	timeDiff := actualExpiry.Sub(expectedExpiry).Abs()
 
-	assert.Less(t, timeDiff, 5*time.Second, "expiration should be approximately 7 days from now")
+	assert.Less(t, timeDiff, 5*time.Second, "expiration should be approximately AccessTokenTTL from now")
 }
 
 func TestJWT_ClaimsIntegrity(t *testing.T) {
@@ -222,3 +223,32 @@ func TestGenerateJWT_AdminFlag(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, claims.IsAdmin, "IsAdmin should be false for regular user")
 }
+
+func TestRefreshToken_GenerationAndHashing(t *testing.T) {
+	first, err := newRefreshToken()
+	require.NoError(t, err)
+
+	second, err := newRefreshToken()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second, "refresh tokens should be random")
+	assert.GreaterOrEqual(t, len(first), 43, "refresh token should encode 32 bytes")
+
+	// the stored hash must be stable and must not reveal the token
+	assert.Equal(t, hashRefreshToken(first), hashRefreshToken(first))
+	assert.NotEqual(t, hashRefreshToken(first), hashRefreshToken(second))
+	assert.NotContains(t, hashRefreshToken(first), first)
+}
+
+func TestOAuthCookieOptions(t *testing.T) {
+	options := oauthCookieOptions("https://api.algopatterns.cc")
+
+	assert.Equal(t, http.SameSiteLaxMode, options.SameSite, "the provider's redirect back must carry the cookie")
+	assert.True(t, options.HttpOnly)
+	assert.True(t, options.Secure)
+	assert.Equal(t, "/api/v1/auth/", options.Path)
+
+	options = oauthCookieOptions("http://localhost:8080/backend/")
+	assert.False(t, options.Secure)
+	assert.Equal(t, "/backend/api/v1/auth/", options.Path)
+}
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// approves or denies a pending device code.
+// returns 1 if the code was pending, 0 if it expired or was already decided
+var decideDeviceScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if status ~= ARGV[3] then
+	return 0
+end
+
+redis.call('HSET', KEYS[1], 'status', ARGV[1], 'user_id', ARGV[2])
+return 1
+`)
+
+// checks a device code on behalf of the polling client. approved and denied
+// codes are removed, so only one poll gets the result. uses the Redis clock
+// so every instance sees the same time.
+// returns {status} or {"approved", user ID}, with status one of "expired",
+// "slow_down", "pending" or "denied"
+var pollDeviceScript = redis.NewScript(`
+local minInterval = tonumber(ARGV[1])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+
+local device = redis.call('HMGET', KEYS[1], 'status', 'user_id', 'last_poll')
+if not device[1] then
+	return {'expired'}
+end
+
+local lastPoll = tonumber(device[3])
+redis.call('HSET', KEYS[1], 'last_poll', tostring(now))
+
+if lastPoll and now - lastPoll < minInterval then
+	return {'slow_down'}
+end
+
+if device[1] == 'approved' then
+	redis.call('DEL', KEYS[1])
+	return {'approved', device[2]}
+end
+
+if device[1] == 'denied' then
+	redis.call('DEL', KEYS[1])
+	return {'denied'}
+end
+
+return {'pending'}
+`)
+
+// holds device codes in Redis. like refresh tokens, only a hash of the
+// device code is stored
+type DeviceStore struct {
+	client redis.UniversalClient
+}
+
+func NewDeviceStore(client redis.UniversalClient) *DeviceStore {
+	return &DeviceStore{client: client}
+}
+
+// starts a device sign-in
+func (s *DeviceStore) Start(ctx context.Context) (*DeviceAuthorization, error) {
+	deviceCode, err := newDeviceCode()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashRefreshToken(deviceCode)
+
+	for range userCodeMaxRetries {
+		userCode, err := newUserCode()
+		if err != nil {
+			return nil, err
+		}
+
+		// the user code must not point at another pending sign-in
+		claimed, err := s.client.SetNX(ctx, fmt.Sprintf(keyDeviceUserCode, userCode), hash, DeviceCodeTTL).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to store user code: %w", err)
+		}
+
+		if !claimed {
+			continue
+		}
+
+		deviceKey := fmt.Sprintf(keyDeviceCode, hash)
+
+		pipe := s.client.Pipeline()
+		pipe.HSet(ctx, deviceKey, "status", deviceStatusPending, "user_code", userCode)
+		pipe.Expire(ctx, deviceKey, DeviceCodeTTL)
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to store device code: %w", err)
+		}
+
+		return &DeviceAuthorization{
+			DeviceCode: deviceCode,
+			UserCode:   FormatUserCode(userCode),
+		}, nil
+	}
+
+	return nil, errors.New("failed to generate an unused user code")
+}
+
+// approves the sign-in of the user code for the user
+func (s *DeviceStore) Approve(ctx context.Context, userCode, userID string) error {
+	return s.decide(ctx, userCode, deviceStatusApproved, userID)
+}
+
+// denies the sign-in of the user code
+func (s *DeviceStore) Deny(ctx context.Context, userCode string) error {
+	return s.decide(ctx, userCode, deviceStatusDenied, "")
+}
+
+func (s *DeviceStore) decide(ctx context.Context, userCode, status, userID string) error {
+	code := NormalizeUserCode(userCode)
+	if len(code) != userCodeLength {
+		return ErrInvalidUserCode
+	}
+
+	// a user code can be used once
+	hash, err := s.client.GetDel(ctx, fmt.Sprintf(keyDeviceUserCode, code)).Result()
+	if errors.Is(err, redis.Nil) {
+		return ErrInvalidUserCode
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read user code: %w", err)
+	}
+
+	decided, err := decideDeviceScript.Run(ctx, s.client, []string{fmt.Sprintf(keyDeviceCode, hash)},
+		status, userID, deviceStatusPending).Int()
+	if err != nil {
+		return fmt.Errorf("failed to update device code: %w", err)
+	}
+
+	if decided == 0 {
+		return ErrInvalidUserCode
+	}
+
+	return nil
+}
+
+// checks whether the sign-in of the device code was approved and returns the
+// approving user. until then it fails with ErrAuthorizationPending, or
+// ErrSlowDown if the client polls more often than DevicePollInterval
+func (s *DeviceStore) Poll(ctx context.Context, deviceCode string) (string, error) {
+	key := fmt.Sprintf(keyDeviceCode, hashRefreshToken(deviceCode))
+
+	// a second of slack so clients polling at the interval aren't told off for jitter
+	minInterval := (DevicePollInterval - time.Second).Seconds()
+
+	result, err := pollDeviceScript.Run(ctx, s.client, []string{key}, minInterval).StringSlice()
+	if err != nil {
+		return "", fmt.Errorf("failed to read device code: %w", err)
+	}
+
+	switch result[0] {
+	case deviceStatusApproved:
+		if len(result) < 2 || result[1] == "" {
+			return "", ErrDeviceCodeExpired
+		}
+		return result[1], nil
+	case deviceStatusDenied:
+		return "", ErrAccessDenied
+	case deviceStatusPending:
+		return "", ErrAuthorizationPending
+	case "slow_down":
+		return "", ErrSlowDown
+	default:
+		return "", ErrDeviceCodeExpired
+	}
+}
+
+// strips the separator and spacing users may type, so "bcdf-ghjk" matches
+// "BCDFGHJK"
+func NormalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '-' || r == ' ':
+			return -1
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return r
+		}
+	}, code)
+}
+
+// formats a user code for display, as XXXX-XXXX
+func FormatUserCode(code string) string {
+	half := len(code) / 2
+	return code[:half] + "-" + code[half:]
+}
+
+func newDeviceCode() (string, error) {
+	b := make([]byte, deviceCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate device code: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func newUserCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+
+	code := make([]byte, userCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate user code: %w", err)
+		}
+		code[i] = userCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
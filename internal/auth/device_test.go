@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUserCode(t *testing.T) {
+	code, err := newUserCode()
+
+	require.NoError(t, err)
+	assert.Len(t, code, userCodeLength)
+	for _, r := range code {
+		assert.True(t, strings.ContainsRune(userCodeAlphabet, r), "unexpected character %q", r)
+	}
+}
+
+func TestUserCodeRoundTrip(t *testing.T) {
+	formatted := FormatUserCode("BCDFGHJK")
+
+	assert.Equal(t, "BCDF-GHJK", formatted)
+	assert.Equal(t, "BCDFGHJK", NormalizeUserCode(formatted))
+	assert.Equal(t, "BCDFGHJK", NormalizeUserCode(" bcdf-ghjk "))
+	assert.Equal(t, "BCDFGHJK", NormalizeUserCode("bcdf ghjk"))
+}
+
+func TestNewDeviceCodeIsUnique(t *testing.T) {
+	first, err := newDeviceCode()
+	require.NoError(t, err)
+
+	second, err := newDeviceCode()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.NotEqual(t, first, hashRefreshToken(first), "only the hash should be stored")
+}
@@ -83,8 +83,9 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 	}
 }
 
-// requires authenticated user with admin role
-func AdminAuthMiddleware() gin.HandlerFunc {
+// requires authenticated user with admin role. the role in the token is
+// confirmed with roles so revoked or banned admins lose access immediately.
+func AdminAuthMiddleware(roles AdminChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// First check if user is authenticated
 		authHeader := c.GetHeader("Authorization")
@@ -116,6 +117,19 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		isAdmin, err := roles.IsActiveAdmin(c.Request.Context(), claims.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify admin role"})
+			c.Abort()
+			return
+		}
+
+		if !isAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
 		c.Set("is_admin", claims.IsAdmin)
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// holds refresh tokens in Redis. only a hash of each token is stored, and
+// every token can be used once: rotating it issues a replacement.
+type RefreshStore struct {
+	client redis.UniversalClient
+}
+
+func NewRefreshStore(client redis.UniversalClient) *RefreshStore {
+	return &RefreshStore{client: client}
+}
+
+// creates a new refresh token for the user
+func (s *RefreshStore) Issue(ctx context.Context, userID string) (string, error) {
+	token, err := newRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	hash := hashRefreshToken(token)
+	userKey := fmt.Sprintf(keyUserRefreshTokens, userID)
+
+	// a plain pipeline: the keys live in different Redis Cluster slots, which
+	// one transaction can't span
+	pipe := s.client.Pipeline()
+	pipe.Set(ctx, fmt.Sprintf(keyRefreshToken, hash), userID, RefreshTokenTTL)
+	pipe.SAdd(ctx, userKey, hash)
+	pipe.Expire(ctx, userKey, RefreshTokenTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// exchanges a refresh token for a new one and returns the owning user.
+// presenting a token that was already rotated means it leaked, so every
+// refresh token of the user is revoked.
+func (s *RefreshStore) Rotate(ctx context.Context, token string) (userID, newToken string, err error) {
+	hash := hashRefreshToken(token)
+
+	// GETDEL makes sure only one request can rotate a token
+	userID, err = s.client.GetDel(ctx, fmt.Sprintf(keyRefreshToken, hash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", "", s.checkReuse(ctx, hash)
+	}
+
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read refresh token: %w", err)
+	}
+
+	pipe := s.client.Pipeline()
+	pipe.SRem(ctx, fmt.Sprintf(keyUserRefreshTokens, userID), hash)
+	pipe.Set(ctx, fmt.Sprintf(keyUsedRefreshToken, hash), userID, RefreshTokenTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", "", fmt.Errorf("failed to retire refresh token: %w", err)
+	}
+
+	newToken, err = s.Issue(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return userID, newToken, nil
+}
+
+// revokes a single refresh token. unknown tokens are ignored.
+func (s *RefreshStore) Revoke(ctx context.Context, token string) error {
+	hash := hashRefreshToken(token)
+
+	userID, err := s.client.GetDel(ctx, fmt.Sprintf(keyRefreshToken, hash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	return s.client.SRem(ctx, fmt.Sprintf(keyUserRefreshTokens, userID), hash).Err()
+}
+
+// revokes every refresh token of the user
+func (s *RefreshStore) RevokeAll(ctx context.Context, userID string) error {
+	userKey := fmt.Sprintf(keyUserRefreshTokens, userID)
+
+	hashes, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list refresh tokens: %w", err)
+	}
+
+	// one DEL per key, since they can be in different cluster slots
+	pipe := s.client.Pipeline()
+	for _, hash := range hashes {
+		pipe.Del(ctx, fmt.Sprintf(keyRefreshToken, hash))
+	}
+
+	pipe.Del(ctx, userKey)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// handles a token that isn't active: revokes the user's tokens if it was
+// rotated before, and reports it as invalid either way
+func (s *RefreshStore) checkReuse(ctx context.Context, hash string) error {
+	userID, err := s.client.Get(ctx, fmt.Sprintf(keyUsedRefreshToken, hash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return ErrInvalidRefreshToken
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read refresh token: %w", err)
+	}
+
+	if err := s.RevokeAll(ctx, userID); err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// what a WebSocket ticket vouches for
+type wsTicket struct {
+	UserID    string `json:"u"`
+	Nonce     string `json:"n"`
+	ExpiresAt int64  `json:"e"` // unix seconds
+}
+
+// issues and redeems one-time WebSocket tickets. a ticket is signed, so it
+// is checked without a lookup; redeeming records its nonce in Redis until it
+// expires, so it can't be used twice
+type TicketStore struct {
+	client redis.UniversalClient
+}
+
+func NewTicketStore(client redis.UniversalClient) *TicketStore {
+	return &TicketStore{client: client}
+}
+
+// creates a ticket for the user, valid for WSTicketTTL
+func (s *TicketStore) Issue(userID string) (string, error) {
+	key, err := ticketKey()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, wsTicketNonceBytes)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate ticket: %w", err)
+	}
+
+	payload, err := json.Marshal(wsTicket{
+		UserID:    userID,
+		Nonce:     base64.RawURLEncoding.EncodeToString(nonce),
+		ExpiresAt: time.Now().Add(WSTicketTTL).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode ticket: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + sign(key, encoded), nil
+}
+
+// checks a ticket and uses it up. returns the user it was issued to, or
+// ErrInvalidWSTicket
+func (s *TicketStore) Redeem(ctx context.Context, ticket string) (string, error) {
+	t, err := parseTicket(ticket, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	ttl := time.Until(time.Unix(t.ExpiresAt, 0)) + time.Second
+
+	// SET NX makes sure only one connection can use a ticket
+	fresh, err := s.client.SetNX(ctx, fmt.Sprintf(keyUsedWSTicket, t.Nonce), t.UserID, ttl).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to redeem ticket: %w", err)
+	}
+
+	if !fresh {
+		return "", ErrInvalidWSTicket
+	}
+
+	return t.UserID, nil
+}
+
+// verifies a ticket's signature and expiry
+func parseTicket(ticket string, now time.Time) (*wsTicket, error) {
+	key, err := ticketKey()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, signature, found := strings.Cut(ticket, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(sign(key, encoded))) {
+		return nil, ErrInvalidWSTicket
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidWSTicket
+	}
+
+	var t wsTicket
+	if err := json.Unmarshal(payload, &t); err != nil || t.UserID == "" || t.Nonce == "" {
+		return nil, ErrInvalidWSTicket
+	}
+
+	if now.Unix() > t.ExpiresAt {
+		return nil, ErrInvalidWSTicket
+	}
+
+	return &t, nil
+}
+
+// the signing key, derived from JWT_SECRET so a ticket can never pass for an
+// access token or the other way around
+func ticketKey() ([]byte, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, fmt.Errorf("JWT_SECRET not set")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("websocket ticket"))
+
+	return mac.Sum(nil), nil
+}
+
+func sign(key []byte, data string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// answers SET NX from memory, the only command redeeming a ticket sends
+type setNXHook struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (h *setNXHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *setNXHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		set, ok := cmd.(*redis.BoolCmd)
+		if !ok || cmd.Name() != "set" {
+			return next(ctx, cmd)
+		}
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		key := cmd.Args()[1].(string)
+		set.SetVal(!h.keys[key])
+		h.keys[key] = true
+
+		return nil
+	}
+}
+
+func (h *setNXHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func newTestTicketStore(t *testing.T) *TicketStore {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret-key-for-testing")
+
+	// nothing listens here, the hook answers instead
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	client.AddHook(&setNXHook{keys: map[string]bool{}})
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck,gosec // test cleanup
+
+	return NewTicketStore(client)
+}
+
+func TestTicketRedeemsOnce(t *testing.T) {
+	store := newTestTicketStore(t)
+
+	ticket, err := store.Issue("user-123")
+	require.NoError(t, err)
+
+	userID, err := store.Redeem(context.Background(), ticket)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", userID)
+
+	_, err = store.Redeem(context.Background(), ticket)
+	assert.ErrorIs(t, err, ErrInvalidWSTicket, "a replayed ticket is refused")
+}
+
+func TestTicketRejectsTampering(t *testing.T) {
+	store := newTestTicketStore(t)
+
+	ticket, err := store.Issue("user-123")
+	require.NoError(t, err)
+
+	other, err := store.Issue("user-456")
+	require.NoError(t, err)
+
+	payload, _, _ := strings.Cut(ticket, ".")
+	_, signature, _ := strings.Cut(other, ".")
+
+	for name, forged := range map[string]string{
+		"swapped signature": payload + "." + signature,
+		"unsigned":          payload,
+		"empty":             "",
+	} {
+		_, err := store.Redeem(context.Background(), forged)
+		assert.ErrorIs(t, err, ErrInvalidWSTicket, name)
+	}
+
+	t.Setenv("JWT_SECRET", "another-secret")
+	_, err = store.Redeem(context.Background(), ticket)
+	assert.ErrorIs(t, err, ErrInvalidWSTicket, "tickets are bound to the server's secret")
+}
+
+func TestTicketExpires(t *testing.T) {
+	store := newTestTicketStore(t)
+
+	ticket, err := store.Issue("user-123")
+	require.NoError(t, err)
+
+	_, err = parseTicket(ticket, time.Now().Add(WSTicketTTL-time.Second))
+	require.NoError(t, err)
+
+	_, err = parseTicket(ticket, time.Now().Add(WSTicketTTL+time.Second))
+	assert.ErrorIs(t, err, ErrInvalidWSTicket)
+}
+
+func TestTicketIsNotAnAccessToken(t *testing.T) {
+	store := newTestTicketStore(t)
+
+	ticket, err := store.Issue("user-123")
+	require.NoError(t, err)
+
+	_, err = ValidateJWT(ticket)
+	assert.Error(t, err)
+
+	token, err := GenerateJWT("user-123", "test@example.com", false)
+	require.NoError(t, err)
+
+	_, err = store.Redeem(context.Background(), token)
+	assert.ErrorIs(t, err, ErrInvalidWSTicket)
+}
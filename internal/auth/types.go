@@ -1,9 +1,80 @@
 package auth
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	// access tokens are short-lived, clients renew them with a refresh token
+	AccessTokenTTL = 15 * time.Minute
+
+	// refresh tokens expire if unused for this long
+	RefreshTokenTTL = 30 * 24 * time.Hour
+
+	refreshTokenBytes = 32
+
+	// device codes let clients without a browser, like the TUI, sign in: the
+	// user approves the code shown by the client on the website
+	DeviceCodeTTL      = 10 * time.Minute
+	DevicePollInterval = 5 * time.Second
+
+	deviceCodeBytes    = 32
+	userCodeLength     = 8
+	userCodeAlphabet   = "BCDFGHJKLMNPQRSTVWXZ" // no vowels, so codes don't spell words
+	userCodeMaxRetries = 5
+
+	// WebSocket tickets stand in for the access token in the upgrade URL,
+	// where it would end up in logs and browser history. they only need to
+	// last until the client connects
+	WSTicketTTL = 30 * time.Second
+
+	wsTicketNonceBytes = 16
+)
+
+// redis keys, all keyed by the SHA-256 of the token except the per-user set
+// and the ticket nonces
+const (
+	keyRefreshToken      = "auth:refresh:%s"      // -> user ID
+	keyUsedRefreshToken  = "auth:refresh:used:%s" // -> user ID, for reuse detection
+	keyUserRefreshTokens = "auth:refresh:user:%s" // set of token hashes
+
+	keyDeviceCode     = "auth:device:%s"      // hash of status, user_code, user_id and last_poll
+	keyDeviceUserCode = "auth:device:user:%s" // normalized user code -> device code hash
+
+	keyUsedWSTicket = "auth:ws-ticket:used:%s" // ticket nonce, kept until the ticket expires
+)
+
+// states of a device code
+const (
+	deviceStatusPending  = "pending"
+	deviceStatusApproved = "approved"
+	deviceStatusDenied   = "denied"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused, all sessions revoked")
+
+	ErrInvalidUserCode      = errors.New("invalid or expired user code")
+	ErrAuthorizationPending = errors.New("authorization pending")
+	ErrSlowDown             = errors.New("polling too fast")
+	ErrAccessDenied         = errors.New("access denied")
+	ErrDeviceCodeExpired    = errors.New("device code expired")
+
+	ErrInvalidWSTicket = errors.New("invalid, expired or used websocket ticket")
+)
+
+// a pending device sign-in. the device code stays with the client, which
+// polls with it; the user code is what the user enters on the website
+type DeviceAuthorization struct {
+	DeviceCode string
+	UserCode   string
+}
+
 // represents JWT claims
 type Claims struct {
 	UserID  string `json:"user_id"`
@@ -11,3 +82,8 @@ type Claims struct {
 	IsAdmin bool   `json:"is_admin"`
 	jwt.RegisteredClaims
 }
+
+// looks up a user's current admin role, since token claims can be stale until the token is refreshed
+type AdminChecker interface {
+	IsActiveAdmin(ctx context.Context, userID string) (bool, error)
+}
@@ -0,0 +1,115 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reads Stripe settings from the environment. billing stays disabled
+// unless the secret key, webhook secret and price are all set.
+func ConfigFromEnv() Config {
+	returnURL := os.Getenv("BILLING_RETURN_URL")
+	if returnURL == "" {
+		returnURL = "http://localhost:3000/settings/billing"
+	}
+
+	return Config{
+		SecretKey:     os.Getenv("STRIPE_SECRET_KEY"),
+		WebhookSecret: os.Getenv("STRIPE_WEBHOOK_SECRET"),
+		PriceID:       os.Getenv("STRIPE_PRICE_ID"),
+		ReturnURL:     returnURL,
+	}
+}
+
+// creates a new billing service
+func New(db *pgxpool.Pool, config Config) *Service {
+	return &Service{
+		db:     db,
+		config: config,
+		client: &http.Client{Timeout: apiTimeout},
+	}
+}
+
+// reports whether Stripe is configured
+func (s *Service) Enabled() bool {
+	return s.config.SecretKey != "" && s.config.WebhookSecret != "" && s.config.PriceID != ""
+}
+
+// starts a Stripe Checkout for the payg subscription and returns its URL.
+// creates the user's Stripe customer on first use.
+func (s *Service) CreateCheckout(ctx context.Context, userID string) (string, error) {
+	if !s.Enabled() {
+		return "", ErrNotConfigured
+	}
+
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if account.tier == TierPAYG && account.status != nil && paidStatuses[*account.status] {
+		return "", ErrAlreadySubscribed
+	}
+
+	customerID := ""
+	if account.customerID != nil {
+		customerID = *account.customerID
+	} else {
+		created, err := s.createCustomer(ctx, userID, account.email)
+		if err != nil {
+			return "", err
+		}
+
+		// another request may have linked a customer meanwhile; use whichever was stored
+		if err := s.db.QueryRow(ctx, querySetCustomer, userID, created).Scan(&customerID); err != nil {
+			return "", fmt.Errorf("failed to save stripe customer: %w", err)
+		}
+	}
+
+	return s.createCheckoutSession(ctx, userID, customerID)
+}
+
+// opens the Stripe Billing Portal for the user and returns its URL
+func (s *Service) CreatePortal(ctx context.Context, userID string) (string, error) {
+	if !s.Enabled() {
+		return "", ErrNotConfigured
+	}
+
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if account.customerID == nil {
+		return "", ErrNoCustomer
+	}
+
+	return s.createPortalSession(ctx, *account.customerID)
+}
+
+type billingAccount struct {
+	email      string
+	tier       string
+	customerID *string
+	status     *string
+}
+
+func (s *Service) getAccount(ctx context.Context, userID string) (*billingAccount, error) {
+	var a billingAccount
+
+	err := s.db.QueryRow(ctx, queryGetBillingAccount, userID).Scan(&a.email, &a.tier, &a.customerID, &a.status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get billing account: %w", err)
+	}
+
+	return &a, nil
+}
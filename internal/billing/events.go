@@ -0,0 +1,139 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// verifies and applies a Stripe webhook event.
+// events are processed once; redeliveries of a processed event are acknowledged without effect.
+func (s *Service) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	if !s.Enabled() {
+		return ErrNotConfigured
+	}
+
+	if err := verifySignature(payload, signature, s.config.WebhookSecret, time.Now()); err != nil {
+		return err
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil || event.ID == "" {
+		return ErrMalformedEvent
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck,gosec // no-op after commit
+
+	tag, err := tx.Exec(ctx, queryRecordEvent, event.ID, event.Type)
+	if err != nil {
+		return fmt.Errorf("failed to record billing event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil // already processed
+	}
+
+	switch event.Type {
+	case eventCheckoutCompleted:
+		err = applyCheckout(ctx, tx, event)
+	case eventSubscriptionCreated, eventSubscriptionUpdated, eventSubscriptionDeleted:
+		err = applySubscription(ctx, tx, event)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// links the customer to the user who started the checkout
+// (normally already linked when the checkout was created)
+func applyCheckout(ctx context.Context, tx pgx.Tx, event Event) error {
+	var session checkoutSession
+	if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+		return ErrMalformedEvent
+	}
+
+	if session.Mode != "subscription" || session.ClientReferenceID == "" || session.Customer == "" {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, queryLinkCheckout, session.ClientReferenceID, session.Customer); err != nil {
+		return fmt.Errorf("failed to link checkout customer: %w", err)
+	}
+
+	return nil
+}
+
+// moves the subscriber between tiers according to the subscription status
+func applySubscription(ctx context.Context, tx pgx.Tx, event Event) error {
+	var sub subscription
+	if err := json.Unmarshal(event.Data.Object, &sub); err != nil || sub.Customer == "" {
+		return ErrMalformedEvent
+	}
+
+	// a deleted subscription can carry any final status, but never keeps the paid tier
+	paid := paidStatuses[sub.Status] && event.Type != eventSubscriptionDeleted
+	eventAt := time.Unix(event.Created, 0).UTC()
+
+	var userID, oldTier, newTier string
+	err := tx.QueryRow(ctx, queryApplySubscription, sub.Customer, sub.ID, sub.Status, paid, eventAt).Scan(&userID, &oldTier, &newTier)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		var known bool
+		if err := tx.QueryRow(ctx, queryCustomerExists, sub.Customer).Scan(&known); err != nil {
+			return fmt.Errorf("failed to look up stripe customer: %w", err)
+		}
+
+		if known {
+			return nil // a newer event was already applied
+		}
+
+		// customer created outside the checkout flow; fall back to the user in the metadata
+		metadataUserID := sub.Metadata["user_id"]
+		if metadataUserID == "" {
+			logger.Warn("ignoring subscription for unknown stripe customer", "customer", sub.Customer, "subscription", sub.ID)
+			return nil
+		}
+
+		tag, err := tx.Exec(ctx, queryLinkCheckout, metadataUserID, sub.Customer)
+		if err != nil {
+			return fmt.Errorf("failed to link stripe customer: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			logger.Warn("ignoring subscription for unknown user", "user_id", metadataUserID, "subscription", sub.ID)
+			return nil
+		}
+
+		err = tx.QueryRow(ctx, queryApplySubscription, sub.Customer, sub.ID, sub.Status, paid, eventAt).Scan(&userID, &oldTier, &newTier)
+		if err != nil {
+			return fmt.Errorf("failed to apply subscription: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to apply subscription: %w", err)
+	}
+
+	if oldTier != newTier {
+		if _, err := tx.Exec(ctx, queryRecordTierChange, userID, oldTier, newTier, "subscription_"+sub.Status); err != nil {
+			return fmt.Errorf("failed to record tier change: %w", err)
+		}
+
+		logger.Info("subscription changed tier",
+			"user_id", userID,
+			"old_tier", oldTier,
+			"new_tier", newTier,
+			"status", sub.Status,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package billing
+
+const (
+	queryGetBillingAccount = `
+		SELECT email, COALESCE(tier, 'free'), stripe_customer_id, subscription_status
+		FROM users
+		WHERE id = $1
+	`
+
+	// keeps an existing customer if another request linked one first
+	querySetCustomer = `
+		UPDATE users
+		SET stripe_customer_id = COALESCE(stripe_customer_id, $2), updated_at = NOW()
+		WHERE id = $1
+		RETURNING stripe_customer_id
+	`
+
+	queryRecordEvent = `
+		INSERT INTO billing_events (id, type)
+		VALUES ($1, $2)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	// links the customer from a completed checkout to the user who started it
+	queryLinkCheckout = `
+		UPDATE users
+		SET stripe_customer_id = $2, updated_at = NOW()
+		WHERE id = $1
+		AND (stripe_customer_id IS NULL OR stripe_customer_id = $2)
+	`
+
+	// applies a subscription change unless a newer event was already applied.
+	// moves the user to payg while paid, and back to free afterwards
+	// (users on another tier, e.g. byok, keep it when a subscription ends).
+	queryApplySubscription = `
+		WITH target AS (
+			SELECT id, tier
+			FROM users
+			WHERE stripe_customer_id = $1
+			AND (subscription_event_at IS NULL OR subscription_event_at <= $5)
+			FOR UPDATE
+		)
+		UPDATE users u
+		SET
+			stripe_subscription_id = $2,
+			subscription_status = $3,
+			subscription_event_at = $5,
+			tier = CASE
+				WHEN $4 THEN 'payg'
+				WHEN u.tier = 'payg' THEN 'free'
+				ELSE u.tier
+			END,
+			updated_at = NOW()
+		FROM target
+		WHERE u.id = target.id
+		RETURNING u.id, COALESCE(target.tier, 'free'), u.tier
+	`
+
+	queryCustomerExists = `
+		SELECT EXISTS (SELECT 1 FROM users WHERE stripe_customer_id = $1)
+	`
+
+	queryRecordTierChange = `
+		INSERT INTO tier_changes (user_id, old_tier, new_tier, reason)
+		VALUES ($1, $2, $3, $4)
+	`
+)
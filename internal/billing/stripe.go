@@ -0,0 +1,97 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// creates a Stripe customer for a user
+func (s *Service) createCustomer(ctx context.Context, userID, email string) (string, error) {
+	form := url.Values{}
+	form.Set("email", email)
+	form.Set("metadata[user_id]", userID)
+
+	var c customer
+	if err := s.post(ctx, "/customers", form, "customer-"+userID, &c); err != nil {
+		return "", err
+	}
+
+	return c.ID, nil
+}
+
+// creates a subscription Checkout Session for the payg price
+func (s *Service) createCheckoutSession(ctx context.Context, userID, customerID string) (string, error) {
+	form := url.Values{}
+	form.Set("mode", "subscription")
+	form.Set("customer", customerID)
+	form.Set("client_reference_id", userID)
+	form.Set("line_items[0][price]", s.config.PriceID) // metered, so no quantity
+	form.Set("subscription_data[metadata][user_id]", userID)
+	form.Set("success_url", withQuery(s.config.ReturnURL, "checkout", "success"))
+	form.Set("cancel_url", withQuery(s.config.ReturnURL, "checkout", "cancelled"))
+
+	var session checkoutSession
+	if err := s.post(ctx, "/checkout/sessions", form, "", &session); err != nil {
+		return "", err
+	}
+
+	return session.URL, nil
+}
+
+// creates a Billing Portal session where the customer manages or cancels the subscription
+func (s *Service) createPortalSession(ctx context.Context, customerID string) (string, error) {
+	form := url.Values{}
+	form.Set("customer", customerID)
+	form.Set("return_url", s.config.ReturnURL)
+
+	var session portalSession
+	if err := s.post(ctx, "/billing_portal/sessions", form, "", &session); err != nil {
+		return "", err
+	}
+
+	return session.URL, nil
+}
+
+// sends a form-encoded request to the Stripe API and decodes the response into out
+func (s *Service) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build stripe request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+s.config.SecretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read stripe response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr apiError
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("stripe %s (%d): %s", path, resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("stripe %s: unexpected status %d", path, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode stripe response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package billing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// tiers set by subscriptions (users.tier)
+const (
+	TierFree = "free"
+	TierPAYG = "payg"
+)
+
+// Stripe subscription statuses that keep the paid tier.
+// past_due keeps it while Stripe retries the payment.
+var paidStatuses = map[string]bool{
+	"active":   true,
+	"trialing": true,
+	"past_due": true,
+}
+
+// webhook events handled
+const (
+	eventCheckoutCompleted   = "checkout.session.completed"
+	eventSubscriptionCreated = "customer.subscription.created"
+	eventSubscriptionUpdated = "customer.subscription.updated"
+	eventSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+// Stripe API constants
+const (
+	apiBaseURL = "https://api.stripe.com/v1"
+	apiTimeout = 15 * time.Second
+
+	// header carrying the webhook signature ("t=<unix>,v1=<hex hmac>")
+	SignatureHeader = "Stripe-Signature"
+
+	// webhooks signed longer ago than this are rejected (replay protection)
+	signatureTolerance = 5 * time.Minute
+)
+
+// errors
+var (
+	ErrNotConfigured     = errors.New("billing is not configured")
+	ErrInvalidSignature  = errors.New("invalid webhook signature")
+	ErrNoCustomer        = errors.New("no billing account, subscribe first")
+	ErrAlreadySubscribed = errors.New("already subscribed, manage the subscription in the billing portal")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUnknownSubscriber = errors.New("subscription does not belong to a known user")
+	ErrMalformedEvent    = errors.New("malformed webhook event")
+)
+
+// Stripe settings, read from the environment
+type Config struct {
+	SecretKey     string // STRIPE_SECRET_KEY
+	WebhookSecret string // STRIPE_WEBHOOK_SECRET
+	PriceID       string // STRIPE_PRICE_ID, the metered payg price
+	ReturnURL     string // BILLING_RETURN_URL, where users land after checkout or the portal
+}
+
+// creates checkout and portal sessions and applies subscription changes to user tiers
+type Service struct {
+	db     *pgxpool.Pool
+	config Config
+	client *http.Client
+}
+
+// a Stripe webhook event
+type Event struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"` // unix seconds
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// the fields used from a Checkout Session object
+type checkoutSession struct {
+	ID                string `json:"id"`
+	URL               string `json:"url"`
+	Mode              string `json:"mode"`
+	ClientReferenceID string `json:"client_reference_id"` // our user ID
+	Customer          string `json:"customer"`
+	Subscription      string `json:"subscription"`
+}
+
+// the fields used from a Subscription object
+type subscription struct {
+	ID       string            `json:"id"`
+	Customer string            `json:"customer"`
+	Status   string            `json:"status"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// the fields used from Customer and Billing Portal Session objects
+type customer struct {
+	ID string `json:"id"`
+}
+
+type portalSession struct {
+	URL string `json:"url"`
+}
+
+// error body returned by the Stripe API
+type apiError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
@@ -0,0 +1,65 @@
+package billing
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := "whsec_test"
+	payload := []byte(`{"id":"evt_1","type":"customer.subscription.updated"}`)
+	now := time.Unix(1_700_000_000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	valid := hex.EncodeToString(sign(secret, ts, payload))
+	other := hex.EncodeToString(sign("whsec_old", ts, payload))
+
+	tests := []struct {
+		name    string
+		header  string
+		payload []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{"valid", "t=" + ts + ",v1=" + valid, payload, now, false},
+		{"any v1 may match", "t=" + ts + ",v1=" + other + ",v1=" + valid, payload, now, false},
+		{"ignores v0 and spaces", "t=" + ts + ", v0=abc, v1=" + valid, payload, now, false},
+		{"wrong secret", "t=" + ts + ",v1=" + other, payload, now, true},
+		{"tampered payload", "t=" + ts + ",v1=" + valid, []byte(`{"id":"evt_2"}`), now, true},
+		{"too old", "t=" + ts + ",v1=" + valid, payload, now.Add(6 * time.Minute), true},
+		{"from the future", "t=" + ts + ",v1=" + valid, payload, now.Add(-6 * time.Minute), true},
+		{"missing timestamp", "v1=" + valid, payload, now, true},
+		{"missing signature", "t=" + ts, payload, now, true},
+		{"empty header", "", payload, now, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifySignature(tt.payload, tt.header, secret, tt.now)
+			if tt.wantErr && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("verifySignature() = %v, want ErrInvalidSignature", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("verifySignature() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://algopatterns.cc/settings/billing", "https://algopatterns.cc/settings/billing?checkout=success"},
+		{"https://algopatterns.cc/settings?tab=billing", "https://algopatterns.cc/settings?checkout=success&tab=billing"},
+	}
+
+	for _, tt := range tests {
+		if got := withQuery(tt.in, "checkout", "success"); got != tt.want {
+			t.Errorf("withQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -147,6 +147,7 @@ func DefaultConfig() *Config {
 			"/health",
 			"/healthz",
 			"/ready",
+			"/readyz",
 			"/metrics",
 			"/api/v1/ws", // websocket connections are persistent, not burst requests
 		},
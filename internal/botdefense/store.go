@@ -24,12 +24,12 @@ const (
 
 // manages trapped IPs and rate limits in Redis
 type Store struct {
-	client *redis.Client
+	client redis.UniversalClient
 	config *Config
 }
 
 // creates a new bot defense store
-func NewStore(client *redis.Client, config *Config) *Store {
+func NewStore(client redis.UniversalClient, config *Config) *Store {
 	return &Store{
 		client: client,
 		config: config,
@@ -85,7 +85,13 @@ func (s *Store) ReleaseIP(ctx context.Context, ip string) error {
 	trappedKey := fmt.Sprintf(keyTrappedIP, ip)
 	reasonKey := fmt.Sprintf(keyTrapReason, ip)
 
-	return s.client.Del(ctx, trappedKey, reasonKey).Err()
+	// separate deletes, the keys can be in different cluster slots
+	pipe := s.client.Pipeline()
+	pipe.Del(ctx, trappedKey)
+	pipe.Del(ctx, reasonKey)
+
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
 // increments the request count for an IP and returns the new count
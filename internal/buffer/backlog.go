@@ -0,0 +1,199 @@
+package buffer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// pops up to limit entries (all when limit is 0) from the head of a session's
+// list. the session leaves the dirty set before the list is checked and
+// rejoins it while entries remain, so entries appended meanwhile are never
+// stranded
+func (b *SessionBuffer) drainList(ctx context.Context, key, dirtyKey, sessionID string, limit int64) ([]string, error) {
+	items, err := b.client.LRange(ctx, key, 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := b.client.Pipeline()
+
+	var trim *redis.StatusCmd
+	if len(items) > 0 {
+		trim = pipe.LTrim(ctx, key, int64(len(items)), -1)
+	}
+	pipe.SRem(ctx, dirtyKey, sessionID)
+	remaining := pipe.LLen(ctx, key)
+
+	pipe.Exec(ctx) //nolint:errcheck,gosec // checked per command below
+
+	// still in the list, the next pass gets them
+	if trim != nil && trim.Err() != nil {
+		return nil, trim.Err()
+	}
+
+	if remaining.Err() != nil || remaining.Val() > 0 {
+		b.client.SAdd(ctx, dirtyKey, sessionID) //nolint:errcheck,gosec // the next write marks it again
+	}
+
+	return items, nil
+}
+
+// puts entries that failed to flush back at the head of their sessions'
+// lists, in their original order. putBack does the same in memory while
+// redis is unreachable
+func requeue[T any](
+	ctx context.Context,
+	b *SessionBuffer,
+	keyFormat, dirtyKey string,
+	entries []T,
+	sessionOf func(*T) string,
+	putBack func(m *memoryBuffer),
+) error {
+	if b.inMemory(putBack) {
+		return nil
+	}
+
+	pipe := b.client.Pipeline()
+
+	// pushing to the head reverses, so push the newest first
+	for i := len(entries) - 1; i >= 0; i-- {
+		entryJSON, err := json.Marshal(&entries[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal buffered entry: %w", err)
+		}
+
+		sessionID := sessionOf(&entries[i])
+		pipe.LPush(ctx, fmt.Sprintf(keyFormat, sessionID), entryJSON)
+		pipe.SAdd(ctx, dirtyKey, sessionID)
+	}
+
+	_, err := pipe.Exec(ctx)
+	if b.fallBack(ctx, err, putBack) {
+		return nil
+	}
+
+	return err
+}
+
+// the sessions in a dirty set, or those with entries in memory while redis
+// is unreachable
+func (b *SessionBuffer) dirtySessions(ctx context.Context, dirtyKey string, inMemory func(m *memoryBuffer) []string) ([]string, error) {
+	var sessionIDs []string
+	read := func(m *memoryBuffer) { sessionIDs = inMemory(m) }
+	if b.inMemory(read) {
+		return sessionIDs, nil
+	}
+
+	sessionIDs, err := b.client.SMembers(ctx, dirtyKey).Result()
+	if b.fallBack(ctx, err, read) {
+		return sessionIDs, nil
+	}
+
+	return sessionIDs, err
+}
+
+// decodes buffered entries, skipping any that are corrupt
+func unmarshalEntries[T any](entryJSONs []string, kind, sessionID string) []T {
+	entries := make([]T, 0, len(entryJSONs))
+
+	for _, entryJSON := range entryJSONs {
+		var entry T
+		if err := json.Unmarshal([]byte(entryJSON), &entry); err != nil {
+			logger.ErrorErr(err, "failed to unmarshal buffered "+kind, "session_id", sessionID)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// counts the entries waiting to be flushed, in two round trips. while redis
+// is unreachable it counts the entries held in memory instead
+func (b *SessionBuffer) Backlog(ctx context.Context) (*Backlog, error) {
+	var backlog *Backlog
+	count := func(m *memoryBuffer) { backlog = m.backlog() }
+	if b.inMemory(count) {
+		return backlog, nil
+	}
+
+	pipe := b.client.Pipeline()
+	codeCmd := pipe.SCard(ctx, keyDirtySessionsCode)
+	messageCmd := pipe.SMembers(ctx, keyDirtySessionsMessages)
+	revisionCmd := pipe.SMembers(ctx, keyDirtySessionsCodeRevisions)
+	activityCmd := pipe.SMembers(ctx, keyDirtySessionsActivity)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		if b.fallBack(ctx, err, count) {
+			return backlog, nil
+		}
+		return nil, fmt.Errorf("failed to list dirty sessions: %w", err)
+	}
+
+	pipe = b.client.Pipeline()
+	lengths := func(keyFormat string, sessionIDs []string) []*redis.IntCmd {
+		cmds := make([]*redis.IntCmd, len(sessionIDs))
+		for i, sessionID := range sessionIDs {
+			cmds[i] = pipe.LLen(ctx, fmt.Sprintf(keyFormat, sessionID))
+		}
+		return cmds
+	}
+
+	messageLens := lengths(keySessionMessages, messageCmd.Val())
+	revisionLens := lengths(keySessionCodeRevisionsPending, revisionCmd.Val())
+	activityLens := lengths(keySessionActivity, activityCmd.Val())
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to measure buffered lists: %w", err)
+	}
+
+	backlog = &Backlog{Code: codeCmd.Val(), SessionMessages: make(map[string]int64)}
+
+	for i, cmd := range messageLens {
+		if n := cmd.Val(); n > 0 {
+			backlog.Messages += n
+			backlog.SessionMessages[messageCmd.Val()[i]] = n
+		}
+	}
+	for _, cmd := range revisionLens {
+		backlog.CodeRevisions += cmd.Val()
+	}
+	for _, cmd := range activityLens {
+		backlog.Activity += cmd.Val()
+	}
+
+	return backlog, nil
+}
+
+// entries waiting across every kind
+func (bl *Backlog) Total() int64 {
+	return bl.Code + bl.Messages + bl.CodeRevisions + bl.Activity
+}
+
+// the buffer and the sessions over the backpressure thresholds
+func (bl *Backlog) pressure() *pressure {
+	p := &pressure{overloaded: bl.Total() > maxBacklog}
+
+	for sessionID, n := range bl.SessionMessages {
+		if n > maxSessionBacklog {
+			if p.sessions == nil {
+				p.sessions = make(map[string]bool)
+			}
+			p.sessions[sessionID] = true
+		}
+	}
+
+	return p
+}
+
+// reports whether chat for the session should be refused until the flusher
+// catches up, because the buffer or the session's chat is backed up
+func (b *SessionBuffer) Backpressure(sessionID string) bool {
+	p := b.pressure.Load()
+	return p != nil && (p.overloaded || p.sessions[sessionID])
+}
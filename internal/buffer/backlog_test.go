@@ -0,0 +1,32 @@
+package buffer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBacklogPressure(t *testing.T) {
+	b := &SessionBuffer{}
+	assert.False(t, b.Backpressure("s1"), "no pressure before the first measurement")
+
+	backlog := &Backlog{
+		Code:     10,
+		Messages: maxSessionBacklog + 5,
+		SessionMessages: map[string]int64{
+			"s1": maxSessionBacklog + 1,
+			"s2": 4,
+		},
+	}
+	assert.Equal(t, int64(maxSessionBacklog+15), backlog.Total())
+
+	b.pressure.Store(backlog.pressure())
+	assert.True(t, b.Backpressure("s1"))
+	assert.False(t, b.Backpressure("s2"))
+
+	// an overloaded buffer pushes back on every session
+	backlog.Activity = maxBacklog
+	b.pressure.Store(backlog.pressure())
+	assert.True(t, b.Backpressure("s2"))
+	assert.True(t, b.Backpressure("s3"))
+}
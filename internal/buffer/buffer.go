@@ -5,37 +5,43 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/internal/logger"
 )
 
 // handles redis-backed buffering for session data
 type SessionBuffer struct {
-	client       *redis.Client
+	client       redis.UniversalClient
 	flushTimeout time.Duration
+	pressure     atomic.Pointer[pressure] // set by the flusher
+	fallback     memoryBuffer             // used while redis is unreachable
 }
 
-// creates a new session buffer with redis connection
-func NewSessionBuffer(redisURL string, flushTimeout time.Duration) (*SessionBuffer, error) {
-	opts, err := redis.ParseURL(redisURL)
+// creates a new session buffer connected to redis. mode is one of the
+// RedisMode* constants and picks how redisURL is read
+func NewSessionBuffer(mode, redisURL string, flushTimeout time.Duration) (*SessionBuffer, error) {
+	client, err := newRedisClient(mode, redisURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse redis url: %w", err)
+		return nil, err
 	}
 
-	client := redis.NewClient(opts)
-
 	// test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close() //nolint:errcheck,gosec // failing anyway
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
 	}
 
-	logger.Info("connected to redis")
+	logger.Info("connected to redis", "mode", mode)
 
 	return &SessionBuffer{
 		client:       client,
@@ -43,6 +49,34 @@ func NewSessionBuffer(redisURL string, flushTimeout time.Duration) (*SessionBuff
 	}, nil
 }
 
+// creates a client from a URL in the format go-redis reads for the mode: the
+// node for single, the sentinels with a master_name parameter for sentinel,
+// and any cluster nodes for cluster. further addresses go in addr parameters
+func newRedisClient(mode, redisURL string) (redis.UniversalClient, error) {
+	switch mode {
+	case RedisModeSentinel:
+		opts, err := redis.ParseFailoverURL(redisURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse redis sentinel url: %w", err)
+		}
+		return redis.NewFailoverClient(opts), nil
+	case RedisModeCluster:
+		opts, err := redis.ParseClusterURL(redisURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse redis cluster url: %w", err)
+		}
+		return redis.NewClusterClient(opts), nil
+	case RedisModeSingle, "":
+		opts, err := redis.ParseURL(redisURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse redis url: %w", err)
+		}
+		return redis.NewClient(opts), nil
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q", mode)
+	}
+}
+
 // closes the redis connection
 func (b *SessionBuffer) Close() error {
 	return b.client.Close()
@@ -50,6 +84,14 @@ func (b *SessionBuffer) Close() error {
 
 // stores the current code for a session and marks it dirty
 func (b *SessionBuffer) SetCode(ctx context.Context, sessionID, code string) error {
+	write := func(m *memoryBuffer) {
+		m.codes[sessionID] = code
+		m.dirtyCode[sessionID] = true
+	}
+	if b.inMemory(write) {
+		return nil
+	}
+
 	pipe := b.client.Pipeline()
 
 	// set the code
@@ -61,15 +103,25 @@ func (b *SessionBuffer) SetCode(ctx context.Context, sessionID, code string) err
 
 	_, err := pipe.Exec(ctx)
 	if err != nil {
+		if b.fallBack(ctx, err, write) {
+			return nil
+		}
 		return fmt.Errorf("failed to set code in redis: %w", err)
 	}
 
 	return nil
 }
 
-// retrieves the current code for a session from redis.
+// retrieves the current code for a session from redis, or from memory while
+// redis is unreachable.
 // returns empty string if not found (caller should fall back to postgres).
 func (b *SessionBuffer) GetCode(ctx context.Context, sessionID string) (string, error) {
+	var code string
+	read := func(m *memoryBuffer) { code = m.codes[sessionID] }
+	if b.inMemory(read) {
+		return code, nil
+	}
+
 	codeKey := fmt.Sprintf(keySessionCode, sessionID)
 	code, err := b.client.Get(ctx, codeKey).Result()
 
@@ -78,6 +130,9 @@ func (b *SessionBuffer) GetCode(ctx context.Context, sessionID string) (string,
 	}
 
 	if err != nil {
+		if b.fallBack(ctx, err, read) {
+			return code, nil
+		}
 		return "", fmt.Errorf("failed to get code from redis: %w", err)
 	}
 
@@ -86,6 +141,11 @@ func (b *SessionBuffer) GetCode(ctx context.Context, sessionID string) (string,
 
 // appends a chat message to the session's message buffer
 func (b *SessionBuffer) AddChatMessage(ctx context.Context, msg *BufferedChatMessage) error {
+	write := func(m *memoryBuffer) { m.messages[msg.SessionID] = append(m.messages[msg.SessionID], *msg) }
+	if b.inMemory(write) {
+		return nil
+	}
+
 	msgJSON, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %w", err)
@@ -102,6 +162,9 @@ func (b *SessionBuffer) AddChatMessage(ctx context.Context, msg *BufferedChatMes
 
 	_, err = pipe.Exec(ctx)
 	if err != nil {
+		if b.fallBack(ctx, err, write) {
+			return nil
+		}
 		return fmt.Errorf("failed to add message to redis: %w", err)
 	}
 
@@ -110,42 +173,90 @@ func (b *SessionBuffer) AddChatMessage(ctx context.Context, msg *BufferedChatMes
 
 // returns all session IDs with unflushed code changes
 func (b *SessionBuffer) GetDirtyCodeSessions(ctx context.Context) ([]string, error) {
-	return b.client.SMembers(ctx, keyDirtySessionsCode).Result()
+	return b.dirtySessions(ctx, keyDirtySessionsCode, func(m *memoryBuffer) []string {
+		return slices.Collect(maps.Keys(m.dirtyCode))
+	})
 }
 
 // returns all session IDs with unflushed messages
 func (b *SessionBuffer) GetDirtyMessageSessions(ctx context.Context) ([]string, error) {
-	return b.client.SMembers(ctx, keyDirtySessionsMessages).Result()
+	return b.dirtySessions(ctx, keyDirtySessionsMessages, func(m *memoryBuffer) []string {
+		return sessionsWith(m.messages)
+	})
 }
 
-// retrieves and clears the code for a session
-// returns the code and removes the session from dirty set
-func (b *SessionBuffer) FlushCode(ctx context.Context, sessionID string) (string, error) {
-	codeKey := fmt.Sprintf(keySessionCode, sessionID)
+// returns the buffered code of each session and takes the sessions out of
+// the dirty set, in one round trip. sessions without buffered code are left
+// out. the code stays in redis for reads
+func (b *SessionBuffer) FlushCodes(ctx context.Context, sessionIDs []string) ([]sessions.SessionCode, error) {
+	var codes []sessions.SessionCode
+	if b.inMemory(func(m *memoryBuffer) { codes = m.flushCodes(sessionIDs) }) {
+		return codes, nil
+	}
 
-	// get the code
-	code, err := b.client.Get(ctx, codeKey).Result()
-	if errors.Is(err, redis.Nil) {
-		// no code to flush, just remove from dirty set
-		b.client.SRem(ctx, keyDirtySessionsCode, sessionID) //nolint:errcheck // best-effort cleanup
-		return "", nil
+	pipe := b.client.Pipeline()
+	gets := make([]*redis.StringCmd, len(sessionIDs))
+
+	for i, sessionID := range sessionIDs {
+		// leave the dirty set before reading, so code set meanwhile marks it again
+		pipe.SRem(ctx, keyDirtySessionsCode, sessionID)
+		gets[i] = pipe.Get(ctx, fmt.Sprintf(keySessionCode, sessionID))
 	}
-	if err != nil {
-		return "", fmt.Errorf("failed to get code for flush: %w", err)
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		// nothing can be trusted to have left the dirty set
+		return nil, fmt.Errorf("failed to get code for flush: %w", err)
 	}
 
-	// remove from dirty set (keep the code in redis for reads)
-	b.client.SRem(ctx, keyDirtySessionsCode, sessionID)
+	codes = make([]sessions.SessionCode, 0, len(sessionIDs))
+	for i, get := range gets {
+		if code, err := get.Result(); err == nil && code != "" {
+			codes = append(codes, sessions.SessionCode{SessionID: sessionIDs[i], Code: code})
+		}
+	}
 
-	return code, nil
+	return codes, nil
+}
+
+// marks sessions whose code failed to flush, so the next pass retries them
+func (b *SessionBuffer) markCodeDirty(ctx context.Context, sessionIDs ...string) error {
+	write := func(m *memoryBuffer) {
+		for _, sessionID := range sessionIDs {
+			m.dirtyCode[sessionID] = true
+		}
+	}
+	if b.inMemory(write) {
+		return nil
+	}
+
+	members := make([]any, len(sessionIDs))
+	for i, sessionID := range sessionIDs {
+		members[i] = sessionID
+	}
+
+	err := b.client.SAdd(ctx, keyDirtySessionsCode, members...).Err()
+	if b.fallBack(ctx, err, write) {
+		return nil
+	}
+
+	return err
 }
 
 // retrieves chat messages for a session without clearing them
 func (b *SessionBuffer) GetBufferedChatMessages(ctx context.Context, sessionID string) ([]BufferedChatMessage, error) {
+	var buffered []BufferedChatMessage
+	read := func(m *memoryBuffer) { buffered = slices.Clone(m.messages[sessionID]) }
+	if b.inMemory(read) {
+		return buffered, nil
+	}
+
 	msgKey := fmt.Sprintf(keySessionMessages, sessionID)
 
 	msgJSONs, err := b.client.LRange(ctx, msgKey, 0, -1).Result()
 	if err != nil {
+		if b.fallBack(ctx, err, read) {
+			return buffered, nil
+		}
 		return nil, fmt.Errorf("failed to get buffered messages: %w", err)
 	}
 
@@ -166,50 +277,240 @@ func (b *SessionBuffer) GetBufferedChatMessages(ctx context.Context, sessionID s
 	return messages, nil
 }
 
-// retrieves and clears all chat messages for a session
-// returns the messages and removes the session from dirty set
-func (b *SessionBuffer) FlushChatMessages(ctx context.Context, sessionID string) ([]BufferedChatMessage, error) {
-	msgKey := fmt.Sprintf(keySessionMessages, sessionID)
+// takes up to limit chat entries (all when limit is 0) from the head of the
+// session's buffer, oldest first
+func (b *SessionBuffer) FlushChatMessages(ctx context.Context, sessionID string, limit int64) ([]BufferedChatMessage, error) {
+	var messages []BufferedChatMessage
+	if b.inMemory(func(m *memoryBuffer) { messages = take(m.messages, sessionID, limit) }) {
+		return messages, nil
+	}
 
-	// get all messages
-	msgJSONs, err := b.client.LRange(ctx, msgKey, 0, -1).Result()
+	msgJSONs, err := b.drainList(ctx, fmt.Sprintf(keySessionMessages, sessionID), keyDirtySessionsMessages, sessionID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages for flush: %w", err)
 	}
 
-	if len(msgJSONs) == 0 {
-		b.client.SRem(ctx, keyDirtySessionsMessages, sessionID)
-		return nil, nil
+	return unmarshalEntries[BufferedChatMessage](msgJSONs, "message", sessionID), nil
+}
+
+// puts chat entries that failed to flush back at the head of their
+// sessions' buffers, keeping their order
+func (b *SessionBuffer) requeueChatMessages(ctx context.Context, messages []BufferedChatMessage) error {
+	sessionOf := func(msg *BufferedChatMessage) string { return msg.SessionID }
+	return requeue(ctx, b, keySessionMessages, keyDirtySessionsMessages, messages, sessionOf,
+		func(m *memoryBuffer) { putBack(m.messages, messages, sessionOf) })
+}
+
+// records a code checkpoint for undo/redo. does nothing if the code matches
+// the current checkpoint, otherwise the redo history is discarded.
+func (b *SessionBuffer) RecordCodeRevision(ctx context.Context, sessionID, code string) error {
+	revision := BufferedCodeRevision{SessionID: sessionID, Code: code, CreatedAt: time.Now()}
+
+	// undo history lives in redis only, memory just keeps the checkpoint for postgres
+	write := func(m *memoryBuffer) { m.revisions[sessionID] = append(m.revisions[sessionID], revision) }
+	if b.inMemory(write) {
+		return nil
 	}
 
-	// parse messages
-	messages := make([]BufferedChatMessage, 0, len(msgJSONs))
-	for _, msgJSON := range msgJSONs {
-		var msg BufferedChatMessage
-		if err := json.Unmarshal([]byte(msgJSON), &msg); err != nil {
-			logger.ErrorErr(err, "failed to unmarshal buffered message", "session_id", sessionID)
-			continue
+	ringKey := fmt.Sprintf(keySessionCodeRevisions, sessionID)
+
+	current, err := b.client.LIndex(ctx, ringKey, -1).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		if b.fallBack(ctx, err, write) {
+			return nil
 		}
-		messages = append(messages, msg)
+		return fmt.Errorf("failed to get current code revision: %w", err)
+	}
+
+	if err == nil {
+		var rev BufferedCodeRevision
+		if err := json.Unmarshal([]byte(current), &rev); err == nil && rev.Code == code {
+			return nil
+		}
+	}
+
+	revJSON, err := json.Marshal(&revision)
+	if err != nil {
+		return fmt.Errorf("failed to marshal code revision: %w", err)
 	}
 
-	// clear the list and remove from dirty set
 	pipe := b.client.Pipeline()
-	pipe.Del(ctx, msgKey)
-	pipe.SRem(ctx, keyDirtySessionsMessages, sessionID)
-	pipe.Exec(ctx) //nolint:errcheck,gosec // best-effort cleanup, messages already retrieved
 
-	return messages, nil
+	// append to ring, dropping the oldest checkpoints
+	pipe.RPush(ctx, ringKey, revJSON)
+	pipe.LTrim(ctx, ringKey, -MaxCodeRevisions, -1)
+
+	// a new edit invalidates everything that was undone
+	pipe.Del(ctx, fmt.Sprintf(keySessionCodeRedo, sessionID))
+
+	// queue for postgres
+	pipe.RPush(ctx, fmt.Sprintf(keySessionCodeRevisionsPending, sessionID), revJSON)
+	pipe.SAdd(ctx, keyDirtySessionsCodeRevisions, sessionID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		if b.fallBack(ctx, err, write) {
+			return nil
+		}
+		return fmt.Errorf("failed to record code revision: %w", err)
+	}
+
+	return nil
+}
+
+// moves the current checkpoint to the redo history and returns the code of
+// the previous one. returns false if there is nothing to undo.
+func (b *SessionBuffer) UndoCodeRevision(ctx context.Context, sessionID string) (string, bool, error) {
+	ringKey := fmt.Sprintf(keySessionCodeRevisions, sessionID)
+
+	count, err := b.client.LLen(ctx, ringKey).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to count code revisions: %w", err)
+	}
+
+	// the last entry is the current code, so undo needs one more
+	if count < 2 {
+		return "", false, nil
+	}
+
+	redoKey := fmt.Sprintf(keySessionCodeRedo, sessionID)
+	if err := b.client.LMove(ctx, ringKey, redoKey, "RIGHT", "RIGHT").Err(); err != nil {
+		return "", false, fmt.Errorf("failed to undo code revision: %w", err)
+	}
+
+	previous, err := b.client.LIndex(ctx, ringKey, -1).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get previous code revision: %w", err)
+	}
+
+	var rev BufferedCodeRevision
+	if err := json.Unmarshal([]byte(previous), &rev); err != nil {
+		return "", false, fmt.Errorf("failed to unmarshal code revision: %w", err)
+	}
+
+	return rev.Code, true, nil
+}
+
+// moves the most recently undone checkpoint back to the ring and returns its
+// code. returns false if there is nothing to redo.
+func (b *SessionBuffer) RedoCodeRevision(ctx context.Context, sessionID string) (string, bool, error) {
+	ringKey := fmt.Sprintf(keySessionCodeRevisions, sessionID)
+	redoKey := fmt.Sprintf(keySessionCodeRedo, sessionID)
+
+	next, err := b.client.LMove(ctx, redoKey, ringKey, "RIGHT", "RIGHT").Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("failed to redo code revision: %w", err)
+	}
+
+	var rev BufferedCodeRevision
+	if err := json.Unmarshal([]byte(next), &rev); err != nil {
+		return "", false, fmt.Errorf("failed to unmarshal code revision: %w", err)
+	}
+
+	return rev.Code, true, nil
+}
+
+// returns all session IDs with unflushed code revisions
+func (b *SessionBuffer) GetDirtyCodeRevisionSessions(ctx context.Context) ([]string, error) {
+	return b.dirtySessions(ctx, keyDirtySessionsCodeRevisions, func(m *memoryBuffer) []string {
+		return sessionsWith(m.revisions)
+	})
+}
+
+// takes up to limit code revisions (all when limit is 0) waiting to be
+// flushed for a session, oldest first
+func (b *SessionBuffer) FlushCodeRevisions(ctx context.Context, sessionID string, limit int64) ([]BufferedCodeRevision, error) {
+	var revisions []BufferedCodeRevision
+	if b.inMemory(func(m *memoryBuffer) { revisions = take(m.revisions, sessionID, limit) }) {
+		return revisions, nil
+	}
+
+	revJSONs, err := b.drainList(ctx, fmt.Sprintf(keySessionCodeRevisionsPending, sessionID), keyDirtySessionsCodeRevisions, sessionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code revisions for flush: %w", err)
+	}
+
+	return unmarshalEntries[BufferedCodeRevision](revJSONs, "code revision", sessionID), nil
+}
+
+// re-queues code revisions that failed to flush
+func (b *SessionBuffer) requeueCodeRevisions(ctx context.Context, revisions []BufferedCodeRevision) error {
+	sessionOf := func(rev *BufferedCodeRevision) string { return rev.SessionID }
+	return requeue(ctx, b, keySessionCodeRevisionsPending, keyDirtySessionsCodeRevisions, revisions, sessionOf,
+		func(m *memoryBuffer) { putBack(m.revisions, revisions, sessionOf) })
+}
+
+// queues a session activity sample for the analytics rollup
+func (b *SessionBuffer) RecordActivity(ctx context.Context, sample *sessions.ActivitySample) error {
+	write := func(m *memoryBuffer) { m.activity[sample.SessionID] = append(m.activity[sample.SessionID], *sample) }
+	if b.inMemory(write) {
+		return nil
+	}
+
+	sampleJSON, err := json.Marshal(sample)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity sample: %w", err)
+	}
+
+	pipe := b.client.Pipeline()
+	pipe.RPush(ctx, fmt.Sprintf(keySessionActivity, sample.SessionID), sampleJSON)
+	pipe.SAdd(ctx, keyDirtySessionsActivity, sample.SessionID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		if b.fallBack(ctx, err, write) {
+			return nil
+		}
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+
+	return nil
+}
+
+// returns all session IDs with unflushed activity samples
+func (b *SessionBuffer) GetDirtyActivitySessions(ctx context.Context) ([]string, error) {
+	return b.dirtySessions(ctx, keyDirtySessionsActivity, func(m *memoryBuffer) []string {
+		return sessionsWith(m.activity)
+	})
+}
+
+// takes up to limit activity samples (all when limit is 0) waiting to be
+// flushed for a session
+func (b *SessionBuffer) FlushActivity(ctx context.Context, sessionID string, limit int64) ([]sessions.ActivitySample, error) {
+	var samples []sessions.ActivitySample
+	if b.inMemory(func(m *memoryBuffer) { samples = take(m.activity, sessionID, limit) }) {
+		return samples, nil
+	}
+
+	sampleJSONs, err := b.drainList(ctx, fmt.Sprintf(keySessionActivity, sessionID), keyDirtySessionsActivity, sessionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity for flush: %w", err)
+	}
+
+	return unmarshalEntries[sessions.ActivitySample](sampleJSONs, "activity", sessionID), nil
+}
+
+// re-queues activity samples that failed to flush
+func (b *SessionBuffer) requeueActivity(ctx context.Context, samples []sessions.ActivitySample) error {
+	sessionOf := func(sample *sessions.ActivitySample) string { return sample.SessionID }
+	return requeue(ctx, b, keySessionActivity, keyDirtySessionsActivity, samples, sessionOf,
+		func(m *memoryBuffer) { putBack(m.activity, samples, sessionOf) })
 }
 
 // removes all buffered data for a session (call after session ends)
 func (b *SessionBuffer) ClearSession(ctx context.Context, sessionID string) error {
+	b.inMemory(func(m *memoryBuffer) { m.clearSession(sessionID) })
+
 	codeKey := fmt.Sprintf(keySessionCode, sessionID)
 	msgKey := fmt.Sprintf(keySessionMessages, sessionID)
 
 	pipe := b.client.Pipeline()
 	pipe.Del(ctx, codeKey)
 	pipe.Del(ctx, msgKey)
+	pipe.Del(ctx, fmt.Sprintf(keySessionCodeRevisions, sessionID))
+	pipe.Del(ctx, fmt.Sprintf(keySessionCodeRedo, sessionID))
 	pipe.SRem(ctx, keyDirtySessionsCode, sessionID)
 	pipe.SRem(ctx, keyDirtySessionsMessages, sessionID)
 
@@ -218,7 +519,7 @@ func (b *SessionBuffer) ClearSession(ctx context.Context, sessionID string) erro
 }
 
 // returns the underlying redis client for advanced operations
-func (b *SessionBuffer) Client() *redis.Client {
+func (b *SessionBuffer) Client() redis.UniversalClient {
 	return b.client
 }
 
@@ -340,3 +641,76 @@ func (b *SessionBuffer) ClearRAGCache(ctx context.Context, sessionID string) err
 	cacheKey := fmt.Sprintf(keyRAGCache, sessionID)
 	return b.client.Del(ctx, cacheKey).Err()
 }
+
+// stores the conversation summary of a session's draft
+func (b *SessionBuffer) SetConversationSummary(ctx context.Context, sessionID string, summary *CachedConversationSummary) error {
+	summaryJSON, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversation summary: %w", err)
+	}
+
+	key := fmt.Sprintf(keyConversationSummary, sessionID)
+	if err := b.client.Set(ctx, key, summaryJSON, ConversationSummaryTTL).Err(); err != nil {
+		return fmt.Errorf("failed to set conversation summary: %w", err)
+	}
+
+	return nil
+}
+
+// retrieves the conversation summary of a session's draft, nil if there is none
+func (b *SessionBuffer) GetConversationSummary(ctx context.Context, sessionID string) (*CachedConversationSummary, error) {
+	summaryJSON, err := b.client.Get(ctx, fmt.Sprintf(keyConversationSummary, sessionID)).Result()
+
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation summary: %w", err)
+	}
+
+	var summary CachedConversationSummary
+	if err := json.Unmarshal([]byte(summaryJSON), &summary); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal conversation summary: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// stores clarifying questions awaiting an answer, replacing earlier ones
+func (b *SessionBuffer) SetClarification(ctx context.Context, scope string, clarification *CachedClarification) error {
+	clarificationJSON, err := json.Marshal(clarification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal clarification: %w", err)
+	}
+
+	key := fmt.Sprintf(keyClarification, scope)
+	if err := b.client.Set(ctx, key, clarificationJSON, ClarificationTTL).Err(); err != nil {
+		return fmt.Errorf("failed to set clarification: %w", err)
+	}
+
+	return nil
+}
+
+// retrieves the clarifying questions awaiting an answer, nil if there are none
+func (b *SessionBuffer) GetClarification(ctx context.Context, scope string) (*CachedClarification, error) {
+	clarificationJSON, err := b.client.Get(ctx, fmt.Sprintf(keyClarification, scope)).Result()
+
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clarification: %w", err)
+	}
+
+	var clarification CachedClarification
+	if err := json.Unmarshal([]byte(clarificationJSON), &clarification); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal clarification: %w", err)
+	}
+
+	return &clarification, nil
+}
+
+// removes answered clarifying questions
+func (b *SessionBuffer) ClearClarification(ctx context.Context, scope string) error {
+	return b.client.Del(ctx, fmt.Sprintf(keyClarification, scope)).Err()
+}
@@ -0,0 +1,268 @@
+package buffer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+// runs op against the fallback if it's active. returns false, without
+// running op, when redis should be used
+func (b *SessionBuffer) inMemory(op func(m *memoryBuffer)) bool {
+	m := &b.fallback
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.active {
+		return false
+	}
+
+	op(m)
+	return true
+}
+
+// activates the fallback if err shows redis is unreachable, and runs op
+// there instead. returns false when err is redis's answer, for the caller to
+// return
+func (b *SessionBuffer) fallBack(ctx context.Context, err error, op func(m *memoryBuffer)) bool {
+	if !unreachable(ctx, err) {
+		return false
+	}
+
+	m := &b.fallback
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.activate(err)
+	op(m)
+	return true
+}
+
+// reports whether err means redis couldn't be reached, as opposed to an
+// error reply or the caller giving up
+func unreachable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	var reply redis.Error
+	return !errors.As(err, &reply)
+}
+
+// pings redis until ctx is cancelled, switching to the fallback when it stops
+// answering and back once it answers again. the client reconnects by itself
+func (b *SessionBuffer) Start(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.checkRedis(ctx)
+		}
+	}
+}
+
+func (b *SessionBuffer) checkRedis(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	if err := b.client.Ping(pingCtx).Err(); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+
+		b.fallback.mu.Lock()
+		b.fallback.activate(err)
+		b.fallback.mu.Unlock()
+		return
+	}
+
+	if err := b.restore(ctx); err != nil {
+		logger.Warn("redis is back but buffered writes couldn't be moved there yet", "error", err)
+	}
+}
+
+// moves what the fallback holds into redis and turns it off. writes wait
+// meanwhile, so none land in memory after the move. on failure the fallback
+// stays on and the next check tries again
+func (b *SessionBuffer) restore(ctx context.Context) error {
+	m := &b.fallback
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.active {
+		return nil
+	}
+
+	pipe := b.client.Pipeline()
+
+	for sessionID, code := range m.codes {
+		pipe.Set(ctx, fmt.Sprintf(keySessionCode, sessionID), code, 0)
+	}
+	for sessionID := range m.dirtyCode {
+		pipe.SAdd(ctx, keyDirtySessionsCode, sessionID)
+	}
+
+	if err := pushAll(ctx, pipe, keySessionMessages, keyDirtySessionsMessages, m.messages); err != nil {
+		return err
+	}
+	if err := pushAll(ctx, pipe, keySessionCodeRevisionsPending, keyDirtySessionsCodeRevisions, m.revisions); err != nil {
+		return err
+	}
+	if err := pushAll(ctx, pipe, keySessionActivity, keyDirtySessionsActivity, m.activity); err != nil {
+		return err
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to move buffered writes to redis: %w", err)
+	}
+
+	logger.Info("redis is reachable again, moved buffered writes back",
+		"entries", m.backlog().Total(),
+		"unreachable_for", time.Since(m.since).Round(time.Second),
+	)
+
+	m.active, m.cause = false, nil
+	m.codes, m.dirtyCode, m.messages, m.revisions, m.activity = nil, nil, nil, nil, nil
+	metrics.BufferFallback.Set(0)
+
+	return nil
+}
+
+// appends the entries of each session to the tail of its redis list, after
+// what was buffered there before redis went away
+func pushAll[T any](ctx context.Context, pipe redis.Pipeliner, keyFormat, dirtyKey string, lists map[string][]T) error {
+	for sessionID, entries := range lists {
+		for i := range entries {
+			entryJSON, err := json.Marshal(&entries[i])
+			if err != nil {
+				return fmt.Errorf("failed to marshal buffered entry: %w", err)
+			}
+			pipe.RPush(ctx, fmt.Sprintf(keyFormat, sessionID), entryJSON)
+		}
+		pipe.SAdd(ctx, dirtyKey, sessionID)
+	}
+
+	return nil
+}
+
+// readiness check: fails while writes are held in memory. the server keeps
+// working meanwhile, so it's meant as an optional check
+func (b *SessionBuffer) Check(ctx context.Context) error {
+	b.fallback.mu.Lock()
+	active, since, cause := b.fallback.active, b.fallback.since, b.fallback.cause
+	b.fallback.mu.Unlock()
+
+	if active {
+		return fmt.Errorf("redis unreachable since %s, buffering writes in memory: %w", since.Format(time.RFC3339), cause)
+	}
+
+	return b.client.Ping(ctx).Err()
+}
+
+// turns the fallback on (must hold m.mu)
+func (m *memoryBuffer) activate(cause error) {
+	if m.active {
+		return
+	}
+
+	m.active, m.since, m.cause = true, time.Now(), cause
+	m.codes = make(map[string]string)
+	m.dirtyCode = make(map[string]bool)
+	m.messages = make(map[string][]BufferedChatMessage)
+	m.revisions = make(map[string][]BufferedCodeRevision)
+	m.activity = make(map[string][]sessions.ActivitySample)
+
+	metrics.BufferFallback.Set(1)
+	logger.Warn("redis is unreachable, buffering writes in memory", "error", cause)
+}
+
+// returns the code of the sessions and takes them out of the dirty set, like
+// FlushCodes. the code stays for reads
+func (m *memoryBuffer) flushCodes(sessionIDs []string) []sessions.SessionCode {
+	codes := make([]sessions.SessionCode, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		delete(m.dirtyCode, sessionID)
+		if code := m.codes[sessionID]; code != "" {
+			codes = append(codes, sessions.SessionCode{SessionID: sessionID, Code: code})
+		}
+	}
+
+	return codes
+}
+
+// drops everything buffered for a session, like ClearSession does in redis
+func (m *memoryBuffer) clearSession(sessionID string) {
+	delete(m.codes, sessionID)
+	delete(m.dirtyCode, sessionID)
+	delete(m.messages, sessionID)
+}
+
+// the entries waiting in memory, counted like the redis lists
+func (m *memoryBuffer) backlog() *Backlog {
+	backlog := &Backlog{Code: int64(len(m.dirtyCode)), SessionMessages: make(map[string]int64)}
+
+	for sessionID, messages := range m.messages {
+		backlog.Messages += int64(len(messages))
+		backlog.SessionMessages[sessionID] = int64(len(messages))
+	}
+	for _, revisions := range m.revisions {
+		backlog.CodeRevisions += int64(len(revisions))
+	}
+	for _, samples := range m.activity {
+		backlog.Activity += int64(len(samples))
+	}
+
+	return backlog
+}
+
+// takes up to limit entries (all when limit is 0) from the head of a
+// session's list
+func take[T any](lists map[string][]T, sessionID string, limit int64) []T {
+	list := lists[sessionID]
+
+	n := len(list)
+	if limit > 0 && int64(n) > limit {
+		n = int(limit)
+	}
+
+	if n == len(list) {
+		delete(lists, sessionID)
+	} else {
+		lists[sessionID] = list[n:]
+	}
+
+	return list[:n:n]
+}
+
+// puts entries back at the head of their sessions' lists, in their original
+// order
+func putBack[T any](lists map[string][]T, entries []T, sessionOf func(*T) string) {
+	bySession := make(map[string][]T)
+	for i := range entries {
+		sessionID := sessionOf(&entries[i])
+		bySession[sessionID] = append(bySession[sessionID], entries[i])
+	}
+
+	for sessionID, returned := range bySession {
+		lists[sessionID] = append(returned, lists[sessionID]...)
+	}
+}
+
+// sessions with entries in lists
+func sessionsWith[T any](lists map[string][]T) []string {
+	return slices.Collect(maps.Keys(lists))
+}
@@ -0,0 +1,105 @@
+package buffer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+)
+
+// a buffer whose redis refuses connections
+func unreachableBuffer(t *testing.T) *SessionBuffer {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck,gosec // test cleanup
+
+	return &SessionBuffer{client: client}
+}
+
+func TestFallbackHoldsWritesWhileRedisIsUnreachable(t *testing.T) {
+	ctx := context.Background()
+	b := unreachableBuffer(t)
+
+	require.NoError(t, b.SetCode(ctx, "s1", "sound(\"bd\")"))
+	assert.Error(t, b.Check(ctx), "readiness reports the fallback")
+
+	code, err := b.GetCode(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "sound(\"bd\")", code)
+
+	for _, id := range []string{"m1", "m2", "m3"} {
+		require.NoError(t, b.AddChatMessage(ctx, &BufferedChatMessage{ID: id, SessionID: "s1"}))
+	}
+	require.NoError(t, b.RecordCodeRevision(ctx, "s1", "sound(\"bd\")"))
+	require.NoError(t, b.RecordActivity(ctx, &sessions.ActivitySample{SessionID: "s2"}))
+
+	backlog, err := b.Backlog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), backlog.Code)
+	assert.Equal(t, int64(3), backlog.Messages)
+	assert.Equal(t, int64(1), backlog.CodeRevisions)
+	assert.Equal(t, int64(1), backlog.Activity)
+
+	dirty, err := b.GetDirtyMessageSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1"}, dirty)
+
+	// the flusher drains memory like the redis lists
+	messages, err := b.FlushChatMessages(ctx, "s1", 2)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "m1", messages[0].ID)
+
+	require.NoError(t, b.requeueChatMessages(ctx, messages[1:]))
+	buffered, err := b.GetBufferedChatMessages(ctx, "s1")
+	require.NoError(t, err)
+	require.Len(t, buffered, 2)
+	assert.Equal(t, "m2", buffered[0].ID)
+	assert.Equal(t, "m3", buffered[1].ID)
+
+	codes, err := b.FlushCodes(ctx, []string{"s1"})
+	require.NoError(t, err)
+	assert.Equal(t, []sessions.SessionCode{{SessionID: "s1", Code: "sound(\"bd\")"}}, codes)
+
+	dirty, err = b.GetDirtyCodeSessions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, dirty)
+
+	// code stays readable after it's flushed
+	code, err = b.GetCode(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "sound(\"bd\")", code)
+
+	// redis is still away, so nothing moves
+	assert.Error(t, b.restore(ctx))
+	assert.True(t, b.fallback.active)
+}
+
+func TestTakeAndPutBack(t *testing.T) {
+	lists := map[string][]BufferedChatMessage{
+		"s1": {{ID: "a", SessionID: "s1"}, {ID: "b", SessionID: "s1"}, {ID: "c", SessionID: "s1"}},
+	}
+
+	taken := take(lists, "s1", 2)
+	require.Len(t, taken, 2)
+	require.Len(t, lists["s1"], 1)
+
+	// appending to what's left can't overwrite what was taken
+	lists["s1"] = append(lists["s1"], BufferedChatMessage{ID: "d", SessionID: "s1"})
+	assert.Equal(t, "b", taken[1].ID)
+
+	putBack(lists, taken, func(msg *BufferedChatMessage) string { return msg.SessionID })
+	ids := make([]string, 0, len(lists["s1"]))
+	for _, msg := range lists["s1"] {
+		ids = append(ids, msg.ID)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ids)
+
+	assert.Len(t, take(lists, "s1", 0), 4)
+	assert.NotContains(t, lists, "s1")
+}
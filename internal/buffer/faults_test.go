@@ -0,0 +1,68 @@
+package buffer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/faults"
+)
+
+// a buffer whose redis calls go through an injector. nothing listens at its
+// address, so the tests only see injected faults
+func faultyBuffer(t *testing.T) (*SessionBuffer, *faults.Injector) {
+	t.Helper()
+
+	injector := faults.New()
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	client.AddHook(injector.RedisHook())
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck,gosec // test cleanup
+
+	return &SessionBuffer{client: client}, injector
+}
+
+// records the code written straight to postgres
+type codeRepo struct {
+	sessions.Repository
+	codes map[string]string
+}
+
+func (r *codeRepo) UpdateSessionCode(_ context.Context, sessionID, code string) error {
+	r.codes[sessionID] = code
+	return nil
+}
+
+func TestBufferedRepositoryWritesToPostgresWhenRedisErrors(t *testing.T) {
+	ctx := context.Background()
+	b, injector := faultyBuffer(t)
+	db := &codeRepo{codes: map[string]string{}}
+	repo := NewBufferedRepository(db, b)
+
+	require.NoError(t, injector.Set(faults.TargetRedis, faults.Fault{ErrorRate: 1}))
+
+	require.NoError(t, repo.UpdateSessionCode(ctx, "s1", `s("bd")`))
+	assert.Equal(t, `s("bd")`, db.codes["s1"], "the write went straight to postgres")
+	assert.False(t, b.fallback.active, "an error reply means redis is up")
+}
+
+func TestBufferedRepositoryHoldsWritesWhenRedisDrops(t *testing.T) {
+	ctx := context.Background()
+	b, injector := faultyBuffer(t)
+	db := &codeRepo{codes: map[string]string{}}
+	repo := NewBufferedRepository(db, b)
+
+	require.NoError(t, injector.Set(faults.TargetRedis, faults.Fault{DropRate: 1}))
+
+	require.NoError(t, repo.UpdateSessionCode(ctx, "s1", `s("bd")`))
+	assert.Empty(t, db.codes, "the write waits in memory for the flusher")
+	assert.Error(t, b.Check(ctx), "readiness reports the fallback")
+
+	code, err := b.GetCode(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, `s("bd")`, code)
+}
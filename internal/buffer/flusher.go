@@ -2,14 +2,21 @@ package buffer
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"time"
 
 	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
 )
 
-// handles periodic flushing of buffered data from Redis to Postgres
+// most passes the final flush makes to empty the buffer on shutdown
+const maxShutdownPasses = 20
+
+// handles periodic flushing of buffered data from Redis to Postgres. each
+// pass gathers the entries of every dirty session and writes them in bulk,
+// and passes come quicker while a backlog drains
 type Flusher struct {
 	buffer      *SessionBuffer
 	sessionRepo sessions.Repository
@@ -45,31 +52,91 @@ func (f *Flusher) Stop() {
 func (f *Flusher) run() {
 	defer f.wg.Done()
 
-	ticker := time.NewTicker(f.interval)
-	defer ticker.Stop()
+	timer := time.NewTimer(f.interval)
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
-			f.flush()
+		case <-timer.C:
+			backlog, more := f.flush()
+			timer.Reset(nextFlushInterval(f.interval, backlog, more))
 		case <-f.stopCh:
-			// final flush before stopping
+			// final flush before stopping, until nothing is left
 			logger.Info("flushing remaining buffer data before shutdown")
-			f.flush()
+			for range maxShutdownPasses {
+				if _, more := f.flush(); !more {
+					break
+				}
+			}
 			return
 		}
 	}
 }
 
-func (f *Flusher) flush() {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// the wait before the next pass: the base interval while the backlog is one
+// batch or less, shrinking as it grows so bursts drain quickly
+func nextFlushInterval(base time.Duration, backlog int64, more bool) time.Duration {
+	if more {
+		return minFlushInterval
+	}
+
+	if backlog <= flushBatchSize {
+		return base
+	}
+
+	interval := time.Duration(float64(base) * flushBatchSize / float64(backlog))
+	return max(interval, minFlushInterval)
+}
+
+// runs one flush pass. returns the backlog it started with (0 if it
+// couldn't be measured) and whether sessions had more than one pass takes
+func (f *Flusher) flush() (int64, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), flushPassTimeout)
 	defer cancel()
 
+	var total int64
+	if backlog, err := f.buffer.Backlog(ctx); err != nil {
+		logger.ErrorErr(err, "failed to measure buffer backlog")
+	} else {
+		total = backlog.Total()
+		f.updatePressure(backlog)
+	}
+
 	// flush code updates
 	f.flushCode(ctx)
 
-	// flush messages
-	f.flushMessages(ctx)
+	// flush messages, code revisions and activity
+	moreMessages := f.flushMessages(ctx)
+	moreRevisions := f.flushCodeRevisions(ctx)
+	moreActivity := f.flushActivity(ctx)
+
+	return total, moreMessages || moreRevisions || moreActivity
+}
+
+// publishes the backlog and the backpressure it causes
+func (f *Flusher) updatePressure(backlog *Backlog) {
+	metrics.BufferBacklog.Set(float64(backlog.Code), "code")
+	metrics.BufferBacklog.Set(float64(backlog.Messages), "messages")
+	metrics.BufferBacklog.Set(float64(backlog.CodeRevisions), "code_revisions")
+	metrics.BufferBacklog.Set(float64(backlog.Activity), "activity")
+
+	p := backlog.pressure()
+	previous := f.buffer.pressure.Swap(p)
+
+	overloaded := 0.0
+	if p.overloaded {
+		overloaded = 1
+	}
+	metrics.BufferBackpressure.Set(overloaded, "buffer")
+	metrics.BufferBackpressure.Set(float64(len(p.sessions)), "sessions")
+
+	if wasOverloaded := previous != nil && previous.overloaded; p.overloaded != wasOverloaded {
+		if p.overloaded {
+			logger.Warn("buffer backlog over the limit, refusing chat until it drains", "backlog", backlog.Total(), "limit", maxBacklog)
+		} else {
+			logger.Info("buffer backlog drained, accepting chat again", "backlog", backlog.Total())
+		}
+	}
 }
 
 func (f *Flusher) flushCode(ctx context.Context) {
@@ -85,102 +152,328 @@ func (f *Flusher) flushCode(ctx context.Context) {
 
 	logger.Debug("flushing code for sessions", "count", len(sessionIDs))
 
-	for _, sessionID := range sessionIDs {
-		code, err := f.buffer.FlushCode(ctx, sessionID)
+	defer observeFlush("code", time.Now(), len(sessionIDs))
+
+	for chunk := range slices.Chunk(sessionIDs, flushBatchSize) {
+		codes, err := f.buffer.FlushCodes(ctx, chunk)
 		if err != nil {
-			logger.ErrorErr(err, "failed to flush code from buffer", "session_id", sessionID)
+			logger.ErrorErr(err, "failed to flush code from buffer", "sessions", len(chunk))
 			continue
 		}
 
-		if code == "" {
-			continue
-		}
+		failed := writeBatched(ctx, "code", codes, f.sessionRepo.UpdateSessionCodes,
+			func(ctx context.Context, c *sessions.SessionCode) error {
+				return f.sessionRepo.UpdateSessionCode(ctx, c.SessionID, c.Code)
+			})
 
-		if err := f.sessionRepo.UpdateSessionCode(ctx, sessionID, code); err != nil {
-			logger.ErrorErr(err, "failed to persist code to postgres", "session_id", sessionID)
-			// re-add to dirty set so we retry next flush
-			f.buffer.SetCode(ctx, sessionID, code) //nolint:errcheck,gosec // best-effort retry
-		} else {
-			logger.Debug("flushed code to postgres", "session_id", sessionID)
+		// the code is still in redis, marking the sessions dirty retries them
+		for _, i := range failed {
+			f.buffer.markCodeDirty(ctx, codes[i].SessionID) //nolint:errcheck,gosec // best-effort retry
 		}
 	}
 }
 
-func (f *Flusher) flushMessages(ctx context.Context) {
+// returns whether a session had more messages than one pass takes
+func (f *Flusher) flushMessages(ctx context.Context) bool {
 	sessionIDs, err := f.buffer.GetDirtyMessageSessions(ctx)
 	if err != nil {
 		logger.ErrorErr(err, "failed to get dirty message sessions")
-		return
+		return false
 	}
 
 	if len(sessionIDs) == 0 {
-		return
+		return false
 	}
 
 	logger.Debug("flushing messages for sessions", "count", len(sessionIDs))
 
+	start := time.Now()
+	var messages []BufferedChatMessage
+	more := false
+
 	for _, sessionID := range sessionIDs {
-		messages, err := f.buffer.FlushChatMessages(ctx, sessionID)
+		drained, err := f.buffer.FlushChatMessages(ctx, sessionID, flushSessionLimit)
 		if err != nil {
 			logger.ErrorErr(err, "failed to flush messages from buffer", "session_id", sessionID)
 			continue
 		}
 
-		for _, msg := range messages {
-			_, err := f.sessionRepo.AddChatMessage(
-				ctx,
-				msg.SessionID,
-				msg.UserID,
-				msg.Content,
-				msg.DisplayName,
-				msg.AvatarURL,
-			)
-			if err != nil {
-				logger.ErrorErr(err, "failed to persist chat message to postgres",
-					"session_id", msg.SessionID,
-				)
-				// re-add failed message to buffer
-				f.buffer.AddChatMessage(ctx, &msg) //nolint:errcheck,gosec // best-effort retry
-			}
+		more = more || len(drained) >= flushSessionLimit
+		messages = append(messages, drained...)
+	}
+
+	if len(messages) == 0 {
+		return more
+	}
+
+	observeFlushLag("messages", oldest(messages, func(msg *BufferedChatMessage) time.Time { return msg.CreatedAt }))
+
+	defer observeFlush("messages", start, len(messages))
+
+	// chunks keep each session's entries in order, reactions follow their messages
+	for chunk := range slices.Chunk(messages, flushBatchSize) {
+		entries := make([]sessions.ChatEntry, len(chunk))
+		for i := range chunk {
+			entries[i] = chatEntry(&chunk[i])
+		}
+
+		failed := writeBatched(ctx, "messages", entries, f.sessionRepo.AddChatEntries, f.persistChatEntry)
+		if len(failed) > 0 {
+			f.buffer.requeueChatMessages(ctx, pick(chunk, failed)) //nolint:errcheck,gosec // best-effort retry
+		}
+	}
+
+	return more
+}
+
+// returns whether a session had more code revisions than one pass takes
+func (f *Flusher) flushCodeRevisions(ctx context.Context) bool {
+	sessionIDs, err := f.buffer.GetDirtyCodeRevisionSessions(ctx)
+	if err != nil {
+		logger.ErrorErr(err, "failed to get dirty code revision sessions")
+		return false
+	}
+
+	if len(sessionIDs) == 0 {
+		return false
+	}
+
+	logger.Debug("flushing code revisions for sessions", "count", len(sessionIDs))
+
+	start := time.Now()
+	var revisions []BufferedCodeRevision
+	more := false
+
+	for _, sessionID := range sessionIDs {
+		drained, err := f.buffer.FlushCodeRevisions(ctx, sessionID, flushSessionLimit)
+		if err != nil {
+			logger.ErrorErr(err, "failed to flush code revisions from buffer", "session_id", sessionID)
+			continue
 		}
+
+		more = more || len(drained) >= flushSessionLimit
+		revisions = append(revisions, drained...)
+	}
+
+	if len(revisions) == 0 {
+		return more
 	}
+
+	observeFlushLag("code_revisions", oldest(revisions, func(rev *BufferedCodeRevision) time.Time { return rev.CreatedAt }))
+
+	defer observeFlush("code_revisions", start, len(revisions))
+
+	for chunk := range slices.Chunk(revisions, flushBatchSize) {
+		failed := writeBatched(ctx, "code_revisions", chunk, f.addCodeRevisions,
+			func(ctx context.Context, rev *BufferedCodeRevision) error {
+				return f.sessionRepo.AddCodeRevision(ctx, rev.SessionID, rev.Code, rev.CreatedAt)
+			})
+
+		if len(failed) > 0 {
+			f.buffer.requeueCodeRevisions(ctx, pick(chunk, failed)) //nolint:errcheck,gosec // best-effort retry
+		}
+	}
+
+	return more
+}
+
+func (f *Flusher) addCodeRevisions(ctx context.Context, revisions []BufferedCodeRevision) error {
+	rows := make([]sessions.CodeRevision, len(revisions))
+	for i, rev := range revisions {
+		rows[i] = sessions.CodeRevision{SessionID: rev.SessionID, Code: rev.Code, CreatedAt: rev.CreatedAt}
+	}
+
+	return f.sessionRepo.AddCodeRevisions(ctx, rows)
+}
+
+// returns whether a session had more activity samples than one pass takes
+func (f *Flusher) flushActivity(ctx context.Context) bool {
+	sessionIDs, err := f.buffer.GetDirtyActivitySessions(ctx)
+	if err != nil {
+		logger.ErrorErr(err, "failed to get dirty activity sessions")
+		return false
+	}
+
+	if len(sessionIDs) == 0 {
+		return false
+	}
+
+	logger.Debug("flushing activity for sessions", "count", len(sessionIDs))
+
+	start := time.Now()
+	var samples []sessions.ActivitySample
+	more := false
+
+	for _, sessionID := range sessionIDs {
+		drained, err := f.buffer.FlushActivity(ctx, sessionID, flushSessionLimit)
+		if err != nil {
+			logger.ErrorErr(err, "failed to flush activity from buffer", "session_id", sessionID)
+			continue
+		}
+
+		more = more || len(drained) >= flushSessionLimit
+		samples = append(samples, drained...)
+	}
+
+	if len(samples) == 0 {
+		return more
+	}
+
+	observeFlushLag("activity", oldest(samples, func(sample *sessions.ActivitySample) time.Time { return sample.BucketStart }))
+
+	defer observeFlush("activity", start, len(samples))
+
+	for chunk := range slices.Chunk(samples, flushBatchSize) {
+		failed := writeBatched(ctx, "activity", chunk, f.sessionRepo.AddActivities, f.sessionRepo.AddActivity)
+
+		if len(failed) > 0 {
+			f.buffer.requeueActivity(ctx, pick(chunk, failed)) //nolint:errcheck,gosec // best-effort retry
+		}
+	}
+
+	return more
+}
+
+// writes entries with one bulk write. when it fails the entries are written
+// one at a time, so one bad entry (say, of a deleted session) doesn't hold
+// back the rest. returns the indexes of entries that couldn't be written
+func writeBatched[T any](
+	ctx context.Context,
+	kind string,
+	entries []T,
+	writeAll func(context.Context, []T) error,
+	writeOne func(context.Context, *T) error,
+) []int {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := writeAll(ctx, entries)
+	if err == nil {
+		return nil
+	}
+
+	logger.ErrorErr(err, "failed to persist batch to postgres, writing entries one by one", "kind", kind, "entries", len(entries))
+
+	var failed []int
+	for i := range entries {
+		if err := writeOne(ctx, &entries[i]); err != nil {
+			logger.ErrorErr(err, "failed to persist buffered entry to postgres", "kind", kind)
+			failed = append(failed, i)
+		}
+	}
+
+	return failed
+}
+
+// writes a buffered chat message or reaction change to Postgres
+func (f *Flusher) persistChatEntry(ctx context.Context, entry *sessions.ChatEntry) error {
+	switch entry.Kind {
+	case ChatEntryReactionAdded:
+		return f.sessionRepo.AddChatReaction(ctx, entry.SessionID, entry.ParentMessageID, entry.UserID, entry.DisplayName, entry.Content)
+	case ChatEntryReactionRemoved:
+		return f.sessionRepo.RemoveChatReaction(ctx, entry.SessionID, entry.ParentMessageID, entry.UserID, entry.DisplayName, entry.Content)
+	default:
+		_, err := f.sessionRepo.AddChatMessage(ctx, &entry.AddChatMessageRequest)
+		return err
+	}
+}
+
+func chatEntry(msg *BufferedChatMessage) sessions.ChatEntry {
+	return sessions.ChatEntry{
+		Kind: msg.Kind,
+		AddChatMessageRequest: sessions.AddChatMessageRequest{
+			ID:              msg.ID,
+			SessionID:       msg.SessionID,
+			UserID:          msg.UserID,
+			Content:         msg.Content,
+			DisplayName:     msg.DisplayName,
+			AvatarURL:       msg.AvatarURL,
+			ParentMessageID: msg.ParentMessageID,
+		},
+	}
+}
+
+// the entries at the given indexes
+func pick[T any](entries []T, indexes []int) []T {
+	picked := make([]T, len(indexes))
+	for i, index := range indexes {
+		picked[i] = entries[index]
+	}
+
+	return picked
+}
+
+// the earliest time among entries
+func oldest[T any](entries []T, at func(*T) time.Time) time.Time {
+	var first time.Time
+	for i := range entries {
+		if t := at(&entries[i]); first.IsZero() || t.Before(first) {
+			first = t
+		}
+	}
+
+	return first
+}
+
+// records a flush pass writing entries as old as oldest
+func observeFlushLag(kind string, oldest time.Time) {
+	if !oldest.IsZero() {
+		metrics.BufferFlushLag.Observe(time.Since(oldest).Seconds(), kind)
+	}
+}
+
+// records the duration and size of a flush pass. size counts sessions for
+// code and items for messages, code revisions and activity.
+func observeFlush(kind string, start time.Time, size int) {
+	metrics.BufferFlushDuration.Observe(time.Since(start).Seconds(), kind)
+	metrics.BufferFlushBatchSize.Observe(float64(size), kind)
 }
 
 // immediately flushes all data for a specific session
 func (f *Flusher) FlushSession(ctx context.Context, sessionID string) error {
 	// flush code
-	code, err := f.buffer.FlushCode(ctx, sessionID)
+	codes, err := f.buffer.FlushCodes(ctx, []string{sessionID})
 	if err != nil {
 		return err
 	}
 
-	if code != "" {
-		if err := f.sessionRepo.UpdateSessionCode(ctx, sessionID, code); err != nil {
+	for _, c := range codes {
+		if err := f.sessionRepo.UpdateSessionCode(ctx, c.SessionID, c.Code); err != nil {
 			logger.ErrorErr(err, "failed to persist code on session flush", "session_id", sessionID)
 		}
 	}
 
 	// flush chat messages
-	messages, err := f.buffer.FlushChatMessages(ctx, sessionID)
+	messages, err := f.buffer.FlushChatMessages(ctx, sessionID, 0)
 	if err != nil {
 		return err
 	}
 
-	for _, msg := range messages {
-		_, err := f.sessionRepo.AddChatMessage(
-			ctx,
-			msg.SessionID,
-			msg.UserID,
-			msg.Content,
-			msg.DisplayName,
-			msg.AvatarURL,
-		)
-		if err != nil {
-			logger.ErrorErr(err, "failed to persist chat message on session flush",
-				"session_id", msg.SessionID,
-			)
-		}
+	entries := make([]sessions.ChatEntry, len(messages))
+	for i := range messages {
+		entries[i] = chatEntry(&messages[i])
+	}
+	writeBatched(ctx, "messages", entries, f.sessionRepo.AddChatEntries, f.persistChatEntry)
+
+	// flush code revisions
+	revisions, err := f.buffer.FlushCodeRevisions(ctx, sessionID, 0)
+	if err != nil {
+		return err
+	}
+
+	writeBatched(ctx, "code_revisions", revisions, f.addCodeRevisions,
+		func(ctx context.Context, rev *BufferedCodeRevision) error {
+			return f.sessionRepo.AddCodeRevision(ctx, rev.SessionID, rev.Code, rev.CreatedAt)
+		})
+
+	// flush activity
+	samples, err := f.buffer.FlushActivity(ctx, sessionID, 0)
+	if err != nil {
+		return err
 	}
 
+	writeBatched(ctx, "activity", samples, f.sessionRepo.AddActivities, f.sessionRepo.AddActivity)
+
 	return nil
 }
@@ -0,0 +1,61 @@
+package buffer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextFlushInterval(t *testing.T) {
+	base := 5 * time.Second
+
+	assert.Equal(t, base, nextFlushInterval(base, 0, false))
+	assert.Equal(t, base, nextFlushInterval(base, flushBatchSize, false))
+	assert.Equal(t, base/2, nextFlushInterval(base, 2*flushBatchSize, false))
+	assert.Equal(t, minFlushInterval, nextFlushInterval(base, 1000*flushBatchSize, false))
+
+	// sessions left over from the last pass are flushed right away
+	assert.Equal(t, minFlushInterval, nextFlushInterval(base, 0, true))
+}
+
+func TestWriteBatched(t *testing.T) {
+	entries := []string{"a", "bad", "c", "bad"}
+
+	writeOne := func(_ context.Context, entry *string) error {
+		if *entry == "bad" {
+			return errors.New("bad entry")
+		}
+		return nil
+	}
+
+	var bulk int
+	ok := func(context.Context, []string) error { bulk++; return nil }
+	fail := func(context.Context, []string) error { bulk++; return errors.New("batch failed") }
+
+	assert.Empty(t, writeBatched(context.Background(), "test", entries, ok, writeOne))
+	assert.Equal(t, 1, bulk)
+
+	// a failed batch falls back to one write per entry
+	failed := writeBatched(context.Background(), "test", entries, fail, writeOne)
+	assert.Equal(t, []int{1, 3}, failed)
+	assert.Equal(t, []string{"bad", "bad"}, pick(entries, failed))
+
+	assert.Nil(t, writeBatched(context.Background(), "test", nil, fail, writeOne))
+	assert.Equal(t, 2, bulk)
+}
+
+func TestOldest(t *testing.T) {
+	now := time.Now()
+	revisions := []BufferedCodeRevision{
+		{CreatedAt: now},
+		{CreatedAt: now.Add(-time.Minute)},
+		{CreatedAt: now.Add(time.Minute)},
+	}
+
+	at := func(r *BufferedCodeRevision) time.Time { return r.CreatedAt }
+	assert.Equal(t, now.Add(-time.Minute), oldest(revisions, at))
+	assert.True(t, oldest(nil, at).IsZero())
+}
@@ -2,9 +2,13 @@ package buffer
 
 import (
 	"context"
+	"slices"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/keyset"
 	"codeberg.org/algopatterns/server/internal/logger"
 )
 
@@ -35,39 +39,138 @@ func (r *BufferedRepository) UpdateSessionCode(ctx context.Context, sessionID, c
 	return nil
 }
 
-// buffers chat message to Redis instead of direct Postgres write
-func (r *BufferedRepository) AddChatMessage(
-	ctx context.Context,
-	sessionID, userID, content, displayName, avatarURL string,
-) (*sessions.Message, error) {
+// buffers chat message to Redis instead of direct Postgres write.
+// the message ID is assigned here so replies and reactions can refer to it
+// before it is flushed.
+func (r *BufferedRepository) AddChatMessage(ctx context.Context, req *sessions.AddChatMessageRequest) (*sessions.Message, error) {
+	if req.ID == "" {
+		id, err := sessions.NewMessageID()
+		if err != nil {
+			return nil, err
+		}
+
+		req.ID = id
+	}
+
 	msg := &BufferedChatMessage{
-		SessionID:   sessionID,
-		UserID:      userID,
-		Content:     content,
-		DisplayName: displayName,
-		AvatarURL:   avatarURL,
-		CreatedAt:   time.Now(),
+		ID:              req.ID,
+		SessionID:       req.SessionID,
+		UserID:          req.UserID,
+		Content:         req.Content,
+		DisplayName:     req.DisplayName,
+		AvatarURL:       req.AvatarURL,
+		ParentMessageID: req.ParentMessageID,
+		CreatedAt:       time.Now(),
 	}
 
 	if err := r.buffer.AddChatMessage(ctx, msg); err != nil {
-		logger.ErrorErr(err, "failed to buffer chat message", "session_id", sessionID)
+		logger.ErrorErr(err, "failed to buffer chat message", "session_id", req.SessionID)
 		// fall back to direct DB write
-		return r.db.AddChatMessage(ctx, sessionID, userID, content, displayName, avatarURL)
+		return r.db.AddChatMessage(ctx, req)
 	}
 
 	// return a placeholder message (real one will be created on flush)
-	return &sessions.Message{
-		SessionID: sessionID,
+	return bufferedToMessage(msg), nil
+}
+
+// buffers a reaction behind the messages it may refer to
+func (r *BufferedRepository) AddChatReaction(ctx context.Context, sessionID, messageID, userID, displayName, emoji string) error {
+	if err := r.buffer.AddChatMessage(ctx, newBufferedReaction(ChatEntryReactionAdded, sessionID, messageID, userID, displayName, emoji)); err != nil {
+		logger.ErrorErr(err, "failed to buffer chat reaction", "session_id", sessionID)
+		return r.db.AddChatReaction(ctx, sessionID, messageID, userID, displayName, emoji)
+	}
+
+	return nil
+}
+
+// buffers a reaction removal, keeping it ordered after the reaction
+func (r *BufferedRepository) RemoveChatReaction(ctx context.Context, sessionID, messageID, userID, displayName, emoji string) error {
+	if err := r.buffer.AddChatMessage(ctx, newBufferedReaction(ChatEntryReactionRemoved, sessionID, messageID, userID, displayName, emoji)); err != nil {
+		logger.ErrorErr(err, "failed to buffer chat reaction removal", "session_id", sessionID)
+		return r.db.RemoveChatReaction(ctx, sessionID, messageID, userID, displayName, emoji)
+	}
+
+	return nil
+}
+
+func newBufferedReaction(kind, sessionID, messageID, userID, displayName, emoji string) *BufferedChatMessage {
+	return &BufferedChatMessage{
+		Kind:            kind,
+		SessionID:       sessionID,
+		UserID:          userID,
+		Content:         emoji,
+		DisplayName:     displayName,
+		ParentMessageID: messageID,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func bufferedToMessage(bm *BufferedChatMessage) *sessions.Message {
+	msg := &sessions.Message{
+		ID:        bm.ID,
+		SessionID: bm.SessionID,
 		Role:      "user",
-		Content:   content,
-		CreatedAt: msg.CreatedAt,
-	}, nil
+		Content:   bm.Content,
+		CreatedAt: bm.CreatedAt,
+	}
+	if bm.UserID != "" {
+		msg.UserID = &bm.UserID
+	}
+	if bm.DisplayName != "" {
+		msg.DisplayName = &bm.DisplayName
+	}
+	if bm.AvatarURL != "" {
+		msg.AvatarURL = &bm.AvatarURL
+	}
+	if bm.ParentMessageID != "" {
+		msg.ParentMessageID = &bm.ParentMessageID
+	}
+
+	return msg
+}
+
+// applies an unflushed reaction change to the message it belongs to, if loaded.
+// reactors are matched the same way as in Postgres: by user ID, else display name
+func applyBufferedReaction(messages []*sessions.Message, bm *BufferedChatMessage) {
+	sameReactor := func(r sessions.Reaction) bool {
+		if r.Emoji != bm.Content {
+			return false
+		}
+		if bm.UserID != "" {
+			return r.UserID != nil && *r.UserID == bm.UserID
+		}
+		return r.UserID == nil && r.DisplayName != nil && *r.DisplayName == bm.DisplayName
+	}
+
+	for _, msg := range messages {
+		if msg.ID != bm.ParentMessageID {
+			continue
+		}
+
+		exists := slices.ContainsFunc(msg.Reactions, sameReactor)
+
+		switch {
+		case bm.Kind == ChatEntryReactionRemoved:
+			msg.Reactions = slices.DeleteFunc(msg.Reactions, sameReactor)
+		case !exists:
+			reaction := sessions.Reaction{Emoji: bm.Content}
+			if bm.UserID != "" {
+				reaction.UserID = &bm.UserID
+			}
+			if bm.DisplayName != "" {
+				reaction.DisplayName = &bm.DisplayName
+			}
+			msg.Reactions = append(msg.Reactions, reaction)
+		}
+
+		return
+	}
 }
 
 // === PASS-THROUGH OPERATIONS (no buffering needed) ===
 
-func (r *BufferedRepository) CreateSession(ctx context.Context, req *sessions.CreateSessionRequest) (*sessions.Session, error) {
-	return r.db.CreateSession(ctx, req)
+func (r *BufferedRepository) CreateSession(ctx context.Context, req *sessions.CreateSessionRequest, inTx func(tx pgx.Tx, session *sessions.Session) error) (*sessions.Session, error) {
+	return r.db.CreateSession(ctx, req, inTx)
 }
 
 func (r *BufferedRepository) CreateAnonymousSession(ctx context.Context) (*sessions.Session, error) {
@@ -92,16 +195,32 @@ func (r *BufferedRepository) GetUserSessions(ctx context.Context, userID string,
 	return r.db.GetUserSessions(ctx, userID, activeOnly)
 }
 
-func (r *BufferedRepository) ListDiscoverableSessions(ctx context.Context, limit, offset int) ([]*sessions.Session, int, error) {
-	return r.db.ListDiscoverableSessions(ctx, limit, offset)
+func (r *BufferedRepository) ListDiscoverableSessions(ctx context.Context, page keyset.Page) ([]*sessions.Session, keyset.Edges, int, error) {
+	return r.db.ListDiscoverableSessions(ctx, page)
+}
+
+func (r *BufferedRepository) ListSessions(ctx context.Context, activeOnly bool, limit, offset int) ([]*sessions.Session, int, error) {
+	return r.db.ListSessions(ctx, activeOnly, limit, offset)
 }
 
 func (r *BufferedRepository) SetDiscoverable(ctx context.Context, sessionID string, isDiscoverable bool) error {
 	return r.db.SetDiscoverable(ctx, sessionID, isDiscoverable)
 }
 
-func (r *BufferedRepository) EndSession(ctx context.Context, sessionID string) error {
-	return r.db.EndSession(ctx, sessionID)
+func (r *BufferedRepository) SetChatSlowMode(ctx context.Context, sessionID string, seconds int) error {
+	return r.db.SetChatSlowMode(ctx, sessionID, seconds)
+}
+
+func (r *BufferedRepository) GetExpiryPolicy(ctx context.Context, sessionID string) (*sessions.ExpiryPolicy, error) {
+	return r.db.GetExpiryPolicy(ctx, sessionID)
+}
+
+func (r *BufferedRepository) SetExpiryPolicy(ctx context.Context, sessionID string, policy *sessions.ExpiryPolicy) error {
+	return r.db.SetExpiryPolicy(ctx, sessionID, policy)
+}
+
+func (r *BufferedRepository) EndSession(ctx context.Context, sessionID string, inTx func(tx pgx.Tx) error) error {
+	return r.db.EndSession(ctx, sessionID, inTx)
 }
 
 func (r *BufferedRepository) AddAuthenticatedParticipant(ctx context.Context, sessionID, userID, displayName, role string) (*sessions.Participant, error) {
@@ -124,6 +243,14 @@ func (r *BufferedRepository) UpdateParticipantRole(ctx context.Context, particip
 	return r.db.UpdateParticipantRole(ctx, participantID, role)
 }
 
+func (r *BufferedRepository) UpdateParticipantPermissions(ctx context.Context, participantID string, permissions *sessions.Permission) error {
+	return r.db.UpdateParticipantPermissions(ctx, participantID, permissions)
+}
+
+func (r *BufferedRepository) SetParticipantMuted(ctx context.Context, participantID string, muted bool) error {
+	return r.db.SetParticipantMuted(ctx, participantID, muted)
+}
+
 func (r *BufferedRepository) AddAnonymousParticipant(ctx context.Context, sessionID, displayName, role string) (*sessions.AnonymousParticipant, error) {
 	return r.db.AddAnonymousParticipant(ctx, sessionID, displayName, role)
 }
@@ -136,8 +263,8 @@ func (r *BufferedRepository) RemoveParticipant(ctx context.Context, participantI
 	return r.db.RemoveParticipant(ctx, participantID)
 }
 
-func (r *BufferedRepository) CreateInviteToken(ctx context.Context, req *sessions.CreateInviteTokenRequest) (*sessions.InviteToken, error) {
-	return r.db.CreateInviteToken(ctx, req)
+func (r *BufferedRepository) CreateInviteToken(ctx context.Context, req *sessions.CreateInviteTokenRequest, inTx func(tx pgx.Tx, token *sessions.InviteToken) error) (*sessions.InviteToken, error) {
+	return r.db.CreateInviteToken(ctx, req, inTx)
 }
 
 func (r *BufferedRepository) ListInviteTokens(ctx context.Context, sessionID string) ([]*sessions.InviteToken, error) {
@@ -148,8 +275,8 @@ func (r *BufferedRepository) ValidateInviteToken(ctx context.Context, token stri
 	return r.db.ValidateInviteToken(ctx, token)
 }
 
-func (r *BufferedRepository) IncrementTokenUses(ctx context.Context, tokenID string) error {
-	return r.db.IncrementTokenUses(ctx, tokenID)
+func (r *BufferedRepository) ClaimInviteToken(ctx context.Context, tokenID string) (bool, error) {
+	return r.db.ClaimInviteToken(ctx, tokenID)
 }
 
 func (r *BufferedRepository) RevokeInviteToken(ctx context.Context, tokenID string) error {
@@ -176,26 +303,93 @@ func (r *BufferedRepository) GetChatMessages(ctx context.Context, sessionID stri
 		return dbMessages, nil
 	}
 
-	// convert buffered messages to session messages
+	// convert buffered messages to session messages and apply buffered reactions
 	for _, bm := range bufferedMsgs {
-		msg := &sessions.Message{
-			SessionID: bm.SessionID,
-			Role:      "user",
-			Content:   bm.Content,
-			CreatedAt: bm.CreatedAt,
-		}
-		if bm.DisplayName != "" {
-			msg.DisplayName = &bm.DisplayName
-		}
-		if bm.AvatarURL != "" {
-			msg.AvatarURL = &bm.AvatarURL
+		switch bm.Kind {
+		case ChatEntryReactionAdded, ChatEntryReactionRemoved:
+			applyBufferedReaction(dbMessages, &bm)
+		default:
+			dbMessages = append(dbMessages, bufferedToMessage(&bm))
 		}
-		dbMessages = append(dbMessages, msg)
 	}
 
 	return dbMessages, nil
 }
 
+// ListChatMessages fetches the messages of a page like the Postgres version,
+// with unflushed messages from the buffer mixed in where they fall
+func (r *BufferedRepository) ListChatMessages(ctx context.Context, sessionID string, page keyset.Page) ([]*sessions.Message, error) {
+	messages, err := r.db.ListChatMessages(ctx, sessionID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	bufferedMsgs, err := r.buffer.GetBufferedChatMessages(ctx, sessionID)
+	if err != nil {
+		// log but don't fail - Postgres messages are still valid
+		logger.Warn("failed to get buffered chat messages", "session_id", sessionID, "error", err)
+		return messages, nil
+	}
+
+	if len(bufferedMsgs) == 0 {
+		return messages, nil
+	}
+
+	// the page may end anywhere among the buffered messages, so all of them
+	// join before the page is selected again
+	for _, bm := range bufferedMsgs {
+		switch bm.Kind {
+		case ChatEntryReactionAdded, ChatEntryReactionRemoved:
+			applyBufferedReaction(messages, &bm)
+		default:
+			messages = append(messages, bufferedToMessage(&bm))
+		}
+	}
+
+	return keyset.Select(page, messages, sessions.MessageKey), nil
+}
+
+// revisions are buffered by SessionBuffer.RecordCodeRevision, so this is only
+// called by the flusher
+func (r *BufferedRepository) AddCodeRevision(ctx context.Context, sessionID, code string, createdAt time.Time) error {
+	return r.db.AddCodeRevision(ctx, sessionID, code, createdAt)
+}
+
+// AI exchanges are rare, so they skip the buffer
+func (r *BufferedRepository) AddAgentExchange(ctx context.Context, exchange *sessions.AgentExchange) error {
+	return r.db.AddAgentExchange(ctx, exchange)
+}
+
+// unflushed chat and checkpoints are at most a flush interval behind, so
+// transcripts skip the buffer
+func (r *BufferedRepository) ListTranscript(ctx context.Context, sessionID string, limit int) ([]*sessions.TranscriptEntry, error) {
+	return r.db.ListTranscript(ctx, sessionID, limit)
+}
+
+// activity is buffered by SessionBuffer.RecordActivity, so this is only
+// called by the flusher
+func (r *BufferedRepository) AddActivity(ctx context.Context, sample *sessions.ActivitySample) error {
+	return r.db.AddActivity(ctx, sample)
+}
+
+// unflushed activity is at most a flush interval behind, so reads skip the buffer
+func (r *BufferedRepository) GetSessionAnalytics(ctx context.Context, sessionID string, bucket time.Duration, since time.Time) ([]*sessions.ActivitySample, error) {
+	return r.db.GetSessionAnalytics(ctx, sessionID, bucket, since)
+}
+
+// snapshots are immutable and rare, so they skip the buffer
+func (r *BufferedRepository) CreateSnapshot(ctx context.Context, sessionID, code, reason string) (*sessions.Snapshot, error) {
+	return r.db.CreateSnapshot(ctx, sessionID, code, reason)
+}
+
+func (r *BufferedRepository) ListSnapshots(ctx context.Context, sessionID string, limit, offset int) ([]*sessions.Snapshot, int, error) {
+	return r.db.ListSnapshots(ctx, sessionID, limit, offset)
+}
+
+func (r *BufferedRepository) GetSnapshot(ctx context.Context, sessionID, snapshotID string) (*sessions.Snapshot, error) {
+	return r.db.GetSnapshot(ctx, sessionID, snapshotID)
+}
+
 func (r *BufferedRepository) UpdateLastActivity(ctx context.Context, sessionID string) error {
 	return r.db.UpdateLastActivity(ctx, sessionID)
 }
@@ -218,10 +412,28 @@ func (r *BufferedRepository) GetLastUserSession(ctx context.Context, userID stri
 	return r.db.GetLastUserSession(ctx, userID)
 }
 
-func (r *BufferedRepository) ListStaleSessions(ctx context.Context, threshold time.Time) ([]*sessions.Session, error) {
-	return r.db.ListStaleSessions(ctx, threshold)
+func (r *BufferedRepository) ListStaleSessions(ctx context.Context, inactivityThreshold time.Duration) ([]*sessions.StaleSession, error) {
+	return r.db.ListStaleSessions(ctx, inactivityThreshold)
 }
 
 func (r *BufferedRepository) CountActiveParticipants(ctx context.Context, sessionID string) (int, error) {
 	return r.db.CountActiveParticipants(ctx, sessionID)
 }
+
+// === BULK WRITES (used by the flusher, which writes to the wrapped repository) ===
+
+func (r *BufferedRepository) UpdateSessionCodes(ctx context.Context, codes []sessions.SessionCode) error {
+	return r.db.UpdateSessionCodes(ctx, codes)
+}
+
+func (r *BufferedRepository) AddChatEntries(ctx context.Context, entries []sessions.ChatEntry) error {
+	return r.db.AddChatEntries(ctx, entries)
+}
+
+func (r *BufferedRepository) AddCodeRevisions(ctx context.Context, revisions []sessions.CodeRevision) error {
+	return r.db.AddCodeRevisions(ctx, revisions)
+}
+
+func (r *BufferedRepository) AddActivities(ctx context.Context, samples []sessions.ActivitySample) error {
+	return r.db.AddActivities(ctx, samples)
+}
@@ -0,0 +1,49 @@
+package buffer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+)
+
+func TestApplyBufferedReaction(t *testing.T) {
+	userID := "user-1"
+	messages := []*sessions.Message{{ID: "m1"}, {ID: "m2"}}
+
+	applyBufferedReaction(messages, newBufferedReaction(ChatEntryReactionAdded, "s1", "m2", userID, "Ada", "🔥"))
+	assert.Empty(t, messages[0].Reactions)
+	require.Len(t, messages[1].Reactions, 1)
+	assert.Equal(t, "🔥", messages[1].Reactions[0].Emoji)
+	assert.Equal(t, userID, *messages[1].Reactions[0].UserID)
+
+	// reacting twice with the same emoji is a no-op, like the unique index
+	applyBufferedReaction(messages, newBufferedReaction(ChatEntryReactionAdded, "s1", "m2", userID, "Ada", "🔥"))
+	assert.Len(t, messages[1].Reactions, 1)
+
+	// an anonymous guest with the same display name is a different reactor
+	applyBufferedReaction(messages, newBufferedReaction(ChatEntryReactionAdded, "s1", "m2", "", "Ada", "🔥"))
+	require.Len(t, messages[1].Reactions, 2)
+	assert.Nil(t, messages[1].Reactions[1].UserID)
+
+	applyBufferedReaction(messages, newBufferedReaction(ChatEntryReactionRemoved, "s1", "m2", userID, "Ada", "🔥"))
+	require.Len(t, messages[1].Reactions, 1)
+	assert.Nil(t, messages[1].Reactions[0].UserID)
+}
+
+func TestApplyBufferedReactionToUnloadedMessage(t *testing.T) {
+	messages := []*sessions.Message{{ID: "m1"}}
+
+	applyBufferedReaction(messages, newBufferedReaction(ChatEntryReactionAdded, "s1", "older", "user-1", "Ada", "👍"))
+	assert.Empty(t, messages[0].Reactions)
+}
+
+func TestBufferedToMessage(t *testing.T) {
+	msg := bufferedToMessage(&BufferedChatMessage{ID: "m2", SessionID: "s1", Content: "agreed", ParentMessageID: "m1"})
+	require.NotNil(t, msg.ParentMessageID)
+	assert.Equal(t, "m1", *msg.ParentMessageID)
+	assert.Nil(t, msg.UserID, "anonymous messages have no user")
+	assert.Nil(t, msg.AvatarURL)
+}
@@ -1,17 +1,32 @@
 package buffer
 
-import "time"
+import (
+	"sync"
+	"time"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+)
 
 // chat message waiting to be flushed to postgres
 type BufferedChatMessage struct {
-	SessionID   string    `json:"session_id"`
-	UserID      string    `json:"user_id,omitempty"`
-	Content     string    `json:"content"`
-	DisplayName string    `json:"display_name,omitempty"`
-	AvatarURL   string    `json:"avatar_url,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
+	Kind            string    `json:"kind,omitempty"` // empty for chat messages, see ChatEntry* for reactions
+	ID              string    `json:"id,omitempty"`
+	SessionID       string    `json:"session_id"`
+	UserID          string    `json:"user_id,omitempty"`
+	Content         string    `json:"content"` // the emoji for reactions
+	DisplayName     string    `json:"display_name,omitempty"`
+	AvatarURL       string    `json:"avatar_url,omitempty"`
+	ParentMessageID string    `json:"parent_message_id,omitempty"` // replied or reacted to
+	CreatedAt       time.Time `json:"created_at"`
 }
 
+// kinds of buffered chat entries besides messages. reactions share the
+// message list so they are flushed after the messages they refer to
+const (
+	ChatEntryReactionAdded   = sessions.ChatEntryReactionAdded
+	ChatEntryReactionRemoved = sessions.ChatEntryReactionRemoved
+)
+
 // redis key patterns
 const (
 	// session:{sessionID}:code - stores current code as string
@@ -26,6 +41,25 @@ const (
 	// dirty_sessions:messages - set of session IDs with unflushed messages
 	keyDirtySessionsMessages = "dirty_sessions:messages"
 
+	// session:{sessionID}:code_revisions - ring of code checkpoints as JSON list (newest last).
+	// the last entry is the current checkpoint, earlier entries are undo targets
+	keySessionCodeRevisions = "session:%s:code_revisions"
+
+	// session:{sessionID}:code_revisions:redo - checkpoints undone since the last edit (newest last)
+	keySessionCodeRedo = "session:%s:code_revisions:redo"
+
+	// session:{sessionID}:code_revisions:pending - checkpoints waiting to be flushed to postgres
+	keySessionCodeRevisionsPending = "session:%s:code_revisions:pending"
+
+	// dirty_sessions:code_revisions - set of session IDs with unflushed code revisions
+	keyDirtySessionsCodeRevisions = "dirty_sessions:code_revisions"
+
+	// session:{sessionID}:activity - activity samples waiting to be rolled up, as JSON list
+	keySessionActivity = "session:%s:activity"
+
+	// dirty_sessions:activity - set of session IDs with unflushed activity samples
+	keyDirtySessionsActivity = "dirty_sessions:activity"
+
 	// paste_lock:{sessionID} - indicates session has paste lock active
 	keyPasteLock = "paste_lock:%s"
 
@@ -34,11 +68,122 @@ const (
 
 	// rag_cache:{sessionID} - stores cached rag results (docs + examples)
 	keyRAGCache = "rag_cache:%s"
+
+	// agent_summary:{sessionID} - rolling summary of a draft's AI conversation
+	keyConversationSummary = "agent_summary:%s"
+
+	// agent_clarification:{scope} - clarifying questions awaiting an answer,
+	// scope is strudel:{strudelID} or session:{sessionID}
+	keyClarification = "agent_clarification:%s"
+)
+
+// flushing and backpressure
+const (
+	// entries written to postgres per statement, and the backlog one flush
+	// pass at the base interval is expected to keep up with
+	flushBatchSize = 1000
+
+	// entries taken from one session's list per pass, so a flooded session
+	// can't starve the others
+	flushSessionLimit = 500
+
+	// shortest wait between passes while a backlog drains
+	minFlushInterval = 250 * time.Millisecond
+
+	// how long one flush pass may take
+	flushPassTimeout = 30 * time.Second
+
+	// chat is refused while more entries than this wait in redis overall,
+	// or more chat entries than maxSessionBacklog for the session
+	maxBacklog        = 50000
+	maxSessionBacklog = 2000
+)
+
+// entries waiting in redis to be flushed, by kind. every instance shares the
+// lists, so every instance measures the same backlog
+type Backlog struct {
+	Code          int64 // sessions with unflushed code
+	Messages      int64
+	CodeRevisions int64
+	Activity      int64
+
+	// chat entries of each session with any waiting
+	SessionMessages map[string]int64
+}
+
+// sessions and the whole buffer over the backpressure thresholds, as of the
+// last flush pass
+type pressure struct {
+	overloaded bool
+	sessions   map[string]bool
+}
+
+// redis deployments NewSessionBuffer connects to
+const (
+	RedisModeSingle   = "single"
+	RedisModeSentinel = "sentinel"
+	RedisModeCluster  = "cluster"
 )
 
+const (
+	// how often redis is pinged to notice it going away and coming back
+	healthCheckInterval = 2 * time.Second
+
+	// how long a ping may take before redis counts as unreachable
+	pingTimeout = time.Second
+)
+
+// holds the writes the flusher persists while redis is unreachable, so
+// sessions keep working. the flusher drains it like the redis lists, and
+// whatever is left when redis is back is moved there
+type memoryBuffer struct {
+	mu     sync.Mutex
+	active bool
+	since  time.Time
+	cause  error
+
+	codes     map[string]string // latest code per session, kept for reads until redis is back
+	dirtyCode map[string]bool
+	messages  map[string][]BufferedChatMessage
+	revisions map[string][]BufferedCodeRevision
+	activity  map[string][]sessions.ActivitySample
+}
+
+// number of code checkpoints kept per session for undo
+const MaxCodeRevisions = 100
+
+// code checkpoint stored in the revision ring
+type BufferedCodeRevision struct {
+	SessionID string    `json:"session_id"`
+	Code      string    `json:"code"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // ttl for rag cache (reuse docs for follow-up messages within this window)
 const RAGCacheTTL = 10 * time.Minute
 
+// ttl for draft conversation summaries, refreshed whenever one is updated
+const ConversationSummaryTTL = 24 * time.Hour
+
+// summary of the first Folded messages of a draft's AI conversation. drafts
+// send their history with every request, Digest tells whether it still
+// starts with the messages the summary covers
+type CachedConversationSummary struct {
+	Summary string `json:"summary"`
+	Folded  int    `json:"folded"`
+	Digest  string `json:"digest"`
+}
+
+// how long clarifying questions wait for an answer
+const ClarificationTTL = 30 * time.Minute
+
+// clarifying questions the agent asked about a request
+type CachedClarification struct {
+	Query            string   `json:"query"`
+	ConcreteRequests []string `json:"concrete_requests,omitempty"`
+	Questions        []string `json:"questions"`
+}
+
 // stores retrieved docs and examples for reuse
 type CachedRAGResult struct {
 	Docs     []CachedDoc     `json:"docs"`
@@ -0,0 +1,253 @@
+// Package cards builds the OpenGraph preview cards of shared strudels: the
+// title, description and link the frontend renders into its pages, and an
+// image of the code generated by an image worker.
+package cards
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/internal/assets"
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// reads card settings from the environment
+func ConfigFromEnv() Config {
+	return Config{
+		WorkerURL:   os.Getenv("CARD_IMAGE_URL"),
+		WorkerToken: os.Getenv("CARD_IMAGE_TOKEN"),
+		AppURL:      strings.TrimRight(os.Getenv("APP_URL"), "/"),
+	}
+}
+
+// creates the card service. backend may be nil, which leaves cards without
+// images. image jobs are only claimed by instances that can run them
+func New(db *pgxpool.Pool, queue *jobs.Queue, strudelGetter StrudelGetter, backend assets.Backend, config Config) *Service {
+	s := &Service{
+		db:       db,
+		jobs:     queue,
+		strudels: strudelGetter,
+		backend:  backend,
+		client:   &http.Client{Timeout: workerTimeout},
+		config:   config,
+	}
+
+	if s.ImagesEnabled() {
+		queue.Register(jobImage, s.handleImageJob, jobs.WithMaxAttempts(maxAttempts))
+	}
+
+	return s
+}
+
+// reports whether the image worker and object storage are configured
+func (s *Service) ImagesEnabled() bool {
+	return s.config.WorkerURL != "" && s.backend != nil
+}
+
+// gets the card of a public strudel. while the image of its current title,
+// author and code isn't stored the card has none, and the image is queued
+func (s *Service) Get(ctx context.Context, strudelID string) (*Card, error) {
+	strudel, err := s.strudels.GetPublic(ctx, strudelID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrStrudelNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	card := s.card(strudel)
+	if !s.ImagesEnabled() {
+		return card, nil
+	}
+
+	version := cardVersion(strudel)
+
+	var storedVersion, imageURL string
+	err = s.db.QueryRow(ctx, queryGetCardImage, strudelID).Scan(&storedVersion, &imageURL)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	if storedVersion == version && imageURL != "" {
+		card.ImageURL = &imageURL
+		card.TwitterCard = "summary_large_image"
+		return card, nil
+	}
+
+	// the card is served without an image either way
+	if err := s.requestImage(ctx, strudelID, version); err != nil {
+		logger.ErrorErr(err, "failed to queue card image", "strudel_id", strudelID)
+	}
+
+	return card, nil
+}
+
+// the text of a strudel's card
+func (s *Service) card(strudel *strudels.Strudel) *Card {
+	description := strings.TrimSpace(strudel.Description)
+	if description == "" {
+		description = "A Strudel pattern"
+		if strudel.AuthorName != "" {
+			description += " by " + strudel.AuthorName
+		}
+		description += ", live coded on algopatterns."
+	}
+
+	return &Card{
+		Title:       strudel.Title,
+		Description: truncate(description, maxDescriptionLength),
+		AuthorName:  strudel.AuthorName,
+		URL:         s.config.AppURL + "/strudel/" + strudel.ID,
+		ImageWidth:  ImageWidth,
+		ImageHeight: ImageHeight,
+		ImageAlt:    fmt.Sprintf("Code of %q", strudel.Title),
+		TwitterCard: "summary",
+		UpdatedAt:   strudel.UpdatedAt,
+	}
+}
+
+// queues the image of a version unless it is stored or queued recently
+func (s *Service) requestImage(ctx context.Context, strudelID, version string) error {
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		var requested string
+		err := tx.QueryRow(ctx, queryRequestCardImage, strudelID, version, requestRetryAfter.Seconds()).Scan(&requested)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.jobs.EnqueueTx(ctx, tx, jobImage, imageJob{StrudelID: strudelID})
+	})
+}
+
+// generates and stores the image of the strudel's current version
+func (s *Service) handleImageJob(ctx context.Context, payload json.RawMessage) error {
+	var job imageJob
+	if err := jobs.Decode(payload, &job); err != nil {
+		return err
+	}
+
+	strudel, err := s.strudels.GetPublic(ctx, job.StrudelID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// unpublished or deleted since, the cleanup removes its card
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	version := cardVersion(strudel)
+
+	var storedVersion string
+	if err := s.db.QueryRow(ctx, queryGetCardVersion, strudel.ID).Scan(&storedVersion); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	if storedVersion == version {
+		return nil
+	}
+
+	image, err := s.rasterize(ctx, cardSVG(strudel))
+	if err != nil {
+		return err
+	}
+
+	// versioned keys, so caches in front of storage never serve a stale image
+	key := fmt.Sprintf("cards/strudels/%s/%s.png", strudel.ID, version)
+	if err := s.backend.Put(ctx, key, "image/png", image); err != nil {
+		return err
+	}
+
+	var replaced string
+	err = s.db.QueryRow(ctx, queryStoreCardImage, strudel.ID, version, key, s.backend.URL(key)).Scan(&replaced)
+	if err != nil {
+		return err
+	}
+
+	if replaced != "" && replaced != key {
+		if err := s.backend.Delete(ctx, replaced); err != nil {
+			logger.Warn("failed to delete replaced card image", "key", replaced, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// deletes the cards and images of strudels that were unpublished or
+// deleted, run periodically
+func (s *Service) DeleteStaleCards(ctx context.Context) {
+	rows, err := s.db.Query(ctx, queryListStaleCards, cleanupBatch)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to list stale cards")
+		}
+		return
+	}
+
+	stale, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (staleCard, error) {
+		var c staleCard
+		err := row.Scan(&c.strudelID, &c.objectKey)
+		return c, err
+	})
+	if err != nil {
+		logger.ErrorErr(err, "failed to list stale cards")
+		return
+	}
+
+	deleted := 0
+	for _, c := range stale {
+		if c.objectKey != "" && s.backend != nil {
+			if err := s.backend.Delete(ctx, c.objectKey); err != nil {
+				// kept for the next run
+				logger.Warn("failed to delete card image", "error", err, "strudel_id", c.strudelID)
+				continue
+			}
+		}
+
+		if _, err := s.db.Exec(ctx, queryDeleteCard, c.strudelID); err != nil {
+			logger.Warn("failed to delete card", "error", err, "strudel_id", c.strudelID)
+			continue
+		}
+
+		deleted++
+	}
+
+	if deleted > 0 {
+		logger.Info("deleted cards of unpublished strudels", "count", deleted)
+	}
+}
+
+// identifies what the image of a strudel shows
+func cardVersion(strudel *strudels.Strudel) string {
+	h := sha256.New()
+	for _, part := range []string{layoutVersion, strudel.Title, strudel.AuthorName, strudel.Code} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// shortens text to at most n characters, ending it with an ellipsis
+func truncate(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
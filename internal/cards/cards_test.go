@@ -0,0 +1,165 @@
+package cards
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/internal/jobs"
+)
+
+// serves one public strudel
+type publicStrudel struct {
+	strudel *strudels.Strudel
+}
+
+func (p publicStrudel) GetPublic(_ context.Context, strudelID string) (*strudels.Strudel, error) {
+	if p.strudel == nil || p.strudel.ID != strudelID {
+		return nil, pgx.ErrNoRows
+	}
+
+	return p.strudel, nil
+}
+
+func testStrudel() *strudels.Strudel {
+	return &strudels.Strudel{
+		ID:         "s1",
+		Title:      "Night <drive>",
+		AuthorName: "ada",
+		Code:       "note(\"c e g\")\n\t.s(\"piano\")",
+	}
+}
+
+func TestGetWithoutImages(t *testing.T) {
+	s := New(nil, jobs.New(nil), publicStrudel{testStrudel()}, nil, Config{AppURL: "https://algopatterns.dev"})
+
+	card, err := s.Get(context.Background(), "s1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if card.URL != "https://algopatterns.dev/strudel/s1" {
+		t.Errorf("URL = %q", card.URL)
+	}
+	if card.Description != "A Strudel pattern by ada, live coded on algopatterns." {
+		t.Errorf("Description = %q", card.Description)
+	}
+	if card.ImageURL != nil || card.TwitterCard != "summary" {
+		t.Errorf("card without images has image %v and twitter card %q", card.ImageURL, card.TwitterCard)
+	}
+
+	if _, err := s.Get(context.Background(), "missing"); !errors.Is(err, ErrStrudelNotFound) {
+		t.Errorf("Get() of a missing strudel error = %v, want ErrStrudelNotFound", err)
+	}
+}
+
+func TestCardVersion(t *testing.T) {
+	base := cardVersion(testStrudel())
+
+	edits := map[string]func(*strudels.Strudel){
+		"title":  func(s *strudels.Strudel) { s.Title = "Day drive" },
+		"code":   func(s *strudels.Strudel) { s.Code += "\n.slow(2)" },
+		"author": func(s *strudels.Strudel) { s.AuthorName = "grace" },
+	}
+	for name, edit := range edits {
+		strudel := testStrudel()
+		edit(strudel)
+
+		if cardVersion(strudel) == base {
+			t.Errorf("changing the %s didn't change the version", name)
+		}
+	}
+
+	strudel := testStrudel()
+	strudel.Description = "new description"
+	if cardVersion(strudel) != base {
+		t.Error("changing the description changed the version, but it isn't on the image")
+	}
+}
+
+func TestCardSVG(t *testing.T) {
+	svg := string(cardSVG(testStrudel()))
+
+	for _, want := range []string{
+		"Night &lt;drive&gt;",
+		"by ada",
+		`&#34;c e g&#34;`,
+		`<tspan x="80" y="270">`, // second line of code
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("cardSVG() missing %q in:\n%s", want, svg)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	code := strings.Repeat("s(\"bd\")\n", 20) + strings.Repeat("x", 100)
+
+	lines := snippet(code)
+	if len(lines) != maxSnippetLines {
+		t.Fatalf("snippet() = %d lines, want %d", len(lines), maxSnippetLines)
+	}
+	if lines[len(lines)-1] != "…" {
+		t.Errorf("last line = %q, want an ellipsis", lines[len(lines)-1])
+	}
+
+	if got := snippet(strings.Repeat("x", 100))[0]; len([]rune(got)) != maxSnippetColumns {
+		t.Errorf("long line cut to %d characters, want %d", len([]rune(got)), maxSnippetColumns)
+	}
+}
+
+func TestRasterize(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantErr   bool
+		permanent bool
+	}{
+		{"png", http.StatusOK, "\x89PNG\r\n\x1a\nrest", false, false},
+		{"not a png", http.StatusOK, "<html>", true, true},
+		{"rejected", http.StatusBadRequest, "", true, true},
+		{"worker down", http.StatusBadGateway, "", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Type") != "image/svg+xml" || r.Header.Get("Authorization") != "Bearer token" {
+					t.Errorf("unexpected request headers %v", r.Header)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body)) //nolint:errcheck,gosec // test server
+			}))
+			defer server.Close()
+
+			s := &Service{client: server.Client(), config: Config{WorkerURL: server.URL, WorkerToken: "token"}}
+
+			image, err := s.rasterize(context.Background(), []byte("<svg/>"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rasterize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && jobs.IsPermanent(err) != tt.permanent {
+				t.Errorf("rasterize() error %v permanent = %v, want %v", err, jobs.IsPermanent(err), tt.permanent)
+			}
+			if err == nil && string(image) != tt.body {
+				t.Errorf("rasterize() = %q, want the worker's body", image)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("truncate() = %q, want it unchanged", got)
+	}
+	if got := truncate("a long title here", 8); got != "a long…" {
+		t.Errorf("truncate() = %q, want %q", got, "a long…")
+	}
+}
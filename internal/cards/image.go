@@ -0,0 +1,167 @@
+package cards
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/internal/jobs"
+)
+
+// colors of the card
+const (
+	backgroundColor = "#0d1117"
+	codeBoxColor    = "#161b22"
+	textColor       = "#e6edf3"
+	mutedColor      = "#8b949e"
+	accentColor     = "#a371f7"
+)
+
+// chroma style the code is highlighted with, matching the dark card
+const highlightStyle = "github-dark"
+
+var codeLexer = chroma.Coalesce(lexers.Get("javascript"))
+
+// draws the card of a strudel as an SVG: the title, the author and the start
+// of the highlighted code
+func cardSVG(strudel *strudels.Strudel) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		ImageWidth, ImageHeight, ImageWidth, ImageHeight)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", backgroundColor)
+
+	fmt.Fprintf(&b, `<text x="64" y="104" font-family="sans-serif" font-size="56" font-weight="bold" fill="%s">%s</text>`+"\n",
+		textColor, escape(truncate(strudel.Title, maxTitleLength)))
+
+	if strudel.AuthorName != "" {
+		fmt.Fprintf(&b, `<text x="64" y="152" font-family="sans-serif" font-size="28" fill="%s">by %s</text>`+"\n",
+			mutedColor, escape(truncate(strudel.AuthorName, maxTitleLength)))
+	}
+
+	fmt.Fprintf(&b, `<rect x="48" y="188" width="%d" height="372" rx="16" fill="%s"/>`+"\n", ImageWidth-96, codeBoxColor)
+	b.WriteString(`<text font-family="monospace" font-size="24" xml:space="preserve">` + "\n")
+	for i, line := range highlightedLines(snippet(strudel.Code)) {
+		fmt.Fprintf(&b, `<tspan x="80" y="%d">%s</tspan>`+"\n", 236+i*34, line)
+	}
+	b.WriteString("</text>\n")
+
+	fmt.Fprintf(&b, `<text x="64" y="606" font-family="sans-serif" font-size="24" font-weight="bold" fill="%s">algopatterns</text>`+"\n", accentColor)
+	b.WriteString("</svg>\n")
+
+	return []byte(b.String())
+}
+
+// the lines of code shown on the card, cut to fit
+func snippet(code string) []string {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(code, "\t", "  ")), "\n")
+	if len(lines) > maxSnippetLines {
+		lines = append(lines[:maxSnippetLines-1], "…")
+	}
+
+	for i, line := range lines {
+		lines[i] = truncate(strings.TrimRight(line, " \r"), maxSnippetColumns)
+	}
+
+	return lines
+}
+
+// highlights lines of code as SVG tspans, one string per line
+func highlightedLines(lines []string) []string {
+	style := styles.Get(highlightStyle)
+
+	tokens, err := codeLexer.Tokenise(nil, strings.Join(lines, "\n"))
+	if err != nil {
+		// plain text still makes a card
+		out := make([]string, len(lines))
+		for i, line := range lines {
+			out[i] = fmt.Sprintf(`<tspan fill="%s">%s</tspan>`, textColor, escape(line))
+		}
+		return out
+	}
+
+	split := chroma.SplitTokensIntoLines(tokens.Tokens())
+
+	out := make([]string, len(lines))
+	for i := range out {
+		if i >= len(split) {
+			break
+		}
+
+		var b strings.Builder
+		for _, token := range split[i] {
+			text := strings.TrimSuffix(token.Value, "\n")
+			if text == "" {
+				continue
+			}
+
+			color := textColor
+			if entry := style.Get(token.Type); entry.Colour.IsSet() {
+				color = entry.Colour.String()
+			}
+
+			fmt.Fprintf(&b, `<tspan fill="%s">%s</tspan>`, color, escape(text))
+		}
+
+		out[i] = b.String()
+	}
+
+	return out
+}
+
+// asks the image worker to turn an SVG into a PNG. rejections are permanent,
+// other failures worth retrying
+func (s *Service) rasterize(ctx context.Context, svg []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.WorkerURL, bytes.NewReader(svg))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "image/svg+xml")
+	req.Header.Set("Accept", "image/png")
+	if s.config.WorkerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.WorkerToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("image worker request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("image worker returned %d", resp.StatusCode)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, jobs.Permanent(fmt.Errorf("%w: %w", ErrWorkerRejected, err))
+		}
+
+		return nil, err
+	}
+
+	image, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read card image: %w", err)
+	}
+
+	if len(image) > maxImageBytes || !bytes.HasPrefix(image, []byte("\x89PNG\r\n\x1a\n")) {
+		return nil, jobs.Permanent(ErrInvalidImage)
+	}
+
+	return image, nil
+}
+
+// escapes text for SVG
+func escape(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text)) //nolint:errcheck,gosec // writing to a strings.Builder doesn't fail
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package cards
+
+const (
+	queryGetCardImage = `
+		SELECT COALESCE(version, ''), COALESCE(image_url, '')
+		FROM strudel_cards
+		WHERE strudel_id = $1
+	`
+
+	// marks a version as being generated. nothing is returned when its image
+	// is stored, or was requested recently and may still be generated
+	queryRequestCardImage = `
+		INSERT INTO strudel_cards (strudel_id, pending_version, requested_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (strudel_id) DO UPDATE
+		SET pending_version = EXCLUDED.pending_version, requested_at = NOW()
+		WHERE strudel_cards.version IS DISTINCT FROM EXCLUDED.pending_version
+		  AND (strudel_cards.pending_version IS DISTINCT FROM EXCLUDED.pending_version
+		    OR strudel_cards.requested_at < NOW() - make_interval(secs => $3))
+		RETURNING strudel_id
+	`
+
+	queryGetCardVersion = `
+		SELECT COALESCE(version, '') FROM strudel_cards WHERE strudel_id = $1
+	`
+
+	// stores a generated image, returning the key of the one it replaced
+	queryStoreCardImage = `
+		WITH old AS (
+			SELECT strudel_id, object_key FROM strudel_cards WHERE strudel_id = $1 FOR UPDATE
+		)
+		UPDATE strudel_cards c
+		SET version = $2, object_key = $3, image_url = $4,
+			pending_version = NULLIF(c.pending_version, $2), updated_at = NOW()
+		FROM old
+		WHERE c.strudel_id = old.strudel_id
+		RETURNING COALESCE(old.object_key, '')
+	`
+
+	// cards of strudels that were unpublished or deleted
+	queryListStaleCards = `
+		SELECT c.strudel_id, COALESCE(c.object_key, '')
+		FROM strudel_cards c
+		JOIN user_strudels s ON s.id = c.strudel_id
+		WHERE s.is_public = false OR s.deleted_at IS NOT NULL
+		LIMIT $1
+	`
+
+	queryDeleteCard = `
+		DELETE FROM strudel_cards WHERE strudel_id = $1
+	`
+)
@@ -0,0 +1,105 @@
+package cards
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/internal/assets"
+	"codeberg.org/algopatterns/server/internal/jobs"
+)
+
+// background job that generates the image of a card
+const jobImage = "cards.image"
+
+const (
+	// size of card images, the one social networks show large
+	ImageWidth  = 1200
+	ImageHeight = 630
+
+	// changing the card layout changes every version, regenerating images
+	// as they're requested
+	layoutVersion = "1"
+
+	// code shown on the image
+	maxSnippetLines   = 10
+	maxSnippetColumns = 64
+
+	// characters of the title on the image, and of descriptions
+	maxTitleLength       = 40
+	maxDescriptionLength = 200
+
+	// attempts before the image of a version is given up on, until it is
+	// requested again after requestRetryAfter
+	maxAttempts       = 3
+	requestRetryAfter = 10 * time.Minute
+
+	// timeout of image worker requests
+	workerTimeout = 30 * time.Second
+
+	// largest image accepted from the image worker
+	maxImageBytes = 5 << 20
+
+	// cards of strudels no longer public deleted per cleanup run
+	cleanupBatch = 100
+)
+
+// errors
+var (
+	ErrStrudelNotFound = errors.New("strudel not found")
+	ErrWorkerRejected  = errors.New("image worker rejected the card")
+	ErrInvalidImage    = errors.New("image worker returned no PNG")
+)
+
+// settings of card generation, read from the environment. images stay
+// disabled unless the worker URL is set and object storage is configured
+type Config struct {
+	WorkerURL   string // CARD_IMAGE_URL, endpoint of the worker turning SVG into PNG
+	WorkerToken string // CARD_IMAGE_TOKEN, sent as a bearer token
+	AppURL      string // APP_URL, the frontend the cards link to
+}
+
+// reads public strudels (implemented by strudels.Repository)
+type StrudelGetter interface {
+	GetPublic(ctx context.Context, strudelID string) (*strudels.Strudel, error)
+}
+
+// OpenGraph and Twitter preview cards of shared public strudels. images are
+// generated in the background and cached in object storage per version of
+// the title, author and code
+type Service struct {
+	db       *pgxpool.Pool
+	jobs     *jobs.Queue
+	strudels StrudelGetter
+	backend  assets.Backend // nil when storage is not configured
+	client   *http.Client
+	config   Config
+}
+
+// what a shared link to a strudel previews as
+type Card struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	AuthorName  string    `json:"author_name,omitempty"`
+	URL         string    `json:"url"`       // the strudel's page on the frontend
+	ImageURL    *string   `json:"image_url"` // nil until the image of the current version is generated
+	ImageWidth  int       `json:"image_width"`
+	ImageHeight int       `json:"image_height"`
+	ImageAlt    string    `json:"image_alt"`
+	TwitterCard string    `json:"twitter_card"` // summary_large_image with an image, summary without
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type imageJob struct {
+	StrudelID string `json:"strudel_id"`
+}
+
+// a card image to delete
+type staleCard struct {
+	strudelID string
+	objectKey string
+}
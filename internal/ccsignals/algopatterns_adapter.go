@@ -33,6 +33,24 @@ func (v *StrudelValidator) ValidateOwnership(ctx context.Context, userID, code s
 	}, nil
 }
 
+// checks if the user published a strudel with this exact code
+func (v *StrudelValidator) ValidatePublishedOwnership(ctx context.Context, userID, code string) (*ContentMatch, error) {
+	published, err := v.repo.UserPublishedStrudelWithCode(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !published {
+		return &ContentMatch{Found: false}, nil
+	}
+
+	return &ContentMatch{
+		Found:    true,
+		OwnerID:  userID,
+		IsPublic: true,
+	}, nil
+}
+
 // checks if code matches any public strudel
 func (v *StrudelValidator) ValidatePublicContent(ctx context.Context, code string) (*ContentMatch, error) {
 	allowsAI, err := v.repo.PublicStrudelExistsWithCodeAllowsAI(ctx, code)
@@ -17,6 +17,7 @@ type Detector struct {
 	store        LockStore
 	validator    ContentValidator
 	fingerprints *IndexedFingerprintStore
+	decisions    DecisionLog
 }
 
 // creates a new detector with the given dependencies
@@ -34,16 +35,23 @@ func (d *Detector) WithFingerprints(fps *IndexedFingerprintStore) *Detector {
 	return d
 }
 
+// records lock decisions so they can be inspected later
+func (d *Detector) WithDecisionLog(log DecisionLog) *Detector {
+	d.decisions = log
+	return d
+}
+
 // contains the result of paste detection
 type DetectionResult struct {
 	ShouldLock       bool
 	Reason           string
 	MatchedContent   *ContentMatch
 	FingerprintMatch *MatchResult
+	PartialMatch     *PartialMatch // a section of a protected work matched the pasted lines
 }
 
 // analyzes a code update and determines if it should be locked
-func (d *Detector) DetectPaste(ctx context.Context, _, userID, previousCode, newCode string) (*DetectionResult, error) {
+func (d *Detector) DetectPaste(ctx context.Context, sessionID, userID, previousCode, newCode string) (*DetectionResult, error) {
 	if !d.IsLargeDelta(previousCode, newCode) {
 		return &DetectionResult{
 			ShouldLock: false,
@@ -52,7 +60,14 @@ func (d *Detector) DetectPaste(ctx context.Context, _, userID, previousCode, new
 	}
 
 	// large delta detected - validate against legitimate sources
+	result := d.validatePaste(ctx, userID, previousCode, newCode)
+	d.recordDetection(ctx, sessionID, userID, newCode, result)
 
+	return result, nil
+}
+
+// checks a large delta against the user's own work, public work and protected fingerprints
+func (d *Detector) validatePaste(ctx context.Context, userID, previousCode, newCode string) *DetectionResult {
 	// check 1: does code match user's own content?
 	if userID != "" && d.validator != nil {
 		match, err := d.validator.ValidateOwnership(ctx, userID, newCode)
@@ -61,7 +76,7 @@ func (d *Detector) DetectPaste(ctx context.Context, _, userID, previousCode, new
 				ShouldLock:     false,
 				Reason:         "code matches user's own content",
 				MatchedContent: match,
-			}, nil
+			}
 		}
 	}
 
@@ -74,14 +89,14 @@ func (d *Detector) DetectPaste(ctx context.Context, _, userID, previousCode, new
 					ShouldLock:     false,
 					Reason:         "code matches public content that allows AI",
 					MatchedContent: match,
-				}, nil
+				}
 			}
 
 			return &DetectionResult{
 				ShouldLock:     true,
 				Reason:         "code matches public content with no-ai restriction",
 				MatchedContent: match,
-			}, nil
+			}
 		}
 	}
 
@@ -94,14 +109,32 @@ func (d *Detector) DetectPaste(ctx context.Context, _, userID, previousCode, new
 					ShouldLock:       true,
 					Reason:           "content is similar to protected work with no-ai restriction",
 					FingerprintMatch: fpMatch,
-				}, nil
+				}
 			}
 
 			return &DetectionResult{
 				ShouldLock:       false,
 				Reason:           "content is similar to work that allows AI",
 				FingerprintMatch: fpMatch,
-			}, nil
+			}
+		}
+
+		// check 4: do the pasted lines match a section of protected work?
+		partial := d.fingerprints.FindBestPartialMatch(pastedSection(previousCode, newCode))
+		if partial != nil {
+			if !partial.CCSignal.AllowsAI() {
+				return &DetectionResult{
+					ShouldLock:   true,
+					Reason:       "pasted section is similar to protected work with no-ai restriction",
+					PartialMatch: partial,
+				}
+			}
+
+			return &DetectionResult{
+				ShouldLock:   false,
+				Reason:       "pasted section is similar to work that allows AI",
+				PartialMatch: partial,
+			}
 		}
 	}
 
@@ -109,7 +142,7 @@ func (d *Detector) DetectPaste(ctx context.Context, _, userID, previousCode, new
 	return &DetectionResult{
 		ShouldLock: true,
 		Reason:     "large delta with no matching content in database",
-	}, nil
+	}
 }
 
 // handles a code update event, managing locks as needed
@@ -146,7 +179,17 @@ func (d *Detector) CheckUnlock(ctx context.Context, sessionID, currentCode strin
 	}
 
 	if d.IsSignificantEdit(state.BaselineCode, currentCode) {
-		return d.store.RemoveLock(ctx, sessionID)
+		if err := d.store.RemoveLock(ctx, sessionID); err != nil {
+			return err
+		}
+
+		d.record(ctx, sessionID, Decision{
+			Action:     DecisionUnlock,
+			Reason:     "significant edits since the paste",
+			CodeLength: len(currentCode),
+		})
+
+		return nil
 	}
 
 	return d.store.RefreshTTL(ctx, sessionID, d.config.LockTTL)
@@ -166,6 +209,97 @@ func (d *Detector) IsLocked(ctx context.Context, sessionID string) (bool, error)
 	return state != nil && state.Locked, nil
 }
 
+// returns the current lock state of a session
+func (d *Detector) GetLock(ctx context.Context, sessionID string) (*LockState, error) {
+	if d.store == nil {
+		return nil, ErrNilStore
+	}
+
+	return d.store.GetLock(ctx, sessionID)
+}
+
+// returns the latest decisions for a session, newest first. empty when no
+// decision log is set.
+func (d *Detector) RecentDecisions(ctx context.Context, sessionID string, limit int) ([]Decision, error) {
+	if d.decisions == nil {
+		return []Decision{}, nil
+	}
+
+	return d.decisions.RecentDecisions(ctx, sessionID, limit)
+}
+
+// returns the most recent lock decision for a session, nil when none was
+// logged. it explains why the session is locked
+func (d *Detector) LockDecision(ctx context.Context, sessionID string) (*Decision, error) {
+	decisions, err := d.RecentDecisions(ctx, sessionID, maxDecisions)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range decisions {
+		if decisions[i].Action == DecisionLock {
+			return &decisions[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// lifts a session's lock when the user owns what was pasted: the protected
+// work it matched is theirs, or they published a strudel with the pasted
+// code. reports whether the lock was lifted
+func (d *Detector) UnlockForOwner(ctx context.Context, sessionID, userID string) (bool, error) {
+	if d.store == nil {
+		return false, ErrNilStore
+	}
+
+	state, err := d.store.GetLock(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	if state == nil || !state.Locked || userID == "" {
+		return false, nil
+	}
+
+	owner := false
+
+	decision, err := d.LockDecision(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	if decision != nil && decision.MatchedWorkID != "" && decision.MatchedOwnerID == userID {
+		owner = true
+	}
+
+	if !owner && d.validator != nil {
+		match, err := d.validator.ValidatePublishedOwnership(ctx, userID, state.BaselineCode)
+		if err != nil {
+			return false, err
+		}
+
+		owner = match != nil && match.Found
+	}
+
+	if !owner {
+		return false, nil
+	}
+
+	if err := d.store.RemoveLock(ctx, sessionID); err != nil {
+		return false, err
+	}
+
+	d.record(ctx, sessionID, Decision{
+		Action:     DecisionUnlock,
+		Reason:     "ownership verified on appeal",
+		UserID:     userID,
+		CodeLength: len(state.BaselineCode),
+	})
+
+	return true, nil
+}
+
 // sets a paste lock for a session
 func (d *Detector) SetLock(ctx context.Context, sessionID, baselineCode string, ttl time.Duration) error {
 	if d.store == nil {
@@ -184,6 +318,51 @@ func (d *Detector) RemoveLock(ctx context.Context, sessionID string) error {
 	return d.store.RemoveLock(ctx, sessionID)
 }
 
+// adds the outcome of a paste check to the decision log
+func (d *Detector) recordDetection(ctx context.Context, sessionID, userID, code string, result *DetectionResult) {
+	decision := Decision{
+		Action:     DecisionAllow,
+		Reason:     result.Reason,
+		UserID:     userID,
+		CodeLength: len(code),
+	}
+
+	if result.ShouldLock {
+		decision.Action = DecisionLock
+	}
+
+	if result.MatchedContent != nil {
+		decision.MatchedOwnerID = result.MatchedContent.OwnerID
+		decision.CCSignal = result.MatchedContent.CCSignal
+	}
+
+	if result.FingerprintMatch != nil && result.FingerprintMatch.Record != nil {
+		decision.MatchedOwnerID = result.FingerprintMatch.Record.CreatorID
+		decision.MatchedWorkID = result.FingerprintMatch.Record.WorkID
+		decision.CCSignal = result.FingerprintMatch.Record.CCSignal
+	}
+
+	if result.PartialMatch != nil {
+		decision.MatchedOwnerID = result.PartialMatch.CreatorID
+		decision.MatchedWorkID = result.PartialMatch.WorkID
+		decision.CCSignal = result.PartialMatch.CCSignal
+		decision.MatchedLines = result.PartialMatch.Lines()
+		decision.Coverage = result.PartialMatch.Coverage
+	}
+
+	d.record(ctx, sessionID, decision)
+}
+
+// best-effort: the log is only used for moderation, so failures never affect detection
+func (d *Detector) record(ctx context.Context, sessionID string, decision Decision) {
+	if d.decisions == nil || sessionID == "" {
+		return
+	}
+
+	decision.DecidedAt = time.Now()
+	d.decisions.RecordDecision(ctx, sessionID, decision) //nolint:errcheck,gosec // best-effort
+}
+
 // determines if a code update has a large delta
 func (d *Detector) IsLargeDelta(previousCode, newCode string) bool {
 	// significant growth (paste addition)
@@ -356,6 +356,181 @@ func TestDetector_WithFingerprints(t *testing.T) {
 	})
 }
 
+func TestDetector_DecisionLog(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+
+	t.Run("records large delta outcomes newest first", func(t *testing.T) {
+		store := NewMemoryLockStore()
+		defer func() { _ = store.Close() }() //nolint:errcheck // test cleanup
+
+		validator := &mockValidator{
+			publicMatch: &ContentMatch{Found: true, OwnerID: "owner1", CCSignal: SignalNoAI},
+		}
+		d := NewDetector(config, store, validator).WithDecisionLog(store)
+
+		largeCode := string(make([]byte, 300))
+		if _, err := d.DetectPaste(ctx, "session1", "user1", "hello", "hello world"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := d.DetectPaste(ctx, "session1", "user1", "", largeCode); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		decisions, err := d.RecentDecisions(ctx, "session1", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(decisions) != 1 {
+			t.Fatalf("expected 1 decision (small deltas aren't recorded), got %d", len(decisions))
+		}
+
+		decision := decisions[0]
+		if decision.Action != DecisionLock || decision.UserID != "user1" || decision.MatchedOwnerID != "owner1" || decision.CCSignal != SignalNoAI {
+			t.Errorf("unexpected decision: %+v", decision)
+		}
+		if decision.CodeLength != len(largeCode) || decision.DecidedAt.IsZero() {
+			t.Errorf("expected code length and time to be set: %+v", decision)
+		}
+
+		// significant edits unlock and are recorded too
+		if err := store.SetLock(ctx, "session1", largeCode, config.LockTTL); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := d.CheckUnlock(ctx, "session1", "completely different"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		decisions, err = d.RecentDecisions(ctx, "session1", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(decisions) != 2 || decisions[0].Action != DecisionUnlock {
+			t.Errorf("expected unlock as newest decision, got %+v", decisions)
+		}
+	})
+
+	t.Run("keeps the latest decisions", func(t *testing.T) {
+		store := NewMemoryLockStore()
+		defer func() { _ = store.Close() }() //nolint:errcheck // test cleanup
+
+		for i := range maxDecisions + 5 {
+			if err := store.RecordDecision(ctx, "session1", Decision{CodeLength: i}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		decisions, err := store.RecentDecisions(ctx, "session1", maxDecisions+5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(decisions) != maxDecisions {
+			t.Fatalf("expected %d decisions, got %d", maxDecisions, len(decisions))
+		}
+		if decisions[0].CodeLength != maxDecisions+4 {
+			t.Errorf("expected newest decision first, got %+v", decisions[0])
+		}
+	})
+
+	t.Run("no decision log returns empty", func(t *testing.T) {
+		d := NewDetector(config, nil, nil)
+
+		decisions, err := d.RecentDecisions(ctx, "session1", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(decisions) != 0 {
+			t.Errorf("expected no decisions, got %d", len(decisions))
+		}
+	})
+}
+
+func TestDetector_UnlockForOwner(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultConfig()
+	pasted := string(make([]byte, 300))
+
+	lockedDetector := func(t *testing.T, validator *mockValidator, decision Decision) (*Detector, *MemoryLockStore) {
+		store := NewMemoryLockStore()
+		t.Cleanup(func() { _ = store.Close() }) //nolint:errcheck // test cleanup
+
+		if err := store.SetLock(ctx, "session1", pasted, config.LockTTL); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.RecordDecision(ctx, "session1", decision); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return NewDetector(config, store, validator).WithDecisionLog(store), store
+	}
+
+	t.Run("owner of the matched work", func(t *testing.T) {
+		d, _ := lockedDetector(t, &mockValidator{}, Decision{Action: DecisionLock, MatchedOwnerID: "user1", MatchedWorkID: "work1"})
+
+		unlocked, err := d.UnlockForOwner(ctx, "session1", "user1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !unlocked {
+			t.Error("expected the owner of the matched work to unlock")
+		}
+
+		locked, _ := d.IsLocked(ctx, "session1")
+		if locked {
+			t.Error("expected session to be unlocked")
+		}
+
+		decision, _ := d.RecentDecisions(ctx, "session1", 1)
+		if len(decision) != 1 || decision[0].Action != DecisionUnlock || decision[0].UserID != "user1" {
+			t.Errorf("expected the unlock to be recorded, got %+v", decision)
+		}
+	})
+
+	t.Run("published the pasted code", func(t *testing.T) {
+		validator := &mockValidator{publishedMatch: &ContentMatch{Found: true, OwnerID: "user1", IsPublic: true}}
+		d, _ := lockedDetector(t, validator, Decision{Action: DecisionLock})
+
+		unlocked, err := d.UnlockForOwner(ctx, "session1", "user1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !unlocked {
+			t.Error("expected published code to unlock")
+		}
+	})
+
+	t.Run("someone else's work stays locked", func(t *testing.T) {
+		d, _ := lockedDetector(t, &mockValidator{}, Decision{Action: DecisionLock, MatchedOwnerID: "owner1", MatchedWorkID: "work1"})
+
+		unlocked, err := d.UnlockForOwner(ctx, "session1", "user1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if unlocked {
+			t.Error("expected lock to stay")
+		}
+
+		lock, _ := d.GetLock(ctx, "session1")
+		if !lock.Locked || lock.ExpiresIn <= 0 || lock.ExpiresIn > config.LockTTL {
+			t.Errorf("expected lock with remaining ttl, got %+v", lock)
+		}
+	})
+
+	t.Run("latest lock decision explains the lock", func(t *testing.T) {
+		d, store := lockedDetector(t, &mockValidator{}, Decision{Action: DecisionLock, Reason: "first"})
+		_ = store.RecordDecision(ctx, "session1", Decision{Action: DecisionLock, Reason: "second"}) //nolint:errcheck // memory store
+		_ = store.RecordDecision(ctx, "session1", Decision{Action: DecisionAllow})                  //nolint:errcheck // memory store
+
+		decision, err := d.LockDecision(ctx, "session1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decision == nil || decision.Reason != "second" {
+			t.Errorf("expected latest lock decision, got %+v", decision)
+		}
+	})
+}
+
 // helpers
 
 func generateLines(n int) string {
@@ -371,6 +546,7 @@ type mockValidator struct {
 	ownershipErr   error
 	publicMatch    *ContentMatch
 	publicErr      error
+	publishedMatch *ContentMatch
 }
 
 func (m *mockValidator) ValidateOwnership(_ context.Context, _, _ string) (*ContentMatch, error) {
@@ -393,5 +569,12 @@ func (m *mockValidator) ValidatePublicContent(_ context.Context, _ string) (*Con
 	return &ContentMatch{Found: false}, nil
 }
 
+func (m *mockValidator) ValidatePublishedOwnership(_ context.Context, _, _ string) (*ContentMatch, error) {
+	if m.publishedMatch != nil {
+		return m.publishedMatch, nil
+	}
+	return &ContentMatch{Found: false}, nil
+}
+
 // compile-time interface check
 var _ ContentValidator = (*mockValidator)(nil)
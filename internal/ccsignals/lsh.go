@@ -1,6 +1,8 @@
 package ccsignals
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"sync"
 )
 
@@ -22,8 +24,19 @@ type FingerprintRecord struct {
 	WorkID        string
 	CreatorID     string
 	CCSignal      CCSignal
-	Content       string
+	Content       string // empty for records loaded from persistence
+	ContentHash   string // see ContentHash
 	ContentLength int
+	LineCount     int
+
+	// fingerprints of the work's windows of lines, in windowRanges order,
+	// 0 for windows too short to fingerprint
+	Windows []Fingerprint
+
+	// set on window records, the lines of the work the window covers
+	// (0-based, end exclusive)
+	StartLine int
+	EndLine   int
 }
 
 // represents a fingerprint match
@@ -194,20 +207,46 @@ func (idx *LSHIndex) GetRecord(id string) *FingerprintRecord {
 	return idx.records[id]
 }
 
-// in-memory indexed fingerprint store (no persistence, computed from strudels at startup)
+// in-memory indexed fingerprint store. records can be persisted with a
+// PostgresFingerprintStore so the index doesn't have to be recomputed at startup
 type IndexedFingerprintStore struct {
-	index  *LSHIndex
-	hasher *SimHasher
+	index        *LSHIndex
+	windows      *LSHIndex // window records, see FindPartialMatches
+	hasher       *SimHasher
+	shingleSize  int
+	windowLines  int
+	windowStride int
+}
+
+// settings fingerprints are computed with. persisted fingerprints computed
+// with other settings have to be recomputed
+type IndexParams struct {
+	ShingleSize  int
+	WindowLines  int
+	WindowStride int
 }
 
 // creates a new in-memory indexed store
 func NewInMemoryIndexedStore(numBands, threshold, shingleSize int) *IndexedFingerprintStore {
+	hasher := NewSimHasher(shingleSize)
+
 	return &IndexedFingerprintStore{
-		index:  NewLSHIndex(numBands, threshold),
-		hasher: NewSimHasher(shingleSize),
+		index:        NewLSHIndex(numBands, threshold),
+		windows:      NewLSHIndex(numBands, threshold),
+		hasher:       hasher,
+		shingleSize:  hasher.shingleSize,
+		windowLines:  DefaultWindowLines,
+		windowStride: DefaultWindowStride,
 	}
 }
 
+// identifies content without keeping it: hex sha256 of the UTF-8 bytes,
+// matching encode(sha256(convert_to(code, 'UTF8')), 'hex') in postgres
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
 // adds a fingerprint record computed from a strudel
 func (s *IndexedFingerprintStore) AddFromStrudel(workID, creatorID string, ccSignal CCSignal, content string) {
 	fingerprint := s.hasher.Hash(content)
@@ -219,19 +258,58 @@ func (s *IndexedFingerprintStore) AddFromStrudel(workID, creatorID string, ccSig
 		CreatorID:     creatorID,
 		CCSignal:      ccSignal,
 		Content:       content,
+		ContentHash:   ContentHash(content),
 		ContentLength: len(content),
+		LineCount:     countLines(content),
+		Windows:       s.windowFingerprints(content),
 	}
 
+	s.Remove(workID)
 	s.index.Insert(record)
+	s.insertWindows(record)
+}
+
+// returns the record of a work, nil when it isn't indexed
+func (s *IndexedFingerprintStore) Record(workID string) *FingerprintRecord {
+	return s.index.GetRecord(workID)
+}
+
+// returns the IDs of every indexed work
+func (s *IndexedFingerprintStore) WorkIDs() []string {
+	s.index.mu.RLock()
+	defer s.index.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.index.records))
+	for id := range s.index.records {
+		ids = append(ids, id)
+	}
+
+	return ids
 }
 
-// inserts a pre-loaded record directly into the index
+// settings the fingerprints are computed with
+func (s *IndexedFingerprintStore) Params() IndexParams {
+	return IndexParams{
+		ShingleSize:  s.shingleSize,
+		WindowLines:  s.windowLines,
+		WindowStride: s.windowStride,
+	}
+}
+
+// inserts a pre-loaded record directly into the index, replacing any
+// record of the same work
 func (s *IndexedFingerprintStore) InsertRecord(record *FingerprintRecord) {
+	s.Remove(record.ID)
 	s.index.Insert(record)
+	s.insertWindows(record)
 }
 
-// removes a fingerprint by work ID
+// removes a fingerprint and its windows by work ID
 func (s *IndexedFingerprintStore) Remove(workID string) {
+	if record := s.index.GetRecord(workID); record != nil {
+		s.removeWindows(record)
+	}
+
 	s.index.Remove(workID)
 }
 
@@ -245,20 +323,17 @@ func (s *IndexedFingerprintStore) UpdateFromStrudel(workID, creatorID string, cc
 
 	if exists {
 		// skip if content identical
-		if existing.Content == content {
+		if existing.ContentHash == ContentHash(content) {
 			return false
 		}
 
 		// skip if line count unchanged (small edits within lines)
-		oldLines := countLines(existing.Content)
-		newLines := countLines(content)
-		if oldLines == newLines {
+		if existing.LineCount == countLines(content) {
 			return false
 		}
 	}
 
-	// significant change or new record - remove old and add new
-	s.Remove(workID)
+	// significant change or new record - AddFromStrudel replaces the old one
 	s.AddFromStrudel(workID, creatorID, ccSignal, content)
 	return true
 }
@@ -330,3 +330,52 @@ func TestIndexedFingerprintStore_InsertRecord(t *testing.T) {
 		t.Errorf("Size() = %d, want 2", indexed.Size())
 	}
 }
+
+func TestIndexedFingerprintStore_InsertRecordReplaces(t *testing.T) {
+	indexed := NewInMemoryIndexedStore(4, 10, 3)
+
+	indexed.InsertRecord(&FingerprintRecord{ID: "work1", WorkID: "work1", Fingerprint: 0xABCD})
+	indexed.InsertRecord(&FingerprintRecord{ID: "work1", WorkID: "work1", Fingerprint: 0xABCD})
+	indexed.Remove("work1")
+
+	if results := indexed.index.Query(0xABCD); len(results) != 0 {
+		t.Errorf("expected no stale bucket entries after removal, got %d results", len(results))
+	}
+}
+
+func TestIndexedFingerprintStore_UpdatePersistedRecord(t *testing.T) {
+	indexed := NewInMemoryIndexedStore(4, 10, 3)
+
+	content := "line one\nline two\nline three"
+	indexed.AddFromStrudel("work1", "creator1", SignalNoAI, content)
+
+	// records loaded from postgres carry no content, only its hash and line count
+	record := *indexed.Record("work1")
+	record.Content = ""
+	indexed.InsertRecord(&record)
+
+	if indexed.UpdateFromStrudel("work1", "creator1", SignalNoAI, content) {
+		t.Error("expected unchanged content to skip rehashing")
+	}
+
+	if indexed.UpdateFromStrudel("work1", "creator1", SignalNoAI, "line one\nline 2\nline three") {
+		t.Error("expected edits within lines to skip rehashing")
+	}
+
+	if !indexed.UpdateFromStrudel("work1", "creator1", SignalNoAI, content+"\nline four") {
+		t.Error("expected an added line to rehash")
+	}
+
+	if got := indexed.Record("work1"); got.LineCount != 4 || got.ContentHash != ContentHash(content+"\nline four") {
+		t.Errorf("expected record to describe the new content, got %+v", got)
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	// sha256 of "abc", as postgres computes it for the consistency check
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+
+	if got := ContentHash("abc"); got != want {
+		t.Errorf("ContentHash() = %s, want %s", got, want)
+	}
+}
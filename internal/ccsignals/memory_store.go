@@ -8,10 +8,11 @@ import (
 
 // MemoryLockStore implements LockStore using in-memory storage
 type MemoryLockStore struct {
-	mu     sync.RWMutex
-	locks  map[string]*memoryLock
-	done   chan struct{}
-	closed bool
+	mu        sync.RWMutex
+	locks     map[string]*memoryLock
+	decisions map[string][]Decision // newest first
+	done      chan struct{}
+	closed    bool
 }
 
 type memoryLock struct {
@@ -23,8 +24,9 @@ type memoryLock struct {
 // creates a new in-memory lock store
 func NewMemoryLockStore() *MemoryLockStore {
 	store := &MemoryLockStore{
-		locks: make(map[string]*memoryLock),
-		done:  make(chan struct{}),
+		locks:     make(map[string]*memoryLock),
+		decisions: make(map[string][]Decision),
+		done:      make(chan struct{}),
 	}
 
 	go store.cleanupLoop()
@@ -66,6 +68,7 @@ func (s *MemoryLockStore) GetLock(_ context.Context, sessionID string) (*LockSta
 		Locked:       true,
 		BaselineCode: lock.baseline,
 		LockedAt:     lock.lockedAt,
+		ExpiresIn:    time.Until(lock.expiresAt),
 	}, nil
 }
 
@@ -90,6 +93,33 @@ func (s *MemoryLockStore) RefreshTTL(_ context.Context, sessionID string, ttl ti
 	return nil
 }
 
+// prepends a decision to the session's log, keeping the latest maxDecisions
+func (s *MemoryLockStore) RecordDecision(_ context.Context, sessionID string, decision Decision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	decisions := append([]Decision{decision}, s.decisions[sessionID]...)
+	if len(decisions) > maxDecisions {
+		decisions = decisions[:maxDecisions]
+	}
+
+	s.decisions[sessionID] = decisions
+	return nil
+}
+
+// returns up to limit decisions for a session, newest first
+func (s *MemoryLockStore) RecentDecisions(_ context.Context, sessionID string, limit int) ([]Decision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	decisions := s.decisions[sessionID]
+	if len(decisions) > limit {
+		decisions = decisions[:limit]
+	}
+
+	return append([]Decision{}, decisions...), nil
+}
+
 // stops the cleanup goroutine
 func (s *MemoryLockStore) Close() error {
 	s.mu.Lock()
@@ -128,4 +158,10 @@ func (s *MemoryLockStore) cleanup() {
 			delete(s.locks, sessionID)
 		}
 	}
+
+	for sessionID, decisions := range s.decisions {
+		if len(decisions) > 0 && now.Sub(decisions[0].DecidedAt) > decisionTTL {
+			delete(s.decisions, sessionID)
+		}
+	}
 }
@@ -0,0 +1,164 @@
+package ccsignals
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// deletions are tombstoned so other instances can drop them from their
+	// index; tombstones older than this are purged
+	fingerprintTombstoneRetention = 24 * time.Hour
+
+	fingerprintColumns = `strudel_id, user_id, cc_signal, fingerprint, content_hash, content_length, line_count, shingle_size, window_fingerprints, window_lines, window_stride, deleted_at IS NOT NULL, updated_at`
+
+	queryUpsertFingerprint = `
+		INSERT INTO strudel_fingerprints (strudel_id, user_id, cc_signal, fingerprint, content_hash, content_length, line_count, shingle_size, window_fingerprints, window_lines, window_stride)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (strudel_id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			cc_signal = EXCLUDED.cc_signal,
+			fingerprint = EXCLUDED.fingerprint,
+			content_hash = EXCLUDED.content_hash,
+			content_length = EXCLUDED.content_length,
+			line_count = EXCLUDED.line_count,
+			shingle_size = EXCLUDED.shingle_size,
+			window_fingerprints = EXCLUDED.window_fingerprints,
+			window_lines = EXCLUDED.window_lines,
+			window_stride = EXCLUDED.window_stride,
+			deleted_at = NULL,
+			updated_at = NOW()
+	`
+
+	queryDeleteFingerprint = `
+		UPDATE strudel_fingerprints
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE strudel_id = $1 AND deleted_at IS NULL
+	`
+
+	// changes strictly after $1, live and tombstoned
+	queryFingerprintChanges = `
+		SELECT ` + fingerprintColumns + `
+		FROM strudel_fingerprints
+		WHERE updated_at > $1
+		ORDER BY updated_at
+	`
+
+	queryPurgeFingerprintTombstones = `
+		DELETE FROM strudel_fingerprints
+		WHERE deleted_at < NOW() - make_interval(secs => $1)
+	`
+)
+
+// persists fingerprint records in postgres so the LSH index can be loaded
+// at startup instead of recomputed, and kept in sync across instances
+type PostgresFingerprintStore struct {
+	db *pgxpool.Pool
+}
+
+// a persisted fingerprint, or the tombstone of a removed one
+type FingerprintChange struct {
+	Record    *FingerprintRecord
+	Params    IndexParams // settings the record was computed with
+	Deleted   bool
+	UpdatedAt time.Time
+}
+
+// creates a postgres-backed fingerprint store
+func NewPostgresFingerprintStore(db *pgxpool.Pool) *PostgresFingerprintStore {
+	return &PostgresFingerprintStore{db: db}
+}
+
+// stores a record computed with the given settings
+func (s *PostgresFingerprintStore) Save(ctx context.Context, record *FingerprintRecord, params IndexParams) error {
+	windows := make([]int64, len(record.Windows))
+	for i, fingerprint := range record.Windows {
+		windows[i] = int64(fingerprint) //nolint:gosec // stored bit for bit in a BIGINT
+	}
+
+	_, err := s.db.Exec(ctx, queryUpsertFingerprint,
+		record.WorkID,
+		record.CreatorID,
+		string(record.CCSignal),
+		int64(record.Fingerprint), //nolint:gosec // stored bit for bit in a BIGINT
+		record.ContentHash,
+		record.ContentLength,
+		record.LineCount,
+		params.ShingleSize,
+		windows,
+		params.WindowLines,
+		params.WindowStride,
+	)
+
+	return err
+}
+
+// tombstones the record of a work
+func (s *PostgresFingerprintStore) Delete(ctx context.Context, workID string) error {
+	_, err := s.db.Exec(ctx, queryDeleteFingerprint, workID)
+	return err
+}
+
+// returns records and tombstones changed after since, oldest first. the
+// zero time returns everything
+func (s *PostgresFingerprintStore) Changes(ctx context.Context, since time.Time) ([]FingerprintChange, error) {
+	rows, err := s.db.Query(ctx, queryFingerprintChanges, since)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var changes []FingerprintChange
+
+	for rows.Next() {
+		var record FingerprintRecord
+		var change FingerprintChange
+		var ccSignal string
+		var fingerprint int64
+		var windows []int64
+
+		err := rows.Scan(
+			&record.WorkID,
+			&record.CreatorID,
+			&ccSignal,
+			&fingerprint,
+			&record.ContentHash,
+			&record.ContentLength,
+			&record.LineCount,
+			&change.Params.ShingleSize,
+			&windows,
+			&change.Params.WindowLines,
+			&change.Params.WindowStride,
+			&change.Deleted,
+			&change.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		record.ID = record.WorkID
+		record.CCSignal = CCSignal(ccSignal)
+		record.Fingerprint = Fingerprint(fingerprint) //nolint:gosec // stored bit for bit in a BIGINT
+		for _, window := range windows {
+			record.Windows = append(record.Windows, Fingerprint(window)) //nolint:gosec // stored bit for bit in a BIGINT
+		}
+
+		change.Record = &record
+
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// deletes tombstones every instance has had time to apply
+func (s *PostgresFingerprintStore) PurgeTombstones(ctx context.Context) (int64, error) {
+	tag, err := s.db.Exec(ctx, queryPurgeFingerprintTombstones, fingerprintTombstoneRetention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -2,6 +2,7 @@ package ccsignals
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -12,15 +13,16 @@ import (
 const (
 	keyPasteLock     = "ccsignals:paste_lock:%s"
 	keyPasteBaseline = "ccsignals:paste_baseline:%s"
+	keyDecisions     = "ccsignals:decisions:%s"
 )
 
 // implements LockStore using Redis
 type RedisLockStore struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 // creates a new Redis-backed lock store
-func NewRedisLockStore(client *redis.Client) *RedisLockStore {
+func NewRedisLockStore(client redis.UniversalClient) *RedisLockStore {
 	return &RedisLockStore{client: client}
 }
 
@@ -65,6 +67,7 @@ func (s *RedisLockStore) GetLock(ctx context.Context, sessionID string) (*LockSt
 	pipe := s.client.Pipeline()
 	lockCmd := pipe.Get(ctx, lockKey)
 	baselineCmd := pipe.Get(ctx, baselineKey)
+	ttlCmd := pipe.PTTL(ctx, lockKey)
 
 	_, err := pipe.Exec(ctx)
 	if err != nil && !errors.Is(err, redis.Nil) {
@@ -106,9 +109,16 @@ func (s *RedisLockStore) GetLock(ctx context.Context, sessionID string) (*LockSt
 		return &LockState{Locked: false}, nil
 	}
 
+	// negative when the key has no expiry, which SetLock never does
+	ttl := ttlCmd.Val()
+	if ttl < 0 {
+		ttl = 0
+	}
+
 	return &LockState{
 		Locked:       true,
 		BaselineCode: baseline,
+		ExpiresIn:    ttl,
 	}, nil
 }
 
@@ -117,7 +127,13 @@ func (s *RedisLockStore) RemoveLock(ctx context.Context, sessionID string) error
 	lockKey := fmt.Sprintf(keyPasteLock, sessionID)
 	baselineKey := fmt.Sprintf(keyPasteBaseline, sessionID)
 
-	return s.client.Del(ctx, lockKey, baselineKey).Err()
+	// separate deletes, the keys can be in different cluster slots
+	pipe := s.client.Pipeline()
+	pipe.Del(ctx, lockKey)
+	pipe.Del(ctx, baselineKey)
+
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
 // extends the lock TTL without changing other state
@@ -133,6 +149,44 @@ func (s *RedisLockStore) RefreshTTL(ctx context.Context, sessionID string, ttl t
 	return err
 }
 
+// prepends a decision to the session's log, keeping the latest maxDecisions
+func (s *RedisLockStore) RecordDecision(ctx context.Context, sessionID string, decision Decision) error {
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf(keyDecisions, sessionID)
+
+	pipe := s.client.Pipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, maxDecisions-1)
+	pipe.Expire(ctx, key, decisionTTL)
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// returns up to limit decisions for a session, newest first
+func (s *RedisLockStore) RecentDecisions(ctx context.Context, sessionID string, limit int) ([]Decision, error) {
+	entries, err := s.client.LRange(ctx, fmt.Sprintf(keyDecisions, sessionID), 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := make([]Decision, 0, len(entries))
+	for _, entry := range entries {
+		var decision Decision
+		if err := json.Unmarshal([]byte(entry), &decision); err != nil {
+			return nil, fmt.Errorf("failed to decode decision: %w", err)
+		}
+
+		decisions = append(decisions, decision)
+	}
+
+	return decisions, nil
+}
+
 // closes the redis connection
 func (s *RedisLockStore) Close() error {
 	return s.client.Close()
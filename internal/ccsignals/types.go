@@ -64,9 +64,37 @@ type LockState struct {
 	Locked       bool
 	BaselineCode string
 	LockedAt     time.Time
+	ExpiresIn    time.Duration // until the lock lapses, unless it is refreshed by further edits
 	Reason       string
 }
 
+// paste detection outcomes kept in the decision log
+const (
+	DecisionLock   = "lock"
+	DecisionAllow  = "allow"
+	DecisionUnlock = "unlock"
+)
+
+// how many decisions are kept per session, and for how long
+const (
+	maxDecisions = 50
+	decisionTTL  = 24 * time.Hour
+)
+
+// a paste detection outcome, recorded so moderators can see why a session was locked
+type Decision struct {
+	Action         string    `json:"action"`
+	Reason         string    `json:"reason"`
+	UserID         string    `json:"user_id,omitempty"`
+	MatchedOwnerID string    `json:"matched_owner_id,omitempty"`
+	MatchedWorkID  string    `json:"matched_work_id,omitempty"` // fingerprint matches only
+	MatchedLines   string    `json:"matched_lines,omitempty"`   // partial matches only, lines of the matched work
+	Coverage       float64   `json:"coverage,omitempty"`        // partial matches only, share of the matched work pasted
+	CCSignal       CCSignal  `json:"cc_signal,omitempty"`
+	CodeLength     int       `json:"code_length"`
+	DecidedAt      time.Time `json:"decided_at"`
+}
+
 // represents a match result from content validation
 type ContentMatch struct {
 	Found    bool
@@ -83,10 +111,17 @@ type LockStore interface {
 	RefreshTTL(ctx context.Context, sessionID string, ttl time.Duration) error
 }
 
+// defines the interface for keeping recent decisions per session
+type DecisionLog interface {
+	RecordDecision(ctx context.Context, sessionID string, decision Decision) error
+	RecentDecisions(ctx context.Context, sessionID string, limit int) ([]Decision, error)
+}
+
 // defines the interface for validating content ownership
 type ContentValidator interface {
 	ValidateOwnership(ctx context.Context, userID, code string) (*ContentMatch, error)
 	ValidatePublicContent(ctx context.Context, code string) (*ContentMatch, error)
+	ValidatePublishedOwnership(ctx context.Context, userID, code string) (*ContentMatch, error)
 }
 
 // defines the interface for session management
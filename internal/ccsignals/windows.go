@@ -0,0 +1,217 @@
+package ccsignals
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// protected works are also fingerprinted in overlapping windows of lines,
+	// so pasting a section of one is caught even when the whole document's
+	// fingerprint is far off
+	DefaultWindowLines  = 8
+	DefaultWindowStride = 4
+
+	// windows with less text are too generic to fingerprint
+	minWindowChars = 60
+)
+
+// line range of a window, 0-based with end exclusive
+type lineRange struct {
+	start, end int
+}
+
+// a section of a protected work that pasted code matched
+type PartialMatch struct {
+	WorkID    string
+	CreatorID string
+	CCSignal  CCSignal
+	StartLine int     // first matched line of the protected work, 1-based
+	EndLine   int     // last matched line, inclusive
+	Coverage  float64 // share of the protected work's lines the paste matched
+	Windows   int     // matched windows
+}
+
+// describes the matched lines, e.g. "12-27"
+func (m *PartialMatch) Lines() string {
+	return fmt.Sprintf("%d-%d", m.StartLine, m.EndLine)
+}
+
+// windows of lines lines each, stride lines apart, over a text of
+// lineCount lines. the last window always ends at the last line. texts that
+// fit in one window get a single window
+func windowRanges(lineCount, lines, stride int) []lineRange {
+	if lineCount <= lines {
+		return []lineRange{{0, lineCount}}
+	}
+
+	var ranges []lineRange
+	start := 0
+
+	for ; start+lines < lineCount; start += stride {
+		ranges = append(ranges, lineRange{start, start + lines})
+	}
+
+	return append(ranges, lineRange{lineCount - lines, lineCount})
+}
+
+// fingerprints each window of content, in windowRanges order. windows too
+// short to fingerprint get 0
+func (s *IndexedFingerprintStore) windowFingerprints(content string) []Fingerprint {
+	lines := strings.Split(content, "\n")
+	ranges := windowRanges(len(lines), s.windowLines, s.windowStride)
+
+	fingerprints := make([]Fingerprint, len(ranges))
+	for i, r := range ranges {
+		text := strings.Join(lines[r.start:r.end], "\n")
+		if len(strings.TrimSpace(text)) >= minWindowChars {
+			fingerprints[i] = s.hasher.Hash(text)
+		}
+	}
+
+	return fingerprints
+}
+
+// adds the window records of a work to the window index
+func (s *IndexedFingerprintStore) insertWindows(record *FingerprintRecord) {
+	ranges := windowRanges(record.LineCount, s.windowLines, s.windowStride)
+	if len(ranges) != len(record.Windows) {
+		// computed with other window settings, can't be placed
+		return
+	}
+
+	for i, fingerprint := range record.Windows {
+		if fingerprint == 0 {
+			continue
+		}
+
+		s.windows.Insert(&FingerprintRecord{
+			ID:          windowID(record.WorkID, i),
+			Fingerprint: fingerprint,
+			WorkID:      record.WorkID,
+			CreatorID:   record.CreatorID,
+			CCSignal:    record.CCSignal,
+			StartLine:   ranges[i].start,
+			EndLine:     ranges[i].end,
+		})
+	}
+}
+
+// removes the window records of a work
+func (s *IndexedFingerprintStore) removeWindows(record *FingerprintRecord) {
+	for i := range record.Windows {
+		s.windows.Remove(windowID(record.WorkID, i))
+	}
+}
+
+func windowID(workID string, i int) string {
+	return fmt.Sprintf("%s#%d", workID, i)
+}
+
+// finds protected works that sections of pasted matched, best coverage first
+func (s *IndexedFingerprintStore) FindPartialMatches(pasted string) []*PartialMatch {
+	type hit struct {
+		record  *FingerprintRecord
+		covered map[int]bool // matched lines of the work
+		windows int
+	}
+
+	hits := make(map[string]*hit)
+
+	for _, fingerprint := range s.windowFingerprints(pasted) {
+		if fingerprint == 0 {
+			continue
+		}
+
+		for _, match := range s.windows.Query(fingerprint) {
+			window := match.Record
+
+			h := hits[window.WorkID]
+			if h == nil {
+				h = &hit{record: window, covered: make(map[int]bool)}
+				hits[window.WorkID] = h
+			}
+
+			h.windows++
+			for line := window.StartLine; line < window.EndLine; line++ {
+				h.covered[line] = true
+			}
+		}
+	}
+
+	matches := make([]*PartialMatch, 0, len(hits))
+	for workID, h := range hits {
+		work := s.index.GetRecord(workID)
+		if work == nil || work.LineCount == 0 {
+			continue
+		}
+
+		first, last := work.LineCount, 0
+		for line := range h.covered {
+			first, last = min(first, line), max(last, line)
+		}
+
+		matches = append(matches, &PartialMatch{
+			WorkID:    workID,
+			CreatorID: h.record.CreatorID,
+			CCSignal:  h.record.CCSignal,
+			StartLine: first + 1,
+			EndLine:   last + 1,
+			Coverage:  float64(len(h.covered)) / float64(work.LineCount),
+			Windows:   h.windows,
+		})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Coverage != matches[j].Coverage {
+			return matches[i].Coverage > matches[j].Coverage
+		}
+		return matches[i].WorkID < matches[j].WorkID
+	})
+
+	return matches
+}
+
+// finds the protected work the paste covers most, nil when no section matched
+func (s *IndexedFingerprintStore) FindBestPartialMatch(pasted string) *PartialMatch {
+	matches := s.FindPartialMatches(pasted)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	return matches[0]
+}
+
+// the text inserted between previous and current, widened to whole lines
+func pastedSection(previous, current string) string {
+	prefix := 0
+	for prefix < len(previous) && prefix < len(current) && previous[prefix] == current[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(previous)-prefix && suffix < len(current)-prefix &&
+		previous[len(previous)-1-suffix] == current[len(current)-1-suffix] {
+		suffix++
+	}
+
+	start, end := prefix, len(current)-suffix
+
+	// an insert that starts or ends with a line break covers only its own lines
+	if start < end && current[start] == '\n' {
+		start++
+	}
+	if start < end && current[end-1] == '\n' {
+		end--
+	}
+
+	start = strings.LastIndexByte(current[:start], '\n') + 1
+	if i := strings.IndexByte(current[end:], '\n'); i >= 0 {
+		end += i
+	} else {
+		end = len(current)
+	}
+
+	return current[start:end]
+}
@@ -0,0 +1,156 @@
+package ccsignals
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// a protected work of distinct strudel lines
+func windowedWork(lines int) []string {
+	sounds := []string{"bd", "sd", "hh", "cp", "rim", "oh", "lt", "mt", "ht", "cr", "rd", "cb", "sh", "tb", "perc", "misc"}
+	banks := []string{"RolandTR909", "RolandTR808", "LinnDrum", "AkaiMPC60", "KorgMinipops", "BossDR110"}
+	effects := []string{"room", "delay", "lpf", "hpf", "crush", "coarse", "shape", "vowel"}
+
+	work := make([]string, lines)
+	for i := range work {
+		work[i] = fmt.Sprintf(`$: s("%s*%d ~ %s").bank("%s").%s(%d).gain(.%d) // %s %s`,
+			sounds[i%len(sounds)], i%5+2, sounds[(i*7+3)%len(sounds)], banks[i%len(banks)],
+			effects[(i*3)%len(effects)], (i*37)%900+100, i%9+1, effects[i%len(effects)], sounds[(i*5+1)%len(sounds)])
+	}
+
+	return work
+}
+
+func TestWindowRanges(t *testing.T) {
+	tests := []struct {
+		lineCount int
+		want      []lineRange
+	}{
+		{5, []lineRange{{0, 5}}},
+		{8, []lineRange{{0, 8}}},
+		{14, []lineRange{{0, 8}, {4, 12}, {6, 14}}},
+		{16, []lineRange{{0, 8}, {4, 12}, {8, 16}}},
+	}
+
+	for _, tt := range tests {
+		got := windowRanges(tt.lineCount, 8, 4)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("windowRanges(%d) = %v, want %v", tt.lineCount, got, tt.want)
+		}
+	}
+}
+
+func TestPastedSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		current  string
+		want     string
+	}{
+		{"empty editor", "", "a\nb", "a\nb"},
+		{"appended lines", "a\nb", "a\nb\nc\nd", "c\nd"},
+		{"inserted lines", "a\nd", "a\nb\nc\nd", "b\nc"},
+		{"mid-line insert", "one two\nthree", "one pasted two\nthree", "one pasted two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pastedSection(tt.previous, tt.current); got != tt.want {
+				t.Errorf("pastedSection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexedFingerprintStore_FindPartialMatches(t *testing.T) {
+	indexed := NewInMemoryIndexedStore(4, 10, 3)
+
+	work := windowedWork(32)
+	indexed.AddFromStrudel("work1", "creator1", SignalNoAI, strings.Join(work, "\n"))
+
+	t.Run("pasted section", func(t *testing.T) {
+		match := indexed.FindBestPartialMatch(strings.Join(work[8:24], "\n"))
+		if match == nil {
+			t.Fatal("expected the pasted section to match")
+		}
+
+		if match.WorkID != "work1" || match.CreatorID != "creator1" || match.CCSignal != SignalNoAI {
+			t.Errorf("unexpected match: %+v", match)
+		}
+
+		if match.StartLine > 9 || match.EndLine < 24 {
+			t.Errorf("expected lines 9-24 to be covered, got %s", match.Lines())
+		}
+
+		if match.Coverage < 0.5 || match.Coverage > 0.75 {
+			t.Errorf("expected about half the work covered, got %.2f", match.Coverage)
+		}
+	})
+
+	t.Run("unrelated code", func(t *testing.T) {
+		pasted := strings.Repeat(`note("c3 e3 g3 b3").s("sawtooth").lpf(sine.range(300, 2000).slow(8))`+"\n", 10)
+		if match := indexed.FindBestPartialMatch(pasted); match != nil {
+			t.Errorf("expected no match, got %+v", match)
+		}
+	})
+
+	t.Run("removed work", func(t *testing.T) {
+		indexed.Remove("work1")
+
+		if match := indexed.FindBestPartialMatch(strings.Join(work[8:24], "\n")); match != nil {
+			t.Errorf("expected windows to be removed with the work, got %+v", match)
+		}
+	})
+}
+
+func TestDetector_PartialPaste(t *testing.T) {
+	ctx := context.Background()
+
+	work := windowedWork(32)
+	ownCode := `setcps(0.6)` + "\n" + `$: note("<c3 a2 f2 g2>").s("gm_acoustic_bass")`
+
+	t.Run("section of no-ai work locks", func(t *testing.T) {
+		indexed := NewInMemoryIndexedStore(4, 10, 3)
+		indexed.AddFromStrudel("work1", "creator1", SignalNoAI, strings.Join(work, "\n"))
+
+		store := NewMemoryLockStore()
+		defer func() { _ = store.Close() }() //nolint:errcheck // test cleanup
+
+		d := NewDetector(DefaultConfig(), store, nil).WithFingerprints(indexed).WithDecisionLog(store)
+
+		newCode := ownCode + "\n" + strings.Join(work[4:20], "\n")
+		result, err := d.DetectPaste(ctx, "session1", "user1", ownCode, newCode)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !result.ShouldLock || result.PartialMatch == nil {
+			t.Fatalf("expected a partial match lock, got %+v", result)
+		}
+
+		decisions, _ := d.RecentDecisions(ctx, "session1", 1)
+		if len(decisions) != 1 || decisions[0].MatchedWorkID != "work1" || decisions[0].Coverage == 0 || decisions[0].MatchedLines == "" {
+			t.Errorf("expected the partial match to be logged, got %+v", decisions)
+		}
+	})
+
+	t.Run("section of open work is allowed", func(t *testing.T) {
+		indexed := NewInMemoryIndexedStore(4, 10, 3)
+		indexed.AddFromStrudel("work1", "creator1", SignalCredit, strings.Join(work, "\n"))
+
+		d := NewDetector(DefaultConfig(), nil, nil).WithFingerprints(indexed)
+
+		newCode := ownCode + "\n" + strings.Join(work[4:20], "\n")
+		result, err := d.DetectPaste(ctx, "session1", "user1", ownCode, newCode)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.ShouldLock || result.PartialMatch == nil {
+			t.Errorf("expected the paste to be allowed on a partial match, got %+v", result)
+		}
+	})
+}
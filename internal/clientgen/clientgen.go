@@ -0,0 +1,403 @@
+// Package clientgen generates the typed Go and TypeScript API clients from
+// the Swagger 2.0 spec swag writes to docs/openapi. see make client
+package clientgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// where the clients are generated to, relative to the repository
+const (
+	GoPackage    = "algopatterns"
+	GoDir        = "clients/go/" + GoPackage
+	TypeScriptTo = "clients/typescript/src/index.ts"
+)
+
+const definitionPrefix = "#/definitions/"
+
+// methods in the order endpoints of a path are generated in
+var methods = []string{"get", "post", "put", "patch", "delete"}
+
+// names the generated code defines itself, so definitions can't take them
+var builtinNames = map[string]bool{
+	"Client": true, "NewClient": true, "APIError": true, "DefaultBaseURL": true,
+	"Ptr": true, "ClientOptions": true, "ApiError": true, "DEFAULT_BASE_URL": true,
+}
+
+// reads the spec at path
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path given by the developer
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+
+	return &spec, nil
+}
+
+// generates both clients, returning the files by their path in the repository
+func Generate(spec *Spec) (map[string][]byte, error) {
+	api, err := Build(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	types, client, err := api.Go(GoPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		GoDir + "/types.go":  types,
+		GoDir + "/client.go": client,
+		TypeScriptTo:         api.TypeScript(),
+	}, nil
+}
+
+// names the spec's definitions and operations for the generators
+func Build(spec *Spec) (*API, error) {
+	api := &API{BaseURL: "https://" + spec.Host + strings.TrimSuffix(spec.BasePath, "/")}
+	if spec.Host == "" {
+		api.BaseURL = ""
+	}
+
+	typeNames, err := nameDefinitions(spec.Definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make([]string, 0, len(spec.Definitions))
+	for name := range spec.Definitions {
+		definitions = append(definitions, name)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return typeNames[definitions[i]] < typeNames[definitions[j]]
+	})
+
+	for _, definition := range definitions {
+		api.Types = append(api.Types, buildType(typeNames[definition], spec.Definitions[definition]))
+	}
+
+	// references are resolved to the generated names up front, so the
+	// generators only look names up
+	resolve := func(s *Schema) {
+		walk(s, func(s *Schema) {
+			if name, ok := strings.CutPrefix(s.Ref, definitionPrefix); ok {
+				s.Ref = typeNames[name]
+			}
+		})
+	}
+	for _, t := range api.Types {
+		for _, f := range t.Fields {
+			resolve(f.Schema)
+		}
+	}
+
+	taken := map[string]bool{}
+	for _, name := range typeNames {
+		taken[name] = true
+	}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ops []*Operation
+	for _, path := range paths {
+		for _, method := range methods {
+			op, ok := spec.Paths[path][method]
+			if !ok {
+				continue
+			}
+
+			endpoint, ok := buildEndpoint(method, path, op)
+			if !ok {
+				continue
+			}
+
+			api.Endpoints = append(api.Endpoints, endpoint)
+			ops = append(ops, op)
+		}
+	}
+
+	// routes documented together share a summary
+	summaries := map[string]int{}
+	for _, op := range ops {
+		summaries[op.Summary]++
+	}
+
+	endpointNames := map[string]bool{}
+	for i, endpoint := range api.Endpoints {
+		endpoint.Name = uniqueEndpointName(endpoint, ops[i], summaries[ops[i].Summary] > 1, endpointNames)
+		if taken[endpoint.Name+"Params"] {
+			return nil, fmt.Errorf("%s %s: %sParams is already a type", endpoint.Method, endpoint.Path, endpoint.Name)
+		}
+
+		for _, param := range endpoint.params() {
+			resolve(param.Schema)
+		}
+		resolve(endpoint.Body)
+		resolve(endpoint.Result)
+	}
+
+	return api, nil
+}
+
+// names each definition after its Go type. where packages define types of
+// the same name, they are prefixed with as much of their package path as
+// tells them apart
+func nameDefinitions(definitions map[string]*Schema) (map[string]string, error) {
+	byShortName := map[string][]string{}
+	for name := range definitions {
+		short := shortName(name)
+		byShortName[short] = append(byShortName[short], name)
+	}
+
+	names := map[string]string{}
+	used := map[string]string{}
+	for short, group := range byShortName {
+		depth := 0
+		if len(group) > 1 || builtinNames[goName(short)] {
+			depth = 1
+		}
+
+		for ; depth <= 10; depth++ {
+			named := map[string]bool{}
+			for _, definition := range group {
+				named[goName(packagePath(definition, depth)+" "+short)] = true
+			}
+			if len(named) == len(group) {
+				break
+			}
+		}
+
+		for _, definition := range group {
+			name := goName(packagePath(definition, depth) + " " + short)
+			if other, ok := used[name]; ok {
+				return nil, fmt.Errorf("definitions %s and %s are both named %s", other, definition, name)
+			}
+
+			used[name] = definition
+			names[definition] = name
+		}
+	}
+
+	return names, nil
+}
+
+func buildType(name string, s *Schema) *Type {
+	t := &Type{Name: name, Doc: s.Description}
+
+	if s.Type == "string" && len(s.Enum) > 0 {
+		for i, value := range s.Enum {
+			t.Enum = append(t.Enum, fmt.Sprint(value))
+
+			constant := name + goName(fmt.Sprint(value))
+			if i < len(s.EnumNames) {
+				constant = goName(s.EnumNames[i])
+			}
+			t.EnumNames = append(t.EnumNames, constant)
+		}
+
+		return t
+	}
+
+	properties := make([]string, 0, len(s.Properties))
+	for property := range s.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	fields := map[string]bool{}
+	for _, property := range properties {
+		field := goName(property)
+		for i := 2; fields[field]; i++ {
+			field = goName(property) + strconv.Itoa(i)
+		}
+		fields[field] = true
+
+		schema := s.Properties[property]
+		t.Fields = append(t.Fields, &Field{
+			Name:     field,
+			JSON:     property,
+			Doc:      schema.Description,
+			Schema:   schema,
+			Required: slices.Contains(s.Required, property),
+		})
+	}
+
+	return t
+}
+
+// builds the endpoint of an operation. WebSocket upgrades can't be called
+// over HTTP and are left out
+func buildEndpoint(method, path string, op *Operation) (*Endpoint, bool) {
+	endpoint := &Endpoint{
+		Method: strings.ToUpper(method),
+		Path:   path,
+		Doc:    op.Description,
+	}
+	if endpoint.Doc == "" {
+		endpoint.Doc = op.Summary
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	if slices.Contains(codes, "101") {
+		return nil, false
+	}
+
+	var success *Response
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			success = op.Responses[code]
+			break
+		}
+	}
+
+	jsonResponse := len(op.Produces) == 0 || slices.Contains(op.Produces, "application/json")
+	switch {
+	case success == nil || !jsonResponse:
+		endpoint.Raw = true
+	case success.Schema != nil && success.Schema.Type == "string":
+		endpoint.Raw = true
+	case success.Schema != nil:
+		endpoint.Result = unwrap(success.Schema)
+	}
+
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "body":
+			endpoint.Body = unwrap(p.Schema)
+			endpoint.BodyRequired = p.Required
+		case "path", "query", "header":
+			param := &Param{
+				Name:     p.Name,
+				Doc:      p.Description,
+				Schema:   &Schema{Type: p.Type, Format: p.Format, Items: p.Items},
+				Required: p.Required || p.In == "path",
+			}
+
+			switch p.In {
+			case "path":
+				param.Field = argName(p.Name)
+				endpoint.PathParams = append(endpoint.PathParams, param)
+			case "query":
+				param.Field = goName(p.Name)
+				endpoint.Query = append(endpoint.Query, param)
+			case "header":
+				param.Field = goName(p.Name)
+				endpoint.Headers = append(endpoint.Headers, param)
+			}
+		}
+	}
+
+	// in the order they appear in the path
+	sort.SliceStable(endpoint.PathParams, func(i, j int) bool {
+		return strings.Index(path, "{"+endpoint.PathParams[i].Name+"}") < strings.Index(path, "{"+endpoint.PathParams[j].Name+"}")
+	})
+
+	return endpoint, true
+}
+
+// names the endpoint after its summary. endpoints sharing a summary are told
+// apart by the end of their path
+func uniqueEndpointName(endpoint *Endpoint, op *Operation, shared bool, used map[string]bool) string {
+	base := goName(op.Summary)
+	if op.Summary == "" {
+		base = goName(endpoint.Method + " " + endpoint.Path)
+	}
+
+	if shared {
+		segments := strings.Split(strings.Trim(endpoint.Path, "/"), "/")
+		for i := len(segments) - 1; i >= 0; i-- {
+			if !strings.HasPrefix(segments[i], "{") {
+				base += goName(segments[i])
+				break
+			}
+		}
+	}
+
+	name := base
+	for i := 2; used[name] || builtinNames[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	used[name] = true
+
+	return name
+}
+
+// every parameter but the body
+func (e *Endpoint) params() []*Param {
+	return append(append(append([]*Param{}, e.PathParams...), e.Query...), e.Headers...)
+}
+
+// reports whether the endpoint takes a parameters struct, and whether it
+// must be given because a parameter is required
+func (e *Endpoint) hasParams() (has, required bool) {
+	for _, p := range append(slices.Clone(e.Query), e.Headers...) {
+		has = true
+		required = required || p.Required
+	}
+
+	return has, required
+}
+
+// a composite schema such as Response{data=Item} is typed as its base
+func unwrap(s *Schema) *Schema {
+	if s != nil && s.Ref == "" && len(s.AllOf) > 0 {
+		return s.AllOf[0]
+	}
+
+	return s
+}
+
+// calls fn for s and every schema inside it
+func walk(s *Schema, fn func(*Schema)) {
+	if s == nil {
+		return
+	}
+
+	fn(s)
+	walk(s.Items, fn)
+	for _, sub := range s.AllOf {
+		walk(sub, fn)
+	}
+	for _, sub := range s.Properties {
+		walk(sub, fn)
+	}
+	walk(additionalProperties(s), fn)
+}
+
+// the schema of a map's values, nil when s is no map
+func additionalProperties(s *Schema) *Schema {
+	if len(s.AdditionalProperties) == 0 {
+		return nil
+	}
+
+	if s.parsedAdditional == nil {
+		var values Schema
+		if err := json.Unmarshal(s.AdditionalProperties, &values); err != nil {
+			return nil // true or false
+		}
+		s.parsedAdditional = &values
+	}
+
+	return s.parsedAdditional
+}
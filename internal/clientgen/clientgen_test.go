@@ -0,0 +1,299 @@
+package clientgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSpec = `{
+  "host": "api.example.com",
+  "paths": {
+    "/api/v1/strudels/{id}": {
+      "get": {
+        "summary": "Get strudel by ID",
+        "description": "Get a strudel",
+        "produces": ["application/json"],
+        "parameters": [
+          {"type": "string", "name": "id", "in": "path", "required": true},
+          {"type": "string", "name": "If-None-Match", "in": "header"}
+        ],
+        "responses": {"200": {"schema": {"$ref": "#/definitions/api_rest_strudels.Strudel"}}}
+      },
+      "delete": {
+        "summary": "Delete strudel",
+        "parameters": [{"type": "string", "name": "id", "in": "path", "required": true}],
+        "responses": {"200": {"schema": {"$ref": "#/definitions/api_rest_strudels.MessageResponse"}}}
+      }
+    },
+    "/api/v1/moderation/{id}/approve": {
+      "post": {
+        "summary": "Resolve item",
+        "parameters": [{"type": "integer", "name": "id", "in": "path", "required": true}],
+        "responses": {"204": {"description": "No Content"}}
+      }
+    },
+    "/api/v1/moderation/{id}/remove": {
+      "post": {
+        "summary": "Resolve item",
+        "parameters": [{"type": "integer", "name": "id", "in": "path", "required": true}],
+        "responses": {"204": {"description": "No Content"}}
+      }
+    },
+    "/api/v1/strudels": {
+      "get": {
+        "summary": "List user's strudels",
+        "parameters": [
+          {"type": "integer", "name": "limit", "in": "query"},
+          {"type": "array", "items": {"type": "string"}, "name": "tags", "in": "query"}
+        ],
+        "responses": {"200": {"schema": {"$ref": "#/definitions/api_rest_strudels.Strudel"}}}
+      },
+      "post": {
+        "summary": "Create strudel",
+        "parameters": [{"name": "request", "in": "body", "required": true, "schema": {"$ref": "#/definitions/api_rest_strudels.CreateRequest"}}],
+        "responses": {"201": {"schema": {"$ref": "#/definitions/api_rest_strudels.Strudel"}}}
+      }
+    },
+    "/api/v1/feed.ics": {
+      "get": {
+        "summary": "Calendar feed",
+        "produces": ["text/calendar"],
+        "responses": {"200": {"schema": {"type": "string"}}}
+      }
+    },
+    "/api/v1/ws": {
+      "get": {
+        "summary": "Connect",
+        "responses": {"101": {"description": "Switching Protocols"}}
+      }
+    }
+  },
+  "definitions": {
+    "api_rest_strudels.Strudel": {
+      "type": "object",
+      "required": ["id"],
+      "properties": {
+        "id": {"type": "string"},
+        "tags": {"type": "array", "items": {"type": "string"}},
+        "signal": {"$ref": "#/definitions/internal_ccsignals.CCSignal"},
+        "stats": {"description": "play counts", "allOf": [{"$ref": "#/definitions/api_rest_strudels.Stats"}]},
+        "extra": {"type": "object", "additionalProperties": {"type": "integer"}}
+      }
+    },
+    "api_rest_strudels.Stats": {"type": "object", "properties": {"plays": {"type": "integer"}}},
+    "api_rest_strudels.CreateRequest": {
+      "type": "object",
+      "required": ["title"],
+      "properties": {"title": {"type": "string"}, "is_public": {"type": "boolean"}}
+    },
+    "api_rest_strudels.MessageResponse": {"type": "object", "properties": {"message": {"type": "string"}}},
+    "api_rest_admin.MessageResponse": {"type": "object", "properties": {"message": {"type": "string"}}},
+    "api_rest_agent.Message": {"type": "object", "properties": {"role": {"type": "string"}}},
+    "internal_agent.Message": {"type": "object", "properties": {"content": {"type": "string"}}},
+    "internal_ccsignals.CCSignal": {
+      "type": "string",
+      "enum": ["cc-cr", "no-ai"],
+      "x-enum-varnames": ["SignalCredit", "SignalNoAI"]
+    }
+  }
+}`
+
+func buildTestAPI(t *testing.T) *API {
+	t.Helper()
+
+	var spec Spec
+	if err := json.Unmarshal([]byte(testSpec), &spec); err != nil {
+		t.Fatalf("failed to parse test spec: %v", err)
+	}
+
+	api, err := Build(&spec)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return api
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"user_id":                "UserID",
+		"If-None-Match":          "IfNoneMatch",
+		"List user's strudels":   "ListUsersStrudels",
+		"Stream generate (SSE)":  "StreamGenerateSSE",
+		"ai_features_enabled":    "AIFeaturesEnabled",
+		"strudel_ids":            "StrudelIDs",
+		"HTTPServer":             "HTTPServer",
+		"codeUpdatesPerSecond":   "CodeUpdatesPerSecond",
+		"404":                    "X404",
+		"Get any strudel by ID ": "GetAnyStrudelByID",
+	}
+
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLowerName(t *testing.T) {
+	tests := map[string]string{
+		"GetStrudel": "getStrudel",
+		"AIFeatures": "aiFeatures",
+		"ID":         "id",
+		"Ping":       "ping",
+	}
+
+	for in, want := range tests {
+		if got := lowerName(in); got != want {
+			t.Errorf("lowerName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuildNamesDefinitions(t *testing.T) {
+	api := buildTestAPI(t)
+
+	names := map[string]bool{}
+	for _, typ := range api.Types {
+		names[typ.Name] = true
+	}
+
+	// shared names take as much of the package path as tells them apart
+	for _, want := range []string{"Strudel", "StrudelsMessageResponse", "AdminMessageResponse", "RestAgentMessage", "InternalAgentMessage", "CCSignal"} {
+		if !names[want] {
+			t.Errorf("no type named %s among %v", want, names)
+		}
+	}
+}
+
+func TestBuildEndpoints(t *testing.T) {
+	api := buildTestAPI(t)
+
+	endpoints := map[string]*Endpoint{}
+	for _, e := range api.Endpoints {
+		endpoints[e.Name] = e
+	}
+
+	if _, ok := endpoints["Connect"]; ok {
+		t.Error("WebSocket upgrade should be left out")
+	}
+
+	if _, ok := endpoints["ResolveItemApprove"]; !ok {
+		t.Errorf("endpoints sharing a summary should be told apart by their path, got %v", endpoints)
+	}
+	if _, ok := endpoints["ResolveItemRemove"]; !ok {
+		t.Errorf("endpoints sharing a summary should be told apart by their path, got %v", endpoints)
+	}
+
+	if feed := endpoints["CalendarFeed"]; feed == nil || !feed.Raw {
+		t.Errorf("CalendarFeed = %+v, want a raw endpoint", feed)
+	}
+
+	get := endpoints["GetStrudelByID"]
+	if get == nil || get.Result == nil || get.Result.Ref != "Strudel" {
+		t.Fatalf("GetStrudelByID = %+v, want a Strudel result", get)
+	}
+	if len(get.Headers) != 1 || get.Headers[0].Field != "IfNoneMatch" {
+		t.Errorf("GetStrudelByID headers = %+v", get.Headers)
+	}
+}
+
+func TestGo(t *testing.T) {
+	api := buildTestAPI(t)
+
+	types, client, err := api.Go("algopatterns")
+	if err != nil {
+		t.Fatalf("Go() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"ID string `json:\"id\"`",
+		"Signal *CCSignal `json:\"signal,omitempty\"`",
+		"Stats *Stats `json:\"stats,omitempty\"`",
+		"Extra map[string]int `json:\"extra,omitempty\"`",
+		"IsPublic *bool `json:\"is_public,omitempty\"`",
+		"SignalCredit CCSignal = \"cc-cr\"",
+	} {
+		if !bytes.Contains(collapseSpaces(types), []byte(want)) {
+			t.Errorf("types.go lacks %s", want)
+		}
+	}
+
+	for _, want := range []string{
+		"func (c *Client) GetStrudelByID(ctx context.Context, id string, params *GetStrudelByIDParams) (*Strudel, error)",
+		"func (c *Client) CreateStrudel(ctx context.Context, body *CreateRequest) (*Strudel, error)",
+		"func (c *Client) ResolveItemApprove(ctx context.Context, id int) error",
+		"func (c *Client) CalendarFeed(ctx context.Context) (*http.Response, error)",
+		`path: "/api/v1/strudels/" + url.PathEscape(id)`,
+		`path: "/api/v1/moderation/" + strconv.Itoa(id) + "/approve"`,
+		`r.setHeader("If-None-Match", params.IfNoneMatch)`,
+		`r.setQuery("tags", params.Tags)`,
+	} {
+		if !bytes.Contains(client, []byte(want)) {
+			t.Errorf("client.go lacks %s", want)
+		}
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	ts := buildTestAPI(t).TypeScript()
+
+	for _, want := range []string{
+		"export type CCSignal = \"cc-cr\" | \"no-ai\";",
+		"  id: string;",
+		"  tags?: string[];",
+		"  extra?: Record<string, number>;",
+		"  \"If-None-Match\"?: string;",
+		"async getStrudelByID(id: string, params?: GetStrudelByIDParams): Promise<Strudel> {",
+		"async createStrudel(body: CreateRequest): Promise<Strudel> {",
+		"async resolveItemApprove(id: number): Promise<void> {",
+		"async calendarFeed(): Promise<Response> {",
+		"`/api/v1/strudels/${encodeURIComponent(id)}`",
+		`headers: { "If-None-Match": params?.["If-None-Match"] }`,
+	} {
+		if !bytes.Contains(ts, []byte(want)) {
+			t.Errorf("index.ts lacks %s", want)
+		}
+	}
+}
+
+// the committed clients must be regenerated whenever the spec changes
+func TestClientsUpToDate(t *testing.T) {
+	root := filepath.Join("..", "..")
+
+	spec, err := Load(filepath.Join(root, "docs", "openapi", "swagger.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Generate(spec)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for path, want := range files {
+		got, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			t.Errorf("%s is missing, run make client", path)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date with the spec, run make client", path)
+		}
+	}
+}
+
+// gofmt aligns struct fields, which doesn't matter to the tests
+func collapseSpaces(src []byte) []byte {
+	var b strings.Builder
+	for line := range strings.SplitSeq(string(src), "\n") {
+		b.WriteString(strings.Join(strings.Fields(line), " "))
+		b.WriteString("\n")
+	}
+
+	return []byte(b.String())
+}
@@ -0,0 +1,520 @@
+package clientgen
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// written at the top of every generated file
+const generatedHeader = "Code generated by clientgen from docs/openapi/swagger.json. DO NOT EDIT."
+
+// the hand-written part of client.go: the client, its errors and the
+// request plumbing the endpoints share
+const goClientPrelude = `
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultBaseURL is the public API
+const DefaultBaseURL = %q
+
+// Client calls the API. Token is sent as a bearer token when set
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client // http.DefaultClient when nil
+}
+
+// NewClient returns a client of the API at baseURL, DefaultBaseURL when empty
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), Token: token}
+}
+
+// APIError is returned for responses outside 2xx. Code, Message and Details
+// are read from the API's error body
+type APIError struct {
+	StatusCode int    ` + "`json:\"-\"`" + `
+	Code       string ` + "`json:\"error\"`" + `
+	Message    string ` + "`json:\"message\"`" + `
+	Details    string ` + "`json:\"details,omitempty\"`" + `
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("api error %%d", e.StatusCode)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+
+	return msg
+}
+
+// Ptr returns a pointer to v, for optional fields and parameters
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   any
+}
+
+// sets a query parameter, unless it is unset
+func (r *request) setQuery(name string, v any) {
+	if value, ok := formatParam(v); ok {
+		if r.query == nil {
+			r.query = url.Values{}
+		}
+		r.query.Set(name, value)
+	}
+}
+
+// sets a header, unless it is unset
+func (r *request) setHeader(name string, v any) {
+	if value, ok := formatParam(v); ok {
+		if r.header == nil {
+			r.header = http.Header{}
+		}
+		r.header.Set(name, value)
+	}
+}
+
+// formats a parameter, reporting false for nil pointers and empty lists
+func formatParam(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case *string:
+		if v == nil {
+			return "", false
+		}
+		return *v, true
+	case int:
+		return strconv.Itoa(v), true
+	case *int:
+		if v == nil {
+			return "", false
+		}
+		return strconv.Itoa(*v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case *int64:
+		if v == nil {
+			return "", false
+		}
+		return strconv.FormatInt(*v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case *float64:
+		if v == nil {
+			return "", false
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case *bool:
+		if v == nil {
+			return "", false
+		}
+		return strconv.FormatBool(*v), true
+	case []string:
+		if len(v) == 0 {
+			return "", false
+		}
+		return strings.Join(v, ","), true
+	}
+
+	return fmt.Sprint(v), true
+}
+
+// sends r and returns the response, or an *APIError outside 2xx
+func (c *Client) send(ctx context.Context, r request) (*http.Response, error) {
+	var body io.Reader
+	if r.body != nil {
+		data, err := json.Marshal(r.body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %%w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	target := strings.TrimRight(c.BaseURL, "/") + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %%w", err)
+	}
+
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if r.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, readError(resp)
+	}
+
+	return resp, nil
+}
+
+// sends r and decodes the JSON response into out, unless out is nil
+func (c *Client) do(ctx context.Context, r request, out any) error {
+	if r.header == nil {
+		r.header = http.Header{}
+	}
+	r.header.Set("Accept", "application/json")
+
+	resp, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, err := io.Copy(io.Discard, resp.Body)
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %%w", err)
+	}
+
+	return nil
+}
+
+func readError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(data, apiErr); err != nil || (apiErr.Code == "" && apiErr.Message == "") {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return apiErr
+}
+`
+
+// generates the Go client: types.go with the definitions and client.go with
+// a method per endpoint
+func (a *API) Go(pkg string) (types, client []byte, err error) {
+	var t strings.Builder
+	fmt.Fprintf(&t, "// %s\n\npackage %s\n\n", generatedHeader, pkg)
+
+	for _, typ := range a.Types {
+		a.writeGoType(&t, typ)
+	}
+	typesSrc := t.String()
+	if strings.Contains(typesSrc, "time.Time") {
+		typesSrc = strings.Replace(typesSrc, "package "+pkg+"\n", "package "+pkg+"\n\nimport \"time\"\n", 1)
+	}
+
+	types, err = format.Source([]byte(typesSrc))
+	if err != nil {
+		return nil, nil, fmt.Errorf("generated Go types don't compile: %w", err)
+	}
+
+	var c strings.Builder
+	fmt.Fprintf(&c, "// %s\n\n// Package %s is a typed client of the algopatterns API.\npackage %s\n", generatedHeader, pkg, pkg)
+	fmt.Fprintf(&c, goClientPrelude, a.BaseURL)
+
+	for _, endpoint := range a.Endpoints {
+		a.writeGoEndpoint(&c, endpoint)
+	}
+
+	client, err = format.Source([]byte(c.String()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("generated Go client doesn't compile: %w", err)
+	}
+
+	return types, client, nil
+}
+
+func (a *API) writeGoType(b *strings.Builder, t *Type) {
+	writeGoDoc(b, "", t.Name, t.Doc)
+
+	switch {
+	case len(t.Enum) > 0:
+		fmt.Fprintf(b, "type %s string\n\nconst (\n", t.Name)
+		for i, value := range t.Enum {
+			fmt.Fprintf(b, "\t%s %s = %q\n", t.EnumNames[i], t.Name, value)
+		}
+		b.WriteString(")\n\n")
+	case len(t.Fields) == 0:
+		fmt.Fprintf(b, "type %s map[string]any\n\n", t.Name)
+	default:
+		fmt.Fprintf(b, "type %s struct {\n", t.Name)
+		for _, f := range t.Fields {
+			writeGoDoc(b, "\t", "", f.Doc)
+
+			tag := f.JSON
+			if !f.Required {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(b, "\t%s %s `json:%q`\n", f.Name, a.goType(f.Schema, f.Required), tag)
+		}
+		b.WriteString("}\n\n")
+	}
+}
+
+// the Go type of a schema. optional scalars and objects are pointers, so
+// leaving them out differs from sending their zero value
+func (a *API) goType(s *Schema, required bool) string {
+	if s == nil {
+		return "any"
+	}
+
+	optional := func(t string) string {
+		if required {
+			return t
+		}
+		return "*" + t
+	}
+
+	switch {
+	case s.Ref != "":
+		if a.isMap(s.Ref) {
+			return s.Ref
+		}
+		return optional(s.Ref)
+	case len(s.AllOf) > 0:
+		return a.goType(s.AllOf[0], required)
+	}
+
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" {
+			return optional("time.Time")
+		}
+		return optional("string")
+	case "integer":
+		if s.Format == "int64" {
+			return optional("int64")
+		}
+		return optional("int")
+	case "number":
+		return optional("float64")
+	case "boolean":
+		return optional("bool")
+	case "array":
+		return "[]" + a.goType(s.Items, true)
+	case "object":
+		if values := additionalProperties(s); values != nil {
+			return "map[string]" + a.goType(values, true)
+		}
+	}
+
+	return "any"
+}
+
+// reports whether the named type is generated as a map
+func (a *API) isMap(name string) bool {
+	for _, t := range a.Types {
+		if t.Name == name {
+			return len(t.Enum) == 0 && len(t.Fields) == 0
+		}
+	}
+
+	return false
+}
+
+func (a *API) writeGoEndpoint(b *strings.Builder, e *Endpoint) {
+	hasParams, paramsRequired := e.hasParams()
+
+	if hasParams {
+		fmt.Fprintf(b, "\n// %sParams are the query and header parameters of %s\ntype %sParams struct {\n", e.Name, e.Name, e.Name)
+		for _, p := range append(append([]*Param{}, e.Query...), e.Headers...) {
+			writeGoDoc(b, "\t", "", p.Doc)
+			fmt.Fprintf(b, "\t%s %s\n", p.Field, a.goType(p.Schema, p.Required))
+		}
+		b.WriteString("}\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, p := range e.PathParams {
+		args = append(args, p.Field+" "+a.goType(p.Schema, true))
+	}
+
+	if e.Body != nil {
+		args = append(args, "body "+a.goType(e.Body, false))
+	}
+
+	switch {
+	case hasParams && paramsRequired:
+		args = append(args, "params "+e.Name+"Params")
+	case hasParams:
+		args = append(args, "params *"+e.Name+"Params")
+	}
+
+	var result string
+	switch {
+	case e.Raw:
+		result = "(*http.Response, error)"
+	case e.Result != nil && e.Result.Ref != "":
+		result = "(*" + e.Result.Ref + ", error)"
+	case e.Result != nil:
+		result = "(" + a.goType(e.Result, true) + ", error)"
+	default:
+		result = "error"
+	}
+
+	b.WriteString("\n")
+	writeGoDoc(b, "", e.Name, fmt.Sprintf("calls %s %s. %s", e.Method, e.Path, e.Doc))
+	if e.Raw {
+		b.WriteString("// The caller closes the response body.\n")
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(%s) %s {\n", e.Name, strings.Join(args, ", "), result)
+
+	fmt.Fprintf(b, "\tr := request{method: http.Method%s, path: %s}\n", methodName(e.Method), goPath(e))
+
+	if e.Body != nil {
+		b.WriteString("\tif body != nil {\n\t\tr.body = body\n\t}\n")
+	}
+
+	if hasParams {
+		indent := "\t"
+		if !paramsRequired {
+			b.WriteString("\tif params != nil {\n")
+			indent = "\t\t"
+		}
+		for _, p := range e.Query {
+			fmt.Fprintf(b, "%sr.setQuery(%q, params.%s)\n", indent, p.Name, p.Field)
+		}
+		for _, p := range e.Headers {
+			fmt.Fprintf(b, "%sr.setHeader(%q, params.%s)\n", indent, p.Name, p.Field)
+		}
+		if !paramsRequired {
+			b.WriteString("\t}\n")
+		}
+	}
+
+	switch {
+	case e.Raw:
+		b.WriteString("\treturn c.send(ctx, r)\n")
+	case e.Result != nil && e.Result.Ref != "":
+		fmt.Fprintf(b, "\tvar out %s\n\tif err := c.do(ctx, r, &out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n", e.Result.Ref)
+	case e.Result != nil:
+		fmt.Fprintf(b, "\tvar out %s\n\terr := c.do(ctx, r, &out)\n\treturn out, err\n", a.goType(e.Result, true))
+	default:
+		b.WriteString("\treturn c.do(ctx, r, nil)\n")
+	}
+
+	b.WriteString("}\n")
+}
+
+// the Go expression of an endpoint's path, escaping its parameters
+func goPath(e *Endpoint) string {
+	path := e.Path
+	parts := []string{}
+
+	for path != "" {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			parts = append(parts, fmt.Sprintf("%q", path))
+			break
+		}
+
+		end := strings.Index(path[start:], "}") + start
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", path[:start]))
+		}
+
+		name := path[start+1 : end]
+		for _, p := range e.PathParams {
+			if p.Name != name {
+				continue
+			}
+
+			if p.Schema.Type == "integer" {
+				parts = append(parts, "strconv.Itoa("+p.Field+")")
+			} else {
+				parts = append(parts, "url.PathEscape("+p.Field+")")
+			}
+		}
+
+		path = path[end+1:]
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+// GET is Get, for http.MethodGet
+func methodName(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+// writes a doc comment starting with name, wrapped at 80 columns. nothing
+// is written without a doc
+func writeGoDoc(b *strings.Builder, indent, name, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	if name != "" {
+		doc = name + " " + doc
+	}
+
+	for _, line := range wrap(doc, 77-len(indent)) {
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
+
+// breaks text into lines of at most width characters, keeping words whole
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,148 @@
+package clientgen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// words written in capitals in Go names
+var initialisms = map[string]bool{
+	"ai": true, "api": true, "bpm": true, "cc": true, "cpm": true, "csv": true,
+	"html": true, "http": true, "id": true, "ip": true, "json": true, "jwt": true,
+	"rtt": true, "sse": true, "ttl": true, "ui": true, "url": true, "utc": true,
+	"uuid": true,
+}
+
+// splits snake_case, kebab-case, camelCase and sentences into lowercase words
+func words(s string) []string {
+	s = strings.ReplaceAll(s, "'", "")
+
+	var (
+		out  []string
+		word []rune
+	)
+
+	flush := func() {
+		if len(word) > 0 {
+			out = append(out, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(word) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// fooBar and HTTPServer, but not HTTP
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || nextLower {
+				flush()
+			}
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	return out
+}
+
+// an exported Go name for s
+func goName(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		switch {
+		case initialisms[w]:
+			b.WriteString(strings.ToUpper(w))
+		case strings.HasSuffix(w, "s") && initialisms[w[:len(w)-1]]:
+			b.WriteString(strings.ToUpper(w[:len(w)-1]) + "s") // IDs, URLs
+		default:
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+
+	return name
+}
+
+// the Go name with its leading word in lowercase, for TypeScript methods and
+// Go arguments: GetStrudel is getStrudel, AIFeatures is aiFeatures
+func lowerName(name string) string {
+	runes := []rune(name)
+
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+
+	if upper > 1 && upper < len(runes) {
+		upper-- // the last capital starts the next word
+	}
+
+	for i := range upper {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
+
+// names an argument can't take in the generated code of either language
+var reserved = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+	"class": true, "delete": true, "function": true, "new": true, "this": true,
+	"ctx": true, "params": true, "body": true, "out": true, "r": true, "err": true,
+}
+
+// a Go argument or TypeScript parameter for the wire name of a parameter
+func argName(name string) string {
+	arg := lowerName(goName(name))
+	if reserved[arg] {
+		arg += "Arg"
+	}
+
+	return arg
+}
+
+// the definition name without the package swag prefixes it with
+func shortName(definition string) string {
+	if i := strings.LastIndex(definition, "."); i >= 0 {
+		return definition[i+1:]
+	}
+
+	return definition
+}
+
+// the last depth elements of the package path swag prefixes a definition
+// with: admin for api_rest_admin.MessageResponse at depth 1
+func packagePath(definition string, depth int) string {
+	i := strings.LastIndex(definition, ".")
+	if i < 0 || depth == 0 {
+		return ""
+	}
+
+	elements := strings.Split(definition[:i], "_")
+	return strings.Join(elements[max(len(elements)-depth, 0):], "_")
+}
+
+// reports whether s can be written as a TypeScript property without quotes
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if r != '_' && r != '$' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return s != ""
+}
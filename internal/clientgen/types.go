@@ -0,0 +1,112 @@
+package clientgen
+
+import "encoding/json"
+
+// the parts of a Swagger 2.0 document, as written by swag, that the clients
+// are generated from
+type Spec struct {
+	Host        string                           `json:"host"`
+	BasePath    string                           `json:"basePath"`
+	Paths       map[string]map[string]*Operation `json:"paths"`
+	Definitions map[string]*Schema               `json:"definitions"`
+}
+
+type Operation struct {
+	Summary     string                `json:"summary"`
+	Description string                `json:"description"`
+	Produces    []string              `json:"produces"`
+	Parameters  []*Parameter          `json:"parameters"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path, query, header or body
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Type        string  `json:"type"`
+	Format      string  `json:"format"`
+	Items       *Schema `json:"items"`
+	Schema      *Schema `json:"schema"` // body parameters
+}
+
+type Response struct {
+	Description string  `json:"description"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref         string             `json:"$ref"`
+	Type        string             `json:"type"`
+	Format      string             `json:"format"`
+	Description string             `json:"description"`
+	Properties  map[string]*Schema `json:"properties"`
+	Required    []string           `json:"required"`
+	Items       *Schema            `json:"items"`
+	AllOf       []*Schema          `json:"allOf"`
+	Enum        []any              `json:"enum"`
+	EnumNames   []string           `json:"x-enum-varnames"`
+
+	// a schema, or a bool in the spec. only schemas are used
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+
+	// AdditionalProperties once parsed, see additionalProperties
+	parsedAdditional *Schema
+}
+
+// what the clients are generated from: the spec's definitions and
+// operations, named for both languages
+type API struct {
+	BaseURL   string
+	Types     []*Type
+	Endpoints []*Endpoint
+}
+
+// a definition of the spec
+type Type struct {
+	Name   string
+	Doc    string
+	Fields []*Field // objects
+
+	// string enums
+	Enum      []string
+	EnumNames []string // Go constant of each value
+}
+
+type Field struct {
+	Name     string // Go field
+	JSON     string
+	Doc      string
+	Schema   *Schema
+	Required bool
+}
+
+// an operation of the spec
+type Endpoint struct {
+	Name   string // Go method, the TypeScript one starts lowercase
+	Method string
+	Path   string
+	Doc    string
+
+	PathParams []*Param
+	Query      []*Param
+	Headers    []*Param
+
+	Body         *Schema // nil without a request body
+	BodyRequired bool
+
+	// Result is the decoded body of a JSON response, nil when there is none.
+	// Raw endpoints answer with something other than JSON (streams, files,
+	// redirects), so the response is handed back as is
+	Result *Schema
+	Raw    bool
+}
+
+type Param struct {
+	Name     string // on the wire
+	Field    string // Go field or argument
+	Doc      string
+	Schema   *Schema
+	Required bool
+}
@@ -0,0 +1,334 @@
+package clientgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the hand-written part of index.ts: the client, its errors and the
+// request plumbing the endpoints share
+const tsClientPrelude = `
+/** The public API. */
+export const DEFAULT_BASE_URL = %q;
+
+export interface ClientOptions {
+  /** Defaults to DEFAULT_BASE_URL. */
+  baseUrl?: string;
+  /** Sent as a bearer token when set. */
+  token?: string;
+  /** Defaults to the global fetch. */
+  fetch?: typeof fetch;
+}
+
+/** Thrown for responses outside 2xx, with the API's error body. */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly code: string;
+  readonly details?: string;
+
+  constructor(status: number, code: string, message: string, details?: string) {
+    super(message);
+    this.name = "ApiError";
+    this.status = status;
+    this.code = code;
+    this.details = details;
+  }
+}
+
+type QueryValue = string | number | boolean | string[] | undefined;
+
+interface Call {
+  query?: Record<string, QueryValue>;
+  headers?: Record<string, string | undefined>;
+  body?: unknown;
+  json?: boolean;
+}
+
+async function apiError(response: Response): Promise<ApiError> {
+  const text = await response.text();
+  try {
+    const body = JSON.parse(text) as { error?: string; message?: string; details?: string };
+    if (body.error || body.message) {
+      return new ApiError(response.status, body.error ?? "", body.message ?? body.error ?? "", body.details);
+    }
+  } catch {
+    // not the API's error body
+  }
+  return new ApiError(response.status, "", text.trim() || response.statusText);
+}
+
+export class Client {
+  baseUrl: string;
+  token?: string;
+  private readonly fetchFn: typeof fetch;
+
+  constructor(options: ClientOptions = {}) {
+    this.baseUrl = (options.baseUrl ?? DEFAULT_BASE_URL).replace(/\/+$/, "");
+    this.token = options.token;
+    this.fetchFn = options.fetch ?? globalThis.fetch.bind(globalThis);
+  }
+
+  /** Sends a request and returns the response, throwing ApiError outside 2xx. */
+  async send(method: string, path: string, call: Call = {}): Promise<Response> {
+    const url = new URL(this.baseUrl + path);
+    for (const [name, value] of Object.entries(call.query ?? {})) {
+      if (value === undefined || (Array.isArray(value) && value.length === 0)) {
+        continue;
+      }
+      url.searchParams.set(name, Array.isArray(value) ? value.join(",") : String(value));
+    }
+
+    const headers: Record<string, string> = {};
+    for (const [name, value] of Object.entries(call.headers ?? {})) {
+      if (value !== undefined) {
+        headers[name] = value;
+      }
+    }
+    if (call.json) {
+      headers["Accept"] = "application/json";
+    }
+    if (this.token) {
+      headers["Authorization"] = ` + "`Bearer ${this.token}`" + `;
+    }
+
+    let body: string | undefined;
+    if (call.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(call.body);
+    }
+
+    const response = await this.fetchFn(url, { method, headers, body });
+    if (!response.ok) {
+      throw await apiError(response);
+    }
+    return response;
+  }
+
+  private async json<T>(method: string, path: string, call: Call = {}): Promise<T> {
+    const response = await this.send(method, path, { ...call, json: true });
+    if (response.status === 204) {
+      return undefined as T;
+    }
+    return (await response.json()) as T;
+  }
+`
+
+// generates the TypeScript client: the definitions as types and a Client
+// class with a method per endpoint
+func (a *API) TypeScript() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n", generatedHeader)
+
+	for _, t := range a.Types {
+		writeTSType(&b, t)
+	}
+
+	for _, e := range a.Endpoints {
+		if has, _ := e.hasParams(); !has {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n/** Query and header parameters of %s. */\nexport interface %sParams {\n", lowerName(e.Name), e.Name)
+		for _, p := range append(append([]*Param{}, e.Query...), e.Headers...) {
+			writeTSDoc(&b, "  ", p.Doc)
+			optional := "?"
+			if p.Required {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsProperty(p.Name), optional, tsType(p.Schema))
+		}
+		b.WriteString("}\n")
+	}
+
+	fmt.Fprintf(&b, tsClientPrelude, a.BaseURL)
+	for _, e := range a.Endpoints {
+		writeTSEndpoint(&b, e)
+	}
+	b.WriteString("}\n")
+
+	return []byte(b.String())
+}
+
+func writeTSType(b *strings.Builder, t *Type) {
+	b.WriteString("\n")
+	writeTSDoc(b, "", t.Doc)
+
+	switch {
+	case len(t.Enum) > 0:
+		values := make([]string, len(t.Enum))
+		for i, value := range t.Enum {
+			values[i] = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(b, "export type %s = %s;\n", t.Name, strings.Join(values, " | "))
+	case len(t.Fields) == 0:
+		fmt.Fprintf(b, "export type %s = Record<string, unknown>;\n", t.Name)
+	default:
+		fmt.Fprintf(b, "export interface %s {\n", t.Name)
+		for _, f := range t.Fields {
+			writeTSDoc(b, "  ", f.Doc)
+			optional := "?"
+			if f.Required {
+				optional = ""
+			}
+			fmt.Fprintf(b, "  %s%s: %s;\n", tsProperty(f.JSON), optional, tsType(f.Schema))
+		}
+		b.WriteString("}\n")
+	}
+}
+
+// the TypeScript type of a schema
+func tsType(s *Schema) string {
+	if s == nil {
+		return "unknown"
+	}
+
+	switch {
+	case s.Ref != "":
+		return s.Ref
+	case len(s.AllOf) > 0:
+		return tsType(s.AllOf[0])
+	}
+
+	switch s.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		items := tsType(s.Items)
+		if strings.ContainsAny(items, " <") {
+			return "Array<" + items + ">"
+		}
+		return items + "[]"
+	case "object":
+		if values := additionalProperties(s); values != nil {
+			return "Record<string, " + tsType(values) + ">"
+		}
+	}
+
+	return "unknown"
+}
+
+func writeTSEndpoint(b *strings.Builder, e *Endpoint) {
+	hasParams, paramsRequired := e.hasParams()
+
+	var args []string
+	for _, p := range e.PathParams {
+		args = append(args, p.Field+": "+tsType(p.Schema))
+	}
+	if e.Body != nil {
+		// optional parameters can't come before the required params
+		optional := "?"
+		if e.BodyRequired || paramsRequired {
+			optional = ""
+		}
+		args = append(args, "body"+optional+": "+tsType(e.Body))
+	}
+	switch {
+	case hasParams && paramsRequired:
+		args = append(args, "params: "+e.Name+"Params")
+	case hasParams:
+		args = append(args, "params?: "+e.Name+"Params")
+	}
+
+	var result, call string
+	switch {
+	case e.Raw:
+		result, call = "Response", "this.send"
+	case e.Result != nil:
+		result, call = tsType(e.Result), "this.json"
+	default:
+		result, call = "void", "this.json"
+	}
+
+	b.WriteString("\n")
+	doc := fmt.Sprintf("%s\n\n`%s %s`", e.Doc, e.Method, e.Path)
+	if e.Raw {
+		doc += "; resolves to the response itself."
+	}
+	writeTSDoc(b, "  ", doc)
+	fmt.Fprintf(b, "  async %s(%s): Promise<%s> {\n", lowerName(e.Name), strings.Join(args, ", "), result)
+
+	var options []string
+	if e.Body != nil {
+		options = append(options, "body")
+	}
+	if len(e.Query) > 0 {
+		options = append(options, "query: "+tsPick(e.Query))
+	}
+	if len(e.Headers) > 0 {
+		options = append(options, "headers: "+tsPick(e.Headers))
+	}
+
+	callArgs := fmt.Sprintf("%q, %s", e.Method, tsPath(e))
+	if len(options) > 0 {
+		callArgs += ", { " + strings.Join(options, ", ") + " }"
+	}
+
+	if result == "void" {
+		fmt.Fprintf(b, "    await %s<void>(%s);\n  }\n", call, callArgs)
+		return
+	}
+	if e.Raw {
+		fmt.Fprintf(b, "    return %s(%s);\n  }\n", call, callArgs)
+		return
+	}
+	fmt.Fprintf(b, "    return %s<%s>(%s);\n  }\n", call, result, callArgs)
+}
+
+// an object literal taking the named parameters from params
+func tsPick(params []*Param) string {
+	fields := make([]string, len(params))
+	for i, p := range params {
+		access := "params?." + p.Name
+		if !isIdentifier(p.Name) {
+			access = fmt.Sprintf("params?.[%q]", p.Name)
+		}
+		fields[i] = tsProperty(p.Name) + ": " + access
+	}
+
+	return "{ " + strings.Join(fields, ", ") + " }"
+}
+
+// the template literal of an endpoint's path, escaping its parameters
+func tsPath(e *Endpoint) string {
+	path := e.Path
+	for _, p := range e.PathParams {
+		path = strings.ReplaceAll(path, "{"+p.Name+"}", "${encodeURIComponent("+p.Field+")}")
+	}
+
+	return "`" + path + "`"
+}
+
+func tsProperty(name string) string {
+	if isIdentifier(name) {
+		return name
+	}
+
+	return fmt.Sprintf("%q", name)
+}
+
+func writeTSDoc(b *strings.Builder, indent, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+
+	lines := wrap(strings.ReplaceAll(doc, "*/", "*\\/"), 77-len(indent))
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		if line == "" {
+			fmt.Fprintf(b, "%s *\n", indent)
+			continue
+		}
+		fmt.Fprintf(b, "%s * %s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
@@ -1,54 +1,213 @@
 package config
 
 import (
-	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-// loads configuration from environment variables
+const (
+	// default time a draining instance waits for websocket clients to leave
+	defaultDrainWindow = 60 * time.Second
+
+	// default time code_update is held back for clients that can't edit
+	defaultCoalesceWindow = 50 * time.Millisecond
+
+	// default replication lag past which a replica stops serving reads
+	defaultReplicaMaxLag = 10 * time.Second
+
+	// default time browsers only reach production over HTTPS
+	defaultHSTSMaxAge = 365 * 24 * time.Hour
+
+	// default origin of the web app during development
+	defaultCORSOrigin = "http://localhost:3000"
+)
+
+// loads configuration from environment variables, layered over the YAML file
+// at CONFIG_FILE if set. every problem is reported at once as a ValidationError
 func LoadEnvironmentVariables() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		_ = err // not an error - production environments may not have .env file
 	}
 
-	openaiKey := os.Getenv("OPENAI_API_KEY")
-	anthropicKey := os.Getenv("ANTHROPIC_API_KEY")
-	supabaseConnStr := os.Getenv("SUPABASE_CONNECTION_STRING")
-	redisURL := os.Getenv("REDIS_URL")
-	jwtSecret := os.Getenv("JWT_SECRET")
+	path := os.Getenv("CONFIG_FILE")
+
+	var file *File
+	if path != "" {
+		var err error
+		if file, err = LoadFile(path); err != nil {
+			return nil, err
+		}
+
+		file.ApplyEnv()
+	}
+
+	if err := Validate(file); err != nil {
+		return nil, err
+	}
+
 	environment := os.Getenv("ENVIRONMENT")
+	if environment == "" {
+		environment = "development"
+	}
 
-	if openaiKey == "" {
-		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is required")
+	autoMigrate, _ := strconv.ParseBool(os.Getenv("AUTO_MIGRATE"))       //nolint:errcheck // checked by Validate
+	faultInjection, _ := strconv.ParseBool(os.Getenv("FAULT_INJECTION")) //nolint:errcheck // checked by Validate
+
+	drainWindow := defaultDrainWindow
+	if window, err := time.ParseDuration(os.Getenv("DRAIN_WINDOW")); err == nil {
+		drainWindow = window
 	}
 
-	if anthropicKey == "" {
-		return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable is required")
+	coalesceWindow := defaultCoalesceWindow
+	if window, err := time.ParseDuration(os.Getenv("WS_COALESCE_WINDOW")); err == nil {
+		coalesceWindow = window
 	}
 
-	if supabaseConnStr == "" {
-		return nil, fmt.Errorf("SUPABASE_CONNECTION_STRING environment variable is required")
+	redisMode := os.Getenv("REDIS_MODE")
+	if redisMode == "" {
+		redisMode = "single"
 	}
 
-	if redisURL == "" {
-		return nil, fmt.Errorf("REDIS_URL environment variable is required")
+	replicaMaxLag := defaultReplicaMaxLag
+	if lag, err := time.ParseDuration(os.Getenv("REPLICA_MAX_LAG")); err == nil {
+		replicaMaxLag = lag
 	}
 
-	if jwtSecret == "" {
-		return nil, fmt.Errorf("JWT_SECRET environment variable is required")
+	// HSTS is only sent in production unless set, development runs on plain HTTP
+	var hstsMaxAge time.Duration
+	if environment == "production" {
+		hstsMaxAge = defaultHSTSMaxAge
+	}
+	if maxAge, err := time.ParseDuration(os.Getenv("HSTS_MAX_AGE")); err == nil {
+		hstsMaxAge = maxAge
 	}
 
-	if environment == "" {
-		environment = "development"
+	// embeds are made to be framed by any site unless restricted
+	embedFrameAncestors := strings.Fields(os.Getenv("EMBED_FRAME_ANCESTORS"))
+	if len(embedFrameAncestors) == 0 {
+		embedFrameAncestors = []string{"*"}
+	}
+
+	config := &Config{
+		OpenAIKey:           os.Getenv("OPENAI_API_KEY"),
+		AnthropicKey:        os.Getenv("ANTHROPIC_API_KEY"),
+		SupabaseConnString:  os.Getenv("SUPABASE_CONNECTION_STRING"),
+		RedisURL:            os.Getenv("REDIS_URL"),
+		RedisMode:           redisMode,
+		Environment:         environment,
+		DrainWindow:         drainWindow,
+		CoalesceWindow:      coalesceWindow,
+		AutoMigrate:         autoMigrate,
+		FaultInjection:      faultInjection,
+		ReplicaConnStrings:  ReplicaConnStrings(),
+		ReplicaMaxLag:       replicaMaxLag,
+		CORSOrigins:         CORSOrigins(),
+		HSTSMaxAge:          hstsMaxAge,
+		EmbedFrameAncestors: embedFrameAncestors,
+		TrustedProxies:      TrustedProxies(),
+		FilePath:            path,
+	}
+
+	if file != nil {
+		config.RateLimits = file.RateLimits
+		config.Features = file.Features
+	}
+
+	return config, nil
+}
+
+// loads the database connection string like LoadEnvironmentVariables,
+// without requiring the rest of the configuration. for commands that only
+// need the database
+func LoadDatabaseURL() (string, error) {
+	if err := godotenv.Load(); err != nil {
+		_ = err // no .env file is fine
+	}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		file, err := LoadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		file.ApplyEnv()
+	}
+
+	dsn := os.Getenv("SUPABASE_CONNECTION_STRING")
+	if dsn == "" {
+		return "", &ValidationError{Problems: []string{"SUPABASE_CONNECTION_STRING is required (or database.url in the config file)"}}
+	}
+
+	return dsn, nil
+}
+
+// the read replica connection strings in SUPABASE_REPLICA_CONNECTION_STRINGS,
+// comma-separated. nil when no replicas are configured
+func ReplicaConnStrings() []string {
+	var dsns []string
+	for _, dsn := range strings.Split(os.Getenv("SUPABASE_REPLICA_CONNECTION_STRINGS"), ",") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			dsns = append(dsns, dsn)
+		}
+	}
+
+	return dsns
+}
+
+// the addresses and CIDR ranges in TRUSTED_PROXIES, comma-separated. nil
+// when no proxy is trusted
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	return proxies
+}
+
+// the origins in CORS_ORIGIN, comma-separated. the web app's development
+// origin when unset
+func CORSOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(os.Getenv("CORS_ORIGIN"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	if len(origins) == 0 {
+		return []string{defaultCORSOrigin}
+	}
+
+	return origins
+}
+
+// reports whether the transformer, generator or embedder, or one of their
+// fallbacks, is configured to use provider
+func providerInUse(provider string) bool {
+	for key, fallback := range map[string]string{
+		"TRANSFORMER_PROVIDER":          "anthropic",
+		"GENERATOR_PROVIDER":            "anthropic",
+		"EMBEDDER_PROVIDER":             "openai",
+		"TRANSFORMER_FALLBACK_PROVIDER": "",
+		"GENERATOR_FALLBACK_PROVIDER":   "",
+		"EMBEDDER_FALLBACK_PROVIDER":    "",
+	} {
+		configured := os.Getenv(key)
+		if configured == "" {
+			configured = fallback
+		}
+
+		if configured == provider {
+			return true
+		}
 	}
 
-	return &Config{
-		OpenAIKey:          openaiKey,
-		AnthropicKey:       anthropicKey,
-		SupabaseConnString: supabaseConnStr,
-		RedisURL:           redisURL,
-		Environment:        environment,
-	}, nil
+	return false
 }
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// feature flags, switched in the config file's features section and
+// reloaded while running
+const (
+	// AI code generation over REST and websocket
+	FeatureAIGeneration = "ai_generation"
+
+	// session chat messages, replies and reactions
+	FeatureChat = "chat"
+)
+
+// flags that aren't set in the config file have these values
+var featureDefaults = map[string]bool{
+	FeatureAIGeneration: true,
+	FeatureChat:         true,
+}
+
+// the current flags, swapped as a whole on reload
+var features atomic.Pointer[map[string]bool]
+
+// replaces the feature flags. flags missing from overrides get their default
+func SetFeatures(overrides map[string]bool) {
+	flags := maps.Clone(featureDefaults)
+	for name, enabled := range overrides {
+		if _, known := flags[name]; known {
+			flags[name] = enabled
+		}
+	}
+
+	features.Store(&flags)
+}
+
+// reports whether a feature is switched on
+func FeatureEnabled(name string) bool {
+	if flags := features.Load(); flags != nil {
+		return (*flags)[name]
+	}
+
+	return featureDefaults[name]
+}
+
+// returns the known feature flags, sorted
+func FeatureNames() []string {
+	return slices.Sorted(maps.Keys(featureDefaults))
+}
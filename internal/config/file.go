@@ -0,0 +1,67 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reads a YAML config file. unknown keys are rejected so typos don't go
+// unnoticed
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var file File
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return &file, nil
+}
+
+// sets the environment variables of the file's settings that aren't set
+// already, so the environment overrides the file
+func (f *File) ApplyEnv() {
+	applyEnv(reflect.ValueOf(f).Elem())
+}
+
+func applyEnv(v reflect.Value) {
+	for i := range v.NumField() {
+		field := v.Field(i)
+
+		if field.Kind() == reflect.Struct {
+			applyEnv(field)
+			continue
+		}
+
+		key := v.Type().Field(i).Tag.Get("env")
+		if key == "" || field.Kind() != reflect.String || field.String() == "" {
+			continue
+		}
+
+		if _, set := os.LookupEnv(key); !set {
+			os.Setenv(key, field.String()) //nolint:errcheck,gosec // only fails for invalid keys
+		}
+	}
+}
+
+// reports whether the settings that are only read at startup differ between
+// two files
+func (f *File) NeedsRestart(other *File) bool {
+	a, b := *f, *other
+	a.RateLimits, b.RateLimits = RateLimitsFile{}, RateLimitsFile{}
+	a.Features, b.Features = nil, nil
+
+	return !reflect.DeepEqual(a, b)
+}
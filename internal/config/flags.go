@@ -38,3 +38,42 @@ func DefaultDocsFlags() Flags {
 func DefaultConceptsFlags() Flags {
 	return Flags{Path: "./docs/concepts", Clear: false}
 }
+
+// parses CLI flags for the reembed subcommand
+func ParseReembedFlags() ReembedFlags {
+	args := os.Args[2:]
+
+	fs := flag.NewFlagSet("reembed", flag.ExitOnError)
+	target := fs.String("target", "all", "what to re-embed: docs, strudels or all")
+	batchSize := fs.Int("batch-size", 100, "rows embedded per request")
+	restart := fs.Bool("restart", false, "start over instead of resuming an earlier run")
+	fs.Parse(args) //nolint:errcheck,gosec // G104: ExitOnError flag set handles errors
+
+	return ReembedFlags{Target: *target, BatchSize: *batchSize, Restart: *restart}
+}
+
+// parses CLI flags for the server's config validate subcommand
+func ParseValidateFlags() ValidateFlags {
+	args := os.Args[3:]
+
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	path := fs.String("config", "", "config file to check, defaults to CONFIG_FILE")
+	fs.Parse(args) //nolint:errcheck,gosec // G104: ExitOnError flag set handles errors
+
+	return ValidateFlags{Path: *path}
+}
+
+// parses CLI flags for the eval subcommand
+func ParseEvalFlags() EvalFlags {
+	args := os.Args[2:]
+
+	fs := flag.NewFlagSet("eval", flag.ExitOnError)
+	queries := fs.String("queries", "", "golden query set (JSON), defaults to the built-in set")
+	k := fs.Int("k", 5, "results scored per query")
+	out := fs.String("out", "", "write the report as JSON to this path")
+	baseline := fs.String("baseline", "", "earlier JSON report to check for regressions against")
+	tolerance := fs.Float64("tolerance", 0.02, "allowed drop in recall@k or MRR before failing")
+	fs.Parse(args) //nolint:errcheck,gosec // G104: ExitOnError flag set handles errors
+
+	return EvalFlags{Queries: *queries, K: *k, Out: *out, Baseline: *baseline, Tolerance: *tolerance}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// how often the config file is checked for changes
+const reloadInterval = 10 * time.Second
+
+// watches the config file and calls onChange with it whenever it changes and
+// is still valid. only rate limits and feature flags can change while
+// running; other changes are logged and wait for a restart. blocks until ctx
+// is done
+func WatchFile(ctx context.Context, path string, onChange func(*File)) {
+	current, err := LoadFile(path)
+	if err != nil {
+		logger.ErrorErr(err, "failed to load config file, not watching it", "path", path)
+		return
+	}
+
+	modTime := fileModTime(path)
+
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// mounted config maps are swapped rather than written, so any
+		// change of the modification time counts
+		changed := fileModTime(path)
+		if changed.Equal(modTime) {
+			continue
+		}
+		modTime = changed
+
+		next, err := LoadFile(path)
+		if err != nil {
+			logger.ErrorErr(err, "failed to reload config file, keeping the current settings", "path", path)
+			continue
+		}
+
+		if problems := next.validateReloadable(); len(problems) > 0 {
+			logger.Error("invalid config file, keeping the current settings", "path", path, "problems", problems)
+			continue
+		}
+
+		if next.NeedsRestart(current) {
+			logger.Warn("config file changed settings that need a restart, only rate limits and features were reloaded", "path", path)
+		}
+
+		current = next
+		onChange(next)
+		logger.Info("config file reloaded", "path", path)
+	}
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
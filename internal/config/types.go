@@ -1,14 +1,153 @@
 package config
 
+import "time"
+
 type Config struct {
 	OpenAIKey          string
 	AnthropicKey       string
 	SupabaseConnString string
 	RedisURL           string
+	RedisMode          string // single, sentinel or cluster
 	Environment        string
+	DrainWindow        time.Duration // how long draining waits for clients to reconnect elsewhere
+	CoalesceWindow     time.Duration // how long code_update is held back for clients that can't edit
+	AutoMigrate        bool          // apply pending schema migrations on boot
+	FaultInjection     bool          // let admins inject redis and postgres faults, never in production
+
+	// read replicas for lag-tolerant reads, and how far behind one may fall
+	// before reads go back to the primary
+	ReplicaConnStrings []string
+	ReplicaMaxLag      time.Duration
+
+	// browser-facing security: origins allowed to call the API with
+	// credentials, how long browsers stick to HTTPS (0 sends no HSTS header)
+	// and the sites allowed to frame embedded views
+	CORSOrigins         []string
+	HSTSMaxAge          time.Duration
+	EmbedFrameAncestors []string
+
+	// addresses of the load balancers and proxies whose X-Forwarded-For is
+	// believed. client IPs key rate limits, bans and audit logs
+	TrustedProxies []string
+
+	// settings only the config file holds; reloaded while running
+	FilePath   string // CONFIG_FILE, empty when configured by env only
+	RateLimits RateLimitsFile
+	Features   map[string]bool
+}
+
+// a YAML config file. string settings map to the environment variable in
+// their env tag, which overrides the file when set. durations are strings
+// like "90s" so they layer the same way
+type File struct {
+	Server     ServerFile      `yaml:"server"`
+	Database   DatabaseFile    `yaml:"database"`
+	Redis      RedisFile       `yaml:"redis"`
+	Auth       AuthFile        `yaml:"auth"`
+	LLM        LLMFile         `yaml:"llm"`
+	RateLimits RateLimitsFile  `yaml:"rate_limits"` // reloaded while running
+	Features   map[string]bool `yaml:"features"`    // reloaded while running
+}
+
+type ServerFile struct {
+	Port                string `yaml:"port" env:"PORT"`
+	Environment         string `yaml:"environment" env:"ENVIRONMENT"`
+	BaseURL             string `yaml:"base_url" env:"BASE_URL"`
+	CORSOrigin          string `yaml:"cors_origin" env:"CORS_ORIGIN"` // comma-separated
+	AllowedOrigins      string `yaml:"allowed_origins" env:"ALLOWED_ORIGINS"`
+	HSTSMaxAge          string `yaml:"hsts_max_age" env:"HSTS_MAX_AGE"`
+	EmbedFrameAncestors string `yaml:"embed_frame_ancestors" env:"EMBED_FRAME_ANCESTORS"` // space-separated
+	TrustedProxies      string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`             // comma-separated
+	DrainWindow         string `yaml:"drain_window" env:"DRAIN_WINDOW"`
+	CoalesceWindow      string `yaml:"coalesce_window" env:"WS_COALESCE_WINDOW"`
+	MetricsToken        string `yaml:"metrics_token" env:"METRICS_TOKEN"`
+	FaultInjection      string `yaml:"fault_injection" env:"FAULT_INJECTION"`
+}
+
+type DatabaseFile struct {
+	URL           string `yaml:"url" env:"SUPABASE_CONNECTION_STRING"`
+	AutoMigrate   string `yaml:"auto_migrate" env:"AUTO_MIGRATE"`
+	ReplicaURLs   string `yaml:"replica_urls" env:"SUPABASE_REPLICA_CONNECTION_STRINGS"` // comma-separated
+	ReplicaMaxLag string `yaml:"replica_max_lag" env:"REPLICA_MAX_LAG"`
+}
+
+type RedisFile struct {
+	URL  string `yaml:"url" env:"REDIS_URL"`
+	Mode string `yaml:"mode" env:"REDIS_MODE"`
+}
+
+type AuthFile struct {
+	JWTSecret     string `yaml:"jwt_secret" env:"JWT_SECRET"`
+	SessionSecret string `yaml:"session_secret" env:"SESSION_SECRET"`
+}
+
+type LLMFile struct {
+	AnthropicAPIKey     string `yaml:"anthropic_api_key" env:"ANTHROPIC_API_KEY"`
+	OpenAIAPIKey        string `yaml:"openai_api_key" env:"OPENAI_API_KEY"`
+	OllamaBaseURL       string `yaml:"ollama_base_url" env:"OLLAMA_BASE_URL"`
+	TransformerProvider string `yaml:"transformer_provider" env:"TRANSFORMER_PROVIDER"`
+	TransformerModel    string `yaml:"transformer_model" env:"TRANSFORMER_MODEL"`
+	GeneratorProvider   string `yaml:"generator_provider" env:"GENERATOR_PROVIDER"`
+	GeneratorModel      string `yaml:"generator_model" env:"GENERATOR_MODEL"`
+	EmbedderProvider    string `yaml:"embedder_provider" env:"EMBEDDER_PROVIDER"`
+	EmbedderModel       string `yaml:"embedder_model" env:"EMBEDDER_MODEL"`
+
+	// providers used while the primary ones are failing
+	TransformerFallbackProvider string `yaml:"transformer_fallback_provider" env:"TRANSFORMER_FALLBACK_PROVIDER"`
+	TransformerFallbackModel    string `yaml:"transformer_fallback_model" env:"TRANSFORMER_FALLBACK_MODEL"`
+	GeneratorFallbackProvider   string `yaml:"generator_fallback_provider" env:"GENERATOR_FALLBACK_PROVIDER"`
+	GeneratorFallbackModel      string `yaml:"generator_fallback_model" env:"GENERATOR_FALLBACK_MODEL"`
+	EmbedderFallbackProvider    string `yaml:"embedder_fallback_provider" env:"EMBEDDER_FALLBACK_PROVIDER"`
+	OllamaFallbackBaseURL       string `yaml:"ollama_fallback_base_url" env:"OLLAMA_FALLBACK_BASE_URL"`
+
+	// retries of transient provider failures
+	RetryMaxAttempts   string `yaml:"retry_max_attempts" env:"LLM_RETRY_MAX_ATTEMPTS"`
+	RetryBaseDelay     string `yaml:"retry_base_delay" env:"LLM_RETRY_BASE_DELAY"`
+	RetryMaxDelay      string `yaml:"retry_max_delay" env:"LLM_RETRY_MAX_DELAY"`
+	EmbedderHedgeDelay string `yaml:"embedder_hedge_delay" env:"EMBEDDER_HEDGE_DELAY"`
+
+	AgentCacheTTL      string `yaml:"agent_cache_ttl" env:"AGENT_CACHE_TTL"`
+	AgentMaxRepairs    string `yaml:"agent_max_repairs" env:"AGENT_MAX_REPAIRS"`
+	AgentHistoryTokens string `yaml:"agent_history_tokens" env:"AGENT_HISTORY_TOKENS"`
+	EmbeddingCacheTTL  string `yaml:"embedding_cache_ttl" env:"EMBEDDING_CACHE_TTL"`
+	RerankTimeout      string `yaml:"rerank_timeout" env:"RERANK_TIMEOUT"`
+}
+
+// overrides of the REST rate limits
+type RateLimitsFile struct {
+	Enabled *bool                    `yaml:"enabled"`
+	Rules   map[string]RateLimitRule `yaml:"rules"` // by rule name, "default" for routes without their own rule
+}
+
+type RateLimitRule struct {
+	Limit  int    `yaml:"limit"`
+	Window string `yaml:"window"`
+}
+
+// problems found in the configuration, reported all at once
+type ValidationError struct {
+	Problems []string
 }
 
 type Flags struct {
 	Path  string
 	Clear bool
 }
+
+type ReembedFlags struct {
+	Target    string // "docs", "strudels" or "all"
+	BatchSize int
+	Restart   bool
+}
+
+type ValidateFlags struct {
+	Path string // config file, empty to use CONFIG_FILE
+}
+
+type EvalFlags struct {
+	Queries   string // golden set path, empty for the built-in set
+	K         int
+	Out       string
+	Baseline  string
+	Tolerance float64
+}
@@ -0,0 +1,283 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// providers the transformer and generator can use
+var textProviders = []string{"anthropic", "openai", "ollama"}
+
+// providers the embedder can use
+var embeddingProviders = []string{"openai", "ollama"}
+
+// redis deployments REDIS_MODE selects, and how REDIS_URL is parsed for each
+var redisModes = map[string]func(string) error{
+	"single": func(url string) error {
+		_, err := redis.ParseURL(url)
+		return err
+	},
+	"sentinel": func(url string) error {
+		opts, err := redis.ParseFailoverURL(url)
+		if err == nil && opts.MasterName == "" {
+			err = errors.New("master_name is required")
+		}
+		return err
+	},
+	"cluster": func(url string) error {
+		_, err := redis.ParseClusterURL(url)
+		return err
+	},
+}
+
+// a host name or IPv4 address with an optional port
+var originHost = regexp.MustCompile(`^[A-Za-z0-9.-]+(:[0-9]+)?$`)
+
+// settings parsed as durations by the packages that use them
+var durationSettings = []string{
+	"DRAIN_WINDOW", "AGENT_CACHE_TTL", "EMBEDDING_CACHE_TTL", "RERANK_TIMEOUT",
+	"LLM_RETRY_BASE_DELAY", "LLM_RETRY_MAX_DELAY", "EMBEDDER_HEDGE_DELAY",
+	"REPLICA_MAX_LAG", "WS_COALESCE_WINDOW", "HSTS_MAX_AGE",
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// checks the environment, with the config file's settings applied, and the
+// settings only the file holds. file may be nil
+func Validate(file *File) error {
+	var problems []string
+
+	for _, required := range []struct{ key, fileKey string }{
+		{"SUPABASE_CONNECTION_STRING", "database.url"},
+		{"REDIS_URL", "redis.url"},
+		{"JWT_SECRET", "auth.jwt_secret"},
+	} {
+		if os.Getenv(required.key) == "" {
+			problems = append(problems, fmt.Sprintf("%s is required (or %s in the config file)", required.key, required.fileKey))
+		}
+	}
+
+	if dsn := os.Getenv("SUPABASE_CONNECTION_STRING"); dsn != "" {
+		if _, err := pgxpool.ParseConfig(dsn); err != nil {
+			problems = append(problems, "SUPABASE_CONNECTION_STRING is not a valid Postgres connection string: "+redact(err.Error(), dsn))
+		}
+	}
+
+	for i, dsn := range ReplicaConnStrings() {
+		if _, err := pgxpool.ParseConfig(dsn); err != nil {
+			problems = append(problems, fmt.Sprintf("SUPABASE_REPLICA_CONNECTION_STRINGS entry %d is not a valid Postgres connection string: %s", i+1, redact(err.Error(), dsn)))
+		}
+	}
+
+	redisMode := os.Getenv("REDIS_MODE")
+	if redisMode == "" {
+		redisMode = "single"
+	}
+
+	if parseRedisURL, known := redisModes[redisMode]; !known {
+		problems = append(problems, fmt.Sprintf("REDIS_MODE must be single, sentinel or cluster, got %q", redisMode))
+	} else if url := os.Getenv("REDIS_URL"); url != "" {
+		if err := parseRedisURL(url); err != nil {
+			problems = append(problems, fmt.Sprintf("REDIS_URL is not a valid Redis URL for %s mode (redis:// or rediss://): %s", redisMode, redact(err.Error(), url)))
+		}
+	}
+
+	if port := os.Getenv("PORT"); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			problems = append(problems, fmt.Sprintf("PORT must be a number between 1 and 65535, got %q", port))
+		}
+	}
+
+	if autoMigrate := os.Getenv("AUTO_MIGRATE"); autoMigrate != "" {
+		if _, err := strconv.ParseBool(autoMigrate); err != nil {
+			problems = append(problems, fmt.Sprintf("AUTO_MIGRATE must be true or false, got %q", autoMigrate))
+		}
+	}
+
+	if faultInjection := os.Getenv("FAULT_INJECTION"); faultInjection != "" {
+		enabled, err := strconv.ParseBool(faultInjection)
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("FAULT_INJECTION must be true or false, got %q", faultInjection))
+		case enabled && os.Getenv("ENVIRONMENT") == "production":
+			problems = append(problems, "FAULT_INJECTION can't be enabled in production")
+		}
+	}
+
+	if attempts := os.Getenv("LLM_RETRY_MAX_ATTEMPTS"); attempts != "" {
+		if n, err := strconv.Atoi(attempts); err != nil || n < 1 {
+			problems = append(problems, fmt.Sprintf("LLM_RETRY_MAX_ATTEMPTS must be a number of at least 1, got %q", attempts))
+		}
+	}
+
+	if repairs := os.Getenv("AGENT_MAX_REPAIRS"); repairs != "" {
+		if n, err := strconv.Atoi(repairs); err != nil || n < 0 {
+			problems = append(problems, fmt.Sprintf("AGENT_MAX_REPAIRS must be a number of at least 0, got %q", repairs))
+		}
+	}
+
+	if tokens := os.Getenv("AGENT_HISTORY_TOKENS"); tokens != "" {
+		if n, err := strconv.Atoi(tokens); err != nil || n < 1 {
+			problems = append(problems, fmt.Sprintf("AGENT_HISTORY_TOKENS must be a number of at least 1, got %q", tokens))
+		}
+	}
+
+	for _, key := range []string{"CORS_ORIGIN", "ALLOWED_ORIGINS"} {
+		for _, origin := range strings.Split(os.Getenv(key), ",") {
+			if origin = strings.TrimSpace(origin); origin != "" && !isOrigin(origin) {
+				problems = append(problems, fmt.Sprintf("%s entries must be origins like https://algopatterns.cc, without a path or wildcard, got %q", key, origin))
+			}
+		}
+	}
+
+	// production refuses WebSocket connections from browsers on other origins
+	if os.Getenv("ENVIRONMENT") == "production" && strings.TrimSpace(os.Getenv("ALLOWED_ORIGINS")) == "" {
+		problems = append(problems, "ALLOWED_ORIGINS is required in production, browsers can't open WebSocket connections without it")
+	}
+
+	for _, source := range strings.Fields(os.Getenv("EMBED_FRAME_ANCESTORS")) {
+		// CSP allows a wildcard for subdomains, an origin's other parts are literal
+		wildcard := strings.Replace(source, "://*.", "://", 1)
+		if source != "*" && source != "'self'" && !isOrigin(wildcard) {
+			problems = append(problems, fmt.Sprintf("EMBED_FRAME_ANCESTORS entries must be origins, 'self' or *, got %q", source))
+		}
+	}
+
+	for _, proxy := range TrustedProxies() {
+		if !isAddressOrPrefix(proxy) {
+			problems = append(problems, fmt.Sprintf("TRUSTED_PROXIES entries must be IP addresses or CIDR ranges like 10.0.0.0/8, got %q", proxy))
+		}
+	}
+
+	for _, key := range durationSettings {
+		if problem := checkDuration(key, os.Getenv(key)); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	problems = append(problems, checkProviders()...)
+
+	if file != nil {
+		problems = append(problems, file.validateReloadable()...)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// checks the settings that can change while running
+func (f *File) validateReloadable() []string {
+	var problems []string
+
+	names := make([]string, 0, len(f.RateLimits.Rules))
+	for name := range f.RateLimits.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rule := f.RateLimits.Rules[name]
+		if rule.Limit <= 0 {
+			problems = append(problems, fmt.Sprintf("rate_limits.rules.%s.limit must be positive", name))
+		}
+
+		if rule.Window == "" {
+			problems = append(problems, fmt.Sprintf("rate_limits.rules.%s.window is required", name))
+		} else if window, err := time.ParseDuration(rule.Window); err != nil || window <= 0 {
+			problems = append(problems, fmt.Sprintf("rate_limits.rules.%s.window must be a positive duration like 1m, got %q", name, rule.Window))
+		}
+	}
+
+	for name := range f.Features {
+		if _, known := featureDefaults[name]; !known {
+			problems = append(problems, fmt.Sprintf("unknown feature flag %q (known: %s)", name, strings.Join(FeatureNames(), ", ")))
+		}
+	}
+
+	return problems
+}
+
+// checks provider names, and that the providers in use have an API key
+func checkProviders() []string {
+	var problems []string
+
+	for key, allowed := range map[string][]string{
+		"TRANSFORMER_PROVIDER":          textProviders,
+		"GENERATOR_PROVIDER":            textProviders,
+		"EMBEDDER_PROVIDER":             embeddingProviders,
+		"TRANSFORMER_FALLBACK_PROVIDER": textProviders,
+		"GENERATOR_FALLBACK_PROVIDER":   textProviders,
+		"EMBEDDER_FALLBACK_PROVIDER":    embeddingProviders,
+	} {
+		if provider := os.Getenv(key); provider != "" && !slices.Contains(allowed, provider) {
+			problems = append(problems, fmt.Sprintf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), provider))
+		}
+	}
+
+	// self-hosted setups running on local models need neither key
+	if os.Getenv("OPENAI_API_KEY") == "" && providerInUse("openai") {
+		problems = append(problems, "OPENAI_API_KEY is required while a component uses the openai provider")
+	}
+
+	if os.Getenv("ANTHROPIC_API_KEY") == "" && providerInUse("anthropic") {
+		problems = append(problems, "ANTHROPIC_API_KEY is required while a component uses the anthropic provider")
+	}
+
+	sort.Strings(problems)
+
+	return problems
+}
+
+func checkDuration(key, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	if d, err := time.ParseDuration(value); err != nil || d < 0 {
+		return fmt.Sprintf("%s must be a duration like 90s, 5m or 24h, got %q", key, value)
+	}
+
+	return ""
+}
+
+// reports whether s is a bare http(s) origin, as browsers send it in the
+// Origin header
+func isOrigin(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+
+	return u.User == nil && u.Path == "" && u.RawQuery == "" && u.Fragment == "" && originHost.MatchString(u.Host)
+}
+
+func isAddressOrPrefix(s string) bool {
+	if _, err := netip.ParseAddr(s); err == nil {
+		return true
+	}
+
+	_, err := netip.ParsePrefix(s)
+	return err == nil
+}
+
+// keeps credentials in connection strings out of error messages
+func redact(message, secret string) string {
+	return strings.ReplaceAll(message, secret, "<redacted>")
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// parses connString into a pool config for the supabase pooler
+func PoolConfig(connString string) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, err
+	}
+
+	// configure connection pool for supabase free tier pooler compatibility
+	// free tier has ~10-15 pooler connections, so keep our pool small
+	poolConfig.MaxConns = 5
+	poolConfig.MinConns = 1
+	poolConfig.MaxConnLifetime = 30 * time.Minute
+	poolConfig.MaxConnIdleTime = 5 * time.Minute
+	poolConfig.HealthCheckPeriod = 1 * time.Minute
+
+	// CRITICAL: use simple protocol for supabase pooler (PgBouncer) compatibility
+	// pgBouncer in transaction mode doesn't support prepared statements,
+	// which causes connections to hang on subsequent queries
+	poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+
+	return poolConfig, nil
+}
+
+// creates a router over primary and a pool for each replica connection
+// string. replicas connect lazily, so one that is down at boot only gets no
+// reads until its lag check passes
+func NewRouter(primary *pgxpool.Pool, replicaConnStrings []string, maxLag time.Duration) (*Router, error) {
+	pools := make([]*pgxpool.Pool, 0, len(replicaConnStrings))
+
+	for i, dsn := range replicaConnStrings {
+		poolConfig, err := PoolConfig(dsn)
+		if err != nil {
+			closePools(pools)
+			return nil, fmt.Errorf("failed to parse replica %d config: %w", i+1, err)
+		}
+
+		poolConfig.MaxConns = replicaMaxConns
+		poolConfig.MinConns = 0
+
+		pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+		if err != nil {
+			closePools(pools)
+			return nil, fmt.Errorf("failed to create replica %d pool: %w", i+1, err)
+		}
+
+		pools = append(pools, pool)
+	}
+
+	return newRouter(primary, pools, maxLag), nil
+}
+
+func newRouter(primary *pgxpool.Pool, pools []*pgxpool.Pool, maxLag time.Duration) *Router {
+	r := &Router{primary: primary, maxLag: maxLag}
+
+	for i, pool := range pools {
+		r.replicas = append(r.replicas, &replica{name: fmt.Sprintf("replica-%d", i+1), pool: pool})
+	}
+
+	return r
+}
+
+// closes the replica pools. the primary belongs to the caller
+func (r *Router) Close() {
+	for _, rep := range r.replicas {
+		rep.pool.Close()
+	}
+}
+
+func closePools(pools []*pgxpool.Pool) {
+	for _, pool := range pools {
+		pool.Close()
+	}
+}
@@ -0,0 +1,12 @@
+package database
+
+// seconds the replica's replayed data is behind the primary. a caught-up
+// replica reports 0 however long ago the last write was, and so does a
+// server that isn't replicating
+const queryReplicaLag = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+	END::float8
+`
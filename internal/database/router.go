@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+// marks ctx so reads made with it go to the primary, for reads that must see
+// a write made just before
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// reports whether ctx was marked by WithPrimary
+func UsesPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool) //nolint:errcheck // false when unset
+	return primary
+}
+
+// the primary, for writes and reads that must be current
+func (r *Router) Primary() *pgxpool.Pool {
+	return r.primary
+}
+
+// the pool for a lag-tolerant read: a healthy replica in turn, or the primary
+// when ctx is marked by WithPrimary or no replica is healthy
+func (r *Router) Reader(ctx context.Context) *pgxpool.Pool {
+	if len(r.replicas) == 0 || UsesPrimary(ctx) {
+		metrics.ReplicaReads.Inc("primary")
+		return r.primary
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		rep := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if rep.healthy.Load() {
+			metrics.ReplicaReads.Inc("replica")
+			return rep.pool
+		}
+	}
+
+	metrics.ReplicaReads.Inc("primary")
+	return r.primary
+}
+
+// sends every read made while handling a write request to the primary, so
+// handlers that read back what they just wrote see it
+func (r *Router) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			c.Request = c.Request.WithContext(WithPrimary(c.Request.Context()))
+		}
+
+		c.Next()
+	}
+}
+
+// measures replica lag until ctx is cancelled. replicas get reads only once
+// a check finds them within the allowed lag
+func (r *Router) Start(ctx context.Context) {
+	if len(r.replicas) == 0 {
+		return
+	}
+
+	r.checkReplicas(ctx)
+
+	ticker := time.NewTicker(lagCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.checkReplicas(ctx)
+		}
+	}
+}
+
+func (r *Router) checkReplicas(ctx context.Context) {
+	for _, rep := range r.replicas {
+		lag, err := rep.lag(ctx)
+		healthy := err == nil && lag <= r.maxLag
+
+		if err != nil {
+			metrics.ReplicaLag.Set(-1, rep.name)
+		} else {
+			metrics.ReplicaLag.Set(lag.Seconds(), rep.name)
+		}
+
+		if rep.healthy.Swap(healthy) == healthy {
+			continue
+		}
+
+		switch {
+		case healthy:
+			logger.Info("read replica is serving reads", "replica", rep.name, "lag", lag)
+		case err != nil:
+			logger.Warn("read replica failed its lag check, reading from the primary", "replica", rep.name, "error", err)
+		default:
+			logger.Warn("read replica fell behind, reading from the primary", "replica", rep.name, "lag", lag, "max_lag", r.maxLag)
+		}
+	}
+}
+
+func (rep *replica) lag(ctx context.Context) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, lagCheckTimeout)
+	defer cancel()
+
+	var seconds float64
+	if err := rep.pool.QueryRow(ctx, queryReplicaLag).Scan(&seconds); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// readiness check reporting replicas that get no reads. reads fall back to
+// the primary, so this is meant as an optional check
+func (r *Router) Check(_ context.Context) error {
+	var down []string
+	for _, rep := range r.replicas {
+		if !rep.healthy.Load() {
+			down = append(down, rep.name)
+		}
+	}
+
+	if len(down) > 0 {
+		return fmt.Errorf("not serving reads: %s", strings.Join(down, ", "))
+	}
+
+	return nil
+}
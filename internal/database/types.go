@@ -0,0 +1,37 @@
+package database
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// how often replica lag is measured
+	lagCheckInterval = 5 * time.Second
+
+	// how long a lag check may take before the replica counts as down
+	lagCheckTimeout = 2 * time.Second
+
+	// replica pools stay small, like the primary's, for the supabase pooler
+	replicaMaxConns = 5
+)
+
+// routes reads to read replicas and everything else to the primary.
+// replicas that fall behind, or fail their lag check, get no reads until
+// they catch up. with no replicas every read goes to the primary
+type Router struct {
+	primary  *pgxpool.Pool
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64 // round robin over healthy replicas
+}
+
+type replica struct {
+	name    string // "replica-1", for logs and metrics
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+type primaryKey struct{}
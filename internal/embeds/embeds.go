@@ -0,0 +1,140 @@
+package embeds
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func New(db *pgxpool.Pool) *Service {
+	return &Service{db: db}
+}
+
+// creates an embed token for an active session hosted by the user
+func (s *Service) CreateForSession(ctx context.Context, userID, sessionID string) (*Token, error) {
+	return s.create(ctx, queryCreateSessionToken, userID, sessionID)
+}
+
+// creates an embed token for a strudel owned by the user
+func (s *Service) CreateForStrudel(ctx context.Context, userID, strudelID string) (*Token, error) {
+	return s.create(ctx, queryCreateStrudelToken, userID, strudelID)
+}
+
+func (s *Service) create(ctx context.Context, query, userID, targetID string) (*Token, error) {
+	value, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := scanToken(s.db.QueryRow(ctx, query, value, userID, targetID, MaxTokensPerUser))
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return token, err
+	}
+
+	// nothing inserted: either the limit was hit or the target isn't the user's
+	var active int
+	if err := s.db.QueryRow(ctx, queryCountActiveTokens, userID).Scan(&active); err != nil {
+		return nil, err
+	}
+
+	if active >= MaxTokensPerUser {
+		return nil, ErrTooManyTokens
+	}
+
+	return nil, ErrTargetNotFound
+}
+
+// gets all unrevoked embed tokens of a user, newest first
+func (s *Service) List(ctx context.Context, userID string) ([]*Token, error) {
+	rows, err := s.db.Query(ctx, queryListTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	tokens := []*Token{}
+
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// revokes an embed token owned by the user; existing embeds stop resolving immediately
+func (s *Service) Revoke(ctx context.Context, tokenID, userID string) error {
+	result, err := s.db.Exec(ctx, queryRevokeToken, tokenID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
+
+// resolves an unrevoked embed token to the view it grants
+func (s *Service) Resolve(ctx context.Context, token string) (*View, error) {
+	var v View
+
+	err := s.db.QueryRow(ctx, queryResolveToken, token).Scan(
+		&v.Kind,
+		&v.TargetID,
+		&v.Title,
+		&v.Code,
+		&v.IsLive,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTokenNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+func scanToken(row pgx.Row) (*Token, error) {
+	var t Token
+
+	err := row.Scan(
+		&t.ID,
+		&t.Token,
+		&t.SessionID,
+		&t.StrudelID,
+		&t.UserID,
+		&t.CreatedAt,
+		&t.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Kind = KindStrudel
+	if t.SessionID != nil {
+		t.Kind = KindSession
+	}
+
+	return &t, nil
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return "emb_" + hex.EncodeToString(b), nil
+}
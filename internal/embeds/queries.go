@@ -0,0 +1,56 @@
+package embeds
+
+const (
+	// only the active session's host can create a token for it
+	queryCreateSessionToken = `
+		INSERT INTO embed_tokens (token, user_id, session_id)
+		SELECT $1, $2, s.id
+		FROM sessions s
+		WHERE s.id = $3 AND s.host_user_id = $2 AND s.is_active = true
+		  AND (SELECT COUNT(*) FROM embed_tokens WHERE user_id = $2 AND revoked_at IS NULL) < $4
+		RETURNING id, token, session_id, strudel_id, user_id, created_at, revoked_at
+	`
+
+	queryCreateStrudelToken = `
+		INSERT INTO embed_tokens (token, user_id, strudel_id)
+		SELECT $1, $2, us.id
+		FROM user_strudels us
+		WHERE us.id = $3 AND us.user_id = $2 AND us.deleted_at IS NULL
+		  AND (SELECT COUNT(*) FROM embed_tokens WHERE user_id = $2 AND revoked_at IS NULL) < $4
+		RETURNING id, token, session_id, strudel_id, user_id, created_at, revoked_at
+	`
+
+	queryCountActiveTokens = `
+		SELECT COUNT(*) FROM embed_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	queryListTokens = `
+		SELECT id, token, session_id, strudel_id, user_id, created_at, revoked_at
+		FROM embed_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	queryRevokeToken = `
+		UPDATE embed_tokens
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	// an unrevoked token resolves to its target's current title and code;
+	// tokens of a trashed strudel stop resolving until it is restored
+	queryResolveToken = `
+		SELECT
+			CASE WHEN et.session_id IS NOT NULL THEN 'session' ELSE 'strudel' END,
+			COALESCE(s.id, us.id),
+			COALESCE(s.title, us.title),
+			COALESCE(s.code, us.code),
+			COALESCE(s.is_active, false)
+		FROM embed_tokens et
+		LEFT JOIN sessions s ON s.id = et.session_id
+		LEFT JOIN user_strudels us ON us.id = et.strudel_id
+		WHERE et.token = $1 AND et.revoked_at IS NULL
+		  AND (et.strudel_id IS NULL OR us.deleted_at IS NULL)
+	`
+)
@@ -0,0 +1,52 @@
+package embeds
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// what an embed token points at
+const (
+	KindSession = "session"
+	KindStrudel = "strudel"
+)
+
+// management constants
+const (
+	MaxTokensPerUser = 50 // active (unrevoked) tokens
+	tokenBytes       = 32
+)
+
+// errors
+var (
+	ErrTokenNotFound  = errors.New("embed token not found")
+	ErrTargetNotFound = errors.New("embed target not found")
+	ErrTooManyTokens  = errors.New("embed token limit reached")
+)
+
+type Service struct {
+	db *pgxpool.Pool
+}
+
+// a revocable token granting a read-only view of one session or strudel
+type Token struct {
+	ID        string     `json:"id"`
+	Token     string     `json:"token"`
+	Kind      string     `json:"kind"`
+	SessionID *string    `json:"session_id,omitempty"`
+	StrudelID *string    `json:"strudel_id,omitempty"`
+	UserID    string     `json:"user_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// what an embed token resolves to; only code and play state, never chat or participants
+type View struct {
+	Kind     string `json:"kind"`
+	TargetID string `json:"target_id"`
+	Title    string `json:"title"`
+	Code     string `json:"code"`
+	IsLive   bool   `json:"is_live"` // session is active; always false for strudels
+}
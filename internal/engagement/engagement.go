@@ -0,0 +1,168 @@
+package engagement
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"codeberg.org/algopatterns/server/internal/database"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+func New(db *pgxpool.Pool, redisClient redis.UniversalClient) *Service {
+	return &Service{db: db, redis: redisClient}
+}
+
+// sends the trending feed to read replicas
+func (s *Service) SetReadReplicas(replicas *database.Router) {
+	s.replicas = replicas
+}
+
+// the pool for a lag-tolerant read
+func (s *Service) reader(ctx context.Context) *pgxpool.Pool {
+	if s.replicas == nil {
+		return s.db
+	}
+
+	return s.replicas.Reader(ctx)
+}
+
+// likes a public strudel. liking it again changes nothing
+func (s *Service) Like(ctx context.Context, strudelID, userID string) (*LikeStatus, error) {
+	if err := s.requirePublic(ctx, strudelID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec(ctx, queryLike, strudelID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.LikeStatus(ctx, strudelID, userID)
+}
+
+// takes back a like
+func (s *Service) Unlike(ctx context.Context, strudelID, userID string) (*LikeStatus, error) {
+	if err := s.requirePublic(ctx, strudelID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec(ctx, queryUnlike, strudelID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.LikeStatus(ctx, strudelID, userID)
+}
+
+// returns whether the user likes a strudel and how many users do
+func (s *Service) LikeStatus(ctx context.Context, strudelID, userID string) (*LikeStatus, error) {
+	var status LikeStatus
+
+	if err := s.db.QueryRow(ctx, queryLikeStatus, strudelID, userID).Scan(&status.Liked, &status.Likes); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// counts a play of a public strudel unless the listener played it within the
+// debounce window. reports whether the play was counted
+func (s *Service) RecordPlay(ctx context.Context, strudelID, userID, ip string) (bool, error) {
+	if err := s.requirePublic(ctx, strudelID); err != nil {
+		return false, err
+	}
+
+	key := fmt.Sprintf(keyPlay, strudelID, listener(userID, ip))
+
+	first, err := s.redis.SetNX(ctx, key, 1, playDebounce).Result()
+	if err != nil {
+		// a play counted twice beats losing plays while redis is down
+		logger.Warn("failed to debounce play, counting it", "error", err, "strudel_id", strudelID)
+		first = true
+	}
+
+	if !first {
+		return false, nil
+	}
+
+	if _, err := s.db.Exec(ctx, queryCountPlay, strudelID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// returns public strudels ranked by recent engagement, as of the last rollup
+func (s *Service) Trending(ctx context.Context, limit, offset int) ([]TrendingStrudel, error) {
+	rows, err := s.reader(ctx).Query(ctx, queryTrending, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	trending := []TrendingStrudel{}
+
+	for rows.Next() {
+		var strudel TrendingStrudel
+		var authorName *string
+
+		err := rows.Scan(
+			&strudel.ID,
+			&strudel.UserID,
+			&authorName,
+			&strudel.Title,
+			&strudel.Description,
+			&strudel.CCSignal,
+			&strudel.Tags,
+			&strudel.CreatedAt,
+			&strudel.Likes,
+			&strudel.Plays,
+			&strudel.Forks,
+			&strudel.Score,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if authorName != nil {
+			strudel.AuthorName = *authorName
+		}
+
+		trending = append(trending, strudel)
+	}
+
+	return trending, rows.Err()
+}
+
+// recomputes the trending rollup, run periodically. readers keep seeing the
+// previous rollup while it runs
+func (s *Service) RefreshTrending(ctx context.Context) {
+	if _, err := s.db.Exec(ctx, queryRefreshTrending); err != nil {
+		logger.ErrorErr(err, "failed to refresh trending strudels")
+	}
+}
+
+func (s *Service) requirePublic(ctx context.Context, strudelID string) error {
+	var exists bool
+
+	if err := s.db.QueryRow(ctx, queryPublicStrudelExists, strudelID).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrStrudelNotFound
+	}
+
+	return nil
+}
+
+// who a play is debounced for: signed-in users across devices, everyone else
+// by address
+func listener(userID, ip string) string {
+	if userID != "" {
+		return "user:" + userID
+	}
+
+	return "ip:" + ip
+}
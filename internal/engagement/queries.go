@@ -0,0 +1,47 @@
+package engagement
+
+const (
+	queryPublicStrudelExists = `
+		SELECT EXISTS(
+			SELECT 1 FROM user_strudels
+			WHERE id = $1 AND is_public = true AND deleted_at IS NULL
+		)
+	`
+
+	queryLike = `
+		INSERT INTO strudel_likes (strudel_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	queryUnlike = `
+		DELETE FROM strudel_likes
+		WHERE strudel_id = $1 AND user_id = $2
+	`
+
+	queryLikeStatus = `
+		SELECT
+			EXISTS(SELECT 1 FROM strudel_likes WHERE strudel_id = $1 AND user_id = $2),
+			(SELECT COUNT(*) FROM strudel_likes WHERE strudel_id = $1)
+	`
+
+	queryCountPlay = `
+		INSERT INTO strudel_plays (strudel_id, day, count)
+		VALUES ($1, CURRENT_DATE, 1)
+		ON CONFLICT (strudel_id, day) DO UPDATE SET count = strudel_plays.count + 1
+	`
+
+	// strudels made private or trashed since the last rollup are left out
+	queryTrending = `
+		SELECT s.id, s.user_id, u.name, s.title, s.description, s.cc_signal, s.tags, s.created_at,
+			t.likes, t.plays, t.forks, t.score
+		FROM strudel_trending t
+		INNER JOIN user_strudels s ON s.id = t.strudel_id
+		LEFT JOIN users u ON s.user_id = u.id
+		WHERE t.score > 0 AND s.is_public = true AND s.deleted_at IS NULL
+		ORDER BY t.score DESC, s.created_at DESC, s.id
+		LIMIT $1 OFFSET $2
+	`
+
+	queryRefreshTrending = `REFRESH MATERIALIZED VIEW CONCURRENTLY strudel_trending`
+)
@@ -0,0 +1,58 @@
+package engagement
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"codeberg.org/algopatterns/server/internal/database"
+)
+
+// plays of a strudel by the same listener within this window count once
+const playDebounce = 30 * time.Minute
+
+// redis key marking a counted play, by strudel and listener
+// ("user:<id>" | "ip:<address>")
+const keyPlay = "engagement:play:%s:%s"
+
+// trending page sizes
+const (
+	DefaultTrendingLimit = 20
+	MaxTrendingLimit     = 100
+)
+
+// errors
+var (
+	ErrStrudelNotFound = errors.New("strudel not found or not public")
+)
+
+// counts likes and plays of public strudels and ranks them by engagement
+type Service struct {
+	db       *pgxpool.Pool
+	replicas *database.Router // nil reads everything from db
+	redis    redis.UniversalClient
+}
+
+// whether the user likes a strudel, and its like count
+type LikeStatus struct {
+	Liked bool `json:"liked"`
+	Likes int  `json:"likes"`
+}
+
+// a public strudel in the trending feed. counts are as of the last rollup
+type TrendingStrudel struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	AuthorName  string    `json:"author_name,omitempty"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	CCSignal    *string   `json:"cc_signal,omitempty"`
+	Tags        []string  `json:"tags"`
+	CreatedAt   time.Time `json:"created_at"`
+	Likes       int       `json:"likes"`
+	Plays       int       `json:"plays"`
+	Forks       int       `json:"forks"`
+	Score       float64   `json:"score"`
+}
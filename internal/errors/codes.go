@@ -0,0 +1,75 @@
+package errors
+
+import "time"
+
+// Code is a machine-readable error code, sent as the "error" field of REST
+// error responses and WebSocket error messages
+type Code string
+
+// standard error codes
+const (
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeValidationError      Code = "validation_error"
+	CodeServerError          Code = "server_error"
+	CodeBadRequest           Code = "bad_request"
+	CodeConflict             Code = "conflict"
+	CodePreconditionRequired Code = "precondition_required"
+	CodeTooManyRequests      Code = "too_many_requests"
+	CodePayloadTooLarge      Code = "payload_too_large"
+	CodeServiceUnavailable   Code = "service_unavailable"
+	CodeInvalidOperation     Code = "invalid_operation"
+	CodeSessionNotFound      Code = "session_not_found"
+	CodeInvalidInvite        Code = "invalid_invite"
+	CodeParticipantNotFound  Code = "participant_not_found"
+
+	// device sign-in polling, named as in RFC 8628
+	CodeAuthorizationPending Code = "authorization_pending"
+	CodeSlowDown             Code = "slow_down"
+	CodeAccessDenied         Code = "access_denied"
+	CodeExpiredToken         Code = "expired_token"
+
+	// AI generation
+	CodeBYOKRequired        Code = "byok_required"
+	CodeRateLimitExceeded   Code = "rate_limit_exceeded" // quota used up
+	CodePasteLocked         Code = "paste_locked"
+	CodeAIUnavailable       Code = "ai_unavailable"
+	CodeProviderRateLimited Code = "provider_rate_limited"
+	CodeProviderRejected    Code = "provider_rejected"
+
+	// collaboration
+	CodeRegionLocked    Code = "region_locked"
+	CodeFeatureDisabled Code = "feature_disabled"
+	CodeMuted           Code = "muted"
+	CodeSlowMode        Code = "slow_mode"
+	CodeServerBusy      Code = "server_busy"
+	CodeContentBlocked  Code = "content_blocked"
+)
+
+// Retry tells a client whether sending the same request again can succeed
+// without anyone doing anything, and how long to wait first
+type Retry struct {
+	Retryable bool
+	After     time.Duration // 0 when the request can be retried right away
+}
+
+// codes whose cause goes away by itself. every other code needs the request,
+// the user or the session to change first
+var retries = map[Code]Retry{
+	CodeServerError:          {Retryable: true, After: time.Second},
+	CodeServiceUnavailable:   {Retryable: true, After: 5 * time.Second},
+	CodeTooManyRequests:      {Retryable: true, After: time.Second},
+	CodeConflict:             {Retryable: true}, // after catching up with the current code
+	CodeAuthorizationPending: {Retryable: true},
+	CodeSlowDown:             {Retryable: true, After: 5 * time.Second},
+	CodeAIUnavailable:        {Retryable: true, After: 5 * time.Second},
+	CodeProviderRateLimited:  {Retryable: true, After: 10 * time.Second},
+	CodeSlowMode:             {Retryable: true}, // sent with the time left in the window
+	CodeServerBusy:           {Retryable: true, After: 3 * time.Second},
+}
+
+// returns how clients should retry requests failing with the code
+func (c Code) Retry() Retry {
+	return retries[c]
+}
@@ -147,6 +147,18 @@ func Conflict(c *gin.Context, message string) {
 	})
 }
 
+// PreconditionRequired returns a 428 error for writes that must say which version they're based on
+func PreconditionRequired(c *gin.Context, message string) {
+	if message == "" {
+		message = "precondition required"
+	}
+
+	c.JSON(http.StatusPreconditionRequired, ErrorResponse{
+		Error:   CodePreconditionRequired,
+		Message: message,
+	})
+}
+
 // TooManyRequests returns a 429 too many requests error
 func TooManyRequests(c *gin.Context, message string) {
 	if message == "" {
@@ -159,6 +171,30 @@ func TooManyRequests(c *gin.Context, message string) {
 	})
 }
 
+// PayloadTooLarge returns a 413 error for request bodies over the route's limit
+func PayloadTooLarge(c *gin.Context, message string) {
+	if message == "" {
+		message = "request body too large"
+	}
+
+	c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+		Error:   CodePayloadTooLarge,
+		Message: message,
+	})
+}
+
+// ServiceUnavailable returns a 503 error for features that are disabled or not configured
+func ServiceUnavailable(c *gin.Context, message string) {
+	if message == "" {
+		message = "service unavailable"
+	}
+
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+		Error:   CodeServiceUnavailable,
+		Message: message,
+	})
+}
+
 // InvalidOperation returns a 400 bad request error for invalid operations
 func InvalidOperation(c *gin.Context, message string) {
 	if message == "" {
@@ -199,6 +235,15 @@ func ParticipantNotFound(c *gin.Context) {
 	})
 }
 
+// DeviceAuthorization returns a 400 error while polling for a device sign-in,
+// with code one of the device codes (authorization_pending, slow_down, ...)
+func DeviceAuthorization(c *gin.Context, code Code, message string) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   code,
+		Message: message,
+	})
+}
+
 // IsValidUUID validates a UUID string format
 func IsValidUUID(id string) bool {
 	if id == "" {
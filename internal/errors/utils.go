@@ -14,22 +14,6 @@ import (
 // UUID format: xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx (36 characters)
 var uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
 
-// standard error codes
-const (
-	CodeUnauthorized        = "unauthorized"
-	CodeForbidden           = "forbidden"
-	CodeNotFound            = "not_found"
-	CodeValidationError     = "validation_error"
-	CodeServerError         = "server_error"
-	CodeBadRequest          = "bad_request"
-	CodeConflict            = "conflict"
-	CodeTooManyRequests     = "too_many_requests"
-	CodeInvalidOperation    = "invalid_operation"
-	CodeSessionNotFound     = "session_not_found"
-	CodeInvalidInvite       = "invalid_invite"
-	CodeParticipantNotFound = "participant_not_found"
-)
-
 // error categories for classification
 const (
 	CategoryDatabase   = "database"
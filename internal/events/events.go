@@ -0,0 +1,239 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func New(db *pgxpool.Pool) *Service {
+	return &Service{db: db}
+}
+
+// notifies RSVPs through their webhooks when an event goes live
+func (s *Service) SetEventPublisher(events EventPublisher) {
+	s.events = events
+}
+
+// schedules a performance in an active session hosted by the user
+func (s *Service) Create(ctx context.Context, hostUserID string, req CreateEventRequest) (*Event, error) {
+	if err := validateStart(req.StartsAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	event, err := scanEvent(s.db.QueryRow(
+		ctx,
+		queryCreateEvent,
+		hostUserID,
+		req.SessionID,
+		req.Title,
+		req.Description,
+		req.StartsAt,
+		MaxUpcomingPerHost,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, s.createConflict(ctx, hostUserID)
+	}
+
+	return event, err
+}
+
+// lists scheduled and live events, soonest first. viewerID may be empty
+func (s *Service) ListUpcoming(ctx context.Context, viewerID string, limit, offset int) ([]*Event, int, error) {
+	rows, err := s.db.Query(ctx, queryListUpcoming, nullable(viewerID), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	events := []*Event{}
+	total := 0
+
+	for rows.Next() {
+		var e Event
+		err := rows.Scan(
+			&e.ID,
+			&e.HostUserID,
+			&e.HostName,
+			&e.SessionID,
+			&e.Title,
+			&e.Description,
+			&e.StartsAt,
+			&e.Status,
+			&e.WentLiveAt,
+			&e.RSVPCount,
+			&e.RSVPed,
+			&e.CreatedAt,
+			&e.UpdatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		events = append(events, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// lists the events of the calendar feed, soonest first. hostUserID may be
+// empty for the events of every host
+func (s *Service) ListFeed(ctx context.Context, hostUserID string) ([]*Event, error) {
+	rows, err := s.db.Query(ctx, queryListFeed, nil, nullable(hostUserID), maxFeedEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	events := []*Event{}
+
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// gets an event. viewerID may be empty
+func (s *Service) Get(ctx context.Context, eventID, viewerID string) (*Event, error) {
+	event, err := scanEvent(s.db.QueryRow(ctx, queryGetEvent, nullable(viewerID), eventID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrEventNotFound
+	}
+
+	return event, err
+}
+
+// cancels a scheduled event hosted by the user
+func (s *Service) Cancel(ctx context.Context, eventID, hostUserID string) error {
+	result, err := s.db.Exec(ctx, queryCancelEvent, eventID, hostUserID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		// distinguish "not your event" from "not scheduled anymore"
+		var status string
+		err := s.db.QueryRow(ctx, queryHostedEventStatus, eventID, hostUserID).Scan(&status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrEventNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return ErrNotUpcoming
+	}
+
+	return nil
+}
+
+// RSVPs the user to a scheduled or live event. RSVPing twice is a no-op
+func (s *Service) RSVP(ctx context.Context, eventID, userID string) error {
+	result, err := s.db.Exec(ctx, queryAddRSVP, eventID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return s.rsvpConflict(ctx, eventID)
+	}
+
+	return nil
+}
+
+// withdraws the user's RSVP, if any
+func (s *Service) CancelRSVP(ctx context.Context, eventID, userID string) error {
+	_, err := s.db.Exec(ctx, queryRemoveRSVP, eventID, userID)
+	return err
+}
+
+// explains why a create inserted nothing: the session isn't the user's
+// active session, or the user reached the upcoming event limit
+func (s *Service) createConflict(ctx context.Context, hostUserID string) error {
+	var upcoming int
+	if err := s.db.QueryRow(ctx, queryCountUpcomingForHost, hostUserID).Scan(&upcoming); err != nil {
+		return err
+	}
+
+	if upcoming >= MaxUpcomingPerHost {
+		return ErrTooManyEvents
+	}
+
+	return ErrSessionNotFound
+}
+
+// explains why an RSVP inserted nothing: the event doesn't exist or isn't
+// upcoming anymore. RSVPing twice lands here too, and isn't an error
+func (s *Service) rsvpConflict(ctx context.Context, eventID string) error {
+	var status string
+	err := s.db.QueryRow(ctx, queryEventStatus, eventID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrEventNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if status == StatusScheduled || status == StatusLive {
+		return nil
+	}
+
+	return ErrNotUpcoming
+}
+
+func validateStart(startsAt, now time.Time) error {
+	if !startsAt.After(now) || startsAt.Sub(now) > maxScheduleAhead {
+		return ErrInvalidStart
+	}
+
+	return nil
+}
+
+func nullable(id string) *string {
+	if id == "" {
+		return nil
+	}
+
+	return &id
+}
+
+func scanEvent(row pgx.Row) (*Event, error) {
+	var e Event
+
+	err := row.Scan(
+		&e.ID,
+		&e.HostUserID,
+		&e.HostName,
+		&e.SessionID,
+		&e.Title,
+		&e.Description,
+		&e.StartsAt,
+		&e.Status,
+		&e.WentLiveAt,
+		&e.RSVPCount,
+		&e.RSVPed,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
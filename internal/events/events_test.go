@@ -0,0 +1,28 @@
+package events
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateStart(t *testing.T) {
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		startsAt time.Time
+		expected error
+	}{
+		{now.Add(time.Hour), nil},
+		{now.Add(maxScheduleAhead), nil},
+		{now, ErrInvalidStart},
+		{now.Add(-time.Minute), ErrInvalidStart},
+		{now.Add(maxScheduleAhead + time.Minute), ErrInvalidStart},
+	}
+
+	for _, tt := range tests {
+		if err := validateStart(tt.startsAt, now); !errors.Is(err, tt.expected) {
+			t.Errorf("validateStart(%v) = %v, expected %v", tt.startsAt, err, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// events have no end time, calendars show them with this length
+	defaultEventDuration = "PT1H"
+
+	icalTimeFormat = "20060102T150405Z"
+
+	// RFC 5545 lines are folded at 75 octets
+	icalLineLength = 75
+)
+
+var icalEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// writes events as an iCalendar (RFC 5545) feed named name
+func WriteCalendar(w io.Writer, name string, events []*Event) error {
+	var buf bytes.Buffer
+
+	writeICalLine(&buf, "BEGIN:VCALENDAR")
+	writeICalLine(&buf, "VERSION:2.0")
+	writeICalLine(&buf, "PRODID:-//algopatterns//events//EN")
+	writeICalLine(&buf, "CALSCALE:GREGORIAN")
+	writeICalLine(&buf, "METHOD:PUBLISH")
+	writeICalLine(&buf, "X-WR-CALNAME:"+escapeICalText(name))
+	writeICalLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeICalLine(&buf, "X-PUBLISHED-TTL:PT1H")
+
+	for _, e := range events {
+		status := "CONFIRMED"
+		if e.Status == StatusCancelled {
+			status = "CANCELLED"
+		}
+
+		description := e.Description
+		if e.HostName != "" {
+			description = strings.TrimSpace(description + "\n\nHosted by " + e.HostName)
+		}
+
+		writeICalLine(&buf, "BEGIN:VEVENT")
+		writeICalLine(&buf, "UID:"+e.ID+"@algopatterns")
+		writeICalLine(&buf, "DTSTAMP:"+formatICalTime(e.UpdatedAt))
+		writeICalLine(&buf, "LAST-MODIFIED:"+formatICalTime(e.UpdatedAt))
+		writeICalLine(&buf, "DTSTART:"+formatICalTime(e.StartsAt))
+		writeICalLine(&buf, "DURATION:"+defaultEventDuration)
+		writeICalLine(&buf, "SUMMARY:"+escapeICalText(e.Title))
+		if description != "" {
+			writeICalLine(&buf, "DESCRIPTION:"+escapeICalText(description))
+		}
+		writeICalLine(&buf, "STATUS:"+status)
+		writeICalLine(&buf, "END:VEVENT")
+	}
+
+	writeICalLine(&buf, "END:VCALENDAR")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func formatICalTime(t time.Time) string {
+	return t.UTC().Format(icalTimeFormat)
+}
+
+func escapeICalText(s string) string {
+	return icalEscaper.Replace(s)
+}
+
+// writes a content line, folding it without splitting multi-byte characters
+func writeICalLine(buf *bytes.Buffer, line string) {
+	limit := icalLineLength
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+
+		// continuation lines start with a space
+		limit = icalLineLength - 1
+	}
+
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCalendar(t *testing.T) {
+	startsAt := time.Date(2026, 3, 1, 21, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	var buf bytes.Buffer
+	err := WriteCalendar(&buf, "algopatterns events", []*Event{{
+		ID:          "event-1",
+		HostName:    "DJ Cool",
+		Title:       "Live coding, acid; techno",
+		Description: "bring headphones",
+		StartsAt:    startsAt,
+		Status:      StatusCancelled,
+		UpdatedAt:   startsAt,
+	}})
+	if err != nil {
+		t.Fatalf("WriteCalendar() error = %v", err)
+	}
+
+	ics := buf.String()
+	expected := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:event-1@algopatterns\r\n",
+		"DTSTART:20260301T203000Z\r\n",
+		"SUMMARY:Live coding\\, acid\\; techno\r\n",
+		"DESCRIPTION:bring headphones\\n\\nHosted by DJ Cool\r\n",
+		"STATUS:CANCELLED\r\n",
+		"END:VCALENDAR\r\n",
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(ics, line) {
+			t.Errorf("calendar is missing %q:\n%s", line, ics)
+		}
+	}
+}
+
+func TestWriteICalLineFolds(t *testing.T) {
+	var buf bytes.Buffer
+	writeICalLine(&buf, "SUMMARY:"+strings.Repeat("é", 100))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected folded lines, got %q", buf.String())
+	}
+
+	unfolded := lines[0]
+	for _, line := range lines {
+		if len(line) > icalLineLength {
+			t.Errorf("line is %d octets, expected at most %d", len(line), icalLineLength)
+		}
+	}
+
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, " ") {
+			t.Errorf("continuation line %q doesn't start with a space", line)
+		}
+		unfolded += line[1:]
+	}
+
+	if unfolded != "SUMMARY:"+strings.Repeat("é", 100) {
+		t.Errorf("unfolded line = %q", unfolded)
+	}
+}
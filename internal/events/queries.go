@@ -0,0 +1,129 @@
+package events
+
+const (
+	// $1 is the requesting user, or NULL when anonymous
+	eventColumns = `
+		e.id, e.host_user_id, COALESCE(u.name, ''), e.session_id, e.title, e.description,
+		e.starts_at, e.status, e.went_live_at,
+		(SELECT COUNT(*) FROM performance_event_rsvps r WHERE r.event_id = e.id),
+		EXISTS (SELECT 1 FROM performance_event_rsvps r WHERE r.event_id = e.id AND r.user_id = $1),
+		e.created_at, e.updated_at`
+
+	// a new event has no RSVPs yet
+	createdColumns = `
+		e.id, e.host_user_id, COALESCE(u.name, ''), e.session_id, e.title, e.description,
+		e.starts_at, e.status, e.went_live_at, 0, false, e.created_at, e.updated_at`
+
+	// inserts only for an active session hosted by the user, below the upcoming limit
+	queryCreateEvent = `
+		WITH created AS (
+			INSERT INTO performance_events (host_user_id, session_id, title, description, starts_at)
+			SELECT $1, s.id, $3, $4, $5
+			FROM sessions s
+			WHERE s.id = $2 AND s.host_user_id = $1 AND s.is_active = true
+			  AND (SELECT COUNT(*) FROM performance_events
+			       WHERE host_user_id = $1 AND status IN ('scheduled', 'live')) < $6
+			RETURNING *
+		)
+		SELECT ` + createdColumns + `
+		FROM created e
+		LEFT JOIN users u ON e.host_user_id = u.id
+	`
+
+	queryCountUpcomingForHost = `
+		SELECT COUNT(*) FROM performance_events
+		WHERE host_user_id = $1 AND status IN ('scheduled', 'live')
+	`
+
+	// scheduled and live events, soonest first
+	queryListUpcoming = `
+		SELECT ` + eventColumns + `, COUNT(*) OVER()
+		FROM performance_events e
+		LEFT JOIN users u ON e.host_user_id = u.id
+		WHERE e.status IN ('scheduled', 'live')
+		ORDER BY e.starts_at
+		LIMIT $2 OFFSET $3
+	`
+
+	// upcoming events for the calendar feed, optionally of one host ($2).
+	// cancelled events stay in the feed until their start so subscribed
+	// calendars mark them cancelled instead of silently dropping them
+	queryListFeed = `
+		SELECT ` + eventColumns + `
+		FROM performance_events e
+		LEFT JOIN users u ON e.host_user_id = u.id
+		WHERE (e.status IN ('scheduled', 'live')
+		       OR (e.status = 'cancelled' AND e.starts_at > NOW()))
+		  AND ($2::uuid IS NULL OR e.host_user_id = $2)
+		ORDER BY e.starts_at
+		LIMIT $3
+	`
+
+	queryGetEvent = `
+		SELECT ` + eventColumns + `
+		FROM performance_events e
+		LEFT JOIN users u ON e.host_user_id = u.id
+		WHERE e.id = $2
+	`
+
+	queryCancelEvent = `
+		UPDATE performance_events
+		SET status = 'cancelled', updated_at = NOW()
+		WHERE id = $1 AND host_user_id = $2 AND status = 'scheduled'
+	`
+
+	queryEventStatus = `
+		SELECT status FROM performance_events WHERE id = $1
+	`
+
+	queryHostedEventStatus = `
+		SELECT status FROM performance_events WHERE id = $1 AND host_user_id = $2
+	`
+
+	queryAddRSVP = `
+		INSERT INTO performance_event_rsvps (event_id, user_id)
+		SELECT id, $2 FROM performance_events
+		WHERE id = $1 AND status IN ('scheduled', 'live')
+		ON CONFLICT DO NOTHING
+	`
+
+	queryRemoveRSVP = `
+		DELETE FROM performance_event_rsvps
+		WHERE event_id = $1 AND user_id = $2
+	`
+
+	queryListRSVPUserIDs = `
+		SELECT user_id FROM performance_event_rsvps WHERE event_id = $1
+	`
+
+	// moves due events live and lists their sessions as discoverable. claimed
+	// in one statement, so only one instance notifies each event's RSVPs
+	queryStartDueEvents = `
+		WITH due AS (
+			UPDATE performance_events e
+			SET status = 'live', went_live_at = NOW(), updated_at = NOW()
+			FROM sessions s
+			WHERE e.session_id = s.id
+			  AND s.is_active = true
+			  AND e.status = 'scheduled'
+			  AND e.starts_at <= NOW()
+			RETURNING e.id, e.session_id, e.title, e.starts_at
+		), listed AS (
+			UPDATE sessions
+			SET is_discoverable = true
+			WHERE id IN (SELECT session_id FROM due)
+		)
+		SELECT id, session_id, title, starts_at FROM due
+	`
+
+	// events whose session ended: scheduled ones are cancelled, live ones end
+	queryCloseEndedSessionEvents = `
+		UPDATE performance_events e
+		SET status = CASE WHEN e.status = 'scheduled' THEN 'cancelled' ELSE 'ended' END,
+		    updated_at = NOW()
+		FROM sessions s
+		WHERE e.session_id = s.id
+		  AND s.is_active = false
+		  AND e.status IN ('scheduled', 'live')
+	`
+)
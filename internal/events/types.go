@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// event statuses
+const (
+	StatusScheduled = "scheduled"
+	StatusLive      = "live" // the session is discoverable and RSVPs were notified
+	StatusEnded     = "ended"
+	StatusCancelled = "cancelled"
+)
+
+const (
+	MaxUpcomingPerHost = 10
+
+	// most events in a calendar feed
+	maxFeedEvents = 200
+
+	// how far ahead an event may be scheduled
+	maxScheduleAhead = 90 * 24 * time.Hour
+
+	// how often the worker looks for events that are due to go live
+	pollInterval = 30 * time.Second
+)
+
+// errors
+var (
+	ErrEventNotFound   = errors.New("event not found")
+	ErrSessionNotFound = errors.New("session not found or not hosted by you")
+	ErrTooManyEvents   = errors.New("upcoming event limit reached")
+	ErrInvalidStart    = errors.New("event must start in the future and within 90 days")
+	ErrNotUpcoming     = errors.New("event is no longer upcoming")
+)
+
+// queues webhook events in the transaction of the write they report (implemented by webhooks.Service)
+type EventPublisher interface {
+	PublishTx(ctx context.Context, tx pgx.Tx, userID, event string, data any) error
+}
+
+type Service struct {
+	db     *pgxpool.Pool
+	events EventPublisher
+}
+
+// a scheduled public performance in a session
+type Event struct {
+	ID          string     `json:"id"`
+	HostUserID  string     `json:"host_user_id"`
+	HostName    string     `json:"host_name"`
+	SessionID   string     `json:"session_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	StartsAt    time.Time  `json:"starts_at"`
+	Status      string     `json:"status"`
+	WentLiveAt  *time.Time `json:"went_live_at,omitempty"`
+	RSVPCount   int        `json:"rsvp_count"`
+	RSVPed      bool       `json:"rsvped"` // whether the requesting user RSVPed
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// fields of a new event
+type CreateEventRequest struct {
+	SessionID   string
+	Title       string
+	Description string
+	StartsAt    time.Time
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/webhooks"
+)
+
+// takes due events live and closes events whose session ended.
+// runs until ctx is cancelled.
+func (s *Service) Start(ctx context.Context) {
+	logger.Info("starting event scheduler", "poll_interval", pollInterval)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("event scheduler stopped")
+			return
+		case <-ticker.C:
+			s.closeEndedSessionEvents(ctx)
+			s.startDue(ctx)
+		}
+	}
+}
+
+// makes the sessions of due events discoverable and notifies their RSVPs in
+// the same transaction, so an event doesn't go live without its notifications
+func (s *Service) startDue(ctx context.Context) {
+	var started []webhooks.PerformanceEventData
+
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, queryStartDueEvents)
+		if err != nil {
+			return err
+		}
+
+		started, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (webhooks.PerformanceEventData, error) {
+			var data webhooks.PerformanceEventData
+			err := row.Scan(&data.EventID, &data.SessionID, &data.Title, &data.StartsAt)
+			return data, err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, data := range started {
+			if err := s.notifyRSVPs(ctx, tx, data); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Warn("failed to start due events", "error", err)
+		return
+	}
+
+	for _, data := range started {
+		logger.Info("event went live", "event_id", data.EventID, "session_id", data.SessionID)
+	}
+}
+
+func (s *Service) notifyRSVPs(ctx context.Context, tx pgx.Tx, data webhooks.PerformanceEventData) error {
+	if s.events == nil {
+		return nil
+	}
+
+	rows, err := tx.Query(ctx, queryListRSVPUserIDs, data.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to list RSVPs of event %s: %w", data.EventID, err)
+	}
+
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("failed to list RSVPs of event %s: %w", data.EventID, err)
+	}
+
+	for _, userID := range userIDs {
+		if err := s.events.PublishTx(ctx, tx, userID, webhooks.EventPerformanceLive, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) closeEndedSessionEvents(ctx context.Context) {
+	result, err := s.db.Exec(ctx, queryCloseEndedSessionEvents)
+	if err != nil {
+		logger.Warn("failed to close events of ended sessions", "error", err)
+		return
+	}
+
+	if n := result.RowsAffected(); n > 0 {
+		logger.Debug("closed events of ended sessions", "count", n)
+	}
+}
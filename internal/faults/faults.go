@@ -0,0 +1,155 @@
+// Package faults injects latency, errors and dropped connections into the
+// calls made to redis and postgres, to check the fallbacks that cover them
+// actually work. it is only wired up outside production, when FAULT_INJECTION
+// is set
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the dependencies faults can be injected into
+const (
+	TargetRedis    = "redis"
+	TargetPostgres = "postgres"
+)
+
+// every target, in the order they are listed
+var Targets = []string{TargetRedis, TargetPostgres}
+
+var (
+	// returned by calls failed with an error
+	ErrInjected = errors.New("injected fault")
+
+	// returned by calls failed as if the connection dropped
+	ErrDropped = errors.New("injected fault: connection dropped")
+)
+
+// what happens to the calls made to a target. each call is delayed by
+// Latency, then fails with an error or a dropped connection at the given rates
+type Fault struct {
+	Latency   time.Duration
+	ErrorRate float64 // 0 to 1
+	DropRate  float64 // 0 to 1, at most 1 together with ErrorRate
+
+	// redis only: limits the fault to commands on keys starting with this
+	KeyPrefix string
+}
+
+// checks the rates add up to a probability and latency isn't negative
+func (f Fault) Validate() error {
+	switch {
+	case f.Latency < 0:
+		return fmt.Errorf("latency must not be negative")
+	case f.ErrorRate < 0 || f.ErrorRate > 1:
+		return fmt.Errorf("error rate must be between 0 and 1")
+	case f.DropRate < 0 || f.DropRate > 1:
+		return fmt.Errorf("drop rate must be between 0 and 1")
+	case f.ErrorRate+f.DropRate > 1:
+		return fmt.Errorf("error and drop rates must add up to at most 1")
+	}
+
+	return nil
+}
+
+// holds the faults currently injected into each target. safe for concurrent
+// use; changes apply to the next call
+type Injector struct {
+	mu     sync.RWMutex
+	faults map[string]Fault
+
+	roll func() float64 // replaced in tests
+}
+
+func New() *Injector {
+	return &Injector{
+		faults: make(map[string]Fault),
+		roll:   rand.Float64,
+	}
+}
+
+// injects fault into target's calls, replacing what was injected before
+func (i *Injector) Set(target string, fault Fault) error {
+	if !slices.Contains(Targets, target) {
+		return fmt.Errorf("unknown target %q, must be one of %s", target, strings.Join(Targets, ", "))
+	}
+
+	if err := fault.Validate(); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.faults[target] = fault
+	return nil
+}
+
+// stops injecting faults into target
+func (i *Injector) Clear(target string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.faults, target)
+}
+
+// stops injecting faults into every target
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	clear(i.faults)
+}
+
+// returns the faults currently injected, by target
+func (i *Injector) Faults() map[string]Fault {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return maps.Clone(i.faults)
+}
+
+// applies target's fault to a call on key (empty when the call has none):
+// waits out the latency, then returns the error the call should fail with,
+// or nil to let it through
+func (i *Injector) inject(ctx context.Context, target, key string) error {
+	i.mu.RLock()
+	fault, ok := i.faults[target]
+	i.mu.RUnlock()
+
+	if !ok || (fault.KeyPrefix != "" && !strings.HasPrefix(key, fault.KeyPrefix)) {
+		return nil
+	}
+
+	if fault.Latency > 0 {
+		timer := time.NewTimer(fault.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if fault.ErrorRate == 0 && fault.DropRate == 0 {
+		return nil
+	}
+
+	switch roll := i.roll(); {
+	case roll < fault.ErrorRate:
+		return ErrInjected
+	case roll < fault.ErrorRate+fault.DropRate:
+		return ErrDropped
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package faults
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// injects TargetPostgres faults into the queries made through pools created
+// from config, by failing the connection acquire each query starts with.
+// errors fail the query and keep the connection, drops also close it
+func (i *Injector) PreparePool(config *pgxpool.Config) {
+	prepare := config.PrepareConn
+
+	config.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+		switch err := i.inject(ctx, TargetPostgres, ""); err {
+		case nil:
+		case ErrDropped:
+			return false, err
+		default:
+			return true, err
+		}
+
+		if prepare != nil {
+			return prepare(ctx, conn)
+		}
+
+		return true, nil
+	}
+}
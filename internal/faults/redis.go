@@ -0,0 +1,88 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// a redis error reply. callers treat these as redis answering, so the session
+// buffer passes them on instead of switching to its in-memory fallback
+type replyError struct{}
+
+func (replyError) Error() string { return "ERR " + ErrInjected.Error() }
+
+func (replyError) RedisError() {}
+
+func (replyError) Unwrap() error { return ErrInjected }
+
+// returns a hook that injects TargetRedis faults into a client's commands:
+// errors come back as error replies, drops as a failed network read
+func (i *Injector) RedisHook() redis.Hook {
+	return redisHook{injector: i}
+}
+
+type redisHook struct {
+	injector *Injector
+}
+
+func (h redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.injector.inject(ctx, TargetRedis, commandKey(cmd)); err != nil {
+			err = redisError(err)
+			cmd.SetErr(err)
+			return err
+		}
+
+		return next(ctx, cmd)
+	}
+}
+
+// a pipeline is one round trip, so it fails or is delayed as a whole. with a
+// key prefix it is affected when its first command's key matches
+func (h redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		var key string
+		if len(cmds) > 0 {
+			key = commandKey(cmds[0])
+		}
+
+		if err := h.injector.inject(ctx, TargetRedis, key); err != nil {
+			err = redisError(err)
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+
+		return next(ctx, cmds)
+	}
+}
+
+func redisError(err error) error {
+	switch {
+	case errors.Is(err, ErrInjected):
+		return replyError{}
+	case errors.Is(err, ErrDropped):
+		return &net.OpError{Op: "read", Net: "tcp", Err: err}
+	}
+
+	return err
+}
+
+// the key a command works on, by the convention that it's the first argument
+func commandKey(cmd redis.Cmder) string {
+	args := cmd.Args()
+	if len(args) < 2 {
+		return ""
+	}
+
+	return fmt.Sprint(args[1])
+}
@@ -0,0 +1,184 @@
+package follows
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func New(db *pgxpool.Pool) *Service {
+	return &Service{db: db}
+}
+
+// follows a user. following them again changes nothing
+func (s *Service) Follow(ctx context.Context, followerID, followeeID string) (*FollowStatus, error) {
+	if followerID == followeeID {
+		return nil, ErrFollowSelf
+	}
+
+	if err := s.requireUser(ctx, followeeID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec(ctx, queryFollow, followerID, followeeID); err != nil {
+		return nil, err
+	}
+
+	return s.Status(ctx, followerID, followeeID)
+}
+
+// stops following a user
+func (s *Service) Unfollow(ctx context.Context, followerID, followeeID string) (*FollowStatus, error) {
+	if err := s.requireUser(ctx, followeeID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec(ctx, queryUnfollow, followerID, followeeID); err != nil {
+		return nil, err
+	}
+
+	return s.Status(ctx, followerID, followeeID)
+}
+
+// returns whether the follower follows a user, and the user's follow counts
+func (s *Service) Status(ctx context.Context, followerID, userID string) (*FollowStatus, error) {
+	var status FollowStatus
+
+	err := s.db.QueryRow(ctx, queryFollowStatus, followerID, userID).
+		Scan(&status.Following, &status.Followers, &status.Follows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// lists the users following a user, most recent first
+func (s *Service) Followers(ctx context.Context, userID string, limit int, after *Cursor) ([]Follow, *Cursor, error) {
+	if err := s.requireUser(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	return s.listFollows(ctx, queryFollowers, userID, limit, after)
+}
+
+// lists the users a user follows, most recently followed first
+func (s *Service) Following(ctx context.Context, userID string, limit int, after *Cursor) ([]Follow, *Cursor, error) {
+	if err := s.requireUser(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	return s.listFollows(ctx, queryFollowing, userID, limit, after)
+}
+
+// returns what the users a user follows published, forked and scheduled,
+// newest first, with the next page's cursor or nil on the last page
+func (s *Service) Feed(ctx context.Context, userID string, limit int, after *Cursor) ([]FeedItem, *Cursor, error) {
+	afterTime, afterID := cursorArgs(after)
+
+	// fetch one extra item to know whether there is a next page
+	rows, err := s.db.Query(ctx, queryFeed, userID, afterTime, afterID, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+	items := []FeedItem{}
+
+	for rows.Next() {
+		var item FeedItem
+		var authorName *string
+
+		err := rows.Scan(
+			&item.Type, &item.ID, &item.UserID, &authorName, &item.Title, &item.Description,
+			&item.ForkedFrom, &item.StartsAt, &item.OccurredAt,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if authorName != nil {
+			item.AuthorName = *authorName
+		}
+
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(items) <= limit {
+		return items, nil, nil
+	}
+
+	items = items[:limit]
+	last := items[limit-1]
+
+	return items, &Cursor{Time: last.OccurredAt, ID: last.ID}, nil
+}
+
+func (s *Service) listFollows(ctx context.Context, query, userID string, limit int, after *Cursor) ([]Follow, *Cursor, error) {
+	afterTime, afterID := cursorArgs(after)
+
+	rows, err := s.db.Query(ctx, query, userID, afterTime, afterID, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+	follows := []Follow{}
+
+	for rows.Next() {
+		var follow Follow
+		var name, avatarURL *string
+
+		if err := rows.Scan(&follow.UserID, &name, &avatarURL, &follow.FollowedAt); err != nil {
+			return nil, nil, err
+		}
+
+		if name != nil {
+			follow.Name = *name
+		}
+		if avatarURL != nil {
+			follow.AvatarURL = *avatarURL
+		}
+
+		follows = append(follows, follow)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(follows) <= limit {
+		return follows, nil, nil
+	}
+
+	follows = follows[:limit]
+	last := follows[limit-1]
+
+	return follows, &Cursor{Time: last.FollowedAt, ID: last.UserID}, nil
+}
+
+func (s *Service) requireUser(ctx context.Context, userID string) error {
+	var exists bool
+	if err := s.db.QueryRow(ctx, queryUserExists, userID).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// query arguments of a cursor, NULLs for the first page
+func cursorArgs(after *Cursor) (any, any) {
+	if after == nil {
+		return nil, nil
+	}
+
+	return after.Time, after.ID
+}
@@ -0,0 +1,83 @@
+package follows
+
+const (
+	queryUserExists = `
+		SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)
+	`
+
+	queryFollow = `
+		INSERT INTO user_follows (follower_id, followee_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	queryUnfollow = `
+		DELETE FROM user_follows
+		WHERE follower_id = $1 AND followee_id = $2
+	`
+
+	queryFollowStatus = `
+		SELECT
+			EXISTS(SELECT 1 FROM user_follows WHERE follower_id = $1 AND followee_id = $2),
+			(SELECT COUNT(*) FROM user_follows WHERE followee_id = $2),
+			(SELECT COUNT(*) FROM user_follows WHERE follower_id = $2)
+	`
+
+	// $2 and $3 are the cursor, NULL for the first page
+	queryFollowers = `
+		SELECT f.follower_id, u.name, u.avatar_url, f.created_at
+		FROM user_follows f
+		LEFT JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1
+		  AND ($2::timestamptz IS NULL OR (f.created_at, f.follower_id) < ($2, $3::uuid))
+		ORDER BY f.created_at DESC, f.follower_id DESC
+		LIMIT $4
+	`
+
+	queryFollowing = `
+		SELECT f.followee_id, u.name, u.avatar_url, f.created_at
+		FROM user_follows f
+		LEFT JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1
+		  AND ($2::timestamptz IS NULL OR (f.created_at, f.followee_id) < ($2, $3::uuid))
+		ORDER BY f.created_at DESC, f.followee_id DESC
+		LIMIT $4
+	`
+
+	// public strudels and upcoming or past (not cancelled) events of followed
+	// users, newest first. each branch applies the cursor itself so it can
+	// stop early on its index. $2 and $3 are the cursor, NULL for the first page
+	queryFeed = `
+		WITH followed AS (
+			SELECT followee_id FROM user_follows WHERE follower_id = $1
+		)
+		SELECT f.type, f.id, f.user_id, u.name, f.title, f.description,
+			f.forked_from, f.starts_at, f.occurred_at
+		FROM (
+			(SELECT
+				CASE WHEN s.forked_from IS NULL THEN 'published' ELSE 'forked' END AS type,
+				s.id, s.user_id, s.title, COALESCE(s.description, '') AS description,
+				s.forked_from, NULL::timestamptz AS starts_at, s.published_at AS occurred_at
+			FROM user_strudels s
+			WHERE s.user_id IN (SELECT followee_id FROM followed)
+			  AND s.is_public = true AND s.deleted_at IS NULL AND s.published_at IS NOT NULL
+			  AND ($2::timestamptz IS NULL OR (s.published_at, s.id) < ($2, $3::uuid))
+			ORDER BY s.published_at DESC, s.id DESC
+			LIMIT $4)
+
+			UNION ALL
+
+			(SELECT 'event', e.id, e.host_user_id, e.title, e.description,
+				NULL::uuid, e.starts_at, e.created_at
+			FROM performance_events e
+			WHERE e.host_user_id IN (SELECT followee_id FROM followed)
+			  AND e.status <> 'cancelled'
+			  AND ($2::timestamptz IS NULL OR (e.created_at, e.id) < ($2, $3::uuid))
+			ORDER BY e.created_at DESC, e.id DESC
+			LIMIT $4)
+		) f
+		LEFT JOIN users u ON u.id = f.user_id
+		ORDER BY f.occurred_at DESC, f.id DESC
+		LIMIT $4
+	`
+)
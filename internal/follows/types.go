@@ -0,0 +1,67 @@
+package follows
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// page sizes of the follower lists and the feed
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// feed item types
+const (
+	ItemPublished = "published" // a public strudel
+	ItemForked    = "forked"    // a public fork of another strudel
+	ItemEvent     = "event"     // a scheduled performance
+)
+
+// errors
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrFollowSelf   = errors.New("cannot follow yourself")
+)
+
+// follows between users and the feed of followed users' activity
+type Service struct {
+	db *pgxpool.Pool
+}
+
+// a user in a follower or following list
+type Follow struct {
+	UserID     string    `json:"user_id"`
+	Name       string    `json:"name,omitempty"`
+	AvatarURL  string    `json:"avatar_url,omitempty"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// whether the user follows another user, and that user's follow counts
+type FollowStatus struct {
+	Following bool `json:"following"`
+	Followers int  `json:"followers"`
+	Follows   int  `json:"follows"`
+}
+
+// something a followed user did
+type FeedItem struct {
+	Type        string     `json:"type"` // published, forked or event
+	ID          string     `json:"id"`   // strudel or event ID
+	UserID      string     `json:"user_id"`
+	AuthorName  string     `json:"author_name,omitempty"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	ForkedFrom  *string    `json:"forked_from,omitempty"` // forks only
+	StartsAt    *time.Time `json:"starts_at,omitempty"`   // events only
+	OccurredAt  time.Time  `json:"occurred_at"`
+}
+
+// position of the last item of a page, followed_at for follow lists and
+// occurred_at for the feed
+type Cursor struct {
+	Time time.Time `json:"t"`
+	ID   string    `json:"id"`
+}
@@ -0,0 +1,110 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// creates a checker for the given dependencies
+func NewChecker(checks ...Check) *Checker {
+	c := &Checker{}
+	for _, check := range checks {
+		if check.TTL <= 0 {
+			check.TTL = defaultTTL
+		}
+
+		c.checks = append(c.checks, &cachedCheck{Check: check})
+	}
+
+	return c
+}
+
+// takes the instance out of rotation for good, used when draining or shutting
+// down so the load balancer stops sending new traffic
+func (c *Checker) MarkUnready() {
+	c.unready.Store(true)
+}
+
+// runs the checks whose cached results expired, in parallel, and reports
+// whether the instance should receive traffic
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.unready.Load() {
+		return Report{Status: StatusDraining}
+	}
+
+	results := make([]DependencyStatus, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = check.status(ctx)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:       StatusOK,
+		Ready:        true,
+		Dependencies: make(map[string]DependencyStatus, len(c.checks)),
+	}
+
+	for i, check := range c.checks {
+		result := results[i]
+		report.Dependencies[check.Name] = result
+
+		if result.Status == StatusOK {
+			continue
+		}
+
+		if check.Optional {
+			if report.Ready {
+				report.Status = StatusDegraded
+			}
+			continue
+		}
+
+		report.Status = StatusDown
+		report.Ready = false
+	}
+
+	return report
+}
+
+// returns the cached result, or runs the check if it expired
+func (cc *cachedCheck) status(ctx context.Context) DependencyStatus {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if !cc.checkedAt.IsZero() && time.Since(cc.checkedAt) < cc.TTL {
+		return cc.result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := cc.Run(checkCtx)
+
+	result := DependencyStatus{
+		Status:    StatusOK,
+		Optional:  cc.Optional,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	// a probe that gave up shouldn't leave its failure behind for the next one
+	if ctx.Err() != nil {
+		return result
+	}
+
+	cc.result = result
+	cc.checkedAt = time.Now()
+
+	return result
+}
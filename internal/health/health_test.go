@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckerReportsDependencies(t *testing.T) {
+	checker := NewChecker(
+		Check{Name: "postgres", Run: func(context.Context) error { return nil }},
+		Check{Name: "llm", Optional: true, Run: func(context.Context) error { return errors.New("unreachable") }},
+	)
+
+	report := checker.Check(context.Background())
+	assert.True(t, report.Ready)
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, StatusOK, report.Dependencies["postgres"].Status)
+	assert.Equal(t, StatusDown, report.Dependencies["llm"].Status)
+	assert.Equal(t, "unreachable", report.Dependencies["llm"].Error)
+}
+
+func TestCheckerRequiredDependencyDown(t *testing.T) {
+	checker := NewChecker(
+		Check{Name: "redis", Run: func(context.Context) error { return errors.New("connection refused") }},
+		Check{Name: "llm", Optional: true, Run: func(context.Context) error { return errors.New("unreachable") }},
+	)
+
+	report := checker.Check(context.Background())
+	assert.False(t, report.Ready)
+	assert.Equal(t, StatusDown, report.Status)
+}
+
+func TestCheckerCachesResults(t *testing.T) {
+	var runs atomic.Int32
+	checker := NewChecker(Check{Name: "postgres", TTL: 50 * time.Millisecond, Run: func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}})
+
+	checker.Check(context.Background())
+	checker.Check(context.Background())
+	assert.Equal(t, int32(1), runs.Load())
+
+	time.Sleep(60 * time.Millisecond)
+
+	checker.Check(context.Background())
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+func TestCheckerMarkUnready(t *testing.T) {
+	checker := NewChecker(Check{Name: "postgres", Run: func(context.Context) error { return nil }})
+	checker.MarkUnready()
+
+	report := checker.Check(context.Background())
+	assert.False(t, report.Ready)
+	assert.Equal(t, StatusDraining, report.Status)
+	assert.Empty(t, report.Dependencies)
+}
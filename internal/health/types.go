@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// dependency states reported by readiness
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded" // an optional dependency is down, still serving
+	StatusDown     = "down"
+	StatusDraining = "draining" // shutting down or handing connections to another instance
+)
+
+const (
+	// how long a result is reused when a check doesn't set its own TTL
+	defaultTTL = 5 * time.Second
+
+	// how long a single check may take before it counts as down
+	checkTimeout = 2 * time.Second
+)
+
+// checks that a dependency is reachable. it should be cheap, probes call it
+// every few seconds
+type CheckFunc func(ctx context.Context) error
+
+// a dependency readiness depends on
+type Check struct {
+	Name string
+
+	// optional checks report degraded instead of taking the instance out of
+	// rotation; every instance shares them, so failing would take all down
+	Optional bool
+
+	// how long the result is reused (default 5s)
+	TTL time.Duration
+
+	Run CheckFunc
+}
+
+// runs dependency checks for readiness probes, caching each result for its TTL
+type Checker struct {
+	checks  []*cachedCheck
+	unready atomic.Bool
+}
+
+type cachedCheck struct {
+	Check
+
+	// held while the check runs, so concurrent probes share one run
+	mu        sync.Mutex
+	result    DependencyStatus
+	checkedAt time.Time
+}
+
+// outcome of a single dependency check
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	Optional  bool   `json:"optional,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// readiness of the instance and its dependencies
+type Report struct {
+	Status       string                      `json:"status"`
+	Ready        bool                        `json:"ready"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+}
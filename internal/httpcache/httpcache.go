@@ -0,0 +1,91 @@
+// Package httpcache answers conditional GETs from the versions of what a
+// response shows, and sets the cache policies of responses.
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// how long shared caches may keep public content before revalidating it
+const PublicMaxAge = time.Minute
+
+// a strong validator for a representation that changes with version and
+// parts. parts cover what the version doesn't, like rows of other tables,
+// and are hashed into the tag. Version reads the version back
+func ETag(version time.Time, parts ...string) string {
+	tag := version.UTC().Format(time.RFC3339Nano)
+
+	if len(parts) > 0 {
+		h := sha256.New()
+		for _, part := range parts {
+			h.Write([]byte(part))
+			h.Write([]byte{0})
+		}
+		tag += "/" + hex.EncodeToString(h.Sum(nil)[:8])
+	}
+
+	return `"` + tag + `"`
+}
+
+// reads back the version of a tag made by ETag, as sent in If-Match
+func Version(etag string) (time.Time, error) {
+	value := strings.Trim(strings.TrimPrefix(strings.TrimSpace(etag), "W/"), `"`)
+	value, _, _ = strings.Cut(value, "/")
+
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// sets etag on the response and, when the request's If-None-Match already
+// has it, answers 304 Not Modified. reports whether it did, for the caller
+// to return
+func NotModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+
+	if !matches(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// If-None-Match compares tags weakly: W/ prefixes don't matter
+func matches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for tag := range strings.SplitSeq(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lets browsers and shared caches keep a response for maxAge, then
+// revalidate it with its ETag. the response may still differ for signed in
+// users, like an owner's view of their strudel
+func Public(c *gin.Context, maxAge time.Duration) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	c.Header("Vary", "Authorization")
+}
+
+// keeps a response out of shared caches and has browsers revalidate it with
+// its ETag whenever it's used
+func Private(c *gin.Context) {
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("Vary", "Authorization")
+}
@@ -0,0 +1,85 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestETagVersionRoundTrip(t *testing.T) {
+	// postgres keeps microseconds, tags must not lose them
+	updatedAt := time.Date(2026, 3, 1, 12, 30, 15, 123456000, time.FixedZone("CET", 3600))
+
+	plain := ETag(updatedAt)
+	if plain != `"2026-03-01T11:30:15.123456Z"` {
+		t.Errorf("ETag() = %s", plain)
+	}
+
+	withParts := ETag(updatedAt, "msg-1", "credit")
+	if withParts == plain || withParts == ETag(updatedAt, "msg-2", "credit") {
+		t.Errorf("ETag() with parts = %s, want it to change with them", withParts)
+	}
+
+	for _, tag := range []string{plain, withParts, "W/" + withParts} {
+		version, err := Version(tag)
+		if err != nil || !version.Equal(updatedAt) {
+			t.Errorf("Version(%s) = %v, %v, want %v", tag, version, err, updatedAt)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	etag := `"2026-03-01T11:30:15Z/abc"`
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{etag, true},
+		{"W/" + etag, true},
+		{`"other", ` + etag, true},
+		{"*", true},
+		{`"2026-03-01T11:30:15Z"`, false},
+	}
+
+	for _, tt := range tests {
+		if got := matches(tt.header, etag); got != tt.want {
+			t.Errorf("matches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	etag := ETag(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	for _, ifNoneMatch := range []string{"", etag} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		if ifNoneMatch != "" {
+			c.Request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		answered := NotModified(c, etag)
+		if !answered {
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		}
+
+		wantStatus := http.StatusOK
+		if ifNoneMatch != "" {
+			wantStatus = http.StatusNotModified
+		}
+
+		if w.Code != wantStatus || answered != (ifNoneMatch != "") {
+			t.Errorf("If-None-Match %q: status %d, answered %v", ifNoneMatch, w.Code, answered)
+		}
+		if got := w.Header().Get("ETag"); got != etag {
+			t.Errorf("ETag header = %q, want %q", got, etag)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+func New(db *pgxpool.Pool) *Queue {
+	return &Queue{
+		db:       db,
+		handlers: make(map[string]registration),
+		workers:  defaultWorkers,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// attempts before a job of the kind is moved to the dead letters
+func WithMaxAttempts(attempts int) Option {
+	return func(r *registration) {
+		r.maxAttempts = max(attempts, 1)
+	}
+}
+
+// sets the handler of a job kind. this instance only claims jobs of
+// registered kinds, so during a rolling deploy old instances leave new kinds
+// to the instances that know them
+func (q *Queue) Register(kind string, handler Handler, opts ...Option) {
+	r := registration{handler: handler, maxAttempts: defaultMaxAttempts}
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handlers[kind] = r
+}
+
+// stores a job to run in the background. the insert outlives the caller's
+// cancellation, so work isn't lost because the request that queued it ended
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), enqueueTimeout)
+	defer cancel()
+
+	if err := q.insert(ctx, q.db, kind, payload); err != nil {
+		return err
+	}
+
+	q.signal()
+	return nil
+}
+
+// stores a job as part of tx, so it runs only if tx commits (the outbox
+// pattern). workers pick it up on their next poll
+func (q *Queue) EnqueueTx(ctx context.Context, tx pgx.Tx, kind string, payload any) error {
+	return q.insert(ctx, tx, kind, payload)
+}
+
+func (q *Queue) insert(ctx context.Context, db execer, kind string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s job: %w", kind, err)
+	}
+
+	if _, err := db.Exec(ctx, queryEnqueue, kind, data); err != nil {
+		return fmt.Errorf("failed to enqueue %s job: %w", kind, err)
+	}
+
+	return nil
+}
+
+// wakes an idle worker without blocking
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// marks err as not worth retrying: the job goes straight to the dead letters
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// decodes a job's payload, failing permanently when it doesn't fit v
+func Decode(payload json.RawMessage, v any) error {
+	if err := json.Unmarshal(payload, v); err != nil {
+		return Permanent(fmt.Errorf("invalid job payload: %w", err))
+	}
+
+	return nil
+}
+
+// delay before the next attempt after attempts failed ones
+func backoff(attempts int) time.Duration {
+	delay := initialBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
+
+// gets dead letters, most recently failed first, with the total matching.
+// kind filters by job kind unless empty
+func (q *Queue) ListDeadLetters(ctx context.Context, kind string, limit, offset int) ([]*DeadLetter, int, error) {
+	rows, err := q.db.Query(ctx, queryListDeadLetters, kind, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	letters := []*DeadLetter{}
+	total := 0
+
+	for rows.Next() {
+		var d DeadLetter
+		if err := rows.Scan(&d.ID, &d.Kind, &d.Payload, &d.Attempts, &d.Error, &d.CreatedAt, &d.FailedAt, &total); err != nil {
+			return nil, 0, err
+		}
+
+		letters = append(letters, &d)
+	}
+
+	return letters, total, rows.Err()
+}
+
+// puts a dead letter back on the queue with fresh attempts, e.g. after the
+// bug or outage that failed it was fixed
+func (q *Queue) RetryDeadLetter(ctx context.Context, id string) error {
+	var retried string
+	err := q.db.QueryRow(ctx, queryRetryDeadLetter, id).Scan(&retried)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrDeadLetterMissing
+	}
+
+	if err != nil {
+		return err
+	}
+
+	q.signal()
+	return nil
+}
+
+// deletes dead letters past the retention period, run periodically
+func (q *Queue) DeleteOldDeadLetters(ctx context.Context) {
+	result, err := q.db.Exec(ctx, queryDeleteOldDeadLetters, deadLetterRetention.Seconds())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to delete old dead letters")
+		}
+		return
+	}
+
+	if deleted := result.RowsAffected(); deleted > 0 {
+		logger.Info("deleted old dead letters", "count", deleted)
+	}
+}
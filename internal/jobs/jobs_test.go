@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, initialBackoff},
+		{2, 2 * initialBackoff},
+		{4, 8 * initialBackoff},
+		{30, maxBackoff},
+		{1000, maxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("bad payload")
+	err := Permanent(cause)
+
+	if !IsPermanent(err) || !errors.Is(err, cause) {
+		t.Errorf("expected a permanent error wrapping the cause, got %v", err)
+	}
+
+	// still recognized after more wrapping
+	if !IsPermanent(errors.Join(errors.New("context"), err)) {
+		t.Error("expected a wrapped permanent error to stay permanent")
+	}
+
+	if IsPermanent(cause) || Permanent(nil) != nil {
+		t.Error("only errors marked with Permanent are permanent")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var v struct {
+		ID string `json:"id"`
+	}
+
+	if err := Decode(json.RawMessage(`{"id":"abc"}`), &v); err != nil || v.ID != "abc" {
+		t.Errorf("unexpected decode result %+v (%v)", v, err)
+	}
+
+	if err := Decode(json.RawMessage(`{"id":1}`), &v); !IsPermanent(err) {
+		t.Errorf("expected a permanent error for a mismatched payload, got %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	q := New(nil)
+	handler := func(context.Context, json.RawMessage) error { return nil }
+
+	q.Register("b.kind", handler)
+	q.Register("a.kind", handler, WithMaxAttempts(3))
+	q.Register("c.kind", handler, WithMaxAttempts(0))
+
+	if kinds := q.kinds(); !slices.Equal(kinds, []string{"a.kind", "b.kind", "c.kind"}) {
+		t.Errorf("unexpected kinds %v", kinds)
+	}
+
+	want := map[string]int{"a.kind": 3, "b.kind": defaultMaxAttempts, "c.kind": 1}
+	for kind, attempts := range want {
+		if got := q.handlers[kind].maxAttempts; got != attempts {
+			t.Errorf("%s: max attempts %d, want %d", kind, got, attempts)
+		}
+	}
+}
+
+func TestCallRecoversPanics(t *testing.T) {
+	err := call(context.Background(), func(context.Context, json.RawMessage) error {
+		panic("boom")
+	}, claimedJob{kind: "test"})
+
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected the panic as an error, got %v", err)
+	}
+}
+
+func TestCallSetsTimeout(t *testing.T) {
+	err := call(context.Background(), func(ctx context.Context, _ json.RawMessage) error {
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > jobTimeout {
+			t.Errorf("expected a deadline within %v", jobTimeout)
+		}
+		return nil
+	}, claimedJob{kind: "test"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignalDoesNotBlock(t *testing.T) {
+	q := New(nil)
+
+	// more signals than the channel holds, with no worker listening
+	for range 3 {
+		q.signal()
+	}
+
+	if len(q.wake) != 1 {
+		t.Errorf("expected one pending wake up, got %d", len(q.wake))
+	}
+}
+
+func TestTruncateError(t *testing.T) {
+	long := errors.New(strings.Repeat("é", maxErrorLength))
+
+	message := truncateError(long)
+	if len(message) > maxErrorLength || !strings.HasPrefix(long.Error(), message) {
+		t.Errorf("unexpected truncation to %d bytes", len(message))
+	}
+
+	if !strings.HasSuffix(message, "é") {
+		t.Error("expected truncation on a rune boundary")
+	}
+}
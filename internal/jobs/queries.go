@@ -0,0 +1,81 @@
+package jobs
+
+const (
+	queryEnqueue = `
+		INSERT INTO jobs (kind, payload)
+		VALUES ($1, $2)
+	`
+
+	// claims the oldest due job of a kind this instance can run. the attempt
+	// is counted up front, so a job that keeps crashing its worker still runs
+	// out of attempts
+	queryClaim = `
+		UPDATE jobs
+		SET attempts = attempts + 1,
+		    locked_until = NOW() + make_interval(secs => $2)
+		WHERE id = (
+		  SELECT id FROM jobs
+		  WHERE kind = ANY($1)
+		    AND run_at <= NOW()
+		    AND (locked_until IS NULL OR locked_until < NOW())
+		  ORDER BY run_at
+		  LIMIT 1
+		  FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, kind, payload, attempts
+	`
+
+	queryComplete = `
+		DELETE FROM jobs WHERE id = $1
+	`
+
+	queryRetry = `
+		UPDATE jobs
+		SET run_at = NOW() + make_interval(secs => $2),
+		    locked_until = NULL,
+		    last_error = $3
+		WHERE id = $1
+	`
+
+	// gives back a job interrupted by shutdown, without counting the attempt
+	queryRelease = `
+		UPDATE jobs
+		SET attempts = attempts - 1,
+		    locked_until = NULL
+		WHERE id = $1
+	`
+
+	queryMoveToDeadLetters = `
+		WITH failed AS (
+		  DELETE FROM jobs WHERE id = $1
+		  RETURNING id, kind, payload, attempts, created_at
+		)
+		INSERT INTO job_dead_letters (id, kind, payload, attempts, error, created_at)
+		SELECT id, kind, payload, attempts, $2, created_at FROM failed
+	`
+
+	queryListDeadLetters = `
+		SELECT id, kind, payload, attempts, error, created_at, failed_at,
+		       COUNT(*) OVER () AS total
+		FROM job_dead_letters
+		WHERE $1 = '' OR kind = $1
+		ORDER BY failed_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	// puts a dead letter back on the queue with fresh attempts
+	queryRetryDeadLetter = `
+		WITH retried AS (
+		  DELETE FROM job_dead_letters WHERE id = $1
+		  RETURNING id, kind, payload, created_at
+		)
+		INSERT INTO jobs (id, kind, payload, created_at)
+		SELECT id, kind, payload, created_at FROM retried
+		RETURNING id
+	`
+
+	queryDeleteOldDeadLetters = `
+		DELETE FROM job_dead_letters
+		WHERE failed_at < NOW() - make_interval(secs => $1)
+	`
+)
@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// queue constants
+const (
+	// workers per instance running jobs
+	defaultWorkers = 4
+
+	// attempts before a failing job is moved to the dead letters
+	defaultMaxAttempts = 10
+	initialBackoff     = 5 * time.Second // doubled after each failed attempt
+	maxBackoff         = time.Hour
+
+	// how often idle workers look for due jobs enqueued by other instances.
+	// jobs enqueued on this instance wake a worker right away
+	pollInterval = 2 * time.Second
+
+	// a claimed job becomes due again after this, in case the instance dies
+	// while running it. handlers are cancelled well before
+	claimLease = 5 * time.Minute
+	jobTimeout = 2 * time.Minute
+
+	// how long Enqueue may take, independent of the caller's context
+	enqueueTimeout = 5 * time.Second
+
+	// dead letters older than this are deleted
+	deadLetterRetention = 30 * 24 * time.Hour
+
+	// characters of a handler's error kept with the job
+	maxErrorLength = 1000
+)
+
+// errors
+var (
+	ErrUnknownKind       = errors.New("no handler registered for job kind")
+	ErrDeadLetterMissing = errors.New("dead letter not found")
+)
+
+// runs a job. returning an error retries the job with backoff, or moves it to
+// the dead letters once it has used its attempts; wrap the error with
+// Permanent to give up right away
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// a durable queue of background jobs stored in postgres. jobs survive
+// restarts and crashes and are run at least once, by any instance, so
+// handlers must be safe to run again
+type Queue struct {
+	db *pgxpool.Pool
+
+	mu       sync.RWMutex
+	handlers map[string]registration
+
+	workers int
+	wake    chan struct{} // signalled when a job is enqueued on this instance
+}
+
+type registration struct {
+	handler     Handler
+	maxAttempts int
+}
+
+// runs statements on the pool or in a transaction
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// options of a job kind
+type Option func(*registration)
+
+// a job claimed by a worker
+type claimedJob struct {
+	id       string
+	kind     string
+	payload  json.RawMessage
+	attempts int // including this one
+}
+
+// a job that failed permanently or ran out of attempts
+type DeadLetter struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	Error     string          `json:"error"`
+	CreatedAt time.Time       `json:"created_at"` // when the job was first enqueued
+	FailedAt  time.Time       `json:"failed_at"`
+}
+
+// an error a handler won't recover from by retrying, such as a payload it
+// can't decode
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
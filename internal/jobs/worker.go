@@ -0,0 +1,179 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+// runs the workers until ctx is cancelled. safe to run on every instance;
+// jobs are claimed with row locks. register handlers before starting
+func (q *Queue) Start(ctx context.Context) {
+	logger.Info("starting job workers", "workers", q.workers, "kinds", q.kinds())
+
+	var wg sync.WaitGroup
+	for range q.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx)
+		}()
+	}
+
+	wg.Wait()
+	logger.Info("job workers stopped")
+}
+
+// runs due jobs until none are left, then waits for a new one or the next poll
+func (q *Queue) work(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for q.runNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// claims and runs one due job, reporting whether there was one
+func (q *Queue) runNext(ctx context.Context) bool {
+	kinds := q.kinds()
+	if ctx.Err() != nil || len(kinds) == 0 {
+		return false
+	}
+
+	var job claimedJob
+	err := q.db.QueryRow(ctx, queryClaim, kinds, claimLease.Seconds()).Scan(&job.id, &job.kind, &job.payload, &job.attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to claim job")
+		}
+		return false
+	}
+
+	q.run(ctx, job)
+	return true
+}
+
+// runs a claimed job and records its outcome
+func (q *Queue) run(ctx context.Context, job claimedJob) {
+	q.mu.RLock()
+	r, ok := q.handlers[job.kind]
+	q.mu.RUnlock()
+
+	start := time.Now()
+
+	err := Permanent(ErrUnknownKind)
+	if ok {
+		err = call(ctx, r.handler, job)
+	}
+
+	metrics.JobDuration.Observe(time.Since(start).Seconds(), job.kind)
+
+	// record even if the worker is stopping, so the attempt isn't lost
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), enqueueTimeout)
+	defer cancel()
+
+	var result string
+	var recordErr error
+
+	switch {
+	case err == nil:
+		result = "succeeded"
+		_, recordErr = q.db.Exec(recordCtx, queryComplete, job.id)
+
+	case ctx.Err() != nil:
+		// stopped by shutdown rather than failed, run it again without
+		// counting the attempt
+		result = "released"
+		_, recordErr = q.db.Exec(recordCtx, queryRelease, job.id)
+
+	case IsPermanent(err) || job.attempts >= r.maxAttempts:
+		result = "dead"
+		_, recordErr = q.db.Exec(recordCtx, queryMoveToDeadLetters, job.id, truncateError(err))
+
+		logger.Warn("job failed permanently",
+			"job_id", job.id,
+			"kind", job.kind,
+			"attempts", job.attempts,
+			"error", err,
+		)
+
+	default:
+		result = "retried"
+		delay := backoff(job.attempts)
+		_, recordErr = q.db.Exec(recordCtx, queryRetry, job.id, delay.Seconds(), truncateError(err))
+
+		logger.Warn("job failed, retrying",
+			"job_id", job.id,
+			"kind", job.kind,
+			"attempts", job.attempts,
+			"retry_in", delay,
+			"error", err,
+		)
+	}
+
+	metrics.JobsProcessed.Inc(job.kind, result)
+
+	// the lease runs out and the job is claimed again
+	if recordErr != nil {
+		logger.ErrorErr(recordErr, "failed to record job outcome", "job_id", job.id, "kind", job.kind, "result", result)
+	}
+}
+
+// runs handler with the job timeout, turning a panic into an error
+func call(ctx context.Context, handler Handler, job claimedJob) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job.payload)
+}
+
+// registered job kinds, sorted
+func (q *Queue) kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+
+	slices.Sort(kinds)
+	return kinds
+}
+
+func truncateError(err error) string {
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		message = strings.ToValidUTF8(message[:maxErrorLength], "")
+	}
+
+	return message
+}
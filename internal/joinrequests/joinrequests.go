@@ -0,0 +1,193 @@
+package joinrequests
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+func New(db *pgxpool.Pool) *Service {
+	return &Service{db: db}
+}
+
+// knocks on a session. a user with an open request gets it refreshed
+// instead of a new one. inTx, when set, runs in the transaction of the
+// write, e.g. to notify the host
+func (s *Service) Create(ctx context.Context, req CreateRequest, inTx func(tx pgx.Tx, request *Request) error) (*Request, error) {
+	var request *Request
+
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		var err error
+		request, err = scanRequest(tx.QueryRow(ctx, queryCreateRequest,
+			req.SessionID,
+			req.UserID,
+			req.DisplayName,
+			req.Message,
+			RequestTTL.Seconds(),
+			MaxPendingPerSession,
+		))
+		if err != nil || inTx == nil {
+			return err
+		}
+
+		return inTx(tx, request)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTooManyPending
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// gets a request of a session
+func (s *Service) Get(ctx context.Context, sessionID, requestID string) (*Request, error) {
+	request, err := scanRequest(s.db.QueryRow(ctx, queryGetRequest, requestID, sessionID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRequestNotFound
+	}
+
+	return request, err
+}
+
+// gets the open requests of a session, oldest first
+func (s *Service) ListPending(ctx context.Context, sessionID string) ([]*Request, error) {
+	rows, err := s.db.Query(ctx, queryListPending, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	requests := []*Request{}
+
+	for rows.Next() {
+		request, err := scanRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, rows.Err()
+}
+
+// approves a request with the given role. inTx, when set, runs in the
+// transaction of the write, e.g. to notify the requester
+func (s *Service) Approve(ctx context.Context, sessionID, requestID, role string, inTx func(tx pgx.Tx, request *Request) error) (*Request, error) {
+	return s.decide(ctx, sessionID, requestID, StatusApproved, &role, inTx)
+}
+
+// denies a request. inTx, when set, runs in the transaction of the write
+func (s *Service) Deny(ctx context.Context, sessionID, requestID string, inTx func(tx pgx.Tx, request *Request) error) (*Request, error) {
+	return s.decide(ctx, sessionID, requestID, StatusDenied, nil, inTx)
+}
+
+func (s *Service) decide(
+	ctx context.Context,
+	sessionID, requestID, status string,
+	role *string,
+	inTx func(tx pgx.Tx, request *Request) error,
+) (*Request, error) {
+	var request *Request
+
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		var err error
+		request, err = scanRequest(tx.QueryRow(ctx, queryDecideRequest, requestID, sessionID, status, role))
+		if err != nil || inTx == nil {
+			return err
+		}
+
+		return inTx(tx, request)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// nothing updated: either there's no such request or it isn't open anymore
+		if _, err := s.Get(ctx, sessionID, requestID); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrNotPending
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// gets the role a recent approval admits the user to the session with
+func (s *Service) AdmittedRole(ctx context.Context, sessionID, userID string) (string, error) {
+	var role string
+
+	err := s.db.QueryRow(ctx, queryAdmittedRole, sessionID, userID, AdmissionTTL.Seconds()).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotAdmitted
+	}
+
+	return role, err
+}
+
+// runs the retention worker until ctx is cancelled. expiry needs no
+// worker, pending requests read as expired once their time is up.
+func (s *Service) Start(ctx context.Context) {
+	logger.Info("starting join request worker", "retention", retention)
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("join request worker stopped")
+			return
+		case <-ticker.C:
+			s.deleteOld(ctx)
+		}
+	}
+}
+
+// deletes requests past the retention period
+func (s *Service) deleteOld(ctx context.Context) {
+	result, err := s.db.Exec(ctx, queryDeleteOld, retention.Seconds())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to delete old join requests")
+		}
+		return
+	}
+
+	if deleted := result.RowsAffected(); deleted > 0 {
+		logger.Info("deleted old join requests", "count", deleted)
+	}
+}
+
+func scanRequest(row pgx.Row) (*Request, error) {
+	var r Request
+
+	err := row.Scan(
+		&r.ID,
+		&r.SessionID,
+		&r.UserID,
+		&r.DisplayName,
+		&r.Message,
+		&r.Status,
+		&r.Role,
+		&r.ExpiresAt,
+		&r.DecidedAt,
+		&r.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
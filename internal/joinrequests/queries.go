@@ -0,0 +1,55 @@
+package joinrequests
+
+const (
+	// pending requests past their expiry read as expired
+	requestColumns = `id, session_id, user_id, display_name, message,
+		CASE WHEN status = 'pending' AND expires_at <= NOW() THEN 'expired' ELSE status END,
+		role, expires_at, decided_at, created_at`
+
+	// knocking again while a request is open refreshes it. other users'
+	// open requests count towards the session's limit.
+	queryCreateRequest = `
+		INSERT INTO session_join_requests (session_id, user_id, display_name, message, expires_at)
+		SELECT $1, $2, $3, NULLIF($4, ''), NOW() + make_interval(secs => $5)
+		WHERE (
+			SELECT COUNT(*) FROM session_join_requests
+			WHERE session_id = $1 AND user_id <> $2 AND status = 'pending' AND expires_at > NOW()
+		) < $6
+		ON CONFLICT (session_id, user_id) WHERE status = 'pending'
+		DO UPDATE SET display_name = EXCLUDED.display_name, message = EXCLUDED.message, expires_at = EXCLUDED.expires_at
+		RETURNING ` + requestColumns
+
+	queryGetRequest = `
+		SELECT ` + requestColumns + `
+		FROM session_join_requests
+		WHERE id = $1 AND session_id = $2
+	`
+
+	queryListPending = `
+		SELECT ` + requestColumns + `
+		FROM session_join_requests
+		WHERE session_id = $1 AND status = 'pending' AND expires_at > NOW()
+		ORDER BY created_at
+	`
+
+	queryDecideRequest = `
+		UPDATE session_join_requests
+		SET status = $3, role = $4, decided_at = NOW()
+		WHERE id = $1 AND session_id = $2 AND status = 'pending' AND expires_at > NOW()
+		RETURNING ` + requestColumns
+
+	// the role of the user's latest approval that still admits them
+	queryAdmittedRole = `
+		SELECT role
+		FROM session_join_requests
+		WHERE session_id = $1 AND user_id = $2 AND status = 'approved'
+		  AND decided_at > NOW() - make_interval(secs => $3)
+		ORDER BY decided_at DESC
+		LIMIT 1
+	`
+
+	queryDeleteOld = `
+		DELETE FROM session_join_requests
+		WHERE created_at < NOW() - make_interval(secs => $1)
+	`
+)
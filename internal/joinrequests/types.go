@@ -0,0 +1,68 @@
+package joinrequests
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// request statuses. expired is never stored, pending requests past their
+// expiry are reported as expired.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusDenied   = "denied"
+	StatusExpired  = "expired"
+)
+
+// request constants
+const (
+	// how long the host has to answer a knock
+	RequestTTL = 10 * time.Minute
+
+	// how long an approval lets the user connect (and reconnect) to the session
+	AdmissionTTL = 24 * time.Hour
+
+	// the most open requests a session can have, so knocks can't flood the host
+	MaxPendingPerSession = 50
+
+	// requests older than this are deleted
+	retention         = 7 * 24 * time.Hour
+	retentionInterval = time.Hour
+)
+
+// errors
+var (
+	ErrRequestNotFound = errors.New("join request not found")
+	ErrNotPending      = errors.New("join request was already answered or has expired")
+	ErrTooManyPending  = errors.New("session has too many open join requests")
+	ErrNotAdmitted     = errors.New("no approved join request")
+)
+
+// stores knock-to-join requests
+type Service struct {
+	db *pgxpool.Pool
+}
+
+// a request to join a session, answered by the host
+type Request struct {
+	ID          string     `json:"id"`
+	SessionID   string     `json:"session_id"`
+	UserID      string     `json:"user_id"`
+	DisplayName string     `json:"display_name"`
+	Message     *string    `json:"message,omitempty"`
+	Status      string     `json:"status"`
+	Role        *string    `json:"role,omitempty"` // granted on approval
+	ExpiresAt   time.Time  `json:"expires_at"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// contains data for knocking on a session
+type CreateRequest struct {
+	SessionID   string
+	UserID      string
+	DisplayName string
+	Message     string // optional note to the host
+}
@@ -0,0 +1,175 @@
+// Package keyset pages through sorted lists by position rather than offset.
+// a page continues from the sort value and id of the row at the edge of the
+// page before it, so pages stay stable while rows come and go and cost the
+// same at any depth.
+package keyset
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// the order a list is paged in
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// parses "field" (ascending) or "-field" (descending)
+func ParseSort(s string) Sort {
+	if field, ok := strings.CutPrefix(s, "-"); ok {
+		return Sort{Field: field, Desc: true}
+	}
+
+	return Sort{Field: s}
+}
+
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+
+	return s.Field
+}
+
+// the place of a row in a sort: its sort value, with its id breaking ties
+type Key struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// where a page starts: right after the row at Key, or right before it when
+// Backward. Sort is the sort the position was taken in
+type Position struct {
+	Sort string `json:"s"`
+	Key
+	Backward bool `json:"b,omitempty"`
+}
+
+// one page of a list
+type Page struct {
+	Limit int
+	Sort  Sort
+	After *Position // nil for the first page
+}
+
+// the positions of the pages on either side of a page, nil where there are
+// none
+type Edges struct {
+	Next *Position
+	Prev *Position
+}
+
+func (p Page) backward() bool {
+	return p.After != nil && p.After.Backward
+}
+
+// rows are fetched walking away from the position, so a backward page is
+// fetched against the sort and turned around by Cut
+func (p Page) descending() bool {
+	return p.Sort.Desc != p.backward()
+}
+
+// rows to fetch for the page: one more than it holds, to tell whether more
+// follow
+func (p Page) Fetch() int {
+	return p.Limit + 1
+}
+
+// the ORDER BY expression to fetch the page's rows in
+func (p Page) OrderBy(column, idColumn string) string {
+	dir := "ASC"
+	if p.descending() {
+		dir = "DESC"
+	}
+
+	return fmt.Sprintf("%s %s, %s %s", column, dir, idColumn, dir)
+}
+
+// the condition for rows past the page's position, with its arguments from
+// $arg on. cursor values are strings, cast to the column types. empty for the
+// first page
+func (p Page) Where(column, columnType, idColumn, idType string, arg int) (string, []any) {
+	if p.After == nil {
+		return "", nil
+	}
+
+	op := ">"
+	if p.descending() {
+		op = "<"
+	}
+
+	condition := fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d::%s)", column, idColumn, op, arg, columnType, arg+1, idType)
+	return condition, []any{p.After.Value, p.After.ID}
+}
+
+// what Where, OrderBy and Fetch select, for lists held in memory: up to
+// Fetch items past the position, in fetch order
+func Select[T any](p Page, items []T, key func(T) Key) []T {
+	desc := p.descending()
+
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		if desc {
+			return compare(key(b), key(a))
+		}
+		return compare(key(a), key(b))
+	})
+
+	if p.After != nil {
+		sorted = slices.DeleteFunc(sorted, func(item T) bool {
+			c := compare(key(item), p.After.Key)
+			if desc {
+				return c >= 0
+			}
+			return c <= 0
+		})
+	}
+
+	return sorted[:min(len(sorted), p.Fetch())]
+}
+
+func compare(a, b Key) int {
+	return cmp.Or(strings.Compare(a.Value, b.Value), strings.Compare(a.ID, b.ID))
+}
+
+// turns the rows fetched for a page into the page, in sort order, and finds
+// the positions of the pages around it
+func Cut[T any](p Page, rows []T, key func(T) Key) ([]T, Edges) {
+	more := len(rows) > p.Limit
+	if more {
+		rows = rows[:p.Limit]
+	}
+
+	// a page is only reached backward from a page after it
+	hasNext, hasPrev := more, p.After != nil
+	if p.backward() {
+		rows = slices.Clone(rows)
+		slices.Reverse(rows)
+		hasNext, hasPrev = true, more
+	}
+
+	var edges Edges
+	if len(rows) == 0 {
+		return rows, edges
+	}
+
+	sort := p.Sort.String()
+	if hasNext {
+		edges.Next = &Position{Sort: sort, Key: key(rows[len(rows)-1])}
+	}
+	if hasPrev {
+		edges.Prev = &Position{Sort: sort, Key: key(rows[0]), Backward: true}
+	}
+
+	return rows, edges
+}
+
+// formats t as a sort value. the width is fixed and the zone UTC, so values
+// compare as strings the way the times do, at the microseconds postgres keeps
+func Time(t time.Time) string {
+	return t.UTC().Round(time.Microsecond).Format("2006-01-02T15:04:05.000000Z")
+}
@@ -0,0 +1,145 @@
+package keyset
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type row struct {
+	id    string
+	value string
+}
+
+func rowKey(r row) Key {
+	return Key{Value: r.value, ID: r.id}
+}
+
+// rows 0-9, with values tied in pairs so ids break the ties
+func testRows() []row {
+	rows := make([]row, 10)
+	for i := range rows {
+		rows[i] = row{id: strconv.Itoa(i), value: strconv.Itoa(i / 2)}
+	}
+
+	return rows
+}
+
+func ids(rows []row) []string {
+	out := make([]string, len(rows))
+	for i, r := range rows {
+		out[i] = r.id
+	}
+
+	return out
+}
+
+func page(p Page, rows []row) ([]row, Edges) {
+	return Cut(p, Select(p, rows, rowKey), rowKey)
+}
+
+func TestParseSort(t *testing.T) {
+	for _, s := range []string{"created_at", "-created_at"} {
+		if got := ParseSort(s).String(); got != s {
+			t.Errorf("ParseSort(%q).String() = %q", s, got)
+		}
+	}
+
+	if sort := ParseSort("-title"); sort.Field != "title" || !sort.Desc {
+		t.Errorf("ParseSort(-title) = %+v, want title descending", sort)
+	}
+}
+
+func TestPagesForwardAndBack(t *testing.T) {
+	rows := testRows()
+	p := Page{Limit: 4, Sort: Sort{Field: "v", Desc: true}}
+
+	var walked [][]string
+	var last Edges
+	for {
+		got, edges := page(p, rows)
+		walked = append(walked, ids(got))
+		last = edges
+		if edges.Next == nil {
+			break
+		}
+		p.After = edges.Next
+	}
+
+	want := [][]string{{"9", "8", "7", "6"}, {"5", "4", "3", "2"}, {"1", "0"}}
+	if !slices.EqualFunc(walked, want, slices.Equal) {
+		t.Fatalf("forward pages = %v, want %v", walked, want)
+	}
+
+	// and back from the last page
+	p.After = last.Prev
+	got, edges := page(p, rows)
+	if !slices.Equal(ids(got), want[1]) {
+		t.Errorf("page before the last = %v, want %v", ids(got), want[1])
+	}
+	if edges.Next == nil || edges.Prev == nil {
+		t.Fatalf("middle page edges = %+v, want both", edges)
+	}
+
+	p.After = edges.Prev
+	got, edges = page(p, rows)
+	if !slices.Equal(ids(got), want[0]) {
+		t.Errorf("first page walking back = %v, want %v", ids(got), want[0])
+	}
+	if edges.Prev != nil {
+		t.Errorf("first page has a previous page at %+v", edges.Prev)
+	}
+}
+
+func TestFirstPageHasNoPrev(t *testing.T) {
+	got, edges := page(Page{Limit: 20, Sort: Sort{Field: "v"}}, testRows())
+
+	if len(got) != 10 || edges.Next != nil || edges.Prev != nil {
+		t.Errorf("single page = %d rows, edges %+v, want 10 rows and none", len(got), edges)
+	}
+}
+
+func TestWhereAndOrderBy(t *testing.T) {
+	p := Page{Limit: 20, Sort: Sort{Field: "created_at", Desc: true}}
+
+	if where, args := p.Where("created_at", "timestamptz", "id", "uuid", 3); where != "" || args != nil {
+		t.Errorf("first page Where() = %q, %v, want none", where, args)
+	}
+
+	p.After = &Position{Sort: "-created_at", Key: Key{Value: "2026-01-01T00:00:00.000000Z", ID: "abc"}}
+	where, args := p.Where("created_at", "timestamptz", "id", "uuid", 3)
+	if want := "(created_at, id) < ($3::timestamptz, $4::uuid)"; where != want {
+		t.Errorf("Where() = %q, want %q", where, want)
+	}
+	if len(args) != 2 || args[0] != p.After.Value || args[1] != p.After.ID {
+		t.Errorf("Where() args = %v", args)
+	}
+	if got := p.OrderBy("created_at", "id"); got != "created_at DESC, id DESC" {
+		t.Errorf("OrderBy() = %q", got)
+	}
+
+	// a backward page is fetched against the sort
+	p.After.Backward = true
+	if where, _ := p.Where("created_at", "timestamptz", "id", "uuid", 1); where != "(created_at, id) > ($1::timestamptz, $2::uuid)" {
+		t.Errorf("backward Where() = %q", where)
+	}
+	if got := p.OrderBy("created_at", "id"); got != "created_at ASC, id ASC" {
+		t.Errorf("backward OrderBy() = %q", got)
+	}
+}
+
+func TestTimeComparesAsString(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	times := []time.Time{base, base.Add(time.Microsecond), base.Add(90 * time.Millisecond), base.Add(time.Hour)}
+
+	for i := 1; i < len(times); i++ {
+		if a, b := Time(times[i-1]), Time(times[i]); a >= b {
+			t.Errorf("Time(%v) = %q sorts after Time(%v) = %q", times[i-1], a, times[i], b)
+		}
+	}
+
+	if got := Time(base); got != "2026-03-01T11:00:00.000000Z" {
+		t.Errorf("Time() = %q, want UTC with microseconds", got)
+	}
+}
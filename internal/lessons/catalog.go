@@ -0,0 +1,184 @@
+package lessons
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"codeberg.org/algopatterns/server/internal/strudel"
+)
+
+var (
+	frontmatterPattern = regexp.MustCompile(`(?s)^---\n(.*?)\n---\n`)
+	titlePattern       = regexp.MustCompile(`(?m)^title:\s*(.+)$`)
+	slugPattern        = regexp.MustCompile(`[^a-z0-9]+`)
+	lineCommentPattern = regexp.MustCompile(`//.*`)
+
+	// scale("C:minor:pentatonic") → minor:pentatonic
+	scaleModePattern = regexp.MustCompile("scale\\s*\\(\\s*[\"'`][A-Ga-g][#b]?\\d*:([\\w:]+)[\"'`]")
+)
+
+// directory of the teaching concept files, read from LESSONS_PATH
+func ConceptsPathFromEnv() string {
+	if path := os.Getenv("LESSONS_PATH"); path != "" {
+		return path
+	}
+
+	return defaultConceptsPath
+}
+
+// builds the lessons of every .mdx concept file in dir, in file name order
+func LoadLessons(dir string) ([]*Lesson, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.mdx"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var lessons []*Lesson
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		lessons = append(lessons, ParseConcept(string(content))...)
+	}
+
+	return lessons, nil
+}
+
+// text and code under a heading
+type block struct {
+	title string
+	prose []string
+	code  []string
+}
+
+// builds a lesson from each top-level heading of a concept file, with a step
+// for each subheading. a heading without subheadings is a one-step lesson
+func ParseConcept(content string) []*Lesson {
+	concept := ""
+	if frontmatter := frontmatterPattern.FindStringSubmatch(content); frontmatter != nil {
+		if title := titlePattern.FindStringSubmatch(frontmatter[1]); title != nil {
+			concept = strings.TrimSpace(title[1])
+		}
+		content = content[len(frontmatter[0]):]
+	}
+
+	var lessons []*Lesson
+	var intro *block
+	var steps []*block
+	var current *block
+	inFence := false
+
+	finish := func() {
+		if intro != nil {
+			lessons = append(lessons, buildLesson(concept, intro, steps))
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inFence = !inFence
+			continue
+		case inFence:
+			if current != nil {
+				current.code = append(current.code, line)
+			}
+			continue
+		case strings.HasPrefix(trimmed, "## "):
+			if intro != nil {
+				current = &block{title: strings.TrimSpace(trimmed[3:])}
+				steps = append(steps, current)
+			}
+			continue
+		case strings.HasPrefix(trimmed, "# "):
+			finish()
+			intro = &block{title: strings.TrimSpace(trimmed[2:])}
+			steps = nil
+			current = intro
+			continue
+		}
+
+		if current != nil {
+			current.prose = append(current.prose, line)
+		}
+	}
+	finish()
+
+	return lessons
+}
+
+func buildLesson(concept string, intro *block, steps []*block) *Lesson {
+	lesson := &Lesson{
+		ID:      slug(intro.title),
+		Title:   intro.title,
+		Concept: concept,
+		Summary: firstParagraph(intro.prose),
+	}
+
+	if len(steps) == 0 {
+		steps = []*block{intro}
+	}
+
+	for _, b := range steps {
+		example := strings.TrimSpace(strings.Join(b.code, "\n"))
+		lesson.Steps = append(lesson.Steps, Step{
+			Title:       b.title,
+			Explanation: strings.TrimSpace(strings.Join(b.prose, "\n")),
+			Example:     example,
+			Criteria:    criteriaOf(example),
+		})
+	}
+
+	return lesson
+}
+
+// what code has to do to match an example: call the same methods and use
+// the same scale modes
+func criteriaOf(example string) Criteria {
+	code := lineCommentPattern.ReplaceAllString(example, "")
+
+	var criteria Criteria
+	for _, function := range strudel.ExtractFunctions(code) {
+		if !slices.Contains(criteria.Functions, function) {
+			criteria.Functions = append(criteria.Functions, function)
+		}
+	}
+
+	for _, match := range scaleModePattern.FindAllStringSubmatch(code, -1) {
+		if !slices.Contains(criteria.Scales, match[1]) {
+			criteria.Scales = append(criteria.Scales, match[1])
+		}
+	}
+
+	return criteria
+}
+
+func firstParagraph(lines []string) string {
+	var paragraph []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+
+	return strings.Join(paragraph, " ")
+}
+
+func slug(title string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
@@ -0,0 +1,46 @@
+package lessons
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"codeberg.org/algopatterns/server/internal/strudel"
+)
+
+// checks code against the step: it has to lint without errors and meet the
+// step's criteria
+func (s *Step) Check(code string) StepCheck {
+	if strings.TrimSpace(code) == "" {
+		return StepCheck{Missing: []string{"write some code"}}
+	}
+
+	var missing []string
+
+	for _, d := range strudel.Lint(code) {
+		if d.Severity == strudel.SeverityError {
+			missing = append(missing, fmt.Sprintf("fix line %d: %s", d.Line, d.Message))
+		}
+	}
+
+	uncommented := lineCommentPattern.ReplaceAllString(code, "")
+
+	functions := strudel.ExtractFunctions(uncommented)
+	for _, function := range s.Criteria.Functions {
+		if !slices.Contains(functions, function) {
+			missing = append(missing, fmt.Sprintf("call .%s()", function))
+		}
+	}
+
+	var scales []string
+	for _, match := range scaleModePattern.FindAllStringSubmatch(uncommented, -1) {
+		scales = append(scales, match[1])
+	}
+	for _, scale := range s.Criteria.Scales {
+		if !slices.Contains(scales, scale) {
+			missing = append(missing, fmt.Sprintf("use the %s scale", strings.ReplaceAll(scale, ":", " ")))
+		}
+	}
+
+	return StepCheck{Passed: len(missing) == 0, Missing: missing}
+}
@@ -0,0 +1,145 @@
+package lessons
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lessons whose id is taken get the concept prefixed to it
+func New(db *pgxpool.Pool, lessons []*Lesson) *Service {
+	s := &Service{db: db, byID: make(map[string]*Lesson, len(lessons))}
+
+	for _, lesson := range lessons {
+		if _, taken := s.byID[lesson.ID]; taken {
+			lesson.ID = slug(lesson.Concept + " " + lesson.Title)
+		}
+
+		s.lessons = append(s.lessons, lesson)
+		s.byID[lesson.ID] = lesson
+	}
+
+	return s
+}
+
+// lists the lessons, in the order of the concept files
+func (s *Service) List() []LessonSummary {
+	summaries := make([]LessonSummary, 0, len(s.lessons))
+	for _, lesson := range s.lessons {
+		summaries = append(summaries, LessonSummary{
+			ID:        lesson.ID,
+			Title:     lesson.Title,
+			Concept:   lesson.Concept,
+			Summary:   lesson.Summary,
+			StepCount: len(lesson.Steps),
+		})
+	}
+
+	return summaries
+}
+
+func (s *Service) Get(lessonID string) (*Lesson, error) {
+	lesson, ok := s.byID[lessonID]
+	if !ok {
+		return nil, ErrLessonNotFound
+	}
+
+	return lesson, nil
+}
+
+// starts a lesson in a session from its first step, replacing the lesson in
+// progress. userID may be empty
+func (s *Service) Start(ctx context.Context, sessionID, lessonID, userID string) (*Progress, error) {
+	if _, err := s.Get(lessonID); err != nil {
+		return nil, err
+	}
+
+	var startedBy *string
+	if userID != "" {
+		startedBy = &userID
+	}
+
+	return s.scanProgress(s.db.QueryRow(ctx, queryStartLesson, sessionID, lessonID, startedBy))
+}
+
+// gets the session's lesson progress
+func (s *Service) Progress(ctx context.Context, sessionID string) (*Progress, error) {
+	progress, err := s.scanProgress(s.db.QueryRow(ctx, queryGetProgress, sessionID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoLesson
+	}
+
+	return progress, err
+}
+
+// checks code against the session's current step and moves on to the next
+// step when it passes
+func (s *Service) Check(ctx context.Context, sessionID, code string) (*CheckResult, error) {
+	progress, err := s.Progress(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if progress.Current == nil {
+		return nil, ErrCompleted
+	}
+
+	result := &CheckResult{StepCheck: progress.Current.Check(code), Progress: progress}
+	if !result.Passed {
+		return result, nil
+	}
+
+	advanced, err := s.scanProgress(s.db.QueryRow(ctx, queryAdvanceStep, sessionID, progress.Step, progress.StepCount))
+	if errors.Is(err, pgx.ErrNoRows) {
+		// a concurrent check advanced it first
+		result.Progress, err = s.Progress(ctx, sessionID)
+		return result, err
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.Advanced = true
+	result.Progress = advanced
+
+	return result, nil
+}
+
+// ends the session's lesson
+func (s *Service) Stop(ctx context.Context, sessionID string) error {
+	tag, err := s.db.Exec(ctx, queryStopLesson, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNoLesson
+	}
+
+	return nil
+}
+
+// scans a session_lessons row and attaches the lesson's current step
+func (s *Service) scanProgress(row pgx.Row) (*Progress, error) {
+	var p Progress
+
+	err := row.Scan(&p.SessionID, &p.LessonID, &p.Step, &p.StartedBy, &p.StartedAt, &p.UpdatedAt, &p.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := s.Get(p.LessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.StepCount = len(lesson.Steps)
+	if p.CompletedAt == nil && p.Step < p.StepCount {
+		p.Current = &lesson.Steps[p.Step]
+	}
+
+	return &p, nil
+}
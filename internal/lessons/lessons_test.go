@@ -0,0 +1,125 @@
+package lessons
+
+import (
+	"reflect"
+	"testing"
+)
+
+const concept = "---\ntitle: Music Theory\n---\n\n" +
+	"# Scales\n\nOrdered sets of notes.\nThey define a key.\n\nMore detail.\n\n" +
+	"## Minor Scale\nPattern: W-H-W-W-H-W-W\n```javascript\nn(\"0 2 3 5\").scale(\"C:minor\") // natural minor\n```\n\n" +
+	"## Pentatonic\n5-note scales.\n```javascript\nn(\"0 2 4 7 9\").scale(\"C:major:pentatonic\").slow(2)\n```\n\n" +
+	"# Time Signatures\n\nBeats per measure.\n```javascript\ns(\"bd sd sd\").cpm(0.5)\n```\n"
+
+func TestParseConcept(t *testing.T) {
+	lessons := ParseConcept(concept)
+
+	if len(lessons) != 2 {
+		t.Fatalf("ParseConcept() returned %d lessons, expected 2", len(lessons))
+	}
+
+	scales := lessons[0]
+	if scales.ID != "scales" || scales.Concept != "Music Theory" || scales.Summary != "Ordered sets of notes. They define a key." {
+		t.Errorf("unexpected lesson: %+v", scales)
+	}
+
+	expected := []Step{
+		{
+			Title:       "Minor Scale",
+			Explanation: "Pattern: W-H-W-W-H-W-W",
+			Example:     "n(\"0 2 3 5\").scale(\"C:minor\") // natural minor",
+			Criteria:    Criteria{Functions: []string{"scale"}, Scales: []string{"minor"}},
+		},
+		{
+			Title:       "Pentatonic",
+			Explanation: "5-note scales.",
+			Example:     "n(\"0 2 4 7 9\").scale(\"C:major:pentatonic\").slow(2)",
+			Criteria:    Criteria{Functions: []string{"scale", "slow"}, Scales: []string{"major:pentatonic"}},
+		},
+	}
+	if !reflect.DeepEqual(scales.Steps, expected) {
+		t.Errorf("steps = %+v, expected %+v", scales.Steps, expected)
+	}
+
+	// a heading without subheadings is a single step
+	signatures := lessons[1]
+	if len(signatures.Steps) != 1 || signatures.Steps[0].Title != "Time Signatures" || !reflect.DeepEqual(signatures.Steps[0].Criteria.Functions, []string{"cpm"}) {
+		t.Errorf("unexpected lesson: %+v", signatures)
+	}
+}
+
+func TestLoadLessonsFromConcepts(t *testing.T) {
+	lessons, err := LoadLessons("../../docs/concepts")
+	if err != nil {
+		t.Fatalf("LoadLessons() error = %v", err)
+	}
+
+	if len(lessons) == 0 {
+		t.Fatal("expected lessons from the concept files")
+	}
+
+	for _, lesson := range lessons {
+		if lesson.ID == "" || len(lesson.Steps) == 0 {
+			t.Errorf("lesson without id or steps: %+v", lesson)
+		}
+	}
+}
+
+func TestNewDisambiguatesIDs(t *testing.T) {
+	s := New(nil, []*Lesson{
+		{ID: "ambient", Title: "Ambient", Concept: "Music Genres"},
+		{ID: "ambient", Title: "Ambient", Concept: "Sound Design"},
+	})
+
+	if _, err := s.Get("ambient"); err != nil {
+		t.Errorf("Get(ambient) error = %v", err)
+	}
+
+	if _, err := s.Get("sound-design-ambient"); err != nil {
+		t.Errorf("Get(sound-design-ambient) error = %v", err)
+	}
+}
+
+func TestStepCheck(t *testing.T) {
+	step := Step{Criteria: Criteria{Functions: []string{"scale"}, Scales: []string{"minor:pentatonic"}}}
+
+	tests := []struct {
+		name     string
+		code     string
+		expected StepCheck
+	}{
+		{
+			name:     "meets the criteria",
+			code:     "n(\"0 3 5 7\").scale(\"A:minor:pentatonic\")",
+			expected: StepCheck{Passed: true},
+		},
+		{
+			name:     "empty code",
+			code:     "  ",
+			expected: StepCheck{Missing: []string{"write some code"}},
+		},
+		{
+			name:     "wrong scale",
+			code:     "n(\"0 2 4\").scale(\"C:major\")",
+			expected: StepCheck{Missing: []string{"use the minor pentatonic scale"}},
+		},
+		{
+			name:     "commented out calls don't count",
+			code:     "n(\"0 3 5\") // .scale(\"A:minor:pentatonic\")",
+			expected: StepCheck{Missing: []string{"call .scale()", "use the minor pentatonic scale"}},
+		},
+		{
+			name:     "code that doesn't parse",
+			code:     "n(\"0 3 5\").scale(\"A:minor:pentatonic\"",
+			expected: StepCheck{Missing: []string{"fix line 1: unclosed '('"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if check := step.Check(tt.code); !reflect.DeepEqual(check, tt.expected) {
+				t.Errorf("Check() = %+v, expected %+v", check, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package lessons
+
+const (
+	progressColumns = `session_id, lesson_id, step, started_by, started_at, updated_at, completed_at`
+
+	// starting a lesson replaces the one in progress
+	queryStartLesson = `
+		INSERT INTO session_lessons (session_id, lesson_id, started_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (session_id)
+		DO UPDATE SET lesson_id = EXCLUDED.lesson_id, step = 0, started_by = EXCLUDED.started_by,
+			started_at = NOW(), updated_at = NOW(), completed_at = NULL
+		RETURNING ` + progressColumns
+
+	queryGetProgress = `
+		SELECT ` + progressColumns + `
+		FROM session_lessons
+		WHERE session_id = $1
+	`
+
+	// only advances from the step that was checked, so concurrent checks
+	// don't skip a step
+	queryAdvanceStep = `
+		UPDATE session_lessons
+		SET step = step + 1, updated_at = NOW(),
+			completed_at = CASE WHEN step + 1 >= $3 THEN NOW() END
+		WHERE session_id = $1 AND step = $2 AND completed_at IS NULL
+		RETURNING ` + progressColumns
+
+	queryStopLesson = `
+		DELETE FROM session_lessons WHERE session_id = $1
+	`
+)
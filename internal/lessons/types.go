@@ -0,0 +1,83 @@
+package lessons
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// where the teaching concepts live unless LESSONS_PATH says otherwise
+const defaultConceptsPath = "docs/concepts"
+
+// errors
+var (
+	ErrLessonNotFound = errors.New("lesson not found")
+	ErrNoLesson       = errors.New("no lesson in progress")
+	ErrCompleted      = errors.New("lesson already completed")
+)
+
+type Service struct {
+	db      *pgxpool.Pool
+	lessons []*Lesson
+	byID    map[string]*Lesson
+}
+
+// a guided lesson, built from one heading of a teaching concept file
+type Lesson struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Concept string `json:"concept"` // title of the concept file, e.g. "Music Theory"
+	Summary string `json:"summary,omitempty"`
+	Steps   []Step `json:"steps"`
+}
+
+// a lesson listed without its steps
+type LessonSummary struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Concept   string `json:"concept"`
+	Summary   string `json:"summary,omitempty"`
+	StepCount int    `json:"step_count"`
+}
+
+// one thing to learn and try out
+type Step struct {
+	Title       string   `json:"title"`
+	Explanation string   `json:"explanation"`
+	Example     string   `json:"example,omitempty"`
+	Criteria    Criteria `json:"criteria"`
+}
+
+// what the code has to do to finish a step, read off the step's example.
+// it must also run without lint errors
+type Criteria struct {
+	Functions []string `json:"functions,omitempty"` // methods the code must call, like scale
+	Scales    []string `json:"scales,omitempty"`    // scale modes the code must use, like minor:pentatonic
+}
+
+// result of checking code against a step
+type StepCheck struct {
+	Passed  bool     `json:"passed"`
+	Missing []string `json:"missing,omitempty"` // unmet criteria, readable
+}
+
+// a session's progress through a lesson
+type Progress struct {
+	SessionID   string     `json:"session_id"`
+	LessonID    string     `json:"lesson_id"`
+	Step        int        `json:"step"` // index of the current step, the step count once completed
+	StepCount   int        `json:"step_count"`
+	Current     *Step      `json:"current,omitempty"` // nil once completed
+	StartedBy   *string    `json:"started_by,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// result of checking a session's code against its current step
+type CheckResult struct {
+	StepCheck
+	Advanced bool      `json:"advanced"` // the step passed and the lesson moved on
+	Progress *Progress `json:"progress"`
+}
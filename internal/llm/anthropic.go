@@ -2,16 +2,16 @@ package llm
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"codeberg.org/algopatterns/server/internal/tracing"
 )
 
 const (
@@ -24,12 +24,12 @@ const (
 // shared HTTP client for Anthropic API calls
 var anthropicHTTPClient = &http.Client{
 	Timeout: 60 * time.Second,
-	Transport: &http.Transport{
+	Transport: tracing.NewTransport(&http.Transport{
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 10,
 		IdleConnTimeout:     90 * time.Second,
 		TLSHandshakeTimeout: 10 * time.Second,
-	},
+	}),
 }
 
 // rate limiter for Anthropic API calls (50 requests/second with burst capacity of 10)
@@ -92,11 +92,12 @@ type AnthropicConfig struct {
 	Model       string  // e.g., "claude-3-haiku-20240307"
 	MaxTokens   int     // max tokens for response
 	Temperature float32 // 0.0 to 1.0
+	Retry       RetryPolicy
 }
 
 type AnthropicTransformer struct {
-	config     AnthropicConfig
-	httpClient *http.Client
+	config    AnthropicConfig
+	requester *requester
 }
 
 func NewAnthropicTransformer(config AnthropicConfig) *AnthropicTransformer {
@@ -109,8 +110,8 @@ func NewAnthropicTransformer(config AnthropicConfig) *AnthropicTransformer {
 	}
 
 	return &AnthropicTransformer{
-		config:     config,
-		httpClient: anthropicHTTPClient,
+		config:    config,
+		requester: newRequester(ProviderAnthropic, anthropicHTTPClient, anthropicRateLimiter, config.Retry),
 	}
 }
 
@@ -132,37 +133,13 @@ func (t *AnthropicTransformer) AnalyzeQuery(ctx context.Context, userQuery strin
 		},
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", anthropicMessagesURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", t.config.APIKey)
-	req.Header.Set("anthropic-version", anthropicVersion)
-
-	// rate limiting
-	if err := anthropicRateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
-	}
-
-	resp, err := t.httpClient.Do(req)
+	resp, err := t.post(ctx, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
 
 	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body) //nolint:errcheck
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var transformResp transformResponse
 	if err := json.NewDecoder(resp.Body).Decode(&transformResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
@@ -213,35 +190,13 @@ func (t *AnthropicTransformer) GenerateTextStream(ctx context.Context, req TextG
 		Stream:      true,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	resp, err := t.post(ctx, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", anthropicMessagesURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-api-key", t.config.APIKey)
-	httpReq.Header.Set("anthropic-version", anthropicVersion)
-
-	if err := anthropicRateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
+		return nil, err
 	}
 
-	resp, err := t.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
 	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body) //nolint:errcheck
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var fullText strings.Builder
 	var usage Usage
 
@@ -319,37 +274,13 @@ func (t *AnthropicTransformer) GenerateText(ctx context.Context, req TextGenerat
 		Messages:    messages,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", anthropicMessagesURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-api-key", t.config.APIKey)
-	httpReq.Header.Set("anthropic-version", anthropicVersion)
-
-	// rate limiting
-	if err := anthropicRateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
-	}
-
-	resp, err := t.httpClient.Do(httpReq)
+	resp, err := t.post(ctx, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
 
 	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body) //nolint:errcheck
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var apiResp transformResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
@@ -368,6 +299,16 @@ func (t *AnthropicTransformer) GenerateText(ctx context.Context, req TextGenerat
 	}, nil
 }
 
+// sends a messages request, retrying transient failures. the caller closes
+// the body
+func (t *AnthropicTransformer) post(ctx context.Context, reqBody transformRequest) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("x-api-key", t.config.APIKey)
+	header.Set("anthropic-version", anthropicVersion)
+
+	return t.requester.post(ctx, anthropicMessagesURL, reqBody, header)
+}
+
 // returns system prompt for query transformation
 func buildTransformationPrompt() string {
 	const prompt = `You are a query analyzer for Strudel music code generation.
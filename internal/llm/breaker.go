@@ -0,0 +1,79 @@
+package llm
+
+import "time"
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{health: 1, now: time.Now}
+}
+
+// reports whether a call may go to the provider. once the cooldown of an open
+// circuit is over, one trial call is let through at a time
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+
+	if b.probing || b.now().Sub(b.openedAt) < breakerCooldown {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+// records a successful call, closing the circuit after a trial call
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.open {
+		b.open = false
+		b.probing = false
+		b.health = breakerRecoveredHealth
+		return
+	}
+
+	b.health = b.health*(1-breakerDecay) + breakerDecay
+}
+
+// records a failed call, opening the circuit once health drops too low or
+// when a trial call fails
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.health *= 1 - breakerDecay
+
+	if b.open || b.health < breakerOpenBelow {
+		b.open = true
+		b.probing = false
+		b.openedAt = b.now()
+	}
+}
+
+// forgets a call that says nothing about the provider, like one the caller
+// cancelled, so a trial call can be made again
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// returns the circuit's state and health
+func (b *circuitBreaker) state() (string, float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case !b.open:
+		return CircuitClosed, b.health
+	case b.probing || b.now().Sub(b.openedAt) >= breakerCooldown:
+		return CircuitHalfOpen, b.health
+	default:
+		return CircuitOpen, b.health
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"codeberg.org/algopatterns/server/internal/config"
 )
@@ -22,8 +23,9 @@ func loadConfig() (*Config, error) {
 
 	transformerAPIKey := getAPIKeyForProvider(transformerProvider, baseConfig)
 
+	// ollama models default in the client
 	transformerModel := os.Getenv("TRANSFORMER_MODEL")
-	if transformerModel == "" {
+	if transformerModel == "" && transformerProvider != ProviderOllama {
 		transformerModel = "claude-3-haiku-20240307" // default
 	}
 
@@ -36,7 +38,7 @@ func loadConfig() (*Config, error) {
 	generatorAPIKey := getAPIKeyForProvider(generatorProvider, baseConfig)
 
 	generatorModel := os.Getenv("GENERATOR_MODEL")
-	if generatorModel == "" {
+	if generatorModel == "" && generatorProvider != ProviderOllama {
 		generatorModel = "claude-sonnet-4-20250514" // default
 	}
 
@@ -50,10 +52,25 @@ func loadConfig() (*Config, error) {
 	embedderAPIKey := baseConfig.OpenAIKey
 
 	embedderModel := os.Getenv("EMBEDDER_MODEL")
-	if embedderModel == "" {
+	if embedderModel == "" && embedderProvider != ProviderOllama {
 		embedderModel = "text-embedding-3-small" // default
 	}
 
+	// fallback providers, used while the primary ones are failing
+	transformerFallbackProvider := Provider(os.Getenv("TRANSFORMER_FALLBACK_PROVIDER"))
+	transformerFallbackModel := os.Getenv("TRANSFORMER_FALLBACK_MODEL")
+	if transformerFallbackModel == "" {
+		transformerFallbackModel = fallbackTransformerModels[transformerFallbackProvider]
+	}
+
+	generatorFallbackProvider := Provider(os.Getenv("GENERATOR_FALLBACK_PROVIDER"))
+	generatorFallbackModel := os.Getenv("GENERATOR_FALLBACK_MODEL")
+	if generatorFallbackModel == "" {
+		generatorFallbackModel = fallbackGeneratorModels[generatorFallbackProvider]
+	}
+
+	embedderFallbackProvider := Provider(os.Getenv("EMBEDDER_FALLBACK_PROVIDER"))
+
 	// optional transformer parameters
 	transformerMaxTokens := 200 // default
 	if maxTokensStr := os.Getenv("TRANSFORMER_MAX_TOKENS"); maxTokensStr != "" {
@@ -84,6 +101,24 @@ func loadConfig() (*Config, error) {
 		}
 	}
 
+	// retries of transient API failures, unset values use the defaults
+	retry := RetryPolicy{HedgeDelay: defaultHedgeDelay}
+	if val, err := strconv.Atoi(os.Getenv("LLM_RETRY_MAX_ATTEMPTS")); err == nil {
+		retry.MaxAttempts = val
+	}
+
+	if val, err := time.ParseDuration(os.Getenv("LLM_RETRY_BASE_DELAY")); err == nil {
+		retry.BaseDelay = val
+	}
+
+	if val, err := time.ParseDuration(os.Getenv("LLM_RETRY_MAX_DELAY")); err == nil {
+		retry.MaxDelay = val
+	}
+
+	if val, err := time.ParseDuration(os.Getenv("EMBEDDER_HEDGE_DELAY")); err == nil {
+		retry.HedgeDelay = val
+	}
+
 	return &Config{
 		TransformerProvider:    transformerProvider,
 		TransformerAPIKey:      transformerAPIKey,
@@ -98,5 +133,38 @@ func loadConfig() (*Config, error) {
 		EmbedderProvider:       embedderProvider,
 		EmbedderAPIKey:         embedderAPIKey,
 		EmbedderModel:          embedderModel,
+		OllamaBaseURL:          os.Getenv("OLLAMA_BASE_URL"),
+
+		TransformerFallbackProvider: transformerFallbackProvider,
+		TransformerFallbackAPIKey:   getAPIKeyForProvider(transformerFallbackProvider, baseConfig),
+		TransformerFallbackModel:    transformerFallbackModel,
+		GeneratorFallbackProvider:   generatorFallbackProvider,
+		GeneratorFallbackAPIKey:     getAPIKeyForProvider(generatorFallbackProvider, baseConfig),
+		GeneratorFallbackModel:      generatorFallbackModel,
+		EmbedderFallbackProvider:    embedderFallbackProvider,
+		EmbedderFallbackAPIKey:      baseConfig.OpenAIKey,
+		OllamaFallbackBaseURL:       os.Getenv("OLLAMA_FALLBACK_BASE_URL"),
+		Retry:                       retry,
 	}, nil
 }
+
+// returns the config with each component's fallback in place of its primary.
+// sampling settings and the embedding model are shared with the primaries
+func (c *Config) fallback() *Config {
+	fallback := *c
+
+	fallback.TransformerProvider = c.TransformerFallbackProvider
+	fallback.TransformerAPIKey = c.TransformerFallbackAPIKey
+	fallback.TransformerModel = c.TransformerFallbackModel
+	fallback.GeneratorProvider = c.GeneratorFallbackProvider
+	fallback.GeneratorAPIKey = c.GeneratorFallbackAPIKey
+	fallback.GeneratorModel = c.GeneratorFallbackModel
+	fallback.EmbedderProvider = c.EmbedderFallbackProvider
+	fallback.EmbedderAPIKey = c.EmbedderFallbackAPIKey
+
+	if c.OllamaFallbackBaseURL != "" {
+		fallback.OllamaBaseURL = c.OllamaFallbackBaseURL
+	}
+
+	return &fallback
+}
@@ -0,0 +1,283 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+// how a call ended, beyond its error
+type attempt struct {
+	callerStopped bool // the caller ended the call, says nothing about the provider
+	delivered     bool // output already reached the caller, too late to fail over
+}
+
+func newCircuit[T any](provider Provider, client T) *circuit[T] {
+	return &circuit[T]{provider: provider, client: client, breaker: newCircuitBreaker()}
+}
+
+// runs call on the primary, or on the fallback while the primary's circuit is
+// open or when the primary fails. returns the circuit that answered
+func (f *failover[T]) do(ctx context.Context, call func(client T, a *attempt) error) (*circuit[T], error) {
+	var err error
+	var a attempt
+
+	if f.primary.breaker.allow() {
+		err = call(f.primary.client, &a)
+		if !f.primary.record(ctx, err, a) || a.delivered {
+			return f.primary, err
+		}
+	} else {
+		err = fmt.Errorf("%s %w", f.primary.provider, ErrCircuitOpen)
+	}
+
+	if f.fallback == nil || !f.fallback.breaker.allow() {
+		return f.primary, err
+	}
+
+	metrics.LLMFailovers.Inc(f.component, string(f.primary.provider))
+	if !errors.Is(err, ErrCircuitOpen) {
+		logger.Warn("llm provider failed, using fallback",
+			"component", f.component,
+			"provider", f.primary.provider,
+			"fallback", f.fallback.provider,
+			"error", err,
+		)
+	}
+
+	a = attempt{}
+	err = call(f.fallback.client, &a)
+	f.fallback.record(ctx, err, a)
+
+	return f.fallback, err
+}
+
+// records the outcome of a call and reports whether the provider failed.
+// only outages count against the provider: a rejected request or key is the
+// caller's problem and would fail on the fallback just the same
+func (c *circuit[T]) record(ctx context.Context, err error, a attempt) bool {
+	switch {
+	case err == nil:
+		c.breaker.success()
+		return false
+	case a.callerStopped || ctx.Err() != nil || !providerFailed(err):
+		c.breaker.abandon()
+		return false
+	default:
+		c.breaker.failure()
+		return true
+	}
+}
+
+func providerFailed(err error) bool {
+	switch KindOf(err) {
+	case ErrorUnavailable, ErrorTimeout, ErrorRateLimited:
+		return true
+	default:
+		return false
+	}
+}
+
+// returns the state of the component's providers
+func (f *failover[T]) circuits() []CircuitStatus {
+	statuses := []CircuitStatus{f.primary.status(f.component, false)}
+	if f.fallback != nil {
+		statuses = append(statuses, f.fallback.status(f.component, true))
+	}
+
+	return statuses
+}
+
+func (c *circuit[T]) status(component string, fallback bool) CircuitStatus {
+	state, health := c.breaker.state()
+
+	return CircuitStatus{
+		Component: component,
+		Provider:  c.provider,
+		Fallback:  fallback,
+		State:     state,
+		Health:    health,
+	}
+}
+
+// describes how the component is degraded, empty while its primary is healthy
+func (f *failover[T]) degradation() string {
+	state, _ := f.primary.breaker.state()
+	if state == CircuitClosed {
+		return ""
+	}
+
+	if f.fallback == nil {
+		return fmt.Sprintf("%s: %s circuit %s, no fallback", f.component, f.primary.provider, state)
+	}
+
+	if fallbackState, _ := f.fallback.breaker.state(); fallbackState == CircuitOpen {
+		return fmt.Sprintf("%s: %s and fallback %s circuits open", f.component, f.primary.provider, f.fallback.provider)
+	}
+
+	return fmt.Sprintf("%s: %s circuit %s, using %s", f.component, f.primary.provider, state, f.fallback.provider)
+}
+
+// marks which model answered and whether it was the fallback
+func answeredBy[T TextGenerator](f *failover[T], answered *circuit[T], resp *TextGenerationResponse) *TextGenerationResponse {
+	if resp.Model == "" {
+		resp.Model = answered.client.Model()
+	}
+	resp.Fallback = answered == f.fallback
+
+	return resp
+}
+
+func generateText[T TextGenerator](ctx context.Context, f *failover[T], req TextGenerationRequest) (*TextGenerationResponse, error) {
+	var resp *TextGenerationResponse
+
+	answered, err := f.do(ctx, func(client T, _ *attempt) error {
+		var err error
+		resp, err = client.GenerateText(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return answeredBy(f, answered, resp), nil
+}
+
+// streams from the primary, failing over only while no chunk was sent yet
+func generateTextStream[T TextGenerator](ctx context.Context, f *failover[T], req TextGenerationRequest, onChunk func(chunk string) error) (*TextGenerationResponse, error) {
+	var resp *TextGenerationResponse
+
+	answered, err := f.do(ctx, func(client T, a *attempt) error {
+		var err error
+		resp, err = client.GenerateTextStream(ctx, req, func(chunk string) error {
+			a.delivered = true
+			if err := onChunk(chunk); err != nil {
+				a.callerStopped = true
+				return err
+			}
+
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return answeredBy(f, answered, resp), nil
+}
+
+func (g *failoverGenerator) GenerateText(ctx context.Context, req TextGenerationRequest) (*TextGenerationResponse, error) {
+	return generateText(ctx, g.failover, req)
+}
+
+func (g *failoverGenerator) GenerateTextStream(ctx context.Context, req TextGenerationRequest, onChunk func(chunk string) error) (*TextGenerationResponse, error) {
+	return generateTextStream(ctx, g.failover, req, onChunk)
+}
+
+// the primary's model; responses name the model that answered
+func (g *failoverGenerator) Model() string {
+	return g.primary.client.Model()
+}
+
+func (t *failoverTransformer) TransformQuery(ctx context.Context, userQuery string) (string, error) {
+	var query string
+
+	_, err := t.do(ctx, func(client transformerClient, _ *attempt) error {
+		var err error
+		query, err = client.TransformQuery(ctx, userQuery)
+		return err
+	})
+
+	return query, err
+}
+
+func (t *failoverTransformer) AnalyzeQuery(ctx context.Context, userQuery string) (*QueryAnalysis, error) {
+	var analysis *QueryAnalysis
+
+	_, err := t.do(ctx, func(client transformerClient, _ *attempt) error {
+		var err error
+		analysis, err = client.AnalyzeQuery(ctx, userQuery)
+		return err
+	})
+
+	return analysis, err
+}
+
+func (t *failoverTransformer) GenerateText(ctx context.Context, req TextGenerationRequest) (*TextGenerationResponse, error) {
+	return generateText(ctx, t.failover, req)
+}
+
+func (t *failoverTransformer) GenerateTextStream(ctx context.Context, req TextGenerationRequest, onChunk func(chunk string) error) (*TextGenerationResponse, error) {
+	return generateTextStream(ctx, t.failover, req, onChunk)
+}
+
+func (t *failoverTransformer) Model() string {
+	return t.primary.client.Model()
+}
+
+func (e *failoverEmbedder) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	var embedding []float32
+
+	_, err := e.do(ctx, func(client Embedder, _ *attempt) error {
+		var err error
+		embedding, err = client.GenerateEmbedding(ctx, text)
+		return err
+	})
+
+	return embedding, err
+}
+
+func (e *failoverEmbedder) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	var embeddings [][]float32
+
+	_, err := e.do(ctx, func(client Embedder, _ *attempt) error {
+		var err error
+		embeddings, err = client.GenerateEmbeddings(ctx, texts)
+		return err
+	})
+
+	return embeddings, err
+}
+
+// returns the circuits of the LLM's components
+func (l *CompositeLLM) Circuits() []CircuitStatus {
+	var statuses []CircuitStatus
+	for _, component := range []any{l.QueryTransformer, l.TextGenerator, l.Embedder} {
+		if f, ok := component.(interface{ circuits() []CircuitStatus }); ok {
+			statuses = append(statuses, f.circuits()...)
+		}
+	}
+
+	return statuses
+}
+
+// returns a readiness check that fails while a component's primary provider
+// is failing, naming what the component does instead
+func CircuitCheck(l LLM) func(ctx context.Context) error {
+	return func(context.Context) error {
+		composite, ok := l.(*CompositeLLM)
+		if !ok {
+			return nil
+		}
+
+		var problems []string
+		for _, component := range []any{composite.QueryTransformer, composite.TextGenerator, composite.Embedder} {
+			if f, ok := component.(interface{ degradation() string }); ok {
+				if problem := f.degradation(); problem != "" {
+					problems = append(problems, problem)
+				}
+			}
+		}
+
+		if len(problems) > 0 {
+			return errors.New(strings.Join(problems, "; "))
+		}
+
+		return nil
+	}
+}
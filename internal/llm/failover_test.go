@@ -0,0 +1,260 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errOutage = &APIError{Provider: ProviderAnthropic, Kind: ErrorUnavailable, StatusCode: 529, Body: "overloaded"}
+
+// generator answering with its model name, or failing while down
+type fakeGenerator struct {
+	model  string
+	down   bool
+	err    error    // returned while down, errOutage if nil
+	chunks []string // streamed before failing when down
+	calls  int
+}
+
+func (g *fakeGenerator) failure() error {
+	if g.err != nil {
+		return g.err
+	}
+
+	return errOutage
+}
+
+func (g *fakeGenerator) GenerateText(_ context.Context, _ TextGenerationRequest) (*TextGenerationResponse, error) {
+	g.calls++
+	if g.down {
+		return nil, g.failure()
+	}
+
+	return &TextGenerationResponse{Text: g.model}, nil
+}
+
+func (g *fakeGenerator) GenerateTextStream(_ context.Context, _ TextGenerationRequest, onChunk func(chunk string) error) (*TextGenerationResponse, error) {
+	g.calls++
+	for _, chunk := range g.chunks {
+		if err := onChunk(chunk); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.down {
+		return nil, g.failure()
+	}
+
+	return &TextGenerationResponse{Text: g.model}, nil
+}
+
+func (g *fakeGenerator) Model() string {
+	return g.model
+}
+
+func newTestFailover(primary, fallback *fakeGenerator) *failoverGenerator {
+	f := &failover[TextGenerator]{component: "generator", primary: newCircuit[TextGenerator](ProviderAnthropic, primary)}
+	if fallback != nil {
+		f.fallback = newCircuit[TextGenerator](ProviderOpenAI, fallback)
+	}
+
+	return &failoverGenerator{f}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker()
+	b.now = func() time.Time { return now }
+
+	// a single failure doesn't open the circuit
+	b.failure()
+	b.success()
+	assert.True(t, b.allow())
+
+	for range 4 {
+		b.failure()
+	}
+
+	state, health := b.state()
+	assert.Equal(t, CircuitOpen, state)
+	assert.Less(t, health, breakerOpenBelow)
+	assert.False(t, b.allow())
+
+	// one trial call after the cooldown
+	now = now.Add(breakerCooldown)
+	assert.True(t, b.allow())
+	assert.False(t, b.allow(), "only one trial call at a time")
+
+	// a failed trial reopens for another cooldown
+	b.failure()
+	assert.False(t, b.allow())
+
+	now = now.Add(breakerCooldown)
+	require.True(t, b.allow())
+	b.success()
+
+	state, health = b.state()
+	assert.Equal(t, CircuitClosed, state)
+	assert.Equal(t, breakerRecoveredHealth, health)
+}
+
+func TestCircuitBreakerAbandonedTrial(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker()
+	b.now = func() time.Time { return now }
+
+	for range 4 {
+		b.failure()
+	}
+
+	now = now.Add(breakerCooldown)
+	require.True(t, b.allow())
+
+	// a cancelled trial doesn't keep the circuit from trying again
+	b.abandon()
+	assert.True(t, b.allow())
+}
+
+func TestFailoverGenerator(t *testing.T) {
+	primary := &fakeGenerator{model: "claude-sonnet-4-20250514", down: true}
+	fallback := &fakeGenerator{model: "gpt-4o"}
+	generator := newTestFailover(primary, fallback)
+
+	resp, err := generator.GenerateText(context.Background(), TextGenerationRequest{})
+	require.NoError(t, err)
+	assert.True(t, resp.Fallback)
+	assert.Equal(t, "gpt-4o", resp.Model)
+	assert.Equal(t, "claude-sonnet-4-20250514", generator.Model())
+
+	// once the circuit opens the primary isn't called anymore
+	for range 5 {
+		_, err := generator.GenerateText(context.Background(), TextGenerationRequest{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 4, primary.calls)
+	assert.Equal(t, 6, fallback.calls)
+
+	statuses := generator.circuits()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, CircuitOpen, statuses[0].State)
+	assert.Equal(t, CircuitClosed, statuses[1].State)
+	assert.Equal(t, "generator: anthropic circuit open, using openai", generator.degradation())
+}
+
+func TestFailoverGeneratorHealthy(t *testing.T) {
+	primary := &fakeGenerator{model: "claude-sonnet-4-20250514"}
+	fallback := &fakeGenerator{model: "gpt-4o"}
+	generator := newTestFailover(primary, fallback)
+
+	resp, err := generator.GenerateText(context.Background(), TextGenerationRequest{})
+	require.NoError(t, err)
+	assert.False(t, resp.Fallback)
+	assert.Equal(t, "claude-sonnet-4-20250514", resp.Model)
+	assert.Zero(t, fallback.calls)
+	assert.Empty(t, generator.degradation())
+}
+
+func TestFailoverWithoutFallbackFailsFast(t *testing.T) {
+	primary := &fakeGenerator{model: "claude-sonnet-4-20250514", down: true}
+	generator := newTestFailover(primary, nil)
+
+	for range 4 {
+		_, err := generator.GenerateText(context.Background(), TextGenerationRequest{})
+		require.ErrorIs(t, err, errOutage)
+	}
+
+	_, err := generator.GenerateText(context.Background(), TextGenerationRequest{})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 4, primary.calls)
+	assert.Equal(t, "generator: anthropic circuit open, no fallback", generator.degradation())
+}
+
+func TestFailoverIgnoresRejectedRequests(t *testing.T) {
+	rejected := &APIError{Provider: ProviderAnthropic, Kind: ErrorInvalidRequest, StatusCode: 400, Body: "prompt is too long"}
+	primary := &fakeGenerator{model: "claude-sonnet-4-20250514", down: true, err: rejected}
+	fallback := &fakeGenerator{model: "gpt-4o"}
+	generator := newTestFailover(primary, fallback)
+
+	for range 5 {
+		_, err := generator.GenerateText(context.Background(), TextGenerationRequest{})
+		require.ErrorIs(t, err, rejected)
+	}
+
+	state, health := generator.primary.breaker.state()
+	assert.Equal(t, CircuitClosed, state)
+	assert.Equal(t, 1.0, health)
+	assert.Zero(t, fallback.calls)
+}
+
+func TestFailoverStream(t *testing.T) {
+	t.Run("fails over before the first chunk", func(t *testing.T) {
+		primary := &fakeGenerator{model: "claude-sonnet-4-20250514", down: true}
+		fallback := &fakeGenerator{model: "gpt-4o", chunks: []string{"s(", "\"bd\")"}}
+		generator := newTestFailover(primary, fallback)
+
+		var streamed []string
+		resp, err := generator.GenerateTextStream(context.Background(), TextGenerationRequest{}, func(chunk string) error {
+			streamed = append(streamed, chunk)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, resp.Fallback)
+		assert.Equal(t, []string{"s(", "\"bd\")"}, streamed)
+	})
+
+	t.Run("doesn't fail over after chunks were sent", func(t *testing.T) {
+		primary := &fakeGenerator{model: "claude-sonnet-4-20250514", down: true, chunks: []string{"s("}}
+		fallback := &fakeGenerator{model: "gpt-4o"}
+		generator := newTestFailover(primary, fallback)
+
+		_, err := generator.GenerateTextStream(context.Background(), TextGenerationRequest{}, func(string) error { return nil })
+		require.ErrorIs(t, err, errOutage)
+		assert.Zero(t, fallback.calls)
+	})
+
+	t.Run("caller errors don't count against the provider", func(t *testing.T) {
+		primary := &fakeGenerator{model: "claude-sonnet-4-20250514", chunks: []string{"s("}}
+		fallback := &fakeGenerator{model: "gpt-4o"}
+		generator := newTestFailover(primary, fallback)
+
+		errGone := errors.New("client disconnected")
+		for range 5 {
+			_, err := generator.GenerateTextStream(context.Background(), TextGenerationRequest{}, func(string) error { return errGone })
+			require.ErrorIs(t, err, errGone)
+		}
+
+		state, health := generator.primary.breaker.state()
+		assert.Equal(t, CircuitClosed, state)
+		assert.Equal(t, 1.0, health)
+		assert.Zero(t, fallback.calls)
+	})
+}
+
+func TestBuildEmbedderRejectsOtherFallbackModel(t *testing.T) {
+	config := &Config{
+		EmbedderProvider:         ProviderOpenAI,
+		EmbedderModel:            "text-embedding-3-small",
+		EmbedderFallbackProvider: ProviderOllama,
+	}
+
+	_, err := buildEmbedder(config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must use the embedder's model openai/text-embedding-3-small")
+
+	config = &Config{
+		EmbedderProvider:         ProviderOllama,
+		EmbedderModel:            "nomic-embed-text",
+		OllamaBaseURL:            "http://ollama-1:11434",
+		EmbedderFallbackProvider: ProviderOllama,
+		OllamaFallbackBaseURL:    "http://ollama-2:11434",
+	}
+
+	embedder, err := buildEmbedder(config)
+	require.NoError(t, err)
+	assert.Len(t, embedder.(*failoverEmbedder).circuits(), 2)
+}
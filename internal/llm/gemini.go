@@ -0,0 +1,217 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/tracing"
+)
+
+const (
+	geminiBaseURL      = "https://generativelanguage.googleapis.com/v1beta/models"
+	defaultGeminiModel = "gemini-2.5-flash"
+)
+
+// shared HTTP client for Gemini API calls
+var geminiHTTPClient = &http.Client{
+	Timeout: 60 * time.Second,
+	Transport: tracing.NewTransport(&http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}),
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent        `json:"contents"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"` // "user" or "model"
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiGenerationConfig struct {
+	MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
+	Temperature     float32 `json:"temperature"`
+}
+
+// also the shape of each streamed event
+type geminiResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata,omitempty"`
+}
+
+type GeminiConfig struct {
+	APIKey      string
+	Model       string  // e.g., "gemini-2.5-flash"
+	MaxTokens   int     // max tokens for response
+	Temperature float32 // 0.0 to 2.0
+	Retry       RetryPolicy
+}
+
+// implements TextGenerator for Google Gemini
+type GeminiGenerator struct {
+	config    GeminiConfig
+	requester *requester
+}
+
+func NewGeminiGenerator(config GeminiConfig) *GeminiGenerator {
+	if config.Model == "" {
+		config.Model = defaultGeminiModel
+	}
+
+	return &GeminiGenerator{
+		config:    config,
+		requester: newRequester(ProviderGemini, geminiHTTPClient, nil, config.Retry),
+	}
+}
+
+func (g *GeminiGenerator) Model() string {
+	return g.config.Model
+}
+
+func (g *GeminiGenerator) GenerateText(ctx context.Context, req TextGenerationRequest) (*TextGenerationResponse, error) {
+	resp, err := g.send(ctx, req, "generateContent")
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	var genResp geminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(genResp.Candidates) == 0 {
+		return nil, fmt.Errorf("no candidates in response")
+	}
+
+	var usage Usage
+	if genResp.UsageMetadata != nil {
+		usage.InputTokens = genResp.UsageMetadata.PromptTokenCount
+		usage.OutputTokens = genResp.UsageMetadata.CandidatesTokenCount
+	}
+
+	return &TextGenerationResponse{
+		Text:  joinGeminiParts(genResp.Candidates[0].Content.Parts),
+		Usage: usage,
+	}, nil
+}
+
+func (g *GeminiGenerator) GenerateTextStream(ctx context.Context, req TextGenerationRequest, onChunk func(chunk string) error) (*TextGenerationResponse, error) {
+	resp, err := g.send(ctx, req, "streamGenerateContent?alt=sse")
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	var fullText strings.Builder
+	var usage Usage
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var event geminiResponse
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+			continue // skip malformed events
+		}
+
+		if len(event.Candidates) > 0 {
+			if text := joinGeminiParts(event.Candidates[0].Content.Parts); text != "" {
+				fullText.WriteString(text)
+				if err := onChunk(text); err != nil {
+					return nil, fmt.Errorf("chunk callback error: %w", err)
+				}
+			}
+		}
+
+		// usage is cumulative, the last event holds the totals
+		if event.UsageMetadata != nil {
+			usage.InputTokens = event.UsageMetadata.PromptTokenCount
+			usage.OutputTokens = event.UsageMetadata.CandidatesTokenCount
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading stream: %w", err)
+	}
+
+	return &TextGenerationResponse{
+		Text:  fullText.String(),
+		Usage: usage,
+	}, nil
+}
+
+// sends a request to the given model method and checks the status, retrying
+// transient failures
+func (g *GeminiGenerator) send(ctx context.Context, req TextGenerationRequest, method string) (*http.Response, error) {
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = g.config.MaxTokens
+	}
+
+	reqBody := geminiRequest{
+		Contents: make([]geminiContent, 0, len(req.Messages)),
+		GenerationConfig: geminiGenerationConfig{
+			MaxOutputTokens: maxTokens,
+			Temperature:     g.config.Temperature,
+		},
+	}
+
+	if req.SystemPrompt != "" {
+		reqBody.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: req.SystemPrompt}}}
+	}
+
+	// gemini calls the assistant "model"
+	for _, msg := range req.Messages {
+		role := msg.Role
+		if role == "assistant" {
+			role = "model"
+		}
+
+		reqBody.Contents = append(reqBody.Contents, geminiContent{
+			Role:  role,
+			Parts: []geminiPart{{Text: msg.Content}},
+		})
+	}
+
+	url := fmt.Sprintf("%s/%s:%s", geminiBaseURL, g.config.Model, method)
+
+	header := http.Header{}
+	header.Set("x-goog-api-key", g.config.APIKey)
+
+	return g.requester.post(ctx, url, reqBody, header)
+}
+
+func joinGeminiParts(parts []geminiPart) string {
+	var text strings.Builder
+	for _, part := range parts {
+		text.WriteString(part.Text)
+	}
+
+	return text.String()
+}
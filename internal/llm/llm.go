@@ -3,6 +3,10 @@ package llm
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/metrics"
+	"codeberg.org/algopatterns/server/internal/tracing"
 )
 
 // creates a new LLM with config from environment variables
@@ -22,59 +26,333 @@ func NewLLMWithConfig(_ context.Context, config *Config) (LLM, error) {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
 
-	var transformer QueryTransformer
+	transformer, err := buildTransformer(config)
+	if err != nil {
+		return nil, err
+	}
 
-	switch config.TransformerProvider {
-	case ProviderAnthropic:
-		transformer = NewAnthropicTransformer(AnthropicConfig{
-			APIKey:      config.TransformerAPIKey,
-			Model:       config.TransformerModel,
-			MaxTokens:   config.TransformerMaxTokens,
-			Temperature: config.TransformerTemperature,
-		})
-	case ProviderOpenAI:
-		transformer = NewOpenAIGenerator(OpenAIConfig{
-			APIKey: config.TransformerAPIKey,
-			Model:  config.TransformerModel,
-		})
-	default:
-		return nil, fmt.Errorf("unsupported transformer provider: %s", config.TransformerProvider)
+	generator, err := buildGenerator(config)
+	if err != nil {
+		return nil, err
 	}
 
-	var textGenerator TextGenerator
+	embedder, err := buildEmbedder(config)
+	if err != nil {
+		return nil, err
+	}
 
+	return &CompositeLLM{
+		QueryTransformer: transformer,
+		Embedder:         embedder,
+		TextGenerator:    generator,
+	}, nil
+}
+
+func newGenerator(config *Config) (TextGenerator, error) {
 	switch config.GeneratorProvider {
 	case ProviderAnthropic:
-		textGenerator = NewAnthropicTransformer(AnthropicConfig{
+		return NewAnthropicTransformer(AnthropicConfig{
 			APIKey:      config.GeneratorAPIKey,
 			Model:       config.GeneratorModel,
 			MaxTokens:   config.GeneratorMaxTokens,
 			Temperature: config.GeneratorTemperature,
-		})
+			Retry:       config.Retry,
+		}), nil
 	case ProviderOpenAI:
-		textGenerator = NewOpenAIGenerator(OpenAIConfig{
+		return NewOpenAIGenerator(OpenAIConfig{
 			APIKey: config.GeneratorAPIKey,
 			Model:  config.GeneratorModel,
-		})
+			Retry:  config.Retry,
+		}), nil
+	case ProviderOllama:
+		return NewOllamaGenerator(OllamaConfig{
+			BaseURL:     config.OllamaBaseURL,
+			Model:       config.GeneratorModel,
+			MaxTokens:   config.GeneratorMaxTokens,
+			Temperature: config.GeneratorTemperature,
+			Retry:       config.Retry,
+		}), nil
 	default:
 		return nil, fmt.Errorf("unsupported generator provider: %s", config.GeneratorProvider)
 	}
+}
+
+// creates the generator behind a circuit breaker, failing over to the
+// fallback generator when one is configured
+func buildGenerator(config *Config) (TextGenerator, error) {
+	primary, err := newGenerator(config)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &failover[TextGenerator]{
+		component: "generator",
+		primary:   newCircuit(config.GeneratorProvider, instrumentGenerator(config.GeneratorProvider, primary)),
+	}
+
+	if config.GeneratorFallbackProvider != "" {
+		fallback, err := newGenerator(config.fallback())
+		if err != nil {
+			return nil, fmt.Errorf("invalid fallback: %w", err)
+		}
+
+		f.fallback = newCircuit(config.GeneratorFallbackProvider, instrumentGenerator(config.GeneratorFallbackProvider, fallback))
+	}
+
+	return &failoverGenerator{f}, nil
+}
+
+// the query transformer's client. transformer models are small and cheap, so
+// it also generates text for auxiliary calls like reranking
+type transformerClient interface {
+	QueryTransformer
+	TextGenerator
+}
+
+// creates the query transformer behind a circuit breaker, failing over to
+// the fallback transformer when one is configured
+func buildTransformer(config *Config) (transformerClient, error) {
+	primary, err := newTransformer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &failover[transformerClient]{
+		component: "transformer",
+		primary:   newCircuit(config.TransformerProvider, primary),
+	}
+
+	if config.TransformerFallbackProvider != "" {
+		fallback, err := newTransformer(config.fallback())
+		if err != nil {
+			return nil, fmt.Errorf("invalid fallback: %w", err)
+		}
+
+		f.fallback = newCircuit(config.TransformerFallbackProvider, fallback)
+	}
+
+	return &failoverTransformer{f}, nil
+}
+
+func newTransformer(config *Config) (transformerClient, error) {
+	switch config.TransformerProvider {
+	case ProviderAnthropic:
+		return NewAnthropicTransformer(AnthropicConfig{
+			APIKey:      config.TransformerAPIKey,
+			Model:       config.TransformerModel,
+			MaxTokens:   config.TransformerMaxTokens,
+			Temperature: config.TransformerTemperature,
+			Retry:       config.Retry,
+		}), nil
+	case ProviderOpenAI:
+		return NewOpenAIGenerator(OpenAIConfig{
+			APIKey: config.TransformerAPIKey,
+			Model:  config.TransformerModel,
+			Retry:  config.Retry,
+		}), nil
+	case ProviderOllama:
+		return NewOllamaGenerator(OllamaConfig{
+			BaseURL:     config.OllamaBaseURL,
+			Model:       config.TransformerModel,
+			MaxTokens:   config.TransformerMaxTokens,
+			Temperature: config.TransformerTemperature,
+			Retry:       config.Retry,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported transformer provider: %s", config.TransformerProvider)
+	}
+}
+
+// creates a text generator on the configured transformer model
+// (TRANSFORMER_PROVIDER, TRANSFORMER_MODEL), for cheap auxiliary calls
+func NewTransformerGenerator() (TextGenerator, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load LLM config: %w", err)
+	}
+
+	transformer, err := buildTransformer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return instrumentGenerator(config.TransformerProvider, transformer), nil
+}
+
+// creates the configured embedder from environment variables, for tools
+// that only need embeddings
+func NewEmbedder() (Embedder, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load LLM config: %w", err)
+	}
+
+	return buildEmbedder(config)
+}
 
-	var embedder Embedder
+// identifies the configured embedding model as "provider/model". embeddings
+// are only comparable when this matches, so caches and re-embedding key on it
+func EmbeddingModel() (string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load LLM config: %w", err)
+	}
+
+	return embeddingModel(config), nil
+}
+
+func embeddingModel(config *Config) string {
+	model := config.EmbedderModel
+	if model == "" && config.EmbedderProvider == ProviderOllama {
+		model = defaultOllamaEmbeddingModel
+	}
+
+	return string(config.EmbedderProvider) + "/" + model
+}
 
+// creates the embedder behind a circuit breaker, failing over to the
+// fallback embedder when one is configured. the fallback must embed with
+// the same model, or search would compare embeddings of different models
+func buildEmbedder(config *Config) (Embedder, error) {
+	primary, err := newEmbedder(config)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &failover[Embedder]{
+		component: "embedder",
+		primary:   newCircuit(config.EmbedderProvider, primary),
+	}
+
+	if config.EmbedderFallbackProvider != "" {
+		fallbackConfig := config.fallback()
+		if model, fallbackModel := embeddingModel(config), embeddingModel(fallbackConfig); model != fallbackModel {
+			return nil, fmt.Errorf("fallback embedder %s must use the embedder's model %s", fallbackModel, model)
+		}
+
+		fallback, err := newEmbedder(fallbackConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fallback: %w", err)
+		}
+
+		f.fallback = newCircuit(config.EmbedderFallbackProvider, fallback)
+	}
+
+	return &failoverEmbedder{f}, nil
+}
+
+func newEmbedder(config *Config) (Embedder, error) {
 	switch config.EmbedderProvider {
 	case ProviderOpenAI:
-		embedder = NewOpenAIEmbedder(OpenAIConfig{
+		return NewOpenAIEmbedder(OpenAIConfig{
 			APIKey: config.EmbedderAPIKey,
 			Model:  config.EmbedderModel,
-		})
+			Retry:  config.Retry,
+		}), nil
+	case ProviderOllama:
+		return &paddedEmbedder{NewOllamaEmbedder(OllamaConfig{
+			BaseURL: config.OllamaBaseURL,
+			Model:   config.EmbedderModel,
+			Retry:   config.Retry,
+		})}, nil
 	default:
 		return nil, fmt.Errorf("unsupported embedder provider: %s", config.EmbedderProvider)
 	}
+}
 
-	return &CompositeLLM{
-		QueryTransformer: transformer,
-		Embedder:         embedder,
-		TextGenerator:    textGenerator,
-	}, nil
+func (e *paddedEmbedder) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embedding, err := e.Embedder.GenerateEmbedding(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	return padEmbedding(embedding)
+}
+
+func (e *paddedEmbedder) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings, err := e.Embedder.GenerateEmbeddings(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, embedding := range embeddings {
+		if embeddings[i], err = padEmbedding(embedding); err != nil {
+			return nil, err
+		}
+	}
+
+	return embeddings, nil
+}
+
+func padEmbedding(embedding []float32) ([]float32, error) {
+	if len(embedding) > EmbeddingDimensions {
+		return nil, fmt.Errorf("embedding has %d dimensions, at most %d are supported", len(embedding), EmbeddingDimensions)
+	}
+
+	if len(embedding) == EmbeddingDimensions {
+		return embedding, nil
+	}
+
+	padded := make([]float32, EmbeddingDimensions)
+	copy(padded, embedding)
+
+	return padded, nil
+}
+
+// wraps a text generator so its requests are traced and their latency and
+// token usage are recorded
+func instrumentGenerator(provider Provider, generator TextGenerator) TextGenerator {
+	return &instrumentedGenerator{
+		TextGenerator: generator,
+		provider:      provider,
+	}
+}
+
+func (g *instrumentedGenerator) GenerateText(ctx context.Context, req TextGenerationRequest) (*TextGenerationResponse, error) {
+	ctx, span := g.startSpan(ctx, "generate")
+	start := time.Now()
+
+	resp, err := g.TextGenerator.GenerateText(ctx, req)
+	g.observe(span, "generate", start, resp, err)
+
+	return resp, err
+}
+
+func (g *instrumentedGenerator) GenerateTextStream(ctx context.Context, req TextGenerationRequest, onChunk func(chunk string) error) (*TextGenerationResponse, error) {
+	ctx, span := g.startSpan(ctx, "stream")
+	start := time.Now()
+
+	resp, err := g.TextGenerator.GenerateTextStream(ctx, req, onChunk)
+	g.observe(span, "stream", start, resp, err)
+
+	return resp, err
+}
+
+func (g *instrumentedGenerator) startSpan(ctx context.Context, operation string) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, "llm."+operation,
+		tracing.String("llm.provider", string(g.provider)),
+		tracing.String("llm.model", g.Model()),
+	)
+}
+
+// records a finished request and ends its span. failed requests only count
+// towards latency.
+func (g *instrumentedGenerator) observe(span *tracing.Span, operation string, start time.Time, resp *TextGenerationResponse, err error) {
+	defer span.End()
+
+	provider := string(g.provider)
+	metrics.LLMRequestDuration.Observe(time.Since(start).Seconds(), provider, operation)
+
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+
+	metrics.LLMTokens.Add(float64(resp.Usage.InputTokens), provider, "input")
+	metrics.LLMTokens.Add(float64(resp.Usage.OutputTokens), provider, "output")
+
+	span.SetAttributes(
+		tracing.Int("llm.input_tokens", resp.Usage.InputTokens),
+		tracing.Int("llm.output_tokens", resp.Usage.OutputTokens),
+	)
 }
@@ -120,6 +120,73 @@ func TestAnthropicConfig_Defaults(t *testing.T) {
 	assert.Equal(t, float32(defaultTemperature), transformer.config.Temperature)
 }
 
+func TestRegistry_Generator(t *testing.T) {
+	registry := NewRegistry(ProviderAnthropic, DefaultProviders()...)
+
+	generator, err := registry.Generator("", "", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, byokAnthropicModel, generator.Model())
+
+	generator, err = registry.Generator(ProviderOpenAI, "", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, byokOpenAIModel, generator.Model())
+
+	generator, err = registry.Generator(ProviderGemini, "gemini-2.5-pro", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, "gemini-2.5-pro", generator.Model())
+
+	_, err = registry.Generator(ProviderMistral, "gpt-4o", "test-key")
+	require.ErrorIs(t, err, ErrModelNotAllowed)
+
+	_, err = registry.Generator(Provider("invalid"), "", "test-key")
+	require.ErrorIs(t, err, ErrUnsupportedProvider)
+
+	_, err = registry.Generator(ProviderOllama, "", "test-key")
+	require.ErrorIs(t, err, ErrUnsupportedProvider)
+}
+
+func TestRegistryFromEnv(t *testing.T) {
+	t.Setenv("BYOK_PROVIDERS", "openai,ollama")
+	t.Setenv("BYOK_DEFAULT_PROVIDER", "ollama")
+	t.Setenv("BYOK_OLLAMA_MODELS", "qwen2.5-coder,llama3.1")
+	t.Setenv("OLLAMA_BASE_URL", "http://localhost:11434/")
+
+	registry := RegistryFromEnv()
+
+	providers := registry.Providers()
+	require.Len(t, providers, 2)
+	assert.Equal(t, ProviderOllama, registry.DefaultProvider())
+	assert.Equal(t, "http://localhost:11434/", providers[1].BaseURL)
+
+	generator, err := registry.Generator("", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "qwen2.5-coder", generator.Model())
+
+	_, err = registry.Generator(ProviderAnthropic, "", "test-key")
+	require.ErrorIs(t, err, ErrUnsupportedProvider)
+}
+
+func TestPadEmbedding(t *testing.T) {
+	padded, err := padEmbedding([]float32{0.5, 0.25})
+	require.NoError(t, err)
+	require.Len(t, padded, EmbeddingDimensions)
+	assert.Equal(t, float32(0.5), padded[0])
+	assert.Equal(t, float32(0), padded[EmbeddingDimensions-1])
+
+	_, err = padEmbedding(make([]float32, EmbeddingDimensions+1))
+	require.Error(t, err)
+}
+
+func TestOllamaConfig_Defaults(t *testing.T) {
+	generator := NewOllamaGenerator(OllamaConfig{BaseURL: "http://ollama:11434/"})
+	assert.Equal(t, defaultOllamaChatModel, generator.Model())
+	assert.Equal(t, "http://ollama:11434", generator.config.BaseURL)
+
+	embedder := NewOllamaEmbedder(OllamaConfig{})
+	assert.Equal(t, defaultOllamaEmbeddingModel, embedder.Model())
+	assert.Equal(t, defaultOllamaBaseURL, embedder.config.BaseURL)
+}
+
 func TestOpenAIConfig_Defaults(t *testing.T) {
 	embedder := NewOpenAIEmbedder(OpenAIConfig{
 		APIKey: "test-key",
@@ -162,3 +229,20 @@ func TestEmbeddings_InputValidation(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "no texts provided")
 }
+
+func TestProbeTargets(t *testing.T) {
+	urls := probeTargets(&Config{
+		TransformerProvider: ProviderAnthropic,
+		GeneratorProvider:   ProviderAnthropic,
+		EmbedderProvider:    ProviderOpenAI,
+	})
+	assert.Equal(t, []string{"https://api.anthropic.com", "https://api.openai.com"}, urls)
+
+	urls = probeTargets(&Config{
+		TransformerProvider: ProviderOllama,
+		GeneratorProvider:   ProviderOllama,
+		EmbedderProvider:    ProviderOllama,
+		OllamaBaseURL:       "http://ollama:11434/",
+	})
+	assert.Equal(t, []string{"http://ollama:11434/api/version"}, urls)
+}
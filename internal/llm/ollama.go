@@ -0,0 +1,286 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/tracing"
+)
+
+const (
+	defaultOllamaBaseURL        = "http://localhost:11434"
+	defaultOllamaChatModel      = "llama3.1"
+	defaultOllamaEmbeddingModel = "nomic-embed-text"
+)
+
+// shared HTTP client for Ollama API calls. local models can be slow to
+// load, so requests get more time than the hosted APIs
+var ollamaHTTPClient = &http.Client{
+	Timeout: 5 * time.Minute,
+	Transport: tracing.NewTransport(&http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}),
+}
+
+type ollamaChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []openaiChatMessage `json:"messages"`
+	Stream   bool                `json:"stream"`
+	Format   string              `json:"format,omitempty"` // "json" constrains output to JSON
+	Options  ollamaOptions       `json:"options"`
+}
+
+type ollamaOptions struct {
+	Temperature float32 `json:"temperature"`
+	NumPredict  int     `json:"num_predict,omitempty"` // max tokens to generate
+}
+
+// also the shape of each streamed line
+type ollamaChatResponse struct {
+	Message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"message"`
+	Done            bool `json:"done"`
+	PromptEvalCount int  `json:"prompt_eval_count"`
+	EvalCount       int  `json:"eval_count"`
+}
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+type OllamaConfig struct {
+	BaseURL     string  // e.g., "http://localhost:11434"
+	Model       string  // e.g., "llama3.1" or "nomic-embed-text"
+	MaxTokens   int     // max tokens for response
+	Temperature float32 // 0.0 to 1.0
+	Retry       RetryPolicy
+}
+
+// implements TextGenerator, QueryTransformer and Embedder for a local Ollama
+// server. embeddings aren't hedged, a second copy would only queue behind
+// the first on the same server
+type OllamaClient struct {
+	config    OllamaConfig
+	requester *requester
+}
+
+// creates a client for chat models
+func NewOllamaGenerator(config OllamaConfig) *OllamaClient {
+	if config.Model == "" {
+		config.Model = defaultOllamaChatModel
+	}
+
+	return newOllamaClient(config)
+}
+
+// creates a client for embedding models
+func NewOllamaEmbedder(config OllamaConfig) *OllamaClient {
+	if config.Model == "" {
+		config.Model = defaultOllamaEmbeddingModel
+	}
+
+	return newOllamaClient(config)
+}
+
+func newOllamaClient(config OllamaConfig) *OllamaClient {
+	if config.BaseURL == "" {
+		config.BaseURL = defaultOllamaBaseURL
+	}
+
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	return &OllamaClient{
+		config:    config,
+		requester: newRequester(ProviderOllama, ollamaHTTPClient, nil, config.Retry),
+	}
+}
+
+func (o *OllamaClient) Model() string {
+	return o.config.Model
+}
+
+func (o *OllamaClient) GenerateText(ctx context.Context, req TextGenerationRequest) (*TextGenerationResponse, error) {
+	resp, err := o.post(ctx, "/api/chat", o.chatRequest(req, false))
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	var chatResp ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &TextGenerationResponse{
+		Text: chatResp.Message.Content,
+		Usage: Usage{
+			InputTokens:  chatResp.PromptEvalCount,
+			OutputTokens: chatResp.EvalCount,
+		},
+	}, nil
+}
+
+func (o *OllamaClient) GenerateTextStream(ctx context.Context, req TextGenerationRequest, onChunk func(chunk string) error) (*TextGenerationResponse, error) {
+	resp, err := o.post(ctx, "/api/chat", o.chatRequest(req, true))
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	var fullText strings.Builder
+	var usage Usage
+
+	// ollama streams one JSON object per line
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			continue // skip malformed lines
+		}
+
+		if content := chunk.Message.Content; content != "" {
+			fullText.WriteString(content)
+			if err := onChunk(content); err != nil {
+				return nil, fmt.Errorf("chunk callback error: %w", err)
+			}
+		}
+
+		// counts are only sent with the final line
+		if chunk.Done {
+			usage.InputTokens = chunk.PromptEvalCount
+			usage.OutputTokens = chunk.EvalCount
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading stream: %w", err)
+	}
+
+	return &TextGenerationResponse{
+		Text:  fullText.String(),
+		Usage: usage,
+	}, nil
+}
+
+func (o *OllamaClient) TransformQuery(ctx context.Context, userQuery string) (string, error) {
+	analysis, err := o.AnalyzeQuery(ctx, userQuery)
+	if err != nil {
+		return "", err
+	}
+
+	return userQuery + " " + analysis.TransformedQuery, nil
+}
+
+func (o *OllamaClient) AnalyzeQuery(ctx context.Context, userQuery string) (*QueryAnalysis, error) {
+	chatReq := o.chatRequest(TextGenerationRequest{
+		SystemPrompt: buildTransformationPrompt(),
+		Messages:     []Message{{Role: "user", Content: fmt.Sprintf("User query: %s", userQuery)}},
+	}, false)
+	chatReq.Format = "json" // small local models wander off without it
+
+	resp, err := o.post(ctx, "/api/chat", chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	var chatResp ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var analysis QueryAnalysis
+	if err := json.Unmarshal([]byte(strings.TrimSpace(chatResp.Message.Content)), &analysis); err != nil {
+		return nil, fmt.Errorf("failed to parse query analysis JSON: %w", err)
+	}
+
+	return &analysis, nil
+}
+
+func (o *OllamaClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := o.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embeddings returned")
+	}
+
+	return embeddings[0], nil
+}
+
+func (o *OllamaClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("no texts provided")
+	}
+
+	resp, err := o.post(ctx, "/api/embed", ollamaEmbedRequest{Model: o.config.Model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	var embResp ollamaEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(embResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embResp.Embeddings))
+	}
+
+	return embResp.Embeddings, nil
+}
+
+func (o *OllamaClient) chatRequest(req TextGenerationRequest, stream bool) ollamaChatRequest {
+	messages := make([]openaiChatMessage, 0, len(req.Messages)+1)
+
+	if req.SystemPrompt != "" {
+		messages = append(messages, openaiChatMessage{Role: "system", Content: req.SystemPrompt})
+	}
+
+	for _, msg := range req.Messages {
+		messages = append(messages, openaiChatMessage(msg))
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = o.config.MaxTokens
+	}
+
+	return ollamaChatRequest{
+		Model:    o.config.Model,
+		Messages: messages,
+		Stream:   stream,
+		Options: ollamaOptions{
+			Temperature: o.config.Temperature,
+			NumPredict:  maxTokens,
+		},
+	}
+}
+
+// sends a JSON request and checks the status, retrying transient failures.
+// the caller closes the body
+func (o *OllamaClient) post(ctx context.Context, path string, body any) (*http.Response, error) {
+	return o.requester.post(ctx, o.config.BaseURL+path, body, nil)
+}
@@ -2,16 +2,16 @@ package llm
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"codeberg.org/algopatterns/server/internal/tracing"
 )
 
 const (
@@ -25,12 +25,12 @@ const (
 // shared HTTP client for OpenAI API calls
 var openaiHTTPClient = &http.Client{
 	Timeout: 60 * time.Second, // total request timeout
-	Transport: &http.Transport{
+	Transport: tracing.NewTransport(&http.Transport{
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 10,
 		IdleConnTimeout:     90 * time.Second,
 		TLSHandshakeTimeout: 10 * time.Second,
-	},
+	}),
 }
 
 // rate limiter for OpenAI API calls
@@ -60,11 +60,20 @@ type embeddingResponse struct {
 type OpenAIConfig struct {
 	APIKey string
 	Model  string // e.g., "text-embedding-3-small"
+
+	// chat completions endpoint of an OpenAI-compatible API (Mistral, Ollama).
+	// empty uses OpenAI
+	BaseURL string
+
+	// provider behind BaseURL, labels metrics. empty is openai
+	Provider Provider
+
+	Retry RetryPolicy
 }
 
 type OpenAIEmbedder struct {
-	config     OpenAIConfig
-	httpClient *http.Client
+	config    OpenAIConfig
+	requester *requester
 }
 
 func NewOpenAIEmbedder(config OpenAIConfig) *OpenAIEmbedder {
@@ -73,8 +82,8 @@ func NewOpenAIEmbedder(config OpenAIConfig) *OpenAIEmbedder {
 	}
 
 	return &OpenAIEmbedder{
-		config:     config,
-		httpClient: openaiHTTPClient, // use shared client with proper timeouts and connection pooling
+		config:    config,
+		requester: newRequester(ProviderOpenAI, openaiHTTPClient, openaiRateLimiter, config.Retry),
 	}
 }
 
@@ -102,38 +111,13 @@ func (e *OpenAIEmbedder) GenerateEmbeddings(ctx context.Context, texts []string)
 		Encoding: "float",
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	body, err := e.requester.postHedged(ctx, openaiEmbeddingsURL, reqBody, bearer(e.config.APIKey))
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", openaiEmbeddingsURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", e.config.APIKey))
-
-	// apply rate limiting before making the request
-	if err := openaiRateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
-	}
-
-	resp, err := e.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-
-	defer resp.Body.Close() //nolint:errcheck
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body) //nolint:errcheck
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	var embResp embeddingResponse
-	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+	if err := json.Unmarshal(body, &embResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
@@ -199,10 +183,10 @@ type openaiChatResponse struct {
 	} `json:"usage"`
 }
 
-// implements TextGenerator and QueryTransformer for openai
+// implements TextGenerator and QueryTransformer for openai and compatible APIs
 type OpenAIGenerator struct {
-	config     OpenAIConfig
-	httpClient *http.Client
+	config    OpenAIConfig
+	requester *requester
 }
 
 func NewOpenAIGenerator(config OpenAIConfig) *OpenAIGenerator {
@@ -210,9 +194,20 @@ func NewOpenAIGenerator(config OpenAIConfig) *OpenAIGenerator {
 		config.Model = defaultOpenAIChatModel
 	}
 
+	if config.Provider == "" {
+		config.Provider = ProviderOpenAI
+	}
+
+	// other APIs enforce their own limits
+	var limiter *rate.Limiter
+	if config.BaseURL == "" {
+		config.BaseURL = openaiChatCompletionsURL
+		limiter = openaiRateLimiter
+	}
+
 	return &OpenAIGenerator{
-		config:     config,
-		httpClient: openaiHTTPClient,
+		config:    config,
+		requester: newRequester(config.Provider, openaiHTTPClient, limiter, config.Retry),
 	}
 }
 
@@ -243,35 +238,13 @@ func (g *OpenAIGenerator) GenerateText(ctx context.Context, req TextGenerationRe
 		Temperature: 0.7,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", openaiChatCompletionsURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.config.APIKey))
-
-	if err := openaiRateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
-	}
-
-	resp, err := g.httpClient.Do(httpReq)
+	resp, err := g.requester.post(ctx, g.config.BaseURL, reqBody, bearer(g.config.APIKey))
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
 
 	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body) //nolint:errcheck
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var chatResp openaiChatResponse
 	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
@@ -312,34 +285,13 @@ func (g *OpenAIGenerator) GenerateTextStream(ctx context.Context, req TextGenera
 		Stream:      true,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	resp, err := g.requester.post(ctx, g.config.BaseURL, reqBody, bearer(g.config.APIKey))
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", openaiChatCompletionsURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.config.APIKey))
-
-	if err := openaiRateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
+		return nil, err
 	}
 
-	resp, err := g.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
 	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body) //nolint:errcheck
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var fullText strings.Builder
 	var usage Usage
 
@@ -413,35 +365,13 @@ func (g *OpenAIGenerator) AnalyzeQuery(ctx context.Context, userQuery string) (*
 		MaxTokens:   200,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	resp, err := g.requester.post(ctx, g.config.BaseURL, reqBody, bearer(g.config.APIKey))
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", openaiChatCompletionsURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.config.APIKey))
-
-	if err := openaiRateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
-	}
-
-	resp, err := g.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
 
 	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body) //nolint:errcheck
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var chatResp openaiChatResponse
 	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
@@ -460,3 +390,10 @@ func (g *OpenAIGenerator) AnalyzeQuery(ctx context.Context, userQuery string) (*
 
 	return &analysis, nil
 }
+
+func bearer(apiKey string) http.Header {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+apiKey)
+
+	return header
+}
@@ -0,0 +1,44 @@
+package llm
+
+import "strings"
+
+// list prices of the models the platform offers, matched by model name
+// prefix so dated snapshots share a price. more specific prefixes come first
+var modelPrices = []struct {
+	prefix string
+	price  modelPrice
+}{
+	{"claude-opus-4", modelPrice{input: 15, output: 75}},
+	{"claude-sonnet-4", modelPrice{input: 3, output: 15}},
+	{"claude-3-7-sonnet", modelPrice{input: 3, output: 15}},
+	{"claude-3-5-sonnet", modelPrice{input: 3, output: 15}},
+	{"claude-3-5-haiku", modelPrice{input: 0.8, output: 4}},
+	{"claude-3-haiku", modelPrice{input: 0.25, output: 1.25}},
+	{"gpt-4o-mini", modelPrice{input: 0.15, output: 0.6}},
+	{"gpt-4o", modelPrice{input: 2.5, output: 10}},
+	{"gpt-4.1-mini", modelPrice{input: 0.4, output: 1.6}},
+	{"gpt-4.1-nano", modelPrice{input: 0.1, output: 0.4}},
+	{"gpt-4.1", modelPrice{input: 2, output: 8}},
+}
+
+// list prices in USD per million tokens
+type modelPrice struct {
+	input  float64
+	output float64
+}
+
+// estimates what tokens of a model cost in USD. local models are free;
+// reports false for models without a known price
+func EstimateCost(provider, model string, inputTokens, outputTokens int64) (float64, bool) {
+	if provider == "ollama" {
+		return 0, true
+	}
+
+	for _, p := range modelPrices {
+		if strings.HasPrefix(model, p.prefix) {
+			return (float64(inputTokens)*p.price.input + float64(outputTokens)*p.price.output) / 1_000_000, true
+		}
+	}
+
+	return 0, false
+}
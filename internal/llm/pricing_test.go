@@ -0,0 +1,26 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateCost(t *testing.T) {
+	cost, known := EstimateCost("anthropic", "claude-sonnet-4-20250514", 1_000_000, 100_000)
+	assert.True(t, known)
+	assert.InDelta(t, 4.5, cost, 0.0001)
+
+	// the mini model isn't priced like its larger sibling
+	cost, known = EstimateCost("openai", "gpt-4o-mini", 1_000_000, 1_000_000)
+	assert.True(t, known)
+	assert.InDelta(t, 0.75, cost, 0.0001)
+
+	cost, known = EstimateCost("ollama", "llama3", 5_000_000, 5_000_000)
+	assert.True(t, known)
+	assert.Zero(t, cost)
+
+	cost, known = EstimateCost("openai", "some-future-model", 1000, 1000)
+	assert.False(t, known)
+	assert.Zero(t, cost)
+}
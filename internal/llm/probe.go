@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// hosts probed to check that a hosted provider is reachable
+var probeURLs = map[Provider]string{
+	ProviderAnthropic: "https://api.anthropic.com",
+	ProviderOpenAI:    "https://api.openai.com",
+}
+
+// probes only wait for response headers, a slow provider shouldn't hold up
+// readiness
+var probeHTTPClient = &http.Client{Timeout: 5 * time.Second}
+
+// checks that the providers configured in the environment can be reached.
+// probes aren't authenticated and don't use tokens: any HTTP response, even
+// an error status, counts as reachable.
+func ProbeProviders() (func(ctx context.Context) error, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load LLM config: %w", err)
+	}
+
+	urls := probeTargets(config)
+
+	return func(ctx context.Context) error {
+		for _, url := range urls {
+			if err := probe(ctx, url); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, nil
+}
+
+// returns the URL to probe for each distinct provider in use
+func probeTargets(config *Config) []string {
+	var urls []string
+	for _, provider := range []Provider{config.TransformerProvider, config.GeneratorProvider, config.EmbedderProvider} {
+		url := probeURLs[provider]
+		if provider == ProviderOllama {
+			url = config.OllamaBaseURL
+			if url == "" {
+				url = defaultOllamaBaseURL
+			}
+			url = strings.TrimRight(url, "/") + "/api/version"
+		}
+
+		if url != "" && !slices.Contains(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
+func probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create probe request: %w", err)
+	}
+
+	resp, err := probeHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s unreachable: %w", req.URL.Host, err)
+	}
+	resp.Body.Close() //nolint:errcheck,gosec // nothing to read
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// returns the providers available to BYOK requests out of the box
+func DefaultProviders() []ProviderSpec {
+	return []ProviderSpec{
+		{
+			Name:         ProviderAnthropic,
+			DefaultModel: byokAnthropicModel,
+			Models:       []string{byokAnthropicModel, "claude-opus-4-1-20250805", "claude-3-5-haiku-20241022"},
+		},
+		{
+			Name:         ProviderOpenAI,
+			DefaultModel: byokOpenAIModel,
+			Models:       []string{byokOpenAIModel, "gpt-4o-mini", "gpt-4.1", "gpt-4.1-mini"},
+		},
+		{
+			Name:         ProviderGemini,
+			DefaultModel: byokGeminiModel,
+			Models:       []string{byokGeminiModel, "gemini-2.5-pro"},
+		},
+		{
+			Name:         ProviderMistral,
+			DefaultModel: byokMistralModel,
+			Models:       []string{byokMistralModel, "codestral-latest", "mistral-small-latest"},
+			BaseURL:      mistralChatCompletionsURL,
+		},
+	}
+}
+
+// creates a registry of the given providers. defaultProvider serves requests
+// that don't name one and falls back to the first provider if not registered.
+func NewRegistry(defaultProvider Provider, specs ...ProviderSpec) *Registry {
+	r := &Registry{
+		providers:       make(map[Provider]ProviderSpec, len(specs)),
+		defaultProvider: defaultProvider,
+	}
+
+	for _, spec := range specs {
+		if spec.DefaultModel != "" && !slices.Contains(spec.Models, spec.DefaultModel) {
+			spec.Models = append([]string{spec.DefaultModel}, spec.Models...)
+		}
+
+		if _, exists := r.providers[spec.Name]; !exists {
+			r.order = append(r.order, spec.Name)
+		}
+
+		r.providers[spec.Name] = spec
+	}
+
+	if _, ok := r.providers[r.defaultProvider]; !ok && len(r.order) > 0 {
+		r.defaultProvider = r.order[0]
+	}
+
+	return r
+}
+
+// creates a registry from the default providers and environment overrides:
+//
+//	BYOK_PROVIDERS                  comma-separated providers to offer (default: all)
+//	BYOK_DEFAULT_PROVIDER           provider used when a request names none (default: anthropic)
+//	BYOK_<PROVIDER>_MODELS          comma-separated model allowlist
+//	BYOK_<PROVIDER>_DEFAULT_MODEL   model used when a request names none
+//	OLLAMA_BASE_URL                 registers a self-hosted ollama server, e.g. http://localhost:11434
+func RegistryFromEnv() *Registry {
+	specs := DefaultProviders()
+
+	if baseURL := os.Getenv("OLLAMA_BASE_URL"); baseURL != "" {
+		specs = append(specs, ProviderSpec{
+			Name:         ProviderOllama,
+			DefaultModel: byokOllamaModel,
+			BaseURL:      baseURL,
+		})
+	}
+
+	enabled := splitEnvList("BYOK_PROVIDERS")
+
+	configured := make([]ProviderSpec, 0, len(specs))
+	for _, spec := range specs {
+		if len(enabled) > 0 && !slices.Contains(enabled, string(spec.Name)) {
+			continue
+		}
+
+		prefix := "BYOK_" + strings.ToUpper(string(spec.Name)) + "_"
+
+		if models := splitEnvList(prefix + "MODELS"); len(models) > 0 {
+			spec.Models = models
+			spec.DefaultModel = models[0]
+		}
+
+		if model := os.Getenv(prefix + "DEFAULT_MODEL"); model != "" {
+			spec.DefaultModel = model
+		}
+
+		configured = append(configured, spec)
+	}
+
+	defaultProvider := Provider(os.Getenv("BYOK_DEFAULT_PROVIDER"))
+	if defaultProvider == "" {
+		defaultProvider = ProviderAnthropic
+	}
+
+	return NewRegistry(defaultProvider, configured...)
+}
+
+// returns the registered providers in registration order
+func (r *Registry) Providers() []ProviderSpec {
+	specs := make([]ProviderSpec, 0, len(r.order))
+	for _, name := range r.order {
+		specs = append(specs, r.providers[name])
+	}
+
+	return specs
+}
+
+func (r *Registry) DefaultProvider() Provider {
+	return r.defaultProvider
+}
+
+// creates a text generator for a provider/model pair using a user-provided
+// API key. empty provider or model select the configured defaults.
+func (r *Registry) Generator(provider Provider, model, apiKey string) (TextGenerator, error) {
+	if provider == "" {
+		provider = r.defaultProvider
+	}
+
+	spec, ok := r.providers[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProvider, provider)
+	}
+
+	if model == "" {
+		model = spec.DefaultModel
+	}
+
+	if !slices.Contains(spec.Models, model) {
+		return nil, fmt.Errorf("%w: %s/%s", ErrModelNotAllowed, provider, model)
+	}
+
+	var generator TextGenerator
+
+	switch provider {
+	case ProviderAnthropic:
+		generator = NewAnthropicTransformer(AnthropicConfig{
+			APIKey:      apiKey,
+			Model:       model,
+			MaxTokens:   byokMaxTokens,
+			Temperature: byokTemperature,
+		})
+	case ProviderOllama:
+		generator = NewOllamaGenerator(OllamaConfig{
+			BaseURL:     spec.BaseURL,
+			Model:       model,
+			MaxTokens:   byokMaxTokens,
+			Temperature: byokTemperature,
+		})
+	case ProviderGemini:
+		generator = NewGeminiGenerator(GeminiConfig{
+			APIKey:      apiKey,
+			Model:       model,
+			MaxTokens:   byokMaxTokens,
+			Temperature: byokTemperature,
+		})
+	default:
+		// openai and the OpenAI-compatible APIs
+		generator = NewOpenAIGenerator(OpenAIConfig{
+			APIKey:   apiKey,
+			Model:    model,
+			BaseURL:  spec.BaseURL,
+			Provider: provider,
+		})
+	}
+
+	return instrumentGenerator(provider, generator), nil
+}
+
+func splitEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,336 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+func newRequester(provider Provider, client *http.Client, limiter *rate.Limiter, policy RetryPolicy) *requester {
+	return &requester{
+		provider: provider,
+		client:   client,
+		limiter:  limiter,
+		policy:   policy.withDefaults(),
+	}
+}
+
+// fills fields left zero with the defaults. hedging stays off unless set
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryAttempts
+	}
+
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultRetryBaseDelay
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultRetryMaxDelay
+	}
+
+	return p
+}
+
+// returns how long to wait before the given retry (1 for the first): a random
+// duration up to the exponential backoff, so clients that failed together
+// don't retry together
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.BaseDelay
+	for i := 1; i < retry && ceiling < p.MaxDelay; i++ {
+		ceiling *= 2
+	}
+
+	return rand.N(min(ceiling, p.MaxDelay)) + 1 //nolint:gosec // jitter doesn't need a secure source
+}
+
+// posts a JSON body and checks the status, retrying transient failures. the
+// caller closes the body of the returned response
+func (r *requester) post(ctx context.Context, url string, body any, header http.Header) (*http.Response, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	return retry(ctx, r, func(ctx context.Context) (*http.Response, error) {
+		return r.send(ctx, url, jsonData, header)
+	})
+}
+
+// posts an idempotent JSON request like post, hedging each attempt when the
+// policy has a hedge delay. returns the response body
+func (r *requester) postHedged(ctx context.Context, url string, body any, header http.Header) ([]byte, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	return retry(ctx, r, func(ctx context.Context) ([]byte, error) {
+		return r.hedge(ctx, func(ctx context.Context) ([]byte, error) {
+			resp, err := r.send(ctx, url, jsonData, header)
+			if err != nil {
+				return nil, err
+			}
+
+			defer resp.Body.Close() //nolint:errcheck
+
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read response: %w", err)
+			}
+
+			return respBody, nil
+		})
+	})
+}
+
+// sends one request and checks the status
+func (r *requester) send(ctx context.Context, url string, jsonData []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header = header.Clone()
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if r.limiter != nil {
+		if err := r.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter error: %w", err)
+		}
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, r.transportError(ctx, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body) //nolint:errcheck
+		resp.Body.Close()                //nolint:errcheck,gosec // body already read
+
+		return nil, &APIError{
+			Provider:   r.provider,
+			Kind:       statusKind(resp.StatusCode),
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: retryAfter(resp.Header, time.Now()),
+		}
+	}
+
+	return resp, nil
+}
+
+// classifies a request that got no response. the caller giving up isn't
+// the provider's failure and is returned as is
+func (r *requester) transportError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	kind := ErrorUnavailable
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		kind = ErrorTimeout
+	}
+
+	return &APIError{Provider: r.provider, Kind: kind, Err: err}
+}
+
+// calls call until it succeeds, fails for a reason retrying won't fix, or
+// the policy's attempts run out. waits out Retry-After when the provider
+// sends one, failing right away when it asks for longer than MaxDelay
+func retry[T any](ctx context.Context, r *requester, call func(ctx context.Context) (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := call(ctx)
+		if err == nil {
+			return result, nil
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.Retryable() || attempt >= r.policy.MaxAttempts {
+			return result, err
+		}
+
+		delay := r.policy.backoff(attempt)
+		if apiErr.RetryAfter > 0 {
+			if apiErr.RetryAfter > r.policy.MaxDelay {
+				return result, err
+			}
+
+			delay = apiErr.RetryAfter
+		}
+
+		metrics.LLMRetries.Inc(string(r.provider), string(apiErr.Kind))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
+
+// sends a second copy of the request when the first hasn't answered within
+// the hedge delay, and returns the first success. the slower copy is
+// cancelled
+func (r *requester) hedge(ctx context.Context, call func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	if r.policy.HedgeDelay <= 0 {
+		return call(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		body []byte
+		err  error
+	}
+
+	results := make(chan result, 2)
+	start := func() {
+		go func() {
+			body, err := call(ctx)
+			results <- result{body, err}
+		}()
+	}
+
+	start()
+	pending := 1
+
+	timer := time.NewTimer(r.policy.HedgeDelay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			metrics.LLMHedgedRequests.Inc(string(r.provider))
+			start()
+			pending++
+		case res := <-results:
+			pending--
+			if res.err == nil || pending == 0 {
+				return res.body, res.err
+			}
+		}
+	}
+}
+
+func statusKind(status int) ErrorKind {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorAuth
+	case status == http.StatusRequestTimeout || status >= 500:
+		return ErrorUnavailable
+	default:
+		return ErrorInvalidRequest
+	}
+}
+
+// parses how long the provider asks to wait before retrying: OpenAI's
+// retry-after-ms, or Retry-After as seconds or an HTTP date
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return max(time.Duration(seconds*float64(time.Second)), 0)
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+
+	return 0
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("failed to send request: %v", e.Err)
+	}
+
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// reports whether the call may succeed when retried. timeouts aren't
+// retried, the caller would wait for the client timeout again
+func (e *APIError) Retryable() bool {
+	return e.Kind == ErrorRateLimited || e.Kind == ErrorUnavailable
+}
+
+// returns why a provider call failed, or "" for errors that aren't the
+// provider's. calls skipped by an open circuit count as unavailable
+func KindOf(err error) ErrorKind {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind
+	}
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return ErrorUnavailable
+	}
+
+	return ""
+}
+
+// returns a message telling the user what went wrong with a provider call
+// and what to do about it, or "" for errors that aren't the provider's. byok
+// reports whether the call used the user's own API key, whose problems only
+// they can fix; problems with the server's keys are shown as unavailable
+func UserMessage(err error, byok bool) string {
+	switch KindOf(err) {
+	case ErrorRateLimited:
+		if byok {
+			return "your API key hit the provider's rate limit, wait a moment and try again"
+		}
+
+		return "AI generation is busy right now, try again in a moment"
+	case ErrorTimeout:
+		return "the AI provider took too long to answer, try again shortly"
+	case ErrorAuth:
+		if byok {
+			return "the provider rejected your API key, check that it is valid and has access to the model"
+		}
+
+		return "AI generation is temporarily unavailable, try again shortly"
+	case ErrorInvalidRequest:
+		if byok {
+			return "the provider rejected the request, check that your API key has access to the model"
+		}
+
+		return "the AI provider rejected the request, try a shorter prompt"
+	case ErrorUnavailable:
+		return "AI generation is temporarily unavailable, try again shortly"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,182 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+// serves the given statuses in order, then 200
+func newFlakyServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		call := int(calls.Add(1))
+		if call <= len(statuses) {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(statuses[call-1])
+			fmt.Fprintf(w, `{"error":"attempt %d"}`, call) //nolint:errcheck
+			return
+		}
+
+		fmt.Fprint(w, `{"ok":true}`) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func postBody(t *testing.T, r *requester, url string) (string, error) {
+	t.Helper()
+
+	resp, err := r.post(context.Background(), url, map[string]string{}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body), nil
+}
+
+func TestRequesterRetriesTransientFailures(t *testing.T) {
+	server, calls := newFlakyServer(t, nil, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	r := newRequester(ProviderOpenAI, server.Client(), nil, testRetryPolicy)
+
+	body, err := postBody(t, r, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, body)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRequesterGivesUp(t *testing.T) {
+	t.Run("after the last attempt", func(t *testing.T) {
+		server, calls := newFlakyServer(t, nil, 529, 529, 529, 529)
+		r := newRequester(ProviderAnthropic, server.Client(), nil, testRetryPolicy)
+
+		_, err := postBody(t, r, server.URL)
+		require.Error(t, err)
+		assert.Equal(t, `API request failed with status 529: {"error":"attempt 3"}`, err.Error())
+		assert.Equal(t, ErrorUnavailable, KindOf(err))
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("on errors retrying won't fix", func(t *testing.T) {
+		server, calls := newFlakyServer(t, nil, http.StatusUnauthorized)
+		r := newRequester(ProviderAnthropic, server.Client(), nil, testRetryPolicy)
+
+		_, err := postBody(t, r, server.URL)
+		require.Error(t, err)
+		assert.Equal(t, ErrorAuth, KindOf(err))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("when asked to wait longer than the max delay", func(t *testing.T) {
+		server, calls := newFlakyServer(t, http.Header{"Retry-After": {"30"}}, http.StatusTooManyRequests)
+		r := newRequester(ProviderOpenAI, server.Client(), nil, testRetryPolicy)
+
+		_, err := postBody(t, r, server.URL)
+		require.Error(t, err)
+
+		apiErr, ok := err.(*APIError)
+		require.True(t, ok)
+		assert.Equal(t, ErrorRateLimited, apiErr.Kind)
+		assert.Equal(t, 30*time.Second, apiErr.RetryAfter)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestRequesterHonoursRetryAfter(t *testing.T) {
+	server, calls := newFlakyServer(t, http.Header{"Retry-After-Ms": {"20"}}, http.StatusTooManyRequests)
+	r := newRequester(ProviderOpenAI, server.Client(), nil, testRetryPolicy)
+
+	start := time.Now()
+	_, err := postBody(t, r, server.URL)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRequesterHedgesSlowRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first copy hangs until it's cancelled. the server only notices
+		// the client went away once the body was read
+		if calls.Add(1) == 1 {
+			io.Copy(io.Discard, r.Body) //nolint:errcheck
+			<-r.Context().Done()
+			return
+		}
+
+		fmt.Fprint(w, `{"hedged":true}`) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	policy := testRetryPolicy
+	policy.HedgeDelay = 10 * time.Millisecond
+	r := newRequester(ProviderOpenAI, server.Client(), nil, policy)
+
+	body, err := r.postHedged(context.Background(), server.URL, map[string]string{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"hedged":true}`, string(body))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"missing", http.Header{}, 0},
+		{"seconds", http.Header{"Retry-After": {"2"}}, 2 * time.Second},
+		{"milliseconds first", http.Header{"Retry-After": {"2"}, "Retry-After-Ms": {"150"}}, 150 * time.Millisecond},
+		{"http date", http.Header{"Retry-After": {now.Add(5 * time.Second).Format(http.TimeFormat)}}, 5 * time.Second},
+		{"date in the past", http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}}, 0},
+		{"garbage", http.Header{"Retry-After": {"soon"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryAfter(tt.header, now))
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for range 100 {
+		assert.LessOrEqual(t, policy.backoff(1), 100*time.Millisecond)
+		assert.LessOrEqual(t, policy.backoff(3), 400*time.Millisecond)
+		assert.LessOrEqual(t, policy.backoff(50), time.Second)
+		assert.Positive(t, policy.backoff(50))
+	}
+}
+
+func TestUserMessage(t *testing.T) {
+	rejected := &APIError{Provider: ProviderOpenAI, Kind: ErrorAuth, StatusCode: http.StatusUnauthorized}
+
+	assert.Contains(t, UserMessage(fmt.Errorf("failed to generate code: %w", rejected), true), "rejected your API key")
+	assert.Contains(t, UserMessage(rejected, false), "temporarily unavailable")
+	assert.Contains(t, UserMessage(fmt.Errorf("anthropic %w", ErrCircuitOpen), false), "temporarily unavailable")
+	assert.Empty(t, UserMessage(context.Canceled, true))
+}
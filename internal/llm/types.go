@@ -1,6 +1,14 @@
 package llm
 
-import "context"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
 
 // combines query transformation, embedding generation, and text generation
 type LLM interface {
@@ -46,6 +54,22 @@ type TextGenerator interface {
 	Model() string
 }
 
+// width of the embedding columns in the database (text-embedding-3-small)
+const EmbeddingDimensions = 1536
+
+// zero-pads embeddings from models with fewer dimensions so they fit the
+// embedding columns. padding leaves cosine similarity unchanged, but
+// embeddings from different models still can't be compared
+type paddedEmbedder struct {
+	Embedder
+}
+
+// records request latency and token usage of the wrapped generator
+type instrumentedGenerator struct {
+	TextGenerator
+	provider Provider
+}
+
 // contains inputs for text generation
 type TextGenerationRequest struct {
 	SystemPrompt string    // system-level instructions
@@ -55,8 +79,10 @@ type TextGenerationRequest struct {
 
 // contains output from text generation
 type TextGenerationResponse struct {
-	Text  string // generated text
-	Usage Usage  // token usage statistics
+	Text     string // generated text
+	Usage    Usage  // token usage statistics
+	Model    string // model that answered when it may differ from Model(), set by failover
+	Fallback bool   // answered by the fallback provider because the primary is failing
 }
 
 // contains token usage statistics from llm api calls
@@ -74,8 +100,183 @@ type Message struct {
 const (
 	ProviderAnthropic Provider = "anthropic"
 	ProviderOpenAI    Provider = "openai"
+	ProviderGemini    Provider = "gemini"
+	ProviderMistral   Provider = "mistral"
+	ProviderOllama    Provider = "ollama" // self-hosted, registered when OLLAMA_BASE_URL is set
+)
+
+// defaults for generators created from user-provided API keys
+const (
+	byokAnthropicModel = "claude-sonnet-4-20250514"
+	byokOpenAIModel    = "gpt-4o"
+	byokGeminiModel    = "gemini-2.5-flash"
+	byokMistralModel   = "mistral-large-latest"
+	byokOllamaModel    = "llama3.1"
+	byokMaxTokens      = 4096
+	byokTemperature    = 0.7
+
+	mistralChatCompletionsURL = "https://api.mistral.ai/v1/chat/completions"
+)
+
+var (
+	ErrUnsupportedProvider = errors.New("unsupported provider")
+	ErrModelNotAllowed     = errors.New("model not allowed for provider")
+	ErrCircuitOpen         = errors.New("provider circuit open")
+)
+
+// models of fallback components without a configured model. ollama models
+// default in the client
+var (
+	fallbackTransformerModels = map[Provider]string{
+		ProviderAnthropic: "claude-3-haiku-20240307",
+		ProviderOpenAI:    "gpt-4o-mini",
+	}
+	fallbackGeneratorModels = map[Provider]string{
+		ProviderAnthropic: "claude-sonnet-4-20250514",
+		ProviderOpenAI:    "gpt-4o",
+	}
+)
+
+// circuit breaker settings
+const (
+	// weight of the latest call in a provider's health score
+	breakerDecay = 0.2
+
+	// health under which the circuit opens: four failures in a row from
+	// full health, or about every other call failing
+	breakerOpenBelow = 0.5
+
+	// how long an open circuit skips its provider before a trial call
+	breakerCooldown = 30 * time.Second
+
+	// health a circuit closes with after a successful trial call, so a
+	// provider that keeps failing opens again quickly
+	breakerRecoveredHealth = 0.7
+)
+
+// states of a provider's circuit
+const (
+	CircuitClosed   = "closed"    // healthy, receives calls
+	CircuitOpen     = "open"      // failing, skipped until the cooldown ends
+	CircuitHalfOpen = "half_open" // cooldown over, a trial call decides
 )
 
+// tracks the health of one provider and stops calling it while it fails
+type circuitBreaker struct {
+	mu       sync.Mutex
+	health   float64 // 0 to 1, decaying average of call outcomes
+	open     bool
+	openedAt time.Time
+	probing  bool // a trial call of a half-open circuit is running
+	now      func() time.Time
+}
+
+// a component's client for one provider, behind its circuit breaker
+type circuit[T any] struct {
+	provider Provider
+	model    string
+	client   T
+	breaker  *circuitBreaker
+}
+
+// calls a component's primary provider, and its fallback while the primary's
+// circuit is open or when a call to it fails. without a fallback, calls fail
+// fast while the circuit is open instead of waiting for a failing provider
+type failover[T any] struct {
+	component string // "transformer", "generator" or "embedder"
+	primary   *circuit[T]
+	fallback  *circuit[T] // nil when no fallback is configured
+}
+
+// failover for the query transformer, which also generates auxiliary text
+type failoverTransformer struct {
+	*failover[transformerClient]
+}
+
+type failoverGenerator struct {
+	*failover[TextGenerator]
+}
+
+type failoverEmbedder struct {
+	*failover[Embedder]
+}
+
+// the state of a component's provider
+type CircuitStatus struct {
+	Component string   `json:"component"`
+	Provider  Provider `json:"provider"`
+	Fallback  bool     `json:"fallback"` // the component's fallback provider
+	State     string   `json:"state"`
+	Health    float64  `json:"health"` // 0 to 1, weighted towards recent calls
+}
+
+// default retry settings, used for policy fields left zero
+const (
+	defaultRetryAttempts  = 3
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+
+	// embeddings answer in well under a second, a request still waiting
+	// after this is likely stuck behind a slow replica
+	defaultHedgeDelay = 2 * time.Second
+)
+
+// how a client retries API calls that failed for transient reasons
+type RetryPolicy struct {
+	MaxAttempts int           // including the first call, 1 disables retries
+	BaseDelay   time.Duration // wait before the first retry, doubling per retry, with full jitter
+	MaxDelay    time.Duration // longest wait; a longer Retry-After fails the call instead
+
+	// idempotent calls (embeddings) send a second copy of a request that
+	// hasn't answered after this long and use whichever answers first.
+	// 0 disables hedging
+	HedgeDelay time.Duration
+}
+
+// sends a client's API requests, retrying transient failures
+type requester struct {
+	provider Provider
+	client   *http.Client
+	limiter  *rate.Limiter // nil for APIs that enforce their own limits
+	policy   RetryPolicy
+}
+
+// why a provider API call failed
+type ErrorKind string
+
+const (
+	ErrorRateLimited    ErrorKind = "rate_limited"    // 429, retried
+	ErrorUnavailable    ErrorKind = "unavailable"     // 5xx or no connection, retried
+	ErrorTimeout        ErrorKind = "timeout"         // no answer in time
+	ErrorAuth           ErrorKind = "auth"            // API key rejected
+	ErrorInvalidRequest ErrorKind = "invalid_request" // other 4xx, e.g. prompt too long or unknown model
+)
+
+// a failed provider API call
+type APIError struct {
+	Provider   Provider
+	Kind       ErrorKind
+	StatusCode int           // 0 when no response arrived
+	Body       string        // response body of the failed call
+	RetryAfter time.Duration // how long the provider asked to wait, 0 if it didn't say
+	Err        error         // transport error when no response arrived
+}
+
+// a provider that BYOK requests can be routed to
+type ProviderSpec struct {
+	Name         Provider `json:"name"`
+	DefaultModel string   `json:"default_model"`
+	Models       []string `json:"models"` // allowlist, always contains DefaultModel
+	BaseURL      string   `json:"-"`      // endpoint of OpenAI-compatible APIs
+}
+
+// the providers and models BYOK requests may pick from
+type Registry struct {
+	providers       map[Provider]ProviderSpec
+	order           []Provider
+	defaultProvider Provider
+}
+
 // holds configuration for llm initialization
 type Config struct {
 	// transformer configuration (query expansion)
@@ -96,4 +297,26 @@ type Config struct {
 	EmbedderProvider Provider
 	EmbedderAPIKey   string
 	EmbedderModel    string // e.g., "text-embedding-3-small"
+
+	// ollama server used by any component configured with the ollama provider
+	OllamaBaseURL string // e.g., "http://localhost:11434"
+
+	// providers the components fail over to while their primary is failing.
+	// an empty provider disables failover. the embedder's fallback must use
+	// the same model, embeddings of different models can't be compared
+	TransformerFallbackProvider Provider
+	TransformerFallbackAPIKey   string
+	TransformerFallbackModel    string
+	GeneratorFallbackProvider   Provider
+	GeneratorFallbackAPIKey     string
+	GeneratorFallbackModel      string
+	EmbedderFallbackProvider    Provider
+	EmbedderFallbackAPIKey      string
+
+	// ollama server of fallback components, defaults to OllamaBaseURL
+	OllamaFallbackBaseURL string
+
+	// retries of all components' API calls; the hedge delay only applies
+	// to the embedder
+	Retry RetryPolicy
 }
@@ -7,6 +7,8 @@ func getAPIKeyForProvider(provider Provider, baseConfig *config.Config) string {
 	switch provider {
 	case ProviderOpenAI:
 		return baseConfig.OpenAIKey
+	case ProviderOllama:
+		return ""
 	default:
 		return baseConfig.AnthropicKey
 	}
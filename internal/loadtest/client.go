@@ -0,0 +1,258 @@
+package loadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"codeberg.org/algopatterns/server/internal/errors"
+	ws "codeberg.org/algopatterns/server/internal/websocket"
+)
+
+// the code every session starts with
+const initialCode = `s("bd sd")`
+
+// patterns clients cycle through, so lint and the document see real edits
+var patterns = []string{
+	`s("bd sd")`,
+	`s("bd*2 [~ sd] hh*4")`,
+	`note("c3 e3 g3").s("sawtooth")`,
+	`s("bd ~ sd ~").bank("tr909")`,
+}
+
+// a simulated participant: it sends code updates at the configured rate and
+// times the ones it receives from the rest of its session
+type client struct {
+	name      string
+	sessionID string
+	host      bool
+	conn      *websocket.Conn
+
+	sent         int
+	rejected     atomic.Int64
+	deliveries   atomic.Int64
+	disconnected bool
+	latencies    []float64 // milliseconds, written by the read loop only
+
+	// agent requests waiting for their response, by request id
+	agentMu        sync.Mutex
+	agentPending   map[string]time.Time
+	agentSent      int
+	agentResponses int
+	agentErrors    int
+	agentLatencies []float64
+
+	stopping atomic.Bool
+	done     chan struct{}
+}
+
+func dial(serverURL, sessionID, name string, host bool) (*client, error) {
+	role := "co-author"
+	if host {
+		role = "host"
+	}
+
+	query := url.Values{"session_id": {sessionID}, "name": {name}, "role": {role}}
+	conn, _, err := websocket.DefaultDialer.Dial(serverURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect %s: %w", name, err)
+	}
+
+	c := &client{
+		name:         name,
+		sessionID:    sessionID,
+		host:         host,
+		conn:         conn,
+		agentPending: make(map[string]time.Time),
+		done:         make(chan struct{}),
+	}
+	go c.read()
+
+	return c, nil
+}
+
+func (c *client) read() {
+	defer close(c.done)
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.disconnected = !c.stopping.Load()
+			return
+		}
+
+		received := time.Now()
+
+		// the server batches queued messages into one frame, newline separated
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var msg ws.Message
+			if err := decoder.Decode(&msg); err != nil {
+				break
+			}
+
+			c.handle(&msg, received)
+		}
+	}
+}
+
+func (c *client) handle(msg *ws.Message, received time.Time) {
+	switch msg.Type {
+	case ws.TypeCodeUpdate:
+		c.deliveries.Add(1)
+
+		var payload ws.CodeUpdatePayload
+		if msg.UnmarshalPayload(&payload) == nil {
+			if sentAt, ok := sentAt(payload.Code); ok {
+				c.latencies = append(c.latencies, milliseconds(received.Sub(sentAt)))
+			}
+		}
+
+	case ws.TypeAgentResponseDone:
+		var payload ws.AgentResponseDonePayload
+		if msg.UnmarshalPayload(&payload) == nil {
+			c.finishAgentRequest(payload.RequestID, received, false)
+		}
+
+	case ws.TypeError:
+		var payload errors.ErrorResponse
+		if msg.UnmarshalPayload(&payload) != nil {
+			return
+		}
+
+		switch {
+		case payload.RequestID != nil:
+			c.finishAgentRequest(*payload.RequestID, received, true)
+		case payload.Error == "too_many_requests":
+			c.rejected.Add(1)
+		}
+	}
+}
+
+// sends code updates, and agent requests from the host, until stop closes
+func (c *client) run(cfg Config, stop <-chan struct{}) {
+	interval := time.Duration(float64(time.Second) / cfg.UpdateRate)
+
+	// clients start spread over the interval instead of in lockstep
+	select {
+	case <-time.After(rand.N(interval)):
+	case <-stop:
+		return
+	}
+
+	updates := time.NewTicker(interval)
+	defer updates.Stop()
+
+	var agentRequests <-chan time.Time
+	if c.host && cfg.AgentInterval > 0 {
+		ticker := time.NewTicker(cfg.AgentInterval)
+		defer ticker.Stop()
+		agentRequests = ticker.C
+	}
+
+	if err := c.sendCodeUpdate(); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-updates.C:
+			if err := c.sendCodeUpdate(); err != nil {
+				return
+			}
+		case <-agentRequests:
+			if err := c.sendAgentRequest(); err != nil {
+				return
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (c *client) sendCodeUpdate() error {
+	code := fmt.Sprintf("// %d\n%s", time.Now().UnixNano(), patterns[c.sent%len(patterns)])
+	c.sent++
+
+	return c.send(ws.TypeCodeUpdate, ws.CodeUpdatePayload{Code: code, Source: "typed"})
+}
+
+func (c *client) sendAgentRequest() error {
+	c.agentMu.Lock()
+	c.agentSent++
+	requestID := fmt.Sprintf("%s-%d", c.name, c.agentSent)
+	c.agentPending[requestID] = time.Now()
+	c.agentMu.Unlock()
+
+	return c.send(ws.TypeAgentRequest, ws.AgentRequestPayload{
+		RequestID:      requestID,
+		UserQuery:      "make the drums busier",
+		EditorState:    initialCode,
+		Provider:       "ollama",
+		ProviderAPIKey: "loadtest",
+	})
+}
+
+func (c *client) finishAgentRequest(requestID string, at time.Time, failed bool) {
+	c.agentMu.Lock()
+	defer c.agentMu.Unlock()
+
+	sentAt, ok := c.agentPending[requestID]
+	if !ok {
+		return
+	}
+	delete(c.agentPending, requestID)
+
+	if failed {
+		c.agentErrors++
+		return
+	}
+
+	c.agentResponses++
+	c.agentLatencies = append(c.agentLatencies, milliseconds(at.Sub(sentAt)))
+}
+
+func (c *client) send(msgType string, payload any) error {
+	msg, err := ws.NewMessage(msgType, c.sessionID, "", payload)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// closes the connection and waits for the read loop to finish
+func (c *client) close() {
+	c.stopping.Store(true)
+	c.conn.Close() //nolint:errcheck,gosec // leaving anyway
+	<-c.done
+}
+
+// the send time a client wrote into the first line of its code
+func sentAt(code string) (time.Time, bool) {
+	line, _, _ := strings.Cut(code, "\n")
+	nanos, err := strconv.ParseInt(strings.TrimPrefix(line, "// "), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, nanos), true
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,249 @@
+// Package loadtest drives the websocket hub with simulated sessions to
+// measure broadcast latency, dropped sends and memory growth. the hub runs
+// in-process with the real handlers; only postgres and the model are stubbed
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// runs the simulation described by cfg and reports what it measured.
+// cancelling ctx ends the run early, still reporting on it
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	cfg = cfg.withDefaults()
+
+	srv, err := startServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer srv.stop()
+
+	memory := Memory{}
+	memory.HeapBeforeMB, memory.GoroutinesStart = settledMemory()
+
+	sampler := startSampler()
+
+	clients, err := connect(srv, cfg)
+	if err != nil {
+		sampler.stop()
+		return nil, err
+	}
+
+	logger.Info("load test running",
+		"sessions", cfg.Sessions,
+		"clients", len(clients),
+		"duration", cfg.Duration,
+	)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.run(cfg, stop)
+		}()
+	}
+
+	select {
+	case <-time.After(cfg.Duration):
+	case <-ctx.Done():
+	}
+	close(stop)
+	wg.Wait()
+
+	waitForDeliveries(clients, cfg)
+
+	for _, c := range clients {
+		c.close()
+	}
+	waitForHubToEmpty(srv)
+
+	memory.HeapPeakMB = sampler.stop()
+	memory.HeapAfterMB, memory.GoroutinesEnd = settledMemory()
+	memory.HeapGrowthMB = memory.HeapAfterMB - memory.HeapBeforeMB
+
+	report := summarize(cfg, clients)
+	report.Memory = memory
+
+	// the flusher writes what is left when it stops
+	srv.stop()
+	report.StoreWrites = int(srv.store.codeWrites.Load())
+
+	return report, nil
+}
+
+// opens every session's connections, the host first so it creates the session
+func connect(srv *server, cfg Config) ([]*client, error) {
+	run := time.Now().UnixNano()
+	clients := make([]*client, 0, cfg.Sessions*cfg.ClientsPerSession)
+
+	for s := range cfg.Sessions {
+		sessionID := fmt.Sprintf("loadtest-%d-%d", run, s)
+
+		for i := range cfg.ClientsPerSession {
+			c, err := dial(srv.url(), sessionID, fmt.Sprintf("s%d-c%d", s, i), i == 0)
+			if err != nil {
+				for _, open := range clients {
+					open.close()
+				}
+				return nil, err
+			}
+
+			clients = append(clients, c)
+		}
+	}
+
+	return clients, nil
+}
+
+// waits until every accepted update has reached the rest of its session, or
+// until the settle time is up
+func waitForDeliveries(clients []*client, cfg Config) {
+	deadline := time.Now().Add(cfg.Settle)
+
+	for time.Now().Before(deadline) {
+		expected, delivered := deliveries(clients, cfg)
+		if delivered >= expected {
+			return
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// the code updates clients should have received and the ones they did
+func deliveries(clients []*client, cfg Config) (expected, delivered int) {
+	for _, c := range clients {
+		accepted := c.sent - int(c.rejected.Load())
+		expected += accepted * (cfg.ClientsPerSession - 1)
+		delivered += int(c.deliveries.Load())
+	}
+
+	return expected, delivered
+}
+
+func waitForHubToEmpty(srv *server) {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for srv.hub.Stats().Connections > 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func summarize(cfg Config, clients []*client) *Report {
+	report := &Report{
+		Sessions:          cfg.Sessions,
+		ClientsPerSession: cfg.ClientsPerSession,
+		DurationSeconds:   cfg.Duration.Seconds(),
+		UpdateRate:        cfg.UpdateRate,
+	}
+
+	var broadcast, agent []float64
+	for _, c := range clients {
+		report.CodeUpdatesSent += c.sent
+		report.CodeUpdatesRejected += int(c.rejected.Load())
+		report.Deliveries += int(c.deliveries.Load())
+		broadcast = append(broadcast, c.latencies...)
+
+		if c.disconnected {
+			report.Disconnects++
+		}
+
+		c.agentMu.Lock()
+		report.AgentRequests += c.agentSent
+		report.AgentResponses += c.agentResponses
+		report.AgentErrors += c.agentErrors
+		agent = append(agent, c.agentLatencies...)
+		c.agentMu.Unlock()
+	}
+
+	expected, delivered := deliveries(clients, cfg)
+	report.DroppedSends = max(expected-delivered, 0)
+	report.BroadcastLatency = percentiles(broadcast)
+	report.AgentLatency = percentiles(agent)
+
+	return report
+}
+
+// nearest-rank percentiles of samples in milliseconds
+func percentiles(samples []float64) Latency {
+	if len(samples) == 0 {
+		return Latency{}
+	}
+
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)]
+	}
+
+	return Latency{
+		Samples: len(sorted),
+		P50:     rank(0.50),
+		P95:     rank(0.95),
+		P99:     rank(0.99),
+		Max:     sorted[len(sorted)-1],
+	}
+}
+
+// the live heap after a collection, and the goroutines still running
+func settledMemory() (float64, int) {
+	runtime.GC()
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return megabytes(stats.HeapAlloc), runtime.NumGoroutine()
+}
+
+// samples the heap while clients run, remembering the largest
+type sampler struct {
+	done chan struct{}
+	peak chan float64
+}
+
+func startSampler() *sampler {
+	s := &sampler{done: make(chan struct{}), peak: make(chan float64)}
+
+	go func() {
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+
+		var peak float64
+		var stats runtime.MemStats
+		for {
+			runtime.ReadMemStats(&stats)
+			peak = max(peak, megabytes(stats.HeapAlloc))
+
+			select {
+			case <-ticker.C:
+			case <-s.done:
+				s.peak <- peak
+				return
+			}
+		}
+	}()
+
+	return s
+}
+
+// stops sampling and returns the peak heap in megabytes
+func (s *sampler) stop() float64 {
+	close(s.done)
+	return <-s.peak
+}
+
+func megabytes(bytes uint64) float64 {
+	return float64(bytes) / (1 << 20)
+}
@@ -0,0 +1,174 @@
+package loadtest
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPercentiles(t *testing.T) {
+	samples := make([]float64, 0, 100)
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, float64(i))
+	}
+
+	got := percentiles(samples)
+	want := Latency{Samples: 100, P50: 50, P95: 95, P99: 99, Max: 100}
+	if got != want {
+		t.Errorf("percentiles = %+v, want %+v", got, want)
+	}
+
+	if samples[0] != 100 {
+		t.Error("percentiles sorted the caller's samples")
+	}
+
+	if got := percentiles(nil); got != (Latency{}) {
+		t.Errorf("percentiles of no samples = %+v, want zero", got)
+	}
+
+	if got := percentiles([]float64{7}); got.P50 != 7 || got.P99 != 7 {
+		t.Errorf("percentiles of one sample = %+v, want 7 throughout", got)
+	}
+}
+
+func TestSentAt(t *testing.T) {
+	at := time.Unix(0, 1_700_000_000_123_456_789)
+
+	got, ok := sentAt("// 1700000000123456789\n" + initialCode)
+	if !ok || !got.Equal(at) {
+		t.Errorf("sentAt = %v, %v, want %v", got, ok, at)
+	}
+
+	if _, ok := sentAt(initialCode); ok {
+		t.Error("expected code without a timestamp to have no send time")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Report{
+		BroadcastLatency: Latency{P50: 5, P95: 20},
+		AgentLatency:     Latency{P95: 200},
+		Memory:           Memory{HeapGrowthMB: 10},
+	}
+
+	t.Run("within tolerance", func(t *testing.T) {
+		current := &Report{
+			BroadcastLatency: Latency{P50: 5.5, P95: 23},
+			AgentLatency:     Latency{P95: 190},
+			Memory:           Memory{HeapGrowthMB: 11},
+		}
+
+		if regressions := Compare(baseline, current, 0.2); len(regressions) != 0 {
+			t.Errorf("expected no regressions, got %+v", regressions)
+		}
+	})
+
+	t.Run("below the noise floor", func(t *testing.T) {
+		small := &Report{BroadcastLatency: Latency{P50: 0.5, P95: 1}}
+		current := &Report{BroadcastLatency: Latency{P50: 1, P95: 2.5}}
+
+		if regressions := Compare(small, current, 0.2); len(regressions) != 0 {
+			t.Errorf("expected sub-millisecond differences to be ignored, got %+v", regressions)
+		}
+	})
+
+	t.Run("regressed", func(t *testing.T) {
+		current := &Report{
+			BroadcastLatency: Latency{P50: 5, P95: 40},
+			AgentLatency:     Latency{P95: 200},
+			Memory:           Memory{HeapGrowthMB: 30},
+			DroppedSends:     1,
+			Disconnects:      2,
+		}
+
+		regressions := Compare(baseline, current, 0.2)
+
+		metrics := map[string]bool{}
+		for _, r := range regressions {
+			metrics[r.Metric] = true
+		}
+
+		for _, metric := range []string{"broadcast p95 ms", "heap growth mb", "dropped sends", "disconnects"} {
+			if !metrics[metric] {
+				t.Errorf("expected %q to regress, got %+v", metric, regressions)
+			}
+		}
+
+		if len(regressions) != 4 {
+			t.Errorf("expected 4 regressions, got %+v", regressions)
+		}
+	})
+}
+
+func TestReportRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	report := &Report{Sessions: 2, BroadcastLatency: Latency{Samples: 3, P95: 1.5}, DroppedSends: 1}
+
+	if err := SaveReport(path, report); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+
+	loaded, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("LoadReport: %v", err)
+	}
+
+	if *loaded != *report {
+		t.Errorf("loaded %+v, want %+v", loaded, report)
+	}
+
+	var out strings.Builder
+	if err := WriteReport(&out, loaded, []Regression{{Metric: "dropped sends", Baseline: 0, Current: 1}}); err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "regressions against baseline") {
+		t.Errorf("expected regressions in the output, got:\n%s", out.String())
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the hub for a few seconds")
+	}
+
+	report, err := Run(context.Background(), Config{
+		Sessions:          2,
+		ClientsPerSession: 3,
+		Duration:          time.Second,
+		UpdateRate:        5,
+		AgentInterval:     300 * time.Millisecond,
+		LLMLatency:        5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if report.CodeUpdatesSent == 0 {
+		t.Fatal("expected clients to send code updates")
+	}
+
+	if report.DroppedSends != 0 || report.Disconnects != 0 {
+		t.Errorf("expected every update delivered, got %d dropped and %d disconnects", report.DroppedSends, report.Disconnects)
+	}
+
+	// every accepted update reaches the other two clients of its session
+	accepted := report.CodeUpdatesSent - report.CodeUpdatesRejected
+	if report.Deliveries != accepted*2 {
+		t.Errorf("expected %d deliveries, got %d", accepted*2, report.Deliveries)
+	}
+
+	if report.BroadcastLatency.Samples != report.Deliveries {
+		t.Errorf("expected a latency sample per delivery, got %d for %d", report.BroadcastLatency.Samples, report.Deliveries)
+	}
+
+	if report.AgentResponses == 0 || report.AgentErrors != 0 {
+		t.Errorf("expected agent requests to be answered, got %d responses and %d errors", report.AgentResponses, report.AgentErrors)
+	}
+
+	if report.StoreWrites == 0 {
+		t.Error("expected code to reach the store")
+	}
+}
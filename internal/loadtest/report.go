@@ -0,0 +1,107 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// differences too small to call a regression whatever the tolerance, since
+// runs on the same machine vary by about this much
+const (
+	latencyNoiseMs = 2
+	memoryNoiseMB  = 2
+)
+
+// prints the report and any regressions against a baseline
+func WriteReport(w io.Writer, report *Report, regressions []Regression) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d sessions x %d clients, %.0fs at %.1f updates/s per client\n\n",
+		report.Sessions, report.ClientsPerSession, report.DurationSeconds, report.UpdateRate)
+
+	fmt.Fprintf(&b, "code updates   %d sent, %d rejected by the rate limit\n", report.CodeUpdatesSent, report.CodeUpdatesRejected)
+	fmt.Fprintf(&b, "deliveries     %d received, %d dropped, %d clients disconnected\n", report.Deliveries, report.DroppedSends, report.Disconnects)
+	fmt.Fprintf(&b, "store writes   %d\n", report.StoreWrites)
+	fmt.Fprintf(&b, "agent          %d requests, %d responses, %d errors\n\n", report.AgentRequests, report.AgentResponses, report.AgentErrors)
+
+	fmt.Fprintf(&b, "%-10s %8s %9s %9s %9s %9s\n", "LATENCY", "SAMPLES", "P50 MS", "P95 MS", "P99 MS", "MAX MS")
+	for _, row := range []struct {
+		name    string
+		latency Latency
+	}{
+		{"broadcast", report.BroadcastLatency},
+		{"agent", report.AgentLatency},
+	} {
+		l := row.latency
+		fmt.Fprintf(&b, "%-10s %8d %9.2f %9.2f %9.2f %9.2f\n", row.name, l.Samples, l.P50, l.P95, l.P99, l.Max)
+	}
+
+	m := report.Memory
+	fmt.Fprintf(&b, "\nheap           %.1f MB before, %.1f MB peak, %.1f MB after (%+.1f MB)\n", m.HeapBeforeMB, m.HeapPeakMB, m.HeapAfterMB, m.HeapGrowthMB)
+	fmt.Fprintf(&b, "goroutines     %d before, %d after\n", m.GoroutinesStart, m.GoroutinesEnd)
+
+	if len(regressions) > 0 {
+		b.WriteString("\nregressions against baseline:\n")
+		for _, r := range regressions {
+			fmt.Fprintf(&b, "  %s: %.2f -> %.2f\n", r.Metric, r.Baseline, r.Current)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writes report as JSON, for use as a later run's baseline
+func SaveReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// returns the metrics that got worse than the baseline by more than
+// tolerance, a fraction of the baseline. any dropped send or disconnect
+// beyond the baseline's is a regression
+func Compare(baseline, current *Report, tolerance float64) []Regression {
+	var regressions []Regression
+
+	check := func(metric string, base, cur, noise float64) {
+		if cur > base*(1+tolerance) && cur-base > noise {
+			regressions = append(regressions, Regression{Metric: metric, Baseline: base, Current: cur})
+		}
+	}
+
+	check("broadcast p95 ms", baseline.BroadcastLatency.P95, current.BroadcastLatency.P95, latencyNoiseMs)
+	check("broadcast p50 ms", baseline.BroadcastLatency.P50, current.BroadcastLatency.P50, latencyNoiseMs)
+	check("agent p95 ms", baseline.AgentLatency.P95, current.AgentLatency.P95, latencyNoiseMs)
+	check("heap growth mb", baseline.Memory.HeapGrowthMB, current.Memory.HeapGrowthMB, memoryNoiseMB)
+
+	if current.DroppedSends > baseline.DroppedSends {
+		regressions = append(regressions, Regression{Metric: "dropped sends", Baseline: float64(baseline.DroppedSends), Current: float64(current.DroppedSends)})
+	}
+
+	if current.Disconnects > baseline.Disconnects {
+		regressions = append(regressions, Regression{Metric: "disconnects", Baseline: float64(baseline.Disconnects), Current: float64(current.Disconnects)})
+	}
+
+	return regressions
+}
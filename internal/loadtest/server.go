@@ -0,0 +1,230 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/buffer"
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/retriever"
+	ws "codeberg.org/algopatterns/server/internal/websocket"
+)
+
+// the model the stub LLM answers as
+const stubModel = "loadtest"
+
+// the hub wired up the way cmd/server wires it, with the database and the
+// model replaced by stubs
+type server struct {
+	hub     *ws.Hub
+	store   *store
+	buffer  *buffer.SessionBuffer
+	flusher *buffer.Flusher
+	http    *httptest.Server
+	llm     *httptest.Server
+
+	stopOnce sync.Once
+}
+
+func startServer(cfg Config) (*server, error) {
+	s := &server{
+		hub:   ws.NewHub(),
+		store: &store{latency: cfg.StoreLatency},
+		llm:   httptest.NewServer(stubLLM(cfg.LLMLatency)),
+	}
+
+	var sessionRepo sessions.Repository = s.store
+	if cfg.RedisURL != "" {
+		sessionBuffer, err := buffer.NewSessionBuffer(buffer.RedisModeSingle, cfg.RedisURL, cfg.FlushInterval)
+		if err != nil {
+			s.llm.Close()
+			return nil, err
+		}
+
+		s.buffer = sessionBuffer
+		s.flusher = buffer.NewFlusher(sessionBuffer, s.store, cfg.FlushInterval)
+		sessionRepo = buffer.NewBufferedRepository(s.store, sessionBuffer)
+
+		s.hub.SetCodeHistory(sessionBuffer)
+		s.hub.SetActivityRecorder(sessionBuffer)
+		s.hub.SetChatBackpressure(sessionBuffer)
+		s.flusher.Start()
+	}
+
+	agentClient := agent.New(stubRetriever{}, nil)
+	providers := llm.NewRegistry(llm.ProviderOllama, llm.ProviderSpec{
+		Name:         llm.ProviderOllama,
+		DefaultModel: stubModel,
+		BaseURL:      s.llm.URL,
+	})
+
+	s.hub.RegisterHandler(ws.TypeCodeUpdate, ws.CodeUpdateHandler(sessionRepo, nil))
+	s.hub.RegisterHandler(ws.TypeCodeOperation, ws.CodeOperationHandler(nil))
+	s.hub.RegisterHandler(ws.TypePing, ws.PingHandler())
+	s.hub.RegisterHandler(ws.TypeAgentRequest, ws.AgentRequestHandler(agentClient, providers, nil, nil, nil, nil))
+
+	s.hub.OnDocumentSnapshot(func(sessionID, code string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		sessionRepo.UpdateSessionCode(ctx, sessionID, code) //nolint:errcheck,gosec // the store doesn't fail
+	})
+
+	go s.hub.Run()
+
+	s.http = httptest.NewServer(http.HandlerFunc(s.connect))
+
+	return s, nil
+}
+
+// the websocket URL clients connect to
+func (s *server) url() string {
+	return "ws" + strings.TrimPrefix(s.http.URL, "http") + "/ws"
+}
+
+func (s *server) stop() {
+	s.stopOnce.Do(func() {
+		s.http.Close()
+		s.hub.Shutdown()
+
+		if s.flusher != nil {
+			s.flusher.Stop()
+			s.buffer.Close() //nolint:errcheck,gosec // nothing left to flush
+		}
+
+		s.llm.Close()
+	})
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(*http.Request) bool { return true }, // loopback only
+}
+
+// joins the session named in the query as the given role, skipping the
+// session lookups and connection limits of the real handler
+func (s *server) connect(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	clientID, err := ws.GenerateClientID()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	name := query.Get("name")
+	client := ws.NewClient(clientID, query.Get("session_id"), "user-"+name, name, query.Get("role"), "127.0.0.1", initialCode, nil, true, conn, s.hub)
+
+	s.hub.Register <- client
+
+	go client.WritePump()
+	go client.ReadPump()
+}
+
+// answers every chat request with a short pattern streamed in a few chunks,
+// the way ollama streams
+func stubLLM(latency time.Duration) http.Handler {
+	chunks := []string{"```js\n", `s("bd*2, hh*4")`, "\n  .bank(\"tr909\")", "\n```"}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher, _ := w.(http.Flusher)
+		encoder := json.NewEncoder(w)
+
+		for _, chunk := range chunks {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+
+			encoder.Encode(map[string]any{"message": map[string]string{"role": "assistant", "content": chunk}}) //nolint:errcheck,gosec // the client may be gone
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		encoder.Encode(map[string]any{"done": true, "prompt_eval_count": 1200, "eval_count": 40}) //nolint:errcheck,gosec // the client may be gone
+	})
+}
+
+// finds no documentation or examples, so prompts go to the model bare
+type stubRetriever struct{}
+
+func (stubRetriever) HybridSearchDocs(_ context.Context, _, _ string, _ int) ([]retriever.SearchResult, error) {
+	return nil, nil
+}
+
+func (stubRetriever) HybridSearchExamples(_ context.Context, _, _ string, _ int) ([]retriever.ExampleResult, error) {
+	return nil, nil
+}
+
+// stands in for postgres behind the handlers and the flusher, counting the
+// code that reaches it. other repository methods are never called
+type store struct {
+	sessions.Repository
+
+	latency    time.Duration
+	codeWrites atomic.Int64
+}
+
+func (s *store) write() {
+	if s.latency > 0 {
+		time.Sleep(s.latency)
+	}
+}
+
+func (s *store) UpdateSessionCode(_ context.Context, _, _ string) error {
+	s.write()
+	s.codeWrites.Add(1)
+
+	return nil
+}
+
+func (s *store) UpdateSessionCodes(_ context.Context, codes []sessions.SessionCode) error {
+	s.write()
+	s.codeWrites.Add(int64(len(codes)))
+
+	return nil
+}
+
+func (s *store) AddCodeRevision(_ context.Context, _, _ string, _ time.Time) error {
+	s.write()
+	return nil
+}
+
+func (s *store) AddCodeRevisions(_ context.Context, _ []sessions.CodeRevision) error {
+	s.write()
+	return nil
+}
+
+func (s *store) AddActivity(_ context.Context, _ *sessions.ActivitySample) error {
+	s.write()
+	return nil
+}
+
+func (s *store) AddActivities(_ context.Context, _ []sessions.ActivitySample) error {
+	s.write()
+	return nil
+}
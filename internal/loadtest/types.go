@@ -0,0 +1,93 @@
+package loadtest
+
+import (
+	"time"
+)
+
+// what a run simulates
+type Config struct {
+	Sessions          int           // concurrent sessions
+	ClientsPerSession int           // writers per session, the first one hosts
+	Duration          time.Duration // how long clients keep sending code updates
+	UpdateRate        float64       // code updates per second per client
+	AgentInterval     time.Duration // time between agent requests of a session's host, 0 sends none
+	LLMLatency        time.Duration // delay of the stub model before each streamed chunk
+	RedisURL          string        // buffer writes in redis and flush them as the server does; empty writes straight to the store
+	FlushInterval     time.Duration // how often the flusher empties the buffer
+	StoreLatency      time.Duration // added to every store write, standing in for postgres
+	Settle            time.Duration // how long to wait for messages in flight once sending stops
+}
+
+// fills in the defaults of unset fields
+func (c Config) withDefaults() Config {
+	if c.Sessions <= 0 {
+		c.Sessions = 10
+	}
+	if c.ClientsPerSession <= 0 {
+		c.ClientsPerSession = 4
+	}
+	if c.Duration <= 0 {
+		c.Duration = 30 * time.Second
+	}
+	if c.UpdateRate <= 0 {
+		c.UpdateRate = 2
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = 5 * time.Second
+	}
+	if c.Settle <= 0 {
+		c.Settle = 2 * time.Second
+	}
+
+	return c
+}
+
+// latency percentiles in milliseconds
+type Latency struct {
+	Samples int     `json:"samples"`
+	P50     float64 `json:"p50_ms"`
+	P95     float64 `json:"p95_ms"`
+	P99     float64 `json:"p99_ms"`
+	Max     float64 `json:"max_ms"`
+}
+
+// heap and goroutines before the clients connect, at the busiest point and
+// after they have all left. growth that outlives the run is a leak
+type Memory struct {
+	HeapBeforeMB    float64 `json:"heap_before_mb"`
+	HeapPeakMB      float64 `json:"heap_peak_mb"`
+	HeapAfterMB     float64 `json:"heap_after_mb"`
+	HeapGrowthMB    float64 `json:"heap_growth_mb"`
+	GoroutinesStart int     `json:"goroutines_before"`
+	GoroutinesEnd   int     `json:"goroutines_after"`
+}
+
+// the outcome of a run
+type Report struct {
+	Sessions          int     `json:"sessions"`
+	ClientsPerSession int     `json:"clients_per_session"`
+	DurationSeconds   float64 `json:"duration_seconds"`
+	UpdateRate        float64 `json:"update_rate"`
+
+	CodeUpdatesSent     int     `json:"code_updates_sent"`
+	CodeUpdatesRejected int     `json:"code_updates_rejected"` // refused by the rate limit
+	Deliveries          int     `json:"deliveries"`            // code_update messages received
+	DroppedSends        int     `json:"dropped_sends"`         // deliveries that never arrived
+	Disconnects         int     `json:"disconnects"`           // clients the server closed during the run
+	BroadcastLatency    Latency `json:"broadcast_latency"`
+
+	AgentRequests  int     `json:"agent_requests"`
+	AgentResponses int     `json:"agent_responses"`
+	AgentErrors    int     `json:"agent_errors"`
+	AgentLatency   Latency `json:"agent_latency"` // request to agent_response_done
+
+	StoreWrites int    `json:"store_writes"` // code writes that reached the store
+	Memory      Memory `json:"memory"`
+}
+
+// a metric that got worse than its baseline allows
+type Regression struct {
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+}
@@ -8,6 +8,9 @@ import (
 
 var (
 	defaultLogger *slog.Logger
+
+	// the minimum level logged, debug outside production
+	level = new(slog.LevelVar)
 )
 
 func init() {
@@ -16,14 +19,16 @@ func init() {
 	var handler slog.Handler
 
 	if env == "production" {
+		level.Set(slog.LevelInfo)
 		opts := &slog.HandlerOptions{
-			Level: slog.LevelInfo,
+			Level: level,
 		}
 
 		handler = slog.NewJSONHandler(os.Stdout, opts)
 	} else {
+		level.Set(slog.LevelDebug)
 		opts := &slog.HandlerOptions{
-			Level: slog.LevelDebug,
+			Level: level,
 		}
 
 		handler = slog.NewTextHandler(os.Stderr, opts)
@@ -32,6 +37,12 @@ func init() {
 	defaultLogger = slog.New(handler)
 }
 
+// changes the minimum level logged, e.g. to keep tools that run the server
+// in-process quiet
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
 func Default() *slog.Logger {
 	return defaultLogger
 }
@@ -0,0 +1,163 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/notifications"
+)
+
+// reads the mailer configuration. without MAIL_PROVIDER, emails are logged instead of sent
+func ConfigFromEnv() Config {
+	config := Config{
+		Provider:       strings.ToLower(os.Getenv("MAIL_PROVIDER")),
+		From:           os.Getenv("MAIL_FROM"),
+		AppURL:         strings.TrimRight(os.Getenv("APP_URL"), "/"),
+		SMTPHost:       os.Getenv("SMTP_HOST"),
+		SMTPPort:       os.Getenv("SMTP_PORT"),
+		SMTPUsername:   os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:   os.Getenv("SMTP_PASSWORD"),
+		ResendAPIKey:   os.Getenv("RESEND_API_KEY"),
+		SendGridAPIKey: os.Getenv("SENDGRID_API_KEY"),
+	}
+
+	if config.Provider == "" {
+		config.Provider = ProviderLog
+	}
+
+	if config.From == "" {
+		config.From = defaultFromAddress
+	}
+
+	if config.AppURL == "" {
+		config.AppURL = defaultAppURL
+	}
+
+	if config.SMTPPort == "" {
+		config.SMTPPort = defaultSMTPPort
+	}
+
+	return config
+}
+
+// creates a mailer with the configured provider
+func New(db *pgxpool.Pool, config Config) (*Service, error) {
+	provider, err := newProvider(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db:       db,
+		config:   config,
+		provider: provider,
+	}, nil
+}
+
+// the configured provider, "log" when emails are only logged
+func (s *Service) ProviderName() string {
+	return s.config.Provider
+}
+
+// emails an invitation to join a session, unless the address belongs to a
+// user who turned invitation emails off
+func (s *Service) SendInvite(ctx context.Context, invite Invite) error {
+	preferences, err := s.preferencesByEmail(ctx, invite.To)
+	if err != nil {
+		return err
+	}
+
+	if preferences.Unsubscribed || !preferences.Invites {
+		return ErrUnsubscribed
+	}
+
+	msg, err := inviteTemplate.render(s.config.From, invite.To, inviteData{
+		Invite:      invite,
+		SettingsURL: s.settingsURL(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render invite email: %w", err)
+	}
+
+	return s.provider.Send(ctx, msg)
+}
+
+// the frontend link that joins a session with an invite token
+func (s *Service) InviteURL(token string) string {
+	return s.config.AppURL + "/join?invite=" + url.QueryEscape(token)
+}
+
+// emails a digest of unread notifications (implements notifications.DigestSender).
+// users who unsubscribed from all email are skipped without error, so their
+// notifications aren't retried with every digest.
+func (s *Service) SendDigest(ctx context.Context, digest notifications.Digest) error {
+	preferences, err := s.GetPreferences(ctx, digest.UserID)
+	if err != nil {
+		return err
+	}
+
+	if preferences.Unsubscribed {
+		return nil
+	}
+
+	attributions, highlights := attributionSummary(digest.Notifications)
+
+	msg, err := digestTemplate.render(s.config.From, digest.Email, digestData{
+		Name:             digest.Name,
+		Period:           digest.Frequency,
+		Notifications:    digest.Notifications,
+		Attributions:     attributions,
+		Highlights:       highlights,
+		NotificationsURL: s.config.AppURL + "/notifications",
+		SettingsURL:      s.settingsURL(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render digest email: %w", err)
+	}
+
+	return s.provider.Send(ctx, msg)
+}
+
+// gets a user's email preferences; users who never changed them get the defaults
+func (s *Service) GetPreferences(ctx context.Context, userID string) (*Preferences, error) {
+	return s.scanPreferences(s.db.QueryRow(ctx, queryGetPreferences, userID))
+}
+
+// replaces a user's email preferences
+func (s *Service) UpdatePreferences(ctx context.Context, userID string, preferences Preferences) (*Preferences, error) {
+	return s.scanPreferences(s.db.QueryRow(ctx, queryUpsertPreferences, userID, preferences.Invites, preferences.Unsubscribed))
+}
+
+func (s *Service) preferencesByEmail(ctx context.Context, email string) (*Preferences, error) {
+	return s.scanPreferences(s.db.QueryRow(ctx, queryGetPreferencesByEmail, email))
+}
+
+func (s *Service) scanPreferences(row pgx.Row) (*Preferences, error) {
+	var p Preferences
+
+	err := row.Scan(&p.Invites, &p.Unsubscribed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return defaultPreferences(), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func defaultPreferences() *Preferences {
+	return &Preferences{Invites: true}
+}
+
+func (s *Service) settingsURL() string {
+	return s.config.AppURL + "/settings/notifications"
+}
@@ -0,0 +1,176 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/notifications"
+	"codeberg.org/algopatterns/server/internal/webhooks"
+)
+
+func TestInviteTemplate(t *testing.T) {
+	msg, err := inviteTemplate.render("algopatterns <noreply@example.com>", "guest@example.com", inviteData{
+		Invite: Invite{
+			To:           "guest@example.com",
+			InviterName:  "Ada",
+			SessionTitle: "<b>late</b>\r\nBcc: x@example.com",
+			Role:         "co-author",
+			URL:          "https://example.com/join?token=abc",
+		},
+		SettingsURL: "https://example.com/settings/notifications",
+	})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		t.Errorf("subject spans lines: %q", msg.Subject)
+	}
+
+	if !strings.HasPrefix(msg.Subject, "Ada invited you") {
+		t.Errorf("unexpected subject: %q", msg.Subject)
+	}
+
+	if strings.Contains(msg.HTML, "<b>late</b>") {
+		t.Error("session title not escaped in HTML body")
+	}
+
+	if !strings.Contains(msg.Text, "https://example.com/join?token=abc") || !strings.Contains(msg.Text, "as a co-author") {
+		t.Errorf("text body missing invite details: %q", msg.Text)
+	}
+}
+
+func TestAttributionSummary(t *testing.T) {
+	attribution := func(title string) *notifications.Notification {
+		data, _ := json.Marshal(webhooks.AttributionEventData{StrudelTitle: title}) //nolint:errcheck // static data
+		return &notifications.Notification{Kind: notifications.KindAttributionRecorded, Data: data}
+	}
+
+	list := []*notifications.Notification{
+		attribution("bass"),
+		{Kind: notifications.KindStrudelForked, Data: json.RawMessage(`{}`)},
+		attribution("drums"),
+		attribution("drums"),
+		attribution("pads"),
+		attribution("keys"),
+	}
+
+	count, highlights := attributionSummary(list)
+	if count != 5 {
+		t.Errorf("count = %d, want 5", count)
+	}
+
+	if highlights != `"drums", "bass", "pads"` {
+		t.Errorf("highlights = %s", highlights)
+	}
+}
+
+func TestDigestTemplate(t *testing.T) {
+	msg, err := digestTemplate.render("from@example.com", "user@example.com", digestData{
+		Name:   "Ada",
+		Period: notifications.DigestWeekly,
+		Notifications: []*notifications.Notification{
+			{Message: `Your strudel was forked as "remix"`, CreatedAt: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)},
+		},
+		Attributions:     1,
+		Highlights:       `"drums"`,
+		NotificationsURL: "https://example.com/notifications",
+		SettingsURL:      "https://example.com/settings/notifications",
+	})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	if msg.Subject != "Your weekly algopatterns digest" {
+		t.Errorf("unexpected subject: %q", msg.Subject)
+	}
+
+	for _, want := range []string{`referenced your strudels 1 time`, `most often "drums"`, `forked as "remix" (Feb 3)`} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("text body missing %q: %s", want, msg.Text)
+		}
+	}
+}
+
+func TestBuildMIME(t *testing.T) {
+	body, err := buildMIME(Message{
+		From:    "algopatterns <noreply@example.com>",
+		To:      "user@example.com",
+		Subject: "Grüße",
+		Text:    "plain",
+		HTML:    "<p>html</p>",
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	message := string(body)
+	for _, want := range []string{"Subject: =?utf-8?q?", "multipart/alternative", "text/plain; charset=utf-8", "text/html; charset=utf-8", "<p>html</p>"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message missing %q", want)
+		}
+	}
+}
+
+func TestHTTPProviders(t *testing.T) {
+	msg := Message{From: "algopatterns <noreply@example.com>", To: "user@example.com", Subject: "hi", Text: "t", HTML: "h"}
+
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("missing api key, got %q", r.Header.Get("Authorization"))
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+
+		if received["subject"] == "fail" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"invalid"}`)) //nolint:errcheck,gosec // test response
+		}
+	}))
+	defer server.Close()
+
+	resend := &resendProvider{apiKey: "key", url: server.URL, client: server.Client()}
+	if err := resend.Send(context.Background(), msg); err != nil {
+		t.Fatalf("resend send failed: %v", err)
+	}
+
+	if to, _ := received["to"].([]any); len(to) != 1 || to[0] != "user@example.com" {
+		t.Errorf("unexpected resend recipients: %v", received["to"])
+	}
+
+	sendGrid := &sendGridProvider{apiKey: "key", url: server.URL, client: server.Client()}
+	if err := sendGrid.Send(context.Background(), msg); err != nil {
+		t.Fatalf("sendgrid send failed: %v", err)
+	}
+
+	if from, _ := received["from"].(map[string]any); from["email"] != "noreply@example.com" || from["name"] != "algopatterns" {
+		t.Errorf("unexpected sendgrid sender: %v", received["from"])
+	}
+
+	msg.Subject = "fail"
+	if err := resend.Send(context.Background(), msg); err == nil || !strings.Contains(err.Error(), "422") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	if _, err := newProvider(Config{Provider: ProviderLog}); err != nil {
+		t.Errorf("log provider: %v", err)
+	}
+
+	if _, err := newProvider(Config{Provider: ProviderResend}); err == nil {
+		t.Error("expected error for resend without an API key")
+	}
+
+	if _, err := newProvider(Config{Provider: "carrier-pigeon"}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
@@ -0,0 +1,203 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// builds the provider selected by the config
+func newProvider(config Config) (Provider, error) {
+	client := &http.Client{Timeout: sendTimeout}
+
+	switch config.Provider {
+	case ProviderLog:
+		return logProvider{}, nil
+
+	case ProviderSMTP:
+		if config.SMTPHost == "" {
+			return nil, fmt.Errorf("%w: SMTP_HOST is required", ErrNotConfigured)
+		}
+
+		return &smtpProvider{
+			host:     config.SMTPHost,
+			port:     config.SMTPPort,
+			username: config.SMTPUsername,
+			password: config.SMTPPassword,
+		}, nil
+
+	case ProviderResend:
+		if config.ResendAPIKey == "" {
+			return nil, fmt.Errorf("%w: RESEND_API_KEY is required", ErrNotConfigured)
+		}
+
+		return &resendProvider{apiKey: config.ResendAPIKey, url: resendAPIURL, client: client}, nil
+
+	case ProviderSendGrid:
+		if config.SendGridAPIKey == "" {
+			return nil, fmt.Errorf("%w: SENDGRID_API_KEY is required", ErrNotConfigured)
+		}
+
+		return &sendGridProvider{apiKey: config.SendGridAPIKey, url: sendGridAPIURL, client: client}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, config.Provider)
+}
+
+func (logProvider) Send(_ context.Context, msg Message) error {
+	logger.Info("email not sent (log provider)",
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", msg.Text,
+	)
+
+	return nil
+}
+
+func (p *smtpProvider) Send(ctx context.Context, msg Message) error {
+	body, err := buildMIME(msg)
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if p.username != "" {
+		auth = smtp.PlainAuth("", p.username, p.password, p.host)
+	}
+
+	// smtp.SendMail has no context; run it aside so a stuck server can't outlive ctx
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(p.host, p.port), auth, from.Address, []string{msg.To}, body)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("smtp send failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("smtp send failed: %w", ctx.Err())
+	}
+}
+
+func (p *resendProvider) Send(ctx context.Context, msg Message) error {
+	return postJSON(ctx, p.client, p.url, p.apiKey, map[string]any{
+		"from":    msg.From,
+		"to":      []string{msg.To},
+		"subject": msg.Subject,
+		"text":    msg.Text,
+		"html":    msg.HTML,
+	})
+}
+
+func (p *sendGridProvider) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	return postJSON(ctx, p.client, p.url, p.apiKey, map[string]any{
+		"personalizations": []map[string]any{
+			{"to": []map[string]string{{"email": msg.To}}},
+		},
+		"from":    map[string]string{"email": from.Address, "name": from.Name},
+		"subject": msg.Subject,
+		"content": []map[string]string{
+			{"type": "text/plain", "value": msg.Text},
+			{"type": "text/html", "value": msg.HTML},
+		},
+	})
+}
+
+// posts a JSON body with a bearer key. any non-2xx response is an error.
+func postJSON(ctx context.Context, client *http.Client, url, apiKey string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode email: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build email request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("email request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize)) //nolint:errcheck // partial body is fine for the error
+		return fmt.Errorf("email provider responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(responseBody)))
+	}
+
+	return nil
+}
+
+// builds a multipart/alternative message with quoted-printable text and HTML parts
+func buildMIME(msg Message) ([]byte, error) {
+	boundaryBytes := make([]byte, 12)
+	if _, err := rand.Read(boundaryBytes); err != nil {
+		return nil, err
+	}
+	boundary := "algopatterns-" + hex.EncodeToString(boundaryBytes)
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", msg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+		writer := quotedprintable.NewWriter(&buf)
+		if _, err := writer.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
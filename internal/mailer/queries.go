@@ -0,0 +1,27 @@
+package mailer
+
+const (
+	queryGetPreferences = `
+		SELECT invites, unsubscribed
+		FROM email_preferences
+		WHERE user_id = $1
+	`
+
+	// preferences of whoever has the address; none when it isn't a user's
+	queryGetPreferencesByEmail = `
+		SELECT p.invites, p.unsubscribed
+		FROM email_preferences p
+		INNER JOIN users u ON u.id = p.user_id
+		WHERE LOWER(u.email) = LOWER($1)
+	`
+
+	queryUpsertPreferences = `
+		INSERT INTO email_preferences (user_id, invites, unsubscribed)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET invites = EXCLUDED.invites,
+		    unsubscribed = EXCLUDED.unsubscribed,
+		    updated_at = NOW()
+		RETURNING invites, unsubscribed
+	`
+)
@@ -0,0 +1,159 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/json"
+	htmltemplate "html/template"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"codeberg.org/algopatterns/server/internal/notifications"
+	"codeberg.org/algopatterns/server/internal/webhooks"
+)
+
+// one email template, rendered as plain text and HTML from the same data
+type emailTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+var inviteTemplate = newEmailTemplate(
+	`{{if .InviterName}}{{.InviterName}} invited you{{else}}You're invited{{end}} to "{{.SessionTitle}}" on algopatterns`,
+
+	`Hi,
+
+{{if .InviterName}}{{.InviterName}} invited you{{else}}You've been invited{{end}} to join the live coding session "{{.SessionTitle}}" on algopatterns as a {{.Role}}.
+
+Join the session: {{.URL}}
+
+If you weren't expecting this invitation, you can ignore this email.
+
+Email settings: {{.SettingsURL}}
+`,
+
+	`<p>Hi,</p>
+<p>{{if .InviterName}}<strong>{{.InviterName}}</strong> invited you{{else}}You've been invited{{end}} to join the live coding session <strong>{{.SessionTitle}}</strong> on algopatterns as a {{.Role}}.</p>
+<p><a href="{{.URL}}">Join the session</a></p>
+<p>If you weren't expecting this invitation, you can ignore this email.</p>
+<p style="color:#888;font-size:12px"><a href="{{.SettingsURL}}">Email settings</a></p>
+`,
+)
+
+var digestTemplate = newEmailTemplate(
+	`Your {{.Period}} algopatterns digest`,
+
+	`Hi{{if .Name}} {{.Name}}{{end}},
+
+Here's what happened on algopatterns since your last digest.
+{{if .Attributions}}
+The AI assistant referenced your strudels {{.Attributions}} {{if eq .Attributions 1}}time{{else}}times{{end}}{{if .Highlights}}, most often {{.Highlights}}{{end}}.
+{{end}}
+{{range .Notifications}}- {{.Message}} ({{.CreatedAt.Format "Jan 2"}})
+{{end}}
+See all notifications: {{.NotificationsURL}}
+
+You get this {{.Period}} digest of unread notifications because you turned it on. Change it: {{.SettingsURL}}
+`,
+
+	`<p>Hi{{if .Name}} {{.Name}}{{end}},</p>
+<p>Here's what happened on algopatterns since your last digest.</p>
+{{if .Attributions}}<p>The AI assistant referenced your strudels <strong>{{.Attributions}}</strong> {{if eq .Attributions 1}}time{{else}}times{{end}}{{if .Highlights}}, most often {{.Highlights}}{{end}}.</p>
+{{end}}<ul>
+{{range .Notifications}}<li>{{.Message}} <span style="color:#888">({{.CreatedAt.Format "Jan 2"}})</span></li>
+{{end}}</ul>
+<p><a href="{{.NotificationsURL}}">See all notifications</a></p>
+<p style="color:#888;font-size:12px">You get this {{.Period}} digest of unread notifications because you turned it on. <a href="{{.SettingsURL}}">Change it</a></p>
+`,
+)
+
+func newEmailTemplate(subject, text, html string) emailTemplate {
+	return emailTemplate{
+		subject: texttemplate.Must(texttemplate.New("subject").Parse(subject)),
+		text:    texttemplate.Must(texttemplate.New("text").Parse(text)),
+		html:    htmltemplate.Must(htmltemplate.New("html").Parse(html)),
+	}
+}
+
+// renders the template into a message to the given address
+func (t emailTemplate) render(from, to string, data any) (Message, error) {
+	var subject, text, html bytes.Buffer
+
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return Message{}, err
+	}
+
+	if err := t.text.Execute(&text, data); err != nil {
+		return Message{}, err
+	}
+
+	if err := t.html.Execute(&html, data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		From:    from,
+		To:      to,
+		Subject: strings.Join(strings.Fields(subject.String()), " "), // single line, whatever titles contain
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// data of the invite template
+type inviteData struct {
+	Invite
+	SettingsURL string
+}
+
+// data of the digest template
+type digestData struct {
+	Name             string
+	Period           string // daily or weekly
+	Notifications    []*notifications.Notification
+	Attributions     int    // notifications about strudels used as AI references
+	Highlights       string // quoted titles of the most used strudels
+	NotificationsURL string
+	SettingsURL      string
+}
+
+// summarizes the attribution notifications of a digest
+func attributionSummary(list []*notifications.Notification) (count int, highlights string) {
+	uses := map[string]int{}
+	var titles []string
+
+	for _, n := range list {
+		if n.Kind != notifications.KindAttributionRecorded {
+			continue
+		}
+
+		count++
+
+		var data webhooks.AttributionEventData
+		if err := json.Unmarshal(n.Data, &data); err != nil || data.StrudelTitle == "" {
+			continue
+		}
+
+		if uses[data.StrudelTitle] == 0 {
+			titles = append(titles, data.StrudelTitle)
+		}
+		uses[data.StrudelTitle]++
+	}
+
+	// most used first, ties in order of appearance (newest first)
+	slices.SortStableFunc(titles, func(a, b string) int {
+		return uses[b] - uses[a]
+	})
+
+	if len(titles) > maxDigestHighlights {
+		titles = titles[:maxDigestHighlights]
+	}
+
+	quoted := make([]string, len(titles))
+	for i, title := range titles {
+		quoted[i] = `"` + title + `"`
+	}
+
+	return count, strings.Join(quoted, ", ")
+}
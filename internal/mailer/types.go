@@ -0,0 +1,114 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// providers
+const (
+	ProviderLog      = "log" // dev-mode sink, logs emails instead of sending them
+	ProviderSMTP     = "smtp"
+	ProviderResend   = "resend"
+	ProviderSendGrid = "sendgrid"
+)
+
+// provider API endpoints
+const (
+	resendAPIURL   = "https://api.resend.com/emails"
+	sendGridAPIURL = "https://api.sendgrid.com/v3/mail/send"
+)
+
+// delivery constants
+const (
+	sendTimeout         = 15 * time.Second
+	maxErrorBodySize    = 1024 // bytes of a provider error response kept in the error
+	defaultSMTPPort     = "587"
+	defaultFromAddress  = "algopatterns <noreply@localhost>"
+	defaultAppURL       = "http://localhost:3000"
+	maxDigestHighlights = 3 // strudels named in the attribution summary of a digest
+)
+
+// errors
+var (
+	ErrUnknownProvider = errors.New("unknown mail provider")
+	ErrNotConfigured   = errors.New("mail provider is not configured")
+	ErrUnsubscribed    = errors.New("recipient has unsubscribed from these emails")
+)
+
+type Config struct {
+	Provider       string // MAIL_PROVIDER: smtp, resend, sendgrid or log (default)
+	From           string // MAIL_FROM, e.g. "algopatterns <noreply@example.com>"
+	AppURL         string // APP_URL, the frontend that links in emails point to
+	SMTPHost       string // SMTP_HOST
+	SMTPPort       string // SMTP_PORT, defaults to 587 (STARTTLS)
+	SMTPUsername   string // SMTP_USERNAME
+	SMTPPassword   string // SMTP_PASSWORD
+	ResendAPIKey   string // RESEND_API_KEY
+	SendGridAPIKey string // SENDGRID_API_KEY
+}
+
+// sends rendered emails
+type Provider interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// renders and sends templated emails, honouring users' email preferences
+type Service struct {
+	db       *pgxpool.Pool
+	config   Config
+	provider Provider
+}
+
+// a rendered email with plain text and HTML bodies
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// which emails a user receives. digest frequency is a notification preference
+type Preferences struct {
+	Invites      bool `json:"invites"`      // session invitations
+	Unsubscribed bool `json:"unsubscribed"` // no email at all, including digests
+}
+
+// an invitation to join a session
+type Invite struct {
+	To           string // email address
+	InviterName  string
+	SessionTitle string
+	Role         string
+	URL          string // accepts the invitation
+}
+
+// logs emails instead of sending them
+type logProvider struct{}
+
+// sends through an SMTP server with STARTTLS when offered
+type smtpProvider struct {
+	host     string
+	port     string
+	username string
+	password string
+}
+
+// sends through the Resend API
+type resendProvider struct {
+	apiKey string
+	url    string
+	client *http.Client
+}
+
+// sends through the SendGrid v3 API
+type sendGridProvider struct {
+	apiKey string
+	url    string
+	client *http.Client
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+)
+
+// creates and registers a counter
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		desc:   desc{name: name, help: help, kind: kindCounter, labels: labels},
+		values: make(map[string]*series),
+	}
+
+	defaultRegistry.register(c)
+
+	return c
+}
+
+// creates and registers a gauge
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{
+		desc:   desc{name: name, help: help, kind: kindGauge, labels: labels},
+		values: make(map[string]*series),
+	}
+
+	defaultRegistry.register(g)
+
+	return g
+}
+
+// creates and registers a histogram with the given bucket upper bounds
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	h := &HistogramVec{
+		desc:    desc{name: name, help: help, kind: kindHistogram, labels: labels},
+		buckets: sorted,
+		values:  make(map[string]*histogramSeries),
+	}
+
+	defaultRegistry.register(h)
+
+	return h
+}
+
+// increments the counter by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// increments the counter by v. negative values are ignored.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	getSeries(c.values, c.labels, labelValues).value += v
+}
+
+// sets the gauge to v
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	getSeries(g.values, g.labels, labelValues).value = v
+}
+
+// removes a series, e.g. when the session it describes has ended
+func (g *GaugeVec) Delete(labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.values, seriesKey(labelValues))
+}
+
+// records an observation
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := seriesKey(labelValues)
+
+	s, exists := h.values[key]
+	if !exists {
+		s = &histogramSeries{
+			labelValues: normalizeLabelValues(h.labels, labelValues),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.values[key] = s
+	}
+
+	// observations above the largest bucket only count towards +Inf
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+
+	s.count++
+	s.sum += v
+}
+
+func (c *CounterVec) describe() *desc { return &c.desc }
+
+func (g *GaugeVec) describe() *desc { return &g.desc }
+
+func (h *HistogramVec) describe() *desc { return &h.desc }
+
+func (c *CounterVec) write(b *[]byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeSeries(b, &c.desc, c.values)
+}
+
+func (g *GaugeVec) write(b *[]byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	writeSeries(b, &g.desc, g.values)
+}
+
+func (h *HistogramVec) write(b *[]byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, key := range sortedKeys(h.values) {
+		s := h.values[key]
+
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			writeSample(b, h.name+"_bucket", h.labels, s.labelValues, "le", formatFloat(upper), float64(cumulative))
+		}
+
+		writeSample(b, h.name+"_bucket", h.labels, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(b, h.name+"_sum", h.labels, s.labelValues, "", "", s.sum)
+		writeSample(b, h.name+"_count", h.labels, s.labelValues, "", "", float64(s.count))
+	}
+}
+
+// returns the series for a set of label values, creating it if needed
+// (must be called with the metric's lock held)
+func getSeries(values map[string]*series, labels, labelValues []string) *series {
+	key := seriesKey(labelValues)
+
+	s, exists := values[key]
+	if !exists {
+		s = &series{labelValues: normalizeLabelValues(labels, labelValues)}
+		values[key] = s
+	}
+
+	return s
+}
+
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, labelSeparator)
+}
+
+// pads or truncates label values to the number of label names
+func normalizeLabelValues(labels, labelValues []string) []string {
+	normalized := make([]string, len(labels))
+	copy(normalized, labelValues)
+
+	return normalized
+}
+
+func (r *registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.collectors = append(r.collectors, c)
+}
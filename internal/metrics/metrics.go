@@ -0,0 +1,236 @@
+package metrics
+
+import (
+	"net/http"
+)
+
+var defaultRegistry = &registry{}
+
+// websocket hub
+var (
+	WebSocketConnections = NewGaugeVec(
+		"algopatterns_websocket_connections",
+		"Active WebSocket connections per session.",
+		"session_id",
+	)
+
+	WebSocketListeners = NewGaugeVec(
+		"algopatterns_websocket_listeners",
+		"Read-only audience WebSocket connections per session.",
+		"session_id",
+	)
+
+	WebSocketMessagesBroadcast = NewCounterVec(
+		"algopatterns_websocket_messages_broadcast_total",
+		"Messages broadcast to session clients, by message type.",
+		"type",
+	)
+
+	WebSocketCodeUpdatesCoalesced = NewCounterVec(
+		"algopatterns_websocket_code_updates_coalesced_total",
+		"code_update messages replaced by a newer one before reaching a read-only client.",
+	)
+
+	WebSocketRateLimitUpdates = NewCounterVec(
+		"algopatterns_websocket_rate_limit_updates_total",
+		"Code update rate limit changes sent to writers, by reason (backlog, headroom).",
+		"reason",
+	)
+
+	WebSocketPingRTT = NewHistogramVec(
+		"algopatterns_websocket_ping_rtt_seconds",
+		"Round trip time of WebSocket pings.",
+		DefaultBuckets,
+	)
+
+	WebSocketDeadConnections = NewCounterVec(
+		"algopatterns_websocket_dead_connections_total",
+		"Connections closed for not answering pings or not reading, by reason (missed_pings, stalled).",
+		"reason",
+	)
+
+	PasteLockEvents = NewCounterVec(
+		"algopatterns_paste_lock_events_total",
+		"Paste lock changes sent to clients, by event (locked, unlocked) and reason.",
+		"event", "reason",
+	)
+)
+
+// paste detection
+var (
+	FingerprintIndexSize = NewGaugeVec(
+		"algopatterns_ccsignals_fingerprint_index_size",
+		"No-ai strudels in this instance's fingerprint index.",
+	)
+
+	FingerprintRepairs = NewCounterVec(
+		"algopatterns_ccsignals_fingerprint_repairs_total",
+		"Persisted fingerprints fixed by the startup consistency check, by action (recomputed, removed).",
+		"action",
+	)
+)
+
+// redis buffer
+var (
+	BufferFlushDuration = NewHistogramVec(
+		"algopatterns_buffer_flush_duration_seconds",
+		"Time taken to flush buffered data from Redis to Postgres, by kind.",
+		DefaultBuckets,
+		"kind",
+	)
+
+	BufferFlushBatchSize = NewHistogramVec(
+		"algopatterns_buffer_flush_batch_size",
+		"Number of sessions or items written per flush, by kind.",
+		BatchSizeBuckets,
+		"kind",
+	)
+
+	BufferFlushLag = NewHistogramVec(
+		"algopatterns_buffer_flush_lag_seconds",
+		"Age of the oldest entry written by a flush pass, by kind (messages, code_revisions, activity).",
+		FlushLagBuckets,
+		"kind",
+	)
+
+	BufferBacklog = NewGaugeVec(
+		"algopatterns_buffer_backlog",
+		"Entries waiting in Redis to be flushed at the start of the last pass, by kind (sessions for code).",
+		"kind",
+	)
+
+	BufferBackpressure = NewGaugeVec(
+		"algopatterns_buffer_backpressure",
+		"Chat refused until the flusher catches up: 1 for the whole buffer, or the number of backed up sessions.",
+		"scope",
+	)
+
+	BufferFallback = NewGaugeVec(
+		"algopatterns_buffer_fallback",
+		"1 while Redis is unreachable and buffered writes are held in memory.",
+	)
+)
+
+// agent
+var (
+	LLMRequestDuration = NewHistogramVec(
+		"algopatterns_llm_request_duration_seconds",
+		"Latency of LLM text generation requests, by provider and operation (generate, stream).",
+		LLMBuckets,
+		"provider", "operation",
+	)
+
+	LLMTokens = NewCounterVec(
+		"algopatterns_llm_tokens_total",
+		"Tokens used by LLM text generation, by provider and direction (input, output).",
+		"provider", "direction",
+	)
+
+	LLMFailovers = NewCounterVec(
+		"algopatterns_llm_failovers_total",
+		"Calls sent to a fallback provider, by component (transformer, generator, embedder) and failing primary provider.",
+		"component", "provider",
+	)
+
+	LLMRetries = NewCounterVec(
+		"algopatterns_llm_retries_total",
+		"LLM API calls retried after a transient failure, by provider and error kind (rate_limited, unavailable).",
+		"provider", "kind",
+	)
+
+	LLMHedgedRequests = NewCounterVec(
+		"algopatterns_llm_hedged_requests_total",
+		"Second copies of slow idempotent LLM API requests (embeddings), by provider.",
+		"provider",
+	)
+
+	AgentResponseCache = NewCounterVec(
+		"algopatterns_agent_response_cache_total",
+		"Agent response cache lookups, by result (hit, miss, bypass).",
+		"result",
+	)
+
+	RetrieverEmbeddingCache = NewCounterVec(
+		"algopatterns_retriever_embedding_cache_total",
+		"Query embedding cache lookups, by result (hit, miss).",
+		"result",
+	)
+
+	RetrieverSearchDuration = NewHistogramVec(
+		"algopatterns_retriever_search_duration_seconds",
+		"Latency of hybrid retriever searches, by index (docs, examples).",
+		DefaultBuckets,
+		"index",
+	)
+
+	RetrieverRerank = NewCounterVec(
+		"algopatterns_retriever_rerank_total",
+		"Reranking attempts, by index (docs, examples) and result (ok, timeout, error).",
+		"index", "result",
+	)
+
+	RetrieverRerankDuration = NewHistogramVec(
+		"algopatterns_retriever_rerank_duration_seconds",
+		"Latency of reranking calls, by index (docs, examples).",
+		DefaultBuckets,
+		"index",
+	)
+)
+
+// background jobs
+var (
+	JobsProcessed = NewCounterVec(
+		"algopatterns_jobs_processed_total",
+		"Background job runs, by kind and result (succeeded, retried, dead, released).",
+		"kind", "result",
+	)
+
+	JobDuration = NewHistogramVec(
+		"algopatterns_job_duration_seconds",
+		"Time taken to run background jobs, by kind.",
+		DefaultBuckets,
+		"kind",
+	)
+)
+
+// read replicas
+var (
+	ReplicaLag = NewGaugeVec(
+		"algopatterns_db_replica_lag_seconds",
+		"Replication lag of each read replica at its last check, -1 when the check failed.",
+		"replica",
+	)
+
+	ReplicaReads = NewCounterVec(
+		"algopatterns_db_routed_reads_total",
+		"Lag-tolerant reads, by the pool that served them (replica, primary).",
+		"target",
+	)
+)
+
+// returns all registered metrics in the prometheus text exposition format
+func Gather() []byte {
+	defaultRegistry.mu.Lock()
+	collectors := append([]collector(nil), defaultRegistry.collectors...)
+	defaultRegistry.mu.Unlock()
+
+	var b []byte
+
+	for _, c := range collectors {
+		d := c.describe()
+
+		b = append(b, "# HELP "+d.name+" "+escapeHelp(d.help)+"\n"...)
+		b = append(b, "# TYPE "+d.name+" "+d.kind+"\n"...)
+		c.write(&b)
+	}
+
+	return b
+}
+
+// serves the registered metrics for prometheus to scrape
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write(Gather()) //nolint:errcheck,gosec // client went away, nothing to do
+	})
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCounterExposition(t *testing.T) {
+	c := &CounterVec{
+		desc:   desc{name: "test_total", help: "Test counter.", kind: kindCounter, labels: []string{"type"}},
+		values: make(map[string]*series),
+	}
+
+	c.Inc("code_op")
+	c.Add(2, "code_op")
+	c.Inc("chat \"message\"")
+	c.Add(-1, "code_op") // ignored
+
+	var b []byte
+	c.write(&b)
+
+	want := "test_total{type=\"chat \\\"message\\\"\"} 1\n" +
+		"test_total{type=\"code_op\"} 3\n"
+
+	if string(b) != want {
+		t.Errorf("unexpected counter output:\ngot:\n%s\nwant:\n%s", b, want)
+	}
+}
+
+func TestGaugeDelete(t *testing.T) {
+	g := &GaugeVec{
+		desc:   desc{name: "test_gauge", kind: kindGauge, labels: []string{"session_id"}},
+		values: make(map[string]*series),
+	}
+
+	g.Set(3, "a")
+	g.Set(1, "b")
+	g.Delete("a")
+
+	var b []byte
+	g.write(&b)
+
+	if want := "test_gauge{session_id=\"b\"} 1\n"; string(b) != want {
+		t.Errorf("expected %q, got %q", want, b)
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	h := &HistogramVec{
+		desc:    desc{name: "test_seconds", kind: kindHistogram, labels: []string{"kind"}},
+		buckets: []float64{0.1, 1},
+		values:  make(map[string]*histogramSeries),
+	}
+
+	h.Observe(0.05, "code")
+	h.Observe(0.1, "code") // upper bounds are inclusive
+	h.Observe(0.5, "code")
+	h.Observe(5, "code")
+
+	var b []byte
+	h.write(&b)
+
+	want := strings.Join([]string{
+		`test_seconds_bucket{kind="code",le="0.1"} 2`,
+		`test_seconds_bucket{kind="code",le="1"} 3`,
+		`test_seconds_bucket{kind="code",le="+Inf"} 4`,
+		`test_seconds_sum{kind="code"} 5.65`,
+		`test_seconds_count{kind="code"} 4`,
+	}, "\n") + "\n"
+
+	if string(b) != want {
+		t.Errorf("unexpected histogram output:\ngot:\n%s\nwant:\n%s", b, want)
+	}
+}
+
+func TestGatherIncludesHelpAndType(t *testing.T) {
+	out := string(Gather())
+
+	for _, line := range []string{
+		"# TYPE algopatterns_websocket_connections gauge",
+		"# TYPE algopatterns_buffer_flush_duration_seconds histogram",
+		"# HELP algopatterns_llm_tokens_total ",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("expected output to contain %q", line)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"sync"
+)
+
+// metric kinds as written in the exposition format
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// separates label values in series keys (cannot appear in valid utf-8)
+const labelSeparator = "\xff"
+
+// bucket upper bounds in seconds for fast operations (redis, postgres, search)
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// bucket upper bounds in seconds for llm requests
+var LLMBuckets = []float64{0.25, 0.5, 1, 2.5, 5, 10, 20, 40, 80, 160}
+
+// bucket upper bounds for flush batch sizes
+var BatchSizeBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// bucket upper bounds in seconds for how long buffered data waits to be flushed
+var FlushLagBuckets = []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+// something that can write its series in the prometheus text format
+type collector interface {
+	describe() *desc
+	write(b *[]byte)
+}
+
+// name, help text and label names shared by all series of a metric
+type desc struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+// monotonically increasing value partitioned by labels
+type CounterVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*series
+}
+
+// value that can go up and down partitioned by labels
+type GaugeVec struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*series
+}
+
+// distribution of observations partitioned by labels
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramSeries
+}
+
+// a single labelled value
+type series struct {
+	labelValues []string
+	value       float64
+}
+
+// a single labelled histogram
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // per bucket, not cumulative
+	count       uint64
+	sum         float64
+}
+
+// holds all registered metrics
+type registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
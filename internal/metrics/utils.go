@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// writes every series of a counter or gauge
+func writeSeries(b *[]byte, d *desc, values map[string]*series) {
+	for _, key := range sortedKeys(values) {
+		s := values[key]
+		writeSample(b, d.name, d.labels, s.labelValues, "", "", s.value)
+	}
+}
+
+// writes one sample line. extraLabel is appended after the metric's own
+// labels when set (used for histogram "le").
+func writeSample(b *[]byte, name string, labels, labelValues []string, extraLabel, extraValue string, value float64) {
+	*b = append(*b, name...)
+
+	if len(labels) > 0 || extraLabel != "" {
+		*b = append(*b, '{')
+
+		for i, label := range labels {
+			if i > 0 {
+				*b = append(*b, ',')
+			}
+			*b = appendLabel(*b, label, labelValues[i])
+		}
+
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				*b = append(*b, ',')
+			}
+			*b = appendLabel(*b, extraLabel, extraValue)
+		}
+
+		*b = append(*b, '}')
+	}
+
+	*b = append(*b, ' ')
+	*b = append(*b, formatFloat(value)...)
+	*b = append(*b, '\n')
+}
+
+func appendLabel(b []byte, name, value string) []byte {
+	b = append(b, name...)
+	b = append(b, '=', '"')
+	b = append(b, escapeLabelValue(value)...)
+	return append(b, '"')
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
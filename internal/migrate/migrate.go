@@ -0,0 +1,337 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+
+func New(db *pgxpool.Pool, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// reads the migrations in fsys, sorted by version. down migrations are read
+// from down/ and must revert a migration of the same file name
+func Load(fsys fs.FS) ([]Migration, error) {
+	ups, err := readDir(fsys, upDir)
+	if err != nil {
+		return nil, err
+	}
+
+	downs, err := readDir(fsys, downDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(ups))
+	byVersion := make(map[string]int, len(ups))
+
+	for _, up := range ups {
+		if _, ok := byVersion[up.Version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %s", up.Version)
+		}
+
+		byVersion[up.Version] = len(migrations)
+		migrations = append(migrations, up)
+	}
+
+	for _, down := range downs {
+		i, ok := byVersion[down.Version]
+		if !ok || migrations[i].Name != down.Name {
+			return nil, fmt.Errorf("down migration %s_%s reverts no migration", down.Version, down.Name)
+		}
+
+		migrations[i].Down = down.Up
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return strings.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// reads the migration files of a directory, ignoring other files
+func readDir(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		sql, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{Version: match[1], Name: match[2], Up: string(sql)})
+	}
+
+	return migrations, nil
+}
+
+// applies every pending migration in version order, returning how many were
+// applied. each runs in its own transaction with its history entry
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	if err := m.ensureHistory(ctx); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, migration := range m.migrations {
+		ok, err := m.apply(ctx, migration)
+		if err != nil {
+			return applied, fmt.Errorf("failed to apply migration %s_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		if ok {
+			logger.Info("applied migration", "version", migration.Version, "name", migration.Name)
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+// rolls back the latest steps applied migrations, returning how many were
+// rolled back. stops at a migration without a down migration
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if err := m.ensureHistory(ctx); err != nil {
+		return 0, err
+	}
+
+	rolledBack := 0
+	for rolledBack < steps {
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return rolledBack, err
+		}
+
+		latest := latestApplied(statuses)
+		if latest == nil {
+			break
+		}
+
+		if latest.Unknown {
+			return rolledBack, fmt.Errorf("%w: %s_%s", ErrUnknownApplied, latest.Version, latest.Name)
+		}
+
+		migration := m.find(latest.Version)
+		if migration.Down == "" {
+			return rolledBack, fmt.Errorf("%w: %s_%s", ErrNoDown, migration.Version, migration.Name)
+		}
+
+		if err := m.revert(ctx, *migration); err != nil {
+			return rolledBack, fmt.Errorf("failed to roll back migration %s_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		logger.Info("rolled back migration", "version", migration.Version, "name", migration.Name)
+		rolledBack++
+	}
+
+	return rolledBack, nil
+}
+
+// lists the migrations of this binary and those applied to the database,
+// by version
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureHistory(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(ctx, queryApplied)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]string)
+	for rows.Next() {
+		var version, name string
+		if err := rows.Scan(&version, &name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		applied[version] = name
+	}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return statuses(m.migrations, applied), nil
+}
+
+// records the migrations up to version as applied without running them,
+// for databases whose schema was set up by hand. returns how many were recorded
+func (m *Migrator) Baseline(ctx context.Context, version string) (int, error) {
+	if m.find(version) == nil {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownVersion, version)
+	}
+
+	if err := m.ensureHistory(ctx); err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	err := m.locked(ctx, func(tx pgx.Tx) error {
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+
+			tag, err := tx.Exec(ctx, queryRecordBaseline, migration.Version, migration.Name, migration.Up)
+			if err != nil {
+				return err
+			}
+
+			recorded += int(tag.RowsAffected())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return recorded, nil
+}
+
+// merges the known migrations with the applied versions
+func statuses(migrations []Migration, applied map[string]string) []Status {
+	result := make([]Status, 0, len(migrations))
+	known := make(map[string]bool, len(migrations))
+
+	for _, migration := range migrations {
+		_, ok := applied[migration.Version]
+		known[migration.Version] = true
+
+		result = append(result, Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: ok,
+			Down:    migration.Down != "",
+		})
+	}
+
+	for version, name := range applied {
+		if !known[version] {
+			result = append(result, Status{Version: version, Name: name, Applied: true, Unknown: true})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b Status) int {
+		return strings.Compare(a.Version, b.Version)
+	})
+
+	return result
+}
+
+// the applied migration with the highest version, nil when none is
+func latestApplied(statuses []Status) *Status {
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if statuses[i].Applied {
+			return &statuses[i]
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) find(version string) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) ensureHistory(ctx context.Context) error {
+	return m.locked(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, queryEnsureHistory, pgx.QueryExecModeSimpleProtocol)
+		return err
+	})
+}
+
+// applies a migration unless another instance got to it first
+func (m *Migrator) apply(ctx context.Context, migration Migration) (bool, error) {
+	applied := false
+
+	err := m.locked(ctx, func(tx pgx.Tx) error {
+		var done bool
+		if err := tx.QueryRow(ctx, queryIsApplied, migration.Version).Scan(&done); err != nil || done {
+			return err
+		}
+
+		// migrations hold several statements, which only the simple protocol runs
+		if _, err := tx.Exec(ctx, migration.Up, pgx.QueryExecModeSimpleProtocol); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, queryRecord, migration.Version, migration.Name, migration.Up); err != nil {
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+
+	return applied, err
+}
+
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	return m.locked(ctx, func(tx pgx.Tx) error {
+		var done bool
+		if err := tx.QueryRow(ctx, queryIsApplied, migration.Version).Scan(&done); err != nil || !done {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, migration.Down, pgx.QueryExecModeSimpleProtocol); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, queryForget, migration.Version)
+		return err
+	})
+}
+
+// runs fn in a transaction holding the migrations lock
+func (m *Migrator) locked(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	if _, err := tx.Exec(ctx, queryLock, lockKey); err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,106 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"codeberg.org/algopatterns/server/supabase/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20260102000000_second.sql":      {Data: []byte("CREATE TABLE b ();")},
+		"20260101000000_first.sql":       {Data: []byte("CREATE TABLE a ();")},
+		"README.md":                      {Data: []byte("not a migration")},
+		"down/20260102000000_second.sql": {Data: []byte("DROP TABLE b;")},
+	}
+
+	loaded, err := Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(loaded))
+	}
+
+	first, second := loaded[0], loaded[1]
+	if first.Version != "20260101000000" || first.Name != "first" || first.Down != "" {
+		t.Errorf("unexpected first migration %+v", first)
+	}
+
+	if second.Name != "second" || second.Up != "CREATE TABLE b ();" || second.Down != "DROP TABLE b;" {
+		t.Errorf("unexpected second migration %+v", second)
+	}
+}
+
+func TestLoadRejectsStrayDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20260101000000_first.sql":        {Data: []byte("CREATE TABLE a ();")},
+		"down/20260101000000_renamed.sql": {Data: []byte("DROP TABLE a;")},
+	}
+
+	if _, err := Load(fsys); err == nil {
+		t.Error("expected an error for a down migration without its migration")
+	}
+}
+
+func TestLoadRejectsDuplicateVersions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20260101000000_first.sql":  {Data: []byte("CREATE TABLE a ();")},
+		"20260101000000_second.sql": {Data: []byte("CREATE TABLE b ();")},
+	}
+
+	if _, err := Load(fsys); err == nil {
+		t.Error("expected an error for two migrations with one version")
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+
+	for i := 1; i < len(loaded); i++ {
+		if loaded[i-1].Version >= loaded[i].Version {
+			t.Errorf("migrations out of order: %s before %s", loaded[i-1].Version, loaded[i].Version)
+		}
+	}
+
+	if last := loaded[len(loaded)-1]; last.Down == "" {
+		t.Errorf("expected the latest migration %s_%s to have a down migration", last.Version, last.Name)
+	}
+}
+
+func TestStatuses(t *testing.T) {
+	known := []Migration{
+		{Version: "1", Name: "first"},
+		{Version: "2", Name: "second", Down: "DROP TABLE b;"},
+	}
+
+	got := statuses(known, map[string]string{"1": "first", "3": "newer"})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 statuses, got %+v", got)
+	}
+
+	if !got[0].Applied || got[1].Applied || !got[1].Down {
+		t.Errorf("unexpected statuses of known migrations: %+v", got[:2])
+	}
+
+	if !got[2].Unknown || !got[2].Applied || got[2].Name != "newer" {
+		t.Errorf("expected the unknown applied migration last, got %+v", got[2])
+	}
+
+	if latest := latestApplied(got); latest == nil || latest.Version != "3" {
+		t.Errorf("expected version 3 as the latest applied, got %+v", latest)
+	}
+
+	if latest := latestApplied(statuses(known, nil)); latest != nil {
+		t.Errorf("expected no applied migration, got %+v", latest)
+	}
+}
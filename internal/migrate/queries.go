@@ -0,0 +1,41 @@
+package migrate
+
+const (
+	// the table the Supabase CLI keeps, created for databases it never touched
+	queryEnsureHistory = `
+		CREATE SCHEMA IF NOT EXISTS supabase_migrations;
+		CREATE TABLE IF NOT EXISTS supabase_migrations.schema_migrations (
+		  version TEXT PRIMARY KEY,
+		  statements TEXT[],
+		  name TEXT
+		);
+	`
+
+	queryLock = `
+		SELECT pg_advisory_xact_lock($1)
+	`
+
+	queryApplied = `
+		SELECT version, COALESCE(name, '') FROM supabase_migrations.schema_migrations
+		ORDER BY version
+	`
+
+	queryIsApplied = `
+		SELECT EXISTS (SELECT 1 FROM supabase_migrations.schema_migrations WHERE version = $1)
+	`
+
+	queryRecord = `
+		INSERT INTO supabase_migrations.schema_migrations (version, name, statements)
+		VALUES ($1, $2, ARRAY[$3])
+	`
+
+	queryRecordBaseline = `
+		INSERT INTO supabase_migrations.schema_migrations (version, name, statements)
+		VALUES ($1, $2, ARRAY[$3])
+		ON CONFLICT (version) DO NOTHING
+	`
+
+	queryForget = `
+		DELETE FROM supabase_migrations.schema_migrations WHERE version = $1
+	`
+)
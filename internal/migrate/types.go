@@ -0,0 +1,50 @@
+package migrate
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migration files are named <version>_<name>.sql, like the Supabase CLI expects
+const (
+	upDir   = "."
+	downDir = "down"
+)
+
+// held for each migration's transaction so instances migrating on boot at the
+// same time apply every migration once. transaction-scoped, so it works
+// through PgBouncer in transaction mode
+const lockKey = 7215306211 // arbitrary, unique to migrations
+
+// errors
+var (
+	ErrNoDown         = errors.New("migration has no down migration")
+	ErrUnknownApplied = errors.New("latest applied migration is not in this binary")
+	ErrUnknownVersion = errors.New("no migration with version")
+)
+
+// a schema migration, up and optionally down
+type Migration struct {
+	Version string // e.g. 20260224000000, sorts by time
+	Name    string // e.g. add_jobs
+	Up      string
+	Down    string // empty when the migration can't be rolled back
+}
+
+// applies migrations to a database. applied versions are tracked in the
+// Supabase CLI's history table, so `supabase db push` and `server migrate`
+// see each other's work
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration // sorted by version
+}
+
+// a migration and whether it was applied
+type Status struct {
+	Version string `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+	Down    bool   `json:"down"`    // can be rolled back
+	Unknown bool   `json:"unknown"` // applied but not in this binary, e.g. by a newer release
+}
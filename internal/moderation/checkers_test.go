@@ -0,0 +1,59 @@
+package moderation
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKeywordChecker(t *testing.T) {
+	checker := NewKeywordChecker(
+		[]string{"Bad Word"},
+		[]string{"spam-link"},
+		[]string{"meh"},
+	)
+
+	tests := []struct {
+		text   string
+		action string
+	}{
+		{"this has a bad word in it", ActionBlock},
+		{"BAD, WORD!", ActionBlock},
+		{"badword", ActionAllow},
+		{"click my spam link", ActionHide},
+		{"meh.", ActionFlag},
+		{"mehh", ActionAllow},
+		{"a bad word and meh", ActionBlock},
+		{"nice groove", ActionAllow},
+		{"", ActionAllow},
+	}
+
+	for _, tt := range tests {
+		verdict, err := checker.Check(context.Background(), Content{Text: tt.text})
+		if err != nil {
+			t.Fatalf("Check(%q) error: %v", tt.text, err)
+		}
+
+		if verdict.Action != tt.action {
+			t.Errorf("Check(%q) = %q, want %q", tt.text, verdict.Action, tt.action)
+		}
+	}
+}
+
+func TestParseClassification(t *testing.T) {
+	tests := []struct {
+		answer string
+		action string
+	}{
+		{"ALLOW", ActionAllow},
+		{"flag", ActionFlag},
+		{" BLOCK.", ActionHide},
+		{"I cannot help with that", ActionAllow},
+		{"", ActionAllow},
+	}
+
+	for _, tt := range tests {
+		if got := parseClassification(tt.answer).Action; got != tt.action {
+			t.Errorf("parseClassification(%q) = %q, want %q", tt.answer, got, tt.action)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+func ConfigFromEnv() Config {
+	return Config{
+		BlockWords: splitList(os.Getenv("MODERATION_BLOCK_WORDS")),
+		HideWords:  splitList(os.Getenv("MODERATION_HIDE_WORDS")),
+		FlagWords:  splitList(os.Getenv("MODERATION_FLAG_WORDS")),
+		UseLLM:     os.Getenv("MODERATION_LLM") == "true",
+	}
+}
+
+// creates a moderation service. generator may be nil, which disables LLM classification.
+func New(db *pgxpool.Pool, config Config, generator llm.TextGenerator) *Service {
+	s := &Service{db: db}
+
+	if len(config.BlockWords)+len(config.HideWords)+len(config.FlagWords) > 0 {
+		s.checkers = append(s.checkers, NewKeywordChecker(config.BlockWords, config.HideWords, config.FlagWords))
+	}
+
+	if config.UseLLM && generator != nil {
+		s.checkers = append(s.checkers, NewLLMChecker(generator))
+	}
+
+	return s
+}
+
+// adds a checker, e.g. an external classification API
+func (s *Service) AddChecker(checker Checker) {
+	s.checkers = append(s.checkers, checker)
+}
+
+// reports whether any checker is configured
+func (s *Service) Enabled() bool {
+	return len(s.checkers) > 0
+}
+
+// runs all checkers and returns the most severe verdict. a failing checker
+// is skipped so an outage of the classifier doesn't stop chat.
+func (s *Service) Screen(ctx context.Context, content Content) Verdict {
+	verdict := Verdict{Action: ActionAllow}
+
+	if strings.TrimSpace(content.Text) == "" {
+		return verdict
+	}
+
+	for _, checker := range s.checkers {
+		v, err := checker.Check(ctx, content)
+		if err != nil {
+			logger.Warn("moderation check failed, skipping",
+				"content_type", content.Type,
+				"error", err,
+			)
+			continue
+		}
+
+		if severity[v.Action] > severity[verdict.Action] {
+			verdict = v
+		}
+
+		if verdict.Action == ActionBlock {
+			break
+		}
+	}
+
+	if verdict.Action == ActionBlock {
+		logger.Info("content blocked by moderation",
+			"content_type", content.Type,
+			"author_id", content.AuthorID,
+			"session_id", content.SessionID,
+			"reason", verdict.Reason,
+		)
+	}
+
+	return verdict
+}
+
+// queues flagged or hidden content for review. call after the content was
+// stored so its ID is known. other verdicts are ignored.
+func (s *Service) Report(ctx context.Context, content Content, verdict Verdict) {
+	if verdict.Action != ActionFlag && verdict.Action != ActionHide {
+		return
+	}
+
+	_, err := s.db.Exec(context.WithoutCancel(ctx), queryEnqueue,
+		content.Type,
+		content.ID,
+		content.SessionID,
+		content.AuthorID,
+		content.AuthorName,
+		content.Text,
+		verdict.Action,
+		verdict.Reason,
+	)
+	if err != nil {
+		logger.ErrorErr(err, "failed to queue content for review",
+			"content_type", content.Type,
+			"content_id", content.ID,
+		)
+	}
+}
+
+// lists review queue items, newest first. empty filters match everything.
+func (s *Service) List(ctx context.Context, status, contentType string, limit, offset int) ([]QueueItem, int, error) {
+	var total int
+	if err := s.db.QueryRow(ctx, queryCount, status, contentType).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(ctx, queryList, status, contentType, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	items := []QueueItem{}
+
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		items = append(items, *item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// closes a pending item as approved or removed
+func (s *Service) Resolve(ctx context.Context, itemID, reviewerID, status string) (*QueueItem, error) {
+	item, err := scanItem(s.db.QueryRow(ctx, queryResolve, itemID, status, reviewerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrItemNotFound
+	}
+
+	return item, err
+}
+
+func scanItem(row pgx.Row) (*QueueItem, error) {
+	var item QueueItem
+	err := row.Scan(
+		&item.ID,
+		&item.ContentType,
+		&item.ContentID,
+		&item.SessionID,
+		&item.AuthorID,
+		&item.AuthorName,
+		&item.Content,
+		&item.Action,
+		&item.Reason,
+		&item.Status,
+		&item.ReviewedBy,
+		&item.ReviewedAt,
+		&item.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,31 @@
+package moderation
+
+const (
+	queryEnqueue = `
+		INSERT INTO moderation_queue (content_type, content_id, session_id, author_id, author_name, content, action, reason)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, '')::uuid, NULLIF($4, '')::uuid, NULLIF($5, ''), $6, $7, $8)
+	`
+
+	queryList = `
+		SELECT id, content_type, content_id, session_id, author_id, author_name, content, action, reason, status, reviewed_by, reviewed_at, created_at
+		FROM moderation_queue
+		WHERE ($1 = '' OR status = $1)
+		  AND ($2 = '' OR content_type = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	queryCount = `
+		SELECT COUNT(*)
+		FROM moderation_queue
+		WHERE ($1 = '' OR status = $1)
+		  AND ($2 = '' OR content_type = $2)
+	`
+
+	queryResolve = `
+		UPDATE moderation_queue
+		SET status = $2, reviewed_by = $3, reviewed_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING id, content_type, content_id, session_id, author_id, author_name, content, action, reason, status, reviewed_by, reviewed_at, created_at
+	`
+)
@@ -0,0 +1,101 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// what happens to screened content, from least to most severe.
+// flagged and hidden content is queued for admin review.
+const (
+	ActionAllow = "allow"
+	ActionFlag  = "flag"  // shared as usual
+	ActionHide  = "hide"  // shadow-hidden: only the author sees it until approved
+	ActionBlock = "block" // rejected
+)
+
+var severity = map[string]int{
+	ActionAllow: 0,
+	ActionFlag:  1,
+	ActionHide:  2,
+	ActionBlock: 3,
+}
+
+// kinds of content that are screened
+const (
+	ContentChatMessage = "chat_message"
+	ContentStrudel     = "strudel"
+)
+
+// review queue statuses
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRemoved  = "removed"
+)
+
+const (
+	// the classifier runs inline with chat, so it gets little time before failing open
+	llmTimeout = 3 * time.Second
+
+	// longest text sent to the classifier
+	maxClassifiedLength = 4000
+)
+
+var ErrItemNotFound = errors.New("moderation item not found or already resolved")
+
+// moderation settings, read from the environment
+type Config struct {
+	BlockWords []string // MODERATION_BLOCK_WORDS, comma-separated words or phrases
+	HideWords  []string // MODERATION_HIDE_WORDS
+	FlagWords  []string // MODERATION_FLAG_WORDS
+	UseLLM     bool     // MODERATION_LLM=true classifies content with the platform LLM
+}
+
+// screens content and keeps the review queue
+type Service struct {
+	db       *pgxpool.Pool
+	checkers []Checker
+}
+
+// decides what to do with a piece of content. implementations return an
+// allow verdict for content they have no objection to.
+type Checker interface {
+	Check(ctx context.Context, content Content) (Verdict, error)
+}
+
+// a piece of user content to screen
+type Content struct {
+	Type       string // ContentChatMessage or ContentStrudel
+	ID         string // message or strudel ID, if known
+	SessionID  string
+	AuthorID   string // empty for anonymous participants
+	AuthorName string
+	Text       string
+}
+
+// the outcome of screening
+type Verdict struct {
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// an entry of the review queue
+type QueueItem struct {
+	ID          string     `json:"id"`
+	ContentType string     `json:"content_type"`
+	ContentID   *string    `json:"content_id,omitempty"`
+	SessionID   *string    `json:"session_id,omitempty"`
+	AuthorID    *string    `json:"author_id,omitempty"`
+	AuthorName  *string    `json:"author_name,omitempty"`
+	Content     string     `json:"content"`
+	Action      string     `json:"action"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status"`
+	ReviewedBy  *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
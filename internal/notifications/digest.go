@@ -0,0 +1,127 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// runs the digest worker until ctx is cancelled. safe to run on every
+// instance; users due a digest are claimed with row locks.
+func (s *Service) Start(ctx context.Context) {
+	logger.Info("starting notification worker", "poll_interval", digestPollInterval, "digests", s.digests != nil)
+
+	ticker := time.NewTicker(digestPollInterval)
+	defer ticker.Stop()
+
+	lastRetention := time.Time{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("notification worker stopped")
+			return
+		case <-ticker.C:
+			if s.digests != nil {
+				s.sendDueDigests(ctx)
+			}
+
+			if time.Since(lastRetention) >= retentionInterval {
+				s.deleteOld(ctx)
+				lastRetention = time.Now()
+			}
+		}
+	}
+}
+
+// claims users due a digest and emails each their unread notifications
+func (s *Service) sendDueDigests(ctx context.Context) {
+	rows, err := s.db.Query(ctx, queryClaimDigests, digestBatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to claim notification digests")
+		}
+		return
+	}
+
+	var claimed []Digest
+	for rows.Next() {
+		var d Digest
+		if err := rows.Scan(&d.UserID, &d.Frequency, &d.Email, &d.Name); err != nil {
+			rows.Close()
+			logger.ErrorErr(err, "failed to scan notification digest")
+			return
+		}
+
+		claimed = append(claimed, d)
+	}
+
+	rows.Close()
+
+	for _, d := range claimed {
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.sendDigest(ctx, d)
+	}
+}
+
+// sends one digest and marks its notifications as emailed. nothing is sent
+// when the user has no unread notifications that weren't emailed before.
+func (s *Service) sendDigest(ctx context.Context, d Digest) {
+	rows, err := s.db.Query(ctx, queryListUndigested, d.UserID, maxDigestNotifications)
+	if err != nil {
+		logger.ErrorErr(err, "failed to list notifications for digest", "user_id", d.UserID)
+		return
+	}
+
+	var ids []string
+	for rows.Next() {
+		var n Notification
+		if err := rows.Scan(&n.ID, &n.Kind, &n.Message, &n.Data, &n.ReadAt, &n.CreatedAt); err != nil {
+			rows.Close()
+			logger.ErrorErr(err, "failed to scan notification for digest", "user_id", d.UserID)
+			return
+		}
+
+		d.Notifications = append(d.Notifications, &n)
+		ids = append(ids, n.ID)
+	}
+
+	rows.Close()
+
+	if len(d.Notifications) == 0 {
+		return
+	}
+
+	// unsent notifications stay undigested and go out with the next digest
+	if err := s.digests.SendDigest(ctx, d); err != nil {
+		logger.Warn("failed to send notification digest", "error", err, "user_id", d.UserID)
+		return
+	}
+
+	// record even if the worker is stopping, so the digest isn't sent twice
+	markCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), writeTimeout)
+	defer cancel()
+
+	if _, err := s.db.Exec(markCtx, queryMarkEmailed, ids); err != nil {
+		logger.ErrorErr(err, "failed to mark notifications as emailed", "user_id", d.UserID)
+	}
+}
+
+// deletes notifications past the retention period
+func (s *Service) deleteOld(ctx context.Context) {
+	result, err := s.db.Exec(ctx, queryDeleteOld, retention.Seconds())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to delete old notifications")
+		}
+		return
+	}
+
+	if deleted := result.RowsAffected(); deleted > 0 {
+		logger.Info("deleted old notifications", "count", deleted)
+	}
+}
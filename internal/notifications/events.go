@@ -0,0 +1,91 @@
+package notifications
+
+import (
+	"fmt"
+
+	"codeberg.org/algopatterns/server/internal/webhooks"
+)
+
+// data of session.invite, sent to the invited user
+type InviteData struct {
+	SessionID string `json:"session_id"`
+	Title     string `json:"title"`
+	InvitedBy string `json:"invited_by"` // user ID
+	Role      string `json:"role"`
+	Token     string `json:"token,omitempty"` // invite token, for joining from the notification
+}
+
+// data of session.join_request, sent to the host, and of the answer sent to the requester
+type JoinRequestData struct {
+	SessionID   string `json:"session_id"`
+	RequestID   string `json:"request_id"`
+	Title       string `json:"title"`
+	DisplayName string `json:"display_name"` // of the requester
+	Status      string `json:"status"`
+}
+
+// builds the notification for a webhook event. ok is false for events
+// users aren't notified of.
+func fromEvent(event string, data any) (kind, message string, ok bool) {
+	switch event {
+	case webhooks.EventStrudelForked:
+		d, isFork := data.(webhooks.ForkEventData)
+		if !isFork {
+			return "", "", false
+		}
+
+		return KindStrudelForked, fmt.Sprintf("Your strudel was forked as %q", d.ForkTitle), true
+
+	case webhooks.EventAttributionRecorded:
+		d, isAttribution := data.(webhooks.AttributionEventData)
+		if !isAttribution {
+			return "", "", false
+		}
+
+		return KindAttributionRecorded, fmt.Sprintf("Your strudel %q was used as a reference by the AI assistant", d.StrudelTitle), true
+
+	case webhooks.EventSessionEnded:
+		d, isSession := data.(webhooks.SessionEventData)
+		if !isSession || d.Reason == reasonEndedByHost {
+			return "", "", false
+		}
+
+		return KindSessionEnded, sessionEndedMessage(d), true
+	}
+
+	return "", "", false
+}
+
+func sessionEndedMessage(d webhooks.SessionEventData) string {
+	switch d.Reason {
+	case reasonInactivity:
+		return fmt.Sprintf("Your session %q was ended after a period of inactivity", d.Title)
+	case reasonEndedByAdmin:
+		return fmt.Sprintf("Your session %q was ended by an administrator", d.Title)
+	default:
+		return fmt.Sprintf("Your session %q was ended", d.Title)
+	}
+}
+
+// message of the notification sent to a user invited to a session
+func InviteMessage(inviterName, title string) string {
+	if inviterName == "" {
+		return fmt.Sprintf("You were invited to the session %q", title)
+	}
+
+	return fmt.Sprintf("%s invited you to the session %q", inviterName, title)
+}
+
+// message of the notification sent to a host when someone asks to join
+func JoinRequestMessage(displayName, title string) string {
+	return fmt.Sprintf("%s asked to join your session %q", displayName, title)
+}
+
+// message of the notification sent to a user whose join request was answered
+func JoinRequestAnsweredMessage(title string, approved bool) string {
+	if approved {
+		return fmt.Sprintf("Your request to join the session %q was approved", title)
+	}
+
+	return fmt.Sprintf("Your request to join the session %q was declined", title)
+}
@@ -0,0 +1,75 @@
+package notifications
+
+import (
+	"testing"
+
+	"codeberg.org/algopatterns/server/internal/webhooks"
+)
+
+func TestFromEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   string
+		data    any
+		kind    string
+		message string
+		ok      bool
+	}{
+		{
+			name:    "fork",
+			event:   webhooks.EventStrudelForked,
+			data:    webhooks.ForkEventData{StrudelID: "s1", ForkID: "s2", ForkTitle: "my remix"},
+			kind:    KindStrudelForked,
+			message: `Your strudel was forked as "my remix"`,
+			ok:      true,
+		},
+		{
+			name:    "attribution",
+			event:   webhooks.EventAttributionRecorded,
+			data:    webhooks.AttributionEventData{StrudelID: "s1", StrudelTitle: "drums"},
+			kind:    KindAttributionRecorded,
+			message: `Your strudel "drums" was used as a reference by the AI assistant`,
+			ok:      true,
+		},
+		{
+			name:    "session ended by cleanup",
+			event:   webhooks.EventSessionEnded,
+			data:    webhooks.SessionEventData{SessionID: "x", Title: "jam", Reason: reasonInactivity},
+			kind:    KindSessionEnded,
+			message: `Your session "jam" was ended after a period of inactivity`,
+			ok:      true,
+		},
+		{
+			name:    "session ended by admin",
+			event:   webhooks.EventSessionEnded,
+			data:    webhooks.SessionEventData{SessionID: "x", Title: "jam", Reason: reasonEndedByAdmin},
+			kind:    KindSessionEnded,
+			message: `Your session "jam" was ended by an administrator`,
+			ok:      true,
+		},
+		{
+			name:  "session ended by host",
+			event: webhooks.EventSessionEnded,
+			data:  webhooks.SessionEventData{SessionID: "x", Title: "jam", Reason: reasonEndedByHost},
+		},
+		{
+			name:  "unrelated event",
+			event: webhooks.EventSessionCreated,
+			data:  webhooks.SessionEventData{SessionID: "x", Title: "jam"},
+		},
+		{
+			name:  "unexpected data",
+			event: webhooks.EventStrudelForked,
+			data:  map[string]string{"fork_title": "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, message, ok := fromEvent(tt.event, tt.data)
+			if ok != tt.ok || kind != tt.kind || message != tt.message {
+				t.Errorf("fromEvent() = (%q, %q, %v), want (%q, %q, %v)", kind, message, ok, tt.kind, tt.message, tt.ok)
+			}
+		})
+	}
+}
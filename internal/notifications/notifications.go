@@ -0,0 +1,161 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/jobs"
+)
+
+func New(db *pgxpool.Pool, queue *jobs.Queue) *Service {
+	s := &Service{db: db, jobs: queue}
+
+	queue.Register(jobNotify, s.handleNotifyJob)
+
+	return s
+}
+
+// enables email digests
+func (s *Service) SetDigestSender(digests DigestSender) {
+	s.digests = digests
+}
+
+// stores a notification for a user in the transaction of the write it's
+// about. the notification is written by a background job so callers never
+// wait on notification bookkeeping, and it's only stored when the write commits
+func (s *Service) NotifyTx(ctx context.Context, tx pgx.Tx, userID, kind, message string, data any) error {
+	if userID == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s notification: %w", kind, err)
+	}
+
+	return s.jobs.EnqueueTx(ctx, tx, jobNotify, notifyJob{UserID: userID, Kind: kind, Message: message, Data: payload})
+}
+
+func (s *Service) handleNotifyJob(ctx context.Context, payload json.RawMessage) error {
+	var job notifyJob
+	if err := jobs.Decode(payload, &job); err != nil {
+		return err
+	}
+
+	_, err := s.db.Exec(ctx, queryCreate, job.UserID, job.Kind, job.Message, job.Data)
+	return err
+}
+
+// notifies the user of the events they'd want to hear about; other events
+// are ignored. lets the service sit next to webhooks as an event publisher.
+func (s *Service) PublishTx(ctx context.Context, tx pgx.Tx, userID, event string, data any) error {
+	kind, message, ok := fromEvent(event, data)
+	if !ok {
+		return nil
+	}
+
+	return s.NotifyTx(ctx, tx, userID, kind, message, data)
+}
+
+// gets a user's notifications, newest first, with the total matching and the unread count
+func (s *Service) List(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]*Notification, int, int, error) {
+	rows, err := s.db.Query(ctx, queryList, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	defer rows.Close()
+	notifications := []*Notification{}
+	total := 0
+
+	for rows.Next() {
+		var n Notification
+		if err := rows.Scan(&n.ID, &n.Kind, &n.Message, &n.Data, &n.ReadAt, &n.CreatedAt, &total); err != nil {
+			return nil, 0, 0, err
+		}
+
+		notifications = append(notifications, &n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	unread, err := s.UnreadCount(ctx, userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return notifications, total, unread, nil
+}
+
+// counts a user's unread notifications
+func (s *Service) UnreadCount(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := s.db.QueryRow(ctx, queryUnreadCount, userID).Scan(&count)
+	return count, err
+}
+
+// marks a notification of the user as read
+func (s *Service) MarkRead(ctx context.Context, notificationID, userID string) (*Notification, error) {
+	var n Notification
+
+	err := s.db.QueryRow(ctx, queryMarkRead, notificationID, userID).Scan(&n.ID, &n.Kind, &n.Message, &n.Data, &n.ReadAt, &n.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotificationNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+// marks all of a user's notifications as read, returning how many were unread
+func (s *Service) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	result, err := s.db.Exec(ctx, queryMarkAllRead, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// gets a user's notification settings; users who never changed them get the defaults
+func (s *Service) GetPreferences(ctx context.Context, userID string) (*Preferences, error) {
+	var p Preferences
+
+	err := s.db.QueryRow(ctx, queryGetPreferences, userID).Scan(&p.EmailDigest, &p.LastDigestAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &Preferences{EmailDigest: DigestOff}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// sets how often a user receives the email digest
+func (s *Service) SetEmailDigest(ctx context.Context, userID, frequency string) (*Preferences, error) {
+	if !slices.Contains(DigestFrequencies, frequency) {
+		return nil, ErrUnknownDigest
+	}
+
+	var p Preferences
+
+	err := s.db.QueryRow(ctx, queryUpsertPreferences, userID, frequency).Scan(&p.EmailDigest, &p.LastDigestAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,94 @@
+package notifications
+
+const (
+	queryCreate = `
+		INSERT INTO notifications (user_id, kind, message, data)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	queryList = `
+		SELECT id, kind, message, data, read_at, created_at,
+			COUNT(*) OVER () AS total
+		FROM notifications
+		WHERE user_id = $1 AND ($2 = false OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	queryUnreadCount = `
+		SELECT COUNT(*)
+		FROM notifications
+		WHERE user_id = $1 AND read_at IS NULL
+	`
+
+	queryMarkRead = `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, kind, message, data, read_at, created_at
+	`
+
+	queryMarkAllRead = `
+		UPDATE notifications
+		SET read_at = NOW()
+		WHERE user_id = $1 AND read_at IS NULL
+	`
+
+	queryGetPreferences = `
+		SELECT email_digest, last_digest_at
+		FROM notification_preferences
+		WHERE user_id = $1
+	`
+
+	// the first digest goes out one period after it is enabled
+	queryUpsertPreferences = `
+		INSERT INTO notification_preferences (user_id, email_digest, last_digest_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET email_digest = EXCLUDED.email_digest,
+		    last_digest_at = COALESCE(notification_preferences.last_digest_at, NOW()),
+		    updated_at = NOW()
+		RETURNING email_digest, last_digest_at
+	`
+
+	// claims users due a digest by moving last_digest_at to now, so other
+	// instances skip them
+	queryClaimDigests = `
+		WITH due AS (
+			SELECT user_id
+			FROM notification_preferences
+			WHERE email_digest <> 'off'
+			  AND (last_digest_at IS NULL OR last_digest_at <= NOW() - CASE email_digest
+			      WHEN 'daily' THEN INTERVAL '1 day'
+			      ELSE INTERVAL '7 days'
+			  END)
+			ORDER BY last_digest_at NULLS FIRST
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE notification_preferences p
+		SET last_digest_at = NOW()
+		FROM due, users u
+		WHERE p.user_id = due.user_id AND u.id = p.user_id
+		RETURNING p.user_id, p.email_digest, u.email, COALESCE(u.name, '')
+	`
+
+	queryListUndigested = `
+		SELECT id, kind, message, data, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND read_at IS NULL AND emailed_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	queryMarkEmailed = `
+		UPDATE notifications
+		SET emailed_at = NOW()
+		WHERE id = ANY($1)
+	`
+
+	queryDeleteOld = `
+		DELETE FROM notifications
+		WHERE created_at < NOW() - make_interval(secs => $1)
+	`
+)
@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/jobs"
+)
+
+// notification kinds
+const (
+	KindStrudelForked       = "strudel.forked"
+	KindAttributionRecorded = "attribution.recorded"
+	KindSessionInvite       = "session.invite"
+	KindSessionEnded        = "session.ended"
+	KindJoinRequest         = "session.join_request"
+	KindJoinRequestApproved = "session.join_approved"
+	KindJoinRequestDenied   = "session.join_denied"
+)
+
+// email digest frequencies
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// frequencies a user can choose for the email digest
+var DigestFrequencies = []string{DigestOff, DigestDaily, DigestWeekly}
+
+// background job that stores a notification
+const jobNotify = "notifications.create"
+
+// worker constants
+const (
+	writeTimeout = 5 * time.Second
+
+	// how often the worker looks for users due a digest, and how many it claims at once
+	digestPollInterval = 15 * time.Minute
+	digestBatchSize    = 50
+
+	// the most notifications listed in one digest; the rest are still unread in-app
+	maxDigestNotifications = 50
+
+	// notifications older than this are deleted
+	retention         = 90 * 24 * time.Hour
+	retentionInterval = time.Hour
+)
+
+// payload of a jobNotify job
+type notifyJob struct {
+	UserID  string          `json:"user_id"`
+	Kind    string          `json:"kind"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// errors
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrUnknownDigest        = errors.New("unknown email digest frequency")
+)
+
+// reasons of session.ended events; hosts aren't notified of sessions they ended themselves
+const (
+	reasonEndedByHost  = "ended_by_host"
+	reasonEndedByAdmin = "ended_by_admin"
+	reasonInactivity   = "inactivity"
+)
+
+// stores notifications and sends email digests of unread ones
+type Service struct {
+	db      *pgxpool.Pool
+	jobs    *jobs.Queue
+	digests DigestSender // nil disables email digests
+}
+
+// sends email digests (implemented by the mailer)
+type DigestSender interface {
+	SendDigest(ctx context.Context, digest Digest) error
+}
+
+// a notification shown to one user
+type Notification struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// a user's notification settings
+type Preferences struct {
+	EmailDigest  string     `json:"email_digest"`
+	LastDigestAt *time.Time `json:"last_digest_at,omitempty"`
+}
+
+// unread notifications due to be emailed to a user
+type Digest struct {
+	UserID        string
+	Email         string
+	Name          string
+	Frequency     string // daily or weekly
+	Notifications []*Notification
+}
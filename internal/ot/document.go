@@ -0,0 +1,90 @@
+package ot
+
+import "unicode/utf16"
+
+// creates a document at revision 0 with the given text
+func NewDocument(text string) *Document {
+	return &Document{
+		text:       utf16.Encode([]rune(text)),
+		maxHistory: DefaultMaxHistory,
+	}
+}
+
+// returns the current document text
+func (d *Document) Text() string {
+	return string(utf16.Decode(d.text))
+}
+
+// returns the number of operations applied to the document
+func (d *Document) Revision() int {
+	return d.revision
+}
+
+// returns the document length in UTF-16 code units
+func (d *Document) Length() int {
+	return len(d.text)
+}
+
+// rebases an operation made against an earlier revision onto the current
+// revision by transforming it against every operation applied since
+func (d *Document) Transform(revision int, op Operation) (Operation, error) {
+	if revision > d.revision {
+		return nil, ErrRevisionInFuture
+	}
+
+	if revision < d.historyBase {
+		return nil, ErrRevisionTooOld
+	}
+
+	for _, concurrent := range d.history[revision-d.historyBase:] {
+		var err error
+
+		op, _, err = Transform(op, concurrent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return op, nil
+}
+
+// applies an operation based on the current revision and returns the new revision
+func (d *Document) Apply(op Operation) (int, error) {
+	text, err := apply(d.text, op)
+	if err != nil {
+		return d.revision, err
+	}
+
+	d.commit(text, op)
+
+	return d.revision, nil
+}
+
+// replaces the whole document, recording the change as a single operation
+// so that concurrent edits can still be transformed against it
+func (d *Document) Replace(text string) (Operation, int) {
+	var op Operation
+	op = op.Delete(len(d.text)).Insert(text)
+
+	if op == nil {
+		// replacing an empty document with empty text is a no-op
+		return op, d.revision
+	}
+
+	d.commit(utf16.Encode([]rune(text)), op)
+
+	return op, d.revision
+}
+
+// stores the new text and records the operation that produced it
+func (d *Document) commit(text []uint16, op Operation) {
+	d.text = text
+	d.revision++
+	d.history = append(d.history, op)
+
+	// drop the oldest operations once history exceeds the limit
+	if overflow := len(d.history) - d.maxHistory; overflow > 0 {
+		d.history = append([]Operation(nil), d.history[overflow:]...)
+		d.historyBase += overflow
+	}
+}
@@ -0,0 +1,325 @@
+package ot
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf16"
+)
+
+// returns true if the component retains characters
+func (c Component) IsRetain() bool {
+	return c.Retain > 0
+}
+
+// returns true if the component inserts characters
+func (c Component) IsInsert() bool {
+	return c.Insert != ""
+}
+
+// returns true if the component deletes characters
+func (c Component) IsDelete() bool {
+	return c.Delete > 0
+}
+
+// appends a retain component, merging with a trailing retain
+func (o Operation) Retain(n int) Operation {
+	if n <= 0 {
+		return o
+	}
+
+	if last := len(o) - 1; last >= 0 && o[last].IsRetain() {
+		o[last].Retain += n
+		return o
+	}
+
+	return append(o, Component{Retain: n})
+}
+
+// appends an insert component. inserts are always ordered before an adjacent
+// delete so that equivalent operations have a single canonical form.
+func (o Operation) Insert(s string) Operation {
+	if s == "" {
+		return o
+	}
+
+	last := len(o) - 1
+
+	if last >= 0 && o[last].IsInsert() {
+		o[last].Insert += s
+		return o
+	}
+
+	if last >= 0 && o[last].IsDelete() {
+		if last > 0 && o[last-1].IsInsert() {
+			o[last-1].Insert += s
+			return o
+		}
+
+		o = append(o, o[last])
+		o[last] = Component{Insert: s}
+		return o
+	}
+
+	return append(o, Component{Insert: s})
+}
+
+// appends a delete component, merging with a trailing delete
+func (o Operation) Delete(n int) Operation {
+	if n <= 0 {
+		return o
+	}
+
+	if last := len(o) - 1; last >= 0 && o[last].IsDelete() {
+		o[last].Delete += n
+		return o
+	}
+
+	return append(o, Component{Delete: n})
+}
+
+// returns the length of the document this operation can be applied to
+func (o Operation) BaseLength() int {
+	n := 0
+
+	for _, c := range o {
+		n += c.Retain + c.Delete
+	}
+
+	return n
+}
+
+// returns the length of the document after this operation is applied
+func (o Operation) TargetLength() int {
+	n := 0
+
+	for _, c := range o {
+		n += c.Retain + utf16Len(c.Insert)
+	}
+
+	return n
+}
+
+// returns true if the operation leaves the document unchanged
+func (o Operation) IsNoop() bool {
+	for _, c := range o {
+		if !c.IsRetain() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checks that every component sets exactly one field
+func (o Operation) Validate() error {
+	for i, c := range o {
+		set := 0
+
+		if c.Retain != 0 {
+			set++
+		}
+
+		if c.Insert != "" {
+			set++
+		}
+
+		if c.Delete != 0 {
+			set++
+		}
+
+		if set != 1 || c.Retain < 0 || c.Delete < 0 {
+			return fmt.Errorf("%w at index %d", ErrInvalidComponent, i)
+		}
+	}
+
+	return nil
+}
+
+// encodes the operation in ot.js format
+func (o Operation) MarshalJSON() ([]byte, error) {
+	out := make([]any, 0, len(o))
+
+	for _, c := range o {
+		switch {
+		case c.IsRetain():
+			out = append(out, c.Retain)
+		case c.IsInsert():
+			out = append(out, c.Insert)
+		case c.IsDelete():
+			out = append(out, -c.Delete)
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+// decodes an operation from ot.js format
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	op := make(Operation, 0, len(raw))
+
+	for i, item := range raw {
+		var n int
+		if err := json.Unmarshal(item, &n); err == nil {
+			switch {
+			case n > 0:
+				op = op.Retain(n)
+			case n < 0:
+				op = op.Delete(-n)
+			default:
+				return fmt.Errorf("%w at index %d: zero length", ErrInvalidComponent, i)
+			}
+
+			continue
+		}
+
+		var s string
+		if err := json.Unmarshal(item, &s); err != nil || s == "" {
+			return fmt.Errorf("%w at index %d", ErrInvalidComponent, i)
+		}
+
+		op = op.Insert(s)
+	}
+
+	*o = op
+	return nil
+}
+
+// applies an operation to a string
+func Apply(text string, op Operation) (string, error) {
+	out, err := apply(utf16.Encode([]rune(text)), op)
+	if err != nil {
+		return "", err
+	}
+
+	return string(utf16.Decode(out)), nil
+}
+
+func apply(text []uint16, op Operation) ([]uint16, error) {
+	if op.BaseLength() != len(text) {
+		return nil, ErrBaseLengthMismatch
+	}
+
+	out := make([]uint16, 0, op.TargetLength())
+	pos := 0
+
+	for _, c := range op {
+		switch {
+		case c.IsRetain():
+			out = append(out, text[pos:pos+c.Retain]...)
+			pos += c.Retain
+		case c.IsInsert():
+			out = append(out, utf16.Encode([]rune(c.Insert))...)
+		case c.IsDelete():
+			pos += c.Delete
+		default:
+			return nil, ErrInvalidComponent
+		}
+	}
+
+	return out, nil
+}
+
+// transforms two concurrent operations a and b, both based on the same
+// document, into a' and b' such that apply(apply(d, a), b') equals
+// apply(apply(d, b), a'). when both insert at the same position, a's
+// insert is placed first.
+func Transform(a, b Operation) (Operation, Operation, error) {
+	if a.BaseLength() != b.BaseLength() {
+		return nil, nil, ErrIncompatibleOps
+	}
+
+	var aPrime, bPrime Operation
+
+	i, j := 0, 0
+	var ca, cb *Component
+
+	next := func(op Operation, idx *int) *Component {
+		if *idx >= len(op) {
+			return nil
+		}
+
+		c := op[*idx]
+		*idx++
+
+		return &c
+	}
+
+	ca = next(a, &i)
+	cb = next(b, &j)
+
+	for ca != nil || cb != nil {
+		if ca != nil && ca.IsInsert() {
+			aPrime = aPrime.Insert(ca.Insert)
+			bPrime = bPrime.Retain(utf16Len(ca.Insert))
+			ca = next(a, &i)
+			continue
+		}
+
+		if cb != nil && cb.IsInsert() {
+			aPrime = aPrime.Retain(utf16Len(cb.Insert))
+			bPrime = bPrime.Insert(cb.Insert)
+			cb = next(b, &j)
+			continue
+		}
+
+		if ca == nil || cb == nil {
+			return nil, nil, ErrIncompatibleOps
+		}
+
+		switch {
+		case ca.IsRetain() && cb.IsRetain():
+			n := min(ca.Retain, cb.Retain)
+			aPrime = aPrime.Retain(n)
+			bPrime = bPrime.Retain(n)
+			ca.Retain -= n
+			cb.Retain -= n
+
+		case ca.IsDelete() && cb.IsDelete():
+			// both deleted the same range, nothing left to do
+			n := min(ca.Delete, cb.Delete)
+			ca.Delete -= n
+			cb.Delete -= n
+
+		case ca.IsDelete() && cb.IsRetain():
+			n := min(ca.Delete, cb.Retain)
+			aPrime = aPrime.Delete(n)
+			ca.Delete -= n
+			cb.Retain -= n
+
+		case ca.IsRetain() && cb.IsDelete():
+			n := min(ca.Retain, cb.Delete)
+			bPrime = bPrime.Delete(n)
+			ca.Retain -= n
+			cb.Delete -= n
+
+		default:
+			return nil, nil, ErrInvalidComponent
+		}
+
+		if ca.Retain == 0 && ca.Delete == 0 {
+			ca = next(a, &i)
+		}
+
+		if cb.Retain == 0 && cb.Delete == 0 {
+			cb = next(b, &j)
+		}
+	}
+
+	return aPrime, bPrime, nil
+}
+
+// returns the length of a string in UTF-16 code units
+func utf16Len(s string) int {
+	n := 0
+
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+
+	return n
+}
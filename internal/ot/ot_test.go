@@ -0,0 +1,239 @@
+package ot
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		op   Operation
+		want string
+	}{
+		{
+			name: "insert at start",
+			text: "sound(\"bd\")",
+			op:   Operation{}.Insert("// kick\n").Retain(11),
+			want: "// kick\nsound(\"bd\")",
+		},
+		{
+			name: "delete in middle",
+			text: "sound(\"bd sd\")",
+			op:   Operation{}.Retain(9).Delete(3).Retain(2),
+			want: "sound(\"bd\")",
+		},
+		{
+			name: "replace word",
+			text: "note(\"c e g\")",
+			op:   Operation{}.Retain(6).Delete(5).Insert("a c e").Retain(2),
+			want: "note(\"a c e\")",
+		},
+		{
+			name: "astral characters count as two units",
+			text: "🥁 bd",
+			op:   Operation{}.Retain(2).Delete(3).Insert(" sd"),
+			want: "🥁 sd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.text, tt.op)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyBaseLengthMismatch(t *testing.T) {
+	_, err := Apply("abc", Operation{}.Retain(5))
+	if !errors.Is(err, ErrBaseLengthMismatch) {
+		t.Errorf("Apply() error = %v, want %v", err, ErrBaseLengthMismatch)
+	}
+}
+
+func TestTransformConverges(t *testing.T) {
+	base := "sound(\"bd sd\").fast(2)"
+
+	tests := []struct {
+		name string
+		a    Operation
+		b    Operation
+	}{
+		{
+			name: "inserts at different positions",
+			a:    Operation{}.Insert("$: ").Retain(22),
+			b:    Operation{}.Retain(22).Insert(".gain(0.8)"),
+		},
+		{
+			name: "inserts at same position",
+			a:    Operation{}.Retain(9).Insert(" hh").Retain(13),
+			b:    Operation{}.Retain(9).Insert(" cp").Retain(13),
+		},
+		{
+			name: "overlapping deletes",
+			a:    Operation{}.Retain(7).Delete(5).Retain(10),
+			b:    Operation{}.Retain(10).Delete(5).Retain(7),
+		},
+		{
+			name: "delete and insert inside deleted range",
+			a:    Operation{}.Retain(14).Delete(8),
+			b:    Operation{}.Retain(20).Insert("4").Retain(2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aPrime, bPrime, err := Transform(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+
+			afterA, err := Apply(base, tt.a)
+			if err != nil {
+				t.Fatalf("Apply(a) error = %v", err)
+			}
+
+			afterB, err := Apply(base, tt.b)
+			if err != nil {
+				t.Fatalf("Apply(b) error = %v", err)
+			}
+
+			left, err := Apply(afterA, bPrime)
+			if err != nil {
+				t.Fatalf("Apply(b') error = %v", err)
+			}
+
+			right, err := Apply(afterB, aPrime)
+			if err != nil {
+				t.Fatalf("Apply(a') error = %v", err)
+			}
+
+			if left != right {
+				t.Errorf("documents diverged: %q vs %q", left, right)
+			}
+		})
+	}
+}
+
+func TestTransformTieBreak(t *testing.T) {
+	a := Operation{}.Insert("a")
+	b := Operation{}.Insert("b")
+
+	aPrime, _, err := Transform(a, b)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+
+	got, _ := Apply("b", aPrime)
+	if got != "ab" {
+		t.Errorf("expected first operand's insert first, got %q", got)
+	}
+}
+
+func TestOperationJSON(t *testing.T) {
+	var op Operation
+	if err := json.Unmarshal([]byte(`[3,"xy",-2,1]`), &op); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := Operation{{Retain: 3}, {Insert: "xy"}, {Delete: 2}, {Retain: 1}}
+	if len(op) != len(want) {
+		t.Fatalf("got %d components, want %d", len(op), len(want))
+	}
+
+	for i := range want {
+		if op[i] != want[i] {
+			t.Errorf("component %d = %+v, want %+v", i, op[i], want[i])
+		}
+	}
+
+	data, err := json.Marshal(op)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if string(data) != `[3,"xy",-2,1]` {
+		t.Errorf("Marshal() = %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`[0]`), &op); err == nil {
+		t.Error("expected error for zero-length component")
+	}
+}
+
+func TestDocumentTransformsStaleRevisions(t *testing.T) {
+	doc := NewDocument("sound(\"bd\")")
+
+	// two writers edit revision 0 concurrently
+	first := Operation{}.Retain(9).Insert(" sd").Retain(2)
+	second := Operation{}.Retain(11).Insert(".fast(2)")
+
+	if _, err := doc.Apply(first); err != nil {
+		t.Fatalf("Apply(first) error = %v", err)
+	}
+
+	rebased, err := doc.Transform(0, second)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+
+	revision, err := doc.Apply(rebased)
+	if err != nil {
+		t.Fatalf("Apply(rebased) error = %v", err)
+	}
+
+	if revision != 2 {
+		t.Errorf("revision = %d, want 2", revision)
+	}
+
+	if got := doc.Text(); got != "sound(\"bd sd\").fast(2)" {
+		t.Errorf("Text() = %q", got)
+	}
+
+	if _, err := doc.Transform(3, second); !errors.Is(err, ErrRevisionInFuture) {
+		t.Errorf("expected ErrRevisionInFuture, got %v", err)
+	}
+}
+
+func TestDocumentHistoryLimit(t *testing.T) {
+	doc := NewDocument("")
+	doc.maxHistory = 2
+
+	for range 3 {
+		doc.Apply(Operation{}.Retain(doc.Length()).Insert("x")) //nolint:errcheck,gosec // test setup
+	}
+
+	if _, err := doc.Transform(0, Operation{}); !errors.Is(err, ErrRevisionTooOld) {
+		t.Errorf("expected ErrRevisionTooOld, got %v", err)
+	}
+
+	if _, err := doc.Transform(1, Operation{}.Retain(1)); err != nil {
+		t.Errorf("Transform(1) error = %v", err)
+	}
+}
+
+func TestDocumentReplace(t *testing.T) {
+	doc := NewDocument("old")
+
+	op, revision := doc.Replace("new code")
+	if revision != 1 {
+		t.Errorf("revision = %d, want 1", revision)
+	}
+
+	if op.BaseLength() != 3 || op.TargetLength() != 8 {
+		t.Errorf("unexpected replace operation %+v", op)
+	}
+
+	if doc.Text() != "new code" {
+		t.Errorf("Text() = %q", doc.Text())
+	}
+}
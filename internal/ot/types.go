@@ -0,0 +1,43 @@
+// package ot implements operational transformation for plain-text documents.
+// operations use the ot.js wire format so browser editors can share one model
+// with the server: positive integers retain, negative integers delete and
+// strings insert. all lengths are measured in UTF-16 code units to match
+// JavaScript string indexing.
+package ot
+
+import "errors"
+
+// maximum number of operations kept for transforming stale revisions
+const DefaultMaxHistory = 500
+
+// errors
+var (
+	ErrBaseLengthMismatch = errors.New("operation base length does not match document length")
+	ErrInvalidComponent   = errors.New("invalid operation component")
+	ErrRevisionTooOld     = errors.New("revision is no longer in history")
+	ErrRevisionInFuture   = errors.New("revision is ahead of the document")
+	ErrIncompatibleOps    = errors.New("operations are not based on the same document")
+)
+
+// a single step of an operation. exactly one field is set.
+type Component struct {
+	Retain int
+	Insert string
+	Delete int
+}
+
+// a sequence of components that transforms a document of BaseLength()
+// units into a document of TargetLength() units
+type Operation []Component
+
+// a text document with a linear revision history.
+// not safe for concurrent use; callers must synchronize access.
+type Document struct {
+	text     []uint16
+	revision int
+
+	// operations applied since historyBase, oldest first
+	history     []Operation
+	historyBase int
+	maxHistory  int
+}
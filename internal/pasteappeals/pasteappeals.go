@@ -0,0 +1,108 @@
+package pasteappeals
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func New(db *pgxpool.Pool) *Service {
+	return &Service{db: db}
+}
+
+// records an appeal. fails with ErrAppealPending while the user has an
+// appeal for the session waiting for review
+func (s *Service) Create(ctx context.Context, req CreateAppeal) (*Appeal, error) {
+	appeal, err := scanAppeal(s.db.QueryRow(ctx, queryCreate,
+		req.SessionID,
+		req.UserID,
+		req.Message,
+		req.ClaimsOwnership,
+		req.LockReason,
+		req.MatchedWorkID,
+		req.CCSignal,
+		req.Verified,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAppealPending
+	}
+
+	return appeal, err
+}
+
+// gets the user's latest appeal for a session, nil when there is none
+func (s *Service) Latest(ctx context.Context, sessionID, userID string) (*Appeal, error) {
+	appeal, err := scanAppeal(s.db.QueryRow(ctx, queryLatest, sessionID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return appeal, err
+}
+
+// lists appeals, newest first. an empty status matches every appeal
+func (s *Service) List(ctx context.Context, status string, limit, offset int) ([]*Appeal, int, error) {
+	var total int
+	if err := s.db.QueryRow(ctx, queryCount, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(ctx, queryList, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	appeals := []*Appeal{}
+
+	for rows.Next() {
+		appeal, err := scanAppeal(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		appeals = append(appeals, appeal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return appeals, total, nil
+}
+
+// closes a pending appeal as approved or rejected
+func (s *Service) Resolve(ctx context.Context, appealID, reviewerID, status string) (*Appeal, error) {
+	appeal, err := scanAppeal(s.db.QueryRow(ctx, queryResolve, appealID, status, reviewerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAppealNotFound
+	}
+
+	return appeal, err
+}
+
+func scanAppeal(row pgx.Row) (*Appeal, error) {
+	var appeal Appeal
+	err := row.Scan(
+		&appeal.ID,
+		&appeal.SessionID,
+		&appeal.UserID,
+		&appeal.Message,
+		&appeal.ClaimsOwnership,
+		&appeal.LockReason,
+		&appeal.MatchedWorkID,
+		&appeal.CCSignal,
+		&appeal.Status,
+		&appeal.Verified,
+		&appeal.ReviewedBy,
+		&appeal.ReviewedAt,
+		&appeal.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &appeal, nil
+}
@@ -0,0 +1,43 @@
+package pasteappeals
+
+const (
+	appealColumns = `id, session_id, user_id, message, claims_ownership, lock_reason, matched_work_id,
+		cc_signal, status, verified, reviewed_by, reviewed_at, created_at`
+
+	// verified appeals are approved on creation. a user can only have one
+	// pending appeal per session
+	queryCreate = `
+		INSERT INTO paste_lock_appeals (session_id, user_id, message, claims_ownership, lock_reason, matched_work_id, cc_signal, status, verified, reviewed_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), NULLIF($6, '')::uuid, NULLIF($7, ''),
+			CASE WHEN $8 THEN 'approved' ELSE 'pending' END, $8, CASE WHEN $8 THEN NOW() END)
+		ON CONFLICT (session_id, user_id) WHERE status = 'pending' DO NOTHING
+		RETURNING ` + appealColumns
+
+	queryLatest = `
+		SELECT ` + appealColumns + `
+		FROM paste_lock_appeals
+		WHERE session_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	queryList = `
+		SELECT ` + appealColumns + `
+		FROM paste_lock_appeals
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	queryCount = `
+		SELECT COUNT(*)
+		FROM paste_lock_appeals
+		WHERE ($1 = '' OR status = $1)
+	`
+
+	queryResolve = `
+		UPDATE paste_lock_appeals
+		SET status = $2, reviewed_by = NULLIF($3, '')::uuid, reviewed_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING ` + appealColumns
+)
@@ -0,0 +1,55 @@
+package pasteappeals
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// appeal statuses
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// errors
+var (
+	ErrAppealNotFound = errors.New("paste lock appeal not found or already resolved")
+	ErrAppealPending  = errors.New("an appeal for this session is already waiting for review")
+)
+
+// stores appeals against paste locks
+type Service struct {
+	db *pgxpool.Pool
+}
+
+// an appeal against a session's paste lock
+type Appeal struct {
+	ID              string     `json:"id"`
+	SessionID       string     `json:"session_id"`
+	UserID          string     `json:"user_id"`
+	Message         *string    `json:"message,omitempty"`
+	ClaimsOwnership bool       `json:"claims_ownership"`
+	LockReason      *string    `json:"lock_reason,omitempty"`
+	MatchedWorkID   *string    `json:"matched_work_id,omitempty"`
+	CCSignal        *string    `json:"cc_signal,omitempty"`
+	Status          string     `json:"status"`
+	Verified        bool       `json:"verified"` // ownership verified, the lock was lifted without review
+	ReviewedBy      *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// contains data for appealing a paste lock
+type CreateAppeal struct {
+	SessionID       string
+	UserID          string
+	Message         string // optional note for the reviewer
+	ClaimsOwnership bool
+	LockReason      string // from the lock decision, if any
+	MatchedWorkID   string
+	CCSignal        string
+	Verified        bool // stored as approved instead of pending
+}
@@ -0,0 +1,319 @@
+package presence
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// creates a registry for this server instance
+func NewRegistry(client redis.UniversalClient) (*Registry, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate instance id: %w", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "server"
+	}
+
+	return &Registry{
+		client:     client,
+		instanceID: hostname + "-" + hex.EncodeToString(suffix),
+		ops:        make(chan op, opQueueSize),
+		stopCh:     make(chan struct{}),
+		local:      make(map[string]Connection),
+	}, nil
+}
+
+// returns the ID this instance writes its connections under
+func (r *Registry) InstanceID() string {
+	return r.instanceID
+}
+
+// begins the background heartbeat and write loop
+func (r *Registry) Start() {
+	r.wg.Add(1)
+	go r.run()
+	logger.Info("presence registry started", "instance_id", r.instanceID)
+}
+
+// stops the heartbeat and removes this instance's connections from the registry
+func (r *Registry) Stop() {
+	close(r.stopCh)
+	r.wg.Wait()
+	logger.Info("presence registry stopped", "instance_id", r.instanceID)
+}
+
+// records a connection held by this instance. never blocks; the write is
+// applied in order by the background loop.
+func (r *Registry) Track(conn Connection) {
+	conn.InstanceID = r.instanceID
+
+	r.mu.Lock()
+	r.local[conn.ClientID] = conn
+	r.mu.Unlock()
+
+	r.enqueue(op{conn: conn})
+}
+
+// removes a connection previously recorded with Track
+func (r *Registry) Untrack(clientID string) {
+	r.mu.Lock()
+	conn, exists := r.local[clientID]
+	delete(r.local, clientID)
+	r.mu.Unlock()
+
+	if exists {
+		r.enqueue(op{conn: conn, removed: true})
+	}
+}
+
+// returns the live connections to a session held by other instances
+func (r *Registry) RemoteSessionConnections(ctx context.Context, sessionID string) ([]Connection, error) {
+	key := fmt.Sprintf(keySession, sessionID)
+
+	entries, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string]string, len(entries))
+	conns := make(map[string]Connection, len(entries))
+
+	for clientID, data := range entries {
+		var conn Connection
+		if err := json.Unmarshal([]byte(data), &conn); err != nil {
+			owners[clientID] = ""
+			continue
+		}
+
+		owners[clientID] = conn.InstanceID
+		conns[clientID] = conn
+	}
+
+	live, err := r.liveRemoteClients(ctx, key, owners)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Connection, 0, len(live))
+	for _, clientID := range live {
+		result = append(result, conns[clientID])
+	}
+
+	return result, nil
+}
+
+// returns how many live connections other instances hold for a user
+func (r *Registry) RemoteUserConnectionCount(ctx context.Context, userID string) (int, error) {
+	return r.countRemote(ctx, fmt.Sprintf(keyUser, userID))
+}
+
+// returns how many live connections other instances hold for an IP address
+func (r *Registry) RemoteIPConnectionCount(ctx context.Context, ipAddress string) (int, error) {
+	return r.countRemote(ctx, fmt.Sprintf(keyIP, ipAddress))
+}
+
+func (r *Registry) countRemote(ctx context.Context, key string) (int, error) {
+	owners, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	live, err := r.liveRemoteClients(ctx, key, owners)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(live), nil
+}
+
+// filters client ID -> instance ID entries down to clients of other live
+// instances, deleting entries whose instance no longer sends heartbeats
+func (r *Registry) liveRemoteClients(ctx context.Context, key string, owners map[string]string) ([]string, error) {
+	instances := make(map[string]*redis.IntCmd)
+	pipe := r.client.Pipeline()
+
+	for _, instanceID := range owners {
+		if instanceID == "" || instanceID == r.instanceID {
+			continue
+		}
+
+		if _, seen := instances[instanceID]; !seen {
+			instances[instanceID] = pipe.Exists(ctx, fmt.Sprintf(keyInstance, instanceID))
+		}
+	}
+
+	if len(instances) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var live, dead []string
+
+	for clientID, instanceID := range owners {
+		if instanceID == r.instanceID {
+			continue
+		}
+
+		if cmd, ok := instances[instanceID]; ok && cmd.Val() > 0 {
+			live = append(live, clientID)
+		} else {
+			dead = append(dead, clientID)
+		}
+	}
+
+	if len(dead) > 0 {
+		if err := r.client.HDel(ctx, key, dead...).Err(); err != nil {
+			logger.Warn("failed to remove stale presence entries",
+				"key", key,
+				"count", len(dead),
+				"error", err,
+			)
+		}
+	}
+
+	return live, nil
+}
+
+func (r *Registry) enqueue(o op) {
+	select {
+	case r.ops <- o:
+	default:
+		// the next heartbeat reconciles the registry with the local map
+		logger.Warn("presence write queue full, deferring to heartbeat",
+			"client_id", o.conn.ClientID,
+			"session_id", o.conn.SessionID,
+		)
+	}
+}
+
+func (r *Registry) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+
+	r.heartbeat()
+
+	for {
+		select {
+		case o := <-r.ops:
+			r.apply(o)
+		case <-ticker.C:
+			r.heartbeat()
+		case <-r.stopCh:
+			r.removeAll()
+			return
+		}
+	}
+}
+
+// writes a single register/unregister
+func (r *Registry) apply(o op) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	pipe := r.client.Pipeline()
+
+	if o.removed {
+		deleteConnection(ctx, pipe, o.conn)
+	} else if err := writeConnection(ctx, pipe, o.conn); err != nil {
+		logger.ErrorErr(err, "failed to encode presence entry", "client_id", o.conn.ClientID)
+		return
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Warn("failed to write presence entry",
+			"client_id", o.conn.ClientID,
+			"session_id", o.conn.SessionID,
+			"error", err,
+		)
+	}
+}
+
+// refreshes the instance heartbeat, re-asserts local connections and removes
+// entries this instance no longer holds (e.g., after a dropped write)
+func (r *Registry) heartbeat() {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	r.mu.Lock()
+	local := maps.Clone(r.local)
+	r.mu.Unlock()
+
+	recorded, err := r.client.HGetAll(ctx, fmt.Sprintf(keyInstanceClients, r.instanceID)).Result()
+	if err != nil {
+		logger.Warn("failed to read presence entries", "instance_id", r.instanceID, "error", err)
+		recorded = nil
+	}
+
+	pipe := r.client.Pipeline()
+	pipe.Set(ctx, fmt.Sprintf(keyInstance, r.instanceID), "1", InstanceTTL)
+
+	for clientID, data := range recorded {
+		if _, held := local[clientID]; held {
+			continue
+		}
+
+		var conn Connection
+		if err := json.Unmarshal([]byte(data), &conn); err != nil {
+			pipe.HDel(ctx, fmt.Sprintf(keyInstanceClients, r.instanceID), clientID)
+			continue
+		}
+
+		deleteConnection(ctx, pipe, conn)
+	}
+
+	for _, conn := range local {
+		if err := writeConnection(ctx, pipe, conn); err != nil {
+			logger.ErrorErr(err, "failed to encode presence entry", "client_id", conn.ClientID)
+		}
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Warn("presence heartbeat failed", "instance_id", r.instanceID, "error", err)
+	}
+}
+
+// removes every connection this instance recorded, including ones whose
+// unregister has not been applied yet
+func (r *Registry) removeAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	instanceClientsKey := fmt.Sprintf(keyInstanceClients, r.instanceID)
+
+	recorded, err := r.client.HGetAll(ctx, instanceClientsKey).Result()
+	if err != nil {
+		logger.Warn("failed to read presence entries", "instance_id", r.instanceID, "error", err)
+	}
+
+	pipe := r.client.Pipeline()
+
+	for _, data := range recorded {
+		var conn Connection
+		if err := json.Unmarshal([]byte(data), &conn); err == nil {
+			deleteConnection(ctx, pipe, conn)
+		}
+	}
+
+	pipe.Del(ctx, instanceClientsKey)
+	pipe.Del(ctx, fmt.Sprintf(keyInstance, r.instanceID))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Warn("failed to remove presence entries", "instance_id", r.instanceID, "error", err)
+	}
+}
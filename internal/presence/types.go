@@ -0,0 +1,72 @@
+package presence
+
+import (
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// a websocket connection as seen by every server instance
+type Connection struct {
+	ClientID    string `json:"client_id"`
+	SessionID   string `json:"session_id"`
+	UserID      string `json:"user_id,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	Role        string `json:"role"`
+	IPAddress   string `json:"ip_address,omitempty"`
+	InstanceID  string `json:"instance_id"`
+}
+
+// redis key patterns
+const (
+	// presence:instance:{instanceID} - heartbeat key, expires when the instance stops refreshing it
+	keyInstance = "presence:instance:%s"
+
+	// presence:instance:{instanceID}:clients - set of JSON connections held by the instance
+	keyInstanceClients = "presence:instance:%s:clients"
+
+	// presence:session:{sessionID} - hash of client ID -> JSON connection
+	keySession = "presence:session:%s"
+
+	// presence:user:{userID} - hash of client ID -> instance ID
+	keyUser = "presence:user:%s"
+
+	// presence:ip:{ip} - hash of client ID -> instance ID
+	keyIP = "presence:ip:%s"
+)
+
+const (
+	// how often an instance refreshes its heartbeat and re-asserts its connections
+	HeartbeatInterval = 10 * time.Second
+
+	// an instance that misses heartbeats for this long is considered dead
+	InstanceTTL = 30 * time.Second
+
+	// pending register/unregister writes; when full, writes wait for the next heartbeat
+	opQueueSize = 1024
+
+	// maximum time a single redis write batch may take
+	writeTimeout = 5 * time.Second
+)
+
+// a pending write to the shared registry
+type op struct {
+	conn    Connection
+	removed bool
+}
+
+// shares websocket presence between server instances through Redis.
+// each instance only writes its own connections; entries of instances whose
+// heartbeat expired are ignored and deleted lazily on read.
+type Registry struct {
+	client     redis.UniversalClient
+	instanceID string
+	ops        chan op
+	stopCh     chan struct{}
+	wg         sync.WaitGroup
+
+	// connections held by this instance, re-asserted on every heartbeat
+	mu    sync.Mutex
+	local map[string]Connection
+}
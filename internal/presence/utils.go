@@ -0,0 +1,54 @@
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// queues the writes recording a connection. every key expires with the
+// instance TTL so abandoned keys disappear once no instance refreshes them.
+func writeConnection(ctx context.Context, pipe redis.Pipeliner, conn Connection) error {
+	data, err := json.Marshal(conn)
+	if err != nil {
+		return err
+	}
+
+	instanceClientsKey := fmt.Sprintf(keyInstanceClients, conn.InstanceID)
+	pipe.HSet(ctx, instanceClientsKey, conn.ClientID, data)
+	pipe.Expire(ctx, instanceClientsKey, InstanceTTL)
+
+	sessionKey := fmt.Sprintf(keySession, conn.SessionID)
+	pipe.HSet(ctx, sessionKey, conn.ClientID, data)
+	pipe.Expire(ctx, sessionKey, InstanceTTL)
+
+	if conn.UserID != "" {
+		userKey := fmt.Sprintf(keyUser, conn.UserID)
+		pipe.HSet(ctx, userKey, conn.ClientID, conn.InstanceID)
+		pipe.Expire(ctx, userKey, InstanceTTL)
+	}
+
+	if conn.IPAddress != "" {
+		ipKey := fmt.Sprintf(keyIP, conn.IPAddress)
+		pipe.HSet(ctx, ipKey, conn.ClientID, conn.InstanceID)
+		pipe.Expire(ctx, ipKey, InstanceTTL)
+	}
+
+	return nil
+}
+
+// queues the writes removing a connection
+func deleteConnection(ctx context.Context, pipe redis.Pipeliner, conn Connection) {
+	pipe.HDel(ctx, fmt.Sprintf(keyInstanceClients, conn.InstanceID), conn.ClientID)
+	pipe.HDel(ctx, fmt.Sprintf(keySession, conn.SessionID), conn.ClientID)
+
+	if conn.UserID != "" {
+		pipe.HDel(ctx, fmt.Sprintf(keyUser, conn.UserID), conn.ClientID)
+	}
+
+	if conn.IPAddress != "" {
+		pipe.HDel(ctx, fmt.Sprintf(keyIP, conn.IPAddress), conn.ClientID)
+	}
+}
@@ -0,0 +1,69 @@
+package quota
+
+const (
+	queryGetUserTier = `
+		SELECT COALESCE(tier, 'free') FROM users WHERE id = $1
+	`
+
+	queryCountUserUsage = `
+		SELECT COUNT(*)::BIGINT
+		FROM usage_logs
+		WHERE user_id = $1
+		AND is_byok = $2
+		AND created_at >= $3
+	`
+
+	queryCountSessionUsage = `
+		SELECT COUNT(*)::BIGINT
+		FROM usage_logs
+		WHERE session_id = $1
+		AND user_id IS NULL
+		AND is_byok = $2
+		AND created_at >= $3
+	`
+
+	// in micro-USD, like the spend counters
+	querySumUserSpend = `
+		SELECT ROUND(COALESCE(SUM(estimated_cost_usd), 0) * 1000000)::BIGINT
+		FROM usage_logs
+		WHERE user_id = $1
+		AND is_byok = $2
+		AND created_at >= $3
+	`
+
+	querySumSessionSpend = `
+		SELECT ROUND(COALESCE(SUM(estimated_cost_usd), 0) * 1000000)::BIGINT
+		FROM usage_logs
+		WHERE session_id = $1
+		AND user_id IS NULL
+		AND is_byok = $2
+		AND created_at >= $3
+	`
+
+	queryLogUsage = `
+		INSERT INTO usage_logs (user_id, session_id, provider, model, input_tokens, output_tokens, estimated_cost_usd, is_byok)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	// tokens of generations logged without a cost are returned separately so
+	// they can be priced now
+	queryMonthlyUsage = `
+		SELECT
+			provider,
+			model,
+			COUNT(*)::INT,
+			(COUNT(*) FILTER (WHERE is_byok))::INT,
+			COALESCE(SUM(input_tokens), 0)::BIGINT,
+			COALESCE(SUM(output_tokens), 0)::BIGINT,
+			COALESCE(SUM(estimated_cost_usd), 0)::FLOAT8,
+			(COUNT(*) FILTER (WHERE estimated_cost_usd IS NULL))::INT,
+			COALESCE(SUM(input_tokens) FILTER (WHERE estimated_cost_usd IS NULL), 0)::BIGINT,
+			COALESCE(SUM(output_tokens) FILTER (WHERE estimated_cost_usd IS NULL), 0)::BIGINT
+		FROM usage_logs
+		WHERE user_id = $1
+		AND created_at >= $2
+		AND created_at < $3
+		GROUP BY provider, model
+		ORDER BY COUNT(*) DESC, provider, model
+	`
+)
@@ -0,0 +1,317 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// takes one generation from both counters if neither they nor the spend is
+// at its limit. spend is only added once the generation's cost is known.
+// KEYS: daily, monthly, spend. ARGV: daily limit, monthly limit, spend limit
+// in micro-USD (-1 = unlimited), daily expiry, monthly expiry (unix seconds).
+// returns {allowed (0|1), daily count, monthly count, spend}
+var reserveScript = redis.NewScript(`
+local daily = tonumber(redis.call('GET', KEYS[1]) or '0')
+local monthly = tonumber(redis.call('GET', KEYS[2]) or '0')
+local spend = tonumber(redis.call('GET', KEYS[3]) or '0')
+local dailyLimit = tonumber(ARGV[1])
+local monthlyLimit = tonumber(ARGV[2])
+local spendLimit = tonumber(ARGV[3])
+
+if (dailyLimit >= 0 and daily >= dailyLimit) or (monthlyLimit >= 0 and monthly >= monthlyLimit) or (spendLimit >= 0 and spend >= spendLimit) then
+	return {0, daily, monthly, spend}
+end
+
+daily = redis.call('INCR', KEYS[1])
+monthly = redis.call('INCR', KEYS[2])
+redis.call('EXPIREAT', KEYS[1], ARGV[4])
+redis.call('EXPIREAT', KEYS[2], ARGV[5])
+
+return {1, daily, monthly, spend}
+`)
+
+// adds to a spend counter that exists. a missing counter is left to be
+// seeded from usage_logs, which already holds the cost being added.
+// KEYS: spend. ARGV: micro-USD
+var addSpendScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('INCRBY', KEYS[1], ARGV[1])
+end
+
+return 0
+`)
+
+// creates a new quota service with the default tier limits
+func New(db *pgxpool.Pool, redisClient redis.UniversalClient) *Service {
+	return &Service{
+		db:     db,
+		redis:  redisClient,
+		limits: DefaultLimits,
+	}
+}
+
+// counts a generation against the subject's quota if it has room left.
+// call Release if the generation then fails, and Record once it succeeds.
+// returns nil when the request can't be attributed to a user or session.
+func (s *Service) Reserve(ctx context.Context, subject Subject) (*Result, error) {
+	identity := identityOf(subject)
+	if identity == "" {
+		return nil, nil
+	}
+
+	tier, limits, err := s.tierOf(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	p := periodsAt(time.Now().UTC())
+	keys := counterKeys(identity, subject.BYOK, p)
+
+	if err := s.seedAll(ctx, keys, subject, p); err != nil {
+		return nil, err
+	}
+
+	values, err := reserveScript.Run(ctx, s.redis, []string{keys.daily, keys.monthly, keys.spend},
+		limits.Daily, limits.Monthly, spendLimitMicros(limits.MonthlySpendUSD), p.dayEnd.Unix(), p.monthEnd.Unix(),
+	).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve quota: %w", err)
+	}
+
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected quota script result: %v", values)
+	}
+
+	return &Result{
+		Allowed: values[0] == 1,
+		Tier:    tier,
+		Daily:   newPeriodUsage(int(values[1]), limits.Daily, p.dayEnd),
+		Monthly: newPeriodUsage(int(values[2]), limits.Monthly, p.monthEnd),
+		Spend:   newSpendUsage(values[3], limits.MonthlySpendUSD, p.monthEnd),
+	}, nil
+}
+
+// returns the subject's quota without counting a generation. Allowed reports
+// whether another generation would be
+func (s *Service) Status(ctx context.Context, subject Subject) (*Result, error) {
+	identity := identityOf(subject)
+	if identity == "" {
+		return nil, nil
+	}
+
+	tier, limits, err := s.tierOf(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	p := periodsAt(time.Now().UTC())
+	keys := counterKeys(identity, subject.BYOK, p)
+
+	if err := s.seedAll(ctx, keys, subject, p); err != nil {
+		return nil, err
+	}
+
+	values, err := s.redis.MGet(ctx, keys.daily, keys.monthly, keys.spend).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota counters: %w", err)
+	}
+
+	counts := make([]int64, len(values))
+	for i, value := range values {
+		if str, ok := value.(string); ok {
+			counts[i], _ = strconv.ParseInt(str, 10, 64)
+		}
+	}
+
+	result := &Result{
+		Tier:    tier,
+		Daily:   newPeriodUsage(int(counts[0]), limits.Daily, p.dayEnd),
+		Monthly: newPeriodUsage(int(counts[1]), limits.Monthly, p.monthEnd),
+		Spend:   newSpendUsage(counts[2], limits.MonthlySpendUSD, p.monthEnd),
+	}
+	result.Allowed = result.Daily.Remaining != 0 && result.Monthly.Remaining != 0 && result.Spend.RemainingUSD != 0
+
+	return result, nil
+}
+
+// gives back a reserved generation that did not complete
+func (s *Service) Release(ctx context.Context, subject Subject) {
+	identity := identityOf(subject)
+	if identity == "" {
+		return
+	}
+
+	keys := counterKeys(identity, subject.BYOK, periodsAt(time.Now().UTC()))
+
+	pipe := s.redis.Pipeline()
+	pipe.Decr(ctx, keys.daily)
+	pipe.Decr(ctx, keys.monthly)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Warn("failed to release quota", "error", err, "identity", identity)
+	}
+}
+
+// stores a completed generation in usage_logs with its estimated cost, and
+// adds the cost to the subject's monthly spend
+func (s *Service) Record(ctx context.Context, subject Subject, usage Usage) {
+	var userID *string
+	if subject.UserID != "" {
+		userID = &subject.UserID
+	}
+
+	var sessionID *string
+	if subject.SessionID != "" {
+		sessionID = &subject.SessionID
+	}
+
+	provider := usage.Provider
+	if provider == "" {
+		provider = "platform"
+	}
+
+	// unpriced models are stored without a cost so they can be priced later
+	var cost *float64
+	if estimate, known := llm.EstimateCost(provider, usage.Model, int64(usage.InputTokens), int64(usage.OutputTokens)); known {
+		cost = &estimate
+	}
+
+	_, err := s.db.Exec(ctx, queryLogUsage,
+		userID,
+		sessionID,
+		provider,
+		usage.Model,
+		usage.InputTokens,
+		usage.OutputTokens,
+		cost,
+		subject.BYOK,
+	)
+	if err != nil {
+		logger.Warn("failed to record usage", "error", err, "user_id", subject.UserID, "session_id", subject.SessionID)
+		return
+	}
+
+	identity := identityOf(subject)
+	if identity == "" || cost == nil || *cost <= 0 {
+		return
+	}
+
+	keys := counterKeys(identity, subject.BYOK, periodsAt(time.Now().UTC()))
+	if err := addSpendScript.Run(ctx, s.redis, []string{keys.spend}, toMicros(*cost)).Err(); err != nil {
+		logger.Warn("failed to add usage spend", "error", err, "identity", identity)
+	}
+}
+
+// returns a user's usage per provider and model in the calendar month
+// (UTC) containing month
+func (s *Service) MonthlyUsage(ctx context.Context, userID string, month time.Time) (*MonthlyUsage, error) {
+	p := periodsAt(month)
+
+	rows, err := s.db.Query(ctx, queryMonthlyUsage, userID, p.monthStart, p.monthEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query monthly usage: %w", err)
+	}
+
+	defer rows.Close()
+	usage := MonthlyUsage{Month: p.monthStart.Format("2006-01"), Models: []ModelUsage{}}
+
+	for rows.Next() {
+		var m ModelUsage
+		var unpriced int
+		var unpricedInput, unpricedOutput int64
+
+		err := rows.Scan(
+			&m.Provider,
+			&m.Model,
+			&m.Generations,
+			&m.BYOKGenerations,
+			&m.InputTokens,
+			&m.OutputTokens,
+			&m.EstimatedCostUSD,
+			&unpriced,
+			&unpricedInput,
+			&unpricedOutput,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		m.PricingKnown = true
+		if unpriced > 0 {
+			cost, known := llm.EstimateCost(m.Provider, m.Model, unpricedInput, unpricedOutput)
+			m.EstimatedCostUSD += cost
+			m.PricingKnown = known
+		}
+
+		usage.Generations += m.Generations
+		usage.BYOKGenerations += m.BYOKGenerations
+		usage.InputTokens += m.InputTokens
+		usage.OutputTokens += m.OutputTokens
+		usage.EstimatedCostUSD += m.EstimatedCostUSD
+		usage.Models = append(usage.Models, m)
+	}
+
+	return &usage, rows.Err()
+}
+
+// looks up the tier a subject is counted against and its limits
+func (s *Service) tierOf(ctx context.Context, subject Subject) (string, Limits, error) {
+	storedTier := ""
+	if subject.UserID != "" && !subject.BYOK {
+		if err := s.db.QueryRow(ctx, queryGetUserTier, subject.UserID).Scan(&storedTier); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return "", Limits{}, fmt.Errorf("failed to get user tier: %w", err)
+		}
+	}
+
+	tier := tierFor(subject, storedTier)
+	return tier, s.limits[tier], nil
+}
+
+func (s *Service) seedAll(ctx context.Context, keys counters, subject Subject, p periods) error {
+	if err := s.seed(ctx, keys.daily, subject, queryCountUserUsage, queryCountSessionUsage, p.dayStart, p.dayEnd); err != nil {
+		return err
+	}
+	if err := s.seed(ctx, keys.monthly, subject, queryCountUserUsage, queryCountSessionUsage, p.monthStart, p.monthEnd); err != nil {
+		return err
+	}
+
+	return s.seed(ctx, keys.spend, subject, querySumUserSpend, querySumSessionSpend, p.monthStart, p.monthEnd)
+}
+
+// rebuilds a missing counter from usage_logs (after a Redis restart or eviction)
+func (s *Service) seed(ctx context.Context, key string, subject Subject, userQuery, sessionQuery string, since, expireAt time.Time) error {
+	exists, err := s.redis.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check quota counter: %w", err)
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	var value int64
+	if subject.UserID != "" {
+		err = s.db.QueryRow(ctx, userQuery, subject.UserID, subject.BYOK, since).Scan(&value)
+	} else {
+		err = s.db.QueryRow(ctx, sessionQuery, subject.SessionID, subject.BYOK, since).Scan(&value)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to count usage: %w", err)
+	}
+
+	// NX: another instance may have seeded (and counted) in the meantime
+	if err := s.redis.SetNX(ctx, key, value, time.Until(expireAt)).Err(); err != nil {
+		return fmt.Errorf("failed to seed quota counter: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// tiers generations are counted against
+const (
+	TierAnonymous = "anonymous" // not signed in, counted per session
+	TierFree      = "free"
+	TierPAYG      = "payg"
+	TierBYOK      = "byok" // requests made with the caller's own API key
+)
+
+// Unlimited marks a limit (and its remaining count) that is not enforced
+const Unlimited = -1
+
+// generations allowed per period, and the estimated model cost in USD
+// allowed per month
+type Limits struct {
+	Daily           int
+	Monthly         int
+	MonthlySpendUSD float64
+}
+
+// per-tier limits. BYOK requests are not free for the platform
+// (retrieval and embeddings still run on our keys), so they are capped too,
+// but their model cost is paid by the caller and not capped.
+// the spend caps keep long generations on expensive models from costing
+// more than the generation counts suggest
+var DefaultLimits = map[string]Limits{
+	TierAnonymous: {Daily: 4, Monthly: 20, MonthlySpendUSD: 0.5},
+	TierFree:      {Daily: 4, Monthly: 60, MonthlySpendUSD: 1.5},
+	TierPAYG:      {Daily: 1000, Monthly: 20000, MonthlySpendUSD: 100},
+	TierBYOK:      {Daily: 500, Monthly: 10000, MonthlySpendUSD: Unlimited},
+}
+
+// redis keys, by identity ("user:<id>" | "session:<id>"), pool and period.
+// the braces keep a subject's counters in one Redis Cluster slot, so the
+// reserve script can touch all three
+const (
+	keyDaily   = "quota:{%s:%s}:day:%s"   // period "2006-01-02"
+	keyMonthly = "quota:{%s:%s}:month:%s" // period "2006-01"
+	keySpend   = "quota:{%s:%s}:spend:%s" // period "2006-01", in micro-USD
+)
+
+// spend counters hold whole micro-USD so redis can add them atomically
+const microUSD = 1_000_000
+
+// counters for requests on the platform key and on the caller's own key are kept apart
+const (
+	poolPlatform = "platform"
+	poolBYOK     = "byok"
+)
+
+// who a generation is counted against
+type Subject struct {
+	UserID    string // empty for anonymous requests
+	SessionID string // counts anonymous requests
+	BYOK      bool   // the request uses the caller's own API key
+}
+
+// usage of a finished generation, stored in usage_logs
+type Usage struct {
+	Provider     string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+}
+
+// counts for one period
+type PeriodUsage struct {
+	Used      int       `json:"used"`
+	Limit     int       `json:"limit"`     // Unlimited (-1) when not enforced
+	Remaining int       `json:"remaining"` // Unlimited (-1) when not enforced
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// estimated model cost for the current month
+type SpendUsage struct {
+	UsedUSD      float64   `json:"used_usd"`
+	LimitUSD     float64   `json:"limit_usd"`     // Unlimited (-1) when not enforced
+	RemainingUSD float64   `json:"remaining_usd"` // Unlimited (-1) when not enforced
+	ResetAt      time.Time `json:"reset_at"`
+}
+
+// outcome of reserving a generation
+type Result struct {
+	Allowed bool        `json:"allowed"`
+	Tier    string      `json:"tier"`
+	Daily   PeriodUsage `json:"daily"`
+	Monthly PeriodUsage `json:"monthly"`
+	Spend   SpendUsage  `json:"spend"`
+}
+
+// a user's AI usage in one calendar month (UTC), from usage_logs
+type MonthlyUsage struct {
+	Month            string       `json:"month"` // "2006-01"
+	Generations      int          `json:"generations"`
+	BYOKGenerations  int          `json:"byok_generations"` // made with the user's own key
+	InputTokens      int64        `json:"input_tokens"`
+	OutputTokens     int64        `json:"output_tokens"`
+	EstimatedCostUSD float64      `json:"estimated_cost_usd"` // includes BYOK generations, paid by the user
+	Models           []ModelUsage `json:"models"`
+}
+
+// usage of one provider and model within a month
+type ModelUsage struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Generations      int     `json:"generations"`
+	BYOKGenerations  int     `json:"byok_generations"`
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	PricingKnown     bool    `json:"pricing_known"` // false when some generations have no price, they count as 0
+}
+
+// tracks and enforces AI generation quotas.
+// redis holds the counters checked on every request; usage_logs in Postgres
+// is the durable record the counters are rebuilt from when missing.
+type Service struct {
+	db     *pgxpool.Pool
+	redis  redis.UniversalClient
+	limits map[string]Limits
+}
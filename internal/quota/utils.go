@@ -0,0 +1,113 @@
+package quota
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// picks the tier a request is counted against
+func tierFor(subject Subject, storedTier string) string {
+	switch {
+	case subject.BYOK:
+		return TierBYOK
+	case subject.UserID == "":
+		return TierAnonymous
+	case storedTier == TierPAYG:
+		return TierPAYG
+	default:
+		// includes users on the byok plan making a request without a key
+		return TierFree
+	}
+}
+
+// the redis identity a subject's counters are stored under
+func identityOf(subject Subject) string {
+	switch {
+	case subject.UserID != "":
+		return "user:" + subject.UserID
+	case subject.SessionID != "":
+		return "session:" + subject.SessionID
+	default:
+		return ""
+	}
+}
+
+// redis keys of a subject's counters for one pool and period
+type counters struct {
+	daily, monthly, spend string
+}
+
+func counterKeys(identity string, byok bool, p periods) counters {
+	pool := poolPlatform
+	if byok {
+		pool = poolBYOK
+	}
+
+	month := p.monthStart.Format("2006-01")
+
+	return counters{
+		daily:   fmt.Sprintf(keyDaily, identity, pool, p.dayStart.Format("2006-01-02")),
+		monthly: fmt.Sprintf(keyMonthly, identity, pool, month),
+		spend:   fmt.Sprintf(keySpend, identity, pool, month),
+	}
+}
+
+// UTC day and month boundaries around a point in time
+type periods struct {
+	dayStart, dayEnd     time.Time
+	monthStart, monthEnd time.Time
+}
+
+func periodsAt(now time.Time) periods {
+	now = now.UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return periods{
+		dayStart:   dayStart,
+		dayEnd:     dayStart.AddDate(0, 0, 1),
+		monthStart: monthStart,
+		monthEnd:   monthStart.AddDate(0, 1, 0),
+	}
+}
+
+func newPeriodUsage(used, limit int, resetAt time.Time) PeriodUsage {
+	if limit == Unlimited {
+		return PeriodUsage{Used: used, Limit: Unlimited, Remaining: Unlimited, ResetAt: resetAt}
+	}
+
+	return PeriodUsage{
+		Used:      used,
+		Limit:     limit,
+		Remaining: max(limit-used, 0),
+		ResetAt:   resetAt,
+	}
+}
+
+func newSpendUsage(usedMicros int64, limitUSD float64, resetAt time.Time) SpendUsage {
+	used := float64(usedMicros) / microUSD
+	if limitUSD == Unlimited {
+		return SpendUsage{UsedUSD: used, LimitUSD: Unlimited, RemainingUSD: Unlimited, ResetAt: resetAt}
+	}
+
+	return SpendUsage{
+		UsedUSD:      used,
+		LimitUSD:     limitUSD,
+		RemainingUSD: max(limitUSD-used, 0),
+		ResetAt:      resetAt,
+	}
+}
+
+func toMicros(usd float64) int64 {
+	return int64(math.Round(usd * microUSD))
+}
+
+// the spend limit as passed to the reserve script
+func spendLimitMicros(limitUSD float64) int64 {
+	if limitUSD == Unlimited {
+		return Unlimited
+	}
+
+	return toMicros(limitUSD)
+}
@@ -0,0 +1,145 @@
+package quota
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTierFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		subject    Subject
+		storedTier string
+		want       string
+	}{
+		{"own key wins over stored tier", Subject{UserID: "u1", BYOK: true}, TierPAYG, TierBYOK},
+		{"anonymous with own key", Subject{SessionID: "s1", BYOK: true}, "", TierBYOK},
+		{"anonymous", Subject{SessionID: "s1"}, "", TierAnonymous},
+		{"free user", Subject{UserID: "u1"}, TierFree, TierFree},
+		{"payg user", Subject{UserID: "u1"}, TierPAYG, TierPAYG},
+		{"byok plan without a key", Subject{UserID: "u1"}, TierBYOK, TierFree},
+		{"unknown tier", Subject{UserID: "u1"}, "", TierFree},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tierFor(tt.subject, tt.storedTier); got != tt.want {
+				t.Errorf("tierFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentityOf(t *testing.T) {
+	tests := []struct {
+		subject Subject
+		want    string
+	}{
+		{Subject{UserID: "u1", SessionID: "s1"}, "user:u1"},
+		{Subject{SessionID: "s1"}, "session:s1"},
+		{Subject{BYOK: true}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := identityOf(tt.subject); got != tt.want {
+			t.Errorf("identityOf(%+v) = %q, want %q", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestCounterKeys(t *testing.T) {
+	p := periodsAt(time.Date(2026, 1, 31, 23, 59, 0, 0, time.UTC))
+
+	keys := counterKeys("user:u1", false, p)
+	if keys.daily != "quota:{user:u1:platform}:day:2026-01-31" {
+		t.Errorf("daily key = %q", keys.daily)
+	}
+	if keys.monthly != "quota:{user:u1:platform}:month:2026-01" {
+		t.Errorf("monthly key = %q", keys.monthly)
+	}
+	if keys.spend != "quota:{user:u1:platform}:spend:2026-01" {
+		t.Errorf("spend key = %q", keys.spend)
+	}
+
+	keys = counterKeys("user:u1", true, p)
+	if keys.daily != "quota:{user:u1:byok}:day:2026-01-31" {
+		t.Errorf("byok daily key = %q", keys.daily)
+	}
+}
+
+func TestPeriodsAt(t *testing.T) {
+	// 01:30 on Dec 31 in UTC+3 is still Dec 30 in UTC
+	now := time.Date(2025, 12, 31, 1, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	p := periodsAt(now)
+
+	if want := time.Date(2025, 12, 30, 0, 0, 0, 0, time.UTC); !p.dayStart.Equal(want) {
+		t.Errorf("dayStart = %v, want %v", p.dayStart, want)
+	}
+	if want := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC); !p.dayEnd.Equal(want) {
+		t.Errorf("dayEnd = %v, want %v", p.dayEnd, want)
+	}
+	if want := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC); !p.monthStart.Equal(want) {
+		t.Errorf("monthStart = %v, want %v", p.monthStart, want)
+	}
+	if want := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC); !p.monthEnd.Equal(want) {
+		t.Errorf("monthEnd = %v, want %v", p.monthEnd, want)
+	}
+}
+
+func TestNewPeriodUsage(t *testing.T) {
+	reset := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		used      int
+		limit     int
+		remaining int
+	}{
+		{"under limit", 3, 10, 7},
+		{"at limit", 10, 10, 0},
+		{"over limit after a concurrent reserve", 11, 10, 0},
+		{"unlimited", 42, Unlimited, Unlimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPeriodUsage(tt.used, tt.limit, reset)
+			if got.Remaining != tt.remaining || got.Used != tt.used || got.Limit != tt.limit {
+				t.Errorf("newPeriodUsage(%d, %d) = %+v, want remaining %d", tt.used, tt.limit, got, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestNewSpendUsage(t *testing.T) {
+	reset := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		used      int64
+		limit     float64
+		remaining float64
+	}{
+		{"under limit", 250_000, 1.5, 1.25},
+		{"over limit after a long generation", 1_600_000, 1.5, 0},
+		{"unlimited", 42_000_000, Unlimited, Unlimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newSpendUsage(tt.used, tt.limit, reset)
+			if got.RemainingUSD != tt.remaining || got.UsedUSD != float64(tt.used)/microUSD {
+				t.Errorf("newSpendUsage(%d, %v) = %+v, want remaining %v", tt.used, tt.limit, got, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestSpendLimitMicros(t *testing.T) {
+	if got := spendLimitMicros(1.5); got != 1_500_000 {
+		t.Errorf("spendLimitMicros(1.5) = %d", got)
+	}
+	if got := spendLimitMicros(Unlimited); got != Unlimited {
+		t.Errorf("spendLimitMicros(Unlimited) = %d", got)
+	}
+}
@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/config"
+)
+
+// a token bucket: Limit requests per Window, refilled continuously
+type Rule struct {
+	// identifies the bucket, so routes sharing a name share a budget
+	Name string
+
+	// bucket capacity (max burst)
+	Limit int
+
+	// time to refill an empty bucket
+	Window time.Duration
+}
+
+// holds rate limiting configuration
+type Config struct {
+	// whether rate limiting is active
+	Enabled bool
+
+	// applied to /api routes without a specific rule
+	Default Rule
+
+	// per-route rules keyed by "<METHOD> <route pattern>"
+	Routes map[string]Rule
+
+	// paths that are never rate limited (prefix match)
+	ExemptPaths []string
+}
+
+// returns sensible defaults
+func DefaultConfig() *Config {
+	generate := Rule{Name: "agent_generate", Limit: 10, Window: time.Minute}
+	strudelWrite := Rule{Name: "strudel_write", Limit: 30, Window: time.Minute}
+	sessionCreate := Rule{Name: "session_create", Limit: 10, Window: time.Minute}
+
+	return &Config{
+		Enabled: true,
+		Default: Rule{Name: "default", Limit: 120, Window: time.Minute},
+		Routes: map[string]Rule{
+			// AI generation (LLM calls are the most expensive requests)
+			"POST /api/v1/agent/generate":        generate,
+			"POST /api/v1/agent/generate/stream": generate,
+
+			// strudel CRUD
+			"POST /api/v1/strudels":       strudelWrite,
+			"PUT /api/v1/strudels/:id":    strudelWrite,
+			"DELETE /api/v1/strudels/:id": strudelWrite,
+
+			// sessions and invites
+			"POST /api/v1/sessions":            sessionCreate,
+			"POST /api/v1/sessions/:id/invite": {Name: "invite_create", Limit: 10, Window: time.Minute},
+			"POST /api/v1/sessions/join":       {Name: "session_join", Limit: 20, Window: time.Minute},
+
+			// webhook test deliveries hit third-party servers
+			"POST /api/v1/webhooks/:id/ping": {Name: "webhook_ping", Limit: 5, Window: time.Minute},
+		},
+		ExemptPaths: []string{
+			"/api/v1/ws",             // websocket messages have their own limits
+			"/api/v1/billing/events", // stripe retries and bursts are expected
+		},
+	}
+}
+
+// returns the defaults with the config file's overrides applied. rules are
+// overridden by name, so routes sharing a budget change together
+func ConfigWithOverrides(overrides config.RateLimitsFile) (*Config, error) {
+	c := DefaultConfig()
+
+	if overrides.Enabled != nil {
+		c.Enabled = *overrides.Enabled
+	}
+
+	known := map[string]bool{c.Default.Name: true}
+	for _, rule := range c.Routes {
+		known[rule.Name] = true
+	}
+
+	for name := range overrides.Rules {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown rate limit rule %q", name)
+		}
+	}
+
+	override := func(rule Rule) (Rule, error) {
+		o, exists := overrides.Rules[rule.Name]
+		if !exists {
+			return rule, nil
+		}
+
+		window, err := time.ParseDuration(o.Window)
+		if err != nil || window <= 0 || o.Limit <= 0 {
+			return rule, fmt.Errorf("invalid rate limit rule %q", rule.Name)
+		}
+
+		return Rule{Name: rule.Name, Limit: o.Limit, Window: window}, nil
+	}
+
+	var err error
+	if c.Default, err = override(c.Default); err != nil {
+		return nil, err
+	}
+
+	routes := maps.Clone(c.Routes)
+	for route, rule := range routes {
+		if routes[route], err = override(rule); err != nil {
+			return nil, err
+		}
+	}
+	c.Routes = routes
+
+	return c, nil
+}
+
+// returns the rule for a matched route, or false if it is not limited
+func (c *Config) RuleFor(method, route string) (Rule, bool) {
+	if route == "" || c.isExemptPath(route) {
+		return Rule{}, false
+	}
+
+	if rule, ok := c.Routes[method+" "+route]; ok {
+		return rule, true
+	}
+
+	if strings.HasPrefix(route, "/api/") {
+		return c.Default, true
+	}
+
+	return Rule{}, false
+}
+
+// checks if a path bypasses rate limiting
+func (c *Config) isExemptPath(path string) bool {
+	for _, ep := range c.ExemptPaths {
+		if path == ep || strings.HasPrefix(path, ep+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// tokens added per second
+func (r Rule) refillRate() float64 {
+	return float64(r.Limit) / r.Window.Seconds()
+}
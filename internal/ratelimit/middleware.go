@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/auth"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// response headers
+const (
+	HeaderLimit      = "X-RateLimit-Limit"
+	HeaderRemaining  = "X-RateLimit-Remaining"
+	HeaderReset      = "X-RateLimit-Reset" // seconds until the bucket is full
+	HeaderRetryAfter = "Retry-After"       // seconds until the next request is allowed
+)
+
+// limits REST requests per user (or per IP for anonymous requests)
+type Limiter struct {
+	config atomic.Pointer[Config]
+	store  *Store
+}
+
+// creates a new rate limiter
+func New(config *Config, store *Store) *Limiter {
+	l := &Limiter{store: store}
+	l.config.Store(config)
+
+	return l
+}
+
+// swaps the rules applied to the next requests. buckets are kept, so a rule
+// keeps its tokens unless its name changes
+func (l *Limiter) SetConfig(config *Config) {
+	l.config.Store(config)
+}
+
+// returns a Gin middleware that enforces the configured rules.
+// registered on the router, so it runs before route-level auth middleware;
+// requests are attributed to a user by validating the bearer token here.
+// fails open when Redis is unavailable.
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		config := l.config.Load()
+		if !config.Enabled {
+			c.Next()
+			return
+		}
+
+		rule, limited := config.RuleFor(c.Request.Method, c.FullPath())
+		if !limited {
+			c.Next()
+			return
+		}
+
+		identity := identify(c)
+
+		result, err := l.store.Take(c.Request.Context(), rule, identity)
+		if err != nil {
+			logger.ErrorErr(err, "failed to check rate limit", "rule", rule.Name, "identity", identity)
+			c.Next()
+			return
+		}
+
+		setHeaders(c, result)
+
+		if !result.Allowed {
+			logger.Warn("rest rate limit exceeded", "rule", rule.Name, "identity", identity, "path", c.FullPath())
+			errors.TooManyRequests(c, "rate limit exceeded, try again in "+strconv.Itoa(ceilSeconds(result.RetryAfter))+"s")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// keys buckets by user ID when a valid token is present, otherwise by IP
+func identify(c *gin.Context) string {
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		if claims, err := auth.ValidateJWT(token); err == nil && claims.UserID != "" {
+			return "user:" + claims.UserID
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+func setHeaders(c *gin.Context, result *Result) {
+	c.Header(HeaderLimit, strconv.Itoa(result.Limit))
+	c.Header(HeaderRemaining, strconv.Itoa(result.Remaining))
+	c.Header(HeaderReset, strconv.Itoa(ceilSeconds(result.Reset)))
+
+	if !result.Allowed {
+		c.Header(HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	}
+}
+
+// rounds up so clients never retry too early
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
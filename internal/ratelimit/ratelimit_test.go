@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"codeberg.org/algopatterns/server/internal/config"
+	"codeberg.org/algopatterns/server/internal/security"
+)
+
+func TestRuleFor(t *testing.T) {
+	config := DefaultConfig()
+
+	tests := []struct {
+		name    string
+		method  string
+		route   string
+		limited bool
+		rule    string
+	}{
+		{"specific rule", "POST", "/api/v1/agent/generate", true, "agent_generate"},
+		{"shared bucket", "POST", "/api/v1/agent/generate/stream", true, "agent_generate"},
+		{"method matters", "GET", "/api/v1/strudels/:id", true, "default"},
+		{"strudel update", "PUT", "/api/v1/strudels/:id", true, "strudel_write"},
+		{"invite creation", "POST", "/api/v1/sessions/:id/invite", true, "invite_create"},
+		{"websocket exempt", "GET", "/api/v1/ws", false, ""},
+		{"non-api route", "GET", "/health", false, ""},
+		{"unmatched route", "GET", "", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, limited := config.RuleFor(tt.method, tt.route)
+			if limited != tt.limited {
+				t.Fatalf("RuleFor(%q, %q) limited = %v, want %v", tt.method, tt.route, limited, tt.limited)
+			}
+			if rule.Name != tt.rule {
+				t.Errorf("RuleFor(%q, %q) rule = %q, want %q", tt.method, tt.route, rule.Name, tt.rule)
+			}
+		})
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	rule := Rule{Name: "test", Limit: 10, Window: 10 * time.Second} // 1 token per second
+
+	tests := []struct {
+		name       string
+		allowed    bool
+		tokens     float64
+		remaining  int
+		reset      time.Duration
+		retryAfter time.Duration
+	}{
+		{"full bucket after one request", true, 9, 9, time.Second, 0},
+		{"fractional tokens round down", true, 2.5, 2, 7500 * time.Millisecond, 0},
+		{"denied waits for one token", false, 0.25, 0, 9750 * time.Millisecond, 750 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newResult(rule, tt.allowed, tt.tokens)
+
+			if result.Remaining != tt.remaining {
+				t.Errorf("Remaining = %d, want %d", result.Remaining, tt.remaining)
+			}
+			if result.Reset != tt.reset {
+				t.Errorf("Reset = %v, want %v", result.Reset, tt.reset)
+			}
+			if result.RetryAfter != tt.retryAfter {
+				t.Errorf("RetryAfter = %v, want %v", result.RetryAfter, tt.retryAfter)
+			}
+		})
+	}
+}
+
+func TestCeilSeconds(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want int
+	}{
+		{0, 0},
+		{time.Millisecond, 1},
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+	}
+
+	for _, tt := range tests {
+		if got := ceilSeconds(tt.in); got != tt.want {
+			t.Errorf("ceilSeconds(%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConfigWithOverrides(t *testing.T) {
+	disabled := false
+
+	c, err := ConfigWithOverrides(config.RateLimitsFile{
+		Enabled: &disabled,
+		Rules: map[string]config.RateLimitRule{
+			"agent_generate": {Limit: 3, Window: "30s"},
+			"default":        {Limit: 60, Window: "1m"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ConfigWithOverrides() error = %v", err)
+	}
+
+	if c.Enabled {
+		t.Error("expected rate limiting to be disabled")
+	}
+
+	// routes sharing a rule change together
+	for _, route := range []string{"/api/v1/agent/generate", "/api/v1/agent/generate/stream"} {
+		rule, _ := c.RuleFor("POST", route)
+		if rule.Limit != 3 || rule.Window != 30*time.Second {
+			t.Errorf("RuleFor(POST, %q) = %+v, want 3 per 30s", route, rule)
+		}
+	}
+
+	if rule, _ := c.RuleFor("GET", "/api/v1/strudels"); rule.Limit != 60 {
+		t.Errorf("default limit = %d, want 60", rule.Limit)
+	}
+
+	if rule, _ := c.RuleFor("PUT", "/api/v1/strudels/:id"); rule.Limit != 30 {
+		t.Errorf("strudel_write limit = %d, want the default 30", rule.Limit)
+	}
+
+	if _, err := ConfigWithOverrides(config.RateLimitsFile{
+		Rules: map[string]config.RateLimitRule{"agent_generat": {Limit: 3, Window: "30s"}},
+	}); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+}
+
+func TestIdentifyIgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		trusted []string
+		peer    string
+		want    string
+	}{
+		{"untrusted peer", nil, "203.0.113.7", "ip:203.0.113.7"},
+		{"peer outside the trusted range", []string{"10.0.0.0/8"}, "203.0.113.7", "ip:203.0.113.7"},
+		{"trusted load balancer", []string{"10.0.0.0/8"}, "10.0.0.2", "ip:198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := security.DefaultConfig()
+			config.TrustedProxies = tt.trusted
+
+			router := gin.New()
+			if err := security.TrustProxies(router, config); err != nil {
+				t.Fatalf("TrustProxies() error = %v", err)
+			}
+
+			var identity string
+			router.GET("/api/v1/strudels", func(c *gin.Context) { identity = identify(c) })
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/strudels", nil)
+			req.RemoteAddr = tt.peer + ":1234"
+			req.Header.Set("X-Forwarded-For", "198.51.100.1")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if identity != tt.want {
+				t.Errorf("identify() = %q, want %q", identity, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyBucket = "ratelimit:%s:%s" // rule name, identity
+
+// refills the bucket for the time elapsed since the last request, then takes a token if one is available.
+// uses the Redis clock so every instance sees the same time.
+// returns {allowed (0|1), tokens left as a string (Lua numbers are truncated to integers otherwise)}
+var takeTokenScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('EXPIRE', KEYS[1], ttl)
+
+return {allowed, tostring(tokens)}
+`)
+
+// holds token buckets in Redis
+type Store struct {
+	client redis.UniversalClient
+}
+
+// creates a new rate limit store
+func NewStore(client redis.UniversalClient) *Store {
+	return &Store{client: client}
+}
+
+// takes a token from the identity's bucket for the rule
+func (s *Store) Take(ctx context.Context, rule Rule, identity string) (*Result, error) {
+	key := fmt.Sprintf(keyBucket, rule.Name, identity)
+
+	// an idle bucket is full again after one window, so it can expire then
+	ttl := int64(rule.Window.Seconds()) + 1
+
+	values, err := takeTokenScript.Run(ctx, s.client, []string{key}, rule.Limit, rule.refillRate(), ttl).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) != 2 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	tokensStr, ok := values[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse remaining tokens: %w", err)
+	}
+
+	return newResult(rule, allowed == 1, tokens), nil
+}
+
+// outcome of taking a token
+type Result struct {
+	Allowed bool
+	Limit   int
+
+	// whole requests left in the bucket
+	Remaining int
+
+	// until the bucket is full again
+	Reset time.Duration
+
+	// until the next request is allowed (0 when allowed)
+	RetryAfter time.Duration
+}
+
+func newResult(rule Rule, allowed bool, tokens float64) *Result {
+	rate := rule.refillRate()
+
+	result := &Result{
+		Allowed:   allowed,
+		Limit:     rule.Limit,
+		Remaining: int(tokens),
+		Reset:     secondsToDuration((float64(rule.Limit) - tokens) / rate),
+	}
+
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+
+	return result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,65 @@
+package renders
+
+const (
+	renderColumns = `
+		id, strudel_id, status, duration_seconds, COALESCE(url, ''), COALESCE(error, ''), created_at, completed_at`
+
+	queryGetStrudelCode = `
+		SELECT code FROM user_strudels
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	// frees the strudel of renders whose job was lost
+	queryAbandonRenders = `
+		UPDATE strudel_renders
+		SET status = 'failed', error = 'render was abandoned', completed_at = NOW()
+		WHERE strudel_id = $1 AND status IN ('queued', 'rendering')
+		  AND created_at < NOW() - make_interval(secs => $2)
+	`
+
+	// nothing is returned while another render of the strudel is in progress
+	queryCreateRender = `
+		INSERT INTO strudel_renders (strudel_id, user_id, code, duration_seconds)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (strudel_id) WHERE status IN ('queued', 'rendering') DO NOTHING
+		RETURNING ` + renderColumns
+
+	queryGetLatestRender = `
+		SELECT ` + renderColumns + `
+		FROM strudel_renders
+		WHERE strudel_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	// claims a render for an attempt, nothing is returned once it finished
+	queryStartRender = `
+		UPDATE strudel_renders
+		SET status = 'rendering', attempts = attempts + 1
+		WHERE id = $1 AND status IN ('queued', 'rendering')
+		RETURNING strudel_id, code, duration_seconds, attempts
+	`
+
+	queryRecordRenderError = `
+		UPDATE strudel_renders SET error = $2 WHERE id = $1
+	`
+
+	queryFailRender = `
+		UPDATE strudel_renders
+		SET status = 'failed', error = $2, completed_at = NOW()
+		WHERE id = $1
+	`
+
+	queryCompleteRender = `
+		UPDATE strudel_renders
+		SET status = 'ready', object_key = $2, url = $3, error = NULL, completed_at = NOW()
+		WHERE id = $1
+	`
+
+	// the strudel keeps only its latest finished render
+	queryDeleteReplacedRenders = `
+		DELETE FROM strudel_renders
+		WHERE strudel_id = $1 AND id <> $2 AND status IN ('ready', 'failed')
+		RETURNING COALESCE(object_key, '')
+	`
+)
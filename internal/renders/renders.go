@@ -0,0 +1,257 @@
+// Package renders turns strudels into audio files with an external headless
+// renderer, run through the job queue, and keeps the latest one as the
+// strudel's playable preview.
+package renders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/assets"
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// reads the renderer settings from the environment
+func ConfigFromEnv() Config {
+	return Config{
+		URL:   os.Getenv("RENDER_URL"),
+		Token: os.Getenv("RENDER_TOKEN"),
+	}
+}
+
+// creates the render service. backend may be nil, which disables rendering.
+// render jobs are only claimed by instances that can run them
+func New(db *pgxpool.Pool, queue *jobs.Queue, backend assets.Backend, config Config) *Service {
+	s := &Service{
+		db:      db,
+		jobs:    queue,
+		backend: backend,
+		client:  &http.Client{Timeout: renderTimeout},
+		config:  config,
+	}
+
+	if s.Enabled() {
+		queue.Register(jobRender, s.handleRenderJob, jobs.WithMaxAttempts(maxAttempts))
+	}
+
+	return s
+}
+
+// reports whether the renderer and object storage are configured
+func (s *Service) Enabled() bool {
+	return s.config.URL != "" && s.backend != nil
+}
+
+// queues a render of the current code of a strudel owned by the user
+func (s *Service) Request(ctx context.Context, strudelID, userID string, durationSeconds int) (*Render, error) {
+	if !s.Enabled() {
+		return nil, ErrNotConfigured
+	}
+
+	var render *Render
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		var code string
+		err := tx.QueryRow(ctx, queryGetStrudelCode, strudelID, userID).Scan(&code)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrStrudelNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, queryAbandonRenders, strudelID, abandonedAfter.Seconds()); err != nil {
+			return err
+		}
+
+		render, err = scanRender(tx.QueryRow(ctx, queryCreateRender, strudelID, userID, code, durationSeconds))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRenderInProgress
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.jobs.EnqueueTx(ctx, tx, jobRender, renderJob{RenderID: render.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return render, nil
+}
+
+// gets the latest render of a strudel owned by the user
+func (s *Service) Latest(ctx context.Context, strudelID, userID string) (*Render, error) {
+	render, err := scanRender(s.db.QueryRow(ctx, queryGetLatestRender, strudelID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRenderNotFound
+	}
+
+	return render, err
+}
+
+// renders the code of a render, stores the audio and makes it the strudel's
+// preview. failed attempts are retried until the render runs out of them
+func (s *Service) handleRenderJob(ctx context.Context, payload json.RawMessage) error {
+	var job renderJob
+	if err := jobs.Decode(payload, &job); err != nil {
+		return err
+	}
+
+	var strudelID, code string
+	var durationSeconds, attempts int
+
+	err := s.db.QueryRow(ctx, queryStartRender, job.RenderID).Scan(&strudelID, &code, &durationSeconds, &attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// finished, abandoned or deleted with its strudel
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("renders/%s/%s.%s", strudelID, job.RenderID, audioExtension)
+
+	err = s.renderTo(ctx, key, code, durationSeconds)
+	if err != nil {
+		if jobs.IsPermanent(err) || attempts >= maxAttempts {
+			s.recordError(ctx, queryFailRender, job.RenderID, err)
+			return jobs.Permanent(err)
+		}
+
+		s.recordError(ctx, queryRecordRenderError, job.RenderID, err)
+		return err
+	}
+
+	return s.complete(ctx, job.RenderID, strudelID, key)
+}
+
+// renders code and uploads the audio to key
+func (s *Service) renderTo(ctx context.Context, key, code string, durationSeconds int) error {
+	audio, err := s.render(ctx, code, durationSeconds)
+	if err != nil {
+		return err
+	}
+
+	return s.backend.Put(ctx, key, audioContentType, audio)
+}
+
+// asks the renderer for durationSeconds of audio of code. rejections of the
+// code are permanent, other failures worth retrying
+func (s *Service) render(ctx context.Context, code string, durationSeconds int) ([]byte, error) {
+	body, err := json.Marshal(renderRequest{Code: code, DurationSeconds: durationSeconds, Format: audioExtension})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", audioContentType)
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("renderer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength)) //nolint:errcheck // best-effort detail
+		err := fmt.Errorf("renderer returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+
+		// the code doesn't render, trying again won't change that
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, jobs.Permanent(fmt.Errorf("%w: %w", ErrRendererRejected, err))
+		}
+
+		return nil, err
+	}
+
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, maxAudioBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered audio: %w", err)
+	}
+
+	switch {
+	case len(audio) > maxAudioBytes:
+		return nil, jobs.Permanent(ErrRenderTooLarge)
+	case len(audio) == 0:
+		return nil, ErrRendererEmptyBody
+	}
+
+	return audio, nil
+}
+
+// marks a render ready and deletes the renders it replaces, with their files
+func (s *Service) complete(ctx context.Context, renderID, strudelID, key string) error {
+	var replaced []string
+
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, queryCompleteRender, renderID, key, s.backend.URL(key)); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, queryDeleteReplacedRenders, strudelID, renderID)
+		if err != nil {
+			return err
+		}
+
+		replaced, err = pgx.CollectRows(rows, pgx.RowTo[string])
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, old := range replaced {
+		if old == "" {
+			continue
+		}
+
+		if err := s.backend.Delete(ctx, old); err != nil {
+			logger.Warn("failed to delete replaced render", "key", old, "error", err)
+		}
+	}
+
+	logger.Info("strudel rendered", "strudel_id", strudelID, "render_id", renderID)
+	return nil
+}
+
+// keeps why an attempt failed with the render
+func (s *Service) recordError(ctx context.Context, query, renderID string, renderErr error) {
+	message := renderErr.Error()
+	if len(message) > maxErrorLength {
+		message = strings.ToValidUTF8(message[:maxErrorLength], "")
+	}
+
+	if _, err := s.db.Exec(context.WithoutCancel(ctx), query, renderID, message); err != nil {
+		logger.ErrorErr(err, "failed to record render error", "render_id", renderID)
+	}
+}
+
+func scanRender(row pgx.Row) (*Render, error) {
+	var r Render
+	err := row.Scan(&r.ID, &r.StrudelID, &r.Status, &r.DurationSeconds, &r.URL, &r.Error, &r.CreatedAt, &r.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
@@ -0,0 +1,98 @@
+package renders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"codeberg.org/algopatterns/server/internal/jobs"
+)
+
+// a renderer answering every request with status and body
+func testRenderer(t *testing.T, status int, body []byte) *Service {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want the configured token", got)
+		}
+
+		var req renderRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode render request: %v", err)
+		}
+		if req.Code != `s("bd")` || req.DurationSeconds != 30 || req.Format != "mp3" {
+			t.Errorf("unexpected render request %+v", req)
+		}
+
+		w.WriteHeader(status)
+		w.Write(body) //nolint:errcheck,gosec // test server
+	}))
+	t.Cleanup(server.Close)
+
+	return &Service{
+		client: server.Client(),
+		config: Config{URL: server.URL, Token: "secret"},
+	}
+}
+
+func TestRender(t *testing.T) {
+	audio, err := testRenderer(t, http.StatusOK, []byte("ID3")).render(context.Background(), `s("bd")`, 30)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	if string(audio) != "ID3" {
+		t.Errorf("render() = %q, want the renderer's body", audio)
+	}
+}
+
+func TestRenderFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      []byte
+		permanent bool
+	}{
+		{"rejected code", http.StatusUnprocessableEntity, []byte("syntax error"), true},
+		{"rate limited", http.StatusTooManyRequests, nil, false},
+		{"renderer down", http.StatusServiceUnavailable, nil, false},
+		{"empty audio", http.StatusOK, nil, false},
+		{"too large", http.StatusOK, make([]byte, maxAudioBytes+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testRenderer(t, tt.status, tt.body).render(context.Background(), `s("bd")`, 30)
+			if err == nil {
+				t.Fatal("render() succeeded, want an error")
+			}
+			if got := jobs.IsPermanent(err); got != tt.permanent {
+				t.Errorf("render() error %v permanent = %v, want %v", err, got, tt.permanent)
+			}
+		})
+	}
+}
+
+func TestRenderKeepsRejectionReason(t *testing.T) {
+	_, err := testRenderer(t, http.StatusBadRequest, []byte("unknown function foo")).render(context.Background(), `s("bd")`, 30)
+	if !errors.Is(err, ErrRendererRejected) {
+		t.Fatalf("render() error = %v, want ErrRendererRejected", err)
+	}
+	if got := err.Error(); got != "renderer rejected the code: renderer returned 400: unknown function foo" {
+		t.Errorf("render() error = %q", got)
+	}
+}
+
+func TestDisabledWithoutStorage(t *testing.T) {
+	s := New(nil, jobs.New(nil), nil, Config{URL: "http://renderer"})
+	if s.Enabled() {
+		t.Error("Enabled() = true without object storage")
+	}
+
+	if _, err := s.Request(context.Background(), "s1", "u1", 30); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Request() error = %v, want ErrNotConfigured", err)
+	}
+}
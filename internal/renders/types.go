@@ -0,0 +1,102 @@
+package renders
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/assets"
+	"codeberg.org/algopatterns/server/internal/jobs"
+)
+
+// render statuses (must match DB check constraint)
+const (
+	StatusQueued    = "queued"
+	StatusRendering = "rendering"
+	StatusReady     = "ready"
+	StatusFailed    = "failed"
+)
+
+// background job that renders a requested render
+const jobRender = "renders.render"
+
+const (
+	// length of rendered audio
+	DefaultDurationSeconds = 30
+	MinDurationSeconds     = 5
+	MaxDurationSeconds     = 60
+
+	// attempts before a render fails for good. renders the renderer rejects
+	// fail right away
+	maxAttempts = 3
+
+	// how long the renderer may take, within the job timeout
+	renderTimeout = 90 * time.Second
+
+	// largest audio file accepted from the renderer
+	maxAudioBytes = 20 << 20
+
+	// renders still in progress this long after they were requested were
+	// lost, e.g. their job was dead-lettered, and no longer block new ones
+	abandonedAfter = 15 * time.Minute
+
+	// characters of the renderer's error kept with the render
+	maxErrorLength = 500
+
+	// rendered files, at renders/<strudel id>/<render id>.mp3
+	audioContentType = "audio/mpeg"
+	audioExtension   = "mp3"
+)
+
+// errors
+var (
+	ErrNotConfigured     = errors.New("audio rendering is not configured")
+	ErrStrudelNotFound   = errors.New("strudel not found")
+	ErrRenderNotFound    = errors.New("render not found")
+	ErrRenderInProgress  = errors.New("a render of this strudel is already in progress")
+	ErrRendererRejected  = errors.New("renderer rejected the code")
+	ErrRenderTooLarge    = errors.New("rendered audio is too large")
+	ErrRendererEmptyBody = errors.New("renderer returned no audio")
+)
+
+// settings of the external renderer, read from the environment. rendering
+// stays disabled unless the URL is set and object storage is configured
+type Config struct {
+	URL   string // RENDER_URL, endpoint the renderer takes render requests on
+	Token string // RENDER_TOKEN, sent as a bearer token
+}
+
+// renders strudels to audio with an external headless renderer (Strudel in
+// a browser with ffmpeg) and keeps the files in object storage
+type Service struct {
+	db      *pgxpool.Pool
+	jobs    *jobs.Queue
+	backend assets.Backend // nil when storage is not configured
+	client  *http.Client
+	config  Config
+}
+
+// an audio render of a strudel
+type Render struct {
+	ID              string     `json:"id"`
+	StrudelID       string     `json:"strudel_id"`
+	Status          string     `json:"status"`
+	DurationSeconds int        `json:"duration_seconds"`
+	URL             string     `json:"url,omitempty"`   // set once ready
+	Error           string     `json:"error,omitempty"` // why the last attempt failed
+	CreatedAt       time.Time  `json:"created_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+type renderJob struct {
+	RenderID string `json:"render_id"`
+}
+
+// what the renderer is asked to render
+type renderRequest struct {
+	Code            string `json:"code"`
+	DurationSeconds int    `json:"duration_seconds"`
+	Format          string `json:"format"`
+}
@@ -0,0 +1,109 @@
+package retriever
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+// reads EMBEDDING_CACHE_TTL, e.g. "72h". zero disables the cache
+func EmbeddingCacheTTLFromEnv() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("EMBEDDING_CACHE_TTL")); err == nil {
+		return ttl
+	}
+
+	return defaultEmbeddingCacheTTL
+}
+
+func NewRedisEmbeddingCache(client redis.UniversalClient, model string, ttl time.Duration) *RedisEmbeddingCache {
+	return &RedisEmbeddingCache{client: client, model: model, ttl: ttl}
+}
+
+func (c *RedisEmbeddingCache) GetEmbedding(ctx context.Context, key string) ([]float32, error) {
+	data, err := c.client.Get(ctx, fmt.Sprintf(keyEmbeddingCache, c.model, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeEmbedding(data)
+}
+
+func (c *RedisEmbeddingCache) SetEmbedding(ctx context.Context, key string, embedding []float32) error {
+	return c.client.Set(ctx, fmt.Sprintf(keyEmbeddingCache, c.model, key), encodeEmbedding(embedding), c.ttl).Err()
+}
+
+// caches query embeddings. a nil cache disables caching
+func (c *Client) SetEmbeddingCache(cache EmbeddingCache) {
+	c.embeddingCache = cache
+}
+
+// embeds a search query, reusing cached embeddings. cache errors fail open
+func (c *Client) embedQuery(ctx context.Context, text string) ([]float32, error) {
+	if c.embeddingCache == nil {
+		return c.llm.GenerateEmbedding(ctx, text)
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	key := hex.EncodeToString(sum[:])
+
+	cached, err := c.embeddingCache.GetEmbedding(ctx, key)
+	if err != nil {
+		logger.Warn("embedding cache lookup failed", "error", err)
+	}
+
+	if cached != nil {
+		metrics.RetrieverEmbeddingCache.Inc("hit")
+		return cached, nil
+	}
+
+	metrics.RetrieverEmbeddingCache.Inc("miss")
+
+	embedding, err := c.llm.GenerateEmbedding(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.embeddingCache.SetEmbedding(context.WithoutCancel(ctx), key, embedding); err != nil {
+		logger.Warn("embedding cache store failed", "error", err)
+	}
+
+	return embedding, nil
+}
+
+// packs an embedding as little-endian float32s, a quarter of the size of json
+func encodeEmbedding(embedding []float32) []byte {
+	data := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(v))
+	}
+
+	return data
+}
+
+func decodeEmbedding(data []byte) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("invalid cached embedding length %d", len(data))
+	}
+
+	embedding := make([]float32, len(data)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+
+	return embedding, nil
+}
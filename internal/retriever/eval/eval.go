@@ -0,0 +1,178 @@
+package eval
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	IndexDocs     = "docs"
+	IndexExamples = "examples"
+)
+
+//go:embed golden_queries.json
+var defaultGoldenSet []byte
+
+// reads a golden set from path, or the built-in set when path is empty
+func LoadGoldenSet(path string) ([]GoldenQuery, error) {
+	data := defaultGoldenSet
+
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read golden set: %w", err)
+		}
+	}
+
+	var queries []GoldenQuery
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("failed to parse golden set: %w", err)
+	}
+
+	return queries, nil
+}
+
+// runs every golden query against the searcher and scores the top k results.
+// search errors are recorded per query rather than aborting the run
+func Run(ctx context.Context, searcher Searcher, queries []GoldenQuery, k int) *Report {
+	report := &Report{K: k, Indexes: map[string]IndexScore{}}
+
+	for _, q := range queries {
+		if len(q.ExpectedDocs) > 0 {
+			var retrieved []string
+			results, err := searcher.HybridSearchDocs(ctx, q.Query, q.EditorState, k)
+			for _, r := range results {
+				retrieved = append(retrieved, r.PageName+"#"+r.SectionTitle)
+			}
+
+			report.add(score(q.ID, IndexDocs, q.ExpectedDocs, retrieved, k, matchDoc, err))
+		}
+
+		if len(q.ExpectedExamples) > 0 {
+			var retrieved []string
+			results, err := searcher.HybridSearchExamples(ctx, q.Query, q.EditorState, k)
+			for _, r := range results {
+				retrieved = append(retrieved, r.Title)
+			}
+
+			report.add(score(q.ID, IndexExamples, q.ExpectedExamples, retrieved, k, strings.EqualFold, err))
+		}
+	}
+
+	for index, s := range report.Indexes {
+		if scored := s.Queries - s.Errors; scored > 0 {
+			s.Recall /= float64(scored)
+			s.MRR /= float64(scored)
+		}
+		report.Indexes[index] = s
+	}
+
+	return report
+}
+
+// accumulates sums; Run turns them into means once all queries are scored
+func (r *Report) add(q QueryScore) {
+	r.Queries = append(r.Queries, q)
+
+	s := r.Indexes[q.Index]
+	s.Queries++
+
+	if q.Error != "" {
+		s.Errors++
+	} else {
+		s.Recall += q.Recall
+		s.MRR += q.RR
+	}
+
+	r.Indexes[q.Index] = s
+}
+
+// scores retrieved against expected: recall@k is the share of expected items
+// in the top k, the reciprocal rank is 1/rank of the first relevant result
+func score(id, index string, expected, retrieved []string, k int, match func(expected, retrieved string) bool, err error) QueryScore {
+	q := QueryScore{ID: id, Index: index}
+	if err != nil {
+		q.Error = err.Error()
+		q.Missed = expected
+		return q
+	}
+
+	top := retrieved[:min(k, len(retrieved))]
+
+	for _, want := range expected {
+		found := false
+
+		for rank, got := range top {
+			if !match(want, got) {
+				continue
+			}
+
+			found = true
+			if rr := 1 / float64(rank+1); rr > q.RR {
+				q.RR = rr
+			}
+			break
+		}
+
+		if found {
+			q.Recall++
+		} else {
+			q.Missed = append(q.Missed, want)
+		}
+	}
+
+	q.Recall /= float64(len(expected))
+
+	return q
+}
+
+// matches "page" against any section of the page, "page#section" against that
+// section only. retrieved chunks are always "page#section"
+func matchDoc(expected, retrieved string) bool {
+	page, section, hasSection := strings.Cut(expected, "#")
+	gotPage, gotSection, _ := strings.Cut(retrieved, "#")
+
+	if page != gotPage {
+		return false
+	}
+
+	return !hasSection || strings.EqualFold(section, gotSection)
+}
+
+// returns metrics that fell more than tolerance below the baseline
+func Compare(baseline, current *Report, tolerance float64) []Regression {
+	var regressions []Regression
+
+	for _, index := range indexNames(baseline) {
+		base := baseline.Indexes[index]
+		cur, ok := current.Indexes[index]
+		if !ok {
+			continue
+		}
+
+		if cur.Recall < base.Recall-tolerance {
+			regressions = append(regressions, Regression{Index: index, Metric: "recall@k", Baseline: base.Recall, Current: cur.Recall})
+		}
+
+		if cur.MRR < base.MRR-tolerance {
+			regressions = append(regressions, Regression{Index: index, Metric: "mrr", Baseline: base.MRR, Current: cur.MRR})
+		}
+	}
+
+	return regressions
+}
+
+func indexNames(r *Report) []string {
+	names := make([]string, 0, len(r.Indexes))
+	for index := range r.Indexes {
+		names = append(names, index)
+	}
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,112 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"codeberg.org/algopatterns/server/internal/retriever"
+)
+
+// returns canned results for every query
+type mockSearcher struct {
+	docs    []retriever.SearchResult
+	docsErr error
+}
+
+func (m *mockSearcher) HybridSearchDocs(_ context.Context, _, _ string, _ int) ([]retriever.SearchResult, error) {
+	return m.docs, m.docsErr
+}
+
+func (m *mockSearcher) HybridSearchExamples(_ context.Context, _, _ string, _ int) ([]retriever.ExampleResult, error) {
+	return nil, nil
+}
+
+func TestLoadGoldenSet(t *testing.T) {
+	queries, err := LoadGoldenSet("")
+	if err != nil {
+		t.Fatalf("failed to load built-in golden set: %v", err)
+	}
+
+	if len(queries) == 0 {
+		t.Fatal("expected built-in golden set to have queries")
+	}
+
+	seen := map[string]bool{}
+	for _, q := range queries {
+		if q.ID == "" || q.Query == "" {
+			t.Errorf("query %+v is missing an id or query", q)
+		}
+
+		if len(q.ExpectedDocs) == 0 && len(q.ExpectedExamples) == 0 {
+			t.Errorf("query %s has no expected results", q.ID)
+		}
+
+		if seen[q.ID] {
+			t.Errorf("duplicate query id %s", q.ID)
+		}
+		seen[q.ID] = true
+	}
+}
+
+func TestRun(t *testing.T) {
+	searcher := &mockSearcher{docs: []retriever.SearchResult{
+		{PageName: "learn/samples.mdx", SectionTitle: "PAGE_SUMMARY"},
+		{PageName: "learn/effects.mdx", SectionTitle: "room"},
+		{PageName: "learn/notes.mdx", SectionTitle: "Notes"},
+	}}
+
+	queries := []GoldenQuery{
+		{ID: "hit-second", Query: "reverb", ExpectedDocs: []string{"learn/effects.mdx#Room"}},
+		{ID: "half", Query: "notes", ExpectedDocs: []string{"learn/notes.mdx", "learn/tonal.mdx"}},
+		{ID: "outside-k", Query: "notes", ExpectedDocs: []string{"learn/notes.mdx"}},
+	}
+
+	report := Run(context.Background(), searcher, queries[:2], 3)
+
+	docs := report.Indexes[IndexDocs]
+	if docs.Queries != 2 {
+		t.Fatalf("expected 2 scored queries, got %d", docs.Queries)
+	}
+
+	// recall: (1 + 0.5) / 2, mrr: (1/2 + 1/3) / 2
+	if docs.Recall != 0.75 {
+		t.Errorf("expected recall 0.75, got %v", docs.Recall)
+	}
+
+	if want := (0.5 + 1.0/3) / 2; math.Abs(docs.MRR-want) > 1e-9 {
+		t.Errorf("expected mrr %v, got %v", want, docs.MRR)
+	}
+
+	if missed := report.Queries[1].Missed; len(missed) != 1 || missed[0] != "learn/tonal.mdx" {
+		t.Errorf("expected learn/tonal.mdx to be missed, got %v", missed)
+	}
+
+	// results past k don't count
+	if q := Run(context.Background(), searcher, queries[2:], 2).Queries[0]; q.Recall != 0 || q.RR != 0 {
+		t.Errorf("expected no credit outside the top k, got recall %v rr %v", q.Recall, q.RR)
+	}
+}
+
+func TestRunSearchError(t *testing.T) {
+	searcher := &mockSearcher{docsErr: errors.New("db down")}
+
+	report := Run(context.Background(), searcher, []GoldenQuery{
+		{ID: "q", Query: "beat", ExpectedDocs: []string{"learn/samples.mdx"}},
+	}, 5)
+
+	if s := report.Indexes[IndexDocs]; s.Errors != 1 || s.Recall != 0 {
+		t.Errorf("expected 1 error and no recall, got %+v", s)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Report{Indexes: map[string]IndexScore{IndexDocs: {Recall: 0.8, MRR: 0.6}}}
+	current := &Report{Indexes: map[string]IndexScore{IndexDocs: {Recall: 0.79, MRR: 0.5}}}
+
+	regressions := Compare(baseline, current, 0.02)
+	if len(regressions) != 1 || regressions[0].Metric != "mrr" {
+		t.Errorf("expected only an mrr regression, got %+v", regressions)
+	}
+}
@@ -0,0 +1,69 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// prints per-index scores, the queries that missed expected results and any
+// regressions against a baseline
+func WriteReport(w io.Writer, report *Report, regressions []Regression) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%-10s %8s %10s %7s %7s\n", "INDEX", "QUERIES", fmt.Sprintf("RECALL@%d", report.K), "MRR", "ERRORS")
+	for _, index := range indexNames(report) {
+		s := report.Indexes[index]
+		fmt.Fprintf(&b, "%-10s %8d %10.3f %7.3f %7d\n", index, s.Queries, s.Recall, s.MRR, s.Errors)
+	}
+
+	var misses []string
+	for _, q := range report.Queries {
+		switch {
+		case q.Error != "":
+			misses = append(misses, fmt.Sprintf("  %s [%s] error: %s", q.ID, q.Index, q.Error))
+		case len(q.Missed) > 0:
+			misses = append(misses, fmt.Sprintf("  %s [%s] recall %.2f, missed: %s", q.ID, q.Index, q.Recall, strings.Join(q.Missed, ", ")))
+		}
+	}
+
+	if len(misses) > 0 {
+		fmt.Fprintf(&b, "\nmissed expected results:\n%s\n", strings.Join(misses, "\n"))
+	}
+
+	if len(regressions) > 0 {
+		b.WriteString("\nregressions against baseline:\n")
+		for _, r := range regressions {
+			fmt.Fprintf(&b, "  %s %s: %.3f -> %.3f\n", r.Index, r.Metric, r.Baseline, r.Current)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writes report as JSON, for use as a later run's baseline
+func SaveReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,59 @@
+package eval
+
+import (
+	"context"
+
+	"codeberg.org/algopatterns/server/internal/retriever"
+)
+
+// the retrieval calls under evaluation (implemented by *retriever.Client)
+type Searcher interface {
+	HybridSearchDocs(ctx context.Context, query, editorState string, k int) ([]retriever.SearchResult, error)
+	HybridSearchExamples(ctx context.Context, query, editorState string, k int) ([]retriever.ExampleResult, error)
+}
+
+// a curated query and the results it should retrieve
+type GoldenQuery struct {
+	ID          string `json:"id"`
+	Query       string `json:"query"`
+	EditorState string `json:"editor_state,omitempty"`
+
+	// doc chunks as "page" or "page#section", e.g. "learn/effects.mdx#room"
+	ExpectedDocs []string `json:"expected_docs,omitempty"`
+
+	// example strudel titles (case-insensitive)
+	ExpectedExamples []string `json:"expected_examples,omitempty"`
+}
+
+// scores for one query against one index
+type QueryScore struct {
+	ID     string   `json:"id"`
+	Index  string   `json:"index"` // "docs" or "examples"
+	Recall float64  `json:"recall"`
+	RR     float64  `json:"reciprocal_rank"`
+	Missed []string `json:"missed,omitempty"` // expected results not in the top k
+	Error  string   `json:"error,omitempty"`
+}
+
+// aggregate scores for one index
+type IndexScore struct {
+	Queries int     `json:"queries"`
+	Recall  float64 `json:"recall_at_k"`
+	MRR     float64 `json:"mrr"`
+	Errors  int     `json:"errors"`
+}
+
+// results of an evaluation run. saved as JSON to compare later runs against
+type Report struct {
+	K       int                   `json:"k"`
+	Indexes map[string]IndexScore `json:"indexes"`
+	Queries []QueryScore          `json:"queries"`
+}
+
+// a metric that dropped below the baseline by more than the tolerance
+type Regression struct {
+	Index    string
+	Metric   string
+	Baseline float64
+	Current  float64
+}
@@ -57,13 +57,55 @@ const (
 		WHERE us.searchable_tsvector @@ websearch_to_tsquery('english', $1)
 		  AND us.cc_signal IS NOT NULL
 		  AND us.cc_signal != 'no-ai'
-		  AND us.use_in_training = true
 		  AND us.is_public = true
+		  AND us.deleted_at IS NULL
 		  AND u.training_consent = true
 		ORDER BY rank DESC
 		LIMIT $2
 	`
 
+	getStrudelEmbeddingQuery = `
+		SELECT embedding FROM user_strudels WHERE id = $1
+	`
+
+	// nearest public strudels, excluding every strudel sharing the fork tree
+	// (the root ancestor and all of its descendants) of the given strudel
+	similarStrudelsQuery = `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, forked_from FROM user_strudels WHERE id = $2
+			UNION
+			SELECT p.id, p.forked_from
+			FROM user_strudels p
+			INNER JOIN ancestors a ON p.id = a.forked_from
+		),
+		lineage AS (
+			SELECT id FROM ancestors WHERE forked_from IS NULL
+			UNION
+			SELECT c.id
+			FROM user_strudels c
+			INNER JOIN lineage l ON c.forked_from = l.id
+		)
+		SELECT
+			s.id::text,
+			s.title,
+			COALESCE(s.description, ''),
+			s.code,
+			s.tags,
+			s.user_id::text,
+			COALESCE(u.name, 'Anonymous') as author_name,
+			'' as url,
+			(1 - (s.embedding <=> $1))::real as similarity
+		FROM user_strudels s
+		LEFT JOIN users u ON s.user_id = u.id
+		WHERE s.is_public = true
+		  AND s.deleted_at IS NULL
+		  AND s.embedding IS NOT NULL
+		  AND s.id <> $2
+		  AND s.id NOT IN (SELECT id FROM lineage)
+		ORDER BY s.embedding <=> $1
+		LIMIT $3
+	`
+
 	fetchSpecialChunkQuery = `
 		SELECT
 			id::text,
@@ -0,0 +1,264 @@
+package retriever
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+	"codeberg.org/algopatterns/server/internal/tracing"
+)
+
+const rerankSystemPrompt = `You rate how relevant search results are to a query about Strudel, a live coding music language.
+Score each numbered passage from 0 (unrelated) to 10 (directly answers the query).
+Reply with only a JSON array of numbers, one per passage, in passage order. Example: [7, 0, 3]`
+
+func RerankConfigFromEnv() RerankConfig {
+	config := RerankConfig{
+		Candidates: defaultRerankCandidates,
+		Timeout:    defaultRerankTimeout,
+		URL:        os.Getenv("RERANK_URL"),
+	}
+
+	config.Enabled, _ = strconv.ParseBool(os.Getenv("RERANK_ENABLED")) //nolint:errcheck // unset or invalid means disabled
+
+	if n, err := strconv.Atoi(os.Getenv("RERANK_CANDIDATES")); err == nil && n > 0 {
+		config.Candidates = n
+	}
+
+	if timeout, err := time.ParseDuration(os.Getenv("RERANK_TIMEOUT")); err == nil && timeout > 0 {
+		config.Timeout = timeout
+	}
+
+	return config
+}
+
+// creates the reranker described by config: a cross-encoder when URL is set,
+// otherwise the transformer model
+func NewReranker(config RerankConfig) (Reranker, error) {
+	if config.URL != "" {
+		return NewCrossEncoderReranker(config.URL), nil
+	}
+
+	generator, err := llm.NewTransformerGenerator()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLLMReranker(generator), nil
+}
+
+func NewLLMReranker(generator llm.TextGenerator) *LLMReranker {
+	return &LLMReranker{generator: generator}
+}
+
+func (r *LLMReranker) Rerank(ctx context.Context, query string, passages []string) ([]float32, error) {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Query: %s\n", query)
+
+	for i, passage := range passages {
+		fmt.Fprintf(&prompt, "\n[%d]\n%s\n", i, passage)
+	}
+
+	resp, err := r.generator.GenerateText(ctx, llm.TextGenerationRequest{
+		SystemPrompt: rerankSystemPrompt,
+		Messages:     []llm.Message{{Role: "user", Content: prompt.String()}},
+		MaxTokens:    8 * len(passages),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRerankScores(resp.Text, len(passages))
+}
+
+// reads the model's JSON array of 0-10 scores, normalized to 0-1
+func parseRerankScores(text string, n int) ([]float32, error) {
+	start, end := strings.Index(text, "["), strings.LastIndex(text, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no score array in reranker response")
+	}
+
+	var scores []float32
+	if err := json.Unmarshal([]byte(text[start:end+1]), &scores); err != nil {
+		return nil, fmt.Errorf("failed to parse reranker scores: %w", err)
+	}
+
+	if len(scores) != n {
+		return nil, fmt.Errorf("reranker returned %d scores for %d passages", len(scores), n)
+	}
+
+	for i := range scores {
+		scores[i] /= 10
+	}
+
+	return scores, nil
+}
+
+func NewCrossEncoderReranker(url string) *CrossEncoderReranker {
+	return &CrossEncoderReranker{
+		url: strings.TrimSuffix(url, "/"),
+		httpClient: &http.Client{
+			Transport: tracing.NewTransport(http.DefaultTransport),
+		},
+	}
+}
+
+func (r *CrossEncoderReranker) Rerank(ctx context.Context, query string, passages []string) ([]float32, error) {
+	body, err := json.Marshal(map[string]any{"query": query, "texts": passages})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url+"/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cross-encoder returned status %d", resp.StatusCode)
+	}
+
+	var ranked []struct {
+		Index int     `json:"index"`
+		Score float32 `json:"score"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ranked); err != nil {
+		return nil, fmt.Errorf("failed to decode cross-encoder response: %w", err)
+	}
+
+	scores := make([]float32, len(passages))
+	for _, r := range ranked {
+		if r.Index < 0 || r.Index >= len(scores) {
+			return nil, fmt.Errorf("cross-encoder returned out of range index %d", r.Index)
+		}
+		scores[r.Index] = r.Score
+	}
+
+	return scores, nil
+}
+
+// reranks the top candidates of hybrid searches. a nil reranker disables
+// reranking. reranking that exceeds timeout falls back to hybrid order
+func (c *Client) SetReranker(reranker Reranker, candidates int, timeout time.Duration) {
+	c.reranker = reranker
+	c.rerankCandidates = candidates
+	c.rerankTimeout = timeout
+}
+
+// how many merged results to keep before reranking down to topK
+func (c *Client) mergeK(topK int) int {
+	if c.reranker == nil {
+		return topK
+	}
+
+	return max(topK, c.rerankCandidates)
+}
+
+func (c *Client) rerankDocs(ctx context.Context, query string, results []SearchResult, topK int) []SearchResult {
+	passages := make([]string, len(results))
+	for i, r := range results {
+		passages[i] = truncatePassage(r.SectionTitle + "\n" + r.Content)
+	}
+
+	order := c.rerankOrder(ctx, "docs", query, passages)
+	if order == nil {
+		return results[:min(topK, len(results))]
+	}
+
+	reranked := make([]SearchResult, 0, topK)
+	for _, i := range order[:min(topK, len(order))] {
+		reranked = append(reranked, results[i])
+	}
+
+	return reranked
+}
+
+func (c *Client) rerankExamples(ctx context.Context, query string, results []ExampleResult, topK int) []ExampleResult {
+	passages := make([]string, len(results))
+	for i, r := range results {
+		passages[i] = truncatePassage(r.Title + "\n" + r.Description + "\n" + strings.Join(r.Tags, ", ") + "\n" + r.Code)
+	}
+
+	order := c.rerankOrder(ctx, "examples", query, passages)
+	if order == nil {
+		return results[:min(topK, len(results))]
+	}
+
+	reranked := make([]ExampleResult, 0, topK)
+	for _, i := range order[:min(topK, len(order))] {
+		reranked = append(reranked, results[i])
+	}
+
+	return reranked
+}
+
+// returns passage indexes by descending reranker score, or nil if reranking
+// is disabled, failed or ran out of time
+func (c *Client) rerankOrder(ctx context.Context, index, query string, passages []string) []int {
+	if c.reranker == nil || len(passages) < 2 {
+		return nil
+	}
+
+	defer func(start time.Time) {
+		metrics.RetrieverRerankDuration.Observe(time.Since(start).Seconds(), index)
+	}(time.Now())
+
+	ctx, cancel := context.WithTimeout(ctx, c.rerankTimeout)
+	defer cancel()
+
+	scores, err := c.reranker.Rerank(ctx, query, passages)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			metrics.RetrieverRerank.Inc(index, "timeout")
+		} else {
+			metrics.RetrieverRerank.Inc(index, "error")
+		}
+
+		logger.Warn("reranking failed, using hybrid order", "index", index, "error", err)
+		return nil
+	}
+
+	metrics.RetrieverRerank.Inc(index, "ok")
+
+	return orderByScore(scores)
+}
+
+// stable, so ties keep their hybrid order
+func orderByScore(scores []float32) []int {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	return order
+}
+
+func truncatePassage(text string) string {
+	if len(text) <= maxRerankPassageChars {
+		return text
+	}
+
+	return strings.ToValidUTF8(text[:maxRerankPassageChars], "")
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
+	"codeberg.org/algopatterns/server/internal/database"
 	"codeberg.org/algopatterns/server/internal/llm"
 	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+	"codeberg.org/algopatterns/server/internal/tracing"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pgvector/pgvector-go"
 )
@@ -27,13 +31,28 @@ func NewWithTopK(db *pgxpool.Pool, llm llm.LLM, topK int) *Client {
 	}
 }
 
+// sends searches to read replicas. indexes are built in the background, so
+// a little lag goes unnoticed
+func (c *Client) SetReadReplicas(replicas *database.Router) {
+	c.replicas = replicas
+}
+
+// the pool for a search
+func (c *Client) reader(ctx context.Context) *pgxpool.Pool {
+	if c.replicas == nil {
+		return c.db
+	}
+
+	return c.replicas.Reader(ctx)
+}
+
 func (c *Client) VectorSearch(ctx context.Context, queryText string, topK int) ([]SearchResult, error) {
-	embedding, err := c.llm.GenerateEmbedding(ctx, queryText)
+	embedding, err := c.embedQuery(ctx, queryText)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 	}
 
-	rows, err := c.db.Query(ctx, vectorSearchQuery, pgvector.NewVector(embedding), topK)
+	rows, err := c.reader(ctx).Query(ctx, vectorSearchQuery, pgvector.NewVector(embedding), topK)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute search query: %w", err)
 	}
@@ -68,12 +87,12 @@ func (c *Client) VectorSearch(ctx context.Context, queryText string, topK int) (
 }
 
 func (c *Client) SearchExamples(ctx context.Context, queryText string, topK int) ([]ExampleResult, error) {
-	embedding, err := c.llm.GenerateEmbedding(ctx, queryText)
+	embedding, err := c.embedQuery(ctx, queryText)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 	}
 
-	rows, err := c.db.Query(ctx, searchExamplesQuery, pgvector.NewVector(embedding), topK)
+	rows, err := c.reader(ctx).Query(ctx, searchExamplesQuery, pgvector.NewVector(embedding), topK)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute search query: %w", err)
 	}
@@ -111,7 +130,7 @@ func (c *Client) SearchExamples(ctx context.Context, queryText string, topK int)
 }
 
 func (c *Client) BM25Search(ctx context.Context, queryText string, topK int) ([]SearchResult, error) {
-	rows, err := c.db.Query(ctx, bm25SearchDocsQuery, queryText, topK)
+	rows, err := c.reader(ctx).Query(ctx, bm25SearchDocsQuery, queryText, topK)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute BM25 search: %w", err)
 	}
@@ -147,7 +166,7 @@ func (c *Client) BM25Search(ctx context.Context, queryText string, topK int) ([]
 }
 
 func (c *Client) BM25SearchExamples(ctx context.Context, queryText string, topK int) ([]ExampleResult, error) {
-	rows, err := c.db.Query(ctx, bm25SearchExamplesQuery, queryText, topK)
+	rows, err := c.reader(ctx).Query(ctx, bm25SearchExamplesQuery, queryText, topK)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute BM25 example search: %w", err)
 	}
@@ -186,6 +205,11 @@ func (c *Client) BM25SearchExamples(ctx context.Context, queryText string, topK
 }
 
 func (c *Client) HybridSearchDocs(ctx context.Context, userQuery, _ string, topK int) ([]SearchResult, error) {
+	defer observeSearch("docs", time.Now())
+
+	ctx, span := tracing.Start(ctx, "retriever.HybridSearchDocs", tracing.Int("retriever.top_k", topK))
+	defer span.End()
+
 	searchQuery, err := c.llm.TransformQuery(ctx, userQuery)
 	if err != nil {
 		logger.Warn("query transformation failed, using original query", "error", err)
@@ -193,7 +217,7 @@ func (c *Client) HybridSearchDocs(ctx context.Context, userQuery, _ string, topK
 	}
 
 	// run vector and BM25 searches in parallel
-	searchK := topK + 5 // get extra results for better merging
+	searchK := c.mergeK(topK) + 5 // get extra results for better merging
 
 	var vectorResults, bm25Results []SearchResult
 	var vectorErr, bm25Err error
@@ -214,6 +238,7 @@ func (c *Client) HybridSearchDocs(ctx context.Context, userQuery, _ string, topK
 	wg.Wait()
 
 	if vectorErr != nil {
+		span.RecordError(vectorErr)
 		return nil, fmt.Errorf("vector search failed: %w", vectorErr)
 	}
 
@@ -222,7 +247,8 @@ func (c *Client) HybridSearchDocs(ctx context.Context, userQuery, _ string, topK
 		bm25Results = []SearchResult{}
 	}
 
-	merged := mergeVectorAndBM25Docs(vectorResults, bm25Results, topK)
+	merged := mergeVectorAndBM25Docs(vectorResults, bm25Results, c.mergeK(topK))
+	merged = c.rerankDocs(ctx, userQuery, merged, topK)
 
 	organized, err := c.organizeByPage(ctx, merged)
 	if err != nil {
@@ -234,13 +260,18 @@ func (c *Client) HybridSearchDocs(ctx context.Context, userQuery, _ string, topK
 
 // hybrid search (vector + BM25) for strudel examples
 func (c *Client) HybridSearchExamples(ctx context.Context, userQuery, _ string, topK int) ([]ExampleResult, error) {
+	defer observeSearch("examples", time.Now())
+
+	ctx, span := tracing.Start(ctx, "retriever.HybridSearchExamples", tracing.Int("retriever.top_k", topK))
+	defer span.End()
+
 	searchQuery, err := c.llm.TransformQuery(ctx, userQuery)
 	if err != nil {
 		logger.Warn("query transformation failed, using original query", "error", err)
 		searchQuery = userQuery
 	}
 
-	searchK := topK + 5 // get extra results for better merging
+	searchK := c.mergeK(topK) + 5 // get extra results for better merging
 
 	var vectorResults, bm25Results []ExampleResult
 	var vectorErr, bm25Err error
@@ -263,6 +294,7 @@ func (c *Client) HybridSearchExamples(ctx context.Context, userQuery, _ string,
 	wg.Wait()
 
 	if vectorErr != nil {
+		span.RecordError(vectorErr)
 		return nil, fmt.Errorf("vector search failed: %w", vectorErr)
 	}
 
@@ -271,7 +303,95 @@ func (c *Client) HybridSearchExamples(ctx context.Context, userQuery, _ string,
 		bm25Results = []ExampleResult{}
 	}
 
-	merged := mergeVectorAndBM25Examples(vectorResults, bm25Results, topK)
+	merged := mergeVectorAndBM25Examples(vectorResults, bm25Results, c.mergeK(topK))
+
+	return c.rerankExamples(ctx, userQuery, merged, topK), nil
+}
+
+// finds public strudels similar to the given one, excluding its fork lineage.
+// uses the strudel's stored embedding when it has one; otherwise text is
+// embedded, but only if allowEmbedding is set (the strudel permits AI use).
+func (c *Client) SearchSimilarStrudels(
+	ctx context.Context,
+	strudelID, text string,
+	allowEmbedding bool,
+	topK int,
+) ([]ExampleResult, error) {
+	defer observeSearch("similar", time.Now())
+
+	ctx, span := tracing.Start(ctx, "retriever.SearchSimilarStrudels", tracing.Int("retriever.top_k", topK))
+	defer span.End()
+
+	// from the primary, the strudel may be too new for a replica
+	var stored *pgvector.Vector
+	if err := c.db.QueryRow(ctx, getStrudelEmbeddingQuery, strudelID).Scan(&stored); err != nil {
+		return nil, fmt.Errorf("failed to load strudel embedding: %w", err)
+	}
+
+	embedding, err := c.strudelEmbedding(ctx, stored, text, allowEmbedding)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	rows, err := c.reader(ctx).Query(ctx, similarStrudelsQuery, embedding, strudelID, topK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute similarity query: %w", err)
+	}
+
+	defer rows.Close()
+	var results []ExampleResult
+
+	for rows.Next() {
+		var result ExampleResult
+
+		err := rows.Scan(
+			&result.ID,
+			&result.Title,
+			&result.Description,
+			&result.Code,
+			&result.Tags,
+			&result.UserID,
+			&result.AuthorName,
+			&result.URL,
+			&result.Similarity,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// returns a strudel's stored embedding, or embeds text when it has none and
+// allowEmbedding is set
+func (c *Client) strudelEmbedding(ctx context.Context, stored *pgvector.Vector, text string, allowEmbedding bool) (pgvector.Vector, error) {
+	switch {
+	case stored != nil:
+		return *stored, nil
+
+	case allowEmbedding:
+		generated, err := c.llm.GenerateEmbedding(ctx, text)
+		if err != nil {
+			return pgvector.Vector{}, fmt.Errorf("failed to generate strudel embedding: %w", err)
+		}
+
+		return pgvector.NewVector(generated), nil
+
+	default:
+		return pgvector.Vector{}, ErrNoEmbedding
+	}
+}
 
-	return merged, nil
+// records the latency of a hybrid search
+func observeSearch(index string, start time.Time) {
+	metrics.RetrieverSearchDuration.Observe(time.Since(start).Seconds(), index)
 }
@@ -0,0 +1,136 @@
+//go:build integration
+
+package retriever
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"codeberg.org/algopatterns/server/internal/chunker"
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/storage"
+	"codeberg.org/algopatterns/server/internal/testdb"
+)
+
+// the topics the keyword embedder knows, one embedding axis each
+var topics = []string{"drums", "synth", "reverb"}
+
+// embeds text as the sum of the axes of the topics it mentions, so similarity
+// follows shared topics without calling a model
+type keywordEmbedder struct {
+	llm.LLM
+}
+
+func (keywordEmbedder) GenerateEmbedding(_ context.Context, text string) ([]float32, error) {
+	embedding := make([]float32, llm.EmbeddingDimensions)
+	for i, topic := range topics {
+		if strings.Contains(strings.ToLower(text), topic) {
+			embedding[i] = 1
+		}
+	}
+
+	// cosine similarity is undefined for the zero vector
+	embedding[len(embedding)-1] = 0.01
+
+	return embedding, nil
+}
+
+// stores chunks under a page of their own, removed when the test ends
+func seedChunks(t *testing.T, contents ...string) (*Client, string) {
+	t.Helper()
+
+	ctx := context.Background()
+	db := testdb.Open(t)
+	store := storage.NewClientFromPool(db)
+	embedder := keywordEmbedder{}
+
+	page := "integration-" + testdb.Unique(t)
+	t.Cleanup(func() {
+		if _, err := db.Exec(context.Background(), "DELETE FROM doc_embeddings WHERE page_name = $1", page); err != nil {
+			t.Errorf("failed to remove seeded chunks: %v", err)
+		}
+	})
+
+	for i, content := range contents {
+		embedding, _ := embedder.GenerateEmbedding(ctx, content)
+		chunk := chunker.Chunk{
+			PageName:     page,
+			PageURL:      "https://strudel.cc/learn/" + page,
+			SectionTitle: topics[i%len(topics)],
+			Content:      content,
+			Metadata:     map[string]interface{}{},
+		}
+
+		if err := store.InsertChunk(ctx, chunk, embedding); err != nil {
+			t.Fatalf("failed to seed chunk: %v", err)
+		}
+	}
+
+	return New(db, embedder), page
+}
+
+// only the seeded page's results, other tests may share the database
+func onPage(results []SearchResult, page string) []SearchResult {
+	var filtered []SearchResult
+	for _, r := range results {
+		if r.PageName == page {
+			filtered = append(filtered, r)
+		}
+	}
+
+	return filtered
+}
+
+func TestVectorSearchIntegration(t *testing.T) {
+	c, page := seedChunks(t,
+		"Play drums with s(\"bd sd\")",
+		"A synth with reverb on top",
+		"Reverb adds space with .room()",
+	)
+
+	results, err := c.VectorSearch(context.Background(), "how do I add reverb?", 10)
+	if err != nil {
+		t.Fatalf("VectorSearch failed: %v", err)
+	}
+
+	results = onPage(results, page)
+	if len(results) != 3 {
+		t.Fatalf("Expected the 3 seeded chunks, got %d", len(results))
+	}
+
+	if !strings.HasPrefix(results[0].Content, "Reverb") {
+		t.Errorf("Expected the reverb-only chunk first, got %q", results[0].Content)
+	}
+
+	if !strings.HasPrefix(results[2].Content, "Play drums") {
+		t.Errorf("Expected the drums chunk last, got %q", results[2].Content)
+	}
+
+	for i := range len(results) - 1 {
+		if results[i].Similarity < results[i+1].Similarity {
+			t.Errorf("Results not sorted by similarity at position %d", i)
+		}
+	}
+}
+
+func TestBM25SearchIntegration(t *testing.T) {
+	c, page := seedChunks(t,
+		"Play drums with s(\"bd sd\")",
+		"Layer a synth pad under the melody",
+	)
+
+	results, err := c.BM25Search(context.Background(), "synth pad", 10)
+	if err != nil {
+		t.Fatalf("BM25Search failed: %v", err)
+	}
+
+	results = onPage(results, page)
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 keyword match, got %d", len(results))
+	}
+
+	if results[0].SectionTitle != "synth" || results[0].Similarity <= 0 {
+		t.Errorf("Expected the synth chunk with a positive rank, got %+v", results[0])
+	}
+}
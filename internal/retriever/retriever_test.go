@@ -1,13 +1,18 @@
 package retriever
 
 import (
+	"context"
+	"errors"
 	"log"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"codeberg.org/algopatterns/server/internal/llm"
 	"codeberg.org/algopatterns/server/internal/strudel"
 	"github.com/joho/godotenv"
+	"github.com/pgvector/pgvector-go"
 )
 
 func init() {
@@ -195,3 +200,106 @@ func TestUniqueStrings(t *testing.T) {
 		}
 	}
 }
+
+// verifies cached embeddings survive the binary round trip
+func TestEncodeEmbedding(t *testing.T) {
+	embedding := []float32{0.5, -1.25, 0, 3.0e-7}
+
+	decoded, err := decodeEmbedding(encodeEmbedding(embedding))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(decoded, embedding) {
+		t.Errorf("Expected %v, got %v", embedding, decoded)
+	}
+
+	if _, err := decodeEmbedding([]byte{1, 2, 3}); err == nil {
+		t.Error("Expected error for truncated embedding")
+	}
+}
+
+// verifies reranker scores are parsed from surrounding text and normalized
+func TestParseRerankScores(t *testing.T) {
+	scores, err := parseRerankScores("Scores: [10, 0, 5]", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(scores, []float32{1, 0, 0.5}) {
+		t.Errorf("Expected [1 0 0.5], got %v", scores)
+	}
+
+	if _, err := parseRerankScores("[1, 2]", 3); err == nil {
+		t.Error("Expected error for score count mismatch")
+	}
+
+	if _, err := parseRerankScores("no idea", 1); err == nil {
+		t.Error("Expected error for missing array")
+	}
+}
+
+// verifies reranking reorders results and falls back to hybrid order on failure
+func TestRerankDocs(t *testing.T) {
+	results := []SearchResult{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	c := &Client{}
+	c.SetReranker(rerankerFunc(func(_ context.Context, _ string, passages []string) ([]float32, error) {
+		return []float32{0.1, 0.9, 0.5}, nil
+	}), 20, time.Second)
+
+	reranked := c.rerankDocs(context.Background(), "query", results, 2)
+	if len(reranked) != 2 || reranked[0].ID != "b" || reranked[1].ID != "c" {
+		t.Errorf("Expected [b c], got %v", reranked)
+	}
+
+	c.SetReranker(rerankerFunc(func(_ context.Context, _ string, _ []string) ([]float32, error) {
+		return nil, errors.New("unavailable")
+	}), 20, time.Second)
+
+	fallback := c.rerankDocs(context.Background(), "query", results, 2)
+	if len(fallback) != 2 || fallback[0].ID != "a" || fallback[1].ID != "b" {
+		t.Errorf("Expected hybrid order [a b], got %v", fallback)
+	}
+}
+
+type rerankerFunc func(ctx context.Context, query string, passages []string) ([]float32, error)
+
+func (f rerankerFunc) Rerank(ctx context.Context, query string, passages []string) ([]float32, error) {
+	return f(ctx, query, passages)
+}
+
+// verifies stored embeddings are reused and code is only embedded when allowed
+func TestStrudelEmbedding(t *testing.T) {
+	embedder := &countingEmbedder{}
+	c := &Client{llm: embedder}
+	stored := pgvector.NewVector([]float32{0.3, 0.4})
+
+	embedding, err := c.strudelEmbedding(context.Background(), &stored, "code", false)
+	if err != nil || !slices.Equal(embedding.Slice(), stored.Slice()) {
+		t.Errorf("Expected stored embedding, got %v, %v", embedding.Slice(), err)
+	}
+
+	if _, err := c.strudelEmbedding(context.Background(), nil, "code", false); !errors.Is(err, ErrNoEmbedding) {
+		t.Errorf("Expected ErrNoEmbedding without permission, got %v", err)
+	}
+
+	if embedder.calls != 0 {
+		t.Errorf("Expected no embedding calls, got %d", embedder.calls)
+	}
+
+	embedding, err = c.strudelEmbedding(context.Background(), nil, "code", true)
+	if err != nil || len(embedding.Slice()) != 1 || embedder.calls != 1 {
+		t.Errorf("Expected one generated embedding, got %v, %v after %d calls", embedding.Slice(), err, embedder.calls)
+	}
+}
+
+type countingEmbedder struct {
+	llm.LLM
+	calls int
+}
+
+func (e *countingEmbedder) GenerateEmbedding(_ context.Context, _ string) ([]float32, error) {
+	e.calls++
+	return []float32{1}, nil
+}
@@ -1,15 +1,81 @@
 package retriever
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/database"
 	"codeberg.org/algopatterns/server/internal/llm"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// returned when a strudel has no stored embedding and may not be embedded
+var ErrNoEmbedding = errors.New("strudel has no embedding")
+
+// stores query embeddings keyed by a hash of the query text
+type EmbeddingCache interface {
+	GetEmbedding(ctx context.Context, key string) ([]float32, error) // nil on a miss
+	SetEmbedding(ctx context.Context, key string, embedding []float32) error
+}
+
+const (
+	keyEmbeddingCache = "retriever:embedding:%s:%s"
+
+	defaultEmbeddingCacheTTL = 7 * 24 * time.Hour
+)
+
+// implements EmbeddingCache in redis. keys include the embedding model, so
+// switching models never serves stale vectors
+type RedisEmbeddingCache struct {
+	client redis.UniversalClient
+	model  string
+	ttl    time.Duration
+}
+
+// scores passages by relevance to a query, higher is more relevant
+type Reranker interface {
+	Rerank(ctx context.Context, query string, passages []string) ([]float32, error)
+}
+
+const (
+	defaultRerankCandidates = 20
+	defaultRerankTimeout    = 800 * time.Millisecond
+
+	maxRerankPassageChars = 1000
 )
 
+// reranking settings, read from the environment
+type RerankConfig struct {
+	Enabled    bool          // RERANK_ENABLED
+	Candidates int           // RERANK_CANDIDATES, merged results passed to the reranker
+	Timeout    time.Duration // RERANK_TIMEOUT, latency budget before falling back to hybrid order
+	URL        string        // RERANK_URL, cross-encoder endpoint. unset reranks with the transformer model
+}
+
+// scores passages with an llm, asking for a 0-10 rating per passage
+type LLMReranker struct {
+	generator llm.TextGenerator
+}
+
+// scores passages with a cross-encoder served over HTTP (text-embeddings-inference /rerank API)
+type CrossEncoderReranker struct {
+	url        string
+	httpClient *http.Client
+}
+
 // client performs vector similarity search on documentation and examples
 type Client struct {
-	db   *pgxpool.Pool
-	llm  llm.LLM
-	topK int
+	db               *pgxpool.Pool
+	replicas         *database.Router // nil searches db
+	llm              llm.LLM
+	topK             int
+	embeddingCache   EmbeddingCache
+	reranker         Reranker
+	rerankCandidates int
+	rerankTimeout    time.Duration
 }
 
 // represents a document chunk from vector search
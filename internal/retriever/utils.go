@@ -122,7 +122,7 @@ func (c *Client) organizeByPage(ctx context.Context, chunks []SearchResult) ([]S
 // fetchSpecialChunk fetches a special chunk (PAGE_SUMMARY or PAGE_EXAMPLES) by page name
 func (c *Client) fetchSpecialChunk(ctx context.Context, pageName, sectionTitle string) (*SearchResult, error) {
 	var result SearchResult
-	err := c.db.QueryRow(ctx, fetchSpecialChunkQuery, pageName, sectionTitle).Scan(
+	err := c.reader(ctx).QueryRow(ctx, fetchSpecialChunkQuery, pageName, sectionTitle).Scan(
 		&result.ID,
 		&result.PageName,
 		&result.PageURL,
@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// connects to the worker server at RPC_SERVER_ADDR with RPC_TOKEN. returns
+// nil without an address, for workers that then wire their own providers
+func ClientFromEnv() (*Client, error) {
+	addr := os.Getenv("RPC_SERVER_ADDR")
+	if addr == "" {
+		return nil, nil
+	}
+
+	return Dial(addr, os.Getenv("RPC_TOKEN"))
+}
+
+// connects to the worker server at addr. the connection is made on the first
+// call. traffic is not encrypted, so the server must only be reachable on a
+// private network
+func Dial(addr, token string) (*Client, error) {
+	return dial(addr, token)
+}
+
+func dial(addr, token string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(tokenCredentials(token)),
+		grpc.WithDefaultCallOptions(
+			grpc.CallContentSubtype(jsonCodec{}.Name()),
+			grpc.MaxCallRecvMsgSize(maxMessageBytes),
+			grpc.MaxCallSendMsgSize(maxMessageBytes),
+		),
+	}, opts...)
+
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rpc client: %w", err)
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// the embedding model the server embeds with
+func (c *Client) EmbeddingModel(ctx context.Context) (string, error) {
+	var resp InfoResponse
+	if err := c.invoke(ctx, "Info", &InfoRequest{}, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.EmbeddingModel, nil
+}
+
+// embeds texts on the server, in batches of MaxEmbedTexts. implements llm.Embedder
+func (c *Client) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+
+	for start := 0; start < len(texts); start += MaxEmbedTexts {
+		batch := texts[start:min(start+MaxEmbedTexts, len(texts))]
+
+		var resp EmbedResponse
+		if err := c.invoke(ctx, "Embed", &EmbedRequest{Texts: batch}, &resp); err != nil {
+			return nil, err
+		}
+
+		if len(resp.Embeddings) != len(batch) {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", len(batch), len(resp.Embeddings))
+		}
+
+		embeddings = append(embeddings, resp.Embeddings...)
+	}
+
+	return embeddings, nil
+}
+
+// embeds one text on the server. implements llm.Embedder
+func (c *Client) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := c.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	return embeddings[0], nil
+}
+
+// runs a hybrid search of the docs or examples index
+func (c *Client) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	var resp SearchResponse
+	if err := c.invoke(ctx, "Search", req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// analyzes, tags and lints strudel code
+func (c *Client) Analyze(ctx context.Context, req *AnalyzeRequest) (*AnalyzeResponse, error) {
+	var resp AnalyzeResponse
+	if err := c.invoke(ctx, "Analyze", req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (c *Client) invoke(ctx context.Context, method string, req, resp any) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultCallTimeout)
+		defer cancel()
+	}
+
+	return c.conn.Invoke(ctx, fullMethod(method), req, resp)
+}
+
+// sends the shared token with every call
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{authorizationKey: "Bearer " + string(t)}, nil
+}
+
+// the token is meant for private networks without TLS
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
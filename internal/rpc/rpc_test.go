@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"codeberg.org/algopatterns/server/internal/retriever"
+)
+
+type fakeEmbedder struct {
+	err error
+}
+
+func (f *fakeEmbedder) GenerateEmbedding(_ context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text))}, f.err
+}
+
+func (f *fakeEmbedder) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i], _ = f.GenerateEmbedding(ctx, text) //nolint:errcheck // checked below
+	}
+
+	return embeddings, f.err
+}
+
+type fakeSearcher struct{}
+
+func (fakeSearcher) HybridSearchDocs(_ context.Context, query, _ string, topK int) ([]retriever.SearchResult, error) {
+	return []retriever.SearchResult{{ID: "doc-1", SectionTitle: query, Similarity: float32(topK)}}, nil
+}
+
+func (fakeSearcher) HybridSearchExamples(_ context.Context, query, _ string, _ int) ([]retriever.ExampleResult, error) {
+	return []retriever.ExampleResult{{ID: "ex-1", Title: query}}, nil
+}
+
+// a server on an in-memory listener and a client for it with token
+func startTest(t *testing.T, embedder *fakeEmbedder, token string) *Client {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(embedder, "test-embedding-model", fakeSearcher{}, "secret")
+	go server.Serve(lis) //nolint:errcheck // stopped by cleanup
+	t.Cleanup(func() { server.Stop(context.Background()) })
+
+	client, err := dial("passthrough:///bufnet", token,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck // test cleanup
+
+	return client
+}
+
+func TestEmbedRoundTrip(t *testing.T) {
+	client := startTest(t, &fakeEmbedder{}, "secret")
+	ctx := context.Background()
+
+	model, err := client.EmbeddingModel(ctx)
+	if err != nil || model != "test-embedding-model" {
+		t.Fatalf("expected the server's model, got %q (%v)", model, err)
+	}
+
+	// more texts than one call takes are batched
+	texts := make([]string, MaxEmbedTexts+3)
+	for i := range texts {
+		texts[i] = string(make([]byte, i%7))
+	}
+
+	embeddings, err := client.GenerateEmbeddings(ctx, texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(embeddings) != len(texts) {
+		t.Fatalf("expected %d embeddings, got %d", len(texts), len(embeddings))
+	}
+
+	for i, embedding := range embeddings {
+		if embedding[0] != float32(i%7) {
+			t.Fatalf("embedding %d out of order: %v", i, embedding)
+		}
+	}
+}
+
+func TestRejectsWrongToken(t *testing.T) {
+	client := startTest(t, &fakeEmbedder{}, "wrong")
+
+	_, err := client.EmbeddingModel(context.Background())
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected unauthenticated, got %v", err)
+	}
+}
+
+func TestSearchAndAnalyze(t *testing.T) {
+	client := startTest(t, &fakeEmbedder{}, "secret")
+	ctx := context.Background()
+
+	docs, err := client.Search(ctx, &SearchRequest{Query: "reverb", Index: IndexDocs, TopK: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs.Docs) != 1 || docs.Docs[0].SectionTitle != "reverb" || docs.Docs[0].Similarity != 3 {
+		t.Errorf("unexpected docs: %+v", docs.Docs)
+	}
+
+	_, err = client.Search(ctx, &SearchRequest{Query: "reverb", Index: "sessions", TopK: 3})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument for an unknown index, got %v", err)
+	}
+
+	analyzed, err := client.Analyze(ctx, &AnalyzeRequest{Code: `s("bd sd").room(0.5)`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analyzed.Analysis.LineCount != 1 || len(analyzed.Tags) == 0 {
+		t.Errorf("unexpected analysis: %+v", analyzed)
+	}
+}
+
+func TestProviderErrorsAreUnavailable(t *testing.T) {
+	client := startTest(t, &fakeEmbedder{err: errors.New("openai: 500 internal error, key sk-123")}, "secret")
+
+	_, err := client.GenerateEmbedding(context.Background(), "kick")
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected unavailable, got %v", err)
+	}
+
+	// provider details stay in the server's logs
+	if msg := status.Convert(err).Message(); msg != "failed to generate embeddings" {
+		t.Errorf("unexpected message: %q", msg)
+	}
+}
@@ -0,0 +1,170 @@
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/strudel"
+)
+
+// creates the internal worker server. every call must carry token
+func NewServer(embedder llm.Embedder, embeddingModel string, searcher Searcher, token string) *Server {
+	s := &Server{
+		embedder:       embedder,
+		embeddingModel: embeddingModel,
+		searcher:       searcher,
+		token:          token,
+	}
+
+	s.grpc = grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxMessageBytes),
+		grpc.MaxSendMsgSize(maxMessageBytes),
+		grpc.ChainUnaryInterceptor(s.recoverPanics, s.authenticate),
+	)
+	s.grpc.RegisterService(&serviceDesc, s)
+
+	return s
+}
+
+// serves calls on lis until Stop
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// stops accepting calls and waits for running calls, or cancels them when ctx ends
+func (s *Server) Stop(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpc.Stop()
+	}
+}
+
+func (s *Server) Info(_ context.Context, _ *InfoRequest) (*InfoResponse, error) {
+	return &InfoResponse{EmbeddingModel: s.embeddingModel}, nil
+}
+
+func (s *Server) Embed(ctx context.Context, req *EmbedRequest) (*EmbedResponse, error) {
+	if len(req.Texts) == 0 {
+		return &EmbedResponse{Embeddings: [][]float32{}}, nil
+	}
+
+	if len(req.Texts) > MaxEmbedTexts {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d texts can be embedded per call", MaxEmbedTexts)
+	}
+
+	embeddings, err := s.embedder.GenerateEmbeddings(ctx, req.Texts)
+	if err != nil {
+		return nil, providerError("failed to generate embeddings", err)
+	}
+
+	return &EmbedResponse{Embeddings: embeddings}, nil
+}
+
+func (s *Server) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	if req.TopK <= 0 || req.TopK > maxTopK {
+		return nil, status.Errorf(codes.InvalidArgument, "top_k must be between 1 and %d", maxTopK)
+	}
+
+	switch req.Index {
+	case IndexDocs:
+		docs, err := s.searcher.HybridSearchDocs(ctx, req.Query, req.EditorState, req.TopK)
+		if err != nil {
+			return nil, providerError("failed to search docs", err)
+		}
+		return &SearchResponse{Docs: docs}, nil
+
+	case IndexExamples:
+		examples, err := s.searcher.HybridSearchExamples(ctx, req.Query, req.EditorState, req.TopK)
+		if err != nil {
+			return nil, providerError("failed to search examples", err)
+		}
+		return &SearchResponse{Examples: examples}, nil
+
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "index must be %q or %q", IndexDocs, IndexExamples)
+	}
+}
+
+func (s *Server) Analyze(_ context.Context, req *AnalyzeRequest) (*AnalyzeResponse, error) {
+	if len(req.Code) > maxCodeBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "code must be at most %d bytes", maxCodeBytes)
+	}
+
+	analysis := strudel.AnalyzeCode(req.Code)
+	diagnostics := strudel.Lint(req.Code)
+	if diagnostics == nil {
+		diagnostics = []strudel.Diagnostic{}
+	}
+
+	return &AnalyzeResponse{
+		Analysis:    analysis,
+		Tags:        strudel.GenerateTags(analysis, req.Category, req.ExistingTags),
+		Diagnostics: diagnostics,
+	}, nil
+}
+
+// rejects calls without the shared token
+func (s *Server) authenticate(
+	ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var token string
+	if values := md.Get(authorizationKey); len(values) > 0 {
+		token = strings.TrimPrefix(values[0], "Bearer ")
+	}
+
+	if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return handler(ctx, req)
+}
+
+// turns a panicking call into an internal error instead of crashing the server
+func (s *Server) recoverPanics(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("rpc call panicked", "method", info.FullMethod, "panic", r)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// logs a failed provider or database call and hides its details from the caller
+func providerError(message string, err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, message)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, message)
+	}
+
+	logger.ErrorErr(err, message)
+	return status.Error(codes.Unavailable, message)
+}
@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// methods of the worker service, what protoc would generate from
+//
+//	service Workers {
+//	  rpc Info(InfoRequest) returns (InfoResponse);
+//	  rpc Embed(EmbedRequest) returns (EmbedResponse);
+//	  rpc Search(SearchRequest) returns (SearchResponse);
+//	  rpc Analyze(AnalyzeRequest) returns (AnalyzeResponse);
+//	}
+type workersServer interface {
+	Info(context.Context, *InfoRequest) (*InfoResponse, error)
+	Embed(context.Context, *EmbedRequest) (*EmbedResponse, error)
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*workersServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Info", Handler: unaryHandler("Info", workersServer.Info)},
+		{MethodName: "Embed", Handler: unaryHandler("Embed", workersServer.Embed)},
+		{MethodName: "Search", Handler: unaryHandler("Search", workersServer.Search)},
+		{MethodName: "Analyze", Handler: unaryHandler("Analyze", workersServer.Analyze)},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+// full method name as sent on the wire, e.g. /algopatterns.internal.v1.Workers/Embed
+func fullMethod(method string) string {
+	return "/" + ServiceName + "/" + method
+}
+
+// decodes the request of a unary method and calls it through the interceptors
+func unaryHandler[Req, Resp any](
+	method string,
+	call func(workersServer, context.Context, *Req) (*Resp, error),
+) grpc.MethodHandler {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		req := new(Req)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+
+		if interceptor == nil {
+			return call(srv.(workersServer), ctx, req)
+		}
+
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod(method)}
+		handler := func(ctx context.Context, req any) (any, error) {
+			return call(srv.(workersServer), ctx, req.(*Req))
+		}
+
+		return interceptor(ctx, req, info, handler)
+	}
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"codeberg.org/algopatterns/server/internal/llm"
+	"codeberg.org/algopatterns/server/internal/retriever"
+	"codeberg.org/algopatterns/server/internal/strudel"
+)
+
+// full name of the internal worker service
+const ServiceName = "algopatterns.internal.v1.Workers"
+
+// search indexes
+const (
+	IndexDocs     = "docs"
+	IndexExamples = "examples"
+)
+
+const (
+	// texts embedded per call
+	MaxEmbedTexts = 256
+
+	// results returned per search
+	maxTopK = 50
+
+	// largest code analyzed per call
+	maxCodeBytes = 256 * 1024
+
+	// messages carry up to MaxEmbedTexts embeddings as JSON
+	maxMessageBytes = 32 << 20
+
+	// metadata key of the shared token
+	authorizationKey = "authorization"
+
+	// how long a client waits for a call unless its context has a deadline
+	defaultCallTimeout = 2 * time.Minute
+)
+
+// searches the docs and examples indexes (implemented by retriever.Client)
+type Searcher interface {
+	HybridSearchDocs(ctx context.Context, userQuery, editorState string, topK int) ([]retriever.SearchResult, error)
+	HybridSearchExamples(ctx context.Context, userQuery, editorState string, topK int) ([]retriever.ExampleResult, error)
+}
+
+// serves embedding generation, retriever search and code analysis to worker
+// processes, so they don't need their own LLM and database wiring
+type Server struct {
+	embedder       llm.Embedder
+	embeddingModel string
+	searcher       Searcher
+	token          string
+	grpc           *grpc.Server
+}
+
+// calls a Server from a worker process
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+type InfoRequest struct{}
+
+type InfoResponse struct {
+	EmbeddingModel string `json:"embedding_model"` // stored embeddings must come from this model
+}
+
+type EmbedRequest struct {
+	Texts []string `json:"texts"`
+}
+
+type EmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"` // in the order of the texts
+}
+
+type SearchRequest struct {
+	Query       string `json:"query"`
+	EditorState string `json:"editor_state,omitempty"`
+	Index       string `json:"index"` // docs or examples
+	TopK        int    `json:"top_k"`
+}
+
+type SearchResponse struct {
+	Docs     []retriever.SearchResult  `json:"docs,omitempty"`
+	Examples []retriever.ExampleResult `json:"examples,omitempty"`
+}
+
+type AnalyzeRequest struct {
+	Code         string   `json:"code"`
+	Category     string   `json:"category,omitempty"`      // passed to tag generation
+	ExistingTags []string `json:"existing_tags,omitempty"` // kept by tag generation
+}
+
+type AnalyzeResponse struct {
+	Analysis    strudel.CodeAnalysis `json:"analysis"`
+	Tags        []string             `json:"tags"`
+	Diagnostics []strudel.Diagnostic `json:"diagnostics"`
+}
@@ -0,0 +1,86 @@
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errors
+var (
+	ErrInvalidURL     = errors.New("URL must be an absolute https URL")
+	ErrBlockedAddress = errors.New("URL points to a private or reserved address")
+)
+
+// carrier-grade NAT range (RFC 6598), not covered by netip's IsPrivate
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// checks that a user-supplied URL is an absolute https URL that doesn't name
+// a private host. hostnames are checked again when dialing, since DNS can change.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return ErrInvalidURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return ErrBlockedAddress
+	}
+
+	if ip, err := netip.ParseAddr(host); err == nil && !IsPublicAddr(ip) {
+		return ErrBlockedAddress
+	}
+
+	return nil
+}
+
+// reports whether an address is publicly routable
+func IsPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	return ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// creates a client for requests to user-supplied URLs. it refuses to connect
+// to non-public addresses (including after DNS resolution) and doesn't follow
+// redirects, so it can't be used to reach internal services.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip, err := netip.ParseAddr(host)
+			if err != nil || !IsPublicAddr(ip) {
+				return ErrBlockedAddress
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:errcheck // always an *http.Transport
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil // a proxy would connect on our behalf, bypassing the address check
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
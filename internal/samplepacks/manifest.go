@@ -0,0 +1,145 @@
+package samplepacks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"codeberg.org/algopatterns/server/internal/safehttp"
+)
+
+// names strudel code can refer to in s("...") patterns
+var soundNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// parses and validates a strudel.json sample map. values are a file, a list
+// of files, or an object of pitch to file(s). other keys starting with "_"
+// are metadata and ignored.
+func ParseManifest(data []byte) (*Manifest, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: not a JSON object", ErrInvalidManifest)
+	}
+
+	manifest := &Manifest{Sounds: make(map[string][]string)}
+
+	if base, ok := raw["_base"]; ok {
+		if err := json.Unmarshal(base, &manifest.Base); err != nil {
+			return nil, fmt.Errorf("%w: _base must be a string", ErrInvalidManifest)
+		}
+
+		if err := safehttp.ValidateURL(manifest.Base); err != nil {
+			return nil, fmt.Errorf("%w: _base: %w", ErrInvalidManifest, err)
+		}
+	}
+
+	for name, value := range raw {
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+
+		if !soundNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%w: invalid sound name %q", ErrInvalidManifest, name)
+		}
+
+		files, err := parseSoundFiles(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: sound %q: %w", ErrInvalidManifest, name, err)
+		}
+
+		manifest.Sounds[name] = files
+	}
+
+	if len(manifest.Sounds) == 0 {
+		return nil, fmt.Errorf("%w: no sounds", ErrInvalidManifest)
+	}
+
+	if len(manifest.Sounds) > maxSounds {
+		return nil, fmt.Errorf("%w: more than %d sounds", ErrInvalidManifest, maxSounds)
+	}
+
+	return manifest, nil
+}
+
+// names of the manifest's sounds, sorted
+func (m *Manifest) SoundNames() []string {
+	names := make([]string, 0, len(m.Sounds))
+	for name := range m.Sounds {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+func parseSoundFiles(value json.RawMessage) ([]string, error) {
+	var files []string
+
+	switch value = bytes.TrimSpace(value); {
+	case len(value) > 0 && value[0] == '"':
+		var file string
+		if err := json.Unmarshal(value, &file); err != nil {
+			return nil, err
+		}
+		files = []string{file}
+
+	case len(value) > 0 && value[0] == '[':
+		if err := json.Unmarshal(value, &files); err != nil {
+			return nil, fmt.Errorf("expected a list of files")
+		}
+
+	case len(value) > 0 && value[0] == '{':
+		var pitched map[string]json.RawMessage
+		if err := json.Unmarshal(value, &pitched); err != nil {
+			return nil, err
+		}
+
+		for _, v := range pitched {
+			var pitchFiles []string
+			if err := json.Unmarshal(v, &pitchFiles); err != nil {
+				var file string
+				if err := json.Unmarshal(v, &file); err != nil {
+					return nil, fmt.Errorf("expected a file or list of files per pitch")
+				}
+				pitchFiles = []string{file}
+			}
+
+			files = append(files, pitchFiles...)
+		}
+
+	default:
+		return nil, fmt.Errorf("expected a file, a list of files or an object of pitches")
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files")
+	}
+
+	if len(files) > maxFilesPerSound {
+		return nil, fmt.Errorf("more than %d files", maxFilesPerSound)
+	}
+
+	for _, file := range files {
+		if err := validateFilePath(file); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// files are paths relative to the base URL, or absolute https URLs
+func validateFilePath(file string) error {
+	if file == "" || len(file) > maxFilePathLen {
+		return fmt.Errorf("file paths must be 1-%d characters", maxFilePathLen)
+	}
+
+	if strings.Contains(file, "://") && !strings.HasPrefix(file, "https://") {
+		return fmt.Errorf("file %q must be a relative path or an https URL", file)
+	}
+
+	return nil
+}
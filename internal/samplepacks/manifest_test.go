@@ -0,0 +1,74 @@
+package samplepacks
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	data := []byte(`{
+		"_base": "https://raw.githubusercontent.com/user/pack/main/",
+		"_comment": "ignored",
+		"bd": "drums/bd.wav",
+		"hh": ["drums/hh1.wav", "drums/hh2.wav"],
+		"piano": {"a0": "piano/A0.mp3", "c4": ["piano/C4.mp3"]},
+		"remote": "https://cdn.example.com/remote.wav"
+	}`)
+
+	manifest, err := ParseManifest(data)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if manifest.Base != "https://raw.githubusercontent.com/user/pack/main/" {
+		t.Errorf("unexpected base %q", manifest.Base)
+	}
+
+	if names := manifest.SoundNames(); !slices.Equal(names, []string{"bd", "hh", "piano", "remote"}) {
+		t.Errorf("unexpected sounds %v", names)
+	}
+
+	if len(manifest.Sounds["piano"]) != 2 {
+		t.Errorf("expected 2 piano files, got %v", manifest.Sounds["piano"])
+	}
+}
+
+func TestParseManifestInvalid(t *testing.T) {
+	tests := map[string]string{
+		"not an object":   `["bd.wav"]`,
+		"no sounds":       `{"_base": "https://example.com/"}`,
+		"http base":       `{"_base": "http://example.com/", "bd": "bd.wav"}`,
+		"private base":    `{"_base": "https://127.0.0.1/", "bd": "bd.wav"}`,
+		"bad sound name":  `{"bd drum": "bd.wav"}`,
+		"empty file list": `{"bd": []}`,
+		"number value":    `{"bd": 1}`,
+		"non-https file":  `{"bd": "ftp://example.com/bd.wav"}`,
+		"bad pitch value": `{"piano": {"c4": 1}}`,
+		"empty file path": `{"bd": ""}`,
+	}
+
+	for name, data := range tests {
+		if _, err := ParseManifest([]byte(data)); !errors.Is(err, ErrInvalidManifest) {
+			t.Errorf("%s: expected ErrInvalidManifest, got %v", name, err)
+		}
+	}
+}
+
+func TestDefaultBaseURL(t *testing.T) {
+	tests := []struct {
+		manifestURL string
+		base        string
+		expected    string
+	}{
+		{"https://example.com/packs/drums/strudel.json", "", "https://example.com/packs/drums/"},
+		{"https://example.com/strudel.json?v=2", "", "https://example.com/"},
+		{"https://example.com/strudel.json", "https://cdn.example.com/drums/", "https://cdn.example.com/drums/"},
+	}
+
+	for _, tt := range tests {
+		if got := defaultBaseURL(tt.manifestURL, &Manifest{Base: tt.base}); got != tt.expected {
+			t.Errorf("defaultBaseURL(%q) = %q, expected %q", tt.manifestURL, got, tt.expected)
+		}
+	}
+}
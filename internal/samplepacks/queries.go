@@ -0,0 +1,44 @@
+package samplepacks
+
+const (
+	samplePackColumns = `id, user_id, name, description, manifest_url, base_url, license, sounds, created_at, updated_at`
+
+	queryCreateSamplePack = `
+		INSERT INTO sample_packs (user_id, name, description, manifest_url, base_url, license, sounds)
+		SELECT $1, $2, $3, $4, $5, $6, $7
+		WHERE (SELECT COUNT(*) FROM sample_packs WHERE user_id = $1) < $8
+		ON CONFLICT DO NOTHING
+		RETURNING ` + samplePackColumns
+
+	queryNameTaken = `
+		SELECT EXISTS (SELECT 1 FROM sample_packs WHERE LOWER(name) = LOWER($1))
+	`
+
+	// $1 filters by name, description or an exact sound name when not empty
+	queryListSamplePacks = `
+		SELECT ` + samplePackColumns + `, COUNT(*) OVER()
+		FROM sample_packs
+		WHERE $1 = ''
+		   OR name ILIKE '%' || $1 || '%'
+		   OR description ILIKE '%' || $1 || '%'
+		   OR $1 = ANY(sounds)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	queryGetSamplePack = `
+		SELECT ` + samplePackColumns + `
+		FROM sample_packs
+		WHERE id = $1
+	`
+
+	queryDeleteSamplePack = `
+		DELETE FROM sample_packs
+		WHERE id = $1 AND user_id = $2
+	`
+
+	queryListRegistry = `
+		SELECT name, manifest_url, sounds
+		FROM sample_packs
+	`
+)
@@ -0,0 +1,294 @@
+package samplepacks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/safehttp"
+	"codeberg.org/algopatterns/server/internal/strudel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// licenses a sample pack may be registered under
+var Licenses = []string{
+	strudels.LicenseCC0,
+	strudels.LicenseBY,
+	strudels.LicenseBYSA,
+	strudels.LicenseBYNC,
+	strudels.LicenseBYNCSA,
+	strudels.LicenseBYND,
+	strudels.LicenseBYNCND,
+}
+
+func New(db *pgxpool.Pool) *Service {
+	return &Service{
+		db:     db,
+		client: safehttp.NewClient(fetchTimeout),
+	}
+}
+
+// fetches and validates the pack's manifest, then registers the pack with
+// the sounds it provides
+func (s *Service) Create(ctx context.Context, userID string, req CreateSamplePackRequest) (*SamplePack, error) {
+	if !slices.Contains(Licenses, req.License) {
+		return nil, ErrInvalidLicense
+	}
+
+	if err := safehttp.ValidateURL(req.ManifestURL); err != nil {
+		return nil, err
+	}
+
+	if req.BaseURL != "" {
+		if err := safehttp.ValidateURL(req.BaseURL); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest, err := s.fetchManifest(ctx, req.ManifestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := req.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL(req.ManifestURL, manifest)
+	}
+
+	pack, err := scanSamplePack(s.db.QueryRow(
+		ctx,
+		queryCreateSamplePack,
+		userID,
+		req.Name,
+		req.Description,
+		req.ManifestURL,
+		baseURL,
+		req.License,
+		manifest.SoundNames(),
+		MaxPacksPerUser,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, s.createConflict(ctx, req.Name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.reloadRegistry(ctx)
+
+	return pack, nil
+}
+
+// lists packs newest first, optionally matching query against the name,
+// description or sound names
+func (s *Service) List(ctx context.Context, query string, limit, offset int) ([]*SamplePack, int, error) {
+	rows, err := s.db.Query(ctx, queryListSamplePacks, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	packs := []*SamplePack{}
+	total := 0
+
+	for rows.Next() {
+		var p SamplePack
+		err := rows.Scan(
+			&p.ID,
+			&p.UserID,
+			&p.Name,
+			&p.Description,
+			&p.ManifestURL,
+			&p.BaseURL,
+			&p.License,
+			&p.Sounds,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		packs = append(packs, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return packs, total, nil
+}
+
+func (s *Service) Get(ctx context.Context, packID string) (*SamplePack, error) {
+	pack, err := scanSamplePack(s.db.QueryRow(ctx, queryGetSamplePack, packID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPackNotFound
+	}
+
+	return pack, err
+}
+
+// deletes a pack owned by the user
+func (s *Service) Delete(ctx context.Context, packID, userID string) error {
+	result, err := s.db.Exec(ctx, queryDeleteSamplePack, packID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrPackNotFound
+	}
+
+	s.reloadRegistry(ctx)
+
+	return nil
+}
+
+// keeps the packs code analysis recognizes up to date, including packs
+// registered through other instances. runs until ctx is cancelled.
+func (s *Service) Start(ctx context.Context) {
+	s.reloadRegistry(ctx)
+
+	ticker := time.NewTicker(registryRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reloadRegistry(ctx)
+		}
+	}
+}
+
+// hands every registered pack's sounds to code analysis
+func (s *Service) reloadRegistry(ctx context.Context) {
+	packs, err := s.listRegistry(ctx)
+	if err != nil {
+		logger.Warn("failed to load sample pack registry", "error", err)
+		return
+	}
+
+	strudel.SetSamplePacks(packs)
+}
+
+func (s *Service) listRegistry(ctx context.Context) ([]strudel.SamplePack, error) {
+	rows, err := s.db.Query(ctx, queryListRegistry)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var packs []strudel.SamplePack
+
+	for rows.Next() {
+		var p strudel.SamplePack
+		if err := rows.Scan(&p.Name, &p.ManifestURL, &p.Sounds); err != nil {
+			return nil, err
+		}
+
+		packs = append(packs, p)
+	}
+
+	return packs, rows.Err()
+}
+
+// explains why a create inserted nothing: the name is taken or the user
+// reached the pack limit
+func (s *Service) createConflict(ctx context.Context, name string) error {
+	var taken bool
+	if err := s.db.QueryRow(ctx, queryNameTaken, name).Scan(&taken); err != nil {
+		return err
+	}
+
+	if taken {
+		return ErrNameTaken
+	}
+
+	return ErrTooManyPacks
+}
+
+func (s *Service) fetchManifest(ctx context.Context, manifestURL string) (*Manifest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, safehttp.ErrBlockedAddress) {
+			return nil, safehttp.ErrBlockedAddress
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrManifestUnreachable, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrManifestUnreachable, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrManifestUnreachable, err)
+	}
+
+	if len(data) > maxManifestSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrInvalidManifest, maxManifestSize)
+	}
+
+	return ParseManifest(data)
+}
+
+// strudel resolves relative files against _base, or else the manifest's directory
+func defaultBaseURL(manifestURL string, manifest *Manifest) string {
+	if manifest.Base != "" {
+		return manifest.Base
+	}
+
+	u, err := url.Parse(manifestURL)
+	if err != nil {
+		return manifestURL
+	}
+
+	u.Path = strings.TrimSuffix(path.Dir(u.Path), "/") + "/"
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String()
+}
+
+func scanSamplePack(row pgx.Row) (*SamplePack, error) {
+	var p SamplePack
+
+	err := row.Scan(
+		&p.ID,
+		&p.UserID,
+		&p.Name,
+		&p.Description,
+		&p.ManifestURL,
+		&p.BaseURL,
+		&p.License,
+		&p.Sounds,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,67 @@
+package samplepacks
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// registration constants
+const (
+	MaxPacksPerUser = 20
+
+	fetchTimeout    = 10 * time.Second
+	maxManifestSize = 1 << 20 // bytes
+
+	maxSounds        = 1000
+	maxFilesPerSound = 256
+	maxFilePathLen   = 512
+
+	// how often each instance reloads the packs analysis recognizes
+	registryRefreshInterval = 5 * time.Minute
+)
+
+// errors
+var (
+	ErrPackNotFound        = errors.New("sample pack not found")
+	ErrTooManyPacks        = errors.New("sample pack limit reached")
+	ErrNameTaken           = errors.New("a sample pack with this name already exists")
+	ErrInvalidLicense      = errors.New("unsupported sample pack license")
+	ErrManifestUnreachable = errors.New("failed to fetch the sample pack manifest")
+	ErrInvalidManifest     = errors.New("invalid strudel.json manifest")
+)
+
+type Service struct {
+	db     *pgxpool.Pool
+	client *http.Client
+}
+
+type SamplePack struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ManifestURL string    `json:"manifest_url"`
+	BaseURL     string    `json:"base_url"`
+	License     string    `json:"license"`
+	Sounds      []string  `json:"sounds"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// fields of a new sample pack
+type CreateSamplePackRequest struct {
+	Name        string
+	Description string
+	ManifestURL string
+	BaseURL     string // defaults to the manifest's _base, then the manifest's directory
+	License     string
+}
+
+// a parsed strudel.json sample map
+type Manifest struct {
+	Base   string              // the "_base" URL prefix of relative files, if any
+	Sounds map[string][]string // sound name → sample files
+}
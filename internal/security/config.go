@@ -0,0 +1,104 @@
+// Package security hardens the HTTP API for browsers and against slow or
+// oversized requests: CORS, security headers, request body limits, a guard
+// on WebSocket handshakes and which proxies may name the client's IP
+package security
+
+import (
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/config"
+)
+
+// how a route's responses may be used by the browser beyond the JSON default
+type Policy struct {
+	// may be framed by FrameAncestors, for embedded views
+	Framed bool
+
+	// HTML styled by an inline <style> block
+	InlineStyles bool
+}
+
+// holds the security middleware configuration
+type Config struct {
+	// origins allowed to call the API from a browser with credentials
+	AllowedOrigins []string
+
+	// how long browsers only reach the API over HTTPS. 0 sends no HSTS header
+	HSTSMaxAge time.Duration
+
+	// CSP sources allowed to frame routes whose policy is Framed
+	FrameAncestors []string
+
+	// per-route policies keyed by route pattern
+	Policies map[string]Policy
+
+	// body size applied to routes without a specific limit
+	DefaultBodyLimit int64
+
+	// per-route body limits keyed by "<METHOD> <route pattern>"
+	BodyLimits map[string]int64
+
+	// proxies whose X-Forwarded-For names the client. nil trusts none, so
+	// the client is the peer address
+	TrustedProxies []string
+
+	// WebSocket handshakes allowed in progress at once, per IP and in total
+	MaxHandshakesPerIP int
+	MaxHandshakes      int
+}
+
+// returns sensible defaults for local development
+func DefaultConfig() *Config {
+	code := int64(2 << 20)   // 1MB of code, which grows when JSON-escaped
+	prompt := int64(1 << 20) // the query, conversation history and editor code
+
+	return &Config{
+		AllowedOrigins: []string{"http://localhost:3000"},
+		FrameAncestors: []string{"*"},
+		Policies: map[string]Policy{
+			"/api/v1/embed/:token":            {Framed: true},
+			"/api/v1/sessions/:id/transcript": {InlineStyles: true},
+		},
+		DefaultBodyLimit: 256 << 10,
+		BodyLimits: map[string]int64{
+			"POST /api/v1/strudels":        code,
+			"PUT /api/v1/strudels/:id":     code,
+			"POST /api/v1/strudels/import": 4 << 20, // a share link can hold 2MB of encoded code
+			"POST /api/v1/sessions":        code,
+			"PUT /api/v1/sessions/:id":     code,
+
+			"POST /api/v1/agent/generate":        prompt,
+			"POST /api/v1/agent/generate/stream": prompt,
+		},
+		MaxHandshakesPerIP: 10,
+		MaxHandshakes:      500,
+	}
+}
+
+// returns the defaults with the browser-facing settings of the server's
+// configuration applied
+func FromConfig(cfg *config.Config) *Config {
+	c := DefaultConfig()
+
+	if len(cfg.CORSOrigins) > 0 {
+		c.AllowedOrigins = cfg.CORSOrigins
+	}
+
+	if len(cfg.EmbedFrameAncestors) > 0 {
+		c.FrameAncestors = cfg.EmbedFrameAncestors
+	}
+
+	c.HSTSMaxAge = cfg.HSTSMaxAge
+	c.TrustedProxies = cfg.TrustedProxies
+
+	return c
+}
+
+// returns the body limit for a matched route
+func (c *Config) BodyLimitFor(method, route string) int64 {
+	if limit, ok := c.BodyLimits[method+" "+route]; ok {
+		return limit
+	}
+
+	return c.DefaultBodyLimit
+}
@@ -0,0 +1,67 @@
+package security
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// what a preflight may ask for, and what scripts may read from responses
+const (
+	allowedMethods = "GET, POST, PUT, DELETE, PATCH, OPTIONS"
+	allowedHeaders = "Content-Type, Authorization, X-Requested-With, If-Match, If-None-Match"
+	exposedHeaders = "X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After, ETag, Link"
+)
+
+// how long browsers cache a preflight's answer
+const preflightMaxAge = 10 * 60
+
+// returns a Gin middleware that lets the configured origins call the API
+// with credentials. other origins get no CORS headers, so browsers keep
+// responses from their scripts, and their preflights are refused. requests
+// without an Origin (CLI, TUI, servers) pass untouched
+func CORS(config *Config) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(config.AllowedOrigins))
+	for _, origin := range config.AllowedOrigins {
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		header := c.Writer.Header()
+
+		// responses differ by origin, shared caches must not mix them up
+		header.Add("Vary", "Origin")
+
+		if origin != "" && allowed[origin] {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+			header.Set("Access-Control-Expose-Headers", exposedHeaders)
+		}
+
+		if c.Request.Method != http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		if isPreflight(c.Request) {
+			if !allowed[origin] {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+
+			header.Set("Access-Control-Allow-Methods", allowedMethods)
+			header.Set("Access-Control-Allow-Headers", allowedHeaders)
+			header.Set("Access-Control-Max-Age", strconv.Itoa(preflightMaxAge))
+		}
+
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// reports whether a request is a CORS preflight rather than a plain OPTIONS
+func isPreflight(r *http.Request) bool {
+	return r.Header.Get("Origin") != "" && strings.TrimSpace(r.Header.Get("Access-Control-Request-Method")) != ""
+}
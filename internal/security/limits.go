@@ -0,0 +1,96 @@
+package security
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// returns a Gin middleware capping request bodies at the route's limit.
+// a declared length over it is refused up front; bodies without one are cut
+// off while reading, failing the handler's bind
+func BodyLimit(config *Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		limit := config.BodyLimitFor(c.Request.Method, c.FullPath())
+
+		if c.Request.ContentLength > limit {
+			errors.PayloadTooLarge(c, "request body exceeds "+strconv.FormatInt(limit>>10, 10)+"KB")
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// counts WebSocket handshakes in progress
+type handshakes struct {
+	mu    sync.Mutex
+	total int
+	perIP map[string]int
+}
+
+func (h *handshakes) acquire(ip string, maxPerIP, maxTotal int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.total >= maxTotal || h.perIP[ip] >= maxPerIP {
+		return false
+	}
+
+	h.total++
+	h.perIP[ip]++
+
+	return true
+}
+
+func (h *handshakes) release(ip string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.total--
+	if h.perIP[ip]--; h.perIP[ip] <= 0 {
+		delete(h.perIP, ip)
+	}
+}
+
+// returns a Gin middleware capping the WebSocket handshakes in progress, per
+// IP and in total. the connection limits only count clients once upgraded,
+// so without it a client can hold many handshakes open in the database
+// lookups before the upgrade. the handshake's own reads are bounded by the
+// server's ReadHeaderTimeout and the upgrader's HandshakeTimeout. clients are
+// counted by the IP TrustProxies lets the engine believe
+func UpgradeGuard(config *Config) gin.HandlerFunc {
+	inFlight := &handshakes{perIP: make(map[string]int)}
+
+	return func(c *gin.Context) {
+		if !websocket.IsWebSocketUpgrade(c.Request) {
+			c.Next()
+			return
+		}
+
+		ip := c.ClientIP()
+		if !inFlight.acquire(ip, config.MaxHandshakesPerIP, config.MaxHandshakes) {
+			logger.Warn("websocket handshake refused", "ip", ip)
+			c.Header("Retry-After", "1")
+			errors.TooManyRequests(c, "too many connection attempts in progress, try again shortly")
+			c.Abort()
+			return
+		}
+		defer inFlight.release(ip)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,10 @@
+package security
+
+import "github.com/gin-gonic/gin"
+
+// sets which proxies the engine believes about the client's IP. gin trusts
+// X-Forwarded-For from any peer by default, which lets a client pick the IP
+// its rate limits, bans and handshake counts are kept under
+func TrustProxies(engine *gin.Engine, config *Config) error {
+	return engine.SetTrustedProxies(config.TrustedProxies)
+}
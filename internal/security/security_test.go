@@ -0,0 +1,285 @@
+package security
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// a router with the middleware, its POST routes fail when the body can't be read
+func newRouter(middleware gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware)
+
+	read := func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+
+	router.GET("/api/v1/strudels/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/v1/strudels", read)
+	router.POST("/api/v1/webhooks", read)
+	router.GET("/api/v1/embed/:token", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/sessions/:id/transcript", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return router
+}
+
+func serve(router http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestCORS(t *testing.T) {
+	config := DefaultConfig()
+	config.AllowedOrigins = []string{"https://algopatterns.cc", "https://staging.algopatterns.cc"}
+	router := newRouter(CORS(config))
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		preflight   bool
+		status      int
+		allowOrigin string
+	}{
+		{"allowed origin", "GET", "https://staging.algopatterns.cc", false, http.StatusOK, "https://staging.algopatterns.cc"},
+		{"other origin gets no grant", "GET", "https://evil.example", false, http.StatusOK, ""},
+		{"no origin", "GET", "", false, http.StatusOK, ""},
+		{"allowed preflight", "OPTIONS", "https://algopatterns.cc", true, http.StatusNoContent, "https://algopatterns.cc"},
+		{"other preflight refused", "OPTIONS", "https://evil.example", true, http.StatusForbidden, ""},
+		{"plain options", "OPTIONS", "", false, http.StatusNoContent, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/strudels/1", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "PUT")
+			}
+
+			w := serve(router, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			if got := w.Header().Get("Vary"); got != "Origin" {
+				t.Errorf("Vary = %q, want Origin", got)
+			}
+
+			preflightGranted := w.Header().Get("Access-Control-Allow-Methods") != ""
+			if preflightGranted != (tt.preflight && tt.allowOrigin != "") {
+				t.Errorf("Access-Control-Allow-Methods set = %v", preflightGranted)
+			}
+		})
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	config := DefaultConfig()
+	config.FrameAncestors = []string{"'self'", "https://blog.example.com"}
+	config.HSTSMaxAge = 365 * 24 * time.Hour
+	router := newRouter(Headers(config))
+
+	tests := []struct {
+		name         string
+		path         string
+		csp          string
+		frameOptions string
+	}{
+		{"json", "/api/v1/strudels/1", "default-src 'none'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'", "DENY"},
+		{"embed is framed", "/api/v1/embed/abc", "default-src 'none'; base-uri 'none'; form-action 'none'; frame-ancestors 'self' https://blog.example.com", ""},
+		{"html transcript", "/api/v1/sessions/1/transcript", "default-src 'none'; base-uri 'none'; form-action 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'", "DENY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, httptest.NewRequest("GET", tt.path, nil))
+
+			if got := w.Header().Get("Content-Security-Policy"); got != tt.csp {
+				t.Errorf("Content-Security-Policy = %q, want %q", got, tt.csp)
+			}
+			if got := w.Header().Get("X-Frame-Options"); got != tt.frameOptions {
+				t.Errorf("X-Frame-Options = %q, want %q", got, tt.frameOptions)
+			}
+			if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+			}
+			if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=31536000; includeSubDomains" {
+				t.Errorf("Strict-Transport-Security = %q", got)
+			}
+		})
+	}
+
+	config.HSTSMaxAge = 0
+	w := serve(newRouter(Headers(config)), httptest.NewRequest("GET", "/api/v1/strudels/1", nil))
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("Strict-Transport-Security = %q without a max age, want none", got)
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	config := DefaultConfig()
+	config.DefaultBodyLimit = 10
+	config.BodyLimits = map[string]int64{"POST /api/v1/strudels": 100}
+	router := newRouter(BodyLimit(config))
+
+	tests := []struct {
+		name    string
+		path    string
+		size    int
+		chunked bool
+		status  int
+	}{
+		{"within route limit", "/api/v1/strudels", 100, false, http.StatusOK},
+		{"over route limit", "/api/v1/strudels", 101, false, http.StatusRequestEntityTooLarge},
+		{"default limit", "/api/v1/webhooks", 11, false, http.StatusRequestEntityTooLarge},
+		{"undeclared length is cut off", "/api/v1/strudels", 101, true, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(strings.Repeat("a", tt.size)))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+
+			w := serve(router, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.status == http.StatusRequestEntityTooLarge && !strings.Contains(w.Body.String(), "payload_too_large") {
+				t.Errorf("body = %s, want a payload_too_large error", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpgradeGuard(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxHandshakesPerIP = 2
+	config.MaxHandshakes = 3
+
+	// handshakes stay in progress until released
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	router := gin.New()
+	router.Use(UpgradeGuard(config))
+	router.GET("/api/v1/ws", func(c *gin.Context) {
+		if c.Query("hold") != "" {
+			entered <- struct{}{}
+			<-release
+		}
+		c.Status(http.StatusOK)
+	})
+
+	handshake := func(ip string, hold bool) *http.Request {
+		path := "/api/v1/ws"
+		if hold {
+			path += "?hold=1"
+		}
+
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+
+		return req
+	}
+
+	var wg sync.WaitGroup
+	for _, ip := range []string{"10.0.0.1", "10.0.0.1", "10.0.0.2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serve(router, handshake(ip, true))
+		}()
+		<-entered
+	}
+
+	if w := serve(router, handshake("10.0.0.1", false)); w.Code != http.StatusTooManyRequests {
+		t.Errorf("third handshake from one IP: status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if w := serve(router, handshake("10.0.0.3", false)); w.Code != http.StatusTooManyRequests {
+		t.Errorf("handshake over the total: status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	// plain requests are never held back
+	if w := serve(router, httptest.NewRequest("GET", "/api/v1/ws", nil)); w.Code != http.StatusOK {
+		t.Errorf("plain request: status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if w := serve(router, handshake("10.0.0.1", false)); w.Code != http.StatusOK {
+		t.Errorf("handshake after the others finished: status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestUpgradeGuardIgnoresSpoofedForwardedFor(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxHandshakesPerIP = 1
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	router := gin.New()
+	if err := TrustProxies(router, config); err != nil {
+		t.Fatalf("TrustProxies() error = %v", err)
+	}
+	router.Use(UpgradeGuard(config))
+	router.GET("/api/v1/ws", func(c *gin.Context) {
+		if c.Query("hold") != "" {
+			entered <- struct{}{}
+			<-release
+		}
+		c.Status(http.StatusOK)
+	})
+
+	// each handshake claims to come from another client
+	handshake := func(forwardedFor, path string) *http.Request {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+
+		return req
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serve(router, handshake("198.51.100.1", "/api/v1/ws?hold=1"))
+	}()
+	<-entered
+
+	if w := serve(router, handshake("198.51.100.2", "/api/v1/ws")); w.Code != http.StatusTooManyRequests {
+		t.Errorf("handshake with a spoofed X-Forwarded-For: status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	close(release)
+	<-done
+}
@@ -0,0 +1,51 @@
+package stats
+
+const (
+	queryCountActiveSessions = `
+		SELECT COUNT(*)::INT
+		FROM sessions
+		WHERE is_active = true AND last_activity >= NOW() - make_interval(secs => $1)
+	`
+
+	// $1 is the window in seconds strudels count as recently published in
+	queryCountPublicStrudels = `
+		SELECT
+			COUNT(*)::INT,
+			(COUNT(*) FILTER (WHERE published_at >= NOW() - make_interval(secs => $1)))::INT
+		FROM user_strudels
+		WHERE is_public = true AND deleted_at IS NULL
+	`
+
+	queryTopTags = `
+		SELECT LOWER(tag), COUNT(*)::INT AS strudels
+		FROM user_strudels, unnest(tags) AS tag
+		WHERE is_public = true AND deleted_at IS NULL
+		GROUP BY LOWER(tag)
+		HAVING COUNT(*) >= $1
+		ORDER BY strudels DESC, LOWER(tag)
+		LIMIT $2
+	`
+
+	queryCountGenerations = `
+		SELECT COUNT(*)::INT FROM usage_logs
+	`
+
+	// platform generations logged without a cost are priced from their tokens
+	queryUsageByModel = `
+		SELECT
+			provider,
+			model,
+			COUNT(*)::INT,
+			(COUNT(*) FILTER (WHERE is_byok))::INT,
+			COALESCE(SUM(estimated_cost_usd) FILTER (WHERE is_byok IS NOT TRUE), 0)::FLOAT8,
+			(COUNT(*) FILTER (WHERE is_byok IS NOT TRUE AND estimated_cost_usd IS NULL))::INT,
+			COALESCE(SUM(input_tokens) FILTER (WHERE is_byok IS NOT TRUE AND estimated_cost_usd IS NULL), 0)::BIGINT,
+			COALESCE(SUM(output_tokens) FILTER (WHERE is_byok IS NOT TRUE AND estimated_cost_usd IS NULL), 0)::BIGINT,
+			COALESCE(SUM(input_tokens), 0)::BIGINT,
+			COALESCE(SUM(output_tokens), 0)::BIGINT
+		FROM usage_logs
+		WHERE created_at >= $1
+		GROUP BY provider, model
+		ORDER BY COUNT(*) DESC, provider, model
+	`
+)
@@ -0,0 +1,144 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/database"
+	"codeberg.org/algopatterns/server/internal/llm"
+)
+
+func New(db *pgxpool.Pool) *Service {
+	return &Service{db: db}
+}
+
+// sends the stats queries to read replicas
+func (s *Service) SetReadReplicas(replicas *database.Router) {
+	s.replicas = replicas
+}
+
+// the pool for a lag-tolerant read
+func (s *Service) reader(ctx context.Context) *pgxpool.Pool {
+	if s.replicas == nil {
+		return s.db
+	}
+
+	return s.replicas.Reader(ctx)
+}
+
+// returns the public platform stats, computed at most once per cache period
+// on each instance
+func (s *Service) Public(ctx context.Context) (*PublicStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.public != nil && time.Since(s.publicCached) < publicCacheTTL {
+		return s.public, nil
+	}
+
+	public, err := s.queryPublic(ctx)
+	if err != nil {
+		// stale numbers beat an empty stats page
+		if s.public != nil {
+			return s.public, nil
+		}
+
+		return nil, err
+	}
+
+	s.public = public
+	s.publicCached = time.Now()
+
+	return public, nil
+}
+
+func (s *Service) queryPublic(ctx context.Context) (*PublicStats, error) {
+	stats := PublicStats{TopTags: []TagCount{}, GeneratedAt: time.Now()}
+	db := s.reader(ctx)
+
+	if err := db.QueryRow(ctx, queryCountActiveSessions, activeSessionWindow.Seconds()).Scan(&stats.ActiveSessions); err != nil {
+		return nil, fmt.Errorf("failed to count active sessions: %w", err)
+	}
+
+	err := db.QueryRow(ctx, queryCountPublicStrudels, publishedWindow.Seconds()).Scan(&stats.PublicStrudels, &stats.StrudelsPublishedWeek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count public strudels: %w", err)
+	}
+
+	if err := db.QueryRow(ctx, queryCountGenerations).Scan(&stats.TotalAIGenerations); err != nil {
+		return nil, fmt.Errorf("failed to count AI generations: %w", err)
+	}
+
+	rows, err := db.Query(ctx, queryTopTags, minTagStrudels, topTagsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top tags: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, err
+		}
+		stats.TopTags = append(stats.TopTags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// returns AI usage per provider and model since the given time, with the
+// estimated cost of generations made with platform keys
+func (s *Service) Usage(ctx context.Context, since time.Time) (*UsageStats, error) {
+	rows, err := s.reader(ctx).Query(ctx, queryUsageByModel, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+
+	defer rows.Close()
+	usage := UsageStats{Since: since, Providers: []ProviderUsage{}}
+
+	for rows.Next() {
+		var p ProviderUsage
+		var unpriced int
+		var unpricedInput, unpricedOutput int64
+
+		err := rows.Scan(
+			&p.Provider,
+			&p.Model,
+			&p.Generations,
+			&p.BYOKGenerations,
+			&p.EstimatedCostUSD,
+			&unpriced,
+			&unpricedInput,
+			&unpricedOutput,
+			&p.InputTokens,
+			&p.OutputTokens,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		p.PricingKnown = true
+		if unpriced > 0 {
+			cost, known := llm.EstimateCost(p.Provider, p.Model, unpricedInput, unpricedOutput)
+			p.EstimatedCostUSD += cost
+			p.PricingKnown = known
+		}
+
+		usage.Generations += p.Generations
+		usage.InputTokens += p.InputTokens
+		usage.OutputTokens += p.OutputTokens
+		usage.EstimatedCostUSD += p.EstimatedCostUSD
+		usage.Providers = append(usage.Providers, p)
+	}
+
+	return &usage, rows.Err()
+}
@@ -0,0 +1,76 @@
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/database"
+)
+
+const (
+	// how long public stats are reused before they are queried again
+	publicCacheTTL = 5 * time.Minute
+
+	// tags listed in public stats
+	topTagsLimit = 10
+
+	// tags on fewer public strudels are left out of public stats, so rare
+	// tags don't point at their authors
+	minTagStrudels = 3
+
+	// sessions idle for longer don't count as active
+	activeSessionWindow = 30 * time.Minute
+
+	// strudels published within this long count as published this week
+	publishedWindow = 7 * 24 * time.Hour
+)
+
+type Service struct {
+	db       *pgxpool.Pool
+	replicas *database.Router // nil reads everything from db
+
+	// public stats and when they were computed
+	mu           sync.Mutex
+	public       *PublicStats
+	publicCached time.Time
+}
+
+// anonymized platform stats for the public stats page
+type PublicStats struct {
+	ActiveSessions        int        `json:"active_sessions"`
+	StrudelsPublishedWeek int        `json:"strudels_published_this_week"`
+	PublicStrudels        int        `json:"public_strudels"`
+	TopTags               []TagCount `json:"top_tags"`
+	TotalAIGenerations    int        `json:"total_ai_generations"`
+	GeneratedAt           time.Time  `json:"generated_at"`
+}
+
+// how many public strudels carry a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// AI usage since a point in time, with costs of platform keys
+type UsageStats struct {
+	Since            time.Time       `json:"since"`
+	Generations      int             `json:"generations"`
+	InputTokens      int64           `json:"input_tokens"`
+	OutputTokens     int64           `json:"output_tokens"`
+	EstimatedCostUSD float64         `json:"estimated_cost_usd"` // platform keys only, BYOK is paid by the user
+	Providers        []ProviderUsage `json:"providers"`
+}
+
+// AI usage of one provider and model
+type ProviderUsage struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Generations      int     `json:"generations"`
+	BYOKGenerations  int     `json:"byok_generations"` // made with the user's own key
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	PricingKnown     bool    `json:"pricing_known"` // false when the model has no price, its cost counts as 0
+}
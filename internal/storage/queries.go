@@ -9,3 +9,66 @@ const (
 		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 )
+
+// re-embedding. strudels are only re-embedded while they still permit AI use
+const (
+	countDocEmbeddingsQuery = "SELECT COUNT(*) FROM doc_embeddings"
+
+	listDocEmbeddingSourcesQuery = `
+		SELECT id, content
+		FROM doc_embeddings
+		WHERE $1::uuid IS NULL OR id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	updateDocEmbeddingQuery = "UPDATE doc_embeddings SET embedding = $1 WHERE id = $2"
+
+	reembeddableStrudelsFilter = `
+		FROM user_strudels us
+		INNER JOIN users u ON us.user_id = u.id
+		WHERE us.embedding IS NOT NULL
+		  AND us.cc_signal IS NOT NULL
+		  AND us.cc_signal != 'no-ai'
+		  AND us.is_public = true
+		  AND u.training_consent = true
+	`
+
+	countStrudelEmbeddingsQuery = "SELECT COUNT(*) " + reembeddableStrudelsFilter
+
+	listStrudelEmbeddingSourcesQuery = `
+		SELECT us.id, concat_ws(E'\n\n', us.title, us.description, us.code)
+	` + reembeddableStrudelsFilter + `
+		  AND ($1::uuid IS NULL OR us.id > $1)
+		ORDER BY us.id
+		LIMIT $2
+	`
+
+	updateStrudelEmbeddingQuery = "UPDATE user_strudels SET embedding = $1 WHERE id = $2"
+
+	getReembedJobQuery = `
+		SELECT target, model, last_id, processed, total, started_at, completed_at
+		FROM reembed_jobs
+		WHERE target = $1 AND model = $2
+	`
+
+	startReembedJobQuery = `
+		INSERT INTO reembed_jobs (target, model, total)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (target, model) DO UPDATE
+		SET last_id = NULL, processed = 0, total = $3, started_at = NOW(), updated_at = NOW(), completed_at = NULL
+		RETURNING target, model, last_id, processed, total, started_at, completed_at
+	`
+
+	updateReembedProgressQuery = `
+		UPDATE reembed_jobs
+		SET last_id = $3, processed = $4, updated_at = NOW()
+		WHERE target = $1 AND model = $2
+	`
+
+	completeReembedJobQuery = `
+		UPDATE reembed_jobs
+		SET completed_at = NOW(), updated_at = NOW()
+		WHERE target = $1 AND model = $2
+	`
+)
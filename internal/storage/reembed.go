@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/pgvector/pgvector-go"
+)
+
+// per-target queries for counting, listing and updating embedding rows
+type reembedQuerySet struct {
+	count, list, update string
+}
+
+var reembedQueries = map[string]reembedQuerySet{
+	ReembedTargetDocs:     {countDocEmbeddingsQuery, listDocEmbeddingSourcesQuery, updateDocEmbeddingQuery},
+	ReembedTargetStrudels: {countStrudelEmbeddingsQuery, listStrudelEmbeddingSourcesQuery, updateStrudelEmbeddingQuery},
+}
+
+func queriesFor(target string) (reembedQuerySet, error) {
+	queries, ok := reembedQueries[target]
+	if !ok {
+		return queries, fmt.Errorf("unknown re-embed target: %s", target)
+	}
+
+	return queries, nil
+}
+
+// returns the number of rows a re-embed of target covers
+func (c *Client) CountEmbeddingSources(ctx context.Context, target string) (int, error) {
+	queries, err := queriesFor(target)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := c.pool.QueryRow(ctx, queries.count).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", target, err)
+	}
+
+	return count, nil
+}
+
+// returns the next batch of rows to re-embed, in id order after afterID
+func (c *Client) ListEmbeddingSources(ctx context.Context, target string, afterID *string, limit int) ([]EmbeddingSource, error) {
+	queries, err := queriesFor(target)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.pool.Query(ctx, queries.list, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", target, err)
+	}
+
+	defer rows.Close()
+	var sources []EmbeddingSource
+
+	for rows.Next() {
+		var source EmbeddingSource
+		if err := rows.Scan(&source.ID, &source.Text); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", target, err)
+		}
+
+		sources = append(sources, source)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %s rows: %w", target, err)
+	}
+
+	return sources, nil
+}
+
+// returns the run of target with model, or nil if there hasn't been one
+func (c *Client) GetReembedJob(ctx context.Context, target, model string) (*ReembedJob, error) {
+	job, err := scanReembedJob(c.pool.QueryRow(ctx, getReembedJobQuery, target, model))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get re-embed job: %w", err)
+	}
+
+	return job, nil
+}
+
+// starts (or restarts) a run of target with model from the first row
+func (c *Client) StartReembedJob(ctx context.Context, target, model string, total int) (*ReembedJob, error) {
+	job, err := scanReembedJob(c.pool.QueryRow(ctx, startReembedJobQuery, target, model, total))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start re-embed job: %w", err)
+	}
+
+	return job, nil
+}
+
+// stores a batch of new embeddings and advances the job's cursor in the same
+// transaction, so a crash never skips or double-counts rows
+func (c *Client) SaveReembedBatch(ctx context.Context, job *ReembedJob, sources []EmbeddingSource, embeddings [][]float32) error {
+	if len(sources) != len(embeddings) {
+		return fmt.Errorf("sources and embeddings length mismatch")
+	}
+
+	if len(sources) == 0 {
+		return nil
+	}
+
+	queries, err := queriesFor(job.Target)
+	if err != nil {
+		return err
+	}
+
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// defer rollback - will be no-op if commit succeeds
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.Warn("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	lastID := sources[len(sources)-1].ID
+	processed := job.Processed + len(sources)
+
+	batch := &pgx.Batch{}
+	for i, source := range sources {
+		batch.Queue(queries.update, pgvector.NewVector(embeddings[i]), source.ID)
+	}
+	batch.Queue(updateReembedProgressQuery, job.Target, job.Model, lastID, processed)
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to update embeddings: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	job.LastID = &lastID
+	job.Processed = processed
+
+	return nil
+}
+
+// marks a run as finished
+func (c *Client) CompleteReembedJob(ctx context.Context, job *ReembedJob) error {
+	if _, err := c.pool.Exec(ctx, completeReembedJobQuery, job.Target, job.Model); err != nil {
+		return fmt.Errorf("failed to complete re-embed job: %w", err)
+	}
+
+	return nil
+}
+
+func scanReembedJob(row pgx.Row) (*ReembedJob, error) {
+	var job ReembedJob
+
+	err := row.Scan(
+		&job.Target,
+		&job.Model,
+		&job.LastID,
+		&job.Processed,
+		&job.Total,
+		&job.StartedAt,
+		&job.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,26 @@
+package storage
+
+import "time"
+
+// tables whose embeddings can be regenerated
+const (
+	ReembedTargetDocs     = "docs"
+	ReembedTargetStrudels = "strudels"
+)
+
+// progress of re-embedding one target with one model
+type ReembedJob struct {
+	Target      string
+	Model       string
+	LastID      *string // resume cursor, nil before the first batch
+	Processed   int
+	Total       int
+	StartedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// a stored row and the text its embedding is generated from
+type EmbeddingSource struct {
+	ID   string
+	Text string
+}
@@ -1,8 +1,11 @@
 package strudel
 
 import (
+	"maps"
+	"math"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -168,6 +171,52 @@ func buildEffectLookup() map[string]string {
 // effect categorization lookup
 var effectCategories = buildEffectLookup()
 
+// instrument names for sounds, matched exactly
+var instrumentSounds = map[string]string{
+	"bd": "kick", "sd": "snare", "rim": "rimshot", "cp": "clap",
+	"hh": "hi-hat", "oh": "hi-hat", "cr": "cymbal", "rd": "cymbal",
+	"ht": "tom", "mt": "tom", "lt": "tom",
+	"sh": "shaker", "cb": "cowbell", "tb": "tambourine", "perc": "percussion",
+	"sine": "synth", "sawtooth": "synth", "square": "synth", "triangle": "synth",
+	"supersaw": "synth", "pulse": "synth",
+	"white": "noise", "pink": "noise", "brown": "noise", "crackle": "noise",
+}
+
+// instrument names for sounds containing a keyword (e.g. gm_acoustic_bass)
+var instrumentKeywords = []struct {
+	keyword    string
+	instrument string
+}{
+	{"piano", "piano"},
+	{"rhodes", "piano"},
+	{"bass", "bass"},
+	{"guitar", "guitar"},
+	{"violin", "strings"},
+	{"cello", "strings"},
+	{"string", "strings"},
+	{"organ", "organ"},
+	{"flute", "flute"},
+	{"trumpet", "brass"},
+	{"brass", "brass"},
+	{"sax", "saxophone"},
+	{"choir", "vocals"},
+	{"voice", "vocals"},
+	{"vibraphone", "mallets"},
+	{"marimba", "mallets"},
+	{"bell", "bells"},
+}
+
+var (
+	// global tempo: setcpm(120/4), setcps(0.5), or a pattern's .cpm(30)
+	tempoPattern = regexp.MustCompile(`(setcpm|setcps|\.cpm)\s*\(\s*(\d*\.?\d+)\s*(?:([*/])\s*(\d*\.?\d+)\s*)?\)`)
+
+	// numeric speed changes: .fast(2), .slow(0.5)
+	speedPattern = regexp.MustCompile(`\.(fast|slow)\s*\(\s*(\d*\.?\d+)\s*\)`)
+
+	// a note pattern without an explicit sound plays strudel's default triangle synth
+	noteCallPattern = regexp.MustCompile(`\b(?:note|n)\s*\(`)
+)
+
 // performs full semantic analysis on Strudel code
 func AnalyzeCode(code string) CodeAnalysis {
 	parsed := Parse(code)
@@ -177,12 +226,19 @@ func AnalyzeCode(code string) CodeAnalysis {
 		EffectTags:     analyzeEffects(parsed),
 		MusicalTags:    analyzeMusicalElements(code, parsed),
 		ComplexityTags: analyzeComplexityTags(code, parsed),
+		Instruments:    analyzeInstruments(code, parsed),
+		SamplePacks:    analyzeSamplePacks(code, parsed),
 		Complexity:     calculateComplexity(code, parsed),
+		EstimatedBPM:   estimateBPM(code),
 		LineCount:      strings.Count(code, "\n") + 1,
 		FunctionCount:  len(parsed.Functions),
 		VariableCount:  len(parsed.Variables),
 	}
 
+	if len(analysis.SamplePacks) > 0 {
+		analysis.SoundTags = append(analysis.SoundTags, "sample-pack")
+	}
+
 	return analysis
 }
 
@@ -346,6 +402,115 @@ func calculateComplexity(code string, parsed ParsedCode) int {
 	return score
 }
 
+// maps sounds to the instruments they represent, sorted by name
+func analyzeInstruments(code string, parsed ParsedCode) []string {
+	instruments := make(map[string]bool)
+
+	for _, sound := range parsed.Sounds {
+		if instrument, ok := instrumentSounds[sound]; ok {
+			instruments[instrument] = true
+			continue
+		}
+
+		if strings.HasPrefix(sound, "wt_") || strings.HasPrefix(sound, "z_") {
+			instruments["synth"] = true
+			continue
+		}
+
+		name := strings.ToLower(sound)
+		for _, kw := range instrumentKeywords {
+			if strings.Contains(name, kw.keyword) {
+				instruments[kw.instrument] = true
+				break
+			}
+		}
+	}
+
+	if len(parsed.Sounds) == 0 && noteCallPattern.MatchString(code) {
+		instruments["synth"] = true
+	}
+
+	return slices.Sorted(maps.Keys(instruments))
+}
+
+// estimates the tempo in beats per minute from setcpm/setcps and numeric
+// fast/slow calls. returns 0 when the estimate falls outside a plausible range.
+func estimateBPM(code string) float64 {
+	cpm := float64(defaultCPM)
+
+	// the last tempo call wins, as it does when strudel evaluates the code
+	if matches := tempoPattern.FindAllStringSubmatch(code, -1); len(matches) > 0 {
+		match := matches[len(matches)-1]
+
+		value, ok := evalTempoExpr(match[2], match[3], match[4])
+		if !ok {
+			return 0
+		}
+
+		if match[1] == "setcps" {
+			value *= 60
+		}
+
+		cpm = value
+	}
+
+	// a single speed factor used throughout the code is treated as a tempo
+	// change; mixed factors are per-layer rhythm and leave the tempo alone
+	factors := make(map[float64]bool)
+	for _, match := range speedPattern.FindAllStringSubmatch(code, -1) {
+		factor, err := strconv.ParseFloat(match[2], 64)
+		if err != nil || factor == 0 {
+			continue
+		}
+
+		if match[1] == "slow" {
+			factor = 1 / factor
+		}
+
+		factors[factor] = true
+	}
+
+	if len(factors) == 1 {
+		for factor := range factors {
+			cpm *= factor
+		}
+	}
+
+	bpm := math.Round(cpm*beatsPerCycle*10) / 10
+	if bpm < minBPM || bpm > maxBPM {
+		return 0
+	}
+
+	return bpm
+}
+
+// evaluates a tempo argument of the form "a", "a/b" or "a*b"
+func evalTempoExpr(left, operator, right string) (float64, bool) {
+	a, err := strconv.ParseFloat(left, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if operator == "" {
+		return a, true
+	}
+
+	b, err := strconv.ParseFloat(right, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if operator == "*" {
+		return a * b, true
+	}
+
+	if b == 0 {
+		return 0, false
+	}
+
+	return a / b, true
+}
+
 // combines analysis with existing metadata to create final tag list
 func GenerateTags(analysis CodeAnalysis, category string, existingTags []string) []string {
 	tags := make(map[string]bool)
@@ -204,3 +204,52 @@ func TestCalculateComplexity(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzeInstruments(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected []string
+	}{
+		{"drum kit", `s("bd*2, hh*4, ~ sd")`, []string{"hi-hat", "kick", "snare"}},
+		{"general midi sounds", `note("c2 e2").s("gm_acoustic_bass")`, []string{"bass"}},
+		{"wavetables and waveforms", `s("wt_flute sawtooth")`, []string{"synth"}},
+		{"note without sound", `note("c e g")`, []string{"synth"}},
+		{"unknown sound", `s("mystery")`, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instruments := AnalyzeCode(tt.code).Instruments
+
+			if strings.Join(instruments, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Instruments = %v, expected %v", instruments, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEstimateBPM(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected float64
+	}{
+		{"default tempo", `s("bd sd")`, 120},
+		{"setcpm expression", "setcpm(140/4)\ns(\"bd sd\")", 140},
+		{"setcps", "setcps(0.6)\ns(\"bd sd\")", 144},
+		{"last tempo call wins", "setcpm(30)\nsetcpm(25)", 100},
+		{"single fast factor", `s("bd sd").fast(2)`, 240},
+		{"single slow factor", "setcpm(35)\nnote(\"c e\").slow(2)", 70},
+		{"mixed factors are ignored", "setcpm(30)\nstack(s(\"bd\").fast(2), s(\"hh\").fast(4))", 120},
+		{"implausible tempo", "setcpm(500)", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if bpm := estimateBPM(tt.code); bpm != tt.expected {
+				t.Errorf("estimateBPM() = %v, expected %v", bpm, tt.expected)
+			}
+		})
+	}
+}
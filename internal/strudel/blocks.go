@@ -0,0 +1,44 @@
+package strudel
+
+import (
+	"regexp"
+	"strings"
+)
+
+// the label starting a pattern block such as "drums: s("bd sd")". a leading
+// underscore mutes the block in the REPL, "$:" blocks are anonymous. labels
+// start at the beginning of a line, so indented object keys don't match
+var blockLabelPattern = regexp.MustCompile(`^_?([A-Za-z_$][\w$]*)\s*:`)
+
+// finds the lines of the pattern block with the given label. the block runs
+// from its label to the line before the next label, without trailing blank
+// lines. lines are 1-based and inclusive.
+func FindBlock(code, name string) (startLine, endLine int, ok bool) {
+	if name == "" || name == "$" {
+		return 0, 0, false
+	}
+
+	lines := strings.Split(code, "\n")
+
+	for i, line := range lines {
+		match := blockLabelPattern.FindStringSubmatch(line)
+		if match == nil || match[1] != name {
+			continue
+		}
+
+		end := i
+		for j := i + 1; j < len(lines); j++ {
+			if blockLabelPattern.MatchString(lines[j]) {
+				break
+			}
+
+			if strings.TrimSpace(lines[j]) != "" {
+				end = j
+			}
+		}
+
+		return i + 1, end + 1, true
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,37 @@
+package strudel
+
+import "testing"
+
+func TestFindBlock(t *testing.T) {
+	code := `setcps(0.5)
+
+drums: s("bd sd")
+  .bank("tr909")
+
+_bass: note("c2 eb2")
+  .s("sawtooth")
+$: s("hh*8")`
+
+	tests := []struct {
+		name      string
+		block     string
+		wantStart int
+		wantEnd   int
+		wantOK    bool
+	}{
+		{"labeled block with continuation", "drums", 3, 4, true},
+		{"muted block", "bass", 6, 7, true},
+		{"anonymous blocks have no name", "$", 0, 0, false},
+		{"unknown block", "keys", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := FindBlock(code, tt.block)
+			if start != tt.wantStart || end != tt.wantEnd || ok != tt.wantOK {
+				t.Errorf("FindBlock(%q) = %d, %d, %v, want %d, %d, %v",
+					tt.block, start, end, ok, tt.wantStart, tt.wantEnd, tt.wantOK)
+			}
+		})
+	}
+}
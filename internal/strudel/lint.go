@@ -0,0 +1,621 @@
+package strudel
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maximum number of diagnostics reported for one piece of code
+const maxDiagnostics = 50
+
+var (
+	// identifiers followed by a call: fast(, .lpf (
+	callPattern = regexp.MustCompile(`[A-Za-z_$][\w$]*\s*\(`)
+
+	// user declarations: const pat = ..., function build(...)
+	declarationPattern = regexp.MustCompile(`(?:let|const|var|function)\s+([A-Za-z_$][\w$]*)`)
+
+	// custom functions: register('swingit', ...)
+	registerPattern = regexp.MustCompile("register\\s*\\(\\s*[\"'`]([\\w$]+)")
+
+	// calls that build a pattern from their arguments: stack(), cat()
+	emptyCombinatorPattern = regexp.MustCompile(`(?:^|[^\w$])(stack|cat|seq|fastcat|slowcat|sequence|polymeter|arrange|layer|timeCat)\s*\(\s*\)`)
+
+	// stack( as a function or method call
+	stackPattern = regexp.MustCompile(`(?:^|[^\w$])(stack)\s*\(`)
+
+	// the call a pattern argument starts with: note("c e"), s("bd")
+	leadingCallPattern = regexp.MustCompile(`^([A-Za-z_$][\w$]*)\s*\(`)
+)
+
+// core Strudel functions and pattern methods (effects are in effectDefs)
+var patternFunctions = []string{
+	// pattern sources
+	"s", "sound", "n", "note", "freq", "chord", "mini", "m", "pure", "silence", "run",
+	"stack", "cat", "seq", "fastcat", "slowcat", "sequence", "timeCat", "polymeter", "polymeterSteps",
+	"arrange", "layer", "superimpose", "samples", "soundAlias", "register", "slider",
+	"irand", "rand", "perlin", "sine", "cosine", "saw", "isaw", "square", "tri", "choose",
+	"chooseCycles", "wchoose", "wchooseCycles", "randcat", "wrandcat", "pick", "pickRestart",
+	"pickReset", "inhabit", "squeeze", "mouseX", "mouseY",
+
+	// tempo and setup
+	"setcps", "setCps", "setcpm", "setCpm", "cps", "cpm", "hush", "initAudioOnFirstClick",
+	"aliasBank", "loadCsound", "initHydra", "H",
+
+	// time
+	"fast", "slow", "hurry", "early", "late", "rev", "palindrome", "iter", "iterBack", "ply",
+	"segment", "struct", "mask", "euclid", "euclidRot", "euclidLegato", "euclidLegatoRot",
+	"swing", "swingBy", "inside", "outside", "zoom", "compress", "fastGap", "focus", "linger",
+	"ribbon", "chunk", "chunkBack", "fastChunk", "brak", "press", "pressBy", "off", "echoWith",
+	"stut", "stutWith", "invert", "inv", "ratio", "range", "rangex", "range2", "round",
+	"floor", "ceil", "toBipolar", "fromBipolar", "add", "sub", "mul", "div", "mod", "pow",
+	"set", "keep", "keepif", "fmap", "withValue", "apply", "as", "x", "y", "nudge",
+
+	// conditions and randomness
+	"every", "firstOf", "lastOf", "when", "whenmod", "while", "sometimes", "sometimesBy",
+	"often", "rarely", "almostNever", "almostAlways", "always", "never", "someCycles",
+	"someCyclesBy", "degrade", "degradeBy", "undegrade", "undegradeBy", "jux", "juxBy",
+	"seed", "shuffle", "scramble", "bite",
+
+	// tonal
+	"scale", "mode", "scaleTranspose", "transpose", "voicing", "voicings", "rootNotes",
+	"dict", "anchor", "octave", "up", "arp", "arpWith", "octaves",
+
+	// controls not covered by effectDefs
+	"bank", "legato", "unit", "dry", "size", "sz", "cutoff", "ctf", "lp", "hcutoff", "hp",
+	"bandf", "bp", "resonance", "hresonance", "bandq", "delayfb", "delayt", "dt", "dfb",
+	"vel", "channel", "accelerate", "color", "colour", "midi", "midichan", "ccn", "ccv",
+	"osc", "sysex", "duration", "dur", "hold", "density", "waveloss", "squiz", "djf", "lsize",
+
+	// visuals and debugging
+	"log", "logValues", "scope", "tscope", "fscope", "pianoroll", "punchcard", "spiral",
+	"spectrum", "wordfall", "markcss", "hydra", "analyze", "_scope", "_pianoroll",
+	"_punchcard", "_spiral", "_spectrum",
+}
+
+// common javascript methods and globals callable in Strudel code
+var jsFunctions = []string{
+	"map", "filter", "reduce", "forEach", "join", "split", "slice", "concat", "includes",
+	"indexOf", "push", "pop", "shift", "fill", "from", "keys", "values", "entries", "flat",
+	"flatMap", "find", "some", "sort", "reverse", "toString", "toFixed", "replace", "trim",
+	"repeat", "padStart", "then", "catch", "finally", "call", "bind", "parseInt",
+	"parseFloat", "fetch", "setTimeout", "setInterval", "clearInterval", "Array", "Object",
+	"String", "Number", "Boolean", "Promise", "isNaN", "require", "eval",
+}
+
+// receivers whose methods are plain javascript (Math.floor, console.log)
+var jsGlobals = []string{"Math", "JSON", "Object", "Array", "console", "Number", "String", "Date", "Promise"}
+
+// keywords that look like calls: if (...), for (...)
+var jsKeywords = []string{
+	"if", "for", "while", "switch", "catch", "function", "return", "typeof", "new", "await",
+	"async", "do", "with", "else", "yield", "super", "import", "export", "delete", "void",
+	"in", "of", "instanceof",
+}
+
+// pattern sources that play pitches rather than samples
+var melodicSources = []string{"note", "n", "chord", "freq"}
+
+// functions whose first argument is parsed as mini-notation
+var miniNotationFunctions = []string{"s", "sound", "n", "note", "chord", "mini", "m", "seq", "cat", "bank", "vowel", "struct", "mask"}
+
+// sounds loaded by default besides soundDefs; prefixes end with "_"
+var defaultSounds = []string{
+	"piano", "supersaw", "pulse", "sbd", "bytebeat", "casio", "jazz", "metal", "east", "crow",
+	"insect", "wind", "jvbass", "numbers", "space", "arpy", "sax", "gtr", "ocarina", "gm_", "z_",
+}
+
+// a quoted string or template literal in the code
+type stringLiteral struct {
+	start      int // offset of the opening quote
+	end        int // offset of the closing quote, or where the unterminated literal stops
+	quote      byte
+	terminated bool
+}
+
+// an opening delimiter waiting for its match
+type openDelimiter struct {
+	char   byte
+	offset int
+}
+
+var closingDelimiters = map[byte]byte{')': '(', ']': '[', '}': '{'}
+
+// checks Strudel code for syntax-level problems without evaluating it:
+// unbalanced delimiters, unterminated strings and comments, broken
+// mini-notation, empty patterns, and functions or sounds that are not part
+// of the known definitions. unknown names are warnings since code can load
+// samples and define functions at runtime.
+func Lint(code string) []Diagnostic {
+	l := &linter{code: code, lineStarts: lineStarts(code)}
+
+	masked, literals := l.scan()
+	declared := declaredNames(code, masked)
+
+	l.checkLiterals(masked, literals)
+	l.checkEmptyCombinators(masked)
+	l.checkMixedStacks(masked)
+	l.checkCalls(masked, declared)
+	l.checkSounds(masked, literals)
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		a, b := l.diagnostics[i], l.diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	if len(l.diagnostics) > maxDiagnostics {
+		l.diagnostics = l.diagnostics[:maxDiagnostics]
+	}
+
+	return l.diagnostics
+}
+
+// returns true if any diagnostic is an error
+func HasLintErrors(diagnostics []Diagnostic) bool {
+	return slices.ContainsFunc(diagnostics, func(d Diagnostic) bool {
+		return d.Severity == SeverityError
+	})
+}
+
+type linter struct {
+	code        string
+	lineStarts  []int
+	diagnostics []Diagnostic
+}
+
+func (l *linter) report(offset int, severity, rule, message string) {
+	line, column := l.position(offset)
+
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Severity: severity,
+		Rule:     rule,
+		Message:  message,
+		Line:     line,
+		Column:   column,
+	})
+}
+
+// walks the code once, matching delimiters and collecting string literals.
+// returns a copy of the code with comments and string contents blanked out
+// (offsets preserved) so later checks only see code.
+func (l *linter) scan() (string, []stringLiteral) {
+	code := l.code
+	masked := []byte(code)
+	var literals []stringLiteral
+	var open []openDelimiter
+
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+
+		switch {
+		case c == '/' && strings.HasPrefix(code[i:], "//"):
+			end := strings.IndexByte(code[i:], '\n')
+			if end < 0 {
+				end = len(code)
+			} else {
+				end += i
+			}
+
+			blank(masked, i, end)
+			i = end - 1
+
+		case c == '/' && strings.HasPrefix(code[i:], "/*"):
+			end := strings.Index(code[i+2:], "*/")
+			if end < 0 {
+				l.report(i, SeverityError, RuleUnterminatedComment, "unterminated block comment")
+				blank(masked, i, len(code))
+				i = len(code)
+				continue
+			}
+
+			end += i + 4
+			blank(masked, i, end)
+			i = end - 1
+
+		case c == '"' || c == '\'' || c == '`':
+			end, terminated := closingQuote(code, i)
+			literals = append(literals, stringLiteral{start: i, end: end, quote: c, terminated: terminated})
+			blank(masked, i+1, end)
+
+			if !terminated {
+				if c == '`' {
+					l.report(i, SeverityError, RuleUnterminatedString, "unterminated template literal (missing closing backtick)")
+				} else {
+					l.report(i, SeverityError, RuleUnterminatedString, fmt.Sprintf("unterminated string (missing closing %c)", c))
+				}
+			}
+
+			i = end
+
+		case c == '(' || c == '[' || c == '{':
+			open = append(open, openDelimiter{char: c, offset: i})
+
+		case c == ')' || c == ']' || c == '}':
+			if len(open) == 0 {
+				l.report(i, SeverityError, RuleUnbalancedDelimiter, fmt.Sprintf("unexpected '%c'", c))
+				continue
+			}
+
+			last := open[len(open)-1]
+			open = open[:len(open)-1]
+
+			if last.char != closingDelimiters[c] {
+				line, column := l.position(last.offset)
+				l.report(i, SeverityError, RuleUnbalancedDelimiter,
+					fmt.Sprintf("'%c' does not match '%c' opened at line %d, column %d", c, last.char, line, column))
+			}
+		}
+	}
+
+	for _, delimiter := range open {
+		l.report(delimiter.offset, SeverityError, RuleUnbalancedDelimiter, fmt.Sprintf("unclosed '%c'", delimiter.char))
+	}
+
+	return string(masked), literals
+}
+
+// returns the 1-based line and column of an offset
+func (l *linter) position(offset int) (int, int) {
+	line := sort.Search(len(l.lineStarts), func(i int) bool { return l.lineStarts[i] > offset })
+	return line, utf8.RuneCountInString(l.code[l.lineStarts[line-1]:offset]) + 1
+}
+
+// checks mini-notation brackets and empty patterns inside string arguments
+func (l *linter) checkLiterals(masked string, literals []stringLiteral) {
+	for _, literal := range literals {
+		// already reported, its content is unreliable
+		if !literal.terminated {
+			continue
+		}
+
+		content := l.code[literal.start+1 : literal.end]
+		callee, isArgument := calleeOf(masked, literal.start)
+
+		// backtick patterns are chained directly: `c e g`.note()
+		chained := literal.quote == '`' && strings.HasPrefix(strings.TrimLeft(masked[min(literal.end+1, len(masked)):], " \t\n"), ".")
+
+		if !isArgument && !chained {
+			continue
+		}
+
+		if strings.TrimSpace(content) == "" && (chained || slices.Contains(miniNotationFunctions, callee)) {
+			l.report(literal.start, SeverityWarning, RuleEmptyPattern, "empty pattern produces no events")
+			continue
+		}
+
+		l.checkMiniNotation(literal.start+1, content)
+	}
+}
+
+// reports unbalanced [], <>, {} and () inside a mini-notation string
+func (l *linter) checkMiniNotation(offset int, content string) {
+	pairs := map[byte]byte{']': '[', '>': '<', '}': '{', ')': '('}
+	var open []openDelimiter
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+
+		switch c {
+		case '[', '<', '{', '(':
+			open = append(open, openDelimiter{char: c, offset: offset + i})
+
+		case ']', '>', '}', ')':
+			if len(open) == 0 || open[len(open)-1].char != pairs[c] {
+				l.report(offset+i, SeverityError, RuleUnbalancedMiniNotation, fmt.Sprintf("unexpected '%c' in mini-notation", c))
+				return
+			}
+
+			open = open[:len(open)-1]
+		}
+	}
+
+	if len(open) > 0 {
+		first := open[0]
+		l.report(first.offset, SeverityError, RuleUnbalancedMiniNotation, fmt.Sprintf("unclosed '%c' in mini-notation", first.char))
+	}
+}
+
+// reports combinators called without any patterns: stack()
+func (l *linter) checkEmptyCombinators(masked string) {
+	for _, match := range emptyCombinatorPattern.FindAllStringSubmatchIndex(masked, -1) {
+		name := masked[match[2]:match[3]]
+		l.report(match[2], SeverityWarning, RuleEmptyPattern, fmt.Sprintf("%s() without patterns produces no events", name))
+	}
+}
+
+// reports stacks that layer drum samples with notes. effects chained onto
+// the stack, like .bank(), then apply to both and break one of them
+func (l *linter) checkMixedStacks(masked string) {
+	for _, match := range stackPattern.FindAllStringSubmatchIndex(masked, -1) {
+		open := match[1] - 1
+
+		args, ok := callArguments(masked, open)
+		if !ok {
+			continue
+		}
+
+		var drums, melodic bool
+		for _, arg := range args {
+			source := ""
+			if call := leadingCallPattern.FindStringSubmatch(strings.TrimSpace(arg)); call != nil {
+				source = call[1]
+			}
+
+			switch {
+			case slices.Contains(melodicSources, source) || strings.Contains(arg, ".note("):
+				melodic = true
+			case source == "s" || source == "sound":
+				drums = true
+			}
+		}
+
+		if drums && melodic {
+			l.report(match[2], SeverityWarning, RuleMixedStack, "stack() mixes drum samples with notes, keep drums and synths in separate $: patterns")
+		}
+	}
+}
+
+// splits the arguments of the call whose '(' is at open on top-level
+// commas. returns false if the call is never closed
+func callArguments(masked string, open int) ([]string, bool) {
+	var args []string
+	depth := 0
+	start := open + 1
+
+	for i := open; i < len(masked); i++ {
+		switch masked[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return append(args, masked[start:i]), true
+			}
+		case ',':
+			if depth == 1 {
+				args = append(args, masked[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// reports calls to functions that are neither Strudel functions, effects,
+// javascript built-ins nor declared in the code
+func (l *linter) checkCalls(masked string, declared map[string]bool) {
+	reported := make(map[string]bool)
+
+	for _, match := range callPattern.FindAllStringIndex(masked, -1) {
+		start := match[0]
+		name := strings.TrimRight(masked[start:match[1]-1], " \t\n")
+
+		// part of a number literal (1e3) or a longer identifier
+		if start > 0 && isIdentifierByte(masked[start-1]) {
+			continue
+		}
+
+		before := strings.TrimRight(masked[:start], " \t\n")
+		isMethod := strings.HasSuffix(before, ".")
+
+		if isMethod && slices.Contains(jsGlobals, trailingIdentifier(strings.TrimSuffix(before, "."))) {
+			continue
+		}
+
+		if !isMethod && slices.Contains(jsKeywords, name) {
+			continue
+		}
+
+		if reported[name] || isKnownFunction(name) || declared[name] {
+			continue
+		}
+
+		reported[name] = true
+
+		message := fmt.Sprintf("unknown function %q", name)
+		if suggestion := suggestFunction(name); suggestion != "" {
+			message += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+
+		l.report(start, SeverityWarning, RuleUnknownFunction, message)
+	}
+}
+
+// reports sample names missing from the sound definitions. skipped when the
+// code loads its own samples or selects a bank, since any name may exist then.
+func (l *linter) checkSounds(masked string, literals []stringLiteral) {
+	if strings.Contains(masked, "samples(") || strings.Contains(masked, "bank(") || strings.Contains(masked, "soundAlias(") {
+		return
+	}
+
+	reported := make(map[string]bool)
+
+	for _, literal := range literals {
+		callee, isArgument := calleeOf(masked, literal.start)
+		if !literal.terminated || !isArgument || (callee != "s" && callee != "sound") {
+			continue
+		}
+
+		content := l.code[literal.start+1 : literal.end]
+
+		for _, sound := range parsePatternString(content) {
+			// mini-notation operators such as _ (elongation) aren't sounds
+			if !strings.ContainsFunc(sound, unicode.IsLetter) {
+				continue
+			}
+
+			if reported[sound] || isKnownSound(sound) {
+				continue
+			}
+
+			reported[sound] = true
+			offset := literal.start + 1 + max(strings.Index(content, sound), 0)
+			l.report(offset, SeverityWarning, RuleUnknownSound, fmt.Sprintf("unknown sound %q", sound))
+		}
+	}
+}
+
+// returns the function a literal is the first argument of: s("bd") → "s"
+func calleeOf(masked string, literalStart int) (string, bool) {
+	before := strings.TrimRight(masked[:literalStart], " \t\n")
+	if !strings.HasSuffix(before, "(") {
+		return "", strings.HasSuffix(before, ",")
+	}
+
+	return trailingIdentifier(strings.TrimRight(before[:len(before)-1], " \t\n")), true
+}
+
+// returns the identifier at the end of s, if any
+func trailingIdentifier(s string) string {
+	i := len(s)
+	for i > 0 && isIdentifierByte(s[i-1]) {
+		i--
+	}
+
+	return s[i:]
+}
+
+// collects names the code declares or registers itself
+func declaredNames(code, masked string) map[string]bool {
+	declared := make(map[string]bool)
+
+	for _, match := range declarationPattern.FindAllStringSubmatch(masked, -1) {
+		declared[match[1]] = true
+	}
+
+	for _, match := range registerPattern.FindAllStringSubmatch(code, -1) {
+		declared[match[1]] = true
+	}
+
+	return declared
+}
+
+func isKnownFunction(name string) bool {
+	if _, isEffect := effectCategories[name]; isEffect {
+		return true
+	}
+
+	return slices.Contains(patternFunctions, name) || slices.Contains(jsFunctions, name)
+}
+
+func isKnownSound(sound string) bool {
+	for _, sounds := range soundCategories {
+		if slices.Contains(sounds, sound) {
+			return true
+		}
+	}
+
+	for _, known := range append(defaultSounds, soundDefs.Wavetable...) {
+		if sound == known || (strings.HasSuffix(known, "_") && strings.HasPrefix(sound, known)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returns the closest known function, allowing one edit for short names
+// and two for longer ones
+func suggestFunction(name string) string {
+	best, bestDistance := "", 2
+	if len(name) > 4 {
+		bestDistance = 3
+	}
+
+	candidates := slices.Concat(patternFunctions, slices.Sorted(maps.Keys(effectCategories)))
+	for _, candidate := range candidates {
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	return best
+}
+
+// edit distance counting insertions, deletions, substitutions and swaps of
+// adjacent characters (typos like "fsat") as one edit each
+func editDistance(a, b string) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return rows[len(a)][len(b)]
+}
+
+// returns the offset of the closing quote of the literal opening at start.
+// quotes and double quotes end at a newline, backticks may span lines.
+func closingQuote(code string, start int) (int, bool) {
+	quote := code[start]
+
+	for i := start + 1; i < len(code); i++ {
+		switch code[i] {
+		case '\\':
+			i++
+		case quote:
+			return i, true
+		case '\n':
+			if quote != '`' {
+				return i, false
+			}
+		}
+	}
+
+	return len(code), false
+}
+
+// replaces bytes in [start, end) with spaces, keeping newlines
+func blank(masked []byte, start, end int) {
+	for i := start; i < end && i < len(masked); i++ {
+		if masked[i] != '\n' {
+			masked[i] = ' '
+		}
+	}
+}
+
+// returns the offset each line starts at
+func lineStarts(code string) []int {
+	starts := []int{0}
+
+	for i := 0; i < len(code); i++ {
+		if code[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+
+	return starts
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,168 @@
+package strudel
+
+import (
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected []Diagnostic
+	}{
+		{
+			name: "valid code from docs",
+			code: "note(\"c2 <eb2 <g2 g1>>\".fast(2))\n  .sound(\"<sawtooth square triangle sine>\")\n  .lpf(800).room(.5)._scope()",
+		},
+		{
+			name: "comments and strings are ignored",
+			code: "// stack((\nconst label = \")\"\ns(\"bd*2, hh\") /* ] */ .gain(.8)\nconsole.log('done')",
+		},
+		{
+			name: "declared and registered functions",
+			code: "const swing = register('swingit', (p) => p.late(.1))\nfunction drums() { return s(\"bd sd\") }\ndrums().swingit()",
+		},
+		{
+			name: "unclosed paren",
+			code: "stack(\n  s(\"bd sd\"),\n  note(\"c e g\")",
+			expected: []Diagnostic{
+				{Severity: SeverityError, Rule: RuleUnbalancedDelimiter, Message: "unclosed '('", Line: 1, Column: 6},
+			},
+		},
+		{
+			name: "mismatched closer",
+			code: "stack(s(\"bd\"]",
+			expected: []Diagnostic{
+				{Severity: SeverityError, Rule: RuleUnbalancedDelimiter, Message: "unclosed '('", Line: 1, Column: 6},
+				{Severity: SeverityError, Rule: RuleUnbalancedDelimiter, Message: "']' does not match '(' opened at line 1, column 8", Line: 1, Column: 13},
+			},
+		},
+		{
+			name: "unexpected closer",
+			code: "s(\"bd\"))",
+			expected: []Diagnostic{
+				{Severity: SeverityError, Rule: RuleUnbalancedDelimiter, Message: "unexpected ')'", Line: 1, Column: 8},
+			},
+		},
+		{
+			name: "unterminated backtick",
+			code: "note(`c e g)\n.fast(2)",
+			expected: []Diagnostic{
+				{Severity: SeverityError, Rule: RuleUnbalancedDelimiter, Message: "unclosed '('", Line: 1, Column: 5},
+				{Severity: SeverityError, Rule: RuleUnterminatedString, Message: "unterminated template literal (missing closing backtick)", Line: 1, Column: 6},
+			},
+		},
+		{
+			name: "unterminated string ends at newline",
+			code: "s(\"bd sd)\ns(\"hh\")",
+			expected: []Diagnostic{
+				{Severity: SeverityError, Rule: RuleUnbalancedDelimiter, Message: "unclosed '('", Line: 1, Column: 2},
+				{Severity: SeverityError, Rule: RuleUnterminatedString, Message: "unterminated string (missing closing \")", Line: 1, Column: 3},
+			},
+		},
+		{
+			name: "unterminated block comment",
+			code: "s(\"bd\") /* todo",
+			expected: []Diagnostic{
+				{Severity: SeverityError, Rule: RuleUnterminatedComment, Message: "unterminated block comment", Line: 1, Column: 9},
+			},
+		},
+		{
+			name: "unbalanced mini-notation",
+			code: "s(\"bd [hh hh\")",
+			expected: []Diagnostic{
+				{Severity: SeverityError, Rule: RuleUnbalancedMiniNotation, Message: "unclosed '[' in mini-notation", Line: 1, Column: 7},
+			},
+		},
+		{
+			name: "empty patterns",
+			code: "s(\"\")\nstack()\n`  `.note()",
+			expected: []Diagnostic{
+				{Severity: SeverityWarning, Rule: RuleEmptyPattern, Message: "empty pattern produces no events", Line: 1, Column: 3},
+				{Severity: SeverityWarning, Rule: RuleEmptyPattern, Message: "stack() without patterns produces no events", Line: 2, Column: 1},
+				{Severity: SeverityWarning, Rule: RuleEmptyPattern, Message: "empty pattern produces no events", Line: 3, Column: 1},
+			},
+		},
+		{
+			name: "unknown function with suggestion",
+			code: "s(\"bd sd\").fsat(2).fsat(4)",
+			expected: []Diagnostic{
+				{Severity: SeverityWarning, Rule: RuleUnknownFunction, Message: "unknown function \"fsat\", did you mean \"fast\"?", Line: 1, Column: 12},
+			},
+		},
+		{
+			name: "unknown sound",
+			code: "s(\"bd kick\")",
+			expected: []Diagnostic{
+				{Severity: SeverityWarning, Rule: RuleUnknownSound, Message: "unknown sound \"kick\"", Line: 1, Column: 7},
+			},
+		},
+		{
+			name: "sounds are not checked when a bank is selected",
+			code: "s(\"kick snare\").bank(\"RolandTR909\")",
+		},
+		{
+			name: "stack mixing drums and notes",
+			code: "stack(\n  s(\"bd*4\"),\n  note(\"c1, e1\").sound(\"sawtooth\")\n).bank(\"RolandTR909\")",
+			expected: []Diagnostic{
+				{Severity: SeverityWarning, Rule: RuleMixedStack, Message: "stack() mixes drum samples with notes, keep drums and synths in separate $: patterns", Line: 1, Column: 1},
+			},
+		},
+		{
+			name: "stacks of one kind are allowed",
+			code: "stack(s(\"bd*4\"), sound(\"hh*8\").gain(.5))\nstack(note(\"c e\"), n(\"0 2\").scale(\"C:minor\"))",
+		},
+		{
+			name: "columns count characters",
+			code: "// ♪\ns(\"bd\").lpf(200).wobble()",
+			expected: []Diagnostic{
+				{Severity: SeverityWarning, Rule: RuleUnknownFunction, Message: "unknown function \"wobble\"", Line: 2, Column: 18},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := Lint(tt.code)
+
+			if len(diagnostics) != len(tt.expected) {
+				t.Fatalf("Lint() returned %d diagnostics, expected %d: %+v", len(diagnostics), len(tt.expected), diagnostics)
+			}
+
+			for i, expected := range tt.expected {
+				if diagnostics[i] != expected {
+					t.Errorf("diagnostic %d = %+v, expected %+v", i, diagnostics[i], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestHasLintErrors(t *testing.T) {
+	if HasLintErrors(Lint(`s("bd").fsat(2)`)) {
+		t.Error("warnings should not count as errors")
+	}
+
+	if !HasLintErrors(Lint(`s("bd"`)) {
+		t.Error("unclosed paren should count as an error")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"fast", "fast", 0},
+		{"fsat", "fast", 1},
+		{"slo", "slow", 1},
+		{"lpf", "hpf", 1},
+		{"room", "delay", 5},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
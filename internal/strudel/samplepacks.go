@@ -0,0 +1,103 @@
+package strudel
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// samples('<url>') calls loading a sample map, including github: shorthands
+var samplesCallPattern = regexp.MustCompile("\\bsamples\\s*\\(\\s*['\"`]([^'\"`]+)['\"`]")
+
+// sounds and manifests of the registered community sample packs, swapped
+// wholesale by SetSamplePacks so analysis never sees a partial update
+var samplePackRegistry atomic.Pointer[samplePackIndex]
+
+type samplePackIndex struct {
+	bySound map[string][]string // sound name → pack names
+	byURL   map[string]string   // manifest URL → pack name
+}
+
+// replaces the registered sample packs analysis recognizes
+func SetSamplePacks(packs []SamplePack) {
+	index := &samplePackIndex{
+		bySound: make(map[string][]string),
+		byURL:   make(map[string]string, len(packs)),
+	}
+
+	for _, pack := range packs {
+		index.byURL[normalizeSamplesURL(pack.ManifestURL)] = pack.Name
+
+		for _, sound := range pack.Sounds {
+			index.bySound[sound] = append(index.bySound[sound], pack.Name)
+		}
+	}
+
+	samplePackRegistry.Store(index)
+}
+
+// names of registered packs the code uses: packs whose manifest it loads with
+// samples(), and packs providing a sound that isn't built in. built-in names
+// like "bd" are too common across packs to credit any one of them.
+func analyzeSamplePacks(code string, parsed ParsedCode) []string {
+	index := samplePackRegistry.Load()
+	if index == nil {
+		return nil
+	}
+
+	packs := make(map[string]bool)
+
+	for _, match := range samplesCallPattern.FindAllStringSubmatch(code, -1) {
+		if name, ok := index.byURL[normalizeSamplesURL(match[1])]; ok {
+			packs[name] = true
+		}
+	}
+
+	for _, sound := range parsed.Sounds {
+		if isBuiltinSound(sound) {
+			continue
+		}
+
+		for _, name := range index.bySound[sound] {
+			packs[name] = true
+		}
+	}
+
+	names := mapKeysToSlice(packs)
+	slices.Sort(names)
+
+	return names
+}
+
+func isBuiltinSound(sound string) bool {
+	if strings.HasPrefix(sound, "wt_") {
+		return true
+	}
+
+	for _, sounds := range soundCategories {
+		if contains(sounds, sound) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expands strudel's github:user/repo[/branch] shorthand to the strudel.json
+// it loads, so shorthands match registered manifest URLs
+func normalizeSamplesURL(url string) string {
+	url = strings.TrimSuffix(strings.TrimSpace(url), "/")
+
+	path, ok := strings.CutPrefix(url, "github:")
+	if !ok {
+		return url
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) == 2 {
+		parts = append(parts, "main")
+	}
+
+	return "https://raw.githubusercontent.com/" + strings.Join(parts, "/") + "/strudel.json"
+}
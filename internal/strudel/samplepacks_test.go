@@ -0,0 +1,54 @@
+package strudel
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAnalyzeCode_SamplePacks(t *testing.T) {
+	SetSamplePacks([]SamplePack{
+		{Name: "dirt-samples", ManifestURL: "https://raw.githubusercontent.com/tidalcycles/dirt-samples/main/strudel.json", Sounds: []string{"bd", "casio", "jvbass"}},
+		{Name: "tape-loops", ManifestURL: "https://example.com/loops/strudel.json", Sounds: []string{"bd", "loop"}},
+	})
+	t.Cleanup(func() { SetSamplePacks(nil) })
+
+	tests := []struct {
+		name     string
+		code     string
+		expected []string
+	}{
+		{"pack sound", `s("casio*2 ~ jvbass")`, []string{"dirt-samples"}},
+		{"built-in sound is not credited", `s("bd*4")`, []string{}},
+		{"loaded manifest", `samples('https://example.com/loops/strudel.json')` + "\n" + `s("bd")`, []string{"tape-loops"}},
+		{"github shorthand", `samples('github:tidalcycles/dirt-samples')`, []string{"dirt-samples"}},
+		{"unregistered sound", `s("mystery")`, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeCode(tt.code)
+
+			if !slices.Equal(analysis.SamplePacks, tt.expected) {
+				t.Errorf("Expected SamplePacks %v, got: %v", tt.expected, analysis.SamplePacks)
+			}
+
+			if tagged := contains(analysis.SoundTags, "sample-pack"); tagged != (len(tt.expected) > 0) {
+				t.Errorf("Expected 'sample-pack' in SoundTags to be %v, got: %v", len(tt.expected) > 0, analysis.SoundTags)
+			}
+		})
+	}
+}
+
+func TestNormalizeSamplesURL(t *testing.T) {
+	tests := map[string]string{
+		"github:tidalcycles/dirt-samples":         "https://raw.githubusercontent.com/tidalcycles/dirt-samples/main/strudel.json",
+		"github:user/repo/dev":                    "https://raw.githubusercontent.com/user/repo/dev/strudel.json",
+		" https://example.com/pack/strudel.json ": "https://example.com/pack/strudel.json",
+	}
+
+	for input, expected := range tests {
+		if got := normalizeSamplesURL(input); got != expected {
+			t.Errorf("normalizeSamplesURL(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package strudel
+
+import (
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// hosts serving the Strudel REPL
+var shareLinkHosts = map[string]bool{
+	"strudel.cc":     true,
+	"www.strudel.cc": true,
+}
+
+// "// @title name" metadata comment at the top of shared Strudel code
+var titleCommentPattern = regexp.MustCompile(`(?m)^\s*//\s*@title\s+(.+?)\s*$`)
+
+// decodes the code of a strudel.cc share link. accepts a full link
+// (https://strudel.cc/#<hash>) or the hash alone. the REPL encodes code as
+// URI-escaped base64 of its UTF-8 bytes. short links (https://strudel.cc/?<id>)
+// point to the REPL's own database and can't be decoded.
+func DecodeShareLink(link string) (string, error) {
+	link = strings.TrimSpace(link)
+	hash := link
+
+	if strings.Contains(link, "://") {
+		u, err := url.Parse(link)
+		if err != nil || !shareLinkHosts[strings.ToLower(u.Hostname())] {
+			return "", ErrInvalidShareLink
+		}
+
+		if u.Fragment == "" {
+			if u.RawQuery != "" {
+				return "", ErrShortShareLink
+			}
+			return "", ErrInvalidShareLink
+		}
+
+		hash = u.Fragment
+	} else {
+		unescaped, err := url.PathUnescape(strings.TrimPrefix(link, "#"))
+		if err != nil {
+			return "", ErrInvalidShareLink
+		}
+
+		hash = unescaped
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(hash)
+	if err != nil {
+		// some clients drop the padding when copying links
+		decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(hash, "="))
+	}
+
+	if err != nil || len(decoded) == 0 || !utf8.Valid(decoded) {
+		return "", ErrInvalidShareLink
+	}
+
+	return string(decoded), nil
+}
+
+// returns the title from a "// @title" comment in the code, if any
+func ParseTitle(code string) string {
+	match := titleCommentPattern.FindStringSubmatch(code)
+	if match == nil {
+		return ""
+	}
+
+	// "@title name @by author" puts further metadata on the same line
+	title, _, _ := strings.Cut(match[1], " @")
+	return strings.TrimSpace(title)
+}
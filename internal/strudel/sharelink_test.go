@@ -0,0 +1,60 @@
+package strudel
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestDecodeShareLink(t *testing.T) {
+	code := "// @title héllo\nsound(\"bd sd\")"
+	hash := url.QueryEscape(base64.StdEncoding.EncodeToString([]byte(code)))
+
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+		err      error
+	}{
+		{"full link", "https://strudel.cc/#" + hash, code, nil},
+		{"www host", "https://www.strudel.cc/#" + hash, code, nil},
+		{"hash", hash, code, nil},
+		{"hash with marker", "#" + hash, code, nil},
+		{"unpadded hash", base64.RawStdEncoding.EncodeToString([]byte("s(\"bd\")")), "s(\"bd\")", nil},
+		{"short link", "https://strudel.cc/?xwWRfuCE8TAR", "", ErrShortShareLink},
+		{"other host", "https://example.com/#" + hash, "", ErrInvalidShareLink},
+		{"not base64", "not a hash!", "", ErrInvalidShareLink},
+		{"empty", "", "", ErrInvalidShareLink},
+		{"binary", base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe}), "", ErrInvalidShareLink},
+	}
+
+	for _, tt := range tests {
+		got, err := DecodeShareLink(tt.link)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: DecodeShareLink() error = %v, expected %v", tt.name, err, tt.err)
+			continue
+		}
+
+		if got != tt.expected {
+			t.Errorf("%s: DecodeShareLink() = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestParseTitle(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{"// @title acid rain\nnote(\"c\")", "acid rain"},
+		{"// \"intro\" @by someone\n// @title  night bus  @by someone\ns(\"bd\")", "night bus"},
+		{"s(\"bd\") // no title", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTitle(tt.code); got != tt.expected {
+			t.Errorf("ParseTitle(%q) = %q, expected %q", tt.code, got, tt.expected)
+		}
+	}
+}
@@ -1,18 +1,36 @@
 package strudel
 
+import "errors"
+
 type CodeAnalysis struct {
 	SoundTags      []string // ["drums", "synth", "bass"]
 	EffectTags     []string // ["delay", "reverb", "filter"]
 	MusicalTags    []string // ["melody", "chords", "rhythm"]
 	ComplexityTags []string // ["layered", "advanced", "simple"]
+	Instruments    []string // ["kick", "hi-hat", "synth"]
+	SamplePacks    []string // registered sample packs used: ["dirt-samples"]
 
 	// metrics
-	Complexity    int // 0-10 score
+	Complexity    int     // 0-10 score
+	EstimatedBPM  float64 // 0 when the tempo can't be estimated
 	LineCount     int
 	FunctionCount int
 	VariableCount int
 }
 
+// share link errors
+var (
+	ErrInvalidShareLink = errors.New("not a strudel.cc share link or code hash")
+	ErrShortShareLink   = errors.New("strudel.cc short links can't be imported, open the link and use the code hash link (#...) instead")
+)
+
+// a registered community sample pack, as known to analysis
+type SamplePack struct {
+	Name        string
+	ManifestURL string
+	Sounds      []string
+}
+
 type SoundDefinitions struct {
 	Drums      []string
 	Percussion []string
@@ -60,3 +78,38 @@ type KeywordOptions struct {
 	IncludeScales    bool // include scale names (default: true)
 	Deduplicate      bool // remove duplicates (default: true)
 }
+
+// tempo estimation constants
+const (
+	defaultCPM    = 30 // strudel's default tempo (0.5 cycles per second)
+	beatsPerCycle = 4  // setcpm(120/4) is the idiom for 120 bpm
+	minBPM        = 20
+	maxBPM        = 400
+)
+
+// a problem found while linting Strudel code
+type Diagnostic struct {
+	Severity string `json:"severity"` // "error" | "warning"
+	Rule     string `json:"rule"`     // e.g. "unbalanced_delimiter", "unknown_function"
+	Message  string `json:"message"`
+	Line     int    `json:"line"`   // 1-based
+	Column   int    `json:"column"` // 1-based, in characters
+}
+
+// diagnostic severities
+const (
+	SeverityError   = "error"   // the code will fail to evaluate
+	SeverityWarning = "warning" // the code evaluates but likely doesn't do what was intended
+)
+
+// diagnostic rules
+const (
+	RuleUnbalancedDelimiter    = "unbalanced_delimiter"
+	RuleUnterminatedString     = "unterminated_string"
+	RuleUnterminatedComment    = "unterminated_comment"
+	RuleUnbalancedMiniNotation = "unbalanced_mini_notation"
+	RuleEmptyPattern           = "empty_pattern"
+	RuleUnknownFunction        = "unknown_function"
+	RuleUnknownSound           = "unknown_sound"
+	RuleMixedStack             = "mixed_stack"
+)
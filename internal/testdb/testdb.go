@@ -0,0 +1,119 @@
+// Package testdb connects integration tests to a throwaway Postgres with
+// pgvector, migrated to the latest schema. the tests using it are built with
+// -tags integration; make test-integration starts the database and runs them
+package testdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/migrate"
+	"codeberg.org/algopatterns/server/supabase/migrations"
+)
+
+// the variable holding the database's connection string
+const EnvURL = "TEST_DATABASE_URL"
+
+// what supabase sets up before any migration runs: pgvector lives in the
+// extensions schema, which is on the search path
+const queryBootstrap = `
+	CREATE SCHEMA IF NOT EXISTS extensions;
+	CREATE EXTENSION IF NOT EXISTS vector WITH SCHEMA extensions;
+`
+
+// the schema is migrated once per test binary
+var (
+	migrateOnce sync.Once
+	migrateErr  error
+)
+
+// connects to the database at TEST_DATABASE_URL, applying pending
+// migrations first. the pool is closed when the test ends. a missing database
+// fails the test rather than skipping it, since integration tests only build
+// when asked for
+func Open(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv(EnvURL)
+	if url == "" {
+		t.Fatalf("%s is not set, run integration tests with make test-integration", EnvURL)
+	}
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("invalid %s: %v", EnvURL, err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = `"$user", public, extensions`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	db, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+	t.Cleanup(db.Close)
+
+	migrateOnce.Do(func() {
+		migrateErr = migrateUp(ctx, db)
+	})
+	if migrateErr != nil {
+		t.Fatalf("failed to migrate the test database: %v", migrateErr)
+	}
+
+	return db
+}
+
+func migrateUp(ctx context.Context, db *pgxpool.Pool) error {
+	if _, err := db.Exec(ctx, queryBootstrap); err != nil {
+		return err
+	}
+
+	loaded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	_, err = migrate.New(db, loaded).Up(ctx)
+	return err
+}
+
+// inserts a user signed in with a provider of its own, so tests sharing the
+// database never see each other's users. returns its id
+func SeedUser(t testing.TB, db *pgxpool.Pool, name string) string {
+	t.Helper()
+
+	providerID := Unique(t)
+
+	var id string
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO users (provider, provider_id, email, name)
+		VALUES ('github', $1, $2, $3)
+		RETURNING id
+	`, providerID, providerID+"@example.com", name).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to seed user: %v", err)
+	}
+
+	return id
+}
+
+// returns a random string for names, emails and keys that must not collide
+// with other tests' rows
+func Unique(t testing.TB) string {
+	t.Helper()
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("failed to generate a unique name: %v", err)
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,240 @@
+// Package textdiff computes line diffs and renders them as unified diffs.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// number of unchanged lines shown around each change
+const DefaultContext = 3
+
+// texts needing more line edits than this are diffed as a full replacement,
+// which bounds the work for unrelated inputs
+const maxEdits = 4000
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+// one line of an edit script; a and b are 0-indexed line positions in the old
+// and new text at the point of the edit
+type lineOp struct {
+	kind opKind
+	a, b int
+	text string
+}
+
+// renders the changes from oldText to newText as a unified diff with the
+// given file labels and context lines. returns "" if the texts are equal.
+func Unified(oldLabel, newLabel, oldText, newText string, context int) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldLabel, newLabel)
+
+	for _, h := range hunks(ops, context) {
+		writeHunk(&sb, h)
+	}
+
+	return sb.String()
+}
+
+// splits text into lines without their line breaks; a trailing newline does
+// not start another line
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// computes a shortest edit script with Myers' algorithm
+func diffLines(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEdits)
+
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+
+	// v before each round, so the path can be walked back
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+
+	return replaceAll(a, b)
+}
+
+// walks the trace back from the end of both texts to build the edit script
+func backtrack(a, b []string, trace [][]int) []lineOp {
+	x, y := len(a), len(b)
+	var ops []lineOp
+
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d]
+		at := func(k int) int { return prev[k+d] }
+
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, lineOp{kind: opEqual, a: x, b: y, text: a[x]})
+		}
+
+		if x == prevX {
+			y--
+			ops = append(ops, lineOp{kind: opInsert, a: x, b: y, text: b[y]})
+		} else {
+			x--
+			ops = append(ops, lineOp{kind: opDelete, a: x, b: y, text: a[x]})
+		}
+	}
+
+	// the common prefix
+	for x > 0 {
+		x--
+		y--
+		ops = append(ops, lineOp{kind: opEqual, a: x, b: y, text: a[x]})
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+func replaceAll(a, b []string) []lineOp {
+	ops := make([]lineOp, 0, len(a)+len(b))
+
+	for i, line := range a {
+		ops = append(ops, lineOp{kind: opDelete, a: i, text: line})
+	}
+
+	for i, line := range b {
+		ops = append(ops, lineOp{kind: opInsert, a: len(a), b: i, text: line})
+	}
+
+	return ops
+}
+
+// groups an edit script into hunks of changes with surrounding context
+func hunks(ops []lineOp, context int) [][]lineOp {
+	var result [][]lineOp
+
+	i := 0
+	for i < len(ops) {
+		// find the next change
+		for i < len(ops) && ops[i].kind == opEqual {
+			i++
+		}
+
+		if i == len(ops) {
+			break
+		}
+
+		start := max(i-context, 0)
+
+		// extend while the next change is close enough to share context
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+
+			run := end
+			for run < len(ops) && ops[run].kind == opEqual {
+				run++
+			}
+
+			if run == len(ops) || run-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+
+			end = run
+		}
+
+		result = append(result, ops[start:end])
+		i = end
+	}
+
+	return result
+}
+
+func writeHunk(sb *strings.Builder, h []lineOp) {
+	oldCount, newCount := 0, 0
+	for _, op := range h {
+		if op.kind != opInsert {
+			oldCount++
+		}
+		if op.kind != opDelete {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(h[0].a, oldCount), hunkRange(h[0].b, newCount))
+
+	for _, op := range h {
+		sb.WriteByte(byte(op.kind))
+		sb.WriteString(op.text)
+		sb.WriteByte('\n')
+	}
+}
+
+// formats a hunk range like diff -u: 1-indexed start, count omitted when 1,
+// and the line before the hunk as start when it is empty
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
@@ -0,0 +1,89 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    string
+	}{
+		{
+			name:    "equal",
+			oldText: "a\nb",
+			newText: "a\nb",
+			want:    "",
+		},
+		{
+			name:    "changed line",
+			oldText: "a\nb\nc",
+			newText: "a\nB\nc",
+			want:    "--- v1\n+++ v2\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:    "insert into empty",
+			oldText: "",
+			newText: "a\nb\n",
+			want:    "--- v1\n+++ v2\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "delete all",
+			oldText: "a",
+			newText: "",
+			want:    "--- v1\n+++ v2\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:    "separate hunks",
+			oldText: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			newText: "x\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ny",
+			want: "--- v1\n+++ v2\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+y\n",
+		},
+		{
+			name:    "nearby changes share a hunk",
+			oldText: "1\n2\n3\n4\n5\n6",
+			newText: "x\n2\n3\n4\n5\ny",
+			want:    "--- v1\n+++ v2\n@@ -1,6 +1,6 @@\n-1\n+x\n 2\n 3\n 4\n 5\n-6\n+y\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("v1", "v2", tt.oldText, tt.newText, DefaultContext)
+			if got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesReproducesNewText(t *testing.T) {
+	a := strings.Split("s(\"bd sd\")\n.fast(2)\nnote(\"c e g\")\n.s(\"piano\")\n.room(0.5)", "\n")
+	b := strings.Split("s(\"bd*2 sd\")\n.fast(2)\n.gain(0.8)\nnote(\"c e g\")\n.room(0.5)\n.delay(0.25)", "\n")
+
+	var rebuilt []string
+	changes := 0
+
+	for _, op := range diffLines(a, b) {
+		if op.kind != opDelete {
+			rebuilt = append(rebuilt, op.text)
+		}
+		if op.kind != opEqual {
+			changes++
+		}
+	}
+
+	if strings.Join(rebuilt, "\n") != strings.Join(b, "\n") {
+		t.Fatalf("edit script does not produce the new text: %q", rebuilt)
+	}
+
+	// 3 lines kept, 2 removed and 3 added
+	if changes != 5 {
+		t.Errorf("got %d changed lines, want 5", changes)
+	}
+}
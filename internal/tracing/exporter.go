@@ -0,0 +1,210 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+func newExporter(endpoint string, headers map[string]string, serviceName string) *exporter {
+	return &exporter{
+		endpoint:    endpoint,
+		headers:     headers,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: exportTimeout},
+		queue:       make(chan *Span, exportQueueSize),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// queues a finished span, dropping it if the queue is full
+func (e *exporter) enqueue(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		logger.Debug("trace export queue full, dropping span", "span", span.name)
+	}
+}
+
+// sends queued spans in batches until shutdown
+func (e *exporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, exportBatchSize)
+
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+
+		if err := e.export(ctx, batch); err != nil {
+			logger.Warn("failed to export spans", "error", err, "count", len(batch))
+		}
+
+		batch = batch[:0]
+	}
+
+	// moves everything currently queued into batches
+	drain := func() {
+		for {
+			select {
+			case span := <-e.queue:
+				batch = append(batch, span)
+				if len(batch) >= exportBatchSize {
+					send()
+				}
+			default:
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				send()
+			}
+
+		case <-ticker.C:
+			send()
+
+		case <-e.stop:
+			drain()
+			send()
+			return
+		}
+	}
+}
+
+// exports remaining spans and stops the exporter
+func (e *exporter) shutdown(ctx context.Context) error {
+	close(e.stop)
+
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// posts a batch of spans as an otlp/http json request
+func (e *exporter) export(ctx context.Context, spans []*Span) error {
+	body, err := json.Marshal(e.encode(spans))
+	if err != nil {
+		return fmt.Errorf("failed to marshal spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create export request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send spans: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) //nolint:errcheck
+		return fmt.Errorf("collector returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// builds the ExportTraceServiceRequest in the protobuf json mapping
+func (e *exporter) encode(spans []*Span) otlpRequest {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		encoded = append(encoded, encodeSpan(span))
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: encodeAttributes([]Attribute{String("service.name", e.serviceName)}),
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: scopeName},
+				Spans: encoded,
+			}},
+		}},
+	}
+}
+
+func encodeSpan(span *Span) otlpSpan {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	encoded := otlpSpan{
+		TraceID:           hex.EncodeToString(span.spanContext.TraceID[:]),
+		SpanID:            hex.EncodeToString(span.spanContext.SpanID[:]),
+		Name:              span.name,
+		Kind:              int(span.kind),
+		StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.end.UnixNano(), 10),
+		Attributes:        encodeAttributes(span.attributes),
+		Status: otlpStatus{
+			Code:    span.statusCode,
+			Message: span.statusMessage,
+		},
+	}
+
+	if span.parentSpanID != [8]byte{} {
+		encoded.ParentSpanID = hex.EncodeToString(span.parentSpanID[:])
+	}
+
+	return encoded
+}
+
+func encodeAttributes(attrs []Attribute) []otlpKeyValue {
+	encoded := make([]otlpKeyValue, 0, len(attrs))
+
+	for _, attr := range attrs {
+		var value otlpAnyValue
+
+		switch v := attr.Value.(type) {
+		case string:
+			value.StringValue = &v
+		case int64:
+			// 64-bit integers are strings in the protobuf json mapping
+			s := strconv.FormatInt(v, 10)
+			value.IntValue = &s
+		case float64:
+			value.DoubleValue = &v
+		case bool:
+			value.BoolValue = &v
+		default:
+			s := fmt.Sprint(v)
+			value.StringValue = &s
+		}
+
+		encoded = append(encoded, otlpKeyValue{Key: attr.Key, Value: value})
+	}
+
+	return encoded
+}
@@ -0,0 +1,338 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var globalTracer atomic.Pointer[tracer]
+
+// configures tracing from the standard OTEL_* environment variables and
+// returns a function that flushes pending spans on shutdown. tracing stays
+// disabled (all spans are no-ops) when no otlp endpoint is configured.
+//
+//   - OTEL_EXPORTER_OTLP_TRACES_ENDPOINT: full traces url, or
+//   - OTEL_EXPORTER_OTLP_ENDPOINT: base url, "/v1/traces" is appended
+//   - OTEL_EXPORTER_OTLP_HEADERS: "key=value,key2=value2" sent with exports
+//   - OTEL_SERVICE_NAME: defaults to algopatterns-server
+//   - OTEL_TRACES_SAMPLER_ARG: fraction of new traces to sample, defaults to 1
+//   - OTEL_SDK_DISABLED: "true" turns tracing off
+func Init() (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return noop, nil
+	}
+
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return noop, nil
+		}
+
+		endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+	}
+
+	headers, err := parseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return noop, err
+	}
+
+	sampleRatio := 1.0
+	if arg := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); arg != "" {
+		sampleRatio, err = strconv.ParseFloat(arg, 64)
+		if err != nil || sampleRatio < 0 || sampleRatio > 1 {
+			return noop, fmt.Errorf("OTEL_TRACES_SAMPLER_ARG must be a number between 0 and 1, got %q", arg)
+		}
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	exp := newExporter(endpoint, headers, serviceName)
+	go exp.run()
+
+	globalTracer.Store(&tracer{
+		serviceName: serviceName,
+		sampleRatio: sampleRatio,
+		exporter:    exp,
+	})
+
+	return func(ctx context.Context) error {
+		globalTracer.Store(nil)
+		return exp.shutdown(ctx)
+	}, nil
+}
+
+// reports whether spans are being recorded
+func Enabled() bool {
+	return globalTracer.Load() != nil
+}
+
+// starts an internal span as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return StartWithKind(ctx, name, SpanKindInternal, attrs...)
+}
+
+// starts a span of the given kind. the parent is the span in ctx, or the
+// remote span extracted from an incoming request; without either a new trace
+// is started. the returned context carries the new span.
+func StartWithKind(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	t := globalTracer.Load()
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer:     t,
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: attrs,
+	}
+
+	if parent, ok := parentSpanContext(ctx); ok {
+		span.spanContext.TraceID = parent.TraceID
+		span.spanContext.Sampled = parent.Sampled
+		span.parentSpanID = parent.SpanID
+	} else {
+		span.spanContext.TraceID = newTraceID()
+		span.spanContext.Sampled = sampleTrace(span.spanContext.TraceID, t.sampleRatio)
+	}
+
+	span.spanContext.SpanID = newSpanID()
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// returns the active span in ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// adds attributes to the span
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attributes = append(s.attributes, attrs...)
+}
+
+// marks the span as failed with the error's message. does nothing for nil errors.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.SetError(err.Error())
+}
+
+// marks the span as failed
+func (s *Span) SetError(message string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statusCode = statusError
+	s.statusMessage = message
+}
+
+// returns the identifiers of the span
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+
+	return s.spanContext
+}
+
+// finishes the span and queues it for export. calling End more than once has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	if s.spanContext.Sampled {
+		s.tracer.exporter.enqueue(s)
+	}
+}
+
+// writes the traceparent header for the span in ctx
+func Inject(ctx context.Context, header http.Header) {
+	span := SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+
+	header.Set(traceparentHeader, formatTraceparent(span.spanContext))
+}
+
+// reads the traceparent header of an incoming request so spans started from
+// the returned context continue the caller's trace
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, ok := parseTraceparent(header.Get(traceparentHeader))
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// creates a string attribute
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// creates an integer attribute
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// creates a boolean attribute
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// returns the span context new spans in ctx should use as parent
+func parentSpanContext(ctx context.Context) (SpanContext, bool) {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.spanContext, true
+	}
+
+	sc, ok := ctx.Value(remoteKey{}).(SpanContext)
+
+	return sc, ok
+}
+
+// decides whether a new trace is sampled. uses the trace id as the random
+// source so the decision is the same wherever it is made.
+func sampleTrace(traceID [16]byte, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+
+	if ratio <= 0 {
+		return false
+	}
+
+	// lower 63 bits of the trace id, as in the otel TraceIDRatioBased sampler
+	bound := uint64(ratio * (1 << 63))
+	x := binary.BigEndian.Uint64(traceID[8:]) >> 1
+
+	return x < bound
+}
+
+func newTraceID() [16]byte {
+	var id [16]byte
+	rand.Read(id[:]) //nolint:errcheck,gosec // crypto/rand.Read never fails
+
+	return id
+}
+
+func newSpanID() [8]byte {
+	var id [8]byte
+	rand.Read(id[:]) //nolint:errcheck,gosec // crypto/rand.Read never fails
+
+	return id
+}
+
+func formatTraceparent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return traceparentVersion + "-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + flags
+}
+
+// parses "00-<trace id>-<span id>-<flags>", rejecting all-zero ids
+func parseTraceparent(value string) (SpanContext, bool) {
+	var sc SpanContext
+
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != traceparentVersion {
+		return sc, false
+	}
+
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(sc.TraceID) {
+		return sc, false
+	}
+
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(sc.SpanID) {
+		return sc, false
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return sc, false
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags[0]&flagSampled != 0
+
+	if sc.TraceID == [16]byte{} || sc.SpanID == [8]byte{} {
+		return sc, false
+	}
+
+	return sc, true
+}
+
+// parses OTEL_EXPORTER_OTLP_HEADERS ("key=value,key2=value2", values may be url-encoded)
+func parseHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_HEADERS entry %q", pair)
+		}
+
+		decoded, err := url.PathUnescape(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_HEADERS value for %q: %w", key, err)
+		}
+
+		headers[strings.TrimSpace(key)] = decoded
+	}
+
+	return headers, nil
+}
@@ -0,0 +1,178 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	sc := SpanContext{
+		TraceID: [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		Sampled: true,
+	}
+
+	header := formatTraceparent(sc)
+	if want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"; header != want {
+		t.Fatalf("expected %s, got %s", want, header)
+	}
+
+	parsed, ok := parseTraceparent(header)
+	if !ok {
+		t.Fatal("expected traceparent to parse")
+	}
+
+	if parsed != sc {
+		t.Errorf("expected %+v, got %+v", sc, parsed)
+	}
+}
+
+func TestParseTraceparentRejectsInvalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", // unknown version
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",  // short trace id
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01", // zero trace id
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", // zero span id
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz", // bad flags
+	} {
+		if _, ok := parseTraceparent(value); ok {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestStartWithoutInitIsNoop(t *testing.T) {
+	ctx, span := Start(context.Background(), "noop")
+	if span != nil {
+		t.Fatal("expected nil span while tracing is disabled")
+	}
+
+	// nil spans are safe to use
+	span.SetAttributes(String("key", "value"))
+	span.SetError("failed")
+	span.End()
+
+	header := http.Header{}
+	Inject(ctx, header)
+
+	if header.Get(traceparentHeader) != "" {
+		t.Error("expected no traceparent while tracing is disabled")
+	}
+}
+
+func TestSampleTrace(t *testing.T) {
+	id := newTraceID()
+
+	if !sampleTrace(id, 1) {
+		t.Error("expected ratio 1 to sample every trace")
+	}
+
+	if sampleTrace(id, 0) {
+		t.Error("expected ratio 0 to sample no trace")
+	}
+}
+
+func TestSpansExportedAsOTLP(t *testing.T) {
+	var mu sync.Mutex
+	var requests []otlpRequest
+	var authHeader string
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read export body: %v", err)
+		}
+
+		var req otlpRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("failed to unmarshal export body: %v", err)
+		}
+
+		mu.Lock()
+		requests = append(requests, req)
+		authHeader = r.Header.Get("Authorization")
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", collector.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer%20secret")
+	t.Setenv("OTEL_SERVICE_NAME", "test-service")
+
+	shutdown, err := Init()
+	if err != nil {
+		t.Fatalf("failed to init tracing: %v", err)
+	}
+
+	// continue a trace started by a caller
+	incoming := http.Header{}
+	incoming.Set(traceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := Extract(context.Background(), incoming)
+
+	ctx, parent := StartWithKind(ctx, "GET /api/v1/ping", SpanKindServer, String("http.route", "/api/v1/ping"))
+	_, child := Start(ctx, "child", Int("count", 3))
+	child.SetError("boom")
+	child.End()
+	parent.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := shutdown(ctx); err != nil {
+		t.Fatalf("failed to shut down tracing: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if authHeader != "Bearer secret" {
+		t.Errorf("expected decoded export header, got %q", authHeader)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 export request, got %d", len(requests))
+	}
+
+	resourceSpans := requests[0].ResourceSpans[0]
+	if name := *resourceSpans.Resource.Attributes[0].Value.StringValue; name != "test-service" {
+		t.Errorf("expected service name test-service, got %s", name)
+	}
+
+	spans := resourceSpans.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	childSpan, parentSpan := spans[0], spans[1]
+
+	if parentSpan.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || parentSpan.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("expected server span to continue the remote trace, got trace %s parent %s", parentSpan.TraceID, parentSpan.ParentSpanID)
+	}
+
+	if childSpan.TraceID != parentSpan.TraceID || childSpan.ParentSpanID != parentSpan.SpanID {
+		t.Error("expected child span to be parented to the server span")
+	}
+
+	if parentSpan.Kind != int(SpanKindServer) || childSpan.Kind != int(SpanKindInternal) {
+		t.Errorf("unexpected span kinds %d and %d", parentSpan.Kind, childSpan.Kind)
+	}
+
+	if childSpan.Status.Code != statusError || childSpan.Status.Message != "boom" {
+		t.Errorf("expected error status on child span, got %+v", childSpan.Status)
+	}
+
+	if v := childSpan.Attributes[0].Value.IntValue; v == nil || *v != "3" {
+		t.Error("expected integer attribute encoded as string")
+	}
+
+	if Enabled() {
+		t.Error("expected tracing to be disabled after shutdown")
+	}
+}
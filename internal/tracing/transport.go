@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"net/http"
+)
+
+// wraps an http transport so each outgoing request gets a client span and
+// carries the trace to the server in the traceparent header
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !Enabled() {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, span := StartWithKind(req.Context(), "HTTP "+req.Method, SpanKindClient,
+		String("http.request.method", req.Method),
+		String("server.address", req.URL.Hostname()),
+		String("url.path", req.URL.Path),
+	)
+	defer span.End()
+
+	// round trippers must not modify the caller's request
+	req = req.Clone(ctx)
+	Inject(ctx, req.Header)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(Int("http.response.status_code", resp.StatusCode))
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetError(http.StatusText(resp.StatusCode))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,164 @@
+package tracing
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// service.name reported when OTEL_SERVICE_NAME is unset
+	defaultServiceName = "algopatterns-server"
+
+	// instrumentation scope reported with every span
+	scopeName = "codeberg.org/algopatterns/server"
+
+	// w3c trace context header used for propagation
+	traceparentHeader = "traceparent"
+
+	// only version 00 of the traceparent format exists
+	traceparentVersion = "00"
+
+	// traceparent flag marking a trace as sampled
+	flagSampled = 0x01
+)
+
+// exporter constants
+const (
+	// how often finished spans are sent to the collector
+	exportInterval = 5 * time.Second
+
+	// spans sent per request; a full batch is sent without waiting for the interval
+	exportBatchSize = 512
+
+	// finished spans waiting for export; spans are dropped when full
+	exportQueueSize = 2048
+
+	// maximum time a single export request may take
+	exportTimeout = 10 * time.Second
+)
+
+// role of a span in a trace (values match the otlp enum)
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// otlp status code for failed spans
+const statusError = 2
+
+// identifies a span within a trace, possibly one from another service
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// key/value metadata attached to a span
+type Attribute struct {
+	Key   string
+	Value any // string, int64, float64 or bool
+}
+
+// a timed operation within a trace. a nil span is valid and does nothing,
+// which is what Start returns while tracing is disabled.
+type Span struct {
+	tracer       *tracer
+	name         string
+	kind         SpanKind
+	spanContext  SpanContext
+	parentSpanID [8]byte
+	start        time.Time
+
+	mu            sync.Mutex
+	end           time.Time
+	attributes    []Attribute
+	statusCode    int
+	statusMessage string
+	ended         bool
+}
+
+// creates spans and hands sampled ones to the exporter
+type tracer struct {
+	serviceName string
+	sampleRatio float64
+	exporter    *exporter
+}
+
+// batches finished spans and sends them to an otlp/http collector as json
+type exporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+
+	queue chan *Span
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// context key for the active span
+type spanKey struct{}
+
+// context key for a parent span context extracted from an incoming request
+type remoteKey struct{}
+
+// http.RoundTripper that traces outgoing requests and propagates the trace
+type transport struct {
+	base http.RoundTripper
+}
+
+// otlp/http json request body (opentelemetry-proto ExportTraceServiceRequest)
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
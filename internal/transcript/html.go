@@ -0,0 +1,143 @@
+package transcript
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// chroma style code is highlighted with
+const highlightStyle = "github"
+
+var (
+	highlighter = chromahtml.New(chromahtml.WithClasses(true))
+	codeLexer   = chroma.Coalesce(lexers.Get(codeLanguage))
+)
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; line-height: 1.5; color: #1f2328; max-width: 52rem; margin: 2rem auto; padding: 0 1rem; }
+h1 { margin-bottom: 0; }
+.period, .note { color: #59636e; font-style: italic; }
+.entry { margin: 1.25rem 0; }
+.entry .who { margin: 0 0 0.25rem; }
+.entry time { color: #59636e; font-size: 0.875rem; }
+.entry .text { margin: 0; white-space: pre-wrap; }
+.user_prompt .text { border-left: 3px solid #d1d9e0; padding-left: 0.75rem; color: #59636e; }
+.chroma { padding: 0.75rem; border-radius: 6px; overflow-x: auto; font-size: 0.875rem; }
+{{.CSS}}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="period">{{.Period}}</p>
+{{range .Days}}
+<h2>{{.Date}}</h2>
+{{range .Items}}
+<div class="entry {{.Kind}}">
+<p class="who"><strong>{{.Author}}</strong>{{if .Reply}} replied{{end}}{{if .Action}} {{.Action}}{{end}} · <time datetime="{{.Timestamp}}">{{.Time}}</time></p>
+{{if .Code}}{{.Code}}{{else}}<p class="text">{{.Text}}</p>{{end}}
+</div>
+{{end}}
+{{end}}
+{{if .Truncated}}<hr>
+<p class="note">Transcript cut short after {{.Entries}} entries.</p>
+{{end}}
+</body>
+</html>
+`))
+
+type htmlItem struct {
+	Kind      string
+	Author    string
+	Action    string
+	Reply     bool
+	Timestamp string
+	Time      string
+	Text      string
+	Code      htmltemplate.HTML // highlighted by chroma, which escapes it
+}
+
+type htmlDay struct {
+	Date  string
+	Items []htmlItem
+}
+
+// renders the transcript as a standalone HTML page, with code highlighted
+func HTML(t *Transcript) ([]byte, error) {
+	style := styles.Get(highlightStyle)
+
+	var css strings.Builder
+	if err := highlighter.WriteCSS(&css, style); err != nil {
+		return nil, err
+	}
+
+	var days []htmlDay
+	for _, d := range t.days() {
+		hd := htmlDay{Date: d.date}
+
+		for _, it := range d.items {
+			hi := htmlItem{
+				Kind:      it.kind,
+				Author:    it.author,
+				Action:    it.action,
+				Reply:     it.reply,
+				Timestamp: it.at.Format("2006-01-02T15:04:05Z"),
+				Time:      it.at.Format(timeLayout),
+				Text:      strings.TrimSpace(it.text),
+			}
+
+			if it.code != "" {
+				code, err := highlight(it.code, style)
+				if err != nil {
+					return nil, err
+				}
+				hi.Code = code
+			}
+
+			hd.Items = append(hd.Items, hi)
+		}
+
+		days = append(days, hd)
+	}
+
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, map[string]any{
+		"Title":     t.Title,
+		"Period":    t.period(),
+		"CSS":       htmltemplate.CSS(css.String()),
+		"Days":      days,
+		"Truncated": t.Truncated,
+		"Entries":   len(t.Entries),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renders code as a highlighted <pre> block
+func highlight(code string, style *chroma.Style) (htmltemplate.HTML, error) {
+	tokens, err := codeLexer.Tokenise(nil, strings.TrimRight(code, "\n"))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := highlighter.Format(&buf, style, tokens); err != nil {
+		return "", err
+	}
+
+	return htmltemplate.HTML(buf.String()), nil //nolint:gosec // chroma escapes the code
+}
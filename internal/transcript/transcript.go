@@ -0,0 +1,248 @@
+// Package transcript renders the timeline of a session, its chat, AI
+// exchanges and code checkpoints, as a Markdown or HTML document.
+package transcript
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+)
+
+// formats of the rendered documents
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+// language of session code, for fences and highlighting
+const codeLanguage = "javascript"
+
+const (
+	dateLayout = "2 January 2006"
+	timeLayout = "15:04:05"
+)
+
+// name shown for messages of users who left no display name
+const anonymousName = "Anonymous"
+
+// a session transcript ready to render
+type Transcript struct {
+	SessionID string
+	Title     string
+	StartedAt time.Time
+	EndedAt   *time.Time
+	Entries   []*sessions.TranscriptEntry
+	Truncated bool // more entries than the limit, the last ones are missing
+}
+
+// builds the transcript of a session from up to limit of its entries. pass
+// one more entry than limit to have the transcript say it was cut short
+func New(session *sessions.Session, entries []*sessions.TranscriptEntry, limit int) *Transcript {
+	t := &Transcript{
+		SessionID: session.ID,
+		Title:     session.Title,
+		StartedAt: session.CreatedAt,
+		EndedAt:   session.EndedAt,
+		Entries:   entries,
+	}
+
+	if limit > 0 && len(entries) > limit {
+		t.Entries = entries[:limit]
+		t.Truncated = true
+	}
+
+	if strings.TrimSpace(t.Title) == "" {
+		t.Title = "Untitled session"
+	}
+
+	return t
+}
+
+// renders the transcript in format
+func Render(t *Transcript, format string) ([]byte, error) {
+	switch format {
+	case FormatMarkdown:
+		return Markdown(t), nil
+	case FormatHTML:
+		return HTML(t)
+	default:
+		return nil, fmt.Errorf("unknown transcript format %q", format)
+	}
+}
+
+// an entry as it's shown in a document
+type item struct {
+	kind   string
+	author string
+	action string // what the author did, after their name
+	at     time.Time
+	text   string // prose, empty for code
+	code   string
+	reply  bool
+}
+
+// the entries of one day
+type day struct {
+	date  string
+	items []item
+}
+
+// groups the entries into days, dropping checkpoints of code that didn't
+// change since the last one or the last AI response
+func (t *Transcript) days() []day {
+	var days []day
+	lastCode := ""
+
+	for _, e := range t.Entries {
+		it := item{kind: e.Kind, at: e.CreatedAt.UTC()}
+
+		switch e.Kind {
+		case sessions.MessageTypeChat:
+			it.author = authorName(e.DisplayName)
+			it.text = e.Content
+			it.reply = e.ParentMessageID != nil
+
+		case sessions.MessageTypeUserPrompt:
+			it.author = authorName(e.DisplayName)
+			it.action = "asked the AI assistant"
+			it.text = e.Content
+
+		case sessions.MessageTypeAIResponse:
+			it.author = "AI assistant"
+			if e.IsCodeResponse {
+				it.action = "wrote code"
+				it.code = e.Content
+				lastCode = e.Content
+			} else {
+				it.text = e.Content
+			}
+
+		case sessions.TranscriptEntryCode:
+			if strings.TrimSpace(e.Content) == "" || e.Content == lastCode {
+				continue
+			}
+			it.author = "Code checkpoint"
+			it.code = e.Content
+			lastCode = e.Content
+
+		default:
+			continue
+		}
+
+		date := it.at.Format(dateLayout)
+		if len(days) == 0 || days[len(days)-1].date != date {
+			days = append(days, day{date: date})
+		}
+
+		d := &days[len(days)-1]
+		d.items = append(d.items, it)
+	}
+
+	return days
+}
+
+// the period the session ran, for the heading of documents
+func (t *Transcript) period() string {
+	started := t.StartedAt.UTC().Format(dateLayout + " 15:04")
+	if t.EndedAt == nil {
+		return "Started " + started + " UTC"
+	}
+
+	return started + " – " + t.EndedAt.UTC().Format(dateLayout+" 15:04") + " UTC"
+}
+
+// renders the transcript as Markdown, with code in fenced blocks
+func Markdown(t *Transcript) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(t.Title))
+	fmt.Fprintf(&b, "_%s_\n", t.period())
+
+	for _, d := range t.days() {
+		fmt.Fprintf(&b, "\n## %s\n", d.date)
+
+		for _, it := range d.items {
+			b.WriteString("\n")
+			b.WriteString(markdownHeading(it))
+			b.WriteString("\n\n")
+
+			switch {
+			case it.code != "":
+				fence := codeFence(it.code)
+				fmt.Fprintf(&b, "%s%s\n%s\n%s\n", fence, codeLanguage, strings.TrimRight(it.code, "\n"), fence)
+			case it.kind == sessions.MessageTypeUserPrompt:
+				b.WriteString(quote(it.text))
+				b.WriteString("\n")
+			default:
+				b.WriteString(strings.TrimSpace(it.text))
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	if t.Truncated {
+		fmt.Fprintf(&b, "\n---\n\n_Transcript cut short after %d entries._\n", len(t.Entries))
+	}
+
+	return []byte(b.String())
+}
+
+// the line introducing an entry, e.g. "**Alice** asked the AI assistant · 14:03:12"
+func markdownHeading(it item) string {
+	heading := "**" + escapeMarkdown(it.author) + "**"
+	if it.reply {
+		heading += " replied"
+	}
+	if it.action != "" {
+		heading += " " + it.action
+	}
+
+	return heading + " · " + it.at.Format(timeLayout)
+}
+
+// a backtick fence longer than any run of backticks in code, so code
+// containing fences can't end the block early
+func codeFence(code string) string {
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// quotes text as a Markdown blockquote
+func quote(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`",
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+// escapes text shown inline, like names and titles, so it can't be read as
+// formatting
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+func authorName(displayName *string) string {
+	if displayName == nil || strings.TrimSpace(*displayName) == "" {
+		return anonymousName
+	}
+
+	return *displayName
+}
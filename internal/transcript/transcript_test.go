@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+)
+
+func ptr(s string) *string { return &s }
+
+func testTranscript() *Transcript {
+	start := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	session := &sessions.Session{ID: "s1", Title: "Friday *jam*", CreatedAt: start}
+	entries := []*sessions.TranscriptEntry{
+		{Kind: sessions.MessageTypeChat, DisplayName: ptr("Alice"), Content: "hi all", CreatedAt: at(1)},
+		{Kind: sessions.MessageTypeChat, DisplayName: ptr("Bob"), Content: "hey", ParentMessageID: ptr("m1"), CreatedAt: at(2)},
+		{Kind: sessions.MessageTypeUserPrompt, DisplayName: ptr("Alice"), Content: "add a kick", CreatedAt: at(3)},
+		{Kind: sessions.MessageTypeAIResponse, Content: `s("bd*4")`, IsCodeResponse: true, CreatedAt: at(3)},
+		{Kind: sessions.TranscriptEntryCode, Content: `s("bd*4")`, CreatedAt: at(4)},
+		{Kind: sessions.TranscriptEntryCode, Content: "s(\"bd*4 <sd>\")\n// ```", CreatedAt: at(5)},
+		{Kind: sessions.MessageTypeChat, Content: "<script>alert(1)</script>", CreatedAt: at(6)},
+	}
+
+	return New(session, entries, 10)
+}
+
+func TestMarkdown(t *testing.T) {
+	md := string(Markdown(testTranscript()))
+
+	wants := []string{
+		"# Friday \\*jam\\*\n",
+		"## 1 March 2026\n",
+		"**Alice** · 14:01:00\n\nhi all\n",
+		"**Bob** replied · 14:02:00\n",
+		"**Alice** asked the AI assistant · 14:03:00\n\n> add a kick\n",
+		"**AI assistant** wrote code · 14:03:00\n\n```javascript\ns(\"bd*4\")\n```\n",
+		"**Code checkpoint** · 14:05:00\n\n````javascript\n",
+		"**Anonymous** · 14:06:00\n",
+	}
+	for _, want := range wants {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q in:\n%s", want, md)
+		}
+	}
+
+	// the checkpoint matching the AI response is dropped
+	if strings.Contains(md, "14:04:00") {
+		t.Errorf("Markdown() kept an unchanged checkpoint:\n%s", md)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	page, err := HTML(testTranscript())
+	if err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	html := string(page)
+
+	if strings.Contains(html, "<script>") {
+		t.Errorf("HTML() didn't escape chat content:\n%s", html)
+	}
+	if !strings.Contains(html, `class="chroma"`) {
+		t.Errorf("HTML() didn't highlight code:\n%s", html)
+	}
+	if !strings.Contains(html, "<title>Friday *jam*</title>") {
+		t.Errorf("HTML() missing title:\n%s", html)
+	}
+}
+
+func TestNewTruncates(t *testing.T) {
+	session := &sessions.Session{ID: "s1"}
+	entries := make([]*sessions.TranscriptEntry, 4)
+	for i := range entries {
+		entries[i] = &sessions.TranscriptEntry{Kind: sessions.MessageTypeChat, Content: "x"}
+	}
+
+	tr := New(session, entries, 3)
+	if !tr.Truncated || len(tr.Entries) != 3 {
+		t.Errorf("New() truncated = %v with %d entries, want true with 3", tr.Truncated, len(tr.Entries))
+	}
+	if tr.Title != "Untitled session" {
+		t.Errorf("New() title = %q, want Untitled session", tr.Title)
+	}
+
+	if !strings.Contains(string(Markdown(tr)), "cut short after 3 entries") {
+		t.Error("Markdown() doesn't mention the transcript was cut short")
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"note(\"c e g\")", "```"},
+		{"// ``` inside", "````"},
+		{"`````", "``````"},
+	}
+
+	for _, tt := range tests {
+		if got := codeFence(tt.code); got != tt.want {
+			t.Errorf("codeFence(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
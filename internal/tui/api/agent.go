@@ -0,0 +1,20 @@
+package api
+
+import (
+	"context"
+	"net/http"
+)
+
+// asks the agent for code. without a deadline on ctx it times out after a
+// minute, as answers take longer than other requests
+func (c *Client) Generate(ctx context.Context, req GenerateRequest) (*Generation, error) {
+	ctx, cancel := withDefaultTimeout(ctx, generateTimeout)
+	defer cancel()
+
+	var generation Generation
+	if err := c.do(ctx, http.MethodPost, "/agent/generate", nil, req, &generation); err != nil {
+		return nil, err
+	}
+
+	return &generation, nil
+}
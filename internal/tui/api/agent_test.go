@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/agent/generate" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if req.UserQuery != "a beat" || req.EditorState != `s("hh")` || len(req.ConversationHistory) != 1 {
+			t.Errorf("request = %+v", req)
+		}
+
+		w.Write([]byte(`{"code":"s(\"bd hh\")","is_actionable":true,"is_code_response":true,"model":"m","rate_limit":{"tier":"free","limit":50,"current":3,"remaining":47}}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	generation, err := New(server.URL, "secret").Generate(context.Background(), GenerateRequest{
+		UserQuery:           "a beat",
+		EditorState:         `s("hh")`,
+		ConversationHistory: []HistoryMessage{{Role: "user", Content: "drums"}},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if generation.Code != `s("bd hh")` || !generation.IsCodeResponse || generation.RateLimit == nil || generation.RateLimit.Remaining != 47 {
+		t.Errorf("Generate() = %+v", generation)
+	}
+}
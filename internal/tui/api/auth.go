@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// starts signing in with a device code. the user enters the code at the
+// verification URI while WaitForDeviceLogin polls for the result
+func (c *Client) StartDeviceLogin(ctx context.Context) (*DeviceLogin, error) {
+	var login DeviceLogin
+	if err := c.send(ctx, http.MethodPost, "/auth/device", nil, nil, &login, ""); err != nil {
+		return nil, err
+	}
+
+	return &login, nil
+}
+
+// polls until the user approves or denies the device login, then signs the
+// client in and saves the credentials. fails with ErrLoginDenied,
+// ErrLoginExpired or the error of ctx
+func (c *Client) WaitForDeviceLogin(ctx context.Context, login *DeviceLogin) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(login.ExpiresIn)*time.Second)
+	defer cancel()
+
+	interval := max(time.Duration(login.Interval)*time.Second, minDevicePollInterval)
+	req := deviceTokenRequest{DeviceCode: login.DeviceCode}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ErrLoginExpired
+			}
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var resp authResponse
+		err := c.send(ctx, http.MethodPost, "/auth/device/token", nil, req, &resp, "")
+
+		switch {
+		case err == nil:
+			c.signIn(resp.User, resp.Token, resp.RefreshToken, resp.ExpiresIn)
+			if err := c.saveCredentials(); err != nil {
+				return resp.User, err
+			}
+			return resp.User, nil
+		case IsCode(err, "authorization_pending"):
+			continue
+		case IsCode(err, "slow_down"):
+			// as RFC 8628 asks of clients told to slow down
+			interval += minDevicePollInterval
+		case IsCode(err, "access_denied"):
+			return nil, ErrLoginDenied
+		case IsCode(err, "expired_token"):
+			return nil, ErrLoginExpired
+		case ctx.Err() != nil:
+			// the loop reports the cancellation
+			continue
+		default:
+			return nil, err
+		}
+	}
+}
+
+// signs out: the refresh token is revoked and the saved credentials are
+// removed. the client is signed out even if revoking fails
+func (c *Client) Logout(ctx context.Context) error {
+	c.mu.Lock()
+	refreshToken := c.refreshToken
+	c.mu.Unlock()
+
+	if err := c.clearCredentials(); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	if err := c.send(ctx, http.MethodPost, "/auth/logout", nil, logoutRequest{RefreshToken: refreshToken}, nil, ""); err != nil {
+		return fmt.Errorf("failed to revoke the session: %w", err)
+	}
+
+	return nil
+}
+
+// fetches the signed-in user and remembers it
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	var resp userResponse
+	if err := c.do(ctx, http.MethodGet, "/auth/me", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.user = resp.User
+	c.mu.Unlock()
+
+	return resp.User, c.saveCredentials()
+}
+
+// renews the token with the refresh token. stale is the token that needed
+// renewing: when another request renewed it meanwhile nothing is done, since
+// a refresh token works once
+func (c *Client) refresh(ctx context.Context, stale string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	c.mu.Lock()
+	current, refreshToken := c.token, c.refreshToken
+	c.mu.Unlock()
+
+	if current != stale {
+		return nil
+	}
+
+	// another terminal may have renewed the shared credentials already
+	if c.reloadCredentials(stale) {
+		return nil
+	}
+
+	if refreshToken == "" {
+		return ErrSessionExpired
+	}
+
+	var resp tokenResponse
+	err := c.send(ctx, http.MethodPost, "/auth/refresh", nil, refreshRequest{RefreshToken: refreshToken}, &resp, "")
+	if IsStatus(err, http.StatusUnauthorized) || IsStatus(err, http.StatusForbidden) {
+		c.clearCredentials() //nolint:errcheck,gosec // signed out either way
+		return ErrSessionExpired
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to renew token: %w", err)
+	}
+
+	c.mu.Lock()
+	c.token = resp.Token
+	c.refreshToken = resp.RefreshToken
+	c.expiresAt = expiry(resp.ExpiresIn)
+	c.mu.Unlock()
+
+	return c.saveCredentials()
+}
+
+// reports whether the client holds a refresh token
+func (c *Client) canRefresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.refreshToken != ""
+}
+
+// sets the credentials of a login
+func (c *Client) signIn(user *User, token, refreshToken string, expiresIn int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.user = user
+	c.token = token
+	c.refreshToken = refreshToken
+	c.expiresAt = expiry(expiresIn)
+}
+
+// the time a token lasting expiresIn seconds expires, zero if unknown
+func expiry(expiresIn int) time.Time {
+	if expiresIn <= 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(time.Duration(expiresIn) * time.Second)
+}
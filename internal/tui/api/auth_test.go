@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func init() {
+	minDevicePollInterval = 10 * time.Millisecond
+}
+
+func TestDeviceLogin(t *testing.T) {
+	var polls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/auth/device":
+			w.Write([]byte(`{"device_code":"dev","user_code":"BCDF-GHJK","verification_uri":"https://algopatterns.test/device","expires_in":600,"interval":0}`)) //nolint:errcheck,gosec // test server
+
+		case "/api/v1/auth/device/token":
+			var req deviceTokenRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.DeviceCode != "dev" {
+				t.Errorf("unexpected poll %+v", req)
+			}
+
+			if polls.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"authorization_pending","message":"authorization pending"}`)) //nolint:errcheck,gosec // test server
+				return
+			}
+
+			w.Write([]byte(`{"user":{"id":"u1","name":"ada","email":"ada@example.com"},"token":"access","refresh_token":"refresh","expires_in":900}`)) //nolint:errcheck,gosec // test server
+
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	client.credentialsPath = filepath.Join(t.TempDir(), "algopatterns", credentialsFile)
+
+	login, err := client.StartDeviceLogin(context.Background())
+	if err != nil {
+		t.Fatalf("StartDeviceLogin() error = %v", err)
+	}
+	if login.UserCode != "BCDF-GHJK" {
+		t.Errorf("UserCode = %q", login.UserCode)
+	}
+
+	user, err := client.WaitForDeviceLogin(context.Background(), login)
+	if err != nil {
+		t.Fatalf("WaitForDeviceLogin() error = %v", err)
+	}
+	if user.Name != "ada" || !client.Authenticated() || polls.Load() != 3 {
+		t.Errorf("WaitForDeviceLogin() = %+v after %d polls", user, polls.Load())
+	}
+
+	info, err := os.Stat(client.credentialsPath)
+	if err != nil {
+		t.Fatalf("credentials not saved: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("credentials mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// a later run picks the login up
+	restored := New(server.URL, "")
+	restored.credentialsPath = client.credentialsPath
+	restored.loadCredentials()
+
+	if !restored.Authenticated() || restored.User() == nil || restored.User().Email != "ada@example.com" {
+		t.Errorf("restored client user = %+v", restored.User())
+	}
+
+	// but not one talking to another API
+	other := New("https://elsewhere.test", "")
+	other.credentialsPath = client.credentialsPath
+	other.loadCredentials()
+
+	if other.Authenticated() {
+		t.Error("credentials of another endpoint were used")
+	}
+}
+
+func TestDeviceLoginDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"access_denied","message":"access denied"}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+
+	_, err := client.WaitForDeviceLogin(context.Background(), &DeviceLogin{DeviceCode: "dev", ExpiresIn: 600})
+	if !errors.Is(err, ErrLoginDenied) {
+		t.Errorf("WaitForDeviceLogin() error = %v, want ErrLoginDenied", err)
+	}
+	if client.Authenticated() {
+		t.Error("client signed in after a denied login")
+	}
+}
+
+func TestRefreshOnExpiry(t *testing.T) {
+	var refreshes atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/auth/refresh":
+			var req refreshRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken != "refresh" {
+				t.Errorf("unexpected refresh %+v", req)
+			}
+
+			refreshes.Add(1)
+			w.Write([]byte(`{"token":"renewed","refresh_token":"refresh-2","expires_in":900}`)) //nolint:errcheck,gosec // test server
+
+		case "/api/v1/auth/me":
+			if got := r.Header.Get("Authorization"); got != "Bearer renewed" {
+				t.Errorf("Authorization = %q, want the renewed token", got)
+			}
+			w.Write([]byte(`{"user":{"id":"u1","name":"ada"}}`)) //nolint:errcheck,gosec // test server
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	client.signIn(nil, "stale", "refresh", 10) // expires within the refresh margin
+
+	// concurrent requests spend the refresh token once
+	done := make(chan error)
+	for range 3 {
+		go func() {
+			_, err := client.Me(context.Background())
+			done <- err
+		}()
+	}
+	for range 3 {
+		if err := <-done; err != nil {
+			t.Errorf("Me() error = %v", err)
+		}
+	}
+
+	if n := refreshes.Load(); n != 1 {
+		t.Errorf("refreshed %d times, want once", n)
+	}
+}
+
+func TestRefreshRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"unauthorized","message":"invalid or expired refresh token"}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	client.credentialsPath = filepath.Join(t.TempDir(), credentialsFile)
+	client.signIn(&User{ID: "u1"}, "token", "revoked", 900)
+	if err := client.saveCredentials(); err != nil {
+		t.Fatalf("saveCredentials() error = %v", err)
+	}
+
+	_, err := client.ListSessions(context.Background(), false)
+	if !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("ListSessions() error = %v, want ErrSessionExpired", err)
+	}
+
+	if client.Authenticated() {
+		t.Error("client still signed in")
+	}
+	if _, err := os.Stat(client.credentialsPath); !os.IsNotExist(err) {
+		t.Error("credentials of the expired login were kept")
+	}
+}
@@ -0,0 +1,240 @@
+// Package api is the REST client of the terminal clients, covering the parts
+// of the algopatterns API they use.
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// creates a client of the API at endpoint, authenticating with token when
+// it isn't empty
+func New(endpoint, token string) *Client {
+	return &Client{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		httpClient: &http.Client{},
+		token:      token,
+	}
+}
+
+// creates a client from ALGOPATTERNS_API_ENDPOINT. it signs in with
+// ALGOPATTERNS_TOKEN when set, otherwise with the credentials saved by the
+// last login to the same endpoint
+func NewFromEnv() *Client {
+	endpoint := os.Getenv("ALGOPATTERNS_API_ENDPOINT")
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+
+	if token := os.Getenv("ALGOPATTERNS_TOKEN"); token != "" {
+		return New(endpoint, token)
+	}
+
+	client := New(endpoint, "")
+	client.credentialsPath = defaultCredentialsPath()
+	client.loadCredentials()
+
+	return client
+}
+
+// the API the client talks to
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// reports whether requests are sent with a token
+func (c *Client) Authenticated() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token != ""
+}
+
+// the signed-in user, nil when not signed in or not yet known
+func (c *Client) User() *User {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.user == nil {
+		return nil
+	}
+
+	user := *c.user
+	return &user
+}
+
+// the token to send, renewed first when it is about to expire. empty when
+// not signed in
+func (c *Client) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token, refreshToken, expiresAt := c.token, c.refreshToken, c.expiresAt
+	c.mu.Unlock()
+
+	if token == "" || refreshToken == "" || expiresAt.IsZero() || time.Until(expiresAt) > refreshMargin {
+		return token, nil
+	}
+
+	if err := c.refresh(ctx, token); err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token, nil
+}
+
+// checks that the API can be reached
+func (c *Client) Ping(ctx context.Context) error {
+	return c.send(ctx, http.MethodGet, "/ping", nil, nil, nil, "")
+}
+
+// sends a request to the API and decodes the JSON response into out, which
+// may be nil. error responses are returned as *Error
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	token, err := c.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.send(ctx, method, path, query, body, out, token)
+
+	// the token may be rejected before it expires, e.g. when the server's
+	// secret rotated, so it is renewed once
+	if token != "" && IsStatus(err, http.StatusUnauthorized) && c.canRefresh() {
+		if err := c.refresh(ctx, token); err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		token = c.token
+		c.mu.Unlock()
+
+		return c.send(ctx, method, path, query, body, out, token)
+	}
+
+	return err
+}
+
+// sends a request with the token, which may be empty. without a deadline
+// on ctx it times out after requestTimeout
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body, out any, token string) error {
+	ctx, cancel := withDefaultTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	target := c.endpoint + "/api/v1" + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(data, apiErr); err != nil || apiErr.Code == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// sets a timeout on ctx unless it has a deadline already
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("request failed with status %d", e.StatusCode)
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// reports whether err is an API error with the status code
+func IsStatus(err error, statusCode int) bool {
+	apiErr, ok := err.(*Error)
+	return ok && apiErr.StatusCode == statusCode
+}
+
+// reports whether err is an API error with the error code
+func IsCode(err error, code string) bool {
+	apiErr, ok := err.(*Error)
+	return ok && apiErr.Code == code
+}
+
+// reports whether err means the API couldn't be reached, as opposed to the
+// API answering with an error or the request being canceled
+func IsOffline(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return false
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
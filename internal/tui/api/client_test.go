@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientSendsToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sessions" || r.URL.Query().Get("active_only") != "true" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want the bearer token", got)
+		}
+
+		w.Write([]byte(`{"sessions":[{"id":"s1","title":"jam","is_active":true}],"pagination":{"limit":100}}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	sessions, err := New(server.URL+"/", "secret").ListSessions(context.Background(), true)
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+
+	if len(sessions) != 1 || sessions[0].ID != "s1" || !sessions[0].IsActive {
+		t.Errorf("ListSessions() = %+v", sessions)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/sessions/join" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_invite","message":"invite is invalid or expired"}`)) //nolint:errcheck,gosec // test server
+			return
+		}
+
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("bad gateway")) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+
+	_, err := client.JoinSession(context.Background(), "expired", "ada")
+	if !IsStatus(err, http.StatusBadRequest) {
+		t.Fatalf("JoinSession() error = %v, want a 400 API error", err)
+	}
+	if err.Error() != "invalid_invite: invite is invalid or expired" {
+		t.Errorf("error = %q", err.Error())
+	}
+
+	_, err = client.ListLiveSessions(context.Background())
+	if !IsStatus(err, http.StatusBadGateway) {
+		t.Fatalf("ListLiveSessions() error = %v, want a 502 API error", err)
+	}
+	if err.Error() != "request failed with status 502: bad gateway" {
+		t.Errorf("error = %q", err.Error())
+	}
+}
+
+func TestJoinSessionRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("guest request carried a token")
+		}
+
+		var req joinSessionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.InviteToken != "tok" || req.DisplayName != "ada" {
+			t.Errorf("request = %+v", req)
+		}
+
+		w.Write([]byte(`{"session_id":"s1","role":"viewer","display_name":"ada"}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	result, err := New(server.URL, "").JoinSession(context.Background(), "tok", "ada")
+	if err != nil {
+		t.Fatalf("JoinSession() error = %v", err)
+	}
+
+	if result.SessionID != "s1" || result.Role != "viewer" {
+		t.Errorf("JoinSession() = %+v", result)
+	}
+}
+
+func TestEndSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/sessions/s1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(`{"message":"session ended successfully"}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	if err := New(server.URL, "secret").EndSession(context.Background(), "s1"); err != nil {
+		t.Errorf("EndSession() error = %v", err)
+	}
+
+	if err := New(server.URL, "").EndSession(context.Background(), "s1"); !IsStatus(err, http.StatusUnauthorized) {
+		t.Errorf("EndSession() without a token error = %v, want 401", err)
+	}
+}
+
+func TestIsOffline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	endpoint := server.URL
+
+	if err := New(endpoint, "").Ping(context.Background()); IsOffline(err) {
+		t.Errorf("an API error = %v counts as offline", err)
+	}
+
+	server.Close()
+
+	err := New(endpoint, "").Ping(context.Background())
+	if !IsOffline(err) {
+		t.Errorf("Ping() of a closed server error = %v, want offline", err)
+	}
+	if !IsOffline(fmt.Errorf("request failed: %w", err)) {
+		t.Error("a wrapped network error should count as offline")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := New(endpoint, "").Ping(ctx); IsOffline(err) {
+		t.Errorf("a canceled request error = %v counts as offline", err)
+	}
+}
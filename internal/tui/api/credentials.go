@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// where logins are saved: credentials.json in the algopatterns directory of
+// the user's config dir (~/.config on Linux). empty if there is none
+func defaultCredentialsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, configDirName, credentialsFile)
+}
+
+// reads the saved credentials, if they belong to the client's endpoint.
+// missing or unreadable credentials leave the client signed out
+func (c *Client) loadCredentials() {
+	creds, err := readCredentials(c.credentialsPath)
+	if err != nil || creds.Endpoint != c.endpoint {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = creds.Token
+	c.refreshToken = creds.RefreshToken
+	c.expiresAt = creds.ExpiresAt
+	c.user = creds.User
+}
+
+// takes over the saved credentials if another client renewed them since
+// stale was read. reports whether it did
+func (c *Client) reloadCredentials(stale string) bool {
+	creds, err := readCredentials(c.credentialsPath)
+	if err != nil || creds.Endpoint != c.endpoint || creds.Token == "" || creds.Token == stale {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = creds.Token
+	c.refreshToken = creds.RefreshToken
+	c.expiresAt = creds.ExpiresAt
+	if creds.User != nil {
+		c.user = creds.User
+	}
+
+	return true
+}
+
+// saves the credentials so later runs stay signed in. the file is only
+// readable by the user
+func (c *Client) saveCredentials() error {
+	if c.credentialsPath == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	creds := credentials{
+		Endpoint:     c.endpoint,
+		Token:        c.token,
+		RefreshToken: c.refreshToken,
+		ExpiresAt:    c.expiresAt,
+		User:         c.user,
+	}
+	c.mu.Unlock()
+
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.credentialsPath), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// written next to the file and renamed, so a crash can't leave it half written
+	tmp, err := os.CreateTemp(filepath.Dir(c.credentialsPath), credentialsFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // gone after the rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck,gosec // already failing
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.credentialsPath); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	return nil
+}
+
+// signs the client out and removes the saved credentials
+func (c *Client) clearCredentials() error {
+	c.mu.Lock()
+	c.token = ""
+	c.refreshToken = ""
+	c.expiresAt = time.Time{}
+	c.user = nil
+	c.mu.Unlock()
+
+	if c.credentialsPath == "" {
+		return nil
+	}
+
+	if err := os.Remove(c.credentialsPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove credentials: %w", err)
+	}
+
+	return nil
+}
+
+func readCredentials(path string) (*credentials, error) {
+	if path == "" {
+		return nil, fs.ErrNotExist
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path of the user's own config dir
+	if err != nil {
+		return nil, err
+	}
+
+	var creds credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("invalid credentials file: %w", err)
+	}
+
+	return &creds, nil
+}
@@ -0,0 +1,338 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// connects to a session over WebSocket. the first message received is the
+// session_state
+func (c *Client) Connect(ctx context.Context, opts ConnectOptions) (*Conn, error) {
+	credential, err := c.liveCredential(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := c.liveURL(opts, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout:  requestTimeout,
+		EnableCompression: true,
+	}
+
+	ws, resp, err := dialer.DialContext(ctx, target, nil)
+	if err != nil {
+		// the server answers refused connections with a regular error response
+		if resp != nil {
+			defer resp.Body.Close() //nolint:errcheck
+
+			apiErr := &Error{StatusCode: resp.StatusCode}
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize)) //nolint:errcheck
+			if json.Unmarshal(data, apiErr) == nil && apiErr.Code != "" {
+				return nil, apiErr
+			}
+		}
+
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	ws.SetReadLimit(maxLiveMessageSize)
+
+	return &Conn{ws: ws, codeRate: defaultCodeUpdatesPerSecond}, nil
+}
+
+// the query parameter signing a connection in: a one-time ticket, or the
+// access token on servers that don't issue tickets. empty when signed out
+func (c *Client) liveCredential(ctx context.Context) (url.Values, error) {
+	if !c.Authenticated() {
+		return url.Values{}, nil
+	}
+
+	var ticket ticketResponse
+	err := c.do(ctx, http.MethodPost, "/ws/ticket", nil, nil, &ticket)
+	if err == nil {
+		return url.Values{"ticket": {ticket.Ticket}}, nil
+	}
+
+	if !IsStatus(err, http.StatusNotFound) {
+		return nil, err
+	}
+
+	token, err := c.Token(ctx)
+	if err != nil || token == "" {
+		return url.Values{}, err
+	}
+
+	return url.Values{"token": {token}}, nil
+}
+
+// the WebSocket URL of a connection
+func (c *Client) liveURL(opts ConnectOptions, credential url.Values) (string, error) {
+	u, err := url.Parse(c.endpoint + "/api/v1/ws")
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+
+	query := credential
+	query.Set("session_id", opts.SessionID)
+	if opts.InviteToken != "" {
+		query.Set("invite", opts.InviteToken)
+	}
+	if opts.DisplayName != "" {
+		query.Set("display_name", opts.DisplayName)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// reads the messages of the next frame, which may carry several. the limits
+// the server announces are applied to the connection as they arrive
+func (c *Conn) Read() ([]Message, error) {
+	_, data, err := c.ws.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var msg Message
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return messages, fmt.Errorf("invalid message: %w", err)
+		}
+
+		c.observe(msg)
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// keeps track of the role, permissions and limits the server sets
+func (c *Conn) observe(msg Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch msg.Type {
+	case MessageSessionState:
+		var state SessionState
+		if msg.Decode(&state) != nil {
+			return
+		}
+
+		c.role = state.YourRole
+		c.permissions = state.YourPermissions
+		c.slowMode = time.Duration(state.ChatSlowMode) * time.Second
+		if state.CodeUpdatesPerSecond > 0 {
+			c.codeRate = state.CodeUpdatesPerSecond
+		}
+
+	case MessageRateLimitUpdate:
+		var update RateLimitUpdate
+		if msg.Decode(&update) == nil && update.CodeUpdatesPerSecond > 0 {
+			c.codeRate = update.CodeUpdatesPerSecond
+		}
+
+	case MessagePermissionsUpdated:
+		var update PermissionsUpdated
+		if msg.Decode(&update) == nil {
+			c.permissions = update.Permissions
+		}
+
+	case MessageChatSettings:
+		var settings ChatSettings
+		if msg.Decode(&settings) == nil {
+			c.slowMode = time.Duration(settings.SlowModeSeconds) * time.Second
+		}
+	}
+}
+
+// reports whether the connection may do what the permission allows. the
+// host may do everything
+func (c *Conn) Can(permission string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.role == "host" || slices.Contains(c.permissions, permission)
+}
+
+// shares the code with the session. updates are sent at most at the rate
+// the server allows; ones coming sooner are merged into the latest, which
+// is sent once the rate allows
+func (c *Conn) SendCode(update CodeUpdate) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	wait := time.Until(c.lastCode.Add(c.codeInterval()))
+	if wait <= 0 && c.pendingCode == nil {
+		return c.writeCode(update)
+	}
+
+	pending := update
+	c.pendingCode = &pending
+	if c.codeTimer == nil {
+		c.codeTimer = time.AfterFunc(max(wait, 0), c.flushCode)
+	}
+
+	return nil
+}
+
+// sends the latest held-back code update
+func (c *Conn) flushCode() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.codeTimer = nil
+	if c.pendingCode == nil || c.closed {
+		return
+	}
+
+	update := *c.pendingCode
+	c.pendingCode = nil
+
+	if err := c.writeCode(update); err != nil && c.err == nil {
+		c.err = err
+	}
+}
+
+// sends a code update (must be called with the lock held)
+func (c *Conn) writeCode(update CodeUpdate) error {
+	c.lastCode = time.Now()
+	return c.write(MessageCodeUpdate, update)
+}
+
+// the time between code updates at the current rate (must be called with
+// the lock held)
+func (c *Conn) codeInterval() time.Duration {
+	return time.Second / time.Duration(max(c.codeRate, 1))
+}
+
+// reports whether a code update is waiting to be sent
+func (c *Conn) CodePending() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pendingCode != nil
+}
+
+// sends a chat message. messages over the server's chat limits are refused
+// with ErrChatLimited instead of being sent to be rejected
+func (c *Conn) SendChat(message string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	now := time.Now()
+
+	// the server counts messages of the last minute
+	recent := c.chatTimes[:0]
+	for _, t := range c.chatTimes {
+		if now.Sub(t) < time.Minute {
+			recent = append(recent, t)
+		}
+	}
+	c.chatTimes = recent
+
+	if len(c.chatTimes) >= maxChatMessagesPerMinute {
+		wait := time.Minute - now.Sub(c.chatTimes[0])
+		return fmt.Errorf("%w: wait %s", ErrChatLimited, wait.Round(time.Second))
+	}
+
+	exempt := c.role == "host" || slices.Contains(c.permissions, "manage_participants")
+	if c.slowMode > 0 && !exempt && len(c.chatTimes) > 0 {
+		if wait := c.slowMode - now.Sub(c.chatTimes[len(c.chatTimes)-1]); wait > 0 {
+			return fmt.Errorf("%w: slow mode is on, wait %s", ErrChatLimited, wait.Round(time.Second))
+		}
+	}
+
+	if err := c.write(MessageChatMessage, ChatMessage{Message: message}); err != nil {
+		return err
+	}
+
+	c.chatTimes = append(c.chatTimes, now)
+	return nil
+}
+
+// writes a message (must be called with the lock held)
+func (c *Conn) write(messageType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", messageType, err)
+	}
+
+	frame, err := json.Marshal(Message{Type: messageType, Payload: data})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", messageType, err)
+	}
+
+	c.ws.SetWriteDeadline(time.Now().Add(liveWriteWait)) //nolint:errcheck,gosec // surfaces as a write error
+	return c.ws.WriteMessage(websocket.TextMessage, frame)
+}
+
+// closes the connection, sending the code update still held back
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	if c.codeTimer != nil {
+		c.codeTimer.Stop()
+		c.codeTimer = nil
+	}
+	if c.pendingCode != nil {
+		c.writeCode(*c.pendingCode) //nolint:errcheck,gosec // best-effort, the connection is closing
+		c.pendingCode = nil
+	}
+
+	c.ws.SetWriteDeadline(time.Now().Add(liveWriteWait))                                                      //nolint:errcheck,gosec // closing anyway
+	c.ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")) //nolint:errcheck,gosec // closing anyway
+
+	return c.ws.Close()
+}
+
+// reports whether err means the connection was closed normally
+func IsClosed(err error) bool {
+	return websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) || errors.Is(err, net.ErrClosed)
+}
+
+// decodes the payload of a message
+func (m Message) Decode(payload any) error {
+	return json.Unmarshal(m.Payload, payload)
+}
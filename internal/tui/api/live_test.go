@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// a session server that sends frames on connect and passes on what it
+// receives
+func liveServer(t *testing.T, frames ...string) (*Client, <-chan Message) {
+	t.Helper()
+
+	received := make(chan Message, 16)
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/ws/ticket" {
+			if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" {
+				t.Errorf("unexpected ticket request %s %s", r.Method, r.URL)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ticket":"ticket-1","expires_in":30}`)) //nolint:errcheck,gosec // test server
+			return
+		}
+
+		query := r.URL.Query()
+		if r.URL.Path != "/api/v1/ws" || query.Get("session_id") != "s1" || query.Get("ticket") != "ticket-1" || query.Has("token") {
+			t.Errorf("unexpected connection %s", r.URL)
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		for _, frame := range frames {
+			conn.WriteMessage(websocket.TextMessage, []byte(frame)) //nolint:errcheck,gosec // test server
+		}
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var msg Message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				t.Errorf("invalid message %s", data)
+				return
+			}
+			received <- msg
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return New(server.URL, "secret"), received
+}
+
+func connect(t *testing.T, client *Client) *Conn {
+	t.Helper()
+
+	conn, err := client.Connect(context.Background(), ConnectOptions{SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck,gosec // test cleanup
+
+	return conn
+}
+
+func TestReadSharedFrame(t *testing.T) {
+	client, _ := liveServer(t,
+		`{"type":"session_state","payload":{"code":"s(\"bd\")","your_role":"viewer","your_permissions":["chat"]}}`+"\n"+
+			`{"type":"chat_message","payload":{"message":"hi","display_name":"ada"}}`,
+	)
+	conn := connect(t, client)
+
+	messages, err := conn.Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(messages) != 2 || messages[0].Type != MessageSessionState || messages[1].Type != MessageChatMessage {
+		t.Fatalf("Read() = %+v, want session_state and chat_message", messages)
+	}
+
+	if conn.Can("edit_code") || !conn.Can("chat") {
+		t.Error("permissions of session_state not applied")
+	}
+}
+
+func TestSendCodeKeepsToRate(t *testing.T) {
+	client, received := liveServer(t, `{"type":"rate_limit_update","payload":{"code_updates_per_second":5}}`)
+	conn := connect(t, client)
+
+	if _, err := conn.Read(); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	start := time.Now()
+	for _, code := range []string{"a", "ab", "abc"} {
+		if err := conn.SendCode(CodeUpdate{Code: code}); err != nil {
+			t.Fatalf("SendCode() error = %v", err)
+		}
+	}
+
+	var update CodeUpdate
+
+	if err := (<-received).Decode(&update); err != nil || update.Code != "a" {
+		t.Errorf("first update = %q, want it sent right away", update.Code)
+	}
+
+	// the updates in between are merged into the latest
+	if err := (<-received).Decode(&update); err != nil || update.Code != "abc" {
+		t.Errorf("second update = %q, want the latest code", update.Code)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("second update sent after %s, want it held back to 5 per second", elapsed)
+	}
+
+	select {
+	case msg := <-received:
+		t.Errorf("unexpected extra message %+v", msg)
+	case <-time.After(250 * time.Millisecond):
+	}
+}
+
+func TestSendChatSlowMode(t *testing.T) {
+	client, received := liveServer(t, `{"type":"session_state","payload":{"your_role":"co-author","your_permissions":["chat"],"chat_slow_mode_seconds":30}}`)
+	conn := connect(t, client)
+
+	if _, err := conn.Read(); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if err := conn.SendChat("first"); err != nil {
+		t.Fatalf("SendChat() error = %v", err)
+	}
+	if err := conn.SendChat("second"); !errors.Is(err, ErrChatLimited) {
+		t.Errorf("SendChat() during slow mode error = %v, want ErrChatLimited", err)
+	}
+
+	msg := <-received
+	var chat ChatMessage
+	if msg.Type != MessageChatMessage || msg.Decode(&chat) != nil || chat.Message != "first" {
+		t.Errorf("server received %+v", msg)
+	}
+}
+
+func TestConnectRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"unauthorized","message":"valid authentication required"}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL, "").Connect(context.Background(), ConnectOptions{SessionID: "s1"})
+	if !IsStatus(err, http.StatusUnauthorized) {
+		t.Errorf("Connect() error = %v, want the 401 API error", err)
+	}
+}
+
+func TestConnectWithoutTickets(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// an older server, signing connections in with the access token
+		if r.URL.Path == "/api/v1/ws/ticket" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.URL.Query().Get("token") != "secret" {
+			t.Errorf("unexpected connection %s", r.URL)
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade: %v", err)
+			return
+		}
+		conn.Close() //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	connect(t, New(server.URL, "secret"))
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// lists the sessions the user hosts or takes part in, most recently active
+// first. needs a token
+func (c *Client) ListSessions(ctx context.Context, activeOnly bool) ([]Session, error) {
+	query := url.Values{"limit": {strconv.Itoa(listLimit)}}
+	if activeOnly {
+		query.Set("active_only", "true")
+	}
+
+	var resp sessionsResponse
+	if err := c.do(ctx, http.MethodGet, "/sessions", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Sessions, nil
+}
+
+// lists discoverable live sessions, and the user's own when authenticated
+func (c *Client) ListLiveSessions(ctx context.Context) ([]LiveSession, error) {
+	query := url.Values{"limit": {strconv.Itoa(listLimit)}}
+
+	var resp liveSessionsResponse
+	if err := c.do(ctx, http.MethodGet, "/sessions/live", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Sessions, nil
+}
+
+// creates a session hosted by the user
+func (c *Client) CreateSession(ctx context.Context, title, code string, discoverable bool) (*Session, error) {
+	req := createSessionRequest{Title: title, Code: code, IsDiscoverable: &discoverable}
+
+	var session Session
+	if err := c.do(ctx, http.MethodPost, "/sessions", nil, req, &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// joins a session with an invite token. without a token the user joins as
+// a guest under displayName
+func (c *Client) JoinSession(ctx context.Context, inviteToken, displayName string) (*JoinResult, error) {
+	req := joinSessionRequest{InviteToken: inviteToken, DisplayName: displayName}
+
+	var result JoinResult
+	if err := c.do(ctx, http.MethodPost, "/sessions/join", nil, req, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// lists the participants of a session the user is part of
+func (c *Client) ListParticipants(ctx context.Context, sessionID string) ([]Participant, error) {
+	query := url.Values{"limit": {strconv.Itoa(listLimit)}}
+
+	var resp participantsResponse
+	if err := c.do(ctx, http.MethodGet, "/sessions/"+url.PathEscape(sessionID)+"/participants", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Participants, nil
+}
+
+// ends a session the user hosts, disconnecting everyone in it
+func (c *Client) EndSession(ctx context.Context, sessionID string) error {
+	return c.do(ctx, http.MethodDelete, "/sessions/"+url.PathEscape(sessionID), nil, nil, nil)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// lists a page of the user's strudels, most recently saved first. search
+// matches titles and descriptions; a strudel must have every tag. cursor is
+// the NextCursor or PrevCursor of an earlier page, empty for the first
+func (c *Client) ListStrudels(ctx context.Context, search string, tags []string, cursor string) (*StrudelPage, error) {
+	query := url.Values{
+		"limit": {strconv.Itoa(listLimit)},
+		"sort":  {"-updated_at"},
+	}
+	if search != "" {
+		query.Set("search", search)
+	}
+	if len(tags) > 0 {
+		query.Set("tags", strings.Join(tags, ","))
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var page StrudelPage
+	if err := c.do(ctx, http.MethodGet, "/strudels", query, nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// fetches a strudel of the user, or a public one
+func (c *Client) GetStrudel(ctx context.Context, id string) (*Strudel, error) {
+	var strudel Strudel
+	if err := c.do(ctx, http.MethodGet, "/strudels/"+url.PathEscape(id), nil, nil, &strudel); err != nil {
+		return nil, err
+	}
+
+	return &strudel, nil
+}
+
+// saves a new strudel
+func (c *Client) CreateStrudel(ctx context.Context, req NewStrudel) (*Strudel, error) {
+	var strudel Strudel
+	if err := c.do(ctx, http.MethodPost, "/strudels", nil, req, &strudel); err != nil {
+		return nil, err
+	}
+
+	return &strudel, nil
+}
+
+// changes a strudel of the user. UpdatedAt is required and a strudel saved
+// since fails with a 409 API error
+func (c *Client) UpdateStrudel(ctx context.Context, id string, req StrudelUpdate) (*Strudel, error) {
+	var strudel Strudel
+	if err := c.do(ctx, http.MethodPut, "/strudels/"+url.PathEscape(id), nil, req, &strudel); err != nil {
+		return nil, err
+	}
+
+	return &strudel, nil
+}
+
+// saves a private copy of a strudel that credits it as the original. the
+// copy keeps the original's CC signal, since a fork can't be more permissive
+func (c *Client) ForkStrudel(ctx context.Context, original *Strudel) (*Strudel, error) {
+	id := original.ID
+
+	return c.CreateStrudel(ctx, NewStrudel{
+		Title:      SuffixTitle(original.Title, " (fork)"),
+		Code:       original.Code,
+		CCSignal:   original.CCSignal,
+		ForkedFrom: &id,
+		Tags:       original.Tags,
+	})
+}
+
+// adds suffix to a title, shortening the title to keep within the API's
+// title limit
+func SuffixTitle(title, suffix string) string {
+	if runes := []rune(title); len(runes)+len([]rune(suffix)) > maxStrudelTitleLength {
+		title = string(runes[:maxStrudelTitleLength-len([]rune(suffix))])
+	}
+
+	return title + suffix
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListStrudelsQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/v1/strudels" || query.Get("search") != "bass" || query.Get("tags") != "techno,dark" ||
+			query.Get("cursor") != "next" || query.Get("sort") != "-updated_at" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+
+		w.Write([]byte(`{"strudels":[{"id":"s1","title":"bassline","tags":["techno","dark"]}],"pagination":{"limit":100,"has_more":true,"next_cursor":"after","total":101}}`)) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	page, err := New(server.URL, "secret").ListStrudels(context.Background(), "bass", []string{"techno", "dark"}, "next")
+	if err != nil {
+		t.Fatalf("ListStrudels() error = %v", err)
+	}
+
+	if len(page.Strudels) != 1 || page.Strudels[0].Title != "bassline" || page.Pagination.NextCursor != "after" || *page.Pagination.Total != 101 {
+		t.Errorf("ListStrudels() = %+v", page)
+	}
+}
+
+func TestForkStrudel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req NewStrudel
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		if r.Method != http.MethodPost || req.ForkedFrom == nil || *req.ForkedFrom != "s1" {
+			t.Errorf("fork request = %s %+v", r.Method, req)
+		}
+		if req.CCSignal == nil || *req.CCSignal != "no-ai" || req.IsPublic {
+			t.Errorf("fork should be private and keep the signal, got %+v", req)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Strudel{ID: "s2", Title: req.Title, ForkedFrom: req.ForkedFrom}) //nolint:errcheck,gosec // test server
+	}))
+	defer server.Close()
+
+	signal := "no-ai"
+	fork, err := New(server.URL, "secret").ForkStrudel(context.Background(), &Strudel{ID: "s1", Title: "groove", Code: `s("bd")`, CCSignal: &signal})
+	if err != nil {
+		t.Fatalf("ForkStrudel() error = %v", err)
+	}
+
+	if fork.ID != "s2" || fork.Title != "groove (fork)" {
+		t.Errorf("ForkStrudel() = %+v", fork)
+	}
+}
+
+func TestSuffixTitleFitsLimit(t *testing.T) {
+	title := SuffixTitle(strings.Repeat("ü", maxStrudelTitleLength), " (fork)")
+
+	if n := len([]rune(title)); n != maxStrudelTitleLength || !strings.HasSuffix(title, " (fork)") {
+		t.Errorf("SuffixTitle() = %d runes %q", n, title)
+	}
+}
+
+func TestStrudelUpdateTags(t *testing.T) {
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		update StrudelUpdate
+		want   string
+	}{
+		{"unchanged", StrudelUpdate{UpdatedAt: &updatedAt}, `{"tags":null,"updated_at":"2026-03-01T12:00:00Z"}`},
+		{"cleared", StrudelUpdate{Tags: []string{}}, `{"tags":[]}`},
+		{"set", StrudelUpdate{Tags: []string{"dub"}}, `{"tags":["dub"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.update)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,429 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// API used when ALGOPATTERNS_API_ENDPOINT isn't set
+const DefaultEndpoint = "http://localhost:8080"
+
+const (
+	// timeout of API requests without a deadline of their own
+	requestTimeout = 30 * time.Second
+
+	// the agent's answers take longer
+	generateTimeout = 60 * time.Second
+
+	// largest response read
+	maxResponseSize = 8 << 20
+
+	// items requested per list, the API's maximum page
+	listLimit = 100
+
+	// longest strudel title the API accepts
+	maxStrudelTitleLength = 200
+
+	// tokens are renewed when they expire sooner than this
+	refreshMargin = time.Minute
+
+	// saved logins, in the user's config dir
+	configDirName   = "algopatterns"
+	credentialsFile = "credentials.json"
+)
+
+// limits of live connections, matching the server's
+const (
+	// code updates per second until the server announces another rate
+	defaultCodeUpdatesPerSecond = 30
+
+	// chat messages per minute
+	maxChatMessagesPerMinute = 20
+
+	// largest frame read
+	maxLiveMessageSize = 4 << 20
+
+	// time allowed to write a message
+	liveWriteWait = 10 * time.Second
+)
+
+// device logins are polled at least this far apart, and this much slower
+// each time the server asks to slow down. a variable so tests can poll faster
+var minDevicePollInterval = 5 * time.Second
+
+var (
+	// returned when a chat message would go over the session's chat limits
+	ErrChatLimited = errors.New("sending messages too quickly")
+
+	ErrLoginDenied    = errors.New("login was denied")
+	ErrLoginExpired   = errors.New("login code expired, try again")
+	ErrSessionExpired = errors.New("signed out, login again")
+)
+
+// REST client of the algopatterns API
+type Client struct {
+	endpoint        string
+	httpClient      *http.Client
+	credentialsPath string // empty if logins aren't saved
+
+	mu           sync.Mutex
+	token        string
+	refreshToken string
+	expiresAt    time.Time // zero if unknown
+	user         *User
+
+	// held while renewing, so a refresh token isn't spent twice
+	refreshMu sync.Mutex
+}
+
+// a signed-in user
+type User struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// a saved login
+type credentials struct {
+	Endpoint     string    `json:"endpoint"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitzero"`
+	User         *User     `json:"user,omitempty"`
+}
+
+// a device login in progress: the user enters UserCode at VerificationURI
+type DeviceLogin struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+type authResponse struct {
+	User         *User  `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type ticketResponse struct {
+	Ticket string `json:"ticket"`
+}
+
+type userResponse struct {
+	User *User `json:"user"`
+}
+
+type deviceTokenRequest struct {
+	DeviceCode string `json:"device_code"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// an error response of the API
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"error"` // e.g. "unauthorized", "not_found"
+	Message    string `json:"message"`
+}
+
+// a session the user hosts or takes part in
+type Session struct {
+	ID             string        `json:"id"`
+	HostUserID     string        `json:"host_user_id"`
+	Title          string        `json:"title"`
+	Code           string        `json:"code"`
+	IsActive       bool          `json:"is_active"`
+	IsDiscoverable bool          `json:"is_discoverable"`
+	CreatedAt      time.Time     `json:"created_at"`
+	EndedAt        *time.Time    `json:"ended_at,omitempty"`
+	LastActivity   time.Time     `json:"last_activity"`
+	Participants   []Participant `json:"participants,omitempty"`
+}
+
+// a live session, discoverable or the user's own
+type LiveSession struct {
+	ID               string    `json:"id"`
+	Title            string    `json:"title"`
+	ParticipantCount int       `json:"participant_count"`
+	IsMember         bool      `json:"is_member"`
+	IsDiscoverable   bool      `json:"is_discoverable"`
+	CreatedAt        time.Time `json:"created_at"`
+	LastActivity     time.Time `json:"last_activity"`
+}
+
+type Participant struct {
+	ID          string     `json:"id"`
+	UserID      *string    `json:"user_id,omitempty"`
+	DisplayName *string    `json:"display_name,omitempty"`
+	Role        string     `json:"role"` // host, co-author or viewer
+	Status      string     `json:"status"`
+	Muted       bool       `json:"muted"`
+	JoinedAt    time.Time  `json:"joined_at"`
+	LeftAt      *time.Time `json:"left_at,omitempty"`
+}
+
+// the session joined with an invite token
+type JoinResult struct {
+	SessionID   string `json:"session_id"`
+	Role        string `json:"role"`
+	DisplayName string `json:"display_name"`
+}
+
+type sessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+type liveSessionsResponse struct {
+	Sessions []LiveSession `json:"sessions"`
+}
+
+type participantsResponse struct {
+	Participants []Participant `json:"participants"`
+}
+
+type createSessionRequest struct {
+	Title          string `json:"title"`
+	Code           string `json:"code"`
+	IsDiscoverable *bool  `json:"is_discoverable,omitempty"`
+}
+
+type joinSessionRequest struct {
+	InviteToken string `json:"invite_token"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// types of WebSocket messages
+const (
+	MessageSessionState       = "session_state"
+	MessageCodeUpdate         = "code_update"
+	MessageChatMessage        = "chat_message"
+	MessageUserJoined         = "user_joined"
+	MessageUserLeft           = "user_left"
+	MessageRateLimitUpdate    = "rate_limit_update"
+	MessagePermissionsUpdated = "permissions_updated"
+	MessageChatSettings       = "chat_settings"
+	MessageSessionEnded       = "session_ended"
+	MessageServerShutdown     = "server_shutdown"
+	MessageError              = "error"
+)
+
+// who to connect to a session as
+type ConnectOptions struct {
+	SessionID   string
+	InviteToken string // joins with the invite's role, otherwise the host or a viewer of a discoverable session
+	DisplayName string // used by guests
+}
+
+// a live connection to a session
+type Conn struct {
+	ws *websocket.Conn
+
+	mu          sync.Mutex // serializes writes and guards the fields below
+	role        string
+	permissions []string
+	codeRate    int // code updates per second
+	lastCode    time.Time
+	pendingCode *CodeUpdate // held back until the rate allows
+	codeTimer   *time.Timer
+	chatTimes   []time.Time // chat messages sent in the last minute
+	slowMode    time.Duration
+	err         error // failure sending a held-back update
+	closed      bool
+}
+
+// envelope of WebSocket messages
+type Message struct {
+	Type      string          `json:"type"`
+	SessionID string          `json:"session_id,omitempty"`
+	UserID    string          `json:"user_id,omitempty"`
+	Timestamp time.Time       `json:"timestamp,omitzero"`
+	Sequence  uint64          `json:"seq,omitempty"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// first message of a connection
+type SessionState struct {
+	Code                 string        `json:"code"`
+	Revision             int           `json:"revision"`
+	YourRole             string        `json:"your_role"`
+	YourDisplayName      string        `json:"your_display_name"`
+	YourPermissions      []string      `json:"your_permissions"`
+	Participants         []Presence    `json:"participants"`
+	ChatHistory          []ChatHistory `json:"chat_history"`
+	ListenerCount        int           `json:"listener_count"`
+	ChatSlowMode         int           `json:"chat_slow_mode_seconds"`
+	CodeUpdatesPerSecond int           `json:"code_updates_per_second,omitempty"`
+}
+
+// a participant connected to the session
+type Presence struct {
+	UserID      string `json:"user_id,omitempty"`
+	DisplayName string `json:"display_name"`
+	Role        string `json:"role"`
+	Quality     string `json:"connection_quality,omitempty"` // good, fair or poor
+}
+
+// a chat message sent before the connection
+type ChatHistory struct {
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"display_name"`
+	Content     string `json:"content"`
+	Timestamp   int64  `json:"timestamp"` // Unix milliseconds
+}
+
+// the whole code of the session, sent on every edit
+type CodeUpdate struct {
+	Code        string `json:"code"`
+	CursorLine  int    `json:"cursor_line,omitempty"`
+	CursorCol   int    `json:"cursor_col,omitempty"`
+	DisplayName string `json:"display_name,omitempty"` // set by the server
+	Source      string `json:"source,omitempty"`
+}
+
+type ChatMessage struct {
+	Message     string `json:"message"`
+	DisplayName string `json:"display_name,omitempty"` // set by the server
+	MessageID   string `json:"message_id,omitempty"`
+}
+
+type UserJoined struct {
+	UserID      string `json:"user_id,omitempty"`
+	DisplayName string `json:"display_name"`
+	Role        string `json:"role"`
+}
+
+type UserLeft struct {
+	UserID      string `json:"user_id,omitempty"`
+	DisplayName string `json:"display_name"`
+}
+
+type RateLimitUpdate struct {
+	CodeUpdatesPerSecond int `json:"code_updates_per_second"`
+}
+
+type PermissionsUpdated struct {
+	Permissions []string `json:"permissions"`
+}
+
+type ChatSettings struct {
+	SlowModeSeconds int `json:"slow_mode_seconds"`
+}
+
+type SessionEnded struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// an error the server answered a message with
+type ServerError struct {
+	Code    string `json:"error"`
+	Message string `json:"message"`
+}
+
+// CC signals a strudel can carry, from the most permissive to no-ai
+var CCSignals = []string{"cc-cr", "cc-dc", "cc-ec", "cc-op", "no-ai"}
+
+// a saved strudel
+type Strudel struct {
+	ID                string    `json:"id"`
+	UserID            string    `json:"user_id"`
+	Title             string    `json:"title"`
+	Code              string    `json:"code"`
+	IsPublic          bool      `json:"is_public"`
+	CCSignal          *string   `json:"cc_signal,omitempty"`
+	EffectiveCCSignal *string   `json:"effective_cc_signal,omitempty"` // the most restrictive of it and its originals
+	ForkedFrom        *string   `json:"forked_from,omitempty"`
+	Description       string    `json:"description,omitempty"`
+	Tags              []string  `json:"tags,omitempty"`
+	AutoTags          []string  `json:"auto_tags,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// a page of strudels
+type StrudelPage struct {
+	Strudels   []Strudel `json:"strudels"`
+	Pagination struct {
+		NextCursor string `json:"next_cursor,omitempty"`
+		PrevCursor string `json:"prev_cursor,omitempty"`
+		HasMore    bool   `json:"has_more"`
+		Total      *int   `json:"total,omitempty"`
+	} `json:"pagination"`
+}
+
+// a strudel to save
+type NewStrudel struct {
+	Title      string   `json:"title"`
+	Code       string   `json:"code"`
+	IsPublic   bool     `json:"is_public"`
+	CCSignal   *string  `json:"cc_signal,omitempty"`
+	ForkedFrom *string  `json:"forked_from,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// changes to a strudel; nil fields are left as they are. an empty, non-nil
+// Tags removes every tag
+type StrudelUpdate struct {
+	Title     *string    `json:"title,omitempty"`
+	Code      *string    `json:"code,omitempty"`
+	CCSignal  *string    `json:"cc_signal,omitempty"`
+	Tags      []string   `json:"tags"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"` // the version the change is based on
+}
+
+// a request for the agent
+type GenerateRequest struct {
+	UserQuery           string           `json:"user_query"`
+	EditorState         string           `json:"editor_state,omitempty"`
+	ConversationHistory []HistoryMessage `json:"conversation_history,omitempty"`
+}
+
+// a message of a conversation with the agent
+type HistoryMessage struct {
+	Role    string `json:"role"` // user or assistant
+	Content string `json:"content"`
+}
+
+// the agent's answer: code, or questions when the query wasn't clear enough
+type Generation struct {
+	Code                string     `json:"code,omitempty"`
+	IsActionable        bool       `json:"is_actionable"`
+	IsCodeResponse      bool       `json:"is_code_response"`
+	ClarifyingQuestions []string   `json:"clarifying_questions,omitempty"`
+	DocsRetrieved       int        `json:"docs_retrieved"`
+	ExamplesRetrieved   int        `json:"examples_retrieved"`
+	Model               string     `json:"model"`
+	Cached              bool       `json:"cached,omitempty"`
+	Degraded            bool       `json:"degraded,omitempty"`
+	RateLimit           *RateLimit `json:"rate_limit,omitempty"`
+}
+
+// the caller's AI usage today; limit and remaining are -1 when not enforced
+type RateLimit struct {
+	Tier      string    `json:"tier"`
+	Limit     int       `json:"limit"`
+	Current   int       `json:"current"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+}
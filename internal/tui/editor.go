@@ -12,10 +12,12 @@ import (
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/term"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
 )
 
-// returns a new code editor
-func NewEditor() *EditorModel {
+// returns a new code editor, on the draft of the last run
+func NewEditor(client *api.Client, sync *Syncer) *EditorModel {
 	ti := textinput.New()
 	ti.Placeholder = "type your strudel ideas and press enter to get AI assistance..."
 	ti.Focus()
@@ -53,18 +55,22 @@ func NewEditor() *EditorModel {
 	vp := viewport.New(physicalWidth, physicalHeight-6)
 	vp.YPosition = 0
 
+	history, strudel := sync.draft()
+
 	return &EditorModel{
 		input:               ti,
 		viewport:            vp,
-		conversationHistory: []MessageModel{},
+		conversationHistory: history,
 		isFetching:          false,
 		spinner:             s,
 		glamourRenderer:     renderer,
 		width:               physicalWidth,
 		height:              physicalHeight,
 		ready:               true,
-		shouldScrollBottom:  false,
-		agentClient:         NewAgentClient(),
+		shouldScrollBottom:  len(history) > 0,
+		agentClient:         NewAgentClient(client),
+		sync:                sync,
+		strudel:             strudel,
 	}
 }
 
@@ -90,31 +96,41 @@ func (m *EditorModel) Update(msg tea.Msg) (*EditorModel, tea.Cmd) {
 			query := m.input.Value()
 
 			if strings.TrimSpace(query) != "" {
+				// queries wait behind those queued while offline
+				if m.sync.Offline() || m.sync.HasQueries() {
+					m.input.SetValue("")
+					m.shouldScrollBottom = true
+					return m, m.sync.QueueQuery(query)
+				}
+
 				m.isFetching = true
+				m.pendingQuery = query
+				m.streamingContent = ""
 				m.input.SetValue("")
 
-				// get current code from last agent message
-				currentCode := ""
-				for i := len(m.conversationHistory) - 1; i >= 0; i-- {
-					if m.conversationHistory[i].Role == "assistant" {
-						currentCode = m.conversationHistory[i].Content
-						break
-					}
-				}
+				currentCode := m.GetCode()
 
-				// send to agent using REST API
+				// stream from agent using REST API
 				return m, tea.Batch(
 					m.spinner.Tick,
-					m.agentClient.GenerateCmd(query, currentCode, m.conversationHistory),
+					m.agentClient.GenerateStreamCmd(query, currentCode, m.conversationHistory),
 				)
 			}
 
 			return m, nil
 
+		case "ctrl+o":
+			// the library saves the code, or opens another strudel
+			return m, func() tea.Msg { return EnterLibraryMsg{} }
+
 		case "ctrl+l":
 			m.input.SetValue("")
 			m.conversationHistory = []MessageModel{}
+			m.strudel = nil
 			m.isFetching = false
+			m.pendingQuery = ""
+			m.streamingContent = ""
+			m.saveDraft()
 			return m, nil
 
 		case "pgup", "pgdown":
@@ -144,8 +160,21 @@ func (m *EditorModel) Update(msg tea.Msg) (*EditorModel, tea.Cmd) {
 			return m, cmd
 		}
 
+	case AgentStreamChunkMsg:
+		if !m.isFetching {
+			// conversation was cleared, drain the rest of the stream
+			return m, waitForStream(msg.stream)
+		}
+
+		m.streamingContent += msg.chunk
+		m.shouldScrollBottom = true
+
+		return m, waitForStream(msg.stream)
+
 	case AgentResponseMsg:
 		m.isFetching = false
+		m.pendingQuery = ""
+		m.streamingContent = ""
 
 		// append both user query and assistant response to history (for display)
 		m.conversationHistory = append(m.conversationHistory,
@@ -161,6 +190,8 @@ func (m *EditorModel) Update(msg tea.Msg) (*EditorModel, tea.Cmd) {
 			},
 		)
 
+		m.saveDraft()
+
 		// scroll to bottom to show new message
 		m.shouldScrollBottom = true
 
@@ -169,6 +200,16 @@ func (m *EditorModel) Update(msg tea.Msg) (*EditorModel, tea.Cmd) {
 
 	case AgentErrorMsg:
 		m.isFetching = false
+		m.pendingQuery = ""
+		m.streamingContent = ""
+
+		// asked again once the API can be reached
+		if api.IsOffline(msg.err) {
+			m.sync.markOffline()
+			m.shouldScrollBottom = true
+			m.input.Focus()
+			return m, m.sync.QueueQuery(msg.userQuery)
+		}
 
 		// append user query and error to history
 		m.conversationHistory = append(m.conversationHistory,
@@ -181,6 +222,7 @@ func (m *EditorModel) Update(msg tea.Msg) (*EditorModel, tea.Cmd) {
 				Content: fmt.Sprintf("Error: %v", msg.err),
 			},
 		)
+		m.saveDraft()
 
 		// scroll to bottom to show error
 		m.shouldScrollBottom = true
@@ -251,10 +293,14 @@ func (m *EditorModel) View() string {
 	}
 
 	// header
-	header := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(colorPurple).
-		Render("")
+	status := identity(m.agentClient.api)
+	if m.strudel != nil {
+		status += fmt.Sprintf(" · editing %q", m.strudel.Title)
+	}
+	if sync := m.sync.Status(); sync != "" {
+		status += " · " + sync
+	}
+	header := infoStyle.Render(status)
 
 	headerLine := lipgloss.JoinHorizontal(
 		lipgloss.Left,
@@ -283,7 +329,7 @@ func (m *EditorModel) View() string {
 
 	help := lipgloss.NewStyle().
 		Foreground(colorGray).
-		Render("send: enter | scroll: pgup/pgdn, ctrl+↑/↓ | clear: ctrl+l | exit: ctrl+c")
+		Render("send: enter | scroll: pgup/pgdn, ctrl+↑/↓ | clear: ctrl+l | library: ctrl+o | exit: ctrl+c")
 
 	helpLine := lipgloss.JoinHorizontal(
 		lipgloss.Left,
@@ -300,11 +346,23 @@ func (m *EditorModel) View() string {
 func (m *EditorModel) renderChatHistory() string {
 	var b strings.Builder
 
-	if len(m.conversationHistory) == 0 {
+	messages := m.conversationHistory
+
+	// show the response being streamed after the history
+	if m.isFetching && m.streamingContent != "" {
+		messages = append(messages[:len(messages):len(messages)],
+			MessageModel{Role: "user", Content: m.pendingQuery},
+			MessageModel{Role: "assistant", Content: m.streamingContent},
+		)
+	}
+
+	queued := m.sync.queuedQueries()
+
+	if len(messages) == 0 && len(queued) == 0 {
 		return b.String()
 	}
 
-	for i, msg := range m.conversationHistory {
+	for i, msg := range messages {
 		if i > 0 {
 			b.WriteString("\n")
 		}
@@ -430,15 +488,57 @@ func (m *EditorModel) renderChatHistory() string {
 		}
 	}
 
+	// queries waiting for the API, answered in order once it is back
+	for _, query := range queued {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(colorGray).Bold(true).Render("> you, queued"))
+		b.WriteString("\n\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(colorLightGray).Render(query))
+		b.WriteString("\n")
+	}
+	if len(queued) > 0 {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(colorGray).Italic(true).Render("sent when the API can be reached again"))
+		b.WriteString("\n")
+	}
+
 	return b.String()
 }
 
 func (m *EditorModel) GetCode() string {
-	// return the last assistant message
+	// return the last assistant message, skipping failed requests
 	for i := len(m.conversationHistory) - 1; i >= 0; i-- {
-		if m.conversationHistory[i].Role == "assistant" {
-			return m.conversationHistory[i].Content
+		msg := m.conversationHistory[i]
+		if msg.Role == "assistant" && !strings.HasPrefix(msg.Content, "Error:") {
+			return msg.Content
 		}
 	}
 	return ""
 }
+
+// starts a new conversation on the code of a strudel from the library
+func (m *EditorModel) Open(strudel *api.Strudel) {
+	m.strudel = strudel
+	m.conversationHistory = []MessageModel{{
+		Role:     "assistant",
+		Content:  strudel.Code,
+		Metadata: fmt.Sprintf("opened %q from the library", strudel.Title),
+	}}
+	m.isFetching = false
+	m.pendingQuery = ""
+	m.streamingContent = ""
+	m.shouldScrollBottom = true
+	m.input.Focus()
+	m.saveDraft()
+}
+
+// sets the strudel the editor saves to
+func (m *EditorModel) setStrudel(strudel *api.Strudel) {
+	m.strudel = strudel
+	m.saveDraft()
+}
+
+// keeps the conversation for the next run
+func (m *EditorModel) saveDraft() {
+	m.sync.saveDraft(m.conversationHistory, m.strudel)
+}
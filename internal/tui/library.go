@@ -0,0 +1,810 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
+	"codeberg.org/algopatterns/server/internal/tui/offline"
+)
+
+// returns a new strudel library
+func NewLibrary(client *api.Client, sync *Syncer, editor *EditorModel) *LibraryModel {
+	return &LibraryModel{
+		client: client,
+		sync:   sync,
+		editor: editor,
+		input:  libraryInput(200),
+		title:  libraryInput(200),
+		tags:   libraryInput(20 * 51), // the API's 20 tags of up to 50 characters, comma-separated
+	}
+}
+
+func libraryInput(limit int) textinput.Model {
+	ti := textinput.New()
+	ti.CharLimit = limit
+	ti.Prompt = "> "
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(colorLightGray)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(colorWhite)
+
+	return ti
+}
+
+// loads the first page when the library opens
+func (m *LibraryModel) Init() tea.Cmd {
+	m.view = libraryList
+	m.detail = nil
+	m.err = nil
+
+	if !m.client.Authenticated() {
+		m.err = errNotSignedIn
+		return nil
+	}
+
+	m.loading = true
+	return m.loadCmd("")
+}
+
+func (m *LibraryModel) Update(msg tea.Msg) (*LibraryModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case m.prompt != promptNone:
+			return m.updatePrompt(msg)
+		case m.view == libraryEdit:
+			return m.updateForm(msg)
+		default:
+			return m.updateKeys(msg)
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.input.Width = msg.Width - 4
+		m.title.Width = msg.Width - 14
+		m.tags.Width = msg.Width - 14
+
+	case LibraryLoadedMsg:
+		m.loading = false
+		m.strudels = msg.page.Strudels
+		m.total = msg.page.Pagination.Total
+		m.nextCursor = msg.page.Pagination.NextCursor
+		m.prevCursor = msg.page.Pagination.PrevCursor
+		m.cursor = min(m.cursor, max(len(m.strudels)-1, 0))
+
+	case StrudelLoadedMsg:
+		m.loading = false
+		m.detail = msg.strudel
+		m.view = libraryDetail
+		if msg.edit {
+			m.openForm()
+		}
+
+	case StrudelSavedMsg:
+		m.loading = false
+		m.status = msg.status
+		m.overwrite = false
+
+		// the editor saves on top of what was last saved
+		if msg.created || (m.editor.strudel != nil && m.editor.strudel.ID == msg.strudel.ID) {
+			m.editor.setStrudel(msg.strudel)
+		}
+
+		if m.detail != nil && m.detail.ID == msg.strudel.ID {
+			m.detail = msg.strudel
+		}
+
+		return m, m.loadCmd("")
+
+	case LibraryErrorMsg:
+		m.loading = false
+		m.status = ""
+		m.err = msg.err
+
+		if api.IsOffline(msg.err) {
+			m.sync.markOffline()
+			if msg.save != nil {
+				m.err = nil
+				return m, m.queueSave(*msg.save)
+			}
+			return m, m.sync.resume()
+		}
+
+		if api.IsStatus(msg.err, http.StatusConflict) && msg.editorSave {
+			m.err = nil
+			m.overwrite = true
+			m.status = "the strudel was saved elsewhere since it was opened. press s again to overwrite it."
+		} else if api.IsStatus(msg.err, http.StatusConflict) {
+			m.err = fmt.Errorf("the strudel was saved elsewhere since it was loaded, open it again to edit")
+		}
+	}
+
+	return m, nil
+}
+
+// handles keys while browsing the list or a strudel's details
+func (m *LibraryModel) updateKeys(msg tea.KeyMsg) (*LibraryModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		if m.view == libraryDetail {
+			m.view = libraryList
+			m.detail = nil
+			return m, nil
+		}
+		return m, func() tea.Msg { return LeaveLibraryMsg{} }
+
+	case "up":
+		if m.view == libraryList && m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down":
+		if m.view == libraryList && m.cursor < len(m.strudels)-1 {
+			m.cursor++
+		}
+
+	case "pgdown":
+		if m.view == libraryList && m.nextCursor != "" {
+			m.cursor = 0
+			m.loading = true
+			return m, m.loadCmd(m.nextCursor)
+		}
+
+	case "pgup":
+		if m.view == libraryList && m.prevCursor != "" {
+			m.cursor = 0
+			m.loading = true
+			return m, m.loadCmd(m.prevCursor)
+		}
+
+	case "/":
+		m.openPrompt(promptSearch, "search titles and descriptions, #tag to filter by tag", m.search)
+
+	case "r":
+		m.err = nil
+		m.status = ""
+		m.loading = true
+		return m, m.loadCmd("")
+
+	case "s":
+		return m, m.saveEditor()
+
+	case "enter":
+		if m.view == libraryList {
+			return m, m.fetchSelected(false)
+		}
+
+	case "e":
+		if m.view == libraryDetail {
+			m.openForm()
+			return m, nil
+		}
+		return m, m.fetchSelected(true)
+
+	case "o":
+		if strudel := m.target(); strudel != nil {
+			return m, m.openCmd(strudel.ID)
+		}
+
+	case "f":
+		if strudel := m.target(); strudel != nil {
+			m.status = fmt.Sprintf("forking %q...", strudel.Title)
+			return m, m.forkCmd(strudel.ID)
+		}
+	}
+
+	return m, nil
+}
+
+// handles keys while the search or title prompt is open
+func (m *LibraryModel) updatePrompt(msg tea.KeyMsg) (*LibraryModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.closePrompt()
+		return m, nil
+
+	case "enter":
+		value := strings.TrimSpace(m.input.Value())
+		prompt := m.prompt
+		m.closePrompt()
+		m.err = nil
+
+		if prompt == promptSearch {
+			m.search = value
+			m.cursor = 0
+			m.view = libraryList
+			m.detail = nil
+			m.loading = true
+			return m, m.loadCmd("")
+		}
+
+		if value == "" {
+			return m, nil
+		}
+
+		save := offline.Operation{Title: value, Code: m.editor.GetCode()}
+		if m.sync.Offline() {
+			return m, m.queueSave(save)
+		}
+
+		m.status = "saving..."
+		return m, m.createCmd(save)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// handles keys of the edit form
+func (m *LibraryModel) updateForm(msg tea.KeyMsg) (*LibraryModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.closeForm()
+		return m, nil
+
+	case "tab", "down":
+		m.focusField((m.field + 1) % fieldCount)
+		return m, nil
+
+	case "shift+tab", "up":
+		m.focusField((m.field + fieldCount - 1) % fieldCount)
+		return m, nil
+
+	case "enter":
+		update, err := m.formUpdate()
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+
+		id := m.detail.ID
+		m.closeForm()
+		m.err = nil
+		m.status = "saving..."
+		return m, m.updateCmd(id, update, "saved changes")
+	}
+
+	if m.field == fieldCCSignal {
+		switch msg.String() {
+		case "left":
+			m.ccSignal = m.cycleSignal(-1)
+		case "right", " ":
+			m.ccSignal = m.cycleSignal(1)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if m.field == fieldTitle {
+		m.title, cmd = m.title.Update(msg)
+	} else {
+		m.tags, cmd = m.tags.Update(msg)
+	}
+	return m, cmd
+}
+
+// the strudel the keys apply to: the one shown, or the one under the cursor
+func (m *LibraryModel) target() *api.Strudel {
+	if m.detail != nil {
+		return m.detail
+	}
+
+	if m.view != libraryList || len(m.strudels) == 0 {
+		return nil
+	}
+
+	return &m.strudels[m.cursor]
+}
+
+// saves the editor's code to the strudel it was opened from, or asks for a
+// title to save it as a new one
+func (m *LibraryModel) saveEditor() tea.Cmd {
+	code := m.editor.GetCode()
+	if strings.TrimSpace(code) == "" {
+		m.err = fmt.Errorf("nothing to save, the editor has no code yet")
+		return nil
+	}
+
+	opened := m.editor.strudel
+	if opened == nil {
+		m.openPrompt(promptSaveTitle, "title of the new strudel", "")
+		return nil
+	}
+
+	m.err = nil
+
+	save := offline.Operation{StrudelID: opened.ID, Title: opened.Title, Code: code, BaseUpdatedAt: opened.UpdatedAt}
+	if m.sync.Offline() {
+		return m.queueSave(save)
+	}
+
+	m.status = fmt.Sprintf("saving %q...", opened.Title)
+
+	if m.overwrite {
+		return m.overwriteCmd(opened.ID, code)
+	}
+
+	return m.saveCmd(save)
+}
+
+// keeps a save of the editor's code until the API can be reached
+func (m *LibraryModel) queueSave(save offline.Operation) tea.Cmd {
+	m.status = fmt.Sprintf("offline, saving %q once the API can be reached", save.Title)
+	return m.sync.QueueSave(save)
+}
+
+func (m *LibraryModel) openPrompt(prompt libraryPrompt, placeholder, value string) {
+	m.prompt = prompt
+	m.input.Placeholder = placeholder
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.input.Focus()
+}
+
+func (m *LibraryModel) closePrompt() {
+	m.prompt = promptNone
+	m.input.Blur()
+	m.input.SetValue("")
+}
+
+// fills the edit form with the shown strudel
+func (m *LibraryModel) openForm() {
+	m.view = libraryEdit
+	m.err = nil
+	m.title.SetValue(m.detail.Title)
+	m.tags.SetValue(strings.Join(m.detail.Tags, ", "))
+	m.ccSignal = ""
+	if m.detail.CCSignal != nil {
+		m.ccSignal = *m.detail.CCSignal
+	}
+	m.focusField(fieldTitle)
+}
+
+func (m *LibraryModel) closeForm() {
+	m.view = libraryDetail
+	m.title.Blur()
+	m.tags.Blur()
+}
+
+func (m *LibraryModel) focusField(field int) {
+	m.field = field
+	m.title.Blur()
+	m.tags.Blur()
+
+	switch field {
+	case fieldTitle:
+		m.title.Focus()
+	case fieldTags:
+		m.tags.Focus()
+	}
+}
+
+// the next CC signal in the given direction. a strudel without one can stay
+// without, but the API can't take a signal away once set
+func (m *LibraryModel) cycleSignal(step int) string {
+	choices := api.CCSignals
+	if m.detail.CCSignal == nil {
+		choices = append([]string{""}, choices...)
+	}
+
+	i := slices.Index(choices, m.ccSignal)
+	return choices[(i+step+len(choices))%len(choices)]
+}
+
+// the changes made in the edit form
+func (m *LibraryModel) formUpdate() (api.StrudelUpdate, error) {
+	title := strings.TrimSpace(m.title.Value())
+	if title == "" {
+		return api.StrudelUpdate{}, fmt.Errorf("the title can't be empty")
+	}
+
+	// an empty list removes the tags
+	tags := []string{}
+	for tag := range strings.SplitSeq(m.tags.Value(), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	updatedAt := m.detail.UpdatedAt
+	update := api.StrudelUpdate{Title: &title, Tags: tags, UpdatedAt: &updatedAt}
+
+	if m.ccSignal != "" && (m.detail.CCSignal == nil || *m.detail.CCSignal != m.ccSignal) {
+		signal := m.ccSignal
+		update.CCSignal = &signal
+	}
+
+	return update, nil
+}
+
+// the search text and #tags of a search
+func parseSearch(search string) (string, []string) {
+	var words, tags []string
+	for _, word := range strings.Fields(search) {
+		if tag, ok := strings.CutPrefix(word, "#"); ok {
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return strings.Join(words, " "), tags
+}
+
+func (m *LibraryModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.headerView())
+	b.WriteString("\n\n")
+
+	switch {
+	case m.loading:
+		b.WriteString(infoStyle.Render("loading..."))
+		b.WriteString("\n")
+	case m.view == libraryEdit:
+		b.WriteString(m.formView())
+	case m.view == libraryDetail:
+		b.WriteString(m.detailView())
+	default:
+		b.WriteString(m.listView())
+	}
+
+	b.WriteString("\n")
+
+	if m.prompt != promptNone {
+		b.WriteString(m.input.View())
+		b.WriteString("\n\n")
+	}
+
+	if m.err != nil {
+		b.WriteString(lipgloss.NewStyle().Foreground(colorRed).Render("error: " + m.err.Error()))
+		b.WriteString("\n")
+	} else if m.status != "" {
+		b.WriteString(infoStyle.Render(m.status))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render(m.help()))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func (m *LibraryModel) headerView() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(colorWhite).Background(colorPurple).Padding(0, 1).Render("library")
+
+	details := identity(m.client)
+	if m.search != "" {
+		details += fmt.Sprintf(" · search %q", m.search)
+	}
+	if m.editor.strudel != nil {
+		details += fmt.Sprintf(" · editing %q", m.editor.strudel.Title)
+	}
+	if sync := m.sync.Status(); sync != "" {
+		details += " · " + sync
+	}
+
+	return title + infoStyle.Render("  "+details)
+}
+
+func (m *LibraryModel) listView() string {
+	if m.err != nil && len(m.strudels) == 0 {
+		return ""
+	}
+
+	if len(m.strudels) == 0 {
+		if m.search != "" {
+			return infoStyle.Render("no strudels match the search.") + "\n"
+		}
+		return infoStyle.Render("no strudels yet. press s to save the editor's code.") + "\n"
+	}
+
+	// the rows around the cursor that fit the screen
+	rows := max(m.height-10, 5)
+	start := max(0, min(m.cursor-rows/2, len(m.strudels)-rows))
+	end := min(len(m.strudels), start+rows)
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		strudel := m.strudels[i]
+
+		details := since(strudel.UpdatedAt)
+		if strudel.IsPublic {
+			details += " · public"
+		}
+		if strudel.CCSignal != nil {
+			details += " · " + *strudel.CCSignal
+		}
+		if strudel.ForkedFrom != nil {
+			details += " · fork"
+		}
+		if len(strudel.Tags) > 0 {
+			details += " · #" + strings.Join(strudel.Tags, " #")
+		}
+
+		b.WriteString(m.row(i, strudel.Title, details))
+	}
+
+	if m.total != nil {
+		b.WriteString("\n")
+		b.WriteString(infoStyle.Render(fmt.Sprintf("%d strudels", *m.total)))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// a list row, highlighted under the cursor
+func (m *LibraryModel) row(i int, title, details string) string {
+	cursor := "  "
+	nameStyle := lipgloss.NewStyle().Foreground(colorLightGray)
+	if i == m.cursor {
+		cursor = lipgloss.NewStyle().Foreground(colorPurple).Bold(true).Render("> ")
+		nameStyle = nameStyle.Bold(true).Foreground(colorWhite)
+	}
+
+	return cursor + nameStyle.Render(title) + commandDescStyle.Render(details) + "\n"
+}
+
+func (m *LibraryModel) detailView() string {
+	strudel := m.detail
+
+	var b strings.Builder
+
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(colorWhite).Render(strudel.Title))
+	b.WriteString(infoStyle.Render("  " + strudel.ID))
+	b.WriteString("\n\n")
+
+	if strudel.Description != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(colorLightGray).Render(strudel.Description))
+		b.WriteString("\n\n")
+	}
+
+	visibility := "private"
+	if strudel.IsPublic {
+		visibility = "public"
+	}
+
+	b.WriteString(m.detailLine("saved", since(strudel.UpdatedAt)+", "+visibility))
+	b.WriteString(m.detailLine("tags", joinTags(strudel.Tags)))
+	if len(strudel.AutoTags) > 0 {
+		b.WriteString(m.detailLine("detected", joinTags(strudel.AutoTags)))
+	}
+	b.WriteString(m.detailLine("cc signal", signalText(strudel.CCSignal)))
+	if strudel.EffectiveCCSignal != nil && (strudel.CCSignal == nil || *strudel.EffectiveCCSignal != *strudel.CCSignal) {
+		b.WriteString(m.detailLine("applies", *strudel.EffectiveCCSignal+" (from the original)"))
+	}
+	if strudel.ForkedFrom != nil {
+		b.WriteString(m.detailLine("forked from", *strudel.ForkedFrom))
+	}
+	b.WriteString("\n")
+
+	lines := strings.Split(strudel.Code, "\n")
+	if len(lines) > libraryPreviewLines {
+		lines = append(lines[:libraryPreviewLines], fmt.Sprintf("... %d more lines", len(lines)-libraryPreviewLines))
+	}
+	for _, line := range lines {
+		b.WriteString("  " + lipgloss.NewStyle().Foreground(colorLightGray).Render(line) + "\n")
+	}
+
+	return b.String()
+}
+
+func (m *LibraryModel) detailLine(label, value string) string {
+	return commandDescStyle.Render(fmt.Sprintf("%-12s", label)) + " " + lipgloss.NewStyle().Foreground(colorWhite).Render(value) + "\n"
+}
+
+func (m *LibraryModel) formView() string {
+	var b strings.Builder
+
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(colorWhite).Render("edit " + m.detail.Title))
+	b.WriteString("\n\n")
+
+	label := func(field int, text string) string {
+		style := commandDescStyle
+		if m.field == field {
+			style = style.Foreground(colorWhite).Bold(true)
+		}
+		return style.Render(fmt.Sprintf("%-10s", text))
+	}
+
+	b.WriteString(label(fieldTitle, "title") + m.title.View() + "\n")
+	b.WriteString(label(fieldTags, "tags") + m.tags.View() + "\n")
+
+	signal := "‹ " + signalText(&m.ccSignal) + " ›"
+	if m.field == fieldCCSignal {
+		signal = lipgloss.NewStyle().Foreground(colorWhite).Bold(true).Render(signal)
+	}
+	b.WriteString(label(fieldCCSignal, "cc signal") + "  " + signal + "\n")
+
+	return b.String()
+}
+
+func (m *LibraryModel) help() string {
+	switch {
+	case m.prompt != promptNone:
+		return "confirm: enter | cancel: esc"
+	case m.view == libraryEdit:
+		return "next field: tab | cc signal: ←/→ | save: enter | cancel: esc"
+	case m.view == libraryDetail:
+		return "open in editor: o | edit: e | fork: f | save editor code: s | back: esc"
+	default:
+		return "select: ↑/↓ | details: enter | open: o | edit: e | fork: f | save editor code: s | search: / | pages: pgup/pgdn | refresh: r | back: esc"
+	}
+}
+
+func joinTags(tags []string) string {
+	if len(tags) == 0 {
+		return "none"
+	}
+	return "#" + strings.Join(tags, " #")
+}
+
+func signalText(signal *string) string {
+	if signal == nil || *signal == "" {
+		return "none"
+	}
+	return *signal
+}
+
+// fetches a page of the library for the current search
+func (m *LibraryModel) loadCmd(cursor string) tea.Cmd {
+	client := m.client
+	search, tags := parseSearch(m.search)
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		page, err := client.ListStrudels(ctx, search, tags, cursor)
+		if err != nil {
+			return LibraryErrorMsg{err: err}
+		}
+
+		return LibraryLoadedMsg{page: page}
+	}
+}
+
+// fetches the selected strudel to show its details, or to edit it
+func (m *LibraryModel) fetchSelected(edit bool) tea.Cmd {
+	if len(m.strudels) == 0 {
+		return nil
+	}
+
+	client := m.client
+	id := m.strudels[m.cursor].ID
+	m.loading = true
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		strudel, err := client.GetStrudel(ctx, id)
+		if err != nil {
+			return LibraryErrorMsg{err: err}
+		}
+
+		return StrudelLoadedMsg{strudel: strudel, edit: edit}
+	}
+}
+
+// fetches the latest save of a strudel and opens it in the editor
+func (m *LibraryModel) openCmd(id string) tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		strudel, err := client.GetStrudel(ctx, id)
+		if err != nil {
+			return LibraryErrorMsg{err: err}
+		}
+
+		return OpenStrudelMsg{strudel: strudel}
+	}
+}
+
+func (m *LibraryModel) createCmd(save offline.Operation) tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		strudel, err := client.CreateStrudel(ctx, api.NewStrudel{Title: save.Title, Code: save.Code})
+		if err != nil {
+			return LibraryErrorMsg{err: err, save: &save}
+		}
+
+		return StrudelSavedMsg{strudel: strudel, status: fmt.Sprintf("saved %q", strudel.Title), created: true}
+	}
+}
+
+func (m *LibraryModel) updateCmd(id string, update api.StrudelUpdate, status string) tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		strudel, err := client.UpdateStrudel(ctx, id, update)
+		if err != nil {
+			return LibraryErrorMsg{err: err}
+		}
+
+		return StrudelSavedMsg{strudel: strudel, status: status}
+	}
+}
+
+// saves the editor's code to the strudel it was opened from
+func (m *LibraryModel) saveCmd(save offline.Operation) tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		base := save.BaseUpdatedAt
+		strudel, err := client.UpdateStrudel(ctx, save.StrudelID, api.StrudelUpdate{Code: &save.Code, UpdatedAt: &base})
+		if err != nil {
+			return LibraryErrorMsg{err: err, editorSave: true, save: &save}
+		}
+
+		return StrudelSavedMsg{strudel: strudel, status: fmt.Sprintf("saved %q", strudel.Title)}
+	}
+}
+
+// saves the code over whatever was saved since the strudel was opened
+func (m *LibraryModel) overwriteCmd(id, code string) tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		current, err := client.GetStrudel(ctx, id)
+		if err != nil {
+			return LibraryErrorMsg{err: err}
+		}
+
+		strudel, err := client.UpdateStrudel(ctx, id, api.StrudelUpdate{Code: &code, UpdatedAt: &current.UpdatedAt})
+		if err != nil {
+			return LibraryErrorMsg{err: err}
+		}
+
+		return StrudelSavedMsg{strudel: strudel, status: fmt.Sprintf("overwrote %q", strudel.Title)}
+	}
+}
+
+// forks a strudel, fetching it first for its full code and signal
+func (m *LibraryModel) forkCmd(id string) tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		original, err := client.GetStrudel(ctx, id)
+		if err != nil {
+			return LibraryErrorMsg{err: err}
+		}
+
+		fork, err := client.ForkStrudel(ctx, original)
+		if err != nil {
+			return LibraryErrorMsg{err: err}
+		}
+
+		return StrudelSavedMsg{strudel: fork, status: fmt.Sprintf("forked %q as %q", original.Title, fork.Title)}
+	}
+}
@@ -0,0 +1,467 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
+)
+
+// returns the view of a session connected to
+func NewLive(msg LiveConnectedMsg, width, height int) *LiveModel {
+	code := textarea.New()
+	code.ShowLineNumbers = true
+	code.CharLimit = 0
+	code.MaxHeight = 0
+	code.Prompt = ""
+
+	chat := textinput.New()
+	chat.Placeholder = "say something..."
+	chat.CharLimit = maxLiveChatLength
+	chat.Prompt = "> "
+	chat.PromptStyle = lipgloss.NewStyle().Foreground(colorLightGray)
+	chat.TextStyle = lipgloss.NewStyle().Foreground(colorWhite)
+
+	m := &LiveModel{
+		conn:     msg.conn,
+		stream:   msg.stream,
+		title:    msg.title,
+		code:     code,
+		chatLog:  viewport.New(0, 0),
+		input:    chat,
+		focus:    focusChat,
+		status:   "connecting...",
+		canEdit:  false,
+		presence: []api.Presence{},
+	}
+	m.resize(width, height)
+
+	return m
+}
+
+// starts receiving the session's messages
+func (m *LiveModel) Init() tea.Cmd {
+	return tea.Batch(m.input.Focus(), waitForStream(m.stream))
+}
+
+func (m *LiveModel) Update(msg tea.Msg) (*LiveModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.updateKeys(msg)
+
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+
+	case LiveEventMsg:
+		// a connection left earlier is still draining
+		if msg.stream == m.stream {
+			m.handleMessage(msg.message)
+		}
+		return m, waitForStream(msg.stream)
+
+	case LiveClosedMsg:
+		if msg.stream != m.stream {
+			return m, nil
+		}
+
+		m.disconnected = true
+		if msg.err != nil && !api.IsClosed(msg.err) && !m.ended {
+			m.err = fmt.Errorf("disconnected: %w", msg.err)
+		}
+		m.code.Blur()
+		m.input.Blur()
+	}
+
+	return m, nil
+}
+
+func (m *LiveModel) updateKeys(msg tea.KeyMsg) (*LiveModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.Close()
+		return m, func() tea.Msg { return LeaveLiveMsg{} }
+
+	case "tab":
+		m.toggleFocus()
+		return m, nil
+
+	case "pgup", "pgdown":
+		var cmd tea.Cmd
+		m.chatLog, cmd = m.chatLog.Update(msg)
+		return m, cmd
+	}
+
+	if m.disconnected {
+		return m, nil
+	}
+
+	if m.focus == focusChat {
+		if msg.String() == "enter" {
+			m.sendChat()
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	if !m.canEdit {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.code, cmd = m.code.Update(msg)
+
+	// share the edit; the connection keeps to the session's rate limit
+	if value := m.code.Value(); value != m.lastCode {
+		m.lastCode = value
+		line, col := m.cursor()
+
+		if err := m.conn.SendCode(api.CodeUpdate{Code: value, CursorLine: line + 1, CursorCol: col + 1, Source: "typed"}); err != nil {
+			m.err = fmt.Errorf("failed to send edit: %w", err)
+		}
+	}
+
+	return m, cmd
+}
+
+// switches between the code and the chat. viewers stay in the chat
+func (m *LiveModel) toggleFocus() {
+	if m.focus == focusChat && m.canEdit {
+		m.focus = focusCode
+		m.input.Blur()
+		m.code.Focus()
+		return
+	}
+
+	m.focus = focusChat
+	m.code.Blur()
+	m.input.Focus()
+}
+
+func (m *LiveModel) sendChat() {
+	message := strings.TrimSpace(m.input.Value())
+	if message == "" {
+		return
+	}
+
+	if err := m.conn.SendChat(message); err != nil {
+		if errors.Is(err, api.ErrChatLimited) {
+			m.status = err.Error()
+			return
+		}
+		m.err = fmt.Errorf("failed to send message: %w", err)
+		return
+	}
+
+	m.input.SetValue("")
+	m.status = ""
+}
+
+// applies a message of the session to the view
+func (m *LiveModel) handleMessage(msg api.Message) {
+	switch msg.Type {
+	case api.MessageSessionState:
+		var state api.SessionState
+		if msg.Decode(&state) != nil {
+			return
+		}
+
+		m.role = state.YourRole
+		m.displayName = state.YourDisplayName
+		m.presence = state.Participants
+		m.listeners = state.ListenerCount
+		m.setCode(state.Code)
+
+		m.chat = m.chat[:0]
+		for _, entry := range state.ChatHistory {
+			m.chat = append(m.chat, liveChatLine{name: entry.DisplayName, text: entry.Content})
+		}
+		m.refreshChat()
+
+		m.status = fmt.Sprintf("connected as %s (%s)", m.displayName, m.role)
+		m.updateCanEdit()
+
+	case api.MessageCodeUpdate:
+		var update api.CodeUpdate
+		if msg.Decode(&update) != nil {
+			return
+		}
+
+		m.setCode(update.Code)
+		if update.DisplayName != "" {
+			m.status = "edited by " + update.DisplayName
+		}
+
+	case api.MessageChatMessage:
+		var chat api.ChatMessage
+		if msg.Decode(&chat) == nil {
+			m.addChat(liveChatLine{name: chat.DisplayName, text: chat.Message})
+		}
+
+	case api.MessageUserJoined:
+		var joined api.UserJoined
+		if msg.Decode(&joined) != nil {
+			return
+		}
+
+		m.presence = append(m.presence, api.Presence{UserID: joined.UserID, DisplayName: joined.DisplayName, Role: joined.Role})
+		m.addChat(liveChatLine{text: fmt.Sprintf("%s joined as %s", joined.DisplayName, joined.Role), system: true})
+
+	case api.MessageUserLeft:
+		var left api.UserLeft
+		if msg.Decode(&left) != nil {
+			return
+		}
+
+		for i, p := range m.presence {
+			if p.UserID == left.UserID && p.DisplayName == left.DisplayName {
+				m.presence = append(m.presence[:i], m.presence[i+1:]...)
+				break
+			}
+		}
+		m.addChat(liveChatLine{text: left.DisplayName + " left", system: true})
+
+	case api.MessagePermissionsUpdated:
+		m.updateCanEdit()
+		m.status = "your permissions changed"
+
+	case api.MessageError:
+		var serverErr api.ServerError
+		if msg.Decode(&serverErr) == nil {
+			m.status = serverErr.Message
+		}
+
+	case api.MessageSessionEnded:
+		m.ended = true
+		m.canEdit = false
+		m.addChat(liveChatLine{text: "the session ended", system: true})
+		m.status = "session ended. press esc to go back."
+
+	case api.MessageServerShutdown:
+		m.status = "the server is restarting"
+	}
+}
+
+// follows the connection's permissions, moving viewers to the chat
+func (m *LiveModel) updateCanEdit() {
+	m.canEdit = m.conn.Can("edit_code") && !m.ended
+	if !m.canEdit && m.focus == focusCode {
+		m.toggleFocus()
+	}
+}
+
+// replaces the code, keeping the cursor where it was
+func (m *LiveModel) setCode(code string) {
+	m.lastCode = code
+	if code == m.code.Value() {
+		return
+	}
+
+	line, col := m.cursor()
+	m.code.SetValue(code)
+
+	// SetValue leaves the cursor at the end
+	for m.code.Line() > line {
+		before := m.code.Line()
+		m.code.CursorUp()
+		if m.code.Line() == before && m.code.LineInfo().RowOffset == 0 {
+			break
+		}
+	}
+	m.code.SetCursor(col)
+}
+
+// the line and column of the code cursor, from 0
+func (m *LiveModel) cursor() (int, int) {
+	info := m.code.LineInfo()
+	return m.code.Line(), info.StartColumn + info.ColumnOffset
+}
+
+func (m *LiveModel) addChat(line liveChatLine) {
+	m.chat = append(m.chat, line)
+	if len(m.chat) > maxLiveChatLines {
+		m.chat = m.chat[len(m.chat)-maxLiveChatLines:]
+	}
+	m.refreshChat()
+}
+
+// renders the chat into its viewport, scrolled to the latest message
+func (m *LiveModel) refreshChat() {
+	var b strings.Builder
+
+	width := max(m.chatLog.Width, 1)
+	for _, line := range m.chat {
+		if line.system {
+			b.WriteString(infoStyle.Width(width).Render(line.text))
+		} else {
+			name := lipgloss.NewStyle().Bold(true).Foreground(colorPurple).Render(line.name)
+			b.WriteString(lipgloss.NewStyle().Width(width).Foreground(colorWhite).Render(name + " " + line.text))
+		}
+		b.WriteString("\n")
+	}
+
+	m.chatLog.SetContent(b.String())
+	m.chatLog.GotoBottom()
+}
+
+// lays out the code on the left and presence and chat on the right
+func (m *LiveModel) resize(width, height int) {
+	m.width = width
+	m.height = height
+
+	sideWidth := max(width/3, 24)
+	codeWidth := max(width-sideWidth-3, 20)
+	bodyHeight := max(height-6, 6)
+
+	m.code.SetWidth(codeWidth)
+	m.code.SetHeight(bodyHeight)
+
+	m.chatLog.Width = sideWidth
+	m.chatLog.Height = max(bodyHeight-livePresenceRows-4, 3)
+	m.input.Width = sideWidth - 4
+
+	m.refreshChat()
+}
+
+// closes the connection, sending any edit still held back
+func (m *LiveModel) Close() {
+	if m.conn != nil {
+		m.conn.Close() //nolint:errcheck,gosec // leaving either way
+	}
+}
+
+func (m *LiveModel) View() string {
+	var b strings.Builder
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(colorWhite).Render(m.title)
+	if m.role != "" {
+		header += infoStyle.Render(fmt.Sprintf("  %s · %s", m.displayName, m.role))
+	}
+	if !m.canEdit && !m.disconnected {
+		header += infoStyle.Render(" · read only")
+	}
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	codeBorder := colorDarkGray
+	if m.focus == focusCode {
+		codeBorder = colorPurple
+	}
+	codePane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(codeBorder).
+		Render(m.code.View())
+
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, codePane, " ", m.sideView()))
+	b.WriteString("\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(colorRed).Render("error: " + m.err.Error()))
+	case m.disconnected:
+		b.WriteString(infoStyle.Render("disconnected. press esc to go back."))
+	default:
+		b.WriteString(infoStyle.Render(m.status))
+	}
+	b.WriteString("\n")
+
+	b.WriteString(helpStyle.Render("code/chat: tab | send: enter | scroll chat: pgup/pgdn | leave: esc"))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// presence above the chat
+func (m *LiveModel) sideView() string {
+	var b strings.Builder
+
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(colorWhite).Render(fmt.Sprintf("here (%d)", len(m.presence))))
+	if m.listeners > 0 {
+		b.WriteString(infoStyle.Render(fmt.Sprintf(" +%d listening", m.listeners)))
+	}
+	b.WriteString("\n")
+
+	for i, p := range m.presence {
+		if i == livePresenceRows-1 && len(m.presence) > livePresenceRows {
+			b.WriteString(infoStyle.Render(fmt.Sprintf("  and %d more", len(m.presence)-i)))
+			b.WriteString("\n")
+			break
+		}
+
+		dot := lipgloss.NewStyle().Foreground(colorGreen).Render("● ")
+		if p.Quality == "poor" {
+			dot = lipgloss.NewStyle().Foreground(colorRed).Render("● ")
+		}
+		b.WriteString(dot + commandStyle.Render(p.DisplayName) + commandDescStyle.Render(p.Role))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(m.chatLog.View())
+	b.WriteString("\n")
+	b.WriteString(m.input.View())
+
+	border := colorDarkGray
+	if m.focus == focusChat {
+		border = colorPurple
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.chatLog.Width).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Render(b.String())
+}
+
+// connects to a session and starts reading its messages. the messages are
+// delivered as LiveEventMsg, followed by a LiveClosedMsg
+func connectLive(client *api.Client, sessionID, title, inviteToken string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), liveConnectTimeout)
+		defer cancel()
+
+		conn, err := client.Connect(ctx, api.ConnectOptions{
+			SessionID:   sessionID,
+			InviteToken: inviteToken,
+			DisplayName: os.Getenv("USER"),
+		})
+		if err != nil {
+			return SessionsErrorMsg{err: fmt.Errorf("failed to connect: %w", err)}
+		}
+
+		stream := make(chan tea.Msg, streamChannelSize)
+
+		go func() {
+			defer close(stream)
+
+			for {
+				messages, err := conn.Read()
+				for _, message := range messages {
+					stream <- LiveEventMsg{message: message, stream: stream}
+				}
+
+				if err != nil {
+					stream <- LiveClosedMsg{err: err, stream: stream}
+					return
+				}
+			}
+		}()
+
+		return LiveConnectedMsg{conn: conn, stream: stream, title: title}
+	}
+}
+
+// timeout of connecting to a session
+const liveConnectTimeout = 15 * time.Second
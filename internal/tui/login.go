@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
+)
+
+// returns a new login screen
+func NewLogin(client *api.Client) *LoginModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(colorLightGray)
+
+	return &LoginModel{
+		client:  client,
+		spinner: s,
+	}
+}
+
+// asks the API for a device code
+func (m *LoginModel) Init() tea.Cmd {
+	client := m.client
+
+	start := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		login, err := client.StartDeviceLogin(ctx)
+		return LoginStartedMsg{login: login, err: err}
+	}
+
+	return tea.Batch(m.spinner.Tick, start)
+}
+
+func (m *LoginModel) Update(msg tea.Msg) (*LoginModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			m.Close()
+			return m, func() tea.Msg { return LeaveLoginMsg{} }
+		}
+
+	case LoginStartedMsg:
+		// only the first code is waited for
+		if m.login != nil {
+			return m, nil
+		}
+
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+
+		m.login = msg.login
+		return m, m.waitCmd()
+
+	case LoginDoneMsg:
+		// a login given up on before this one
+		if msg.login != m.login {
+			return m, nil
+		}
+		m.err = msg.err
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// waits for the user to approve the login on the website
+func (m *LoginModel) waitCmd() tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	client, login := m.client, m.login
+
+	return func() tea.Msg {
+		user, err := client.WaitForDeviceLogin(ctx, login)
+		return LoginDoneMsg{login: login, user: user, err: err}
+	}
+}
+
+// stops waiting for the login
+func (m *LoginModel) Close() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+func (m *LoginModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("login"))
+	b.WriteString("\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(colorRed).Render("  " + m.err.Error()))
+		b.WriteString("\n")
+
+	case m.login == nil:
+		b.WriteString("  " + m.spinner.View() + infoStyle.Render(" requesting a login code from "+m.client.Endpoint()))
+		b.WriteString("\n")
+
+	default:
+		b.WriteString("  open " + commandStyle.Render(m.login.VerificationURI) + " and enter the code\n\n")
+		b.WriteString("  " + lipgloss.NewStyle().Bold(true).Foreground(colorWhite).Background(colorPurple).Padding(0, 2).Render(m.login.UserCode))
+		b.WriteString("\n\n")
+		if m.login.VerificationURIComplete != "" {
+			b.WriteString(infoStyle.Render("  or open " + m.login.VerificationURIComplete))
+			b.WriteString("\n\n")
+		}
+		b.WriteString("  " + m.spinner.View() + infoStyle.Render(" waiting for you to approve the login"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("  back: esc"))
+
+	return b.String()
+}
+
+// fetches the signed-in user when only the token is known, e.g. one from
+// ALGOPATTERNS_TOKEN
+func fetchIdentity(client *api.Client) tea.Cmd {
+	if !client.Authenticated() || client.User() != nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		// the user stays unknown if it fails
+		client.Me(ctx) //nolint:errcheck,gosec // best-effort
+		return IdentityMsg{}
+	}
+}
+
+// logs the user out
+func logout(client *api.Client) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), logoutTimeout)
+		defer cancel()
+
+		return LoggedOutMsg{err: client.Logout(ctx)}
+	}
+}
+
+// who the client is signed in as, for the status lines of the views
+func identity(client *api.Client) string {
+	if !client.Authenticated() {
+		return "not signed in"
+	}
+
+	user := client.User()
+	if user == nil {
+		return "signed in"
+	}
+
+	name := user.Name
+	if name == "" {
+		name = user.Email
+	}
+	if user.Email != "" && user.Email != name {
+		name += " <" + user.Email + ">"
+	}
+
+	return "signed in as " + name
+}
@@ -0,0 +1,271 @@
+// Package offline keeps the terminal client's work on disk while the API
+// can't be reached: the editor's draft, and the agent requests and saves
+// queued to be sent once the connection returns.
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// where the store is kept: offline.json in the algopatterns directory of the
+// user's config dir (~/.config on Linux). empty if there is none
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, configDirName, storeFile)
+}
+
+// opens the store at path for the API at endpoint. a store kept for another
+// endpoint starts empty. a corrupt store is moved aside and an empty one
+// returned along with the error, so the client still works. with an empty
+// path nothing is written to disk
+func Open(path, endpoint string) (*Store, error) {
+	s := &Store{
+		path:  path,
+		state: state{Endpoint: endpoint, NextID: 1},
+	}
+
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path of the user's own config dir
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return s, fmt.Errorf("failed to read offline store: %w", err)
+	}
+
+	var saved state
+	if err := json.Unmarshal(data, &saved); err != nil {
+		// kept for the user to recover by hand
+		os.Rename(path, path+corruptSuffix) //nolint:errcheck,gosec // starts empty either way
+		return s, fmt.Errorf("offline store was corrupt, moved it to %s: %w", path+corruptSuffix, err)
+	}
+
+	if saved.Endpoint != endpoint {
+		return s, nil
+	}
+
+	s.state = saved
+	s.state.NextID = max(s.state.NextID, 1)
+
+	return s, nil
+}
+
+// the editor's last draft
+func (s *Store) Draft() Draft {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	draft := Draft{Conversation: slices.Clone(s.state.Draft.Conversation)}
+	if s.state.Draft.Strudel != nil {
+		ref := *s.state.Draft.Strudel
+		draft.Strudel = &ref
+	}
+
+	return draft
+}
+
+// replaces the editor's draft
+func (s *Store) SetDraft(draft Draft) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.Draft = draft
+	return s.write()
+}
+
+// queues a query for the agent
+func (s *Store) EnqueueAgent(query string) (Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op := s.newOperation(KindAgent)
+	op.Query = query
+	s.state.Queue = append(s.state.Queue, op)
+
+	return op, s.write()
+}
+
+// queues a save of code. strudelID is empty to save a new strudel titled
+// title; otherwise base is the save the code was written on top of. a save
+// already waiting for the same strudel takes the code instead, keeping its
+// place and base
+func (s *Store) EnqueueSave(strudelID, title, code string, base time.Time) (Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strudelID != "" {
+		for i, op := range s.state.Queue {
+			if op.Kind == KindSave && op.StrudelID == strudelID && !op.Conflict && op.Error == "" {
+				s.state.Queue[i].Code = code
+				s.state.Queue[i].Title = title
+				return s.state.Queue[i], s.write()
+			}
+		}
+	}
+
+	op := s.newOperation(KindSave)
+	op.StrudelID = strudelID
+	op.Title = title
+	op.Code = code
+	op.BaseUpdatedAt = base
+	s.state.Queue = append(s.state.Queue, op)
+
+	return op, s.write()
+}
+
+// the queued operations, oldest first
+func (s *Store) Queue() []Operation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.state.Queue)
+}
+
+// the oldest operation that can be sent, skipping those waiting for the user
+func (s *Store) Next() (Operation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, op := range s.state.Queue {
+		if !op.Conflict && op.Error == "" {
+			return op, true
+		}
+	}
+
+	return Operation{}, false
+}
+
+// reports whether an agent query is waiting to be sent, so later queries
+// queue behind it
+func (s *Store) HasQueries() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.ContainsFunc(s.state.Queue, func(op Operation) bool {
+		return op.Kind == KindAgent
+	})
+}
+
+// counts the queued operations and those waiting for the user
+func (s *Store) Counts() (queued, unresolved int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, op := range s.state.Queue {
+		if op.Conflict || op.Error != "" {
+			unresolved++
+		}
+	}
+
+	return len(s.state.Queue), unresolved
+}
+
+// removes a sent or discarded operation
+func (s *Store) Remove(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.Queue = slices.DeleteFunc(s.state.Queue, func(op Operation) bool {
+		return op.ID == id
+	})
+
+	return s.write()
+}
+
+// marks a save that conflicts with one made elsewhere
+func (s *Store) MarkConflict(id int64) error {
+	return s.update(id, func(op *Operation) {
+		op.Conflict = true
+	})
+}
+
+// marks an operation the API refused
+func (s *Store) MarkFailed(id int64, reason string) error {
+	return s.update(id, func(op *Operation) {
+		op.Error = reason
+	})
+}
+
+// lets a conflicted or failed operation be sent again
+func (s *Store) Retry(id int64) error {
+	return s.update(id, func(op *Operation) {
+		op.Conflict = false
+		op.Error = ""
+	})
+}
+
+func (s *Store) update(id int64, change func(op *Operation)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.state.Queue, func(op Operation) bool {
+		return op.ID == id
+	})
+	if i < 0 {
+		return nil
+	}
+
+	change(&s.state.Queue[i])
+	return s.write()
+}
+
+// must be called with mu held
+func (s *Store) newOperation(kind string) Operation {
+	op := Operation{ID: s.state.NextID, Kind: kind, CreatedAt: time.Now()}
+	s.state.NextID++
+
+	return op
+}
+
+// saves the state, only readable by the user. must be called with mu held
+func (s *Store) write() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal offline store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// written next to the file and renamed, so a crash can't leave it half written
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), storeFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save offline store: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // gone after the rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck,gosec // already failing
+		return fmt.Errorf("failed to save offline store: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save offline store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save offline store: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package offline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreSurvivesReopening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "algopatterns", storeFile)
+
+	s, err := Open(path, "http://api")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	draft := Draft{
+		Conversation: []Message{{Role: "user", Content: "a beat"}, {Role: "assistant", Content: `s("bd")`}},
+		Strudel:      &StrudelRef{ID: "s1", Title: "groove"},
+	}
+	if err := s.SetDraft(draft); err != nil {
+		t.Fatalf("SetDraft() error = %v", err)
+	}
+	if _, err := s.EnqueueAgent("faster"); err != nil {
+		t.Fatalf("EnqueueAgent() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("store not written: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("store mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	reopened, err := Open(path, "http://api")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	got := reopened.Draft()
+	if len(got.Conversation) != 2 || got.Strudel == nil || got.Strudel.ID != "s1" {
+		t.Errorf("Draft() = %+v", got)
+	}
+
+	queue := reopened.Queue()
+	if len(queue) != 1 || queue[0].Kind != KindAgent || queue[0].Query != "faster" {
+		t.Errorf("Queue() = %+v", queue)
+	}
+
+	// ids keep counting up after reopening
+	op, err := reopened.EnqueueAgent("slower")
+	if err != nil {
+		t.Fatalf("EnqueueAgent() error = %v", err)
+	}
+	if op.ID <= queue[0].ID {
+		t.Errorf("reused id %d after %d", op.ID, queue[0].ID)
+	}
+}
+
+func TestStoreOfAnotherEndpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), storeFile)
+
+	s, _ := Open(path, "http://staging") //nolint:errcheck // nothing to read yet
+	s.EnqueueAgent("a beat")             //nolint:errcheck,gosec // checked by reopening
+
+	other, err := Open(path, "http://api")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if queued, _ := other.Counts(); queued != 0 {
+		t.Errorf("queue of another endpoint was loaded, %d operations", queued)
+	}
+}
+
+func TestCorruptStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), storeFile)
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(path, "http://api")
+	if err == nil {
+		t.Error("Open() of a corrupt store should report it")
+	}
+	if s == nil {
+		t.Fatal("Open() should return an empty store for a corrupt one")
+	}
+
+	if _, err := os.Stat(path + corruptSuffix); err != nil {
+		t.Errorf("corrupt store not moved aside: %v", err)
+	}
+
+	if _, err := s.EnqueueAgent("a beat"); err != nil {
+		t.Errorf("EnqueueAgent() error = %v", err)
+	}
+}
+
+func TestEnqueueSaveCoalesces(t *testing.T) {
+	s, _ := Open("", "http://api") //nolint:errcheck // in memory
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	first, _ := s.EnqueueSave("s1", "groove", "v1", base) //nolint:errcheck // in memory
+	s.EnqueueAgent("a beat")                              //nolint:errcheck,gosec // in memory
+	second, _ := s.EnqueueSave("s1", "groove", "v2", base.Add(time.Hour))
+	s.EnqueueSave("", "new one", "v1", time.Time{}) //nolint:errcheck,gosec // in memory
+
+	if second.ID != first.ID {
+		t.Errorf("second save of s1 got id %d, want %d", second.ID, first.ID)
+	}
+
+	queue := s.Queue()
+	if len(queue) != 3 {
+		t.Fatalf("Queue() has %d operations, want 3", len(queue))
+	}
+	if queue[0].Code != "v2" || !queue[0].BaseUpdatedAt.Equal(base) {
+		t.Errorf("coalesced save = %+v, want the new code on the first base", queue[0])
+	}
+
+	// a conflicted save is left for the user, and later saves queue apart
+	s.MarkConflict(first.ID) //nolint:errcheck,gosec // in memory
+	third, _ := s.EnqueueSave("s1", "groove", "v3", base)
+	if third.ID == first.ID {
+		t.Error("save was merged into a conflicted one")
+	}
+}
+
+func TestNextSkipsUnresolved(t *testing.T) {
+	s, _ := Open("", "http://api") //nolint:errcheck // in memory
+
+	save, _ := s.EnqueueSave("s1", "groove", "v1", time.Now()) //nolint:errcheck // in memory
+	query, _ := s.EnqueueAgent("a beat")                       //nolint:errcheck // in memory
+
+	if op, ok := s.Next(); !ok || op.ID != save.ID {
+		t.Errorf("Next() = %+v, %v, want the save", op, ok)
+	}
+
+	s.MarkConflict(save.ID) //nolint:errcheck,gosec // in memory
+	if op, ok := s.Next(); !ok || op.ID != query.ID {
+		t.Errorf("Next() = %+v, %v, want the query", op, ok)
+	}
+
+	if queued, unresolved := s.Counts(); queued != 2 || unresolved != 1 {
+		t.Errorf("Counts() = %d, %d, want 2, 1", queued, unresolved)
+	}
+
+	s.Remove(query.ID) //nolint:errcheck,gosec // in memory
+	if _, ok := s.Next(); ok {
+		t.Error("Next() should skip the conflicted save")
+	}
+
+	s.Retry(save.ID) //nolint:errcheck,gosec // in memory
+	if op, ok := s.Next(); !ok || op.ID != save.ID {
+		t.Errorf("Next() after Retry() = %+v, %v", op, ok)
+	}
+}
@@ -0,0 +1,81 @@
+package offline
+
+import (
+	"sync"
+	"time"
+)
+
+// kinds of queued operations
+const (
+	KindAgent = "agent" // a query for the agent
+	KindSave  = "save"  // code to save to the library
+)
+
+const (
+	// where the store is kept, in the user's config dir
+	configDirName = "algopatterns"
+	storeFile     = "offline.json"
+
+	// suffix a corrupt store is moved aside to
+	corruptSuffix = ".corrupt"
+)
+
+// keeps the editor's draft and the operations waiting for the network, so
+// neither is lost when the connection drops or the TUI exits
+type Store struct {
+	path string // empty to keep everything in memory
+
+	mu    sync.Mutex
+	state state
+}
+
+// what is written to disk
+type state struct {
+	Endpoint string      `json:"endpoint"`
+	Draft    Draft       `json:"draft"`
+	Queue    []Operation `json:"queue"`
+	NextID   int64       `json:"next_id"`
+}
+
+// the editor's conversation and the strudel it works on
+type Draft struct {
+	Conversation []Message   `json:"conversation,omitempty"`
+	Strudel      *StrudelRef `json:"strudel,omitempty"`
+}
+
+// a message of the editor's conversation
+type Message struct {
+	Role      string   `json:"role"`
+	Content   string   `json:"content"`
+	Metadata  string   `json:"metadata,omitempty"`
+	Questions []string `json:"questions,omitempty"`
+}
+
+// the strudel a draft or a save belongs to
+type StrudelRef struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// an agent request or save waiting to be sent
+type Operation struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// agent requests
+	Query string `json:"query,omitempty"`
+
+	// saves. StrudelID is empty for a new strudel; BaseUpdatedAt is the save
+	// the code was written on top of
+	StrudelID     string    `json:"strudel_id,omitempty"`
+	Title         string    `json:"title,omitempty"`
+	Code          string    `json:"code,omitempty"`
+	BaseUpdatedAt time.Time `json:"base_updated_at,omitzero"`
+
+	// set when sending failed for a reason retrying won't fix. such
+	// operations wait for the user to resolve them
+	Conflict bool   `json:"conflict,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
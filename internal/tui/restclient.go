@@ -1,58 +1,60 @@
 package tui
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
 )
 
 // manages HTTP requests to the agent REST API
 type AgentClient struct {
 	endpoint   string
 	httpClient *http.Client
+	api        *api.Client // signs requests in as the logged-in user
 }
 
-// creates a new agent REST client
-func NewAgentClient() *AgentClient {
-	endpoint := os.Getenv("ALGOPATTERNS_API_ENDPOINT")
-	if endpoint == "" {
-		endpoint = "http://localhost:8080"
-	}
-
+// creates a new agent REST client talking to the same API as client
+func NewAgentClient(client *api.Client) *AgentClient {
 	return &AgentClient{
-		endpoint: endpoint,
+		endpoint: client.Endpoint(),
 		httpClient: &http.Client{
 			Timeout: agentRequestTimeout,
 		},
+		api: client,
 	}
 }
 
-// sends a generate request to the agent REST API
-func (c *AgentClient) Generate(ctx context.Context, userQuery, editorState string, conversationHistory []MessageModel) (*AgentResponseMsg, error) {
-	// filter out messages with empty content (e.g., clarifying questions responses)
-	// LLM APIs reject messages with empty content
-	filteredHistory := make([]MessageModel, 0, len(conversationHistory))
-	for _, msg := range conversationHistory {
-		if msg.Content != "" {
-			filteredHistory = append(filteredHistory, MessageModel{
-				Role:    msg.Role,
-				Content: msg.Content,
-			})
-		}
+// adds the token of the logged-in user to a request, if there is one
+func (c *AgentClient) authorize(req *http.Request) error {
+	token, err := c.api.Token(req.Context())
+	if err != nil {
+		return err
 	}
 
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return nil
+}
+
+// sends a generate request to the agent REST API
+func (c *AgentClient) Generate(ctx context.Context, userQuery, editorState string, conversationHistory []MessageModel) (*AgentResponseMsg, error) {
 	// build request payload
 	payload := agentGenerateRequest{
 		UserQuery:           userQuery,
 		EditorState:         editorState,
-		ConversationHistory: filteredHistory,
+		ConversationHistory: filterHistory(conversationHistory),
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -68,6 +70,9 @@ func (c *AgentClient) Generate(ctx context.Context, userQuery, editorState strin
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
 
 	// send request
 	resp, err := c.httpClient.Do(req)
@@ -122,6 +127,150 @@ func (c *AgentClient) GenerateCmd(userQuery, editorState string, conversationHis
 	}
 }
 
+// sends a generate request to the streaming endpoint, calling onChunk for
+// each piece of generated text as it arrives
+func (c *AgentClient) GenerateStream(ctx context.Context, userQuery, editorState string, conversationHistory []MessageModel, onChunk func(chunk string)) (*AgentResponseMsg, error) {
+	payloadBytes, err := json.Marshal(agentGenerateRequest{
+		UserQuery:           userQuery,
+		EditorState:         editorState,
+		ConversationHistory: filterHistory(conversationHistory),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/agent/generate/stream", c.endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	// errors before streaming starts are returned as regular JSON responses
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		var errResp agentErrorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("%s: %s", errResp.Error, errResp.Message)
+		}
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result agentGenerateResponse
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, streamScanBufferSize), maxStreamEventSize)
+
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+
+		var event agentStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			continue // skip malformed events
+		}
+
+		switch event.Type {
+		case "refs":
+			result.DocsRetrieved = event.DocsRetrieved
+			result.ExamplesRetrieved = event.ExamplesRetrieved
+		case "chunk":
+			onChunk(event.Content)
+		case "error":
+			return nil, fmt.Errorf("generation failed: %s", event.Error)
+		case "done":
+			result.Code = event.Content
+			result.Model = event.Model
+			result.IsCodeResponse = event.IsCodeResponse
+			result.DocsRetrieved = event.DocsRetrieved
+			result.ExamplesRetrieved = event.ExamplesRetrieved
+
+			code, metadata := formatAgentResponse(result)
+			if event.RateLimit != nil && event.RateLimit.Limit > 0 {
+				metadata += fmt.Sprintf(" | daily limit: %d/%d", event.RateLimit.Current, event.RateLimit.Limit)
+			}
+
+			return &AgentResponseMsg{
+				userQuery:      userQuery,
+				code:           code,
+				metadata:       metadata,
+				isCodeResponse: result.IsCodeResponse,
+			}, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading stream: %w", err)
+	}
+
+	return nil, fmt.Errorf("stream ended without a response")
+}
+
+// returns a tea.Cmd that streams a generate request. chunks are delivered as
+// AgentStreamChunkMsg, followed by a final AgentResponseMsg or AgentErrorMsg.
+func (c *AgentClient) GenerateStreamCmd(userQuery, editorState string, conversationHistory []MessageModel) tea.Cmd {
+	stream := make(chan tea.Msg, streamChannelSize)
+
+	go func() {
+		defer close(stream)
+
+		ctx, cancel := context.WithTimeout(context.Background(), agentRequestTimeout)
+		defer cancel()
+
+		resp, err := c.GenerateStream(ctx, userQuery, editorState, conversationHistory, func(chunk string) {
+			stream <- AgentStreamChunkMsg{chunk: chunk, stream: stream}
+		})
+		if err != nil {
+			stream <- AgentErrorMsg{userQuery: userQuery, err: err}
+			return
+		}
+
+		stream <- *resp
+	}()
+
+	return waitForStream(stream)
+}
+
+// returns a tea.Cmd that waits for the next message of a streamed response
+func waitForStream(stream <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-stream
+	}
+}
+
+// removes messages with empty content (e.g., clarifying questions responses)
+// since LLM APIs reject them
+func filterHistory(conversationHistory []MessageModel) []MessageModel {
+	filtered := make([]MessageModel, 0, len(conversationHistory))
+	for _, msg := range conversationHistory {
+		if msg.Content != "" {
+			filtered = append(filtered, MessageModel{
+				Role:    msg.Role,
+				Content: msg.Content,
+			})
+		}
+	}
+
+	return filtered
+}
+
 // REST API request/response types
 
 type agentGenerateRequest struct {
@@ -140,6 +289,21 @@ type agentGenerateResponse struct {
 	ClarifyingQuestions []string `json:"clarifying_questions,omitempty"`
 }
 
+type agentStreamEvent struct {
+	Type              string `json:"type"` // "refs", "chunk", "done", "error"
+	Content           string `json:"content,omitempty"`
+	Error             string `json:"error,omitempty"`
+	DocsRetrieved     int    `json:"docs_retrieved,omitempty"`
+	ExamplesRetrieved int    `json:"examples_retrieved,omitempty"`
+	Model             string `json:"model,omitempty"`
+	IsCodeResponse    bool   `json:"is_code_response,omitempty"`
+	RateLimit         *struct {
+		Limit     int `json:"limit"`
+		Current   int `json:"current"`
+		Remaining int `json:"remaining"`
+	} `json:"rate_limit,omitempty"`
+}
+
 type agentErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
@@ -160,3 +324,10 @@ func formatAgentResponse(result agentGenerateResponse) (string, string) {
 
 // timeout for agent requests
 const agentRequestTimeout = 60 * time.Second
+
+// streaming limits
+const (
+	streamChannelSize    = 64
+	streamScanBufferSize = 64 * 1024
+	maxStreamEventSize   = 1024 * 1024 // done events carry the full response
+)
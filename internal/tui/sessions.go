@@ -0,0 +1,488 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
+)
+
+// returns a new session browser
+func NewSessions(client *api.Client) *SessionsModel {
+	ti := textinput.New()
+	ti.CharLimit = 200
+	ti.Prompt = "> "
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(colorLightGray)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(colorWhite)
+
+	return &SessionsModel{
+		client: client,
+		input:  ti,
+	}
+}
+
+// loads both lists when the browser opens
+func (m *SessionsModel) Init() tea.Cmd {
+	m.loading = true
+	return m.loadCmd()
+}
+
+func (m *SessionsModel) Update(msg tea.Msg) (*SessionsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.inputMode != inputNone {
+			return m.updateInput(msg)
+		}
+		return m.updateKeys(msg)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.input.Width = msg.Width - 4
+
+	case SessionsLoadedMsg:
+		m.loading = false
+		m.mine, m.mineErr = msg.mine, msg.mineErr
+		m.live, m.liveErr = msg.live, msg.liveErr
+		m.cursor = min(m.cursor, max(m.rows()-1, 0))
+
+	case SessionCreatedMsg:
+		m.status = fmt.Sprintf("created %q", msg.session.Title)
+		m.tab = tabMine
+		m.cursor = 0
+		m.loading = true
+		return m, tea.Batch(m.loadCmd(), m.participantsCmd(msg.session.ID, msg.session.Title))
+
+	case SessionJoinedMsg:
+		// the connection takes the invite's role, like the web app's
+		m.status = fmt.Sprintf("joined as %s (%s), connecting...", msg.result.DisplayName, msg.result.Role)
+		return m, connectLive(m.client, msg.result.SessionID, "joined session", msg.inviteToken)
+
+	case ParticipantsLoadedMsg:
+		m.detail = &sessionDetail{
+			sessionID:    msg.sessionID,
+			title:        msg.title,
+			participants: msg.participants,
+		}
+
+	case SessionsErrorMsg:
+		m.loading = false
+		m.status = ""
+		m.err = msg.err
+	}
+
+	return m, nil
+}
+
+// handles keys while the title or invite prompt is open
+func (m *SessionsModel) updateInput(msg tea.KeyMsg) (*SessionsModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.closeInput()
+		return m, nil
+
+	case "enter":
+		value := strings.TrimSpace(m.input.Value())
+		mode := m.inputMode
+		m.closeInput()
+
+		if value == "" {
+			return m, nil
+		}
+
+		m.err = nil
+		if mode == inputCreate {
+			m.status = "creating session..."
+			return m, m.createCmd(value)
+		}
+
+		m.status = "joining session..."
+		return m, m.joinCmd(inviteToken(value))
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// handles keys while browsing
+func (m *SessionsModel) updateKeys(msg tea.KeyMsg) (*SessionsModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		if m.detail != nil {
+			m.detail = nil
+			return m, nil
+		}
+		return m, func() tea.Msg { return LeaveSessionsMsg{} }
+
+	case "tab":
+		m.tab = (m.tab + 1) % 2
+		m.cursor = 0
+		m.detail = nil
+
+	case "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down":
+		if m.cursor < m.rows()-1 {
+			m.cursor++
+		}
+
+	case "r":
+		m.err = nil
+		m.status = ""
+		m.loading = true
+		return m, m.loadCmd()
+
+	case "n":
+		if !m.client.Authenticated() {
+			m.err = errNotSignedIn
+			return m, nil
+		}
+		m.openInput(inputCreate, "session title")
+
+	case "i":
+		m.openInput(inputJoin, "invite token or link")
+
+	case "enter":
+		return m, m.openSelected()
+
+	case "c":
+		return m, m.connectSelected()
+	}
+
+	return m, nil
+}
+
+// shows the participants of the selected session
+func (m *SessionsModel) openSelected() tea.Cmd {
+	if m.rows() == 0 {
+		return nil
+	}
+
+	if !m.client.Authenticated() {
+		m.err = errNotSignedIn
+		return nil
+	}
+
+	if m.tab == tabMine {
+		session := m.mine[m.cursor]
+		return m.participantsCmd(session.ID, session.Title)
+	}
+
+	session := m.live[m.cursor]
+	if !session.IsMember {
+		m.err = fmt.Errorf("join %q with an invite to see who's in it", session.Title)
+		return nil
+	}
+
+	return m.participantsCmd(session.ID, session.Title)
+}
+
+// connects to the selected session, as its host, with the role an earlier
+// invite gave, or as a viewer of a discoverable session
+func (m *SessionsModel) connectSelected() tea.Cmd {
+	if m.rows() == 0 {
+		return nil
+	}
+
+	m.err = nil
+	m.status = "connecting..."
+
+	if m.tab == tabMine {
+		session := m.mine[m.cursor]
+		if !session.IsActive {
+			m.status = ""
+			m.err = fmt.Errorf("%q has ended", session.Title)
+			return nil
+		}
+		return connectLive(m.client, session.ID, session.Title, "")
+	}
+
+	session := m.live[m.cursor]
+	return connectLive(m.client, session.ID, session.Title, "")
+}
+
+func (m *SessionsModel) openInput(mode sessionsInputMode, placeholder string) {
+	m.err = nil
+	m.inputMode = mode
+	m.input.Placeholder = placeholder
+	m.input.SetValue("")
+	m.input.Focus()
+}
+
+func (m *SessionsModel) closeInput() {
+	m.inputMode = inputNone
+	m.input.Blur()
+	m.input.SetValue("")
+}
+
+// number of sessions in the current tab
+func (m *SessionsModel) rows() int {
+	if m.tab == tabMine {
+		return len(m.mine)
+	}
+	return len(m.live)
+}
+
+func (m *SessionsModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.tabsView())
+	b.WriteString("\n\n")
+
+	switch {
+	case m.detail != nil:
+		b.WriteString(m.participantsView())
+	case m.loading:
+		b.WriteString(infoStyle.Render("loading sessions..."))
+		b.WriteString("\n")
+	case m.tab == tabMine:
+		b.WriteString(m.mineView())
+	default:
+		b.WriteString(m.liveView())
+	}
+
+	b.WriteString("\n")
+
+	if m.inputMode != inputNone {
+		b.WriteString(m.input.View())
+		b.WriteString("\n\n")
+	}
+
+	if m.err != nil {
+		b.WriteString(lipgloss.NewStyle().Foreground(colorRed).Render("error: " + m.err.Error()))
+		b.WriteString("\n")
+	} else if m.status != "" {
+		b.WriteString(infoStyle.Render(m.status))
+		b.WriteString("\n")
+	}
+
+	help := "switch: tab | select: ↑/↓ | participants: enter | connect: c | new: n | join: i | refresh: r | back: esc"
+	if m.inputMode != inputNone {
+		help = "confirm: enter | cancel: esc"
+	}
+	b.WriteString(helpStyle.Render(help))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func (m *SessionsModel) tabsView() string {
+	tabs := []string{"my sessions", "live sessions"}
+
+	rendered := make([]string, len(tabs))
+	for i, tab := range tabs {
+		style := lipgloss.NewStyle().Foreground(colorGray).Padding(0, 1)
+		if sessionsTab(i) == m.tab {
+			style = style.Bold(true).Foreground(colorWhite).Background(colorPurple)
+		}
+		rendered[i] = style.Render(tab)
+	}
+
+	signedIn := infoStyle.Render("  " + identity(m.client) + " · " + m.client.Endpoint())
+
+	return lipgloss.JoinHorizontal(lipgloss.Left, append(rendered, signedIn)...)
+}
+
+func (m *SessionsModel) mineView() string {
+	if m.mineErr != nil {
+		if api.IsStatus(m.mineErr, http.StatusUnauthorized) {
+			return infoStyle.Render("sign in to see your sessions.") + "\n"
+		}
+		return lipgloss.NewStyle().Foreground(colorRed).Render(m.mineErr.Error()) + "\n"
+	}
+
+	if len(m.mine) == 0 {
+		return infoStyle.Render("no sessions yet. press n to start one.") + "\n"
+	}
+
+	var b strings.Builder
+	for i, session := range m.mine {
+		state := "ended"
+		if session.IsActive {
+			state = "active"
+		}
+		if session.IsDiscoverable {
+			state += ", discoverable"
+		}
+
+		b.WriteString(m.row(i, session.Title, fmt.Sprintf("%s · %s", state, since(session.LastActivity))))
+	}
+
+	return b.String()
+}
+
+func (m *SessionsModel) liveView() string {
+	if m.liveErr != nil {
+		return lipgloss.NewStyle().Foreground(colorRed).Render(m.liveErr.Error()) + "\n"
+	}
+
+	if len(m.live) == 0 {
+		return infoStyle.Render("no live sessions right now.") + "\n"
+	}
+
+	var b strings.Builder
+	for i, session := range m.live {
+		details := fmt.Sprintf("%d in session · %s", session.ParticipantCount, since(session.LastActivity))
+		if session.IsMember {
+			details += " · joined"
+		}
+
+		b.WriteString(m.row(i, session.Title, details))
+	}
+
+	return b.String()
+}
+
+// a list row, highlighted under the cursor
+func (m *SessionsModel) row(i int, title, details string) string {
+	cursor := "  "
+	nameStyle := lipgloss.NewStyle().Foreground(colorLightGray)
+	if i == m.cursor {
+		cursor = lipgloss.NewStyle().Foreground(colorPurple).Bold(true).Render("> ")
+		nameStyle = nameStyle.Bold(true).Foreground(colorWhite)
+	}
+
+	return cursor + nameStyle.Render(title) + commandDescStyle.Render(details) + "\n"
+}
+
+func (m *SessionsModel) participantsView() string {
+	var b strings.Builder
+
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(colorWhite).Render(m.detail.title))
+	b.WriteString(infoStyle.Render("  " + m.detail.sessionID))
+	b.WriteString("\n\n")
+
+	if len(m.detail.participants) == 0 {
+		b.WriteString(infoStyle.Render("nobody has joined yet."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	for _, p := range m.detail.participants {
+		name := "anonymous"
+		if p.DisplayName != nil && *p.DisplayName != "" {
+			name = *p.DisplayName
+		}
+
+		details := p.Role
+		if p.Status != "" && p.Status != "active" {
+			details += ", " + p.Status
+		}
+		if p.Muted {
+			details += ", muted"
+		}
+
+		b.WriteString("  " + commandStyle.Render(name) + commandDescStyle.Render(details) + "\n")
+	}
+
+	return b.String()
+}
+
+// fetches both lists. each is kept separately, so a guest still sees live
+// sessions when their own can't be listed
+func (m *SessionsModel) loadCmd() tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		var msg SessionsLoadedMsg
+		msg.live, msg.liveErr = client.ListLiveSessions(ctx)
+		if client.Authenticated() {
+			msg.mine, msg.mineErr = client.ListSessions(ctx, false)
+		} else {
+			msg.mineErr = &api.Error{StatusCode: http.StatusUnauthorized}
+		}
+
+		return msg
+	}
+}
+
+func (m *SessionsModel) createCmd(title string) tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		session, err := client.CreateSession(ctx, title, "", false)
+		if err != nil {
+			return SessionsErrorMsg{err: fmt.Errorf("failed to create session: %w", err)}
+		}
+
+		return SessionCreatedMsg{session: session}
+	}
+}
+
+func (m *SessionsModel) joinCmd(token string) tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		result, err := client.JoinSession(ctx, token, os.Getenv("USER"))
+		if err != nil {
+			return SessionsErrorMsg{err: fmt.Errorf("failed to join session: %w", err)}
+		}
+
+		return SessionJoinedMsg{result: result, inviteToken: token}
+	}
+}
+
+func (m *SessionsModel) participantsCmd(sessionID, title string) tea.Cmd {
+	client := m.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		participants, err := client.ListParticipants(ctx, sessionID)
+		if err != nil {
+			return SessionsErrorMsg{err: fmt.Errorf("failed to list participants: %w", err)}
+		}
+
+		return ParticipantsLoadedMsg{sessionID: sessionID, title: title, participants: participants}
+	}
+}
+
+// takes the token out of an invite link, or returns the input as the token
+func inviteToken(input string) string {
+	if u, err := url.Parse(input); err == nil && u.Scheme != "" {
+		if token := u.Query().Get("invite"); token != "" {
+			return token
+		}
+	}
+
+	return input
+}
+
+// how long ago t was, roughly
+func since(t time.Time) string {
+	d := time.Since(t)
+
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,529 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
+	"codeberg.org/algopatterns/server/internal/tui/offline"
+)
+
+// returns a syncer queueing work in store while the API can't be reached
+func NewSyncer(client *api.Client, store *offline.Store) *Syncer {
+	return &Syncer{
+		store:  store,
+		client: client,
+	}
+}
+
+// sends what was left queued by the last run, once the API is reachable
+func (s *Syncer) Init() tea.Cmd {
+	if queued, _ := s.store.Counts(); queued == 0 {
+		return nil
+	}
+
+	return s.ping()
+}
+
+func (s *Syncer) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case SyncProbeMsg:
+		s.ticking = false
+		return s.ping()
+
+	case SyncPingedMsg:
+		s.pinging = false
+
+		if api.IsOffline(msg.err) {
+			s.offline = true
+			return s.probe()
+		}
+
+		if s.offline {
+			s.status = "back online"
+		}
+		s.offline = false
+		return s.next()
+
+	case SyncReplayedMsg:
+		s.replaying = false
+		return s.replayed(msg)
+
+	case SyncResolvedMsg:
+		if api.IsOffline(msg.err) {
+			s.markOffline()
+			return s.probe()
+		}
+
+		if msg.err != nil {
+			s.status = msg.err.Error()
+			return nil
+		}
+
+		s.keep(s.store.Remove(msg.op.ID))
+		s.saved(msg.op, msg.strudel)
+		s.status = msg.status
+		return nil
+	}
+
+	return nil
+}
+
+// reports whether the API couldn't be reached the last time it was tried
+func (s *Syncer) Offline() bool {
+	return s.offline
+}
+
+// reports whether agent queries are queued, which later ones wait behind
+func (s *Syncer) HasQueries() bool {
+	return s.store.HasQueries()
+}
+
+// notes that a request couldn't reach the API
+func (s *Syncer) markOffline() {
+	s.offline = true
+}
+
+// queues a query for the agent
+func (s *Syncer) QueueQuery(query string) tea.Cmd {
+	_, err := s.store.EnqueueAgent(query)
+	s.keep(err)
+
+	return s.resume()
+}
+
+// queues a save of the editor's code
+func (s *Syncer) QueueSave(save offline.Operation) tea.Cmd {
+	_, err := s.store.EnqueueSave(save.StrudelID, save.Title, save.Code, save.BaseUpdatedAt)
+	s.keep(err)
+
+	return s.resume()
+}
+
+// carries on sending the queue, or waits for the API to be reachable
+func (s *Syncer) resume() tea.Cmd {
+	if s.offline {
+		return s.probe()
+	}
+
+	return s.next()
+}
+
+// checks the API again after a while, unless that is already planned
+func (s *Syncer) probe() tea.Cmd {
+	if s.ticking || s.pinging {
+		return nil
+	}
+
+	s.ticking = true
+	return tea.Tick(syncProbeInterval, func(time.Time) tea.Msg {
+		return SyncProbeMsg{}
+	})
+}
+
+// checks the API right away, unless it is being checked
+func (s *Syncer) ping() tea.Cmd {
+	if s.pinging {
+		return nil
+	}
+
+	s.pinging = true
+	client := s.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		return SyncPingedMsg{err: client.Ping(ctx)}
+	}
+}
+
+// sends the oldest queued operation, one at a time so they arrive in order
+func (s *Syncer) next() tea.Cmd {
+	if s.offline || s.replaying {
+		return nil
+	}
+
+	op, ok := s.store.Next()
+	if !ok {
+		return nil
+	}
+
+	if op.Kind == offline.KindAgent {
+		// sent once the editor's own request is answered
+		if s.editor.isFetching {
+			return nil
+		}
+		s.replaying = true
+		return s.replayQuery(op)
+	}
+
+	s.replaying = true
+	return s.replaySave(op)
+}
+
+// asks the agent a queued query, on the editor's code and conversation as
+// they are now
+func (s *Syncer) replayQuery(op offline.Operation) tea.Cmd {
+	agent := s.editor.agentClient
+	code := s.editor.GetCode()
+	history := slices.Clone(s.editor.conversationHistory)
+
+	s.editor.isFetching = true
+	s.editor.pendingQuery = op.Query
+	s.editor.streamingContent = ""
+
+	replay := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), agentRequestTimeout)
+		defer cancel()
+
+		resp, err := agent.Generate(ctx, op.Query, code, history)
+		return SyncReplayedMsg{op: op, response: resp, err: err}
+	}
+
+	return tea.Batch(s.editor.spinner.Tick, replay)
+}
+
+// sends a queued save. an update only applies on top of the save it was
+// written on, so changes made elsewhere meanwhile aren't lost
+func (s *Syncer) replaySave(op offline.Operation) tea.Cmd {
+	client := s.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		var (
+			strudel *api.Strudel
+			err     error
+		)
+
+		if op.StrudelID == "" {
+			strudel, err = client.CreateStrudel(ctx, api.NewStrudel{Title: op.Title, Code: op.Code})
+		} else {
+			base := op.BaseUpdatedAt
+			strudel, err = client.UpdateStrudel(ctx, op.StrudelID, api.StrudelUpdate{Code: &op.Code, UpdatedAt: &base})
+		}
+
+		return SyncReplayedMsg{op: op, strudel: strudel, err: err}
+	}
+}
+
+// handles the outcome of sending a queued operation, then sends the next
+func (s *Syncer) replayed(msg SyncReplayedMsg) tea.Cmd {
+	op := msg.op
+
+	if op.Kind == offline.KindAgent {
+		s.editor.isFetching = false
+		s.editor.pendingQuery = ""
+	}
+
+	switch {
+	case api.IsOffline(msg.err):
+		// stays queued
+		s.markOffline()
+		return s.probe()
+
+	case op.Kind == offline.KindAgent:
+		s.keep(s.store.Remove(op.ID))
+
+		// answered into the editor like a query sent right away
+		var reply tea.Msg = AgentErrorMsg{userQuery: op.Query, err: msg.err}
+		if msg.err == nil {
+			reply = *msg.response
+		}
+		s.editor.Update(reply)
+
+	case api.IsStatus(msg.err, http.StatusConflict):
+		s.keep(s.store.MarkConflict(op.ID))
+		s.status = fmt.Sprintf("%q was saved elsewhere while offline, type sync to resolve it", op.Title)
+
+	case msg.err != nil:
+		s.keep(s.store.MarkFailed(op.ID, msg.err.Error()))
+		s.status = fmt.Sprintf("failed to save %q, type sync to retry", op.Title)
+
+	default:
+		s.keep(s.store.Remove(op.ID))
+		s.saved(op, msg.strudel)
+		s.status = fmt.Sprintf("saved %q queued while offline", msg.strudel.Title)
+	}
+
+	return s.next()
+}
+
+// the editor saves on top of a strudel saved from the queue
+func (s *Syncer) saved(op offline.Operation, strudel *api.Strudel) {
+	opened := s.editor.strudel
+
+	if (op.StrudelID == "" && opened == nil) || (opened != nil && opened.ID == strudel.ID) {
+		s.editor.setStrudel(strudel)
+	}
+}
+
+// overwrites what was saved elsewhere with the queued code
+func (s *Syncer) overwriteCmd(op offline.Operation) tea.Cmd {
+	client := s.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		current, err := client.GetStrudel(ctx, op.StrudelID)
+		if err != nil {
+			return SyncResolvedMsg{op: op, err: err}
+		}
+
+		strudel, err := client.UpdateStrudel(ctx, op.StrudelID, api.StrudelUpdate{Code: &op.Code, UpdatedAt: &current.UpdatedAt})
+		if err != nil {
+			return SyncResolvedMsg{op: op, err: err}
+		}
+
+		return SyncResolvedMsg{op: op, strudel: strudel, status: fmt.Sprintf("overwrote %q", strudel.Title)}
+	}
+}
+
+// saves the queued code as a new strudel, leaving what was saved elsewhere
+func (s *Syncer) saveCopyCmd(op offline.Operation) tea.Cmd {
+	client := s.client
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsRequestTimeout)
+		defer cancel()
+
+		strudel, err := client.CreateStrudel(ctx, api.NewStrudel{
+			Title: api.SuffixTitle(op.Title, " (offline copy)"),
+			Code:  op.Code,
+		})
+		if err != nil {
+			return SyncResolvedMsg{op: op, err: err}
+		}
+
+		return SyncResolvedMsg{op: op, strudel: strudel, status: fmt.Sprintf("saved a copy as %q", strudel.Title)}
+	}
+}
+
+// keeps the editor's conversation and strudel for the next run
+func (s *Syncer) saveDraft(history []MessageModel, strudel *api.Strudel) {
+	draft := offline.Draft{Conversation: make([]offline.Message, 0, len(history))}
+
+	for _, msg := range history {
+		draft.Conversation = append(draft.Conversation, offline.Message(msg))
+	}
+
+	if strudel != nil {
+		draft.Strudel = &offline.StrudelRef{ID: strudel.ID, Title: strudel.Title, UpdatedAt: strudel.UpdatedAt}
+	}
+
+	s.keep(s.store.SetDraft(draft))
+}
+
+// the editor's conversation and strudel of the last run
+func (s *Syncer) draft() ([]MessageModel, *api.Strudel) {
+	draft := s.store.Draft()
+
+	history := make([]MessageModel, 0, len(draft.Conversation))
+	for _, msg := range draft.Conversation {
+		history = append(history, MessageModel(msg))
+	}
+
+	var strudel *api.Strudel
+	if draft.Strudel != nil {
+		strudel = &api.Strudel{ID: draft.Strudel.ID, Title: draft.Strudel.Title, UpdatedAt: draft.Strudel.UpdatedAt}
+	}
+
+	return history, strudel
+}
+
+// the queries waiting to be sent, shown after the editor's conversation
+func (s *Syncer) queuedQueries() []string {
+	var queries []string
+	for _, op := range s.store.Queue() {
+		if op.Kind == offline.KindAgent {
+			queries = append(queries, op.Query)
+		}
+	}
+
+	return queries
+}
+
+// remembers a failure of the store, to show in the status lines
+func (s *Syncer) keep(err error) {
+	if err != nil {
+		s.err = err
+	}
+}
+
+// the state of the connection and queue for the status lines of the views,
+// empty while online with nothing queued
+func (s *Syncer) Status() string {
+	var parts []string
+
+	if s.offline {
+		parts = append(parts, "offline")
+	}
+
+	queued, unresolved := s.store.Counts()
+	if queued > unresolved {
+		parts = append(parts, fmt.Sprintf("%d queued", queued-unresolved))
+	}
+	if unresolved > 0 {
+		parts = append(parts, fmt.Sprintf("%d to resolve (type sync)", unresolved))
+	}
+	if s.err != nil {
+		parts = append(parts, "offline store: "+s.err.Error())
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// returns a new sync screen
+func NewSyncView(sync *Syncer) *SyncModel {
+	return &SyncModel{sync: sync}
+}
+
+// checks the API when the screen opens, to send what can be sent
+func (m *SyncModel) Init() tea.Cmd {
+	m.cursor = 0
+	return m.sync.ping()
+}
+
+func (m *SyncModel) Update(msg tea.Msg) (*SyncModel, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	queue := m.sync.store.Queue()
+	m.cursor = min(m.cursor, max(len(queue)-1, 0))
+
+	switch key.String() {
+	case "esc":
+		return m, func() tea.Msg { return LeaveSyncMsg{} }
+
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+
+	case "down", "j":
+		m.cursor = min(m.cursor+1, max(len(queue)-1, 0))
+
+	case "s":
+		m.sync.status = "checking the connection..."
+		return m, m.sync.ping()
+	}
+
+	if len(queue) == 0 {
+		return m, nil
+	}
+
+	op := queue[m.cursor]
+	sync := m.sync
+
+	switch key.String() {
+	case "r":
+		sync.keep(sync.store.Retry(op.ID))
+		sync.status = "retrying..."
+		return m, sync.resume()
+
+	case "d":
+		sync.keep(sync.store.Remove(op.ID))
+		sync.status = "discarded"
+		return m, sync.resume()
+
+	case "o":
+		if op.Conflict {
+			sync.status = fmt.Sprintf("overwriting %q...", op.Title)
+			return m, sync.overwriteCmd(op)
+		}
+
+	case "n":
+		if op.Kind == offline.KindSave && (op.Conflict || op.Error != "") {
+			sync.status = fmt.Sprintf("saving a copy of %q...", op.Title)
+			return m, sync.saveCopyCmd(op)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *SyncModel) View() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(colorWhite).Background(colorPurple).Padding(0, 1).Render("sync")
+	connection := "online"
+	if m.sync.offline {
+		connection = "offline, checking every " + syncProbeInterval.String()
+	}
+	b.WriteString(title + infoStyle.Render("  "+connection+" · "+m.sync.client.Endpoint()))
+	b.WriteString("\n\n")
+
+	queue := m.sync.store.Queue()
+	if len(queue) == 0 {
+		b.WriteString(infoStyle.Render("nothing queued."))
+		b.WriteString("\n")
+	}
+
+	for i, op := range queue {
+		cursor := "  "
+		style := lipgloss.NewStyle().Foreground(colorLightGray)
+		if i == m.cursor {
+			cursor = commandStyle.Render("> ")
+			style = lipgloss.NewStyle().Foreground(colorWhite)
+		}
+
+		what := fmt.Sprintf("ask %q", op.Query)
+		if op.Kind == offline.KindSave {
+			what = fmt.Sprintf("save %q", op.Title)
+			if op.StrudelID == "" {
+				what += " as a new strudel"
+			}
+		}
+
+		state := infoStyle.Render("queued " + op.CreatedAt.Format(time.Kitchen))
+		switch {
+		case op.Conflict:
+			state = lipgloss.NewStyle().Foreground(colorYellow).Render("saved elsewhere meanwhile")
+		case op.Error != "":
+			state = lipgloss.NewStyle().Foreground(colorRed).Render("failed: " + op.Error)
+		}
+
+		b.WriteString(cursor + style.Render(what) + "  " + state)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if m.sync.status != "" {
+		b.WriteString(infoStyle.Render(m.sync.status))
+		b.WriteString("\n")
+	}
+	if m.sync.err != nil {
+		b.WriteString(lipgloss.NewStyle().Foreground(colorRed).Render("error: offline store: " + m.sync.err.Error()))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render(m.help(queue)))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func (m *SyncModel) help(queue []offline.Operation) string {
+	if len(queue) > 0 {
+		op := queue[min(m.cursor, len(queue)-1)]
+
+		switch {
+		case op.Conflict:
+			return "select: ↑/↓ | overwrite: o | save as new: n | discard: d | sync now: s | back: esc"
+		case op.Kind == offline.KindSave && op.Error != "":
+			return "select: ↑/↓ | retry: r | save as new: n | discard: d | sync now: s | back: esc"
+		}
+	}
+
+	return "select: ↑/↓ | retry: r | discard: d | sync now: s | back: esc"
+}
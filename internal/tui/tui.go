@@ -4,19 +4,41 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
+	"codeberg.org/algopatterns/server/internal/tui/offline"
 )
 
 func NewApp(mode string) *Model {
+	client := api.NewFromEnv()
+
+	// a corrupt store is replaced by an empty one, which is said on the welcome screen
+	store, storeErr := offline.Open(offline.DefaultPath(), client.Endpoint())
+
+	sync := NewSyncer(client, store)
+	editor := NewEditor(client, sync)
+	sync.editor = editor
+
+	welcome := NewWelcome(mode, client, sync)
+	if storeErr != nil {
+		welcome.status = storeErr.Error()
+	}
+
 	return &Model{
-		state:   StateWelcome,
-		mode:    mode,
-		welcome: NewWelcome(mode),
-		editor:  NewEditor(),
+		state:    StateWelcome,
+		mode:     mode,
+		client:   client,
+		welcome:  welcome,
+		editor:   editor,
+		sessions: NewSessions(client),
+		library:  NewLibrary(client, sync, editor),
+		sync:     sync,
+		syncView: NewSyncView(sync),
 	}
 }
 
 func (m *Model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(fetchIdentity(m.client), m.sync.Init())
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -27,8 +49,22 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-		// in editor, ctrl+c should go back to welcome
-		if msg.String() == "ctrl+c" && m.state == StateEditor {
+		// leaving a connected session closes the connection
+		if msg.String() == "ctrl+c" && m.state == StateLive {
+			m.live.Close()
+			m.state = StateWelcome
+			return m, nil
+		}
+
+		// giving up on a login stops waiting for it
+		if msg.String() == "ctrl+c" && m.state == StateLogin {
+			m.login.Close()
+			m.state = StateWelcome
+			return m, nil
+		}
+
+		// in editor, session browser, library and sync screen, ctrl+c should go back to welcome
+		if msg.String() == "ctrl+c" && (m.state == StateEditor || m.state == StateSessions || m.state == StateLibrary || m.state == StateSync) {
 			m.state = StateWelcome
 			return m, nil
 		}
@@ -41,6 +77,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.editor, _ = m.editor.Update(msg)
 		}
 
+		if m.state == StateSessions {
+			m.sessions, _ = m.sessions.Update(msg)
+		}
+
+		if m.state == StateLive {
+			m.live, _ = m.live.Update(msg)
+		}
+
+		if m.state == StateLibrary {
+			m.library, _ = m.library.Update(msg)
+		}
+
 	case ErrorMsg:
 		m.err = msg.err
 		return m, nil
@@ -48,6 +96,94 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case EnterEditorMsg:
 		m.state = StateEditor
 		return m, m.editor.Init()
+
+	case EnterSessionsMsg:
+		m.state = StateSessions
+		return m, m.sessions.Init()
+
+	case LeaveSessionsMsg:
+		m.state = StateWelcome
+		return m, nil
+
+	case LiveConnectedMsg:
+		m.live = NewLive(msg, m.width, m.height)
+		m.state = StateLive
+		return m, m.live.Init()
+
+	case LeaveLiveMsg:
+		m.state = StateSessions
+		return m, m.sessions.Init()
+
+	case EnterLibraryMsg:
+		m.state = StateLibrary
+		return m, m.library.Init()
+
+	case LeaveLibraryMsg:
+		m.state = StateWelcome
+		return m, nil
+
+	case EnterSyncMsg:
+		m.state = StateSync
+		return m, m.syncView.Init()
+
+	case LeaveSyncMsg:
+		m.state = StateWelcome
+		return m, nil
+
+	case SyncProbeMsg, SyncPingedMsg, SyncReplayedMsg, SyncResolvedMsg:
+		return m, m.sync.Update(msg)
+
+	case AgentStreamChunkMsg, AgentResponseMsg, AgentErrorMsg:
+		// answered into the editor even if it was left meanwhile, and queued
+		// queries are sent once it is answered
+		var cmd tea.Cmd
+		m.editor, cmd = m.editor.Update(msg)
+		if _, chunk := msg.(AgentStreamChunkMsg); chunk {
+			return m, cmd
+		}
+		return m, tea.Batch(cmd, m.sync.resume())
+
+	case OpenStrudelMsg:
+		m.editor.Open(msg.strudel)
+		m.state = StateEditor
+		return m, m.editor.Init()
+
+	case EnterLoginMsg:
+		if m.login != nil {
+			m.login.Close()
+		}
+		m.login = NewLogin(m.client)
+		m.state = StateLogin
+		return m, m.login.Init()
+
+	case LeaveLoginMsg:
+		m.state = StateWelcome
+		return m, nil
+
+	case LoginDoneMsg:
+		// a login given up on, or one that failed and is shown on its screen
+		if m.state != StateLogin || msg.login != m.login.login || msg.err != nil {
+			break
+		}
+		m.welcome.status = identity(m.client)
+		m.state = StateWelcome
+		return m, nil
+
+	case LoggedOutMsg:
+		m.welcome.status = "logged out"
+		if msg.err != nil {
+			m.welcome.status = "logged out here, but " + msg.err.Error()
+		}
+		return m, nil
+
+	case LiveEventMsg, LiveClosedMsg:
+		// messages of a connection left behind, still draining
+		if m.state != StateLive {
+			if event, ok := msg.(LiveEventMsg); ok {
+				return m, waitForStream(event.stream)
+			}
+			return m, nil
+		}
 	}
 
 	switch m.state {
@@ -57,6 +193,21 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case StateEditor:
 		return m.updateEditor(msg)
 
+	case StateSessions:
+		return m.updateSessions(msg)
+
+	case StateLive:
+		return m.updateLive(msg)
+
+	case StateLogin:
+		return m.updateLogin(msg)
+
+	case StateLibrary:
+		return m.updateLibrary(msg)
+
+	case StateSync:
+		return m.updateSync(msg)
+
 	default:
 		return m, nil
 	}
@@ -74,6 +225,21 @@ func (m *Model) View() string {
 	case StateEditor:
 		return m.editor.View()
 
+	case StateSessions:
+		return m.sessions.View()
+
+	case StateLive:
+		return m.live.View()
+
+	case StateLogin:
+		return m.login.View()
+
+	case StateLibrary:
+		return m.library.View()
+
+	case StateSync:
+		return m.syncView.View()
+
 	default:
 		return "Unknown state"
 	}
@@ -93,6 +259,41 @@ func (m *Model) updateEditor(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m *Model) updateSessions(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.sessions, cmd = m.sessions.Update(msg)
+
+	return m, cmd
+}
+
+func (m *Model) updateLive(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.live, cmd = m.live.Update(msg)
+
+	return m, cmd
+}
+
+func (m *Model) updateLogin(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.login, cmd = m.login.Update(msg)
+
+	return m, cmd
+}
+
+func (m *Model) updateLibrary(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.library, cmd = m.library.Update(msg)
+
+	return m, cmd
+}
+
+func (m *Model) updateSync(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.syncView, cmd = m.syncView.Update(msg)
+
+	return m, cmd
+}
+
 func errorView(err error) string {
 	return fmt.Sprintf("\n  Error: %v\n\n  Press Ctrl+C to exit\n", err)
 }
@@ -1,10 +1,19 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
+	"codeberg.org/algopatterns/server/internal/tui/offline"
 )
 
 // represents the current state of the TUI
@@ -13,19 +22,31 @@ type AppState int
 const (
 	StateWelcome AppState = iota
 	StateEditor
+	StateSessions
+	StateLive
+	StateLogin
+	StateLibrary
+	StateSync
 	StateOutput
 	StateLoading
 )
 
 // main TUI application model
 type Model struct {
-	state   AppState
-	mode    string
-	width   int
-	height  int
-	err     error
-	welcome *Welcome
-	editor  *EditorModel
+	state    AppState
+	mode     string
+	width    int
+	height   int
+	err      error
+	client   *api.Client // shared by every view, so they're all signed in alike
+	welcome  *Welcome
+	editor   *EditorModel
+	sessions *SessionsModel
+	library  *LibraryModel
+	sync     *Syncer
+	syncView *SyncModel
+	live     *LiveModel  // nil until a session is connected to
+	login    *LoginModel // nil until a login is started
 }
 
 // sent when an error occurs
@@ -36,6 +57,12 @@ type ErrorMsg struct {
 // sent to transition to the editor state
 type EnterEditorMsg struct{}
 
+// sent to transition to the session browser
+type EnterSessionsMsg struct{}
+
+// sent to leave the session browser for the welcome screen
+type LeaveSessionsMsg struct{}
+
 // represents a chat message in the conversation
 type MessageModel struct {
 	Role      string   `json:"role"`
@@ -57,6 +84,10 @@ type EditorModel struct {
 	ready               bool
 	shouldScrollBottom  bool
 	agentClient         *AgentClient
+	sync                *Syncer
+	strudel             *api.Strudel // opened from the library, nil for new code
+	pendingQuery        string       // query whose response is being streamed
+	streamingContent    string       // response text received so far
 }
 
 // sent when the agent completes a request
@@ -68,6 +99,12 @@ type AgentResponseMsg struct {
 	isCodeResponse bool
 }
 
+// sent for each piece of a streamed agent response
+type AgentStreamChunkMsg struct {
+	chunk  string
+	stream <-chan tea.Msg
+}
+
 // sent when the agent encounters an error
 type AgentErrorMsg struct {
 	userQuery string
@@ -79,6 +116,9 @@ type Welcome struct {
 	mode     string
 	input    string
 	commands []Command
+	client   *api.Client
+	sync     *Syncer
+	status   string // outcome of the last login or logout
 }
 
 // represents an available TUI command
@@ -93,3 +133,337 @@ type ServerStartedMsg struct{}
 
 // sent when the ingester completes
 type IngesterCompleteMsg struct{}
+
+// tabs of the session browser
+type sessionsTab int
+
+const (
+	tabMine sessionsTab = iota
+	tabLive
+)
+
+// what the session browser's prompt is asking for
+type sessionsInputMode int
+
+const (
+	inputNone sessionsInputMode = iota
+	inputCreate
+	inputJoin
+)
+
+// timeout of session browser requests
+const sessionsRequestTimeout = 15 * time.Second
+
+var errNotSignedIn = errors.New("sign in first (type login on the welcome screen)")
+
+// session browser: the user's sessions and discoverable live sessions
+type SessionsModel struct {
+	client    *api.Client
+	tab       sessionsTab
+	cursor    int
+	mine      []api.Session
+	mineErr   error
+	live      []api.LiveSession
+	liveErr   error
+	detail    *sessionDetail // participants being shown, nil while browsing
+	loading   bool
+	status    string
+	err       error
+	input     textinput.Model
+	inputMode sessionsInputMode
+	width     int
+	height    int
+}
+
+// a session whose participants are shown
+type sessionDetail struct {
+	sessionID    string
+	title        string
+	participants []api.Participant
+}
+
+// sent when the session lists are fetched
+type SessionsLoadedMsg struct {
+	mine    []api.Session
+	mineErr error
+	live    []api.LiveSession
+	liveErr error
+}
+
+// sent when a session was created
+type SessionCreatedMsg struct {
+	session *api.Session
+}
+
+// sent when a session was joined with an invite
+type SessionJoinedMsg struct {
+	result      *api.JoinResult
+	inviteToken string
+}
+
+// sent when the participants of a session are fetched
+type ParticipantsLoadedMsg struct {
+	sessionID    string
+	title        string
+	participants []api.Participant
+}
+
+// sent when a session browser request fails
+type SessionsErrorMsg struct {
+	err error
+}
+
+// pane of the live view that takes keys
+type liveFocus int
+
+const (
+	focusChat liveFocus = iota
+	focusCode
+)
+
+// live view limits
+const (
+	maxLiveChatLength = 5000 // the server's chat message limit
+	maxLiveChatLines  = 500
+	livePresenceRows  = 8
+)
+
+// a session connected to over WebSocket: the shared code, presence and chat
+type LiveModel struct {
+	conn         *api.Conn
+	stream       <-chan tea.Msg
+	title        string
+	code         textarea.Model
+	lastCode     string // code last received or sent
+	chatLog      viewport.Model
+	chat         []liveChatLine
+	input        textinput.Model
+	focus        liveFocus
+	presence     []api.Presence
+	listeners    int
+	role         string
+	displayName  string
+	canEdit      bool
+	ended        bool
+	disconnected bool
+	status       string
+	err          error
+	width        int
+	height       int
+}
+
+// a line of the live chat
+type liveChatLine struct {
+	name   string
+	text   string
+	system bool // joins, leaves and the session ending
+}
+
+// sent when a session was connected to
+type LiveConnectedMsg struct {
+	conn   *api.Conn
+	stream <-chan tea.Msg
+	title  string
+}
+
+// sent for each message received from a connected session
+type LiveEventMsg struct {
+	message api.Message
+	stream  <-chan tea.Msg
+}
+
+// sent when the connection to a session closes
+type LiveClosedMsg struct {
+	err    error
+	stream <-chan tea.Msg
+}
+
+// sent to leave a connected session for the session browser
+type LeaveLiveMsg struct{}
+
+// timeout of logging out
+const logoutTimeout = 10 * time.Second
+
+// device login screen: shows the code to enter on the website while waiting
+// for the user to approve it
+type LoginModel struct {
+	client  *api.Client
+	login   *api.DeviceLogin // nil until the login started
+	cancel  context.CancelFunc
+	spinner spinner.Model
+	err     error
+}
+
+// sent to start a login
+type EnterLoginMsg struct{}
+
+// sent when the device login started and its code can be shown
+type LoginStartedMsg struct {
+	login *api.DeviceLogin
+	err   error
+}
+
+// sent when the user approved or denied the login, or it expired
+type LoginDoneMsg struct {
+	login *api.DeviceLogin
+	user  *api.User
+	err   error
+}
+
+// sent to leave the login screen for the welcome screen
+type LeaveLoginMsg struct{}
+
+// sent when the user logged out
+type LoggedOutMsg struct {
+	err error
+}
+
+// sent when the signed-in user was fetched
+type IdentityMsg struct{}
+
+// what the library shows
+type libraryView int
+
+const (
+	libraryList libraryView = iota
+	libraryDetail
+	libraryEdit
+)
+
+// what the library's prompt is asking for
+type libraryPrompt int
+
+const (
+	promptNone libraryPrompt = iota
+	promptSearch
+	promptSaveTitle
+)
+
+// fields of the library's edit form
+const (
+	fieldTitle = iota
+	fieldTags
+	fieldCCSignal
+	fieldCount
+)
+
+// lines of code shown in a strudel's details
+const libraryPreviewLines = 12
+
+// strudel library: the user's strudels, to search, open in the editor, save
+// the editor's code to, fork and edit
+type LibraryModel struct {
+	client     *api.Client
+	sync       *Syncer
+	editor     *EditorModel // the code saved comes from, and opened strudels go to, the editor
+	strudels   []api.Strudel
+	total      *int
+	nextCursor string
+	prevCursor string
+	cursor     int
+	search     string // as typed, with #tags
+	view       libraryView
+	detail     *api.Strudel // fetched in full, for the detail and edit views
+	prompt     libraryPrompt
+	input      textinput.Model
+	title      textinput.Model // fields of the edit form
+	tags       textinput.Model
+	ccSignal   string // picked with ←/→, "" for none
+	field      int    // focused field of the edit form
+	overwrite  bool   // the last save conflicted, the next one overwrites
+	loading    bool
+	status     string
+	err        error
+	width      int
+	height     int
+}
+
+// sent to open the library
+type EnterLibraryMsg struct{}
+
+// sent to leave the library for the welcome screen
+type LeaveLibraryMsg struct{}
+
+// sent when a page of the library is fetched
+type LibraryLoadedMsg struct {
+	page *api.StrudelPage
+}
+
+// sent when a strudel is fetched to show or edit
+type StrudelLoadedMsg struct {
+	strudel *api.Strudel
+	edit    bool
+}
+
+// sent when a strudel was saved, forked or edited
+type StrudelSavedMsg struct {
+	strudel *api.Strudel
+	status  string
+	created bool // saved from the editor as a new strudel, which the editor now edits
+}
+
+// sent to open a strudel in the editor
+type OpenStrudelMsg struct {
+	strudel *api.Strudel
+}
+
+// sent when a library request fails
+type LibraryErrorMsg struct {
+	err        error
+	editorSave bool               // saving the editor's code, which may be retried over a conflict
+	save       *offline.Operation // the save to queue if the API couldn't be reached
+}
+
+// how often the API is checked while it can't be reached
+const syncProbeInterval = 10 * time.Second
+
+// keeps the TUI working while the API can't be reached: agent requests and
+// saves are queued in the offline store and sent in order once it is back,
+// and the editor's draft is kept there across runs
+type Syncer struct {
+	store     *offline.Store
+	client    *api.Client
+	editor    *EditorModel // queued queries are answered into the editor
+	offline   bool
+	ticking   bool // a check of the API is scheduled
+	pinging   bool // the API is being checked
+	replaying bool // a queued operation is being sent
+	status    string
+	err       error // of the store
+}
+
+// sync screen: the queued operations, to resolve conflicts and failures
+type SyncModel struct {
+	sync   *Syncer
+	cursor int
+}
+
+// sent to check whether the API can be reached again
+type SyncProbeMsg struct{}
+
+// sent when the API was checked
+type SyncPingedMsg struct {
+	err error
+}
+
+// sent when a queued operation was sent
+type SyncReplayedMsg struct {
+	op       offline.Operation
+	response *AgentResponseMsg // of agent queries
+	strudel  *api.Strudel      // of saves
+	err      error
+}
+
+// sent when a conflicted save was overwritten or saved as a new strudel
+type SyncResolvedMsg struct {
+	op      offline.Operation
+	strudel *api.Strudel
+	status  string
+	err     error
+}
+
+// sent to open the sync screen
+type EnterSyncMsg struct{}
+
+// sent to leave the sync screen for the welcome screen
+type LeaveSyncMsg struct{}
@@ -6,20 +6,29 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"codeberg.org/algopatterns/server/internal/tui/api"
 )
 
 // returns a new welcome screen
-func NewWelcome(mode string) *Welcome {
+func NewWelcome(mode string, client *api.Client, sync *Syncer) *Welcome {
 	commands := []Command{
 		{Name: "start", Description: "start the algopatterns server", Available: true},
 		{Name: "ingest", Description: "run documentation ingester", Available: mode == "development"},
 		{Name: "editor", Description: "interactive code editor", Available: true},
+		{Name: "sessions", Description: "browse, create and join sessions", Available: true},
+		{Name: "library", Description: "search, open, save and fork your strudels", Available: true},
+		{Name: "sync", Description: "review work queued while offline", Available: true},
+		{Name: "login", Description: "sign in with your algopatterns account", Available: true},
+		{Name: "logout", Description: "sign out of this terminal", Available: true},
 		{Name: "quit", Description: "exit algopatterns", Available: true},
 	}
 
 	return &Welcome{
 		mode:     mode,
 		commands: commands,
+		client:   client,
+		sync:     sync,
 	}
 }
 
@@ -61,7 +70,19 @@ func (m *Welcome) View() string {
 
 	modeText := fmt.Sprintf("mode: %s", strings.ToUpper(m.mode))
 	b.WriteString(infoStyle.Render(modeText))
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	b.WriteString(infoStyle.Render(identity(m.client)))
+	b.WriteString("\n")
+	if sync := m.sync.Status(); sync != "" {
+		b.WriteString(infoStyle.Render(sync))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.status != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(colorLightGray).Render(m.status))
+		b.WriteString("\n\n")
+	}
 
 	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(colorWhite).Render("commands:"))
 	b.WriteString("\n\n")
@@ -113,6 +134,39 @@ func (m *Welcome) executeCommand() tea.Cmd {
 			return EnterEditorMsg{}
 		}
 
+	case "sessions":
+		m.input = ""
+		return func() tea.Msg {
+			return EnterSessionsMsg{}
+		}
+
+	case "library":
+		m.input = ""
+		return func() tea.Msg {
+			return EnterLibraryMsg{}
+		}
+
+	case "sync":
+		m.input = ""
+		return func() tea.Msg {
+			return EnterSyncMsg{}
+		}
+
+	case "login":
+		m.input = ""
+		m.status = ""
+		return func() tea.Msg {
+			return EnterLoginMsg{}
+		}
+
+	case "logout":
+		m.input = ""
+		if !m.client.Authenticated() {
+			m.status = "not signed in"
+			return nil
+		}
+		return logout(m.client)
+
 	default:
 		if cmd != "" {
 			return func() tea.Msg {
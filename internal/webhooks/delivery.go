@@ -0,0 +1,261 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// queues an event for delivery to the user's webhooks subscribed to it, in
+// the transaction of the write it reports. the event is stored as a
+// background job so callers never wait on webhook bookkeeping, and it's only
+// stored when the write commits
+func (s *Service) PublishTx(ctx context.Context, tx pgx.Tx, userID, event string, data any) error {
+	if userID == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event %s: %w", event, err)
+	}
+
+	return s.jobs.EnqueueTx(ctx, tx, jobEvent, eventJob{UserID: userID, Event: event, Data: payload})
+}
+
+// creates a delivery of a published event for each subscribed webhook
+func (s *Service) handleEventJob(ctx context.Context, payload json.RawMessage) error {
+	var job eventJob
+	if err := jobs.Decode(payload, &job); err != nil {
+		return err
+	}
+
+	_, err := s.db.Exec(ctx, queryEnqueueEvent, job.UserID, job.Event, job.Data)
+	return err
+}
+
+// queues a ping to a webhook owned by the user, to test the endpoint
+func (s *Service) Ping(ctx context.Context, webhookID, userID string) (*Delivery, error) {
+	payload, err := json.Marshal(map[string]string{"webhook_id": webhookID})
+	if err != nil {
+		return nil, err
+	}
+
+	var d Delivery
+	err = s.db.QueryRow(ctx, queryEnqueuePing, webhookID, userID, EventPing, payload).Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.Event,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.ResponseStatus,
+		&d.ResponseBody,
+		&d.Error,
+		&d.DurationMS,
+		&d.NextAttemptAt,
+		&d.LastAttemptAt,
+		&d.CreatedAt,
+	)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	return &d, nil
+}
+
+// runs the delivery worker until ctx is cancelled. safe to run on every
+// instance; deliveries are claimed with row locks.
+func (s *Service) Start(ctx context.Context) {
+	logger.Info("starting webhook delivery worker", "poll_interval", pollInterval)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastRetention := time.Time{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("webhook delivery worker stopped")
+			return
+		case <-ticker.C:
+			s.deliverDue(ctx)
+
+			if time.Since(lastRetention) >= retentionInterval {
+				s.deleteOldDeliveries(ctx)
+				lastRetention = time.Now()
+			}
+		}
+	}
+}
+
+// claims and sends a batch of due deliveries
+func (s *Service) deliverDue(ctx context.Context) {
+	rows, err := s.db.Query(ctx, queryClaimDue, claimBatchSize, claimLease.Seconds())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to claim webhook deliveries")
+		}
+		return
+	}
+
+	var claimed []claimedDelivery
+	for rows.Next() {
+		var d claimedDelivery
+		if err := rows.Scan(&d.id, &d.event, &d.payload, &d.attempts, &d.createdAt, &d.url, &d.secret); err != nil {
+			rows.Close()
+			logger.ErrorErr(err, "failed to scan webhook delivery")
+			return
+		}
+
+		claimed = append(claimed, d)
+	}
+
+	rows.Close()
+
+	var wg sync.WaitGroup
+	for _, d := range claimed {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(ctx, d)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// sends one delivery attempt and records its outcome
+func (s *Service) deliver(ctx context.Context, d claimedDelivery) {
+	body, err := json.Marshal(Envelope{
+		ID:        d.id,
+		Event:     d.event,
+		CreatedAt: d.createdAt,
+		Data:      d.payload,
+	})
+	if err != nil {
+		logger.ErrorErr(err, "failed to encode webhook envelope", "delivery_id", d.id)
+		return
+	}
+
+	result := s.send(ctx, d.url, d.secret, d.id, d.event, body)
+	attempts := d.attempts + 1
+
+	status := StatusSucceeded
+	var nextAttempt *time.Time
+	var errMessage *string
+
+	if result.err != nil {
+		message := result.err.Error()
+		errMessage = &message
+
+		status = StatusFailed
+		if attempts < maxAttempts {
+			status = StatusPending
+			next := time.Now().Add(backoff(attempts))
+			nextAttempt = &next
+		}
+	}
+
+	var responseStatus *int
+	if result.status != 0 {
+		responseStatus = &result.status
+	}
+
+	durationMS := int(result.duration.Milliseconds())
+
+	// record even if the worker is stopping, so the attempt isn't lost
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deliveryTimeout)
+	defer cancel()
+
+	_, err = s.db.Exec(recordCtx, queryRecordAttempt,
+		d.id,
+		status,
+		responseStatus,
+		result.body,
+		errMessage,
+		durationMS,
+		nextAttempt,
+	)
+	if err != nil {
+		logger.ErrorErr(err, "failed to record webhook delivery", "delivery_id", d.id)
+	}
+
+	if status == StatusFailed {
+		logger.Warn("webhook delivery failed permanently",
+			"delivery_id", d.id,
+			"event", d.event,
+			"attempts", attempts,
+			"error", result.err,
+		)
+	}
+}
+
+// posts a signed envelope to url. any non-2xx response is an error.
+func (s *Service) send(ctx context.Context, url, secret, deliveryID, event string, body []byte) attemptResult {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return attemptResult{err: err}
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "algopatterns-webhooks/1")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return attemptResult{err: err, duration: time.Since(start)}
+	}
+
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize)) //nolint:errcheck // partial body is fine for the log
+
+	result := attemptResult{
+		status:   resp.StatusCode,
+		body:     string(responseBody),
+		duration: time.Since(start),
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.err = fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return result
+}
+
+// deletes delivery logs past the retention period
+func (s *Service) deleteOldDeliveries(ctx context.Context) {
+	result, err := s.db.Exec(ctx, queryDeleteOldDeliveries, deliveryRetention.Seconds())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.ErrorErr(err, "failed to delete old webhook deliveries")
+		}
+		return
+	}
+
+	if deleted := result.RowsAffected(); deleted > 0 {
+		logger.Info("deleted old webhook deliveries", "count", deleted)
+	}
+}
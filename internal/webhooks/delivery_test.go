@@ -0,0 +1,78 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestSendSignsRequest(t *testing.T) {
+	body := []byte(`{"id":"d1","event":"ping","data":{}}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read body: %v", err)
+		}
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if err != nil {
+			t.Errorf("invalid timestamp header: %v", err)
+		}
+
+		if r.Header.Get(HeaderSignature) != Sign("secret", timestamp, received) {
+			t.Error("signature does not match body")
+		}
+
+		if r.Header.Get(HeaderEvent) != EventPing || r.Header.Get(HeaderDelivery) != "d1" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+
+		w.Write([]byte("ok")) //nolint:errcheck,gosec // test response
+	}))
+	defer server.Close()
+
+	// the test server listens on loopback, which the delivery client refuses
+	s := &Service{client: server.Client()}
+
+	result := s.send(context.Background(), server.URL, "secret", "d1", EventPing, body)
+	if result.err != nil {
+		t.Fatalf("unexpected error: %v", result.err)
+	}
+
+	if result.status != http.StatusOK || result.body != "ok" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestSendFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s := &Service{client: server.Client()}
+
+	result := s.send(context.Background(), server.URL, "secret", "d1", EventPing, []byte(`{}`))
+	if result.err == nil || result.status != http.StatusServiceUnavailable {
+		t.Errorf("expected failure with status 503, got %+v", result)
+	}
+}
+
+func TestDeliveryClientBlocksLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s := &Service{client: newHTTPClient()}
+
+	result := s.send(context.Background(), server.URL, "secret", "d1", EventPing, []byte(`{}`))
+	if !errors.Is(result.err, ErrBlockedAddress) {
+		t.Errorf("expected ErrBlockedAddress, got %v", result.err)
+	}
+}
@@ -0,0 +1,110 @@
+package webhooks
+
+const (
+	queryCreateWebhook = `
+		INSERT INTO webhooks (user_id, url, secret, events)
+		SELECT $1, $2, $3, $4
+		WHERE (SELECT COUNT(*) FROM webhooks WHERE user_id = $1) < $5
+		RETURNING id, user_id, url, events, active, secret, created_at, updated_at
+	`
+
+	queryListWebhooks = `
+		SELECT id, user_id, url, events, active, secret, created_at, updated_at
+		FROM webhooks
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	queryGetWebhook = `
+		SELECT id, user_id, url, events, active, secret, created_at, updated_at
+		FROM webhooks
+		WHERE id = $1 AND user_id = $2
+	`
+
+	queryUpdateWebhook = `
+		UPDATE webhooks
+		SET url = COALESCE($1, url),
+		    events = COALESCE($2, events),
+		    active = COALESCE($3, active),
+		    updated_at = NOW()
+		WHERE id = $4 AND user_id = $5
+		RETURNING id, user_id, url, events, active, secret, created_at, updated_at
+	`
+
+	queryRotateSecret = `
+		UPDATE webhooks
+		SET secret = $1, updated_at = NOW()
+		WHERE id = $2 AND user_id = $3
+		RETURNING id, user_id, url, events, active, secret, created_at, updated_at
+	`
+
+	queryDeleteWebhook = `
+		DELETE FROM webhooks
+		WHERE id = $1 AND user_id = $2
+	`
+
+	// one delivery per active webhook of the user subscribed to the event
+	queryEnqueueEvent = `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT id, $2, $3
+		FROM webhooks
+		WHERE user_id = $1 AND active = true AND $2 = ANY(events)
+	`
+
+	queryEnqueuePing = `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT id, $3, $4
+		FROM webhooks
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, webhook_id, event, payload, status, attempts, response_status, response_body, error, duration_ms, next_attempt_at, last_attempt_at, created_at
+	`
+
+	queryListDeliveries = `
+		SELECT d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.response_status, d.response_body, d.error, d.duration_ms, d.next_attempt_at, d.last_attempt_at, d.created_at,
+			COUNT(*) OVER () AS total
+		FROM webhook_deliveries d
+		INNER JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.webhook_id = $1 AND w.user_id = $2
+		ORDER BY d.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	// claims due deliveries by pushing next_attempt_at past the lease, so
+	// other instances skip them while they are being sent
+	queryClaimDue = `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			INNER JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending'
+			  AND d.next_attempt_at <= NOW()
+			  AND w.active = true
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.event, d.payload, d.attempts, d.created_at, w.url, w.secret
+	`
+
+	queryRecordAttempt = `
+		UPDATE webhook_deliveries
+		SET status = $2,
+		    attempts = attempts + 1,
+		    response_status = $3,
+		    response_body = $4,
+		    error = $5,
+		    duration_ms = $6,
+		    next_attempt_at = $7,
+		    last_attempt_at = NOW()
+		WHERE id = $1
+	`
+
+	queryDeleteOldDeliveries = `
+		DELETE FROM webhook_deliveries
+		WHERE created_at < NOW() - make_interval(secs => $1)
+	`
+)
@@ -0,0 +1,205 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/jobs"
+	"codeberg.org/algopatterns/server/internal/safehttp"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// event types
+const (
+	EventSessionCreated      = "session.created"
+	EventSessionEnded        = "session.ended"
+	EventStrudelPublished    = "strudel.published"
+	EventStrudelForked       = "strudel.forked"
+	EventAttributionRecorded = "attribution.recorded"
+	EventPerformanceLive     = "event.live"
+
+	// sent by the test endpoint regardless of subscriptions
+	EventPing = "ping"
+)
+
+// events a webhook can subscribe to
+var Events = []string{
+	EventSessionCreated,
+	EventSessionEnded,
+	EventStrudelPublished,
+	EventStrudelForked,
+	EventAttributionRecorded,
+	EventPerformanceLive,
+}
+
+// delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed" // gave up after maxAttempts
+)
+
+// request headers sent with every delivery
+const (
+	HeaderEvent     = "X-Algopatterns-Event"
+	HeaderDelivery  = "X-Algopatterns-Delivery"
+	HeaderTimestamp = "X-Algopatterns-Timestamp"
+	HeaderSignature = "X-Algopatterns-Signature" // "sha256=" + hex HMAC of "<timestamp>.<body>"
+)
+
+// background job that turns a published event into deliveries
+const jobEvent = "webhooks.event"
+
+// delivery constants
+const (
+	maxAttempts    = 8
+	initialBackoff = 30 * time.Second // doubled after each failed attempt
+	maxBackoff     = time.Hour
+
+	deliveryTimeout     = 10 * time.Second
+	maxResponseBodySize = 1024 // bytes of the response kept in the delivery log
+
+	// how often the worker looks for due deliveries, and how many it claims at once
+	pollInterval   = 5 * time.Second
+	claimBatchSize = 20
+
+	// a claimed delivery becomes due again after this, in case the instance dies mid-delivery
+	claimLease = time.Minute
+
+	// delivery logs older than this are deleted
+	deliveryRetention = 30 * 24 * time.Hour
+	retentionInterval = time.Hour
+)
+
+// management constants
+const (
+	MaxWebhooksPerUser = 10
+	secretBytes        = 32
+)
+
+// errors
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrTooManyWebhooks = errors.New("webhook limit reached")
+	ErrInvalidURL      = safehttp.ErrInvalidURL
+	ErrBlockedAddress  = safehttp.ErrBlockedAddress
+	ErrUnknownEvent    = errors.New("unknown webhook event")
+	ErrNoEvents        = errors.New("webhook must subscribe to at least one event")
+)
+
+// data of session.created and session.ended, sent to the host
+type SessionEventData struct {
+	SessionID string `json:"session_id"`
+	Title     string `json:"title"`
+	Reason    string `json:"reason,omitempty"` // session.ended only
+}
+
+// data of strudel.published, sent to the owner
+type StrudelEventData struct {
+	StrudelID string `json:"strudel_id"`
+	Title     string `json:"title"`
+}
+
+// data of strudel.forked, sent to the owner of the original
+type ForkEventData struct {
+	StrudelID string `json:"strudel_id"` // the original
+	ForkID    string `json:"fork_id"`
+	ForkTitle string `json:"fork_title"`
+	ForkedBy  string `json:"forked_by"` // user ID
+}
+
+// data of event.live, sent to everyone who RSVPed to a scheduled performance
+type PerformanceEventData struct {
+	EventID   string    `json:"event_id"`
+	SessionID string    `json:"session_id"`
+	Title     string    `json:"title"`
+	StartsAt  time.Time `json:"starts_at"`
+}
+
+// data of attribution.recorded, sent to the owner of the strudel used as AI context
+type AttributionEventData struct {
+	StrudelID       string  `json:"strudel_id"`
+	StrudelTitle    string  `json:"strudel_title"`
+	TargetStrudelID *string `json:"target_strudel_id,omitempty"`
+	RequestedBy     string  `json:"requested_by,omitempty"` // user ID, empty for anonymous requests
+	Similarity      float32 `json:"similarity"`
+}
+
+// stores webhooks and delivers events to them
+type Service struct {
+	db     *pgxpool.Pool
+	jobs   *jobs.Queue
+	client *http.Client
+}
+
+// a user's registered endpoint
+type Webhook struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"-"` // only returned on create and rotate
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// fields to change on a webhook (nil leaves the field unchanged)
+type UpdateWebhookRequest struct {
+	URL    *string
+	Events []string
+	Active *bool
+}
+
+// a log entry for one event sent to one webhook
+type Delivery struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	ResponseBody   *string         `json:"response_body,omitempty"`
+	Error          *string         `json:"error,omitempty"`
+	DurationMS     *int            `json:"duration_ms,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"` // only while pending
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// body posted to webhook URLs
+type Envelope struct {
+	ID        string          `json:"id"` // delivery ID, stable across retries
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// payload of a jobEvent job
+type eventJob struct {
+	UserID string          `json:"user_id"`
+	Event  string          `json:"event"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// a delivery claimed by the worker, with what is needed to send it
+type claimedDelivery struct {
+	id        string
+	event     string
+	payload   json.RawMessage
+	attempts  int
+	createdAt time.Time
+	url       string
+	secret    string
+}
+
+// outcome of one delivery attempt
+type attemptResult struct {
+	status   int // 0 when no response was received
+	body     string
+	err      error
+	duration time.Duration
+}
@@ -0,0 +1,59 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/safehttp"
+)
+
+// computes the signature header value for a delivery body. receivers
+// recompute it with their secret and the timestamp header to verify a
+// delivery and reject replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10))) //nolint:errcheck,gosec // hash writes never fail
+	mac.Write([]byte("."))                              //nolint:errcheck,gosec // hash writes never fail
+	mac.Write(body)                                     //nolint:errcheck,gosec // hash writes never fail
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// returns the delay before retrying after the given number of failed attempts
+func backoff(attempts int) time.Duration {
+	delay := initialBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func validateURL(raw string) error {
+	return safehttp.ValidateURL(raw)
+}
+
+// reports whether an address is publicly routable
+func isPublicAddr(ip netip.Addr) bool {
+	return safehttp.IsPublicAddr(ip)
+}
+
+// creates the client used for deliveries, which can't reach internal services
+func newHTTPClient() *http.Client {
+	return safehttp.NewClient(deliveryTimeout)
+}
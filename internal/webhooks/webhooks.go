@@ -0,0 +1,222 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"codeberg.org/algopatterns/server/internal/jobs"
+)
+
+func New(db *pgxpool.Pool, queue *jobs.Queue) *Service {
+	s := &Service{
+		db:     db,
+		jobs:   queue,
+		client: newHTTPClient(),
+	}
+
+	queue.Register(jobEvent, s.handleEventJob)
+
+	return s
+}
+
+// registers a webhook and generates its signing secret
+func (s *Service) Create(ctx context.Context, userID, url string, events []string) (*Webhook, error) {
+	if err := validateURL(url); err != nil {
+		return nil, err
+	}
+
+	events, err := normalizeEvents(events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := scanWebhook(s.db.QueryRow(ctx, queryCreateWebhook, userID, url, secret, events, MaxWebhooksPerUser))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTooManyWebhooks
+	}
+
+	return webhook, err
+}
+
+// gets all webhooks of a user
+func (s *Service) List(ctx context.Context, userID string) ([]*Webhook, error) {
+	rows, err := s.db.Query(ctx, queryListWebhooks, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	webhooks := []*Webhook{}
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// gets a webhook owned by the user
+func (s *Service) Get(ctx context.Context, webhookID, userID string) (*Webhook, error) {
+	webhook, err := scanWebhook(s.db.QueryRow(ctx, queryGetWebhook, webhookID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+
+	return webhook, err
+}
+
+// changes the URL, events or active state of a webhook owned by the user
+func (s *Service) Update(ctx context.Context, webhookID, userID string, req UpdateWebhookRequest) (*Webhook, error) {
+	if req.URL != nil {
+		if err := validateURL(*req.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	var events []string
+	if req.Events != nil {
+		var err error
+		if events, err = normalizeEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook, err := scanWebhook(s.db.QueryRow(ctx, queryUpdateWebhook, req.URL, events, req.Active, webhookID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+
+	return webhook, err
+}
+
+// replaces the signing secret of a webhook owned by the user
+func (s *Service) RotateSecret(ctx context.Context, webhookID, userID string) (*Webhook, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := scanWebhook(s.db.QueryRow(ctx, queryRotateSecret, secret, webhookID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+
+	return webhook, err
+}
+
+// deletes a webhook owned by the user along with its delivery log
+func (s *Service) Delete(ctx context.Context, webhookID, userID string) error {
+	result, err := s.db.Exec(ctx, queryDeleteWebhook, webhookID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// gets the delivery log of a webhook owned by the user, newest first
+func (s *Service) ListDeliveries(ctx context.Context, webhookID, userID string, limit, offset int) ([]*Delivery, int, error) {
+	// distinguish "no deliveries" from "not your webhook"
+	if _, err := s.Get(ctx, webhookID, userID); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(ctx, queryListDeliveries, webhookID, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+	deliveries := []*Delivery{}
+	total := 0
+
+	for rows.Next() {
+		var d Delivery
+		err := rows.Scan(
+			&d.ID,
+			&d.WebhookID,
+			&d.Event,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.ResponseStatus,
+			&d.ResponseBody,
+			&d.Error,
+			&d.DurationMS,
+			&d.NextAttemptAt,
+			&d.LastAttemptAt,
+			&d.CreatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		deliveries = append(deliveries, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+func scanWebhook(row pgx.Row) (*Webhook, error) {
+	var w Webhook
+
+	err := row.Scan(
+		&w.ID,
+		&w.UserID,
+		&w.URL,
+		&w.Events,
+		&w.Active,
+		&w.Secret,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// validates events and removes duplicates, keeping a stable order
+func normalizeEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return nil, ErrNoEvents
+	}
+
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(Events, event) {
+			return nil, ErrUnknownEvent
+		}
+
+		if !slices.Contains(normalized, event) {
+			normalized = append(normalized, event)
+		}
+	}
+
+	slices.Sort(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,119 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// kinds of session activity counted for analytics
+type activityKind int
+
+const (
+	activityCodeEdit activityKind = iota
+	activityAgentRequest
+	activityChatMessage
+)
+
+// sets the store session activity is sampled into once a minute
+func (h *Hub) SetActivityRecorder(recorder ActivityRecorder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.activityRecorder = recorder
+}
+
+// counts an edit, agent request or chat message towards the session's
+// activity
+func (h *Hub) countActivity(sessionID string, kind activityKind) {
+	h.activityMu.Lock()
+	defer h.activityMu.Unlock()
+
+	activity := h.sessionActivity(sessionID)
+
+	switch kind {
+	case activityCodeEdit:
+		activity.codeEdits++
+	case activityAgentRequest:
+		activity.agentRequests++
+	case activityChatMessage:
+		activity.chatMessages++
+	}
+}
+
+// samples the connections of every session on this instance along with the
+// activity counted since the last sample, and hands them to the recorder
+func (h *Hub) recordActivity() {
+	h.mu.RLock()
+	recorder := h.activityRecorder
+	now := time.Now()
+
+	h.transportMu.Lock()
+	for sessionID, transport := range h.transports {
+		h.countPlayback(sessionID, transport, now)
+	}
+	h.transportMu.Unlock()
+
+	h.activityMu.Lock()
+	counted := h.activity
+	h.activity = make(map[string]*sessionActivity)
+	h.activityMu.Unlock()
+
+	// without a recorder the counts are only dropped, so they don't pile up
+	if recorder == nil {
+		h.mu.RUnlock()
+		return
+	}
+
+	// the sample covers the interval that just ended
+	bucket := now.Add(-activityInterval).Truncate(time.Minute)
+	samples := make(map[string]*sessions.ActivitySample)
+
+	sample := func(sessionID string) *sessions.ActivitySample {
+		s, exists := samples[sessionID]
+		if !exists {
+			s = &sessions.ActivitySample{SessionID: sessionID, BucketStart: bucket}
+			samples[sessionID] = s
+		}
+		return s
+	}
+
+	for sessionID, clients := range h.sessions {
+		sample(sessionID).Participants = len(clients)
+	}
+
+	for sessionID, audience := range h.audiences {
+		sample(sessionID).Listeners = len(audience)
+	}
+	h.mu.RUnlock()
+
+	for sessionID, activity := range counted {
+		s := sample(sessionID)
+		s.CodeEdits = activity.codeEdits
+		s.AgentRequests = activity.agentRequests
+		s.ChatMessages = activity.chatMessages
+		s.PlaybackSeconds = activity.playback.Seconds()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), activityRecordTimeout)
+	defer cancel()
+
+	for _, s := range samples {
+		if err := recorder.RecordActivity(ctx, s); err != nil {
+			logger.ErrorErr(err, "failed to record session activity", "session_id", s.SessionID)
+		}
+	}
+}
+
+// returns the counted activity of a session, creating it if needed (must be
+// called with activityMu held)
+func (h *Hub) sessionActivity(sessionID string) *sessionActivity {
+	activity, exists := h.activity[sessionID]
+	if !exists {
+		activity = &sessionActivity{}
+		h.activity[sessionID] = activity
+	}
+
+	return activity
+}
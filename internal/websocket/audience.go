@@ -0,0 +1,298 @@
+package websocket
+
+import (
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+// message types forwarded to audience clients
+var audienceMessageTypes = map[string]bool{
+	TypeCodeUpdate:    true,
+	TypePlay:          true,
+	TypeStop:          true,
+	TypeTransportSync: true,
+}
+
+// message types spectators may send
+var spectatorMessageTypes = map[string]bool{
+	TypeChatMessage:  true,
+	TypeChatReply:    true,
+	TypeChatReaction: true,
+}
+
+// message types forwarded to spectators on top of the audience ones
+var spectatorForwardedTypes = map[string]bool{
+	TypeChatMessage:  true,
+	TypeChatReply:    true,
+	TypeChatReaction: true,
+	TypeChatSettings: true,
+}
+
+// checks if a new audience connection should be allowed. audiences have their
+// own limits, so a venue sharing one IP address can fill a performance.
+func (h *Hub) CanAcceptAudience(sessionID, ipAddress string) (bool, string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.audiences[sessionID]) >= maxAudiencePerSession {
+		return false, "Session audience is full"
+	}
+
+	if h.audienceIPConnections[ipAddress] >= maxAudienceConnectionsPerIP {
+		return false, "Maximum audience connections per IP address exceeded"
+	}
+
+	return true, ""
+}
+
+// returns the number of audience clients of a session on this instance
+func (h *Hub) ListenerCount(sessionID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.audiences[sessionID])
+}
+
+// adds an audience client. audiences are kept apart from participants, so
+// they don't appear in participant lists or presence and aren't announced
+// with user_joined (must be called with lock held)
+func (h *Hub) registerAudience(client *Client) {
+	if h.audiences[client.SessionID] == nil {
+		h.audiences[client.SessionID] = make(map[string]*Client)
+	}
+
+	h.audiences[client.SessionID][client.ID] = client
+	h.audienceIPConnections[client.IPAddress]++
+	metrics.WebSocketListeners.Set(float64(len(h.audiences[client.SessionID])), client.SessionID)
+	h.seedChat(client)
+
+	logger.Debug("audience client registered",
+		"client_id", client.ID,
+		"session_id", client.SessionID,
+	)
+
+	if err := h.sendAudienceState(client); err != nil {
+		logger.ErrorErr(err, "failed to send session state",
+			"client_id", client.ID,
+			"session_id", client.SessionID,
+		)
+	}
+}
+
+// removes an audience client (must be called with lock held)
+func (h *Hub) unregisterAudience(client *Client) {
+	audience, exists := h.audiences[client.SessionID]
+	if !exists {
+		return
+	}
+
+	if _, exists := audience[client.ID]; !exists {
+		return
+	}
+
+	delete(audience, client.ID)
+	client.Close()
+
+	h.audienceIPConnections[client.IPAddress]--
+	if h.audienceIPConnections[client.IPAddress] <= 0 {
+		delete(h.audienceIPConnections, client.IPAddress)
+	}
+
+	if len(audience) == 0 {
+		delete(h.audiences, client.SessionID)
+		metrics.WebSocketListeners.Delete(client.SessionID)
+		h.removeChatIfEmpty(client.SessionID)
+		h.removeTransportIfEmpty(client.SessionID)
+	} else {
+		metrics.WebSocketListeners.Set(float64(len(audience)), client.SessionID)
+	}
+}
+
+// sends session_state to an audience client: the code and performers, and
+// the chat for spectators. embeds don't get the performers either (must be
+// called with lock held)
+func (h *Hub) sendAudienceState(client *Client) error {
+	participants := make([]SessionStateParticipant, 0, len(h.sessions[client.SessionID]))
+
+	for _, c := range h.sessions[client.SessionID] {
+		if client.IsEmbed() {
+			break
+		}
+
+		participants = append(participants, SessionStateParticipant{
+			UserID:      c.UserID,
+			DisplayName: c.DisplayName,
+			Role:        c.Role,
+		})
+	}
+
+	// without performers connected there is no live document; an audience
+	// doesn't create one, since it would never be removed
+	code, revision := client.InitialCode, 0
+	if sd, exists := h.documents[client.SessionID]; exists {
+		code, revision = sd.doc.Text(), sd.doc.Revision()
+	}
+
+	chatHistory := []SessionStateChatMessage{}
+	if client.IsSpectator() && client.InitialChatHistory != nil {
+		chatHistory = client.InitialChatHistory
+	}
+
+	msg, err := NewMessage(TypeSessionState, client.SessionID, "", SessionStatePayload{
+		Code:            code,
+		Revision:        revision,
+		ClientID:        client.ID,
+		YourRole:        client.Role,
+		YourDisplayName: client.DisplayName,
+		YourPermissions: client.Permissions().Names(),
+		Participants:    participants,
+		ChatHistory:     chatHistory,
+		ListenerCount:   len(h.audiences[client.SessionID]),
+		RegionLocks:     []RegionLock{},
+		ChatSlowMode:    h.ChatSlowMode(client.SessionID),
+		Muted:           client.IsMuted(),
+		Transport:       h.transportState(client.SessionID),
+	})
+	if err != nil {
+		return err
+	}
+
+	return client.Send(msg)
+}
+
+// forwards a broadcast to the session's audience. code_op becomes a
+// code_update with the whole document, since audiences don't merge
+// operations. the message is encoded once per wire format rather than once
+// per client (must be called with lock held)
+func (h *Hub) broadcastToAudience(sessionID string, msg *Message) {
+	audience := h.audiences[sessionID]
+	if len(audience) == 0 {
+		return
+	}
+
+	// chat only goes to spectators
+	spectatorsOnly := false
+
+	if msg.Type == TypeCodeOperation {
+		msg = h.audienceCodeUpdate(sessionID, msg)
+		if msg == nil {
+			return
+		}
+	} else if spectatorForwardedTypes[msg.Type] {
+		spectatorsOnly = true
+	} else if !audienceMessageTypes[msg.Type] {
+		return
+	}
+
+	// audiences don't resume, so they don't get sequence numbers
+	audienceMsg := *msg
+	audienceMsg.Sequence = 0
+
+	var encoded [2][]byte // JSON, msgpack
+
+	for clientID, client := range audience {
+		if spectatorsOnly && !client.IsSpectator() {
+			continue
+		}
+
+		format := 0
+		if client.binary {
+			format = 1
+		}
+
+		if encoded[format] == nil {
+			data, err := encodeMessage(&audienceMsg, client.binary)
+			if err != nil {
+				logger.ErrorErr(err, "failed to encode audience message",
+					"session_id", sessionID,
+					"message_type", msg.Type,
+				)
+				return
+			}
+
+			encoded[format] = data
+		}
+
+		send := client.sendBytes
+		if h.coalesces(client, audienceMsg.Type) {
+			send = func(data []byte) error { return client.sendCoalesced(data, h.coalesceWindow) }
+		}
+
+		if err := send(encoded[format]); err != nil {
+			logger.Debug("failed to send message to audience client",
+				"client_id", clientID,
+				"session_id", sessionID,
+				"error", err,
+			)
+		}
+	}
+}
+
+// builds the code_update an audience receives for a code_op (must be called
+// with lock held)
+func (h *Hub) audienceCodeUpdate(sessionID string, msg *Message) *Message {
+	sd, exists := h.documents[sessionID]
+	if !exists {
+		return nil
+	}
+
+	var op CodeOperationPayload
+	if err := msg.UnmarshalPayload(&op); err != nil {
+		return nil
+	}
+
+	update, err := NewMessage(TypeCodeUpdate, sessionID, msg.UserID, CodeUpdatePayload{
+		Code:        sd.doc.Text(),
+		DisplayName: op.DisplayName,
+		UserID:      op.UserID,
+		Role:        op.Role,
+		Source:      "typed",
+		Revision:    op.Revision,
+	})
+	if err != nil {
+		return nil
+	}
+
+	return update
+}
+
+// sends listener_count to the participants of sessions whose audience size
+// changed since the last update. unsequenced, like presence, since only the
+// latest count matters
+func (h *Hub) broadcastListenerCounts() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sessionID, participants := range h.sessions {
+		count := len(h.audiences[sessionID])
+		if count == h.listenerCounts[sessionID] {
+			continue
+		}
+
+		msg, err := NewMessage(TypeListenerCount, sessionID, "", ListenerCountPayload{Count: count})
+		if err != nil {
+			continue
+		}
+
+		for _, client := range participants {
+			if client.awaitingResume {
+				continue
+			}
+
+			client.Send(msg) //nolint:errcheck,gosec // best-effort count update
+		}
+
+		if count == 0 {
+			delete(h.listenerCounts, sessionID)
+		} else {
+			h.listenerCounts[sessionID] = count
+		}
+	}
+
+	// forget counts of sessions without participants left to tell
+	for sessionID := range h.listenerCounts {
+		if _, exists := h.sessions[sessionID]; !exists {
+			delete(h.listenerCounts, sessionID)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package websocket
+
+import (
+	"time"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// sets the chat slow mode of a session and tells its participants and
+// spectators with chat_settings. 0 turns slow mode off
+func (h *Hub) SetChatSlowMode(sessionID string, seconds int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// clients connecting later bring the stored setting
+	if _, exists := h.sessions[sessionID]; !exists && len(h.audiences[sessionID]) == 0 {
+		return
+	}
+
+	h.chatMu.Lock()
+	h.sessionChat(sessionID).slowMode = time.Duration(seconds) * time.Second
+	h.chatMu.Unlock()
+
+	msg, err := NewMessage(TypeChatSettings, sessionID, "", ChatSettingsPayload{SlowModeSeconds: seconds})
+	if err != nil {
+		logger.ErrorErr(err, "failed to create chat settings message", "session_id", sessionID)
+		return
+	}
+
+	h.broadcastToSession(sessionID, msg, "")
+}
+
+// returns the chat slow mode of a session in seconds
+func (h *Hub) ChatSlowMode(sessionID string) int {
+	h.chatMu.Lock()
+	defer h.chatMu.Unlock()
+
+	return h.chatSlowMode(sessionID)
+}
+
+// mutes or unmutes the connections of a participant on this instance and
+// tells them with chat_muted. returns the number of connections updated.
+func (h *Hub) SetParticipantMuted(sessionID, participantID string, muted bool) int {
+	if participantID == "" {
+		return 0
+	}
+
+	h.mu.RLock()
+	var clients []*Client
+	for _, client := range h.sessions[sessionID] {
+		if client.ParticipantID == participantID {
+			clients = append(clients, client)
+		}
+	}
+	for _, client := range h.audiences[sessionID] {
+		if client.ParticipantID == participantID {
+			clients = append(clients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	msg, err := NewMessage(TypeChatMuted, sessionID, "", ChatMutedPayload{Muted: muted})
+
+	for _, client := range clients {
+		client.SetMuted(muted)
+
+		if err == nil {
+			client.Send(msg) //nolint:errcheck,gosec // best-effort, enforcement doesn't depend on it
+		}
+	}
+
+	return len(clients)
+}
+
+// sets the signal chat messages are checked against, so a backed-up buffer
+// turns chat away instead of growing further
+func (h *Hub) SetChatBackpressure(signal ChatBackpressure) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.chatBackpressure = signal
+}
+
+// reports whether chat in the session should wait for the buffer to catch up
+func (h *Hub) chatBackedUp(sessionID string) bool {
+	h.mu.RLock()
+	signal := h.chatBackpressure
+	h.mu.RUnlock()
+
+	return signal != nil && signal.Backpressure(sessionID)
+}
+
+// records a chat message of the client against the session's slow mode.
+// returns how long the client has to wait instead if it chatted too recently.
+// hosts and participants who manage others aren't slowed down
+func (h *Hub) reserveChatMessage(client *Client) time.Duration {
+	if client.Role == "host" || client.Can(sessions.PermManageParticipants) {
+		return 0
+	}
+
+	h.chatMu.Lock()
+	defer h.chatMu.Unlock()
+
+	chat, exists := h.chats[client.SessionID]
+	if !exists || chat.slowMode <= 0 {
+		return 0
+	}
+
+	// anonymous clients are limited per participant, so reconnecting doesn't help
+	key := client.UserID
+	if key == "" {
+		key = client.ParticipantID
+	}
+	if key == "" {
+		key = client.ID
+	}
+
+	now := time.Now()
+	if last, ok := chat.lastMessage[key]; ok {
+		if wait := chat.slowMode - now.Sub(last); wait > 0 {
+			return wait
+		}
+	}
+
+	chat.lastMessage[key] = now
+
+	return 0
+}
+
+// takes the session's slow mode from a connecting client that can chat, which
+// also picks up changes made through other instances (must be called with
+// lock held)
+func (h *Hub) seedChat(client *Client) {
+	if client.IsAudience() && !client.IsSpectator() {
+		return
+	}
+
+	h.chatMu.Lock()
+	h.sessionChat(client.SessionID).slowMode = time.Duration(client.ChatSlowMode) * time.Second
+	h.chatMu.Unlock()
+}
+
+// forgets the chat state of a session once nobody is connected to it anymore
+// (must be called with lock held)
+func (h *Hub) removeChatIfEmpty(sessionID string) {
+	if _, exists := h.sessions[sessionID]; exists || len(h.audiences[sessionID]) > 0 {
+		return
+	}
+
+	h.chatMu.Lock()
+	delete(h.chats, sessionID)
+	h.chatMu.Unlock()
+}
+
+// returns the chat state of a session, creating it if needed (must be called
+// with chatMu held)
+func (h *Hub) sessionChat(sessionID string) *sessionChat {
+	chat, exists := h.chats[sessionID]
+	if !exists {
+		chat = &sessionChat{lastMessage: make(map[string]time.Time)}
+		h.chats[sessionID] = chat
+	}
+
+	return chat
+}
+
+// must be called with chatMu held
+func (h *Hub) chatSlowMode(sessionID string) int {
+	chat, exists := h.chats[sessionID]
+	if !exists {
+		return 0
+	}
+
+	return int(chat.slowMode / time.Second)
+}
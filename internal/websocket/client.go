@@ -1,9 +1,10 @@
 package websocket
 
 import (
-	"encoding/json"
+	"compress/flate"
 	"time"
 
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
 	"codeberg.org/algopatterns/server/internal/errors"
 	"codeberg.org/algopatterns/server/internal/logger"
 	"github.com/gorilla/websocket"
@@ -25,6 +26,7 @@ func NewClient(id, sessionID, userID, displayName, role, ipAddress, initialCode
 		hub:                   hub,
 		send:                  make(chan []byte, 256),
 		closed:                false,
+		binary:                conn.Subprotocol() == SubprotocolMsgpack,
 		codeUpdateTimestamps:  make([]time.Time, 0, maxCodeUpdatesPerSecond),
 		chatMessageTimestamps: make([]time.Time, 0, maxChatMessagesPerMinute),
 	}
@@ -41,11 +43,12 @@ func (c *Client) ReadPump() {
 	c.conn.SetReadDeadline(time.Now().Add(pongWait)) //nolint:errcheck,gosec // G104: websocket setup
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(pongWait)) //nolint:errcheck,gosec // G104: pong handler
+		c.pongReceived()
 		return nil
 	})
 
 	for {
-		_, messageBytes, err := c.conn.ReadMessage()
+		frameType, messageBytes, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				logger.Warn("websocket error",
@@ -58,15 +61,15 @@ func (c *Client) ReadPump() {
 			break
 		}
 
-		// parse the message
-		var msg Message
-		if err := json.Unmarshal(messageBytes, &msg); err != nil {
+		// parse the message (binary frames carry msgpack, text frames JSON)
+		msg, err := decodeMessage(messageBytes, frameType == websocket.BinaryMessage)
+		if err != nil {
 			logger.ErrorErr(err, "failed to unmarshal message",
 				"client_id", c.ID,
 				"session_id", c.SessionID,
 			)
 
-			c.SendError("bad_request", "invalid message format", err.Error())
+			c.SendError(errors.CodeBadRequest, "invalid message format", err.Error())
 			continue
 		}
 
@@ -77,7 +80,7 @@ func (c *Client) ReadPump() {
 		msg.Timestamp = time.Now()
 
 		// forward to hub for processing
-		c.hub.Broadcast <- &msg
+		c.hub.Broadcast <- msg
 	}
 }
 
@@ -85,6 +88,14 @@ func (c *Client) ReadPump() {
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
 
+	// only applies when permessage-deflate was negotiated
+	c.conn.SetCompressionLevel(flate.BestSpeed) //nolint:errcheck,gosec // level is valid
+
+	frameType := websocket.TextMessage
+	if c.binary {
+		frameType = websocket.BinaryMessage
+	}
+
 	defer func() {
 		ticker.Stop()
 		c.conn.Close() //nolint:errcheck,gosec // G104: defer cleanup
@@ -101,19 +112,25 @@ func (c *Client) WritePump() {
 				return
 			}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
+			// small frames are cheaper to send as-is than to deflate
+			c.conn.EnableWriteCompression(len(message) >= compressionThreshold)
+
+			w, err := c.conn.NextWriter(frameType)
 			if err != nil {
 				return
 			}
 
 			w.Write(message) //nolint:errcheck,gosec // G104: websocket write
 
-			// add queued messages to the current websocket message
+			// add queued messages to the current websocket message. msgpack
+			// values are self-delimiting, JSON messages are newline separated
 			n := len(c.send)
 
 			for range n {
-				w.Write([]byte{'\n'}) //nolint:errcheck,gosec // G104: websocket write
-				w.Write(<-c.send)     //nolint:errcheck,gosec // G104: websocket write
+				if !c.binary {
+					w.Write([]byte{'\n'}) //nolint:errcheck,gosec // G104: websocket write
+				}
+				w.Write(<-c.send) //nolint:errcheck,gosec // G104: websocket write
 			}
 
 			if err := w.Close(); err != nil {
@@ -121,6 +138,11 @@ func (c *Client) WritePump() {
 			}
 
 		case <-ticker.C:
+			// closing the connection ends the read pump, which unregisters the client
+			if !c.pingDue() {
+				return
+			}
+
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait)) //nolint:errcheck,gosec // G104: websocket ping timing
 
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
@@ -131,14 +153,7 @@ func (c *Client) WritePump() {
 }
 
 // sends a message to the client
-func (c *Client) Send(msg *Message) (err error) {
-	// recover from panic if channel is closed
-	defer func() {
-		if r := recover(); r != nil {
-			err = ErrConnectionClosed
-		}
-	}()
-
+func (c *Client) Send(msg *Message) error {
 	c.mu.RLock()
 
 	if c.closed {
@@ -148,11 +163,51 @@ func (c *Client) Send(msg *Message) (err error) {
 
 	c.mu.RUnlock()
 
-	messageBytes, marshalErr := json.Marshal(msg)
+	messageBytes, marshalErr := encodeMessage(msg, c.binary)
 	if marshalErr != nil {
 		return marshalErr
 	}
 
+	return c.sendBytes(messageBytes)
+}
+
+// queues a JSON encoded message, re-encoding it for binary clients
+func (c *Client) sendJSON(messageBytes []byte) error {
+	if !c.binary {
+		return c.sendBytes(messageBytes)
+	}
+
+	binaryBytes, err := transcodeMessage(messageBytes)
+	if err != nil {
+		return err
+	}
+
+	return c.sendBytes(binaryBytes)
+}
+
+// queues an already encoded message for the client. a held-back
+// code_update goes first, so the client sees messages in order
+func (c *Client) sendBytes(messageBytes []byte) error {
+	c.coalesce.mu.Lock()
+	defer c.coalesce.mu.Unlock()
+
+	if err := c.queuePending(); err != nil {
+		return err
+	}
+
+	return c.queue(messageBytes)
+}
+
+// puts an encoded message on the send channel, closing the client when it's
+// full
+func (c *Client) queue(messageBytes []byte) (err error) {
+	// recover from panic if channel is closed
+	defer func() {
+		if r := recover(); r != nil {
+			err = ErrConnectionClosed
+		}
+	}()
+
 	select {
 	case c.send <- messageBytes:
 		return nil
@@ -175,45 +230,58 @@ func (c *Client) sendBufferOverflowError() {
 		return
 	}
 
-	errorBytes, err := json.Marshal(errorMsg)
+	errorBytes, err := encodeMessage(errorMsg, c.binary)
 	if err != nil {
 		return
 	}
 
+	frameType := websocket.TextMessage
+	if c.binary {
+		frameType = websocket.BinaryMessage
+	}
+
 	// write directly to websocket with short deadline
 	c.conn.SetWriteDeadline(time.Now().Add(2 * time.Second)) //nolint:errcheck,gosec
-	c.conn.WriteMessage(websocket.TextMessage, errorBytes)   //nolint:errcheck,gosec
+	c.conn.WriteMessage(frameType, errorBytes)               //nolint:errcheck,gosec
 }
 
 // sends an error message to the client
-func (c *Client) SendError(code, message, details string) {
-	c.SendErrorWithRequestID(code, message, details, nil)
+func (c *Client) SendError(code errors.Code, message, details string) {
+	c.sendError(newErrorPayload(code, message, details, nil))
 }
 
 // sends an error message to the client with request_id for correlation
-func (c *Client) SendErrorWithRequestID(code, message, details string, requestID *string) {
-	// sanitize error details in production
-	sanitizedDetails := details
+func (c *Client) SendErrorWithRequestID(code errors.Code, message, details string, requestID *string) {
+	c.sendError(newErrorPayload(code, message, details, requestID))
+}
+
+// sends a retryable error whose wait is known to the caller, such as the time
+// left in a slow mode window
+func (c *Client) SendErrorRetryAfter(code errors.Code, message string, after time.Duration) {
+	payload := newErrorPayload(code, message, "", nil)
+	payload.Retryable = true
+	payload.RetryAfterMs = after.Milliseconds()
 
-	if details != "" {
-		sanitizedDetails = sanitizeErrorString(details)
+	c.sendError(payload)
+}
+
+func (c *Client) sendError(payload ErrorPayload) {
+	// sanitize error details in production
+	if payload.Details != "" {
+		payload.Details = sanitizeErrorString(payload.Details)
 	}
 
-	errorMsg, err := NewMessage(TypeError, c.SessionID, c.UserID, errors.ErrorResponse{
-		Error:     code,
-		Message:   message,
-		Details:   sanitizedDetails,
-		RequestID: requestID,
-	})
+	errorMsg, err := NewMessage(TypeError, c.SessionID, c.UserID, payload)
 	if err != nil {
 		logger.ErrorErr(err, "failed to create error message",
 			"client_id", c.ID,
 			"session_id", c.SessionID,
-			"error_code", code,
+			"error_code", payload.Error,
 		)
 		return
 	}
 
+	c.errorsSent.Add(1)
 	c.Send(errorMsg) //nolint:errcheck,gosec // G104: best effort error notification
 }
 
@@ -225,6 +293,13 @@ func (c *Client) Close() {
 	if !c.closed {
 		c.closed = true
 		close(c.send)
+
+		// drop held-back presence updates
+		for _, throttle := range c.presence {
+			if throttle.timer != nil {
+				throttle.timer.Stop()
+			}
+		}
 	}
 }
 
@@ -236,9 +311,50 @@ func (c *Client) IsClosed() bool {
 	return c.closed
 }
 
+// returns what the client may do in the session
+func (c *Client) Permissions() sessions.Permission {
+	return sessions.EffectivePermissions(c.Role, c.permissions.Load())
+}
+
+// replaces the permissions of the client, nil goes back to the role preset
+func (c *Client) SetPermissions(permissions *sessions.Permission) {
+	c.permissions.Store(permissions)
+}
+
+// checks if the client has the given permission
+func (c *Client) Can(permission sessions.Permission) bool {
+	return c.Permissions().Has(permission)
+}
+
 // checks if the client has write permissions
 func (c *Client) CanWrite() bool {
-	return c.Role == "host" || c.Role == "co-author"
+	return c.Can(sessions.PermEditCode)
+}
+
+// checks if the client is an audience listener. embeds are audience clients
+// that also don't see who is performing, spectators are ones that can chat
+func (c *Client) IsAudience() bool {
+	return c.Role == "audience" || c.IsEmbed() || c.IsSpectator()
+}
+
+// checks if the client is a signed-in audience member who can chat
+func (c *Client) IsSpectator() bool {
+	return c.Role == "spectator"
+}
+
+// checks if the host has muted the client in chat
+func (c *Client) IsMuted() bool {
+	return c.muted.Load()
+}
+
+// mutes or unmutes the client in chat
+func (c *Client) SetMuted(muted bool) {
+	c.muted.Store(muted)
+}
+
+// checks if the client is a read-only embed connected with an embed token
+func (c *Client) IsEmbed() bool {
+	return c.Role == "embed"
 }
 
 // checks if the client can send a code update
@@ -261,7 +377,7 @@ func (c *Client) checkCodeUpdateRateLimit() bool {
 	c.codeUpdateTimestamps = validTimestamps
 
 	// check if we've exceeded the limit
-	if len(c.codeUpdateTimestamps) >= maxCodeUpdatesPerSecond {
+	if len(c.codeUpdateTimestamps) >= c.codeUpdateRate() {
 		return false
 	}
 
@@ -297,3 +413,104 @@ func (c *Client) checkChatRateLimit() bool {
 	c.chatMessageTimestamps = append(c.chatMessageTimestamps, now)
 	return true
 }
+
+// records the diagnostics signature of a code_validation about to be sent.
+// returns false when the client already received the same result.
+func (c *Client) updateValidation(signature string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lastValidation == signature {
+		return false
+	}
+
+	c.lastValidation = signature
+	return true
+}
+
+// broadcasts a presence update to the rest of the session, at most once per
+// presenceThrottleInterval for each message type. an update that arrives
+// sooner replaces any held-back one and is sent when the interval ends, so
+// the latest position is never lost.
+func (c *Client) throttlePresence(msg *Message) {
+	c.mu.Lock()
+
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+
+	if c.presence == nil {
+		c.presence = make(map[string]*presenceThrottle)
+	}
+
+	throttle, exists := c.presence[msg.Type]
+	if !exists {
+		throttle = &presenceThrottle{}
+		c.presence[msg.Type] = throttle
+	}
+
+	// a trailing broadcast is already scheduled, just update what it sends
+	if throttle.timer != nil {
+		throttle.pending = msg
+		c.mu.Unlock()
+		return
+	}
+
+	wait := presenceThrottleInterval - time.Since(throttle.lastSent)
+	if wait > 0 {
+		throttle.pending = msg
+		throttle.timer = time.AfterFunc(wait, func() {
+			c.flushPresence(msg.Type)
+		})
+		c.mu.Unlock()
+		return
+	}
+
+	throttle.lastSent = time.Now()
+	c.mu.Unlock()
+
+	// broadcast outside the client lock, the hub locks other clients
+	c.hub.BroadcastPresence(c.SessionID, msg, c.ID)
+}
+
+// sends the held-back presence update of a message type
+func (c *Client) flushPresence(msgType string) {
+	c.mu.Lock()
+
+	throttle := c.presence[msgType]
+	msg := throttle.pending
+	throttle.pending = nil
+	throttle.timer = nil
+	throttle.lastSent = time.Now()
+	closed := c.closed
+
+	c.mu.Unlock()
+
+	if closed || msg == nil {
+		return
+	}
+
+	c.hub.BroadcastPresence(c.SessionID, msg, c.ID)
+}
+
+// marks an agent request as started, returning false if one is already running
+func (c *Client) startAgentRequest() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.agentRequestActive {
+		return false
+	}
+
+	c.agentRequestActive = true
+	return true
+}
+
+// marks the running agent request as finished
+func (c *Client) finishAgentRequest() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.agentRequestActive = false
+}
@@ -1,9 +1,15 @@
 package websocket
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/strudel"
 )
 
 func TestClientPermissions(t *testing.T) {
@@ -44,6 +50,37 @@ func TestClientPermissions(t *testing.T) {
 	}
 }
 
+func TestClientPermissionOverride(t *testing.T) {
+	client := &Client{
+		ID:        "test-client",
+		SessionID: "test-session",
+		Role:      "co-author",
+		send:      make(chan []byte, 256),
+	}
+
+	// co-author preset
+	assert.True(t, client.CanWrite())
+	assert.True(t, client.Can(sessions.PermUseAgent))
+	assert.False(t, client.Can(sessions.PermManageParticipants))
+
+	// edit code, but no AI
+	custom := sessions.PermEditCode | sessions.PermChat | sessions.PermControlPlayback
+	client.SetPermissions(&custom)
+	assert.True(t, client.CanWrite())
+	assert.False(t, client.Can(sessions.PermUseAgent))
+	assert.Equal(t, []string{"edit_code", "chat", "control_playback"}, client.Permissions().Names())
+
+	// back to the preset
+	client.SetPermissions(nil)
+	assert.True(t, client.Can(sessions.PermUseAgent))
+
+	// hosts can't be restricted
+	none := sessions.Permission(0)
+	client.Role = "host"
+	client.SetPermissions(&none)
+	assert.Equal(t, sessions.PermAll, client.Permissions())
+}
+
 func TestClientIsAuthenticated(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -160,3 +197,130 @@ func TestClientSendMessageToClosedChannel(t *testing.T) {
 	// error is expected when sending to closed channel
 	assert.Error(t, err)
 }
+
+func TestSendCodeValidationSkipsUnchangedResults(t *testing.T) {
+	client := &Client{
+		ID:        "test-client",
+		SessionID: "test-session",
+		UserID:    "user-1",
+		Role:      "host",
+		send:      make(chan []byte, 256),
+	}
+
+	broken := strudel.Lint(`s("bd sd"`)
+	sendCodeValidation(client, broken, 1)
+	sendCodeValidation(client, broken, 2)
+	sendCodeValidation(client, strudel.Lint(`s("bd sd")`), 3)
+
+	require.Len(t, client.send, 2)
+
+	var first, second CodeValidationPayload
+	require.NoError(t, json.Unmarshal(payloadOf(t, <-client.send), &first))
+	require.NoError(t, json.Unmarshal(payloadOf(t, <-client.send), &second))
+
+	assert.False(t, first.Valid)
+	assert.Equal(t, 1, first.Revision)
+	assert.Len(t, first.Diagnostics, 1)
+
+	assert.True(t, second.Valid)
+	assert.Equal(t, 3, second.Revision)
+	assert.Empty(t, second.Diagnostics)
+}
+
+func payloadOf(t *testing.T, data []byte) json.RawMessage {
+	t.Helper()
+
+	var msg Message
+	require.NoError(t, json.Unmarshal(data, &msg))
+	assert.Equal(t, TypeCodeValidation, msg.Type)
+
+	return msg.Payload
+}
+
+func TestClientConnectionQuality(t *testing.T) {
+	hub := NewHub()
+
+	client := &Client{ID: "client", SessionID: "session-1", UserID: "user-1", DisplayName: "Slow", Role: "co-author", hub: hub, send: make(chan []byte, 256)}
+	other := &Client{ID: "other", SessionID: "session-1", Role: "host", hub: hub, send: make(chan []byte, 256)}
+	hub.sessions["session-1"] = map[string]*Client{client.ID: client, other.ID: other}
+
+	nextQuality := func(c *Client) *ConnectionQualityPayload {
+		t.Helper()
+
+		select {
+		case data := <-c.send:
+			var msg Message
+			require.NoError(t, json.Unmarshal(data, &msg))
+			require.Equal(t, TypeConnectionQuality, msg.Type)
+
+			var payload ConnectionQualityPayload
+			require.NoError(t, msg.UnmarshalPayload(&payload))
+			return &payload
+		default:
+			return nil
+		}
+	}
+
+	answer := func(rtt time.Duration) {
+		require.True(t, client.pingDue())
+		client.pings.sentAt = time.Now().Add(-rtt)
+		client.pongReceived()
+	}
+
+	// a good first round trip isn't announced
+	answer(20 * time.Millisecond)
+	assert.Nil(t, nextQuality(other))
+	assert.Equal(t, "good", client.participant().Quality)
+	assert.Equal(t, int64(20), client.participant().RTTMs)
+
+	// a slow one is, to everyone in the session
+	answer(500 * time.Millisecond)
+	if quality := nextQuality(other); assert.NotNil(t, quality) {
+		assert.Equal(t, "poor", quality.Quality)
+		assert.Equal(t, "user-1", quality.UserID)
+		assert.GreaterOrEqual(t, quality.RTTMs, int64(500))
+	}
+	assert.NotNil(t, nextQuality(client))
+
+	answer(200 * time.Millisecond)
+	assert.Equal(t, "fair", nextQuality(other).Quality)
+	nextQuality(client)
+
+	// missed pings make it poor, and enough of them drop the client
+	require.True(t, client.pingDue())
+	require.True(t, client.pingDue())
+	assert.Equal(t, "poor", nextQuality(other).Quality)
+	nextQuality(client)
+
+	require.True(t, client.pingDue())
+	assert.False(t, client.pingDue())
+
+	lost := nextQuality(other)
+	require.NotNil(t, lost)
+	assert.Equal(t, "lost", lost.Quality)
+	assert.Equal(t, maxMissedPings, lost.MissedPings)
+	assert.Nil(t, nextQuality(client), "a lost client isn't told")
+}
+
+func TestClientStalledQueueIsDropped(t *testing.T) {
+	client := &Client{ID: "client", SessionID: "session-1", Role: "audience", send: make(chan []byte, 256)}
+
+	for range stalledQueueDepth {
+		client.send <- []byte("{}")
+	}
+
+	// the client answers pings but never reads its messages
+	for range maxStalledQueueing - 1 {
+		require.True(t, client.pingDue())
+		client.pongReceived()
+	}
+
+	assert.False(t, client.pingDue())
+}
+
+func TestConnectionQualityLevels(t *testing.T) {
+	assert.Equal(t, "good", connectionQuality(40*time.Millisecond, 0))
+	assert.Equal(t, "fair", connectionQuality(goodConnectionRTT+time.Millisecond, 0))
+	assert.Equal(t, "poor", connectionQuality(fairConnectionRTT+time.Millisecond, 0))
+	assert.Equal(t, "poor", connectionQuality(40*time.Millisecond, 1))
+}
@@ -0,0 +1,95 @@
+package websocket
+
+import (
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+// sets how long code_update messages to clients that can't edit are held
+// back, so a burst of updates reaches them as its latest state. writers get
+// every update right away. 0 turns coalescing off
+func (h *Hub) SetCodeUpdateCoalescing(window time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.coalesceWindow = window
+}
+
+// reports whether a message to client may be coalesced with the updates
+// after it (must be called with lock held)
+func (h *Hub) coalesces(client *Client, msgType string) bool {
+	return msgType == TypeCodeUpdate && h.coalesceWindow > 0 && (client.IsAudience() || !client.CanWrite())
+}
+
+// sends a broadcast to one client, holding back code_update when it
+// coalesces (must be called with lock held)
+func (h *Hub) sendTo(client *Client, msg *Message) error {
+	if !h.coalesces(client, msg.Type) {
+		return client.Send(msg)
+	}
+
+	if client.IsClosed() {
+		return ErrConnectionClosed
+	}
+
+	messageBytes, err := encodeMessage(msg, client.binary)
+	if err != nil {
+		return err
+	}
+
+	return client.sendCoalesced(messageBytes, h.coalesceWindow)
+}
+
+// sends an encoded code_update, at most once per window. an update that
+// arrives sooner replaces any held-back one and is sent when the window
+// ends. code_update carries the whole document, so the latest one is all a
+// reader needs.
+func (c *Client) sendCoalesced(messageBytes []byte, window time.Duration) error {
+	co := &c.coalesce
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	// a trailing send is already scheduled, just update what it sends
+	if co.timer != nil {
+		if co.pending != nil {
+			metrics.WebSocketCodeUpdatesCoalesced.Inc()
+		}
+		co.pending = messageBytes
+		return nil
+	}
+
+	wait := window - time.Since(co.lastSent)
+	if wait > 0 {
+		co.pending = messageBytes
+		co.timer = time.AfterFunc(wait, c.flushCoalesced)
+		return nil
+	}
+
+	co.lastSent = time.Now()
+	return c.queue(messageBytes)
+}
+
+// sends the held-back code_update when its window ends
+func (c *Client) flushCoalesced() {
+	co := &c.coalesce
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	co.timer = nil
+	c.queuePending() //nolint:errcheck,gosec // a closed client drops it
+}
+
+// queues the held-back code_update, if any, ahead of whatever is sent next
+// (must hold c.coalesce.mu)
+func (c *Client) queuePending() error {
+	co := &c.coalesce
+	if co.pending == nil {
+		return nil
+	}
+
+	pending := co.pending
+	co.pending = nil
+	co.lastSent = time.Now()
+
+	return c.queue(pending)
+}
@@ -0,0 +1,452 @@
+package websocket
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/ot"
+)
+
+// sets callback to be called with the latest code of documents that changed
+func (h *Hub) OnDocumentSnapshot(callback func(sessionID, code string)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onDocumentSnapshot = callback
+}
+
+// sets callback to be called with the code of edited documents every
+// sessionSnapshotInterval or sessionSnapshotEdits edits, for restorable snapshots
+func (h *Hub) OnSessionSnapshot(callback func(sessionID, code string)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onSessionSnapshot = callback
+}
+
+// sets the checkpoint store used for undo/redo. documents are checkpointed
+// whenever they are snapshotted.
+func (h *Hub) SetCodeHistory(history CodeHistory) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.codeHistory = history
+}
+
+// returns the document for a session (must be called with lock held)
+func (h *Hub) ensureDocument(sessionID, initialCode string) *sessionDocument {
+	sd, exists := h.documents[sessionID]
+	if !exists {
+		sd = &sessionDocument{doc: ot.NewDocument(initialCode), snapshotAt: time.Now()}
+		h.documents[sessionID] = sd
+	}
+
+	return sd
+}
+
+// looks up the shared document for a session
+func (h *Hub) getDocument(sessionID string) (*sessionDocument, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	sd, exists := h.documents[sessionID]
+	if !exists {
+		return nil, ErrDocumentNotFound
+	}
+
+	return sd, nil
+}
+
+// returns the current code and revision of a session document
+func (h *Hub) GetDocument(sessionID string) (string, int, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	sd, exists := h.documents[sessionID]
+	if !exists {
+		return "", 0, false
+	}
+
+	return sd.doc.Text(), sd.doc.Revision(), true
+}
+
+// rebases a client operation onto the latest revision, applies it and
+// broadcasts the result. the sender receives a code_op_ack instead of the
+// broadcast so it can confirm its pending operation.
+func (h *Hub) ApplyCodeOperation(client *Client, revision int, op ot.Operation) (*DocumentChange, error) {
+	sd, err := h.getDocument(client.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	rebased, err := sd.doc.Transform(revision, op)
+	if err != nil {
+		return nil, err
+	}
+
+	previousCode := sd.doc.Text()
+
+	code, err := ot.Apply(previousCode, rebased)
+	if err != nil {
+		return nil, err
+	}
+
+	if len([]byte(code)) > maxCodeSize {
+		return nil, ErrCodeTooLarge
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := checkRegionLocks(sd, client.ID, previousCode, rebased); err != nil {
+		return nil, err
+	}
+
+	newRevision, err := sd.doc.Apply(rebased)
+	if err != nil {
+		return nil, err
+	}
+
+	sd.markEdited()
+	h.countActivity(client.SessionID, activityCodeEdit)
+	locksMoved := shiftRegionLocks(sd, client.ID, previousCode, rebased)
+
+	broadcastMsg, err := NewMessage(TypeCodeOperation, client.SessionID, client.UserID, CodeOperationPayload{
+		Revision:    newRevision,
+		Operation:   rebased,
+		UserID:      client.UserID,
+		DisplayName: client.DisplayName,
+		Role:        client.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h.broadcastToSession(client.SessionID, broadcastMsg, client.ID)
+
+	if locksMoved {
+		h.broadcastRegionLocks(client.SessionID, sd)
+	}
+
+	ackMsg, err := NewMessage(TypeCodeOperationAck, client.SessionID, client.UserID, CodeOperationAckPayload{
+		Revision: newRevision,
+	})
+	if err == nil {
+		client.Send(ackMsg) //nolint:errcheck,gosec // best-effort ack
+	}
+
+	return &DocumentChange{
+		PreviousCode: previousCode,
+		Code:         code,
+		Revision:     newRevision,
+	}, nil
+}
+
+// replaces a session document with full code (e.g., loading a strudel) and
+// broadcasts the code_update to all other clients in the session
+func (h *Hub) ReplaceCode(client *Client, payload CodeUpdatePayload) (*DocumentChange, error) {
+	sd, err := h.getDocument(client.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	previousCode := sd.doc.Text()
+	op := diffOperation(previousCode, payload.Code)
+
+	if err := checkRegionLocks(sd, client.ID, previousCode, op); err != nil {
+		return nil, err
+	}
+
+	_, revision := sd.doc.Replace(payload.Code)
+
+	sd.markEdited()
+	h.countActivity(client.SessionID, activityCodeEdit)
+	locksMoved := shiftRegionLocks(sd, client.ID, previousCode, op)
+	payload.Revision = revision
+
+	broadcastMsg, err := NewMessage(TypeCodeUpdate, client.SessionID, client.UserID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	h.broadcastToSession(client.SessionID, broadcastMsg, client.ID)
+
+	if locksMoved {
+		h.broadcastRegionLocks(client.SessionID, sd)
+	}
+
+	return &DocumentChange{
+		PreviousCode: previousCode,
+		Code:         payload.Code,
+		Revision:     revision,
+	}, nil
+}
+
+// replaces the code of a session document with restored code (e.g., a
+// snapshot restored through the REST API) and broadcasts it to all clients.
+// returns false if no document is open for the session on this instance.
+func (h *Hub) RestoreCode(sessionID, userID, code string) (bool, error) {
+	h.mu.RLock()
+	history := h.codeHistory
+	h.mu.RUnlock()
+
+	sd, err := h.getDocument(sessionID)
+	if err != nil {
+		return false, nil
+	}
+
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	// checkpoint the replaced code so the restore can be undone
+	if history != nil {
+		sd.checkpointed = recordCheckpoint(history, sessionID, sd.doc.Text())
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// a restore overrides locks, they only move with the lines they cover
+	previousCode := sd.doc.Text()
+	_, revision := sd.doc.Replace(code)
+	sd.markEdited()
+	h.countActivity(sessionID, activityCodeEdit)
+	locksMoved := shiftRegionLocks(sd, "", previousCode, diffOperation(previousCode, code))
+
+	broadcastMsg, err := NewMessage(TypeCodeUpdate, sessionID, userID, CodeUpdatePayload{
+		Code:     code,
+		UserID:   userID,
+		Role:     "host",
+		Source:   "restore",
+		Revision: revision,
+	})
+	if err != nil {
+		return true, err
+	}
+
+	h.broadcastToSession(sessionID, broadcastMsg, "")
+
+	if locksMoved {
+		h.broadcastRegionLocks(sessionID, sd)
+	}
+
+	return true, nil
+}
+
+// reverts the session code to the previous checkpoint and broadcasts it to
+// all clients. revision is the latest revision the client has seen, so an
+// undo never discards an edit the client did not know about.
+func (h *Hub) UndoCode(client *Client, revision int) (*DocumentChange, error) {
+	return h.restoreCheckpoint(client, revision, "undo")
+}
+
+// re-applies the most recently undone checkpoint and broadcasts it to all clients
+func (h *Hub) RedoCode(client *Client, revision int) (*DocumentChange, error) {
+	return h.restoreCheckpoint(client, revision, "redo")
+}
+
+func (h *Hub) restoreCheckpoint(client *Client, revision int, source string) (*DocumentChange, error) {
+	h.mu.RLock()
+	history := h.codeHistory
+	h.mu.RUnlock()
+
+	if history == nil {
+		return nil, ErrHistoryUnavailable
+	}
+
+	sd, err := h.getDocument(client.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// holding the document lock keeps other writers and snapshots out until
+	// the restored code is in place
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	if revision != sd.doc.Revision() {
+		return nil, ErrRevisionConflict
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), codeHistoryTimeout)
+	defer cancel()
+
+	previousCode := sd.doc.Text()
+
+	// checkpoint edits made since the last snapshot so undo returns to the
+	// snapshot before them and redo can bring them back
+	if err := history.RecordCodeRevision(ctx, client.SessionID, previousCode); err != nil {
+		return nil, err
+	}
+
+	sd.checkpointed = true
+
+	var code string
+	var ok bool
+
+	if source == "undo" {
+		code, ok, err = history.UndoCodeRevision(ctx, client.SessionID)
+	} else {
+		code, ok, err = history.RedoCodeRevision(ctx, client.SessionID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		if source == "undo" {
+			return nil, ErrNothingToUndo
+		}
+		return nil, ErrNothingToRedo
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	op := diffOperation(previousCode, code)
+
+	if err := checkRegionLocks(sd, client.ID, previousCode, op); err != nil {
+		// step the history back so the rejected checkpoint stays available
+		if source == "undo" {
+			_, _, _ = history.RedoCodeRevision(ctx, client.SessionID)
+		} else {
+			_, _, _ = history.UndoCodeRevision(ctx, client.SessionID)
+		}
+		return nil, err
+	}
+
+	_, newRevision := sd.doc.Replace(code)
+	sd.markEdited()
+	h.countActivity(client.SessionID, activityCodeEdit)
+	locksMoved := shiftRegionLocks(sd, client.ID, previousCode, op)
+
+	broadcastMsg, err := NewMessage(TypeCodeUpdate, client.SessionID, client.UserID, CodeUpdatePayload{
+		Code:        code,
+		DisplayName: client.DisplayName,
+		UserID:      client.UserID,
+		Role:        client.Role,
+		Source:      source,
+		Revision:    newRevision,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the requesting client has no local copy of the restored code, so it
+	// receives the broadcast as well
+	h.broadcastToSession(client.SessionID, broadcastMsg, "")
+
+	if locksMoved {
+		h.broadcastRegionLocks(client.SessionID, sd)
+	}
+
+	return &DocumentChange{
+		PreviousCode: previousCode,
+		Code:         code,
+		Revision:     newRevision,
+	}, nil
+}
+
+// hands every dirty document to the snapshot callback and checkpoints it
+// in the code history
+func (h *Hub) snapshotDocuments() {
+	h.mu.RLock()
+	callback := h.onDocumentSnapshot
+	sessionCallback := h.onSessionSnapshot
+	history := h.codeHistory
+	documents := maps.Clone(h.documents)
+	h.mu.RUnlock()
+
+	snapshots := make(map[string]string)
+	sessionSnapshots := make(map[string]string)
+	now := time.Now()
+
+	for sessionID, sd := range documents {
+		// the document lock orders checkpoints with undo/redo
+		sd.mu.Lock()
+
+		h.mu.Lock()
+		dirty := sd.dirty
+		code := sd.doc.Text()
+		sd.dirty = false
+
+		if sd.edits >= sessionSnapshotEdits || (sd.edits > 0 && now.Sub(sd.snapshotAt) >= sessionSnapshotInterval) {
+			sessionSnapshots[sessionID] = code
+			sd.edits = 0
+			sd.snapshotAt = now
+		}
+		h.mu.Unlock()
+
+		if dirty {
+			snapshots[sessionID] = code
+		}
+
+		// a fresh document is checkpointed once so the first edit can be undone
+		if history != nil && (dirty || !sd.checkpointed) {
+			sd.checkpointed = recordCheckpoint(history, sessionID, code)
+		}
+
+		sd.mu.Unlock()
+	}
+
+	if sessionCallback != nil {
+		for sessionID, code := range sessionSnapshots {
+			sessionCallback(sessionID, code)
+		}
+	}
+
+	if callback == nil {
+		return
+	}
+
+	for sessionID, code := range snapshots {
+		callback(sessionID, code)
+	}
+
+	if len(snapshots) > 0 {
+		logger.Debug("session documents snapshotted", "count", len(snapshots))
+	}
+}
+
+// stores a snapshot as an undo checkpoint (must be called with document lock held)
+func recordCheckpoint(history CodeHistory, sessionID, code string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), codeHistoryTimeout)
+	defer cancel()
+
+	if err := history.RecordCodeRevision(ctx, sessionID, code); err != nil {
+		logger.ErrorErr(err, "failed to record code revision",
+			"session_id", sessionID,
+		)
+		return false
+	}
+
+	return true
+}
+
+// records a change to the document (must be called with hub lock held)
+func (sd *sessionDocument) markEdited() {
+	sd.dirty = true
+	sd.edits++
+}
+
+// removes a session document and returns its code if it has unsaved changes
+// (must be called with lock held)
+func (h *Hub) removeDocument(sessionID string) (string, bool) {
+	sd, exists := h.documents[sessionID]
+	if !exists {
+		return "", false
+	}
+
+	delete(h.documents, sessionID)
+
+	return sd.doc.Text(), sd.dirty
+}
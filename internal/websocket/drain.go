@@ -0,0 +1,104 @@
+package websocket
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+// how often WaitForDrain checks whether clients are still connected
+const drainPollInterval = 250 * time.Millisecond
+
+// puts the hub in drain mode: new connections are refused and every connected
+// client gets a reconnect_hint with a random backoff below spread, so they
+// don't all hit the new instance at once. returns the number of clients told,
+// or 0 if the hub was already draining.
+func (h *Hub) Drain(spread time.Duration) int {
+	if !h.draining.CompareAndSwap(false, true) {
+		return 0
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	hinted := 0
+	for sessionID, sessionClients := range h.sessions {
+		for _, client := range sessionClients {
+			if h.sendReconnectHint(client, sessionID, spread) {
+				hinted++
+			}
+		}
+	}
+
+	for sessionID, audience := range h.audiences {
+		for _, client := range audience {
+			if h.sendReconnectHint(client, sessionID, spread) {
+				hinted++
+			}
+		}
+	}
+
+	return hinted
+}
+
+// reports whether the hub is draining
+func (h *Hub) IsDraining() bool {
+	return h.draining.Load()
+}
+
+// waits until every client has disconnected or ctx is done. returns whether
+// the hub is empty
+func (h *Hub) WaitForDrain(ctx context.Context) bool {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if h.connectionCount() == 0 {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return h.connectionCount() == 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// must be called with lock held
+func (h *Hub) sendReconnectHint(client *Client, sessionID string, spread time.Duration) bool {
+	var backoff time.Duration
+	if spread > 0 {
+		backoff = rand.N(spread)
+	}
+
+	msg, err := NewMessage(TypeReconnectHint, sessionID, "", ReconnectHintPayload{
+		Reason:    "server is restarting, reconnect to continue",
+		BackoffMs: backoff.Milliseconds(),
+		Seq:       h.sessionSequences[sessionID],
+		ClientID:  client.ID,
+	})
+	if err != nil {
+		logger.ErrorErr(err, "failed to create reconnect hint", "session_id", sessionID)
+		return false
+	}
+
+	return client.Send(msg) == nil
+}
+
+func (h *Hub) connectionCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := 0
+	for _, sessionClients := range h.sessions {
+		count += len(sessionClients)
+	}
+	for _, audience := range h.audiences {
+		count += len(audience)
+	}
+
+	return count
+}
@@ -0,0 +1,123 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+)
+
+// msgpack handle shared by all binary clients (safe for concurrent use once configured)
+var msgpackHandle = newMsgpackHandle()
+
+func newMsgpackHandle() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.MapType = reflect.TypeOf(map[string]any(nil))
+	h.RawToString = true
+	return h
+}
+
+// encodes a message as JSON, or as msgpack for binary clients. msgpack
+// payloads are native maps rather than embedded JSON, so code is sent as a
+// raw string without JSON escaping.
+func encodeMessage(msg *Message, binary bool) ([]byte, error) {
+	if !binary {
+		return json.Marshal(msg)
+	}
+
+	wire := binaryMessage{
+		Type:      msg.Type,
+		SessionID: msg.SessionID,
+		UserID:    msg.UserID,
+		Timestamp: msg.Timestamp,
+		Sequence:  msg.Sequence,
+	}
+
+	if len(msg.Payload) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(msg.Payload))
+		decoder.UseNumber()
+
+		var payload any
+		if err := decoder.Decode(&payload); err != nil {
+			return nil, err
+		}
+
+		wire.Payload = normalizeNumbers(payload)
+	}
+
+	var data []byte
+	if err := codec.NewEncoderBytes(&data, msgpackHandle).Encode(&wire); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// decodes a message received in a text (JSON) or binary (msgpack) frame
+func decodeMessage(data []byte, binary bool) (*Message, error) {
+	var msg Message
+
+	if !binary {
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, err
+		}
+
+		return &msg, nil
+	}
+
+	var wire binaryMessage
+	if err := codec.NewDecoderBytes(data, msgpackHandle).Decode(&wire); err != nil {
+		return nil, err
+	}
+
+	msg.Type = wire.Type
+	msg.Sequence = wire.Sequence
+
+	if wire.Payload != nil {
+		payload, err := json.Marshal(wire.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		msg.Payload = payload
+	}
+
+	return &msg, nil
+}
+
+// re-encodes a JSON encoded message (e.g., from the replay buffer) for a binary client
+func transcodeMessage(data []byte) ([]byte, error) {
+	msg, err := decodeMessage(data, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeMessage(msg, true)
+}
+
+// converts json.Number values so integers are sent as msgpack integers
+// instead of floats
+func normalizeNumbers(v any) any {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := value.Float64() //nolint:errcheck,gosec // already validated by the decoder
+		return f
+
+	case map[string]any:
+		for key, item := range value {
+			value[key] = normalizeNumbers(item)
+		}
+
+	case []any:
+		for i, item := range value {
+			value[i] = normalizeNumbers(item)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,158 @@
+package websocket
+
+import (
+	"bytes"
+	"compress/flate"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	msg, err := NewMessage(TypeCodeUpdate, "session-1", "user-1", CodeUpdatePayload{
+		Code:       "note(\"c e g\")\n  .s(\"piano\")",
+		CursorLine: 2,
+		Revision:   7,
+	})
+	require.NoError(t, err)
+	msg.Sequence = 42
+
+	data, err := encodeMessage(msg, true)
+	require.NoError(t, err)
+
+	decoded, err := decodeMessage(data, true)
+	require.NoError(t, err)
+	assert.Equal(t, TypeCodeUpdate, decoded.Type)
+	assert.Equal(t, uint64(42), decoded.Sequence)
+
+	var payload CodeUpdatePayload
+	require.NoError(t, decoded.UnmarshalPayload(&payload))
+	assert.Equal(t, "note(\"c e g\")\n  .s(\"piano\")", payload.Code)
+	assert.Equal(t, 2, payload.CursorLine)
+	assert.Equal(t, 7, payload.Revision)
+}
+
+func TestMsgpackPayloadKeepsIntegers(t *testing.T) {
+	msg, err := NewMessage(TypeCodeOperation, "session-1", "user-1", map[string]any{
+		"revision":  3,
+		"operation": []any{5, "x", -2},
+		"ratio":     0.5,
+	})
+	require.NoError(t, err)
+
+	data, err := encodeMessage(msg, true)
+	require.NoError(t, err)
+
+	decoded, err := decodeMessage(data, true)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"revision":3,"operation":[5,"x",-2],"ratio":0.5}`, string(decoded.Payload))
+}
+
+func TestTranscodeMessage(t *testing.T) {
+	msg, err := NewMessage(TypeChatMessage, "session-1", "user-1", ChatMessagePayload{
+		Message: "hello",
+	})
+	require.NoError(t, err)
+
+	jsonBytes, err := encodeMessage(msg, false)
+	require.NoError(t, err)
+
+	binaryBytes, err := transcodeMessage(jsonBytes)
+	require.NoError(t, err)
+
+	decoded, err := decodeMessage(binaryBytes, true)
+	require.NoError(t, err)
+	assert.Equal(t, TypeChatMessage, decoded.Type)
+	assert.JSONEq(t, string(msg.Payload), string(decoded.Payload))
+}
+
+func TestDecodeMessageRejectsGarbage(t *testing.T) {
+	_, err := decodeMessage([]byte{0xc1}, true)
+	assert.Error(t, err)
+
+	_, err = decodeMessage([]byte("not json"), false)
+	assert.Error(t, err)
+}
+
+// a ~50 KB session, the size where broadcasts start to dominate bandwidth
+func largeCodeMessage(b *testing.B) *Message {
+	line := "$: note(\"<c3 e3 g3 b3>\").s(\"sawtooth\").lpf(sine.range(400, 2000).slow(8)).room(0.4)\n"
+	code := strings.Repeat(line, 50*1024/len(line))
+
+	msg, err := NewMessage(TypeCodeUpdate, "session-1", "user-1", CodeUpdatePayload{
+		Code:        code,
+		CursorLine:  120,
+		CursorCol:   14,
+		DisplayName: "Host",
+		Source:      "typed",
+		Revision:    512,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return msg
+}
+
+func benchmarkEncode(b *testing.B, binary bool) {
+	msg := largeCodeMessage(b)
+
+	var data []byte
+	var err error
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for b.Loop() {
+		data, err = encodeMessage(msg, binary)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	// wire sizes with and without permessage-deflate
+	var compressed bytes.Buffer
+	w, err := flate.NewWriter(&compressed, flate.BestSpeed)
+	if err != nil {
+		b.Fatal(err)
+	}
+	w.Write(data) //nolint:errcheck,gosec // writes to a buffer
+	w.Close()     //nolint:errcheck,gosec // writes to a buffer
+
+	b.ReportMetric(float64(len(data)), "bytes/msg")
+	b.ReportMetric(float64(compressed.Len()), "deflated-bytes/msg")
+}
+
+func BenchmarkEncodeJSON(b *testing.B) {
+	benchmarkEncode(b, false)
+}
+
+func BenchmarkEncodeMsgpack(b *testing.B) {
+	benchmarkEncode(b, true)
+}
+
+func benchmarkDecode(b *testing.B, binary bool) {
+	data, err := encodeMessage(largeCodeMessage(b), binary)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for b.Loop() {
+		if _, err := decodeMessage(data, binary); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeJSON(b *testing.B) {
+	benchmarkDecode(b, false)
+}
+
+func BenchmarkDecodeMsgpack(b *testing.B) {
+	benchmarkDecode(b, true)
+}
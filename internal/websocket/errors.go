@@ -3,8 +3,36 @@ package websocket
 import (
 	"os"
 	"strings"
+
+	"codeberg.org/algopatterns/server/internal/errors"
 )
 
+// the payload of error messages. retryable tells clients whether sending the
+// same message again can succeed without anything else changing, and
+// retry_after_ms how long to wait before doing so
+type ErrorPayload struct {
+	Error        errors.Code `json:"error"`
+	Message      string      `json:"message"`
+	Details      string      `json:"details,omitempty"`
+	RequestID    *string     `json:"request_id,omitempty"` // echoed from the request that failed
+	Retryable    bool        `json:"retryable"`
+	RetryAfterMs int64       `json:"retry_after_ms,omitempty"`
+}
+
+// builds an error payload with the code's retry hint
+func newErrorPayload(code errors.Code, message, details string, requestID *string) ErrorPayload {
+	retry := code.Retry()
+
+	return ErrorPayload{
+		Error:        code,
+		Message:      message,
+		Details:      details,
+		RequestID:    requestID,
+		Retryable:    retry.Retryable,
+		RetryAfterMs: retry.After.Milliseconds(),
+	}
+}
+
 // sanitizes error details for production
 func sanitizeErrorString(errMsg string) string {
 	if errMsg == "" {
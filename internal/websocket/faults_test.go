@@ -0,0 +1,49 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/internal/ccsignals"
+	"codeberg.org/algopatterns/server/internal/faults"
+	"codeberg.org/algopatterns/server/internal/llm"
+)
+
+func TestAgentRequestFailsOpenWhenPasteLocksFail(t *testing.T) {
+	injector := faults.New()
+
+	// nothing listens here, so lock lookups only see injected faults
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	client.AddHook(injector.RedisHook())
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck,gosec // test cleanup
+
+	detector := ccsignals.NewDetector(ccsignals.DefaultConfig(), ccsignals.NewRedisLockStore(client), nil)
+
+	hub, _ := newProtocolHub(t)
+	agentClient, providers := protocolAgent()
+	hub.RegisterHandler(TypeAgentRequest, AgentRequestHandler(agentClient, providers, detector, nil, nil, nil))
+
+	host := joinProtocolClient(t, hub, "host", "host")
+	readFrames(t, host)
+
+	for _, fault := range []faults.Fault{{ErrorRate: 1}, {DropRate: 1}} {
+		require.NoError(t, injector.Set(faults.TargetRedis, fault))
+
+		locked, err := detector.IsLocked(t.Context(), "session-1")
+		require.Error(t, err)
+		require.False(t, locked)
+
+		sendProtocolMessage(t, hub, host, TypeAgentRequest, AgentRequestPayload{
+			RequestID:      "req-1",
+			UserQuery:      "make a beat",
+			Provider:       string(llm.ProviderOllama),
+			ProviderAPIKey: "key",
+		})
+
+		// the request goes ahead as if the session weren't locked
+		assert.Equal(t, []string{TypeAgentResponseChunk, TypeAgentResponseDone}, frameTypes(readFrames(t, host)), "fault %+v", fault)
+	}
+}
@@ -2,12 +2,26 @@ package websocket
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/internal/agent"
 	"codeberg.org/algopatterns/server/internal/ccsignals"
+	"codeberg.org/algopatterns/server/internal/config"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/llm"
 	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+	"codeberg.org/algopatterns/server/internal/moderation"
+	"codeberg.org/algopatterns/server/internal/ot"
+	"codeberg.org/algopatterns/server/internal/quota"
+	"codeberg.org/algopatterns/server/internal/strudel"
 )
 
 // handles code update messages with CC signals detection
@@ -15,40 +29,60 @@ func CodeUpdateHandler(sessionRepo sessions.Repository, detector *ccsignals.Dete
 	return func(hub *Hub, client *Client, msg *Message) error {
 		// check rate limit
 		if !client.checkCodeUpdateRateLimit() {
-			client.SendError("too_many_requests", "too many code updates. maximum 10 per second.", "")
+			client.SendError(errors.CodeTooManyRequests, client.codeUpdateRateError(), "")
 			return ErrRateLimitExceeded
 		}
 
 		// check if client has write permissions
 		if !client.CanWrite() {
-			client.SendError("forbidden", "you don't have permission to edit code", "")
+			client.SendError(errors.CodeForbidden, "you don't have permission to edit code", "")
 			return ErrReadOnly
 		}
 
 		// parse payload
 		var payload CodeUpdatePayload
 		if err := msg.UnmarshalPayload(&payload); err != nil {
-			client.SendError("validation_error", "failed to parse code update", err.Error())
+			client.SendError(errors.CodeValidationError, "failed to parse code update", err.Error())
 			return err
 		}
 
 		// validate code size
 		codeSize := len([]byte(payload.Code))
 		if codeSize > maxCodeSize {
-			client.SendError("bad_request", "code exceeds maximum size. maximum 100 KB allowed.", "")
+			client.SendError(errors.CodeBadRequest, "code exceeds maximum size. maximum 100 KB allowed.", "")
 			return ErrCodeTooLarge
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(msg.Context(), 5*time.Second)
 		defer cancel()
 
-		// get previous code for paste detection
-		session, err := sessionRepo.GetSession(ctx, client.SessionID)
-		previousCode := ""
-		if err == nil && session != nil {
-			previousCode = session.Code
+		// lint before broadcasting; problems are reported to the sender but
+		// never block the update, since live code is routinely invalid mid-edit
+		diagnostics := strudel.Lint(payload.Code)
+
+		// enrich payload with sender information for cursor tracking
+		payload.DisplayName = client.DisplayName
+		payload.UserID = client.UserID
+		payload.Role = client.Role
+
+		// replace the shared document and broadcast to all other clients in the session
+		change, err := hub.ReplaceCode(client, payload)
+		if err != nil {
+			if sendRegionLocked(client, err) {
+				return nil
+			}
+
+			logger.ErrorErr(err, "failed to apply code update",
+				"client_id", client.ID,
+				"session_id", client.SessionID,
+			)
+			return err
 		}
 
+		sendCodeValidation(client, diagnostics, change.Revision)
+
+		previousCode := change.PreviousCode
+
 		// use ccsignals detector for paste detection
 		// only check for 'paste' or 'typed' sources (skip loaded_strudel, forked)
 		shouldCheckPaste := payload.Source == "" || payload.Source == "typed" || payload.Source == "paste"
@@ -77,31 +111,192 @@ func CodeUpdateHandler(sessionRepo sessions.Repository, detector *ccsignals.Dete
 				"client_id", client.ID,
 				"session_id", client.SessionID,
 			)
-			// don't fail the request, broadcast still happens
+			// don't fail the request, broadcast already happened
 		}
 
-		// enrich payload with sender information for cursor tracking
-		payload.DisplayName = client.DisplayName
-		payload.UserID = client.UserID
-		payload.Role = client.Role
+		return nil
+	}
+}
 
-		// create new message with updated payload
-		broadcastMsg, err := NewMessage(TypeCodeUpdate, client.SessionID, client.UserID, payload)
+// handles character-level code edits, merging concurrent edits from multiple writers
+func CodeOperationHandler(detector *ccsignals.Detector) MessageHandler {
+	return func(hub *Hub, client *Client, msg *Message) error {
+		// check rate limit (shared with full code updates)
+		if !client.checkCodeUpdateRateLimit() {
+			client.SendError(errors.CodeTooManyRequests, client.codeUpdateRateError(), "")
+			return ErrRateLimitExceeded
+		}
+
+		// check if client has write permissions
+		if !client.CanWrite() {
+			client.SendError(errors.CodeForbidden, "you don't have permission to edit code", "")
+			return ErrReadOnly
+		}
+
+		// parse payload
+		var payload CodeOperationPayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			client.SendError(errors.CodeValidationError, "failed to parse code operation", err.Error())
+			return err
+		}
+
+		if err := payload.Operation.Validate(); err != nil {
+			client.SendError(errors.CodeValidationError, "invalid code operation", err.Error())
+			return err
+		}
+
+		// rebase onto the latest revision, apply and broadcast
+		change, err := hub.ApplyCodeOperation(client, payload.Revision, payload.Operation)
 		if err != nil {
-			logger.ErrorErr(err, "failed to create broadcast message",
-				"client_id", client.ID,
-				"session_id", client.SessionID,
-			)
+			switch {
+			case stderrors.Is(err, ErrCodeTooLarge):
+				client.SendError(errors.CodeBadRequest, "code exceeds maximum size. maximum 100 KB allowed.", "")
+			case stderrors.Is(err, ot.ErrRevisionTooOld), stderrors.Is(err, ot.ErrRevisionInFuture):
+				client.SendError(errors.CodeConflict, "document revision is out of range, reconnect to resync", err.Error())
+			case stderrors.Is(err, ot.ErrBaseLengthMismatch), stderrors.Is(err, ot.ErrIncompatibleOps):
+				client.SendError(errors.CodeValidationError, "operation does not match document revision", err.Error())
+			case sendRegionLocked(client, err):
+			default:
+				return err
+			}
+
+			return nil
+		}
+
+		sendCodeValidation(client, strudel.Lint(change.Code), change.Revision)
+
+		if detector != nil {
+			ctx, cancel := context.WithTimeout(msg.Context(), 5*time.Second)
+			defer cancel()
+
+			handlePasteDetection(ctx, hub, client, detector, change.PreviousCode, change.Code)
+		}
+
+		return nil
+	}
+}
+
+// handles requests to revert the session code to the previous checkpoint
+func UndoHandler() MessageHandler {
+	return func(hub *Hub, client *Client, msg *Message) error {
+		return handleCodeHistory(client, msg, hub.UndoCode)
+	}
+}
+
+// handles requests to re-apply the most recently undone checkpoint
+func RedoHandler() MessageHandler {
+	return func(hub *Hub, client *Client, msg *Message) error {
+		return handleCodeHistory(client, msg, hub.RedoCode)
+	}
+}
+
+// validates an undo/redo request and restores the checkpoint
+func handleCodeHistory(client *Client, msg *Message, restore func(*Client, int) (*DocumentChange, error)) error {
+	// check rate limit (shared with code updates)
+	if !client.checkCodeUpdateRateLimit() {
+		client.SendError(errors.CodeTooManyRequests, client.codeUpdateRateError(), "")
+		return ErrRateLimitExceeded
+	}
+
+	// check if client has write permissions
+	if !client.CanWrite() {
+		client.SendError(errors.CodeForbidden, "you don't have permission to edit code", "")
+		return ErrReadOnly
+	}
+
+	// parse payload
+	var payload UndoRedoPayload
+	if err := msg.UnmarshalPayload(&payload); err != nil {
+		client.SendError(errors.CodeValidationError, "failed to parse "+msg.Type+" request", err.Error())
+		return err
+	}
+
+	if _, err := restore(client, payload.Revision); err != nil {
+		switch {
+		case stderrors.Is(err, ErrRevisionConflict):
+			client.SendError(errors.CodeConflict, "code changed since your last update, try again", err.Error())
+		case stderrors.Is(err, ErrNothingToUndo), stderrors.Is(err, ErrNothingToRedo):
+			client.SendError(errors.CodeBadRequest, err.Error(), "")
+		case stderrors.Is(err, ErrHistoryUnavailable):
+			client.SendError(errors.CodeServerError, "undo/redo is not available", "")
+		case sendRegionLocked(client, err):
+		default:
 			return err
 		}
+	}
 
-		// broadcast to all other clients in the session
-		hub.BroadcastToSession(client.SessionID, broadcastMsg, client.ID)
+	return nil
+}
+
+// handles requests to lock a line range or pattern block for the sender
+func LockRegionHandler() MessageHandler {
+	return func(hub *Hub, client *Client, msg *Message) error {
+		// check rate limit (shared with code updates)
+		if !client.checkCodeUpdateRateLimit() {
+			client.SendError(errors.CodeTooManyRequests, client.codeUpdateRateError(), "")
+			return ErrRateLimitExceeded
+		}
+
+		// only clients that can edit code can lock it
+		if !client.CanWrite() {
+			client.SendError(errors.CodeForbidden, "you don't have permission to edit code", "")
+			return ErrReadOnly
+		}
+
+		var payload LockRegionPayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			client.SendError(errors.CodeValidationError, "failed to parse lock request", err.Error())
+			return err
+		}
+
+		// the new lock reaches the sender through the region_locks broadcast
+		if _, err := hub.LockRegion(client, payload.StartLine, payload.EndLine, payload.Block); err != nil {
+			switch {
+			case stderrors.Is(err, ErrInvalidRegion), stderrors.Is(err, ErrBlockNotFound), stderrors.Is(err, ErrTooManyRegionLocks):
+				client.SendError(errors.CodeBadRequest, err.Error(), "")
+			case sendRegionLocked(client, err):
+			default:
+				return err
+			}
+		}
 
 		return nil
 	}
 }
 
+// handles requests to release a region lock
+func UnlockRegionHandler() MessageHandler {
+	return func(hub *Hub, client *Client, msg *Message) error {
+		var payload UnlockRegionPayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			client.SendError(errors.CodeValidationError, "failed to parse unlock request", err.Error())
+			return err
+		}
+
+		if err := hub.UnlockRegion(client, payload.LockID); err != nil {
+			if stderrors.Is(err, ErrRegionLockNotFound) {
+				client.SendError(errors.CodeBadRequest, err.Error(), "")
+				return nil
+			}
+
+			return err
+		}
+
+		return nil
+	}
+}
+
+// tells the client which lock blocked its edit. returns false if err isn't a lock conflict
+func sendRegionLocked(client *Client, err error) bool {
+	var lockedErr *RegionLockedError
+	if !stderrors.As(err, &lockedErr) {
+		return false
+	}
+
+	client.SendError(errors.CodeRegionLocked, lockedErr.Error(), "")
+	return true
+}
+
 // uses the ccsignals detector to manage paste locks
 func handlePasteDetection(ctx context.Context, hub *Hub, client *Client, detector *ccsignals.Detector, previousCode, newCode string) {
 	deltaChars := len(newCode) - len(previousCode)
@@ -136,6 +331,8 @@ func handlePasteDetection(ctx context.Context, hub *Hub, client *Client, detecto
 			reason := "paste_detected"
 			if result.FingerprintMatch != nil && !result.FingerprintMatch.Record.CCSignal.AllowsAI() {
 				reason = "similar_to_protected"
+			} else if result.PartialMatch != nil && !result.PartialMatch.CCSignal.AllowsAI() {
+				reason = "similar_to_protected"
 			} else if result.MatchedContent != nil && result.MatchedContent.CCSignal == ccsignals.SignalNoAI {
 				reason = "parent_no_ai"
 			}
@@ -191,16 +388,30 @@ func handlePasteDetection(ctx context.Context, hub *Hub, client *Client, detecto
 
 // handles play messages from host/co-author
 func PlayHandler() MessageHandler {
-	return func(hub *Hub, client *Client, _ *Message) error {
-		// check if client has write permissions (host or co-author)
-		if !client.CanWrite() {
-			client.SendError("forbidden", "only host and co-authors can control playback", "")
+	return func(hub *Hub, client *Client, msg *Message) error {
+		if !client.Can(sessions.PermControlPlayback) {
+			client.SendError(errors.CodeForbidden, "you don't have permission to control playback", "")
 			return ErrReadOnly
 		}
 
+		// the tempo is optional, older clients send an empty payload
+		var request PlayPayload
+		if len(msg.Payload) > 0 {
+			if err := msg.UnmarshalPayload(&request); err != nil {
+				client.SendError(errors.CodeValidationError, "failed to parse play payload", err.Error())
+				return err
+			}
+		}
+
+		if request.CPM != 0 && (request.CPM < minCPM || request.CPM > maxCPM) {
+			client.SendError(errors.CodeValidationError, fmt.Sprintf("cpm must be between %d and %d", minCPM, maxCPM), "")
+			return ErrInvalidTempo
+		}
+
 		// create broadcast payload with display name
 		payload := PlayPayload{
 			DisplayName: client.DisplayName,
+			CPM:         request.CPM,
 		}
 
 		// create broadcast message
@@ -216,10 +427,14 @@ func PlayHandler() MessageHandler {
 		// broadcast to all other clients in the session (exclude sender)
 		hub.BroadcastToSession(client.SessionID, broadcastMsg, client.ID)
 
+		// everyone, the sender included, starts on the clock's cycle boundary
+		transport := hub.StartTransport(client.SessionID, request.CPM, client.DisplayName)
+
 		logger.Info("playback started",
 			"client_id", client.ID,
 			"session_id", client.SessionID,
 			"display_name", client.DisplayName,
+			"cpm", transport.CPM,
 		)
 
 		return nil
@@ -229,9 +444,8 @@ func PlayHandler() MessageHandler {
 // handles stop messages from host/co-author
 func StopHandler() MessageHandler {
 	return func(hub *Hub, client *Client, _ *Message) error {
-		// check if client has write permissions (host or co-author)
-		if !client.CanWrite() {
-			client.SendError("forbidden", "only host and co-authors can control playback", "")
+		if !client.Can(sessions.PermControlPlayback) {
+			client.SendError(errors.CodeForbidden, "you don't have permission to control playback", "")
 			return ErrReadOnly
 		}
 
@@ -252,6 +466,7 @@ func StopHandler() MessageHandler {
 
 		// broadcast to all other clients in the session (exclude sender)
 		hub.BroadcastToSession(client.SessionID, broadcastMsg, client.ID)
+		hub.StopTransport(client.SessionID, client.DisplayName)
 
 		logger.Info("playback stopped",
 			"client_id", client.ID,
@@ -263,12 +478,37 @@ func StopHandler() MessageHandler {
 	}
 }
 
-// handles session chat message messages
-func ChatHandler(sessionRepo sessions.Repository) MessageHandler {
+// handles session chat message messages. moderator may be nil
+func ChatHandler(sessionRepo sessions.Repository, moderator ContentModerator) MessageHandler {
+	return chatHandler(sessionRepo, moderator, TypeChatMessage)
+}
+
+// handles replies to a chat message. moderator may be nil
+func ChatReplyHandler(sessionRepo sessions.Repository, moderator ContentModerator) MessageHandler {
+	return chatHandler(sessionRepo, moderator, TypeChatReply)
+}
+
+// saves and broadcasts a chat message or reply. msgType is echoed in the broadcast
+func chatHandler(sessionRepo sessions.Repository, moderator ContentModerator, msgType string) MessageHandler {
 	return func(hub *Hub, client *Client, msg *Message) error {
+		if !client.Can(sessions.PermChat) {
+			client.SendError(errors.CodeForbidden, "you don't have permission to chat", "")
+			return ErrReadOnly
+		}
+
+		if !config.FeatureEnabled(config.FeatureChat) {
+			client.SendError(errors.CodeFeatureDisabled, "chat is temporarily disabled", "")
+			return ErrFeatureDisabled
+		}
+
+		if client.IsMuted() {
+			client.SendError(errors.CodeMuted, "the host has muted you in chat", "")
+			return ErrMuted
+		}
+
 		// check rate limit
 		if !client.checkChatRateLimit() {
-			client.SendError("too_many_requests", "too many chat messages. maximum 20 per minute.", "")
+			client.SendError(errors.CodeTooManyRequests, "too many chat messages. maximum 20 per minute.", "")
 			return ErrRateLimitExceeded
 		}
 
@@ -276,15 +516,25 @@ func ChatHandler(sessionRepo sessions.Repository) MessageHandler {
 		var payload ChatMessagePayload
 
 		if err := msg.UnmarshalPayload(&payload); err != nil {
-			client.SendError("validation_error", "failed to parse chat message", err.Error())
+			client.SendError(errors.CodeValidationError, "failed to parse chat message", err.Error())
 			return err
 		}
 
+		// replies need a parent, plain messages can't have one
+		if msgType == TypeChatReply {
+			if !sessions.IsValidMessageID(payload.ParentMessageID) {
+				client.SendError(errors.CodeBadRequest, "parent_message_id must be a message ID", "")
+				return ErrInvalidChatReference
+			}
+		} else {
+			payload.ParentMessageID = ""
+		}
+
 		// validate message size
 		messageSize := len([]rune(payload.Message))
 
 		if messageSize > maxChatMessageSize {
-			client.SendError("bad_request", "message exceeds maximum size. maximum 5000 characters allowed.", "")
+			client.SendError(errors.CodeBadRequest, "message exceeds maximum size. maximum 5000 characters allowed.", "")
 			return ErrCodeTooLarge
 		}
 
@@ -292,29 +542,89 @@ func ChatHandler(sessionRepo sessions.Repository) MessageHandler {
 		trimmedMessage := strings.TrimSpace(payload.Message)
 
 		if trimmedMessage == "" {
-			client.SendError("bad_request", "message cannot be empty", "")
+			client.SendError(errors.CodeBadRequest, "message cannot be empty", "")
 			return ErrCodeTooLarge
 		}
 
-		// save chat message (goes to redis buffer via BufferedRepository)
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if hub.chatBackedUp(client.SessionID) {
+			client.SendError(errors.CodeServerBusy, "chat is busy right now. try again in a few seconds.", "")
+			return ErrChatBackedUp
+		}
+
+		// slow mode is checked last, so rejected messages don't count
+		if wait := hub.reserveChatMessage(client); wait > 0 {
+			seconds := int(math.Ceil(wait.Seconds()))
+			client.SendErrorRetryAfter(errors.CodeSlowMode, fmt.Sprintf("slow mode is on. you can send another message in %d seconds.", seconds), wait)
+			return ErrSlowMode
+		}
+
+		ctx, cancel := context.WithTimeout(msg.Context(), 5*time.Second)
 		defer cancel()
 
-		_, err := sessionRepo.AddChatMessage(ctx, client.SessionID, client.UserID, trimmedMessage, client.DisplayName, "")
+		content := moderation.Content{
+			Type:       moderation.ContentChatMessage,
+			SessionID:  client.SessionID,
+			AuthorID:   client.UserID,
+			AuthorName: client.DisplayName,
+			Text:       trimmedMessage,
+		}
+
+		verdict := moderation.Verdict{Action: moderation.ActionAllow}
+		if moderator != nil {
+			verdict = moderator.Screen(ctx, content)
+		}
+
+		payload.DisplayName = client.DisplayName
+		payload.Message = trimmedMessage
+
+		switch verdict.Action {
+		case moderation.ActionBlock:
+			client.SendError(errors.CodeContentBlocked, "message was blocked by content moderation", "")
+			return ErrContentBlocked
+
+		case moderation.ActionHide:
+			// shadow-hidden: the sender sees the message as usual, nobody else
+			// does. it is queued for review instead of being stored
+			if id, err := sessions.NewMessageID(); err == nil {
+				payload.MessageID = id
+				content.ID = id
+			}
+
+			moderator.Report(ctx, content, verdict)
+
+			echo, err := NewMessage(msgType, client.SessionID, client.UserID, payload)
+			if err != nil {
+				return err
+			}
+
+			return client.Send(echo)
+		}
+
+		// save chat message (goes to redis buffer via BufferedRepository)
+		saved, err := sessionRepo.AddChatMessage(ctx, &sessions.AddChatMessageRequest{
+			SessionID:       client.SessionID,
+			UserID:          client.UserID,
+			Content:         trimmedMessage,
+			DisplayName:     client.DisplayName,
+			ParentMessageID: payload.ParentMessageID,
+		})
 		if err != nil {
 			logger.ErrorErr(err, "failed to save chat message",
 				"client_id", client.ID,
 				"session_id", client.SessionID,
 			)
 			// don't fail - broadcast is more important for real-time chat
+		} else {
+			payload.MessageID = saved.ID
 		}
 
-		// add display name to payload
-		payload.DisplayName = client.DisplayName
-		payload.Message = trimmedMessage
+		if verdict.Action == moderation.ActionFlag {
+			content.ID = payload.MessageID
+			moderator.Report(ctx, content, verdict)
+		}
 
 		// create broadcast message
-		broadcastMsg, err := NewMessage(TypeChatMessage, client.SessionID, client.UserID, payload)
+		broadcastMsg, err := NewMessage(msgType, client.SessionID, client.UserID, payload)
 		if err != nil {
 			logger.ErrorErr(err, "failed to create broadcast message",
 				"client_id", client.ID,
@@ -324,6 +634,79 @@ func ChatHandler(sessionRepo sessions.Repository) MessageHandler {
 		}
 
 		// broadcast to all clients in the session (including sender)
+		hub.BroadcastToSession(client.SessionID, broadcastMsg, "")
+		hub.countActivity(client.SessionID, activityChatMessage)
+
+		return nil
+	}
+}
+
+// handles emoji reactions on chat messages. reactions share the chat rate limit
+func ChatReactionHandler(sessionRepo sessions.Repository) MessageHandler {
+	return func(hub *Hub, client *Client, msg *Message) error {
+		if !client.Can(sessions.PermChat) {
+			client.SendError(errors.CodeForbidden, "you don't have permission to chat", "")
+			return ErrReadOnly
+		}
+
+		if !config.FeatureEnabled(config.FeatureChat) {
+			client.SendError(errors.CodeFeatureDisabled, "chat is temporarily disabled", "")
+			return ErrFeatureDisabled
+		}
+
+		if client.IsMuted() {
+			client.SendError(errors.CodeMuted, "the host has muted you in chat", "")
+			return ErrMuted
+		}
+
+		if !client.checkChatRateLimit() {
+			client.SendError(errors.CodeTooManyRequests, "too many chat messages. maximum 20 per minute.", "")
+			return ErrRateLimitExceeded
+		}
+
+		var payload ChatReactionPayload
+
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			client.SendError(errors.CodeValidationError, "failed to parse chat reaction", err.Error())
+			return err
+		}
+
+		if !sessions.IsValidMessageID(payload.MessageID) {
+			client.SendError(errors.CodeBadRequest, "message_id must be a message ID", "")
+			return ErrInvalidChatReference
+		}
+
+		emoji := strings.TrimSpace(payload.Emoji)
+		if emoji == "" || len([]rune(emoji)) > maxReactionSize || strings.ContainsAny(emoji, " \t\n") {
+			client.SendError(errors.CodeBadRequest, "emoji must be a single emoji", "")
+			return ErrInvalidMessage
+		}
+
+		ctx, cancel := context.WithTimeout(msg.Context(), 5*time.Second)
+		defer cancel()
+
+		var err error
+		if payload.Removed {
+			err = sessionRepo.RemoveChatReaction(ctx, client.SessionID, payload.MessageID, client.UserID, client.DisplayName, emoji)
+		} else {
+			err = sessionRepo.AddChatReaction(ctx, client.SessionID, payload.MessageID, client.UserID, client.DisplayName, emoji)
+		}
+
+		if err != nil {
+			logger.ErrorErr(err, "failed to save chat reaction",
+				"client_id", client.ID,
+				"session_id", client.SessionID,
+			)
+		}
+
+		payload.Emoji = emoji
+		payload.DisplayName = client.DisplayName
+
+		broadcastMsg, err := NewMessage(TypeChatReaction, client.SessionID, client.UserID, payload)
+		if err != nil {
+			return err
+		}
+
 		hub.BroadcastToSession(client.SessionID, broadcastMsg, "")
 
 		return nil
@@ -331,7 +714,37 @@ func ChatHandler(sessionRepo sessions.Repository) MessageHandler {
 }
 
 // sendPasteLockStatus sends a paste lock status message to the client
+// sends lint results to the client that changed the code, unless they
+// match the last results it received
+func sendCodeValidation(client *Client, diagnostics []strudel.Diagnostic, revision int) {
+	if diagnostics == nil {
+		diagnostics = []strudel.Diagnostic{}
+	}
+
+	signature, err := json.Marshal(diagnostics)
+	if err != nil || !client.updateValidation(string(signature)) {
+		return
+	}
+
+	msg, err := NewMessage(TypeCodeValidation, client.SessionID, client.UserID, CodeValidationPayload{
+		Valid:       !strudel.HasLintErrors(diagnostics),
+		Revision:    revision,
+		Diagnostics: diagnostics,
+	})
+	if err != nil {
+		return
+	}
+
+	client.Send(msg) //nolint:errcheck,gosec // best-effort
+}
+
 func sendPasteLockStatus(_ *Hub, client *Client, locked bool, reason string) {
+	event := "unlocked"
+	if locked {
+		event = "locked"
+	}
+	metrics.PasteLockEvents.Inc(event, reason)
+
 	payload := PasteLockChangedPayload{
 		Locked: locked,
 		Reason: reason,
@@ -374,6 +787,32 @@ func PingHandler() MessageHandler {
 	}
 }
 
+// handles clock_sync messages, an NTP-style exchange clients repeat to
+// estimate their offset to the server's clock:
+// offset = ((server_receive - client_time) + (server_send - client_receive)) / 2
+func ClockSyncHandler() MessageHandler {
+	return func(_ *Hub, client *Client, msg *Message) error {
+		var payload ClockSyncPayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			client.SendError(errors.CodeValidationError, "failed to parse clock_sync payload", err.Error())
+			return err
+		}
+
+		// the read loop stamps messages as they arrive, before they queue up
+		// in the hub
+		payload.ServerReceive = unixMillis(msg.Timestamp)
+		payload.ServerSend = unixMillis(time.Now())
+
+		reply, err := NewMessage(TypeClockSync, client.SessionID, client.UserID, payload)
+		if err != nil {
+			return err
+		}
+
+		client.Send(reply) //nolint:errcheck,gosec // best-effort, clients sample several times
+		return nil
+	}
+}
+
 // handles cursor position messages for collaboration
 func CursorPositionHandler() MessageHandler {
 	return func(hub *Hub, client *Client, msg *Message) error {
@@ -411,3 +850,355 @@ func CursorPositionHandler() MessageHandler {
 		return nil
 	}
 }
+
+// handles cursor presence updates, throttled per client and kept separate
+// from code updates so they never cause code broadcasts or database writes
+func CursorUpdateHandler() MessageHandler {
+	return func(_ *Hub, client *Client, msg *Message) error {
+		// only hosts and co-authors have a cursor to share
+		if !client.CanWrite() {
+			return nil
+		}
+
+		// silently ignore malformed presence updates
+		var payload CursorUpdatePayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			return nil
+		}
+
+		if payload.Line < 1 || payload.Col < 0 {
+			return nil
+		}
+
+		// enrich payload with sender information
+		payload.UserID = client.UserID
+		payload.DisplayName = client.DisplayName
+		payload.Role = client.Role
+
+		broadcastMsg, err := NewMessage(TypeCursorUpdate, client.SessionID, client.UserID, payload)
+		if err != nil {
+			return err
+		}
+
+		client.throttlePresence(broadcastMsg)
+
+		return nil
+	}
+}
+
+// handles selection presence updates, throttled per client like cursor updates
+func SelectionUpdateHandler() MessageHandler {
+	return func(_ *Hub, client *Client, msg *Message) error {
+		// only hosts and co-authors have a selection to share
+		if !client.CanWrite() {
+			return nil
+		}
+
+		// silently ignore malformed presence updates
+		var payload SelectionUpdatePayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			return nil
+		}
+
+		if len(payload.Ranges) > maxSelectionRanges {
+			return nil
+		}
+
+		for _, rng := range payload.Ranges {
+			if rng.AnchorLine < 1 || rng.HeadLine < 1 || rng.AnchorCol < 0 || rng.HeadCol < 0 {
+				return nil
+			}
+		}
+
+		// an empty list clears the selection for other clients
+		if payload.Ranges == nil {
+			payload.Ranges = []SelectionRange{}
+		}
+
+		// enrich payload with sender information
+		payload.UserID = client.UserID
+		payload.DisplayName = client.DisplayName
+		payload.Role = client.Role
+
+		broadcastMsg, err := NewMessage(TypeSelectionUpdate, client.SessionID, client.UserID, payload)
+		if err != nil {
+			return err
+		}
+
+		client.throttlePresence(broadcastMsg)
+
+		return nil
+	}
+}
+
+// handles resume messages from reconnecting clients
+func ResumeHandler() MessageHandler {
+	return func(hub *Hub, client *Client, msg *Message) error {
+		var payload ResumePayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			client.SendError(errors.CodeValidationError, "failed to parse resume request", err.Error())
+			return err
+		}
+
+		if err := hub.Resume(client, payload.LastSeq, payload.ClientID); err != nil {
+			if stderrors.Is(err, ErrNotResuming) {
+				client.SendError(errors.CodeBadRequest, "resume is only allowed on connections opened with resume=true", "")
+			}
+
+			return err
+		}
+
+		return nil
+	}
+}
+
+// handles AI generation requests, streaming the response back to the sender
+// as agent_response_chunk messages followed by agent_response_done
+func AgentRequestHandler(agentClient *agent.Agent, providers *llm.Registry, detector *ccsignals.Detector, signals CCSignalLookup, ragCache agent.RAGCache, quotas QuotaTracker) MessageHandler {
+	return func(hub *Hub, client *Client, msg *Message) error {
+		// AI output goes into the editor, so only writers may request it
+		if !client.CanWrite() {
+			client.SendError(errors.CodeForbidden, "you don't have permission to edit code", "")
+			return ErrReadOnly
+		}
+
+		if !client.Can(sessions.PermUseAgent) {
+			client.SendError(errors.CodeForbidden, "you don't have permission to use the AI assistant", "")
+			return ErrReadOnly
+		}
+
+		var payload AgentRequestPayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			client.SendError(errors.CodeValidationError, "failed to parse agent request", err.Error())
+			return err
+		}
+
+		requestID := &payload.RequestID
+		if payload.RequestID == "" {
+			requestID = nil
+		}
+
+		if !config.FeatureEnabled(config.FeatureAIGeneration) {
+			client.SendErrorWithRequestID(errors.CodeFeatureDisabled, "AI generation is temporarily disabled", "", requestID)
+			return nil
+		}
+
+		if strings.TrimSpace(payload.UserQuery) == "" {
+			client.SendErrorWithRequestID(errors.CodeBadRequest, "user_query is required", "", requestID)
+			return nil
+		}
+
+		// streaming requires BYOK (same as the REST streaming endpoint)
+		if payload.ProviderAPIKey == "" {
+			client.SendErrorWithRequestID(errors.CodeBYOKRequired, "AI features require your own API key. Add your API key in Settings to use AI assistance.", "", requestID)
+			return nil
+		}
+
+		if !client.startAgentRequest() {
+			client.SendErrorWithRequestID(errors.CodeTooManyRequests, "an agent request is already in progress", "", requestID)
+			return ErrAgentRequestInProgress
+		}
+		defer client.finishAgentRequest()
+
+		ctx, cancel := context.WithTimeout(msg.Context(), agentRequestTimeout)
+		defer cancel()
+
+		// paste lock validation (fail open on redis errors)
+		if detector != nil {
+			locked, err := detector.IsLocked(ctx, client.SessionID)
+			if err != nil {
+				logger.ErrorErr(err, "failed to check paste lock for agent request", "session_id", client.SessionID)
+			} else if locked {
+				client.SendErrorWithRequestID(errors.CodePasteLocked, "AI assistant temporarily disabled - please make significant edits to the pasted code before using AI.", "", requestID)
+				return nil
+			}
+		}
+
+		// block AI for forks from strudels with 'no-ai' signal
+		if payload.ForkedFromID != "" && signals != nil {
+			parentCCSignal, err := signals.GetStrudelCCSignal(ctx, payload.ForkedFromID)
+			if err != nil {
+				client.SendErrorWithRequestID(errors.CodeForbidden, "AI assistant disabled - the original strudel no longer exists or is invalid", "", requestID)
+				return nil
+			}
+
+			if parentCCSignal != nil && *parentCCSignal == strudels.CCSignalNoAI {
+				client.SendErrorWithRequestID(errors.CodeForbidden, "AI assistant disabled - original author restricted AI use for this strudel", "", requestID)
+				return nil
+			}
+		}
+
+		generator, err := providers.Generator(llm.Provider(payload.Provider), payload.Model, payload.ProviderAPIKey)
+		if err != nil {
+			client.SendErrorWithRequestID(errors.CodeBadRequest, "invalid provider or model", err.Error(), requestID)
+			return nil
+		}
+
+		// count the generation against the requester's quota (given back if it fails)
+		subject := quota.Subject{UserID: client.UserID, SessionID: client.SessionID, BYOK: true}
+		quotaResult := reserveAgentQuota(ctx, quotas, subject)
+		if quotaResult != nil && !quotaResult.Allowed {
+			client.SendErrorWithRequestID(errors.CodeRateLimitExceeded, quotaExceededMessage(quotaResult), "", requestID)
+			return nil
+		}
+		generated := false
+		defer func() {
+			if !generated && quotaResult != nil {
+				quotas.Release(context.WithoutCancel(ctx), subject)
+			}
+		}()
+
+		hub.countActivity(client.SessionID, activityAgentRequest)
+
+		history := make([]agent.Message, 0, len(payload.ConversationHistory))
+		for _, m := range payload.ConversationHistory {
+			if m.Content != "" {
+				history = append(history, agent.Message{Role: m.Role, Content: m.Content})
+			}
+		}
+
+		generateReq := agent.GenerateRequest{
+			UserQuery:           payload.UserQuery,
+			EditorState:         payload.EditorState,
+			ConversationHistory: history,
+			CustomGenerator:     generator,
+			SessionID:           client.SessionID,
+			RAGCache:            ragCache,
+		}
+		if quotaResult != nil {
+			generateReq.Tier = quotaResult.Tier
+		}
+
+		promptedAt := time.Now()
+		err = agentClient.GenerateStream(ctx, generateReq, func(event agent.StreamEvent) error {
+			switch event.Type {
+			case "chunk":
+				chunkMsg, err := NewMessage(TypeAgentResponseChunk, client.SessionID, client.UserID, AgentResponseChunkPayload{
+					RequestID: payload.RequestID,
+					Content:   event.Content,
+				})
+				if err != nil {
+					return err
+				}
+
+				// stops generation if the client has disconnected
+				return client.Send(chunkMsg)
+
+			case "done":
+				generated = true
+				if quotaResult != nil {
+					quotas.Record(ctx, subject, quota.Usage{
+						Provider:     payload.Provider,
+						Model:        event.Model,
+						InputTokens:  event.InputTokens,
+						OutputTokens: event.OutputTokens,
+					})
+				}
+
+				doneMsg, err := NewMessage(TypeAgentResponseDone, client.SessionID, client.UserID, AgentResponseDonePayload{
+					RequestID:         payload.RequestID,
+					Code:              event.Content,
+					IsCodeResponse:    event.IsCodeResponse,
+					Model:             event.Model,
+					InputTokens:       event.InputTokens,
+					OutputTokens:      event.OutputTokens,
+					StrudelReferences: event.StrudelReferences,
+					DocReferences:     event.DocReferences,
+					Cached:            event.Cached,
+					Degraded:          event.Degraded,
+					RateLimit:         newAgentRateLimit(quotaResult),
+				})
+				if err != nil {
+					return err
+				}
+
+				hub.recordAgentExchange(&sessions.AgentExchange{
+					SessionID:      client.SessionID,
+					UserID:         client.UserID,
+					DisplayName:    client.DisplayName,
+					Prompt:         payload.UserQuery,
+					Response:       event.Content,
+					IsCodeResponse: event.IsCodeResponse,
+					PromptedAt:     promptedAt,
+				})
+
+				return client.Send(doneMsg)
+			}
+
+			// references are sent again with the done event
+			return nil
+		})
+		if err != nil {
+			if stderrors.Is(err, ErrConnectionClosed) {
+				return nil
+			}
+
+			// agent requests always use the client's own key
+			if message := llm.UserMessage(err, true); message != "" {
+				client.SendErrorWithRequestID(providerErrorCode(llm.KindOf(err)), message, "", requestID)
+				return nil
+			}
+
+			logger.ErrorErr(err, "agent stream failed",
+				"client_id", client.ID,
+				"session_id", client.SessionID,
+			)
+			client.SendErrorWithRequestID(errors.CodeServerError, "failed to generate response", err.Error(), requestID)
+		}
+
+		return nil
+	}
+}
+
+// reserves an agent generation, failing open when the quota can't be checked.
+// returns nil when the request isn't metered.
+func reserveAgentQuota(ctx context.Context, quotas QuotaTracker, subject quota.Subject) *quota.Result {
+	if quotas == nil {
+		return nil
+	}
+
+	result, err := quotas.Reserve(ctx, subject)
+	if err != nil {
+		logger.ErrorErr(err, "failed to check agent quota", "user_id", subject.UserID, "session_id", subject.SessionID)
+		return nil
+	}
+
+	return result
+}
+
+// returns the error code of an agent request the provider failed
+func providerErrorCode(kind llm.ErrorKind) errors.Code {
+	switch kind {
+	case llm.ErrorRateLimited:
+		return errors.CodeProviderRateLimited
+	case llm.ErrorAuth, llm.ErrorInvalidRequest:
+		return errors.CodeProviderRejected
+	default:
+		return errors.CodeAIUnavailable
+	}
+}
+
+func quotaExceededMessage(result *quota.Result) string {
+	if result.Daily.Remaining == 0 {
+		return fmt.Sprintf("Daily AI limit reached (%d/%d). Try again tomorrow.", result.Daily.Used, result.Daily.Limit)
+	}
+
+	if result.Monthly.Remaining != 0 {
+		return fmt.Sprintf("Monthly AI spending limit reached ($%.2f/$%.2f). Try again next month.", result.Spend.UsedUSD, result.Spend.LimitUSD)
+	}
+
+	return fmt.Sprintf("Monthly AI limit reached (%d/%d). Try again next month.", result.Monthly.Used, result.Monthly.Limit)
+}
+
+func newAgentRateLimit(result *quota.Result) *AgentRateLimit {
+	if result == nil {
+		return nil
+	}
+
+	return &AgentRateLimit{
+		Tier:    result.Tier,
+		Daily:   result.Daily,
+		Monthly: result.Monthly,
+		Spend:   result.Spend,
+	}
+}
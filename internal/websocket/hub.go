@@ -1,9 +1,17 @@
 package websocket
 
 import (
+	"cmp"
+	"context"
+	"slices"
 	"time"
 
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/errors"
 	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+	"codeberg.org/algopatterns/server/internal/presence"
+	"codeberg.org/algopatterns/server/internal/tracing"
 )
 
 func NewHub() *Hub {
@@ -18,6 +26,15 @@ func NewHub() *Hub {
 		userConnections:  make(map[string]int),
 		ipConnections:    make(map[string]int),
 		sessionSequences: make(map[string]uint64),
+		documents:        make(map[string]*sessionDocument),
+		replays:          make(map[string]*replayBuffer),
+
+		audiences:             make(map[string]map[string]*Client),
+		audienceIPConnections: make(map[string]int),
+		listenerCounts:        make(map[string]int),
+		chats:                 make(map[string]*sessionChat),
+		transports:            make(map[string]*sessionTransport),
+		activity:              make(map[string]*sessionActivity),
 	}
 }
 
@@ -42,6 +59,32 @@ func (h *Hub) OnClientRegistered(callback func(client *Client)) {
 	h.onClientRegistered = callback
 }
 
+// sets callback to be called with every completed AI assistant request, for
+// session transcripts
+func (h *Hub) OnAgentResponse(callback func(exchange *sessions.AgentExchange)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onAgentResponse = callback
+}
+
+// hands a completed AI assistant request to the agent response callback
+func (h *Hub) recordAgentExchange(exchange *sessions.AgentExchange) {
+	h.mu.RLock()
+	callback := h.onAgentResponse
+	h.mu.RUnlock()
+
+	if callback != nil {
+		callback(exchange)
+	}
+}
+
+// sets the registry used to share connections with other server instances
+func (h *Hub) SetPresenceRegistry(registry PresenceRegistry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.presenceRegistry = registry
+}
+
 // starts the hub's main loop
 func (h *Hub) Run() {
 	h.running = true
@@ -49,6 +92,18 @@ func (h *Hub) Run() {
 		h.running = false
 	}()
 
+	snapshotTicker := time.NewTicker(documentSnapshotInterval)
+	defer snapshotTicker.Stop()
+
+	listenerCountTicker := time.NewTicker(listenerCountInterval)
+	defer listenerCountTicker.Stop()
+
+	activityTicker := time.NewTicker(activityInterval)
+	defer activityTicker.Stop()
+
+	rateLimitTicker := time.NewTicker(rateLimitInterval)
+	defer rateLimitTicker.Stop()
+
 	for {
 		select {
 		case client := <-h.Register:
@@ -60,6 +115,18 @@ func (h *Hub) Run() {
 		case message := <-h.Broadcast:
 			h.handleMessage(message)
 
+		case <-snapshotTicker.C:
+			go h.snapshotDocuments()
+
+		case <-listenerCountTicker.C:
+			h.broadcastListenerCounts()
+
+		case <-activityTicker.C:
+			go h.recordActivity()
+
+		case <-rateLimitTicker.C:
+			h.adjustRateLimits()
+
 		case <-h.shutdown:
 			h.closeAllConnections()
 			return
@@ -72,16 +139,33 @@ func (h *Hub) registerClient(client *Client) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if client.IsAudience() {
+		h.registerAudience(client)
+		return
+	}
+
 	if h.sessions[client.SessionID] == nil {
 		h.sessions[client.SessionID] = make(map[string]*Client)
 	}
 
 	h.sessions[client.SessionID][client.ID] = client
+	metrics.WebSocketConnections.Set(float64(len(h.sessions[client.SessionID])), client.SessionID)
 
 	if client.UserID != "" {
 		h.userConnections[client.UserID]++
 	}
 
+	if h.presenceRegistry != nil {
+		h.presenceRegistry.Track(presence.Connection{
+			ClientID:    client.ID,
+			SessionID:   client.SessionID,
+			UserID:      client.UserID,
+			DisplayName: client.DisplayName,
+			Role:        client.Role,
+			IPAddress:   client.IPAddress,
+		})
+	}
+
 	logger.Info("client registered",
 		"client_id", client.ID,
 		"session_id", client.SessionID,
@@ -90,32 +174,19 @@ func (h *Hub) registerClient(client *Client) {
 		"user_id", client.UserID,
 	)
 
-	// build participants list from connected clients (including the new client)
-	participants := make([]SessionStateParticipant, 0)
-
-	for _, c := range h.sessions[client.SessionID] {
-		participants = append(participants, SessionStateParticipant{
-			UserID:      c.UserID,
-			DisplayName: c.DisplayName,
-			Role:        c.Role,
-		})
-	}
-
-	// send session_state to connecting client
-	sessionStateMsg, err := NewMessage(TypeSessionState, client.SessionID, client.UserID, SessionStatePayload{
-		Code:            client.InitialCode,
-		YourRole:        client.Role,
-		YourDisplayName: client.DisplayName,
-		Participants:    participants,
-		ChatHistory:     client.InitialChatHistory,
-	})
-	if err == nil {
-		if sendErr := client.Send(sessionStateMsg); sendErr != nil {
-			logger.ErrorErr(sendErr, "failed to send session state",
-				"client_id", client.ID,
-				"session_id", client.SessionID,
-			)
-		}
+	// the first client of a session seeds the shared document, later clients
+	// receive the live document instead of their possibly stale initial code
+	h.ensureDocument(client.SessionID, client.InitialCode)
+	h.seedChat(client)
+
+	// resuming clients get session_state only if their missed messages can't be replayed
+	if client.DeferSessionState {
+		client.awaitingResume = true
+	} else if err := h.sendSessionState(client); err != nil {
+		logger.ErrorErr(err, "failed to send session state",
+			"client_id", client.ID,
+			"session_id", client.SessionID,
+		)
 	}
 
 	// broadcast user_joined to other clients in the session
@@ -134,12 +205,62 @@ func (h *Hub) registerClient(client *Client) {
 	}
 }
 
+// sends the full session state to a client (must be called with lock held)
+func (h *Hub) sendSessionState(client *Client) error {
+	// build participants list from connected clients (including the new client)
+	participants := make([]SessionStateParticipant, 0)
+
+	for _, c := range h.sessions[client.SessionID] {
+		participants = append(participants, c.participant())
+	}
+
+	// a session without chat sends an empty history, not null
+	chatHistory := client.InitialChatHistory
+	if chatHistory == nil {
+		chatHistory = []SessionStateChatMessage{}
+	}
+
+	document := h.ensureDocument(client.SessionID, client.InitialCode)
+
+	sessionStateMsg, err := NewMessage(TypeSessionState, client.SessionID, client.UserID, SessionStatePayload{
+		Code:            document.doc.Text(),
+		Revision:        document.doc.Revision(),
+		Seq:             h.sessionSequences[client.SessionID],
+		ClientID:        client.ID,
+		YourRole:        client.Role,
+		YourDisplayName: client.DisplayName,
+		YourPermissions: client.Permissions().Names(),
+		Participants:    participants,
+		ChatHistory:     chatHistory,
+		ListenerCount:   len(h.audiences[client.SessionID]),
+		RegionLocks:     h.regionLocks(client.SessionID),
+		ChatSlowMode:    h.ChatSlowMode(client.SessionID),
+		Muted:           client.IsMuted(),
+		Transport:       h.transportState(client.SessionID),
+		CodeUpdateRate:  client.codeUpdateRate(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return client.Send(sessionStateMsg)
+}
+
 // removes a client from the hub
 func (h *Hub) unregisterClient(client *Client) {
 	h.mu.Lock()
 
-	// capture callback reference under lock
+	// capture callback references under lock
 	callback := h.onClientDisconnect
+	snapshotCallback := h.onDocumentSnapshot
+	var finalCode string
+	var hasFinalCode bool
+
+	if client.IsAudience() {
+		h.unregisterAudience(client)
+		h.mu.Unlock()
+		return
+	}
 
 	sessionClients, exists := h.sessions[client.SessionID]
 	if !exists {
@@ -154,6 +275,11 @@ func (h *Hub) unregisterClient(client *Client) {
 
 	delete(sessionClients, client.ID)
 	client.Close()
+	metrics.WebSocketConnections.Set(float64(len(sessionClients)), client.SessionID)
+
+	if h.presenceRegistry != nil {
+		h.presenceRegistry.Untrack(client.ID)
+	}
 
 	if client.UserID != "" {
 		h.userConnections[client.UserID]--
@@ -178,12 +304,19 @@ func (h *Hub) unregisterClient(client *Client) {
 
 	if len(sessionClients) == 0 {
 		delete(h.sessions, client.SessionID)
+		metrics.WebSocketConnections.Delete(client.SessionID)
 		delete(h.sessionSequences, client.SessionID)
+		delete(h.replays, client.SessionID)
+		finalCode, hasFinalCode = h.removeDocument(client.SessionID)
+		h.removeChatIfEmpty(client.SessionID)
+		h.removeTransportIfEmpty(client.SessionID)
 
 		logger.Info("session has no more clients, removed",
 			"session_id", client.SessionID,
 		)
 	} else {
+		h.releaseClientLocks(client)
+
 		userLeftMsg, err := NewMessage(TypeUserLeft, client.SessionID, client.UserID, UserLeftPayload{
 			UserID:      client.UserID,
 			DisplayName: client.DisplayName,
@@ -195,6 +328,11 @@ func (h *Hub) unregisterClient(client *Client) {
 
 	h.mu.Unlock()
 
+	// persist unsaved document changes before the disconnect callback flushes
+	if hasFinalCode && snapshotCallback != nil {
+		snapshotCallback(client.SessionID, finalCode)
+	}
+
 	// call disconnect callback outside lock (may do DB operations)
 	if callback != nil {
 		callback(client)
@@ -205,17 +343,11 @@ func (h *Hub) unregisterClient(client *Client) {
 func (h *Hub) handleMessage(msg *Message) {
 	h.mu.RLock()
 
-	sessionClients, exists := h.sessions[msg.SessionID]
+	sender, exists := h.sessions[msg.SessionID][msg.ClientID]
 	if !exists {
-		h.mu.RUnlock()
-		logger.Warn("session not found for message",
-			"session_id", msg.SessionID,
-			"message_type", msg.Type,
-		)
-		return
+		sender, exists = h.audiences[msg.SessionID][msg.ClientID]
 	}
 
-	sender, exists := sessionClients[msg.ClientID]
 	h.mu.RUnlock()
 
 	if !exists {
@@ -227,6 +359,20 @@ func (h *Hub) handleMessage(msg *Message) {
 		return
 	}
 
+	// audiences only listen, apart from keeping the connection alive and
+	// their clock in sync. spectators may also chat
+	if sender.IsAudience() && msg.Type != TypePing && msg.Type != TypeClockSync {
+		if !sender.IsSpectator() {
+			sender.SendError(errors.CodeForbidden, "audience connections are read-only", "")
+			return
+		}
+
+		if !spectatorMessageTypes[msg.Type] {
+			sender.SendError(errors.CodeForbidden, "spectators can only chat", "")
+			return
+		}
+	}
+
 	h.mu.RLock()
 	handler, exists := h.handlers[msg.Type]
 	h.mu.RUnlock()
@@ -234,14 +380,33 @@ func (h *Hub) handleMessage(msg *Message) {
 	if exists {
 		// run handler asynchronously to avoid blocking the hub
 		go func() {
+			// each message is its own trace, a connection can live for hours
+			ctx, span := tracing.StartWithKind(context.Background(), "ws "+msg.Type, tracing.SpanKindServer,
+				tracing.String("ws.message_type", msg.Type),
+				tracing.String("ws.session_id", msg.SessionID),
+				tracing.String("ws.client_id", sender.ID),
+			)
+			defer span.End()
+
+			msg.ctx = ctx
+			errorsSent := sender.errorsSent.Load()
+
 			if err := handler(h, sender, msg); err != nil {
+				span.RecordError(err)
+
 				logger.ErrorErr(err, "handler error",
 					"message_type", msg.Type,
 					"client_id", sender.ID,
 					"session_id", msg.SessionID,
 				)
 
-				sender.SendError("server_error", "failed to process message", err.Error())
+				// handlers refusing a message say why themselves; a second,
+				// generic error would tell the client to retry it. a handler
+				// running alongside for the same client can hide a failure
+				// this way, which only costs the client the generic error
+				if sender.errorsSent.Load() == errorsSent {
+					sender.SendError(errors.CodeServerError, "failed to process message", err.Error())
+				}
 			}
 		}()
 	} else {
@@ -252,7 +417,7 @@ func (h *Hub) handleMessage(msg *Message) {
 			"session_id", msg.SessionID,
 		)
 
-		sender.SendError("bad_request", "unsupported message type", "message type not recognized")
+		sender.SendError(errors.CodeBadRequest, "unsupported message type", "message type not recognized")
 	}
 }
 
@@ -263,6 +428,29 @@ func (h *Hub) BroadcastToSession(sessionID string, msg *Message, excludeClientID
 	h.broadcastToSession(sessionID, msg, excludeClientID)
 }
 
+// sends a transient presence message (cursor, selection) to all clients in a
+// session. presence is not sequenced or kept for replay, since only the
+// latest state matters and it would crowd out messages that need replaying.
+func (h *Hub) BroadcastPresence(sessionID string, msg *Message, excludeClientID string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	metrics.WebSocketMessagesBroadcast.Inc(msg.Type)
+
+	for clientID, client := range h.sessions[sessionID] {
+		if clientID == excludeClientID || client.awaitingResume {
+			continue
+		}
+
+		if err := client.Send(msg); err != nil {
+			logger.ErrorErr(err, "failed to send presence message to client",
+				"client_id", clientID,
+				"session_id", sessionID,
+			)
+		}
+	}
+}
+
 // sends a message only to clients with write permissions (host and co-authors)
 func (h *Hub) BroadcastToWriters(sessionID string, msg *Message, excludeClientID string) {
 	h.mu.Lock()
@@ -280,9 +468,11 @@ func (h *Hub) broadcastToWriters(sessionID string, msg *Message, excludeClientID
 	// assign sequence number to message
 	h.sessionSequences[sessionID]++
 	msg.Sequence = h.sessionSequences[sessionID]
+	h.recordReplay(sessionID, msg, excludeClientID, true)
+	metrics.WebSocketMessagesBroadcast.Inc(msg.Type)
 
 	for clientID, client := range sessionClients {
-		if clientID == excludeClientID {
+		if clientID == excludeClientID || client.awaitingResume {
 			continue
 		}
 
@@ -304,28 +494,34 @@ func (h *Hub) broadcastToWriters(sessionID string, msg *Message, excludeClientID
 func (h *Hub) broadcastToSession(sessionID string, msg *Message, excludeClientID string) {
 	sessionClients, exists := h.sessions[sessionID]
 	if !exists {
+		// spectators may chat while the performers are connected elsewhere
+		h.broadcastToAudience(sessionID, msg)
 		return
 	}
 
 	// assign sequence number to message
 	h.sessionSequences[sessionID]++
 	msg.Sequence = h.sessionSequences[sessionID]
+	h.recordReplay(sessionID, msg, excludeClientID, false)
+	metrics.WebSocketMessagesBroadcast.Inc(msg.Type)
 
 	for clientID, client := range sessionClients {
-		if clientID == excludeClientID {
+		if clientID == excludeClientID || client.awaitingResume {
 			continue
 		}
 
-		if err := client.Send(msg); err != nil {
+		if err := h.sendTo(client, msg); err != nil {
 			logger.ErrorErr(err, "failed to send message to client",
 				"client_id", clientID,
 				"session_id", sessionID,
 			)
 		}
 	}
+
+	h.broadcastToAudience(sessionID, msg)
 }
 
-// returns all clients in a session
+// returns all clients in a session connected to this instance
 func (h *Hub) GetSessionClients(sessionID string) []*Client {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -363,12 +559,205 @@ func (h *Hub) GetSessionCount() int {
 	return len(h.sessions)
 }
 
-// IsSessionActive checks if a session has any active WebSocket connections
-func (h *Hub) IsSessionActive(sessionID string) bool {
+// returns everyone connected to a session, including clients of other server instances
+func (h *Hub) GetSessionParticipants(sessionID string) []SessionStateParticipant {
+	h.mu.RLock()
+	participants := make([]SessionStateParticipant, 0, len(h.sessions[sessionID]))
+
+	for _, c := range h.sessions[sessionID] {
+		participants = append(participants, c.participant())
+	}
+
+	registry := h.presenceRegistry
+	h.mu.RUnlock()
+
+	if registry == nil {
+		return participants
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceLookupTimeout)
+	defer cancel()
+
+	remote, err := registry.RemoteSessionConnections(ctx, sessionID)
+	if err != nil {
+		logger.Warn("failed to look up remote participants",
+			"session_id", sessionID,
+			"error", err,
+		)
+
+		return participants
+	}
+
+	for _, conn := range remote {
+		participants = append(participants, SessionStateParticipant{
+			UserID:      conn.UserID,
+			DisplayName: conn.DisplayName,
+			Role:        conn.Role,
+		})
+	}
+
+	return participants
+}
+
+// returns connection counts for this server instance, with the sessions that
+// have the most clients first
+func (h *Hub) Stats() HubStats {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
+
+	stats := HubStats{
+		Sessions:           len(h.sessions),
+		AuthenticatedUsers: len(h.userConnections),
+		UniqueIPs:          len(h.ipConnections),
+		BusiestSessions:    make([]SessionStats, 0, len(h.sessions)),
+	}
+
+	for _, audience := range h.audiences {
+		stats.Listeners += len(audience)
+	}
+
+	for sessionID, sessionClients := range h.sessions {
+		stats.Connections += len(sessionClients)
+		stats.BusiestSessions = append(stats.BusiestSessions, SessionStats{
+			SessionID: sessionID,
+			Clients:   len(sessionClients),
+		})
+	}
+
+	slices.SortFunc(stats.BusiestSessions, func(a, b SessionStats) int {
+		return cmp.Or(cmp.Compare(b.Clients, a.Clients), cmp.Compare(a.SessionID, b.SessionID))
+	})
+
+	if len(stats.BusiestSessions) > statsBusiestSessions {
+		stats.BusiestSessions = stats.BusiestSessions[:statsBusiestSessions]
+	}
+
+	return stats
+}
+
+// sends an error to every connection of a user on this instance and closes
+// them; the read pumps then unregister the clients as usual. returns the
+// number of connections closed.
+func (h *Hub) DisconnectUser(userID string, code errors.Code, message string) int {
+	if userID == "" {
+		return 0
+	}
+
+	h.mu.RLock()
+	var clients []*Client
+	for _, sessionClients := range h.sessions {
+		for _, client := range sessionClients {
+			if client.UserID == userID {
+				clients = append(clients, client)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		client.SendError(code, message, "")
+		client.Close()
+	}
+
+	if len(clients) > 0 {
+		logger.Info("disconnected user",
+			"user_id", userID,
+			"connections", len(clients),
+			"code", code,
+		)
+	}
+
+	return len(clients)
+}
+
+// applies changed permissions to the connections of a participant on this
+// instance and tells them with permissions_updated. nil goes back to the role
+// preset. returns the number of connections updated.
+func (h *Hub) SetParticipantPermissions(sessionID, participantID string, permissions *sessions.Permission) int {
+	if participantID == "" {
+		return 0
+	}
+
+	h.mu.RLock()
+	var clients []*Client
+	for _, client := range h.sessions[sessionID] {
+		if client.ParticipantID == participantID {
+			clients = append(clients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		client.SetPermissions(permissions)
+
+		msg, err := NewMessage(TypePermissionsUpdated, sessionID, client.UserID, PermissionsUpdatedPayload{
+			Permissions: client.Permissions().Names(),
+		})
+		if err != nil {
+			continue
+		}
+
+		client.Send(msg) //nolint:errcheck,gosec // best-effort, enforcement doesn't depend on it
+	}
+
+	return len(clients)
+}
+
+// sends a message to the host's connections to a session on this instance.
+// returns the number of connections it was sent to.
+func (h *Hub) SendToHost(sessionID, msgType string, payload any) int {
+	h.mu.RLock()
+	var hosts []*Client
+	for _, client := range h.sessions[sessionID] {
+		if client.Role == "host" {
+			hosts = append(hosts, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(hosts) == 0 {
+		return 0
+	}
+
+	msg, err := NewMessage(msgType, sessionID, "", payload)
+	if err != nil {
+		logger.ErrorErr(err, "failed to create host message", "session_id", sessionID, "type", msgType)
+		return 0
+	}
+
+	for _, client := range hosts {
+		client.Send(msg) //nolint:errcheck,gosec // best-effort, hosts are also notified in-app
+	}
+
+	return len(hosts)
+}
+
+// IsSessionActive checks if a session has any active WebSocket connections on any server instance
+func (h *Hub) IsSessionActive(sessionID string) bool {
+	h.mu.RLock()
 	sessionClients, exists := h.sessions[sessionID]
-	return exists && len(sessionClients) > 0
+	active := exists && len(sessionClients) > 0
+	registry := h.presenceRegistry
+	h.mu.RUnlock()
+
+	if active || registry == nil {
+		return active
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceLookupTimeout)
+	defer cancel()
+
+	remote, err := registry.RemoteSessionConnections(ctx, sessionID)
+	if err != nil {
+		logger.Warn("failed to look up remote session activity",
+			"session_id", sessionID,
+			"error", err,
+		)
+
+		return false
+	}
+
+	return len(remote) > 0
 }
 
 func (h *Hub) Shutdown() {
@@ -402,18 +791,46 @@ func (h *Hub) closeAllConnections() {
 		}
 	}
 
+	for sessionID, audience := range h.audiences {
+		shutdownMsg, err := NewMessage(TypeServerShutdown, sessionID, "", ServerShutdownPayload{
+			Reason: "server is shutting down for maintenance",
+		})
+		if err != nil {
+			continue
+		}
+
+		for _, client := range audience {
+			client.Send(shutdownMsg) //nolint:errcheck,gosec // best-effort notification
+		}
+	}
+
 	h.mu.Unlock()
 
 	// give clients time to receive the shutdown message
 	time.Sleep(500 * time.Millisecond)
 
 	h.mu.Lock()
-	defer h.mu.Unlock()
 
 	logger.Info("closing all websocket connections")
 
+	// collect unsaved documents before they are dropped, they are persisted
+	// once the lock is released
+	snapshotCallback := h.onDocumentSnapshot
+	unsaved := make(map[string]string)
+	for sessionID := range h.documents {
+		if code, dirty := h.removeDocument(sessionID); dirty {
+			unsaved[sessionID] = code
+		}
+	}
+
 	for sessionID, sessionClients := range h.sessions {
+		metrics.WebSocketConnections.Delete(sessionID)
+
 		for clientID, client := range sessionClients {
+			if h.presenceRegistry != nil {
+				h.presenceRegistry.Untrack(clientID)
+			}
+
 			client.Close()
 			logger.Debug("closed client",
 				"client_id", clientID,
@@ -422,29 +839,73 @@ func (h *Hub) closeAllConnections() {
 		}
 	}
 
+	for sessionID, audience := range h.audiences {
+		metrics.WebSocketListeners.Delete(sessionID)
+
+		for _, client := range audience {
+			client.Close()
+		}
+	}
+
 	// clear all sessions and connection tracking
 	h.sessions = make(map[string]map[string]*Client)
 	h.userConnections = make(map[string]int)
 	h.ipConnections = make(map[string]int)
 	h.sessionSequences = make(map[string]uint64)
+	h.documents = make(map[string]*sessionDocument)
+	h.replays = make(map[string]*replayBuffer)
+	h.audiences = make(map[string]map[string]*Client)
+	h.audienceIPConnections = make(map[string]int)
+	h.listenerCounts = make(map[string]int)
+
+	h.mu.Unlock()
+
+	// call snapshot callback outside lock (writes to the database)
+	if snapshotCallback != nil {
+		for sessionID, code := range unsaved {
+			snapshotCallback(sessionID, code)
+		}
+	}
 }
 
-// checks if a new connection should be allowed based on limits
+// checks if a new connection should be allowed based on limits. connections
+// held by other server instances count towards the limits when a presence
+// registry is set; if it can't be reached, only local connections are counted.
 func (h *Hub) CanAcceptConnection(userID, ipAddress string) (bool, string) {
 	h.mu.RLock()
-	defer h.mu.RUnlock()
+	userCount := h.userConnections[userID]
+	ipCount := h.ipConnections[ipAddress]
+	registry := h.presenceRegistry
+	h.mu.RUnlock()
 
-	// check per-user limit (only for authenticated users)
-	if userID != "" {
-		count := h.userConnections[userID]
-		if count >= maxConnectionsPerUser {
-			return false, "Maximum connections per user exceeded"
+	if registry != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), presenceLookupTimeout)
+		defer cancel()
+
+		if userID != "" && userCount < maxConnectionsPerUser {
+			remote, err := registry.RemoteUserConnectionCount(ctx, userID)
+			if err != nil {
+				logger.Warn("failed to look up remote user connections", "user_id", userID, "error", err)
+			}
+			userCount += remote
+		}
+
+		if ipCount < maxConnectionsPerIP {
+			remote, err := registry.RemoteIPConnectionCount(ctx, ipAddress)
+			if err != nil {
+				logger.Warn("failed to look up remote IP connections", "ip", ipAddress, "error", err)
+			}
+			ipCount += remote
 		}
 	}
 
+	// check per-user limit (only for authenticated users)
+	if userID != "" && userCount >= maxConnectionsPerUser {
+		return false, "Maximum connections per user exceeded"
+	}
+
 	// check per-IP limit
-	count := h.ipConnections[ipAddress]
-	if count >= maxConnectionsPerIP {
+	if ipCount >= maxConnectionsPerIP {
 		return false, "Maximum connections per IP address exceeded"
 	}
 
@@ -474,7 +935,9 @@ func (h *Hub) EndSession(sessionID string, reason string) {
 	h.mu.Lock()
 
 	sessionClients, exists := h.sessions[sessionID]
-	if !exists {
+	audience := h.audiences[sessionID]
+
+	if !exists && len(audience) == 0 {
 		h.mu.Unlock()
 		return
 	}
@@ -482,6 +945,7 @@ func (h *Hub) EndSession(sessionID string, reason string) {
 	logger.Info("ending session, notifying clients",
 		"session_id", sessionID,
 		"client_count", len(sessionClients),
+		"listener_count", len(audience),
 	)
 
 	// send session_ended notification to all clients
@@ -505,17 +969,25 @@ func (h *Hub) EndSession(sessionID string, reason string) {
 		}
 	}
 
+	for _, client := range audience {
+		client.Send(sessionEndedMsg) //nolint:errcheck,gosec // best-effort notification
+	}
+
 	h.mu.Unlock()
 
 	// give clients time to receive the message
 	time.Sleep(100 * time.Millisecond)
 
 	h.mu.Lock()
-	defer h.mu.Unlock()
+
+	for _, client := range h.audiences[sessionID] {
+		h.unregisterAudience(client)
+	}
 
 	// close all connections for this session
 	sessionClients, exists = h.sessions[sessionID]
 	if !exists {
+		h.mu.Unlock()
 		return
 	}
 
@@ -534,6 +1006,10 @@ func (h *Hub) EndSession(sessionID string, reason string) {
 			}
 		}
 
+		if h.presenceRegistry != nil {
+			h.presenceRegistry.Untrack(clientID)
+		}
+
 		client.Close()
 		logger.Debug("closed client due to session end",
 			"client_id", clientID,
@@ -543,7 +1019,20 @@ func (h *Hub) EndSession(sessionID string, reason string) {
 
 	// remove session from hub
 	delete(h.sessions, sessionID)
+	metrics.WebSocketConnections.Delete(sessionID)
 	delete(h.sessionSequences, sessionID)
+	delete(h.replays, sessionID)
+	finalCode, hasFinalCode := h.removeDocument(sessionID)
+	h.removeChatIfEmpty(sessionID)
+	h.removeTransportIfEmpty(sessionID)
+
+	snapshotCallback := h.onDocumentSnapshot
+	h.mu.Unlock()
+
+	// persist unsaved document changes outside lock
+	if hasFinalCode && snapshotCallback != nil {
+		snapshotCallback(sessionID, finalCode)
+	}
 
 	logger.Info("session ended and removed",
 		"session_id", sessionID,
@@ -1,13 +1,20 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"math"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/ot"
+	"codeberg.org/algopatterns/server/internal/presence"
 )
 
 func TestHubCreation(t *testing.T) {
@@ -383,6 +390,118 @@ func TestHubSessionCleanupAfterAllClientsLeave(t *testing.T) {
 	assert.Equal(t, 0, count)
 }
 
+func TestHubStats(t *testing.T) {
+	hub := NewHub()
+
+	go hub.Run()
+	defer hub.Shutdown()
+
+	clients := []*Client{
+		{ID: "client-1", SessionID: "session-a", UserID: "user-1", IPAddress: "10.0.0.1"},
+		{ID: "client-2", SessionID: "session-a", UserID: "user-2", IPAddress: "10.0.0.1"},
+		{ID: "client-3", SessionID: "session-a", IPAddress: "10.0.0.2"},
+		{ID: "client-4", SessionID: "session-b", UserID: "user-1", IPAddress: "10.0.0.1"},
+	}
+
+	for _, client := range clients {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.TrackIPConnection(client.IPAddress) // done by the handler before registering
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	stats := hub.Stats()
+	assert.Equal(t, 2, stats.Sessions)
+	assert.Equal(t, 4, stats.Connections)
+	assert.Equal(t, 2, stats.AuthenticatedUsers)
+	assert.Equal(t, 2, stats.UniqueIPs)
+	assert.Equal(t, []SessionStats{
+		{SessionID: "session-a", Clients: 3},
+		{SessionID: "session-b", Clients: 1},
+	}, stats.BusiestSessions)
+}
+
+func TestHubDisconnectUser(t *testing.T) {
+	hub := NewHub()
+
+	go hub.Run()
+	defer hub.Shutdown()
+
+	banned := []*Client{
+		{ID: "client-1", SessionID: "session-a", UserID: "user-1"},
+		{ID: "client-2", SessionID: "session-b", UserID: "user-1"},
+	}
+	other := &Client{ID: "client-3", SessionID: "session-a", UserID: "user-2"}
+
+	for _, client := range append(banned, other) {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, 2, hub.DisconnectUser("user-1", "forbidden", "account suspended"))
+
+	for _, client := range banned {
+		assert.True(t, client.IsClosed())
+
+		// the error is the last message queued before the channel closed
+		var last Message
+		for data := range client.send {
+			require.NoError(t, json.Unmarshal(data, &last))
+		}
+		assert.Equal(t, TypeError, last.Type)
+	}
+
+	assert.False(t, other.IsClosed())
+	assert.Equal(t, 0, hub.DisconnectUser("user-3", "forbidden", "account suspended"))
+}
+
+func TestHubSendToHost(t *testing.T) {
+	hub := NewHub()
+
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{ID: "client-1", SessionID: "session-a", UserID: "host", Role: "host"}
+	guest := &Client{ID: "client-2", SessionID: "session-a", UserID: "guest", Role: "viewer"}
+	elsewhere := &Client{ID: "client-3", SessionID: "session-b", UserID: "host", Role: "host"}
+
+	for _, client := range []*Client{host, guest, elsewhere} {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// drain session_state and presence messages from registration
+	for _, client := range []*Client{host, guest, elsewhere} {
+		for len(client.send) > 0 {
+			<-client.send
+		}
+	}
+
+	sent := hub.SendToHost("session-a", TypeJoinRequest, JoinRequestPayload{RequestID: "request-1", Status: "pending"})
+	assert.Equal(t, 1, sent)
+
+	require.Len(t, host.send, 1)
+	var msg Message
+	require.NoError(t, json.Unmarshal(<-host.send, &msg))
+	assert.Equal(t, TypeJoinRequest, msg.Type)
+
+	var payload JoinRequestPayload
+	require.NoError(t, json.Unmarshal(msg.Payload, &payload))
+	assert.Equal(t, "request-1", payload.RequestID)
+
+	assert.Empty(t, guest.send)
+	assert.Empty(t, elsewhere.send)
+	assert.Equal(t, 0, hub.SendToHost("session-c", TypeJoinRequest, JoinRequestPayload{}))
+}
+
 func TestHubConcurrentBroadcasts(t *testing.T) {
 	hub := NewHub()
 	go hub.Run()
@@ -457,3 +576,1345 @@ func TestHubConcurrentBroadcasts(t *testing.T) {
 	// hub explicitly shutdown at the end to avoid race with concurrent broadcasts
 	hub.Shutdown()
 }
+
+func TestHubCodeOperationsConverge(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{
+		ID:          "host",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "Host",
+		Role:        "host",
+		InitialCode: "sound(\"bd\")",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	coAuthor := &Client{
+		ID:          "co-author",
+		SessionID:   "session-1",
+		UserID:      "user-2",
+		DisplayName: "Co-author",
+		Role:        "co-author",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- host
+	hub.Register <- coAuthor
+	time.Sleep(100 * time.Millisecond)
+
+	// both writers edit revision 0 without seeing each other's change
+	_, err := hub.ApplyCodeOperation(host, 0, ot.Operation{}.Retain(9).Insert(" sd").Retain(2))
+	require.NoError(t, err)
+
+	change, err := hub.ApplyCodeOperation(coAuthor, 0, ot.Operation{}.Retain(11).Insert(".fast(2)"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, change.Revision)
+	assert.Equal(t, "sound(\"bd sd\").fast(2)", change.Code)
+
+	code, revision, ok := hub.GetDocument("session-1")
+	require.True(t, ok)
+	assert.Equal(t, change.Code, code)
+	assert.Equal(t, 2, revision)
+}
+
+func TestHubCodeOperationRejectsFutureRevision(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	client := &Client{
+		ID:          "client-1",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "User 1",
+		Role:        "host",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- client
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := hub.ApplyCodeOperation(client, 5, ot.Operation{}.Insert("x"))
+	assert.ErrorIs(t, err, ot.ErrRevisionInFuture)
+}
+
+func TestHubSnapshotsDirtyDocuments(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	var snapshotMu sync.Mutex
+	snapshots := make(map[string]string)
+
+	hub.OnDocumentSnapshot(func(sessionID, code string) {
+		snapshotMu.Lock()
+		defer snapshotMu.Unlock()
+		snapshots[sessionID] = code
+	})
+
+	client := &Client{
+		ID:          "client-1",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "User 1",
+		Role:        "host",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- client
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := hub.ReplaceCode(client, CodeUpdatePayload{Code: "note(\"c e g\")"})
+	require.NoError(t, err)
+
+	hub.snapshotDocuments()
+
+	snapshotMu.Lock()
+	assert.Equal(t, "note(\"c e g\")", snapshots["session-1"])
+	delete(snapshots, "session-1")
+	snapshotMu.Unlock()
+
+	// clean documents are not snapshotted again
+	hub.snapshotDocuments()
+
+	snapshotMu.Lock()
+	assert.Empty(t, snapshots)
+	snapshotMu.Unlock()
+}
+
+func TestHubSnapshotsOnEndAndShutdown(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	snapshots := make(chan string, 2)
+
+	// reading the hub from the callback deadlocks if it runs under the lock
+	hub.OnDocumentSnapshot(func(sessionID, code string) {
+		hub.GetSessionCount()
+		snapshots <- sessionID + ": " + code
+	})
+
+	for _, sessionID := range []string{"session-1", "session-2"} {
+		client := &Client{ID: "host-" + sessionID, SessionID: sessionID, UserID: "user-1", Role: "host", hub: hub, send: make(chan []byte, 256)}
+		hub.Register <- client
+		time.Sleep(50 * time.Millisecond)
+
+		_, err := hub.ReplaceCode(client, CodeUpdatePayload{Code: "note(\"c e g\")"})
+		require.NoError(t, err)
+	}
+
+	receive := func() string {
+		select {
+		case snapshot := <-snapshots:
+			return snapshot
+		case <-time.After(2 * time.Second):
+			t.Fatal("no snapshot")
+			return ""
+		}
+	}
+
+	// an ended session's unsaved code is persisted
+	hub.EndSession("session-1", "host ended the session")
+	assert.Equal(t, "session-1: note(\"c e g\")", receive())
+
+	// and so is every other session's when the server shuts down
+	hub.closeAllConnections()
+	assert.Equal(t, "session-2: note(\"c e g\")", receive())
+}
+
+func TestHubSessionSnapshotsAfterEdits(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	var snapshotMu sync.Mutex
+	var snapshots []string
+
+	hub.OnSessionSnapshot(func(_, code string) {
+		snapshotMu.Lock()
+		defer snapshotMu.Unlock()
+		snapshots = append(snapshots, code)
+	})
+
+	client := &Client{
+		ID:          "client-1",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "User 1",
+		Role:        "host",
+		hub:         hub,
+		send:        make(chan []byte, sessionSnapshotEdits+256),
+	}
+
+	hub.Register <- client
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := hub.ReplaceCode(client, CodeUpdatePayload{Code: "x"})
+	require.NoError(t, err)
+
+	// a few edits within the interval are not snapshotted yet
+	hub.snapshotDocuments()
+
+	snapshotMu.Lock()
+	assert.Empty(t, snapshots)
+	snapshotMu.Unlock()
+
+	for i := 1; i < sessionSnapshotEdits; i++ {
+		_, err := hub.ApplyCodeOperation(client, i, ot.Operation{}.Retain(i).Insert("x"))
+		require.NoError(t, err)
+	}
+
+	hub.snapshotDocuments()
+
+	snapshotMu.Lock()
+	require.Len(t, snapshots, 1)
+	assert.Len(t, snapshots[0], sessionSnapshotEdits)
+	snapshotMu.Unlock()
+}
+
+func TestHubRestoreCode(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	client := &Client{
+		ID:          "client-1",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "User 1",
+		Role:        "host",
+		InitialCode: "sound(\"bd\")",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	restored, err := hub.RestoreCode("session-1", "user-1", "note(\"c\")")
+	require.NoError(t, err)
+	assert.False(t, restored, "no document is open before a client joins")
+
+	hub.Register <- client
+	time.Sleep(100 * time.Millisecond)
+	<-client.send // session_state
+
+	restored, err = hub.RestoreCode("session-1", "user-1", "note(\"c\")")
+	require.NoError(t, err)
+	assert.True(t, restored)
+
+	code, _, ok := hub.GetDocument("session-1")
+	require.True(t, ok)
+	assert.Equal(t, "note(\"c\")", code)
+
+	// the host that restored receives the broadcast as well
+	select {
+	case received := <-client.send:
+		var msg Message
+		require.NoError(t, json.Unmarshal(received, &msg))
+		assert.Equal(t, TypeCodeUpdate, msg.Type)
+
+		var payload CodeUpdatePayload
+		require.NoError(t, msg.UnmarshalPayload(&payload))
+		assert.Equal(t, "restore", payload.Source)
+	case <-time.After(time.Second):
+		t.Fatal("client should have received code_update")
+	}
+}
+
+// in-memory CodeHistory with the same semantics as the redis buffer
+type fakeCodeHistory struct {
+	mu        sync.Mutex
+	revisions map[string][]string
+	redo      map[string][]string
+}
+
+func newFakeCodeHistory() *fakeCodeHistory {
+	return &fakeCodeHistory{
+		revisions: make(map[string][]string),
+		redo:      make(map[string][]string),
+	}
+}
+
+func (f *fakeCodeHistory) RecordCodeRevision(_ context.Context, sessionID, code string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	revisions := f.revisions[sessionID]
+	if len(revisions) > 0 && revisions[len(revisions)-1] == code {
+		return nil
+	}
+
+	f.revisions[sessionID] = append(revisions, code)
+	delete(f.redo, sessionID)
+
+	return nil
+}
+
+func (f *fakeCodeHistory) UndoCodeRevision(_ context.Context, sessionID string) (string, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	revisions := f.revisions[sessionID]
+	if len(revisions) < 2 {
+		return "", false, nil
+	}
+
+	f.redo[sessionID] = append(f.redo[sessionID], revisions[len(revisions)-1])
+	f.revisions[sessionID] = revisions[:len(revisions)-1]
+
+	return revisions[len(revisions)-2], true, nil
+}
+
+func (f *fakeCodeHistory) RedoCodeRevision(_ context.Context, sessionID string) (string, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	redo := f.redo[sessionID]
+	if len(redo) == 0 {
+		return "", false, nil
+	}
+
+	code := redo[len(redo)-1]
+	f.redo[sessionID] = redo[:len(redo)-1]
+	f.revisions[sessionID] = append(f.revisions[sessionID], code)
+
+	return code, true, nil
+}
+
+// reads messages from a client's send channel until one of the given type arrives
+func waitForMessage(t *testing.T, client *Client, msgType string) *Message {
+	t.Helper()
+
+	timeout := time.After(time.Second)
+
+	for {
+		select {
+		case data := <-client.send:
+			var msg Message
+			require.NoError(t, json.Unmarshal(data, &msg))
+
+			if msg.Type == msgType {
+				return &msg
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s message", msgType)
+			return nil
+		}
+	}
+}
+
+func TestHubUndoRedo(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	hub.SetCodeHistory(newFakeCodeHistory())
+
+	host := &Client{
+		ID:          "host",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "Host",
+		Role:        "host",
+		InitialCode: "sound(\"bd\")",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- host
+	time.Sleep(100 * time.Millisecond)
+
+	// checkpoint the initial code, then edit without snapshotting
+	hub.snapshotDocuments()
+
+	change, err := hub.ReplaceCode(host, CodeUpdatePayload{Code: "sound(\"bd sd\")"})
+	require.NoError(t, err)
+
+	undone, err := hub.UndoCode(host, change.Revision)
+	require.NoError(t, err)
+	assert.Equal(t, "sound(\"bd\")", undone.Code)
+
+	// the requesting client receives the restored code too
+	msg := waitForMessage(t, host, TypeCodeUpdate)
+
+	var payload CodeUpdatePayload
+	require.NoError(t, msg.UnmarshalPayload(&payload))
+	assert.Equal(t, "sound(\"bd\")", payload.Code)
+	assert.Equal(t, "undo", payload.Source)
+	assert.Equal(t, undone.Revision, payload.Revision)
+
+	redone, err := hub.RedoCode(host, undone.Revision)
+	require.NoError(t, err)
+	assert.Equal(t, "sound(\"bd sd\")", redone.Code)
+
+	_, err = hub.RedoCode(host, redone.Revision)
+	assert.ErrorIs(t, err, ErrNothingToRedo)
+
+	code, _, ok := hub.GetDocument("session-1")
+	require.True(t, ok)
+	assert.Equal(t, "sound(\"bd sd\")", code)
+}
+
+func TestHubUndoRejectsStaleRevision(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	hub.SetCodeHistory(newFakeCodeHistory())
+
+	host := &Client{
+		ID:          "host",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "Host",
+		Role:        "host",
+		InitialCode: "sound(\"bd\")",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	coAuthor := &Client{
+		ID:          "co-author",
+		SessionID:   "session-1",
+		UserID:      "user-2",
+		DisplayName: "Co-author",
+		Role:        "co-author",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- host
+	hub.Register <- coAuthor
+	time.Sleep(100 * time.Millisecond)
+
+	hub.snapshotDocuments()
+
+	seen, err := hub.ReplaceCode(host, CodeUpdatePayload{Code: "sound(\"bd sd\")"})
+	require.NoError(t, err)
+
+	// the co-author edits before the host's undo arrives
+	_, err = hub.ApplyCodeOperation(coAuthor, seen.Revision, ot.Operation{}.Retain(14).Insert(".fast(2)"))
+	require.NoError(t, err)
+
+	_, err = hub.UndoCode(host, seen.Revision)
+	assert.ErrorIs(t, err, ErrRevisionConflict)
+
+	code, _, ok := hub.GetDocument("session-1")
+	require.True(t, ok)
+	assert.Equal(t, "sound(\"bd sd\").fast(2)", code)
+}
+
+func TestHubUndoWithoutHistory(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	client := &Client{
+		ID:          "client-1",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "User 1",
+		Role:        "host",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- client
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := hub.UndoCode(client, 0)
+	assert.ErrorIs(t, err, ErrHistoryUnavailable)
+
+	hub.SetCodeHistory(newFakeCodeHistory())
+
+	_, err = hub.UndoCode(client, 0)
+	assert.ErrorIs(t, err, ErrNothingToUndo)
+}
+
+// in-memory PresenceRegistry; remote holds connections of other instances
+type fakePresenceRegistry struct {
+	mu      sync.Mutex
+	tracked map[string]presence.Connection
+	remote  []presence.Connection
+	err     error
+}
+
+func newFakePresenceRegistry(remote ...presence.Connection) *fakePresenceRegistry {
+	return &fakePresenceRegistry{
+		tracked: make(map[string]presence.Connection),
+		remote:  remote,
+	}
+}
+
+func (f *fakePresenceRegistry) Track(conn presence.Connection) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tracked[conn.ClientID] = conn
+}
+
+func (f *fakePresenceRegistry) Untrack(clientID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.tracked, clientID)
+}
+
+func (f *fakePresenceRegistry) isTracked(clientID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, tracked := f.tracked[clientID]
+	return tracked
+}
+
+func (f *fakePresenceRegistry) RemoteSessionConnections(_ context.Context, sessionID string) ([]presence.Connection, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var conns []presence.Connection
+	for _, conn := range f.remote {
+		if conn.SessionID == sessionID {
+			conns = append(conns, conn)
+		}
+	}
+
+	return conns, nil
+}
+
+func (f *fakePresenceRegistry) RemoteUserConnectionCount(_ context.Context, userID string) (int, error) {
+	return f.count(func(conn presence.Connection) bool { return conn.UserID == userID })
+}
+
+func (f *fakePresenceRegistry) RemoteIPConnectionCount(_ context.Context, ipAddress string) (int, error) {
+	return f.count(func(conn presence.Connection) bool { return conn.IPAddress == ipAddress })
+}
+
+func (f *fakePresenceRegistry) count(match func(presence.Connection) bool) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+
+	count := 0
+	for _, conn := range f.remote {
+		if match(conn) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func TestHubPresenceRegistryTracksClients(t *testing.T) {
+	hub := NewHub()
+	registry := newFakePresenceRegistry()
+	hub.SetPresenceRegistry(registry)
+	go hub.Run()
+	defer hub.Shutdown()
+
+	client := &Client{
+		ID:          "client-1",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "User 1",
+		Role:        "host",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- client
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, registry.isTracked("client-1"))
+
+	hub.Unregister <- client
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, registry.isTracked("client-1"))
+}
+
+func TestHubCountsRemoteConnections(t *testing.T) {
+	hub := NewHub()
+
+	remote := make([]presence.Connection, 0, maxConnectionsPerUser)
+	for i := 0; i < maxConnectionsPerUser; i++ {
+		remote = append(remote, presence.Connection{
+			ClientID:    "remote-" + string(rune('a'+i)),
+			SessionID:   "remote-session",
+			UserID:      "user-1",
+			DisplayName: "User 1",
+			Role:        "co-author",
+			IPAddress:   "10.0.0.1",
+			InstanceID:  "other-instance",
+		})
+	}
+
+	hub.SetPresenceRegistry(newFakePresenceRegistry(remote...))
+
+	ok, _ := hub.CanAcceptConnection("user-1", "10.0.0.2")
+	assert.False(t, ok, "user limit should include connections on other instances")
+
+	ok, _ = hub.CanAcceptConnection("user-2", "10.0.0.1")
+	assert.True(t, ok)
+
+	assert.True(t, hub.IsSessionActive("remote-session"))
+	assert.False(t, hub.IsSessionActive("other-session"))
+
+	participants := hub.GetSessionParticipants("remote-session")
+	assert.Len(t, participants, maxConnectionsPerUser)
+	assert.Equal(t, "User 1", participants[0].DisplayName)
+
+	// remote clients can't be messaged from this instance
+	assert.Empty(t, hub.GetSessionClients("remote-session"))
+}
+
+func TestHubPresenceRegistryUnavailable(t *testing.T) {
+	hub := NewHub()
+	registry := newFakePresenceRegistry(presence.Connection{
+		ClientID:   "remote-a",
+		SessionID:  "remote-session",
+		UserID:     "user-1",
+		InstanceID: "other-instance",
+	})
+	registry.err = errors.New("redis unavailable")
+	hub.SetPresenceRegistry(registry)
+
+	// falls back to local connections only
+	ok, _ := hub.CanAcceptConnection("user-1", "10.0.0.1")
+	assert.True(t, ok)
+	assert.False(t, hub.IsSessionActive("remote-session"))
+	assert.Empty(t, hub.GetSessionParticipants("remote-session"))
+}
+
+func TestHubAudienceReceivesCodeOnly(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{
+		ID:          "host",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "Host",
+		Role:        "host",
+		InitialCode: "sound(\"bd\")",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	listener := &Client{
+		ID:          "listener",
+		SessionID:   "session-1",
+		DisplayName: "Listener",
+		Role:        "audience",
+		IPAddress:   "203.0.113.1",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- host
+	hub.Register <- listener
+	time.Sleep(100 * time.Millisecond)
+
+	// listeners are counted, not listed
+	assert.Len(t, hub.GetSessionParticipants("session-1"), 1)
+	assert.Equal(t, 1, hub.ListenerCount("session-1"))
+	assert.Equal(t, 1, hub.Stats().Listeners)
+
+	var state Message
+	require.NoError(t, json.Unmarshal(<-listener.send, &state))
+	assert.Equal(t, TypeSessionState, state.Type)
+
+	var statePayload SessionStatePayload
+	require.NoError(t, state.UnmarshalPayload(&statePayload))
+	assert.Equal(t, "sound(\"bd\")", statePayload.Code)
+	assert.Equal(t, 1, statePayload.ListenerCount)
+	assert.Empty(t, statePayload.ChatHistory)
+
+	// presence is not forwarded to the audience
+	presenceMsg, err := NewMessage(TypeCursorPosition, "session-1", "user-1", map[string]int{"line": 1})
+	require.NoError(t, err)
+	hub.BroadcastToSession("session-1", presenceMsg, "host")
+
+	// code_op arrives as a code_update with the whole document
+	_, err = hub.ApplyCodeOperation(host, 0, ot.Operation{}.Retain(9).Insert(" sd").Retain(2))
+	require.NoError(t, err)
+
+	select {
+	case received := <-listener.send:
+		var msg Message
+		require.NoError(t, json.Unmarshal(received, &msg))
+		assert.Equal(t, TypeCodeUpdate, msg.Type)
+		assert.Zero(t, msg.Sequence)
+
+		var payload CodeUpdatePayload
+		require.NoError(t, msg.UnmarshalPayload(&payload))
+		assert.Equal(t, "sound(\"bd sd\")", payload.Code)
+		assert.Equal(t, 1, payload.Revision)
+	case <-time.After(time.Second):
+		t.Fatal("listener should have received code_update")
+	}
+
+	hub.Unregister <- listener
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, 0, hub.ListenerCount("session-1"))
+}
+
+func TestHubEmbedDoesNotSeeParticipants(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{
+		ID:          "host",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "Host",
+		Role:        "host",
+		InitialCode: "sound(\"bd\")",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	embed := &Client{
+		ID:          "embed",
+		SessionID:   "session-1",
+		DisplayName: "Embed",
+		Role:        "embed",
+		IPAddress:   "203.0.113.1",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- host
+	hub.Register <- embed
+	time.Sleep(100 * time.Millisecond)
+
+	assert.True(t, embed.IsAudience())
+	assert.Equal(t, 1, hub.ListenerCount("session-1"))
+
+	var state Message
+	require.NoError(t, json.Unmarshal(<-embed.send, &state))
+	assert.Equal(t, TypeSessionState, state.Type)
+
+	var statePayload SessionStatePayload
+	require.NoError(t, state.UnmarshalPayload(&statePayload))
+	assert.Equal(t, "sound(\"bd\")", statePayload.Code)
+	assert.Empty(t, statePayload.Participants)
+	assert.Empty(t, statePayload.ChatHistory)
+}
+
+func TestHubAudienceIsReadOnly(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	listener := &Client{
+		ID:          "listener",
+		SessionID:   "session-1",
+		DisplayName: "Listener",
+		Role:        "audience",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- listener
+	time.Sleep(100 * time.Millisecond)
+	<-listener.send // session_state
+
+	msg, err := NewMessage(TypeChatMessage, "session-1", "", map[string]string{"message": "hi"})
+	require.NoError(t, err)
+	msg.ClientID = listener.ID
+	hub.Broadcast <- msg
+
+	select {
+	case received := <-listener.send:
+		var reply Message
+		require.NoError(t, json.Unmarshal(received, &reply))
+		assert.Equal(t, TypeError, reply.Type)
+	case <-time.After(time.Second):
+		t.Fatal("listener should have received an error")
+	}
+}
+
+func TestHubRegionLocks(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{
+		ID:          "host",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "Host",
+		Role:        "host",
+		InitialCode: "a\nb\nc\nd",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	coAuthor := &Client{
+		ID:          "co-author",
+		SessionID:   "session-1",
+		UserID:      "user-2",
+		DisplayName: "Co-author",
+		Role:        "co-author",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- host
+	hub.Register <- coAuthor
+	time.Sleep(100 * time.Millisecond)
+
+	lock, err := hub.LockRegion(host, 2, 3, "")
+	require.NoError(t, err)
+
+	_, err = hub.LockRegion(coAuthor, 3, 4, "")
+	assert.ErrorIs(t, err, ErrRegionLocked)
+
+	// editing a locked line is rejected, adding a line above the region is not
+	_, err = hub.ApplyCodeOperation(coAuthor, 0, ot.Operation{}.Retain(2).Insert("x").Retain(5))
+	assert.ErrorIs(t, err, ErrRegionLocked)
+
+	_, err = hub.ApplyCodeOperation(coAuthor, 0, ot.Operation{}.Retain(2).Insert("x\n").Retain(5))
+	require.NoError(t, err)
+
+	// the holder can edit, and the lock moved with its lines
+	change, err := hub.ApplyCodeOperation(host, 1, ot.Operation{}.Retain(4).Insert("b").Retain(5))
+	require.NoError(t, err)
+	assert.Equal(t, "a\nx\nbb\nc\nd", change.Code)
+
+	_, err = hub.ReplaceCode(coAuthor, CodeUpdatePayload{Code: "a\nx\nbb\nC\nd"})
+	assert.ErrorIs(t, err, ErrRegionLocked)
+
+	hub.mu.RLock()
+	locks := hub.regionLocks("session-1")
+	hub.mu.RUnlock()
+	require.Len(t, locks, 1)
+	assert.Equal(t, 3, locks[0].StartLine)
+	assert.Equal(t, 4, locks[0].EndLine)
+
+	// only the holder or the host can release a lock
+	assert.ErrorIs(t, hub.UnlockRegion(coAuthor, lock.ID), ErrRegionLockNotFound)
+	require.NoError(t, hub.UnlockRegion(host, lock.ID))
+
+	_, err = hub.ReplaceCode(coAuthor, CodeUpdatePayload{Code: "a\nx\nbb\nC\nd"})
+	require.NoError(t, err)
+}
+
+func TestHubRegionLocksReleasedOnDisconnect(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{
+		ID:          "host",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "Host",
+		Role:        "host",
+		InitialCode: "drums: s(\"bd\")\nbass: note(\"c2\")",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	coAuthor := &Client{
+		ID:          "co-author",
+		SessionID:   "session-1",
+		UserID:      "user-2",
+		DisplayName: "Co-author",
+		Role:        "co-author",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- host
+	hub.Register <- coAuthor
+	time.Sleep(100 * time.Millisecond)
+
+	lock, err := hub.LockRegion(coAuthor, 0, 0, "bass")
+	require.NoError(t, err)
+	assert.Equal(t, 2, lock.StartLine)
+	assert.Equal(t, 2, lock.EndLine)
+
+	_, err = hub.LockRegion(coAuthor, 0, 0, "lead")
+	assert.ErrorIs(t, err, ErrBlockNotFound)
+
+	hub.Unregister <- coAuthor
+	time.Sleep(100 * time.Millisecond)
+
+	hub.mu.RLock()
+	locks := hub.regionLocks("session-1")
+	hub.mu.RUnlock()
+	assert.Empty(t, locks)
+}
+
+func TestHubSpectatorReceivesChat(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{ID: "host", SessionID: "session-1", UserID: "user-1", Role: "host", ChatSlowMode: 30}
+	spectator := &Client{ID: "spectator", SessionID: "session-1", UserID: "user-2", Role: "spectator", IPAddress: "203.0.113.1", ChatSlowMode: 30}
+	listener := &Client{ID: "listener", SessionID: "session-1", Role: "audience", IPAddress: "203.0.113.2"}
+
+	for _, client := range []*Client{host, spectator, listener} {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// spectators are audience members who can chat
+	assert.True(t, spectator.IsAudience())
+	assert.True(t, spectator.Can(sessions.PermChat))
+	assert.Len(t, hub.GetSessionParticipants("session-1"), 1)
+	assert.Equal(t, 2, hub.ListenerCount("session-1"))
+
+	var statePayload SessionStatePayload
+	require.NoError(t, waitForMessage(t, spectator, TypeSessionState).UnmarshalPayload(&statePayload))
+	assert.Equal(t, []string{"chat"}, statePayload.YourPermissions)
+	assert.Equal(t, 30, statePayload.ChatSlowMode)
+	<-listener.send // session_state
+
+	msg, err := NewMessage(TypeChatMessage, "session-1", "user-1", ChatMessagePayload{Message: "hi"})
+	require.NoError(t, err)
+	hub.BroadcastToSession("session-1", msg, "")
+
+	waitForMessage(t, spectator, TypeChatMessage)
+	assert.Empty(t, listener.send)
+
+	hub.SetChatSlowMode("session-1", 0)
+	require.NoError(t, waitForMessage(t, spectator, TypeChatSettings).UnmarshalPayload(&statePayload))
+	assert.Equal(t, 0, hub.ChatSlowMode("session-1"))
+	assert.Empty(t, listener.send)
+}
+
+func TestHubChatSlowMode(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{ID: "host", SessionID: "session-1", UserID: "user-1", Role: "host", ChatSlowMode: 60}
+	viewer := &Client{ID: "viewer", SessionID: "session-1", UserID: "user-2", Role: "viewer", ChatSlowMode: 60}
+	anonymous := &Client{ID: "anonymous", SessionID: "session-1", ParticipantID: "participant-3", Role: "viewer", ChatSlowMode: 60}
+
+	for _, client := range []*Client{host, viewer, anonymous} {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, 60, hub.ChatSlowMode("session-1"))
+
+	// each user gets one message per minute, the host isn't slowed down
+	assert.Zero(t, hub.reserveChatMessage(viewer))
+	assert.Greater(t, hub.reserveChatMessage(viewer), 59*time.Second)
+	assert.Zero(t, hub.reserveChatMessage(anonymous))
+	assert.Zero(t, hub.reserveChatMessage(host))
+	assert.Zero(t, hub.reserveChatMessage(host))
+
+	hub.SetChatSlowMode("session-1", 0)
+	assert.Zero(t, hub.reserveChatMessage(viewer))
+
+	// the chat state goes away with the session
+	for _, client := range []*Client{host, viewer, anonymous} {
+		hub.Unregister <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	hub.chatMu.Lock()
+	assert.Empty(t, hub.chats)
+	hub.chatMu.Unlock()
+}
+
+func TestHubSetParticipantMuted(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	viewer := &Client{ID: "viewer", SessionID: "session-1", UserID: "user-2", ParticipantID: "participant-2", Role: "viewer"}
+	spectator := &Client{ID: "spectator", SessionID: "session-1", UserID: "user-3", ParticipantID: "participant-3", Role: "spectator", IPAddress: "203.0.113.1"}
+
+	for _, client := range []*Client{viewer, spectator} {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, 1, hub.SetParticipantMuted("session-1", "participant-3", true))
+	assert.True(t, spectator.IsMuted())
+	assert.False(t, viewer.IsMuted())
+
+	var payload ChatMutedPayload
+	require.NoError(t, waitForMessage(t, spectator, TypeChatMuted).UnmarshalPayload(&payload))
+	assert.True(t, payload.Muted)
+
+	assert.Equal(t, 1, hub.SetParticipantMuted("session-1", "participant-3", false))
+	assert.False(t, spectator.IsMuted())
+	assert.Equal(t, 0, hub.SetParticipantMuted("session-1", "", true))
+}
+
+func TestHubDrain(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{ID: "host", SessionID: "session-1", UserID: "user-1", Role: "host"}
+	listener := &Client{ID: "listener", SessionID: "session-1", Role: "audience", IPAddress: "203.0.113.1"}
+
+	for _, client := range []*Client{host, listener} {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	hub.BroadcastToSession("session-1", &Message{Type: TypePlay, SessionID: "session-1"}, "")
+	lastSeq := waitForMessage(t, host, TypePlay).Sequence
+
+	assert.False(t, hub.IsDraining())
+	assert.Equal(t, 2, hub.Drain(time.Second))
+	assert.True(t, hub.IsDraining())
+
+	var payload ReconnectHintPayload
+	require.NoError(t, waitForMessage(t, host, TypeReconnectHint).UnmarshalPayload(&payload))
+	assert.Equal(t, "host", payload.ClientID)
+	assert.Equal(t, lastSeq, payload.Seq)
+	assert.Less(t, payload.BackoffMs, int64(1000))
+
+	waitForMessage(t, listener, TypeReconnectHint)
+
+	// draining again doesn't hint twice
+	assert.Equal(t, 0, hub.Drain(time.Second))
+}
+
+func TestHubWaitForDrain(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	client := &Client{ID: "client-1", SessionID: "session-1", UserID: "user-1", Role: "host", send: make(chan []byte, 256)}
+	client.hub = hub
+	hub.Register <- client
+
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	assert.False(t, hub.WaitForDrain(ctx))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		hub.Unregister <- client
+	}()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	assert.True(t, hub.WaitForDrain(ctx))
+}
+
+func TestHubTransport(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{ID: "host", SessionID: "session-1", UserID: "user-1", Role: "host"}
+	listener := &Client{ID: "listener", SessionID: "session-1", Role: "audience", IPAddress: "203.0.113.1"}
+
+	for _, client := range []*Client{host, listener} {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// playback starts on cycle 0 a little ahead, so every client hears about it
+	started := hub.StartTransport("session-1", 0, "Host")
+	assert.True(t, started.Playing)
+	assert.Equal(t, float64(defaultCPM), started.CPM)
+	assert.Zero(t, started.OriginCycle)
+	assert.InDelta(t, transportLead.Milliseconds(), started.OriginTime-started.ServerTime, 1)
+
+	var payload TransportSyncPayload
+	require.NoError(t, waitForMessage(t, host, TypeTransportSync).UnmarshalPayload(&payload))
+	assert.Equal(t, started.OriginTime, payload.OriginTime)
+	waitForMessage(t, listener, TypeTransportSync)
+
+	// a tempo change lands on a whole cycle, at the time the old tempo reaches it
+	changed := hub.StartTransport("session-1", 120, "Host")
+	assert.Equal(t, float64(120), changed.CPM)
+	assert.Equal(t, changed.OriginCycle, math.Trunc(changed.OriginCycle))
+	assert.InDelta(t, started.OriginTime+changed.OriginCycle*2000, changed.OriginTime, 1)
+
+	assert.Equal(t, changed.OriginTime, hub.transportState("session-1").OriginTime)
+
+	stopped := hub.StopTransport("session-1", "Host")
+	assert.False(t, stopped.Playing)
+	assert.False(t, hub.transportState("session-1").Playing)
+
+	// the clock goes away with the session
+	hub.StartTransport("session-1", 0, "Host")
+	for _, client := range []*Client{host, listener} {
+		hub.Unregister <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	hub.transportMu.Lock()
+	assert.Empty(t, hub.transports)
+	hub.transportMu.Unlock()
+}
+
+func TestHubEndSessionForgetsSessionState(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{ID: "host", SessionID: "session-1", UserID: "user-1", Role: "host", ChatSlowMode: 30}
+	listener := &Client{ID: "listener", SessionID: "session-1", Role: "audience", IPAddress: "203.0.113.1"}
+
+	for _, client := range []*Client{host, listener} {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// pretend playback started a few seconds ago
+	hub.StartTransport("session-1", 0, "Host")
+	hub.transportMu.Lock()
+	hub.transports["session-1"].countedUntil = time.Now().Add(-3 * time.Second)
+	hub.transportMu.Unlock()
+
+	hub.EndSession("session-1", "host ended the session")
+
+	assert.False(t, hub.IsSessionActive("session-1"))
+
+	hub.chatMu.Lock()
+	assert.Empty(t, hub.chats)
+	hub.chatMu.Unlock()
+
+	hub.transportMu.Lock()
+	assert.Empty(t, hub.transports)
+	hub.transportMu.Unlock()
+
+	// the playback up to the end is still counted
+	hub.activityMu.Lock()
+	require.Contains(t, hub.activity, "session-1")
+	assert.InDelta(t, 3, hub.activity["session-1"].playback.Seconds(), 0.5)
+	hub.activityMu.Unlock()
+}
+
+func TestClockSyncHandler(t *testing.T) {
+	client := &Client{ID: "listener", SessionID: "session-1", Role: "audience", send: make(chan []byte, 1)}
+
+	msg, err := NewMessage(TypeClockSync, "session-1", "", ClockSyncPayload{ClientTime: 1234.5})
+	require.NoError(t, err)
+
+	require.NoError(t, ClockSyncHandler()(nil, client, msg))
+
+	var reply ClockSyncPayload
+	require.NoError(t, waitForMessage(t, client, TypeClockSync).UnmarshalPayload(&reply))
+	assert.Equal(t, 1234.5, reply.ClientTime)
+	assert.Equal(t, unixMillis(msg.Timestamp), reply.ServerReceive)
+	assert.GreaterOrEqual(t, reply.ServerSend, reply.ServerReceive)
+}
+
+type fakeActivityRecorder struct {
+	mu      sync.Mutex
+	samples map[string]*sessions.ActivitySample
+}
+
+func (f *fakeActivityRecorder) RecordActivity(_ context.Context, sample *sessions.ActivitySample) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.samples[sample.SessionID] = sample
+	return nil
+}
+
+func TestHubRecordActivity(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	recorder := &fakeActivityRecorder{samples: make(map[string]*sessions.ActivitySample)}
+	hub.SetActivityRecorder(recorder)
+
+	host := &Client{ID: "host", SessionID: "session-1", UserID: "user-1", Role: "host"}
+	coAuthor := &Client{ID: "co-author", SessionID: "session-1", UserID: "user-2", Role: "co-author"}
+	listener := &Client{ID: "listener", SessionID: "session-1", Role: "audience", IPAddress: "203.0.113.1"}
+
+	for _, client := range []*Client{host, coAuthor, listener} {
+		client.hub = hub
+		client.send = make(chan []byte, 256)
+		hub.Register <- client
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := hub.ReplaceCode(host, CodeUpdatePayload{Code: "sound(\"bd\")"})
+	require.NoError(t, err)
+	hub.countActivity("session-1", activityChatMessage)
+	hub.countActivity("session-1", activityChatMessage)
+	hub.countActivity("session-1", activityAgentRequest)
+
+	// pretend playback started a few seconds ago
+	hub.StartTransport("session-1", 0, "Host")
+	hub.transportMu.Lock()
+	hub.transports["session-1"].countedUntil = time.Now().Add(-3 * time.Second)
+	hub.transportMu.Unlock()
+
+	hub.recordActivity()
+
+	sample := recorder.samples["session-1"]
+	require.NotNil(t, sample)
+	assert.Equal(t, 2, sample.Participants)
+	assert.Equal(t, 1, sample.Listeners)
+	assert.Equal(t, 1, sample.CodeEdits)
+	assert.Equal(t, 2, sample.ChatMessages)
+	assert.Equal(t, 1, sample.AgentRequests)
+	assert.InDelta(t, 3, sample.PlaybackSeconds, 0.5)
+	assert.Equal(t, sample.BucketStart, sample.BucketStart.Truncate(time.Minute))
+
+	// counts start over with the next sample
+	hub.recordActivity()
+	assert.Zero(t, recorder.samples["session-1"].CodeEdits)
+	assert.Equal(t, 2, recorder.samples["session-1"].Participants)
+}
+
+type fakeChatBackpressure map[string]bool
+
+func (f fakeChatBackpressure) Backpressure(sessionID string) bool {
+	return f[sessionID]
+}
+
+func TestHubChatBackedUp(t *testing.T) {
+	hub := NewHub()
+	assert.False(t, hub.chatBackedUp("session-1"))
+
+	hub.SetChatBackpressure(fakeChatBackpressure{"session-1": true})
+	assert.True(t, hub.chatBackedUp("session-1"))
+	assert.False(t, hub.chatBackedUp("session-2"))
+}
+
+// reads the messages queued for a client until none arrive for a while
+func receivedTypes(t *testing.T, client *Client, quiet time.Duration) []string {
+	t.Helper()
+
+	var types []string
+	for {
+		select {
+		case data := <-client.send:
+			var msg Message
+			require.NoError(t, json.Unmarshal(data, &msg))
+
+			if msg.Type == TypeCodeUpdate {
+				var payload CodeUpdatePayload
+				require.NoError(t, msg.UnmarshalPayload(&payload))
+				types = append(types, msg.Type+":"+payload.Code)
+			} else {
+				types = append(types, msg.Type)
+			}
+		case <-time.After(quiet):
+			return types
+		}
+	}
+}
+
+func TestHubCoalescesCodeUpdates(t *testing.T) {
+	hub := NewHub()
+	hub.SetCodeUpdateCoalescing(50 * time.Millisecond)
+	go hub.Run()
+	defer hub.Shutdown()
+
+	newClient := func(id, role string) *Client {
+		return &Client{
+			ID:          id,
+			SessionID:   "session-1",
+			UserID:      "user-" + id,
+			DisplayName: id,
+			Role:        role,
+			IPAddress:   "203.0.113.1",
+			hub:         hub,
+			send:        make(chan []byte, 256),
+		}
+	}
+
+	host := newClient("host", "host")
+	viewer := newClient("viewer", "viewer")
+	listener := newClient("listener", "audience")
+
+	hub.Register <- host
+	hub.Register <- viewer
+	hub.Register <- listener
+	time.Sleep(100 * time.Millisecond)
+
+	for _, c := range []*Client{host, viewer, listener} {
+		receivedTypes(t, c, 10*time.Millisecond)
+	}
+
+	broadcast := func(msgType string, payload any) {
+		msg, err := NewMessage(msgType, "session-1", "user-writer", payload)
+		require.NoError(t, err)
+		hub.BroadcastToSession("session-1", msg, "")
+	}
+
+	for _, code := range []string{"a", "b", "c"} {
+		broadcast(TypeCodeUpdate, CodeUpdatePayload{Code: code})
+	}
+
+	// writers get every update, readers the first and then the latest
+	assert.Equal(t, []string{"code_update:a", "code_update:b", "code_update:c"}, receivedTypes(t, host, 100*time.Millisecond))
+	assert.Equal(t, []string{"code_update:a", "code_update:c"}, receivedTypes(t, viewer, 100*time.Millisecond))
+	assert.Equal(t, []string{"code_update:a", "code_update:c"}, receivedTypes(t, listener, 100*time.Millisecond))
+
+	// a held-back update goes out before the next message
+	broadcast(TypeCodeUpdate, CodeUpdatePayload{Code: "d"})
+	broadcast(TypeCodeUpdate, CodeUpdatePayload{Code: "e"})
+	broadcast(TypePlay, map[string]string{})
+
+	assert.Equal(t, []string{"code_update:d", "code_update:e", "play"}, receivedTypes(t, viewer, 100*time.Millisecond))
+	assert.Equal(t, []string{"code_update:d", "code_update:e", "play"}, receivedTypes(t, listener, 100*time.Millisecond))
+}
+
+func TestHubCoalescingOff(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	viewer := &Client{
+		ID:        "viewer",
+		SessionID: "session-1",
+		UserID:    "user-viewer",
+		Role:      "viewer",
+		hub:       hub,
+		send:      make(chan []byte, 256),
+	}
+
+	hub.Register <- viewer
+	time.Sleep(100 * time.Millisecond)
+	receivedTypes(t, viewer, 10*time.Millisecond)
+
+	for _, code := range []string{"a", "b", "c"} {
+		msg, err := NewMessage(TypeCodeUpdate, "session-1", "user-writer", CodeUpdatePayload{Code: code})
+		require.NoError(t, err)
+		hub.BroadcastToSession("session-1", msg, "")
+	}
+
+	assert.Equal(t, []string{"code_update:a", "code_update:b", "code_update:c"}, receivedTypes(t, viewer, 50*time.Millisecond))
+}
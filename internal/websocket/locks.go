@@ -0,0 +1,352 @@
+package websocket
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"codeberg.org/algopatterns/server/internal/ot"
+	"codeberg.org/algopatterns/server/internal/strudel"
+)
+
+// claims a line range of the session code for the client, or the lines of a
+// named pattern block if block is set. other clients' edits touching the
+// range are rejected until the lock is released or the client disconnects.
+func (h *Hub) LockRegion(client *Client, startLine, endLine int, block string) (*RegionLock, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sd, exists := h.documents[client.SessionID]
+	if !exists {
+		return nil, ErrDocumentNotFound
+	}
+
+	code := sd.doc.Text()
+
+	if block != "" {
+		var ok bool
+		if startLine, endLine, ok = strudel.FindBlock(code, block); !ok {
+			return nil, ErrBlockNotFound
+		}
+	}
+
+	if startLine < 1 || endLine < startLine || endLine > strings.Count(code, "\n")+1 {
+		return nil, ErrInvalidRegion
+	}
+
+	owned := 0
+	for _, lock := range sd.locks {
+		if lock.overlaps(startLine, endLine) {
+			return nil, &RegionLockedError{Lock: *lock}
+		}
+
+		if lock.clientID == client.ID {
+			owned++
+		}
+	}
+
+	if owned >= maxRegionLocksPerClient {
+		return nil, ErrTooManyRegionLocks
+	}
+
+	id, err := GenerateClientID()
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &RegionLock{
+		ID:          id,
+		StartLine:   startLine,
+		EndLine:     endLine,
+		Block:       block,
+		UserID:      client.UserID,
+		DisplayName: client.DisplayName,
+		clientID:    client.ID,
+	}
+
+	sd.locks = append(sd.locks, lock)
+	h.broadcastRegionLocks(client.SessionID, sd)
+
+	return lock, nil
+}
+
+// releases a lock held by the client. the host can release any lock.
+func (h *Hub) UnlockRegion(client *Client, lockID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sd, exists := h.documents[client.SessionID]
+	if !exists {
+		return ErrRegionLockNotFound
+	}
+
+	i := slices.IndexFunc(sd.locks, func(lock *RegionLock) bool {
+		return lock.ID == lockID && (lock.clientID == client.ID || client.Role == "host")
+	})
+	if i < 0 {
+		return ErrRegionLockNotFound
+	}
+
+	sd.locks = slices.Delete(sd.locks, i, i+1)
+	h.broadcastRegionLocks(client.SessionID, sd)
+
+	return nil
+}
+
+// returns copies of the locks of a session document (must be called with lock held)
+func (h *Hub) regionLocks(sessionID string) []RegionLock {
+	locks := []RegionLock{}
+
+	if sd, exists := h.documents[sessionID]; exists {
+		for _, lock := range sd.locks {
+			locks = append(locks, *lock)
+		}
+	}
+
+	return locks
+}
+
+// releases the locks of a disconnecting client (must be called with lock held)
+func (h *Hub) releaseClientLocks(client *Client) {
+	sd, exists := h.documents[client.SessionID]
+	if !exists {
+		return
+	}
+
+	before := len(sd.locks)
+	sd.locks = slices.DeleteFunc(sd.locks, func(lock *RegionLock) bool {
+		return lock.clientID == client.ID
+	})
+
+	if len(sd.locks) != before {
+		h.broadcastRegionLocks(client.SessionID, sd)
+	}
+}
+
+// sends the current locks to everyone in the session (must be called with lock held)
+func (h *Hub) broadcastRegionLocks(sessionID string, sd *sessionDocument) {
+	locks := make([]RegionLock, 0, len(sd.locks))
+	for _, lock := range sd.locks {
+		locks = append(locks, *lock)
+	}
+
+	msg, err := NewMessage(TypeRegionLocks, sessionID, "", RegionLocksPayload{Locks: locks})
+	if err != nil {
+		return
+	}
+
+	h.broadcastToSession(sessionID, msg, "")
+}
+
+// rejects an operation on previousCode that touches a line locked by another
+// client (must be called with lock held)
+func checkRegionLocks(sd *sessionDocument, clientID, previousCode string, op ot.Operation) error {
+	if len(sd.locks) == 0 {
+		return nil
+	}
+
+	lines := newLineIndex(previousCode)
+
+	for _, edit := range operationEdits(op) {
+		from, to, between := lines.touchedLines(edit)
+
+		for _, lock := range sd.locks {
+			if lock.clientID == clientID {
+				continue
+			}
+
+			// whole lines inserted between two lines are only blocked inside a lock
+			if between && lock.StartLine <= from && to <= lock.EndLine {
+				return &RegionLockedError{Lock: *lock}
+			}
+
+			if !between && lock.overlaps(from, to) {
+				return &RegionLockedError{Lock: *lock}
+			}
+		}
+	}
+
+	return nil
+}
+
+// moves locks with the lines they cover after an operation on previousCode
+// was applied and reports if any moved. a new line added by the lock's owner
+// at the end of its last line grows the lock, so a writer can keep adding to
+// their block (must be called with lock held)
+func shiftRegionLocks(sd *sessionDocument, clientID, previousCode string, op ot.Operation) bool {
+	if len(sd.locks) == 0 {
+		return false
+	}
+
+	lines := newLineIndex(previousCode)
+	edits := operationEdits(op)
+	changed := false
+
+	// later edits first, so line numbers of earlier edits stay valid
+	for i := len(edits) - 1; i >= 0; i-- {
+		edit := edits[i]
+
+		delta := strings.Count(edit.inserted, "\n") - lines.newlinesIn(edit.pos, edit.pos+edit.deleted)
+		if delta == 0 {
+			continue
+		}
+
+		line := lines.lineAt(edit.pos)
+		before := edit.deleted == 0 && lines.isLineStart(edit.pos) && strings.HasSuffix(edit.inserted, "\n")
+		after := edit.deleted == 0 && lines.isLineEnd(edit.pos) && strings.HasPrefix(edit.inserted, "\n")
+
+		for _, lock := range sd.locks {
+			// deleted lines can take a lock's start or end with them, the
+			// lock then keeps the line the deletion started on
+			switch {
+			case lock.StartLine > line || (before && lock.StartLine == line):
+				lock.StartLine = max(lock.StartLine+delta, line)
+				lock.EndLine = max(lock.EndLine+delta, lock.StartLine)
+			case lock.EndLine > line || (lock.EndLine == line && (!after || lock.clientID == clientID)):
+				lock.EndLine = max(lock.EndLine+delta, line)
+			default:
+				continue
+			}
+
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// checks if the lock covers any line from start to end
+func (l *RegionLock) overlaps(start, end int) bool {
+	return l.StartLine <= end && start <= l.EndLine
+}
+
+// one change of an operation, in UTF-16 positions of the text it applies to
+type lineEdit struct {
+	pos      int
+	deleted  int
+	inserted string
+}
+
+// splits an operation into its changes
+func operationEdits(op ot.Operation) []lineEdit {
+	var edits []lineEdit
+	pos := 0
+
+	for _, c := range op {
+		switch {
+		case c.IsRetain():
+			pos += c.Retain
+		case c.IsInsert():
+			edits = append(edits, lineEdit{pos: pos, inserted: c.Insert})
+		case c.IsDelete():
+			// inserts come before an adjacent delete, together they replace text
+			if last := len(edits) - 1; last >= 0 && edits[last].pos == pos && edits[last].deleted == 0 {
+				edits[last].deleted = c.Delete
+			} else {
+				edits = append(edits, lineEdit{pos: pos, deleted: c.Delete})
+			}
+
+			pos += c.Delete
+		}
+	}
+
+	return edits
+}
+
+// builds an operation turning previousCode into code with a single change
+// between their common prefix and suffix, so whole-code replacements only
+// touch the lines that actually differ
+func diffOperation(previousCode, code string) ot.Operation {
+	a := utf16.Encode([]rune(previousCode))
+	b := utf16.Encode([]rune(code))
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	// don't split a surrogate pair
+	if prefix > 0 && utf16.IsSurrogate(rune(a[prefix-1])) {
+		prefix--
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	if suffix > 0 && utf16.IsSurrogate(rune(a[len(a)-suffix])) {
+		suffix--
+	}
+
+	var op ot.Operation
+	return op.Retain(prefix).
+		Insert(string(utf16.Decode(b[prefix : len(b)-suffix]))).
+		Delete(len(a) - prefix - suffix).
+		Retain(suffix)
+}
+
+// maps UTF-16 positions of a text to 1-indexed line numbers
+type lineIndex struct {
+	newlines []int // positions of "\n"
+	length   int
+}
+
+func newLineIndex(text string) lineIndex {
+	var idx lineIndex
+
+	for _, r := range text {
+		if r == '\n' {
+			idx.newlines = append(idx.newlines, idx.length)
+		}
+
+		idx.length += utf16.RuneLen(r)
+	}
+
+	return idx
+}
+
+// returns the line of the character at pos; a newline belongs to the line it ends
+func (idx lineIndex) lineAt(pos int) int {
+	return sort.SearchInts(idx.newlines, pos) + 1
+}
+
+func (idx lineIndex) isLineStart(pos int) bool {
+	return pos == 0 || idx.isNewline(pos-1)
+}
+
+func (idx lineIndex) isLineEnd(pos int) bool {
+	return pos == idx.length || idx.isNewline(pos)
+}
+
+func (idx lineIndex) isNewline(pos int) bool {
+	_, found := slices.BinarySearch(idx.newlines, pos)
+	return found
+}
+
+// counts the newlines between from (inclusive) and to (exclusive)
+func (idx lineIndex) newlinesIn(from, to int) int {
+	return sort.SearchInts(idx.newlines, to) - sort.SearchInts(idx.newlines, from)
+}
+
+// returns the lines an edit changes. an edit inserting whole lines between
+// two lines changes neither of them; it returns the lines around the gap with
+// between set instead.
+func (idx lineIndex) touchedLines(edit lineEdit) (from, to int, between bool) {
+	if edit.deleted > 0 {
+		return idx.lineAt(edit.pos), idx.lineAt(edit.pos + edit.deleted - 1), false
+	}
+
+	line := idx.lineAt(edit.pos)
+
+	if idx.isLineStart(edit.pos) && strings.HasSuffix(edit.inserted, "\n") {
+		return line - 1, line, true
+	}
+
+	if idx.isLineEnd(edit.pos) && strings.HasPrefix(edit.inserted, "\n") {
+		return line, line + 1, true
+	}
+
+	return line, line, false
+}
@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 )
@@ -21,6 +22,16 @@ func NewMessage(msgType, sessionID, userID string, payload interface{}) (*Messag
 	}, nil
 }
 
+// returns the context of the handler processing the message, carrying its
+// trace. messages that are not being handled return a background context.
+func (m *Message) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+
+	return m.ctx
+}
+
 // unmarshals the payload into the provided struct
 func (m *Message) UnmarshalPayload(v interface{}) error {
 	return json.Unmarshal(m.Payload, v)
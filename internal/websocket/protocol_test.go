@@ -0,0 +1,580 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/errors"
+	"codeberg.org/algopatterns/server/internal/llm"
+)
+
+// contract tests for the wire protocol. they assert the exact JSON clients
+// receive, so renaming a field or changing which clients get a message
+// fails here before it breaks the frontend, the TUI or the generated clients
+
+// the envelope of sequenced broadcasts and of direct, unsequenced messages
+var (
+	sequencedEnvelope   = []string{"payload", "seq", "session_id", "timestamp", "type", "user_id"}
+	unsequencedEnvelope = []string{"payload", "session_id", "timestamp", "type", "user_id"}
+)
+
+// stores the code written through the handlers
+type protocolRepo struct {
+	sessions.Repository
+
+	mu    sync.Mutex
+	codes []string
+}
+
+func (r *protocolRepo) UpdateSessionCode(_ context.Context, _, code string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.codes = append(r.codes, code)
+	return nil
+}
+
+func (r *protocolRepo) AddChatMessage(_ context.Context, req *sessions.AddChatMessageRequest) (*sessions.Message, error) {
+	return &sessions.Message{ID: "message-1", SessionID: req.SessionID, Content: req.Content}, nil
+}
+
+// answers every prompt from the cache, so agent requests never reach a provider
+type protocolResponseCache struct{}
+
+func (protocolResponseCache) GetResponse(_ context.Context, _ string) (*agent.CachedResponse, error) {
+	return &agent.CachedResponse{Code: `s("bd sd")`, IsCodeResponse: true, Model: "llama3"}, nil
+}
+
+func (protocolResponseCache) SetResponse(_ context.Context, _ string, _ *agent.CachedResponse) error {
+	return nil
+}
+
+// a wire frame as clients decode it
+type frame map[string]any
+
+func (f frame) msgType() string {
+	msgType, _ := f["type"].(string)
+	return msgType
+}
+
+func (f frame) payload() map[string]any {
+	payload, _ := f["payload"].(map[string]any)
+	return payload
+}
+
+func (f frame) seq() uint64 {
+	seq, _ := f["seq"].(float64)
+	return uint64(seq)
+}
+
+func newProtocolHub(t *testing.T) (*Hub, *protocolRepo) {
+	t.Helper()
+
+	hub := NewHub()
+	go hub.Run()
+	t.Cleanup(hub.Shutdown)
+
+	repo := &protocolRepo{}
+	agentClient, providers := protocolAgent()
+
+	hub.RegisterHandler(TypeCodeUpdate, CodeUpdateHandler(repo, nil))
+	hub.RegisterHandler(TypeAgentRequest, AgentRequestHandler(agentClient, providers, nil, nil, nil, nil))
+
+	return hub, repo
+}
+
+// an agent answering every request from its response cache, so none reaches
+// the provider
+func protocolAgent() (*agent.Agent, *llm.Registry) {
+	agentClient := agent.New(nil, nil)
+	agentClient.SetResponseCache(protocolResponseCache{})
+	providers := llm.NewRegistry(llm.ProviderOllama, llm.ProviderSpec{
+		Name:         llm.ProviderOllama,
+		DefaultModel: "llama3",
+		BaseURL:      "http://127.0.0.1:0",
+	})
+
+	return agentClient, providers
+}
+
+func joinProtocolClient(t *testing.T, hub *Hub, id, role string) *Client {
+	t.Helper()
+
+	client := &Client{
+		ID:          id,
+		SessionID:   "session-1",
+		UserID:      "user-" + id,
+		DisplayName: id,
+		Role:        role,
+		InitialCode: `s("bd")`,
+		IPAddress:   "203.0.113.1",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- client
+	time.Sleep(50 * time.Millisecond)
+
+	return client
+}
+
+// sends a message from the client through the hub, as ReadPump does
+func sendProtocolMessage(t *testing.T, hub *Hub, client *Client, msgType string, payload any) {
+	t.Helper()
+
+	msg, err := NewMessage(msgType, client.SessionID, client.UserID, payload)
+	require.NoError(t, err)
+	msg.ClientID = client.ID
+
+	hub.Broadcast <- msg
+}
+
+// reads the frames queued for a client until none arrive for a while
+func readFrames(t *testing.T, client *Client) []frame {
+	t.Helper()
+
+	var frames []frame
+	for {
+		select {
+		case data := <-client.send:
+			var f frame
+			require.NoError(t, json.Unmarshal(data, &f))
+			frames = append(frames, f)
+		case <-time.After(100 * time.Millisecond):
+			return frames
+		}
+	}
+}
+
+func frameTypes(frames []frame) []string {
+	types := make([]string, len(frames))
+	for i, f := range frames {
+		types[i] = f.msgType()
+	}
+
+	return types
+}
+
+func keys(m map[string]any) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func TestProtocolSessionState(t *testing.T) {
+	hub, _ := newProtocolHub(t)
+	host := joinProtocolClient(t, hub, "host", "host")
+
+	frames := readFrames(t, host)
+	require.Equal(t, []string{TypeSessionState}, frameTypes(frames))
+
+	state := frames[0]
+	assert.Equal(t, unsequencedEnvelope, keys(state))
+	assert.Equal(t, "session-1", state["session_id"])
+	assert.Equal(t, "user-host", state["user_id"])
+
+	payload := state.payload()
+	assert.Equal(t, []string{
+		"chat_history", "chat_slow_mode_seconds", "client_id", "code", "code_updates_per_second",
+		"listener_count", "participants", "region_locks", "revision", "seq", "transport",
+		"your_display_name", "your_permissions", "your_role",
+	}, keys(payload))
+	assert.Equal(t, `s("bd")`, payload["code"])
+	assert.Equal(t, "host", payload["client_id"])
+	assert.Equal(t, "host", payload["your_role"])
+	assert.InDelta(t, 0, payload["seq"], 0)
+
+	// empty lists are sent as [], never null
+	assert.Equal(t, []any{}, payload["chat_history"])
+	assert.Equal(t, []any{}, payload["region_locks"])
+
+	participants, ok := payload["participants"].([]any)
+	require.True(t, ok)
+	require.Len(t, participants, 1)
+	participant, ok := participants[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []string{"display_name", "role", "user_id"}, keys(participant))
+
+	transport, ok := payload["transport"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []string{"cpm", "origin_cycle", "origin_time", "playing", "server_time"}, keys(transport))
+
+	// listeners learn the code, not who is performing or their own rate limit
+	listener := joinProtocolClient(t, hub, "listener", "embed")
+
+	frames = readFrames(t, listener)
+	require.Equal(t, []string{TypeSessionState}, frameTypes(frames))
+	assert.Equal(t, []any{}, frames[0].payload()["participants"])
+	assert.NotContains(t, frames[0].payload(), "code_updates_per_second")
+}
+
+func TestProtocolUserJoinedAndLeft(t *testing.T) {
+	hub, _ := newProtocolHub(t)
+	host := joinProtocolClient(t, hub, "host", "host")
+	readFrames(t, host)
+
+	guest := joinProtocolClient(t, hub, "guest", "co-author")
+
+	// the joining client gets session_state, never its own user_joined
+	guestFrames := readFrames(t, guest)
+	require.Equal(t, []string{TypeSessionState}, frameTypes(guestFrames))
+
+	hostFrames := readFrames(t, host)
+	require.Equal(t, []string{TypeUserJoined}, frameTypes(hostFrames))
+
+	// the host's own user_joined, which went to nobody, took seq 1
+	joined := hostFrames[0]
+	assert.Equal(t, sequencedEnvelope, keys(joined))
+	assert.Equal(t, uint64(2), joined.seq())
+	assert.Equal(t, map[string]any{"user_id": "user-guest", "display_name": "guest", "role": "co-author"}, joined.payload())
+
+	// anonymous users have no user_id to send
+	anonymous := &Client{
+		ID:          "anonymous",
+		SessionID:   "session-1",
+		DisplayName: "anonymous",
+		Role:        "viewer",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+	hub.Register <- anonymous
+	time.Sleep(50 * time.Millisecond)
+	readFrames(t, anonymous)
+
+	hostFrames = readFrames(t, host)
+	require.Equal(t, []string{TypeUserJoined}, frameTypes(hostFrames))
+	assert.Equal(t, []string{"payload", "seq", "session_id", "timestamp", "type"}, keys(hostFrames[0]))
+	assert.Equal(t, map[string]any{"display_name": "anonymous", "role": "viewer"}, hostFrames[0].payload())
+
+	hub.Unregister <- guest
+	time.Sleep(50 * time.Millisecond)
+
+	hostFrames = readFrames(t, host)
+	require.Equal(t, []string{TypeUserLeft}, frameTypes(hostFrames))
+
+	left := hostFrames[0]
+	assert.Equal(t, sequencedEnvelope, keys(left))
+	assert.Equal(t, uint64(4), left.seq())
+	assert.Equal(t, map[string]any{"user_id": "user-guest", "display_name": "guest"}, left.payload())
+
+	// participants changing is not the audience's business
+	listener := joinProtocolClient(t, hub, "listener", "audience")
+	readFrames(t, listener)
+
+	hub.Unregister <- anonymous
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, []string{TypeUserLeft}, frameTypes(readFrames(t, host)))
+	assert.Empty(t, readFrames(t, listener))
+}
+
+func TestProtocolCodeUpdate(t *testing.T) {
+	hub, repo := newProtocolHub(t)
+	host := joinProtocolClient(t, hub, "host", "host")
+	guest := joinProtocolClient(t, hub, "guest", "co-author")
+	viewer := joinProtocolClient(t, hub, "viewer", "viewer")
+	listener := joinProtocolClient(t, hub, "listener", "audience")
+	for _, c := range []*Client{host, guest, viewer, listener} {
+		readFrames(t, c)
+	}
+
+	// the sender's identity comes from the connection, not the payload
+	sendProtocolMessage(t, hub, host, TypeCodeUpdate, map[string]any{
+		"code":         `s("bd sd")`,
+		"source":       "typed",
+		"display_name": "someone else",
+		"user_id":      "user-guest",
+	})
+
+	// the sender is excluded from its own update and gets its lint results
+	frames := readFrames(t, host)
+	require.Equal(t, []string{TypeCodeValidation}, frameTypes(frames))
+	assert.Equal(t, unsequencedEnvelope, keys(frames[0]))
+	assert.Equal(t, map[string]any{"diagnostics": []any{}, "revision": float64(1), "valid": true}, frames[0].payload())
+
+	for _, c := range []*Client{guest, viewer} {
+		frames := readFrames(t, c)
+		require.Equal(t, []string{TypeCodeUpdate}, frameTypes(frames), c.ID)
+
+		update := frames[0]
+		assert.Equal(t, sequencedEnvelope, keys(update))
+		assert.Equal(t, "user-host", update["user_id"])
+		assert.Equal(t, map[string]any{
+			"code":         `s("bd sd")`,
+			"display_name": "host",
+			"user_id":      "user-host",
+			"role":         "host",
+			"source":       "typed",
+			"revision":     float64(1),
+		}, update.payload())
+	}
+
+	// the audience gets the code, unsequenced since it can't resume
+	frames = readFrames(t, listener)
+	require.Equal(t, []string{TypeCodeUpdate}, frameTypes(frames))
+	assert.Equal(t, unsequencedEnvelope, keys(frames[0]))
+	assert.Equal(t, `s("bd sd")`, frames[0].payload()["code"])
+
+	repo.mu.Lock()
+	assert.Equal(t, []string{`s("bd sd")`}, repo.codes)
+	repo.mu.Unlock()
+}
+
+func TestProtocolSeqIsMonotonic(t *testing.T) {
+	hub, _ := newProtocolHub(t)
+	host := joinProtocolClient(t, hub, "host", "host")
+	readFrames(t, host)
+
+	guest := joinProtocolClient(t, hub, "guest", "co-author")
+	readFrames(t, guest)
+
+	for _, code := range []string{"a", "b", "c"} {
+		sendProtocolMessage(t, hub, guest, TypeCodeUpdate, CodeUpdatePayload{Code: `s("` + code + `")`})
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	late := joinProtocolClient(t, hub, "late", "viewer")
+	hub.Unregister <- guest
+	time.Sleep(50 * time.Millisecond)
+
+	// every broadcast to the session takes the next number, with no gaps.
+	// the host's own user_joined, which went to nobody, took 1
+	var seqs []uint64
+	for _, f := range readFrames(t, host) {
+		seqs = append(seqs, f.seq())
+	}
+	assert.Equal(t, []uint64{2, 3, 4, 5, 6, 7}, seqs)
+
+	// a late joiner's session_state carries the last number it has
+	// already accounted for, and later broadcasts continue from there
+	frames := readFrames(t, late)
+	require.Equal(t, []string{TypeSessionState, TypeUserLeft}, frameTypes(frames))
+	assert.InDelta(t, 5, frames[0].payload()["seq"], 0)
+	assert.Equal(t, uint64(7), frames[1].seq())
+}
+
+func TestProtocolErrors(t *testing.T) {
+	hub, repo := newProtocolHub(t)
+	hub.RegisterHandler("failing", func(*Hub, *Client, *Message) error {
+		return stderrors.New("store unreachable")
+	})
+
+	host := joinProtocolClient(t, hub, "host", "host")
+	viewer := joinProtocolClient(t, hub, "viewer", "viewer")
+	readFrames(t, host)
+	readFrames(t, viewer)
+
+	// errors go to the offending client only, and a handler's refusal is
+	// the only error sent for it
+	sendProtocolMessage(t, hub, viewer, TypeCodeUpdate, CodeUpdatePayload{Code: `s("hh")`})
+
+	frames := readFrames(t, viewer)
+	require.Equal(t, []string{TypeError}, frameTypes(frames))
+	assert.Equal(t, unsequencedEnvelope, keys(frames[0]))
+	assert.Equal(t, map[string]any{
+		"error":     "forbidden",
+		"message":   "you don't have permission to edit code",
+		"retryable": false,
+	}, frames[0].payload())
+	assert.Empty(t, readFrames(t, host))
+
+	repo.mu.Lock()
+	assert.Empty(t, repo.codes)
+	repo.mu.Unlock()
+
+	sendProtocolMessage(t, hub, host, "no_such_type", map[string]any{})
+
+	frames = readFrames(t, host)
+	require.Equal(t, []string{TypeError}, frameTypes(frames))
+	assert.Equal(t, map[string]any{
+		"error":     "bad_request",
+		"message":   "unsupported message type",
+		"details":   "message type not recognized",
+		"retryable": false,
+	}, frames[0].payload())
+
+	// handlers failing without saying why are reported by the hub, as
+	// retryable after a backoff
+	sendProtocolMessage(t, hub, host, "failing", map[string]any{})
+
+	frames = readFrames(t, host)
+	require.Equal(t, []string{TypeError}, frameTypes(frames))
+	assert.Equal(t, map[string]any{
+		"error":          "server_error",
+		"message":        "failed to process message",
+		"details":        "store unreachable",
+		"retryable":      true,
+		"retry_after_ms": float64(1000),
+	}, frames[0].payload())
+
+	// errors answering a request echo its request_id
+	sendProtocolMessage(t, hub, host, TypeAgentRequest, AgentRequestPayload{RequestID: "req-1", UserQuery: "make a beat"})
+
+	frames = readFrames(t, host)
+	require.Equal(t, []string{TypeError}, frameTypes(frames))
+	assert.Equal(t, []string{"error", "message", "request_id", "retryable"}, keys(frames[0].payload()))
+	assert.Equal(t, "byok_required", frames[0].payload()["error"])
+	assert.Equal(t, "req-1", frames[0].payload()["request_id"])
+}
+
+func TestProtocolSlowModeError(t *testing.T) {
+	hub, _ := newProtocolHub(t)
+	hub.RegisterHandler(TypeChatMessage, ChatHandler(&protocolRepo{}, nil))
+
+	viewer := joinProtocolClient(t, hub, "viewer", "viewer")
+	readFrames(t, viewer)
+	hub.SetChatSlowMode("session-1", 30)
+
+	sendProtocolMessage(t, hub, viewer, TypeChatMessage, ChatMessagePayload{Message: "hello"})
+	readFrames(t, viewer)
+
+	// the wait is what's left of the slow mode window, not the code's default
+	sendProtocolMessage(t, hub, viewer, TypeChatMessage, ChatMessagePayload{Message: "again"})
+
+	frames := readFrames(t, viewer)
+	require.Equal(t, []string{TypeError}, frameTypes(frames))
+	payload := frames[0].payload()
+	assert.Equal(t, "slow_mode", payload["error"])
+	assert.Equal(t, true, payload["retryable"])
+	assert.InDelta(t, 30000, payload["retry_after_ms"], 1000)
+}
+
+// the error codes the websocket package sends, by constant name
+var protocolErrorCodes = map[string]errors.Code{
+	"CodeTooManyRequests":     errors.CodeTooManyRequests,
+	"CodeForbidden":           errors.CodeForbidden,
+	"CodeValidationError":     errors.CodeValidationError,
+	"CodeBadRequest":          errors.CodeBadRequest,
+	"CodeConflict":            errors.CodeConflict,
+	"CodeServerError":         errors.CodeServerError,
+	"CodePasteLocked":         errors.CodePasteLocked,
+	"CodeBYOKRequired":        errors.CodeBYOKRequired,
+	"CodeRateLimitExceeded":   errors.CodeRateLimitExceeded,
+	"CodeAIUnavailable":       errors.CodeAIUnavailable,
+	"CodeProviderRateLimited": errors.CodeProviderRateLimited,
+	"CodeProviderRejected":    errors.CodeProviderRejected,
+	"CodeContentBlocked":      errors.CodeContentBlocked,
+	"CodeMuted":               errors.CodeMuted,
+	"CodeSlowMode":            errors.CodeSlowMode,
+	"CodeServerBusy":          errors.CodeServerBusy,
+	"CodeFeatureDisabled":     errors.CodeFeatureDisabled,
+	"CodeRegionLocked":        errors.CodeRegionLocked,
+}
+
+// every code the package sends is listed in docs/websocket/API.md with the
+// retry hint clients get for it
+func TestProtocolErrorCodesDocumented(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	used := map[string]bool{}
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		require.NoError(t, err)
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "errors" && strings.HasPrefix(sel.Sel.Name, "Code") && sel.Sel.Name != "Code" {
+				used[sel.Sel.Name] = true
+			}
+			return true
+		})
+	}
+
+	for name := range used {
+		assert.Contains(t, protocolErrorCodes, name, "add %s to protocolErrorCodes and the docs", name)
+	}
+
+	doc, err := os.ReadFile("../../docs/websocket/API.md")
+	require.NoError(t, err)
+
+	// the table under ### `error`: | `code` | retryable | retry_after_ms | description |
+	_, section, found := strings.Cut(string(doc), "### `error`")
+	require.True(t, found, "the error message is not documented")
+	section, _, _ = strings.Cut(section, "\n---")
+
+	rows := map[errors.Code][]string{}
+	row := regexp.MustCompile("(?m)^\\| `([a-z_]+)` +\\|([^|]*)\\|([^|]*)\\|")
+	for _, m := range row.FindAllStringSubmatch(section, -1) {
+		rows[errors.Code(m[1])] = []string{strings.TrimSpace(m[2]), strings.TrimSpace(m[3])}
+	}
+
+	for _, code := range protocolErrorCodes {
+		require.Contains(t, rows, code, "%s is not documented", code)
+
+		retry := code.Retry()
+		retryable := map[bool]string{true: "yes", false: "no"}[retry.Retryable]
+		assert.Equal(t, retryable, rows[code][0], "retryable of %s", code)
+
+		if retry.After > 0 {
+			assert.Equal(t, strconv.FormatInt(retry.After.Milliseconds(), 10), rows[code][1], "retry_after_ms of %s", code)
+		}
+	}
+}
+
+func TestProtocolAgentResponse(t *testing.T) {
+	hub, _ := newProtocolHub(t)
+	host := joinProtocolClient(t, hub, "host", "host")
+	guest := joinProtocolClient(t, hub, "guest", "co-author")
+	readFrames(t, host)
+	readFrames(t, guest)
+
+	sendProtocolMessage(t, hub, host, TypeAgentRequest, AgentRequestPayload{
+		RequestID:      "req-1",
+		UserQuery:      "make a beat",
+		Provider:       string(llm.ProviderOllama),
+		ProviderAPIKey: "key",
+	})
+
+	// chunks then done, to the requester only and outside the sequence
+	frames := readFrames(t, host)
+	require.Equal(t, []string{TypeAgentResponseChunk, TypeAgentResponseDone}, frameTypes(frames))
+
+	for _, f := range frames {
+		assert.Equal(t, unsequencedEnvelope, keys(f))
+	}
+
+	assert.Equal(t, map[string]any{"request_id": "req-1", "content": `s("bd sd")`}, frames[0].payload())
+	assert.Equal(t, map[string]any{
+		"request_id":       "req-1",
+		"code":             `s("bd sd")`,
+		"is_code_response": true,
+		"model":            "llama3",
+		"input_tokens":     float64(0),
+		"output_tokens":    float64(0),
+		"cached":           true,
+	}, frames[1].payload())
+
+	assert.Empty(t, readFrames(t, guest))
+}
@@ -0,0 +1,167 @@
+package websocket
+
+import (
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+// records a ping about to be sent, counting the previous one as missed if it
+// wasn't answered. returns false when the client missed too many pings or
+// stopped reading its messages, and should be disconnected
+func (c *Client) pingDue() bool {
+	p := &c.pings
+	p.mu.Lock()
+
+	if !p.sentAt.IsZero() {
+		p.missed++
+	}
+
+	if len(c.send) >= stalledQueueDepth {
+		p.stalled++
+	} else {
+		p.stalled = 0
+	}
+
+	reason := ""
+	switch {
+	case p.missed >= maxMissedPings:
+		reason = "missed_pings"
+	case p.stalled >= maxStalledQueueing:
+		reason = "stalled"
+	}
+
+	p.sentAt = time.Now()
+	quality := p.nextQuality(reason != "")
+	p.mu.Unlock()
+
+	if quality != "" {
+		c.announceQuality(quality)
+	}
+
+	if reason != "" {
+		metrics.WebSocketDeadConnections.Inc(reason)
+		logger.Info("closing unresponsive websocket connection",
+			"client_id", c.ID,
+			"session_id", c.SessionID,
+			"reason", reason,
+		)
+
+		return false
+	}
+
+	return true
+}
+
+// records the answer to the last ping
+func (c *Client) pongReceived() {
+	p := &c.pings
+	p.mu.Lock()
+
+	if !p.sentAt.IsZero() {
+		p.rtt = time.Since(p.sentAt)
+		p.sentAt = time.Time{}
+		metrics.WebSocketPingRTT.Observe(p.rtt.Seconds())
+	}
+
+	p.missed = 0
+	quality := p.nextQuality(false)
+	p.mu.Unlock()
+
+	if quality != "" {
+		c.announceQuality(quality)
+	}
+}
+
+// returns the last ping round trip and connection quality of the client,
+// zero and empty until the first pong
+func (c *Client) connectionQuality() (time.Duration, string) {
+	c.pings.mu.Lock()
+	defer c.pings.mu.Unlock()
+
+	if c.pings.rtt == 0 {
+		return 0, ""
+	}
+
+	return c.pings.rtt, connectionQuality(c.pings.rtt, c.pings.missed)
+}
+
+// describes the client in participant lists, with its connection quality
+func (c *Client) participant() SessionStateParticipant {
+	rtt, quality := c.connectionQuality()
+
+	return SessionStateParticipant{
+		UserID:      c.UserID,
+		DisplayName: c.DisplayName,
+		Role:        c.Role,
+		RTTMs:       rtt.Milliseconds(),
+		Quality:     quality,
+	}
+}
+
+// updates the quality last announced, returning the new one when it changed
+// and should be announced (must hold p.mu)
+func (p *pingStats) nextQuality(lost bool) string {
+	quality := connectionQuality(p.rtt, p.missed)
+	if lost {
+		quality = "lost"
+	}
+
+	// connections start out good, so they aren't announced when they join
+	previous := p.quality
+	if previous == "" {
+		previous = "good"
+	}
+
+	p.quality = quality
+	if quality == previous {
+		return ""
+	}
+
+	return quality
+}
+
+// rates a connection by its last ping round trip and the pings it missed
+// since
+func connectionQuality(rtt time.Duration, missed int) string {
+	switch {
+	case missed > 0 || rtt > fairConnectionRTT:
+		return "poor"
+	case rtt > goodConnectionRTT:
+		return "fair"
+	default:
+		return "good"
+	}
+}
+
+// tells the session, the client included unless its connection is lost,
+// that the client's connection quality changed. unsequenced, like presence,
+// since only the latest quality matters. audiences aren't announced
+func (c *Client) announceQuality(quality string) {
+	if c.hub == nil || c.IsAudience() {
+		return
+	}
+
+	c.pings.mu.Lock()
+	payload := ConnectionQualityPayload{
+		UserID:      c.UserID,
+		DisplayName: c.DisplayName,
+		Quality:     quality,
+		RTTMs:       c.pings.rtt.Milliseconds(),
+		MissedPings: c.pings.missed,
+	}
+	c.pings.mu.Unlock()
+
+	msg, err := NewMessage(TypeConnectionQuality, c.SessionID, c.UserID, payload)
+	if err != nil {
+		return
+	}
+
+	exclude := ""
+	if quality == "lost" {
+		exclude = c.ID
+	}
+
+	c.hub.BroadcastPresence(c.SessionID, msg, exclude)
+}
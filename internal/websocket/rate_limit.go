@@ -0,0 +1,117 @@
+package websocket
+
+import (
+	"fmt"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/metrics"
+)
+
+// returns the code updates the client may send per second
+func (c *Client) codeUpdateRate() int {
+	if limit := c.codeUpdateLimit.Load(); limit > 0 {
+		return int(limit)
+	}
+
+	return maxCodeUpdatesPerSecond
+}
+
+// the error sent for a code update over the client's limit
+func (c *Client) codeUpdateRateError() string {
+	return fmt.Sprintf("too many code updates. maximum %d per second.", c.codeUpdateRate())
+}
+
+// counts the code updates the client sent in the last second
+func (c *Client) recentCodeUpdates() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	oneSecondAgo := time.Now().Add(-time.Second)
+
+	n := 0
+	for _, ts := range c.codeUpdateTimestamps {
+		if ts.After(oneSecondAgo) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// adjusts the code update limits of the writers in every session to how well
+// the session keeps up, and tells writers whose limit changed with
+// rate_limit_update. unsequenced, like listener_count, since only the latest
+// limit matters
+func (h *Hub) adjustRateLimits() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sessionID, participants := range h.sessions {
+		backedUp := h.sessionBacklog(sessionID) >= sessionBacklogLimit
+
+		for _, client := range participants {
+			if client.awaitingResume || !client.CanWrite() {
+				continue
+			}
+
+			limit, reason := nextCodeUpdateRate(client, backedUp)
+			if limit == client.codeUpdateRate() {
+				continue
+			}
+
+			client.codeUpdateLimit.Store(int32(limit)) //nolint:gosec // bounded by peakCodeUpdatesPerSecond
+			metrics.WebSocketRateLimitUpdates.Inc(reason)
+
+			msg, err := NewMessage(TypeRateLimitUpdate, sessionID, "", RateLimitUpdatePayload{
+				CodeUpdatesPerSecond: limit,
+				Reason:               reason,
+			})
+			if err != nil {
+				continue
+			}
+
+			client.Send(msg) //nolint:errcheck,gosec // best-effort, the limit applies either way
+		}
+	}
+}
+
+// the average number of frames queued for the clients of a session, its
+// participants and audience together (must be called with lock held)
+func (h *Hub) sessionBacklog(sessionID string) int {
+	queued, clients := 0, 0
+
+	for _, client := range h.sessions[sessionID] {
+		queued += len(client.send)
+		clients++
+	}
+	for _, client := range h.audiences[sessionID] {
+		queued += len(client.send)
+		clients++
+	}
+
+	if clients == 0 {
+		return 0
+	}
+
+	return queued / clients
+}
+
+// the next code update limit of a writer: halved while it or its session is
+// backed up, otherwise raised back to the default and, while the writer uses
+// at least half of it, further up to the peak
+func nextCodeUpdateRate(client *Client, sessionBackedUp bool) (int, string) {
+	limit := client.codeUpdateRate()
+
+	if sessionBackedUp || len(client.send) >= clientBacklogLimit {
+		return max(limit/2, minCodeUpdatesPerSecond), "backlog"
+	}
+
+	switch {
+	case client.recentCodeUpdates() >= limit/2:
+		return min(limit+codeUpdateRateStep, peakCodeUpdatesPerSecond), "headroom"
+	case limit < maxCodeUpdatesPerSecond:
+		return min(limit+codeUpdateRateStep, maxCodeUpdatesPerSecond), "headroom"
+	}
+
+	return limit, ""
+}
@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -117,3 +118,199 @@ func TestCanWrite(t *testing.T) {
 		}
 	}
 }
+
+// test presence throttling (one broadcast per interval, latest update wins)
+func TestPresenceThrottle(t *testing.T) {
+	hub := NewHub()
+
+	sender := &Client{ID: "sender", SessionID: "session-1", Role: "host", hub: hub, send: make(chan []byte, 16)}
+	receiver := &Client{ID: "receiver", SessionID: "session-1", Role: "viewer", hub: hub, send: make(chan []byte, 16)}
+
+	hub.sessions["session-1"] = map[string]*Client{
+		sender.ID:   sender,
+		receiver.ID: receiver,
+	}
+
+	for line := 1; line <= 3; line++ {
+		msg, err := NewMessage(TypeCursorUpdate, "session-1", "", CursorUpdatePayload{Line: line})
+		if err != nil {
+			t.Fatalf("failed to create message: %v", err)
+		}
+
+		sender.throttlePresence(msg)
+	}
+
+	// the first update goes out immediately
+	if line := receiveCursorLine(t, receiver); line != 1 {
+		t.Errorf("Expected first broadcast for line 1, got line %d", line)
+	}
+
+	// updates 2 and 3 collapse into one trailing broadcast of the latest
+	if line := receiveCursorLine(t, receiver); line != 3 {
+		t.Errorf("Expected trailing broadcast for line 3, got line %d", line)
+	}
+
+	select {
+	case <-receiver.send:
+		t.Error("Expected no more than two presence broadcasts")
+	case <-time.After(2 * presenceThrottleInterval):
+	}
+
+	if len(sender.send) != 0 {
+		t.Error("Presence updates should not be echoed to the sender")
+	}
+
+	if hub.sessionSequences["session-1"] != 0 {
+		t.Error("Presence updates should not consume sequence numbers")
+	}
+}
+
+func receiveCursorLine(t *testing.T, client *Client) int {
+	t.Helper()
+
+	select {
+	case data := <-client.send:
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("failed to unmarshal message: %v", err)
+		}
+
+		var payload CursorUpdatePayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			t.Fatalf("failed to unmarshal payload: %v", err)
+		}
+
+		return payload.Line
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for presence broadcast")
+		return 0
+	}
+}
+
+// test adaptive code update limits (halved when backed up, raised when used)
+func TestAdaptiveCodeUpdateRate(t *testing.T) {
+	client := &Client{Role: "host", send: make(chan []byte, 256)}
+
+	if limit, _ := nextCodeUpdateRate(client, false); limit != maxCodeUpdatesPerSecond {
+		t.Errorf("Expected an idle writer to keep %d, got %d", maxCodeUpdatesPerSecond, limit)
+	}
+
+	// a backed up session halves the limit down to the minimum
+	for _, want := range []int{15, 7, 5, 5} {
+		limit, reason := nextCodeUpdateRate(client, true)
+		if limit != want || reason != "backlog" {
+			t.Errorf("Expected %d (backlog), got %d (%s)", want, limit, reason)
+		}
+		client.codeUpdateLimit.Store(int32(limit))
+	}
+
+	// the writer's own queue counts too
+	client.codeUpdateLimit.Store(maxCodeUpdatesPerSecond)
+	for range clientBacklogLimit {
+		client.send <- []byte("{}")
+	}
+	if limit, _ := nextCodeUpdateRate(client, false); limit != 15 {
+		t.Errorf("Expected a backed up client to be halved to 15, got %d", limit)
+	}
+	for len(client.send) > 0 {
+		<-client.send
+	}
+
+	// recovery stops at the default while the writer is idle
+	client.codeUpdateLimit.Store(minCodeUpdatesPerSecond)
+	for _, want := range []int{15, 25, 30, 30} {
+		limit, _ := nextCodeUpdateRate(client, false)
+		if limit != want {
+			t.Errorf("Expected recovery to %d, got %d", want, limit)
+		}
+		client.codeUpdateLimit.Store(int32(limit))
+	}
+
+	// a writer using half its limit gets more, up to the peak
+	for range maxCodeUpdatesPerSecond / 2 {
+		client.codeUpdateTimestamps = append(client.codeUpdateTimestamps, time.Now())
+	}
+	if limit, reason := nextCodeUpdateRate(client, false); limit != 40 || reason != "headroom" {
+		t.Errorf("Expected a busy writer to be raised to 40 (headroom), got %d (%s)", limit, reason)
+	}
+
+	client.codeUpdateLimit.Store(peakCodeUpdatesPerSecond)
+	client.codeUpdateTimestamps = append(client.codeUpdateTimestamps, client.codeUpdateTimestamps...)
+	if limit, _ := nextCodeUpdateRate(client, false); limit != peakCodeUpdatesPerSecond {
+		t.Errorf("Expected the limit to stop at %d, got %d", peakCodeUpdatesPerSecond, limit)
+	}
+}
+
+// test that the code update rate limit follows the adjusted limit
+func TestCodeUpdateRateLimitAdjusted(t *testing.T) {
+	client := &Client{}
+	client.codeUpdateLimit.Store(minCodeUpdatesPerSecond)
+
+	for i := 0; i < minCodeUpdatesPerSecond; i++ {
+		if !client.checkCodeUpdateRateLimit() {
+			t.Errorf("Code update %d should have been allowed, but was rate limited", i+1)
+		}
+	}
+
+	if client.checkCodeUpdateRateLimit() {
+		t.Errorf("Code update %d should have been rate limited, but was allowed", minCodeUpdatesPerSecond+1)
+	}
+}
+
+// test that writers of a backed up session are told their new limit
+func TestHubAdjustRateLimits(t *testing.T) {
+	hub := NewHub()
+
+	writer := &Client{ID: "writer", SessionID: "session-1", Role: "host", hub: hub, send: make(chan []byte, 256)}
+	viewer := &Client{ID: "viewer", SessionID: "session-1", Role: "viewer", hub: hub, send: make(chan []byte, 256)}
+
+	hub.sessions["session-1"] = map[string]*Client{
+		writer.ID: writer,
+		viewer.ID: viewer,
+	}
+
+	// the viewer alone backs up the session on average
+	for range 2 * sessionBacklogLimit {
+		viewer.send <- []byte("{}")
+	}
+
+	hub.adjustRateLimits()
+
+	if writer.codeUpdateRate() != maxCodeUpdatesPerSecond/2 {
+		t.Errorf("Expected the writer's limit to be halved, got %d", writer.codeUpdateRate())
+	}
+
+	select {
+	case data := <-writer.send:
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("failed to unmarshal message: %v", err)
+		}
+
+		var payload RateLimitUpdatePayload
+		if err := msg.UnmarshalPayload(&payload); err != nil {
+			t.Fatalf("failed to unmarshal payload: %v", err)
+		}
+
+		if msg.Type != TypeRateLimitUpdate || payload.CodeUpdatesPerSecond != 15 || payload.Reason != "backlog" {
+			t.Errorf("Expected rate_limit_update to 15 (backlog), got %s %+v", msg.Type, payload)
+		}
+	default:
+		t.Fatal("Expected the writer to be told about its new limit")
+	}
+
+	if len(viewer.send) != 2*sessionBacklogLimit {
+		t.Error("Viewers don't send code updates and shouldn't be told about limits")
+	}
+
+	// nothing changed, nothing is sent
+	for len(viewer.send) > 0 {
+		<-viewer.send
+	}
+	writer.codeUpdateLimit.Store(maxCodeUpdatesPerSecond)
+	hub.adjustRateLimits()
+
+	if len(writer.send) != 0 {
+		t.Error("Expected no rate_limit_update when the limit stays the same")
+	}
+}
@@ -0,0 +1,194 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{
+		entries: make([]replayEntry, size),
+	}
+}
+
+// appends an entry, overwriting the oldest one when the buffer is full
+func (b *replayBuffer) add(entry replayEntry) {
+	if b.count < len(b.entries) {
+		b.entries[(b.start+b.count)%len(b.entries)] = entry
+		b.count++
+		return
+	}
+
+	b.entries[b.start] = entry
+	b.start = (b.start + 1) % len(b.entries)
+}
+
+// returns all entries after seq in order. returns false when some of the
+// messages after seq have already been evicted.
+func (b *replayBuffer) since(seq uint64) ([]replayEntry, bool) {
+	if b.count == 0 {
+		return nil, true
+	}
+
+	oldest := b.entries[b.start].seq
+	if seq+1 < oldest {
+		return nil, false
+	}
+
+	entries := make([]replayEntry, 0, b.count)
+
+	for i := range b.count {
+		entry := b.entries[(b.start+i)%len(b.entries)]
+		if entry.seq > seq {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, true
+}
+
+// stores a sequenced broadcast for replay (must be called with lock held)
+func (h *Hub) recordReplay(sessionID string, msg *Message, excludeClientID string, writersOnly bool) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logger.ErrorErr(err, "failed to marshal message for replay",
+			"session_id", sessionID,
+			"message_type", msg.Type,
+		)
+		return
+	}
+
+	buffer, exists := h.replays[sessionID]
+	if !exists {
+		buffer = newReplayBuffer(replayBufferSize)
+		h.replays[sessionID] = buffer
+	}
+
+	buffer.add(replayEntry{
+		seq:             msg.Sequence,
+		msgType:         msg.Type,
+		data:            data,
+		excludeClientID: excludeClientID,
+		writersOnly:     writersOnly,
+	})
+}
+
+// replays the messages a reconnecting client missed since lastSeq. when the
+// gap is no longer covered by the replay buffer the client receives a fresh
+// session_state instead. previousClientID identifies the dropped connection
+// so its own messages are not echoed back.
+func (h *Hub) Resume(client *Client, lastSeq uint64, previousClientID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, exists := h.sessions[client.SessionID][client.ID]; !exists {
+		return ErrClientNotFound
+	}
+
+	// replaying on a live connection would deliver messages twice
+	if !client.awaitingResume {
+		return ErrNotResuming
+	}
+
+	client.awaitingResume = false
+	currentSeq := h.sessionSequences[client.SessionID]
+
+	// a client that has seen nothing needs the full state
+	var entries []replayEntry
+	covered := lastSeq > 0 && lastSeq <= currentSeq
+
+	if covered {
+		if buffer, exists := h.replays[client.SessionID]; exists {
+			entries, covered = buffer.since(lastSeq)
+		} else {
+			covered = lastSeq == currentSeq
+		}
+	}
+
+	if !covered {
+		logger.Info("resume gap not covered by replay buffer, sending session state",
+			"client_id", client.ID,
+			"session_id", client.SessionID,
+			"last_seq", lastSeq,
+			"current_seq", currentSeq,
+		)
+
+		return h.sendSessionState(client)
+	}
+
+	replayed := 0
+
+	for _, entry := range entries {
+		if entry.writersOnly && !client.CanWrite() {
+			continue
+		}
+
+		// e.g. this connection's own user_joined
+		if entry.excludeClientID == client.ID {
+			continue
+		}
+
+		data := entry.data
+
+		// the previous connection never received its own messages. for edits
+		// it was waiting on, send the ack it missed instead.
+		if previousClientID != "" && entry.excludeClientID == previousClientID {
+			if entry.msgType != TypeCodeOperation {
+				continue
+			}
+
+			ack, err := replayAck(client, entry)
+			if err != nil {
+				continue
+			}
+
+			data = ack
+		}
+
+		if err := client.sendJSON(data); err != nil {
+			return err
+		}
+
+		replayed++
+	}
+
+	completeMsg, err := NewMessage(TypeResumeComplete, client.SessionID, client.UserID, ResumeCompletePayload{
+		Replayed: replayed,
+		Seq:      currentSeq,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("client resumed session",
+		"client_id", client.ID,
+		"session_id", client.SessionID,
+		"last_seq", lastSeq,
+		"replayed", replayed,
+	)
+
+	return client.Send(completeMsg)
+}
+
+// builds the code_op_ack for an operation the client sent before reconnecting
+func replayAck(client *Client, entry replayEntry) ([]byte, error) {
+	var msg Message
+	if err := json.Unmarshal(entry.data, &msg); err != nil {
+		return nil, err
+	}
+
+	var payload CodeOperationPayload
+	if err := msg.UnmarshalPayload(&payload); err != nil {
+		return nil, err
+	}
+
+	ackMsg, err := NewMessage(TypeCodeOperationAck, client.SessionID, client.UserID, CodeOperationAckPayload{
+		Revision: payload.Revision,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(ackMsg)
+}
@@ -0,0 +1,141 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayBufferSince(t *testing.T) {
+	buffer := newReplayBuffer(3)
+
+	for seq := uint64(1); seq <= 5; seq++ {
+		buffer.add(replayEntry{seq: seq})
+	}
+
+	// oldest kept entry is 3, so resuming from 2 is still covered
+	entries, ok := buffer.since(2)
+	require.True(t, ok)
+	require.Len(t, entries, 3)
+	assert.Equal(t, uint64(3), entries[0].seq)
+	assert.Equal(t, uint64(5), entries[2].seq)
+
+	// seq 2 was evicted, so resuming from 1 is not covered
+	_, ok = buffer.since(1)
+	assert.False(t, ok)
+
+	entries, ok = buffer.since(5)
+	assert.True(t, ok)
+	assert.Empty(t, entries)
+}
+
+// drains all queued messages and returns them decoded
+func drainMessages(t *testing.T, client *Client) []Message {
+	t.Helper()
+
+	var messages []Message
+
+	for {
+		select {
+		case data := <-client.send:
+			var msg Message
+			require.NoError(t, json.Unmarshal(data, &msg))
+			messages = append(messages, msg)
+		default:
+			return messages
+		}
+	}
+}
+
+func TestHubResumeReplaysMissedMessages(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	host := &Client{
+		ID:          "host",
+		SessionID:   "session-1",
+		UserID:      "user-1",
+		DisplayName: "Host",
+		Role:        "host",
+		hub:         hub,
+		send:        make(chan []byte, 256),
+	}
+
+	hub.Register <- host
+	time.Sleep(100 * time.Millisecond)
+
+	chat, err := NewMessage(TypeChatMessage, "session-1", "user-1", ChatMessagePayload{Message: "first"})
+	require.NoError(t, err)
+	hub.BroadcastToSession("session-1", chat, "")
+	lastSeen := chat.Sequence
+
+	// viewer reconnects and asks to resume
+	viewer := &Client{
+		ID:                "viewer-2",
+		SessionID:         "session-1",
+		DisplayName:       "Viewer",
+		Role:              "viewer",
+		DeferSessionState: true,
+		hub:               hub,
+		send:              make(chan []byte, 256),
+	}
+
+	hub.Register <- viewer
+	time.Sleep(100 * time.Millisecond)
+
+	// messages broadcast before resume are held back
+	missed, err := NewMessage(TypeChatMessage, "session-1", "user-1", ChatMessagePayload{Message: "second"})
+	require.NoError(t, err)
+	hub.BroadcastToSession("session-1", missed, "")
+	assert.Empty(t, drainMessages(t, viewer))
+
+	require.NoError(t, hub.Resume(viewer, lastSeen, "viewer-1"))
+
+	messages := drainMessages(t, viewer)
+	require.Len(t, messages, 2)
+	assert.Equal(t, TypeChatMessage, messages[0].Type)
+	assert.Equal(t, missed.Sequence, messages[0].Sequence)
+	assert.Equal(t, TypeResumeComplete, messages[1].Type)
+
+	// live broadcasts are delivered again after resuming
+	live, err := NewMessage(TypeChatMessage, "session-1", "user-1", ChatMessagePayload{Message: "third"})
+	require.NoError(t, err)
+	hub.BroadcastToSession("session-1", live, "")
+	assert.Len(t, drainMessages(t, viewer), 1)
+}
+
+func TestHubResumeFallsBackToSessionState(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	client := &Client{
+		ID:                "client-2",
+		SessionID:         "session-1",
+		DisplayName:       "Viewer",
+		Role:              "viewer",
+		InitialCode:       "sound(\"bd\")",
+		DeferSessionState: true,
+		hub:               hub,
+		send:              make(chan []byte, 256),
+	}
+
+	hub.Register <- client
+	time.Sleep(100 * time.Millisecond)
+
+	// sequence numbers from an earlier incarnation of the session can't be replayed
+	require.NoError(t, hub.Resume(client, 42, "client-1"))
+
+	messages := drainMessages(t, client)
+	require.Len(t, messages, 1)
+	assert.Equal(t, TypeSessionState, messages[0].Type)
+
+	var state SessionStatePayload
+	require.NoError(t, messages[0].UnmarshalPayload(&state))
+	assert.Equal(t, "sound(\"bd\")", state.Code)
+	assert.Equal(t, "client-2", state.ClientID)
+}
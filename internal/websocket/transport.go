@@ -0,0 +1,170 @@
+package websocket
+
+import (
+	"math"
+	"time"
+
+	"codeberg.org/algopatterns/server/internal/logger"
+)
+
+const (
+	// strudel's default tempo of half a cycle per second
+	defaultCPM = 30
+
+	// tempo range clients may set
+	minCPM = 1
+	maxCPM = 600
+
+	// how far ahead playback is scheduled, so every client gets the
+	// transport_sync before the first cycle starts
+	transportLead = 200 * time.Millisecond
+)
+
+// starts the session's playback clock, or changes its tempo while playing,
+// and sends transport_sync to the session and its audience. cpm 0 keeps the
+// current tempo. a running clock changes tempo on the next cycle boundary so
+// patterns stay aligned; playing again at the same tempo just resends it
+func (h *Hub) StartTransport(sessionID string, cpm float64, displayName string) TransportSyncPayload {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+
+	h.transportMu.Lock()
+	transport, playing := h.transports[sessionID]
+
+	switch {
+	case !playing:
+		if cpm == 0 {
+			cpm = defaultCPM
+		}
+
+		start := now.Add(transportLead)
+		transport = &sessionTransport{cpm: cpm, originTime: start, countedUntil: start}
+		h.transports[sessionID] = transport
+	case cpm != 0 && cpm != transport.cpm:
+		// the first boundary far enough ahead for clients to hear about it,
+		// but never before the clock's own start
+		boundary := max(math.Ceil(transport.cycleAt(now.Add(transportLead))), transport.originCycle)
+		transport.originTime = transport.timeAt(boundary)
+		transport.originCycle = boundary
+		transport.cpm = cpm
+	}
+
+	payload := transport.payload(now)
+	h.transportMu.Unlock()
+
+	payload.DisplayName = displayName
+	h.sendTransportSync(sessionID, payload)
+
+	return payload
+}
+
+// stops the session's playback clock and sends transport_sync to the session
+// and its audience
+func (h *Hub) StopTransport(sessionID, displayName string) TransportSyncPayload {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+
+	h.transportMu.Lock()
+	h.endTransport(sessionID, now)
+	h.transportMu.Unlock()
+
+	payload := stoppedTransport(now)
+	payload.DisplayName = displayName
+	h.sendTransportSync(sessionID, payload)
+
+	return payload
+}
+
+// returns the session's playback clock as sent in session_state
+func (h *Hub) transportState(sessionID string) TransportSyncPayload {
+	h.transportMu.Lock()
+	defer h.transportMu.Unlock()
+
+	now := time.Now()
+	if transport, playing := h.transports[sessionID]; playing {
+		return transport.payload(now)
+	}
+
+	return stoppedTransport(now)
+}
+
+// forgets the playback clock of a session once nobody is connected to it
+// anymore (must be called with lock held)
+func (h *Hub) removeTransportIfEmpty(sessionID string) {
+	if _, exists := h.sessions[sessionID]; exists || len(h.audiences[sessionID]) > 0 {
+		return
+	}
+
+	h.transportMu.Lock()
+	h.endTransport(sessionID, time.Now())
+	h.transportMu.Unlock()
+}
+
+// counts the playback of a running clock and removes it (must be called with
+// transportMu held)
+func (h *Hub) endTransport(sessionID string, now time.Time) {
+	if transport, playing := h.transports[sessionID]; playing {
+		h.countPlayback(sessionID, transport, now)
+		delete(h.transports, sessionID)
+	}
+}
+
+// counts the playback since the clock was last counted as session activity
+// (must be called with transportMu held)
+func (h *Hub) countPlayback(sessionID string, transport *sessionTransport, now time.Time) {
+	if !now.After(transport.countedUntil) {
+		return
+	}
+
+	played := now.Sub(transport.countedUntil)
+	transport.countedUntil = now
+
+	h.activityMu.Lock()
+	h.sessionActivity(sessionID).playback += played
+	h.activityMu.Unlock()
+}
+
+// must be called with lock held
+func (h *Hub) sendTransportSync(sessionID string, payload TransportSyncPayload) {
+	msg, err := NewMessage(TypeTransportSync, sessionID, "", payload)
+	if err != nil {
+		logger.ErrorErr(err, "failed to create transport sync message", "session_id", sessionID)
+		return
+	}
+
+	h.broadcastToSession(sessionID, msg, "")
+}
+
+// returns the cycle the clock is at at t, negative before the clock started
+func (t *sessionTransport) cycleAt(at time.Time) float64 {
+	return t.originCycle + at.Sub(t.originTime).Minutes()*t.cpm
+}
+
+// returns when the clock reaches cycle
+func (t *sessionTransport) timeAt(cycle float64) time.Time {
+	return t.originTime.Add(time.Duration((cycle - t.originCycle) / t.cpm * float64(time.Minute)))
+}
+
+func (t *sessionTransport) payload(now time.Time) TransportSyncPayload {
+	return TransportSyncPayload{
+		Playing:     true,
+		CPM:         t.cpm,
+		OriginCycle: t.originCycle,
+		OriginTime:  unixMillis(t.originTime),
+		ServerTime:  unixMillis(now),
+	}
+}
+
+func stoppedTransport(now time.Time) TransportSyncPayload {
+	return TransportSyncPayload{CPM: defaultCPM, ServerTime: unixMillis(now)}
+}
+
+// milliseconds since the unix epoch, keeping sub-millisecond precision for
+// clock offset estimates
+func unixMillis(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1000
+}
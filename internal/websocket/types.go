@@ -1,12 +1,24 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"codeberg.org/algopatterns/server/algopatterns/sessions"
+	"codeberg.org/algopatterns/server/algopatterns/strudels"
+	"codeberg.org/algopatterns/server/internal/agent"
+	"codeberg.org/algopatterns/server/internal/moderation"
+	"codeberg.org/algopatterns/server/internal/ot"
+	"codeberg.org/algopatterns/server/internal/presence"
+	"codeberg.org/algopatterns/server/internal/quota"
+	"codeberg.org/algopatterns/server/internal/strudel"
 )
 
 // message type constants for websocket communication
@@ -23,6 +35,12 @@ const (
 	// is sent when a user sends a chat message
 	TypeChatMessage = "chat_message"
 
+	// is sent when a user replies to a chat message
+	TypeChatReply = "chat_reply"
+
+	// is sent when a user adds or removes an emoji reaction on a chat message
+	TypeChatReaction = "chat_reaction"
+
 	// is sent when an error occurs
 	TypeError = "error"
 
@@ -35,6 +53,10 @@ const (
 	// is sent by server before shutdown
 	TypeServerShutdown = "server_shutdown"
 
+	// is sent by server while draining, asking the client to reconnect to
+	// another instance
+	TypeReconnectHint = "reconnect_hint"
+
 	// is sent to connecting client with session info
 	TypeSessionState = "session_state"
 
@@ -44,6 +66,14 @@ const (
 	// is sent when host/co-author stops playback
 	TypeStop = "stop"
 
+	// is sent to the session when its playback clock starts, stops or
+	// changes tempo
+	TypeTransportSync = "transport_sync"
+
+	// is sent by clients to measure their clock offset to the server, which
+	// answers with the same type
+	TypeClockSync = "clock_sync"
+
 	// is sent when host ends the session
 	TypeSessionEnded = "session_ended"
 
@@ -52,6 +82,73 @@ const (
 
 	// is sent when a user moves their cursor
 	TypeCursorPosition = "cursor_position"
+
+	// is sent when a user applies a character-level edit to the code
+	TypeCodeOperation = "code_op"
+
+	// is sent to the author of a code_op once it has been applied
+	TypeCodeOperationAck = "code_op_ack"
+
+	// is sent by a reconnecting client with the last sequence number it saw
+	TypeResume = "resume"
+
+	// is sent after missed messages have been replayed to a resuming client
+	TypeResumeComplete = "resume_complete"
+
+	// is sent by a client to ask the AI assistant for code
+	TypeAgentRequest = "agent_request"
+
+	// is sent to the requesting client as generated tokens arrive
+	TypeAgentResponseChunk = "agent_response_chunk"
+
+	// is sent to the requesting client once generation has finished
+	TypeAgentResponseDone = "agent_response_done"
+
+	// is sent when host/co-author reverts the code to the previous checkpoint
+	TypeUndo = "undo"
+
+	// is sent when host/co-author re-applies an undone checkpoint
+	TypeRedo = "redo"
+
+	// is sent when a writer's cursor moves (presence only, not persisted)
+	TypeCursorUpdate = "cursor_update"
+
+	// is sent when a writer's text selection changes (presence only, not persisted)
+	TypeSelectionUpdate = "selection_update"
+
+	// is sent to a writer with lint results for the code they just changed
+	TypeCodeValidation = "code_validation"
+
+	// is sent to participants when the number of audience listeners changes
+	TypeListenerCount = "listener_count"
+
+	// is sent to a writer when its code update rate limit changes
+	TypeRateLimitUpdate = "rate_limit_update"
+
+	// is sent to participants when a participant's connection gets better,
+	// worse or is dropped
+	TypeConnectionQuality = "connection_quality"
+
+	// is sent to a participant when the host changes their permissions
+	TypePermissionsUpdated = "permissions_updated"
+
+	// is sent by a writer to claim a line range or pattern block
+	TypeLockRegion = "lock_region"
+
+	// is sent by a writer to release a region lock
+	TypeUnlockRegion = "unlock_region"
+
+	// is sent to the session when region locks change or move
+	TypeRegionLocks = "region_locks"
+
+	// is sent to the host when someone asks to join, and when a request is answered
+	TypeJoinRequest = "join_request"
+
+	// is sent to the session and its spectators when the host changes chat settings
+	TypeChatSettings = "chat_settings"
+
+	// is sent to a participant when the host mutes or unmutes them
+	TypeChatMuted = "chat_muted"
 )
 
 // client connection constants
@@ -62,19 +159,75 @@ const (
 	// time allowed to read the next pong message from the peer
 	pongWait = 60 * time.Second
 
-	// send pings to peer with this period (must be less than pongWait)
-	pingPeriod = (pongWait * 9) / 10
+	// send pings to peer with this period (must be less than pongWait). short
+	// enough to measure round trips and notice dead connections early
+	pingPeriod = 10 * time.Second
 
 	// maximum message size allowed from peer
 	maxMessageSize = 512 * 1024 // 512 KB
 
 	// rate limiting constants
-	maxCodeUpdatesPerSecond  = 30 // maximum code updates per second
+	maxCodeUpdatesPerSecond  = 30 // code updates per second a client starts with, see adaptive limits
 	maxChatMessagesPerMinute = 20 // maximum chat messages per minute
 
 	// content size limits
 	maxCodeSize        = 100 * 1024 // 100 KB maximum code size
 	maxChatMessageSize = 5000       // 5000 characters maximum chat message size
+	maxReactionSize    = 32         // characters, enough for emoji ZWJ sequences
+)
+
+// adaptive rate limit constants. code update limits are halved for writers
+// whose session or own send queue is backed up and raised for writers using
+// most of theirs while the session keeps up
+const (
+	minCodeUpdatesPerSecond  = 5
+	peakCodeUpdatesPerSecond = 60
+	codeUpdateRateStep       = 10
+
+	// how often limits are adjusted
+	rateLimitInterval = 2 * time.Second
+
+	// queued frames per send queue (of 256) at which a client, or on average
+	// a session, counts as backed up
+	clientBacklogLimit  = 64
+	sessionBacklogLimit = 32
+)
+
+// connection quality constants
+const (
+	// a client that misses this many pings in a row is disconnected, well
+	// before pongWait would close it
+	maxMissedPings = 3
+
+	// a send queue holding this many frames (of 256) at this many pings in a
+	// row belongs to a client that stopped reading, so it is disconnected
+	stalledQueueDepth  = 192
+	maxStalledQueueing = 3
+
+	// round trips up to these count as good or fair, longer ones as poor
+	goodConnectionRTT = 150 * time.Millisecond
+	fairConnectionRTT = 400 * time.Millisecond
+)
+
+// presence constants
+const (
+	// minimum time between presence broadcasts per client and message type
+	presenceThrottleInterval = 50 * time.Millisecond
+
+	// maximum number of ranges in a selection_update (multi-cursor editors)
+	maxSelectionRanges = 32
+
+	// maximum number of region locks a client may hold at once
+	maxRegionLocksPerClient = 5
+)
+
+// wire format constants
+const (
+	// subprotocol selecting msgpack binary frames; clients that don't offer it get JSON text frames
+	SubprotocolMsgpack = "algopatterns.msgpack"
+
+	// frames smaller than this are sent uncompressed, since deflate gains little on them
+	compressionThreshold = 1024
 )
 
 // hub connection limit constants
@@ -83,6 +236,49 @@ const (
 	maxConnectionsPerIP   = 10
 )
 
+// audience constants
+const (
+	// audience connections are counted separately from participants, since a
+	// venue may put hundreds of listeners behind one address
+	maxAudiencePerSession       = 2000
+	maxAudienceConnectionsPerIP = 500
+
+	// how often participants are told about a changed listener count
+	listenerCountInterval = 5 * time.Second
+)
+
+// how often dirty session documents are handed to the snapshot callback
+const documentSnapshotInterval = 5 * time.Second
+
+// an edited session document is handed to the session snapshot callback
+// after this long or this many edits, whichever comes first
+const (
+	sessionSnapshotInterval = 10 * time.Minute
+	sessionSnapshotEdits    = 500
+)
+
+// how often session activity is sampled and handed to the activity recorder,
+// and how long recording may take
+const (
+	activityInterval      = time.Minute
+	activityRecordTimeout = 10 * time.Second
+)
+
+// number of sequenced messages kept per session for replay on reconnect
+const replayBufferSize = 256
+
+// maximum time a streamed agent response may take
+const agentRequestTimeout = 2 * time.Minute
+
+// maximum time a code history operation may take
+const codeHistoryTimeout = 5 * time.Second
+
+// maximum time a presence registry lookup may take before falling back to local state
+const presenceLookupTimeout = 2 * time.Second
+
+// number of sessions listed in hub stats
+const statsBusiestSessions = 20
+
 // errors
 var (
 	ErrSessionNotFound         = errors.New("session not found")
@@ -95,8 +291,40 @@ var (
 	ErrConnectionClosed        = errors.New("connection closed")
 	ErrRateLimitExceeded       = errors.New("rate limit exceeded")
 	ErrCodeTooLarge            = errors.New("code too large")
+	ErrDocumentNotFound        = errors.New("session document not found")
+	ErrNotResuming             = errors.New("connection was not opened for resume")
+	ErrAgentRequestInProgress  = errors.New("agent request already in progress")
+	ErrHistoryUnavailable      = errors.New("code history unavailable")
+	ErrRevisionConflict        = errors.New("document changed since the given revision")
+	ErrNothingToUndo           = errors.New("nothing to undo")
+	ErrNothingToRedo           = errors.New("nothing to redo")
+	ErrInvalidChatReference    = errors.New("invalid chat message reference")
+	ErrContentBlocked          = errors.New("content blocked by moderation")
+	ErrInvalidRegion           = errors.New("invalid line range")
+	ErrBlockNotFound           = errors.New("pattern block not found")
+	ErrTooManyRegionLocks      = errors.New("too many region locks")
+	ErrRegionLockNotFound      = errors.New("region lock not found")
+	ErrRegionLocked            = errors.New("region is locked")
+	ErrMuted                   = errors.New("participant is muted")
+	ErrInvalidTempo            = errors.New("tempo out of range")
+	ErrSlowMode                = errors.New("chat slow mode")
+	ErrChatBackedUp            = errors.New("chat backed up")
+	ErrFeatureDisabled         = errors.New("feature disabled")
 )
 
+// is returned when an edit or lock request touches another client's lock
+type RegionLockedError struct {
+	Lock RegionLock
+}
+
+func (e *RegionLockedError) Error() string {
+	return fmt.Sprintf("lines %d-%d are locked by %s", e.Lock.StartLine, e.Lock.EndLine, e.Lock.DisplayName)
+}
+
+func (e *RegionLockedError) Unwrap() error {
+	return ErrRegionLocked
+}
+
 // represents a websocket message with typed payload
 type Message struct {
 	Type      string          `json:"type"`
@@ -105,7 +333,20 @@ type Message struct {
 	UserID    string          `json:"user_id,omitempty"`
 	Timestamp time.Time       `json:"timestamp"`
 	Sequence  uint64          `json:"seq,omitempty"`
-	Payload   json.RawMessage `json:"payload"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+
+	// carries the trace of the handler processing this message (internal only)
+	ctx context.Context
+}
+
+// msgpack wire form of Message; the payload is a native map instead of embedded JSON
+type binaryMessage struct {
+	Type      string    `codec:"type"`
+	SessionID string    `codec:"session_id"`
+	UserID    string    `codec:"user_id,omitempty"`
+	Timestamp time.Time `codec:"timestamp"`
+	Sequence  uint64    `codec:"seq,omitempty"`
+	Payload   any       `codec:"payload"`
 }
 
 // contains code update information
@@ -115,8 +356,65 @@ type CodeUpdatePayload struct {
 	CursorCol   int    `json:"cursor_col,omitempty"`
 	DisplayName string `json:"display_name,omitempty"`
 	UserID      string `json:"user_id,omitempty"`
-	Role        string `json:"role,omitempty"`   // "host", "co-author" - for cursor tracking
-	Source      string `json:"source,omitempty"` // 'typed' | 'loaded_strudel' | 'forked' | 'paste'
+	Role        string `json:"role,omitempty"`     // "host", "co-author" - for cursor tracking
+	Source      string `json:"source,omitempty"`   // 'typed' | 'loaded_strudel' | 'forked' | 'paste' | 'undo' | 'redo' | 'restore'
+	Revision    int    `json:"revision,omitempty"` // document revision after the update (added by backend)
+}
+
+// contains a character-level edit to the session code
+type CodeOperationPayload struct {
+	Revision    int          `json:"revision"`                             // revision the operation is based on (client) or produces (broadcast)
+	Operation   ot.Operation `json:"operation" swaggertype:"array,object"` // ot.js format: n retains, -n deletes, "s" inserts
+	UserID      string       `json:"user_id,omitempty"`
+	DisplayName string       `json:"display_name,omitempty"`
+	Role        string       `json:"role,omitempty"`
+}
+
+// claims a 1-indexed, inclusive line range, or a named pattern block if block is set
+type LockRegionPayload struct {
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Block     string `json:"block,omitempty"`
+}
+
+// releases a region lock
+type UnlockRegionPayload struct {
+	LockID string `json:"lock_id"`
+}
+
+// contains all region locks of a session
+type RegionLocksPayload struct {
+	Locks []RegionLock `json:"locks"`
+}
+
+// a line range of the session code only its owner may edit
+type RegionLock struct {
+	ID          string `json:"id"`
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	Block       string `json:"block,omitempty"` // pattern block the lock was claimed by
+	UserID      string `json:"user_id,omitempty"`
+	DisplayName string `json:"display_name"`
+
+	// owning connection (internal only, client ids are secret)
+	clientID string
+}
+
+// acknowledges that a code_op was applied
+type CodeOperationAckPayload struct {
+	Revision int `json:"revision"` // revision produced by the acknowledged operation
+}
+
+// contains lint results for the session code at a revision
+type CodeValidationPayload struct {
+	Valid       bool                 `json:"valid"`    // false when any diagnostic is an error
+	Revision    int                  `json:"revision"` // document revision the code was linted at
+	Diagnostics []strudel.Diagnostic `json:"diagnostics"`
+}
+
+// contains the document revision an undo or redo was requested against
+type UndoRedoPayload struct {
+	Revision int `json:"revision"` // latest revision the client has seen
 }
 
 // contains information about a newly joined user
@@ -134,7 +432,17 @@ type UserLeftPayload struct {
 
 // contains a chat message from a user
 type ChatMessagePayload struct {
-	Message     string `json:"message"`
+	Message         string `json:"message"`
+	DisplayName     string `json:"display_name,omitempty"`
+	MessageID       string `json:"message_id,omitempty"`        // assigned by the server
+	ParentMessageID string `json:"parent_message_id,omitempty"` // message replied to (chat_reply)
+}
+
+// adds or removes an emoji reaction on a chat message
+type ChatReactionPayload struct {
+	MessageID   string `json:"message_id"`
+	Emoji       string `json:"emoji"`
+	Removed     bool   `json:"removed,omitempty"`
 	DisplayName string `json:"display_name,omitempty"`
 }
 
@@ -143,21 +451,106 @@ type ServerShutdownPayload struct {
 	Reason string `json:"reason"`
 }
 
+// tells a client when to reconnect and where to resume from
+type ReconnectHintPayload struct {
+	Reason    string `json:"reason"`
+	BackoffMs int64  `json:"backoff_ms"` // wait this long before reconnecting, spreads the load on the new instance
+	Seq       uint64 `json:"seq"`        // last sequence number of the session, for the resume message
+	ClientID  string `json:"client_id"`  // for the resume message
+}
+
 // contains session info sent to connecting client
 type SessionStatePayload struct {
 	Code            string                    `json:"code"`
+	Revision        int                       `json:"revision"`
+	Seq             uint64                    `json:"seq"`       // last sequence number broadcast to the session
+	ClientID        string                    `json:"client_id"` // pass back in resume after reconnecting
 	YourRole        string                    `json:"your_role"`
 	YourDisplayName string                    `json:"your_display_name"`
+	YourPermissions []string                  `json:"your_permissions"`
 	Participants    []SessionStateParticipant `json:"participants"`
 	ChatHistory     []SessionStateChatMessage `json:"chat_history"`
+	ListenerCount   int                       `json:"listener_count"` // audience clients connected to this session
+	RegionLocks     []RegionLock              `json:"region_locks"`
+	ChatSlowMode    int                       `json:"chat_slow_mode_seconds"` // 0 when slow mode is off
+	Muted           bool                      `json:"muted,omitempty"`        // the host muted this client in chat
+	Transport       TransportSyncPayload      `json:"transport"`
+	CodeUpdateRate  int                       `json:"code_updates_per_second,omitempty"` // rate limit of code updates, omitted for audiences
+}
+
+// contains the number of audience clients listening to a session
+type ListenerCountPayload struct {
+	Count int `json:"count"`
+}
+
+// describes a participant's connection after its quality changed
+type ConnectionQualityPayload struct {
+	UserID      string `json:"user_id,omitempty"`
+	DisplayName string `json:"display_name"`
+	Quality     string `json:"quality"` // good, fair, poor or lost
+	RTTMs       int64  `json:"rtt_ms"`
+	MissedPings int    `json:"missed_pings"`
+}
+
+// contains a writer's code update rate limit after the server adjusted it
+type RateLimitUpdatePayload struct {
+	CodeUpdatesPerSecond int    `json:"code_updates_per_second"`
+	Reason               string `json:"reason"` // backlog or headroom
+}
+
+// contains a participant's permissions after the host changed them
+type PermissionsUpdatedPayload struct {
+	Permissions []string `json:"permissions"`
+}
+
+// contains the chat settings of a session
+type ChatSettingsPayload struct {
+	SlowModeSeconds int `json:"slow_mode_seconds"` // 0 when slow mode is off
+}
+
+// tells a participant whether the host muted them
+type ChatMutedPayload struct {
+	Muted bool `json:"muted"`
+}
+
+// contains a request to join the session, sent to the host
+type JoinRequestPayload struct {
+	RequestID   string `json:"request_id"`
+	UserID      string `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	Message     string `json:"message,omitempty"`
+	Status      string `json:"status"`     // pending, approved or denied
+	ExpiresAt   int64  `json:"expires_at"` // unix millis; pending requests can't be answered after
+}
+
+// contains the position a reconnecting client wants to resume from
+type ResumePayload struct {
+	LastSeq  uint64 `json:"last_seq"`            // last sequence number the client received
+	ClientID string `json:"client_id,omitempty"` // client_id from the previous connection's session_state
+}
+
+// contains the outcome of a resume request
+type ResumeCompletePayload struct {
+	Replayed int    `json:"replayed"` // number of messages replayed
+	Seq      uint64 `json:"seq"`      // sequence number the client is now caught up to
 }
 
 // represents a chat message in the chat history
 type SessionStateChatMessage struct {
-	DisplayName string `json:"display_name"`
-	AvatarURL   string `json:"avatar_url,omitempty"`
-	Content     string `json:"content"`
-	Timestamp   int64  `json:"timestamp"` // Unix milliseconds
+	ID              string                     `json:"id,omitempty"`
+	DisplayName     string                     `json:"display_name"`
+	AvatarURL       string                     `json:"avatar_url,omitempty"`
+	Content         string                     `json:"content"`
+	ParentMessageID string                     `json:"parent_message_id,omitempty"`
+	Reactions       []SessionStateChatReaction `json:"reactions,omitempty"`
+	Timestamp       int64                      `json:"timestamp"` // Unix milliseconds
+}
+
+// represents an emoji reaction in the chat history
+type SessionStateChatReaction struct {
+	Emoji       string `json:"emoji"`
+	UserID      string `json:"user_id,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
 }
 
 // represents a participant in session_state
@@ -165,11 +558,35 @@ type SessionStateParticipant struct {
 	UserID      string `json:"user_id,omitempty"`
 	DisplayName string `json:"display_name"`
 	Role        string `json:"role"`
+	RTTMs       int64  `json:"rtt_ms,omitempty"`             // last ping round trip, omitted until measured or for other instances
+	Quality     string `json:"connection_quality,omitempty"` // good, fair or poor
 }
 
-// contains playback start information
+// contains playback start information. clients may send cpm to set the tempo
 type PlayPayload struct {
-	DisplayName string `json:"display_name"`
+	DisplayName string  `json:"display_name"`
+	CPM         float64 `json:"cpm,omitempty"`
+}
+
+// the session's playback clock. clients play cycle origin_cycle at
+// origin_time and advance cpm cycles per minute from there. times are
+// milliseconds since the unix epoch on the server's clock
+type TransportSyncPayload struct {
+	Playing     bool    `json:"playing"`
+	CPM         float64 `json:"cpm"`
+	OriginCycle float64 `json:"origin_cycle"`
+	OriginTime  float64 `json:"origin_time"`
+	ServerTime  float64 `json:"server_time"`
+	DisplayName string  `json:"display_name,omitempty"` // who changed the transport
+}
+
+// contains a clock offset exchange. clients send client_time, the server
+// fills in when it received and answered the message (milliseconds since the
+// unix epoch)
+type ClockSyncPayload struct {
+	ClientTime    float64 `json:"client_time"`
+	ServerReceive float64 `json:"server_receive,omitempty"`
+	ServerSend    float64 `json:"server_send,omitempty"`
 }
 
 // contains playback stop information
@@ -185,7 +602,7 @@ type SessionEndedPayload struct {
 // contains paste lock status change
 type PasteLockChangedPayload struct {
 	Locked bool   `json:"locked"`
-	Reason string `json:"reason,omitempty"` // "paste_detected", "edits_sufficient", "ttl_expired"
+	Reason string `json:"reason,omitempty"` // "paste_detected", "edits_sufficient", "ttl_expired", "ownership_verified", "appeal_approved"
 }
 
 // contains cursor position information for collaboration
@@ -197,6 +614,118 @@ type CursorPositionPayload struct {
 	Role        string `json:"role,omitempty"`         // role (added by backend)
 }
 
+// contains a writer's cursor position for presence
+type CursorUpdatePayload struct {
+	Line        int    `json:"line"`                   // 1-indexed line number
+	Col         int    `json:"col"`                    // 0-indexed column number
+	UserID      string `json:"user_id,omitempty"`      // user ID (added by backend)
+	DisplayName string `json:"display_name,omitempty"` // display name (added by backend)
+	Role        string `json:"role,omitempty"`         // role (added by backend)
+}
+
+// a selected span of code, from anchor (where the selection started) to head
+type SelectionRange struct {
+	AnchorLine int `json:"anchor_line"` // 1-indexed line number
+	AnchorCol  int `json:"anchor_col"`  // 0-indexed column number
+	HeadLine   int `json:"head_line"`
+	HeadCol    int `json:"head_col"`
+}
+
+// contains a writer's current selections for presence
+type SelectionUpdatePayload struct {
+	Ranges      []SelectionRange `json:"ranges"`                 // empty when nothing is selected
+	UserID      string           `json:"user_id,omitempty"`      // user ID (added by backend)
+	DisplayName string           `json:"display_name,omitempty"` // display name (added by backend)
+	Role        string           `json:"role,omitempty"`         // role (added by backend)
+}
+
+// contains a request for AI code generation
+type AgentRequestPayload struct {
+	RequestID           string          `json:"request_id,omitempty"` // echoed back in chunks for correlation
+	UserQuery           string          `json:"user_query"`
+	EditorState         string          `json:"editor_state"`
+	ConversationHistory []agent.Message `json:"conversation_history,omitempty"`
+	Provider            string          `json:"provider,omitempty"`         // see GET /agent/providers
+	Model               string          `json:"model,omitempty"`            // defaults to the provider's default model
+	ProviderAPIKey      string          `json:"provider_api_key,omitempty"` // BYOK key
+	ForkedFromID        string          `json:"forked_from_id,omitempty"`   // blocks AI on restricted forks
+}
+
+// contains a piece of generated text
+type AgentResponseChunkPayload struct {
+	RequestID string `json:"request_id,omitempty"`
+	Content   string `json:"content"`
+}
+
+// contains the final generated content and metadata
+type AgentResponseDonePayload struct {
+	RequestID         string                   `json:"request_id,omitempty"`
+	Code              string                   `json:"code"` // content extracted from markdown if needed
+	IsCodeResponse    bool                     `json:"is_code_response"`
+	Model             string                   `json:"model"`
+	InputTokens       int                      `json:"input_tokens"`
+	OutputTokens      int                      `json:"output_tokens"`
+	StrudelReferences []agent.StrudelReference `json:"strudel_references,omitempty"`
+	DocReferences     []agent.DocReference     `json:"doc_references,omitempty"`
+	Cached            bool                     `json:"cached,omitempty"`     // served from the response cache
+	Degraded          bool                     `json:"degraded,omitempty"`   // generated by the fallback provider during an outage
+	RateLimit         *AgentRateLimit          `json:"rate_limit,omitempty"` // omitted when the request isn't metered
+}
+
+// the requester's AI quota after a generation
+type AgentRateLimit struct {
+	Tier    string            `json:"tier"` // "anonymous", "free", "payg" or "byok"
+	Daily   quota.PeriodUsage `json:"daily"`
+	Monthly quota.PeriodUsage `json:"monthly"`
+	Spend   quota.SpendUsage  `json:"spend"` // estimated model cost this month
+}
+
+// looks up the cc signal of a parent strudel
+type CCSignalLookup interface {
+	GetStrudelCCSignal(ctx context.Context, strudelID string) (*strudels.CCSignal, error)
+}
+
+// counts AI generations against user quotas (implemented by quota.Service)
+type QuotaTracker interface {
+	Reserve(ctx context.Context, subject quota.Subject) (*quota.Result, error)
+	Release(ctx context.Context, subject quota.Subject)
+	Record(ctx context.Context, subject quota.Subject, usage quota.Usage)
+}
+
+// stores session code checkpoints for undo/redo
+type CodeHistory interface {
+	RecordCodeRevision(ctx context.Context, sessionID, code string) error
+	UndoCodeRevision(ctx context.Context, sessionID string) (string, bool, error)
+	RedoCodeRevision(ctx context.Context, sessionID string) (string, bool, error)
+}
+
+// stores sampled session activity for analytics (implemented by buffer.SessionBuffer)
+type ActivityRecorder interface {
+	RecordActivity(ctx context.Context, sample *sessions.ActivitySample) error
+}
+
+// reports when a session's chat is backed up waiting to be persisted
+// (implemented by buffer.SessionBuffer)
+type ChatBackpressure interface {
+	Backpressure(sessionID string) bool
+}
+
+// screens user content and queues it for review (implemented by moderation.Service)
+type ContentModerator interface {
+	Screen(ctx context.Context, content moderation.Content) moderation.Verdict
+	Report(ctx context.Context, content moderation.Content, verdict moderation.Verdict)
+}
+
+// shares connections with other server instances so limits and session
+// activity account for clients connected elsewhere
+type PresenceRegistry interface {
+	Track(conn presence.Connection)
+	Untrack(clientID string)
+	RemoteSessionConnections(ctx context.Context, sessionID string) ([]presence.Connection, error)
+	RemoteUserConnectionCount(ctx context.Context, userID string) (int, error)
+	RemoteIPConnectionCount(ctx context.Context, ipAddress string) (int, error)
+}
+
 // represents a websocket client connection
 type Client struct {
 	// unique identifier for this client
@@ -211,9 +740,22 @@ type Client struct {
 	// display name for this client
 	DisplayName string
 
-	// role in the session (host, co-author, viewer)
+	// role in the session (host, co-author, viewer, spectator, audience, embed)
 	Role string
 
+	// participant record of this client, so permission changes and mutes
+	// reach it live (empty for anonymous hosts, audiences and embeds)
+	ParticipantID string
+
+	// true while the host has muted the client in chat
+	muted atomic.Bool
+
+	// the session's chat slow mode in seconds when the client connected
+	ChatSlowMode int
+
+	// permissions set by the host, nil uses the role preset
+	permissions atomic.Pointer[sessions.Permission]
+
 	// whether this client has an authenticated user account
 	IsAuthenticated bool
 
@@ -226,6 +768,16 @@ type Client struct {
 	// initial chat history to send on connect
 	InitialChatHistory []SessionStateChatMessage
 
+	// skip session_state on connect because the client will send a resume message
+	DeferSessionState bool
+
+	// true until a deferred client has resumed; such clients are skipped by
+	// broadcasts and catch up from the replay buffer (guarded by hub.mu)
+	awaitingResume bool
+
+	// true when the client negotiated SubprotocolMsgpack and exchanges binary frames
+	binary bool
+
 	// websocket connection
 	conn *websocket.Conn
 
@@ -241,14 +793,91 @@ type Client struct {
 	// flag indicating if client is closed
 	closed bool
 
+	// number of errors sent, so the hub can tell whether a failed handler
+	// already told the client why
+	errorsSent atomic.Uint64
+
 	// rate limiting: code update timestamps (sliding window)
 	codeUpdateTimestamps []time.Time
 
+	// code updates allowed per second, adjusted with the load (0 until first
+	// adjusted, meaning maxCodeUpdatesPerSecond)
+	codeUpdateLimit atomic.Int32
+
+	// ping round trips and misses, for connection quality
+	pings pingStats
+
 	// rate limiting: chat message timestamps (sliding window)
 	chatMessageTimestamps []time.Time
+
+	// true while an agent response is being streamed to this client
+	agentRequestActive bool
+
+	// presence throttling state per message type
+	presence map[string]*presenceThrottle
+
+	// diagnostics of the last code_validation sent, to skip unchanged results (guarded by mu)
+	lastValidation string
+
+	// code_update held back for clients that can't edit
+	coalesce codeCoalescer
+}
+
+// limits how often a client's presence updates are broadcast. updates that
+// arrive too soon are held back and only the latest one is sent.
+type presenceThrottle struct {
+	lastSent time.Time
+
+	// latest held-back update, sent when timer fires
+	pending *Message
+	timer   *time.Timer
+}
+
+// ping statistics of a client, written by its write pump and pong handler
+type pingStats struct {
+	mu sync.Mutex
+
+	// when the unanswered ping was sent, zero once answered
+	sentAt time.Time
+
+	rtt     time.Duration
+	missed  int
+	stalled int // pings in a row that found the send queue stalled
+
+	// quality last announced to the session
+	quality string
+}
+
+// limits how often code_update reaches a client that can't edit. guards the
+// send channel too, so held-back updates keep their place among the
+// messages sent after them. acquire before Client.mu
+type codeCoalescer struct {
+	mu       sync.Mutex
+	lastSent time.Time
+
+	// latest held-back update, sent when timer fires. a timer firing after
+	// the client closed finds the send channel closed and drops it
+	pending []byte
+	timer   *time.Timer
 }
 
 // maintains the set of active clients and broadcasts messages to sessions
+// connection counts of this server instance
+type HubStats struct {
+	Sessions           int            `json:"sessions"`
+	Connections        int            `json:"connections"`
+	AuthenticatedUsers int            `json:"authenticated_users"`
+	UniqueIPs          int            `json:"unique_ips"`
+	Listeners          int            `json:"listeners"` // audience clients, not included in connections
+	BusiestSessions    []SessionStats `json:"busiest_sessions"`
+}
+
+// connections of one session on this server instance
+type SessionStats struct {
+	SessionID string `json:"session_id"`
+	Clients   int    `json:"clients"`
+}
+
 type Hub struct {
 	// registered clients by session ID and client ID
 	sessions map[string]map[string]*Client
@@ -283,11 +912,146 @@ type Hub struct {
 	// sequence numbers per session for message ordering
 	sessionSequences map[string]uint64
 
+	// shared code documents per session for merging concurrent edits
+	documents map[string]*sessionDocument
+
+	// recent sequenced messages per session for replay on reconnect
+	replays map[string]*replayBuffer
+
+	// read-only audience clients by session ID and client ID, kept apart from
+	// sessions so participant broadcasts and lists don't iterate them
+	audiences map[string]map[string]*Client
+
+	// connection tracking: IP address -> count of audience connections
+	audienceIPConnections map[string]int
+
+	// listener count last sent to each session's participants
+	listenerCounts map[string]int
+
+	// set while the instance is draining for a deploy; new connections are
+	// refused and clients are told to reconnect elsewhere
+	draining atomic.Bool
+
+	// chat slow mode per session (guarded by chatMu, acquire after mu)
+	chats  map[string]*sessionChat
+	chatMu sync.Mutex
+
+	// playback clock per session (guarded by transportMu, acquire after mu)
+	transports  map[string]*sessionTransport
+	transportMu sync.Mutex
+
+	// activity counted since the last sample, per session (guarded by
+	// activityMu, acquire after mu and transportMu)
+	activity   map[string]*sessionActivity
+	activityMu sync.Mutex
+
 	// callback for client disconnect (e.g., save code to DB)
 	onClientDisconnect func(client *Client)
 
 	// callback for client registered (e.g., send paste lock status)
 	onClientRegistered func(client *Client)
+
+	// callback for persisting document snapshots (e.g., write code to buffer)
+	onDocumentSnapshot func(sessionID, code string)
+
+	// callback for storing restorable session snapshots
+	onSessionSnapshot func(sessionID, code string)
+
+	// callback for storing AI assistant exchanges in the session transcript
+	onAgentResponse func(exchange *sessions.AgentExchange)
+
+	// checkpoint store for undo/redo (nil disables undo/redo)
+	codeHistory CodeHistory
+
+	// cross-instance connection registry (nil limits the hub to local connections)
+	presenceRegistry PresenceRegistry
+
+	// analytics store for session activity (nil disables sampling)
+	activityRecorder ActivityRecorder
+
+	// turns chat away while the buffer catches up (nil accepts all chat)
+	chatBackpressure ChatBackpressure
+
+	// how long code_update is held back for clients that can't edit (0 sends
+	// every update)
+	coalesceWindow time.Duration
+}
+
+// the server-side copy of a session's code
+type sessionDocument struct {
+	// serializes edits to this document; acquire before hub.mu
+	mu sync.Mutex
+
+	// text and revision may also be read while holding hub.mu
+	doc *ot.Document
+
+	// true when the document changed since the last snapshot (guarded by hub.mu)
+	dirty bool
+
+	// edits since the last session snapshot and when it was taken (guarded by hub.mu)
+	edits      int
+	snapshotAt time.Time
+
+	// true once the code has been stored in the code history (guarded by mu)
+	checkpointed bool
+
+	// line ranges claimed by writers (guarded by hub.mu)
+	locks []*RegionLock
+}
+
+// the chat slow mode of a session and when its participants last chatted
+type sessionChat struct {
+	slowMode time.Duration
+
+	// last chat message per user, participant or client
+	lastMessage map[string]time.Time
+}
+
+// the playback clock of a session while it's playing
+type sessionTransport struct {
+	cpm float64
+
+	// playback up to here has been counted as session activity
+	countedUntil time.Time
+
+	// cycle the clock was at, or will be at, at originTime
+	originCycle float64
+	originTime  time.Time
+}
+
+// activity of a session since it was last sampled
+type sessionActivity struct {
+	codeEdits     int
+	agentRequests int
+	chatMessages  int
+	playback      time.Duration
+}
+
+// a sequenced message kept for replay
+type replayEntry struct {
+	seq     uint64
+	msgType string
+	data    []byte
+
+	// client the message was not delivered to (usually the sender)
+	excludeClientID string
+
+	// message was only delivered to hosts and co-authors
+	writersOnly bool
+}
+
+// fixed-size ring of the most recent sequenced messages of a session
+type replayBuffer struct {
+	entries []replayEntry
+	start   int
+	count   int
+}
+
+// describes the result of an edit applied to a session document
+type DocumentChange struct {
+	PreviousCode string
+	Code         string
+	Revision     int
 }
 
 // processes a specific message type
@@ -12,50 +12,43 @@ import (
 )
 
 func getAllowedWebSocketOrigins() []string {
-	if envOrigins := os.Getenv("ALLOWED_ORIGINS"); envOrigins != "" {
-		origins := strings.Split(envOrigins, ",")
-
-		for i := range origins {
-			origins[i] = strings.TrimSpace(origins[i])
+	var origins []string
+	for _, origin := range strings.Split(os.Getenv("ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
 		}
-
-		return origins
 	}
 
-	return []string{}
+	return origins
 }
 
+// guards against cross-site WebSocket hijacking: browsers always send the
+// page's Origin with an upgrade, so a page on another site can't open a
+// connection in the user's name. requests without an Origin don't come from
+// a browser and carry their own ticket or token, so they are let through.
+// ALLOWED_ORIGINS is enforced whenever it is set; without it production
+// refuses every origin and development allows any
 func CheckOrigin(r *http.Request) bool {
 	origin := r.Header.Get("Origin")
-
 	if origin == "" {
-		// allow no origin header in development
-		env := os.Getenv("ENVIRONMENT")
-
-		if env != "production" {
-			return true
-		}
-
-		logger.Warn("websocket connection with no origin header")
-		return false
-	}
-
-	env := os.Getenv("ENVIRONMENT")
-	if env != "production" {
 		return true
 	}
 
-	// production: validate against allowed origins
+	production := os.Getenv("ENVIRONMENT") == "production"
 	allowedOrigins := getAllowedWebSocketOrigins()
 
 	if len(allowedOrigins) == 0 {
+		if !production {
+			return true
+		}
+
 		logger.Warn("websocket origin rejected - ALLOWED_ORIGINS not configured",
 			"origin", origin,
 		)
 		return false
 	}
 
-	if slices.Contains(allowedOrigins, origin) {
+	if slices.ContainsFunc(allowedOrigins, func(allowed string) bool { return strings.EqualFold(allowed, origin) }) {
 		return true
 	}
 
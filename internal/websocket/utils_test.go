@@ -0,0 +1,42 @@
+package websocket
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		allowed     string
+		origin      string
+		accepted    bool
+	}{
+		{"allowed origin", "production", "https://algopatterns.cc, https://staging.algopatterns.cc", "https://staging.algopatterns.cc", true},
+		{"origin case", "production", "https://algopatterns.cc", "https://ALGOPATTERNS.cc", true},
+		{"other site", "production", "https://algopatterns.cc", "https://evil.example", false},
+		{"lookalike", "production", "https://algopatterns.cc", "https://algopatterns.cc.evil.example", false},
+		{"no origin in production", "production", "https://algopatterns.cc", "", true},
+		{"unconfigured production", "production", "", "https://algopatterns.cc", false},
+		{"enforced in development once set", "development", "http://localhost:3000", "https://evil.example", false},
+		{"open development", "development", "", "https://evil.example", true},
+		{"no origin in development", "development", "http://localhost:3000", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENVIRONMENT", tt.environment)
+			t.Setenv("ALLOWED_ORIGINS", tt.allowed)
+
+			req := httptest.NewRequest("GET", "/api/v1/ws", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			assert.Equal(t, tt.accepted, CheckOrigin(req))
+		})
+	}
+}
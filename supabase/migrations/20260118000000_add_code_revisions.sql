@@ -0,0 +1,13 @@
+-- Create code_revisions table for session code checkpoints
+-- Checkpoints are buffered in Redis (where undo/redo reads them) and flushed here
+
+CREATE TABLE code_revisions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  code TEXT NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_code_revisions_session ON code_revisions(session_id, created_at);
+
+COMMENT ON TABLE code_revisions IS 'History of session code, recorded at most once per snapshot interval';
@@ -0,0 +1,23 @@
+-- Add columns for code analysis computed in the background when a strudel is saved
+-- Values are derived from the code (internal/strudel analyzer) and used by the explore page filters
+
+ALTER TABLE user_strudels
+ADD COLUMN IF NOT EXISTS auto_tags TEXT[] DEFAULT '{}',
+ADD COLUMN IF NOT EXISTS instruments TEXT[] DEFAULT '{}',
+ADD COLUMN IF NOT EXISTS complexity SMALLINT,
+ADD COLUMN IF NOT EXISTS estimated_bpm REAL,
+ADD COLUMN IF NOT EXISTS analyzed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_user_strudels_auto_tags ON user_strudels USING GIN (auto_tags);
+CREATE INDEX IF NOT EXISTS idx_user_strudels_instruments ON user_strudels USING GIN (instruments);
+CREATE INDEX IF NOT EXISTS idx_user_strudels_complexity ON user_strudels (complexity) WHERE is_public = true;
+CREATE INDEX IF NOT EXISTS idx_user_strudels_estimated_bpm ON user_strudels (estimated_bpm) WHERE is_public = true;
+
+-- lets the startup backfill find strudels saved before this migration
+CREATE INDEX IF NOT EXISTS idx_user_strudels_unanalyzed ON user_strudels (created_at) WHERE analyzed_at IS NULL;
+
+COMMENT ON COLUMN user_strudels.auto_tags IS 'Tags derived from the code (sounds, effects, musical elements, complexity)';
+COMMENT ON COLUMN user_strudels.instruments IS 'Instruments derived from the sounds used in the code (kick, hi-hat, bass, ...)';
+COMMENT ON COLUMN user_strudels.complexity IS 'Code complexity score from 0 to 10';
+COMMENT ON COLUMN user_strudels.estimated_bpm IS 'Tempo estimated from setcpm/setcps and fast/slow, NULL when unknown';
+COMMENT ON COLUMN user_strudels.analyzed_at IS 'When the analysis columns were last computed';
@@ -0,0 +1,42 @@
+-- Create webhook tables
+-- Users register endpoints for events (session.created, strudel.forked, ...); every event
+-- sent is logged as a delivery, which the delivery worker retries with exponential backoff
+
+CREATE TABLE webhooks (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  events TEXT[] NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhooks_user ON webhooks(user_id);
+
+CREATE TABLE webhook_deliveries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+  attempts INT NOT NULL DEFAULT 0,
+  response_status INT,
+  response_body TEXT,
+  error TEXT,
+  duration_ms INT,
+  next_attempt_at TIMESTAMPTZ DEFAULT NOW(),
+  last_attempt_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- worker polling for due deliveries
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+-- delivery log per webhook, and retention cleanup
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_created ON webhook_deliveries(created_at);
+
+COMMENT ON TABLE webhooks IS 'User-registered webhook endpoints; secret signs deliveries (HMAC-SHA256)';
+COMMENT ON TABLE webhook_deliveries IS 'Delivery log and retry queue for webhook events, kept for 30 days';
@@ -0,0 +1,21 @@
+-- Add Stripe billing
+-- Users subscribe through Stripe Checkout; subscription webhooks move them between
+-- the free and payg tiers. Processed webhook events are recorded so retries are ignored
+
+ALTER TABLE users ADD COLUMN stripe_customer_id TEXT UNIQUE;
+ALTER TABLE users ADD COLUMN stripe_subscription_id TEXT;
+ALTER TABLE users ADD COLUMN subscription_status TEXT;
+ALTER TABLE users ADD COLUMN subscription_event_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN users.stripe_customer_id IS 'Stripe customer, created on first checkout';
+COMMENT ON COLUMN users.stripe_subscription_id IS 'Latest Stripe subscription';
+COMMENT ON COLUMN users.subscription_status IS 'Stripe subscription status (active, trialing, past_due, canceled, ...)';
+COMMENT ON COLUMN users.subscription_event_at IS 'Creation time of the last applied subscription event, so out-of-order events are ignored';
+
+CREATE TABLE billing_events (
+  id TEXT PRIMARY KEY,
+  type TEXT NOT NULL,
+  processed_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+COMMENT ON TABLE billing_events IS 'Stripe webhook events already processed (Stripe retries deliveries)';
@@ -0,0 +1,25 @@
+-- Add moderation support for admins
+-- Banned users can't sign in or join sessions; every admin action is written to an audit log
+
+ALTER TABLE users ADD COLUMN banned_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN ban_reason TEXT;
+ALTER TABLE users ADD COLUMN banned_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN users.banned_at IS 'When the user was banned by an admin, NULL if not banned';
+COMMENT ON COLUMN users.ban_reason IS 'Reason given by the admin for the ban';
+COMMENT ON COLUMN users.banned_by IS 'Admin who banned the user';
+
+CREATE TABLE admin_audit_log (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  admin_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  action TEXT NOT NULL,
+  target_type TEXT NOT NULL,
+  target_id TEXT NOT NULL,
+  details JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_admin_audit_log_created ON admin_audit_log(created_at DESC);
+CREATE INDEX idx_admin_audit_log_target ON admin_audit_log(target_type, target_id, created_at DESC);
+
+COMMENT ON TABLE admin_audit_log IS 'Actions taken through the admin API (session.end, strudel.delete, user.ban, ...)';
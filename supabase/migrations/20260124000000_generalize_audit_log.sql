@@ -0,0 +1,40 @@
+-- Generalize the admin audit log into an append-only log of security-relevant actions
+-- Besides admin actions it records session ends, participant kicks and role changes,
+-- invite tokens and CC signal changes. Entries of a session are visible to its host
+
+ALTER TABLE admin_audit_log RENAME TO audit_log;
+ALTER TABLE audit_log RENAME COLUMN admin_id TO actor_id;
+
+-- entries must outlive the accounts and sessions they mention
+ALTER TABLE audit_log DROP CONSTRAINT admin_audit_log_admin_id_fkey;
+ALTER TABLE audit_log ADD COLUMN session_id UUID;
+
+-- admin actions are namespaced now that other actions share the table
+UPDATE audit_log SET action = 'admin.' || action;
+
+ALTER INDEX idx_admin_audit_log_created RENAME TO idx_audit_log_created;
+ALTER INDEX idx_admin_audit_log_target RENAME TO idx_audit_log_target;
+
+CREATE INDEX idx_audit_log_session ON audit_log(session_id, created_at DESC) WHERE session_id IS NOT NULL;
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_id, created_at DESC);
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+
+CREATE TRIGGER audit_log_no_truncate
+BEFORE TRUNCATE ON audit_log
+FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
+
+COMMENT ON TABLE audit_log IS 'Append-only log of security-relevant actions (admin.*, session.*, participant.*, invite.*, strudel.cc_signal_change)';
+COMMENT ON COLUMN audit_log.actor_id IS 'User who took the action, NULL for the system (e.g. inactivity cleanup)';
+COMMENT ON COLUMN audit_log.session_id IS 'Session the action belongs to, which lets its host read the entry';
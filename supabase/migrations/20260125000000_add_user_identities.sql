@@ -0,0 +1,27 @@
+-- Add linked OAuth identities so one account can sign in with several providers
+-- users.provider/provider_id stay as the provider the account was created with.
+-- A login with a new provider joins the existing account when the provider
+-- vouches for an email that matches it, otherwise it creates a new account
+
+CREATE TABLE user_identities (
+  provider TEXT NOT NULL,           -- "google", "github", "apple", "discord", "gitlab"
+  provider_id TEXT NOT NULL,        -- ID from the OAuth provider
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  email TEXT,                       -- email reported by the provider when linked
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (provider, provider_id)
+);
+
+CREATE INDEX idx_user_identities_user ON user_identities(user_id);
+
+-- matching accounts by email ignores case
+CREATE INDEX idx_users_email_lower ON users(lower(email));
+
+-- every existing account has the identity it was created with
+INSERT INTO user_identities (provider, provider_id, user_id, email, created_at)
+SELECT provider, provider_id, id, email, created_at
+FROM users
+WHERE provider <> 'system';
+
+COMMENT ON TABLE user_identities IS 'OAuth identities that sign in to a user account, including the one it was created with';
+COMMENT ON COLUMN user_identities.email IS 'Email reported by the provider at link time, for support and auditing';
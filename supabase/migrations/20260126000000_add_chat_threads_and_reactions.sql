@@ -0,0 +1,27 @@
+-- Add reply threading and emoji reactions to session chat
+-- Replies are chat messages with a parent_message_id. Reactions are rows of
+-- message_type 'reaction' whose content is the emoji and whose parent is the
+-- message reacted to. Message IDs are assigned before the Redis buffer is
+-- flushed, so parents are not enforced with a foreign key: a reply can be
+-- written before or without its parent
+
+ALTER TABLE session_messages
+ADD COLUMN parent_message_id UUID;
+
+ALTER TABLE session_messages
+DROP CONSTRAINT IF EXISTS session_messages_message_type_check;
+
+ALTER TABLE session_messages
+ADD CONSTRAINT session_messages_message_type_check
+CHECK (message_type IN ('user_prompt', 'ai_response', 'chat', 'reaction'));
+
+CREATE INDEX idx_messages_parent ON session_messages(parent_message_id)
+WHERE parent_message_id IS NOT NULL;
+
+-- one reaction per emoji and participant (anonymous participants are told apart by display name)
+CREATE UNIQUE INDEX idx_messages_reaction_unique
+ON session_messages(parent_message_id, content, COALESCE(user_id::text, display_name))
+WHERE message_type = 'reaction';
+
+COMMENT ON COLUMN session_messages.parent_message_id IS 'Message replied or reacted to';
+COMMENT ON COLUMN session_messages.message_type IS 'Type of message: user_prompt (user prompt to AI), ai_response (AI generated code), chat (user chat message), reaction (emoji reaction to a chat message)';
@@ -0,0 +1,31 @@
+-- Add a review queue for moderated content
+-- Chat messages and strudel titles/descriptions are screened before they are
+-- broadcast or stored. Blocked content is rejected outright and never queued.
+-- Flagged content is shared as usual and queued for review. Hidden content is
+-- only visible to its author until an admin approves it
+
+CREATE TABLE moderation_queue (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  content_type TEXT NOT NULL CHECK (content_type IN ('chat_message', 'strudel')),
+  content_id TEXT,                  -- message or strudel ID (hidden chat messages are never stored)
+  session_id UUID,
+  author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  author_name TEXT,
+  content TEXT NOT NULL,            -- the screened text as submitted
+  action TEXT NOT NULL CHECK (action IN ('flag', 'hide')),
+  reason TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'removed')),
+  reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_moderation_queue_pending ON moderation_queue(created_at DESC)
+WHERE status = 'pending';
+
+CREATE INDEX idx_moderation_queue_status ON moderation_queue(status, created_at DESC);
+
+COMMENT ON TABLE moderation_queue IS 'Flagged and shadow-hidden content awaiting admin review';
+COMMENT ON COLUMN moderation_queue.action IS 'Moderation action taken: flag (shared, needs review) or hide (only visible to the author)';
+COMMENT ON COLUMN moderation_queue.reason IS 'Why the content was queued, e.g. the matched keyword or classifier verdict';
+COMMENT ON COLUMN moderation_queue.status IS 'Review status: pending, approved (content allowed) or removed';
@@ -0,0 +1,19 @@
+-- Track batch re-embedding runs (ingester reembed)
+-- When the embedding model changes, stored doc chunks and strudels are
+-- re-embedded in id order. The cursor is saved after every batch so an
+-- interrupted run resumes where it stopped instead of starting over
+
+CREATE TABLE reembed_jobs (
+  target TEXT NOT NULL CHECK (target IN ('docs', 'strudels')),
+  model TEXT NOT NULL,              -- "provider/model", e.g. openai/text-embedding-3-small
+  last_id UUID,                     -- last row re-embedded, NULL before the first batch
+  processed INTEGER NOT NULL DEFAULT 0,
+  total INTEGER NOT NULL DEFAULT 0, -- rows to re-embed when the run started
+  started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  completed_at TIMESTAMPTZ,
+  PRIMARY KEY (target, model)
+);
+
+COMMENT ON TABLE reembed_jobs IS 'Progress of re-embedding stored docs and strudels with a new embedding model';
+COMMENT ON COLUMN reembed_jobs.last_id IS 'Resume cursor: rows are re-embedded in ascending id order';
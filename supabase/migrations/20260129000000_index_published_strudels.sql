@@ -0,0 +1,47 @@
+-- Index published strudels as RAG examples without manual curation
+-- The server now embeds a strudel when it is public with a permissive cc
+-- signal and its author consents to training, and clears the embedding when
+-- any of those stop holding. Example search no longer requires the admin
+-- use_in_training flag, which keeps its meaning for curated training data
+
+CREATE OR REPLACE FUNCTION search_user_strudels(
+    query_embedding extensions.vector(1536),
+    match_count int DEFAULT 3
+)
+RETURNS TABLE (
+    id UUID,
+    title TEXT,
+    description TEXT,
+    code TEXT,
+    tags TEXT[],
+    user_id UUID,
+    similarity FLOAT
+)
+LANGUAGE plpgsql STABLE
+AS $$
+BEGIN
+    PERFORM set_config('search_path', 'extensions, public', true);
+
+    RETURN QUERY
+    SELECT
+        us.id,
+        us.title,
+        us.description,
+        us.code,
+        us.tags,
+        us.user_id,
+        1 - (us.embedding <=> query_embedding) AS similarity
+    FROM user_strudels us
+    INNER JOIN users u ON us.user_id = u.id
+    WHERE us.cc_signal IS NOT NULL          -- opt-in: must have signal
+      AND us.cc_signal != 'no-ai'           -- not explicitly blocked
+      AND us.is_public = true
+      AND us.embedding IS NOT NULL
+      AND u.training_consent = true         -- user global consent
+    ORDER BY us.embedding <=> query_embedding
+    LIMIT match_count;
+END;
+$$;
+
+COMMENT ON FUNCTION search_user_strudels IS 'Search published user strudels by vector similarity (requires cc_signal + is_public + user.training_consent)';
+COMMENT ON COLUMN user_strudels.embedding IS 'Embedding of title + description + code, set while the strudel is eligible as a RAG example';
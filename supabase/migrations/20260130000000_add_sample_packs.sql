@@ -0,0 +1,26 @@
+-- Create sample pack registry
+-- Users register community sample packs by their strudel.json manifest; the manifest is
+-- validated on registration and its sound names stored, so code analysis can recognize them
+
+CREATE TABLE sample_packs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  manifest_url TEXT NOT NULL,
+  base_url TEXT NOT NULL,
+  license TEXT NOT NULL,
+  sounds TEXT[] NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- pack names are unique regardless of case
+CREATE UNIQUE INDEX idx_sample_packs_name ON sample_packs(LOWER(name));
+CREATE INDEX idx_sample_packs_user ON sample_packs(user_id);
+CREATE INDEX idx_sample_packs_created ON sample_packs(created_at DESC);
+
+-- browse by sound name
+CREATE INDEX idx_sample_packs_sounds ON sample_packs USING GIN(sounds);
+
+COMMENT ON TABLE sample_packs IS 'Community sample packs (strudel.json manifests) recognized by code analysis';
@@ -0,0 +1,33 @@
+-- Create scheduled performance events
+-- Hosts schedule a public performance in one of their sessions; when it starts the session
+-- becomes discoverable and everyone who RSVPed is notified through their webhooks
+
+CREATE TABLE performance_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  host_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  title TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  starts_at TIMESTAMPTZ NOT NULL,
+  status TEXT NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'live', 'ended', 'cancelled')),
+  went_live_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- browsing upcoming events, and the worker polling for due ones
+CREATE INDEX idx_performance_events_upcoming ON performance_events(starts_at) WHERE status IN ('scheduled', 'live');
+CREATE INDEX idx_performance_events_host ON performance_events(host_user_id);
+CREATE INDEX idx_performance_events_session ON performance_events(session_id);
+
+CREATE TABLE performance_event_rsvps (
+  event_id UUID NOT NULL REFERENCES performance_events(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX idx_performance_event_rsvps_user ON performance_event_rsvps(user_id);
+
+COMMENT ON TABLE performance_events IS 'Scheduled public performances; status moves scheduled -> live -> ended, or cancelled';
+COMMENT ON TABLE performance_event_rsvps IS 'Users notified when a performance goes live';
@@ -0,0 +1,16 @@
+-- Create session_snapshots table for restorable copies of session code
+-- Snapshots are taken every few minutes of editing (or after many edits) and
+-- before a restore, and are never updated
+
+CREATE TABLE session_snapshots (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  code TEXT NOT NULL,
+  reason TEXT NOT NULL DEFAULT 'auto' CHECK (reason IN ('auto', 'pre_restore')),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_session_snapshots_session ON session_snapshots(session_id, created_at DESC);
+
+COMMENT ON TABLE session_snapshots IS 'Immutable snapshots of session code that the host can restore';
+COMMENT ON COLUMN session_snapshots.reason IS 'auto: periodic snapshot, pre_restore: code replaced by a restore';
@@ -0,0 +1,18 @@
+-- Create embed_tokens table for read-only embeds of sessions and strudels
+-- A token lets anyone holding it view the code and play state of one session or strudel
+-- (no chat, no participant list) until the owner revokes it
+
+CREATE TABLE embed_tokens (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  token TEXT UNIQUE NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  session_id UUID REFERENCES sessions(id) ON DELETE CASCADE,
+  strudel_id UUID REFERENCES user_strudels(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  revoked_at TIMESTAMPTZ,
+  CHECK ((session_id IS NULL) <> (strudel_id IS NULL))
+);
+
+CREATE INDEX idx_embed_tokens_user ON embed_tokens(user_id) WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE embed_tokens IS 'Revocable tokens granting a read-only view of a session or strudel for embedding';
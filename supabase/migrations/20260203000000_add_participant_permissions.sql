@@ -0,0 +1,9 @@
+-- Add per-participant permission overrides
+-- permissions is a bitmask (edit_code=1, use_agent=2, chat=4, manage_participants=8,
+-- control_playback=16); NULL means the participant has the preset of their role
+
+ALTER TABLE session_participants ADD COLUMN permissions INTEGER;
+ALTER TABLE anonymous_participants ADD COLUMN permissions INTEGER;
+
+COMMENT ON COLUMN session_participants.permissions IS 'Permission bitmask overriding the role preset, NULL to use the preset';
+COMMENT ON COLUMN anonymous_participants.permissions IS 'Permission bitmask overriding the role preset, NULL to use the preset';
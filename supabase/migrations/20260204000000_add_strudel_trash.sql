@@ -0,0 +1,11 @@
+-- Add soft deletion for strudels
+-- Deleted strudels stay in the owner's trash with deleted_at set and are hard-deleted
+-- by the cleanup service 30 days later
+
+ALTER TABLE user_strudels ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- lists a user's trash and finds strudels due for purging
+CREATE INDEX IF NOT EXISTS idx_user_strudels_deleted_at ON user_strudels (user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_user_strudels_purge ON user_strudels (deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMENT ON COLUMN user_strudels.deleted_at IS 'When the strudel was moved to the trash, NULL while it is not deleted';
@@ -0,0 +1,20 @@
+-- Create strudel_versions table for the code history of saved strudels
+-- Every save that changes the code records a version. The server trims the history:
+-- saves within a minute of the latest version replace it, at most 100 versions are
+-- kept, and versions older than 30 days are thinned to the last one of each day
+
+CREATE TABLE strudel_versions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  strudel_id UUID NOT NULL REFERENCES user_strudels(id) ON DELETE CASCADE,
+  version INTEGER NOT NULL,
+  code TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (strudel_id, version)
+);
+
+-- existing strudels start their history with their current code
+INSERT INTO strudel_versions (strudel_id, version, code, created_at)
+SELECT id, 1, code, updated_at FROM user_strudels;
+
+COMMENT ON TABLE strudel_versions IS 'Code history of saved strudels';
+COMMENT ON COLUMN strudel_versions.version IS 'Version number, increasing per strudel from 1';
@@ -0,0 +1,38 @@
+-- Create notification tables
+-- Users are notified in-app when their strudel is forked or used as AI context, when
+-- they're invited to a session, and when a session they host is ended for them.
+-- Unread notifications can also be sent as a daily or weekly email digest
+
+CREATE TABLE notifications (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL,
+  message TEXT NOT NULL,
+  data JSONB NOT NULL DEFAULT '{}',
+  read_at TIMESTAMPTZ,
+  emailed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- listing a user's notifications, newest first
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at DESC);
+
+-- unread counts and digests
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- retention cleanup
+CREATE INDEX idx_notifications_created ON notifications(created_at);
+
+CREATE TABLE notification_preferences (
+  user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  email_digest TEXT NOT NULL DEFAULT 'off' CHECK (email_digest IN ('off', 'daily', 'weekly')),
+  last_digest_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- digest worker polling
+CREATE INDEX idx_notification_preferences_digest ON notification_preferences(last_digest_at) WHERE email_digest <> 'off';
+
+COMMENT ON TABLE notifications IS 'In-app notifications, kept for 90 days';
+COMMENT ON COLUMN notifications.emailed_at IS 'When the notification was included in an email digest';
+COMMENT ON TABLE notification_preferences IS 'Per-user notification settings; users without a row get no email digest';
@@ -0,0 +1,16 @@
+-- Create email_preferences table
+-- Users choose which emails they receive; users without a row get the defaults.
+-- How often the notification digest is sent is a notification preference
+
+CREATE TABLE email_preferences (
+  user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  invites BOOLEAN NOT NULL DEFAULT true,
+  unsubscribed BOOLEAN NOT NULL DEFAULT false,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- invites to an address look up the preferences of the user with that email
+CREATE INDEX idx_users_email_lower ON users(LOWER(email));
+
+COMMENT ON COLUMN email_preferences.invites IS 'Receive session invitations by email';
+COMMENT ON COLUMN email_preferences.unsubscribed IS 'Receive no email at all, including digests';
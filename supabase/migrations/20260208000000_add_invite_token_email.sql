@@ -0,0 +1,7 @@
+-- Bind invite tokens to an email address
+-- Email-bound invites are single-use and only accepted from a signed-in user
+-- with that address, so a leaked link can't be used by anyone else
+
+ALTER TABLE invite_tokens ADD COLUMN email TEXT;
+
+COMMENT ON COLUMN invite_tokens.email IS 'Address the invite was emailed to; NULL = bearer token anyone with the link can use';
@@ -0,0 +1,25 @@
+-- Create session_join_requests table
+-- Knock-to-join: a signed-in user asks to join a session and the host approves
+-- or denies. Pending requests expire; approved requests admit the user to the
+-- session for a while so they can connect and reconnect.
+
+CREATE TABLE session_join_requests (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  display_name TEXT NOT NULL,
+  message TEXT,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied')),
+  role TEXT CHECK (role IN ('co-author', 'viewer')),
+  expires_at TIMESTAMPTZ NOT NULL,
+  decided_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- one open request per user and session; knocking again refreshes it
+CREATE UNIQUE INDEX idx_join_requests_pending ON session_join_requests(session_id, user_id) WHERE status = 'pending';
+CREATE INDEX idx_join_requests_created ON session_join_requests(created_at);
+
+COMMENT ON TABLE session_join_requests IS 'Requests to join a session that wait for the host''s approval';
+COMMENT ON COLUMN session_join_requests.status IS 'pending until the host decides; pending requests past expires_at are expired';
+COMMENT ON COLUMN session_join_requests.role IS 'Role granted by the host on approval';
@@ -0,0 +1,17 @@
+-- Spectators, chat slow mode and muting
+-- Spectators are signed-in audience members who can chat. They connect like
+-- audience listeners, but are recorded as participants so the host can mute them.
+
+ALTER TABLE session_participants DROP CONSTRAINT IF EXISTS session_participants_role_check;
+ALTER TABLE session_participants ADD CONSTRAINT session_participants_role_check
+  CHECK (role IN ('host', 'co-author', 'viewer', 'spectator'));
+
+ALTER TABLE sessions ADD COLUMN chat_slow_mode_seconds INTEGER NOT NULL DEFAULT 0
+  CHECK (chat_slow_mode_seconds >= 0 AND chat_slow_mode_seconds <= 3600);
+
+ALTER TABLE session_participants ADD COLUMN muted_at TIMESTAMPTZ;
+ALTER TABLE anonymous_participants ADD COLUMN muted_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN sessions.chat_slow_mode_seconds IS 'Seconds each participant waits between chat messages; 0 = slow mode off';
+COMMENT ON COLUMN session_participants.muted_at IS 'When the host muted the participant; muted participants stay in the session but cannot chat';
+COMMENT ON COLUMN anonymous_participants.muted_at IS 'When the host muted the participant; muted participants stay in the session but cannot chat';
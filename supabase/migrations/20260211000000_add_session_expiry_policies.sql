@@ -0,0 +1,12 @@
+-- Per-session expiry policies
+-- Hosts can override how long an idle session stays open, and have the
+-- cleanup service revoke invites and publish the final code when it ends one.
+
+ALTER TABLE sessions ADD COLUMN auto_end_idle_hours INTEGER
+  CHECK (auto_end_idle_hours >= 1 AND auto_end_idle_hours <= 168);
+ALTER TABLE sessions ADD COLUMN revoke_invites_on_end BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE sessions ADD COLUMN publish_on_end BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN sessions.auto_end_idle_hours IS 'Hours without activity before the session is ended; NULL = server default';
+COMMENT ON COLUMN sessions.revoke_invites_on_end IS 'Revoke the session''s invite tokens when it is ended for inactivity';
+COMMENT ON COLUMN sessions.publish_on_end IS 'Save the final code as a public strudel of the host when the session is ended for inactivity';
@@ -0,0 +1,22 @@
+-- Create session_activity_rollups table
+-- Per-minute activity of live sessions behind the host analytics endpoint.
+-- Server instances sample their connections and count edits, agent requests,
+-- chat messages and playback once a minute; the buffer flusher adds the
+-- samples here.
+
+CREATE TABLE session_activity_rollups (
+  session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  bucket_start TIMESTAMPTZ NOT NULL,
+  participants INTEGER NOT NULL DEFAULT 0,
+  listeners INTEGER NOT NULL DEFAULT 0,
+  code_edits INTEGER NOT NULL DEFAULT 0,
+  agent_requests INTEGER NOT NULL DEFAULT 0,
+  chat_messages INTEGER NOT NULL DEFAULT 0,
+  playback_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+  PRIMARY KEY (session_id, bucket_start)
+);
+
+COMMENT ON TABLE session_activity_rollups IS 'Activity of live sessions per minute, for host analytics';
+COMMENT ON COLUMN session_activity_rollups.participants IS 'Peak performers connected during the minute';
+COMMENT ON COLUMN session_activity_rollups.listeners IS 'Peak audience connections during the minute';
+COMMENT ON COLUMN session_activity_rollups.playback_seconds IS 'Seconds the session transport was playing during the minute';
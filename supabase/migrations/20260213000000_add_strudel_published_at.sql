@@ -0,0 +1,26 @@
+-- Track when strudels were published
+-- Public stats count the strudels published this week, which created_at can't
+-- tell for strudels made public later.
+
+ALTER TABLE user_strudels ADD COLUMN published_at TIMESTAMPTZ;
+
+UPDATE user_strudels SET published_at = created_at WHERE is_public = true;
+
+CREATE OR REPLACE FUNCTION set_strudel_published_at()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF NEW.is_public = true AND (TG_OP = 'INSERT' OR OLD.is_public IS DISTINCT FROM true) THEN
+    NEW.published_at := NOW();
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_strudel_published_at
+  BEFORE INSERT OR UPDATE OF is_public ON user_strudels
+  FOR EACH ROW
+  EXECUTE FUNCTION set_strudel_published_at();
+
+CREATE INDEX idx_user_strudels_published ON user_strudels(published_at) WHERE is_public = true;
+
+COMMENT ON COLUMN user_strudels.published_at IS 'When the strudel was last made public; kept when it is unpublished';
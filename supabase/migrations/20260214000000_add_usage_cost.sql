@@ -0,0 +1,8 @@
+-- Store the estimated cost of each AI generation
+-- Costs are estimated from list prices when the generation is logged, so later
+-- price changes don't rewrite history. Generations logged before this, or on
+-- models without a known price, have no cost and are priced when read.
+
+ALTER TABLE usage_logs ADD COLUMN estimated_cost_usd NUMERIC(12, 6);
+
+COMMENT ON COLUMN usage_logs.estimated_cost_usd IS 'Estimated model cost in USD at list prices (null when unpriced); counted against monthly spend limits unless is_byok';
@@ -0,0 +1,11 @@
+-- Record whether the provider verified each identity's email
+-- Email-bound invites only admit users who proved they own the invited
+-- address. Identities linked before this are unverified until their next
+-- login refreshes them.
+
+ALTER TABLE user_identities ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_user_identities_verified_email ON user_identities(lower(email)) WHERE email_verified;
+
+COMMENT ON COLUMN user_identities.email IS 'Email reported by the provider at the last login';
+COMMENT ON COLUMN user_identities.email_verified IS 'Whether the provider verified email at the last login; email-bound invites require it';
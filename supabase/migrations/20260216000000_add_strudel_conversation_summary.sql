@@ -0,0 +1,9 @@
+-- Rolling summary of a strudel's AI conversation
+-- Older messages are folded into the summary once the history outgrows the
+-- agent's token budget, so follow-ups send the summary plus recent messages.
+
+ALTER TABLE user_strudels ADD COLUMN conversation_summary TEXT;
+ALTER TABLE user_strudels ADD COLUMN conversation_summary_through TIMESTAMPTZ;
+
+COMMENT ON COLUMN user_strudels.conversation_summary IS 'Summary of the AI conversation up to conversation_summary_through';
+COMMENT ON COLUMN user_strudels.conversation_summary_through IS 'created_at of the newest strudel_messages row folded into the summary';
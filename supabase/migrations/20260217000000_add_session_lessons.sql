@@ -0,0 +1,18 @@
+-- Create session_lessons table
+-- Guided lessons built from the teaching concepts (docs/concepts). A session
+-- runs at most one lesson at a time; step is the index of the current step
+-- and advances when the code passes its checks.
+
+CREATE TABLE session_lessons (
+  session_id UUID PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+  lesson_id TEXT NOT NULL,
+  step INTEGER NOT NULL DEFAULT 0 CHECK (step >= 0),
+  started_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  completed_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE session_lessons IS 'The guided lesson a session is working through';
+COMMENT ON COLUMN session_lessons.lesson_id IS 'Slug of the lesson, from the concept heading it was built from';
+COMMENT ON COLUMN session_lessons.step IS 'Index of the current step; equals the step count once completed';
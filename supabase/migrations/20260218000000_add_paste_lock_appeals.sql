@@ -0,0 +1,29 @@
+-- Create paste_lock_appeals table
+-- Users can appeal a paste lock that blocks the AI assistant in their session.
+-- Appeals wait for admin review, unless the user proved ownership of the
+-- pasted code through their published strudels, which lifts the lock at once.
+
+CREATE TABLE paste_lock_appeals (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  message TEXT,
+  claims_ownership BOOLEAN NOT NULL DEFAULT false,
+  lock_reason TEXT,
+  matched_work_id UUID,
+  cc_signal TEXT,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  verified BOOLEAN NOT NULL DEFAULT false,
+  reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- one open appeal per user and session
+CREATE UNIQUE INDEX idx_paste_lock_appeals_pending ON paste_lock_appeals(session_id, user_id) WHERE status = 'pending';
+CREATE INDEX idx_paste_lock_appeals_status ON paste_lock_appeals(status, created_at DESC);
+
+COMMENT ON TABLE paste_lock_appeals IS 'Appeals against paste locks, reviewed by admins';
+COMMENT ON COLUMN paste_lock_appeals.lock_reason IS 'Why the session was locked, from the paste detection decision';
+COMMENT ON COLUMN paste_lock_appeals.matched_work_id IS 'Protected strudel the pasted code matched, for fingerprint matches';
+COMMENT ON COLUMN paste_lock_appeals.verified IS 'Ownership was verified automatically and the lock lifted without review';
@@ -0,0 +1,27 @@
+-- Create strudel_fingerprints table
+-- Persisted SimHash fingerprints of no-ai strudels for paste detection. The
+-- in-memory LSH index is loaded from here at startup instead of being
+-- recomputed from every strudel, and instances pick up each other's changes
+-- by polling updated_at. Removed fingerprints are tombstoned for a day so
+-- other instances can drop them too.
+
+CREATE TABLE strudel_fingerprints (
+  strudel_id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  cc_signal TEXT NOT NULL,
+  fingerprint BIGINT NOT NULL,
+  content_hash TEXT NOT NULL,
+  content_length INTEGER NOT NULL,
+  line_count INTEGER NOT NULL,
+  shingle_size INTEGER NOT NULL,
+  deleted_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_strudel_fingerprints_updated ON strudel_fingerprints(updated_at);
+
+COMMENT ON TABLE strudel_fingerprints IS 'SimHash fingerprints of no-ai strudels, loaded into the paste detection index';
+COMMENT ON COLUMN strudel_fingerprints.fingerprint IS '64-bit SimHash, stored bit for bit';
+COMMENT ON COLUMN strudel_fingerprints.content_hash IS 'Hex sha256 of the code the fingerprint was computed from, checked against user_strudels at startup';
+COMMENT ON COLUMN strudel_fingerprints.shingle_size IS 'Shingle size the fingerprint was computed with; others are recomputed';
+COMMENT ON COLUMN strudel_fingerprints.deleted_at IS 'Tombstone: the strudel is no longer protected';
@@ -0,0 +1,14 @@
+-- Add window fingerprints to strudel_fingerprints
+-- No-ai strudels are also fingerprinted in overlapping windows of lines, so
+-- pasting a section of one is detected. Rows written before this migration
+-- have no windows and are recomputed at startup, as are rows computed with
+-- other window settings.
+
+ALTER TABLE strudel_fingerprints
+  ADD COLUMN window_fingerprints BIGINT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN window_lines INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN window_stride INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN strudel_fingerprints.window_fingerprints IS 'SimHash of each window of lines, 0 for windows too short to fingerprint';
+COMMENT ON COLUMN strudel_fingerprints.window_lines IS 'Lines per window the window fingerprints were computed with';
+COMMENT ON COLUMN strudel_fingerprints.window_stride IS 'Lines between window starts the window fingerprints were computed with';
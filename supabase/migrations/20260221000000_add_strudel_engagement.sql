@@ -0,0 +1,65 @@
+-- Create strudel engagement tables and the trending rollup
+-- Users like public strudels, and plays are counted per day (the API counts
+-- one play per listener and strudel every 30 minutes). strudel_trending rolls
+-- likes, plays and forks of the last 7 days up into a score that decays with
+-- age, and is refreshed by a background job.
+
+CREATE TABLE strudel_likes (
+  strudel_id UUID NOT NULL REFERENCES user_strudels(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (strudel_id, user_id)
+);
+
+CREATE INDEX idx_strudel_likes_created ON strudel_likes(created_at);
+
+CREATE TABLE strudel_plays (
+  strudel_id UUID NOT NULL REFERENCES user_strudels(id) ON DELETE CASCADE,
+  day DATE NOT NULL DEFAULT CURRENT_DATE,
+  count INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (strudel_id, day)
+);
+
+-- score: recent engagement (a fork counts 5, a like 3, a play 1) divided by
+-- (age in hours + 2)^1.5, with age capped at a week so older strudels that
+-- are popular again still surface
+CREATE MATERIALIZED VIEW strudel_trending AS
+WITH likes AS (
+  SELECT strudel_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE created_at > NOW() - INTERVAL '7 days') AS recent
+  FROM strudel_likes
+  GROUP BY strudel_id
+), plays AS (
+  SELECT strudel_id,
+    SUM(count) AS total,
+    COALESCE(SUM(count) FILTER (WHERE day > CURRENT_DATE - 7), 0) AS recent
+  FROM strudel_plays
+  GROUP BY strudel_id
+), forks AS (
+  SELECT forked_from AS strudel_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE created_at > NOW() - INTERVAL '7 days') AS recent
+  FROM user_strudels
+  WHERE forked_from IS NOT NULL AND deleted_at IS NULL
+  GROUP BY forked_from
+)
+SELECT s.id AS strudel_id,
+  COALESCE(l.total, 0)::int AS likes,
+  COALESCE(p.total, 0)::int AS plays,
+  COALESCE(f.total, 0)::int AS forks,
+  ((3 * COALESCE(l.recent, 0) + COALESCE(p.recent, 0) + 5 * COALESCE(f.recent, 0))
+    / POWER(LEAST(EXTRACT(EPOCH FROM NOW() - s.created_at) / 3600, 168) + 2, 1.5))::float8 AS score
+FROM user_strudels s
+LEFT JOIN likes l ON l.strudel_id = s.id
+LEFT JOIN plays p ON p.strudel_id = s.id
+LEFT JOIN forks f ON f.strudel_id = s.id
+WHERE s.is_public = true AND s.deleted_at IS NULL;
+
+-- required to refresh concurrently
+CREATE UNIQUE INDEX idx_strudel_trending_strudel ON strudel_trending(strudel_id);
+CREATE INDEX idx_strudel_trending_score ON strudel_trending(score DESC);
+
+COMMENT ON TABLE strudel_likes IS 'Likes of public strudels, one per user';
+COMMENT ON TABLE strudel_plays IS 'Debounced play counts of strudels per day';
+COMMENT ON MATERIALIZED VIEW strudel_trending IS 'Engagement rollup of public strudels, refreshed every 5 minutes';
@@ -0,0 +1,24 @@
+-- Create user follows
+-- A user follows other users to see their publishes, forks and scheduled
+-- performances in their feed. The feed is assembled when it's read from the
+-- strudels and events of followed users, so nothing is fanned out on write.
+
+CREATE TABLE user_follows (
+  follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (follower_id, followee_id),
+  CHECK (follower_id <> followee_id)
+);
+
+-- followers of a user, newest first
+CREATE INDEX idx_user_follows_followee ON user_follows(followee_id, created_at DESC);
+
+-- the feed reads the latest public strudels and events of each followed user
+CREATE INDEX IF NOT EXISTS idx_user_strudels_user_published
+ON user_strudels(user_id, published_at DESC) WHERE is_public = true AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_performance_events_host_created
+ON performance_events(host_user_id, created_at DESC);
+
+COMMENT ON TABLE user_follows IS 'Users following other users, for the activity feed';
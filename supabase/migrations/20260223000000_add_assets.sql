@@ -0,0 +1,32 @@
+-- Create uploaded assets
+-- Clients upload files straight to object storage with a presigned URL, then
+-- ask the API to complete the upload: the file is validated and processed
+-- (avatars are cropped and resized) and the result stored as the asset.
+-- Uploads never completed and assets nothing references anymore are deleted
+-- from storage by a background job.
+
+CREATE TABLE assets (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  -- NULL once the owner is deleted, which orphans the asset
+  user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('avatar')),
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready')),
+  upload_key TEXT NOT NULL,  -- where the client uploads the original
+  object_key TEXT,           -- the processed file, once ready
+  content_type TEXT,
+  size_bytes INTEGER,
+  width INTEGER,
+  height INTEGER,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_assets_user_pending ON assets(user_id, created_at) WHERE status = 'pending';
+CREATE INDEX idx_assets_created ON assets(created_at);
+
+-- the uploaded avatar in use. provider logins don't overwrite avatar_url
+-- while it is set
+ALTER TABLE users ADD COLUMN avatar_asset_id UUID REFERENCES assets(id) ON DELETE SET NULL;
+
+COMMENT ON TABLE assets IS 'Files uploaded to object storage, such as avatars';
+COMMENT ON COLUMN users.avatar_asset_id IS 'Uploaded avatar asset, NULL when avatar_url comes from the login provider or was set directly';
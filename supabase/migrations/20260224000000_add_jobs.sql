@@ -0,0 +1,36 @@
+-- Create the background job queue
+-- Work that used to run in fire-and-forget goroutines (attribution records,
+-- notifications, webhook events, fingerprint persistence) is stored as a job
+-- and run by workers on any instance, so it survives restarts and crashes.
+-- Jobs can be enqueued in the transaction of the change they follow (outbox).
+-- Failing jobs are retried with backoff; ones that run out of attempts are
+-- moved to job_dead_letters for admins to inspect and retry.
+
+CREATE TABLE jobs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  kind TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  -- set while a worker runs the job; it becomes due again once this passes
+  locked_until TIMESTAMPTZ,
+  last_error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_jobs_due ON jobs(run_at);
+
+CREATE TABLE job_dead_letters (
+  id UUID PRIMARY KEY,  -- the job's ID
+  kind TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  attempts INTEGER NOT NULL,
+  error TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_job_dead_letters_failed ON job_dead_letters(failed_at DESC);
+
+COMMENT ON TABLE jobs IS 'Durable queue of background jobs, claimed by workers with row locks';
+COMMENT ON TABLE job_dead_letters IS 'Jobs that failed permanently or ran out of attempts';
@@ -0,0 +1,30 @@
+-- Audio renders of strudels
+-- Owners can have a strudel rendered to audio by an external headless
+-- renderer. Requests are stored here and run through the job queue; the
+-- finished file is kept in object storage and its URL is the strudel's
+-- playable preview. Only the latest finished render of a strudel is kept.
+
+CREATE TABLE strudel_renders (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  strudel_id UUID NOT NULL REFERENCES user_strudels(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  status TEXT NOT NULL DEFAULT 'queued'
+    CHECK (status IN ('queued', 'rendering', 'ready', 'failed')),
+  -- the code as it was when the render was requested
+  code TEXT NOT NULL,
+  duration_seconds INTEGER NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  object_key TEXT,
+  url TEXT,
+  error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_strudel_renders_strudel ON strudel_renders(strudel_id, created_at DESC);
+
+-- one render in progress per strudel
+CREATE UNIQUE INDEX idx_strudel_renders_in_progress ON strudel_renders(strudel_id)
+  WHERE status IN ('queued', 'rendering');
+
+COMMENT ON TABLE strudel_renders IS 'Audio renders of strudels, the latest ready one is the strudel''s preview';
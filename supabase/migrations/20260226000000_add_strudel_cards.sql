@@ -0,0 +1,20 @@
+-- Preview cards of shared strudels
+-- Links to public strudels preview with an OpenGraph card. Its image shows
+-- the title, author and code; it is rendered by an image worker in the
+-- background and cached in object storage. version identifies the title,
+-- author and code the stored image shows, so edits regenerate it when the
+-- card is next requested. Cards of unpublished strudels are deleted by the
+-- cleanup service.
+
+CREATE TABLE strudel_cards (
+  strudel_id UUID PRIMARY KEY REFERENCES user_strudels(id) ON DELETE CASCADE,
+  version TEXT,
+  object_key TEXT,
+  image_url TEXT,
+  -- the version being generated, and when it was queued
+  pending_version TEXT,
+  requested_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE strudel_cards IS 'Generated OpenGraph images of public strudels';
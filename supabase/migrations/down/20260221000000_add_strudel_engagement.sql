@@ -0,0 +1,5 @@
+-- Revert 20260221000000_add_strudel_engagement
+
+DROP MATERIALIZED VIEW IF EXISTS strudel_trending;
+DROP TABLE IF EXISTS strudel_plays;
+DROP TABLE IF EXISTS strudel_likes;
@@ -0,0 +1,5 @@
+-- Revert 20260222000000_add_user_follows
+
+DROP INDEX IF EXISTS idx_performance_events_host_created;
+DROP INDEX IF EXISTS idx_user_strudels_user_published;
+DROP TABLE IF EXISTS user_follows;
@@ -0,0 +1,5 @@
+-- Revert 20260223000000_add_assets
+-- Uploaded avatars stay in object storage; avatar_url keeps pointing at them
+
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_asset_id;
+DROP TABLE IF EXISTS assets;
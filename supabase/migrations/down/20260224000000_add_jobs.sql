@@ -0,0 +1,5 @@
+-- Revert 20260224000000_add_jobs
+-- Queued and dead-lettered jobs are lost
+
+DROP TABLE IF EXISTS job_dead_letters;
+DROP TABLE IF EXISTS jobs;
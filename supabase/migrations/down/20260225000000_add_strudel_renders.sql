@@ -0,0 +1,4 @@
+-- Revert 20260225000000_add_strudel_renders
+-- Rendered files stay in object storage under renders/
+
+DROP TABLE IF EXISTS strudel_renders;
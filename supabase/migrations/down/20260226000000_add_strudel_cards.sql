@@ -0,0 +1,4 @@
+-- Revert 20260226000000_add_strudel_cards
+-- Card images stay in object storage under cards/
+
+DROP TABLE IF EXISTS strudel_cards;
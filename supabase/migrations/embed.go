@@ -0,0 +1,9 @@
+// Package migrations embeds the schema migrations, applied by
+// `server migrate` or on boot with AUTO_MIGRATE. down/ holds the reverse of
+// migrations that can be rolled back, named like the migration they revert.
+package migrations
+
+import "embed"
+
+//go:embed *.sql down/*.sql
+var FS embed.FS